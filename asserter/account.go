@@ -43,7 +43,7 @@ func ContainsDuplicateCurrency(currencies []*types.Currency) *types.Currency {
 // struct (including currency.Metadata).
 func ContainsCurrency(currencies []*types.Currency, currency *types.Currency) bool {
 	for _, curr := range currencies {
-		if types.Hash(curr) == types.Hash(currency) {
+		if curr.Equal(currency) {
 			return true
 		}
 	}
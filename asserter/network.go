@@ -328,7 +328,7 @@ func containsNetworkIdentifier(
 	network *types.NetworkIdentifier,
 ) bool {
 	for _, net := range networks {
-		if types.Hash(net) == types.Hash(network) {
+		if net.Equal(network) {
 			return true
 		}
 	}
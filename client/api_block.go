@@ -116,6 +116,14 @@ func (a *BlockAPIService) Block(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -214,6 +222,14 @@ func (a *BlockAPIService) BlockTransaction(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
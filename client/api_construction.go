@@ -111,6 +111,14 @@ func (a *ConstructionAPIService) ConstructionCombine(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -197,6 +205,14 @@ func (a *ConstructionAPIService) ConstructionDerive(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -283,6 +299,14 @@ func (a *ConstructionAPIService) ConstructionHash(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -376,6 +400,14 @@ func (a *ConstructionAPIService) ConstructionMetadata(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -463,6 +495,14 @@ func (a *ConstructionAPIService) ConstructionParse(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -555,6 +595,14 @@ func (a *ConstructionAPIService) ConstructionPayloads(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -645,6 +693,14 @@ func (a *ConstructionAPIService) ConstructionPreprocess(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
@@ -734,6 +790,14 @@ func (a *ConstructionAPIService) ConstructionSubmit(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
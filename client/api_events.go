@@ -114,6 +114,14 @@ func (a *EventsAPIService) EventsBlocks(
 			localVarHTTPResponse.StatusCode,
 			string(localVarBody),
 		)
+	case _nethttp.StatusNotFound,
+		_nethttp.StatusNotImplemented:
+		return nil, nil, fmt.Errorf(
+			"%w: code: %d body: %s",
+			ErrNotImplemented,
+			localVarHTTPResponse.StatusCode,
+			string(localVarBody),
+		)
 	default:
 		return nil, nil, fmt.Errorf(
 			"invalid status code: %d body: %s",
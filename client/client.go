@@ -39,6 +39,11 @@ var (
 	// These status codes may be returned by intermediate services when a Rosetta
 	// implementation is overloaded and should not be considered failures.
 	ErrRetriable = errors.New("retriable http status code received")
+
+	// ErrNotImplemented is returned when a 404 or 501 HTTP code is encountered.
+	// Rosetta implementations return all errors with a 500 HTTP code, so these
+	// status codes indicate that the endpoint is not implemented.
+	ErrNotImplemented = errors.New("endpoint not implemented")
 )
 
 // APIClient manages communication with the Rosetta API v1.4.10
@@ -32,7 +32,7 @@ esac
 # Remove existing client generated code
 mkdir -p tmp;
 DIRS=( types client server )
-IGNORED_FILES=( README.md utils.go utils_test.go marshal_test.go account_currency.go account_coin.go )
+IGNORED_FILES=( README.md utils.go utils_test.go marshal_test.go account_currency.go account_coin.go builder.go builder_test.go deep.go deep_test.go amount_math.go amount_math_test.go canonical.go canonical_test.go fast_json.go fast_json_test.go fast_json_unmarshal.go fast_json_unmarshal_test.go fast_json_strict.go fast_json_strict_disabled.go metadata.go metadata_test.go )
 
 for dir in "${DIRS[@]}"
 do
@@ -105,9 +105,6 @@ rm -f server_tmp/*_service.go
 mv server_tmp/* server;
 rm -rf server_tmp;
 
-# Remove spec file
-rm -f api.json;
-
 # Fix linting issues
 sed "${SED_IFLAG[@]}" 's/Api/API/g' client/* server/*;
 sed "${SED_IFLAG[@]}" 's/Json/JSON/g' client/* server/*;
@@ -204,9 +201,16 @@ do
   rm "types/${type}.go" && cp "templates/${type}.txt" "types/${type}.go";
 done
 
+# Embed spec file in server (after fixing
+# generated code so that it is not modified)
+mv api.json server/api.json;
+
+# Generate Clone, Equal, and JSON methods for types
+TYPE_GEN="go run ./internal/typegen -types types -out types/deep_gen.go -json-out types/fast_json_gen.go -json-unmarshal-out types/fast_json_unmarshal_gen.go -json-types Block"
+
 # Format client generated code
 FORMAT_GEN="gofmt -w /local/types; gofmt -w /local/client; gofmt -w /local/server"
-GOLANG_VERSION=1.16
+GOLANG_VERSION=1.20
 docker run --rm -v "${PWD}":/local \
   golang:${GOLANG_VERSION} sh -c \
-  "cd /local; make deps; ${FORMAT_GEN}; make add-license; make shorten-lines; make fix-imports; go mod tidy;"
+  "cd /local; ${TYPE_GEN}; make deps; ${FORMAT_GEN}; make add-license; make shorten-lines; make fix-imports; go mod tidy;"
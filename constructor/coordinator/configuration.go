@@ -0,0 +1,104 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/constructor/worker"
+)
+
+// Option is used to overwrite default values in
+// Coordinator construction. Any Option not provided
+// falls back to the default value.
+type Option func(c *Coordinator)
+
+// WithRegistry resolves the Helper and *parser.Parser
+// used to construct (and derive accounts, find balances, and
+// parse transactions) on each network from registry. This allows
+// a single Coordinator to run Workflows that span multiple
+// networks (selected with <scenario>.network).
+func WithRegistry(registry *Registry) Option {
+	return func(c *Coordinator) {
+		c.registry = registry
+	}
+}
+
+// WithPrefundedAccounts tracks the utilization of each
+// *job.PrefundedAccount in prefunded. Depleted accounts are
+// excluded from find_balance results and the TopUp Workflow
+// (if provided) is invoked for any underfunded account.
+func WithPrefundedAccounts(prefunded *worker.PrefundedAccounts) Option {
+	return func(c *Coordinator) {
+		c.prefunded = prefunded
+	}
+}
+
+// WithSchedule starts new Jobs of the Workflow named
+// workflow according to schedule (ex: at a target rate
+// for load generation) instead of as often as possible.
+// Only non-reserved Workflows can be scheduled.
+func WithSchedule(workflow string, schedule *Schedule) Option {
+	return func(c *Coordinator) {
+		c.scheduler.schedules[workflow] = schedule
+	}
+}
+
+// WithMaxConcurrency caps the number of Jobs (of all
+// non-reserved Workflows) processing at once. This is
+// in addition to the concurrency of each Workflow.
+func WithMaxConcurrency(concurrency int) Option {
+	return func(c *Coordinator) {
+		c.maxConcurrency = concurrency
+	}
+}
+
+// WithFinalityPolicy determines when transactions broadcast
+// by the Workflow named workflow are considered confirmed
+// (see FinalityPolicy). Coordinator.Finalized must be provided
+// to BroadcastStorage (modules.WithFinalityChecker) for
+// chain-provided finality signals to be used.
+func WithFinalityPolicy(workflow string, policy *FinalityPolicy) Option {
+	return func(c *Coordinator) {
+		c.finalityPolicies[workflow] = policy
+	}
+}
+
+// WithSubscriber notifies subscriber of every
+// *Event emitted by the Coordinator. This option
+// can be provided multiple times.
+func WithSubscriber(subscriber Subscriber) Option {
+	return func(c *Coordinator) {
+		c.subscribers = append(c.subscribers, subscriber)
+	}
+}
+
+// WithDataset starts one Job of the Workflow named workflow
+// for each row in dataset (see Dataset). Once all rows have
+// been started, no new Jobs of the Workflow are started. Only
+// non-reserved Workflows can be provided a Dataset.
+func WithDataset(workflow string, dataset *Dataset) Option {
+	return func(c *Coordinator) {
+		c.datasets[workflow] = dataset
+	}
+}
+
+// WithFeeBumpThreshold overrides the default number of times
+// (DefaultFeeBumpThreshold) a transaction must be broadcast
+// without confirmation before the BumpFee Workflow is invoked
+// to replace it.
+func WithFeeBumpThreshold(broadcasts int) Option {
+	return func(c *Coordinator) {
+		c.feeBumpThreshold = broadcasts
+	}
+}
@@ -16,6 +16,7 @@ package coordinator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -27,6 +28,7 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/constructor/worker"
 	"github.com/coinbase/rosetta-sdk-go/parser"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -39,6 +41,7 @@ func New(
 	handler Handler,
 	parser *parser.Parser,
 	inputWorkflows []*job.Workflow,
+	options ...Option,
 ) (*Coordinator, error) {
 	if len(inputWorkflows) == 0 {
 		return nil, ErrNoWorkflows
@@ -49,6 +52,8 @@ func New(
 	var createAccountWorkflow *job.Workflow
 	var requestFundsWorkflow *job.Workflow
 	var returnFundsWorkflow *job.Workflow
+	var bumpFeeWorkflow *job.Workflow
+	var topUpWorkflow *job.Workflow
 	for i, workflow := range inputWorkflows {
 		if utils.ContainsString(workflowNames, workflow.Name) {
 			return nil, ErrDuplicateWorkflows
@@ -85,14 +90,29 @@ func New(
 			continue
 		}
 
+		if workflow.Name == string(job.BumpFee) {
+			// BumpFee is only invoked by BumpFee (and never
+			// processed in the background).
+			bumpFeeWorkflow = workflow
+			continue
+		}
+
+		if workflow.Name == string(job.TopUp) {
+			if workflow.Concurrency != job.ReservedWorkflowConcurrency {
+				return nil, ErrIncorrectConcurrency
+			}
+
+			topUpWorkflow = workflow
+			continue
+		}
+
 		workflows = append(workflows, workflow)
 	}
 
-	return &Coordinator{
+	c := &Coordinator{
 		storage:               storage,
 		helper:                helper,
 		handler:               handler,
-		worker:                worker.New(helper),
 		parser:                parser,
 		attemptedJobs:         []string{},
 		attemptedWorkflows:    []string{},
@@ -101,7 +121,74 @@ func New(
 		createAccountWorkflow: createAccountWorkflow,
 		requestFundsWorkflow:  requestFundsWorkflow,
 		returnFundsWorkflow:   returnFundsWorkflow,
-	}, nil
+		bumpFeeWorkflow:       bumpFeeWorkflow,
+		topUpWorkflow:         topUpWorkflow,
+		feeBumpThreshold:      DefaultFeeBumpThreshold,
+		scheduler:             newScheduler(),
+		finalityPolicies:      map[string]*FinalityPolicy{},
+		datasets:              map[string]*Dataset{},
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	for name, schedule := range c.scheduler.schedules {
+		if err := scheduleValidation(schedule); err != nil {
+			return nil, fmt.Errorf("%w: workflow %s", err, name)
+		}
+
+		// Only non-reserved Workflows can be scheduled.
+		scheduled := false
+		for _, workflow := range workflows {
+			if workflow.Name == name {
+				scheduled = true
+				break
+			}
+		}
+
+		if !scheduled {
+			return nil, fmt.Errorf("%w: %s cannot be scheduled", ErrWorkflowMissing, name)
+		}
+	}
+
+	for name, policy := range c.finalityPolicies {
+		if err := finalityPolicyValidation(policy); err != nil {
+			return nil, fmt.Errorf("%w: workflow %s", err, name)
+		}
+
+		if c.workflow(name) == nil {
+			return nil, fmt.Errorf("%w: %s", ErrWorkflowMissing, name)
+		}
+	}
+
+	for name, dataset := range c.datasets {
+		if dataset == nil {
+			return nil, fmt.Errorf("%w: workflow %s dataset is nil", ErrDatasetInvalid, name)
+		}
+
+		// Only non-reserved Workflows can be provided a Dataset.
+		provided := false
+		for _, workflow := range workflows {
+			if workflow.Name == name {
+				provided = true
+				break
+			}
+		}
+
+		if !provided {
+			return nil, fmt.Errorf("%w: %s cannot be provided a dataset", ErrWorkflowMissing, name)
+		}
+	}
+
+	c.worker = worker.New(
+		helper,
+		worker.WithNetworkResolver(c.workerHelper),
+		worker.WithPrefundedAccounts(c.prefunded),
+		worker.WithBalanceObserver(c.balanceObserved),
+	)
+
+	return c, nil
 }
 
 func (c *Coordinator) findJob(
@@ -127,6 +214,19 @@ func (c *Coordinator) findJob(
 		return job, nil
 	}
 
+	// Top up any underfunded prefunded account
+	// before starting new workflows.
+	if !returnFunds {
+		topUp, err := c.findTopUp(ctx, dbTx)
+		if err != nil {
+			return nil, err
+		}
+
+		if topUp != nil {
+			return topUp, nil
+		}
+	}
+
 	// We should only attempt the ReturnFunds workflow
 	// if returnFunds is true. If it is true, we know that
 	// c.returnFundsWorkflow must be defined.
@@ -135,12 +235,33 @@ func (c *Coordinator) findJob(
 		availableWorkflows = []*job.Workflow{c.returnFundsWorkflow}
 	}
 
+	// We don't start any new workflows if the
+	// concurrency cap has been reached.
+	capped, err := c.concurrencyCapped(ctx, dbTx, returnFunds)
+	if err != nil {
+		return nil, err
+	}
+	if capped {
+		availableWorkflows = []*job.Workflow{}
+	}
+
 	// Attempt non-reserved workflows
+	var scheduledWait time.Duration
 	for _, workflow := range availableWorkflows {
 		if utils.ContainsString(c.attemptedWorkflows, workflow.Name) {
 			continue
 		}
 
+		// Scheduled workflows are only started once
+		// they are due.
+		if wait := c.scheduler.due(workflow.Name, time.Now()); !returnFunds && wait > 0 {
+			if scheduledWait == 0 || wait < scheduledWait {
+				scheduledWait = wait
+			}
+
+			continue
+		}
+
 		processing, err := c.storage.Processing(ctx, dbTx, workflow.Name)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -154,6 +275,21 @@ func (c *Coordinator) findJob(
 			continue
 		}
 
+		// Workflows provided a Dataset are started
+		// once for each row.
+		if dataset, ok := c.datasets[workflow.Name]; ok {
+			j, err := c.nextDatasetJob(ctx, dbTx, workflow, dataset)
+			if err != nil {
+				return nil, err
+			}
+
+			if j == nil {
+				continue
+			}
+
+			return j, nil
+		}
+
 		return job.New(workflow), nil
 	}
 
@@ -186,6 +322,13 @@ func (c *Coordinator) findJob(
 		return job.New(c.createAccountWorkflow), nil
 	}
 
+	// If any workflows are waiting for their schedule,
+	// we should wait instead of requesting funds.
+	if scheduledWait > 0 {
+		c.scheduledWait = scheduledWait
+		return nil, ErrWorkflowsScheduled
+	}
+
 	if len(allBroadcasts) > 0 {
 		return nil, ErrNoAvailableJobs
 	}
@@ -217,13 +360,90 @@ func (c *Coordinator) findJob(
 	return nil, ErrStalled
 }
 
+// concurrencyCapped returns a boolean indicating if the
+// number of processing Jobs (of non-reserved Workflows)
+// has reached the concurrency cap.
+func (c *Coordinator) concurrencyCapped(
+	ctx context.Context,
+	dbTx database.Transaction,
+	returnFunds bool,
+) (bool, error) {
+	if c.maxConcurrency <= 0 || returnFunds {
+		return false, nil
+	}
+
+	total := 0
+	for _, workflow := range c.workflows {
+		processing, err := c.storage.Processing(ctx, dbTx, workflow.Name)
+		if err != nil {
+			return false, fmt.Errorf(
+				"%w: %s",
+				ErrJobsUnretrievable,
+				err.Error(),
+			)
+		}
+
+		total += len(processing)
+	}
+
+	return total >= c.maxConcurrency, nil
+}
+
+// findTopUp returns a TopUp *job.Job for the first
+// underfunded *job.PrefundedAccount (that has not reached
+// its spend limit), if any.
+func (c *Coordinator) findTopUp(
+	ctx context.Context,
+	dbTx database.Transaction,
+) (*job.Job, error) {
+	if c.topUpWorkflow == nil || c.prefunded == nil ||
+		utils.ContainsString(c.attemptedWorkflows, c.topUpWorkflow.Name) {
+		return nil, nil
+	}
+
+	processing, err := c.storage.Processing(ctx, dbTx, c.topUpWorkflow.Name)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s",
+			ErrJobsUnretrievable,
+			err.Error(),
+		)
+	}
+
+	if len(processing) >= job.ReservedWorkflowConcurrency {
+		return nil, nil
+	}
+
+	statuses, err := c.prefunded.Statuses(ctx, dbTx, c.helper)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPrefundedAccountsUnretrievable, err)
+	}
+
+	for _, status := range statuses {
+		// There is no reason to top up an account
+		// that has reached its spend limit.
+		if !status.Underfunded || status.LimitReached {
+			continue
+		}
+
+		return job.NewTopUp(c.topUpWorkflow, status)
+	}
+
+	return nil, nil
+}
+
 // createTransaction constructs and signs a transaction with the provided intent.
+// If step is not nil, all intermediate artifacts are recorded in it.
 func (c *Coordinator) createTransaction(
 	ctx context.Context,
 	dbTx database.Transaction,
 	broadcast *job.Broadcast,
+	step *PlanStep,
 ) (*types.TransactionIdentifier, string, []*types.Amount, error) {
-	metadataRequest, requiredPublicKeys, err := c.helper.Preprocess(
+	networkHelper := c.helperFor(broadcast.Network)
+	networkParser := c.parserFor(broadcast.Network)
+
+	metadataRequest, requiredPublicKeys, err := networkHelper.Preprocess(
 		ctx,
 		broadcast.Network,
 		broadcast.Intent,
@@ -233,6 +453,11 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to preprocess", err)
 	}
 
+	if step != nil {
+		step.PreprocessOptions = metadataRequest
+		step.RequiredPublicKeys = requiredPublicKeys
+	}
+
 	publicKeys := make([]*types.PublicKey, len(requiredPublicKeys))
 	for i, accountIdentifier := range requiredPublicKeys {
 		keyPair, err := c.helper.GetKey(ctx, dbTx, accountIdentifier)
@@ -247,7 +472,7 @@ func (c *Coordinator) createTransaction(
 		publicKeys[i] = keyPair.PublicKey
 	}
 
-	requiredMetadata, suggestedFees, err := c.helper.Metadata(
+	requiredMetadata, suggestedFees, err := networkHelper.Metadata(
 		ctx,
 		broadcast.Network,
 		metadataRequest,
@@ -257,11 +482,16 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to construct metadata", err)
 	}
 
+	if step != nil {
+		step.Metadata = requiredMetadata
+		step.SuggestedFee = suggestedFees
+	}
+
 	if broadcast.DryRun {
 		return nil, "", suggestedFees, nil
 	}
 
-	unsignedTransaction, payloads, err := c.helper.Payloads(
+	unsignedTransaction, payloads, err := networkHelper.Payloads(
 		ctx,
 		broadcast.Network,
 		broadcast.Intent,
@@ -272,7 +502,7 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to construct payloads", err)
 	}
 
-	parsedOps, signers, _, err := c.helper.Parse(
+	parsedOps, signers, _, err := networkHelper.Parse(
 		ctx,
 		broadcast.Network,
 		false,
@@ -282,6 +512,12 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to parse unsigned transaction", err)
 	}
 
+	if step != nil {
+		step.UnsignedTransaction = unsignedTransaction
+		step.Payloads = payloads
+		step.UnsignedOperations = parsedOps
+	}
+
 	if len(signers) != 0 {
 		return nil, "", nil, fmt.Errorf(
 			"signers should be empty in unsigned transaction but found %d",
@@ -289,7 +525,7 @@ func (c *Coordinator) createTransaction(
 		)
 	}
 
-	if err := c.parser.ExpectedOperations(broadcast.Intent, parsedOps, false, false); err != nil {
+	if err := networkParser.ExpectedOperations(broadcast.Intent, parsedOps, false, false); err != nil {
 		log.Printf(
 			"expected %s, observed %s\n",
 			types.PrintStruct(broadcast.Intent),
@@ -303,7 +539,7 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to sign payloads", err)
 	}
 
-	networkTransaction, err := c.helper.Combine(
+	networkTransaction, err := networkHelper.Combine(
 		ctx,
 		broadcast.Network,
 		unsignedTransaction,
@@ -313,7 +549,7 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to combine signatures", err)
 	}
 
-	signedParsedOps, signers, _, err := c.helper.Parse(
+	signedParsedOps, signers, _, err := networkHelper.Parse(
 		ctx,
 		broadcast.Network,
 		true,
@@ -323,7 +559,14 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to parse signed transaction", err)
 	}
 
-	if err := c.parser.ExpectedOperations(broadcast.Intent, signedParsedOps, false, false); err != nil {
+	if step != nil {
+		step.Signatures = signatures
+		step.SignedTransaction = networkTransaction
+		step.SignedOperations = signedParsedOps
+		step.Signers = signers
+	}
+
+	if err := networkParser.ExpectedOperations(broadcast.Intent, signedParsedOps, false, false); err != nil {
 		log.Printf(
 			"expected %s, observed %s\n",
 			types.PrintStruct(broadcast.Intent),
@@ -336,7 +579,7 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: signed transactions signers do not match intent", err)
 	}
 
-	transactionIdentifier, err := c.helper.Hash(
+	transactionIdentifier, err := networkHelper.Hash(
 		ctx,
 		broadcast.Network,
 		networkTransaction,
@@ -345,6 +588,10 @@ func (c *Coordinator) createTransaction(
 		return nil, "", nil, fmt.Errorf("%w: unable to get transaction hash", err)
 	}
 
+	if step != nil {
+		step.TransactionIdentifier = transactionIdentifier
+	}
+
 	return transactionIdentifier, networkTransaction, nil, nil
 }
 
@@ -366,10 +613,37 @@ func (c *Coordinator) BroadcastComplete(
 		)
 	}
 
+	network, err := j.BroadcastNetwork()
+	if err != nil {
+		return fmt.Errorf("%w: unable to determine broadcast network", err)
+	}
+
+	// The intent must be retrieved before the broadcast
+	// is marked complete (it is needed to release the spend
+	// of any prefunded account if the broadcast failed).
+	var intent []*types.Operation
+	_, confirmationHandler := c.handler.(ConfirmationHandler)
+	if (confirmationHandler && transaction != nil) || (c.prefunded != nil && transaction == nil) {
+		intent, err = j.BroadcastIntent()
+		if err != nil {
+			return fmt.Errorf("%w: unable to determine broadcast intent", err)
+		}
+	}
+
 	if err := j.BroadcastComplete(ctx, transaction); err != nil {
 		return fmt.Errorf("%w: unable to mark broadcast complete", err)
 	}
 
+	// If the broadcast failed, the Job has failed and
+	// we must compensate all of its completed actions.
+	if transaction == nil {
+		c.worker.Compensate(ctx, dbTx, j)
+
+		if err := c.prefunded.Release(ctx, dbTx, c.helper, intent); err != nil {
+			return fmt.Errorf("%w: unable to release prefunded account spend", err)
+		}
+	}
+
 	if _, err := c.storage.Update(ctx, dbTx, j); err != nil {
 		return fmt.Errorf("%w: unable to update job", err)
 	}
@@ -387,10 +661,16 @@ func (c *Coordinator) BroadcastComplete(
 			j.Workflow,
 			jobIdentifier,
 		)
+		c.emitJob(ctx, WorkflowFailed, j, network, nil, ErrBroadcastFailed)
 
 		return nil
 	}
 
+	c.emitJob(ctx, BroadcastConfirmed, j, network, transaction.TransactionIdentifier, nil)
+	if j.Status == job.Completed {
+		c.emitJob(ctx, WorkflowCompleted, j, nil, nil, nil)
+	}
+
 	statusString := fmt.Sprintf(
 		"broadcast complete for job \"%s (%s)\" with transaction hash \"%s\"\n",
 		j.Workflow,
@@ -403,7 +683,7 @@ func (c *Coordinator) BroadcastComplete(
 	//
 	// TODO: modify parser to calculate balance changes for a single
 	// transaction.
-	balanceChanges, err := c.parser.BalanceChanges(ctx, &types.Block{
+	balanceChanges, err := c.parserFor(network).BalanceChanges(ctx, &types.Block{
 		Transactions: []*types.Transaction{
 			transaction,
 		},
@@ -427,9 +707,261 @@ func (c *Coordinator) BroadcastComplete(
 	}
 	color.Magenta(statusString)
 
+	if err := c.confirmed(ctx, jobIdentifier, &Confirmation{
+		Workflow:    j.Workflow,
+		Transaction: transaction,
+		Intent:      intent,
+	}, network); err != nil {
+		return fmt.Errorf("%w: unable to handle broadcast confirmation", err)
+	}
+
 	return nil
 }
 
+// RegisteredBroadcastComplete is called by the broadcast storage
+// of a network registered in the Registry when a transaction
+// broadcast has completed. Jobs are stored with the Coordinator's
+// Helper, so the broadcast is completed in a database.Transaction
+// of the Coordinator's Helper (instead of a database.Transaction
+// of the network's database). If the transaction is nil, then the
+// transaction did not succeed.
+func (c *Coordinator) RegisteredBroadcastComplete(
+	ctx context.Context,
+	jobIdentifier string,
+	transaction *types.Transaction,
+) error {
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	if err := c.BroadcastComplete(ctx, dbTx, jobIdentifier, transaction); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: unable to commit broadcast completion", err)
+	}
+
+	return nil
+}
+
+// stuckBroadcast returns the *job.StuckBroadcast
+// provided to the BumpFee Workflow for a broadcast.
+func (c *Coordinator) stuckBroadcast(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *modules.Broadcast,
+) (*job.StuckBroadcast, error) {
+	j, err := c.storage.Get(ctx, dbTx, broadcast.Identifier)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s",
+			ErrJobMissing,
+			err.Error(),
+		)
+	}
+
+	_, _, metadata, err := c.helperFor(broadcast.NetworkIdentifier).Parse(
+		ctx,
+		broadcast.NetworkIdentifier,
+		true,
+		broadcast.Payload,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse stuck transaction", err)
+	}
+
+	stuck := &job.StuckBroadcast{
+		Job:                   broadcast.Identifier,
+		Workflow:              j.Workflow,
+		Network:               broadcast.NetworkIdentifier,
+		TransactionIdentifier: broadcast.TransactionIdentifier,
+		Intent:                broadcast.Intent,
+		Metadata:              metadata,
+		Broadcasts:            broadcast.Broadcasts,
+		ReplacedTransactions:  broadcast.ReplacedTransactions,
+	}
+	if len(j.State) > 0 {
+		stuck.State = json.RawMessage(j.State)
+	}
+
+	return stuck, nil
+}
+
+// BumpFee is a modules.FeeBumper that replaces a stuck
+// broadcast (one that has been broadcast at least the fee
+// bump threshold times without confirmation) with the first
+// broadcast created by the BumpFee Workflow. The Intent of
+// the *modules.Broadcast is updated to that of the replacement.
+//
+// If there is no BumpFee Workflow or it does not create a
+// broadcast, the stuck transaction is rebroadcast as is.
+func (c *Coordinator) BumpFee(
+	ctx context.Context,
+	broadcast *modules.Broadcast,
+) (*types.TransactionIdentifier, string, error) {
+	if c.bumpFeeWorkflow == nil || broadcast.Broadcasts < c.feeBumpThreshold {
+		return nil, "", nil
+	}
+
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	stuck, err := c.stuckBroadcast(ctx, dbTx, broadcast)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrFeeBumpFailed, err)
+	}
+
+	j, err := job.NewBumpFee(c.bumpFeeWorkflow, stuck)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrFeeBumpFailed, err)
+	}
+
+	var replacement *job.Broadcast
+	for replacement == nil && !j.CheckComplete() {
+		var executionErr *worker.Error
+		replacement, executionErr = c.worker.Process(c.workerContext(ctx, j), dbTx, j)
+		if executionErr != nil {
+			executionErr.Log()
+
+			return nil, "", fmt.Errorf("%w: %v", ErrFeeBumpFailed, executionErr.Err)
+		}
+	}
+
+	if replacement == nil {
+		log.Printf(
+			`bump fee workflow did not replace transaction "%s"`,
+			broadcast.TransactionIdentifier.Hash,
+		)
+
+		return nil, "", nil
+	}
+
+	if replacement.DryRun {
+		return nil, "", fmt.Errorf("%w: replacement cannot be a dry run", ErrFeeBumpFailed)
+	}
+
+	transactionIdentifier, networkTransaction, _, err := c.createTransaction(
+		ctx,
+		dbTx,
+		replacement,
+		nil,
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf(
+			"%w: unable to create replacement transaction: %v",
+			ErrFeeBumpFailed,
+			err,
+		)
+	}
+
+	// Commit any state changes made by the BumpFee
+	// Workflow (ex: saved blobs).
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, "", fmt.Errorf("%w: unable to commit bump fee: %v", ErrFeeBumpFailed, err)
+	}
+
+	broadcast.Intent = replacement.Intent
+	color.Magenta(
+		"replaced transaction \"%s\" for job \"%s\" with transaction \"%s\"\n",
+		broadcast.TransactionIdentifier.Hash,
+		broadcast.Identifier,
+		transactionIdentifier.Hash,
+	)
+
+	return transactionIdentifier, networkTransaction, nil
+}
+
+// workflow returns the *job.Workflow named name
+// (including reserved Workflows).
+func (c *Coordinator) workflow(name string) *job.Workflow {
+	reserved := []*job.Workflow{
+		c.createAccountWorkflow,
+		c.requestFundsWorkflow,
+		c.returnFundsWorkflow,
+		c.bumpFeeWorkflow,
+	}
+	for _, workflow := range append(reserved, c.workflows...) {
+		if workflow != nil && workflow.Name == name {
+			return workflow
+		}
+	}
+
+	return nil
+}
+
+// Plan executes all scenarios of the Workflow named workflowName
+// up to (but not including) /construction/submit and returns
+// a *PlanReport containing every intermediate artifact created
+// while constructing each transaction. This is useful for validating
+// a construction implementation (ex: in CI) without broadcasting
+// anything.
+//
+// Plan never commits any state changes (ex: saved accounts or blobs) and
+// never broadcasts a transaction. Instead, each transaction is considered
+// confirmed as soon as it is constructed (the signed parsed operations
+// are populated in the transaction variable of its scenario). If
+// planning fails, the *PlanReport of all completed steps is returned
+// with the error.
+func (c *Coordinator) Plan(
+	ctx context.Context,
+	workflowName string,
+) (*PlanReport, error) {
+	workflow := c.workflow(workflowName)
+	if workflow == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowMissing, workflowName)
+	}
+
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	report := &PlanReport{Workflow: workflowName, Steps: []*PlanStep{}}
+	j := job.New(workflow)
+	for !j.CheckComplete() {
+		broadcast, executionErr := c.worker.Process(ctx, dbTx, j)
+		if executionErr != nil {
+			executionErr.Log()
+
+			return report, fmt.Errorf("%w: %v", ErrPlanFailed, executionErr.Err)
+		}
+
+		if broadcast == nil {
+			break
+		}
+
+		step := &PlanStep{
+			Scenario: j.Scenarios[j.Index-1].Name,
+			Network:  broadcast.Network,
+			Intent:   broadcast.Intent,
+			DryRun:   broadcast.DryRun,
+		}
+		report.Steps = append(report.Steps, step)
+
+		_, _, suggestedFees, err := c.createTransaction(ctx, dbTx, broadcast, step)
+		if err != nil {
+			return report, fmt.Errorf(
+				"%w: unable to create transaction for scenario %s: %v",
+				ErrPlanFailed,
+				step.Scenario,
+				err,
+			)
+		}
+
+		if broadcast.DryRun {
+			err = j.DryRunComplete(ctx, suggestedFees)
+		} else {
+			err = j.BroadcastComplete(ctx, &types.Transaction{
+				TransactionIdentifier: step.TransactionIdentifier,
+				Operations:            step.SignedOperations,
+			})
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: %v", ErrPlanFailed, err)
+		}
+	}
+
+	return report, nil
+}
+
 func (c *Coordinator) resetVars() {
 	c.attemptedJobs = []string{}
 	c.attemptedWorkflows = []string{}
@@ -460,6 +992,12 @@ func (c *Coordinator) invokeHandlersAndBroadcast(
 		return fmt.Errorf("%w: unable to broadcast all transactions", err)
 	}
 
+	for _, helper := range c.registeredHelpers() {
+		if err := helper.BroadcastAll(ctx); err != nil {
+			return fmt.Errorf("%w: unable to broadcast all transactions", err)
+		}
+	}
+
 	return nil
 }
 
@@ -492,6 +1030,15 @@ func (c *Coordinator) process( // nolint:gocognit
 		c.resetVars()
 		return NoJobsWaitTime, nil
 	}
+	if errors.Is(err, ErrWorkflowsScheduled) {
+		// We don't reset vars here because no
+		// progress has been made.
+		if c.scheduledWait > NoJobsWaitTime {
+			return NoJobsWaitTime, nil
+		}
+
+		return c.scheduledWait, nil
+	}
 	if errors.Is(err, ErrStalled) {
 		color.Yellow("processing stalled")
 
@@ -512,7 +1059,7 @@ func (c *Coordinator) process( // nolint:gocognit
 	}
 	log.Println(statusMessage)
 
-	broadcast, executionErr := c.worker.Process(ctx, dbTx, j)
+	broadcast, executionErr := c.worker.Process(c.workerContext(ctx, j), dbTx, j)
 	if executionErr != nil {
 		if errors.Is(executionErr.Err, worker.ErrCreateAccount) {
 			c.addToUnprocessed(j)
@@ -527,6 +1074,20 @@ func (c *Coordinator) process( // nolint:gocognit
 		// Log the exeuction error to the terminal so
 		// the caller can debug their scripts.
 		executionErr.Log()
+		c.emitJob(ctx, WorkflowFailed, j, nil, nil, executionErr.Err)
+
+		// A row of a Dataset that cannot be processed does
+		// not stop the remaining rows from being processed.
+		dbTx.Discard(ctx)
+		failed, err := c.datasetRowFailed(ctx, j, len(j.Identifier) == 0, executionErr.Err)
+		if err != nil {
+			return -1, fmt.Errorf("%w: unable to record dataset row failure", err)
+		}
+
+		if failed {
+			c.resetVars()
+			return 0, nil
+		}
 
 		return -1, fmt.Errorf("%w: unable to process job", executionErr.Err)
 	}
@@ -536,21 +1097,43 @@ func (c *Coordinator) process( // nolint:gocognit
 	// Note, we ALWAYS store jobs even if they are complete on
 	// their first run so that we can have a full view of everything
 	// we've done in JobStorage.
+	started := len(j.Identifier) == 0
 	jobIdentifier, err := c.storage.Update(ctx, dbTx, j)
 	if err != nil {
 		return -1, fmt.Errorf("%w: unable to update job", err)
 	}
 	j.Identifier = jobIdentifier
 
+	if started {
+		if err := c.datasetRowStarted(ctx, dbTx, j); err != nil {
+			return -1, fmt.Errorf("%w: unable to record dataset row", err)
+		}
+	}
+
 	var transactionCreated *types.TransactionIdentifier
+	var transactionNetwork *types.NetworkIdentifier
+	var networkBroadcastTx database.Transaction
 	if broadcast != nil {
 		// Construct Transaction (or dry run)
 		transactionIdentifier, networkTransaction, suggestedFees, err := c.createTransaction(
 			ctx,
 			dbTx,
 			broadcast,
+			nil,
 		)
 		if err != nil {
+			dbTx.Discard(ctx)
+			failed, rowErr := c.datasetRowFailed(ctx, j, started, err)
+			if rowErr != nil {
+				return -1, fmt.Errorf("%w: unable to record dataset row failure", rowErr)
+			}
+
+			if failed {
+				c.emitJob(ctx, WorkflowFailed, j, nil, nil, err)
+				c.resetVars()
+				return 0, nil
+			}
+
 			return -1, fmt.Errorf("%w: unable to create transaction", err)
 		}
 
@@ -565,21 +1148,34 @@ func (c *Coordinator) process( // nolint:gocognit
 				return -1, fmt.Errorf("%w: unable to update job after dry run", err)
 			}
 		} else {
-			// Invoke Broadcast storage (in same TX as update job)
-			if err := c.helper.Broadcast(
+			// Invoke Broadcast storage (in same TX as update job, unless
+			// the broadcast is on a registered network with its own
+			// database)
+			broadcastTx, separate := c.networkTransaction(ctx, dbTx, broadcast.Network)
+			if separate {
+				defer broadcastTx.Discard(ctx)
+				networkBroadcastTx = broadcastTx
+			}
+
+			if err := c.helperFor(broadcast.Network).Broadcast(
 				ctx,
-				dbTx,
+				broadcastTx,
 				jobIdentifier,
 				broadcast.Network,
 				broadcast.Intent,
 				transactionIdentifier,
 				networkTransaction,
-				broadcast.ConfirmationDepth,
+				c.confirmationDepth(j.Workflow, broadcast.ConfirmationDepth),
 			); err != nil {
 				return -1, fmt.Errorf("%w: unable to enqueue broadcast", err)
 			}
 
+			if err := c.prefunded.Spend(ctx, dbTx, c.helper, broadcast.Intent); err != nil {
+				return -1, fmt.Errorf("%w: unable to record prefunded account spend", err)
+			}
+
 			transactionCreated = transactionIdentifier
+			transactionNetwork = broadcast.Network
 			log.Printf(
 				`created transaction "%s" for job "%s"`,
 				transactionIdentifier.Hash,
@@ -597,6 +1193,28 @@ func (c *Coordinator) process( // nolint:gocognit
 		return -1, fmt.Errorf("%w: unable to commit job update", err)
 	}
 
+	// The broadcast on a registered network is committed after the
+	// job update so that a broadcast never exists for a job that was
+	// not updated.
+	if networkBroadcastTx != nil {
+		if err := networkBroadcastTx.Commit(ctx); err != nil {
+			return -1, fmt.Errorf("%w: unable to commit broadcast", err)
+		}
+	}
+
+	if started {
+		c.scheduler.started(j.Workflow, time.Now())
+		c.emitJob(ctx, WorkflowStarted, j, nil, nil, nil)
+	}
+
+	if transactionCreated != nil {
+		c.emitJob(ctx, BroadcastCreated, j, transactionNetwork, transactionCreated, nil)
+	}
+
+	if j.Status == job.Completed {
+		c.emitJob(ctx, WorkflowCompleted, j, nil, nil, nil)
+	}
+
 	// Invoke handlers and broadcast
 	if err := c.invokeHandlersAndBroadcast(ctx, jobIdentifier, transactionCreated); err != nil {
 		return -1, fmt.Errorf("%w: unable to handle job success", err)
@@ -637,6 +1255,22 @@ func (c *Coordinator) processLoop(
 	return ctx.Err()
 }
 
+// PrefundedAccounts returns the utilization of each
+// *job.PrefundedAccount registered with WithPrefundedAccounts.
+func (c *Coordinator) PrefundedAccounts(
+	ctx context.Context,
+) ([]*job.PrefundedAccountStatus, error) {
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	statuses, err := c.prefunded.Statuses(ctx, dbTx, c.helper)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPrefundedAccountsUnretrievable, err)
+	}
+
+	return statuses, nil
+}
+
 // Process creates and executes jobs
 // until failure.
 func (c *Coordinator) Process(
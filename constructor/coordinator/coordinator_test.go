@@ -18,17 +18,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/constructor/worker"
 	"github.com/coinbase/rosetta-sdk-go/keys"
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/constructor/coordinator"
 	"github.com/coinbase/rosetta-sdk-go/parser"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -2470,3 +2476,2464 @@ func TestNoReservedWorkflows(t *testing.T) {
 	jobStorage.AssertExpectations(t)
 	helper.AssertExpectations(t)
 }
+
+func TestBumpFee(t *testing.T) {
+	ctx := context.Background()
+
+	network := &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Testnet3",
+	}
+	currency := &types.Currency{
+		Symbol:   "tBTC",
+		Decimals: 8,
+	}
+	opsWithOutput := func(value string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: "Vin",
+				Account: &types.AccountIdentifier{
+					Address: "address1",
+				},
+				Amount: &types.Amount{
+					Value:    "-100",
+					Currency: currency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: "Vout",
+				Account: &types.AccountIdentifier{
+					Address: "address2",
+				},
+				Amount: &types.Amount{
+					Value:    value,
+					Currency: currency,
+				},
+			},
+		}
+	}
+	stuckOps := opsWithOutput("90")
+	bumpOps := opsWithOutput("80")
+	workflows := func(bumpFee *job.Workflow) []*job.Workflow {
+		return []*job.Workflow{
+			{
+				Name:        string(job.RequestFunds),
+				Concurrency: 1,
+			},
+			{
+				Name:        string(job.CreateAccount),
+				Concurrency: 1,
+			},
+			bumpFee,
+		}
+	}
+	replaceByFee := &job.Workflow{
+		Name:        string(job.BumpFee),
+		Concurrency: 1,
+		Scenarios: []*job.Scenario{
+			{
+				Name: "bump",
+				Actions: []*job.Action{
+					{
+						Type:       job.SetVariable,
+						Input:      `{{stuck_broadcast.network}}`,
+						OutputPath: "bump.network",
+					},
+					{
+						Type:       job.SetVariable,
+						Input:      `"1"`,
+						OutputPath: "bump.confirmation_depth",
+					},
+					{
+						Type:       job.Math,
+						Input:      `{"operation":"subtraction","left_value":{{stuck_broadcast.intent.1.amount.value}},"right_value":"10"}`, // nolint
+						OutputPath: "output",
+					},
+					{
+						Type:       job.SetVariable,
+						Input:      `[{"operation_identifier":{"index":0},"type":"Vin","account":{"address":"address1"},"amount":{{stuck_broadcast.intent.0.amount}}},{"operation_identifier":{"index":1},"type":"Vout","account":{"address":"address2"},"amount":{"value":{{output}},"currency":{{stuck_broadcast.intent.1.amount.currency}}}}]`, // nolint
+						OutputPath: "bump.operations",
+					},
+					{
+						Type:       job.SetVariable,
+						Input:      `{"nonce":{{stuck_broadcast.metadata.nonce}}}`,
+						OutputPath: "bump.preprocess_metadata",
+					},
+				},
+			},
+		},
+	}
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	defer db.Close(ctx)
+
+	newBroadcast := func(broadcasts int) *modules.Broadcast {
+		return &modules.Broadcast{
+			Identifier:            jobIdentifier,
+			NetworkIdentifier:     network,
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "stuck hash"},
+			Intent:                stuckOps,
+			Payload:               "stuck transaction",
+			Broadcasts:            broadcasts,
+		}
+	}
+
+	t.Run("below threshold", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows(replaceByFee),
+			WithFeeBumpThreshold(3),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, c.workflows, 0)
+
+		broadcast := newBroadcast(2)
+		identifier, payload, err := c.BumpFee(ctx, broadcast)
+		assert.NoError(t, err)
+		assert.Nil(t, identifier)
+		assert.Empty(t, payload)
+		assert.Equal(t, stuckOps, broadcast.Intent)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("replace by fee", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows(replaceByFee),
+			WithFeeBumpThreshold(3),
+		)
+		assert.NoError(t, err)
+
+		dbTx := db.Transaction(ctx)
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(&job.Job{
+			Identifier: jobIdentifier,
+			Workflow:   "transfer",
+			State:      `{"transfer":{"confirmation_depth":"1"}}`,
+		}, nil).Once()
+		helper.On(
+			"Parse",
+			ctx,
+			network,
+			true,
+			"stuck transaction",
+		).Return(
+			stuckOps,
+			[]*types.AccountIdentifier{{Address: "address1"}},
+			map[string]interface{}{"nonce": "5"},
+			nil,
+		).Once()
+
+		metadataOptions := map[string]interface{}{
+			"metadata": "test",
+		}
+		helper.On(
+			"Preprocess",
+			ctx,
+			network,
+			bumpOps,
+			map[string]interface{}{"nonce": "5"},
+		).Return(metadataOptions, nil, nil).Once()
+		fetchedMetadata := map[string]interface{}{
+			"tx_meta": "help",
+		}
+		helper.On(
+			"Metadata",
+			ctx,
+			network,
+			metadataOptions,
+			[]*types.PublicKey{},
+		).Return(fetchedMetadata, nil, nil).Once()
+		signingPayloads := []*types.SigningPayload{
+			{
+				AccountIdentifier: &types.AccountIdentifier{Address: "address1"},
+				Bytes:             []byte("blah"),
+				SignatureType:     types.Ecdsa,
+			},
+		}
+		helper.On(
+			"Payloads",
+			ctx,
+			network,
+			bumpOps,
+			fetchedMetadata,
+			[]*types.PublicKey{},
+		).Return(unsignedTx, signingPayloads, nil).Once()
+		helper.On(
+			"Parse",
+			ctx,
+			network,
+			false,
+			unsignedTx,
+		).Return(bumpOps, []*types.AccountIdentifier{}, nil, nil).Once()
+		signatures := []*types.Signature{
+			{
+				SigningPayload: signingPayloads[0],
+				PublicKey: &types.PublicKey{
+					Bytes:     []byte("pubkey"),
+					CurveType: types.Secp256k1,
+				},
+				SignatureType: types.Ecdsa,
+				Bytes:         []byte("signature"),
+			},
+		}
+		helper.On("Sign", ctx, signingPayloads).Return(signatures, nil).Once()
+		helper.On(
+			"Combine",
+			ctx,
+			network,
+			unsignedTx,
+			signatures,
+		).Return(networkTx, nil).Once()
+		helper.On(
+			"Parse",
+			ctx,
+			network,
+			true,
+			networkTx,
+		).Return(
+			bumpOps,
+			[]*types.AccountIdentifier{{Address: "address1"}},
+			nil,
+			nil,
+		).Once()
+		helper.On(
+			"Hash",
+			ctx,
+			network,
+			networkTx,
+		).Return(&types.TransactionIdentifier{Hash: "bumped hash"}, nil).Once()
+
+		broadcast := newBroadcast(3)
+		identifier, payload, err := c.BumpFee(ctx, broadcast)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.TransactionIdentifier{Hash: "bumped hash"}, identifier)
+		assert.Equal(t, networkTx, payload)
+		assert.Equal(t, bumpOps, broadcast.Intent)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("no replacement", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows(&job.Workflow{
+				Name:        string(job.BumpFee),
+				Concurrency: 1,
+				Scenarios: []*job.Scenario{
+					{
+						Name: "log",
+						Actions: []*job.Action{
+							{
+								Type:  job.PrintMessage,
+								Input: `{"stuck": {{stuck_broadcast.transaction_identifier}}}`,
+							},
+						},
+					},
+				},
+			}),
+		)
+		assert.NoError(t, err)
+
+		dbTx := db.Transaction(ctx)
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(&job.Job{
+			Identifier: jobIdentifier,
+			Workflow:   "transfer",
+		}, nil).Once()
+		helper.On(
+			"Parse",
+			ctx,
+			network,
+			true,
+			"stuck transaction",
+		).Return(stuckOps, []*types.AccountIdentifier{{Address: "address1"}}, nil, nil).Once()
+
+		broadcast := newBroadcast(1)
+		identifier, payload, err := c.BumpFee(ctx, broadcast)
+		assert.NoError(t, err)
+		assert.Nil(t, identifier)
+		assert.Empty(t, payload)
+		assert.Equal(t, stuckOps, broadcast.Intent)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("missing job", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows(replaceByFee),
+		)
+		assert.NoError(t, err)
+
+		dbTx := db.Transaction(ctx)
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(
+			nil,
+			errors.New("not found"),
+		).Once()
+
+		identifier, payload, err := c.BumpFee(ctx, newBroadcast(1))
+		assert.True(t, errors.Is(err, ErrFeeBumpFailed))
+		assert.Contains(t, err.Error(), ErrJobMissing.Error())
+		assert.Nil(t, identifier)
+		assert.Empty(t, payload)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	primary := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	secondary := &types.NetworkIdentifier{Blockchain: "Ethereum", Network: "Ropsten"}
+	currency := &types.Currency{Symbol: "ETH", Decimals: 18}
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: "Transfer",
+			Account: &types.AccountIdentifier{
+				Address: "0x1",
+			},
+			Amount: &types.Amount{
+				Value:    "-10",
+				Currency: currency,
+			},
+		},
+	}
+
+	defaultHelper := &mocks.Helper{}
+	secondaryHelper := &mocks.Helper{}
+	primaryParser := defaultParser(t)
+	secondaryParser := parser.New(nil, nil, nil)
+	registry := NewRegistry()
+	registry.Register(secondary, secondaryHelper, secondaryParser)
+	assert.Equal(t, []*types.NetworkIdentifier{secondary}, registry.Networks())
+
+	c, err := New(
+		&mocks.JobStorage{},
+		defaultHelper,
+		&mocks.Handler{},
+		primaryParser,
+		[]*job.Workflow{
+			{
+				Name:        string(job.RequestFunds),
+				Concurrency: 1,
+			},
+		},
+		WithRegistry(registry),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, defaultHelper, c.helperFor(primary))
+	assert.Equal(t, primaryParser, c.parserFor(primary))
+	assert.Equal(t, secondaryHelper, c.helperFor(secondary))
+	assert.Equal(t, secondaryParser, c.parserFor(secondary))
+
+	// Construction on the secondary network uses the
+	// secondary Helper.
+	metadataOptions := map[string]interface{}{
+		"metadata": "test",
+	}
+	secondaryHelper.On(
+		"Preprocess",
+		ctx,
+		secondary,
+		ops,
+		(map[string]interface{})(nil),
+	).Return(metadataOptions, nil, nil).Once()
+	suggestedFee := []*types.Amount{
+		{
+			Value:    "1",
+			Currency: currency,
+		},
+	}
+	secondaryHelper.On(
+		"Metadata",
+		ctx,
+		secondary,
+		metadataOptions,
+		[]*types.PublicKey{},
+	).Return(map[string]interface{}{}, suggestedFee, nil).Once()
+	_, _, fee, err := c.createTransaction(ctx, nil, &job.Broadcast{
+		Network: secondary,
+		Intent:  ops,
+		DryRun:  true,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, suggestedFee, fee)
+
+	// Workers also resolve the Helper for the network.
+	publicKey := &types.PublicKey{
+		Bytes:     []byte("hello"),
+		CurveType: types.Secp256k1,
+	}
+	secondaryHelper.On(
+		"Derive",
+		ctx,
+		secondary,
+		publicKey,
+		(map[string]interface{})(nil),
+	).Return(&types.AccountIdentifier{Address: "0x2"}, nil, nil).Once()
+	_, err = c.worker.DeriveWorker(ctx, types.PrintStruct(&types.ConstructionDeriveRequest{
+		NetworkIdentifier: secondary,
+		PublicKey:         publicKey,
+	}))
+	assert.NoError(t, err)
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	// Helpers of registered networks use their own
+	// database.Transaction (instead of the transaction
+	// of the Coordinator's Helper).
+	primaryTx := db.ReadTransaction(ctx)
+	defer primaryTx.Discard(ctx)
+	secondaryTx := db.ReadTransaction(ctx)
+	defer secondaryTx.Discard(ctx)
+
+	networkTx, separate := c.networkTransaction(ctx, primaryTx, primary)
+	assert.Equal(t, primaryTx, networkTx)
+	assert.False(t, separate)
+
+	secondaryHelper.On("DatabaseTransaction", ctx).Return(secondaryTx).Once()
+	networkTx, separate = c.networkTransaction(ctx, primaryTx, secondary)
+	assert.Equal(t, secondaryTx, networkTx)
+	assert.True(t, separate)
+
+	account := &types.AccountIdentifier{Address: "0x2"}
+	secondaryHelper.On("DatabaseTransaction", ctx).Return(secondaryTx).Once()
+	secondaryHelper.On(
+		"Balance",
+		ctx,
+		secondaryTx,
+		account,
+		currency,
+	).Return(&types.Amount{Value: "10", Currency: currency}, nil).Once()
+	amount, err := c.workerHelper(secondary).Balance(ctx, primaryTx, account, currency)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", amount.Value)
+	assert.Equal(t, defaultHelper, c.workerHelper(primary))
+
+	// Broadcasts on registered networks are also
+	// broadcast by the network's Helper.
+	assert.Equal(t, []Helper{secondaryHelper}, c.registeredHelpers())
+
+	defaultHelper.AssertExpectations(t)
+	secondaryHelper.AssertExpectations(t)
+}
+
+func TestRegisteredBroadcastComplete(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	jobStorage := &mocks.JobStorage{}
+	helper := &mocks.Helper{}
+	c, err := New(
+		jobStorage,
+		helper,
+		&mocks.Handler{},
+		defaultParser(t),
+		[]*job.Workflow{
+			{
+				Name:        string(job.RequestFunds),
+				Concurrency: 1,
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	// Jobs are loaded in a transaction of the
+	// Coordinator's Helper.
+	dbTx := db.ReadTransaction(ctx)
+	helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+	jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(nil, errors.New("missing")).Once()
+
+	err = c.RegisteredBroadcastComplete(ctx, jobIdentifier, nil)
+	assert.True(t, errors.Is(err, ErrJobMissing))
+	helper.AssertExpectations(t)
+	jobStorage.AssertExpectations(t)
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+
+	network := &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Testnet3",
+	}
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: "Vin",
+			Account: &types.AccountIdentifier{
+				Address: "address1",
+			},
+			Amount: &types.Amount{
+				Value: "-100",
+				Currency: &types.Currency{
+					Symbol:   "tBTC",
+					Decimals: 8,
+				},
+			},
+		},
+	}
+	workflows := []*job.Workflow{
+		{
+			Name:        string(job.RequestFunds),
+			Concurrency: 1,
+		},
+		{
+			Name:        string(job.CreateAccount),
+			Concurrency: 1,
+		},
+		{
+			Name:        "transfer",
+			Concurrency: 1,
+			Scenarios: []*job.Scenario{
+				{
+					Name: "transfer",
+					Actions: []*job.Action{
+						{
+							Type:       job.SetVariable,
+							Input:      types.PrintStruct(network),
+							OutputPath: "transfer.network",
+						},
+						{
+							Type:       job.SetVariable,
+							Input:      `"1"`,
+							OutputPath: "transfer.confirmation_depth",
+						},
+						{
+							Type:       job.SetVariable,
+							Input:      types.PrintStruct(ops),
+							OutputPath: "transfer.operations",
+						},
+					},
+				},
+				{
+					Name: "estimate",
+					Actions: []*job.Action{
+						{
+							Type:       job.SetVariable,
+							Input:      types.PrintStruct(network),
+							OutputPath: "estimate.network",
+						},
+						{
+							Type:       job.SetVariable,
+							Input:      `"1"`,
+							OutputPath: "estimate.confirmation_depth",
+						},
+						{
+							Type:       job.SetVariable,
+							Input:      `{{transfer.transaction.operations}}`,
+							OutputPath: "estimate.operations",
+						},
+						{
+							Type:       job.SetVariable,
+							Input:      `{"parent":{{transfer.transaction.transaction_identifier.hash}}}`,
+							OutputPath: "estimate.preprocess_metadata",
+						},
+						{
+							Type:       job.SetVariable,
+							Input:      `"true"`,
+							OutputPath: "estimate.dry_run",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	defer db.Close(ctx)
+
+	metadataOptions := map[string]interface{}{
+		"metadata": "test",
+	}
+	fetchedMetadata := map[string]interface{}{
+		"tx_meta": "help",
+	}
+	signingPayloads := []*types.SigningPayload{
+		{
+			AccountIdentifier: &types.AccountIdentifier{Address: "address1"},
+			Bytes:             []byte("blah"),
+			SignatureType:     types.Ecdsa,
+		},
+	}
+	signatures := []*types.Signature{
+		{
+			SigningPayload: signingPayloads[0],
+			PublicKey: &types.PublicKey{
+				Bytes:     []byte("pubkey"),
+				CurveType: types.Secp256k1,
+			},
+			SignatureType: types.Ecdsa,
+			Bytes:         []byte("signature"),
+		},
+	}
+	signers := []*types.AccountIdentifier{{Address: "address1"}}
+	transactionIdentifier := &types.TransactionIdentifier{Hash: "hash"}
+
+	t.Run("full plan", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		c, err := New(
+			&mocks.JobStorage{},
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+		)
+		assert.NoError(t, err)
+
+		helper.On("DatabaseTransaction", ctx).Return(db.Transaction(ctx)).Once()
+		helper.On(
+			"Preprocess",
+			ctx,
+			network,
+			ops,
+			(map[string]interface{})(nil),
+		).Return(metadataOptions, nil, nil).Once()
+		helper.On(
+			"Metadata",
+			ctx,
+			network,
+			metadataOptions,
+			[]*types.PublicKey{},
+		).Return(fetchedMetadata, nil, nil).Once()
+		helper.On(
+			"Payloads",
+			ctx,
+			network,
+			ops,
+			fetchedMetadata,
+			[]*types.PublicKey{},
+		).Return(unsignedTx, signingPayloads, nil).Once()
+		helper.On(
+			"Parse",
+			ctx,
+			network,
+			false,
+			unsignedTx,
+		).Return(ops, []*types.AccountIdentifier{}, nil, nil).Once()
+		helper.On("Sign", ctx, signingPayloads).Return(signatures, nil).Once()
+		helper.On(
+			"Combine",
+			ctx,
+			network,
+			unsignedTx,
+			signatures,
+		).Return(networkTx, nil).Once()
+		helper.On(
+			"Parse",
+			ctx,
+			network,
+			true,
+			networkTx,
+		).Return(ops, signers, nil, nil).Once()
+		helper.On(
+			"Hash",
+			ctx,
+			network,
+			networkTx,
+		).Return(transactionIdentifier, nil).Once()
+
+		// The second scenario depends on the
+		// planned transaction.
+		helper.On(
+			"Preprocess",
+			ctx,
+			network,
+			ops,
+			map[string]interface{}{"parent": "hash"},
+		).Return(metadataOptions, nil, nil).Once()
+		suggestedFee := []*types.Amount{
+			{
+				Value:    "10",
+				Currency: ops[0].Amount.Currency,
+			},
+		}
+		helper.On(
+			"Metadata",
+			ctx,
+			network,
+			metadataOptions,
+			[]*types.PublicKey{},
+		).Return(fetchedMetadata, suggestedFee, nil).Once()
+
+		report, err := c.Plan(ctx, "transfer")
+		assert.NoError(t, err)
+		assert.Equal(t, &PlanReport{
+			Workflow: "transfer",
+			Steps: []*PlanStep{
+				{
+					Scenario:              "transfer",
+					Network:               network,
+					Intent:                ops,
+					PreprocessOptions:     metadataOptions,
+					Metadata:              fetchedMetadata,
+					UnsignedTransaction:   unsignedTx,
+					Payloads:              signingPayloads,
+					UnsignedOperations:    ops,
+					Signatures:            signatures,
+					SignedTransaction:     networkTx,
+					SignedOperations:      ops,
+					Signers:               signers,
+					TransactionIdentifier: transactionIdentifier,
+				},
+				{
+					Scenario:          "estimate",
+					Network:           network,
+					Intent:            ops,
+					DryRun:            true,
+					PreprocessOptions: metadataOptions,
+					Metadata:          fetchedMetadata,
+					SuggestedFee:      suggestedFee,
+				},
+			},
+		}, report)
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("construction failure", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		c, err := New(
+			&mocks.JobStorage{},
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+		)
+		assert.NoError(t, err)
+
+		helper.On("DatabaseTransaction", ctx).Return(db.Transaction(ctx)).Once()
+		helper.On(
+			"Preprocess",
+			ctx,
+			network,
+			ops,
+			(map[string]interface{})(nil),
+		).Return(metadataOptions, nil, nil).Once()
+		helper.On(
+			"Metadata",
+			ctx,
+			network,
+			metadataOptions,
+			[]*types.PublicKey{},
+		).Return(nil, nil, errors.New("node offline")).Once()
+
+		report, err := c.Plan(ctx, "transfer")
+		assert.True(t, errors.Is(err, ErrPlanFailed))
+		assert.Equal(t, &PlanReport{
+			Workflow: "transfer",
+			Steps: []*PlanStep{
+				{
+					Scenario:          "transfer",
+					Network:           network,
+					Intent:            ops,
+					PreprocessOptions: metadataOptions,
+				},
+			},
+		}, report)
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("missing workflow", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		c, err := New(
+			&mocks.JobStorage{},
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+		)
+		assert.NoError(t, err)
+
+		report, err := c.Plan(ctx, "stake")
+		assert.True(t, errors.Is(err, ErrWorkflowMissing))
+		assert.Nil(t, report)
+
+		helper.AssertExpectations(t)
+	})
+}
+
+func TestTopUp(t *testing.T) {
+	ctx := context.Background()
+
+	currency := &types.Currency{
+		Symbol:   "tBTC",
+		Decimals: 8,
+	}
+	account := &job.PrefundedAccount{
+		AccountIdentifier: &types.AccountIdentifier{Address: "faucet"},
+		Currency:          currency,
+		SpendLimit:        "1000",
+		MinimumBalance:    "100",
+	}
+	topUp := &job.Workflow{
+		Name:        string(job.TopUp),
+		Concurrency: 1,
+		Scenarios: []*job.Scenario{
+			{
+				Name: "top_up",
+				Actions: []*job.Action{
+					{
+						Type:  job.PrintMessage,
+						Input: `{"account": {{prefunded_account.account.account_identifier}}}`,
+					},
+				},
+			},
+		},
+	}
+	workflows := []*job.Workflow{
+		{
+			Name:        string(job.RequestFunds),
+			Concurrency: 1,
+		},
+		{
+			Name:        string(job.CreateAccount),
+			Concurrency: 1,
+		},
+		{
+			Name:        "transfer",
+			Concurrency: 1,
+		},
+		topUp,
+	}
+
+	t.Run("incorrect concurrency", func(t *testing.T) {
+		c, err := New(
+			&mocks.JobStorage{},
+			&mocks.Helper{},
+			&mocks.Handler{},
+			defaultParser(t),
+			[]*job.Workflow{
+				{
+					Name:        string(job.TopUp),
+					Concurrency: 2,
+				},
+			},
+		)
+		assert.Nil(t, c)
+		assert.True(t, errors.Is(err, ErrIncorrectConcurrency))
+	})
+
+	newCoordinator := func(
+		t *testing.T,
+		jobStorage *mocks.JobStorage,
+		helper *mocks.Helper,
+	) *Coordinator {
+		prefunded, err := worker.NewPrefundedAccounts([]*job.PrefundedAccount{account})
+		assert.NoError(t, err)
+
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			WithPrefundedAccounts(prefunded),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, c.workflows, 1)
+
+		return c
+	}
+
+	t.Run("underfunded", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c := newCoordinator(t, jobStorage, helper)
+
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, string(job.TopUp)).Return(
+			[]*job.Job{},
+			nil,
+		).Once()
+		helper.On("GetBlob", ctx, mock.Anything, mock.Anything).Return(
+			true,
+			[]byte("250"),
+			nil,
+		).Once()
+		balance := &types.Amount{Value: "50", Currency: currency}
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			account.AccountIdentifier,
+			currency,
+		).Return(balance, nil).Once()
+
+		j, err := c.findJob(ctx, nil, false)
+		assert.NoError(t, err)
+		assert.Equal(t, string(job.TopUp), j.Workflow)
+		assert.JSONEq(t, types.PrintStruct(&job.PrefundedAccountStatus{
+			Account:     account,
+			Balance:     balance,
+			Spent:       "250",
+			Underfunded: true,
+		}), gjson.Get(j.State, job.PrefundedAccountVariable).Raw)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("spend limit reached", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c := newCoordinator(t, jobStorage, helper)
+
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, string(job.TopUp)).Return(
+			[]*job.Job{},
+			nil,
+		).Once()
+		helper.On("GetBlob", ctx, mock.Anything, mock.Anything).Return(
+			true,
+			[]byte("1000"),
+			nil,
+		).Once()
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			account.AccountIdentifier,
+			currency,
+		).Return(&types.Amount{Value: "50", Currency: currency}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, "transfer").Return(
+			[]*job.Job{},
+			nil,
+		).Once()
+
+		j, err := c.findJob(ctx, nil, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "transfer", j.Workflow)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("broadcast failed", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c := newCoordinator(t, jobStorage, helper)
+
+		network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+		intent := []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{Index: 0},
+				Account:             account.AccountIdentifier,
+				Amount:              &types.Amount{Value: "-100", Currency: currency},
+			},
+		}
+		j := &job.Job{
+			Identifier: "job1",
+			Workflow:   "transfer",
+			Status:     job.Broadcasting,
+			Index:      1,
+			Scenarios:  []*job.Scenario{{Name: "transfer"}},
+			State: fmt.Sprintf(
+				`{"transfer":{"network":%s,"operations":%s}}`,
+				types.PrintStruct(network),
+				types.PrintStruct(intent),
+			),
+		}
+
+		// The spend of the failed broadcast is released.
+		jobStorage.On("Get", ctx, mock.Anything, "job1").Return(j, nil).Once()
+		helper.On("GetBlob", ctx, mock.Anything, mock.Anything).Return(
+			true,
+			[]byte("250"),
+			nil,
+		).Once()
+		helper.On("SetBlob", ctx, mock.Anything, mock.Anything, []byte("150")).Return(nil).Once()
+		jobStorage.On("Update", ctx, mock.Anything, j).Return("job1", nil).Once()
+
+		assert.NoError(t, c.BroadcastComplete(ctx, nil, "job1", nil))
+		assert.Equal(t, job.Failed, j.Status)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("top up processing", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c := newCoordinator(t, jobStorage, helper)
+
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, string(job.TopUp)).Return(
+			[]*job.Job{job.New(topUp)},
+			nil,
+		).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, "transfer").Return(
+			[]*job.Job{},
+			nil,
+		).Once()
+
+		j, err := c.findJob(ctx, nil, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "transfer", j.Workflow)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+}
+
+func TestSchedule(t *testing.T) {
+	ctx := context.Background()
+
+	workflows := []*job.Workflow{
+		{
+			Name:        string(job.RequestFunds),
+			Concurrency: 1,
+		},
+		{
+			Name:        string(job.CreateAccount),
+			Concurrency: 1,
+		},
+		{
+			Name:        "transfer",
+			Concurrency: 10,
+		},
+		{
+			Name:        "stake",
+			Concurrency: 10,
+		},
+	}
+
+	t.Run("invalid schedules", func(t *testing.T) {
+		for name, test := range map[string]struct {
+			workflow string
+			schedule *Schedule
+
+			err error
+		}{
+			"missing schedule": {
+				workflow: "transfer",
+				err:      ErrScheduleInvalid,
+			},
+			"interval and rate": {
+				workflow: "transfer",
+				schedule: &Schedule{Interval: time.Second, Rate: 1},
+				err:      ErrScheduleInvalid,
+			},
+			"negative jitter": {
+				workflow: "transfer",
+				schedule: &Schedule{Rate: 1, Jitter: -1},
+				err:      ErrScheduleInvalid,
+			},
+			"interval and cron": {
+				workflow: "transfer",
+				schedule: &Schedule{Interval: time.Second, Cron: "* * * * *"},
+				err:      ErrScheduleInvalid,
+			},
+			"invalid cron": {
+				workflow: "transfer",
+				schedule: &Schedule{Cron: "61 * * * *"},
+				err:      ErrScheduleInvalid,
+			},
+			"cron never matches": {
+				workflow: "transfer",
+				schedule: &Schedule{Cron: "0 0 30 2 *"},
+				err:      ErrScheduleInvalid,
+			},
+			"missing workflow": {
+				workflow: "withdraw",
+				schedule: &Schedule{Rate: 1},
+				err:      ErrWorkflowMissing,
+			},
+			"reserved workflow": {
+				workflow: string(job.RequestFunds),
+				schedule: &Schedule{Rate: 1},
+				err:      ErrWorkflowMissing,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				c, err := New(
+					&mocks.JobStorage{},
+					&mocks.Helper{},
+					&mocks.Handler{},
+					defaultParser(t),
+					workflows,
+					WithSchedule(test.workflow, test.schedule),
+				)
+				assert.Nil(t, c)
+				assert.True(t, errors.Is(err, test.err))
+			})
+		}
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		assert.Equal(t, 250*time.Millisecond, (&Schedule{Rate: 4}).interval())
+		assert.Equal(t, time.Minute, (&Schedule{Interval: time.Minute}).interval())
+
+		s := newScheduler()
+		s.schedules["transfer"] = &Schedule{Interval: time.Minute, Jitter: time.Second}
+		now := time.Now()
+		assert.Equal(t, time.Duration(0), s.due("transfer", now))
+
+		s.started("transfer", now)
+		wait := s.due("transfer", now)
+		assert.True(t, wait >= time.Minute)
+		assert.True(t, wait < time.Minute+time.Second)
+		assert.Equal(t, time.Duration(0), s.due("transfer", now.Add(2*time.Minute)))
+
+		// Workflows without a schedule are always due.
+		s.started("stake", now)
+		assert.Equal(t, time.Duration(0), s.due("stake", now))
+	})
+
+	t.Run("cron", func(t *testing.T) {
+		for expression, test := range map[string]struct {
+			now  time.Time
+			next time.Time
+		}{
+			"*/15 * * * *": {
+				now:  time.Date(2022, 3, 1, 10, 16, 30, 0, time.UTC),
+				next: time.Date(2022, 3, 1, 10, 30, 0, 0, time.UTC),
+			},
+			"@hourly": {
+				now:  time.Date(2022, 3, 1, 23, 0, 0, 0, time.UTC),
+				next: time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC),
+			},
+			"0 9-17/4 * * 1-5": { // weekdays at 9, 13, and 17
+				now:  time.Date(2022, 3, 4, 17, 30, 0, 0, time.UTC), // Friday
+				next: time.Date(2022, 3, 7, 9, 0, 0, 0, time.UTC),
+			},
+			"0 0 1 * 7": { // the 1st of the month or Sunday
+				now:  time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+				next: time.Date(2022, 3, 6, 0, 0, 0, 0, time.UTC),
+			},
+			"0 0 29 2 *": {
+				now:  time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+				next: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		} {
+			cron, err := parseCron(expression)
+			assert.NoError(t, err)
+			assert.Equal(t, test.next, cron.next(test.now), expression)
+		}
+
+		s := newScheduler()
+		s.schedules["transfer"] = &Schedule{Cron: "0 * * * *"}
+		now := time.Date(2022, 3, 1, 10, 59, 0, 0, time.UTC)
+
+		// The first Job waits for the first matching time.
+		assert.Equal(t, time.Minute, s.due("transfer", now))
+		assert.Equal(t, time.Duration(0), s.due("transfer", now.Add(time.Minute)))
+
+		s.started("transfer", now.Add(time.Minute))
+		assert.Equal(t, time.Hour, s.due("transfer", now.Add(time.Minute)))
+	})
+
+	t.Run("scheduled workflow", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		c, err := New(
+			jobStorage,
+			&mocks.Helper{},
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows[:3],
+			WithSchedule("transfer", &Schedule{Interval: time.Minute}),
+		)
+		assert.NoError(t, err)
+
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, "transfer").Return(
+			[]*job.Job{},
+			nil,
+		).Once()
+		j, err := c.findJob(ctx, nil, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "transfer", j.Workflow)
+
+		// Once started, the workflow is not due
+		// until the interval has elapsed.
+		c.scheduler.started("transfer", time.Now())
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Broadcasting", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		j, err = c.findJob(ctx, nil, false)
+		assert.Nil(t, j)
+		assert.True(t, errors.Is(err, ErrWorkflowsScheduled))
+		assert.True(t, c.scheduledWait > 59*time.Second)
+
+		jobStorage.AssertExpectations(t)
+	})
+
+	t.Run("max concurrency", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		c, err := New(
+			jobStorage,
+			&mocks.Helper{},
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			WithMaxConcurrency(2),
+		)
+		assert.NoError(t, err)
+
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, "transfer").Return(
+			[]*job.Job{{Workflow: "transfer"}},
+			nil,
+		).Twice()
+		jobStorage.On("Processing", ctx, mock.Anything, "stake").Return(
+			[]*job.Job{},
+			nil,
+		).Once()
+		j, err := c.findJob(ctx, nil, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "transfer", j.Workflow)
+
+		jobStorage.On("Ready", ctx, mock.Anything).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, "transfer").Return(
+			[]*job.Job{{Workflow: "transfer"}},
+			nil,
+		).Once()
+		jobStorage.On("Processing", ctx, mock.Anything, "stake").Return(
+			[]*job.Job{{Workflow: "stake"}},
+			nil,
+		).Once()
+		jobStorage.On("Broadcasting", ctx, mock.Anything).Return(
+			[]*job.Job{{Workflow: "stake"}},
+			nil,
+		).Once()
+		j, err = c.findJob(ctx, nil, false)
+		assert.Nil(t, j)
+		assert.True(t, errors.Is(err, ErrNoAvailableJobs))
+
+		jobStorage.AssertExpectations(t)
+	})
+}
+
+type finalityHelper struct {
+	*mocks.Helper
+}
+
+func (h *finalityHelper) Call(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	method string,
+	parameters map[string]interface{},
+) (map[string]interface{}, error) {
+	ret := h.Called(ctx, network, method, parameters)
+
+	var result map[string]interface{}
+	if ret.Get(0) != nil {
+		result = ret.Get(0).(map[string]interface{})
+	}
+
+	return result, ret.Error(1)
+}
+
+type confirmationHandler struct {
+	*mocks.Handler
+}
+
+func (h *confirmationHandler) BroadcastConfirmed(
+	ctx context.Context,
+	jobIdentifier string,
+	confirmation *Confirmation,
+) error {
+	return h.Called(ctx, jobIdentifier, confirmation).Error(0)
+}
+
+func TestFinality(t *testing.T) {
+	ctx := context.Background()
+
+	network := &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Testnet3",
+	}
+	currency := &types.Currency{
+		Symbol:   "tBTC",
+		Decimals: 8,
+	}
+	intent := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: "Vin",
+			Account: &types.AccountIdentifier{
+				Address: "address1",
+			},
+			Amount: &types.Amount{
+				Value:    "-100",
+				Currency: currency,
+			},
+		},
+	}
+	workflows := []*job.Workflow{
+		{
+			Name:        string(job.RequestFunds),
+			Concurrency: 1,
+		},
+		{
+			Name:        string(job.CreateAccount),
+			Concurrency: 1,
+		},
+		{
+			Name:        "transfer",
+			Concurrency: 10,
+		},
+		{
+			Name:        "stake",
+			Concurrency: 10,
+		},
+	}
+	policies := []Option{
+		WithFinalityPolicy("transfer", &FinalityPolicy{
+			ConfirmationDepth: 5,
+			Method:            "finality",
+			Parameters:        map[string]interface{}{"commitment": "finalized"},
+			ResultPath:        "status.finalized",
+		}),
+		WithFinalityPolicy("stake", &FinalityPolicy{
+			ConfirmationDepth: 10,
+		}),
+	}
+	broadcast := &modules.Broadcast{
+		Identifier:            jobIdentifier,
+		NetworkIdentifier:     network,
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx hash"},
+		Intent:                intent,
+	}
+	block := &types.BlockIdentifier{Hash: "block 10", Index: 10}
+	callParameters := map[string]interface{}{
+		"commitment":             "finalized",
+		"transaction_identifier": broadcast.TransactionIdentifier,
+		"block_identifier":       block,
+	}
+	broadcastingJob := func(workflow string) *job.Job {
+		return &job.Job{
+			Identifier: jobIdentifier,
+			Workflow:   workflow,
+			Status:     job.Broadcasting,
+			Index:      1,
+			Scenarios:  []*job.Scenario{{Name: workflow}},
+			State: fmt.Sprintf(
+				`{"%s":{"network":%s,"operations":%s}}`,
+				workflow,
+				types.PrintStruct(network),
+				types.PrintStruct(intent),
+			),
+		}
+	}
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	defer db.Close(ctx)
+
+	t.Run("invalid policies", func(t *testing.T) {
+		for name, test := range map[string]struct {
+			workflow string
+			policy   *FinalityPolicy
+
+			err error
+		}{
+			"missing policy": {
+				workflow: "transfer",
+				err:      ErrFinalityPolicyInvalid,
+			},
+			"negative depth": {
+				workflow: "transfer",
+				policy:   &FinalityPolicy{ConfirmationDepth: -1},
+				err:      ErrFinalityPolicyInvalid,
+			},
+			"result path without method": {
+				workflow: "transfer",
+				policy:   &FinalityPolicy{ResultPath: "finalized"},
+				err:      ErrFinalityPolicyInvalid,
+			},
+			"missing workflow": {
+				workflow: "withdraw",
+				policy:   &FinalityPolicy{ConfirmationDepth: 1},
+				err:      ErrWorkflowMissing,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				c, err := New(
+					&mocks.JobStorage{},
+					&mocks.Helper{},
+					&mocks.Handler{},
+					defaultParser(t),
+					workflows,
+					WithFinalityPolicy(test.workflow, test.policy),
+				)
+				assert.Nil(t, c)
+				assert.True(t, errors.Is(err, test.err))
+			})
+		}
+	})
+
+	t.Run("confirmation depth", func(t *testing.T) {
+		c, err := New(
+			&mocks.JobStorage{},
+			&mocks.Helper{},
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			policies...,
+		)
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(5), c.confirmationDepth("transfer", 1))
+		assert.Equal(t, int64(10), c.confirmationDepth("stake", 1))
+		assert.Equal(t, int64(1), c.confirmationDepth(string(job.RequestFunds), 1))
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(jobStorage, helper, &mocks.Handler{}, defaultParser(t), workflows)
+		assert.NoError(t, err)
+
+		finalized, err := c.Finalized(ctx, nil, broadcast, block)
+		assert.NoError(t, err)
+		assert.True(t, finalized)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("policy without method", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			&finalityHelper{helper},
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			policies...,
+		)
+		assert.NoError(t, err)
+
+		dbTx := db.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+		jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(
+			broadcastingJob("stake"),
+			nil,
+		).Once()
+
+		finalized, err := c.Finalized(ctx, dbTx, broadcast, block)
+		assert.NoError(t, err)
+		assert.True(t, finalized)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("finality unsupported", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			policies...,
+		)
+		assert.NoError(t, err)
+
+		dbTx := db.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+		jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(
+			broadcastingJob("transfer"),
+			nil,
+		).Once()
+
+		finalized, err := c.Finalized(ctx, dbTx, broadcast, block)
+		assert.True(t, errors.Is(err, ErrFinalityUnsupported))
+		assert.False(t, finalized)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("finality signal", func(t *testing.T) {
+		for name, test := range map[string]struct {
+			result map[string]interface{}
+
+			finalized bool
+			err       error
+		}{
+			"finalized": {
+				result:    map[string]interface{}{"status": map[string]interface{}{"finalized": true}},
+				finalized: true,
+			},
+			"not finalized": {
+				result: map[string]interface{}{"status": map[string]interface{}{"finalized": false}},
+			},
+			"missing result": {
+				result: map[string]interface{}{"status": map[string]interface{}{}},
+				err:    ErrFinalityCheckFailed,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				jobStorage := &mocks.JobStorage{}
+				helper := &mocks.Helper{}
+				c, err := New(
+					jobStorage,
+					&finalityHelper{helper},
+					&mocks.Handler{},
+					defaultParser(t),
+					workflows,
+					policies...,
+				)
+				assert.NoError(t, err)
+
+				dbTx := db.Transaction(ctx)
+				defer dbTx.Discard(ctx)
+				jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(
+					broadcastingJob("transfer"),
+					nil,
+				).Once()
+				helper.On(
+					"Call",
+					ctx,
+					network,
+					"finality",
+					callParameters,
+				).Return(test.result, nil).Once()
+
+				finalized, err := c.Finalized(ctx, dbTx, broadcast, block)
+				if test.err != nil {
+					assert.True(t, errors.Is(err, test.err))
+				} else {
+					assert.NoError(t, err)
+				}
+				assert.Equal(t, test.finalized, finalized)
+
+				jobStorage.AssertExpectations(t)
+				helper.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("broadcast confirmed", func(t *testing.T) {
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		handler := &mocks.Handler{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&confirmationHandler{handler},
+			defaultParser(t),
+			workflows,
+		)
+		assert.NoError(t, err)
+
+		observed := []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type:   "Vin",
+				Status: types.String("success"),
+				Account: &types.AccountIdentifier{
+					Address: "address1",
+				},
+				Amount: &types.Amount{
+					Value:    "-100",
+					Currency: currency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type:   "Vout",
+				Status: types.String("success"),
+				Account: &types.AccountIdentifier{
+					Address: "address2",
+				},
+				Amount: &types.Amount{
+					Value:    "90",
+					Currency: currency,
+				},
+			},
+		}
+		transaction := &types.Transaction{
+			TransactionIdentifier: broadcast.TransactionIdentifier,
+			Operations:            observed,
+		}
+
+		dbTx := db.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		j := broadcastingJob("transfer")
+		jobStorage.On("Get", ctx, dbTx, jobIdentifier).Return(j, nil).Once()
+		jobStorage.On("Update", ctx, dbTx, j).Return(jobIdentifier, nil).Once()
+		handler.On(
+			"BroadcastConfirmed",
+			ctx,
+			jobIdentifier,
+			mock.Anything,
+		).Return(nil).Run(func(args mock.Arguments) {
+			confirmation := args.Get(2).(*Confirmation)
+			assert.Equal(t, "transfer", confirmation.Workflow)
+			assert.Equal(t, transaction, confirmation.Transaction)
+			assert.Equal(t, intent, confirmation.Intent)
+			assert.Len(t, confirmation.Diff.MissingIntent, 0)
+			assert.Len(t, confirmation.Diff.ExtraObserved, 1)
+			assert.Equal(t, observed[1], confirmation.Diff.ExtraObserved[0].Operation)
+		}).Once()
+
+		assert.NoError(t, c.BroadcastComplete(ctx, dbTx, jobIdentifier, transaction))
+		assert.Equal(t, job.Completed, j.Status)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+	})
+}
+
+type broadcastStateHelper struct {
+	*mocks.Helper
+}
+
+func (h *broadcastStateHelper) GetAllBroadcasts(
+	ctx context.Context,
+) ([]*modules.Broadcast, error) {
+	ret := h.Called(ctx)
+
+	var broadcasts []*modules.Broadcast
+	if ret.Get(0) != nil {
+		broadcasts = ret.Get(0).([]*modules.Broadcast)
+	}
+
+	return broadcasts, ret.Error(1)
+}
+
+func TestState(t *testing.T) {
+	ctx := context.Background()
+
+	network := &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Testnet3",
+	}
+	workflows := []*job.Workflow{
+		{
+			Name:        "transfer",
+			Concurrency: 10,
+			Scenarios: []*job.Scenario{
+				{Name: "create"},
+				{Name: "transfer"},
+			},
+		},
+	}
+	readyJob := &job.Job{
+		Workflow:  "transfer",
+		Status:    job.Ready,
+		Index:     1,
+		Scenarios: workflows[0].Scenarios,
+		State:     `{"create":{"account":"addr 1"}}`,
+	}
+	broadcastingJob := &job.Job{
+		Workflow:  "transfer",
+		Status:    job.Broadcasting,
+		Index:     2,
+		Scenarios: workflows[0].Scenarios,
+		State:     `{"create":{"account":"addr 2"}}`,
+	}
+	broadcast := &modules.Broadcast{
+		NetworkIdentifier:     network,
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx hash"},
+		Payload:               "signed tx",
+		ConfirmationDepth:     3,
+	}
+
+	newDatabase := func(t *testing.T) (database.Database, func()) {
+		dir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+
+		db, err := database.NewBadgerDatabase(
+			ctx,
+			dir,
+			database.WithIndexCacheSize(database.TinyIndexCacheSize),
+		)
+		assert.NoError(t, err)
+
+		return db, func() {
+			db.Close(ctx)
+			utils.RemoveTempDir(dir)
+		}
+	}
+
+	sourceDB, closeSource := newDatabase(t)
+	defer closeSource()
+	sourceStorage := modules.NewJobStorage(sourceDB)
+
+	dbTx := sourceDB.Transaction(ctx)
+	_, err := sourceStorage.Update(ctx, dbTx, readyJob)
+	assert.NoError(t, err)
+	_, err = sourceStorage.Update(ctx, dbTx, broadcastingJob)
+	assert.NoError(t, err)
+	assert.NoError(t, dbTx.Commit(ctx))
+	broadcast.Identifier = broadcastingJob.Identifier
+
+	var state *State
+	t.Run("export unsupported", func(t *testing.T) {
+		c, err := New(&mocks.JobStorage{}, &mocks.Helper{}, &mocks.Handler{}, nil, workflows)
+		assert.NoError(t, err)
+
+		exported, err := c.ExportState(ctx)
+		assert.Nil(t, exported)
+		assert.True(t, errors.Is(err, ErrStateUnsupported))
+
+		c, err = New(sourceStorage, &mocks.Helper{}, &mocks.Handler{}, nil, workflows)
+		assert.NoError(t, err)
+
+		exported, err = c.ExportState(ctx)
+		assert.Nil(t, exported)
+		assert.True(t, errors.Is(err, ErrStateUnsupported))
+	})
+
+	t.Run("export", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		c, err := New(
+			sourceStorage,
+			&broadcastStateHelper{helper},
+			&mocks.Handler{},
+			nil,
+			workflows,
+		)
+		assert.NoError(t, err)
+
+		helper.On("GetAllBroadcasts", ctx).Return([]*modules.Broadcast{broadcast}, nil).Once()
+		state, err = c.ExportState(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &State{
+			Jobs: []*JobState{
+				{Job: readyJob},
+				{Job: broadcastingJob, Broadcast: broadcast},
+			},
+		}, state)
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("import invalid state", func(t *testing.T) {
+		c, err := New(sourceStorage, &mocks.Helper{}, &mocks.Handler{}, nil, workflows)
+		assert.NoError(t, err)
+
+		for name, invalid := range map[string]*JobState{
+			"missing job": {},
+			"missing broadcast": {
+				Job: broadcastingJob,
+			},
+			"ready with broadcast": {
+				Job:       readyJob,
+				Broadcast: broadcast,
+			},
+			"index out of range": {
+				Job: &job.Job{
+					Identifier: "10",
+					Status:     job.Ready,
+					Index:      3,
+					Scenarios:  workflows[0].Scenarios,
+				},
+			},
+			"completed": {
+				Job: &job.Job{
+					Identifier: "10",
+					Status:     job.Completed,
+					Index:      2,
+					Scenarios:  workflows[0].Scenarios,
+				},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				helper := &mocks.Helper{}
+				c.helper = helper
+				helper.On("DatabaseTransaction", ctx).Return(sourceDB.Transaction(ctx)).Once()
+
+				err := c.ImportState(ctx, &State{Jobs: []*JobState{invalid}})
+				assert.True(t, errors.Is(err, ErrJobStateInvalid))
+				helper.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		destinationDB, closeDestination := newDatabase(t)
+		defer closeDestination()
+		destinationStorage := modules.NewJobStorage(destinationDB)
+
+		helper := &mocks.Helper{}
+		c, err := New(destinationStorage, helper, &mocks.Handler{}, nil, workflows)
+		assert.NoError(t, err)
+
+		helper.On("DatabaseTransaction", ctx).Return(destinationDB.Transaction(ctx)).Once()
+		helper.On(
+			"Broadcast",
+			ctx,
+			mock.Anything,
+			broadcastingJob.Identifier,
+			network,
+			broadcast.Intent,
+			broadcast.TransactionIdentifier,
+			"signed tx",
+			int64(3),
+		).Return(nil).Once()
+		assert.NoError(t, c.ImportState(ctx, state))
+
+		dbTx := destinationDB.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		ready, err := destinationStorage.Ready(ctx, dbTx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{readyJob}, ready)
+
+		broadcasting, err := destinationStorage.Broadcasting(ctx, dbTx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{broadcastingJob}, broadcasting)
+
+		helper.AssertExpectations(t)
+	})
+}
+
+type eventRecorder struct {
+	events []*Event
+}
+
+func (r *eventRecorder) HandleEvent(ctx context.Context, event *Event) {
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) types() []EventType {
+	eventTypes := make([]EventType, len(r.events))
+	for i, event := range r.events {
+		eventTypes[i] = event.Type
+	}
+
+	r.events = nil
+	return eventTypes
+}
+
+func TestEvents(t *testing.T) {
+	ctx := context.Background()
+
+	network := &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Testnet3",
+	}
+	currency := &types.Currency{
+		Symbol:   "tBTC",
+		Decimals: 8,
+	}
+	account := &types.AccountIdentifier{Address: "address1"}
+	workflows := []*job.Workflow{
+		{
+			Name:        "transfer",
+			Concurrency: 1,
+			Scenarios: []*job.Scenario{
+				{
+					Name: "find",
+					Actions: []*job.Action{
+						{
+							Type:       job.FindBalance,
+							Input:      `{"minimum_balance":{"value": "100", "currency": {"symbol":"tBTC", "decimals":8}}}`, // nolint
+							OutputPath: "sender",
+						},
+					},
+				},
+			},
+		},
+	}
+	broadcastingJob := func(identifier string) *job.Job {
+		return &job.Job{
+			Identifier: identifier,
+			Workflow:   "transfer",
+			Status:     job.Broadcasting,
+			Index:      1,
+			Scenarios:  []*job.Scenario{{Name: "transfer"}},
+			State: fmt.Sprintf(
+				`{"transfer":{"network":%s,"operations":[]}}`,
+				types.PrintStruct(network),
+			),
+		}
+	}
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	jobStorage := &mocks.JobStorage{}
+	helper := &mocks.Helper{}
+	recorder := &eventRecorder{}
+	c, err := New(
+		jobStorage,
+		helper,
+		&mocks.Handler{},
+		defaultParser(t),
+		workflows,
+		WithSubscriber(recorder),
+	)
+	assert.NoError(t, err)
+
+	t.Run("balance found and workflow completed", func(t *testing.T) {
+		dbTx := db.Transaction(ctx)
+		helper.On("HeadBlockExists", ctx).Return(true).Once()
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+		helper.On("AllAccounts", mock.Anything, dbTx).Return(
+			[]*types.AccountIdentifier{account},
+			nil,
+		).Once()
+		helper.On("LockedAccounts", mock.Anything, dbTx).Return(
+			[]*types.AccountIdentifier{},
+			nil,
+		).Once()
+		helper.On("Balance", mock.Anything, dbTx, account, currency).Return(
+			&types.Amount{Value: "150", Currency: currency},
+			nil,
+		).Once()
+		jobStorage.On("Update", ctx, dbTx, mock.Anything).Return("job1", nil).Once()
+		helper.On("BroadcastAll", ctx).Return(nil).Once()
+
+		sleep, err := c.process(ctx, false)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), sleep)
+
+		assert.Len(t, recorder.events, 3)
+		assert.Equal(t, "150", recorder.events[0].Balance.Balance.Value)
+		assert.Equal(t, "transfer", recorder.events[1].Workflow)
+		assert.Equal(t, "job1", recorder.events[1].Job)
+		assert.Equal(t, []EventType{
+			BalanceFound,
+			WorkflowStarted,
+			WorkflowCompleted,
+		}, recorder.types())
+	})
+
+	t.Run("balance insufficient", func(t *testing.T) {
+		dbTx := db.ReadTransaction(ctx)
+		helper.On("HeadBlockExists", ctx).Return(true).Once()
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+		helper.On("AllAccounts", mock.Anything, dbTx).Return(
+			[]*types.AccountIdentifier{account},
+			nil,
+		).Once()
+		helper.On("LockedAccounts", mock.Anything, dbTx).Return(
+			[]*types.AccountIdentifier{},
+			nil,
+		).Once()
+		helper.On("Balance", mock.Anything, dbTx, account, currency).Return(
+			&types.Amount{Value: "50", Currency: currency},
+			nil,
+		).Once()
+
+		sleep, err := c.process(ctx, false)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), sleep)
+
+		assert.Len(t, recorder.events, 1)
+		assert.Nil(t, recorder.events[0].Balance)
+		assert.Equal(t, "100", recorder.events[0].BalanceRequest.MinimumBalance.Value)
+		assert.Equal(t, []EventType{BalanceInsufficient}, recorder.types())
+		c.resetVars()
+	})
+
+	t.Run("broadcast confirmed", func(t *testing.T) {
+		dbTx := db.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		transaction := &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+			Operations:            []*types.Operation{},
+		}
+		jobStorage.On("Get", ctx, dbTx, "job2").Return(broadcastingJob("job2"), nil).Once()
+		jobStorage.On("Update", ctx, dbTx, mock.Anything).Return("job2", nil).Once()
+
+		assert.NoError(t, c.BroadcastComplete(ctx, dbTx, "job2", transaction))
+		assert.Equal(t, network, recorder.events[0].Network)
+		assert.Equal(t, transaction.TransactionIdentifier, recorder.events[0].Transaction)
+		assert.Equal(t, []EventType{BroadcastConfirmed, WorkflowCompleted}, recorder.types())
+	})
+
+	t.Run("broadcast failed", func(t *testing.T) {
+		dbTx := db.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobStorage.On("Get", ctx, dbTx, "job3").Return(broadcastingJob("job3"), nil).Once()
+		jobStorage.On("Update", ctx, dbTx, mock.Anything).Return("job3", nil).Once()
+
+		assert.NoError(t, c.BroadcastComplete(ctx, dbTx, "job3", nil))
+		assert.Equal(t, ErrBroadcastFailed.Error(), recorder.events[0].Error)
+		assert.Equal(t, []EventType{WorkflowFailed}, recorder.types())
+	})
+
+	t.Run("broadcast stale", func(t *testing.T) {
+		dbTx := db.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		transactionIdentifier := &types.TransactionIdentifier{Hash: "tx 4"}
+		jobStorage.On("Get", ctx, dbTx, "job4").Return(broadcastingJob("job4"), nil).Once()
+
+		assert.NoError(t, c.BroadcastStale(ctx, dbTx, "job4", transactionIdentifier))
+		assert.Equal(t, "job4", recorder.events[0].Job)
+		assert.Equal(t, transactionIdentifier, recorder.events[0].Transaction)
+		assert.Equal(t, []EventType{BroadcastStale}, recorder.types())
+	})
+
+	jobStorage.AssertExpectations(t)
+	helper.AssertExpectations(t)
+}
+
+func TestLoadDataset(t *testing.T) {
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	var tests = map[string]struct {
+		file     string
+		contents string
+
+		rows []map[string]interface{}
+		err  error
+	}{
+		"csv": {
+			file:     "rows.csv",
+			contents: "address, amount\naddr1,10\naddr2,20\n",
+			rows: []map[string]interface{}{
+				{"address": "addr1", "amount": "10"},
+				{"address": "addr2", "amount": "20"},
+			},
+		},
+		"json lines": {
+			file:     "rows.jsonl",
+			contents: "{\"address\":\"addr1\",\"amount\":10}\n\n{\"address\":\"addr2\",\"amount\":20}\n",
+			rows: []map[string]interface{}{
+				{"address": "addr1", "amount": float64(10)},
+				{"address": "addr2", "amount": float64(20)},
+			},
+		},
+		"invalid json lines": {
+			file:     "invalid.jsonl",
+			contents: "{\"address\":\"addr1\"}\nnot json\n",
+			err:      ErrDatasetInvalid,
+		},
+		"unsupported extension": {
+			file:     "rows.txt",
+			contents: "addr1",
+			err:      ErrDatasetInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := path.Join(dir, test.file)
+			assert.NoError(t, os.WriteFile(file, []byte(test.contents), 0600))
+
+			dataset, err := LoadDataset(file)
+			if test.err != nil {
+				assert.Nil(t, dataset)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.rows, dataset.Rows)
+		})
+	}
+}
+
+func TestDataset(t *testing.T) {
+	ctx := context.Background()
+
+	workflows := []*job.Workflow{
+		{
+			Name:        "transfer",
+			Concurrency: 1,
+			Scenarios: []*job.Scenario{
+				{
+					Name: "transfer",
+					Actions: []*job.Action{
+						{
+							Type:       job.SetVariable,
+							Input:      `{{dataset_row.amount}}`,
+							OutputPath: "amount",
+						},
+					},
+				},
+			},
+		},
+	}
+	dataset := &Dataset{
+		Rows: []map[string]interface{}{
+			{"amount": "10"},
+			{"amount": "20"},
+		},
+	}
+
+	t.Run("invalid dataset", func(t *testing.T) {
+		c, err := New(
+			&mocks.JobStorage{},
+			&mocks.Helper{},
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			WithDataset("transfer", nil),
+		)
+		assert.Nil(t, c)
+		assert.True(t, errors.Is(err, ErrDatasetInvalid))
+	})
+
+	t.Run("missing workflow", func(t *testing.T) {
+		c, err := New(
+			&mocks.JobStorage{},
+			&mocks.Helper{},
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			WithDataset("other", dataset),
+		)
+		assert.Nil(t, c)
+		assert.True(t, errors.Is(err, ErrWorkflowMissing))
+	})
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	jobStorage := &mocks.JobStorage{}
+	helper := &mocks.Helper{}
+	c, err := New(
+		jobStorage,
+		helper,
+		&mocks.Handler{},
+		defaultParser(t),
+		workflows,
+		WithDataset("transfer", dataset),
+	)
+	assert.NoError(t, err)
+
+	completed := map[string]*job.Job{}
+	for i, row := range dataset.Rows {
+		identifier := fmt.Sprintf("job%d", i+1)
+		dbTx := db.Transaction(ctx)
+		helper.On("HeadBlockExists", ctx).Return(true).Once()
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+		if i == 0 {
+			helper.On("GetBlob", ctx, dbTx, "dataset/transfer/next").Return(false, nil, nil).Once()
+		} else {
+			helper.On("GetBlob", ctx, dbTx, "dataset/transfer/next").Return(
+				true,
+				[]byte(fmt.Sprintf("%d", i)),
+				nil,
+			).Once()
+		}
+		jobStorage.On(
+			"Update",
+			ctx,
+			dbTx,
+			mock.Anything,
+		).Return(identifier, nil).Run(func(args mock.Arguments) {
+			j := args.Get(2).(*job.Job)
+			assert.Equal(t, int64(i), gjson.Get(j.State, job.DatasetIndexVariable).Int())
+			assert.Equal(t, row["amount"], gjson.Get(j.State, "amount").String())
+			completed[identifier] = j
+		}).Once()
+		helper.On(
+			"SetBlob",
+			ctx,
+			dbTx,
+			fmt.Sprintf("dataset/transfer/%d", i),
+			[]byte(identifier),
+		).Return(nil).Once()
+		helper.On(
+			"SetBlob",
+			ctx,
+			dbTx,
+			"dataset/transfer/next",
+			[]byte(fmt.Sprintf("%d", i+1)),
+		).Return(nil).Once()
+		helper.On("BroadcastAll", ctx).Return(nil).Once()
+
+		sleep, err := c.process(ctx, false)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), sleep)
+		assert.Equal(t, job.Completed, completed[identifier].Status)
+	}
+
+	t.Run("all rows started", func(t *testing.T) {
+		dbTx := db.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+		helper.On("GetBlob", ctx, dbTx, "dataset/transfer/next").Return(
+			true,
+			[]byte("2"),
+			nil,
+		).Once()
+		jobStorage.On("Broadcasting", ctx, dbTx).Return(
+			[]*job.Job{{Identifier: "job0"}},
+			nil,
+		).Once()
+
+		j, err := c.findJob(ctx, dbTx, false)
+		assert.Nil(t, j)
+		assert.True(t, errors.Is(err, ErrNoAvailableJobs))
+	})
+
+	t.Run("results", func(t *testing.T) {
+		dbTx := db.ReadTransaction(ctx)
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		helper.On("GetBlob", ctx, dbTx, "dataset/transfer/0").Return(
+			true,
+			[]byte("job1"),
+			nil,
+		).Once()
+		helper.On("GetBlob", ctx, dbTx, "dataset/transfer/1").Return(false, nil, nil).Once()
+
+		confirmed := completed["job1"]
+		confirmed.State = `{"transfer":{"transaction":{"transaction_identifier":{"hash":"tx1"}}}}`
+		jobStorage.On("Get", ctx, dbTx, "job1").Return(confirmed, nil).Once()
+
+		results, err := c.DatasetResults(ctx, "transfer")
+		assert.NoError(t, err)
+		assert.Equal(t, []*DatasetRowResult{
+			{
+				Index:  0,
+				Row:    dataset.Rows[0],
+				Job:    "job1",
+				Status: job.Completed,
+				Transactions: []*types.TransactionIdentifier{
+					{Hash: "tx1"},
+				},
+			},
+			{
+				Index: 1,
+				Row:   dataset.Rows[1],
+			},
+		}, results)
+
+		_, err = c.DatasetResults(ctx, "other")
+		assert.True(t, errors.Is(err, ErrWorkflowMissing))
+	})
+
+	jobStorage.AssertExpectations(t)
+	helper.AssertExpectations(t)
+
+	t.Run("failed row", func(t *testing.T) {
+		// The row is missing the amount used by the workflow.
+		failedDataset := &Dataset{
+			Rows: []map[string]interface{}{
+				{"memo": "hello"},
+			},
+		}
+		jobStorage := &mocks.JobStorage{}
+		helper := &mocks.Helper{}
+		c, err := New(
+			jobStorage,
+			helper,
+			&mocks.Handler{},
+			defaultParser(t),
+			workflows,
+			WithDataset("transfer", failedDataset),
+		)
+		assert.NoError(t, err)
+
+		dbTx := db.Transaction(ctx)
+		helper.On("HeadBlockExists", ctx).Return(true).Once()
+		helper.On("DatabaseTransaction", ctx).Return(dbTx).Once()
+		jobStorage.On("Ready", ctx, dbTx).Return([]*job.Job{}, nil).Once()
+		jobStorage.On("Processing", ctx, dbTx, "transfer").Return([]*job.Job{}, nil).Once()
+		helper.On("GetBlob", ctx, dbTx, "dataset/transfer/next").Return(false, nil, nil).Once()
+
+		// The failure is recorded in a new transaction (which can
+		// only be opened once the first transaction is discarded).
+		var failedTx database.Transaction
+		helper.On("DatabaseTransaction", ctx).Return(
+			func(ctx context.Context) database.Transaction {
+				failedTx = db.Transaction(ctx)
+				return failedTx
+			},
+		).Once()
+		isFailedTx := mock.MatchedBy(func(tx database.Transaction) bool {
+			return tx == failedTx
+		})
+		var failed *job.Job
+		jobStorage.On(
+			"Update",
+			ctx,
+			isFailedTx,
+			mock.Anything,
+		).Return("job1", nil).Run(func(args mock.Arguments) {
+			failed = args.Get(2).(*job.Job)
+			assert.Equal(t, job.Failed, failed.Status)
+		}).Once()
+		helper.On(
+			"SetBlob",
+			ctx,
+			isFailedTx,
+			"dataset/transfer/0",
+			[]byte("job1"),
+		).Return(nil).Once()
+		helper.On(
+			"SetBlob",
+			ctx,
+			isFailedTx,
+			"dataset/transfer/next",
+			[]byte("1"),
+		).Return(nil).Once()
+		var message []byte
+		helper.On(
+			"SetBlob",
+			ctx,
+			isFailedTx,
+			"dataset/transfer/0/error",
+			mock.Anything,
+		).Return(nil).Run(func(args mock.Arguments) {
+			message = args.Get(3).([]byte)
+		}).Once()
+
+		// The failure is recorded and processing continues.
+		sleep, err := c.process(ctx, false)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), sleep)
+		assert.Contains(t, string(message), "dataset_row.amount")
+
+		readTx := db.ReadTransaction(ctx)
+		helper.On("DatabaseTransaction", ctx).Return(readTx).Once()
+		helper.On("GetBlob", ctx, readTx, "dataset/transfer/0").Return(
+			true,
+			[]byte("job1"),
+			nil,
+		).Once()
+		jobStorage.On("Get", ctx, readTx, "job1").Return(failed, nil).Once()
+		helper.On("GetBlob", ctx, readTx, "dataset/transfer/0/error").Return(
+			true,
+			message,
+			nil,
+		).Once()
+
+		results, err := c.DatasetResults(ctx, "transfer")
+		assert.NoError(t, err)
+		assert.Equal(t, []*DatasetRowResult{
+			{
+				Index:  0,
+				Row:    failedDataset.Rows[0],
+				Job:    "job1",
+				Status: job.Failed,
+				Error:  string(message),
+			},
+		}, results)
+
+		jobStorage.AssertExpectations(t)
+		helper.AssertExpectations(t)
+	})
+}
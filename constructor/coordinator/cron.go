@@ -0,0 +1,207 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxCronYears is the number of years searched for
+	// the next time that matches a cron expression.
+	maxCronYears = 5
+)
+
+// cronMacros are the supported shorthands
+// for common cron expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField is the set of values
+// allowed by a cron expression field.
+type cronField uint64
+
+func (f cronField) matches(value int) bool {
+	return f&(1<<uint(value)) != 0
+}
+
+// cronSchedule is a parsed cron expression with the standard
+// 5 fields (minute, hour, day of month, month, and day of week).
+type cronSchedule struct {
+	minute     cronField
+	hour       cronField
+	dayOfMonth cronField
+	month      cronField
+	dayOfWeek  cronField
+
+	// When both day of month and day of week are
+	// restricted (not *), a day matches if either
+	// matches (like cron).
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
+}
+
+// parseCronField parses a comma-separated list of values,
+// ranges (a-b), and steps (*/n or a-b/n) between min and
+// max (inclusive).
+func parseCronField(field string, min int, max int) (cronField, error) {
+	var parsed cronField
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s", part)
+			}
+
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid range %s", part)
+			}
+
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid range %s", part)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %s", part)
+			}
+
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("%s is out of range [%d, %d]", part, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			parsed |= 1 << uint(value)
+		}
+	}
+
+	return parsed, nil
+}
+
+// parseCron parses a cron expression with 5 fields
+// (minute, hour, day of month, month, and day of week)
+// or one of the cronMacros (ex: @hourly).
+func parseCron(expression string) (*cronSchedule, error) {
+	if macro, ok := cronMacros[strings.TrimSpace(expression)]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 { // nolint:gomnd
+		return nil, fmt.Errorf("expected 5 fields in %q, found %d", expression, len(fields))
+	}
+
+	bounds := [][2]int{
+		{0, 59}, // minute
+		{0, 23}, // hour
+		{1, 31}, // day of month
+		{1, 12}, // month
+		{0, 7},  // day of week (0 and 7 are Sunday)
+	}
+	parsed := make([]cronField, len(fields))
+	for i, field := range fields {
+		var err error
+		parsed[i], err = parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+		}
+	}
+
+	dayOfWeek := parsed[4]
+	if dayOfWeek.matches(7) { // nolint:gomnd
+		dayOfWeek |= 1
+	}
+
+	return &cronSchedule{
+		minute:        parsed[0],
+		hour:          parsed[1],
+		dayOfMonth:    parsed[2],
+		month:         parsed[3],
+		dayOfWeek:     dayOfWeek,
+		dayOfMonthAny: fields[2] == "*",
+		dayOfWeekAny:  fields[4] == "*",
+	}, nil
+}
+
+// dayMatches returns a boolean indicating if
+// the day of t matches the cronSchedule.
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth.matches(t.Day())
+	dayOfWeek := c.dayOfWeek.matches(int(t.Weekday()))
+
+	switch {
+	case c.dayOfMonthAny && c.dayOfWeekAny:
+		return true
+	case c.dayOfMonthAny:
+		return dayOfWeek
+	case c.dayOfWeekAny:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
+
+// next returns the first time after t (in the location
+// of t) that matches the cronSchedule. If no time matches
+// within maxCronYears (ex: 0 0 30 2 *), the zero time.Time
+// is returned.
+func (c *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !c.month.matches(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hour.matches(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minute.matches(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,397 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// CSVDatasetExtension is the file extension of
+	// a dataset with a header row and one row per line.
+	CSVDatasetExtension = ".csv"
+
+	// JSONLinesDatasetExtension is the file extension of a
+	// dataset with one JSON object per line.
+	JSONLinesDatasetExtension = ".jsonl"
+
+	datasetNamespace = "dataset"
+	datasetNextKey   = "next"
+	datasetErrorKey  = "error"
+)
+
+// Dataset is a collection of rows. When a Dataset is provided
+// for a Workflow (with WithDataset), one Job is started for each
+// row (in order) with its values stored in the dataset_row variable
+// (and its index in the dataset_index variable). The number of rows
+// processed at once is bounded by the Concurrency of the Workflow.
+type Dataset struct {
+	Rows []map[string]interface{}
+}
+
+// DatasetRowResult is the result of processing
+// a single row of a Dataset.
+type DatasetRowResult struct {
+	Index int                    `json:"index"`
+	Row   map[string]interface{} `json:"row"`
+
+	// Job and Status are empty if no
+	// Job has been started for the row.
+	Job    string     `json:"job,omitempty"`
+	Status job.Status `json:"status,omitempty"`
+
+	// Transactions are all transactions confirmed
+	// by the Job (in the order of its scenarios).
+	Transactions []*types.TransactionIdentifier `json:"transactions,omitempty"`
+
+	// Error is populated if the row could not
+	// be processed (ex: its transaction could
+	// not be constructed).
+	Error string `json:"error,omitempty"`
+}
+
+// LoadDataset loads a *Dataset from a CSV (CSVDatasetExtension)
+// or JSON lines (JSONLinesDatasetExtension) file. All values in
+// a CSV file are loaded as strings.
+func LoadDataset(file string) (*Dataset, error) {
+	cleanedPath := path.Clean(file)
+	f, err := os.Open(cleanedPath) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to open %s: %v", ErrDatasetInvalid, cleanedPath, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("%s: could not close %s\n", err.Error(), cleanedPath)
+		}
+	}()
+
+	var rows []map[string]interface{}
+	switch extension := path.Ext(cleanedPath); extension {
+	case CSVDatasetExtension:
+		rows, err = parseCSVDataset(f)
+	case JSONLinesDatasetExtension:
+		rows, err = parseJSONLinesDataset(f)
+	default:
+		return nil, fmt.Errorf("%w: unsupported extension %s", ErrDatasetInvalid, extension)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrDatasetInvalid, cleanedPath, err)
+	}
+
+	return &Dataset{Rows: rows}, nil
+}
+
+// parseCSVDataset parses rows from a CSV
+// file with a header row.
+func parseCSVDataset(r io.Reader) ([]map[string]interface{}, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read header: %w", err)
+	}
+
+	rows := []map[string]interface{}{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read row %d: %w", len(rows), err)
+		}
+
+		row := map[string]interface{}{}
+		for i, column := range header {
+			row[strings.TrimSpace(column)] = record[i]
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+// parseJSONLinesDataset parses rows from a file
+// with one JSON object per line (empty lines
+// are skipped).
+func parseJSONLinesDataset(r io.Reader) ([]map[string]interface{}, error) {
+	scanner := bufio.NewScanner(r)
+	rows := []map[string]interface{}{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("unable to parse line %d: %w", line, err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read lines: %w", err)
+	}
+
+	return rows, nil
+}
+
+func datasetKey(workflow string, key string) string {
+	return fmt.Sprintf("%s/%s/%s", datasetNamespace, workflow, key)
+}
+
+// getDatasetBlob returns the string stored at key
+// for the Dataset of workflow.
+func (c *Coordinator) getDatasetBlob(
+	ctx context.Context,
+	dbTx database.Transaction,
+	workflow string,
+	key string,
+) (bool, string, error) {
+	exists, value, err := c.helper.GetBlob(ctx, dbTx, datasetKey(workflow, key))
+	if err != nil {
+		return false, "", fmt.Errorf("%w: %v", ErrDatasetUnretrievable, err)
+	}
+
+	return exists, string(value), nil
+}
+
+// nextDatasetRow returns the index of the next row
+// of the Dataset of workflow to start a Job for.
+func (c *Coordinator) nextDatasetRow(
+	ctx context.Context,
+	dbTx database.Transaction,
+	workflow string,
+) (int, error) {
+	exists, value, err := c.getDatasetBlob(ctx, dbTx, workflow, datasetNextKey)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	next, err := strconv.Atoi(value)
+	if err != nil {
+		return -1, fmt.Errorf("%w: %v", ErrDatasetUnretrievable, err)
+	}
+
+	return next, nil
+}
+
+// nextDatasetJob returns a *job.Job for the next row of
+// the Dataset of workflow (or nil if all rows have been
+// started).
+func (c *Coordinator) nextDatasetJob(
+	ctx context.Context,
+	dbTx database.Transaction,
+	workflow *job.Workflow,
+	dataset *Dataset,
+) (*job.Job, error) {
+	next, err := c.nextDatasetRow(ctx, dbTx, workflow.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if next >= len(dataset.Rows) {
+		return nil, nil
+	}
+
+	return job.NewDatasetRow(workflow, next, dataset.Rows[next])
+}
+
+// datasetRowStarted records that the Job for a row
+// of a Dataset has been started (if j was created
+// for a row).
+func (c *Coordinator) datasetRowStarted(
+	ctx context.Context,
+	dbTx database.Transaction,
+	j *job.Job,
+) error {
+	if _, ok := c.datasets[j.Workflow]; !ok {
+		return nil
+	}
+
+	index := gjson.Get(j.State, job.DatasetIndexVariable)
+	if !index.Exists() {
+		return nil
+	}
+
+	row := int(index.Int())
+	if err := c.helper.SetBlob(
+		ctx,
+		dbTx,
+		datasetKey(j.Workflow, strconv.Itoa(row)),
+		[]byte(j.Identifier),
+	); err != nil {
+		return fmt.Errorf("%w: unable to store job for row %d", err, row)
+	}
+
+	if err := c.helper.SetBlob(
+		ctx,
+		dbTx,
+		datasetKey(j.Workflow, datasetNextKey),
+		[]byte(strconv.Itoa(row+1)),
+	); err != nil {
+		return fmt.Errorf("%w: unable to store next row", err)
+	}
+
+	return nil
+}
+
+// datasetRowError returns the key of the error
+// recorded for row of the Dataset of workflow.
+func datasetRowError(row int) string {
+	return fmt.Sprintf("%d/%s", row, datasetErrorKey)
+}
+
+// datasetRowFailed records that the Job for a row of a Dataset
+// could not be processed (in a new database.Transaction, as any
+// changes made while processing the row are discarded) so that
+// the remaining rows are still processed. It returns false if
+// j was not created for a row. started indicates whether j was
+// first stored in the discarded database.Transaction.
+func (c *Coordinator) datasetRowFailed(
+	ctx context.Context,
+	j *job.Job,
+	started bool,
+	failure error,
+) (bool, error) {
+	if _, ok := c.datasets[j.Workflow]; !ok {
+		return false, nil
+	}
+
+	index := gjson.Get(j.State, job.DatasetIndexVariable)
+	if !index.Exists() {
+		return false, nil
+	}
+
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	if started {
+		j.Identifier = ""
+	}
+
+	j.Status = job.Failed
+	jobIdentifier, err := c.storage.Update(ctx, dbTx, j)
+	if err != nil {
+		return false, fmt.Errorf("%w: unable to update job", err)
+	}
+	j.Identifier = jobIdentifier
+
+	if started {
+		if err := c.datasetRowStarted(ctx, dbTx, j); err != nil {
+			return false, err
+		}
+	}
+
+	row := int(index.Int())
+	if err := c.helper.SetBlob(
+		ctx,
+		dbTx,
+		datasetKey(j.Workflow, datasetRowError(row)),
+		[]byte(failure.Error()),
+	); err != nil {
+		return false, fmt.Errorf("%w: unable to store error for row %d", err, row)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("%w: unable to commit row %d failure", err, row)
+	}
+
+	log.Printf(`recorded failure of row %d of dataset "%s": %s`, row, j.Workflow, failure.Error())
+	return true, nil
+}
+
+// DatasetResults returns the *DatasetRowResult of each row of
+// the Dataset provided for the Workflow named workflow.
+func (c *Coordinator) DatasetResults(
+	ctx context.Context,
+	workflow string,
+) ([]*DatasetRowResult, error) {
+	dataset, ok := c.datasets[workflow]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no dataset", ErrWorkflowMissing, workflow)
+	}
+
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	results := make([]*DatasetRowResult, len(dataset.Rows))
+	for i, row := range dataset.Rows {
+		result := &DatasetRowResult{Index: i, Row: row}
+		results[i] = result
+
+		exists, identifier, err := c.getDatasetBlob(ctx, dbTx, workflow, strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			continue
+		}
+
+		j, err := c.storage.Get(ctx, dbTx, identifier)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrJobMissing, err.Error())
+		}
+
+		result.Job = identifier
+		result.Status = j.Status
+		if j.Status == job.Failed {
+			exists, message, err := c.getDatasetBlob(ctx, dbTx, workflow, datasetRowError(i))
+			if err != nil {
+				return nil, err
+			}
+
+			if exists {
+				result.Error = message
+			}
+		}
+		for _, scenario := range j.Scenarios {
+			hash := gjson.Get(
+				j.State,
+				fmt.Sprintf("%s.%s.transaction_identifier", scenario.Name, job.Transaction),
+			)
+			if !hash.Exists() {
+				continue
+			}
+
+			var transactionIdentifier types.TransactionIdentifier
+			if err := json.Unmarshal([]byte(hash.Raw), &transactionIdentifier); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrDatasetUnretrievable, err)
+			}
+
+			result.Transactions = append(result.Transactions, &transactionIdentifier)
+		}
+	}
+
+	return results, nil
+}
@@ -62,4 +62,62 @@ var (
 	// ErrNoWorkflows is returned when no workflows are provided
 	// during initialization.
 	ErrNoWorkflows = errors.New("no workflows")
+
+	// ErrFeeBumpFailed is returned when the BumpFee Workflow
+	// cannot create a replacement for a stuck broadcast.
+	ErrFeeBumpFailed = errors.New("unable to bump fee")
+
+	// ErrWorkflowMissing is returned when a Workflow
+	// cannot be found by name.
+	ErrWorkflowMissing = errors.New("workflow missing")
+
+	// ErrPlanFailed is returned when a Workflow cannot
+	// be planned.
+	ErrPlanFailed = errors.New("unable to plan workflow")
+
+	// ErrPrefundedAccountsUnretrievable is returned when the
+	// utilization of prefunded accounts cannot be determined.
+	ErrPrefundedAccountsUnretrievable = errors.New("unable to retrieve prefunded accounts")
+
+	// ErrScheduleInvalid is returned when a *Schedule
+	// is invalid.
+	ErrScheduleInvalid = errors.New("invalid schedule")
+
+	// ErrWorkflowsScheduled is returned when no Jobs can
+	// be started until a scheduled Workflow is due.
+	ErrWorkflowsScheduled = errors.New("workflows scheduled")
+
+	// ErrFinalityPolicyInvalid is returned when a
+	// *FinalityPolicy is not valid.
+	ErrFinalityPolicyInvalid = errors.New("invalid finality policy")
+
+	// ErrFinalityUnsupported is returned when a *FinalityPolicy
+	// requires a /call but the Helper does not implement
+	// FinalityHelper.
+	ErrFinalityUnsupported = errors.New("finality checks unsupported")
+
+	// ErrFinalityCheckFailed is returned when the finality
+	// of a transaction cannot be determined.
+	ErrFinalityCheckFailed = errors.New("unable to check finality")
+
+	// ErrStateUnsupported is returned when the JobStorage
+	// or Helper do not support exporting or importing
+	// in-flight Jobs.
+	ErrStateUnsupported = errors.New("job state export and import unsupported")
+
+	// ErrJobStateInvalid is returned when an imported
+	// *JobState cannot be resumed.
+	ErrJobStateInvalid = errors.New("invalid job state")
+
+	// ErrBroadcastFailed is the error of the WorkflowFailed
+	// *Event emitted when a broadcast fails.
+	ErrBroadcastFailed = errors.New("broadcast failed")
+
+	// ErrDatasetInvalid is returned when a *Dataset
+	// cannot be loaded or is not valid.
+	ErrDatasetInvalid = errors.New("invalid dataset")
+
+	// ErrDatasetUnretrievable is returned when the progress
+	// of a *Dataset cannot be retrieved.
+	ErrDatasetUnretrievable = errors.New("unable to retrieve dataset progress")
 )
@@ -0,0 +1,202 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// EventType is the type of an *Event.
+type EventType string
+
+const (
+	// WorkflowStarted is emitted when the first scenario
+	// of a Job is processed (and the Job is stored).
+	WorkflowStarted EventType = "workflow_started"
+
+	// WorkflowCompleted is emitted when all
+	// scenarios of a Job have completed.
+	WorkflowCompleted EventType = "workflow_completed"
+
+	// WorkflowFailed is emitted when a Job cannot be processed
+	// or its broadcast fails. The Error of the *Event is populated.
+	WorkflowFailed EventType = "workflow_failed"
+
+	// BroadcastCreated is emitted when a transaction
+	// is created and enqueued for broadcast.
+	BroadcastCreated EventType = "broadcast_created"
+
+	// BroadcastConfirmed is emitted when a
+	// broadcast transaction is confirmed.
+	BroadcastConfirmed EventType = "broadcast_confirmed"
+
+	// BroadcastStale is emitted when a broadcast transaction
+	// has not been seen on-chain within the stale depth.
+	BroadcastStale EventType = "broadcast_stale"
+
+	// BalanceFound is emitted when find_balance finds
+	// a balance. The Balance of the *Event is populated.
+	BalanceFound EventType = "balance_found"
+
+	// BalanceInsufficient is emitted when find_balance
+	// cannot find a sufficient balance.
+	BalanceInsufficient EventType = "balance_insufficient"
+)
+
+// Event describes some change in the lifecycle of
+// a Job. Only fields relevant to the EventType are
+// populated.
+type Event struct {
+	Type      EventType `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Workflow  string    `json:"workflow,omitempty"`
+
+	// Job is empty for balance events emitted
+	// while processing the first scenario of a Job.
+	Job string `json:"job,omitempty"`
+
+	Network     *types.NetworkIdentifier     `json:"network,omitempty"`
+	Transaction *types.TransactionIdentifier `json:"transaction,omitempty"`
+
+	// BalanceRequest is the input to find_balance
+	// for BalanceFound and BalanceInsufficient.
+	BalanceRequest *job.FindBalanceInput  `json:"balance_request,omitempty"`
+	Balance        *job.FindBalanceOutput `json:"balance,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Subscriber is notified of every *Event emitted by the
+// Coordinator. HandleEvent is invoked synchronously while
+// processing, so it should not block.
+type Subscriber interface {
+	HandleEvent(context.Context, *Event)
+}
+
+// jobContextKey is used to attach the *job.Job
+// being processed to the context provided to
+// the worker (for balance events).
+type jobContextKey struct{}
+
+// workerContext returns the context.Context to provide to
+// the worker when processing j. It only contains j if there
+// are Subscribers.
+func (c *Coordinator) workerContext(ctx context.Context, j *job.Job) context.Context {
+	if len(c.subscribers) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, jobContextKey{}, j)
+}
+
+// emit sends event to all Subscribers.
+func (c *Coordinator) emit(ctx context.Context, event *Event) {
+	if len(c.subscribers) == 0 {
+		return
+	}
+
+	event.Timestamp = time.Now()
+	for _, subscriber := range c.subscribers {
+		subscriber.HandleEvent(ctx, event)
+	}
+}
+
+// emitJob sends an *Event of eventType for j
+// to all Subscribers.
+func (c *Coordinator) emitJob(
+	ctx context.Context,
+	eventType EventType,
+	j *job.Job,
+	network *types.NetworkIdentifier,
+	transaction *types.TransactionIdentifier,
+	err error,
+) {
+	event := &Event{
+		Type:        eventType,
+		Workflow:    j.Workflow,
+		Job:         j.Identifier,
+		Network:     network,
+		Transaction: transaction,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	c.emit(ctx, event)
+}
+
+// balanceObserved is a worker.BalanceObserver that emits
+// BalanceFound and BalanceInsufficient events for the *job.Job
+// in ctx (if any).
+func (c *Coordinator) balanceObserved(
+	ctx context.Context,
+	input *job.FindBalanceInput,
+	output *job.FindBalanceOutput,
+) {
+	j, ok := ctx.Value(jobContextKey{}).(*job.Job)
+	if !ok {
+		return
+	}
+
+	eventType := BalanceFound
+	if output == nil {
+		eventType = BalanceInsufficient
+	}
+
+	c.emit(ctx, &Event{
+		Type:           eventType,
+		Workflow:       j.Workflow,
+		Job:            j.Identifier,
+		Network:        input.Network,
+		BalanceRequest: input,
+		Balance:        output,
+	})
+}
+
+// BroadcastStale is called by the broadcast coordinator
+// when a transaction broadcast is considered stale. It
+// emits a BroadcastStale event.
+func (c *Coordinator) BroadcastStale(
+	ctx context.Context,
+	dbTx database.Transaction,
+	jobIdentifier string,
+	transactionIdentifier *types.TransactionIdentifier,
+) error {
+	j, err := c.storage.Get(ctx, dbTx, jobIdentifier)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrJobMissing, err.Error())
+	}
+
+	network, err := j.BroadcastNetwork()
+	if err != nil {
+		return fmt.Errorf("%w: unable to determine broadcast network", err)
+	}
+
+	c.emit(ctx, &Event{
+		Type:        BroadcastStale,
+		Workflow:    j.Workflow,
+		Job:         jobIdentifier,
+		Network:     network,
+		Transaction: transactionIdentifier,
+	})
+
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// DefaultFinalityResultPath is the path in the /call
+	// result that is used to determine if a transaction
+	// is final when no ResultPath is provided.
+	DefaultFinalityResultPath = "finalized"
+
+	// finalityTransactionParameter and finalityBlockParameter
+	// are added to the parameters of each finality /call.
+	finalityTransactionParameter = "transaction_identifier"
+	finalityBlockParameter       = "block_identifier"
+)
+
+// FinalityPolicy controls when a transaction broadcast by
+// a Workflow is considered confirmed.
+type FinalityPolicy struct {
+	// ConfirmationDepth overrides the confirmation depth
+	// of each broadcast (if greater than 0).
+	ConfirmationDepth int64
+
+	// Method is the /call method invoked (once the
+	// confirmation depth is reached) to determine if a
+	// transaction is final. If Method is empty, no
+	// chain-provided finality signal is used.
+	Method string
+
+	// Parameters are provided to Method (in addition to
+	// the transaction_identifier and block_identifier of
+	// the transaction).
+	Parameters map[string]interface{}
+
+	// ResultPath is the gjson path of the boolean in
+	// the /call result that indicates finality.
+	ResultPath string
+}
+
+// FinalityHelper is an optional extension of the Helper
+// that is required to use a FinalityPolicy with a Method.
+type FinalityHelper interface {
+	// Call invokes the /call endpoint
+	// using the online node.
+	Call(
+		context.Context,
+		*types.NetworkIdentifier,
+		string, // method
+		map[string]interface{}, // parameters
+	) (map[string]interface{}, error)
+}
+
+// Confirmation is provided to the ConfirmationHandler
+// when a broadcast is confirmed.
+type Confirmation struct {
+	Workflow    string             `json:"workflow"`
+	Transaction *types.Transaction `json:"transaction"`
+	Intent      []*types.Operation `json:"intent"`
+
+	// Diff describes all differences between the
+	// Intent and the confirmed on-chain operations.
+	Diff *parser.IntentDiff `json:"diff"`
+}
+
+// ConfirmationHandler is an optional extension of the
+// Handler that is invoked whenever a broadcast is confirmed.
+type ConfirmationHandler interface {
+	BroadcastConfirmed(
+		context.Context,
+		string, // job identifier
+		*Confirmation,
+	) error
+}
+
+// finalityPolicyValidation ensures a *FinalityPolicy
+// is valid.
+func finalityPolicyValidation(policy *FinalityPolicy) error {
+	if policy == nil {
+		return fmt.Errorf("%w: finality policy is nil", ErrFinalityPolicyInvalid)
+	}
+
+	if policy.ConfirmationDepth < 0 {
+		return fmt.Errorf(
+			"%w: confirmation depth %d cannot be negative",
+			ErrFinalityPolicyInvalid,
+			policy.ConfirmationDepth,
+		)
+	}
+
+	if len(policy.Method) == 0 && (len(policy.Parameters) > 0 || len(policy.ResultPath) > 0) {
+		return fmt.Errorf(
+			"%w: parameters and result path require a method",
+			ErrFinalityPolicyInvalid,
+		)
+	}
+
+	return nil
+}
+
+// confirmationDepth returns the confirmation depth to use
+// for a broadcast created by workflow.
+func (c *Coordinator) confirmationDepth(workflow string, depth int64) int64 {
+	policy, ok := c.finalityPolicies[workflow]
+	if !ok || policy.ConfirmationDepth <= 0 {
+		return depth
+	}
+
+	return policy.ConfirmationDepth
+}
+
+// Finalized returns a boolean indicating if a broadcast
+// included in block is final according to the FinalityPolicy
+// of the Workflow that created it. Broadcasts created by a
+// Workflow without a FinalityPolicy Method are always final.
+//
+// Finalized can be used as a modules.FinalityChecker. dbTx
+// must be a database.Transaction of the network the broadcast
+// was made on (ex: the transaction BroadcastStorage uses to
+// add a block).
+func (c *Coordinator) Finalized(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *modules.Broadcast,
+	block *types.BlockIdentifier,
+) (bool, error) {
+	if len(c.finalityPolicies) == 0 {
+		return true, nil
+	}
+
+	// Jobs are stored in the database of the Coordinator's
+	// Helper, so a broadcast on a registered network must
+	// look up its Job in a separate database.Transaction.
+	if c.helperFor(broadcast.NetworkIdentifier) != c.helper {
+		dbTx = c.helper.DatabaseTransaction(ctx)
+		defer dbTx.Discard(ctx)
+	}
+
+	j, err := c.storage.Get(ctx, dbTx, broadcast.Identifier)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrJobMissing, err.Error())
+	}
+
+	policy, ok := c.finalityPolicies[j.Workflow]
+	if !ok || len(policy.Method) == 0 {
+		return true, nil
+	}
+
+	helper, ok := c.helperFor(broadcast.NetworkIdentifier).(FinalityHelper)
+	if !ok {
+		return false, fmt.Errorf(
+			"%w: helper does not implement FinalityHelper",
+			ErrFinalityUnsupported,
+		)
+	}
+
+	parameters := map[string]interface{}{}
+	for k, v := range policy.Parameters {
+		parameters[k] = v
+	}
+	parameters[finalityTransactionParameter] = broadcast.TransactionIdentifier
+	parameters[finalityBlockParameter] = block
+
+	result, err := helper.Call(ctx, broadcast.NetworkIdentifier, policy.Method, parameters)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrFinalityCheckFailed, err.Error())
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrFinalityCheckFailed, err.Error())
+	}
+
+	resultPath := policy.ResultPath
+	if len(resultPath) == 0 {
+		resultPath = DefaultFinalityResultPath
+	}
+
+	finalized := gjson.GetBytes(resultBytes, resultPath)
+	if !finalized.Exists() {
+		return false, fmt.Errorf(
+			"%w: %s missing from %s result",
+			ErrFinalityCheckFailed,
+			resultPath,
+			policy.Method,
+		)
+	}
+
+	return finalized.Bool(), nil
+}
+
+// confirmed invokes the ConfirmationHandler (if the
+// Handler implements it) with the *Confirmation of
+// a transaction.
+func (c *Coordinator) confirmed(
+	ctx context.Context,
+	jobIdentifier string,
+	confirmation *Confirmation,
+	network *types.NetworkIdentifier,
+) error {
+	handler, ok := c.handler.(ConfirmationHandler)
+	if !ok {
+		return nil
+	}
+
+	diff, err := c.parserFor(network).ExpectedOperationsDiff(
+		confirmation.Intent,
+		confirmation.Transaction.Operations,
+		true,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: unable to diff confirmed operations", err)
+	}
+	confirmation.Diff = diff
+
+	return handler.BroadcastConfirmed(ctx, jobIdentifier, confirmation)
+}
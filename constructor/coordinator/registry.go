@@ -0,0 +1,292 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/worker"
+	"github.com/coinbase/rosetta-sdk-go/keys"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Registry resolves the Helper (usually backed by a
+// *fetcher.Fetcher for the network) and *parser.Parser
+// (backed by an *asserter.Asserter for the network) to
+// use for each *types.NetworkIdentifier in Workflows
+// that span multiple networks (ex: withdraw on one network
+// and check the deposit on another).
+//
+// Any network that is not registered uses the Helper
+// and *parser.Parser provided to the Coordinator.
+//
+// The Helper of each registered network must be backed by
+// its own database and *modules.BroadcastStorage (synced
+// with the network), so that broadcasts on the network are
+// confirmed. Jobs are stored with the Coordinator's Helper,
+// so the *modules.BroadcastStorage of a registered network
+// should invoke Coordinator.RegisteredBroadcastComplete
+// (instead of Coordinator.BroadcastComplete) when a
+// broadcast completes.
+type Registry struct {
+	networks map[string]*networkEntry
+	mutex    sync.RWMutex
+}
+
+type networkEntry struct {
+	network *types.NetworkIdentifier
+	helper  Helper
+	parser  *parser.Parser
+}
+
+// NewRegistry returns a new *Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		networks: map[string]*networkEntry{},
+	}
+}
+
+// Register adds the Helper and *parser.Parser for network
+// to the *Registry (replacing any existing registration).
+func (r *Registry) Register(
+	network *types.NetworkIdentifier,
+	helper Helper,
+	parser *parser.Parser,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.networks[types.Hash(network)] = &networkEntry{
+		network: network,
+		helper:  helper,
+		parser:  parser,
+	}
+}
+
+// Networks returns all registered *types.NetworkIdentifier.
+func (r *Registry) Networks() []*types.NetworkIdentifier {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	networks := []*types.NetworkIdentifier{}
+	for _, entry := range r.networks {
+		networks = append(networks, entry.network)
+	}
+
+	return networks
+}
+
+func (r *Registry) lookup(network *types.NetworkIdentifier) (*networkEntry, bool) {
+	if r == nil || network == nil {
+		return nil, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, ok := r.networks[types.Hash(network)]
+	return entry, ok
+}
+
+// helperFor returns the Helper to use for network.
+func (c *Coordinator) helperFor(network *types.NetworkIdentifier) Helper {
+	if entry, ok := c.registry.lookup(network); ok && entry.helper != nil {
+		return entry.helper
+	}
+
+	return c.helper
+}
+
+// parserFor returns the *parser.Parser to use for network.
+func (c *Coordinator) parserFor(network *types.NetworkIdentifier) *parser.Parser {
+	if entry, ok := c.registry.lookup(network); ok && entry.parser != nil {
+		return entry.parser
+	}
+
+	return c.parser
+}
+
+// registeredHelpers returns the Helper of each registered
+// network that is not the Coordinator's Helper.
+func (c *Coordinator) registeredHelpers() []Helper {
+	if c.registry == nil {
+		return nil
+	}
+
+	c.registry.mutex.RLock()
+	defer c.registry.mutex.RUnlock()
+
+	helpers := []Helper{}
+	for _, entry := range c.registry.networks {
+		if entry.helper == nil || entry.helper == c.helper {
+			continue
+		}
+
+		helpers = append(helpers, entry.helper)
+	}
+
+	return helpers
+}
+
+// networkTransaction returns the database.Transaction to provide
+// to the Helper of network. If network uses the Coordinator's
+// Helper, dbTx is returned. Otherwise, a new database.Transaction
+// of the network's Helper is returned (and separate is true) that
+// the caller must commit or discard.
+func (c *Coordinator) networkTransaction(
+	ctx context.Context,
+	dbTx database.Transaction,
+	network *types.NetworkIdentifier,
+) (networkTx database.Transaction, separate bool) {
+	helper := c.helperFor(network)
+	if helper == c.helper {
+		return dbTx, false
+	}
+
+	return helper.DatabaseTransaction(ctx), true
+}
+
+// workerHelper is the worker.NetworkResolver
+// used by the Coordinator's *worker.Worker.
+func (c *Coordinator) workerHelper(network *types.NetworkIdentifier) worker.Helper {
+	helper := c.helperFor(network)
+	if helper == c.helper {
+		return helper
+	}
+
+	return &networkWorkerHelper{Helper: helper}
+}
+
+// networkWorkerHelper is the worker.Helper of a registered
+// network. The *worker.Worker provides the database.Transaction
+// of the Coordinator's Helper to all actions, so each call that
+// accepts a database.Transaction uses a new database.Transaction
+// of the network's Helper instead.
+type networkWorkerHelper struct {
+	Helper
+}
+
+// StoreKey stores a key in the database of the network.
+func (h *networkWorkerHelper) StoreKey(
+	ctx context.Context,
+	_ database.Transaction,
+	account *types.AccountIdentifier,
+	keyPair *keys.KeyPair,
+) error {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	if err := h.Helper.StoreKey(ctx, dbTx, account, keyPair); err != nil {
+		return err
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// GetKey gets a key from the database of the network.
+func (h *networkWorkerHelper) GetKey(
+	ctx context.Context,
+	_ database.Transaction,
+	account *types.AccountIdentifier,
+) (*keys.KeyPair, error) {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return h.Helper.GetKey(ctx, dbTx, account)
+}
+
+// AllAccounts returns all accounts in the
+// database of the network.
+func (h *networkWorkerHelper) AllAccounts(
+	ctx context.Context,
+	_ database.Transaction,
+) ([]*types.AccountIdentifier, error) {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return h.Helper.AllAccounts(ctx, dbTx)
+}
+
+// LockedAccounts returns all locked accounts
+// in the database of the network.
+func (h *networkWorkerHelper) LockedAccounts(
+	ctx context.Context,
+	_ database.Transaction,
+) ([]*types.AccountIdentifier, error) {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return h.Helper.LockedAccounts(ctx, dbTx)
+}
+
+// Balance returns the balance of an account
+// in the database of the network.
+func (h *networkWorkerHelper) Balance(
+	ctx context.Context,
+	_ database.Transaction,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) (*types.Amount, error) {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return h.Helper.Balance(ctx, dbTx, account, currency)
+}
+
+// Coins returns the coins of an account
+// in the database of the network.
+func (h *networkWorkerHelper) Coins(
+	ctx context.Context,
+	_ database.Transaction,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) ([]*types.Coin, error) {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return h.Helper.Coins(ctx, dbTx, account, currency)
+}
+
+// SetBlob stores a blob in the database of the network.
+func (h *networkWorkerHelper) SetBlob(
+	ctx context.Context,
+	_ database.Transaction,
+	key string,
+	value []byte,
+) error {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	if err := h.Helper.SetBlob(ctx, dbTx, key, value); err != nil {
+		return err
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// GetBlob gets a blob from the database of the network.
+func (h *networkWorkerHelper) GetBlob(
+	ctx context.Context,
+	_ database.Transaction,
+	key string,
+) (bool, []byte, error) {
+	dbTx := h.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	return h.Helper.GetBlob(ctx, dbTx, key)
+}
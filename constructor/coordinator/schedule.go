@@ -0,0 +1,173 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Schedule controls how often new Jobs of a Workflow
+// are started. Exactly one of Interval, Rate, and Cron
+// must be populated.
+type Schedule struct {
+	// Interval is the time between the start of each Job.
+	Interval time.Duration
+
+	// Rate is the target number of Jobs started
+	// per second (ex: for load generation).
+	Rate float64
+
+	// Cron is a cron expression with 5 fields (minute,
+	// hour, day of month, month, and day of week) or a
+	// shorthand like @hourly. Jobs are started at each
+	// time that matches Cron (in local time).
+	Cron string
+
+	// Jitter is the maximum random delay added
+	// to the start of each Job.
+	Jitter time.Duration
+}
+
+// scheduler tracks when the next Job of
+// each scheduled Workflow can be started.
+type scheduler struct {
+	schedules map[string]*Schedule
+	crons     map[string]*cronSchedule
+	next      map[string]time.Time
+	rand      *rand.Rand
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		schedules: map[string]*Schedule{},
+		crons:     map[string]*cronSchedule{},
+		next:      map[string]time.Time{},
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// scheduleValidation ensures a *Schedule is valid.
+func scheduleValidation(schedule *Schedule) error {
+	if schedule == nil {
+		return fmt.Errorf("%w: schedule is nil", ErrScheduleInvalid)
+	}
+
+	populated := 0
+	for _, set := range []bool{schedule.Interval > 0, schedule.Rate > 0, len(schedule.Cron) > 0} {
+		if set {
+			populated++
+		}
+	}
+
+	if populated != 1 {
+		return fmt.Errorf(
+			"%w: exactly one of interval, rate, and cron must be populated",
+			ErrScheduleInvalid,
+		)
+	}
+
+	if schedule.Interval < 0 || schedule.Rate < 0 || schedule.Jitter < 0 {
+		return fmt.Errorf("%w: schedule cannot be negative", ErrScheduleInvalid)
+	}
+
+	if len(schedule.Cron) > 0 {
+		cron, err := parseCron(schedule.Cron)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrScheduleInvalid, err)
+		}
+
+		if cron.next(time.Now()).IsZero() {
+			return fmt.Errorf("%w: %s never matches", ErrScheduleInvalid, schedule.Cron)
+		}
+	}
+
+	return nil
+}
+
+// interval returns the time to wait
+// between the start of each Job.
+func (s *Schedule) interval() time.Duration {
+	if s.Rate > 0 {
+		return time.Duration(float64(time.Second) / s.Rate)
+	}
+
+	return s.Interval
+}
+
+// cron returns the parsed Cron of the *Schedule of
+// workflow (or nil if it is not scheduled with Cron).
+func (s *scheduler) cron(workflow string) *cronSchedule {
+	schedule, ok := s.schedules[workflow]
+	if !ok || len(schedule.Cron) == 0 {
+		return nil
+	}
+
+	cron, ok := s.crons[workflow]
+	if !ok {
+		// Cron is parsed when the schedule
+		// is validated, so it cannot fail here.
+		cron, _ = parseCron(schedule.Cron)
+		s.crons[workflow] = cron
+	}
+
+	return cron
+}
+
+// due returns the time until the next Job of workflow
+// can be started (or 0 if it can be started now).
+func (s *scheduler) due(workflow string, now time.Time) time.Duration {
+	next, ok := s.next[workflow]
+	if !ok {
+		// The first Job of a workflow scheduled with
+		// Cron waits for the first matching time.
+		cron := s.cron(workflow)
+		if cron == nil {
+			return 0
+		}
+
+		next = cron.next(now)
+		s.next[workflow] = next
+	}
+
+	if !now.Before(next) {
+		return 0
+	}
+
+	return next.Sub(now)
+}
+
+// started is invoked when a Job of
+// workflow is started.
+func (s *scheduler) started(workflow string, now time.Time) {
+	schedule, ok := s.schedules[workflow]
+	if !ok {
+		return
+	}
+
+	var next time.Time
+	if cron := s.cron(workflow); cron != nil {
+		next = cron.next(now)
+	} else {
+		next = now.Add(schedule.interval())
+	}
+
+	if schedule.Jitter > 0 {
+		next = next.Add(time.Duration(s.rand.Int63n(int64(schedule.Jitter))))
+	}
+
+	s.next[workflow] = next
+}
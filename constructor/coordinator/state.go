@@ -0,0 +1,310 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// JobStateStorage is an optional extension of JobStorage
+// that is required to export and import in-flight Jobs.
+type JobStateStorage interface {
+	// AllProcessing returns all in-flight (ready
+	// or broadcasting) Jobs.
+	AllProcessing(context.Context) ([]*job.Job, error)
+
+	// Import stores in-flight Jobs under their
+	// existing identifiers.
+	Import(context.Context, database.Transaction, []*job.Job) error
+}
+
+// BroadcastStateHelper is an optional extension of the
+// Helper that is required to export the pending broadcasts
+// of broadcasting Jobs.
+type BroadcastStateHelper interface {
+	// GetAllBroadcasts returns all
+	// pending broadcasts.
+	GetAllBroadcasts(context.Context) ([]*modules.Broadcast, error)
+}
+
+// JobState is the full state of an in-flight Job (its
+// scenario variables and index of the next scenario to
+// execute) and its pending broadcast (if it is broadcasting).
+type JobState struct {
+	Job       *job.Job           `json:"job"`
+	Broadcast *modules.Broadcast `json:"broadcast,omitempty"`
+}
+
+// State is all in-flight Jobs of a Coordinator. It can be
+// exported from one Coordinator and imported into another
+// (ex: after a restart on a new database) to resume all
+// in-flight Workflows instead of abandoning them (and any
+// funds they have reserved).
+type State struct {
+	Jobs []*JobState `json:"jobs"`
+}
+
+// ExportState returns the *State of all in-flight Jobs (sorted
+// by identifier). The JobStorage must implement JobStateStorage and,
+// if any Job is broadcasting, the Helper must implement
+// BroadcastStateHelper.
+func (c *Coordinator) ExportState(ctx context.Context) (*State, error) {
+	storage, ok := c.storage.(JobStateStorage)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: job storage does not implement JobStateStorage",
+			ErrStateUnsupported,
+		)
+	}
+
+	jobs, err := storage.AllProcessing(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrJobsUnretrievable, err.Error())
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return identifierLess(jobs[i].Identifier, jobs[j].Identifier)
+	})
+
+	broadcasts, err := c.pendingBroadcasts(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{Jobs: make([]*JobState, len(jobs))}
+	for i, j := range jobs {
+		jobState := &JobState{Job: j}
+		if j.Status == job.Broadcasting {
+			broadcast, ok := broadcasts[j.Identifier]
+			if !ok {
+				return nil, fmt.Errorf(
+					"%w: broadcast for job %s is missing",
+					ErrBroadcastsUnretrievable,
+					j.Identifier,
+				)
+			}
+
+			jobState.Broadcast = broadcast
+		}
+
+		state.Jobs[i] = jobState
+	}
+
+	return state, nil
+}
+
+// pendingBroadcasts returns all pending broadcasts by
+// Job identifier (only if any of jobs are broadcasting).
+func (c *Coordinator) pendingBroadcasts(
+	ctx context.Context,
+	jobs []*job.Job,
+) (map[string]*modules.Broadcast, error) {
+	broadcasts := map[string]*modules.Broadcast{}
+
+	broadcasting := false
+	for _, j := range jobs {
+		if j.Status == job.Broadcasting {
+			broadcasting = true
+			break
+		}
+	}
+
+	if !broadcasting {
+		return broadcasts, nil
+	}
+
+	helper, ok := c.helper.(BroadcastStateHelper)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: helper does not implement BroadcastStateHelper",
+			ErrStateUnsupported,
+		)
+	}
+
+	allBroadcasts, err := helper.GetAllBroadcasts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBroadcastsUnretrievable, err.Error())
+	}
+
+	for _, broadcast := range allBroadcasts {
+		broadcasts[broadcast.Identifier] = broadcast
+	}
+
+	return broadcasts, nil
+}
+
+// ImportState stores all Jobs in a *State (under their existing
+// identifiers) so that they are resumed on the next call to Process.
+// The pending broadcast of each broadcasting Job is enqueued for
+// broadcast again in the same database transaction. The JobStorage
+// must implement JobStateStorage.
+//
+// Jobs retain their Scenarios, so they are resumed even if their
+// Workflow is no longer provided to the Coordinator.
+func (c *Coordinator) ImportState(ctx context.Context, state *State) error {
+	storage, ok := c.storage.(JobStateStorage)
+	if !ok {
+		return fmt.Errorf(
+			"%w: job storage does not implement JobStateStorage",
+			ErrStateUnsupported,
+		)
+	}
+
+	if state == nil {
+		return fmt.Errorf("%w: state is nil", ErrJobStateInvalid)
+	}
+
+	dbTx := c.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	// Broadcasts on registered networks are enqueued in a
+	// database.Transaction of the network's Helper.
+	networkTxs := map[string]database.Transaction{}
+	defer func() {
+		for _, networkTx := range networkTxs {
+			networkTx.Discard(ctx)
+		}
+	}()
+
+	jobs := make([]*job.Job, len(state.Jobs))
+	for i, jobState := range state.Jobs {
+		if err := jobStateValidation(jobState); err != nil {
+			return err
+		}
+
+		j := jobState.Job
+		if j.Status == job.Broadcasting {
+			broadcast := jobState.Broadcast
+			broadcastTx, ok := networkTxs[types.Hash(broadcast.NetworkIdentifier)]
+			if !ok {
+				var separate bool
+				broadcastTx, separate = c.networkTransaction(
+					ctx,
+					dbTx,
+					broadcast.NetworkIdentifier,
+				)
+				if separate {
+					networkTxs[types.Hash(broadcast.NetworkIdentifier)] = broadcastTx
+				}
+			}
+
+			if err := c.helperFor(broadcast.NetworkIdentifier).Broadcast(
+				ctx,
+				broadcastTx,
+				j.Identifier,
+				broadcast.NetworkIdentifier,
+				broadcast.Intent,
+				broadcast.TransactionIdentifier,
+				broadcast.Payload,
+				broadcast.ConfirmationDepth,
+			); err != nil {
+				return fmt.Errorf("%w: unable to enqueue broadcast for job %s", err, j.Identifier)
+			}
+		}
+
+		jobs[i] = j
+	}
+
+	if err := storage.Import(ctx, dbTx, jobs); err != nil {
+		return fmt.Errorf("%w: unable to import jobs", err)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: unable to commit job import", err)
+	}
+
+	for _, networkTx := range networkTxs {
+		if err := networkTx.Commit(ctx); err != nil {
+			return fmt.Errorf("%w: unable to commit broadcast import", err)
+		}
+	}
+
+	// Imported Jobs may be processable.
+	c.resetVars()
+
+	return nil
+}
+
+// jobStateValidation ensures a *JobState
+// can be resumed.
+func jobStateValidation(jobState *JobState) error {
+	if jobState == nil || jobState.Job == nil {
+		return fmt.Errorf("%w: job is nil", ErrJobStateInvalid)
+	}
+
+	j := jobState.Job
+	if len(j.Identifier) == 0 {
+		return fmt.Errorf("%w: job identifier is empty", ErrJobStateInvalid)
+	}
+
+	if j.Index < 0 || j.Index > len(j.Scenarios) {
+		return fmt.Errorf(
+			"%w: job %s index %d is out of range",
+			ErrJobStateInvalid,
+			j.Identifier,
+			j.Index,
+		)
+	}
+
+	switch j.Status {
+	case job.Ready:
+		if jobState.Broadcast != nil {
+			return fmt.Errorf(
+				"%w: ready job %s cannot have a broadcast",
+				ErrJobStateInvalid,
+				j.Identifier,
+			)
+		}
+	case job.Broadcasting:
+		broadcast := jobState.Broadcast
+		if broadcast == nil || broadcast.TransactionIdentifier == nil ||
+			broadcast.NetworkIdentifier == nil {
+			return fmt.Errorf(
+				"%w: broadcasting job %s has no broadcast",
+				ErrJobStateInvalid,
+				j.Identifier,
+			)
+		}
+	default:
+		return fmt.Errorf(
+			"%w: job %s is %s",
+			ErrJobStateInvalid,
+			j.Identifier,
+			j.Status,
+		)
+	}
+
+	return nil
+}
+
+// identifierLess sorts Job identifiers numerically
+// (falling back to lexicographic order).
+func identifierLess(a string, b string) bool {
+	aIndex, aErr := strconv.Atoi(a)
+	bIndex, bErr := strconv.Atoi(b)
+	if aErr != nil || bErr != nil {
+		return a < b
+	}
+
+	return aIndex < bIndex
+}
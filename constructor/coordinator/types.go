@@ -36,6 +36,11 @@ const (
 	// we wait when no jobs are available
 	// to process.
 	NoJobsWaitTime = 10 * time.Second
+
+	// DefaultFeeBumpThreshold is the default number of times
+	// a transaction must be broadcast without confirmation
+	// before the BumpFee Workflow is invoked.
+	DefaultFeeBumpThreshold = 1
 )
 
 // Helper is used by the coordinator to process Jobs.
@@ -226,6 +231,54 @@ type Coordinator struct {
 	createAccountWorkflow *job.Workflow
 	requestFundsWorkflow  *job.Workflow
 	returnFundsWorkflow   *job.Workflow
+	bumpFeeWorkflow       *job.Workflow
+	topUpWorkflow         *job.Workflow
+
+	feeBumpThreshold int
+	registry         *Registry
+	prefunded        *worker.PrefundedAccounts
+
+	scheduler      *scheduler
+	scheduledWait  time.Duration
+	maxConcurrency int
+
+	finalityPolicies map[string]*FinalityPolicy
+	subscribers      []Subscriber
+	datasets         map[string]*Dataset
+}
+
+// PlanStep contains all artifacts created while
+// constructing a single transaction during Plan.
+type PlanStep struct {
+	// Scenario is the name of the scenario
+	// that created the broadcast.
+	Scenario string                   `json:"scenario"`
+	Network  *types.NetworkIdentifier `json:"network"`
+	Intent   []*types.Operation       `json:"intent"`
+	DryRun   bool                     `json:"dry_run"`
+
+	PreprocessOptions  map[string]interface{}     `json:"preprocess_options,omitempty"`
+	RequiredPublicKeys []*types.AccountIdentifier `json:"required_public_keys,omitempty"`
+	Metadata           map[string]interface{}     `json:"metadata,omitempty"`
+	SuggestedFee       []*types.Amount            `json:"suggested_fee,omitempty"`
+
+	UnsignedTransaction string                  `json:"unsigned_transaction,omitempty"`
+	Payloads            []*types.SigningPayload `json:"payloads,omitempty"`
+	UnsignedOperations  []*types.Operation      `json:"unsigned_operations,omitempty"`
+
+	Signatures        []*types.Signature         `json:"signatures,omitempty"`
+	SignedTransaction string                     `json:"signed_transaction,omitempty"`
+	SignedOperations  []*types.Operation         `json:"signed_operations,omitempty"`
+	Signers           []*types.AccountIdentifier `json:"signers,omitempty"`
+
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier,omitempty"`
+}
+
+// PlanReport is the result of Plan. Steps are
+// populated in the order they were created.
+type PlanReport struct {
+	Workflow string      `json:"workflow"`
+	Steps    []*PlanStep `json:"steps"`
 }
 
 // JobStorage allows for the persistent and transactional
@@ -208,6 +208,90 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		"control flow": {
+			file: "control_flow.ros",
+			expectedWorkflows: []*job.Workflow{
+				{
+					Name:        "split_balance",
+					Concurrency: 1,
+					Scenarios: []*job.Scenario{
+						{
+							Name: "split",
+							Actions: []*job.Action{
+								{
+									Type:       job.SetVariable,
+									Input:      `"0"`,
+									OutputPath: "outputs",
+								},
+								{
+									Type:       job.SetVariable,
+									Input:      `"1003"`,
+									OutputPath: "remaining",
+								},
+								{
+									Type:  job.While,
+									Input: `{"operation": "greater_or_equal","left_value": {{remaining}},"right_value": "100"}`, // nolint
+									Actions: []*job.Action{
+										{
+											Type:       job.Math,
+											Input:      `{"operation": "subtraction","left_value": {{remaining}},"right_value": "100"}`, // nolint
+											OutputPath: "remaining",
+										},
+										{
+											Type:       job.Math,
+											Input:      `{"operation": "addition","left_value": {{outputs}},"right_value": "1"}`, // nolint
+											OutputPath: "outputs",
+										},
+									},
+								},
+								{
+									Type:       job.Math,
+									Input:      `{"operation": "modulo","left_value": "1003","right_value": "100"}`,
+									OutputPath: "dust",
+								},
+								{
+									Type:       job.Compare,
+									Input:      `{"operation": "equal","left_value": {{dust}},"right_value": {{remaining}}}`, // nolint
+									OutputPath: "consistent",
+								},
+								{
+									Type:  job.If,
+									Input: `{{consistent}}`,
+									Actions: []*job.Action{
+										{
+											Type:       job.SetVariable,
+											Input:      `"consistent"`,
+											OutputPath: "result",
+										},
+									},
+									Else: []*job.Action{
+										{
+											Type:       job.SetVariable,
+											Input:      `"inconsistent"`,
+											OutputPath: "result",
+										},
+										{
+											Type:  job.PrintMessage,
+											Input: `{"remaining": {{remaining}}}`,
+										},
+									},
+								},
+								{
+									Type:  job.If,
+									Input: `{"operation": "not_equal","left_value": {{outputs}},"right_value": "10"}`, // nolint
+									Actions: []*job.Action{
+										{
+											Type:  job.Assert,
+											Input: `"-1"`,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"workflow error: missing concurrency": {
 			file:                "missing_concurrency.ros",
 			expectedErr:         ErrParsingWorkflowConcurrency,
@@ -322,6 +406,18 @@ func TestParse(t *testing.T) {
 			expectedErrLine:     12,
 			expectedErrContents: `"currency": {{currency}`,
 		},
+		"block error: else after while": {
+			file:                "while_else.ros",
+			expectedErr:         ErrSyntax,
+			expectedErrLine:     6,
+			expectedErrContents: "} else {",
+		},
+		"block error: unexpected end of input": {
+			file:                "block_eof.ros",
+			expectedErr:         ErrUnexpectedEOF,
+			expectedErrLine:     5,
+			expectedErrContents: "remaining = {{remaining}} - 100;",
+		},
 		"file error: file does not exist": {
 			file:        "blah_blah.ros",
 			expectedErr: ErrCannotOpenFile,
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -39,6 +40,14 @@ const (
 	subtract            = "-"
 	multiply            = "*"
 	divide              = "/"
+	modulo              = "%"
+	greaterOrEqual      = ">="
+	lessOrEqual         = "<="
+	equalTo             = "=="
+	notEqualTo          = "!="
+	greaterThan         = ">"
+	lessThan            = "<"
+	elseBlock           = "}else{"
 	openParens          = "("
 	closeParens         = ")"
 	endLine             = ";"
@@ -47,6 +56,24 @@ const (
 	pathSeparator       = "."
 )
 
+// blockRegex matches the opening line of an if
+// or while block (ex: while ({{i}} < 10) {).
+var blockRegex = regexp.MustCompile(`^(if|while)\s*\((.+)\)\s*\{$`)
+
+// comparisonSymbols are ordered so that multi-character
+// symbols are matched before their prefixes.
+var comparisonSymbols = []struct {
+	symbol    string
+	operation job.ComparisonOperation
+}{
+	{greaterOrEqual, job.GreaterOrEqual},
+	{lessOrEqual, job.LessOrEqual},
+	{equalTo, job.Equal},
+	{notEqualTo, job.NotEqual},
+	{greaterThan, job.GreaterThan},
+	{lessThan, job.LessThan},
+}
+
 type parser struct {
 	scanner      *bufio.Scanner
 	lineNumber   int
@@ -106,6 +133,36 @@ func wrapValue(input string) string {
 	return fmt.Sprintf(`"%s"`, input)
 }
 
+// parseComparison attempts to parse a native comparison
+// (ex: {{balance}} >= 100) into a *job.CompareInput.
+func parseComparison(expression string) (string, bool) {
+	// Don't mistake a JSON object for a comparison.
+	if strings.HasPrefix(expression, openBrakcet) &&
+		!strings.HasPrefix(expression, openDoubleBracket) {
+		return "", false
+	}
+
+	for _, comparison := range comparisonSymbols {
+		tokens := strings.SplitN(expression, comparison.symbol, split2)
+		if len(tokens) != split2 {
+			continue
+		}
+
+		return fmt.Sprintf(
+			`{"operation": "%s","left_value": %s,"right_value": %s}`,
+			comparison.operation,
+			wrapValue(strings.TrimSpace(tokens[0])),
+			wrapValue(strings.TrimSpace(tokens[1])),
+		), true
+	}
+
+	return "", false
+}
+
+func isElse(line string) bool {
+	return strings.Join(strings.Fields(line), "") == elseBlock
+}
+
 func parseActionType(line string) (job.ActionType, string, string, error) {
 	var outputPath string
 
@@ -132,19 +189,29 @@ func parseActionType(line string) (job.ActionType, string, string, error) {
 		case job.GenerateKey, job.Derive, job.SaveAccount, job.PrintMessage,
 			job.RandomString, job.Math, job.FindBalance, job.RandomNumber, job.Assert,
 			job.FindCurrencyAmount, job.LoadEnv, job.HTTPRequest, job.SetBlob,
-			job.GetBlob:
+			job.GetBlob, job.Compare, job.SignPayload, job.RandomBytes, job.Timestamp,
+			job.Hash, job.AssertBalance, job.AssertCoins:
 			return thisAction, outputPath, tokens[1], nil
 		default:
 			return "", "", "", ErrInvalidActionType
 		}
 	}
 
+	// Attempt to parse native Compare
+	if len(outputPath) > 0 {
+		syntheticOutput, ok := parseComparison(strings.TrimSuffix(remaining, endLine))
+		if ok {
+			return job.Compare, outputPath, syntheticOutput + endLine, nil
+		}
+	}
+
 	// Attempt to parse native Math
 	for symbol, mathOperation := range map[string]job.MathOperation{
 		add:      job.Addition,
 		subtract: job.Subtraction,
 		multiply: job.Multiplication,
 		divide:   job.Division,
+		modulo:   job.Modulo,
 	} {
 		tokens = strings.SplitN(remaining, symbol, split2)
 		if len(tokens) == split2 {
@@ -231,6 +298,108 @@ func (p *parser) parseAction(
 	return nil, ctx.Err()
 }
 
+// parseBlock parses an if or while block (and the else
+// block of an if) starting at line.
+func (p *parser) parseBlock(
+	ctx context.Context,
+	variables map[string]struct{},
+	line string,
+) (*job.Action, error) {
+	matches := blockRegex.FindStringSubmatch(line)
+	condition := strings.TrimSpace(matches[2])
+	input, ok := parseComparison(condition)
+	if !ok {
+		input = wrapValue(condition)
+	}
+
+	missingVariables, err := checkForVariables(ctx, variables, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missingVariables) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrVariableUndefined, missingVariables)
+	}
+
+	action := &job.Action{
+		Type:  job.ActionType(matches[1]),
+		Input: input,
+	}
+
+	var end string
+	action.Actions, end, err = p.parseActions(ctx, variables)
+	if errors.Is(err, ErrEOF) {
+		return nil, fmt.Errorf("%w (block parsing): %s", ErrUnexpectedEOF, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if end == closeBracket {
+		return action, nil
+	}
+
+	if !isElse(end) || action.Type != job.If {
+		return nil, fmt.Errorf(
+			"%w: expected %s block to end with }, but got %s",
+			ErrSyntax,
+			action.Type,
+			end,
+		)
+	}
+
+	action.Else, end, err = p.parseActions(ctx, variables)
+	if errors.Is(err, ErrEOF) {
+		return nil, fmt.Errorf("%w (block parsing): %s", ErrUnexpectedEOF, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if end != closeBracket {
+		return nil, fmt.Errorf(
+			"%w: expected else block to end with }, but got %s",
+			ErrSyntax,
+			end,
+		)
+	}
+
+	return action, nil
+}
+
+// parseActions parses actions until it reaches a line that
+// ends the enclosing scenario or block and returns that line.
+func (p *parser) parseActions(
+	ctx context.Context,
+	variables map[string]struct{},
+) ([]*job.Action, string, error) {
+	actions := []*job.Action{}
+	for ctx.Err() == nil {
+		line, err := p.readLine(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if line == closeBracket || line == endScenarioContinue || isElse(line) {
+			return actions, line, nil
+		}
+
+		var action *job.Action
+		if blockRegex.MatchString(line) {
+			action, err = p.parseBlock(ctx, variables, line)
+		} else {
+			action, err = p.parseAction(ctx, variables, line)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: unable to parse action", err)
+		}
+
+		actions = append(actions, action)
+	}
+
+	return nil, "", ctx.Err()
+}
+
 func parseScenarioName(line string) (string, error) {
 	tokens := strings.SplitN(line, openBrakcet, split2)
 	if len(tokens) != split2 {
@@ -270,34 +439,25 @@ func (p *parser) parseScenario(
 		return nil, false, fmt.Errorf("%w: %s", ErrDuplicateScenarioName, name)
 	}
 
-	actions := []*job.Action{}
-	for ctx.Err() == nil {
-		line, err := p.readLine(ctx)
-		if err != nil {
-			return nil, false, fmt.Errorf("%w: scenario parsing failed", err)
-		}
-		if line == closeBracket {
-			return &job.Scenario{
-				Name:    name,
-				Actions: actions,
-			}, false, nil
-		}
-		if line == endScenarioContinue {
-			return &job.Scenario{
-				Name:    name,
-				Actions: actions,
-			}, true, nil
-		}
-
-		action, err := p.parseAction(ctx, variables, line)
-		if err != nil {
-			return nil, false, fmt.Errorf("%w: unable to parse action", err)
-		}
-
-		actions = append(actions, action)
+	actions, end, err := p.parseActions(ctx, variables)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: scenario parsing failed", err)
 	}
 
-	return nil, false, ctx.Err()
+	switch end {
+	case closeBracket:
+		return &job.Scenario{
+			Name:    name,
+			Actions: actions,
+		}, false, nil
+	case endScenarioContinue:
+		return &job.Scenario{
+			Name:    name,
+			Actions: actions,
+		}, true, nil
+	default:
+		return nil, false, fmt.Errorf("%w: else must follow an if block", ErrSyntax)
+	}
 }
 
 func parseWorkflowName(line string) (string, int, error) {
@@ -43,6 +43,18 @@ var (
 	// from a job.
 	ErrUnableToCreateBroadcast = errors.New("unable to create broadcast")
 
+	// ErrUnableToCreateBumpFee is returned when the *StuckBroadcast
+	// cannot be stored in the state of a BumpFee Job.
+	ErrUnableToCreateBumpFee = errors.New("unable to create bump fee job")
+
+	// ErrUnableToCreateTopUp is returned when the *PrefundedAccountStatus
+	// cannot be stored in the state of a TopUp Job.
+	ErrUnableToCreateTopUp = errors.New("unable to create top up job")
+
+	// ErrUnableToCreateDatasetRow is returned when a dataset row
+	// cannot be stored in the state of a Job.
+	ErrUnableToCreateDatasetRow = errors.New("unable to create dataset row job")
+
 	// ErrOperationFormat is returned when []*types.Operation cannot be unmarshaled
 	// from <scenario_name>.operations.
 	ErrOperationFormat = errors.New("operation format")
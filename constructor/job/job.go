@@ -38,6 +38,53 @@ func New(workflow *Workflow) *Job {
 	}
 }
 
+// NewBumpFee creates a new *Job for the BumpFee
+// Workflow that replaces a *StuckBroadcast.
+func NewBumpFee(workflow *Workflow, stuck *StuckBroadcast) (*Job, error) {
+	state, err := sjson.SetRaw("", StuckBroadcastVariable, types.PrintStruct(stuck))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToCreateBumpFee, err.Error())
+	}
+
+	j := New(workflow)
+	j.State = state
+
+	return j, nil
+}
+
+// NewTopUp creates a new *Job for the TopUp
+// Workflow that tops up a *PrefundedAccountStatus.
+func NewTopUp(workflow *Workflow, status *PrefundedAccountStatus) (*Job, error) {
+	state, err := sjson.SetRaw("", PrefundedAccountVariable, types.PrintStruct(status))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToCreateTopUp, err.Error())
+	}
+
+	j := New(workflow)
+	j.State = state
+
+	return j, nil
+}
+
+// NewDatasetRow creates a new *Job for the row at index
+// of a dataset (with the values in row).
+func NewDatasetRow(workflow *Workflow, index int, row map[string]interface{}) (*Job, error) {
+	state, err := sjson.SetRaw("", DatasetRowVariable, types.PrintStruct(row))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToCreateDatasetRow, err.Error())
+	}
+
+	state, err = sjson.Set(state, DatasetIndexVariable, index)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToCreateDatasetRow, err.Error())
+	}
+
+	j := New(workflow)
+	j.State = state
+
+	return j, nil
+}
+
 // CreateBroadcast returns a *Broadcast for a given job or
 // nil if none is required.
 func (j *Job) CreateBroadcast() (*Broadcast, error) {
@@ -165,6 +212,38 @@ func (j *Job) getBroadcastScenario() (*Scenario, error) {
 	return j.Scenarios[broadcastIndex], nil
 }
 
+// BroadcastNetwork returns the *types.NetworkIdentifier
+// of the broadcast a Job is waiting on.
+func (j *Job) BroadcastNetwork() (*types.NetworkIdentifier, error) {
+	scenario, err := j.getBroadcastScenario()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToHandleBroadcast, err.Error())
+	}
+
+	var network types.NetworkIdentifier
+	if err := j.unmarshalStruct(scenario.Name, Network, &network); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkInvalid, err.Error())
+	}
+
+	return &network, nil
+}
+
+// BroadcastIntent returns the intent ([]*types.Operation)
+// of the broadcast a Job is waiting on.
+func (j *Job) BroadcastIntent() ([]*types.Operation, error) {
+	scenario, err := j.getBroadcastScenario()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToHandleBroadcast, err.Error())
+	}
+
+	var operations []*types.Operation
+	if err := j.unmarshalStruct(scenario.Name, Operations, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrOperationFormat, err.Error())
+	}
+
+	return operations, nil
+}
+
 func (j *Job) injectKeyAndMarkReady(
 	scenarioName string,
 	key ReservedVariable,
@@ -183,6 +262,7 @@ func (j *Job) injectKeyAndMarkReady(
 
 	if j.CheckComplete() {
 		j.Status = Completed
+		j.Compensations = nil
 		return nil
 	}
 
@@ -26,6 +26,22 @@ const (
 	// ReservedWorkflowConcurrency is the expected concurrency
 	// of the create account and request funds scenario.
 	ReservedWorkflowConcurrency = 1
+
+	// StuckBroadcastVariable is the variable in the state of a
+	// BumpFee Job that contains the *StuckBroadcast to replace.
+	StuckBroadcastVariable = "stuck_broadcast"
+
+	// PrefundedAccountVariable is the variable in the state of a
+	// TopUp Job that contains the *PrefundedAccountStatus to top up.
+	PrefundedAccountVariable = "prefunded_account"
+
+	// DatasetRowVariable is the variable in the state of a Job
+	// started from a dataset that contains the values of its row.
+	DatasetRowVariable = "dataset_row"
+
+	// DatasetIndexVariable is the variable in the state of a Job
+	// started from a dataset that contains the index of its row.
+	DatasetIndexVariable = "dataset_index"
 )
 
 // ReservedVariable is a reserved variable
@@ -106,15 +122,47 @@ const (
 	// on UTXO blockchains.
 	Math ActionType = "math"
 
+	// Compare is used to compare 2 numbers (ex: {{balance}} >= 100).
+	// The result is saved as a boolean that can be used as the condition
+	// of an If or While.
+	Compare ActionType = "compare"
+
+	// If performs Actions when its input evaluates to true and
+	// Else when it does not. The input can be a boolean (often the
+	// output of Compare) or a CompareInput.
+	If ActionType = "if"
+
+	// While performs Actions until its input (evaluated before each
+	// iteration like the input of If) is no longer true. This is useful
+	// for expressing workflows like "split balance into N outputs" or
+	// "retry until balance >= X".
+	While ActionType = "while"
+
 	// RandomString generates a string according to some provided regex.
 	// It is used to generate account names for blockchains that require
 	// on-chain origination.
 	RandomString ActionType = "random_string"
 
-	// RandomNumber generates a random number in some range [min, max).
-	// It is used to generate random transaction amounts.
+	// RandomNumber generates a random number in some range [min, max)
+	// drawn from some RandomDistribution (uniform by default). It is used
+	// to generate random transaction amounts.
 	RandomNumber ActionType = "random_number"
 
+	// RandomBytes generates a random byte slice of some length
+	// (encoded as hex by default). It is used to generate random
+	// memos, nonces, or salts.
+	RandomBytes ActionType = "random_bytes"
+
+	// Timestamp returns the current time (optionally offset by
+	// some number of seconds). It is used to populate expiration
+	// times or to record when a scenario was executed.
+	Timestamp ActionType = "timestamp"
+
+	// Hash returns the hex-encoded hash of some data (ex: a string
+	// or any scenario value) using some HashFunction. It is used to
+	// derive data like commitments or identifiers from other values.
+	Hash ActionType = "hash"
+
 	// FindCurrencyAmount finds a *types.Amount for a certain currency
 	// in an array of []*types.Amount. This is typically used when parsing
 	// the suggested fee response from /construction/metadata.
@@ -126,6 +174,18 @@ const (
 	// suggested fee to broadcast a transaction.
 	Assert ActionType = "assert"
 
+	// AssertBalance queries the balance of an account (on the Data API)
+	// and causes execution to fail if it does not satisfy a comparison.
+	// The observed *types.Amount is returned. This is used by workflows
+	// that verify their own side effects on-chain.
+	AssertBalance ActionType = "assert_balance"
+
+	// AssertCoins queries the coins of an account (on the Data API) and
+	// causes execution to fail if they do not include some coins or their
+	// count does not satisfy a comparison. The observed []*types.Coin is
+	// returned.
+	AssertCoins ActionType = "assert_coins"
+
 	// LoadEnv loads some value from an environment variable. This
 	// is very useful injecting an API token for algorithmic fauceting
 	// when running CI.
@@ -133,7 +193,9 @@ const (
 
 	// HTTPRequest makes an HTTP request at some URL. This is useful
 	// for making a request to a faucet to automate Construction API
-	// testing.
+	// testing or for calling external services (ex: fee oracles) during
+	// scenario execution. The response (or some value extracted from
+	// it with a JSON path) is saved to the OutputPath.
 	HTTPRequest ActionType = "http_request"
 
 	// SetBlob stores an arbitrary blob at some key (any valid JSON is
@@ -149,6 +211,13 @@ const (
 	// GetBlob attempts to retrieve some previously saved blob.
 	// If the blob is not accessible, it will return an error.
 	GetBlob ActionType = "get_blob"
+
+	// SignPayload signs arbitrary bytes (or a message) with the key
+	// of a stored account and returns the *types.Signature. This is
+	// useful for workflows that need auxiliary signatures (ex: permits,
+	// memos, or proof-of-ownership messages) beyond the signatures
+	// required to construct a transaction.
+	SignPayload ActionType = "sign_payload"
 )
 
 // Action is a step of computation that
@@ -157,6 +226,35 @@ type Action struct {
 	Input      string     `json:"input"`
 	Type       ActionType `json:"type"`
 	OutputPath string     `json:"output_path,omitempty"`
+
+	// Actions are performed by If (when its condition is true)
+	// and While (on each iteration).
+	Actions []*Action `json:"actions,omitempty"`
+
+	// Else are performed by If when its condition is false.
+	Else []*Action `json:"else,omitempty"`
+
+	// Timeout is the maximum number of seconds each attempt
+	// of the Action may take (the context provided to the
+	// Action is canceled once it elapses). If not populated,
+	// there is no timeout.
+	Timeout int `json:"timeout,omitempty"`
+
+	// Retries is the number of times the Action is retried
+	// (waiting RetryDelay seconds between attempts) if it
+	// fails. Actions that fail because an account must be
+	// created or because a balance is unsatisfiable are
+	// never retried.
+	Retries    int `json:"retries,omitempty"`
+	RetryDelay int `json:"retry_delay,omitempty"`
+
+	// Compensation is invoked (with the state of the Job at
+	// the time of failure) if any later Action in the Job fails
+	// or if a broadcast created by the Job fails. This is useful
+	// for undoing side effects outside of the database (ex:
+	// releasing a reserved UTXO with http_request). Compensations
+	// are invoked in the reverse order they were registered.
+	Compensation *Action `json:"compensation,omitempty"`
 }
 
 // GenerateKeyInput is the input for GenerateKey.
@@ -193,6 +291,9 @@ const (
 	Multiplication MathOperation = "multiplication"
 
 	Division MathOperation = "division"
+
+	// Modulo is the remainder of LeftValue / RightValue.
+	Modulo MathOperation = "modulo"
 )
 
 // MathInput is the input to Math.
@@ -202,6 +303,58 @@ type MathInput struct {
 	RightValue string        `json:"right_value"`
 }
 
+// ComparisonOperation is some comparison that
+// can be performed on 2 numbers.
+type ComparisonOperation string
+
+const (
+	// Equal is LeftValue == RightValue.
+	Equal ComparisonOperation = "equal"
+
+	// NotEqual is LeftValue != RightValue.
+	NotEqual ComparisonOperation = "not_equal"
+
+	// GreaterThan is LeftValue > RightValue.
+	GreaterThan ComparisonOperation = "greater_than"
+
+	// GreaterOrEqual is LeftValue >= RightValue.
+	GreaterOrEqual ComparisonOperation = "greater_or_equal"
+
+	// LessThan is LeftValue < RightValue.
+	LessThan ComparisonOperation = "less_than"
+
+	// LessOrEqual is LeftValue <= RightValue.
+	LessOrEqual ComparisonOperation = "less_or_equal"
+)
+
+// CompareInput is the input to Compare.
+type CompareInput struct {
+	Operation  ComparisonOperation `json:"operation"`
+	LeftValue  string              `json:"left_value"`
+	RightValue string              `json:"right_value"`
+}
+
+// SignPayloadInput is the input to SignPayload.
+type SignPayloadInput struct {
+	// AccountIdentifier is the account whose stored key
+	// is used to sign.
+	AccountIdentifier *types.AccountIdentifier `json:"account_identifier"`
+
+	// HexBytes are the hex-encoded bytes to sign. Exactly one
+	// of HexBytes and Message must be populated. Note, some
+	// signature types (ex: ecdsa) can only sign 32-byte hashes.
+	HexBytes string `json:"hex_bytes,omitempty"`
+
+	// Message is a UTF-8 message to sign.
+	Message string `json:"message,omitempty"`
+
+	// CurveType can be populated to ensure the stored
+	// key uses a particular curve.
+	CurveType types.CurveType `json:"curve_type,omitempty"`
+
+	SignatureType types.SignatureType `json:"signature_type"`
+}
+
 // FindBalanceInput is the input to FindBalance.
 type FindBalanceInput struct {
 	// AccountIdentifier can be optionally provided to ensure the balance returned
@@ -238,6 +391,20 @@ type FindBalanceInput struct {
 	// for avoiding using the same Coin twice.
 	NotCoins []*types.CoinIdentifier `json:"not_coins,omitempty"`
 
+	// CoinSelection is the strategy used to select coins that (together)
+	// have the minimum balance when RequireCoin is true. If not populated,
+	// the first coin with the minimum balance is selected.
+	CoinSelection CoinSelectionStrategy `json:"coin_selection,omitempty"`
+
+	// MaxInputs is the maximum number of coins that can be selected
+	// by CoinSelection. If the value is <= 0, there is no limit (except
+	// for ConsolidateCoins, where it is required).
+	MaxInputs int `json:"max_inputs,omitempty"`
+
+	// DustThreshold can be populated to ignore coins with a
+	// value less than it when using CoinSelection.
+	DustThreshold string `json:"dust_threshold,omitempty"`
+
 	// CreateLimit is used to determine if we should create a new address using
 	// the CreateAccount Workflow. This will only occur if the
 	// total number of addresses is under some pre-defined limit.
@@ -248,6 +415,11 @@ type FindBalanceInput struct {
 	// created with some probability [0, 100). This will override the search
 	// for any valid accounts and instead return ErrCreateAccount.
 	CreateProbability int `json:"create_probability,omitempty"`
+
+	// Network can be populated in Workflows that span multiple
+	// networks to find a balance on a particular network (instead
+	// of the network of the default Helper).
+	Network *types.NetworkIdentifier `json:"network,omitempty"`
 }
 
 // FindBalanceOutput is returned by FindBalance.
@@ -259,15 +431,155 @@ type FindBalanceOutput struct {
 	// Balance found at a particular currency.
 	Balance *types.Amount `json:"balance"`
 
-	// Coin is populated if RequireCoin is true.
+	// Coin is populated if RequireCoin is true (and
+	// a single coin is selected).
 	Coin *types.CoinIdentifier `json:"coin,omitempty"`
+
+	// Coins are populated if CoinSelection is populated.
+	// Balance is the sum of their amounts.
+	Coins []*types.Coin `json:"coins,omitempty"`
 }
 
+// CoinSelectionStrategy is the algorithm used
+// by FindBalance to select coins.
+type CoinSelectionStrategy string
+
+const (
+	// LargestFirst selects the largest coins
+	// until the minimum balance is reached.
+	LargestFirst CoinSelectionStrategy = "largest_first"
+
+	// SmallestFirst selects the smallest coins
+	// until the minimum balance is reached.
+	SmallestFirst CoinSelectionStrategy = "smallest_first"
+
+	// BranchAndBound searches for the set of coins
+	// that exceeds the minimum balance by the least
+	// amount (ideally matching it exactly, so that no
+	// change output is required).
+	BranchAndBound CoinSelectionStrategy = "branch_and_bound"
+
+	// ConsolidateCoins selects the MaxInputs smallest
+	// coins (consolidating as many coins as possible)
+	// if they have the minimum balance.
+	ConsolidateCoins CoinSelectionStrategy = "consolidate"
+)
+
+// RandomDistribution is the probability distribution
+// a RandomNumber is drawn from.
+type RandomDistribution string
+
+const (
+	// UniformDistribution draws each number in the
+	// range with equal probability.
+	UniformDistribution RandomDistribution = "uniform"
+
+	// NormalDistribution draws numbers from a normal distribution
+	// (resampling any number outside of the range) with some
+	// Mean and StandardDeviation.
+	NormalDistribution RandomDistribution = "normal"
+
+	// ZipfDistribution draws numbers from a Zipf distribution
+	// (with some Exponent) offset by the Minimum, so that
+	// small numbers are much more likely than large numbers.
+	ZipfDistribution RandomDistribution = "zipf"
+)
+
 // RandomNumberInput is used to generate a random
 // number in the range [minimum, maximum).
 type RandomNumberInput struct {
 	Minimum string `json:"minimum"`
 	Maximum string `json:"maximum"`
+
+	// Distribution defaults to UniformDistribution.
+	Distribution RandomDistribution `json:"distribution,omitempty"`
+
+	// Mean and StandardDeviation are only used by
+	// NormalDistribution. They default to the midpoint of
+	// the range and a sixth of the range, respectively.
+	Mean              string `json:"mean,omitempty"`
+	StandardDeviation string `json:"standard_deviation,omitempty"`
+
+	// Exponent is only used by ZipfDistribution and
+	// must be > 1 (defaults to 1.1).
+	Exponent float64 `json:"exponent,omitempty"`
+}
+
+// Encoding is the string representation of bytes.
+type Encoding string
+
+const (
+	// UTF8Encoding represents bytes as a UTF-8 string.
+	UTF8Encoding Encoding = "utf8"
+
+	// HexEncoding represents bytes as a hex string
+	// (without a 0x prefix).
+	HexEncoding Encoding = "hex"
+
+	// Base64Encoding represents bytes as a standard
+	// base64 string.
+	Base64Encoding Encoding = "base64"
+)
+
+// RandomBytesInput is the input to RandomBytes.
+type RandomBytesInput struct {
+	Length int `json:"length"`
+
+	// Encoding defaults to HexEncoding (UTF8Encoding
+	// is not supported).
+	Encoding Encoding `json:"encoding,omitempty"`
+}
+
+// TimestampUnit is the unit of a Timestamp.
+type TimestampUnit string
+
+const (
+	// Seconds is the number of seconds
+	// since the Unix epoch.
+	Seconds TimestampUnit = "seconds"
+
+	// Milliseconds is the number of milliseconds
+	// since the Unix epoch.
+	Milliseconds TimestampUnit = "milliseconds"
+
+	// RFC3339 is an RFC 3339 string (in UTC).
+	RFC3339 TimestampUnit = "rfc3339"
+)
+
+// TimestampInput is the input to Timestamp.
+type TimestampInput struct {
+	// Unit defaults to Seconds.
+	Unit TimestampUnit `json:"unit,omitempty"`
+
+	// OffsetSeconds is added to the
+	// current time (ex: to create an expiry).
+	OffsetSeconds int64 `json:"offset_seconds,omitempty"`
+}
+
+// HashFunction is a function supported by Hash.
+type HashFunction string
+
+const (
+	// Sha256 is SHA-256.
+	Sha256 HashFunction = "sha256"
+
+	// Keccak256 is the legacy Keccak-256 used by Ethereum
+	// (not the standardized SHA3-256).
+	Keccak256 HashFunction = "keccak256"
+)
+
+// HashInput is the input to Hash.
+type HashInput struct {
+	Function HashFunction `json:"function"`
+
+	// Data is hashed after being decoded with Encoding if it
+	// is a string. Any other JSON value (ex: a populated scenario
+	// value) is hashed as its raw JSON bytes.
+	Data json.RawMessage `json:"data"`
+
+	// Encoding of Data (if it is a string). Defaults
+	// to UTF8Encoding.
+	Encoding Encoding `json:"encoding,omitempty"`
 }
 
 // FindCurrencyAmountInput is the input
@@ -277,6 +589,44 @@ type FindCurrencyAmountInput struct {
 	Amounts  []*types.Amount `json:"amounts"`
 }
 
+// AssertBalanceInput is the input
+// to AssertBalance.
+type AssertBalanceInput struct {
+	// Network is the network to query. If not populated,
+	// the default network is queried.
+	Network  *types.NetworkIdentifier `json:"network,omitempty"`
+	Account  *types.AccountIdentifier `json:"account_identifier"`
+	Currency *types.Currency          `json:"currency"`
+
+	// The observed balance must satisfy
+	// <balance> <Operation> <Value>.
+	Operation ComparisonOperation `json:"operation"`
+	Value     string              `json:"value"`
+}
+
+// AssertCoinsInput is the input
+// to AssertCoins.
+type AssertCoinsInput struct {
+	// Network is the network to query. If not populated,
+	// the default network is queried.
+	Network  *types.NetworkIdentifier `json:"network,omitempty"`
+	Account  *types.AccountIdentifier `json:"account_identifier"`
+	Currency *types.Currency          `json:"currency"`
+
+	// Contains are coins that must be owned by
+	// the account.
+	Contains []*types.CoinIdentifier `json:"contains,omitempty"`
+
+	// Excludes are coins that must not be owned
+	// by the account (ex: coins that were spent).
+	Excludes []*types.CoinIdentifier `json:"excludes,omitempty"`
+
+	// If Operation is populated, the number of coins
+	// must satisfy <count> <Operation> <Count>.
+	Operation ComparisonOperation `json:"operation,omitempty"`
+	Count     string              `json:"count,omitempty"`
+}
+
 // HTTPMethod is a type representing
 // allowed HTTP methods.
 type HTTPMethod string
@@ -297,6 +647,16 @@ type HTTPRequestInput struct {
 	// If the Method is POST, the Body
 	// can be populated with JSON.
 	Body string `json:"body"`
+
+	// Headers are added to the request (ex: an
+	// API token for algorithmic fauceting).
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ResponsePath is an optional JSON path (in gjson syntax) used
+	// to extract a value from a JSON response. If populated, only
+	// the extracted value is returned (instead of the entire
+	// response).
+	ResponsePath string `json:"response_path,omitempty"`
 }
 
 // SetBlobInput is the input to
@@ -352,8 +712,98 @@ const (
 	// returned to a single address (like a faucet). This
 	// is useful for CI testing.
 	ReturnFunds ReservedWorkflow = "return_funds"
+
+	// BumpFee is invoked when a broadcast has not been confirmed
+	// on-chain after it has been rebroadcast some number of times.
+	// The *StuckBroadcast is populated in the state of the Job
+	// (at StuckBroadcastVariable) and the first broadcast created by
+	// the Job replaces the stuck transaction (ex: replace-by-fee or
+	// child-pays-for-parent). If the Job does not create a broadcast,
+	// the stuck transaction is rebroadcast as is.
+	BumpFee ReservedWorkflow = "bump_fee"
+
+	// TopUp is invoked when the balance of a *PrefundedAccount
+	// falls below its minimum balance. The *PrefundedAccountStatus
+	// is populated in the state of the Job (at PrefundedAccountVariable).
+	// Like RequestFunds, TopUp should wait for the funds to arrive
+	// (ex: with find_balance) and must be executed with a concurrency of 1.
+	TopUp ReservedWorkflow = "top_up"
 )
 
+// PrefundedAccount is an account funded outside of the
+// constructor (ex: from a genesis allocation) that is
+// registered with spend limits so that long-running
+// tests never drain it.
+type PrefundedAccount struct {
+	AccountIdentifier *types.AccountIdentifier `json:"account_identifier"`
+	Currency          *types.Currency          `json:"currency"`
+
+	// SpendLimit is the maximum value that may be sent from
+	// the account. If not populated, there is no spend limit.
+	SpendLimit string `json:"spend_limit,omitempty"`
+
+	// MinimumBalance is the balance below which the account
+	// is considered underfunded (and the TopUp Workflow is
+	// invoked). If not populated, it is "0".
+	MinimumBalance string `json:"minimum_balance,omitempty"`
+}
+
+// PrefundedAccountStatus is the utilization of
+// a *PrefundedAccount.
+type PrefundedAccountStatus struct {
+	Account *PrefundedAccount `json:"account"`
+	Balance *types.Amount     `json:"balance"`
+
+	// Spent is the total value sent from the account by
+	// the constructor.
+	Spent string `json:"spent"`
+
+	// LimitReached is true if Spent is at least
+	// the SpendLimit of the account.
+	LimitReached bool `json:"limit_reached"`
+
+	// Underfunded is true if Balance is less than
+	// the MinimumBalance of the account.
+	Underfunded bool `json:"underfunded"`
+}
+
+// Depleted returns a boolean indicating if the
+// account should no longer be used by find_balance.
+func (s *PrefundedAccountStatus) Depleted() bool {
+	return s.LimitReached || s.Underfunded
+}
+
+// StuckBroadcast is the context of a broadcast that
+// has not been confirmed on-chain. It is provided to
+// the BumpFee Workflow.
+type StuckBroadcast struct {
+	// Job is the identifier of the Job that created
+	// the broadcast.
+	Job      string `json:"job"`
+	Workflow string `json:"workflow"`
+
+	// State is the state of the Job that created the
+	// broadcast (if any).
+	State json.RawMessage `json:"state,omitempty"`
+
+	Network               *types.NetworkIdentifier     `json:"network"`
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+	Intent                []*types.Operation           `json:"intent"`
+
+	// Metadata is the metadata returned by /construction/parse for
+	// the signed transaction. This often contains the information
+	// required to construct a replacement (ex: the nonce).
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Broadcasts is the number of times the
+	// transaction has been broadcast.
+	Broadcasts int `json:"broadcasts"`
+
+	// ReplacedTransactions are the transactions previously
+	// replaced by the BumpFee Workflow.
+	ReplacedTransactions []*types.TransactionIdentifier `json:"replaced_transactions,omitempty"`
+}
+
 // Workflow is a collection of scenarios to run (i.e.
 // transactions to broadcast) with some shared state.
 type Workflow struct {
@@ -404,6 +854,11 @@ type Job struct {
 	// a configuration file changes that could corrupt
 	// in-process flows.
 	Scenarios []*Scenario `json:"scenarios"`
+
+	// Compensations are the compensation Actions of all Actions
+	// completed in previous scenarios (in the order they were
+	// completed). They are invoked if the Job fails.
+	Compensations []*Action `json:"compensations,omitempty"`
 }
 
 // Broadcast contains information needed to create
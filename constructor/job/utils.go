@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // UnmarshalInput attempts to strictly unmarshal some input
@@ -32,5 +34,9 @@ func UnmarshalInput(input []byte, output interface{}) error {
 		return fmt.Errorf("%w: unable to unmarshal", err)
 	}
 
+	if err := types.CheckUnknownFields(input, output); err != nil {
+		return fmt.Errorf("%w: unable to unmarshal", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,271 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// MaxBranchAndBoundTries is the maximum number of coin
+// combinations considered by BranchAndBound.
+const MaxBranchAndBoundTries = 100000
+
+type coinValue struct {
+	coin  *types.Coin
+	value *big.Int
+}
+
+// coinSelectionValidation ensures the coin selection
+// fields of a *job.FindBalanceInput are valid.
+func coinSelectionValidation(input *job.FindBalanceInput) error {
+	if len(input.CoinSelection) == 0 {
+		return nil
+	}
+
+	if !input.RequireCoin {
+		return errors.New("coin selection requires coin")
+	}
+
+	switch input.CoinSelection {
+	case job.LargestFirst, job.SmallestFirst, job.BranchAndBound:
+	case job.ConsolidateCoins:
+		if input.MaxInputs <= 0 {
+			return errors.New("consolidate requires max inputs")
+		}
+	default:
+		return fmt.Errorf("coin selection %s is not supported", input.CoinSelection)
+	}
+
+	if len(input.DustThreshold) > 0 {
+		dust, err := types.BigInt(input.DustThreshold)
+		if err != nil {
+			return fmt.Errorf("%w: dust threshold invalid", err)
+		}
+
+		if dust.Sign() < 0 {
+			return fmt.Errorf("dust threshold %s is negative", input.DustThreshold)
+		}
+	}
+
+	return nil
+}
+
+// candidateCoins returns the coins that can be selected (that
+// are not disallowed or dust) sorted from largest to smallest.
+func candidateCoins(input *job.FindBalanceInput, coins []*types.Coin) ([]*coinValue, error) {
+	disallowedCoins := []string{}
+	for _, coinIdentifier := range input.NotCoins {
+		disallowedCoins = append(disallowedCoins, types.Hash(coinIdentifier))
+	}
+
+	dust := big.NewInt(0)
+	if len(input.DustThreshold) > 0 {
+		// DustThreshold is validated in coinSelectionValidation.
+		dust, _ = types.BigInt(input.DustThreshold)
+	}
+
+	candidates := []*coinValue{}
+	for _, coin := range coins {
+		if utils.ContainsString(disallowedCoins, types.Hash(coin.CoinIdentifier)) {
+			continue
+		}
+
+		value, err := types.AmountValue(coin.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		if value.Cmp(dust) < 0 {
+			continue
+		}
+
+		candidates = append(candidates, &coinValue{coin: coin, value: value})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].value.Cmp(candidates[j].value) > 0
+	})
+
+	return candidates, nil
+}
+
+// accumulateCoins selects candidates (in order) until target
+// is reached. It returns nil if target cannot be reached
+// with at most maxInputs coins.
+func accumulateCoins(candidates []*coinValue, target *big.Int, maxInputs int) []*coinValue {
+	selected := []*coinValue{}
+	sum := big.NewInt(0)
+	for _, candidate := range candidates {
+		if len(selected) > 0 && sum.Cmp(target) >= 0 {
+			break
+		}
+
+		if maxInputs > 0 && len(selected) == maxInputs {
+			return nil
+		}
+
+		selected = append(selected, candidate)
+		sum.Add(sum, candidate.value)
+	}
+
+	if len(selected) == 0 || sum.Cmp(target) < 0 {
+		return nil
+	}
+
+	return selected
+}
+
+// branchAndBound searches for the candidates (sorted from largest to
+// smallest) that exceed target by the least amount. The search
+// stops if an exact match is found or after MaxBranchAndBoundTries.
+// It also returns the number of combinations considered.
+func branchAndBound(
+	candidates []*coinValue,
+	target *big.Int,
+	maxInputs int,
+) ([]*coinValue, int) {
+	// remaining[i] is the sum of all candidates at or after i.
+	remaining := make([]*big.Int, len(candidates)+1)
+	remaining[len(candidates)] = big.NewInt(0)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = new(big.Int).Add(remaining[i+1], candidates[i].value)
+	}
+
+	var best []*coinValue
+	var bestExcess *big.Int
+	tries := 0
+
+	var search func(index int, selected []*coinValue, sum *big.Int) bool
+	search = func(index int, selected []*coinValue, sum *big.Int) bool {
+		tries++
+		if tries > MaxBranchAndBoundTries {
+			return true
+		}
+
+		if len(selected) > 0 && sum.Cmp(target) >= 0 {
+			excess := new(big.Int).Sub(sum, target)
+			if bestExcess == nil || excess.Cmp(bestExcess) < 0 {
+				best = append([]*coinValue{}, selected...)
+				bestExcess = excess
+			}
+
+			// Adding more coins only increases the excess.
+			return excess.Sign() == 0
+		}
+
+		if index == len(candidates) || (maxInputs > 0 && len(selected) == maxInputs) {
+			return false
+		}
+
+		// Even selecting all remaining candidates
+		// will not reach target.
+		if new(big.Int).Add(sum, remaining[index]).Cmp(target) < 0 {
+			return false
+		}
+
+		// Any selection from here adds at least the smallest
+		// candidate (the last), so if that does not improve on
+		// the current best excess, no selection from here will.
+		if bestExcess != nil {
+			minExcess := new(big.Int).Add(sum, candidates[len(candidates)-1].value)
+			if minExcess.Sub(minExcess, target).Cmp(bestExcess) >= 0 {
+				return false
+			}
+		}
+
+		included := new(big.Int).Add(sum, candidates[index].value)
+		if search(index+1, append(selected, candidates[index]), included) {
+			return true
+		}
+
+		return search(index+1, selected, sum)
+	}
+
+	search(0, []*coinValue{}, big.NewInt(0))
+
+	return best, tries
+}
+
+// consolidateCoins selects the maxInputs smallest candidates
+// (sorted from largest to smallest). It returns nil if they
+// do not reach target.
+func consolidateCoins(candidates []*coinValue, target *big.Int, maxInputs int) []*coinValue {
+	start := len(candidates) - maxInputs
+	if start < 0 {
+		start = 0
+	}
+
+	selected := candidates[start:]
+	sum := big.NewInt(0)
+	for _, candidate := range selected {
+		sum.Add(sum, candidate.value)
+	}
+
+	if len(selected) == 0 || sum.Cmp(target) < 0 {
+		return nil
+	}
+
+	return selected
+}
+
+// selectCoins returns the coins selected by the CoinSelection
+// of input that (together) have the MinimumBalance of input. It
+// returns nil if no such coins exist.
+func selectCoins(input *job.FindBalanceInput, coins []*types.Coin) ([]*types.Coin, error) {
+	target, err := types.AmountValue(input.MinimumBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := candidateCoins(input, coins)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*coinValue
+	switch input.CoinSelection {
+	case job.LargestFirst:
+		selected = accumulateCoins(candidates, target, input.MaxInputs)
+	case job.SmallestFirst:
+		reversed := make([]*coinValue, len(candidates))
+		for i, candidate := range candidates {
+			reversed[len(candidates)-1-i] = candidate
+		}
+
+		selected = accumulateCoins(reversed, target, input.MaxInputs)
+	case job.BranchAndBound:
+		selected, _ = branchAndBound(candidates, target, input.MaxInputs)
+	case job.ConsolidateCoins:
+		selected = consolidateCoins(candidates, target, input.MaxInputs)
+	}
+
+	if selected == nil {
+		return nil, nil
+	}
+
+	selectedCoins := make([]*types.Coin, len(selected))
+	for i, candidate := range selected {
+		selectedCoins[i] = candidate.coin
+	}
+
+	return selectedCoins, nil
+}
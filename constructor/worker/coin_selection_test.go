@@ -0,0 +1,207 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/constructor/worker"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var selectionCurrency = &types.Currency{
+	Symbol:   "BTC",
+	Decimals: 8,
+}
+
+func selectionCoins(values ...string) []*types.Coin {
+	coins := make([]*types.Coin, len(values))
+	for i, value := range values {
+		coins[i] = &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: fmt.Sprintf("coin%d", i),
+			},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: selectionCurrency,
+			},
+		}
+	}
+
+	return coins
+}
+
+func TestSelectCoins(t *testing.T) {
+	coins := selectionCoins("50", "10", "200", "30", "1", "65")
+
+	tests := map[string]struct {
+		strategy      job.CoinSelectionStrategy
+		minimum       string
+		maxInputs     int
+		dustThreshold string
+		notCoins      []*types.CoinIdentifier
+
+		expected []string
+	}{
+		"largest first": {
+			strategy: job.LargestFirst,
+			minimum:  "220",
+			expected: []string{"coin2", "coin5"},
+		},
+		"largest first (max inputs)": {
+			strategy:  job.LargestFirst,
+			minimum:   "300",
+			maxInputs: 2,
+		},
+		"smallest first": {
+			strategy: job.SmallestFirst,
+			minimum:  "35",
+			expected: []string{"coin4", "coin1", "coin3"},
+		},
+		"smallest first (dust)": {
+			strategy:      job.SmallestFirst,
+			minimum:       "35",
+			dustThreshold: "5",
+			expected:      []string{"coin1", "coin3"},
+		},
+		"smallest first (zero minimum)": {
+			strategy: job.SmallestFirst,
+			minimum:  "0",
+			expected: []string{"coin4"},
+		},
+		"branch and bound (exact match)": {
+			strategy: job.BranchAndBound,
+			minimum:  "95",
+			expected: []string{"coin5", "coin3"},
+		},
+		"branch and bound (least excess)": {
+			strategy:  job.BranchAndBound,
+			minimum:   "128",
+			maxInputs: 3,
+			expected:  []string{"coin5", "coin0", "coin3"},
+		},
+		"branch and bound (not coins)": {
+			strategy: job.BranchAndBound,
+			minimum:  "95",
+			notCoins: []*types.CoinIdentifier{{Identifier: "coin5"}},
+			expected: []string{"coin2"},
+		},
+		"branch and bound (insufficient)": {
+			strategy: job.BranchAndBound,
+			minimum:  "1000",
+		},
+		"consolidate": {
+			strategy:  job.ConsolidateCoins,
+			minimum:   "50",
+			maxInputs: 4,
+			expected:  []string{"coin0", "coin3", "coin1", "coin4"},
+		},
+		"consolidate (insufficient)": {
+			strategy:  job.ConsolidateCoins,
+			minimum:   "100",
+			maxInputs: 3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, err := selectCoins(&job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value:    test.minimum,
+					Currency: selectionCurrency,
+				},
+				RequireCoin:   true,
+				NotCoins:      test.notCoins,
+				CoinSelection: test.strategy,
+				MaxInputs:     test.maxInputs,
+				DustThreshold: test.dustThreshold,
+			}, coins)
+			assert.NoError(t, err)
+
+			if test.expected == nil {
+				assert.Nil(t, selected)
+				return
+			}
+
+			identifiers := make([]string, len(selected))
+			for i, coin := range selected {
+				identifiers[i] = coin.CoinIdentifier.Identifier
+			}
+			assert.Equal(t, test.expected, identifiers)
+		})
+	}
+}
+
+func TestBranchAndBoundPruning(t *testing.T) {
+	// No selection of the 3s matches 10 exactly, so every
+	// selection is considered unless it is pruned.
+	candidates := []*coinValue{{value: big.NewInt(11)}}
+	for i := 0; i < 40; i++ {
+		candidates = append(candidates, &coinValue{value: big.NewInt(3)})
+	}
+
+	selected, tries := branchAndBound(candidates, big.NewInt(10), 0)
+	assert.Equal(t, []*coinValue{candidates[0]}, selected)
+
+	// Once 11 is selected (an excess of 1), any selection of
+	// 9 or more with a 3 cannot do better, so it is pruned
+	// (otherwise, the search is stopped by the limit).
+	assert.Less(t, tries, MaxBranchAndBoundTries)
+}
+
+func TestFindBalanceWorker_CoinSelection(t *testing.T) {
+	ctx := context.Background()
+	account := &types.AccountIdentifier{Address: "addr1"}
+	coins := selectionCoins("50", "10", "200", "30")
+
+	helper := &mocks.Helper{}
+	helper.On("AllAccounts", ctx, mock.Anything).Return(
+		[]*types.AccountIdentifier{account},
+		nil,
+	).Once()
+	helper.On("LockedAccounts", ctx, mock.Anything).Return(
+		[]*types.AccountIdentifier{},
+		nil,
+	).Once()
+	helper.On("Coins", ctx, mock.Anything, account, selectionCurrency).Return(coins, nil).Once()
+
+	worker := New(helper)
+	output, err := worker.FindBalanceWorker(ctx, nil, types.PrintStruct(&job.FindBalanceInput{
+		MinimumBalance: &types.Amount{
+			Value:    "80",
+			Currency: selectionCurrency,
+		},
+		RequireCoin:   true,
+		CoinSelection: job.BranchAndBound,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, types.PrintStruct(&job.FindBalanceOutput{
+		AccountIdentifier: account,
+		Balance: &types.Amount{
+			Value:    "80",
+			Currency: selectionCurrency,
+		},
+		Coins: []*types.Coin{coins[0], coins[3]},
+	}), output)
+
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// DefaultZipfExponent is the exponent of a
+	// ZipfDistribution when none is provided.
+	DefaultZipfExponent = 1.1
+
+	// normalDeviations is the number of standard deviations
+	// between the mean and either end of the range when no
+	// StandardDeviation is provided.
+	normalDeviations = 3
+
+	// maxNormalSamples is the number of samples drawn from a
+	// NormalDistribution before clamping to the range.
+	maxNormalSamples = 100
+)
+
+// newRand returns a *rand.Rand seeded from crypto/rand. The
+// distributions in math/rand are not available on crypto/rand
+// and the numbers they produce are not used for secrets.
+func newRand() (*rand.Rand, error) {
+	var seed [8]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return nil, fmt.Errorf("cannot seed random source: %w", err)
+	}
+
+	return rand.New( // #nosec G404
+		rand.NewSource(int64(binary.BigEndian.Uint64(seed[:]))),
+	), nil
+}
+
+// randomNumber draws a number in the range [min, max)
+// from the distribution of input.
+func randomNumber(input *job.RandomNumberInput, min *big.Int, max *big.Int) (*big.Int, error) {
+	switch input.Distribution {
+	case "", job.UniformDistribution:
+		return utils.RandomNumber(min, max)
+	case job.NormalDistribution:
+		return normalNumber(input, min, max)
+	case job.ZipfDistribution:
+		return zipfNumber(input, min, max)
+	default:
+		return nil, fmt.Errorf("%s is not a supported distribution", input.Distribution)
+	}
+}
+
+// normalNumber draws a number in the range [min, max)
+// from a normal distribution.
+func normalNumber(input *job.RandomNumberInput, min *big.Int, max *big.Int) (*big.Int, error) {
+	width := new(big.Int).Sub(max, min)
+	if width.Sign() <= 0 {
+		return nil, fmt.Errorf(
+			"maximum value %s <= minimum value %s",
+			max.String(),
+			min.String(),
+		)
+	}
+
+	mean := new(big.Float).SetInt(new(big.Int).Add(min, max))
+	mean.Quo(mean, big.NewFloat(2)) // nolint:gomnd
+	if len(input.Mean) > 0 {
+		parsedMean, err := types.BigInt(input.Mean)
+		if err != nil {
+			return nil, err
+		}
+
+		mean.SetInt(parsedMean)
+	}
+
+	deviation := new(big.Float).SetInt(width)
+	deviation.Quo(deviation, big.NewFloat(2*normalDeviations)) // nolint:gomnd
+	if len(input.StandardDeviation) > 0 {
+		parsedDeviation, err := types.BigInt(input.StandardDeviation)
+		if err != nil {
+			return nil, err
+		}
+
+		if parsedDeviation.Sign() < 0 {
+			return nil, errors.New("standard deviation cannot be negative")
+		}
+
+		deviation.SetInt(parsedDeviation)
+	}
+
+	r, err := newRand()
+	if err != nil {
+		return nil, err
+	}
+
+	var sample *big.Int
+	for i := 0; i < maxNormalSamples; i++ {
+		sampleFloat := new(big.Float).Mul(big.NewFloat(r.NormFloat64()), deviation)
+		sampleFloat.Add(sampleFloat, mean)
+		sample, _ = sampleFloat.Int(nil)
+
+		if sample.Cmp(min) >= 0 && sample.Cmp(max) < 0 {
+			return sample, nil
+		}
+	}
+
+	// If the mean is far outside of the range (or the standard
+	// deviation is very small), we clamp the last sample instead
+	// of sampling forever.
+	if sample.Cmp(min) < 0 {
+		return min, nil
+	}
+
+	return new(big.Int).Sub(max, big.NewInt(1)), nil
+}
+
+// zipfNumber draws a number in the range [min, max)
+// from a Zipf distribution offset by min.
+func zipfNumber(input *job.RandomNumberInput, min *big.Int, max *big.Int) (*big.Int, error) {
+	width := new(big.Int).Sub(max, min)
+	if width.Sign() <= 0 {
+		return nil, fmt.Errorf(
+			"maximum value %s <= minimum value %s",
+			max.String(),
+			min.String(),
+		)
+	}
+
+	// The largest value in the range is width - 1.
+	largest := new(big.Int).Sub(width, big.NewInt(1))
+	if !largest.IsUint64() {
+		return nil, fmt.Errorf("range %s is too large for a zipf distribution", width.String())
+	}
+
+	exponent := input.Exponent
+	if exponent == 0 {
+		exponent = DefaultZipfExponent
+	}
+
+	if exponent <= 1 {
+		return nil, fmt.Errorf("zipf exponent %f must be > 1", exponent)
+	}
+
+	r, err := newRand()
+	if err != nil {
+		return nil, err
+	}
+
+	zipf := rand.NewZipf(r, exponent, 1, largest.Uint64())
+	if zipf == nil {
+		return nil, fmt.Errorf("invalid zipf exponent %f", exponent)
+	}
+
+	return new(big.Int).Add(min, new(big.Int).SetUint64(zipf.Uint64())), nil
+}
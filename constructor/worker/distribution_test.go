@@ -0,0 +1,95 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestRandomNumberWorker(t *testing.T) {
+	tests := map[string]struct {
+		input string
+
+		minimum int64
+		maximum int64
+		err     error
+	}{
+		"uniform": {
+			input:   `{"minimum":"10","maximum":"20"}`,
+			minimum: 10,
+			maximum: 20,
+		},
+		"normal": {
+			input:   `{"minimum":"100","maximum":"200","distribution":"normal"}`,
+			minimum: 100,
+			maximum: 200,
+		},
+		"normal with mean outside of range": {
+			input:   `{"minimum":"100","maximum":"200","distribution":"normal","mean":"1000","standard_deviation":"1"}`, // nolint
+			minimum: 199,
+			maximum: 200,
+		},
+		"zipf": {
+			input:   `{"minimum":"5","maximum":"1000","distribution":"zipf","exponent":2}`,
+			minimum: 5,
+			maximum: 1000,
+		},
+		"zipf invalid exponent": {
+			input: `{"minimum":"5","maximum":"1000","distribution":"zipf","exponent":0.5}`,
+			err:   ErrActionFailed,
+		},
+		"zipf range too large": {
+			input: `{"minimum":"0","maximum":"100000000000000000000000","distribution":"zipf"}`,
+			err:   ErrActionFailed,
+		},
+		"empty range": {
+			input: `{"minimum":"10","maximum":"10","distribution":"normal"}`,
+			err:   ErrActionFailed,
+		},
+		"invalid distribution": {
+			input: `{"minimum":"10","maximum":"20","distribution":"poisson"}`,
+			err:   ErrActionFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Sample multiple times to catch out of range values.
+			for i := 0; i < 100; i++ {
+				output, err := RandomNumberWorker(test.input)
+				if test.err != nil {
+					assert.True(t, errors.Is(err, test.err))
+					assert.Equal(t, "", output)
+					return
+				}
+				assert.NoError(t, err)
+
+				var value string
+				assert.NoError(t, json.Unmarshal([]byte(output), &value))
+				number, err := types.BigInt(value)
+				assert.NoError(t, err)
+				assert.True(t, number.Cmp(big.NewInt(test.minimum)) >= 0)
+				assert.True(t, number.Cmp(big.NewInt(test.maximum)) < 0)
+			}
+		})
+	}
+}
@@ -48,6 +48,14 @@ var (
 	// ErrActionFailed is returned when Action exeuction fails with a valid input.
 	ErrActionFailed = errors.New("action execution failed")
 
+	// ErrLoopLimitExceeded is returned when a While does not
+	// complete within MaxLoopIterations.
+	ErrLoopLimitExceeded = errors.New("loop limit exceeded")
+
+	// ErrActionTimeout is returned when an Action does
+	// not complete within its timeout.
+	ErrActionTimeout = errors.New("action timed out")
+
 	// ErrCreateAccount is returned when a new account should
 	// be created using the `create_account` workflow.
 	ErrCreateAccount = errors.New("create account")
@@ -57,6 +65,15 @@ var (
 	// are no pending broadcasts, this usually means that we need
 	// to request funds.
 	ErrUnsatisfiable = errors.New("unsatisfiable balance")
+
+	// ErrHelperUnsupported is returned when an Action requires
+	// an optional extension of the Helper (ex: KeyHelper) that
+	// the Helper does not implement.
+	ErrHelperUnsupported = errors.New("helper does not support action")
+
+	// ErrPrefundedAccountInvalid is returned when a
+	// *job.PrefundedAccount is invalid.
+	ErrPrefundedAccountInvalid = errors.New("invalid prefunded account")
 )
 
 // Error is returned by worker execution.
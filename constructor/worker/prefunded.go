@@ -0,0 +1,306 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// prefundedNamespace prefixes the blob key where the value
+// spent from each *job.PrefundedAccount is stored.
+const prefundedNamespace = "prefunded_account"
+
+// PrefundedAccounts tracks the utilization of each registered
+// *job.PrefundedAccount so that depleted accounts (that have
+// reached their spend limit or fallen below their minimum balance)
+// are never returned by find_balance.
+//
+// The value spent from each account is persisted using
+// the Helper (SetBlob) so it survives restarts.
+type PrefundedAccounts struct {
+	accounts []*job.PrefundedAccount
+	lookup   map[string]*job.PrefundedAccount
+}
+
+// NewPrefundedAccounts validates accounts and
+// returns a new *PrefundedAccounts.
+func NewPrefundedAccounts(accounts []*job.PrefundedAccount) (*PrefundedAccounts, error) {
+	p := &PrefundedAccounts{
+		accounts: accounts,
+		lookup:   map[string]*job.PrefundedAccount{},
+	}
+
+	for _, account := range accounts {
+		if err := prefundedAccountValidation(account); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrPrefundedAccountInvalid, err.Error())
+		}
+
+		if account.MinimumBalance == "" {
+			account.MinimumBalance = "0"
+		}
+
+		key := prefundedKey(account.AccountIdentifier, account.Currency)
+		if _, ok := p.lookup[key]; ok {
+			return nil, fmt.Errorf(
+				"%w: duplicate account %s",
+				ErrPrefundedAccountInvalid,
+				types.PrintStruct(account.AccountIdentifier),
+			)
+		}
+
+		p.lookup[key] = account
+	}
+
+	return p, nil
+}
+
+func prefundedAccountValidation(account *job.PrefundedAccount) error {
+	if err := asserter.AccountIdentifier(account.AccountIdentifier); err != nil {
+		return err
+	}
+
+	if err := asserter.Currency(account.Currency); err != nil {
+		return err
+	}
+
+	for name, value := range map[string]string{
+		"spend limit":     account.SpendLimit,
+		"minimum balance": account.MinimumBalance,
+	} {
+		if value == "" {
+			continue
+		}
+
+		bigValue, err := types.BigInt(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s invalid", err, name)
+		}
+
+		if bigValue.Sign() < 0 {
+			return fmt.Errorf("%s %s is negative", name, value)
+		}
+	}
+
+	return nil
+}
+
+func prefundedKey(account *types.AccountIdentifier, currency *types.Currency) string {
+	return types.Hash(&types.AccountCurrency{
+		Account:  account,
+		Currency: currency,
+	})
+}
+
+func spentKey(account *job.PrefundedAccount) string {
+	return fmt.Sprintf(
+		"%s/%s",
+		prefundedNamespace,
+		prefundedKey(account.AccountIdentifier, account.Currency),
+	)
+}
+
+func (p *PrefundedAccounts) spent(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+	account *job.PrefundedAccount,
+) (*big.Int, error) {
+	exists, value, err := helper.GetBlob(ctx, dbTx, spentKey(account))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return big.NewInt(0), nil
+	}
+
+	return types.BigInt(string(value))
+}
+
+// Spend records the value sent from each *job.PrefundedAccount
+// in intent. It is a no-op if p is nil.
+func (p *PrefundedAccounts) Spend(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+	intent []*types.Operation,
+) error {
+	return p.updateSpent(ctx, dbTx, helper, intent, false)
+}
+
+// Release removes the value sent from each *job.PrefundedAccount
+// in intent from the recorded spend (ex: when the broadcast of
+// intent fails). It is a no-op if p is nil.
+func (p *PrefundedAccounts) Release(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+	intent []*types.Operation,
+) error {
+	return p.updateSpent(ctx, dbTx, helper, intent, true)
+}
+
+// updateSpent adds (or removes, if release is true) the
+// value sent from each *job.PrefundedAccount in intent
+// to its recorded spend.
+func (p *PrefundedAccounts) updateSpent(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+	intent []*types.Operation,
+	release bool,
+) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, op := range intent {
+		if op.Account == nil || op.Amount == nil {
+			continue
+		}
+
+		account, ok := p.lookup[prefundedKey(op.Account, op.Amount.Currency)]
+		if !ok {
+			continue
+		}
+
+		value, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+		}
+
+		if value.Sign() >= 0 {
+			continue
+		}
+
+		spent, err := p.spent(ctx, dbTx, helper, account)
+		if err != nil {
+			return fmt.Errorf("%w: unable to get spent value %s", ErrActionFailed, err.Error())
+		}
+
+		if release {
+			spent.Add(spent, value)
+			if spent.Sign() < 0 {
+				spent.SetInt64(0)
+			}
+		} else {
+			spent.Sub(spent, value)
+		}
+
+		if err := helper.SetBlob(ctx, dbTx, spentKey(account), []byte(spent.String())); err != nil {
+			return fmt.Errorf("%w: unable to store spent value %s", ErrActionFailed, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (p *PrefundedAccounts) status(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+	account *job.PrefundedAccount,
+) (*job.PrefundedAccountStatus, error) {
+	spent, err := p.spent(ctx, dbTx, helper, account)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get spent value %s", ErrActionFailed, err.Error())
+	}
+
+	balance, err := helper.Balance(ctx, dbTx, account.AccountIdentifier, account.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get balance %s", ErrActionFailed, err.Error())
+	}
+
+	balanceValue, err := types.AmountValue(balance)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	// MinimumBalance is always populated and
+	// validated in NewPrefundedAccounts.
+	minimumBalance, _ := types.BigInt(account.MinimumBalance)
+	status := &job.PrefundedAccountStatus{
+		Account:     account,
+		Balance:     balance,
+		Spent:       spent.String(),
+		Underfunded: balanceValue.Cmp(minimumBalance) < 0,
+	}
+
+	if account.SpendLimit != "" {
+		spendLimit, _ := types.BigInt(account.SpendLimit)
+		status.LimitReached = spent.Cmp(spendLimit) >= 0
+	}
+
+	return status, nil
+}
+
+// Statuses returns the *job.PrefundedAccountStatus
+// of each registered *job.PrefundedAccount. It returns
+// nil if p is nil.
+func (p *PrefundedAccounts) Statuses(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+) ([]*job.PrefundedAccountStatus, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	statuses := make([]*job.PrefundedAccountStatus, len(p.accounts))
+	for i, account := range p.accounts {
+		status, err := p.status(ctx, dbTx, helper, account)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[i] = status
+	}
+
+	return statuses, nil
+}
+
+// Depleted returns a boolean indicating if account is a
+// depleted *job.PrefundedAccount in currency. Accounts
+// that are not registered are never depleted.
+func (p *PrefundedAccounts) Depleted(
+	ctx context.Context,
+	dbTx database.Transaction,
+	helper Helper,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) (bool, error) {
+	if p == nil {
+		return false, nil
+	}
+
+	prefunded, ok := p.lookup[prefundedKey(account, currency)]
+	if !ok {
+		return false, nil
+	}
+
+	status, err := p.status(ctx, dbTx, helper, prefunded)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Depleted(), nil
+}
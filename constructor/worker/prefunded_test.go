@@ -0,0 +1,288 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/constructor/worker"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	prefundedCurrency = &types.Currency{
+		Symbol:   "BTC",
+		Decimals: 8,
+	}
+	prefundedAccount = &types.AccountIdentifier{
+		Address: "faucet",
+	}
+)
+
+func TestNewPrefundedAccounts(t *testing.T) {
+	tests := map[string]struct {
+		accounts []*job.PrefundedAccount
+
+		expectedErr error
+	}{
+		"valid accounts": {
+			accounts: []*job.PrefundedAccount{
+				{
+					AccountIdentifier: prefundedAccount,
+					Currency:          prefundedCurrency,
+					SpendLimit:        "100",
+					MinimumBalance:    "10",
+				},
+				{
+					AccountIdentifier: &types.AccountIdentifier{Address: "other"},
+					Currency:          prefundedCurrency,
+				},
+			},
+		},
+		"invalid account": {
+			accounts: []*job.PrefundedAccount{
+				{
+					AccountIdentifier: &types.AccountIdentifier{},
+					Currency:          prefundedCurrency,
+				},
+			},
+			expectedErr: ErrPrefundedAccountInvalid,
+		},
+		"invalid currency": {
+			accounts: []*job.PrefundedAccount{
+				{
+					AccountIdentifier: prefundedAccount,
+				},
+			},
+			expectedErr: ErrPrefundedAccountInvalid,
+		},
+		"invalid spend limit": {
+			accounts: []*job.PrefundedAccount{
+				{
+					AccountIdentifier: prefundedAccount,
+					Currency:          prefundedCurrency,
+					SpendLimit:        "hello",
+				},
+			},
+			expectedErr: ErrPrefundedAccountInvalid,
+		},
+		"negative minimum balance": {
+			accounts: []*job.PrefundedAccount{
+				{
+					AccountIdentifier: prefundedAccount,
+					Currency:          prefundedCurrency,
+					MinimumBalance:    "-1",
+				},
+			},
+			expectedErr: ErrPrefundedAccountInvalid,
+		},
+		"duplicate account": {
+			accounts: []*job.PrefundedAccount{
+				{
+					AccountIdentifier: prefundedAccount,
+					Currency:          prefundedCurrency,
+				},
+				{
+					AccountIdentifier: prefundedAccount,
+					Currency:          prefundedCurrency,
+					SpendLimit:        "10",
+				},
+			},
+			expectedErr: ErrPrefundedAccountInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prefunded, err := NewPrefundedAccounts(test.accounts)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				assert.Nil(t, prefunded)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, prefunded)
+			}
+		})
+	}
+}
+
+func TestPrefundedAccounts(t *testing.T) {
+	ctx := context.Background()
+	account := &job.PrefundedAccount{
+		AccountIdentifier: prefundedAccount,
+		Currency:          prefundedCurrency,
+		SpendLimit:        "100",
+		MinimumBalance:    "10",
+	}
+	key := spentKey(account)
+	prefunded, err := NewPrefundedAccounts([]*job.PrefundedAccount{account})
+	assert.NoError(t, err)
+
+	balance := func(value string) *types.Amount {
+		return &types.Amount{
+			Value:    value,
+			Currency: prefundedCurrency,
+		}
+	}
+	intent := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Account:             prefundedAccount,
+			Amount:              balance("-60"),
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 1},
+			Account:             &types.AccountIdentifier{Address: "recipient"},
+			Amount:              balance("60"),
+		},
+	}
+
+	t.Run("spend", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(false, nil, nil).Once()
+		helper.On("SetBlob", ctx, mock.Anything, key, []byte("60")).Return(nil).Once()
+		assert.NoError(t, prefunded.Spend(ctx, nil, helper, intent))
+
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("60"), nil).Once()
+		helper.On("SetBlob", ctx, mock.Anything, key, []byte("120")).Return(nil).Once()
+		assert.NoError(t, prefunded.Spend(ctx, nil, helper, intent))
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("120"), nil).Once()
+		helper.On("SetBlob", ctx, mock.Anything, key, []byte("60")).Return(nil).Once()
+		assert.NoError(t, prefunded.Release(ctx, nil, helper, intent))
+
+		// The spend is never negative.
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("20"), nil).Once()
+		helper.On("SetBlob", ctx, mock.Anything, key, []byte("0")).Return(nil).Once()
+		assert.NoError(t, prefunded.Release(ctx, nil, helper, intent))
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("statuses", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("60"), nil).Once()
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			prefundedAccount,
+			prefundedCurrency,
+		).Return(balance("5"), nil).Once()
+
+		statuses, err := prefunded.Statuses(ctx, nil, helper)
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.PrefundedAccountStatus{
+			{
+				Account:     account,
+				Balance:     balance("5"),
+				Spent:       "60",
+				Underfunded: true,
+			},
+		}, statuses)
+		assert.True(t, statuses[0].Depleted())
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("depleted", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("60"), nil).Once()
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			prefundedAccount,
+			prefundedCurrency,
+		).Return(balance("50"), nil).Once()
+		depleted, err := prefunded.Depleted(ctx, nil, helper, prefundedAccount, prefundedCurrency)
+		assert.NoError(t, err)
+		assert.False(t, depleted)
+
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("100"), nil).Once()
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			prefundedAccount,
+			prefundedCurrency,
+		).Return(balance("50"), nil).Once()
+		depleted, err = prefunded.Depleted(ctx, nil, helper, prefundedAccount, prefundedCurrency)
+		assert.NoError(t, err)
+		assert.True(t, depleted)
+
+		// Accounts that are not registered are never depleted.
+		depleted, err = prefunded.Depleted(
+			ctx,
+			nil,
+			helper,
+			&types.AccountIdentifier{Address: "recipient"},
+			prefundedCurrency,
+		)
+		assert.NoError(t, err)
+		assert.False(t, depleted)
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("find balance skips depleted", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper, WithPrefundedAccounts(prefunded))
+		recipient := &types.AccountIdentifier{Address: "recipient"}
+		helper.On(
+			"AllAccounts",
+			ctx,
+			mock.Anything,
+		).Return([]*types.AccountIdentifier{prefundedAccount, recipient}, nil).Once()
+		helper.On("LockedAccounts", ctx, mock.Anything).Return([]*types.AccountIdentifier{}, nil).Once()
+		helper.On("GetBlob", ctx, mock.Anything, key).Return(true, []byte("100"), nil).Once()
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			prefundedAccount,
+			prefundedCurrency,
+		).Return(balance("50"), nil).Once()
+		helper.On(
+			"Balance",
+			ctx,
+			mock.Anything,
+			recipient,
+			prefundedCurrency,
+		).Return(balance("20"), nil).Once()
+
+		output, err := worker.FindBalanceWorker(ctx, nil, types.PrintStruct(&job.FindBalanceInput{
+			MinimumBalance: balance("10"),
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, types.PrintStruct(&job.FindBalanceOutput{
+			AccountIdentifier: recipient,
+			Balance:           balance("20"),
+		}), output)
+
+		helper.AssertExpectations(t)
+	})
+}
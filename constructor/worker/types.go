@@ -17,6 +17,7 @@ package worker
 import (
 	"context"
 
+	"github.com/coinbase/rosetta-sdk-go/constructor/job"
 	"github.com/coinbase/rosetta-sdk-go/keys"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -89,7 +90,92 @@ type Helper interface {
 	) (bool, []byte, error)
 }
 
+// KeyHelper is an optional extension of the Helper that
+// retrieves stored keys. The Helper must implement KeyHelper
+// to use sign_payload.
+type KeyHelper interface {
+	// GetKey is called to get the *types.KeyPair
+	// associated with an address.
+	GetKey(
+		context.Context,
+		database.Transaction,
+		*types.AccountIdentifier,
+	) (*keys.KeyPair, error)
+}
+
+// DataHelper is an optional extension of the Helper that
+// queries the Data API directly (usually with a fetcher). It
+// is required by assert_balance and assert_coins so that they
+// observe the live state of an account (instead of the synced
+// state returned by Balance and Coins).
+type DataHelper interface {
+	// AccountBalance returns the balance of an account
+	// in currency on network (the default network if nil).
+	AccountBalance(
+		context.Context,
+		*types.NetworkIdentifier,
+		*types.AccountIdentifier,
+		*types.Currency,
+	) (*types.Amount, error)
+
+	// AccountCoins returns all coins in currency owned by
+	// an account on network (the default network if nil).
+	AccountCoins(
+		context.Context,
+		*types.NetworkIdentifier,
+		*types.AccountIdentifier,
+		*types.Currency,
+	) ([]*types.Coin, error)
+}
+
+// NetworkResolver returns the Helper to use for actions
+// that target a particular *types.NetworkIdentifier (ex:
+// derive or find_balance with a network). If nil is
+// returned, the default Helper is used.
+type NetworkResolver func(*types.NetworkIdentifier) Helper
+
+// BalanceObserver is invoked whenever find_balance finds
+// a balance (with the *job.FindBalanceOutput) or cannot find
+// a sufficient balance (with a nil *job.FindBalanceOutput).
+type BalanceObserver func(
+	context.Context,
+	*job.FindBalanceInput,
+	*job.FindBalanceOutput,
+)
+
 // Worker processes jobs.
 type Worker struct {
-	helper Helper
+	helper          Helper
+	resolver        NetworkResolver
+	prefunded       *PrefundedAccounts
+	balanceObserver BalanceObserver
+}
+
+// Option is used to overwrite default values in
+// Worker construction.
+type Option func(w *Worker)
+
+// WithNetworkResolver resolves the Helper for
+// each action that targets a network with resolver.
+func WithNetworkResolver(resolver NetworkResolver) Option {
+	return func(w *Worker) {
+		w.resolver = resolver
+	}
+}
+
+// WithPrefundedAccounts excludes any depleted
+// *job.PrefundedAccount in prefunded from
+// find_balance results.
+func WithPrefundedAccounts(prefunded *PrefundedAccounts) Option {
+	return func(w *Worker) {
+		w.prefunded = prefunded
+	}
+}
+
+// WithBalanceObserver invokes observer with
+// the result of each find_balance.
+func WithBalanceObserver(observer BalanceObserver) Option {
+	return func(w *Worker) {
+		w.balanceObserver = observer
+	}
 }
@@ -17,17 +17,27 @@ package worker
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lucasjones/reggen"
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
+	"golang.org/x/crypto/sha3"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
@@ -37,9 +47,34 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
+const (
+	// MaxLoopIterations is the maximum number of iterations
+	// a While can perform before execution fails. This prevents
+	// a misconfigured condition from stalling a Job forever.
+	MaxLoopIterations = 1000
+)
+
 // New returns a new *Worker.
-func New(helper Helper) *Worker {
-	return &Worker{helper: helper}
+func New(helper Helper, options ...Option) *Worker {
+	w := &Worker{helper: helper}
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
+}
+
+// helperFor returns the Helper to use for network.
+func (w *Worker) helperFor(network *types.NetworkIdentifier) Helper {
+	if network == nil || w.resolver == nil {
+		return w.helper
+	}
+
+	if helper := w.resolver(network); helper != nil {
+		return helper
+	}
+
+	return w.helper
 }
 
 func marshalString(value string) string {
@@ -68,34 +103,174 @@ func (w *Worker) invokeWorker(
 		return RandomStringWorker(input)
 	case job.Math:
 		return MathWorker(input)
+	case job.Compare:
+		return CompareWorker(input)
 	case job.FindBalance:
-		return w.FindBalanceWorker(ctx, dbTx, input)
+		return w.findBalance(ctx, dbTx, input)
 	case job.RandomNumber:
 		return RandomNumberWorker(input)
+	case job.RandomBytes:
+		return RandomBytesWorker(input)
+	case job.Timestamp:
+		return TimestampWorker(input)
+	case job.Hash:
+		return HashWorker(input)
 	case job.Assert:
 		return "", AssertWorker(input)
+	case job.AssertBalance:
+		return w.AssertBalanceWorker(ctx, dbTx, input)
+	case job.AssertCoins:
+		return w.AssertCoinsWorker(ctx, dbTx, input)
 	case job.FindCurrencyAmount:
 		return FindCurrencyAmountWorker(input)
 	case job.LoadEnv:
 		return LoadEnvWorker(input)
 	case job.HTTPRequest:
-		return HTTPRequestWorker(input)
+		return HTTPRequestWorker(ctx, input)
 	case job.SetBlob:
 		return "", w.SetBlobWorker(ctx, dbTx, input)
 	case job.GetBlob:
 		return w.GetBlobWorker(ctx, dbTx, input)
+	case job.SignPayload:
+		return w.SignPayloadWorker(ctx, dbTx, input)
 	default:
 		return "", fmt.Errorf("%w: %s", ErrInvalidActionType, action)
 	}
 }
 
+// invokeAction invokes an Action with its
+// timeout and retry policy.
+func (w *Worker) invokeAction(
+	ctx context.Context,
+	dbTx database.Transaction,
+	action *job.Action,
+	input string,
+) (string, error) {
+	for attempt := 0; ; attempt++ {
+		output, err := w.invokeActionWithTimeout(ctx, dbTx, action, input)
+		if err == nil {
+			return output, nil
+		}
+
+		// ErrCreateAccount and ErrUnsatisfiable are not
+		// failures that can be resolved by retrying.
+		if attempt >= action.Retries ||
+			errors.Is(err, ErrCreateAccount) ||
+			errors.Is(err, ErrUnsatisfiable) {
+			return "", err
+		}
+
+		log.Printf(
+			"retrying %s (attempt %d of %d): %s\n",
+			action.Type,
+			attempt+1,
+			action.Retries,
+			err.Error(),
+		)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Duration(action.RetryDelay) * time.Second):
+		}
+	}
+}
+
+// invokeActionWithTimeout invokes an Action and returns
+// ErrActionTimeout if it fails because it did not complete
+// within its timeout. The output of an Action that succeeds
+// is always returned (even if the timeout elapsed), so that
+// its writes to dbTx are not repeated by a retry.
+//
+// The Action is invoked with a context.Context that is
+// canceled when the timeout elapses and is always waited
+// for (instead of being abandoned in a goroutine), so that
+// dbTx is never used after invokeActionWithTimeout returns.
+// Actions that call the Helper (ex: find_balance) return as
+// soon as the Helper observes the cancellation.
+func (w *Worker) invokeActionWithTimeout(
+	ctx context.Context,
+	dbTx database.Transaction,
+	action *job.Action,
+	input string,
+) (string, error) {
+	if action.Timeout <= 0 {
+		return w.invokeWorker(ctx, dbTx, action.Type, input)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(action.Timeout)*time.Second)
+	defer cancel()
+
+	output, err := w.invokeWorker(timeoutCtx, dbTx, action.Type, input)
+	if err != nil && ctx.Err() == nil &&
+		errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf(
+			"%w: %s did not complete within %d seconds",
+			ErrActionTimeout,
+			action.Type,
+			action.Timeout,
+		)
+	}
+
+	return output, err
+}
+
+// compensate invokes compensations in reverse order. Any
+// error is logged (instead of returned) so that it does not
+// mask the failure that triggered compensation.
+func (w *Worker) compensate(
+	ctx context.Context,
+	dbTx database.Transaction,
+	state string,
+	compensations []*job.Action,
+) {
+	for i := len(compensations) - 1; i >= 0; i-- {
+		compensation := compensations[i]
+		processedInput, err := PopulateInput(state, compensation.Input)
+		if err == nil {
+			_, err = w.invokeAction(ctx, dbTx, compensation, processedInput)
+		}
+
+		if err != nil {
+			log.Printf(
+				"compensation %s failed: %s\n",
+				types.PrintStruct(compensation),
+				err.Error(),
+			)
+		}
+	}
+}
+
+// Compensate invokes the compensations of all Actions
+// completed by a Job (ex: after its broadcast failed)
+// and clears them.
+func (w *Worker) Compensate(
+	ctx context.Context,
+	dbTx database.Transaction,
+	j *job.Job,
+) {
+	w.compensate(ctx, dbTx, j.State, j.Compensations)
+	j.Compensations = nil
+}
+
 func (w *Worker) actions(
 	ctx context.Context,
 	dbTx database.Transaction,
 	state string,
 	actions []*job.Action,
+	compensations *[]*job.Action,
 ) (string, *Error) {
 	for i, action := range actions {
+		if action.Type == job.If || action.Type == job.While {
+			var err *Error
+			state, err = w.controlFlow(ctx, dbTx, state, i, action, compensations)
+			if err != nil {
+				return "", err
+			}
+
+			continue
+		}
+
 		processedInput, err := PopulateInput(state, action.Input)
 		if err != nil {
 			return "", &Error{
@@ -106,7 +281,7 @@ func (w *Worker) actions(
 			}
 		}
 
-		output, err := w.invokeWorker(ctx, dbTx, action.Type, processedInput)
+		output, err := w.invokeAction(ctx, dbTx, action, processedInput)
 		if err != nil {
 			return "", &Error{
 				ActionIndex:    i,
@@ -117,6 +292,10 @@ func (w *Worker) actions(
 			}
 		}
 
+		if action.Compensation != nil {
+			*compensations = append(*compensations, action.Compensation)
+		}
+
 		if len(output) == 0 {
 			continue
 		}
@@ -139,6 +318,93 @@ func (w *Worker) actions(
 	return state, nil
 }
 
+// condition populates and evaluates the condition
+// of an If or While.
+func (w *Worker) condition(state string, index int, action *job.Action) (bool, *Error) {
+	processedInput, err := PopulateInput(state, action.Input)
+	if err != nil {
+		return false, &Error{
+			ActionIndex: index,
+			Action:      action,
+			State:       state,
+			Err:         fmt.Errorf("%w: unable to populate variables", err),
+		}
+	}
+
+	met, err := ConditionWorker(processedInput)
+	if err != nil {
+		return false, &Error{
+			ActionIndex:    index,
+			Action:         action,
+			ProcessedInput: processedInput,
+			State:          state,
+			Err:            fmt.Errorf("%w: unable to evaluate condition", err),
+		}
+	}
+
+	return met, nil
+}
+
+// controlFlow performs the nested actions of an If
+// or While and returns the resulting state.
+func (w *Worker) controlFlow(
+	ctx context.Context,
+	dbTx database.Transaction,
+	state string,
+	index int,
+	action *job.Action,
+	compensations *[]*job.Action,
+) (string, *Error) {
+	if action.Type == job.If {
+		met, err := w.condition(state, index, action)
+		if err != nil {
+			return "", err
+		}
+
+		if met {
+			return w.actions(ctx, dbTx, state, action.Actions, compensations)
+		}
+
+		return w.actions(ctx, dbTx, state, action.Else, compensations)
+	}
+
+	for i := 0; ctx.Err() == nil; i++ {
+		met, err := w.condition(state, index, action)
+		if err != nil {
+			return "", err
+		}
+
+		if !met {
+			return state, nil
+		}
+
+		if i == MaxLoopIterations {
+			return "", &Error{
+				ActionIndex: index,
+				Action:      action,
+				State:       state,
+				Err: fmt.Errorf(
+					"%w: condition still true after %d iterations",
+					ErrLoopLimitExceeded,
+					MaxLoopIterations,
+				),
+			}
+		}
+
+		state, err = w.actions(ctx, dbTx, state, action.Actions, compensations)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", &Error{
+		ActionIndex: index,
+		Action:      action,
+		State:       state,
+		Err:         ctx.Err(),
+	}
+}
+
 // ProcessNextScenario performs the actions in the next available
 // scenario.
 func (w *Worker) ProcessNextScenario(
@@ -147,7 +413,8 @@ func (w *Worker) ProcessNextScenario(
 	j *job.Job,
 ) *Error {
 	scenario := j.Scenarios[j.Index]
-	newState, err := w.actions(ctx, dbTx, j.State, scenario.Actions)
+	compensations := []*job.Action{}
+	newState, err := w.actions(ctx, dbTx, j.State, scenario.Actions, &compensations)
 	if err != nil {
 		// Set additional context not available within actions.
 		err.Workflow = j.Workflow
@@ -155,11 +422,20 @@ func (w *Worker) ProcessNextScenario(
 		err.Scenario = scenario.Name
 		err.ScenarioIndex = j.Index
 
+		// If the scenario will be retried, we only compensate the
+		// actions completed in this scenario (the Job has not failed).
+		if errors.Is(err.Err, ErrCreateAccount) || errors.Is(err.Err, ErrUnsatisfiable) {
+			w.compensate(ctx, dbTx, err.State, compensations)
+		} else {
+			w.compensate(ctx, dbTx, err.State, append(j.Compensations, compensations...))
+		}
+
 		return err
 	}
 
 	j.State = newState
 	j.Index++
+	j.Compensations = append(j.Compensations, compensations...)
 	return nil
 }
 
@@ -192,6 +468,12 @@ func (w *Worker) Process(
 		}
 	}
 
+	// Compensations are no longer needed once
+	// a Job completes.
+	if broadcast == nil && j.CheckComplete() {
+		j.Compensations = nil
+	}
+
 	return broadcast, nil
 }
 
@@ -211,7 +493,7 @@ func (w *Worker) DeriveWorker(
 		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
 	}
 
-	accountIdentifier, metadata, err := w.helper.Derive(
+	accountIdentifier, metadata, err := w.helperFor(input.NetworkIdentifier).Derive(
 		ctx,
 		input.NetworkIdentifier,
 		input.PublicKey,
@@ -308,6 +590,8 @@ func MathWorker(rawInput string) (string, error) {
 		result, err = types.MultiplyValues(input.LeftValue, input.RightValue)
 	case job.Division:
 		result, err = types.DivideValues(input.LeftValue, input.RightValue)
+	case job.Modulo:
+		result, err = moduloValues(input.LeftValue, input.RightValue)
 	default:
 		return "", fmt.Errorf("%s is not a supported math operation", input.Operation)
 	}
@@ -318,8 +602,105 @@ func MathWorker(rawInput string) (string, error) {
 	return marshalString(result), nil
 }
 
+func moduloValues(a string, b string) (string, error) {
+	aVal, err := types.BigInt(a)
+	if err != nil {
+		return "", err
+	}
+
+	bVal, err := types.BigInt(b)
+	if err != nil {
+		return "", err
+	}
+
+	if bVal.Sign() == 0 {
+		return "", errors.New("modulo by zero")
+	}
+
+	return new(big.Int).Rem(aVal, bVal).String(), nil
+}
+
+// CompareWorker performs some ComparisonOperation on 2 numbers
+// and returns a boolean.
+func CompareWorker(rawInput string) (string, error) {
+	var input job.CompareInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	left, err := types.BigInt(input.LeftValue)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	right, err := types.BigInt(input.RightValue)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	cmp := left.Cmp(right)
+	var result bool
+	switch input.Operation {
+	case job.Equal:
+		result = cmp == 0
+	case job.NotEqual:
+		result = cmp != 0
+	case job.GreaterThan:
+		result = cmp > 0
+	case job.GreaterOrEqual:
+		result = cmp >= 0
+	case job.LessThan:
+		result = cmp < 0
+	case job.LessOrEqual:
+		result = cmp <= 0
+	default:
+		return "", fmt.Errorf(
+			"%w: %s is not a supported comparison operation",
+			ErrInvalidInput,
+			input.Operation,
+		)
+	}
+
+	return strconv.FormatBool(result), nil
+}
+
+// ConditionWorker evaluates the condition of an If or While. The
+// condition can be a boolean (or a string containing a boolean) or
+// a *job.CompareInput.
+func ConditionWorker(rawInput string) (bool, error) {
+	trimmed := strings.TrimSpace(rawInput)
+	if strings.HasPrefix(trimmed, "{") {
+		result, err := CompareWorker(trimmed)
+		if err != nil {
+			return false, err
+		}
+
+		rawInput = result
+	}
+
+	var input interface{}
+	if err := json.Unmarshal([]byte(rawInput), &input); err != nil {
+		return false, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	switch v := input.(type) {
+	case bool:
+		return v, nil
+	case string:
+		result, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		}
+
+		return result, nil
+	default:
+		return false, fmt.Errorf("%w: %s is not a boolean", ErrInvalidInput, rawInput)
+	}
+}
+
 // RandomNumberWorker generates a random number in the range
-// [minimum,maximum).
+// [minimum,maximum) drawn from the provided distribution.
 func RandomNumberWorker(rawInput string) (string, error) {
 	var input job.RandomNumberInput
 	err := job.UnmarshalInput([]byte(rawInput), &input)
@@ -337,7 +718,7 @@ func RandomNumberWorker(rawInput string) (string, error) {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
 
-	randNum, err := utils.RandomNumber(min, max)
+	randNum, err := randomNumber(&input, min, max)
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
@@ -345,6 +726,120 @@ func RandomNumberWorker(rawInput string) (string, error) {
 	return marshalString(randNum.String()), nil
 }
 
+// RandomBytesWorker generates a random byte slice
+// of the provided length.
+func RandomBytesWorker(rawInput string) (string, error) {
+	var input job.RandomBytesInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if input.Length <= 0 {
+		return "", fmt.Errorf("%w: length %d must be positive", ErrInvalidInput, input.Length)
+	}
+
+	randBytes := make([]byte, input.Length)
+	if _, err := rand.Read(randBytes); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	switch input.Encoding {
+	case "", job.HexEncoding:
+		return marshalString(hex.EncodeToString(randBytes)), nil
+	case job.Base64Encoding:
+		return marshalString(base64.StdEncoding.EncodeToString(randBytes)), nil
+	default:
+		return "", fmt.Errorf(
+			"%w: %s is not a supported encoding",
+			ErrInvalidInput,
+			input.Encoding,
+		)
+	}
+}
+
+// TimestampWorker returns the current time
+// (plus the provided offset) in the provided unit.
+func TimestampWorker(rawInput string) (string, error) {
+	// The input is optional.
+	var input job.TimestampInput
+	if len(strings.TrimSpace(rawInput)) > 0 {
+		err := job.UnmarshalInput([]byte(rawInput), &input)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		}
+	}
+
+	now := time.Now().Add(time.Duration(input.OffsetSeconds) * time.Second)
+	switch input.Unit {
+	case "", job.Seconds:
+		return marshalString(strconv.FormatInt(now.Unix(), 10)), nil
+	case job.Milliseconds:
+		return marshalString(strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)), nil
+	case job.RFC3339:
+		return marshalString(now.UTC().Format(time.RFC3339)), nil
+	default:
+		return "", fmt.Errorf("%w: %s is not a supported unit", ErrInvalidInput, input.Unit)
+	}
+}
+
+// HashWorker returns the hex-encoded hash of
+// the provided data.
+func HashWorker(rawInput string) (string, error) {
+	var input job.HashInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if len(input.Data) == 0 {
+		return "", fmt.Errorf("%w: data is missing", ErrInvalidInput)
+	}
+
+	data := []byte(input.Data)
+	var dataString string
+	if err := json.Unmarshal(input.Data, &dataString); err == nil {
+		data, err = decodeString(dataString, input.Encoding)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+		}
+	}
+
+	var digest []byte
+	switch input.Function {
+	case job.Sha256:
+		hash := sha256.Sum256(data)
+		digest = hash[:]
+	case job.Keccak256:
+		hash := sha3.NewLegacyKeccak256()
+		hash.Write(data) // nolint:errcheck
+		digest = hash.Sum(nil)
+	default:
+		return "", fmt.Errorf(
+			"%w: %s is not a supported hash function",
+			ErrInvalidInput,
+			input.Function,
+		)
+	}
+
+	return marshalString(hex.EncodeToString(digest)), nil
+}
+
+// decodeString returns the bytes represented by
+// value in the provided encoding.
+func decodeString(value string, encoding job.Encoding) ([]byte, error) {
+	switch encoding {
+	case "", job.UTF8Encoding:
+		return []byte(value), nil
+	case job.HexEncoding:
+		return hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	case job.Base64Encoding:
+		return base64.StdEncoding.DecodeString(value)
+	default:
+		return nil, fmt.Errorf("%s is not a supported encoding", encoding)
+	}
+}
+
 // balanceMessage prints out a log message while waiting
 // that reflects the *FindBalanceInput.
 func balanceMessage(input *job.FindBalanceInput) string {
@@ -399,20 +894,33 @@ func balanceMessage(input *job.FindBalanceInput) string {
 		)
 	}
 
+	if len(input.CoinSelection) > 0 {
+		message = fmt.Sprintf(
+			"%s using %s coin selection",
+			message,
+			input.CoinSelection,
+		)
+	}
+
 	return message
 }
 
 func (w *Worker) checkAccountCoins(
 	ctx context.Context,
 	dbTx database.Transaction,
+	helper Helper,
 	input *job.FindBalanceInput,
 	account *types.AccountIdentifier,
 ) (string, error) {
-	coins, err := w.helper.Coins(ctx, dbTx, account, input.MinimumBalance.Currency)
+	coins, err := helper.Coins(ctx, dbTx, account, input.MinimumBalance.Currency)
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
 
+	if len(input.CoinSelection) > 0 {
+		return selectedCoinsOutput(input, account, coins)
+	}
+
 	disallowedCoins := []string{}
 	for _, coinIdentifier := range input.NotCoins {
 		disallowedCoins = append(disallowedCoins, types.Hash(coinIdentifier))
@@ -447,13 +955,53 @@ func (w *Worker) checkAccountCoins(
 	return "", nil
 }
 
+// selectedCoinsOutput returns the *job.FindBalanceOutput
+// of the coins selected by the CoinSelection of input (or
+// an empty string if no coins are selected).
+func selectedCoinsOutput(
+	input *job.FindBalanceInput,
+	account *types.AccountIdentifier,
+	coins []*types.Coin,
+) (string, error) {
+	selected, err := selectCoins(input, coins)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	if selected == nil {
+		return "", nil
+	}
+
+	balance := big.NewInt(0)
+	for _, coin := range selected {
+		// All coin values were parsed in selectCoins.
+		value, _ := types.AmountValue(coin.Amount)
+		balance.Add(balance, value)
+	}
+
+	output := &job.FindBalanceOutput{
+		AccountIdentifier: account,
+		Balance: &types.Amount{
+			Value:    balance.String(),
+			Currency: input.MinimumBalance.Currency,
+		},
+		Coins: selected,
+	}
+	if len(selected) == 1 {
+		output.Coin = selected[0].CoinIdentifier
+	}
+
+	return types.PrintStruct(output), nil
+}
+
 func (w *Worker) checkAccountBalance(
 	ctx context.Context,
 	dbTx database.Transaction,
+	helper Helper,
 	input *job.FindBalanceInput,
 	account *types.AccountIdentifier,
 ) (string, error) {
-	amount, err := w.helper.Balance(ctx, dbTx, account, input.MinimumBalance.Currency)
+	amount, err := helper.Balance(ctx, dbTx, account, input.MinimumBalance.Currency)
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 	}
@@ -488,8 +1036,9 @@ func (w *Worker) checkAccountBalance(
 func (w *Worker) availableAccounts(
 	ctx context.Context,
 	dbTx database.Transaction,
+	helper Helper,
 ) ([]*types.AccountIdentifier, []*types.AccountIdentifier, error) {
-	accounts, err := w.helper.AllAccounts(ctx, dbTx)
+	accounts, err := helper.AllAccounts(ctx, dbTx)
 	if err != nil {
 		return nil, nil, fmt.Errorf(
 			"%w: unable to get all accounts %s",
@@ -506,7 +1055,7 @@ func (w *Worker) availableAccounts(
 	// We fetch all locked accounts to subtract them from AllAccounts.
 	// We consider an account "locked" if it is actively involved in a broadcast.
 	unlockedAccounts := []*types.AccountIdentifier{}
-	lockedAccounts, err := w.helper.LockedAccounts(ctx, dbTx)
+	lockedAccounts, err := helper.LockedAccounts(ctx, dbTx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: unable to get locked accounts %s", ErrActionFailed, err)
 	}
@@ -584,7 +1133,7 @@ func findBalanceWorkerInputValidation(input *job.FindBalanceInput) error {
 		}
 	}
 
-	return nil
+	return coinSelectionValidation(input)
 }
 
 func skipAccount(input job.FindBalanceInput, account *types.AccountIdentifier) bool {
@@ -640,7 +1189,8 @@ func (w *Worker) FindBalanceWorker(
 
 	log.Println(balanceMessage(&input))
 
-	accounts, availableAccounts, err := w.availableAccounts(ctx, dbTx)
+	helper := w.helperFor(input.Network)
+	accounts, availableAccounts, err := w.availableAccounts(ctx, dbTx, helper)
 	if err != nil {
 		return "", fmt.Errorf("%w: unable to get available accounts", err)
 	}
@@ -663,12 +1213,27 @@ func (w *Worker) FindBalanceWorker(
 			continue
 		}
 
+		depleted, err := w.prefunded.Depleted(
+			ctx,
+			dbTx,
+			helper,
+			account,
+			input.MinimumBalance.Currency,
+		)
+		if err != nil {
+			return "", fmt.Errorf("%w: unable to check if prefunded account is depleted", err)
+		}
+
+		if depleted {
+			log.Printf("skipping depleted prefunded account %s", account.Address)
+			continue
+		}
+
 		var output string
-		var err error
 		if input.RequireCoin {
-			output, err = w.checkAccountCoins(ctx, dbTx, &input, account)
+			output, err = w.checkAccountCoins(ctx, dbTx, helper, &input, account)
 		} else {
-			output, err = w.checkAccountBalance(ctx, dbTx, &input, account)
+			output, err = w.checkAccountBalance(ctx, dbTx, helper, &input, account)
 		}
 		if err != nil {
 			return "", err
@@ -706,6 +1271,34 @@ func (w *Worker) FindBalanceWorker(
 	return "", ErrUnsatisfiable
 }
 
+// findBalance invokes FindBalanceWorker and
+// notifies the BalanceObserver (if any) of the result.
+func (w *Worker) findBalance(
+	ctx context.Context,
+	dbTx database.Transaction,
+	rawInput string,
+) (string, error) {
+	output, err := w.FindBalanceWorker(ctx, dbTx, rawInput)
+	if w.balanceObserver == nil || (err != nil && !errors.Is(err, ErrUnsatisfiable)) {
+		return output, err
+	}
+
+	// The input and output have already been
+	// validated by FindBalanceWorker.
+	var input job.FindBalanceInput
+	_ = json.Unmarshal([]byte(rawInput), &input)
+
+	var balance *job.FindBalanceOutput
+	if err == nil {
+		balance = &job.FindBalanceOutput{}
+		_ = json.Unmarshal([]byte(output), balance)
+	}
+
+	w.balanceObserver(ctx, &input, balance)
+
+	return output, err
+}
+
 // AssertWorker checks if an input is < 0.
 func AssertWorker(rawInput string) error {
 	// We unmarshal the input here to handle string
@@ -728,6 +1321,172 @@ func AssertWorker(rawInput string) error {
 	return nil
 }
 
+// compare returns true if <left> <operation> <right>.
+func compare(left string, operation job.ComparisonOperation, right string) (bool, error) {
+	result, err := CompareWorker(types.PrintStruct(&job.CompareInput{
+		Operation:  operation,
+		LeftValue:  left,
+		RightValue: right,
+	}))
+	if err != nil {
+		return false, err
+	}
+
+	return strconv.ParseBool(result)
+}
+
+// AssertBalanceWorker queries the balance of an account and
+// returns an error if it does not satisfy the comparison in
+// the input.
+func (w *Worker) AssertBalanceWorker(
+	ctx context.Context,
+	dbTx database.Transaction,
+	rawInput string,
+) (string, error) {
+	var input job.AssertBalanceInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if err := assertInputValidation(input.Account, input.Currency); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	dataHelper, ok := w.helperFor(input.Network).(DataHelper)
+	if !ok {
+		return "", fmt.Errorf("%w: helper does not implement DataHelper", ErrHelperUnsupported)
+	}
+
+	amount, err := dataHelper.AccountBalance(ctx, input.Network, input.Account, input.Currency)
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to fetch balance: %s", ErrActionFailed, err.Error())
+	}
+
+	if amount == nil || len(amount.Value) == 0 {
+		return "", fmt.Errorf(
+			"%w: balance of %s is missing",
+			ErrActionFailed,
+			input.Account.Address,
+		)
+	}
+
+	satisfied, err := compare(amount.Value, input.Operation, input.Value)
+	if err != nil {
+		return "", err
+	}
+
+	if !satisfied {
+		return "", fmt.Errorf(
+			"%w: balance of %s is %s (expected %s %s)",
+			ErrActionFailed,
+			input.Account.Address,
+			amount.Value,
+			input.Operation,
+			input.Value,
+		)
+	}
+
+	return types.PrintStruct(amount), nil
+}
+
+// AssertCoinsWorker queries the coins of an account and
+// returns an error if they do not satisfy the input.
+func (w *Worker) AssertCoinsWorker(
+	ctx context.Context,
+	dbTx database.Transaction,
+	rawInput string,
+) (string, error) {
+	var input job.AssertCoinsInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if err := assertInputValidation(input.Account, input.Currency); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	dataHelper, ok := w.helperFor(input.Network).(DataHelper)
+	if !ok {
+		return "", fmt.Errorf("%w: helper does not implement DataHelper", ErrHelperUnsupported)
+	}
+
+	coins, err := dataHelper.AccountCoins(ctx, input.Network, input.Account, input.Currency)
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to fetch coins: %s", ErrActionFailed, err.Error())
+	}
+
+	owned := map[string]struct{}{}
+	for _, coin := range coins {
+		owned[coin.CoinIdentifier.Identifier] = struct{}{}
+	}
+
+	for _, coin := range input.Contains {
+		if _, ok := owned[coin.Identifier]; !ok {
+			return "", fmt.Errorf(
+				"%w: %s does not own coin %s",
+				ErrActionFailed,
+				input.Account.Address,
+				coin.Identifier,
+			)
+		}
+	}
+
+	for _, coin := range input.Excludes {
+		if _, ok := owned[coin.Identifier]; ok {
+			return "", fmt.Errorf(
+				"%w: %s owns coin %s",
+				ErrActionFailed,
+				input.Account.Address,
+				coin.Identifier,
+			)
+		}
+	}
+
+	if len(input.Operation) > 0 {
+		count := strconv.Itoa(len(coins))
+		satisfied, err := compare(count, input.Operation, input.Count)
+		if err != nil {
+			return "", err
+		}
+
+		if !satisfied {
+			return "", fmt.Errorf(
+				"%w: %s owns %s coins (expected %s %s)",
+				ErrActionFailed,
+				input.Account.Address,
+				count,
+				input.Operation,
+				input.Count,
+			)
+		}
+	}
+
+	if coins == nil {
+		coins = []*types.Coin{}
+	}
+
+	return types.PrintStruct(coins), nil
+}
+
+// assertInputValidation ensures the account and currency
+// of an AssertBalance or AssertCoins input are populated.
+func assertInputValidation(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) error {
+	if account == nil {
+		return errors.New("account identifier is nil")
+	}
+
+	if currency == nil {
+		return errors.New("currency is nil")
+	}
+
+	return nil
+}
+
 // FindCurrencyAmountWorker finds a *types.Amount with a specific
 // *types.Currency in a []*types.Amount.
 func FindCurrencyAmountWorker(rawInput string) (string, error) {
@@ -775,8 +1534,10 @@ func LoadEnvWorker(rawInput string) (string, error) {
 }
 
 // HTTPRequestWorker makes an HTTP request and returns the response to
-// store in a variable. This is useful for algorithmic fauceting.
-func HTTPRequestWorker(rawInput string) (string, error) {
+// store in a variable. This is useful for algorithmic fauceting. The
+// request is canceled when ctx is done (ex: when the timeout of the
+// action elapses).
+func HTTPRequestWorker(ctx context.Context, rawInput string) (string, error) {
 	var input job.HTTPRequestInput
 	err := job.UnmarshalInput([]byte(rawInput), &input)
 	if err != nil {
@@ -795,13 +1556,14 @@ func HTTPRequestWorker(rawInput string) (string, error) {
 	var request *http.Request
 	switch input.Method {
 	case job.MethodGet:
-		request, err = http.NewRequest(http.MethodGet, input.URL, nil)
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, input.URL, nil)
 		if err != nil {
 			return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
 		}
 		request.Header.Set("Accept", "application/json")
 	case job.MethodPost:
-		request, err = http.NewRequest(
+		request, err = http.NewRequestWithContext(
+			ctx,
 			http.MethodPost,
 			input.URL,
 			bytes.NewBufferString(input.Body),
@@ -819,6 +1581,10 @@ func HTTPRequestWorker(rawInput string) (string, error) {
 		)
 	}
 
+	for key, value := range input.Headers {
+		request.Header.Set(key, value)
+	}
+
 	resp, err := client.Do(request)
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
@@ -839,7 +1605,30 @@ func HTTPRequestWorker(rawInput string) (string, error) {
 		)
 	}
 
-	return string(body), nil
+	if len(input.ResponsePath) == 0 {
+		return string(body), nil
+	}
+
+	if !gjson.ValidBytes(body) {
+		return "", fmt.Errorf(
+			"%w: unable to extract %s from non-JSON response %s",
+			ErrActionFailed,
+			input.ResponsePath,
+			body,
+		)
+	}
+
+	value := gjson.GetBytes(body, input.ResponsePath)
+	if !value.Exists() {
+		return "", fmt.Errorf(
+			"%w: %s not found in response %s",
+			ErrActionFailed,
+			input.ResponsePath,
+			body,
+		)
+	}
+
+	return value.Raw, nil
 }
 
 // SetBlobWorker transactionally saves a key and value for use
@@ -896,3 +1685,85 @@ func (w *Worker) GetBlobWorker(
 
 	return string(val), nil
 }
+
+// signPayloadBytes returns the bytes to sign
+// for a *job.SignPayloadInput.
+func signPayloadBytes(input *job.SignPayloadInput) ([]byte, error) {
+	if len(input.HexBytes) > 0 && len(input.Message) > 0 {
+		return nil, errors.New("cannot populate both hex bytes and message")
+	}
+
+	if len(input.Message) > 0 {
+		return []byte(input.Message), nil
+	}
+
+	if len(input.HexBytes) == 0 {
+		return nil, errors.New("hex bytes or message must be populated")
+	}
+
+	return hex.DecodeString(input.HexBytes)
+}
+
+// SignPayloadWorker signs some bytes (or a message) with
+// the key of a stored account. The Helper must implement
+// KeyHelper.
+func (w *Worker) SignPayloadWorker(
+	ctx context.Context,
+	dbTx database.Transaction,
+	rawInput string,
+) (string, error) {
+	var input job.SignPayloadInput
+	err := job.UnmarshalInput([]byte(rawInput), &input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if err := asserter.AccountIdentifier(input.AccountIdentifier); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	if err := asserter.SignatureType(input.SignatureType); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	payload, err := signPayloadBytes(&input)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	keyHelper, ok := w.helper.(KeyHelper)
+	if !ok {
+		return "", fmt.Errorf("%w: %T does not implement KeyHelper", ErrHelperUnsupported, w.helper)
+	}
+
+	keyPair, err := keyHelper.GetKey(ctx, dbTx, input.AccountIdentifier)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	if len(input.CurveType) > 0 && keyPair.PublicKey.CurveType != input.CurveType {
+		return "", fmt.Errorf(
+			"%w: key for %s uses curve %s, not %s",
+			ErrActionFailed,
+			input.AccountIdentifier.Address,
+			keyPair.PublicKey.CurveType,
+			input.CurveType,
+		)
+	}
+
+	signer, err := keyPair.Signer()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	signature, err := signer.Sign(&types.SigningPayload{
+		AccountIdentifier: input.AccountIdentifier,
+		Bytes:             payload,
+		SignatureType:     input.SignatureType,
+	}, input.SignatureType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrActionFailed, err.Error())
+	}
+
+	return types.PrintStruct(signature), nil
+}
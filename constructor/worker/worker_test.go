@@ -16,6 +16,7 @@ package worker
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
@@ -32,6 +34,7 @@ import (
 	"github.com/tidwall/gjson"
 
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	"github.com/coinbase/rosetta-sdk-go/keys"
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/constructor/worker"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -945,6 +948,66 @@ func TestFindBalanceWorker(t *testing.T) {
 			mockHelper: &mocks.Helper{},
 			err:        ErrInvalidInput,
 		},
+		"coin selection without coin": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				CoinSelection: job.LargestFirst,
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+		"unsupported coin selection": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				RequireCoin:   true,
+				CoinSelection: "random",
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+		"consolidate without max inputs": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				RequireCoin:   true,
+				CoinSelection: job.ConsolidateCoins,
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
+		"invalid dust threshold": {
+			input: &job.FindBalanceInput{
+				MinimumBalance: &types.Amount{
+					Value: "100",
+					Currency: &types.Currency{
+						Symbol:   "BTC",
+						Decimals: 8,
+					},
+				},
+				RequireCoin:   true,
+				CoinSelection: job.LargestFirst,
+				DustThreshold: "-1",
+			},
+			mockHelper: &mocks.Helper{},
+			err:        ErrInvalidInput,
+		},
 	}
 
 	for name, test := range tests {
@@ -1545,6 +1608,71 @@ func TestJob_Failures(t *testing.T) {
 			},
 			helper: &mocks.Helper{},
 		},
+		"invalid action: modulo by zero": {
+			scenario: &job.Scenario{
+				Name: "create_address",
+				Actions: []*job.Action{
+					{
+						Type:  job.Math,
+						Input: `{"operation":"modulo", "left_value":"1", "right_value":"0"}`,
+					},
+				},
+			},
+			executionErr: &Error{
+				Workflow: "random",
+				Scenario: "create_address",
+				Action: &job.Action{
+					Type:  job.Math,
+					Input: `{"operation":"modulo", "left_value":"1", "right_value":"0"}`,
+				},
+				ProcessedInput: `{"operation":"modulo", "left_value":"1", "right_value":"0"}`,
+				Err:            ErrActionFailed,
+			},
+			helper: &mocks.Helper{},
+		},
+		"invalid condition": {
+			scenario: &job.Scenario{
+				Name: "create_address",
+				Actions: []*job.Action{
+					{
+						Type:  job.If,
+						Input: `"hello"`,
+					},
+				},
+			},
+			executionErr: &Error{
+				Workflow: "random",
+				Scenario: "create_address",
+				Action: &job.Action{
+					Type:  job.If,
+					Input: `"hello"`,
+				},
+				ProcessedInput: `"hello"`,
+				Err:            ErrInvalidInput,
+			},
+			helper: &mocks.Helper{},
+		},
+		"loop limit exceeded": {
+			scenario: &job.Scenario{
+				Name: "create_address",
+				Actions: []*job.Action{
+					{
+						Type:  job.While,
+						Input: `true`,
+					},
+				},
+			},
+			executionErr: &Error{
+				Workflow: "random",
+				Scenario: "create_address",
+				Action: &job.Action{
+					Type:  job.While,
+					Input: `true`,
+				},
+				Err: ErrLoopLimitExceeded,
+			},
+			helper: &mocks.Helper{},
+		},
 		"invalid broadcast: invalid operations": {
 			scenario: &job.Scenario{
 				Name: "create_send",
@@ -1693,6 +1821,170 @@ func TestJob_Failures(t *testing.T) {
 	}
 }
 
+func TestCompareWorker(t *testing.T) {
+	tests := map[string]struct {
+		input string
+
+		output string
+		err    error
+	}{
+		"equal": {
+			input:  `{"operation":"equal","left_value":"10","right_value":"10"}`,
+			output: "true",
+		},
+		"not equal": {
+			input:  `{"operation":"not_equal","left_value":"10","right_value":"10"}`,
+			output: "false",
+		},
+		"greater than": {
+			input:  `{"operation":"greater_than","left_value":"11","right_value":"10"}`,
+			output: "true",
+		},
+		"greater or equal": {
+			input:  `{"operation":"greater_or_equal","left_value":"9","right_value":"10"}`,
+			output: "false",
+		},
+		"less than (big)": {
+			input:  `{"operation":"less_than","left_value":"-1","right_value":"100000000000000000000000"}`, // nolint
+			output: "true",
+		},
+		"less or equal": {
+			input:  `{"operation":"less_or_equal","left_value":"10","right_value":"10"}`,
+			output: "true",
+		},
+		"invalid operation": {
+			input: `{"operation":"approximately","left_value":"10","right_value":"10"}`,
+			err:   ErrInvalidInput,
+		},
+		"invalid value": {
+			input: `{"operation":"equal","left_value":"ten","right_value":"10"}`,
+			err:   ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := CompareWorker(test.input)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.output, output)
+		})
+	}
+}
+
+func TestJob_ControlFlow(t *testing.T) {
+	ctx := context.Background()
+	workflow := &job.Workflow{
+		Name: "split",
+		Scenarios: []*job.Scenario{
+			{
+				Name: "split_balance",
+				Actions: []*job.Action{
+					{
+						Type:       job.SetVariable,
+						Input:      `"0"`,
+						OutputPath: "outputs",
+					},
+					{
+						Type:       job.SetVariable,
+						Input:      `"1003"`,
+						OutputPath: "remaining",
+					},
+					{
+						// Split remaining into outputs of 100
+						Type:  job.While,
+						Input: `{"operation":"greater_or_equal","left_value":{{remaining}},"right_value":"100"}`, // nolint
+						Actions: []*job.Action{
+							{
+								Type:       job.Math,
+								Input:      `{"operation":"subtraction","left_value":{{remaining}},"right_value":"100"}`, // nolint
+								OutputPath: "remaining",
+							},
+							{
+								Type:       job.Math,
+								Input:      `{"operation":"addition","left_value":{{outputs}},"right_value":"1"}`, // nolint
+								OutputPath: "outputs",
+							},
+						},
+					},
+					{
+						Type:       job.Math,
+						Input:      `{"operation":"modulo","left_value":"1003","right_value":"100"}`,
+						OutputPath: "dust",
+					},
+					{
+						Type:       job.Compare,
+						Input:      `{"operation":"equal","left_value":{{dust}},"right_value":{{remaining}}}`, // nolint
+						OutputPath: "consistent",
+					},
+					{
+						Type:  job.If,
+						Input: `{{consistent}}`,
+						Actions: []*job.Action{
+							{
+								Type:       job.SetVariable,
+								Input:      `"consistent"`,
+								OutputPath: "result",
+							},
+						},
+						Else: []*job.Action{
+							{
+								Type:       job.SetVariable,
+								Input:      `"inconsistent"`,
+								OutputPath: "result",
+							},
+						},
+					},
+					{
+						Type:  job.If,
+						Input: `"false"`,
+						Actions: []*job.Action{
+							{
+								Type:       job.SetVariable,
+								Input:      `"true"`,
+								OutputPath: "skipped",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	j := job.New(workflow)
+	worker := New(&mocks.Helper{})
+
+	// Setup DB
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	db, err := database.NewBadgerDatabase(
+		ctx,
+		dir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	defer db.Close(ctx)
+
+	dbTx := db.Transaction(ctx)
+
+	b, executionErr := worker.Process(ctx, dbTx, j)
+	assert.Nil(t, b)
+	assert.Nil(t, executionErr)
+	assert.True(t, j.CheckComplete())
+
+	assert.Equal(t, "10", gjson.Get(j.State, "outputs").String())
+	assert.Equal(t, "3", gjson.Get(j.State, "remaining").String())
+	assert.Equal(t, "3", gjson.Get(j.State, "dust").String())
+	assert.True(t, gjson.Get(j.State, "consistent").Bool())
+	assert.Equal(t, "consistent", gjson.Get(j.State, "result").String())
+	assert.False(t, gjson.Get(j.State, "skipped").Exists())
+}
+
 func TestHTTPRequestWorker(t *testing.T) {
 	var tests = map[string]struct {
 		input          *job.HTTPRequestInput
@@ -1702,6 +1994,7 @@ func TestHTTPRequestWorker(t *testing.T) {
 		expectedLatency int
 		expectedMethod  string
 		expectedBody    string
+		expectedHeaders map[string]string
 
 		response    string
 		contentType string
@@ -1809,6 +2102,55 @@ func TestHTTPRequestWorker(t *testing.T) {
 			statusCode:      http.StatusInternalServerError,
 			err:             ErrActionFailed,
 		},
+		"post with headers and response path": {
+			input: &job.HTTPRequestInput{
+				Method:       job.MethodPost,
+				URL:          "/fee",
+				Timeout:      100,
+				Body:         `{"currency":"BTC"}`,
+				Headers:      map[string]string{"Authorization": "Bearer token"},
+				ResponsePath: "fees.fast",
+			},
+			expectedPath:    "/fee",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodPost,
+			expectedBody:    `{"currency":"BTC"}`,
+			expectedHeaders: map[string]string{"Authorization": "Bearer token"},
+			contentType:     "application/json; charset=UTF-8",
+			response:        `{"fees":{"fast":{"value":"100"},"slow":{"value":"10"}}}`,
+			statusCode:      http.StatusOK,
+			output:          `{"value":"100"}`,
+		},
+		"missing response path": {
+			input: &job.HTTPRequestInput{
+				Method:       job.MethodGet,
+				URL:          "/fee",
+				Timeout:      100,
+				ResponsePath: "fees.medium",
+			},
+			expectedPath:    "/fee",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodGet,
+			contentType:     "application/json; charset=UTF-8",
+			response:        `{"fees":{"fast":{"value":"100"}}}`,
+			statusCode:      http.StatusOK,
+			err:             ErrActionFailed,
+		},
+		"response path with non-JSON response": {
+			input: &job.HTTPRequestInput{
+				Method:       job.MethodGet,
+				URL:          "/fee",
+				Timeout:      100,
+				ResponsePath: "fees",
+			},
+			expectedPath:    "/fee",
+			expectedLatency: 1,
+			expectedMethod:  http.MethodGet,
+			contentType:     "text/plain",
+			response:        `fees`,
+			statusCode:      http.StatusOK,
+			err:             ErrActionFailed,
+		},
 		"invalid content type": { // we don't throw an error
 			input: &job.HTTPRequestInput{
 				Method:  job.MethodGet,
@@ -1836,6 +2178,9 @@ func TestHTTPRequestWorker(t *testing.T) {
 				body, err := ioutil.ReadAll(r.Body)
 				assert.NoError(t, err)
 				assert.Equal(t, test.expectedBody, string(body))
+				for key, value := range test.expectedHeaders {
+					assert.Equal(t, value, r.Header.Get(key))
+				}
 
 				time.Sleep(time.Duration(test.expectedLatency) * time.Millisecond)
 
@@ -1850,7 +2195,7 @@ func TestHTTPRequestWorker(t *testing.T) {
 				test.input.URL = ts.URL + test.input.URL
 			}
 
-			output, err := HTTPRequestWorker(types.PrintStruct(test.input))
+			output, err := HTTPRequestWorker(context.Background(), types.PrintStruct(test.input))
 			if test.err != nil {
 				assert.Equal(t, "", output)
 				assert.True(t, errors.Is(err, test.err))
@@ -2061,3 +2406,905 @@ func TestBlobWorkers(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkResolver(t *testing.T) {
+	ctx := context.Background()
+	primary := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	secondary := &types.NetworkIdentifier{Blockchain: "Ethereum", Network: "Ropsten"}
+	publicKey := &types.PublicKey{
+		Bytes:     []byte("hello"),
+		CurveType: types.Secp256k1,
+	}
+	currency := &types.Currency{Symbol: "ETH", Decimals: 18}
+	account := &types.AccountIdentifier{Address: "0x1"}
+
+	defaultHelper := &mocks.Helper{}
+	secondaryHelper := &mocks.Helper{}
+	worker := New(defaultHelper, WithNetworkResolver(func(
+		network *types.NetworkIdentifier,
+	) Helper {
+		if types.Hash(network) == types.Hash(secondary) {
+			return secondaryHelper
+		}
+
+		return nil
+	}))
+
+	// Unregistered networks use the default Helper
+	defaultHelper.On(
+		"Derive",
+		ctx,
+		primary,
+		publicKey,
+		(map[string]interface{})(nil),
+	).Return(&types.AccountIdentifier{Address: "tb1"}, nil, nil).Once()
+	output, err := worker.DeriveWorker(ctx, types.PrintStruct(&types.ConstructionDeriveRequest{
+		NetworkIdentifier: primary,
+		PublicKey:         publicKey,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "tb1", gjson.Get(output, "account_identifier.address").String())
+
+	secondaryHelper.On(
+		"Derive",
+		ctx,
+		secondary,
+		publicKey,
+		(map[string]interface{})(nil),
+	).Return(account, nil, nil).Once()
+	output, err = worker.DeriveWorker(ctx, types.PrintStruct(&types.ConstructionDeriveRequest{
+		NetworkIdentifier: secondary,
+		PublicKey:         publicKey,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1", gjson.Get(output, "account_identifier.address").String())
+
+	secondaryHelper.On("AllAccounts", ctx, mock.Anything).Return(
+		[]*types.AccountIdentifier{account},
+		nil,
+	).Once()
+	secondaryHelper.On("LockedAccounts", ctx, mock.Anything).Return(
+		[]*types.AccountIdentifier{},
+		nil,
+	).Once()
+	secondaryHelper.On("Balance", ctx, mock.Anything, account, currency).Return(
+		&types.Amount{Value: "100", Currency: currency},
+		nil,
+	).Once()
+	output, err = worker.FindBalanceWorker(ctx, nil, types.PrintStruct(&job.FindBalanceInput{
+		Network: secondary,
+		MinimumBalance: &types.Amount{
+			Value:    "10",
+			Currency: currency,
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, types.PrintStruct(&job.FindBalanceOutput{
+		AccountIdentifier: account,
+		Balance:           &types.Amount{Value: "100", Currency: currency},
+	}), output)
+
+	defaultHelper.AssertExpectations(t)
+	secondaryHelper.AssertExpectations(t)
+}
+
+// keyHelper is a *mocks.Helper that
+// implements KeyHelper.
+type keyHelper struct {
+	*mocks.Helper
+
+	keys map[string]*keys.KeyPair
+}
+
+func (h *keyHelper) GetKey(
+	ctx context.Context,
+	dbTx database.Transaction,
+	account *types.AccountIdentifier,
+) (*keys.KeyPair, error) {
+	keyPair, ok := h.keys[account.Address]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+
+	return keyPair, nil
+}
+
+func TestSignPayloadWorker(t *testing.T) {
+	ctx := context.Background()
+	secpAccount := &types.AccountIdentifier{Address: "secp"}
+	edAccount := &types.AccountIdentifier{Address: "ed"}
+	missingAccount := &types.AccountIdentifier{Address: "missing"}
+
+	secpKey, err := keys.GenerateKeypair(types.Secp256k1)
+	assert.NoError(t, err)
+	edKey, err := keys.GenerateKeypair(types.Edwards25519)
+	assert.NoError(t, err)
+
+	hash := "ea4ae8bd0f5c3e4b14a85cc8fd0c8a8b1f2d7d0b9d3f6ed1c8e1b2f3a4b5c6d7"
+	hashBytes, err := hex.DecodeString(hash)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input *job.SignPayloadInput
+
+		key         *keys.KeyPair
+		signedBytes []byte
+		expectedErr error
+	}{
+		"ecdsa hex bytes": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: secpAccount,
+				HexBytes:          hash,
+				CurveType:         types.Secp256k1,
+				SignatureType:     types.Ecdsa,
+			},
+			key:         secpKey,
+			signedBytes: hashBytes,
+		},
+		"ed25519 message": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: edAccount,
+				Message:           "I own this account",
+				SignatureType:     types.Ed25519,
+			},
+			key:         edKey,
+			signedBytes: []byte("I own this account"),
+		},
+		"curve mismatch": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: edAccount,
+				Message:           "I own this account",
+				CurveType:         types.Secp256k1,
+				SignatureType:     types.Ed25519,
+			},
+			key:         edKey,
+			expectedErr: ErrActionFailed,
+		},
+		"unsupported signature type for key": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: edAccount,
+				HexBytes:          hash,
+				SignatureType:     types.Ecdsa,
+			},
+			key:         edKey,
+			expectedErr: ErrActionFailed,
+		},
+		"missing key": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: missingAccount,
+				HexBytes:          hash,
+				SignatureType:     types.Ecdsa,
+			},
+			expectedErr: ErrActionFailed,
+		},
+		"both hex bytes and message": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: secpAccount,
+				HexBytes:          hash,
+				Message:           "hello",
+				SignatureType:     types.Ecdsa,
+			},
+			expectedErr: ErrInvalidInput,
+		},
+		"no bytes": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: secpAccount,
+				SignatureType:     types.Ecdsa,
+			},
+			expectedErr: ErrInvalidInput,
+		},
+		"invalid hex": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: secpAccount,
+				HexBytes:          "hello",
+				SignatureType:     types.Ecdsa,
+			},
+			expectedErr: ErrInvalidInput,
+		},
+		"invalid signature type": {
+			input: &job.SignPayloadInput{
+				AccountIdentifier: secpAccount,
+				HexBytes:          hash,
+				SignatureType:     "blah",
+			},
+			expectedErr: ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			helper := &keyHelper{
+				Helper: &mocks.Helper{},
+				keys:   map[string]*keys.KeyPair{},
+			}
+			if test.key != nil {
+				helper.keys[test.input.AccountIdentifier.Address] = test.key
+			}
+			worker := New(helper)
+
+			output, err := worker.SignPayloadWorker(ctx, nil, types.PrintStruct(test.input))
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				assert.Empty(t, output)
+			} else {
+				assert.NoError(t, err)
+
+				var signature types.Signature
+				assert.NoError(t, json.Unmarshal([]byte(output), &signature))
+				assert.Equal(t, test.signedBytes, signature.SigningPayload.Bytes)
+				assert.Equal(t, test.input.AccountIdentifier, signature.SigningPayload.AccountIdentifier)
+				assert.Equal(t, test.key.PublicKey, signature.PublicKey)
+
+				signer, err := test.key.Signer()
+				assert.NoError(t, err)
+				assert.NoError(t, signer.Verify(&signature))
+			}
+
+			helper.AssertExpectations(t)
+		})
+	}
+
+	t.Run("helper without KeyHelper", func(t *testing.T) {
+		worker := New(&mocks.Helper{})
+		output, err := worker.SignPayloadWorker(ctx, nil, types.PrintStruct(&job.SignPayloadInput{
+			AccountIdentifier: secpAccount,
+			HexBytes:          hash,
+			SignatureType:     types.Ecdsa,
+		}))
+		assert.True(t, errors.Is(err, ErrHelperUnsupported))
+		assert.Empty(t, output)
+	})
+}
+
+func TestJob_ActionPolicies(t *testing.T) {
+	ctx := context.Background()
+	reserve := &job.Action{
+		Type:       job.SetVariable,
+		Input:      `{"hash":"utxo","index":0}`,
+		OutputPath: "reserved",
+		Compensation: &job.Action{
+			Type:  job.SetBlob,
+			Input: `{"key":"released","value":{{reserved}}}`,
+		},
+	}
+	released := []byte(`{"hash":"utxo","index":0}`)
+
+	t.Run("retry", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "retry",
+			Scenarios: []*job.Scenario{
+				{
+					Name: "fetch",
+					Actions: []*job.Action{
+						{
+							Type:       job.GetBlob,
+							Input:      `{"key":"config"}`,
+							OutputPath: "config",
+							Retries:    2,
+						},
+					},
+				},
+			},
+		})
+
+		helper.On("GetBlob", ctx, mock.Anything, types.Hash("config")).Return(
+			false,
+			nil,
+			errors.New("unavailable"),
+		).Twice()
+		helper.On("GetBlob", ctx, mock.Anything, types.Hash("config")).Return(
+			true,
+			[]byte(`"hello"`),
+			nil,
+		).Once()
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.Nil(t, executionErr)
+		assert.Equal(t, "hello", gjson.Get(j.State, "config").String())
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "retry",
+			Scenarios: []*job.Scenario{
+				{
+					Name: "fetch",
+					Actions: []*job.Action{
+						{
+							Type:       job.GetBlob,
+							Input:      `{"key":"config"}`,
+							OutputPath: "config",
+							Retries:    1,
+						},
+					},
+				},
+			},
+		})
+
+		helper.On("GetBlob", ctx, mock.Anything, types.Hash("config")).Return(
+			false,
+			nil,
+			errors.New("unavailable"),
+		).Twice()
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.True(t, errors.Is(executionErr.Err, ErrActionFailed))
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "timeout",
+			Scenarios: []*job.Scenario{
+				{
+					Name: "fetch",
+					Actions: []*job.Action{
+						{
+							Type:       job.GetBlob,
+							Input:      `{"key":"config"}`,
+							OutputPath: "config",
+							Timeout:    1,
+						},
+					},
+				},
+			},
+		})
+
+		// The action is invoked with a context that is canceled
+		// when the timeout elapses (and is waited for, so the
+		// dbTx is not used after Process returns).
+		returned := false
+		helper.On("GetBlob", mock.Anything, mock.Anything, types.Hash("config")).Return(
+			false,
+			nil,
+			context.DeadlineExceeded,
+		).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+			returned = true
+		}).Once()
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.True(t, errors.Is(executionErr.Err, ErrActionTimeout))
+		assert.True(t, returned)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("succeeds after timeout", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "timeout",
+			Scenarios: []*job.Scenario{
+				{
+					Name: "store",
+					Actions: []*job.Action{
+						{
+							Type:    job.SetBlob,
+							Input:   `{"key":"config","value":"hello"}`,
+							Timeout: 1,
+							Retries: 1,
+						},
+					},
+				},
+			},
+		})
+
+		// The write succeeds after the timeout elapses, so
+		// it is not retried (and not written twice).
+		helper.On(
+			"SetBlob",
+			mock.Anything,
+			mock.Anything,
+			types.Hash("config"),
+			[]byte(`"hello"`),
+		).Return(nil).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Once()
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.Nil(t, executionErr)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("http request timeout", func(t *testing.T) {
+		done := make(chan struct{})
+		defer close(done)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+		}))
+		defer ts.Close()
+
+		worker := New(&mocks.Helper{})
+		j := job.New(&job.Workflow{
+			Name: "timeout",
+			Scenarios: []*job.Scenario{
+				{
+					Name: "fetch",
+					Actions: []*job.Action{
+						{
+							Type: job.HTTPRequest,
+							Input: types.PrintStruct(&job.HTTPRequestInput{
+								Method:  job.MethodGet,
+								URL:     ts.URL,
+								Timeout: 100,
+							}),
+							OutputPath: "response",
+							Timeout:    1,
+						},
+					},
+				},
+			},
+		})
+
+		start := time.Now()
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.True(t, errors.Is(executionErr.Err, ErrActionTimeout))
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+
+	t.Run("compensate on failure", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "compensate",
+			Scenarios: []*job.Scenario{
+				{
+					Name:    "reserve",
+					Actions: []*job.Action{reserve},
+				},
+				{
+					Name: "spend",
+					Actions: []*job.Action{
+						{
+							Type:  job.Assert,
+							Input: `"-1"`,
+						},
+					},
+				},
+			},
+		})
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.Nil(t, executionErr)
+		assert.Equal(t, []*job.Action{reserve.Compensation}, j.Compensations)
+
+		helper.On(
+			"SetBlob",
+			ctx,
+			mock.Anything,
+			types.Hash("released"),
+			released,
+		).Return(nil).Once()
+		b, executionErr = worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.True(t, errors.Is(executionErr.Err, ErrActionFailed))
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("compensate scenario when unsatisfiable", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "compensate",
+			Scenarios: []*job.Scenario{
+				{
+					Name: "find",
+					Actions: []*job.Action{
+						reserve,
+						{
+							Type:       job.FindBalance,
+							Input:      `{"minimum_balance":{"value":"100","currency":{"symbol":"BTC","decimals":8}}}`, // nolint
+							OutputPath: "account",
+						},
+					},
+				},
+			},
+		})
+		// Compensations of previous scenarios are not
+		// invoked because the Job has not failed.
+		j.Compensations = []*job.Action{
+			{
+				Type:  job.SetBlob,
+				Input: `{"key":"previous","value":"1"}`,
+			},
+		}
+
+		helper.On("AllAccounts", ctx, mock.Anything).Return(
+			[]*types.AccountIdentifier{},
+			nil,
+		).Once()
+		helper.On(
+			"SetBlob",
+			ctx,
+			mock.Anything,
+			types.Hash("released"),
+			released,
+		).Return(nil).Once()
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.True(t, errors.Is(executionErr.Err, ErrCreateAccount))
+		assert.Len(t, j.Compensations, 1)
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("compensate failed broadcast", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		worker := New(helper)
+		j := job.New(&job.Workflow{
+			Name: "compensate",
+			Scenarios: []*job.Scenario{
+				{
+					Name:    "reserve",
+					Actions: []*job.Action{reserve},
+				},
+			},
+		})
+		j.State = `{"reserved":{"hash":"utxo","index":0}}`
+		j.Compensations = []*job.Action{reserve.Compensation}
+
+		helper.On(
+			"SetBlob",
+			ctx,
+			mock.Anything,
+			types.Hash("released"),
+			released,
+		).Return(nil).Once()
+
+		worker.Compensate(ctx, nil, j)
+		assert.Nil(t, j.Compensations)
+
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("compensations cleared on completion", func(t *testing.T) {
+		worker := New(&mocks.Helper{})
+		j := job.New(&job.Workflow{
+			Name: "compensate",
+			Scenarios: []*job.Scenario{
+				{
+					Name:    "reserve",
+					Actions: []*job.Action{reserve},
+				},
+			},
+		})
+
+		b, executionErr := worker.Process(ctx, nil, j)
+		assert.Nil(t, b)
+		assert.Nil(t, executionErr)
+		assert.True(t, j.CheckComplete())
+		assert.Nil(t, j.Compensations)
+	})
+}
+
+func TestRandomBytesWorker(t *testing.T) {
+	tests := map[string]struct {
+		input string
+
+		length int
+		err    error
+	}{
+		"hex": {
+			input:  `{"length":32}`,
+			length: 64,
+		},
+		"base64": {
+			input:  `{"length":3,"encoding":"base64"}`,
+			length: 4,
+		},
+		"invalid length": {
+			input: `{"length":0}`,
+			err:   ErrInvalidInput,
+		},
+		"invalid encoding": {
+			input: `{"length":3,"encoding":"utf8"}`,
+			err:   ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := RandomBytesWorker(test.input)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				assert.Equal(t, "", output)
+				return
+			}
+
+			assert.NoError(t, err)
+			var value string
+			assert.NoError(t, json.Unmarshal([]byte(output), &value))
+			assert.Len(t, value, test.length)
+		})
+	}
+}
+
+func TestTimestampWorker(t *testing.T) {
+	t.Run("seconds", func(t *testing.T) {
+		start := time.Now().Unix()
+		output, err := TimestampWorker("")
+		assert.NoError(t, err)
+
+		var value string
+		assert.NoError(t, json.Unmarshal([]byte(output), &value))
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		assert.NoError(t, err)
+		assert.True(t, seconds >= start && seconds <= time.Now().Unix())
+	})
+
+	t.Run("milliseconds with offset", func(t *testing.T) {
+		start := time.Now().Add(time.Minute).UnixNano() / int64(time.Millisecond)
+		output, err := TimestampWorker(`{"unit":"milliseconds","offset_seconds":60}`)
+		assert.NoError(t, err)
+
+		var value string
+		assert.NoError(t, json.Unmarshal([]byte(output), &value))
+		milliseconds, err := strconv.ParseInt(value, 10, 64)
+		assert.NoError(t, err)
+		assert.True(t, milliseconds >= start)
+	})
+
+	t.Run("rfc3339", func(t *testing.T) {
+		output, err := TimestampWorker(`{"unit":"rfc3339"}`)
+		assert.NoError(t, err)
+
+		var value string
+		assert.NoError(t, json.Unmarshal([]byte(output), &value))
+		_, err = time.Parse(time.RFC3339, value)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid unit", func(t *testing.T) {
+		output, err := TimestampWorker(`{"unit":"days"}`)
+		assert.True(t, errors.Is(err, ErrInvalidInput))
+		assert.Equal(t, "", output)
+	})
+}
+
+func TestHashWorker(t *testing.T) {
+	tests := map[string]struct {
+		input string
+
+		output string
+		err    error
+	}{
+		"sha256": {
+			input:  `{"function":"sha256","data":"hello"}`,
+			output: `"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"`,
+		},
+		"sha256 hex": {
+			input:  `{"function":"sha256","data":"0x68656c6c6f","encoding":"hex"}`,
+			output: `"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"`,
+		},
+		"sha256 base64": {
+			input:  `{"function":"sha256","data":"aGVsbG8=","encoding":"base64"}`,
+			output: `"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"`,
+		},
+		"sha256 json": {
+			input:  `{"function":"sha256","data":{"a":1}}`,
+			output: `"015abd7f5cc57a2dd94b7590f04ad8084273905ee33ec5cebeae62276a97f862"`,
+		},
+		"keccak256": {
+			input:  `{"function":"keccak256","data":"hello"}`,
+			output: `"1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8"`,
+		},
+		"missing data": {
+			input: `{"function":"sha256"}`,
+			err:   ErrInvalidInput,
+		},
+		"invalid hex": {
+			input: `{"function":"sha256","data":"hello","encoding":"hex"}`,
+			err:   ErrInvalidInput,
+		},
+		"invalid function": {
+			input: `{"function":"md5","data":"hello"}`,
+			err:   ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := HashWorker(test.input)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.output, output)
+		})
+	}
+}
+
+type dataHelper struct {
+	*mocks.Helper
+
+	balances map[string]*types.Amount
+	coins    map[string][]*types.Coin
+}
+
+func (h *dataHelper) AccountBalance(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) (*types.Amount, error) {
+	amount, ok := h.balances[account.Address]
+	if !ok {
+		return nil, errors.New("account not found")
+	}
+
+	return amount, nil
+}
+
+func (h *dataHelper) AccountCoins(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) ([]*types.Coin, error) {
+	return h.coins[account.Address], nil
+}
+
+func TestAssertBalanceWorker(t *testing.T) {
+	ctx := context.Background()
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	helper := &dataHelper{
+		Helper: &mocks.Helper{},
+		balances: map[string]*types.Amount{
+			"addr1": {Value: "100", Currency: currency},
+			"addr3": nil,
+		},
+	}
+	worker := New(helper)
+
+	tests := map[string]struct {
+		input string
+
+		output string
+		err    error
+	}{
+		"satisfied": {
+			input:  `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"operation":"greater_or_equal","value":"100"}`, // nolint
+			output: types.PrintStruct(&types.Amount{Value: "100", Currency: currency}),
+		},
+		"not satisfied": {
+			input: `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"operation":"equal","value":"50"}`, // nolint
+			err:   ErrActionFailed,
+		},
+		"unknown account": {
+			input: `{"account_identifier":{"address":"addr2"},"currency":{"symbol":"BTC","decimals":8},"operation":"equal","value":"0"}`, // nolint
+			err:   ErrActionFailed,
+		},
+		"missing balance": {
+			input: `{"account_identifier":{"address":"addr3"},"currency":{"symbol":"BTC","decimals":8},"operation":"equal","value":"0"}`, // nolint
+			err:   ErrActionFailed,
+		},
+		"invalid operation": {
+			input: `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"operation":"~","value":"50"}`, // nolint
+			err:   ErrInvalidInput,
+		},
+		"missing currency": {
+			input: `{"account_identifier":{"address":"addr1"},"operation":"equal","value":"100"}`,
+			err:   ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := worker.AssertBalanceWorker(ctx, nil, test.input)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.output, output)
+		})
+	}
+
+	t.Run("helper without DataHelper", func(t *testing.T) {
+		syncedHelper := &mocks.Helper{}
+		output, err := New(syncedHelper).AssertBalanceWorker(
+			ctx,
+			nil,
+			types.PrintStruct(&job.AssertBalanceInput{
+				Account:   &types.AccountIdentifier{Address: "addr1"},
+				Currency:  currency,
+				Operation: job.LessThan,
+				Value:     "100",
+			}),
+		)
+		assert.Empty(t, output)
+		assert.True(t, errors.Is(err, ErrHelperUnsupported))
+		syncedHelper.AssertExpectations(t)
+	})
+}
+
+func TestAssertCoinsWorker(t *testing.T) {
+	ctx := context.Background()
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	coin := &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{Identifier: "tx1:0"},
+		Amount:         &types.Amount{Value: "100", Currency: currency},
+	}
+	helper := &dataHelper{
+		Helper: &mocks.Helper{},
+		coins: map[string][]*types.Coin{
+			"addr1": {coin},
+		},
+	}
+	worker := New(helper)
+
+	tests := map[string]struct {
+		input string
+
+		output string
+		err    error
+	}{
+		"contains": {
+			input:  `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"contains":[{"identifier":"tx1:0"}],"excludes":[{"identifier":"tx0:0"}]}`, // nolint
+			output: types.PrintStruct([]*types.Coin{coin}),
+		},
+		"count": {
+			input:  `{"account_identifier":{"address":"addr2"},"currency":{"symbol":"BTC","decimals":8},"operation":"equal","count":"0"}`, // nolint
+			output: `[]`,
+		},
+		"missing coin": {
+			input: `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"contains":[{"identifier":"tx2:0"}]}`, // nolint
+			err:   ErrActionFailed,
+		},
+		"excluded coin": {
+			input: `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"excludes":[{"identifier":"tx1:0"}]}`, // nolint
+			err:   ErrActionFailed,
+		},
+		"count not satisfied": {
+			input: `{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8},"operation":"greater_than","count":"1"}`, // nolint
+			err:   ErrActionFailed,
+		},
+		"missing account": {
+			input: `{"currency":{"symbol":"BTC","decimals":8}}`,
+			err:   ErrInvalidInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := worker.AssertCoinsWorker(ctx, nil, test.input)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.output, output)
+		})
+	}
+
+	t.Run("helper without DataHelper", func(t *testing.T) {
+		output, err := New(&mocks.Helper{}).AssertCoinsWorker(
+			ctx,
+			nil,
+			`{"account_identifier":{"address":"addr1"},"currency":{"symbol":"BTC","decimals":8}}`,
+		)
+		assert.Empty(t, output)
+		assert.True(t, errors.Is(err, ErrHelperUnsupported))
+	})
+}
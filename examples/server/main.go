@@ -15,6 +15,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -73,8 +74,16 @@ func main() {
 	// Create the main router handler then apply the logger and Cors
 	// middlewares in sequence.
 	router := NewBlockchainRouter(network, asserter)
-	loggedRouter := server.LoggerMiddleware(router)
+	loggedRouter := server.Chain(router, server.LoggingMiddleware(&server.LoggingConfig{}))
 	corsRouter := server.CorsMiddleware(loggedRouter)
-	log.Printf("Listening on port %d\n", serverPort)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", serverPort), corsRouter))
+
+	// Run shuts down gracefully on SIGINT or SIGTERM.
+	err = server.Run(
+		context.Background(),
+		corsRouter,
+		server.WithAddr(fmt.Sprintf(":%d", serverPort)),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/coinbase/rosetta-sdk-go/client"
 	utils "github.com/coinbase/rosetta-sdk-go/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -54,8 +55,13 @@ func (f *Fetcher) RequestFailedError(
 		}
 	}
 
+	requestErr := ErrRequestFailed
+	if errors.Is(err, client.ErrNotImplemented) {
+		requestErr = ErrRequestNotImplemented
+	}
+
 	return &Error{
-		Err:       fmt.Errorf("%w: %s %s", ErrRequestFailed, message, err.Error()),
+		Err:       fmt.Errorf("%w: %s %s", requestErr, message, err.Error()),
 		ClientErr: rosettaErr,
 		Retry: ((rosettaErr != nil && rosettaErr.Retriable) || transientError(err) || f.forceRetry) &&
 			!errors.Is(err, context.Canceled),
@@ -75,6 +81,11 @@ var (
 	// ErrRequestFailed is returned when a request fails.
 	ErrRequestFailed = errors.New("request failed")
 
+	// ErrRequestNotImplemented is returned when a request fails
+	// because the endpoint is not implemented (it wraps
+	// ErrRequestFailed).
+	ErrRequestNotImplemented = fmt.Errorf("%w: endpoint not implemented", ErrRequestFailed)
+
 	// ErrExhaustedRetries is returned when a request with retries
 	// fails because it was attempted too many times.
 	ErrExhaustedRetries = errors.New("retries exhausted")
@@ -91,6 +102,7 @@ func Err(err error) bool {
 		ErrNoNetworks,
 		ErrNetworkMissing,
 		ErrRequestFailed,
+		ErrRequestNotImplemented,
 		ErrExhaustedRetries,
 		ErrCouldNotAcquireSemaphore,
 	}
@@ -49,6 +49,7 @@ func TestEventsBlocksRetry(t *testing.T) {
 		expectedError       error
 		retriableError      bool
 		non500Error         bool
+		notImplemented      bool
 
 		fetcherMaxRetries uint64
 		shouldCancel      bool
@@ -82,6 +83,14 @@ func TestEventsBlocksRetry(t *testing.T) {
 			fetcherMaxRetries:   5,
 			expectedError:       ErrRequestFailed,
 		},
+		"not implemented": {
+			network:             basicNetwork,
+			expectedMaxSeq:      -1,
+			errorsBeforeSuccess: 1,
+			fetcherMaxRetries:   5,
+			notImplemented:      true,
+			expectedError:       ErrRequestNotImplemented,
+		},
 		"exhausted retries": {
 			network:             basicNetwork,
 			errorsBeforeSuccess: 2,
@@ -124,7 +133,9 @@ func TestEventsBlocksRetry(t *testing.T) {
 				}
 
 				if tries < test.errorsBeforeSuccess {
-					if test.non500Error {
+					if test.notImplemented {
+						w.WriteHeader(http.StatusNotImplemented)
+					} else if test.non500Error {
 						w.Header().Set("Content-Type", "html/text; charset=UTF-8")
 						w.WriteHeader(http.StatusGatewayTimeout)
 						fmt.Fprintln(w, "blah")
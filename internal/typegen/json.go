@@ -0,0 +1,404 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// jsonClosure returns the structs in roots and all of
+// the structs they contain (sorted by name). It returns
+// an error if any of them have a field that cannot be
+// encoded by the generated JSON methods.
+func jsonClosure(types []*typ, roots []string) ([]*typ, error) {
+	byName := map[string]*typ{}
+	for _, t := range types {
+		byName[t.name] = t
+	}
+
+	closure := map[string]*typ{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		if _, ok := closure[name]; ok {
+			return nil
+		}
+
+		t, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%s is not a struct", name)
+		}
+
+		if t.customJSON {
+			return fmt.Errorf("%s has custom JSON methods", name)
+		}
+
+		closure[name] = t
+		for _, f := range t.fields {
+			switch {
+			case f.kind == structKind || f.kind == structsKind:
+				if err := visit(f.goType); err != nil {
+					return err
+				}
+			case f.kind == metadataKind:
+			case (f.kind == valueKind || f.kind == pointerKind) &&
+				(f.enum || f.goType == "string" || jsonInts[f.goType] > 0):
+			default:
+				return fmt.Errorf("%s.%s has an unsupported type", name, f.name)
+			}
+		}
+
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := visit(strings.TrimSpace(root)); err != nil {
+			return nil, err
+		}
+	}
+
+	codecs := []*typ{}
+	for _, t := range closure {
+		codecs = append(codecs, t)
+	}
+
+	sort.Slice(codecs, func(i, j int) bool {
+		return codecs[i].name < codecs[j].name
+	})
+
+	return codecs, nil
+}
+
+// jsonInts are the integer types supported by
+// the generated JSON methods (and their size).
+var jsonInts = map[string]int{
+	"int64": 64,
+	"int32": 32,
+}
+
+// generateMarshalJSON returns the contents of fast_json_gen.go.
+func generateMarshalJSON(types []*typ) []byte {
+	var b bytes.Buffer
+	b.WriteString(license)
+	b.WriteString(`
+// Code generated by internal/typegen. DO NOT EDIT.
+
+package types
+
+import (
+	"strconv"
+)
+`)
+
+	for _, t := range types {
+		writeMarshalJSON(&b, t)
+	}
+
+	return b.Bytes()
+}
+
+// generateUnmarshalJSON returns the contents
+// of fast_json_unmarshal_gen.go.
+func generateUnmarshalJSON(types []*typ) []byte {
+	var b bytes.Buffer
+	b.WriteString(license)
+	b.WriteString(`
+// Code generated by internal/typegen. DO NOT EDIT.
+
+//go:build fastjson
+// +build fastjson
+
+package types
+
+import (
+	"encoding/json"
+)
+`)
+
+	for _, t := range types {
+		writeUnmarshalJSON(&b, t)
+	}
+
+	return b.Bytes()
+}
+
+// writeMarshalJSON writes the MarshalJSON
+// and appendJSON methods of t.
+func writeMarshalJSON(b *bytes.Buffer, t *typ) {
+	fmt.Fprintf(b, `
+// MarshalJSON encodes the %[1]s to JSON
+// without reflection (see fast_json.go).
+func (v *%[1]s) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the %[1]s to b as JSON.
+func (v *%[1]s) appendJSON(b []byte) ([]byte, error) {
+`, t.name)
+
+	for _, f := range t.fields {
+		if f.kind == structKind || f.kind == structsKind || f.kind == metadataKind {
+			b.WriteString("\tvar err error\n")
+			break
+		}
+	}
+
+	b.WriteString("\tb = append(b, '{')\n")
+	for _, f := range t.fields {
+		key := fmt.Sprintf("\tb = appendJSONKey(b, `\"%s\":`)\n", f.jsonName)
+		if !f.omitEmpty {
+			b.WriteString(key)
+			writeMarshalValue(b, f, true)
+			continue
+		}
+
+		var condition string
+		switch {
+		case f.kind == valueKind && jsonInts[f.goType] > 0:
+			condition = "v.%s != 0"
+		case f.kind == valueKind:
+			condition = `v.%s != ""`
+		case f.kind == pointerKind || f.kind == structKind:
+			condition = "v.%s != nil"
+		default:
+			condition = "len(v.%s) > 0"
+		}
+
+		fmt.Fprintf(b, "\tif "+condition+" {\n", f.name)
+		b.WriteString(key)
+		writeMarshalValue(b, f, f.kind == valueKind)
+		b.WriteString("\t}\n")
+	}
+
+	b.WriteString("\n\treturn append(b, '}'), nil\n}\n")
+}
+
+// writeMarshalValue writes the code that appends the value
+// of f to b (or null if it is nil and nullable is true).
+func writeMarshalValue(b *bytes.Buffer, f *field, nullable bool) {
+	value := "v." + f.name
+	if f.kind == pointerKind {
+		value = "*" + value
+	}
+
+	var code string
+	switch f.kind {
+	case valueKind, pointerKind:
+		if size := jsonInts[f.goType]; size > 0 {
+			if f.goType != "int64" {
+				value = fmt.Sprintf("int64(%s)", value)
+			}
+
+			code = fmt.Sprintf("b = strconv.AppendInt(b, %s, 10)\n", value)
+		} else {
+			if f.enum {
+				value = fmt.Sprintf("string(%s)", value)
+			}
+
+			code = fmt.Sprintf("b = appendJSONString(b, %s)\n", value)
+		}
+
+		if f.kind == valueKind {
+			b.WriteString("\t" + code)
+			return
+		}
+	case structKind:
+		code = fmt.Sprintf(`if b, err = %s.appendJSON(b); err != nil {
+			return nil, err
+		}
+`, value)
+	case structsKind:
+		code = fmt.Sprintf(`b = append(b, '[')
+		for i, x := range %s {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if x == nil {
+				b = append(b, "null"...)
+				continue
+			}
+
+			if b, err = x.appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+`, value)
+	case metadataKind:
+		code = fmt.Sprintf(`if b, err = appendJSONValue(b, %s); err != nil {
+			return nil, err
+		}
+`, value)
+	}
+
+	if !nullable {
+		b.WriteString("\t" + code)
+		return
+	}
+
+	fmt.Fprintf(b, `	if v.%s == nil {
+		b = append(b, "null"...)
+	} else {
+		%s	}
+`, f.name, code)
+}
+
+// writeUnmarshalJSON writes the UnmarshalJSON
+// and readJSON methods of t.
+func writeUnmarshalJSON(b *bytes.Buffer, t *typ) {
+	fieldsVar := strings.ToLower(t.name[:1]) + t.name[1:] + "JSONFields"
+	names := []string{}
+	for _, f := range t.fields {
+		names = append(names, fmt.Sprintf("%q", f.jsonName))
+	}
+
+	fmt.Fprintf(b, `
+// UnmarshalJSON decodes the %[1]s from JSON
+// without reflection (see fast_json.go).
+func (v *%[1]s) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias %[1]s
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// %[2]s are the keys
+// of the fields of %[1]s.
+var %[2]s = []string{
+	%[3]s,
+}
+
+// readJSON reads the %[1]s from r.
+func (v *%[1]s) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+`, t.name, fieldsVar, strings.Join(names, ",\n\t"))
+
+	for _, f := range t.fields {
+		fmt.Fprintf(b, "\t\tcase %q:\n", f.jsonName)
+		writeUnmarshalValue(b, f)
+	}
+
+	fmt.Fprintf(b, `		default:
+			return r.skipMember(key, %s)
+		}
+	})
+}
+`, fieldsVar)
+}
+
+// writeUnmarshalValue writes the code
+// that reads the value of f from r.
+func writeUnmarshalValue(b *bytes.Buffer, f *field) {
+	// read is the code that reads the value into x (and sets
+	// ok to true if it was read) and conversion converts x to
+	// the type of the field.
+	read := "x, ok := r.readString()"
+	conversion := "x"
+	if size := jsonInts[f.goType]; size > 0 {
+		read = fmt.Sprintf("x, ok := r.readInt(%d)", size)
+		if f.goType != "int64" {
+			conversion = fmt.Sprintf("%s(x)", f.goType)
+		}
+	} else if f.enum {
+		conversion = fmt.Sprintf("%s(x)", f.goType)
+	}
+
+	// Like encoding/json, null does not modify values
+	// and sets pointers, slices, and maps to nil. Pointers
+	// and slices that are not nil are reused.
+	switch f.kind {
+	case valueKind:
+		fmt.Fprintf(b, `			if r.readNull() {
+				return true
+			}
+
+			%[2]s
+			if ok {
+				v.%[1]s = %[3]s
+			}
+
+			return ok
+`, f.name, read, conversion)
+	case pointerKind:
+		fmt.Fprintf(b, `			if r.readNull() {
+				v.%[1]s = nil
+				return true
+			}
+
+			%[2]s
+			if ok {
+				if v.%[1]s == nil {
+					v.%[1]s = new(%[4]s)
+				}
+				*v.%[1]s = %[3]s
+			}
+
+			return ok
+`, f.name, read, conversion, f.goType)
+	case structKind:
+		fmt.Fprintf(b, `			if r.readNull() {
+				v.%[1]s = nil
+				return true
+			}
+
+			if v.%[1]s == nil {
+				v.%[1]s = &%[2]s{}
+			}
+
+			return v.%[1]s.readJSON(r)
+`, f.name, f.goType)
+	case structsKind:
+		fmt.Fprintf(b, `			if r.readNull() {
+				v.%[1]s = nil
+				return true
+			}
+
+			s := v.%[1]s[:0]
+			ok := r.readArray(func() bool {
+				i := len(s)
+				if i < cap(s) {
+					s = s[:i+1]
+				} else {
+					s = append(s, nil)
+				}
+
+				if r.readNull() {
+					s[i] = nil
+					return true
+				}
+
+				if s[i] == nil {
+					s[i] = &%[2]s{}
+				}
+
+				return s[i].readJSON(r)
+			})
+			if s == nil {
+				s = []*%[2]s{}
+			}
+
+			v.%[1]s = s
+			return ok
+`, f.name, f.goType)
+	case metadataKind:
+		fmt.Fprintf(b, "\t\t\treturn r.readMetadata(&v.%s)\n", f.name)
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// typegen generates types/deep_gen.go (a Clone and an Equal
+// method for each struct in the types package),
+// types/fast_json_gen.go (MarshalJSON methods for the structs
+// that are encoded most often), and
+// types/fast_json_unmarshal_gen.go (UnmarshalJSON methods for
+// the same structs, built with the fastjson build tag). It is
+// invoked by codegen.sh after the types package is generated.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const license = `// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+`
+
+// kind is the kind of a field of a struct
+// in the types package.
+type kind int
+
+const (
+	valueKind kind = iota
+	pointerKind
+	bytesKind
+	stringsKind
+	structKind
+	structsKind
+	metadataKind
+)
+
+// field is a field of a struct in
+// the types package.
+type field struct {
+	name string
+	kind kind
+
+	// goType is the type of the field (or the
+	// type it points to) in the types package.
+	goType string
+
+	// enum is true if goType is a string
+	// enumeration (ex: CoinAction).
+	enum bool
+
+	jsonName  string
+	omitEmpty bool
+}
+
+// typ is a struct in the types package.
+type typ struct {
+	name   string
+	fields []*field
+
+	// customJSON is true if the struct has a hand-written
+	// MarshalJSON or UnmarshalJSON method.
+	customJSON bool
+}
+
+func main() {
+	typesDir := flag.String("types", "types", "directory of the types package")
+	outPath := flag.String("out", "types/deep_gen.go", "path of the generated methods")
+	jsonOutPath := flag.String(
+		"json-out",
+		"types/fast_json_gen.go",
+		"path of the generated MarshalJSON methods",
+	)
+	jsonUnmarshalOutPath := flag.String(
+		"json-unmarshal-out",
+		"types/fast_json_unmarshal_gen.go",
+		"path of the generated UnmarshalJSON methods",
+	)
+	jsonTypes := flag.String(
+		"json-types",
+		"Block",
+		"comma-separated structs (and the structs they contain) to generate JSON methods for",
+	)
+	flag.Parse()
+
+	types, err := parseTypes(*typesDir)
+	if err != nil {
+		log.Fatalf("unable to parse types: %s", err.Error())
+	}
+
+	writeSource(*outPath, generate(types))
+
+	codecs, err := jsonClosure(types, strings.Split(*jsonTypes, ","))
+	if err != nil {
+		log.Fatalf("unable to generate JSON methods: %s", err.Error())
+	}
+
+	writeSource(*jsonOutPath, generateMarshalJSON(codecs))
+	writeSource(*jsonUnmarshalOutPath, generateUnmarshalJSON(codecs))
+}
+
+// writeSource formats and writes
+// Go source code to path.
+func writeSource(path string, source []byte) {
+	formatted, err := format.Source(source)
+	if err != nil {
+		log.Fatalf("unable to format %s: %s", path, err.Error())
+	}
+
+	if err := ioutil.WriteFile(path, formatted, 0600); err != nil {
+		log.Fatalf("unable to write %s: %s", path, err.Error())
+	}
+}
+
+// parseTypes returns a *typ for each exported
+// struct in the types package that is encoded
+// in JSON (sorted by name).
+func parseTypes(dir string) ([]*typ, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, ok := pkgs["types"]
+	if !ok {
+		return nil, fmt.Errorf("types package not found in %s", dir)
+	}
+
+	structs := map[string]*ast.StructType{}
+	enums := map[string]bool{}
+	customJSON := map[string]bool{}
+	for path, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			if method, ok := decl.(*ast.FuncDecl); ok {
+				if jsonMethod(method) && !strings.HasSuffix(path, "_gen.go") {
+					customJSON[receiverName(method)] = true
+				}
+
+				continue
+			}
+
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if ident, ok := typeSpec.Type.(*ast.Ident); ok && ident.Name == "string" {
+					enums[typeSpec.Name.Name] = true
+					continue
+				}
+
+				s, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || !typeSpec.Name.IsExported() || !rosettaType(s) {
+					continue
+				}
+
+				structs[typeSpec.Name.Name] = s
+			}
+		}
+	}
+
+	types := []*typ{}
+	for name, s := range structs {
+		t := &typ{name: name, customJSON: customJSON[name]}
+		for _, f := range s.Fields.List {
+			if len(f.Names) == 0 {
+				return nil, fmt.Errorf("%s has an embedded field", name)
+			}
+
+			parsed, err := parseField(f, structs, enums)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, f.Names[0].Name, err)
+			}
+
+			t.fields = append(t.fields, parsed)
+		}
+
+		types = append(types, t)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].name < types[j].name
+	})
+
+	return types, nil
+}
+
+// rosettaType returns true if s is a Rosetta type
+// (all of its fields are encoded in JSON).
+func rosettaType(s *ast.StructType) bool {
+	for _, f := range s.Fields.List {
+		if f.Tag == nil || !strings.Contains(f.Tag.Value, `json:"`) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonMethod returns true if method is a
+// MarshalJSON or UnmarshalJSON method.
+func jsonMethod(method *ast.FuncDecl) bool {
+	return method.Recv != nil &&
+		(method.Name.Name == "MarshalJSON" || method.Name.Name == "UnmarshalJSON")
+}
+
+// receiverName returns the name of the
+// type of the receiver of method.
+func receiverName(method *ast.FuncDecl) string {
+	recv := method.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// parseField returns the *field for
+// a field of a struct.
+func parseField(
+	f *ast.Field,
+	structs map[string]*ast.StructType,
+	enums map[string]bool,
+) (*field, error) {
+	parsed := &field{name: f.Names[0].Name}
+
+	tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("json")
+	parsed.jsonName = strings.Split(tag, ",")[0]
+	parsed.omitEmpty = strings.Contains(tag, ",omitempty")
+
+	switch t := f.Type.(type) {
+	case *ast.Ident:
+		// Scalars and enumerations are copied
+		// and compared by value.
+		parsed.kind = valueKind
+		parsed.goType = t.Name
+		parsed.enum = enums[t.Name]
+		return parsed, nil
+	case *ast.StarExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+
+		parsed.goType = ident.Name
+		parsed.enum = enums[ident.Name]
+		if _, ok := structs[ident.Name]; ok {
+			parsed.kind = structKind
+		} else {
+			parsed.kind = pointerKind
+		}
+
+		return parsed, nil
+	case *ast.ArrayType:
+		switch elt := t.Elt.(type) {
+		case *ast.Ident:
+			if elt.Name == "byte" {
+				parsed.kind = bytesKind
+				return parsed, nil
+			}
+
+			if elt.Name == "string" {
+				parsed.kind = stringsKind
+				return parsed, nil
+			}
+		case *ast.StarExpr:
+			ident, ok := elt.X.(*ast.Ident)
+			if !ok {
+				break
+			}
+
+			if _, ok := structs[ident.Name]; ok {
+				parsed.kind = structsKind
+				parsed.goType = ident.Name
+				return parsed, nil
+			}
+		}
+	case *ast.MapType:
+		key, keyOk := t.Key.(*ast.Ident)
+		value, valueOk := t.Value.(*ast.InterfaceType)
+		if keyOk && valueOk && key.Name == "string" && len(value.Methods.List) == 0 {
+			parsed.kind = metadataKind
+			return parsed, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type %T", f.Type)
+}
+
+// generate returns the contents of deep_gen.go.
+func generate(types []*typ) []byte {
+	var b bytes.Buffer
+	b.WriteString(license)
+	b.WriteString(`
+// Code generated by internal/typegen. DO NOT EDIT.
+
+package types
+
+import (
+	"bytes"
+)
+`)
+
+	for _, t := range types {
+		writeClone(&b, t)
+		writeEqual(&b, t)
+	}
+
+	return b.Bytes()
+}
+
+// writeClone writes the Clone method of t.
+func writeClone(b *bytes.Buffer, t *typ) {
+	fmt.Fprintf(b, `
+// Clone returns a deep copy of the %[1]s.
+func (v *%[1]s) Clone() *%[1]s {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+`, t.name)
+
+	for _, f := range t.fields {
+		switch f.kind {
+		case pointerKind:
+			fmt.Fprintf(b, `	if v.%[1]s != nil {
+		x := *v.%[1]s
+		c.%[1]s = &x
+	}
+`, f.name)
+		case bytesKind:
+			fmt.Fprintf(b, `	if v.%[1]s != nil {
+		c.%[1]s = append([]byte{}, v.%[1]s...)
+	}
+`, f.name)
+		case stringsKind:
+			fmt.Fprintf(b, `	if v.%[1]s != nil {
+		c.%[1]s = append([]string{}, v.%[1]s...)
+	}
+`, f.name)
+		case structKind:
+			fmt.Fprintf(b, "\tc.%[1]s = v.%[1]s.Clone()\n", f.name)
+		case structsKind:
+			fmt.Fprintf(b, `	if v.%[1]s != nil {
+		c.%[1]s = make([]*%[2]s, len(v.%[1]s))
+		for i, x := range v.%[1]s {
+			c.%[1]s[i] = x.Clone()
+		}
+	}
+`, f.name, f.goType)
+		case metadataKind:
+			fmt.Fprintf(b, "\tc.%[1]s = cloneMetadata(v.%[1]s)\n", f.name)
+		}
+	}
+
+	b.WriteString("\n\treturn &c\n}\n")
+}
+
+// writeEqual writes the Equal method of t.
+func writeEqual(b *bytes.Buffer, t *typ) {
+	fmt.Fprintf(b, `
+// Equal returns true if the %[1]s is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *%[1]s) Equal(o *%[1]s) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+`, t.name)
+
+	for _, f := range t.fields {
+		b.WriteString("\n")
+		switch f.kind {
+		case valueKind:
+			fmt.Fprintf(b, `	if v.%[1]s != o.%[1]s {
+		return false
+	}
+`, f.name)
+		case pointerKind:
+			fmt.Fprintf(b, `	if (v.%[1]s == nil) != (o.%[1]s == nil) ||
+		(v.%[1]s != nil && *v.%[1]s != *o.%[1]s) {
+		return false
+	}
+`, f.name)
+		case bytesKind:
+			fmt.Fprintf(b, `	if !bytes.Equal(v.%[1]s, o.%[1]s) {
+		return false
+	}
+`, f.name)
+		case stringsKind, structsKind:
+			comparison := "v.%[1]s[i] != o.%[1]s[i]"
+			if f.kind == structsKind {
+				comparison = "!v.%[1]s[i].Equal(o.%[1]s[i])"
+			}
+
+			fmt.Fprintf(b, `	if len(v.%[1]s) != len(o.%[1]s) {
+		return false
+	}
+
+	for i := range v.%[1]s {
+		if `+comparison+` {
+			return false
+		}
+	}
+`, f.name)
+		case structKind:
+			fmt.Fprintf(b, `	if !v.%[1]s.Equal(o.%[1]s) {
+		return false
+	}
+`, f.name)
+		case metadataKind:
+			fmt.Fprintf(b, `	if !MetadataEqual(v.%[1]s, o.%[1]s) {
+		return false
+	}
+`, f.name)
+		}
+	}
+
+	b.WriteString("\n\treturn true\n}\n")
+}
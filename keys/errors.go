@@ -36,7 +36,26 @@ var (
 	ErrKeyGenSecp256r1Failed = errors.New(
 		"keygen: error generating key pair for secp256r1 curve type",
 	)
+	ErrKeyGenMaxAttemptsExceeded = errors.New(
+		"keygen: unable to find a valid private key from entropy source",
+	)
+
 	ErrCurveTypeNotSupported = errors.New("not a supported CurveType")
+	ErrCurveTypeNotApproved  = errors.New(
+		"CurveType is not approved in FIPS mode",
+	)
+	ErrSignatureTypeNotApproved = errors.New(
+		"SignatureType is not approved in FIPS mode",
+	)
+
+	ErrExtendedKeyLengthInvalid = errors.New("invalid extended key length")
+	ErrExtendedKeyNotClamped    = errors.New(
+		"extended privkey is not a valid BIP32-Ed25519 key",
+	)
+	ErrExtendedKeyPublicOnly = errors.New(
+		"operation requires an extended privkey",
+	)
+	ErrChainCodeLengthInvalid = errors.New("invalid chain code length")
 
 	ErrSignUnsupportedPayloadSignatureType = errors.New(
 		"sign: unexpected payload.SignatureType while signing",
@@ -67,6 +86,13 @@ func Err(err error) bool {
 		ErrKeyGenSecp256r1Failed,
 		ErrKeyGenEdwards25519Failed,
 		ErrCurveTypeNotSupported,
+		ErrKeyGenMaxAttemptsExceeded,
+		ErrCurveTypeNotApproved,
+		ErrSignatureTypeNotApproved,
+		ErrExtendedKeyLengthInvalid,
+		ErrExtendedKeyNotClamped,
+		ErrExtendedKeyPublicOnly,
+		ErrChainCodeLengthInvalid,
 		ErrSignUnsupportedPayloadSignatureType,
 		ErrSignUnsupportedSignatureType,
 		ErrSignFailed,
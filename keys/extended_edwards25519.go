@@ -0,0 +1,335 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// This file implements BIP32-Ed25519 (as described in
+// "BIP32-Ed25519: Hierarchical Deterministic Keys over a
+// Non-linear Keyspace") using the V2 derivation scheme
+// and Icarus master key generation adopted by Cardano.
+//
+// See: https://input-output-hk.github.io/adrestia/static/Ed25519_BIP.pdf
+
+const (
+	// ExtendedPrivKeyBytesLen is the length of an extended
+	// edwards25519 private key (kL || kR).
+	ExtendedPrivKeyBytesLen = 64
+
+	// ChainCodeBytesLen is the length of a BIP32-Ed25519 chain code.
+	ChainCodeBytesLen = 32
+
+	// HardenedKeyStart is the first hardened child index.
+	HardenedKeyStart uint32 = 0x80000000
+
+	// CIP1852Purpose is the purpose used in CIP-1852 derivation paths.
+	CIP1852Purpose uint32 = 1852
+
+	// CardanoCoinType is the SLIP-44 coin type of Cardano.
+	CardanoCoinType uint32 = 1815
+
+	// icarusIterations is the number of PBKDF2 iterations
+	// used to generate an Icarus master key.
+	icarusIterations = 4096
+)
+
+// ExtendedKey is a BIP32-Ed25519 extended key. If PrivateKey
+// is nil, the ExtendedKey can only be used for non-hardened
+// (soft) public derivation.
+type ExtendedKey struct {
+	PublicKey  *types.PublicKey
+	PrivateKey []byte
+	ChainCode  []byte
+}
+
+// NewExtendedKey returns an ExtendedKey from a 96-byte
+// extended private key (kL || kR || chain code).
+func NewExtendedKey(xprv []byte) (*ExtendedKey, error) {
+	if len(xprv) != ExtendedPrivKeyBytesLen+ChainCodeBytesLen {
+		return nil, fmt.Errorf(
+			"%w: expected %d bytes but got %d",
+			ErrExtendedKeyLengthInvalid,
+			ExtendedPrivKeyBytesLen+ChainCodeBytesLen,
+			len(xprv),
+		)
+	}
+
+	privKey := make([]byte, ExtendedPrivKeyBytesLen)
+	copy(privKey, xprv[:ExtendedPrivKeyBytesLen])
+	chainCode := make([]byte, ChainCodeBytesLen)
+	copy(chainCode, xprv[ExtendedPrivKeyBytesLen:])
+
+	if !extendedPrivateKeyClamped(privKey) {
+		return nil, ErrExtendedKeyNotClamped
+	}
+
+	pubKey, err := extendedPublicKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendedKey{
+		PublicKey:  pubKey,
+		PrivateKey: privKey,
+		ChainCode:  chainCode,
+	}, nil
+}
+
+// NewExtendedKeyFromEntropy returns the master ExtendedKey
+// for some BIP-39 entropy (not the mnemonic) and an optional
+// password using the Icarus scheme (used by Cardano wallets).
+func NewExtendedKeyFromEntropy(entropy []byte, password []byte) (*ExtendedKey, error) {
+	xprv := pbkdf2.Key(
+		password,
+		entropy,
+		icarusIterations,
+		ExtendedPrivKeyBytesLen+ChainCodeBytesLen,
+		sha512.New,
+	)
+	clampExtendedPrivateKey(xprv)
+
+	return NewExtendedKey(xprv)
+}
+
+// Bytes returns the 96-byte extended private key
+// (kL || kR || chain code).
+func (k *ExtendedKey) Bytes() ([]byte, error) {
+	if k.PrivateKey == nil {
+		return nil, ErrExtendedKeyPublicOnly
+	}
+
+	b := make([]byte, 0, ExtendedPrivKeyBytesLen+ChainCodeBytesLen)
+	b = append(b, k.PrivateKey...)
+	return append(b, k.ChainCode...), nil
+}
+
+// Public returns a copy of the ExtendedKey without
+// its PrivateKey.
+func (k *ExtendedKey) Public() *ExtendedKey {
+	return &ExtendedKey{
+		PublicKey: k.PublicKey,
+		ChainCode: k.ChainCode,
+	}
+}
+
+// Derive returns the child ExtendedKey at index. Indexes
+// greater than or equal to HardenedKeyStart are hardened and
+// can only be derived from an ExtendedKey with a PrivateKey.
+func (k *ExtendedKey) Derive(index uint32) (*ExtendedKey, error) {
+	if len(k.ChainCode) != ChainCodeBytesLen {
+		return nil, fmt.Errorf(
+			"%w: expected %d bytes but got %d",
+			ErrChainCodeLengthInvalid,
+			ChainCodeBytesLen,
+			len(k.ChainCode),
+		)
+	}
+
+	if k.PrivateKey == nil {
+		return k.derivePublic(index)
+	}
+
+	return k.derivePrivate(index)
+}
+
+// DerivePath derives the ExtendedKey at each index in path,
+// starting from k.
+func (k *ExtendedKey) DerivePath(path []uint32) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+
+		key = child
+	}
+
+	return key, nil
+}
+
+// Signer returns a Signer for the ExtendedKey.
+func (k *ExtendedKey) Signer() (Signer, error) {
+	if err := assertCurveTypeAllowed(types.Edwards25519); err != nil {
+		return nil, err
+	}
+
+	if k.PrivateKey == nil {
+		return nil, ErrExtendedKeyPublicOnly
+	}
+
+	return &SignerEdwards25519Extended{ExtendedKey: k}, nil
+}
+
+// CIP1852Path returns the CIP-1852 derivation path
+// m/1852'/1815'/account'/role/index.
+func CIP1852Path(account uint32, role uint32, index uint32) []uint32 {
+	return []uint32{
+		HardenedKeyStart + CIP1852Purpose,
+		HardenedKeyStart + CardanoCoinType,
+		HardenedKeyStart + account,
+		role,
+		index,
+	}
+}
+
+func (k *ExtendedKey) derivePrivate(index uint32) (*ExtendedKey, error) {
+	var (
+		zPrefix  byte
+		ccPrefix byte
+		data     []byte
+	)
+	if index >= HardenedKeyStart {
+		zPrefix, ccPrefix = 0x00, 0x01
+		data = k.PrivateKey
+	} else {
+		zPrefix, ccPrefix = 0x02, 0x03
+		data = k.PublicKey.Bytes
+	}
+
+	z := childHMAC(k.ChainCode, zPrefix, data, index)
+	cc := childHMAC(k.ChainCode, ccPrefix, data, index)
+
+	kl, kr := k.PrivateKey[:32], k.PrivateKey[32:]
+	childPrivKey := append(add28Mul8(kl, z[:28]), add256(kr, z[32:])...)
+
+	pubKey, err := extendedPublicKey(childPrivKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendedKey{
+		PublicKey:  pubKey,
+		PrivateKey: childPrivKey,
+		ChainCode:  cc[32:],
+	}, nil
+}
+
+func (k *ExtendedKey) derivePublic(index uint32) (*ExtendedKey, error) {
+	if index >= HardenedKeyStart {
+		return nil, ErrExtendedKeyPublicOnly
+	}
+
+	z := childHMAC(k.ChainCode, 0x02, k.PublicKey.Bytes, index)
+	cc := childHMAC(k.ChainCode, 0x03, k.PublicKey.Bytes, index)
+
+	parent, err := new(edwards25519.Point).SetBytes(k.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPubKeyNotOnCurve, err)
+	}
+
+	tweak, err := scalarFromBytes(add28Mul8(make([]byte, 32), z[:28]))
+	if err != nil {
+		return nil, err
+	}
+
+	child := new(edwards25519.Point).ScalarBaseMult(tweak)
+	child.Add(parent, child)
+
+	return &ExtendedKey{
+		PublicKey: &types.PublicKey{
+			Bytes:     child.Bytes(),
+			CurveType: types.Edwards25519,
+		},
+		ChainCode: cc[32:],
+	}, nil
+}
+
+// childHMAC computes HMAC-SHA512(chainCode, prefix || data || index)
+// where index is serialized as little-endian.
+func childHMAC(chainCode []byte, prefix byte, data []byte, index uint32) []byte {
+	indexBytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(indexBytes, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write([]byte{prefix}) // nolint:errcheck
+	mac.Write(data)           // nolint:errcheck
+	mac.Write(indexBytes)     // nolint:errcheck
+	return mac.Sum(nil)
+}
+
+// add28Mul8 returns x + 8*y where x is 32 bytes and y is 28
+// bytes, both interpreted as little-endian integers.
+func add28Mul8(x []byte, y []byte) []byte {
+	out := make([]byte, 32)
+	var carry uint16
+	for i := 0; i < 28; i++ {
+		r := uint16(x[i]) + uint16(y[i])<<3 + carry
+		out[i] = byte(r)
+		carry = r >> 8
+	}
+
+	for i := 28; i < 32; i++ {
+		r := uint16(x[i]) + carry
+		out[i] = byte(r)
+		carry = r >> 8
+	}
+
+	return out
+}
+
+// add256 returns (x + y) mod 2^256 where x and y are
+// 32 bytes interpreted as little-endian integers.
+func add256(x []byte, y []byte) []byte {
+	out := make([]byte, 32)
+	var carry uint16
+	for i := 0; i < 32; i++ {
+		r := uint16(x[i]) + uint16(y[i]) + carry
+		out[i] = byte(r)
+		carry = r >> 8
+	}
+
+	return out
+}
+
+// scalarFromBytes reduces a 32-byte little-endian
+// integer modulo the group order.
+func scalarFromBytes(b []byte) (*edwards25519.Scalar, error) {
+	wide := make([]byte, 64)
+	copy(wide, b)
+	return edwards25519.NewScalar().SetUniformBytes(wide)
+}
+
+func clampExtendedPrivateKey(privKey []byte) {
+	privKey[0] &= 0xf8
+	privKey[31] &= 0x1f
+	privKey[31] |= 0x40
+}
+
+func extendedPrivateKeyClamped(privKey []byte) bool {
+	return privKey[0]&0x07 == 0 && privKey[31]&0xc0 == 0x40
+}
+
+func extendedPublicKey(privKey []byte) (*types.PublicKey, error) {
+	s, err := scalarFromBytes(privKey[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PublicKey{
+		Bytes:     new(edwards25519.Point).ScalarBaseMult(s).Bytes(),
+		CurveType: types.Edwards25519,
+	}, nil
+}
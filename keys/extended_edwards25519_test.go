@@ -0,0 +1,209 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func mockExtendedKey(t *testing.T) *ExtendedKey {
+	entropy := make([]byte, 16)
+	for i := range entropy {
+		entropy[i] = byte(i)
+	}
+
+	key, err := NewExtendedKeyFromEntropy(entropy, nil)
+	assert.NoError(t, err)
+
+	return key
+}
+
+func TestNewExtendedKey(t *testing.T) {
+	key := mockExtendedKey(t)
+	assert.Len(t, key.PrivateKey, ExtendedPrivKeyBytesLen)
+	assert.Len(t, key.ChainCode, ChainCodeBytesLen)
+	assert.Equal(t, types.Edwards25519, key.PublicKey.CurveType)
+
+	xprv, err := key.Bytes()
+	assert.NoError(t, err)
+
+	imported, err := NewExtendedKey(xprv)
+	assert.NoError(t, err)
+	assert.Equal(t, key, imported)
+
+	_, err = NewExtendedKey(xprv[:95])
+	assert.True(t, errors.Is(err, ErrExtendedKeyLengthInvalid))
+
+	xprv[31] |= 0x80
+	_, err = NewExtendedKey(xprv)
+	assert.True(t, errors.Is(err, ErrExtendedKeyNotClamped))
+
+	_, err = key.Public().Bytes()
+	assert.True(t, errors.Is(err, ErrExtendedKeyPublicOnly))
+}
+
+func TestNewExtendedKeyFromEntropyVectors(t *testing.T) {
+	// Test vectors from CIP-0003 (Icarus master key generation)
+	// for the mnemonic "eight country switch draw meat scout
+	// mystery blade tip drift useless good keep usage title".
+	entropy, err := hex.DecodeString("46e62370a138a182a498b8e2885bc032379ddf38")
+	assert.NoError(t, err)
+
+	var tests = map[string]struct {
+		password string
+		xprv     string
+	}{
+		"no password": {
+			xprv: "c065afd2832cd8b087c4d9ab7011f481ee1e0721e78ea5dd609f3ab3f156d245" +
+				"d176bd8fd4ec60b4731c3918a2a72a0226c0cd119ec35b47e4d55884667f552a" +
+				"23f7fdcd4a10c6cd2c7393ac61d877873e248f417634aa3d812af327ffe9d620",
+		},
+		"password": {
+			password: "foo",
+			xprv: "70531039904019351e1afb361cd1b312a4d0565d4ff9f8062d38acf4b15cce41" +
+				"d7b5738d9c893feea55512a3004acb0d222c35d3e3d5cde943a15a9824cbac59" +
+				"443cf67e589614076ba01e354b1a432e0e6db3b59e37fc56b5fb0222970a010e",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := NewExtendedKeyFromEntropy(entropy, []byte(test.password))
+			assert.NoError(t, err)
+
+			xprv, err := key.Bytes()
+			assert.NoError(t, err)
+			assert.Equal(t, test.xprv, hex.EncodeToString(xprv))
+		})
+	}
+}
+
+func TestExtendedKeyDeriveVectors(t *testing.T) {
+	// Test vector from the BIP32-Ed25519 (V2 derivation) test
+	// suite of the Cardano hdwallet implementation.
+	xprv, err := hex.DecodeString(
+		"f8a29231ee38d6c5bf715d5bac21c750577aa3798b22d79d65bf97d6fadea15a" +
+			"dcd1ee1abdf78bd4be64731a12deb94d3671784112eb6f364b871851fd1c9a24" +
+			"7384db9ad6003bbd08b3b1ddc0d07a597293ff85e961bf252b331262eddfad0d",
+	)
+	assert.NoError(t, err)
+
+	key, err := NewExtendedKey(xprv)
+	assert.NoError(t, err)
+
+	child, err := key.Derive(HardenedKeyStart)
+	assert.NoError(t, err)
+
+	childXprv, err := child.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"60d399da83ef80d8d4f8d223239efdc2b8fef387e1b5219137ffb4e8fbdea15a"+
+			"dc9366b7d003af37c11396de9a83734e30e05e851efa32745c9cd7b42712c890"+
+			"608763770eddf77248ab652984b21b849760d1da74a6f5bd633ce41adceef07a",
+		hex.EncodeToString(childXprv),
+	)
+}
+
+func TestExtendedKeyDerive(t *testing.T) {
+	key := mockExtendedKey(t)
+
+	account, err := key.DerivePath(CIP1852Path(0, 0, 0)[:3])
+	assert.NoError(t, err)
+
+	// Soft derivation from the public key must match
+	// soft derivation from the private key.
+	fromPrivate, err := account.DerivePath([]uint32{0, 5})
+	assert.NoError(t, err)
+
+	fromPublic, err := account.Public().DerivePath([]uint32{0, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, fromPrivate.PublicKey, fromPublic.PublicKey)
+	assert.Equal(t, fromPrivate.ChainCode, fromPublic.ChainCode)
+
+	full, err := key.DerivePath(CIP1852Path(0, 0, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, fromPrivate, full)
+
+	// Hardened derivation requires the private key.
+	_, err = account.Public().Derive(HardenedKeyStart)
+	assert.True(t, errors.Is(err, ErrExtendedKeyPublicOnly))
+
+	// Derived keys must remain valid BIP32-Ed25519 keys.
+	xprv, err := full.Bytes()
+	assert.NoError(t, err)
+	_, err = NewExtendedKey(xprv)
+	assert.NoError(t, err)
+}
+
+func TestSignerEdwards25519Extended(t *testing.T) {
+	// An extended key built from the SHA-512 hash of an
+	// ed25519 seed must produce the same public key and
+	// signatures as the seed.
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	digest := sha512.Sum512(seed)
+	digest[0] &= 0xf8
+	digest[31] &= 0x7f
+	digest[31] |= 0x40
+
+	extended, err := NewExtendedKey(append(digest[:], make([]byte, ChainCodeBytesLen)...))
+	assert.NoError(t, err)
+
+	standard := ed25519.NewKeyFromSeed(seed)
+	assert.Equal(t, []byte(standard.Public().(ed25519.PublicKey)), extended.PublicKey.Bytes)
+
+	signer, err := extended.Signer()
+	assert.NoError(t, err)
+
+	payload := &types.SigningPayload{
+		AccountIdentifier: &types.AccountIdentifier{Address: "test"},
+		Bytes:             []byte("hello"),
+		SignatureType:     types.Ed25519,
+	}
+	signature, err := signer.Sign(payload, types.Ed25519)
+	assert.NoError(t, err)
+	assert.Equal(t, ed25519.Sign(standard, payload.Bytes), signature.Bytes)
+	assert.NoError(t, signer.Verify(signature))
+
+	// Signatures from derived keys must verify.
+	child, err := mockExtendedKey(t).DerivePath(CIP1852Path(0, 0, 0))
+	assert.NoError(t, err)
+
+	signer, err = child.Signer()
+	assert.NoError(t, err)
+
+	signature, err = signer.Sign(payload, types.Ed25519)
+	assert.NoError(t, err)
+	assert.NoError(t, signer.Verify(signature))
+	assert.True(t, ed25519.Verify(child.PublicKey.Bytes, payload.Bytes, signature.Bytes))
+
+	_, err = signer.Sign(payload, types.Ecdsa)
+	assert.True(t, errors.Is(err, ErrSignUnsupportedSignatureType))
+
+	_, err = child.Public().Signer()
+	assert.True(t, errors.Is(err, ErrExtendedKeyPublicOnly))
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// approvedCurveTypes are the CurveTypes that may be used
+// when FIPSMode is enabled. Ed25519 is approved as of
+// FIPS 186-5.
+var approvedCurveTypes = map[types.CurveType]struct{}{
+	types.Secp256r1:    {},
+	types.Edwards25519: {},
+}
+
+// approvedSignatureTypes are the SignatureTypes that may be used
+// when FIPSMode is enabled.
+var approvedSignatureTypes = map[types.SignatureType]struct{}{
+	types.Ecdsa:   {},
+	types.Ed25519: {},
+}
+
+// assertCurveTypeAllowed returns an error if FIPSMode
+// is enabled and the provided CurveType is not approved.
+func assertCurveTypeAllowed(curve types.CurveType) error {
+	if !FIPSMode {
+		return nil
+	}
+
+	if _, ok := approvedCurveTypes[curve]; !ok {
+		return fmt.Errorf("%w: %s", ErrCurveTypeNotApproved, curve)
+	}
+
+	return nil
+}
+
+// assertSignatureTypeAllowed returns an error if FIPSMode
+// is enabled and the provided SignatureType is not approved.
+func assertSignatureTypeAllowed(sigType types.SignatureType) error {
+	if !FIPSMode {
+		return nil
+	}
+
+	if _, ok := approvedSignatureTypes[sigType]; !ok {
+		return fmt.Errorf("%w: %s", ErrSignatureTypeNotApproved, sigType)
+	}
+
+	return nil
+}
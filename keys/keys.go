@@ -15,12 +15,12 @@
 package keys
 
 import (
-	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -32,6 +32,13 @@ import (
 // PrivKeyBytesLen are 32-bytes for all supported curvetypes
 const PrivKeyBytesLen = 32
 
+// maxKeyGenAttempts is the number of candidate private keys
+// we will read from an entropy source before giving up. The
+// probability of a uniformly random candidate being rejected
+// is negligible, so exceeding this almost certainly indicates
+// a broken entropy source.
+const maxKeyGenAttempts = 128
+
 func privateKeyValid(privateKey []byte) error {
 	// We will need to add a switch statement here if we add support
 	// for CurveTypes that have a different private key length than
@@ -59,6 +66,15 @@ func ImportPrivateKey(privKeyHex string, curve types.CurveType) (*KeyPair, error
 		return nil, fmt.Errorf("%w: %s", ErrPrivKeyUndecodable, privKeyHex)
 	}
 
+	return importPrivateKey(privKey, curve)
+}
+
+// importPrivateKey returns a Keypair from raw privkey bytes.
+func importPrivateKey(privKey []byte, curve types.CurveType) (*KeyPair, error) {
+	if err := assertCurveTypeAllowed(curve); err != nil {
+		return nil, err
+	}
+
 	// We check the parsed private key length to ensure we don't panic (most
 	// crypto libraries panic with incorrect private key lengths instead of
 	// throwing an error).
@@ -103,20 +119,17 @@ func ImportPrivateKey(privKeyHex string, curve types.CurveType) (*KeyPair, error
 			return nil, ErrPubKeyNotOnCurve
 		}
 
-		rawPubKey := ecdsa.PublicKey{X: x, Y: y, Curve: crv}
-		rawPrivKey := ecdsa.PrivateKey{
-			PublicKey: rawPubKey,
-			D:         new(big.Int).SetBytes(privKey),
-		}
-
 		pubKey := &types.PublicKey{
-			Bytes:     elliptic.Marshal(crv, rawPubKey.X, rawPubKey.Y),
+			Bytes:     elliptic.Marshal(crv, x, y),
 			CurveType: curve,
 		}
 
+		// We use the provided bytes instead of re-serializing the
+		// scalar so that keys with leading zero bytes are not
+		// truncated.
 		keyPair = &KeyPair{
 			PublicKey:  pubKey,
-			PrivateKey: rawPrivKey.D.Bytes(),
+			PrivateKey: privKey,
 		}
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrCurveTypeNotSupported, curve)
@@ -133,67 +146,71 @@ func ImportPrivateKey(privKeyHex string, curve types.CurveType) (*KeyPair, error
 
 // GenerateKeypair returns a Keypair of a specified CurveType
 func GenerateKeypair(curve types.CurveType) (*KeyPair, error) {
-	var keyPair *KeyPair
+	return GenerateKeypairWithReader(curve, rand.Reader)
+}
 
+// GenerateKeypairWithReader returns a Keypair of a specified CurveType
+// using entropy read from the provided io.Reader. This can be used to
+// source entropy from an HSM or to generate deterministic keys in tests.
+//
+// The provided reader MUST be cryptographically secure when generating
+// keys that will hold real funds.
+func GenerateKeypairWithReader(curve types.CurveType, reader io.Reader) (*KeyPair, error) {
+	if err := assertCurveTypeAllowed(curve); err != nil {
+		return nil, err
+	}
+
+	var (
+		privKey []byte
+		err     error
+	)
 	switch curve {
 	case types.Secp256k1:
-		rawPrivKey, err := btcec.NewPrivateKey(btcec.S256())
+		privKey, err = randomScalar(reader, btcec.S256().N)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrKeyGenSecp256k1Failed, err)
 		}
-		rawPubKey := rawPrivKey.PubKey()
-
-		pubKey := &types.PublicKey{
-			Bytes:     rawPubKey.SerializeCompressed(),
-			CurveType: curve,
-		}
-
-		keyPair = &KeyPair{
-			PublicKey:  pubKey,
-			PrivateKey: rawPrivKey.Serialize(),
-		}
 	case types.Edwards25519:
-		rawPubKey, rawPrivKey, err := ed25519.GenerateKey(nil)
+		// Any non-zero 32-byte seed is a valid edwards25519 private key.
+		privKey, err = randomScalar(reader, nil)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrKeyGenEdwards25519Failed, err)
 		}
-
-		pubKey := &types.PublicKey{
-			Bytes:     rawPubKey,
-			CurveType: curve,
-		}
-
-		keyPair = &KeyPair{
-			PublicKey:  pubKey,
-			PrivateKey: rawPrivKey.Seed(),
-		}
 	case types.Secp256r1:
-		crv := elliptic.P256()
-		rawPrivKey, err := ecdsa.GenerateKey(crv, rand.Reader)
+		privKey, err = randomScalar(reader, elliptic.P256().Params().N)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrKeyGenSecp256r1Failed, err)
 		}
-		rawPubKey := rawPrivKey.PublicKey
-		pubKey := &types.PublicKey{
-			Bytes:     elliptic.Marshal(crv, rawPubKey.X, rawPubKey.Y),
-			CurveType: curve,
-		}
-
-		keyPair = &KeyPair{
-			PublicKey:  pubKey,
-			PrivateKey: rawPrivKey.D.Bytes(),
-		}
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrCurveTypeNotSupported, curve)
 	}
 
-	// We test for validity before returning
-	// the new KeyPair.
-	if err := keyPair.IsValid(); err != nil {
-		return nil, err
+	return importPrivateKey(privKey, curve)
+}
+
+// randomScalar reads PrivKeyBytesLen bytes from reader until
+// it finds a non-zero value that is less than order (if provided).
+// Candidates outside of this range are discarded (rejection
+// sampling) so that the resulting key is uniformly distributed.
+func randomScalar(reader io.Reader, order *big.Int) ([]byte, error) {
+	for i := 0; i < maxKeyGenAttempts; i++ {
+		candidate := make([]byte, PrivKeyBytesLen)
+		if _, err := io.ReadFull(reader, candidate); err != nil {
+			return nil, err
+		}
+
+		if asserter.BytesArrayZero(candidate) {
+			continue
+		}
+
+		if order != nil && new(big.Int).SetBytes(candidate).Cmp(order) >= 0 {
+			continue
+		}
+
+		return candidate, nil
 	}
 
-	return keyPair, nil
+	return nil, ErrKeyGenMaxAttemptsExceeded
 }
 
 // IsValid checks the validity of a KeyPair.
@@ -212,6 +229,10 @@ func (k *KeyPair) IsValid() error {
 // Signer returns the constructs a Signer
 // for the KeyPair.
 func (k *KeyPair) Signer() (Signer, error) {
+	if err := assertCurveTypeAllowed(k.PublicKey.CurveType); err != nil {
+		return nil, err
+	}
+
 	switch k.PublicKey.CurveType {
 	case types.Secp256k1:
 		return &SignerSecp256k1{k}, nil
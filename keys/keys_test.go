@@ -15,6 +15,7 @@
 package keys
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -152,3 +153,72 @@ func TestImportPrivateKey(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateKeypairWithReader(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x01}, PrivKeyBytesLen)
+	curves := []types.CurveType{types.Secp256k1, types.Secp256r1, types.Edwards25519}
+
+	for _, curve := range curves {
+		t.Run(string(curve), func(t *testing.T) {
+			if assertCurveTypeAllowed(curve) != nil {
+				t.Skip("curve not approved in FIPS mode")
+			}
+
+			kp1, err := GenerateKeypairWithReader(curve, bytes.NewReader(seed))
+			assert.NoError(t, err)
+			assert.NoError(t, kp1.IsValid())
+			assert.Equal(t, seed, kp1.PrivateKey)
+
+			kp2, err := GenerateKeypairWithReader(curve, bytes.NewReader(seed))
+			assert.NoError(t, err)
+			assert.Equal(t, kp1, kp2)
+		})
+	}
+
+	t.Run("skips invalid candidates", func(t *testing.T) {
+		if FIPSMode {
+			t.Skip("secp256k1 not approved in FIPS mode")
+		}
+
+		entropy := append(make([]byte, PrivKeyBytesLen), seed...)
+		kp, err := GenerateKeypairWithReader(types.Secp256k1, bytes.NewReader(entropy))
+		assert.NoError(t, err)
+		assert.Equal(t, seed, kp.PrivateKey)
+	})
+
+	t.Run("exhausted reader", func(t *testing.T) {
+		kp, err := GenerateKeypairWithReader(types.Edwards25519, bytes.NewReader(seed[:10]))
+		assert.Nil(t, kp)
+		assert.True(t, errors.Is(err, ErrKeyGenEdwards25519Failed))
+	})
+
+	t.Run("all zero reader", func(t *testing.T) {
+		zeros := make([]byte, PrivKeyBytesLen*maxKeyGenAttempts)
+		kp, err := GenerateKeypairWithReader(types.Edwards25519, bytes.NewReader(zeros))
+		assert.Nil(t, kp)
+		assert.True(t, errors.Is(err, ErrKeyGenEdwards25519Failed))
+		assert.Contains(t, err.Error(), ErrKeyGenMaxAttemptsExceeded.Error())
+	})
+}
+
+func TestFIPSMode(t *testing.T) {
+	_, err := GenerateKeypair(types.Secp256k1)
+	_, importErr := ImportPrivateKey(
+		"0b188af56b25d007fbc4bbf2176cd2a54d876ce4774bb5df38b7c83349405b7a",
+		types.Secp256k1,
+	)
+	sigErr := assertSignatureTypeAllowed(types.EcdsaRecovery)
+	if FIPSMode {
+		assert.True(t, errors.Is(err, ErrCurveTypeNotApproved))
+		assert.True(t, errors.Is(importErr, ErrCurveTypeNotApproved))
+		assert.True(t, errors.Is(sigErr, ErrSignatureTypeNotApproved))
+	} else {
+		assert.NoError(t, err)
+		assert.NoError(t, importErr)
+		assert.NoError(t, sigErr)
+	}
+
+	_, err = GenerateKeypair(types.Secp256r1)
+	assert.NoError(t, err)
+	assert.NoError(t, assertSignatureTypeAllowed(types.Ecdsa))
+}
@@ -39,6 +39,10 @@ func (s *SignerEdwards25519) Sign(
 	payload *types.SigningPayload,
 	sigType types.SignatureType,
 ) (*types.Signature, error) {
+	if err := assertSignatureTypeAllowed(sigType); err != nil {
+		return nil, err
+	}
+
 	err := s.KeyPair.IsValid()
 	if err != nil {
 		return nil, err
@@ -77,6 +81,10 @@ func (s *SignerEdwards25519) Sign(
 // Verify verifies a Signature, by checking the validity of a Signature,
 // the SigningPayload, and the PublicKey of the Signature.
 func (s *SignerEdwards25519) Verify(signature *types.Signature) error {
+	if err := assertSignatureTypeAllowed(signature.SignatureType); err != nil {
+		return err
+	}
+
 	if signature.SignatureType != types.Ed25519 {
 		return fmt.Errorf(
 			"%w: expected %v but got %v",
@@ -0,0 +1,132 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"crypto/sha512"
+	"fmt"
+
+	"filippo.io/edwards25519"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// SignerEdwards25519Extended is initialized from a BIP32-Ed25519
+// ExtendedKey. Signatures produced by this signer are regular
+// Ed25519 signatures.
+type SignerEdwards25519Extended struct {
+	ExtendedKey *ExtendedKey
+}
+
+var _ Signer = (*SignerEdwards25519Extended)(nil)
+
+// PublicKey returns the PublicKey of the signer
+func (s *SignerEdwards25519Extended) PublicKey() *types.PublicKey {
+	return s.ExtendedKey.PublicKey
+}
+
+// Sign arbitrary payloads using an ExtendedKey
+func (s *SignerEdwards25519Extended) Sign(
+	payload *types.SigningPayload,
+	sigType types.SignatureType,
+) (*types.Signature, error) {
+	if err := assertSignatureTypeAllowed(sigType); err != nil {
+		return nil, err
+	}
+
+	if len(s.ExtendedKey.PrivateKey) != ExtendedPrivKeyBytesLen {
+		return nil, fmt.Errorf(
+			"%w: expected %d bytes but got %d",
+			ErrExtendedKeyLengthInvalid,
+			ExtendedPrivKeyBytesLen,
+			len(s.ExtendedKey.PrivateKey),
+		)
+	}
+
+	if !(payload.SignatureType == types.Ed25519 || payload.SignatureType == "") {
+		return nil, fmt.Errorf(
+			"%w: expected %v but got %v",
+			ErrSignUnsupportedPayloadSignatureType,
+			types.Ed25519,
+			payload.SignatureType,
+		)
+	}
+
+	if sigType != types.Ed25519 {
+		return nil, fmt.Errorf(
+			"%w: expected %v but got %v",
+			ErrSignUnsupportedSignatureType,
+			types.Ed25519,
+			sigType,
+		)
+	}
+
+	sig, err := signExtended(
+		s.ExtendedKey.PrivateKey,
+		s.ExtendedKey.PublicKey.Bytes,
+		payload.Bytes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSignFailed, err.Error())
+	}
+
+	return &types.Signature{
+		SigningPayload: payload,
+		PublicKey:      s.ExtendedKey.PublicKey,
+		SignatureType:  payload.SignatureType,
+		Bytes:          sig,
+	}, nil
+}
+
+// Verify verifies a Signature, by checking the validity of a Signature,
+// the SigningPayload, and the PublicKey of the Signature.
+func (s *SignerEdwards25519Extended) Verify(signature *types.Signature) error {
+	// Signatures created with an extended key are
+	// indistinguishable from regular Ed25519 signatures.
+	return (&SignerEdwards25519{}).Verify(signature)
+}
+
+// signExtended performs Ed25519 signing where the
+// secret scalar (kL) and nonce prefix (kR) are provided
+// directly instead of being derived from a seed.
+func signExtended(privKey []byte, pubKey []byte, message []byte) ([]byte, error) {
+	kl, err := scalarFromBytes(privKey[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha512.New()
+	h.Write(privKey[32:]) // nolint:errcheck
+	h.Write(message)      // nolint:errcheck
+	r, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	R := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h.Reset()
+	h.Write(R)       // nolint:errcheck
+	h.Write(pubKey)  // nolint:errcheck
+	h.Write(message) // nolint:errcheck
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	S := edwards25519.NewScalar().MultiplyAdd(k, kl, r)
+
+	return append(R, S.Bytes()...), nil
+}
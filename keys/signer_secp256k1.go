@@ -44,6 +44,10 @@ func (s *SignerSecp256k1) Sign(
 	payload *types.SigningPayload,
 	sigType types.SignatureType,
 ) (*types.Signature, error) {
+	if err := assertSignatureTypeAllowed(sigType); err != nil {
+		return nil, err
+	}
+
 	err := s.KeyPair.IsValid()
 	if err != nil {
 		return nil, err
@@ -91,6 +95,10 @@ func (s *SignerSecp256k1) Sign(
 // Verify verifies a Signature, by checking the validity of a Signature,
 // the SigningPayload, and the PublicKey of the Signature.
 func (s *SignerSecp256k1) Verify(signature *types.Signature) error {
+	if err := assertSignatureTypeAllowed(signature.SignatureType); err != nil {
+		return err
+	}
+
 	pubKey := signature.PublicKey.Bytes
 	message := signature.SigningPayload.Bytes
 	sig := signature.Bytes
@@ -51,6 +51,10 @@ func (s *SignerSecp256r1) Sign(
 	payload *types.SigningPayload,
 	sigType types.SignatureType,
 ) (*types.Signature, error) {
+	if err := assertSignatureTypeAllowed(sigType); err != nil {
+		return nil, err
+	}
+
 	if err := s.KeyPair.IsValid(); err != nil {
 		return nil, err
 	}
@@ -106,6 +110,10 @@ func (s *SignerSecp256r1) Sign(
 // Verify verifies a Signature, by checking the validity of a Signature,
 // the SigningPayload, and the PublicKey of the Signature.
 func (s *SignerSecp256r1) Verify(signature *types.Signature) error {
+	if err := assertSignatureTypeAllowed(signature.SignatureType); err != nil {
+		return err
+	}
+
 	if signature.SignatureType != types.Ecdsa {
 		return fmt.Errorf(
 			"%w: expected %v but got %v",
@@ -0,0 +1,150 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// DecimalValue returns the value of a *types.Amount in whole
+// units of its *types.Currency (i.e. Value / 10^Decimals). This
+// allows amounts denominated in currencies with different
+// Decimals to be compared.
+func DecimalValue(amount *types.Amount) (*big.Rat, error) {
+	val, err := types.AmountValue(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := int32(0)
+	if amount.Currency != nil {
+		decimals = amount.Currency.Decimals
+	}
+
+	if decimals < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrAmountDecimalsInvalid, decimals)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(val, scale), nil
+}
+
+// AmountRatio returns the ratio of the decimal value of numerator
+// to the decimal value of denominator (ex: a staking reward
+// relative to the staked amount).
+func AmountRatio(numerator *types.Amount, denominator *types.Amount) (*big.Rat, error) {
+	num, err := DecimalValue(numerator)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse numerator", err)
+	}
+
+	denom, err := DecimalValue(denominator)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse denominator", err)
+	}
+
+	if denom.Sign() == 0 {
+		return nil, ErrAmountRatioDenominatorZero
+	}
+
+	return new(big.Rat).Quo(num, denom), nil
+}
+
+// ProportionalAmounts returns an error if the absolute decimal value of
+// target is not ratio times the absolute decimal value of base. A
+// relative tolerance (ex: 1/100 for 1%) can be provided to allow
+// for rounding on-chain. If tolerance is nil, the values must
+// match exactly.
+func ProportionalAmounts(
+	base *types.Amount,
+	target *types.Amount,
+	ratio *big.Rat,
+	tolerance *big.Rat,
+) error {
+	baseVal, err := DecimalValue(base)
+	if err != nil {
+		return fmt.Errorf("%w: unable to parse base amount", err)
+	}
+
+	targetVal, err := DecimalValue(target)
+	if err != nil {
+		return fmt.Errorf("%w: unable to parse target amount", err)
+	}
+
+	return proportionalValues(
+		new(big.Rat).Abs(baseVal),
+		new(big.Rat).Abs(targetVal),
+		ratio,
+		tolerance,
+	)
+}
+
+// proportionalValues returns an error if target is not
+// within tolerance of ratio times base.
+func proportionalValues(
+	base *big.Rat,
+	target *big.Rat,
+	ratio *big.Rat,
+	tolerance *big.Rat,
+) error {
+	if ratio == nil {
+		return ErrAmountProportionRatioMissing
+	}
+
+	if tolerance == nil {
+		tolerance = new(big.Rat)
+	}
+
+	if tolerance.Sign() < 0 {
+		return fmt.Errorf("%w: %s", ErrAmountProportionToleranceSign, tolerance.RatString())
+	}
+
+	expected := new(big.Rat).Mul(base, ratio)
+	diff := new(big.Rat).Abs(new(big.Rat).Sub(target, expected))
+	allowed := new(big.Rat).Abs(new(big.Rat).Mul(expected, tolerance))
+	if diff.Cmp(allowed) > 0 {
+		return fmt.Errorf(
+			"%w: expected %s (+/- %s) but got %s",
+			ErrAmountProportionNotMet,
+			expected.FloatString(decimalPlaces),
+			allowed.FloatString(decimalPlaces),
+			target.FloatString(decimalPlaces),
+		)
+	}
+
+	return nil
+}
+
+// decimalPlaces is the number of decimal places
+// used when printing decimal values in errors.
+const decimalPlaces = 18
+
+// amountValue returns the value of amount as a *big.Rat. If
+// decimal is true, the value is scaled by Currency.Decimals.
+func amountValue(amount *types.Amount, decimal bool) (*big.Rat, error) {
+	if decimal {
+		return DecimalValue(amount)
+	}
+
+	val, err := types.AmountValue(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Rat).SetInt(val), nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestDecimalValue(t *testing.T) {
+	var tests = map[string]struct {
+		amount *types.Amount
+
+		value *big.Rat
+		err   error
+	}{
+		"no decimals": {
+			amount: &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "BTC"}},
+			value:  big.NewRat(100, 1),
+		},
+		"decimals": {
+			amount: &types.Amount{Value: "-150", Currency: &types.Currency{Symbol: "BTC", Decimals: 2}},
+			value:  big.NewRat(-3, 2),
+		},
+		"negative decimals": {
+			amount: &types.Amount{Value: "1", Currency: &types.Currency{Symbol: "BTC", Decimals: -1}},
+			err:    ErrAmountDecimalsInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, err := DecimalValue(test.amount)
+			if test.err != nil {
+				assert.Nil(t, value)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 0, test.value.Cmp(value))
+		})
+	}
+}
+
+func TestAmountRatio(t *testing.T) {
+	staked := &types.Amount{Value: "2000000000", Currency: &types.Currency{Symbol: "DOT", Decimals: 10}}
+	reward := &types.Amount{Value: "1000", Currency: &types.Currency{Symbol: "RWD", Decimals: 3}}
+
+	ratio, err := AmountRatio(reward, staked)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", ratio.RatString())
+
+	ratio, err = AmountRatio(reward, &types.Amount{Value: "0", Currency: staked.Currency})
+	assert.Nil(t, ratio)
+	assert.True(t, errors.Is(err, ErrAmountRatioDenominatorZero))
+}
+
+func TestProportionalAmounts(t *testing.T) {
+	currency := &types.Currency{Symbol: "ETH", Decimals: 18}
+	base := &types.Amount{Value: "-1000000000000000000", Currency: currency}
+
+	var tests = map[string]struct {
+		target    *types.Amount
+		ratio     *big.Rat
+		tolerance *big.Rat
+
+		err error
+	}{
+		"exact": {
+			target: &types.Amount{Value: "25000000000000000", Currency: currency},
+			ratio:  big.NewRat(25, 1000),
+		},
+		"exact mismatch": {
+			target: &types.Amount{Value: "25000000000000001", Currency: currency},
+			ratio:  big.NewRat(25, 1000),
+			err:    ErrAmountProportionNotMet,
+		},
+		"within tolerance": {
+			target:    &types.Amount{Value: "25200000000000000", Currency: currency},
+			ratio:     big.NewRat(25, 1000),
+			tolerance: big.NewRat(1, 100),
+		},
+		"outside tolerance": {
+			target:    &types.Amount{Value: "25300000000000000", Currency: currency},
+			ratio:     big.NewRat(25, 1000),
+			tolerance: big.NewRat(1, 100),
+			err:       ErrAmountProportionNotMet,
+		},
+		"missing ratio": {
+			target: &types.Amount{Value: "1", Currency: currency},
+			err:    ErrAmountProportionRatioMissing,
+		},
+		"negative tolerance": {
+			target:    &types.Amount{Value: "25000000000000000", Currency: currency},
+			ratio:     big.NewRat(25, 1000),
+			tolerance: big.NewRat(-1, 100),
+			err:       ErrAmountProportionToleranceSign,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ProportionalAmounts(base, test.target, test.ratio, test.tolerance)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
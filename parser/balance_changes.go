@@ -28,6 +28,33 @@ type BalanceChange struct {
 	Currency   *types.Currency          `json:"currency,omitempty"`
 	Block      *types.BlockIdentifier   `json:"block_identifier,omitempty"`
 	Difference string                   `json:"difference,omitempty"`
+
+	// OperationTypes is the Difference contributed by each
+	// operation type. This is only populated when
+	// BalanceChangeOptions.OperationTypeBreakdown is true.
+	OperationTypes map[string]string `json:"operation_types,omitempty"`
+}
+
+// BalanceChangeOptions are used to configure how
+// balance changes are computed in BalanceChangesWithOptions.
+type BalanceChangeOptions struct {
+	// MergeSubAccounts indicates that balance changes for all
+	// SubAccounts of an address should be merged into a single
+	// BalanceChange keyed by the address (with no SubAccount).
+	// By default, each (account, subaccount, currency) tuple is
+	// returned separately.
+	MergeSubAccounts bool
+
+	// IncludeUnsuccessful indicates that operations that
+	// were not successful should be included in balance changes.
+	// This is useful when computing what a block would have
+	// done if all operations succeeded.
+	IncludeUnsuccessful bool
+
+	// OperationTypeBreakdown indicates that the Difference
+	// contributed by each operation type should be populated
+	// in BalanceChange.OperationTypes.
+	OperationTypeBreakdown bool
 }
 
 // ExemptOperation is a function that returns a boolean indicating
@@ -37,16 +64,19 @@ type ExemptOperation func(*types.Operation) bool
 
 // skipOperation returns a boolean indicating whether
 // an operation should be processed. An operation will
-// not be processed if it is considered unsuccessful.
-func (p *Parser) skipOperation(op *types.Operation) (bool, error) {
-	successful, err := p.Asserter.OperationSuccessful(op)
-	if err != nil {
-		// Should only occur if responses not validated
-		return false, err
-	}
+// not be processed if it is considered unsuccessful
+// (unless includeUnsuccessful is true).
+func (p *Parser) skipOperation(op *types.Operation, includeUnsuccessful bool) (bool, error) {
+	if !includeUnsuccessful {
+		successful, err := p.Asserter.OperationSuccessful(op)
+		if err != nil {
+			// Should only occur if responses not validated
+			return false, err
+		}
 
-	if !successful {
-		return true, nil
+		if !successful {
+			return true, nil
+		}
 	}
 
 	if op.Account == nil {
@@ -77,63 +107,156 @@ func (p *Parser) BalanceChanges(
 	block *types.Block,
 	blockRemoved bool,
 ) ([]*BalanceChange, error) {
-	balanceChanges := map[string]*BalanceChange{}
+	return p.BalanceChangesWithOptions(ctx, block, blockRemoved, nil)
+}
+
+// balanceChangeAccount returns the *types.AccountIdentifier
+// that a balance change should be keyed by.
+func balanceChangeAccount(
+	account *types.AccountIdentifier,
+	options *BalanceChangeOptions,
+) *types.AccountIdentifier {
+	if !options.MergeSubAccounts || account.SubAccount == nil {
+		return account
+	}
+
+	return &types.AccountIdentifier{
+		Address:  account.Address,
+		Metadata: account.Metadata,
+	}
+}
+
+// BalanceChangesWithOptions returns all balance changes for
+// a particular block computed according to the provided
+// *BalanceChangeOptions. If options is nil, the result is
+// equivalent to BalanceChanges.
+func (p *Parser) BalanceChangesWithOptions(
+	ctx context.Context,
+	block *types.Block,
+	blockRemoved bool,
+	options *BalanceChangeOptions,
+) ([]*BalanceChange, error) {
+	accumulator := p.newBalanceAccumulator(block.BlockIdentifier, blockRemoved, options)
 	for _, tx := range block.Transactions {
-		for _, op := range tx.Operations {
-			skip, err := p.skipOperation(op)
+		if err := accumulator.addTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return accumulator.balanceChanges(), nil
+}
+
+// balanceAccumulator incrementally sums balance
+// changes from transactions in a single block.
+type balanceAccumulator struct {
+	parser       *Parser
+	block        *types.BlockIdentifier
+	blockRemoved bool
+	options      *BalanceChangeOptions
+
+	changes map[string]*BalanceChange
+}
+
+func (p *Parser) newBalanceAccumulator(
+	block *types.BlockIdentifier,
+	blockRemoved bool,
+	options *BalanceChangeOptions,
+) *balanceAccumulator {
+	if options == nil {
+		options = &BalanceChangeOptions{}
+	}
+
+	return &balanceAccumulator{
+		parser:       p,
+		block:        block,
+		blockRemoved: blockRemoved,
+		options:      options,
+		changes:      map[string]*BalanceChange{},
+	}
+}
+
+// addTransaction adds the balance changes of all
+// operations in a transaction to the accumulator.
+func (b *balanceAccumulator) addTransaction(tx *types.Transaction) error {
+	if err := b.parser.ValidateOperationsMetadata(tx.Operations); err != nil {
+		return err
+	}
+
+	for _, op := range tx.Operations {
+		skip, err := b.parser.skipOperation(op, b.options.IncludeUnsuccessful)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		// We create a copy of Amount.Value
+		// here to ensure we don't accidentally overwrite
+		// the value of op.Amount.
+		amountValue := op.Amount.Value
+		if b.blockRemoved {
+			negatedValue, err := types.NegateValue(amountValue)
 			if err != nil {
-				return nil, err
-			}
-			if skip {
-				continue
+				return err
 			}
+			amountValue = negatedValue
+		}
+
+		// Merge values by account and currency
+		account := balanceChangeAccount(op.Account, b.options)
+		key := fmt.Sprintf(
+			"%s/%s",
+			types.Hash(account),
+			types.Hash(op.Amount.Currency),
+		)
 
-			// We create a copy of Amount.Value
-			// here to ensure we don't accidentally overwrite
-			// the value of op.Amount.
-			amountValue := op.Amount.Value
-			blockIdentifier := block.BlockIdentifier
-			if blockRemoved {
-				negatedValue, err := types.NegateValue(amountValue)
-				if err != nil {
-					return nil, err
-				}
-				amountValue = negatedValue
+		val, ok := b.changes[key]
+		if !ok {
+			val = &BalanceChange{
+				Account:    account,
+				Currency:   op.Amount.Currency,
+				Difference: "0",
+				Block:      b.block,
 			}
+			if b.options.OperationTypeBreakdown {
+				val.OperationTypes = map[string]string{}
+			}
+			b.changes[key] = val
+		}
 
-			// Merge values by account and currency
-			key := fmt.Sprintf(
-				"%s/%s",
-				types.Hash(op.Account),
-				types.Hash(op.Amount.Currency),
-			)
+		newDifference, err := types.AddValues(val.Difference, amountValue)
+		if err != nil {
+			return err
+		}
+		val.Difference = newDifference
 
-			val, ok := balanceChanges[key]
+		if b.options.OperationTypeBreakdown {
+			typeDifference, ok := val.OperationTypes[op.Type]
 			if !ok {
-				balanceChanges[key] = &BalanceChange{
-					Account:    op.Account,
-					Currency:   op.Amount.Currency,
-					Difference: amountValue,
-					Block:      blockIdentifier,
-				}
-				continue
+				typeDifference = "0"
 			}
 
-			newDifference, err := types.AddValues(val.Difference, amountValue)
+			newTypeDifference, err := types.AddValues(typeDifference, amountValue)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			val.Difference = newDifference
-			balanceChanges[key] = val
+			val.OperationTypes[op.Type] = newTypeDifference
 		}
 	}
 
+	return nil
+}
+
+// balanceChanges returns all balance changes
+// accumulated so far.
+func (b *balanceAccumulator) balanceChanges() []*BalanceChange {
 	i := 0
-	allChanges := make([]*BalanceChange, len(balanceChanges))
-	for _, change := range balanceChanges {
+	allChanges := make([]*BalanceChange, len(b.changes))
+	for _, change := range b.changes {
 		allChanges[i] = change
 		i++
 	}
 
-	return allChanges, nil
+	return allChanges
 }
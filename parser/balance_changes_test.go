@@ -329,3 +329,175 @@ func simpleAsserterConfiguration(
 		},
 	)
 }
+
+func TestBalanceChangesWithOptions(t *testing.T) {
+	var (
+		currency = &types.Currency{
+			Symbol:   "Blah",
+			Decimals: 2,
+		}
+
+		blockIdentifier = &types.BlockIdentifier{
+			Hash:  "1",
+			Index: 1,
+		}
+
+		account = &types.AccountIdentifier{
+			Address: "acct1",
+		}
+
+		stakedAccount = &types.AccountIdentifier{
+			Address: "acct1",
+			SubAccount: &types.SubAccountIdentifier{
+				Address: "staked",
+			},
+		}
+
+		block = &types.Block{
+			BlockIdentifier: blockIdentifier,
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Hash:  "0",
+				Index: 0,
+			},
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: "tx1",
+					},
+					Operations: []*types.Operation{
+						{
+							OperationIdentifier: &types.OperationIdentifier{Index: 0},
+							Type:                "Transfer",
+							Status:              types.String("Success"),
+							Account:             account,
+							Amount:              &types.Amount{Value: "100", Currency: currency},
+						},
+						{
+							OperationIdentifier: &types.OperationIdentifier{Index: 1},
+							Type:                "Fee",
+							Status:              types.String("Success"),
+							Account:             account,
+							Amount:              &types.Amount{Value: "-5", Currency: currency},
+						},
+						{
+							OperationIdentifier: &types.OperationIdentifier{Index: 2},
+							Type:                "Stake",
+							Status:              types.String("Success"),
+							Account:             stakedAccount,
+							Amount:              &types.Amount{Value: "20", Currency: currency},
+						},
+						{
+							OperationIdentifier: &types.OperationIdentifier{Index: 3},
+							Type:                "Transfer",
+							Status:              types.String("Failure"),
+							Account:             account,
+							Amount:              &types.Amount{Value: "1000", Currency: currency},
+						},
+					},
+				},
+			},
+			Timestamp: asserter.MinUnixEpoch + 1,
+		}
+	)
+
+	var tests = map[string]struct {
+		options *BalanceChangeOptions
+		orphan  bool
+		changes []*BalanceChange
+	}{
+		"nil options": {
+			changes: []*BalanceChange{
+				{
+					Account:    account,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "95",
+				},
+				{
+					Account:    stakedAccount,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "20",
+				},
+			},
+		},
+		"merge subaccounts": {
+			options: &BalanceChangeOptions{
+				MergeSubAccounts: true,
+			},
+			changes: []*BalanceChange{
+				{
+					Account:    account,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "115",
+				},
+			},
+		},
+		"include unsuccessful": {
+			options: &BalanceChangeOptions{
+				IncludeUnsuccessful: true,
+			},
+			changes: []*BalanceChange{
+				{
+					Account:    account,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "1095",
+				},
+				{
+					Account:    stakedAccount,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "20",
+				},
+			},
+		},
+		"operation type breakdown (orphan)": {
+			options: &BalanceChangeOptions{
+				MergeSubAccounts:       true,
+				OperationTypeBreakdown: true,
+			},
+			orphan: true,
+			changes: []*BalanceChange{
+				{
+					Account:    account,
+					Currency:   currency,
+					Block:      blockIdentifier,
+					Difference: "-115",
+					OperationTypes: map[string]string{
+						"Transfer": "-100",
+						"Fee":      "5",
+						"Stake":    "-20",
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "Success",
+					Successful: true,
+				},
+				{
+					Status:     "Failure",
+					Successful: false,
+				},
+			})
+			assert.NoError(t, err)
+
+			parser := New(asserter, nil, nil)
+			changes, err := parser.BalanceChangesWithOptions(
+				context.Background(),
+				block,
+				test.orphan,
+				test.options,
+			)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.changes, changes)
+		})
+	}
+}
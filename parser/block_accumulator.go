@@ -0,0 +1,123 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// TransactionIterator invokes handler for each transaction
+// in a block (ex: as transactions are fetched or decoded). If handler
+// returns an error, iteration should stop and the error should
+// be returned.
+type TransactionIterator func(
+	ctx context.Context,
+	handler func(*types.Transaction) error,
+) error
+
+// BlockAccumulator incrementally computes balance changes and coin
+// changes for a block from transactions provided one at a time. This
+// allows callers (ex: a memory-constrained syncer) to discard each
+// transaction after it is added instead of holding an entire
+// *types.Block in memory.
+//
+// A BlockAccumulator is not safe for concurrent use.
+type BlockAccumulator struct {
+	balances *balanceAccumulator
+	coins    *coinAccumulator
+
+	transactions int
+}
+
+// NewBlockAccumulator returns a new *BlockAccumulator for the block
+// with the provided *types.BlockIdentifier. If blockRemoved is true,
+// changes are computed as if the block is being orphaned.
+func (p *Parser) NewBlockAccumulator(
+	block *types.BlockIdentifier,
+	blockRemoved bool,
+	options *BalanceChangeOptions,
+) *BlockAccumulator {
+	return &BlockAccumulator{
+		balances: p.newBalanceAccumulator(block, blockRemoved, options),
+		coins:    p.newCoinAccumulator(blockRemoved),
+	}
+}
+
+// AddTransaction adds the balance changes and coin changes of
+// a transaction to the accumulator. Only the accounts, currencies,
+// coin identifiers, and coin amounts referenced by the resulting
+// changes are retained (not the transaction or its operations),
+// so these must not be modified after AddTransaction returns.
+func (b *BlockAccumulator) AddTransaction(
+	ctx context.Context,
+	tx *types.Transaction,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := b.balances.addTransaction(tx); err != nil {
+		return err
+	}
+
+	if err := b.coins.addTransaction(tx); err != nil {
+		return err
+	}
+
+	b.transactions++
+	return nil
+}
+
+// Transactions returns the number of transactions
+// added to the accumulator.
+func (b *BlockAccumulator) Transactions() int {
+	return b.transactions
+}
+
+// BalanceChanges returns all balance changes accumulated
+// so far. This is equivalent to calling BalanceChangesWithOptions
+// on a block containing all added transactions.
+func (b *BlockAccumulator) BalanceChanges() []*BalanceChange {
+	return b.balances.balanceChanges()
+}
+
+// CoinChanges returns all coin changes accumulated so far. This
+// is equivalent to calling CoinChanges on a block containing all
+// added transactions.
+func (b *BlockAccumulator) CoinChanges() *CoinChanges {
+	return b.coins.coinChanges()
+}
+
+// StreamBlock populates a new *BlockAccumulator with all transactions
+// provided by iterator. This can be used to parse blocks that are too
+// large to hold in memory at once.
+func (p *Parser) StreamBlock(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+	blockRemoved bool,
+	options *BalanceChangeOptions,
+	iterator TransactionIterator,
+) (*BlockAccumulator, error) {
+	accumulator := p.NewBlockAccumulator(block, blockRemoved, options)
+	if err := iterator(ctx, func(tx *types.Transaction) error {
+		return accumulator.AddTransaction(ctx, tx)
+	}); err != nil {
+		return nil, err
+	}
+
+	return accumulator, nil
+}
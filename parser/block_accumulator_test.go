@@ -0,0 +1,107 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestBlockAccumulator(t *testing.T) {
+	asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+		{
+			Status:     "Success",
+			Successful: true,
+		},
+		{
+			Status:     "Failure",
+			Successful: false,
+		},
+	})
+	assert.NoError(t, err)
+	parser := New(asserter, nil, nil)
+
+	block := coinChangeBlock(
+		coinOperation("addr1", "tx0:0", types.CoinSpent, "-100", "Success"),
+		coinOperation("addr2", "tx1:0", types.CoinCreated, "60", "Success"),
+		coinOperation("addr3", "tx1:1", types.CoinCreated, "40", "Success"),
+		coinOperation("addr4", "tx1:2", types.CoinCreated, "40", "Failure"),
+	)
+	block.Transactions = append(block.Transactions, &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: "tx2",
+		},
+		Operations: []*types.Operation{
+			coinOperation("addr3", "tx1:1", types.CoinSpent, "-40", "Success"),
+			coinOperation("addr2", "tx2:0", types.CoinCreated, "40", "Success"),
+		},
+	})
+
+	iterator := func(ctx context.Context, handler func(*types.Transaction) error) error {
+		for _, tx := range block.Transactions {
+			if err := handler(tx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, blockRemoved := range []bool{false, true} {
+		expectedBalances, err := parser.BalanceChanges(context.Background(), block, blockRemoved)
+		assert.NoError(t, err)
+
+		expectedCoins, err := parser.CoinChanges(block, blockRemoved)
+		assert.NoError(t, err)
+
+		accumulator, err := parser.StreamBlock(
+			context.Background(),
+			block.BlockIdentifier,
+			blockRemoved,
+			nil,
+			iterator,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, accumulator.Transactions())
+		assert.ElementsMatch(t, expectedBalances, accumulator.BalanceChanges())
+		assert.Equal(t, expectedCoins, accumulator.CoinChanges())
+	}
+
+	t.Run("invalid transaction", func(t *testing.T) {
+		accumulator := parser.NewBlockAccumulator(block.BlockIdentifier, false, nil)
+		err := accumulator.AddTransaction(context.Background(), &types.Transaction{
+			Operations: []*types.Operation{
+				coinOperation("addr1", "tx3:0", types.CoinCreated, "1", "Success"),
+				coinOperation("addr1", "tx3:0", types.CoinCreated, "1", "Success"),
+			},
+		})
+		assert.True(t, errors.Is(err, ErrCoinChangesDuplicateCoin))
+		assert.Equal(t, 0, accumulator.Transactions())
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		accumulator, err := parser.StreamBlock(ctx, block.BlockIdentifier, false, nil, iterator)
+		assert.Nil(t, accumulator)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
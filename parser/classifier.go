@@ -0,0 +1,140 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Classification is a named pattern (ex: "staking_delegate")
+// that a *types.Transaction can be tagged with if its operations
+// match Descriptions.
+type Classification struct {
+	Name         string
+	Descriptions *Descriptions
+}
+
+// ClassifiedTransaction is a *types.Transaction tagged with
+// the names of all classifications it matched.
+type ClassifiedTransaction struct {
+	Transaction     *types.Transaction
+	Classifications []string
+
+	// Matches contains the result of MatchOperations
+	// for each matched classification.
+	Matches map[string][]*Match
+}
+
+// Is returns a boolean indicating if a *ClassifiedTransaction
+// matched the classification name.
+func (c *ClassifiedTransaction) Is(name string) bool {
+	_, ok := c.Matches[name]
+	return ok
+}
+
+// Classifier tags transactions with all registered classifications
+// whose Descriptions match the transaction's operations. This turns
+// MatchOperations into a reusable indexing primitive.
+type Classifier struct {
+	classifications []*Classification
+	names           map[string]struct{}
+	lock            sync.RWMutex
+}
+
+// NewClassifier returns a new *Classifier
+// with the provided classifications registered.
+func NewClassifier(classifications ...*Classification) (*Classifier, error) {
+	c := &Classifier{
+		names: map[string]struct{}{},
+	}
+
+	for _, classification := range classifications {
+		if err := c.Register(classification.Name, classification.Descriptions); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Register adds a named classification to the *Classifier.
+// Classifications are evaluated in the order they are registered.
+func (c *Classifier) Register(name string, descriptions *Descriptions) error {
+	if len(name) == 0 {
+		return ErrClassificationNameMissing
+	}
+
+	if descriptions == nil || len(descriptions.OperationDescriptions) == 0 {
+		return fmt.Errorf("%w: %s", ErrClassificationDescriptionsMissing, name)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.names[name]; ok {
+		return fmt.Errorf("%w: %s", ErrClassificationDuplicate, name)
+	}
+
+	c.names[name] = struct{}{}
+	c.classifications = append(c.classifications, &Classification{
+		Name:         name,
+		Descriptions: descriptions,
+	})
+
+	return nil
+}
+
+// Classify returns a *ClassifiedTransaction containing all
+// classifications that match the operations in a transaction. A
+// transaction that doesn't match any classification is returned
+// with no Classifications.
+func (c *Classifier) Classify(tx *types.Transaction) *ClassifiedTransaction {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	classified := &ClassifiedTransaction{
+		Transaction:     tx,
+		Classifications: []string{},
+		Matches:         map[string][]*Match{},
+	}
+
+	for _, classification := range c.classifications {
+		matches, err := MatchOperations(classification.Descriptions, tx.Operations)
+		if err != nil {
+			// A match error indicates the transaction does
+			// not fit the classification.
+			continue
+		}
+
+		classified.Classifications = append(classified.Classifications, classification.Name)
+		classified.Matches[classification.Name] = matches
+	}
+
+	return classified
+}
+
+// ClassifyBlock classifies each transaction in a block. The returned
+// slice has the same length and order as block.Transactions.
+func (c *Classifier) ClassifyBlock(block *types.Block) []*ClassifiedTransaction {
+	classified := make([]*ClassifiedTransaction, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		classified[i] = c.Classify(tx)
+	}
+
+	return classified
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestClassifier(t *testing.T) {
+	transfer := &Descriptions{
+		OppositeAmounts: [][]int{{0, 1}},
+		ErrUnmatched:    true,
+		OperationDescriptions: []*OperationDescription{
+			{
+				Type:    "Transfer",
+				Account: &AccountDescription{Exists: true},
+				Amount:  &AmountDescription{Exists: true, Sign: NegativeAmountSign},
+			},
+			{
+				Type:    "Transfer",
+				Account: &AccountDescription{Exists: true},
+				Amount:  &AmountDescription{Exists: true, Sign: PositiveAmountSign},
+			},
+		},
+	}
+	delegate := &Descriptions{
+		OperationDescriptions: []*OperationDescription{
+			{
+				Type:    "Delegate",
+				Account: &AccountDescription{Exists: true},
+			},
+		},
+	}
+	withFee := &Descriptions{
+		OperationDescriptions: []*OperationDescription{
+			{
+				Type:   "Fee",
+				Amount: &AmountDescription{Exists: true, Sign: NegativeAmountSign},
+			},
+		},
+	}
+
+	classifier, err := NewClassifier(
+		&Classification{Name: "transfer", Descriptions: transfer},
+		&Classification{Name: "staking_delegate", Descriptions: delegate},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, classifier.Register("with_fee", withFee))
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		assert.True(t, errors.Is(classifier.Register("", withFee), ErrClassificationNameMissing))
+		assert.True(t, errors.Is(
+			classifier.Register("empty", &Descriptions{}),
+			ErrClassificationDescriptionsMissing,
+		))
+		assert.True(t, errors.Is(
+			classifier.Register("transfer", transfer),
+			ErrClassificationDuplicate,
+		))
+
+		c, err := NewClassifier(
+			&Classification{Name: "transfer", Descriptions: transfer},
+			&Classification{Name: "transfer", Descriptions: transfer},
+		)
+		assert.Nil(t, c)
+		assert.True(t, errors.Is(err, ErrClassificationDuplicate))
+	})
+
+	account := &types.AccountIdentifier{Address: "addr1"}
+	transferTx := &types.Transaction{
+		Operations: []*types.Operation{
+			{
+				Type:    "Transfer",
+				Account: account,
+				Amount:  &types.Amount{Value: "-100"},
+			},
+			{
+				Type:    "Transfer",
+				Account: &types.AccountIdentifier{Address: "addr2"},
+				Amount:  &types.Amount{Value: "100"},
+			},
+		},
+	}
+	delegateTx := &types.Transaction{
+		Operations: []*types.Operation{
+			{
+				Type:    "Delegate",
+				Account: account,
+			},
+			{
+				Type:   "Fee",
+				Amount: &types.Amount{Value: "-10"},
+			},
+		},
+	}
+	unknownTx := &types.Transaction{
+		Operations: []*types.Operation{
+			{
+				Type: "Unknown",
+			},
+		},
+	}
+
+	classified := classifier.ClassifyBlock(&types.Block{
+		Transactions: []*types.Transaction{transferTx, delegateTx, unknownTx},
+	})
+	assert.Len(t, classified, 3)
+
+	assert.Equal(t, transferTx, classified[0].Transaction)
+	assert.Equal(t, []string{"transfer"}, classified[0].Classifications)
+	assert.True(t, classified[0].Is("transfer"))
+	assert.Len(t, classified[0].Matches["transfer"], 2)
+
+	assert.Equal(t, []string{"staking_delegate", "with_fee"}, classified[1].Classifications)
+	assert.False(t, classified[1].Is("transfer"))
+	op, _ := classified[1].Matches["staking_delegate"][0].First()
+	assert.Equal(t, delegateTx.Operations[0], op)
+
+	assert.Equal(t, []string{}, classified[2].Classifications)
+	assert.Empty(t, classified[2].Matches)
+}
@@ -0,0 +1,180 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// CoinChanges are all coins that should be added to and
+// removed from a UTXO set when processing a block.
+type CoinChanges struct {
+	Added   []*types.AccountCoin `json:"added"`
+	Removed []*types.AccountCoin `json:"removed"`
+}
+
+// validateCoinIdentifier returns an error if a *types.CoinIdentifier
+// is not populated or does not match the CoinIdentifierPattern
+// provided to the parser (if any).
+func (p *Parser) validateCoinIdentifier(identifier *types.CoinIdentifier) error {
+	if identifier == nil || len(identifier.Identifier) == 0 {
+		return ErrCoinChangesIdentifierMissing
+	}
+
+	if p.CoinIdentifierPattern != nil &&
+		!p.CoinIdentifierPattern.MatchString(identifier.Identifier) {
+		return fmt.Errorf(
+			"%w: %s does not match %s",
+			ErrCoinChangesIdentifierInvalid,
+			identifier.Identifier,
+			p.CoinIdentifierPattern.String(),
+		)
+	}
+
+	return nil
+}
+
+// CoinChanges returns all coins created and spent by successful
+// operations in a block. If a coin is created and spent in the
+// same block, it is omitted entirely.
+//
+// If blockRemoved is false, Added contains all coins created in the
+// block and Removed contains all coins spent in the block. If
+// blockRemoved is true (the block is being orphaned), this is
+// reversed.
+func (p *Parser) CoinChanges(
+	block *types.Block,
+	blockRemoved bool,
+) (*CoinChanges, error) {
+	accumulator := p.newCoinAccumulator(blockRemoved)
+	for _, txn := range block.Transactions {
+		if err := accumulator.addTransaction(txn); err != nil {
+			return nil, err
+		}
+	}
+
+	return accumulator.coinChanges(), nil
+}
+
+// coinAccumulator incrementally collects coin
+// changes from transactions in a single block.
+type coinAccumulator struct {
+	parser       *Parser
+	blockRemoved bool
+
+	addCoins    map[string]*types.AccountCoin
+	removeCoins map[string]*types.AccountCoin
+	addOrder    []string
+	removeOrder []string
+}
+
+func (p *Parser) newCoinAccumulator(blockRemoved bool) *coinAccumulator {
+	return &coinAccumulator{
+		parser:       p,
+		blockRemoved: blockRemoved,
+		addCoins:     map[string]*types.AccountCoin{},
+		removeCoins:  map[string]*types.AccountCoin{},
+		addOrder:     []string{},
+		removeOrder:  []string{},
+	}
+}
+
+// addTransaction adds the coin changes of all successful
+// operations in a transaction to the accumulator.
+func (c *coinAccumulator) addTransaction(txn *types.Transaction) error {
+	for _, operation := range txn.Operations {
+		if operation.CoinChange == nil || operation.Amount == nil {
+			continue
+		}
+
+		success, err := c.parser.Asserter.OperationSuccessful(operation)
+		if err != nil {
+			return fmt.Errorf("%w: unable to check operation success", err)
+		}
+
+		if !success {
+			continue
+		}
+
+		coinChange := operation.CoinChange
+		if err := c.parser.validateCoinIdentifier(coinChange.CoinIdentifier); err != nil {
+			return err
+		}
+
+		if operation.Account == nil {
+			return fmt.Errorf(
+				"%w: %s",
+				ErrCoinChangesAccountMissing,
+				coinChange.CoinIdentifier.Identifier,
+			)
+		}
+
+		identifier := coinChange.CoinIdentifier.Identifier
+		coinDict := c.removeCoins
+		order := &c.removeOrder
+		if !c.blockRemoved && coinChange.CoinAction == types.CoinCreated ||
+			c.blockRemoved && coinChange.CoinAction == types.CoinSpent {
+			coinDict = c.addCoins
+			order = &c.addOrder
+		}
+
+		if _, ok := coinDict[identifier]; ok {
+			return fmt.Errorf("%w: %s", ErrCoinChangesDuplicateCoin, identifier)
+		}
+
+		coinDict[identifier] = accountCoin(operation)
+		*order = append(*order, identifier)
+	}
+
+	return nil
+}
+
+// coinChanges returns all coin changes accumulated so far. Coins
+// created and spent in the accumulated transactions are omitted.
+func (c *coinAccumulator) coinChanges() *CoinChanges {
+	changes := &CoinChanges{
+		Added:   []*types.AccountCoin{},
+		Removed: []*types.AccountCoin{},
+	}
+	for _, identifier := range c.addOrder {
+		if _, ok := c.removeCoins[identifier]; ok {
+			continue
+		}
+
+		changes.Added = append(changes.Added, c.addCoins[identifier])
+	}
+
+	for _, identifier := range c.removeOrder {
+		if _, ok := c.addCoins[identifier]; ok {
+			continue
+		}
+
+		changes.Removed = append(changes.Removed, c.removeCoins[identifier])
+	}
+
+	return changes
+}
+
+func accountCoin(op *types.Operation) *types.AccountCoin {
+	return &types.AccountCoin{
+		Account: op.Account,
+		Coin: &types.Coin{
+			CoinIdentifier: op.CoinChange.CoinIdentifier,
+			Amount:         op.Amount,
+		},
+	}
+}
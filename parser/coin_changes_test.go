@@ -0,0 +1,177 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func coinOperation(
+	address string,
+	identifier string,
+	action types.CoinAction,
+	value string,
+	status string,
+) *types.Operation {
+	return &types.Operation{
+		Type:   "Transfer",
+		Status: types.String(status),
+		Account: &types.AccountIdentifier{
+			Address: address,
+		},
+		Amount: &types.Amount{
+			Value: value,
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		},
+		CoinChange: &types.CoinChange{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: identifier,
+			},
+			CoinAction: action,
+		},
+	}
+}
+
+func coinChangeBlock(ops ...*types.Operation) *types.Block {
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Hash:  "1",
+			Index: 1,
+		},
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx1",
+				},
+				Operations: ops,
+			},
+		},
+	}
+}
+
+func TestCoinChanges(t *testing.T) {
+	var (
+		spend    = coinOperation("addr1", "tx0:0", types.CoinSpent, "-100", "Success")
+		create1  = coinOperation("addr2", "tx1:0", types.CoinCreated, "60", "Success")
+		create2  = coinOperation("addr3", "tx1:1", types.CoinCreated, "40", "Success")
+		spendNew = coinOperation("addr3", "tx1:1", types.CoinSpent, "-40", "Success")
+		failed   = coinOperation("addr4", "tx1:2", types.CoinCreated, "40", "Failure")
+		noCoin   = &types.Operation{
+			Type:   "Transfer",
+			Status: types.String("Success"),
+		}
+
+		coin = func(op *types.Operation) *types.AccountCoin {
+			return &types.AccountCoin{
+				Account: op.Account,
+				Coin: &types.Coin{
+					CoinIdentifier: op.CoinChange.CoinIdentifier,
+					Amount:         op.Amount,
+				},
+			}
+		}
+	)
+
+	var tests = map[string]struct {
+		block        *types.Block
+		blockRemoved bool
+		pattern      *regexp.Regexp
+
+		changes *CoinChanges
+		err     error
+	}{
+		"simple block": {
+			block: coinChangeBlock(spend, create1, create2, failed, noCoin),
+			changes: &CoinChanges{
+				Added:   []*types.AccountCoin{coin(create1), coin(create2)},
+				Removed: []*types.AccountCoin{coin(spend)},
+			},
+		},
+		"orphaned block": {
+			block:        coinChangeBlock(spend, create1, create2),
+			blockRemoved: true,
+			changes: &CoinChanges{
+				Added:   []*types.AccountCoin{coin(spend)},
+				Removed: []*types.AccountCoin{coin(create1), coin(create2)},
+			},
+		},
+		"created and spent in same block": {
+			block: coinChangeBlock(spend, create1, create2, spendNew),
+			changes: &CoinChanges{
+				Added:   []*types.AccountCoin{coin(create1)},
+				Removed: []*types.AccountCoin{coin(spend)},
+			},
+		},
+		"matching pattern": {
+			block:   coinChangeBlock(spend, create1),
+			pattern: regexp.MustCompile(`^tx[0-9]+:[0-9]+$`),
+			changes: &CoinChanges{
+				Added:   []*types.AccountCoin{coin(create1)},
+				Removed: []*types.AccountCoin{coin(spend)},
+			},
+		},
+		"invalid identifier": {
+			block:   coinChangeBlock(spend, create1),
+			pattern: regexp.MustCompile(`^[0-9a-f]{64}:[0-9]+$`),
+			err:     ErrCoinChangesIdentifierInvalid,
+		},
+		"missing identifier": {
+			block: coinChangeBlock(coinOperation("addr1", "", types.CoinSpent, "-1", "Success")),
+			err:   ErrCoinChangesIdentifierMissing,
+		},
+		"duplicate coin": {
+			block: coinChangeBlock(create1, create1),
+			err:   ErrCoinChangesDuplicateCoin,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "Success",
+					Successful: true,
+				},
+				{
+					Status:     "Failure",
+					Successful: false,
+				},
+			})
+			assert.NoError(t, err)
+
+			parser := New(asserter, nil, nil)
+			parser.CoinIdentifierPattern = test.pattern
+
+			changes, err := parser.CoinChanges(test.block, test.blockRemoved)
+			if test.err != nil {
+				assert.Nil(t, changes)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.changes, changes)
+		})
+	}
+}
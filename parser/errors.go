@@ -45,6 +45,69 @@ var (
 	}
 )
 
+// Fee Errors
+var (
+	ErrFeeExceedsMax         = errors.New("fee exceeds maximum")
+	ErrFeeUnexpectedCurrency = errors.New("fee paid in unexpected currency")
+
+	FeeErrs = []error{
+		ErrFeeExceedsMax,
+		ErrFeeUnexpectedCurrency,
+	}
+)
+
+// Operation Graph Errors
+var (
+	ErrOperationGraphIdentifierMissing = errors.New("operation identifier is missing")
+	ErrOperationGraphDuplicateIndex    = errors.New("duplicate operation index")
+	ErrOperationGraphMissingReference  = errors.New("related operation does not exist")
+	ErrOperationGraphCycle             = errors.New("related operations contain a cycle")
+
+	OperationGraphErrs = []error{
+		ErrOperationGraphIdentifierMissing,
+		ErrOperationGraphDuplicateIndex,
+		ErrOperationGraphMissingReference,
+		ErrOperationGraphCycle,
+	}
+)
+
+// Coin Changes Errors
+var (
+	ErrCoinChangesIdentifierMissing = errors.New("coin identifier is missing")
+	ErrCoinChangesIdentifierInvalid = errors.New("coin identifier is invalid")
+	ErrCoinChangesAccountMissing    = errors.New("coin change account is missing")
+	ErrCoinChangesDuplicateCoin     = errors.New("duplicate coin found")
+
+	CoinChangesErrs = []error{
+		ErrCoinChangesIdentifierMissing,
+		ErrCoinChangesIdentifierInvalid,
+		ErrCoinChangesAccountMissing,
+		ErrCoinChangesDuplicateCoin,
+	}
+)
+
+// Metadata Schema Errors
+var (
+	ErrMetadataSchemaUnknownKey = errors.New("metadata contains unknown key")
+
+	MetadataSchemaErrs = []error{
+		ErrMetadataSchemaUnknownKey,
+	}
+)
+
+// Classifier Errors
+var (
+	ErrClassificationNameMissing         = errors.New("classification name is missing")
+	ErrClassificationDescriptionsMissing = errors.New("classification descriptions are missing")
+	ErrClassificationDuplicate           = errors.New("classification already registered")
+
+	ClassifierErrs = []error{
+		ErrClassificationNameMissing,
+		ErrClassificationDescriptionsMissing,
+		ErrClassificationDuplicate,
+	}
+)
+
 // Match Operations Errors
 var (
 	ErrAccountMatchAccountMissing           = errors.New("account is missing")
@@ -80,6 +143,17 @@ var (
 	ErrMatchOperationsDescriptionsMissing   = errors.New("no descriptions to match")
 	ErrMatchOperationsMatchNotFound         = errors.New("unable to find match for operation")
 	ErrMatchOperationsDescriptionNotMatched = errors.New("could not find match for description")
+	ErrMatchOperationsTooFewMatches         = errors.New("too few operations matched description")
+
+	ErrAmountRelationNotMet            = errors.New("amount relation not met")
+	ErrAmountRelationCurrencyMismatch  = errors.New("amount relation currencies do not match")
+	ErrAmountRelationInvalidComparison = errors.New("invalid amount comparison")
+
+	ErrAmountDecimalsInvalid         = errors.New("currency decimals must be non-negative")
+	ErrAmountRatioDenominatorZero    = errors.New("cannot compute ratio with zero denominator")
+	ErrAmountProportionRatioMissing  = errors.New("amount proportion ratio is missing")
+	ErrAmountProportionNotMet        = errors.New("amount proportion not met")
+	ErrAmountProportionToleranceSign = errors.New("amount proportion tolerance is negative")
 
 	MatchOpsErrs = []error{
 		ErrAccountMatchAccountMissing,
@@ -107,6 +181,15 @@ var (
 		ErrMatchOperationsDescriptionsMissing,
 		ErrMatchOperationsMatchNotFound,
 		ErrMatchOperationsDescriptionNotMatched,
+		ErrMatchOperationsTooFewMatches,
+		ErrAmountRelationNotMet,
+		ErrAmountRelationCurrencyMismatch,
+		ErrAmountRelationInvalidComparison,
+		ErrAmountDecimalsInvalid,
+		ErrAmountRatioDenominatorZero,
+		ErrAmountProportionRatioMissing,
+		ErrAmountProportionNotMet,
+		ErrAmountProportionToleranceSign,
 	}
 )
 
@@ -117,6 +200,11 @@ func Err(err error) (bool, string) {
 	parserErrs := map[string][]error{
 		"intent error":           IntentErrs,
 		"match operations error": MatchOpsErrs,
+		"fee error":              FeeErrs,
+		"operation graph error":  OperationGraphErrs,
+		"coin changes error":     CoinChangesErrs,
+		"classifier error":       ClassifierErrs,
+		"metadata schema error":  MetadataSchemaErrs,
 	}
 
 	for key, val := range parserErrs {
@@ -36,6 +36,31 @@ func TestErr(t *testing.T) {
 			is:     true,
 			source: "match operations error",
 		},
+		"fee error": {
+			err:    ErrFeeExceedsMax,
+			is:     true,
+			source: "fee error",
+		},
+		"operation graph error": {
+			err:    ErrOperationGraphCycle,
+			is:     true,
+			source: "operation graph error",
+		},
+		"coin changes error": {
+			err:    ErrCoinChangesDuplicateCoin,
+			is:     true,
+			source: "coin changes error",
+		},
+		"classifier error": {
+			err:    ErrClassificationDuplicate,
+			is:     true,
+			source: "classifier error",
+		},
+		"metadata schema error": {
+			err:    ErrMetadataSchemaUnknownKey,
+			is:     true,
+			source: "metadata schema error",
+		},
 	}
 
 	for name, test := range tests {
@@ -28,7 +28,7 @@ func (p *Parser) FindExemptions(
 ) []*types.BalanceExemption {
 	matches := []*types.BalanceExemption{}
 	for _, exemption := range p.BalanceExemptions {
-		if exemption.Currency != nil && types.Hash(currency) != types.Hash(exemption.Currency) {
+		if exemption.Currency != nil && !currency.Equal(exemption.Currency) {
 			continue
 		}
 
@@ -66,3 +66,59 @@ func MatchBalanceExemption(
 
 	return nil
 }
+
+// ClassifiedBalanceChange is a *BalanceChange and all
+// *types.BalanceExemption that apply to its account and
+// currency.
+type ClassifiedBalanceChange struct {
+	*BalanceChange
+
+	Exemptions []*types.BalanceExemption `json:"exemptions,omitempty"`
+}
+
+// Exempt returns a boolean indicating if any *types.BalanceExemption
+// applies to the *ClassifiedBalanceChange. The balance of an exempt
+// account and currency may change without a corresponding operation
+// (ex: dynamic or interest-bearing balances), so computed balance
+// changes may not be authoritative.
+func (c *ClassifiedBalanceChange) Exempt() bool {
+	return len(c.Exemptions) > 0
+}
+
+// ClassifyBalanceChanges returns a *ClassifiedBalanceChange for each
+// *BalanceChange, populated with any matching BalanceExemptions
+// provided to the parser.
+func (p *Parser) ClassifyBalanceChanges(
+	changes []*BalanceChange,
+) []*ClassifiedBalanceChange {
+	classified := make([]*ClassifiedBalanceChange, len(changes))
+	for i, change := range changes {
+		classified[i] = &ClassifiedBalanceChange{
+			BalanceChange: change,
+			Exemptions:    p.FindExemptions(change.Account, change.Currency),
+		}
+	}
+
+	return classified
+}
+
+// SplitBalanceChanges separates balance changes into those
+// that are exempt (have at least one matching BalanceExemption)
+// and those that are not. This is useful for callers that
+// want to skip or specially handle exempt balances.
+func (p *Parser) SplitBalanceChanges(
+	changes []*BalanceChange,
+) ([]*BalanceChange, []*BalanceChange) {
+	exempt := []*BalanceChange{}
+	nonExempt := []*BalanceChange{}
+	for _, change := range p.ClassifyBalanceChanges(changes) {
+		if change.Exempt() {
+			exempt = append(exempt, change.BalanceChange)
+			continue
+		}
+
+		nonExempt = append(nonExempt, change.BalanceChange)
+	}
+
+	return exempt, nonExempt
+}
@@ -462,3 +462,86 @@ func TestMatchBalanceExemption(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyBalanceChanges(t *testing.T) {
+	var (
+		btc = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+
+		eth = &types.Currency{
+			Symbol:   "ETH",
+			Decimals: 18,
+		}
+
+		dynamicExemption = &types.BalanceExemption{
+			ExemptionType: types.BalanceDynamic,
+			Currency:      eth,
+		}
+
+		interestExemption = &types.BalanceExemption{
+			ExemptionType:     types.BalanceGreaterOrEqual,
+			SubAccountAddress: stringPointer("interest"),
+		}
+
+		btcChange = &BalanceChange{
+			Account: &types.AccountIdentifier{
+				Address: "test",
+			},
+			Currency:   btc,
+			Difference: "100",
+		}
+
+		ethChange = &BalanceChange{
+			Account: &types.AccountIdentifier{
+				Address: "test",
+			},
+			Currency:   eth,
+			Difference: "100",
+		}
+
+		interestChange = &BalanceChange{
+			Account: &types.AccountIdentifier{
+				Address: "test",
+				SubAccount: &types.SubAccountIdentifier{
+					Address: "interest",
+				},
+			},
+			Currency:   btc,
+			Difference: "-5",
+		}
+
+		changes = []*BalanceChange{btcChange, ethChange, interestChange}
+	)
+
+	p := New(nil, nil, []*types.BalanceExemption{dynamicExemption, interestExemption})
+
+	classified := p.ClassifyBalanceChanges(changes)
+	assert.Equal(t, []*ClassifiedBalanceChange{
+		{
+			BalanceChange: btcChange,
+			Exemptions:    []*types.BalanceExemption{},
+		},
+		{
+			BalanceChange: ethChange,
+			Exemptions:    []*types.BalanceExemption{dynamicExemption},
+		},
+		{
+			BalanceChange: interestChange,
+			Exemptions:    []*types.BalanceExemption{interestExemption},
+		},
+	}, classified)
+	assert.False(t, classified[0].Exempt())
+	assert.True(t, classified[1].Exempt())
+	assert.True(t, classified[2].Exempt())
+
+	exempt, nonExempt := p.SplitBalanceChanges(changes)
+	assert.Equal(t, []*BalanceChange{ethChange, interestChange}, exempt)
+	assert.Equal(t, []*BalanceChange{btcChange}, nonExempt)
+
+	// Without exemptions, nothing should be exempt.
+	exempt, nonExempt = New(nil, nil, nil).SplitBalanceChanges(changes)
+	assert.Empty(t, exempt)
+	assert.Equal(t, changes, nonExempt)
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// ExpectedFee returns the total fee paid in a slice of
+// operations, summed per *types.Currency (in order of first
+// appearance). An operation is considered a fee operation if its
+// Type is in feeOperationTypes.
+//
+// Fees are represented in Rosetta by operations that debit the
+// payer (negative amounts), so fee operations that credit an
+// account (ex: a fee collector or miner) are ignored to avoid
+// double counting. The returned amounts are positive.
+func ExpectedFee(
+	ops []*types.Operation,
+	feeOperationTypes []string,
+) ([]*types.Amount, error) {
+	feeTypes := map[string]struct{}{}
+	for _, feeType := range feeOperationTypes {
+		feeTypes[feeType] = struct{}{}
+	}
+
+	fees := []*types.Amount{}
+	sums := map[string]*big.Int{}
+	for _, op := range ops {
+		if _, ok := feeTypes[op.Type]; !ok {
+			continue
+		}
+
+		if op.Amount == nil {
+			continue
+		}
+
+		val, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse fee amount", err)
+		}
+
+		if val.Sign() >= 0 {
+			continue
+		}
+
+		key := types.Hash(op.Amount.Currency)
+		sum, ok := sums[key]
+		if !ok {
+			sum = big.NewInt(0)
+			sums[key] = sum
+			fees = append(fees, &types.Amount{Currency: op.Amount.Currency})
+		}
+
+		sum.Sub(sum, val)
+	}
+
+	for _, fee := range fees {
+		fee.Value = sums[types.Hash(fee.Currency)].String()
+	}
+
+	return fees, nil
+}
+
+// ValidateFee returns an error if the fee paid in a slice
+// of operations (as computed by ExpectedFee) is greater than
+// maxFee or if any fee is paid in a *types.Currency other than
+// currency.
+func ValidateFee(
+	ops []*types.Operation,
+	feeOperationTypes []string,
+	maxFee *big.Int,
+	currency *types.Currency,
+) error {
+	fees, err := ExpectedFee(ops, feeOperationTypes)
+	if err != nil {
+		return err
+	}
+
+	for _, fee := range fees {
+		if !fee.Currency.Equal(currency) {
+			return fmt.Errorf(
+				"%w: expected %s but got %s",
+				ErrFeeUnexpectedCurrency,
+				types.CurrencyString(currency),
+				types.CurrencyString(fee.Currency),
+			)
+		}
+
+		val, err := types.AmountValue(fee)
+		if err != nil {
+			return err
+		}
+
+		if val.Cmp(maxFee) > 0 {
+			return fmt.Errorf(
+				"%w: %s is greater than %s",
+				ErrFeeExceedsMax,
+				val.String(),
+				maxFee.String(),
+			)
+		}
+	}
+
+	return nil
+}
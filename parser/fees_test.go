@@ -0,0 +1,198 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	feeCurrency = &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+
+	otherFeeCurrency = &types.Currency{
+		Symbol:   "USDC",
+		Decimals: 6,
+	}
+
+	feeOps = []*types.Operation{
+		{
+			Type: "TRANSFER",
+			Amount: &types.Amount{
+				Value:    "-1000",
+				Currency: feeCurrency,
+			},
+		},
+		{
+			Type: "FEE",
+			Amount: &types.Amount{
+				Value:    "-10",
+				Currency: feeCurrency,
+			},
+		},
+		{
+			Type: "FEE",
+			Amount: &types.Amount{
+				Value:    "10",
+				Currency: feeCurrency,
+			},
+		},
+		{
+			Type: "BURN",
+			Amount: &types.Amount{
+				Value:    "-5",
+				Currency: feeCurrency,
+			},
+		},
+		{
+			Type: "FEE",
+		},
+	}
+)
+
+func TestExpectedFee(t *testing.T) {
+	var tests = map[string]struct {
+		ops      []*types.Operation
+		feeTypes []string
+
+		fees []*types.Amount
+		err  bool
+	}{
+		"single fee type": {
+			ops:      feeOps,
+			feeTypes: []string{"FEE"},
+			fees: []*types.Amount{
+				{
+					Value:    "10",
+					Currency: feeCurrency,
+				},
+			},
+		},
+		"multiple fee types": {
+			ops:      feeOps,
+			feeTypes: []string{"FEE", "BURN"},
+			fees: []*types.Amount{
+				{
+					Value:    "15",
+					Currency: feeCurrency,
+				},
+			},
+		},
+		"multiple currencies": {
+			ops: append(feeOps, &types.Operation{
+				Type: "FEE",
+				Amount: &types.Amount{
+					Value:    "-3",
+					Currency: otherFeeCurrency,
+				},
+			}),
+			feeTypes: []string{"FEE"},
+			fees: []*types.Amount{
+				{
+					Value:    "10",
+					Currency: feeCurrency,
+				},
+				{
+					Value:    "3",
+					Currency: otherFeeCurrency,
+				},
+			},
+		},
+		"no fees": {
+			ops:      feeOps,
+			feeTypes: []string{"REWARD"},
+			fees:     []*types.Amount{},
+		},
+		"invalid amount": {
+			ops: []*types.Operation{
+				{
+					Type: "FEE",
+					Amount: &types.Amount{
+						Value:    "hello",
+						Currency: feeCurrency,
+					},
+				},
+			},
+			feeTypes: []string{"FEE"},
+			err:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fees, err := ExpectedFee(test.ops, test.feeTypes)
+			if test.err {
+				assert.Error(t, err)
+				assert.Nil(t, fees)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.fees, fees)
+		})
+	}
+}
+
+func TestValidateFee(t *testing.T) {
+	var tests = map[string]struct {
+		ops      []*types.Operation
+		maxFee   *big.Int
+		currency *types.Currency
+
+		err error
+	}{
+		"fee below max": {
+			ops:      feeOps,
+			maxFee:   big.NewInt(20),
+			currency: feeCurrency,
+		},
+		"fee equal to max": {
+			ops:      feeOps,
+			maxFee:   big.NewInt(10),
+			currency: feeCurrency,
+		},
+		"fee above max": {
+			ops:      feeOps,
+			maxFee:   big.NewInt(9),
+			currency: feeCurrency,
+			err:      ErrFeeExceedsMax,
+		},
+		"unexpected currency": {
+			ops:      feeOps,
+			maxFee:   big.NewInt(20),
+			currency: otherFeeCurrency,
+			err:      ErrFeeUnexpectedCurrency,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateFee(test.ops, []string{"FEE"}, test.maxFee, test.currency)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -26,7 +26,7 @@ import (
 // to be different from the intent if the AccountIdentifier,
 // Amount, or Type has changed.
 func ExpectedOperation(intent *types.Operation, observed *types.Operation) error {
-	if types.Hash(intent.Account) != types.Hash(observed.Account) {
+	if !intent.Account.Equal(observed.Account) {
 		return fmt.Errorf(
 			"%w: expected %s but got %s",
 			ErrExpectedOperationAccountMismatch,
@@ -35,7 +35,7 @@ func ExpectedOperation(intent *types.Operation, observed *types.Operation) error
 		)
 	}
 
-	if types.Hash(intent.Amount) != types.Hash(observed.Amount) {
+	if !intent.Amount.Equal(observed.Amount) {
 		return fmt.Errorf(
 			"%w: expected %s but got %s",
 			ErrExpectedOperationAmountMismatch,
@@ -60,13 +60,22 @@ func ExpectedOperation(intent *types.Operation, observed *types.Operation) error
 // operations differ from observed operations. Optionally,
 // it is possible to error if any extra observed opertions
 // are found or if operations matched are not considered
-// successful.
+// successful. If MetadataSchemas are provided, the metadata of
+// all intended and observed operations is validated.
 func (p *Parser) ExpectedOperations(
 	intent []*types.Operation,
 	observed []*types.Operation,
 	errExtra bool,
 	confirmSuccess bool,
 ) error {
+	if err := p.ValidateOperationsMetadata(intent); err != nil {
+		return fmt.Errorf("%w: intent metadata invalid", err)
+	}
+
+	if err := p.ValidateOperationsMetadata(observed); err != nil {
+		return fmt.Errorf("%w: observed metadata invalid", err)
+	}
+
 	matches := make(map[int]struct{})
 	failedMatches := []*types.Operation{}
 
@@ -0,0 +1,335 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// AccountField is the FieldDiff.Field used for
+	// *types.AccountIdentifier differences.
+	AccountField = "account"
+
+	// AmountField is the FieldDiff.Field used for
+	// *types.Amount differences.
+	AmountField = "amount"
+
+	// TypeField is the FieldDiff.Field used for
+	// operation type differences.
+	TypeField = "type"
+)
+
+// IndexedOperation is a *types.Operation and its index
+// in the slice of intended or observed operations.
+type IndexedOperation struct {
+	Index     int              `json:"index"`
+	Operation *types.Operation `json:"operation"`
+}
+
+// FieldDiff describes a difference in a single field
+// between an intended and an observed operation.
+type FieldDiff struct {
+	Field    string `json:"field"`
+	Intent   string `json:"intent"`
+	Observed string `json:"observed"`
+}
+
+// OperationMismatch is an intended operation and the most
+// similar observed operation that did not match it.
+type OperationMismatch struct {
+	Intent   *IndexedOperation `json:"intent"`
+	Observed *IndexedOperation `json:"observed"`
+	Fields   []*FieldDiff      `json:"fields"`
+}
+
+// IntentDiff is a structured description of all differences
+// between a slice of intended operations and a slice of
+// observed operations.
+type IntentDiff struct {
+	// MissingIntent are intended operations that could
+	// not be matched or paired with any observed operation
+	// (because there are more intended operations than
+	// observed operations).
+	MissingIntent []*IndexedOperation `json:"missing_intent,omitempty"`
+
+	// ExtraObserved are observed operations that could
+	// not be matched or paired with any intended operation
+	// (because there are more observed operations than
+	// intended operations).
+	ExtraObserved []*IndexedOperation `json:"extra_observed,omitempty"`
+
+	// Mismatches are intended operations paired with the
+	// most similar unmatched observed operation.
+	Mismatches []*OperationMismatch `json:"mismatches,omitempty"`
+
+	// Unsuccessful are observed operations that matched an
+	// intended operation but were not successful. This is
+	// only populated when confirmSuccess is true. The intended
+	// operations they matched are not reported elsewhere.
+	Unsuccessful []*IndexedOperation `json:"unsuccessful,omitempty"`
+}
+
+// Empty returns a boolean indicating if there are no
+// differences between intent and observed.
+func (d *IntentDiff) Empty() bool {
+	return len(d.MissingIntent) == 0 &&
+		len(d.ExtraObserved) == 0 &&
+		len(d.Mismatches) == 0 &&
+		len(d.Unsuccessful) == 0
+}
+
+// Report returns a human-readable, multi-line
+// description of an *IntentDiff.
+func (d *IntentDiff) Report() string {
+	if d.Empty() {
+		return "intent matches observed operations\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("intent does not match observed operations\n")
+
+	if len(d.Mismatches) > 0 {
+		b.WriteString("  mismatched operations:\n")
+		for _, mismatch := range d.Mismatches {
+			fmt.Fprintf(
+				&b,
+				"    intent[%d] != observed[%d]\n",
+				mismatch.Intent.Index,
+				mismatch.Observed.Index,
+			)
+			for _, field := range mismatch.Fields {
+				fmt.Fprintf(
+					&b,
+					"      %s: expected %s but got %s\n",
+					field.Field,
+					field.Intent,
+					field.Observed,
+				)
+			}
+		}
+	}
+
+	writeOperations(&b, "missing intent operations", "intent", d.MissingIntent)
+	writeOperations(&b, "extra observed operations", "observed", d.ExtraObserved)
+	writeOperations(
+		&b,
+		"matched operations with unsuccessful status",
+		"observed",
+		d.Unsuccessful,
+	)
+
+	return b.String()
+}
+
+func writeOperations(b *strings.Builder, title string, label string, ops []*IndexedOperation) {
+	if len(ops) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "  %s:\n", title)
+	for _, op := range ops {
+		fmt.Fprintf(
+			b,
+			"    %s[%d] %s=%s %s=%s %s=%s\n",
+			label,
+			op.Index,
+			TypeField,
+			op.Operation.Type,
+			AccountField,
+			accountDiffString(op.Operation.Account),
+			AmountField,
+			amountDiffString(op.Operation.Amount),
+		)
+	}
+}
+
+func accountDiffString(account *types.AccountIdentifier) string {
+	if account == nil {
+		return "<nil>"
+	}
+
+	return types.AccountString(account)
+}
+
+func amountDiffString(amount *types.Amount) string {
+	if amount == nil {
+		return "<nil>"
+	}
+
+	if amount.Currency == nil {
+		return amount.Value
+	}
+
+	return fmt.Sprintf("%s %s", amount.Value, types.CurrencyString(amount.Currency))
+}
+
+// OperationFieldDiffs returns all field-level differences
+// between an intended and an observed operation (using the same
+// fields compared in ExpectedOperation).
+func OperationFieldDiffs(intent *types.Operation, observed *types.Operation) []*FieldDiff {
+	diffs := []*FieldDiff{}
+	if !intent.Account.Equal(observed.Account) {
+		diffs = append(diffs, &FieldDiff{
+			Field:    AccountField,
+			Intent:   accountDiffString(intent.Account),
+			Observed: accountDiffString(observed.Account),
+		})
+	}
+
+	if !intent.Amount.Equal(observed.Amount) {
+		diffs = append(diffs, &FieldDiff{
+			Field:    AmountField,
+			Intent:   amountDiffString(intent.Amount),
+			Observed: amountDiffString(observed.Amount),
+		})
+	}
+
+	if intent.Type != observed.Type {
+		diffs = append(diffs, &FieldDiff{
+			Field:    TypeField,
+			Intent:   intent.Type,
+			Observed: observed.Type,
+		})
+	}
+
+	return diffs
+}
+
+// ExpectedOperationsDiff compares a slice of intended operations
+// with observed operations (using the same matching rules as
+// ExpectedOperations) and returns an *IntentDiff describing all
+// differences instead of the first error encountered. Each
+// difference is reported once: unmatched intended operations are
+// paired with the most similar unmatched observed operations and
+// only the remaining operations are reported as missing or extra.
+// An error is only returned if operation success cannot be
+// determined.
+func (p *Parser) ExpectedOperationsDiff(
+	intent []*types.Operation,
+	observed []*types.Operation,
+	confirmSuccess bool,
+) (*IntentDiff, error) {
+	diff := &IntentDiff{}
+	matches := make(map[int]struct{})
+	unmatchedObserved := []int{}
+
+	for j, obs := range observed {
+		foundMatch := false
+		unsuccessfulIndex := -1
+		for i, in := range intent {
+			if _, exists := matches[i]; exists {
+				continue
+			}
+
+			if err := ExpectedOperation(in, obs); err != nil {
+				continue
+			}
+
+			if confirmSuccess {
+				obsSuccess, err := p.Asserter.OperationSuccessful(obs)
+				if err != nil {
+					return nil, fmt.Errorf("%w: unable to check operation success", err)
+				}
+
+				if !obsSuccess {
+					if unsuccessfulIndex == -1 {
+						unsuccessfulIndex = i
+					}
+
+					continue
+				}
+			}
+
+			matches[i] = struct{}{}
+			foundMatch = true
+			break
+		}
+
+		switch {
+		case foundMatch:
+		case unsuccessfulIndex != -1:
+			// The intended operation is accounted for by
+			// the unsuccessful observed operation.
+			matches[unsuccessfulIndex] = struct{}{}
+			diff.Unsuccessful = append(diff.Unsuccessful, &IndexedOperation{
+				Index:     j,
+				Operation: obs,
+			})
+		default:
+			unmatchedObserved = append(unmatchedObserved, j)
+		}
+	}
+
+	// Pair each unmatched intent with the most similar
+	// unmatched observed operation.
+	paired := make(map[int]struct{})
+	for i, in := range intent {
+		if _, exists := matches[i]; exists {
+			continue
+		}
+
+		bestIndex := -1
+		var bestDiffs []*FieldDiff
+		for _, j := range unmatchedObserved {
+			if _, exists := paired[j]; exists {
+				continue
+			}
+
+			diffs := OperationFieldDiffs(in, observed[j])
+			if bestIndex == -1 || len(diffs) < len(bestDiffs) {
+				bestIndex = j
+				bestDiffs = diffs
+			}
+		}
+
+		if bestIndex == -1 {
+			diff.MissingIntent = append(diff.MissingIntent, &IndexedOperation{
+				Index:     i,
+				Operation: in,
+			})
+			continue
+		}
+
+		paired[bestIndex] = struct{}{}
+		diff.Mismatches = append(diff.Mismatches, &OperationMismatch{
+			Intent: &IndexedOperation{
+				Index:     i,
+				Operation: in,
+			},
+			Observed: &IndexedOperation{
+				Index:     bestIndex,
+				Operation: observed[bestIndex],
+			},
+			Fields: bestDiffs,
+		})
+	}
+
+	for _, j := range unmatchedObserved {
+		if _, exists := paired[j]; exists {
+			continue
+		}
+
+		diff.ExtraObserved = append(diff.ExtraObserved, &IndexedOperation{
+			Index:     j,
+			Operation: observed[j],
+		})
+	}
+
+	return diff, nil
+}
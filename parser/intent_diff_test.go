@@ -0,0 +1,220 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestExpectedOperationsDiff(t *testing.T) {
+	var (
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+
+		sender = &types.Operation{
+			Type: "transfer",
+			Account: &types.AccountIdentifier{
+				Address: "addr1",
+			},
+			Amount: &types.Amount{
+				Value:    "-100",
+				Currency: currency,
+			},
+		}
+
+		recipient = &types.Operation{
+			Type: "transfer",
+			Account: &types.AccountIdentifier{
+				Address: "addr2",
+			},
+			Amount: &types.Amount{
+				Value:    "100",
+				Currency: currency,
+			},
+		}
+
+		wrongRecipient = &types.Operation{
+			Type: "transfer",
+			Account: &types.AccountIdentifier{
+				Address: "addr2",
+			},
+			Amount: &types.Amount{
+				Value:    "90",
+				Currency: currency,
+			},
+		}
+
+		fee = &types.Operation{
+			Type: "fee",
+			Account: &types.AccountIdentifier{
+				Address: "addr3",
+			},
+		}
+
+		failedSender = &types.Operation{
+			Type:    sender.Type,
+			Account: sender.Account,
+			Amount:  sender.Amount,
+			Status:  types.String("failure"),
+		}
+	)
+
+	var tests = map[string]struct {
+		intent         []*types.Operation
+		observed       []*types.Operation
+		confirmSuccess bool
+
+		diff   *IntentDiff
+		report string
+	}{
+		"no differences": {
+			intent:   []*types.Operation{sender, recipient},
+			observed: []*types.Operation{recipient, sender},
+			diff:     &IntentDiff{},
+			report:   "intent matches observed operations\n",
+		},
+		"field mismatch and extra operation": {
+			intent:   []*types.Operation{sender, recipient},
+			observed: []*types.Operation{sender, wrongRecipient, fee},
+			diff: &IntentDiff{
+				Mismatches: []*OperationMismatch{
+					{
+						Intent:   &IndexedOperation{Index: 1, Operation: recipient},
+						Observed: &IndexedOperation{Index: 1, Operation: wrongRecipient},
+						Fields: []*FieldDiff{
+							{
+								Field:    AmountField,
+								Intent:   "100 BTC:8",
+								Observed: "90 BTC:8",
+							},
+						},
+					},
+				},
+				ExtraObserved: []*IndexedOperation{
+					{Index: 2, Operation: fee},
+				},
+			},
+			report: "intent does not match observed operations\n" +
+				"  mismatched operations:\n" +
+				"    intent[1] != observed[1]\n" +
+				"      amount: expected 100 BTC:8 but got 90 BTC:8\n" +
+				"  extra observed operations:\n" +
+				"    observed[2] type=fee account=addr3 amount=<nil>\n",
+		},
+		"missing intent": {
+			intent:   []*types.Operation{sender, fee},
+			observed: []*types.Operation{sender},
+			diff: &IntentDiff{
+				MissingIntent: []*IndexedOperation{
+					{Index: 1, Operation: fee},
+				},
+			},
+			report: "intent does not match observed operations\n" +
+				"  missing intent operations:\n" +
+				"    intent[1] type=fee account=addr3 amount=<nil>\n",
+		},
+		"all fields mismatched": {
+			intent:   []*types.Operation{sender, fee},
+			observed: []*types.Operation{sender, recipient},
+			diff: &IntentDiff{
+				Mismatches: []*OperationMismatch{
+					{
+						Intent:   &IndexedOperation{Index: 1, Operation: fee},
+						Observed: &IndexedOperation{Index: 1, Operation: recipient},
+						Fields: []*FieldDiff{
+							{
+								Field:    AccountField,
+								Intent:   "addr3",
+								Observed: "addr2",
+							},
+							{
+								Field:    AmountField,
+								Intent:   "<nil>",
+								Observed: "100 BTC:8",
+							},
+							{
+								Field:    TypeField,
+								Intent:   "fee",
+								Observed: "transfer",
+							},
+						},
+					},
+				},
+			},
+			report: "intent does not match observed operations\n" +
+				"  mismatched operations:\n" +
+				"    intent[1] != observed[1]\n" +
+				"      account: expected addr3 but got addr2\n" +
+				"      amount: expected <nil> but got 100 BTC:8\n" +
+				"      type: expected fee but got transfer\n",
+		},
+		"unsuccessful match": {
+			intent:         []*types.Operation{sender},
+			observed:       []*types.Operation{failedSender},
+			confirmSuccess: true,
+			diff: &IntentDiff{
+				Unsuccessful: []*IndexedOperation{
+					{Index: 0, Operation: failedSender},
+				},
+			},
+			report: "intent does not match observed operations\n" +
+				"  matched operations with unsuccessful status:\n" +
+				"    observed[0] type=transfer account=addr1 amount=-100 BTC:8\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "success",
+					Successful: true,
+				},
+				{
+					Status:     "failure",
+					Successful: false,
+				},
+			})
+			assert.NoError(t, err)
+
+			parser := New(asserter, nil, nil)
+			diff, err := parser.ExpectedOperationsDiff(
+				test.intent,
+				test.observed,
+				test.confirmSuccess,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.diff, diff)
+			assert.Equal(t, test.diff.Empty(), diff.Empty())
+			assert.Equal(t, test.report, diff.Report())
+
+			// ExpectedOperations should error iff the diff is not empty
+			// (when erroring on extra operations).
+			err = parser.ExpectedOperations(
+				test.intent,
+				test.observed,
+				true,
+				test.confirmSuccess,
+			)
+			assert.Equal(t, !diff.Empty(), err != nil)
+		})
+	}
+}
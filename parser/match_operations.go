@@ -18,6 +18,10 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -101,6 +105,76 @@ func (s AmountSign) String() string {
 type MetadataDescription struct {
 	Key       string
 	ValueKind reflect.Kind // ex: reflect.String
+
+	// Path is a dot-separated path (ex: "fee.payer" or "inputs.0.id")
+	// used to look up nested values. If Path is populated, it is
+	// used instead of Key.
+	Path string
+
+	// ValuePattern is an optional regular expression that the
+	// value (formatted as a string) must match.
+	ValuePattern *regexp.Regexp
+}
+
+// AmountComparison is used to represent the relationship
+// enforced by an AmountRelation.
+type AmountComparison int
+
+const (
+	// EqualAmountComparison requires both sides
+	// of an AmountRelation to be equal.
+	EqualAmountComparison = 0
+
+	// GreaterOrEqualAmountComparison requires the left side
+	// of an AmountRelation to be greater than or equal to
+	// the right side.
+	GreaterOrEqualAmountComparison = 1
+
+	// LessOrEqualAmountComparison requires the left side
+	// of an AmountRelation to be less than or equal to
+	// the right side.
+	LessOrEqualAmountComparison = 2
+)
+
+// String returns a description of an AmountComparison.
+func (c AmountComparison) String() string {
+	switch c {
+	case EqualAmountComparison:
+		return "equal to"
+	case GreaterOrEqualAmountComparison:
+		return "greater than or equal to"
+	case LessOrEqualAmountComparison:
+		return "less than or equal to"
+	default:
+		return "invalid"
+	}
+}
+
+// AmountRelation is used to compare the sum of the absolute
+// values of all operations matched to the OperationDescriptions at
+// Left with the sum of those at Right. For example, a UTXO transfer
+// where the sum of inputs must equal the sum of outputs plus a fee
+// could be described as {Left: {0}, Right: {1, 2}}.
+type AmountRelation struct {
+	Left       []int
+	Right      []int
+	Comparison AmountComparison
+}
+
+// AmountProportion is used to require that the sum of the absolute
+// decimal values of all operations matched to the OperationDescription
+// at Target is Ratio times the sum of those at Base (within a relative
+// Tolerance). For example, a staking reward that must be 5% of the
+// staked amount (+/- 0.1%) could be described as
+// {Base: 0, Target: 1, Ratio: big.NewRat(5, 100), Tolerance: big.NewRat(1, 1000)}.
+//
+// Because decimal values are compared, Base and Target may be
+// denominated in different currencies.
+type AmountProportion struct {
+	Base      int
+	Target    int
+	Ratio     *big.Rat
+	Tolerance *big.Rat
 }
 
 // AccountDescription is used to describe a *types.AccountIdentifier.
@@ -128,6 +202,11 @@ type OperationDescription struct {
 	// any type is considered a match.
 	Type string
 
+	// Status is the operation.Status that must match. If this is left
+	// empty, any status (including no status) is considered a match. This
+	// is ignored when Descriptions.Mempool is true.
+	Status string
+
 	// AllowRepeats indicates that multiple operations can be matched
 	// to a particular description.
 	AllowRepeats bool
@@ -140,6 +219,17 @@ type OperationDescription struct {
 	// and that it should have the CoinAction. If this is not populated,
 	// CoinChange is not checked.
 	CoinAction types.CoinAction
+
+	// MinMatches is the minimum number of operations that must
+	// be matched to this description (if any are matched). This is
+	// only useful when AllowRepeats is true.
+	MinMatches int
+
+	// MaxMatches is the maximum number of operations that can
+	// be matched to this description. Once MaxMatches is reached,
+	// operations are matched to other descriptions. If this is 0,
+	// there is no limit.
+	MaxMatches int
 }
 
 // Descriptions contains a slice of OperationDescriptions and
@@ -167,9 +257,63 @@ type Descriptions struct {
 	// will error if all groups of operations addresses aren't equal.
 	EqualAddresses [][]int
 
+	// AmountRelations are specified using the operation indices of
+	// OperationDescriptions to handle out of order matches. MatchOperations
+	// will error if any AmountRelation is not met.
+	AmountRelations []*AmountRelation
+
+	// AmountProportions are specified using the operation indices of
+	// OperationDescriptions to handle out of order matches. MatchOperations
+	// will error if any AmountProportion is not met.
+	AmountProportions []*AmountProportion
+
+	// DecimalAware indicates that EqualAmounts, OppositeAmounts, and
+	// OppositeOrZeroAmounts should compare values after accounting for
+	// Currency.Decimals (ex: 1000 with 3 decimals is equal to 1 with
+	// 0 decimals).
+	DecimalAware bool
+
 	// ErrUnmatched indicates that an error should be returned
 	// if all operations cannot be matched to a description.
 	ErrUnmatched bool
+
+	// Mempool indicates that operations are unconfirmed (ex: returned
+	// from /construction/parse or /mempool/transaction). In this mode,
+	// operation statuses are ignored and operations are matched in a
+	// canonical order (instead of OperationIdentifier order), so the same
+	// Descriptions can be used before and after confirmation.
+	Mempool bool
+}
+
+// metadataValue returns the value associated with a *MetadataDescription
+// in a map[string]interface{}.
+func metadataValue(req *MetadataDescription, metadata map[string]interface{}) (interface{}, bool) {
+	if len(req.Path) == 0 {
+		val, ok := metadata[req.Key]
+		return val, ok
+	}
+
+	var current interface{} = metadata
+	for _, component := range strings.Split(req.Path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			val, ok := node[component]
+			if !ok {
+				return nil, false
+			}
+			current = val
+		case []interface{}:
+			index, err := strconv.Atoi(component)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
 }
 
 // metadataMatch returns an error if a map[string]interface does not meet
@@ -180,19 +324,33 @@ func metadataMatch(reqs []*MetadataDescription, metadata map[string]interface{})
 	}
 
 	for _, req := range reqs {
-		val, ok := metadata[req.Key]
+		name := req.Key
+		if len(req.Path) > 0 {
+			name = req.Path
+		}
+
+		val, ok := metadataValue(req, metadata)
 		if !ok {
-			return fmt.Errorf("%w: %s", ErrMetadataMatchKeyNotFound, req.Key)
+			return fmt.Errorf("%w: %s", ErrMetadataMatchKeyNotFound, name)
 		}
 
-		if reflect.TypeOf(val).Kind() != req.ValueKind {
+		if val == nil || reflect.TypeOf(val).Kind() != req.ValueKind {
 			return fmt.Errorf(
 				"%w: value of %s is not of type %s",
 				ErrMetadataMatchKeyValueMismatch,
-				req.Key,
+				name,
 				req.ValueKind,
 			)
 		}
+
+		if req.ValuePattern != nil && !req.ValuePattern.MatchString(fmt.Sprint(val)) {
+			return fmt.Errorf(
+				"%w: value of %s does not match %s",
+				ErrMetadataMatchKeyValueMismatch,
+				name,
+				req.ValuePattern.String(),
+			)
+		}
 	}
 
 	return nil
@@ -270,7 +428,7 @@ func amountMatch(req *AmountDescription, amount *types.Amount) error {
 		return nil
 	}
 
-	if amount.Currency == nil || types.Hash(amount.Currency) != types.Hash(req.Currency) {
+	if amount.Currency == nil || !amount.Currency.Equal(req.Currency) {
 		return fmt.Errorf(
 			"%w: expected %+v but got %+v",
 			ErrAmountMatchUnexpectedCurrency,
@@ -309,16 +467,27 @@ func operationMatch(
 	operation *types.Operation,
 	descriptions []*OperationDescription,
 	matches []*Match,
+	ignoreStatus bool,
 ) bool {
 	for i, des := range descriptions {
 		if matches[i] != nil && !des.AllowRepeats { // already matched
 			continue
 		}
 
+		if matches[i] != nil && des.MaxMatches > 0 &&
+			len(matches[i].Operations) >= des.MaxMatches {
+			continue
+		}
+
 		if len(des.Type) > 0 && des.Type != operation.Type {
 			continue
 		}
 
+		if !ignoreStatus && len(des.Status) > 0 &&
+			(operation.Status == nil || *operation.Status != des.Status) {
+			continue
+		}
+
 		if err := accountMatch(des.Account, operation.Account); err != nil {
 			continue
 		}
@@ -364,17 +533,27 @@ func operationMatch(
 // equalAmounts returns an error if a slice of operations do not have
 // equal amounts.
 func equalAmounts(ops []*types.Operation) error {
+	return equalAmountValues(ops, false)
+}
+
+// equalDecimalAmounts returns an error if a slice of operations do not
+// have equal amounts after accounting for Currency.Decimals.
+func equalDecimalAmounts(ops []*types.Operation) error {
+	return equalAmountValues(ops, true)
+}
+
+func equalAmountValues(ops []*types.Operation, decimal bool) error {
 	if len(ops) == 0 {
 		return ErrEqualAmountsNoOperations
 	}
 
-	val, err := types.AmountValue(ops[0].Amount)
+	val, err := amountValue(ops[0].Amount, decimal)
 	if err != nil {
 		return err
 	}
 
 	for _, op := range ops {
-		otherVal, err := types.AmountValue(op.Amount)
+		otherVal, err := amountValue(op.Amount, decimal)
 		if err != nil {
 			return err
 		}
@@ -383,8 +562,8 @@ func equalAmounts(ops []*types.Operation) error {
 			return fmt.Errorf(
 				"%w: %s is not equal to %s",
 				ErrEqualAmountsNotEqual,
-				val.String(),
-				otherVal.String(),
+				val.RatString(),
+				otherVal.RatString(),
 			)
 		}
 	}
@@ -395,60 +574,63 @@ func equalAmounts(ops []*types.Operation) error {
 // oppositeAmounts returns an error if two operations do not have opposite
 // amounts.
 func oppositeAmounts(a *types.Operation, b *types.Operation) error {
-	aVal, err := types.AmountValue(a.Amount)
-	if err != nil {
-		return err
-	}
-
-	bVal, err := types.AmountValue(b.Amount)
-	if err != nil {
-		return err
-	}
-
-	if aVal.Sign() == bVal.Sign() {
-		return fmt.Errorf("%w: %s and %s", ErrOppositeAmountsSameSign, aVal.String(), bVal.String())
-	}
-
-	if aVal.CmpAbs(bVal) != 0 {
-		return fmt.Errorf(
-			"%w: %s and %s",
-			ErrOppositeAmountsAbsValMismatch,
-			aVal.String(),
-			bVal.String(),
-		)
-	}
-
-	return nil
+	return oppositeAmountValues(a, b, false, false)
 }
 
 // oppositeOrZeroAmounts returns an error if two operations do not have opposite
 // amounts and both amounts are not zero.
 func oppositeOrZeroAmounts(a *types.Operation, b *types.Operation) error {
-	aVal, err := types.AmountValue(a.Amount)
+	return oppositeAmountValues(a, b, false, true)
+}
+
+// oppositeDecimalAmounts returns an error if two operations do not have
+// opposite amounts after accounting for Currency.Decimals.
+func oppositeDecimalAmounts(a *types.Operation, b *types.Operation) error {
+	return oppositeAmountValues(a, b, true, false)
+}
+
+// oppositeOrZeroDecimalAmounts returns an error if two operations do not
+// have opposite amounts after accounting for Currency.Decimals and both
+// amounts are not zero.
+func oppositeOrZeroDecimalAmounts(a *types.Operation, b *types.Operation) error {
+	return oppositeAmountValues(a, b, true, true)
+}
+
+func oppositeAmountValues(
+	a *types.Operation,
+	b *types.Operation,
+	decimal bool,
+	allowZero bool,
+) error {
+	aVal, err := amountValue(a.Amount, decimal)
 	if err != nil {
 		return err
 	}
 
-	bVal, err := types.AmountValue(b.Amount)
+	bVal, err := amountValue(b.Amount, decimal)
 	if err != nil {
 		return err
 	}
 
-	zero := big.NewInt(0)
-	if aVal.Cmp(zero) == 0 && bVal.Cmp(zero) == 0 {
+	if allowZero && aVal.Sign() == 0 && bVal.Sign() == 0 {
 		return nil
 	}
 
 	if aVal.Sign() == bVal.Sign() {
-		return fmt.Errorf("%w: %s and %s", ErrOppositeAmountsSameSign, aVal.String(), bVal.String())
+		return fmt.Errorf(
+			"%w: %s and %s",
+			ErrOppositeAmountsSameSign,
+			aVal.RatString(),
+			bVal.RatString(),
+		)
 	}
 
-	if aVal.CmpAbs(bVal) != 0 {
+	if new(big.Rat).Abs(aVal).Cmp(new(big.Rat).Abs(bVal)) != 0 {
 		return fmt.Errorf(
 			"%w: %s and %s",
 			ErrOppositeAmountsAbsValMismatch,
-			aVal.String(),
-			bVal.String(),
+			aVal.RatString(),
+			bVal.RatString(),
 		)
 	}
 
@@ -488,7 +670,7 @@ func equalAddresses(ops []*types.Operation) error {
 }
 
 func matchIndexValid(matches []*Match, index int) error {
-	if index >= len(matches) {
+	if index < 0 || index >= len(matches) {
 		return fmt.Errorf(
 			"%w: at index %d",
 			ErrMatchIndexValidIndexOutOfRange,
@@ -530,6 +712,7 @@ func checkOps(requests [][]int, matches []*Match, valid func([]*types.Operation)
 func compareOppositeMatches(
 	amountPairs [][]int,
 	matches []*Match,
+	equalChecker func([]*types.Operation) error,
 	amountChecker func(*types.Operation, *types.Operation) error,
 ) error {
 	for _, amountMatch := range amountPairs {
@@ -547,14 +730,14 @@ func compareOppositeMatches(
 
 		match0Ops := matches[amountMatch[0]].Operations
 		match1Ops := matches[amountMatch[1]].Operations
-		if err := equalAmounts(match0Ops); err != nil {
+		if err := equalChecker(match0Ops); err != nil {
 			return fmt.Errorf(
 				"%w: amounts comparison error for match index %d",
 				err,
 				amountMatch[0],
 			)
 		}
-		if err := equalAmounts(match1Ops); err != nil {
+		if err := equalChecker(match1Ops); err != nil {
 			return fmt.Errorf(
 				"%w: amounts comparison error for match index %d",
 				err,
@@ -582,7 +765,16 @@ func comparisonMatch(
 	descriptions *Descriptions,
 	matches []*Match,
 ) error {
-	if err := checkOps(descriptions.EqualAmounts, matches, equalAmounts); err != nil {
+	equalChecker := equalAmounts
+	oppositeChecker := oppositeAmounts
+	oppositeOrZeroChecker := oppositeOrZeroAmounts
+	if descriptions.DecimalAware {
+		equalChecker = equalDecimalAmounts
+		oppositeChecker = oppositeDecimalAmounts
+		oppositeOrZeroChecker = oppositeOrZeroDecimalAmounts
+	}
+
+	if err := checkOps(descriptions.EqualAmounts, matches, equalChecker); err != nil {
 		return fmt.Errorf("%w: operation amounts not equal", err)
 	}
 
@@ -590,13 +782,156 @@ func comparisonMatch(
 		return fmt.Errorf("%w: operation addresses not equal", err)
 	}
 
-	if err := compareOppositeMatches(descriptions.OppositeAmounts, matches, oppositeAmounts); err != nil {
+	if err := compareOppositeMatches(
+		descriptions.OppositeAmounts,
+		matches,
+		equalChecker,
+		oppositeChecker,
+	); err != nil {
 		return fmt.Errorf("%w: operation amounts not opposite", err)
 	}
-	if err := compareOppositeMatches(descriptions.OppositeOrZeroAmounts, matches, oppositeOrZeroAmounts); err != nil {
+	if err := compareOppositeMatches(
+		descriptions.OppositeOrZeroAmounts,
+		matches,
+		equalChecker,
+		oppositeOrZeroChecker,
+	); err != nil {
 		return fmt.Errorf("%w: both operation amounts not opposite and not zero", err)
 	}
 
+	for i, relation := range descriptions.AmountRelations {
+		if err := amountRelationMatch(relation, matches); err != nil {
+			return fmt.Errorf("%w: amount relation %d not met", err, i)
+		}
+	}
+
+	for i, proportion := range descriptions.AmountProportions {
+		if err := amountProportionMatch(proportion, matches); err != nil {
+			return fmt.Errorf("%w: amount proportion %d not met", err, i)
+		}
+	}
+
+	return nil
+}
+
+// sumAbsoluteDecimalAmounts returns the sum of the absolute decimal
+// values of all operations matched at index.
+func sumAbsoluteDecimalAmounts(index int, matches []*Match) (*big.Rat, error) {
+	if err := matchIndexValid(matches, index); err != nil {
+		return nil, err
+	}
+
+	sum := new(big.Rat)
+	for _, op := range matches[index].Operations {
+		val, err := DecimalValue(op.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		sum.Add(sum, new(big.Rat).Abs(val))
+	}
+
+	return sum, nil
+}
+
+// amountProportionMatch returns an error if an *AmountProportion
+// is not met by matches.
+func amountProportionMatch(proportion *AmountProportion, matches []*Match) error {
+	base, err := sumAbsoluteDecimalAmounts(proportion.Base, matches)
+	if err != nil {
+		return fmt.Errorf("%w: unable to sum base amounts", err)
+	}
+
+	target, err := sumAbsoluteDecimalAmounts(proportion.Target, matches)
+	if err != nil {
+		return fmt.Errorf("%w: unable to sum target amounts", err)
+	}
+
+	return proportionalValues(base, target, proportion.Ratio, proportion.Tolerance)
+}
+
+// sumAbsoluteAmounts returns the sum of the absolute values of all
+// operations matched at indices and their shared *types.Currency.
+// Optional descriptions that were not matched are skipped.
+func sumAbsoluteAmounts(
+	indices []int,
+	matches []*Match,
+	currency *types.Currency,
+) (*big.Int, *types.Currency, error) {
+	sum := big.NewInt(0)
+	for _, index := range indices {
+		if index < 0 || index >= len(matches) {
+			return nil, nil, fmt.Errorf(
+				"%w: at index %d",
+				ErrMatchIndexValidIndexOutOfRange,
+				index,
+			)
+		}
+
+		if matches[index] == nil {
+			continue
+		}
+
+		for _, op := range matches[index].Operations {
+			val, err := types.AmountValue(op.Amount)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if currency == nil {
+				currency = op.Amount.Currency
+			} else if !currency.Equal(op.Amount.Currency) {
+				return nil, nil, fmt.Errorf(
+					"%w: %+v and %+v",
+					ErrAmountRelationCurrencyMismatch,
+					currency,
+					op.Amount.Currency,
+				)
+			}
+
+			sum.Add(sum, new(big.Int).Abs(val))
+		}
+	}
+
+	return sum, currency, nil
+}
+
+// amountRelationMatch returns an error if an *AmountRelation
+// is not met by matches.
+func amountRelationMatch(relation *AmountRelation, matches []*Match) error {
+	left, currency, err := sumAbsoluteAmounts(relation.Left, matches, nil)
+	if err != nil {
+		return fmt.Errorf("%w: unable to sum left amounts", err)
+	}
+
+	right, _, err := sumAbsoluteAmounts(relation.Right, matches, currency)
+	if err != nil {
+		return fmt.Errorf("%w: unable to sum right amounts", err)
+	}
+
+	cmp := left.Cmp(right)
+	var met bool
+	switch relation.Comparison {
+	case EqualAmountComparison:
+		met = cmp == 0
+	case GreaterOrEqualAmountComparison:
+		met = cmp >= 0
+	case LessOrEqualAmountComparison:
+		met = cmp <= 0
+	default:
+		return fmt.Errorf("%w: %d", ErrAmountRelationInvalidComparison, relation.Comparison)
+	}
+
+	if !met {
+		return fmt.Errorf(
+			"%w: %s is not %s %s",
+			ErrAmountRelationNotMet,
+			left.String(),
+			relation.Comparison.String(),
+			right.String(),
+		)
+	}
+
 	return nil
 }
 
@@ -625,6 +960,31 @@ func (m *Match) First() (*types.Operation, *big.Int) {
 	return nil, nil
 }
 
+// canonicalOperations returns a copy of operations sorted
+// without considering OperationIdentifier, RelatedOperations,
+// or Status (which may not be populated or may differ
+// before confirmation).
+func canonicalOperations(operations []*types.Operation) []*types.Operation {
+	keys := make(map[*types.Operation]string, len(operations))
+	for _, op := range operations {
+		keys[op] = types.Hash(&types.Operation{
+			Type:       op.Type,
+			Account:    op.Account,
+			Amount:     op.Amount,
+			CoinChange: op.CoinChange,
+			Metadata:   op.Metadata,
+		})
+	}
+
+	sorted := make([]*types.Operation, len(operations))
+	copy(sorted, operations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return keys[sorted[i]] < keys[sorted[j]]
+	})
+
+	return sorted
+}
+
 // MatchOperations attempts to match a slice of operations with a slice of
 // OperationDescriptions (high-level descriptions of what operations are
 // desired). If matching succeeds, a slice of matching operations in the
@@ -644,9 +1004,18 @@ func MatchOperations(
 	operationDescriptions := descriptions.OperationDescriptions
 	matches := make([]*Match, len(operationDescriptions))
 
+	if descriptions.Mempool {
+		operations = canonicalOperations(operations)
+	}
+
 	// Match a *types.Operation to each *OperationDescription
 	for i, op := range operations {
-		matchFound := operationMatch(op, operationDescriptions, matches)
+		matchFound := operationMatch(
+			op,
+			operationDescriptions,
+			matches,
+			descriptions.Mempool,
+		)
 		if !matchFound && descriptions.ErrUnmatched {
 			return nil, fmt.Errorf(
 				"%w: at index %d",
@@ -661,6 +1030,17 @@ func MatchOperations(
 		if matches[i] == nil && !descriptions.OperationDescriptions[i].Optional {
 			return nil, fmt.Errorf("%w: %d", ErrMatchOperationsDescriptionNotMatched, i)
 		}
+
+		minMatches := descriptions.OperationDescriptions[i].MinMatches
+		if matches[i] != nil && len(matches[i].Operations) < minMatches {
+			return nil, fmt.Errorf(
+				"%w: expected at least %d operations for description %d but got %d",
+				ErrMatchOperationsTooFewMatches,
+				minMatches,
+				i,
+				len(matches[i].Operations),
+			)
+		}
 	}
 
 	// Once matches are found, assert high-level descriptions between
@@ -17,6 +17,7 @@ package parser
 import (
 	"math/big"
 	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -2008,6 +2009,499 @@ func TestMatchOperations(t *testing.T) {
 			matches: nil,
 			err:     true,
 		},
+		"utxo transfer with fee relation": {
+			operations: []*types.Operation{
+				{
+					Account: &types.AccountIdentifier{Address: "in1"},
+					Amount:  &types.Amount{Value: "-60", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+				{
+					Account: &types.AccountIdentifier{Address: "in2"},
+					Amount:  &types.Amount{Value: "-50", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+				{
+					Account: &types.AccountIdentifier{Address: "out1"},
+					Amount:  &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+				{
+					Type:    "FEE",
+					Account: &types.AccountIdentifier{Address: "miner"},
+					Amount:  &types.Amount{Value: "10", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+			},
+			descriptions: &Descriptions{
+				AmountRelations: []*AmountRelation{
+					{Left: []int{0}, Right: []int{1, 2}},
+				},
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+						AllowRepeats: true,
+						MinMatches:   2,
+					},
+					{
+						Type: "FEE",
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+						AllowRepeats: true,
+					},
+				},
+			},
+			matches: []*Match{
+				{
+					Operations: []*types.Operation{
+						{
+							Account: &types.AccountIdentifier{Address: "in1"},
+							Amount:  &types.Amount{Value: "-60", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+						},
+						{
+							Account: &types.AccountIdentifier{Address: "in2"},
+							Amount:  &types.Amount{Value: "-50", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(-60), big.NewInt(-50)},
+				},
+				{
+					Operations: []*types.Operation{
+						{
+							Type:    "FEE",
+							Account: &types.AccountIdentifier{Address: "miner"},
+							Amount:  &types.Amount{Value: "10", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(10)},
+				},
+				{
+					Operations: []*types.Operation{
+						{
+							Account: &types.AccountIdentifier{Address: "out1"},
+							Amount:  &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(100)},
+				},
+			},
+			err: false,
+		},
+		"utxo transfer with unmet fee relation": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-60", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+				{
+					Amount: &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+			},
+			descriptions: &Descriptions{
+				AmountRelations: []*AmountRelation{
+					{Left: []int{0}, Right: []int{1}},
+				},
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+						AllowRepeats: true,
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+						AllowRepeats: true,
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
+		"amount relation with currency mismatch": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-100", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+				{
+					Amount: &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "ETH", Decimals: 18}},
+				},
+			},
+			descriptions: &Descriptions{
+				AmountRelations: []*AmountRelation{
+					{Left: []int{0}, Right: []int{1}},
+				},
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
+		"amount relation with negative index": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-100", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+				{
+					Amount: &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "BTC", Decimals: 8}},
+				},
+			},
+			descriptions: &Descriptions{
+				AmountRelations: []*AmountRelation{
+					{Left: []int{-1}, Right: []int{1}},
+				},
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
+		"too few repeated matches": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-100"},
+				},
+			},
+			descriptions: &Descriptions{
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+						AllowRepeats: true,
+						MinMatches:   2,
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
+		"max matches overflows to next description": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "100"},
+				},
+				{
+					Amount: &types.Amount{Value: "200"},
+				},
+				{
+					Amount: &types.Amount{Value: "300"},
+				},
+			},
+			descriptions: &Descriptions{
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+						AllowRepeats: true,
+						MaxMatches:   2,
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+				},
+			},
+			matches: []*Match{
+				{
+					Operations: []*types.Operation{
+						{
+							Amount: &types.Amount{Value: "100"},
+						},
+						{
+							Amount: &types.Amount{Value: "200"},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(100), big.NewInt(200)},
+				},
+				{
+					Operations: []*types.Operation{
+						{
+							Amount: &types.Amount{Value: "300"},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(300)},
+				},
+			},
+			err: false,
+		},
+		"metadata path and pattern": {
+			operations: []*types.Operation{
+				{
+					Metadata: map[string]interface{}{
+						"memo": "abc",
+					},
+				},
+				{
+					Metadata: map[string]interface{}{
+						"inputs": []interface{}{
+							map[string]interface{}{
+								"id": "0xdeadbeef",
+							},
+						},
+					},
+				},
+			},
+			descriptions: &Descriptions{
+				OperationDescriptions: []*OperationDescription{
+					{
+						Metadata: []*MetadataDescription{
+							{
+								Path:         "inputs.0.id",
+								ValueKind:    reflect.String,
+								ValuePattern: regexp.MustCompile("^0x[0-9a-f]+$"),
+							},
+						},
+					},
+				},
+				ErrUnmatched: false,
+			},
+			matches: []*Match{
+				{
+					Operations: []*types.Operation{
+						{
+							Metadata: map[string]interface{}{
+								"inputs": []interface{}{
+									map[string]interface{}{
+										"id": "0xdeadbeef",
+									},
+								},
+							},
+						},
+					},
+					Amounts: []*big.Int{nil},
+				},
+			},
+			err: false,
+		},
+		"metadata pattern mismatch": {
+			operations: []*types.Operation{
+				{
+					Metadata: map[string]interface{}{
+						"memo": "abc",
+					},
+				},
+			},
+			descriptions: &Descriptions{
+				OperationDescriptions: []*OperationDescription{
+					{
+						Metadata: []*MetadataDescription{
+							{
+								Key:          "memo",
+								ValueKind:    reflect.String,
+								ValuePattern: regexp.MustCompile("^[0-9]+$"),
+							},
+						},
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
+		"decimal aware opposite amounts": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-1000", Currency: &types.Currency{Symbol: "WBTC", Decimals: 3}},
+				},
+				{
+					Amount: &types.Amount{Value: "1", Currency: &types.Currency{Symbol: "BTC", Decimals: 0}},
+				},
+			},
+			descriptions: &Descriptions{
+				OppositeAmounts: [][]int{{0, 1}},
+				DecimalAware:    true,
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+				},
+			},
+			matches: []*Match{
+				{
+					Operations: []*types.Operation{
+						{
+							Amount: &types.Amount{Value: "-1000", Currency: &types.Currency{Symbol: "WBTC", Decimals: 3}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(-1000)},
+				},
+				{
+					Operations: []*types.Operation{
+						{
+							Amount: &types.Amount{Value: "1", Currency: &types.Currency{Symbol: "BTC", Decimals: 0}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(1)},
+				},
+			},
+			err: false,
+		},
+		"decimal aware equal amounts mismatch": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "1000", Currency: &types.Currency{Symbol: "WBTC", Decimals: 3}},
+				},
+				{
+					Amount: &types.Amount{Value: "1000", Currency: &types.Currency{Symbol: "BTC", Decimals: 0}},
+				},
+			},
+			descriptions: &Descriptions{
+				EqualAmounts: [][]int{{0, 1}},
+				DecimalAware: true,
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists:   true,
+							Currency: &types.Currency{Symbol: "WBTC", Decimals: 3},
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists:   true,
+							Currency: &types.Currency{Symbol: "BTC", Decimals: 0},
+						},
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
+		"amount proportion within tolerance": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-1000000", Currency: &types.Currency{Symbol: "ATOM", Decimals: 6}},
+				},
+				{
+					Amount: &types.Amount{Value: "50100", Currency: &types.Currency{Symbol: "ATOM", Decimals: 6}},
+				},
+			},
+			descriptions: &Descriptions{
+				AmountProportions: []*AmountProportion{
+					{
+						Base:      0,
+						Target:    1,
+						Ratio:     big.NewRat(5, 100),
+						Tolerance: big.NewRat(1, 100),
+					},
+				},
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+				},
+			},
+			matches: []*Match{
+				{
+					Operations: []*types.Operation{
+						{
+							Amount: &types.Amount{Value: "-1000000", Currency: &types.Currency{Symbol: "ATOM", Decimals: 6}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(-1000000)},
+				},
+				{
+					Operations: []*types.Operation{
+						{
+							Amount: &types.Amount{Value: "50100", Currency: &types.Currency{Symbol: "ATOM", Decimals: 6}},
+						},
+					},
+					Amounts: []*big.Int{big.NewInt(50100)},
+				},
+			},
+			err: false,
+		},
+		"amount proportion outside tolerance": {
+			operations: []*types.Operation{
+				{
+					Amount: &types.Amount{Value: "-1000000", Currency: &types.Currency{Symbol: "ATOM", Decimals: 6}},
+				},
+				{
+					Amount: &types.Amount{Value: "60000", Currency: &types.Currency{Symbol: "ATOM", Decimals: 6}},
+				},
+			},
+			descriptions: &Descriptions{
+				AmountProportions: []*AmountProportion{
+					{
+						Base:      0,
+						Target:    1,
+						Ratio:     big.NewRat(5, 100),
+						Tolerance: big.NewRat(1, 100),
+					},
+				},
+				OperationDescriptions: []*OperationDescription{
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   NegativeAmountSign,
+						},
+					},
+					{
+						Amount: &AmountDescription{
+							Exists: true,
+							Sign:   PositiveAmountSign,
+						},
+					},
+				},
+			},
+			matches: nil,
+			err:     true,
+		},
 	}
 
 	for name, test := range tests {
@@ -2108,3 +2602,108 @@ func TestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchOperationsMempool(t *testing.T) {
+	sender := &types.Operation{
+		Type: "transfer",
+		Account: &types.AccountIdentifier{
+			Address: "addr1",
+		},
+		Amount: &types.Amount{
+			Value: "-100",
+		},
+	}
+	recipient := &types.Operation{
+		Type: "transfer",
+		Account: &types.AccountIdentifier{
+			Address: "addr2",
+		},
+		Amount: &types.Amount{
+			Value: "100",
+		},
+	}
+	otherRecipient := &types.Operation{
+		Type: "transfer",
+		Account: &types.AccountIdentifier{
+			Address: "addr3",
+		},
+		Amount: &types.Amount{
+			Value: "100",
+		},
+	}
+
+	descriptions := func(mempool bool) *Descriptions {
+		return &Descriptions{
+			OppositeAmounts: [][]int{{0, 1}},
+			OperationDescriptions: []*OperationDescription{
+				{
+					Type:   "transfer",
+					Status: "success",
+					Amount: &AmountDescription{
+						Exists: true,
+						Sign:   NegativeAmountSign,
+					},
+				},
+				{
+					Type:   "transfer",
+					Status: "success",
+					Amount: &AmountDescription{
+						Exists: true,
+						Sign:   PositiveAmountSign,
+					},
+				},
+			},
+			Mempool: mempool,
+		}
+	}
+
+	withStatus := func(op *types.Operation, index int64, status string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{Index: index},
+			Type:                op.Type,
+			Status:              types.String(status),
+			Account:             op.Account,
+			Amount:              op.Amount,
+		}
+	}
+
+	// Status is required when not in mempool mode.
+	_, err := MatchOperations(descriptions(false), []*types.Operation{sender, recipient})
+	assert.Error(t, err)
+
+	_, err = MatchOperations(descriptions(false), []*types.Operation{
+		withStatus(sender, 0, "success"),
+		withStatus(recipient, 1, "failure"),
+	})
+	assert.Error(t, err)
+
+	confirmed, err := MatchOperations(descriptions(false), []*types.Operation{
+		withStatus(sender, 0, "success"),
+		withStatus(recipient, 1, "success"),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, confirmed, 2)
+
+	// In mempool mode, status is ignored and the result does not
+	// depend on the order of operations.
+	ordered, err := MatchOperations(
+		descriptions(true),
+		[]*types.Operation{sender, recipient, otherRecipient},
+	)
+	assert.NoError(t, err)
+
+	reversed, err := MatchOperations(
+		descriptions(true),
+		[]*types.Operation{otherRecipient, recipient, sender},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, ordered, reversed)
+
+	parsed, err := MatchOperations(descriptions(true), []*types.Operation{
+		withStatus(recipient, 0, "failure"),
+		withStatus(sender, 1, "failure"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "addr1", parsed[0].Operations[0].Account.Address)
+	assert.Equal(t, "addr2", parsed[1].Operations[0].Account.Address)
+}
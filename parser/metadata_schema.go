@@ -0,0 +1,145 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// MetadataSchema describes the metadata expected to be
+// populated in all operations of a particular type.
+type MetadataSchema struct {
+	// Required entries must be present and have
+	// the described kind (and pattern, if provided).
+	Required []*MetadataDescription
+
+	// Optional entries are only validated
+	// if they are present.
+	Optional []*MetadataDescription
+
+	// DisallowUnknownKeys indicates that an error should
+	// be returned if the metadata contains any top-level key
+	// not referenced by Required or Optional.
+	DisallowUnknownKeys bool
+}
+
+// metadataDescriptionKey returns the top-level metadata
+// key referenced by a *MetadataDescription.
+func metadataDescriptionKey(req *MetadataDescription) string {
+	if len(req.Path) == 0 {
+		return req.Key
+	}
+
+	return strings.Split(req.Path, ".")[0]
+}
+
+// Validate returns an error if metadata
+// does not satisfy the *MetadataSchema.
+func (s *MetadataSchema) Validate(metadata map[string]interface{}) error {
+	if err := metadataMatch(s.Required, metadata); err != nil {
+		return err
+	}
+
+	for _, req := range s.Optional {
+		if _, ok := metadataValue(req, metadata); !ok {
+			continue
+		}
+
+		if err := metadataMatch([]*MetadataDescription{req}, metadata); err != nil {
+			return err
+		}
+	}
+
+	if !s.DisallowUnknownKeys {
+		return nil
+	}
+
+	known := map[string]struct{}{}
+	for _, req := range append(s.Required, s.Optional...) {
+		known[metadataDescriptionKey(req)] = struct{}{}
+	}
+
+	unknown := []string{}
+	for key := range metadata {
+		if _, ok := known[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%w: %s", ErrMetadataSchemaUnknownKey, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// ValidateOperationMetadata returns an error if the metadata of an
+// operation does not satisfy the *MetadataSchema registered for its
+// type in MetadataSchemas. Operations with a type that has no
+// registered schema are not validated.
+func (p *Parser) ValidateOperationMetadata(op *types.Operation) error {
+	schema, ok := p.MetadataSchemas[op.Type]
+	if !ok || schema == nil {
+		return nil
+	}
+
+	if err := schema.Validate(op.Metadata); err != nil {
+		return fmt.Errorf(
+			"%w: invalid metadata for operation %s of type %s",
+			err,
+			types.PrintStruct(op.OperationIdentifier),
+			op.Type,
+		)
+	}
+
+	return nil
+}
+
+// ValidateOperationsMetadata calls ValidateOperationMetadata
+// on each operation in a slice.
+func (p *Parser) ValidateOperationsMetadata(ops []*types.Operation) error {
+	if len(p.MetadataSchemas) == 0 {
+		return nil
+	}
+
+	for _, op := range ops {
+		if err := p.ValidateOperationMetadata(op); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateBlockMetadata calls ValidateOperationMetadata on
+// each operation in each transaction in a block.
+func (p *Parser) ValidateBlockMetadata(block *types.Block) error {
+	for _, tx := range block.Transactions {
+		if err := p.ValidateOperationsMetadata(tx.Operations); err != nil {
+			return fmt.Errorf(
+				"%w: transaction %s",
+				err,
+				types.PrintStruct(tx.TransactionIdentifier),
+			)
+		}
+	}
+
+	return nil
+}
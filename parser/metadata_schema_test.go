@@ -0,0 +1,201 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestMetadataSchema(t *testing.T) {
+	schema := &MetadataSchema{
+		Required: []*MetadataDescription{
+			{
+				Key:          "validator",
+				ValueKind:    reflect.String,
+				ValuePattern: regexp.MustCompile("^val[0-9]+$"),
+			},
+		},
+		Optional: []*MetadataDescription{
+			{
+				Path:      "fee.payer",
+				ValueKind: reflect.String,
+			},
+			{
+				Key:       "memo",
+				ValueKind: reflect.String,
+			},
+		},
+		DisallowUnknownKeys: true,
+	}
+
+	var tests = map[string]struct {
+		metadata map[string]interface{}
+
+		err error
+	}{
+		"required only": {
+			metadata: map[string]interface{}{
+				"validator": "val1",
+			},
+		},
+		"required and optional": {
+			metadata: map[string]interface{}{
+				"validator": "val1",
+				"memo":      "hello",
+				"fee": map[string]interface{}{
+					"payer": "addr1",
+				},
+			},
+		},
+		"missing required": {
+			metadata: map[string]interface{}{
+				"memo": "hello",
+			},
+			err: ErrMetadataMatchKeyNotFound,
+		},
+		"required pattern mismatch": {
+			metadata: map[string]interface{}{
+				"validator": "bad",
+			},
+			err: ErrMetadataMatchKeyValueMismatch,
+		},
+		"optional wrong kind": {
+			metadata: map[string]interface{}{
+				"validator": "val1",
+				"memo":      float64(10),
+			},
+			err: ErrMetadataMatchKeyValueMismatch,
+		},
+		"unknown key": {
+			metadata: map[string]interface{}{
+				"validator": "val1",
+				"extra":     true,
+			},
+			err: ErrMetadataSchemaUnknownKey,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := schema.Validate(test.metadata)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParserMetadataSchemas(t *testing.T) {
+	asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+		{
+			Status:     "Success",
+			Successful: true,
+		},
+	})
+	assert.NoError(t, err)
+
+	parser := New(asserter, nil, nil)
+	parser.MetadataSchemas = map[string]*MetadataSchema{
+		"Delegate": {
+			Required: []*MetadataDescription{
+				{
+					Key:       "validator",
+					ValueKind: reflect.String,
+				},
+			},
+		},
+	}
+
+	valid := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 0},
+		Type:                "Delegate",
+		Status:              types.String("Success"),
+		Account:             &types.AccountIdentifier{Address: "addr1"},
+		Amount: &types.Amount{
+			Value:    "-10",
+			Currency: &types.Currency{Symbol: "ATOM", Decimals: 6},
+		},
+		Metadata: map[string]interface{}{
+			"validator": "val1",
+		},
+	}
+	invalid := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 0},
+		Type:                "Delegate",
+		Status:              types.String("Success"),
+		Account:             &types.AccountIdentifier{Address: "addr1"},
+		Amount: &types.Amount{
+			Value:    "-10",
+			Currency: &types.Currency{Symbol: "ATOM", Decimals: 6},
+		},
+	}
+	unregistered := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 1},
+		Type:                "Transfer",
+		Status:              types.String("Success"),
+	}
+
+	t.Run("expected operations", func(t *testing.T) {
+		assert.NoError(t, parser.ExpectedOperations(
+			[]*types.Operation{valid},
+			[]*types.Operation{valid, unregistered},
+			false,
+			false,
+		))
+
+		err := parser.ExpectedOperations(
+			[]*types.Operation{invalid},
+			[]*types.Operation{invalid},
+			false,
+			false,
+		)
+		assert.True(t, errors.Is(err, ErrMetadataMatchKeyNotFound))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		block := &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{Hash: "1", Index: 1},
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx1"},
+					Operations:            []*types.Operation{valid, unregistered},
+				},
+			},
+		}
+		assert.NoError(t, parser.ValidateBlockMetadata(block))
+
+		changes, err := parser.BalanceChanges(context.Background(), block, false)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+
+		block.Transactions[0].Operations = []*types.Operation{invalid}
+		assert.True(t, errors.Is(parser.ValidateBlockMetadata(block), ErrMetadataMatchKeyNotFound))
+
+		changes, err = parser.BalanceChanges(context.Background(), block, false)
+		assert.Nil(t, changes)
+		assert.True(t, errors.Is(err, ErrMetadataMatchKeyNotFound))
+	})
+}
@@ -0,0 +1,295 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// OperationReference is a directed edge in an *OperationGraph
+// from an operation to an operation in its RelatedOperations.
+type OperationReference struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// OperationComponent is a connected component of an
+// *OperationGraph (all operations that are transitively
+// related) and the net amount of each *types.Currency
+// transferred by the operations in the component.
+type OperationComponent struct {
+	Operations       []*types.Operation `json:"operations"`
+	NetAmounts       []*types.Amount    `json:"net_amounts"`
+	NilAmountPresent bool               `json:"nil_amount_present"`
+}
+
+// OperationGraph is the directed graph formed by the
+// RelatedOperations of each operation in a transaction.
+// Unlike GroupOperations, an OperationGraph can be built
+// from operations that have not been asserted.
+type OperationGraph struct {
+	operations map[int64]*types.Operation
+	indexes    []int64
+	edges      map[int64][]int64
+}
+
+// BuildOperationGraph returns the *OperationGraph of a
+// *types.Transaction. An error is returned if any operation
+// is missing an OperationIdentifier, if any OperationIdentifier
+// is duplicated, if any related operation does not exist, or
+// if the graph contains a cycle.
+func BuildOperationGraph(transaction *types.Transaction) (*OperationGraph, error) {
+	graph := &OperationGraph{
+		operations: map[int64]*types.Operation{},
+		indexes:    []int64{},
+		edges:      map[int64][]int64{},
+	}
+
+	for _, op := range transaction.Operations {
+		if op.OperationIdentifier == nil {
+			return nil, ErrOperationGraphIdentifierMissing
+		}
+
+		index := op.OperationIdentifier.Index
+		if _, ok := graph.operations[index]; ok {
+			return nil, fmt.Errorf("%w: %d", ErrOperationGraphDuplicateIndex, index)
+		}
+
+		graph.operations[index] = op
+		graph.indexes = append(graph.indexes, index)
+	}
+
+	sort.Slice(graph.indexes, func(i, j int) bool {
+		return graph.indexes[i] < graph.indexes[j]
+	})
+
+	for _, op := range transaction.Operations {
+		from := op.OperationIdentifier.Index
+		for _, related := range op.RelatedOperations {
+			if related == nil {
+				return nil, ErrOperationGraphIdentifierMissing
+			}
+
+			if _, ok := graph.operations[related.Index]; !ok {
+				return nil, fmt.Errorf(
+					"%w: operation %d references %d",
+					ErrOperationGraphMissingReference,
+					from,
+					related.Index,
+				)
+			}
+
+			graph.edges[from] = append(graph.edges[from], related.Index)
+		}
+	}
+
+	if cycle := graph.findCycle(); cycle != nil {
+		path := make([]string, len(cycle))
+		for i, index := range cycle {
+			path[i] = fmt.Sprintf("%d", index)
+		}
+
+		return nil, fmt.Errorf(
+			"%w: %s",
+			ErrOperationGraphCycle,
+			strings.Join(path, " -> "),
+		)
+	}
+
+	return graph, nil
+}
+
+// findCycle returns the path of the first cycle found
+// in the graph (starting and ending at the same index)
+// or nil if the graph is acyclic.
+func (g *OperationGraph) findCycle() []int64 {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[int64]int{}
+	stack := []int64{}
+
+	var visit func(index int64) []int64
+	visit = func(index int64) []int64 {
+		state[index] = visiting
+		stack = append(stack, index)
+
+		for _, next := range g.edges[index] {
+			switch state[next] {
+			case visiting:
+				for i, stackIndex := range stack {
+					if stackIndex == next {
+						cycle := append([]int64{}, stack[i:]...)
+						return append(cycle, next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[index] = visited
+		return nil
+	}
+
+	for _, index := range g.indexes {
+		if state[index] != unvisited {
+			continue
+		}
+
+		if cycle := visit(index); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// Operation returns the *types.Operation with the
+// provided OperationIdentifier.Index (if it exists).
+func (g *OperationGraph) Operation(index int64) (*types.Operation, bool) {
+	op, ok := g.operations[index]
+	return op, ok
+}
+
+// References returns all edges in the graph,
+// sorted by From and then To.
+func (g *OperationGraph) References() []*OperationReference {
+	references := []*OperationReference{}
+	for _, from := range g.indexes {
+		to := append([]int64{}, g.edges[from]...)
+		sort.Slice(to, func(i, j int) bool { return to[i] < to[j] })
+		for _, index := range to {
+			references = append(references, &OperationReference{From: from, To: index})
+		}
+	}
+
+	return references
+}
+
+// ForwardReferences returns all edges where an operation
+// references an operation with a greater or equal index.
+// The asserter rejects transactions with forward references,
+// so this can be used to explain why a transaction is invalid.
+func (g *OperationGraph) ForwardReferences() []*OperationReference {
+	forward := []*OperationReference{}
+	for _, reference := range g.References() {
+		if reference.To >= reference.From {
+			forward = append(forward, reference)
+		}
+	}
+
+	return forward
+}
+
+// Components returns the connected components of the graph
+// (treating each edge as undirected). Components are returned in
+// ascending order based on the lowest OperationIdentifier.Index
+// in the component and the operations in each component are
+// sorted.
+func (g *OperationGraph) Components() ([]*OperationComponent, error) {
+	parents := map[int64]int64{}
+	for _, index := range g.indexes {
+		parents[index] = index
+	}
+
+	var find func(index int64) int64
+	find = func(index int64) int64 {
+		if parents[index] != index {
+			parents[index] = find(parents[index])
+		}
+
+		return parents[index]
+	}
+
+	for _, from := range g.indexes {
+		for _, to := range g.edges[from] {
+			rootFrom, rootTo := find(from), find(to)
+			if rootFrom == rootTo {
+				continue
+			}
+
+			// Always use the lowest index as the root
+			// so that components are ordered deterministically.
+			if rootFrom < rootTo {
+				parents[rootTo] = rootFrom
+			} else {
+				parents[rootFrom] = rootTo
+			}
+		}
+	}
+
+	components := []*OperationComponent{}
+	componentIndexes := map[int64]int{}
+	sums := []map[string]*big.Int{}
+	for _, index := range g.indexes {
+		root := find(index)
+		position, ok := componentIndexes[root]
+		if !ok {
+			position = len(components)
+			componentIndexes[root] = position
+			components = append(components, &OperationComponent{
+				Operations: []*types.Operation{},
+				NetAmounts: []*types.Amount{},
+			})
+			sums = append(sums, map[string]*big.Int{})
+		}
+
+		component := components[position]
+		op := g.operations[index]
+		component.Operations = append(component.Operations, op)
+		if op.Amount == nil {
+			component.NilAmountPresent = true
+			continue
+		}
+
+		val, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse amount of operation %d", err, index)
+		}
+
+		key := types.Hash(op.Amount.Currency)
+		sum, ok := sums[position][key]
+		if !ok {
+			sum = big.NewInt(0)
+			sums[position][key] = sum
+			component.NetAmounts = append(
+				component.NetAmounts,
+				&types.Amount{Currency: op.Amount.Currency},
+			)
+		}
+
+		sum.Add(sum, val)
+	}
+
+	for i, component := range components {
+		for _, amount := range component.NetAmounts {
+			amount.Value = sums[i][types.Hash(amount.Currency)].String()
+		}
+	}
+
+	return components, nil
+}
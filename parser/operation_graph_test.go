@@ -0,0 +1,194 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func graphOperation(index int64, value string, related ...int64) *types.Operation {
+	op := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: index,
+		},
+		Type: "transfer",
+	}
+
+	if len(value) > 0 {
+		op.Amount = &types.Amount{
+			Value: value,
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+	}
+
+	for _, r := range related {
+		op.RelatedOperations = append(op.RelatedOperations, &types.OperationIdentifier{
+			Index: r,
+		})
+	}
+
+	return op
+}
+
+func TestBuildOperationGraph(t *testing.T) {
+	var tests = map[string]struct {
+		operations []*types.Operation
+
+		components []*OperationComponent
+		references []*OperationReference
+		forward    []*OperationReference
+		err        error
+	}{
+		"simple transfer with fee": {
+			operations: []*types.Operation{
+				graphOperation(0, "-100"),
+				graphOperation(1, "90", 0),
+				graphOperation(2, "-5"),
+				graphOperation(3, ""),
+			},
+			components: []*OperationComponent{
+				{
+					Operations: []*types.Operation{
+						graphOperation(0, "-100"),
+						graphOperation(1, "90", 0),
+					},
+					NetAmounts: []*types.Amount{
+						{
+							Value: "-10",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				},
+				{
+					Operations: []*types.Operation{
+						graphOperation(2, "-5"),
+					},
+					NetAmounts: []*types.Amount{
+						{
+							Value: "-5",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				},
+				{
+					Operations:       []*types.Operation{graphOperation(3, "")},
+					NetAmounts:       []*types.Amount{},
+					NilAmountPresent: true,
+				},
+			},
+			references: []*OperationReference{{From: 1, To: 0}},
+			forward:    []*OperationReference{},
+		},
+		"forward references merge components": {
+			operations: []*types.Operation{
+				graphOperation(0, "-100", 2),
+				graphOperation(1, "50"),
+				graphOperation(2, "50", 1),
+			},
+			components: []*OperationComponent{
+				{
+					Operations: []*types.Operation{
+						graphOperation(0, "-100", 2),
+						graphOperation(1, "50"),
+						graphOperation(2, "50", 1),
+					},
+					NetAmounts: []*types.Amount{
+						{
+							Value: "0",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+					},
+				},
+			},
+			references: []*OperationReference{{From: 0, To: 2}, {From: 2, To: 1}},
+			forward:    []*OperationReference{{From: 0, To: 2}},
+		},
+		"cycle": {
+			operations: []*types.Operation{
+				graphOperation(0, "-100", 2),
+				graphOperation(1, "50", 0),
+				graphOperation(2, "50", 1),
+			},
+			err: ErrOperationGraphCycle,
+		},
+		"self reference": {
+			operations: []*types.Operation{
+				graphOperation(0, "-100", 0),
+			},
+			err: ErrOperationGraphCycle,
+		},
+		"missing reference": {
+			operations: []*types.Operation{
+				graphOperation(0, "-100", 4),
+			},
+			err: ErrOperationGraphMissingReference,
+		},
+		"duplicate index": {
+			operations: []*types.Operation{
+				graphOperation(0, "-100"),
+				graphOperation(0, "100"),
+			},
+			err: ErrOperationGraphDuplicateIndex,
+		},
+		"missing identifier": {
+			operations: []*types.Operation{
+				{},
+			},
+			err: ErrOperationGraphIdentifierMissing,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			graph, err := BuildOperationGraph(&types.Transaction{
+				Operations: test.operations,
+			})
+			if test.err != nil {
+				assert.Nil(t, graph)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+
+			components, err := graph.Components()
+			assert.NoError(t, err)
+			assert.Equal(t, test.components, components)
+			assert.Equal(t, test.references, graph.References())
+			assert.Equal(t, test.forward, graph.ForwardReferences())
+
+			op, ok := graph.Operation(0)
+			assert.True(t, ok)
+			assert.Equal(t, test.operations[0], op)
+		})
+	}
+}
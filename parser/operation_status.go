@@ -0,0 +1,132 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// OperationStatusCounts contains the number of operations
+// observed with each status in a collection of operations.
+type OperationStatusCounts struct {
+	// Successful is the number of operations with a
+	// status marked as successful by the asserter.
+	Successful int `json:"successful"`
+
+	// Failed is the number of operations with a status
+	// marked as unsuccessful by the asserter.
+	Failed int `json:"failed"`
+
+	// Statuses is the number of operations observed
+	// with each *types.Operation.Status.
+	Statuses map[string]int `json:"statuses"`
+}
+
+// SplitOperationsByStatus partitions operations into successful
+// and failed sets using the operation statuses known to the
+// asserter. The order of operations is preserved in each set.
+//
+// This is useful on chains where failed transactions
+// still pay fees (the fee operation is successful while
+// the rest of the transaction is not).
+func (p *Parser) SplitOperationsByStatus(
+	ops []*types.Operation,
+) ([]*types.Operation, []*types.Operation, error) {
+	successful := []*types.Operation{}
+	failed := []*types.Operation{}
+	for _, op := range ops {
+		success, err := p.Asserter.OperationSuccessful(op)
+		if err != nil {
+			// Should only occur if responses not validated
+			return nil, nil, err
+		}
+
+		if success {
+			successful = append(successful, op)
+		} else {
+			failed = append(failed, op)
+		}
+	}
+
+	return successful, failed, nil
+}
+
+// SuccessfulOperations returns only the operations
+// that have a successful status.
+func (p *Parser) SuccessfulOperations(ops []*types.Operation) ([]*types.Operation, error) {
+	successful, _, err := p.SplitOperationsByStatus(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	return successful, nil
+}
+
+// OperationStatusCounts returns the number of successful
+// and failed operations in a collection of operations, along
+// with the number of operations observed with each status.
+func (p *Parser) OperationStatusCounts(
+	ops []*types.Operation,
+) (*OperationStatusCounts, error) {
+	counts := &OperationStatusCounts{
+		Statuses: map[string]int{},
+	}
+	if err := p.addOperationStatusCounts(counts, ops); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// BlockOperationStatusCounts returns the number of successful
+// and failed operations across all transactions in a block,
+// along with the number of operations observed with each status.
+func (p *Parser) BlockOperationStatusCounts(
+	block *types.Block,
+) (*OperationStatusCounts, error) {
+	counts := &OperationStatusCounts{
+		Statuses: map[string]int{},
+	}
+	for _, tx := range block.Transactions {
+		if err := p.addOperationStatusCounts(counts, tx.Operations); err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
+func (p *Parser) addOperationStatusCounts(
+	counts *OperationStatusCounts,
+	ops []*types.Operation,
+) error {
+	for _, op := range ops {
+		success, err := p.Asserter.OperationSuccessful(op)
+		if err != nil {
+			// Should only occur if responses not validated
+			return err
+		}
+
+		if success {
+			counts.Successful++
+		} else {
+			counts.Failed++
+		}
+
+		counts.Statuses[*op.Status]++
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestOperationStatus(t *testing.T) {
+	asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+		{
+			Status:     "Success",
+			Successful: true,
+		},
+		{
+			Status:     "Reverted",
+			Successful: false,
+		},
+		{
+			Status:     "OutOfGas",
+			Successful: false,
+		},
+	})
+	assert.NoError(t, err)
+	parser := New(asserter, nil, nil)
+
+	fee := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 0},
+		Type:                "Fee",
+		Status:              types.String("Success"),
+	}
+	transfer := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 1},
+		Type:                "Transfer",
+		Status:              types.String("Reverted"),
+	}
+	call := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 2},
+		Type:                "Call",
+		Status:              types.String("OutOfGas"),
+	}
+	ops := []*types.Operation{fee, transfer, call}
+
+	t.Run("split", func(t *testing.T) {
+		successful, failed, err := parser.SplitOperationsByStatus(ops)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Operation{fee}, successful)
+		assert.Equal(t, []*types.Operation{transfer, call}, failed)
+
+		successful, err = parser.SuccessfulOperations(ops)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Operation{fee}, successful)
+	})
+
+	t.Run("block counts", func(t *testing.T) {
+		block := &types.Block{
+			Transactions: []*types.Transaction{
+				{Operations: ops},
+				{Operations: []*types.Operation{fee}},
+			},
+		}
+
+		counts, err := parser.BlockOperationStatusCounts(block)
+		assert.NoError(t, err)
+		assert.Equal(t, &OperationStatusCounts{
+			Successful: 2,
+			Failed:     2,
+			Statuses: map[string]int{
+				"Success":  2,
+				"Reverted": 1,
+				"OutOfGas": 1,
+			},
+		}, counts)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		unknown := &types.Operation{
+			Type:   "Transfer",
+			Status: types.String("Pending"),
+		}
+
+		successful, failed, err := parser.SplitOperationsByStatus([]*types.Operation{unknown})
+		assert.Error(t, err)
+		assert.Nil(t, successful)
+		assert.Nil(t, failed)
+
+		counts, err := parser.OperationStatusCounts([]*types.Operation{fee, unknown})
+		assert.Error(t, err)
+		assert.Nil(t, counts)
+	})
+}
@@ -15,6 +15,8 @@
 package parser
 
 import (
+	"regexp"
+
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -24,6 +26,17 @@ type Parser struct {
 	Asserter          *asserter.Asserter
 	ExemptFunc        ExemptOperation
 	BalanceExemptions []*types.BalanceExemption
+
+	// CoinIdentifierPattern is an optional regular expression
+	// that all *types.CoinIdentifier must match in CoinChanges
+	// (ex: "^[0-9a-f]{64}:[0-9]+$" for Bitcoin-like chains).
+	CoinIdentifierPattern *regexp.Regexp
+
+	// MetadataSchemas is an optional map of operation type to the
+	// *MetadataSchema all operations of that type must satisfy. If
+	// populated, metadata is validated in ExpectedOperations and when
+	// computing balance changes.
+	MetadataSchemas map[string]*MetadataSchema
 }
 
 // New creates a new Parser.
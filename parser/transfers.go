@@ -0,0 +1,179 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Transfer is a normalized movement of some amount of a
+// *types.Currency from one account to another. If From is nil,
+// the amount was created (ex: minted). If To is nil, the amount
+// was destroyed (ex: burned or paid as a fee).
+type Transfer struct {
+	From     *types.AccountIdentifier `json:"from,omitempty"`
+	To       *types.AccountIdentifier `json:"to,omitempty"`
+	Amount   string                   `json:"amount"`
+	Currency *types.Currency          `json:"currency"`
+
+	// SelfTransfer is true if From and To are the same account.
+	SelfTransfer bool `json:"self_transfer"`
+}
+
+// transferLeg is a debit or credit that has
+// not yet been fully assigned to a *Transfer.
+type transferLeg struct {
+	account   *types.AccountIdentifier
+	remaining *big.Int
+}
+
+// Transfers returns all *Transfer in a *types.Transaction. Only
+// successful operations with a Type in transferTypes and a populated
+// Account and Amount are considered.
+//
+// Operations are first grouped using GroupOperations (so the
+// transaction should already be asserted). In each group, debits
+// (negative amounts) are assigned to credits (positive amounts) of the
+// same *types.Currency in order of appearance, splitting amounts where
+// necessary. This handles 1:1, 1:N, N:1, and N:M transfers. Any amount
+// that cannot be assigned is returned as a mint (nil From) or burn
+// (nil To).
+func (p *Parser) Transfers(
+	transaction *types.Transaction,
+	transferTypes []string,
+) ([]*Transfer, error) {
+	validTypes := map[string]struct{}{}
+	for _, transferType := range transferTypes {
+		validTypes[transferType] = struct{}{}
+	}
+
+	transfers := []*Transfer{}
+	for _, group := range GroupOperations(transaction) {
+		currencies := []*types.Currency{}
+		debits := map[string][]*transferLeg{}
+		credits := map[string][]*transferLeg{}
+		for _, op := range group.Operations {
+			if _, ok := validTypes[op.Type]; !ok {
+				continue
+			}
+
+			if op.Account == nil || op.Amount == nil {
+				continue
+			}
+
+			successful, err := p.Asserter.OperationSuccessful(op)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unable to check operation success", err)
+			}
+
+			if !successful {
+				continue
+			}
+
+			val, err := types.AmountValue(op.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unable to parse amount", err)
+			}
+
+			key := types.Hash(op.Amount.Currency)
+			if _, ok := debits[key]; !ok {
+				if _, ok := credits[key]; !ok {
+					currencies = append(currencies, op.Amount.Currency)
+				}
+			}
+
+			leg := &transferLeg{account: op.Account, remaining: new(big.Int).Abs(val)}
+			switch val.Sign() {
+			case -1:
+				debits[key] = append(debits[key], leg)
+			case 1:
+				credits[key] = append(credits[key], leg)
+			}
+		}
+
+		for _, currency := range currencies {
+			key := types.Hash(currency)
+			transfers = append(
+				transfers,
+				assignTransfers(debits[key], credits[key], currency)...,
+			)
+		}
+	}
+
+	return transfers, nil
+}
+
+// assignTransfers greedily assigns debits to credits
+// in order of appearance.
+func assignTransfers(
+	debits []*transferLeg,
+	credits []*transferLeg,
+	currency *types.Currency,
+) []*Transfer {
+	transfers := []*Transfer{}
+	d, c := 0, 0
+	for d < len(debits) && c < len(credits) {
+		debit, credit := debits[d], credits[c]
+		amount := debit.remaining
+		if credit.remaining.Cmp(amount) < 0 {
+			amount = credit.remaining
+		}
+
+		transfers = append(transfers, newTransfer(debit.account, credit.account, amount, currency))
+
+		debit.remaining = new(big.Int).Sub(debit.remaining, amount)
+		credit.remaining = new(big.Int).Sub(credit.remaining, amount)
+		if debit.remaining.Sign() == 0 {
+			d++
+		}
+		if credit.remaining.Sign() == 0 {
+			c++
+		}
+	}
+
+	for ; d < len(debits); d++ {
+		transfers = append(
+			transfers,
+			newTransfer(debits[d].account, nil, debits[d].remaining, currency),
+		)
+	}
+
+	for ; c < len(credits); c++ {
+		transfers = append(
+			transfers,
+			newTransfer(nil, credits[c].account, credits[c].remaining, currency),
+		)
+	}
+
+	return transfers
+}
+
+func newTransfer(
+	from *types.AccountIdentifier,
+	to *types.AccountIdentifier,
+	amount *big.Int,
+	currency *types.Currency,
+) *Transfer {
+	return &Transfer{
+		From:         from,
+		To:           to,
+		Amount:       amount.String(),
+		Currency:     currency,
+		SelfTransfer: from != nil && to != nil && from.Equal(to),
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func transferOperation(
+	index int64,
+	opType string,
+	address string,
+	value string,
+	currency *types.Currency,
+	related ...int64,
+) *types.Operation {
+	op := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: index,
+		},
+		Type:   opType,
+		Status: types.String("success"),
+		Account: &types.AccountIdentifier{
+			Address: address,
+		},
+		Amount: &types.Amount{
+			Value:    value,
+			Currency: currency,
+		},
+	}
+
+	for _, r := range related {
+		op.RelatedOperations = append(op.RelatedOperations, &types.OperationIdentifier{
+			Index: r,
+		})
+	}
+
+	return op
+}
+
+func TestTransfers(t *testing.T) {
+	var (
+		eth = &types.Currency{
+			Symbol:   "ETH",
+			Decimals: 18,
+		}
+
+		usdc = &types.Currency{
+			Symbol:   "USDC",
+			Decimals: 6,
+		}
+
+		addr1 = &types.AccountIdentifier{Address: "addr1"}
+		addr2 = &types.AccountIdentifier{Address: "addr2"}
+		addr3 = &types.AccountIdentifier{Address: "addr3"}
+	)
+
+	var tests = map[string]struct {
+		operations []*types.Operation
+		transfers  []*Transfer
+	}{
+		"simple transfer": {
+			operations: []*types.Operation{
+				transferOperation(0, "transfer", "addr1", "-100", eth),
+				transferOperation(1, "transfer", "addr2", "100", eth, 0),
+			},
+			transfers: []*Transfer{
+				{From: addr1, To: addr2, Amount: "100", Currency: eth},
+			},
+		},
+		"one to many with fee": {
+			operations: []*types.Operation{
+				transferOperation(0, "transfer", "addr1", "-100", eth),
+				transferOperation(1, "transfer", "addr2", "60", eth, 0),
+				transferOperation(2, "transfer", "addr3", "40", eth, 0),
+				transferOperation(3, "fee", "addr1", "-10", eth),
+			},
+			transfers: []*Transfer{
+				{From: addr1, To: addr2, Amount: "60", Currency: eth},
+				{From: addr1, To: addr3, Amount: "40", Currency: eth},
+				{From: addr1, Amount: "10", Currency: eth},
+			},
+		},
+		"many to many": {
+			operations: []*types.Operation{
+				transferOperation(0, "transfer", "addr1", "-50", eth),
+				transferOperation(1, "transfer", "addr2", "-50", eth),
+				transferOperation(2, "transfer", "addr3", "70", eth, 0, 1),
+				transferOperation(3, "transfer", "addr1", "30", eth, 0, 1),
+			},
+			transfers: []*Transfer{
+				{From: addr1, To: addr3, Amount: "50", Currency: eth},
+				{From: addr2, To: addr3, Amount: "20", Currency: eth},
+				{From: addr2, To: addr1, Amount: "30", Currency: eth},
+			},
+		},
+		"multi-currency and self transfer": {
+			operations: []*types.Operation{
+				transferOperation(0, "transfer", "addr1", "-100", eth),
+				transferOperation(1, "token_transfer", "addr1", "-5", usdc, 0),
+				transferOperation(2, "token_transfer", "addr1", "5", usdc, 1),
+				transferOperation(3, "transfer", "addr2", "100", eth, 0),
+			},
+			transfers: []*Transfer{
+				{From: addr1, To: addr2, Amount: "100", Currency: eth},
+				{From: addr1, To: addr1, Amount: "5", Currency: usdc, SelfTransfer: true},
+			},
+		},
+		"mint and ignored operations": {
+			operations: []*types.Operation{
+				transferOperation(0, "reward", "addr1", "10", eth),
+				transferOperation(1, "transfer", "addr2", "0", eth),
+				transferOperation(2, "ignored", "addr3", "-100", eth),
+				func() *types.Operation {
+					op := transferOperation(3, "transfer", "addr3", "-100", eth)
+					op.Status = types.String("failure")
+					return op
+				}(),
+			},
+			transfers: []*Transfer{
+				{To: addr1, Amount: "10", Currency: eth},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asserter, err := simpleAsserterConfiguration([]*types.OperationStatus{
+				{
+					Status:     "success",
+					Successful: true,
+				},
+				{
+					Status:     "failure",
+					Successful: false,
+				},
+			})
+			assert.NoError(t, err)
+
+			parser := New(asserter, nil, nil)
+			transfers, err := parser.Transfers(
+				&types.Transaction{Operations: test.operations},
+				[]string{"transfer", "token_transfer", "fee", "reward"},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.transfers, transfers)
+		})
+	}
+}
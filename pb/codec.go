@@ -0,0 +1,54 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrTypeUnsupported is returned when a value
+// that is not a pointer to a struct in the
+// types package is converted.
+var ErrTypeUnsupported = errors.New("type has no message")
+
+// Marshal encodes v (a pointer to any struct in the types
+// package, ex: *types.Block) as protobuf. This is more
+// compact than JSON, so it is useful for persisting (or
+// streaming) Rosetta data.
+func Marshal(v interface{}) ([]byte, error) {
+	m, err := FromType(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(m)
+}
+
+// Unmarshal decodes b (encoded by Marshal) into v (a
+// pointer to the same struct in the types package).
+func Unmarshal(b []byte, v interface{}) error {
+	m, err := newMessage(v)
+	if err != nil {
+		return err
+	}
+
+	if err := proto.Unmarshal(b, m); err != nil {
+		return err
+	}
+
+	return toType(m, v)
+}
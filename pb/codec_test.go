@@ -0,0 +1,94 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	var tests = map[string]struct {
+		value   interface{}
+		decoded interface{}
+	}{
+		"block": {
+			value: &types.Block{
+				BlockIdentifier:       &types.BlockIdentifier{Index: 2, Hash: "block 2"},
+				ParentBlockIdentifier: &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+				Timestamp:             1600000000000,
+				Transactions: []*types.Transaction{
+					{
+						TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx"},
+						Operations:            []*types.Operation{},
+						Metadata:              map[string]interface{}{"size": float64(250)},
+					},
+				},
+			},
+			decoded: &types.Block{},
+		},
+		"block event": {
+			value: &types.BlockEvent{
+				Sequence:        10,
+				BlockIdentifier: &types.BlockIdentifier{Index: 2, Hash: "block 2"},
+				Type:            types.REMOVED,
+			},
+			decoded: &types.BlockEvent{},
+		},
+		"signing payload": {
+			value: &types.SigningPayload{
+				AccountIdentifier: &types.AccountIdentifier{Address: "addr"},
+				Bytes:             []byte("payload"),
+				SignatureType:     types.Ecdsa,
+			},
+			decoded: &types.SigningPayload{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := Marshal(test.value)
+			assert.NoError(t, err)
+
+			assert.NoError(t, Unmarshal(b, test.decoded))
+			assert.Equal(t, test.value, test.decoded)
+
+			// Protobuf is more compact than JSON.
+			j, err := json.Marshal(test.value)
+			assert.NoError(t, err)
+			assert.Less(t, len(b), len(j))
+		})
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	_, err := Marshal(types.BlockIdentifier{Index: 1, Hash: "block 1"})
+	assert.True(t, errors.Is(err, ErrTypeUnsupported))
+
+	_, err = Marshal("block")
+	assert.True(t, errors.Is(err, ErrTypeUnsupported))
+
+	var block *Block
+	assert.True(t, errors.Is(Unmarshal([]byte{}, block), ErrTypeUnsupported))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	assert.Error(t, Unmarshal([]byte{0xff}, &types.Block{}))
+}
@@ -0,0 +1,4139 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by pb/internal/gen. DO NOT EDIT.
+
+package pb
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// FromAccountBalanceRequest converts a *types.AccountBalanceRequest to a *AccountBalanceRequest.
+func FromAccountBalanceRequest(v *types.AccountBalanceRequest) (*AccountBalanceRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountBalanceRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.AccountIdentifier != nil {
+		value, err := FromAccountIdentifier(v.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.AccountIdentifier = value
+	}
+	if v.BlockIdentifier != nil {
+		value, err := FromPartialBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	if len(v.Currencies) > 0 {
+		m.Currencies = make([]*Currency, len(v.Currencies))
+		for i, value := range v.Currencies {
+			converted, err := FromCurrency(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Currencies[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToAccountBalanceRequest converts a *AccountBalanceRequest to a *types.AccountBalanceRequest.
+func ToAccountBalanceRequest(m *AccountBalanceRequest) (*types.AccountBalanceRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountBalanceRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.AccountIdentifier != nil {
+		value, err := ToAccountIdentifier(m.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.AccountIdentifier = value
+	}
+	if m.BlockIdentifier != nil {
+		value, err := ToPartialBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	if len(m.Currencies) > 0 {
+		v.Currencies = make([]*types.Currency, len(m.Currencies))
+		for i, value := range m.Currencies {
+			converted, err := ToCurrency(value)
+			if err != nil {
+				return nil, err
+			}
+			v.Currencies[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromAccountBalanceResponse converts a *types.AccountBalanceResponse to a *AccountBalanceResponse.
+func FromAccountBalanceResponse(v *types.AccountBalanceResponse) (*AccountBalanceResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountBalanceResponse{}
+	if v.BlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	if len(v.Balances) > 0 {
+		m.Balances = make([]*Amount, len(v.Balances))
+		for i, value := range v.Balances {
+			converted, err := FromAmount(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Balances[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToAccountBalanceResponse converts a *AccountBalanceResponse to a *types.AccountBalanceResponse.
+func ToAccountBalanceResponse(m *AccountBalanceResponse) (*types.AccountBalanceResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountBalanceResponse{}
+	if m.BlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	v.Balances = make([]*types.Amount, len(m.Balances))
+	for i, value := range m.Balances {
+		converted, err := ToAmount(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Balances[i] = converted
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromAccountCoin converts a *types.AccountCoin to a *AccountCoin.
+func FromAccountCoin(v *types.AccountCoin) (*AccountCoin, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountCoin{}
+	if v.Account != nil {
+		value, err := FromAccountIdentifier(v.Account)
+		if err != nil {
+			return nil, err
+		}
+		m.Account = value
+	}
+	if v.Coin != nil {
+		value, err := FromCoin(v.Coin)
+		if err != nil {
+			return nil, err
+		}
+		m.Coin = value
+	}
+
+	return m, nil
+}
+
+// ToAccountCoin converts a *AccountCoin to a *types.AccountCoin.
+func ToAccountCoin(m *AccountCoin) (*types.AccountCoin, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountCoin{}
+	if m.Account != nil {
+		value, err := ToAccountIdentifier(m.Account)
+		if err != nil {
+			return nil, err
+		}
+		v.Account = value
+	}
+	if m.Coin != nil {
+		value, err := ToCoin(m.Coin)
+		if err != nil {
+			return nil, err
+		}
+		v.Coin = value
+	}
+
+	return v, nil
+}
+
+// FromAccountCoinsRequest converts a *types.AccountCoinsRequest to a *AccountCoinsRequest.
+func FromAccountCoinsRequest(v *types.AccountCoinsRequest) (*AccountCoinsRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountCoinsRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.AccountIdentifier != nil {
+		value, err := FromAccountIdentifier(v.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.AccountIdentifier = value
+	}
+	m.IncludeMempool = v.IncludeMempool
+	if len(v.Currencies) > 0 {
+		m.Currencies = make([]*Currency, len(v.Currencies))
+		for i, value := range v.Currencies {
+			converted, err := FromCurrency(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Currencies[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToAccountCoinsRequest converts a *AccountCoinsRequest to a *types.AccountCoinsRequest.
+func ToAccountCoinsRequest(m *AccountCoinsRequest) (*types.AccountCoinsRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountCoinsRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.AccountIdentifier != nil {
+		value, err := ToAccountIdentifier(m.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.AccountIdentifier = value
+	}
+	v.IncludeMempool = m.IncludeMempool
+	if len(m.Currencies) > 0 {
+		v.Currencies = make([]*types.Currency, len(m.Currencies))
+		for i, value := range m.Currencies {
+			converted, err := ToCurrency(value)
+			if err != nil {
+				return nil, err
+			}
+			v.Currencies[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromAccountCoinsResponse converts a *types.AccountCoinsResponse to a *AccountCoinsResponse.
+func FromAccountCoinsResponse(v *types.AccountCoinsResponse) (*AccountCoinsResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountCoinsResponse{}
+	if v.BlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	if len(v.Coins) > 0 {
+		m.Coins = make([]*Coin, len(v.Coins))
+		for i, value := range v.Coins {
+			converted, err := FromCoin(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Coins[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToAccountCoinsResponse converts a *AccountCoinsResponse to a *types.AccountCoinsResponse.
+func ToAccountCoinsResponse(m *AccountCoinsResponse) (*types.AccountCoinsResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountCoinsResponse{}
+	if m.BlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	v.Coins = make([]*types.Coin, len(m.Coins))
+	for i, value := range m.Coins {
+		converted, err := ToCoin(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Coins[i] = converted
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromAccountCurrency converts a *types.AccountCurrency to a *AccountCurrency.
+func FromAccountCurrency(v *types.AccountCurrency) (*AccountCurrency, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountCurrency{}
+	if v.Account != nil {
+		value, err := FromAccountIdentifier(v.Account)
+		if err != nil {
+			return nil, err
+		}
+		m.Account = value
+	}
+	if v.Currency != nil {
+		value, err := FromCurrency(v.Currency)
+		if err != nil {
+			return nil, err
+		}
+		m.Currency = value
+	}
+
+	return m, nil
+}
+
+// ToAccountCurrency converts a *AccountCurrency to a *types.AccountCurrency.
+func ToAccountCurrency(m *AccountCurrency) (*types.AccountCurrency, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountCurrency{}
+	if m.Account != nil {
+		value, err := ToAccountIdentifier(m.Account)
+		if err != nil {
+			return nil, err
+		}
+		v.Account = value
+	}
+	if m.Currency != nil {
+		value, err := ToCurrency(m.Currency)
+		if err != nil {
+			return nil, err
+		}
+		v.Currency = value
+	}
+
+	return v, nil
+}
+
+// FromAccountIdentifier converts a *types.AccountIdentifier to a *AccountIdentifier.
+func FromAccountIdentifier(v *types.AccountIdentifier) (*AccountIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &AccountIdentifier{}
+	m.Address = v.Address
+	if v.SubAccount != nil {
+		value, err := FromSubAccountIdentifier(v.SubAccount)
+		if err != nil {
+			return nil, err
+		}
+		m.SubAccount = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToAccountIdentifier converts a *AccountIdentifier to a *types.AccountIdentifier.
+func ToAccountIdentifier(m *AccountIdentifier) (*types.AccountIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.AccountIdentifier{}
+	v.Address = m.Address
+	if m.SubAccount != nil {
+		value, err := ToSubAccountIdentifier(m.SubAccount)
+		if err != nil {
+			return nil, err
+		}
+		v.SubAccount = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromAllow converts a *types.Allow to a *Allow.
+func FromAllow(v *types.Allow) (*Allow, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Allow{}
+	if len(v.OperationStatuses) > 0 {
+		m.OperationStatuses = make([]*OperationStatus, len(v.OperationStatuses))
+		for i, value := range v.OperationStatuses {
+			converted, err := FromOperationStatus(value)
+			if err != nil {
+				return nil, err
+			}
+			m.OperationStatuses[i] = converted
+		}
+	}
+	m.OperationTypes = v.OperationTypes
+	if len(v.Errors) > 0 {
+		m.Errors = make([]*Error, len(v.Errors))
+		for i, value := range v.Errors {
+			converted, err := FromError(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Errors[i] = converted
+		}
+	}
+	m.HistoricalBalanceLookup = v.HistoricalBalanceLookup
+	m.TimestampStartIndex = v.TimestampStartIndex
+	m.CallMethods = v.CallMethods
+	if len(v.BalanceExemptions) > 0 {
+		m.BalanceExemptions = make([]*BalanceExemption, len(v.BalanceExemptions))
+		for i, value := range v.BalanceExemptions {
+			converted, err := FromBalanceExemption(value)
+			if err != nil {
+				return nil, err
+			}
+			m.BalanceExemptions[i] = converted
+		}
+	}
+	m.MempoolCoins = v.MempoolCoins
+
+	return m, nil
+}
+
+// ToAllow converts a *Allow to a *types.Allow.
+func ToAllow(m *Allow) (*types.Allow, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Allow{}
+	v.OperationStatuses = make([]*types.OperationStatus, len(m.OperationStatuses))
+	for i, value := range m.OperationStatuses {
+		converted, err := ToOperationStatus(value)
+		if err != nil {
+			return nil, err
+		}
+		v.OperationStatuses[i] = converted
+	}
+	v.OperationTypes = m.OperationTypes
+	if v.OperationTypes == nil {
+		v.OperationTypes = []string{}
+	}
+	v.Errors = make([]*types.Error, len(m.Errors))
+	for i, value := range m.Errors {
+		converted, err := ToError(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Errors[i] = converted
+	}
+	v.HistoricalBalanceLookup = m.HistoricalBalanceLookup
+	v.TimestampStartIndex = m.TimestampStartIndex
+	v.CallMethods = m.CallMethods
+	if v.CallMethods == nil {
+		v.CallMethods = []string{}
+	}
+	v.BalanceExemptions = make([]*types.BalanceExemption, len(m.BalanceExemptions))
+	for i, value := range m.BalanceExemptions {
+		converted, err := ToBalanceExemption(value)
+		if err != nil {
+			return nil, err
+		}
+		v.BalanceExemptions[i] = converted
+	}
+	v.MempoolCoins = m.MempoolCoins
+
+	return v, nil
+}
+
+// FromAmount converts a *types.Amount to a *Amount.
+func FromAmount(v *types.Amount) (*Amount, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Amount{}
+	m.Value = v.Value
+	if v.Currency != nil {
+		value, err := FromCurrency(v.Currency)
+		if err != nil {
+			return nil, err
+		}
+		m.Currency = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToAmount converts a *Amount to a *types.Amount.
+func ToAmount(m *Amount) (*types.Amount, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Amount{}
+	v.Value = m.Value
+	if m.Currency != nil {
+		value, err := ToCurrency(m.Currency)
+		if err != nil {
+			return nil, err
+		}
+		v.Currency = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromBalanceExemption converts a *types.BalanceExemption to a *BalanceExemption.
+func FromBalanceExemption(v *types.BalanceExemption) (*BalanceExemption, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BalanceExemption{}
+	m.SubAccountAddress = v.SubAccountAddress
+	if v.Currency != nil {
+		value, err := FromCurrency(v.Currency)
+		if err != nil {
+			return nil, err
+		}
+		m.Currency = value
+	}
+	m.ExemptionType = string(v.ExemptionType)
+
+	return m, nil
+}
+
+// ToBalanceExemption converts a *BalanceExemption to a *types.BalanceExemption.
+func ToBalanceExemption(m *BalanceExemption) (*types.BalanceExemption, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BalanceExemption{}
+	v.SubAccountAddress = m.SubAccountAddress
+	if m.Currency != nil {
+		value, err := ToCurrency(m.Currency)
+		if err != nil {
+			return nil, err
+		}
+		v.Currency = value
+	}
+	v.ExemptionType = types.ExemptionType(m.ExemptionType)
+
+	return v, nil
+}
+
+// FromBlock converts a *types.Block to a *Block.
+func FromBlock(v *types.Block) (*Block, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Block{}
+	if v.BlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	if v.ParentBlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.ParentBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.ParentBlockIdentifier = value
+	}
+	m.Timestamp = v.Timestamp
+	if len(v.Transactions) > 0 {
+		m.Transactions = make([]*Transaction, len(v.Transactions))
+		for i, value := range v.Transactions {
+			converted, err := FromTransaction(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Transactions[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToBlock converts a *Block to a *types.Block.
+func ToBlock(m *Block) (*types.Block, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Block{}
+	if m.BlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	if m.ParentBlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.ParentBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.ParentBlockIdentifier = value
+	}
+	v.Timestamp = m.Timestamp
+	v.Transactions = make([]*types.Transaction, len(m.Transactions))
+	for i, value := range m.Transactions {
+		converted, err := ToTransaction(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Transactions[i] = converted
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromBlockEvent converts a *types.BlockEvent to a *BlockEvent.
+func FromBlockEvent(v *types.BlockEvent) (*BlockEvent, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockEvent{}
+	m.Sequence = v.Sequence
+	if v.BlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	m.Type = string(v.Type)
+
+	return m, nil
+}
+
+// ToBlockEvent converts a *BlockEvent to a *types.BlockEvent.
+func ToBlockEvent(m *BlockEvent) (*types.BlockEvent, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockEvent{}
+	v.Sequence = m.Sequence
+	if m.BlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	v.Type = types.BlockEventType(m.Type)
+
+	return v, nil
+}
+
+// FromBlockIdentifier converts a *types.BlockIdentifier to a *BlockIdentifier.
+func FromBlockIdentifier(v *types.BlockIdentifier) (*BlockIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockIdentifier{}
+	m.Index = v.Index
+	m.Hash = v.Hash
+
+	return m, nil
+}
+
+// ToBlockIdentifier converts a *BlockIdentifier to a *types.BlockIdentifier.
+func ToBlockIdentifier(m *BlockIdentifier) (*types.BlockIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockIdentifier{}
+	v.Index = m.Index
+	v.Hash = m.Hash
+
+	return v, nil
+}
+
+// FromBlockRequest converts a *types.BlockRequest to a *BlockRequest.
+func FromBlockRequest(v *types.BlockRequest) (*BlockRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.BlockIdentifier != nil {
+		value, err := FromPartialBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+
+	return m, nil
+}
+
+// ToBlockRequest converts a *BlockRequest to a *types.BlockRequest.
+func ToBlockRequest(m *BlockRequest) (*types.BlockRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.BlockIdentifier != nil {
+		value, err := ToPartialBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+
+	return v, nil
+}
+
+// FromBlockResponse converts a *types.BlockResponse to a *BlockResponse.
+func FromBlockResponse(v *types.BlockResponse) (*BlockResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockResponse{}
+	if v.Block != nil {
+		value, err := FromBlock(v.Block)
+		if err != nil {
+			return nil, err
+		}
+		m.Block = value
+	}
+	if len(v.OtherTransactions) > 0 {
+		m.OtherTransactions = make([]*TransactionIdentifier, len(v.OtherTransactions))
+		for i, value := range v.OtherTransactions {
+			converted, err := FromTransactionIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			m.OtherTransactions[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToBlockResponse converts a *BlockResponse to a *types.BlockResponse.
+func ToBlockResponse(m *BlockResponse) (*types.BlockResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockResponse{}
+	if m.Block != nil {
+		value, err := ToBlock(m.Block)
+		if err != nil {
+			return nil, err
+		}
+		v.Block = value
+	}
+	if len(m.OtherTransactions) > 0 {
+		v.OtherTransactions = make([]*types.TransactionIdentifier, len(m.OtherTransactions))
+		for i, value := range m.OtherTransactions {
+			converted, err := ToTransactionIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			v.OtherTransactions[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromBlockTransaction converts a *types.BlockTransaction to a *BlockTransaction.
+func FromBlockTransaction(v *types.BlockTransaction) (*BlockTransaction, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockTransaction{}
+	if v.BlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	if v.Transaction != nil {
+		value, err := FromTransaction(v.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		m.Transaction = value
+	}
+
+	return m, nil
+}
+
+// ToBlockTransaction converts a *BlockTransaction to a *types.BlockTransaction.
+func ToBlockTransaction(m *BlockTransaction) (*types.BlockTransaction, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockTransaction{}
+	if m.BlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	if m.Transaction != nil {
+		value, err := ToTransaction(m.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		v.Transaction = value
+	}
+
+	return v, nil
+}
+
+// FromBlockTransactionRequest converts a *types.BlockTransactionRequest to a *BlockTransactionRequest.
+func FromBlockTransactionRequest(v *types.BlockTransactionRequest) (*BlockTransactionRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockTransactionRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.BlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.BlockIdentifier = value
+	}
+	if v.TransactionIdentifier != nil {
+		value, err := FromTransactionIdentifier(v.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.TransactionIdentifier = value
+	}
+
+	return m, nil
+}
+
+// ToBlockTransactionRequest converts a *BlockTransactionRequest to a *types.BlockTransactionRequest.
+func ToBlockTransactionRequest(m *BlockTransactionRequest) (*types.BlockTransactionRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockTransactionRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.BlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.BlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.BlockIdentifier = value
+	}
+	if m.TransactionIdentifier != nil {
+		value, err := ToTransactionIdentifier(m.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifier = value
+	}
+
+	return v, nil
+}
+
+// FromBlockTransactionResponse converts a *types.BlockTransactionResponse to a *BlockTransactionResponse.
+func FromBlockTransactionResponse(v *types.BlockTransactionResponse) (*BlockTransactionResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &BlockTransactionResponse{}
+	if v.Transaction != nil {
+		value, err := FromTransaction(v.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		m.Transaction = value
+	}
+
+	return m, nil
+}
+
+// ToBlockTransactionResponse converts a *BlockTransactionResponse to a *types.BlockTransactionResponse.
+func ToBlockTransactionResponse(m *BlockTransactionResponse) (*types.BlockTransactionResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.BlockTransactionResponse{}
+	if m.Transaction != nil {
+		value, err := ToTransaction(m.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		v.Transaction = value
+	}
+
+	return v, nil
+}
+
+// FromCallRequest converts a *types.CallRequest to a *CallRequest.
+func FromCallRequest(v *types.CallRequest) (*CallRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &CallRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	m.Method = v.Method
+	parameters, err := marshalMetadata(v.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	m.Parameters = parameters
+
+	return m, nil
+}
+
+// ToCallRequest converts a *CallRequest to a *types.CallRequest.
+func ToCallRequest(m *CallRequest) (*types.CallRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.CallRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.Method = m.Method
+	parameters, err := unmarshalMetadata(m.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	v.Parameters = parameters
+
+	return v, nil
+}
+
+// FromCallResponse converts a *types.CallResponse to a *CallResponse.
+func FromCallResponse(v *types.CallResponse) (*CallResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &CallResponse{}
+	result, err := marshalMetadata(v.Result)
+	if err != nil {
+		return nil, err
+	}
+	m.Result = result
+	m.Idempotent = v.Idempotent
+
+	return m, nil
+}
+
+// ToCallResponse converts a *CallResponse to a *types.CallResponse.
+func ToCallResponse(m *CallResponse) (*types.CallResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.CallResponse{}
+	result, err := unmarshalMetadata(m.Result)
+	if err != nil {
+		return nil, err
+	}
+	v.Result = result
+	v.Idempotent = m.Idempotent
+
+	return v, nil
+}
+
+// FromCoin converts a *types.Coin to a *Coin.
+func FromCoin(v *types.Coin) (*Coin, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Coin{}
+	if v.CoinIdentifier != nil {
+		value, err := FromCoinIdentifier(v.CoinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.CoinIdentifier = value
+	}
+	if v.Amount != nil {
+		value, err := FromAmount(v.Amount)
+		if err != nil {
+			return nil, err
+		}
+		m.Amount = value
+	}
+
+	return m, nil
+}
+
+// ToCoin converts a *Coin to a *types.Coin.
+func ToCoin(m *Coin) (*types.Coin, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Coin{}
+	if m.CoinIdentifier != nil {
+		value, err := ToCoinIdentifier(m.CoinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.CoinIdentifier = value
+	}
+	if m.Amount != nil {
+		value, err := ToAmount(m.Amount)
+		if err != nil {
+			return nil, err
+		}
+		v.Amount = value
+	}
+
+	return v, nil
+}
+
+// FromCoinChange converts a *types.CoinChange to a *CoinChange.
+func FromCoinChange(v *types.CoinChange) (*CoinChange, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &CoinChange{}
+	if v.CoinIdentifier != nil {
+		value, err := FromCoinIdentifier(v.CoinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.CoinIdentifier = value
+	}
+	m.CoinAction = string(v.CoinAction)
+
+	return m, nil
+}
+
+// ToCoinChange converts a *CoinChange to a *types.CoinChange.
+func ToCoinChange(m *CoinChange) (*types.CoinChange, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.CoinChange{}
+	if m.CoinIdentifier != nil {
+		value, err := ToCoinIdentifier(m.CoinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.CoinIdentifier = value
+	}
+	v.CoinAction = types.CoinAction(m.CoinAction)
+
+	return v, nil
+}
+
+// FromCoinIdentifier converts a *types.CoinIdentifier to a *CoinIdentifier.
+func FromCoinIdentifier(v *types.CoinIdentifier) (*CoinIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &CoinIdentifier{}
+	m.Identifier = v.Identifier
+
+	return m, nil
+}
+
+// ToCoinIdentifier converts a *CoinIdentifier to a *types.CoinIdentifier.
+func ToCoinIdentifier(m *CoinIdentifier) (*types.CoinIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.CoinIdentifier{}
+	v.Identifier = m.Identifier
+
+	return v, nil
+}
+
+// FromConstructionCombineRequest converts a *types.ConstructionCombineRequest to a *ConstructionCombineRequest.
+func FromConstructionCombineRequest(v *types.ConstructionCombineRequest) (*ConstructionCombineRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionCombineRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	m.UnsignedTransaction = v.UnsignedTransaction
+	if len(v.Signatures) > 0 {
+		m.Signatures = make([]*Signature, len(v.Signatures))
+		for i, value := range v.Signatures {
+			converted, err := FromSignature(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Signatures[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToConstructionCombineRequest converts a *ConstructionCombineRequest to a *types.ConstructionCombineRequest.
+func ToConstructionCombineRequest(m *ConstructionCombineRequest) (*types.ConstructionCombineRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionCombineRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.UnsignedTransaction = m.UnsignedTransaction
+	v.Signatures = make([]*types.Signature, len(m.Signatures))
+	for i, value := range m.Signatures {
+		converted, err := ToSignature(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Signatures[i] = converted
+	}
+
+	return v, nil
+}
+
+// FromConstructionCombineResponse converts a *types.ConstructionCombineResponse to a *ConstructionCombineResponse.
+func FromConstructionCombineResponse(v *types.ConstructionCombineResponse) (*ConstructionCombineResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionCombineResponse{}
+	m.SignedTransaction = v.SignedTransaction
+
+	return m, nil
+}
+
+// ToConstructionCombineResponse converts a *ConstructionCombineResponse to a *types.ConstructionCombineResponse.
+func ToConstructionCombineResponse(m *ConstructionCombineResponse) (*types.ConstructionCombineResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionCombineResponse{}
+	v.SignedTransaction = m.SignedTransaction
+
+	return v, nil
+}
+
+// FromConstructionDeriveRequest converts a *types.ConstructionDeriveRequest to a *ConstructionDeriveRequest.
+func FromConstructionDeriveRequest(v *types.ConstructionDeriveRequest) (*ConstructionDeriveRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionDeriveRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.PublicKey != nil {
+		value, err := FromPublicKey(v.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		m.PublicKey = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToConstructionDeriveRequest converts a *ConstructionDeriveRequest to a *types.ConstructionDeriveRequest.
+func ToConstructionDeriveRequest(m *ConstructionDeriveRequest) (*types.ConstructionDeriveRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionDeriveRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.PublicKey != nil {
+		value, err := ToPublicKey(m.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		v.PublicKey = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromConstructionDeriveResponse converts a *types.ConstructionDeriveResponse to a *ConstructionDeriveResponse.
+func FromConstructionDeriveResponse(v *types.ConstructionDeriveResponse) (*ConstructionDeriveResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionDeriveResponse{}
+	if v.AccountIdentifier != nil {
+		value, err := FromAccountIdentifier(v.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.AccountIdentifier = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToConstructionDeriveResponse converts a *ConstructionDeriveResponse to a *types.ConstructionDeriveResponse.
+func ToConstructionDeriveResponse(m *ConstructionDeriveResponse) (*types.ConstructionDeriveResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionDeriveResponse{}
+	if m.AccountIdentifier != nil {
+		value, err := ToAccountIdentifier(m.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.AccountIdentifier = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromConstructionHashRequest converts a *types.ConstructionHashRequest to a *ConstructionHashRequest.
+func FromConstructionHashRequest(v *types.ConstructionHashRequest) (*ConstructionHashRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionHashRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	m.SignedTransaction = v.SignedTransaction
+
+	return m, nil
+}
+
+// ToConstructionHashRequest converts a *ConstructionHashRequest to a *types.ConstructionHashRequest.
+func ToConstructionHashRequest(m *ConstructionHashRequest) (*types.ConstructionHashRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionHashRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.SignedTransaction = m.SignedTransaction
+
+	return v, nil
+}
+
+// FromConstructionMetadataRequest converts a *types.ConstructionMetadataRequest to a *ConstructionMetadataRequest.
+func FromConstructionMetadataRequest(v *types.ConstructionMetadataRequest) (*ConstructionMetadataRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionMetadataRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	options, err := marshalMetadata(v.Options)
+	if err != nil {
+		return nil, err
+	}
+	m.Options = options
+	if len(v.PublicKeys) > 0 {
+		m.PublicKeys = make([]*PublicKey, len(v.PublicKeys))
+		for i, value := range v.PublicKeys {
+			converted, err := FromPublicKey(value)
+			if err != nil {
+				return nil, err
+			}
+			m.PublicKeys[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToConstructionMetadataRequest converts a *ConstructionMetadataRequest to a *types.ConstructionMetadataRequest.
+func ToConstructionMetadataRequest(m *ConstructionMetadataRequest) (*types.ConstructionMetadataRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionMetadataRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	options, err := unmarshalMetadata(m.Options)
+	if err != nil {
+		return nil, err
+	}
+	v.Options = options
+	if len(m.PublicKeys) > 0 {
+		v.PublicKeys = make([]*types.PublicKey, len(m.PublicKeys))
+		for i, value := range m.PublicKeys {
+			converted, err := ToPublicKey(value)
+			if err != nil {
+				return nil, err
+			}
+			v.PublicKeys[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromConstructionMetadataResponse converts a *types.ConstructionMetadataResponse to a *ConstructionMetadataResponse.
+func FromConstructionMetadataResponse(v *types.ConstructionMetadataResponse) (*ConstructionMetadataResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionMetadataResponse{}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+	if len(v.SuggestedFee) > 0 {
+		m.SuggestedFee = make([]*Amount, len(v.SuggestedFee))
+		for i, value := range v.SuggestedFee {
+			converted, err := FromAmount(value)
+			if err != nil {
+				return nil, err
+			}
+			m.SuggestedFee[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToConstructionMetadataResponse converts a *ConstructionMetadataResponse to a *types.ConstructionMetadataResponse.
+func ToConstructionMetadataResponse(m *ConstructionMetadataResponse) (*types.ConstructionMetadataResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionMetadataResponse{}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+	if len(m.SuggestedFee) > 0 {
+		v.SuggestedFee = make([]*types.Amount, len(m.SuggestedFee))
+		for i, value := range m.SuggestedFee {
+			converted, err := ToAmount(value)
+			if err != nil {
+				return nil, err
+			}
+			v.SuggestedFee[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromConstructionParseRequest converts a *types.ConstructionParseRequest to a *ConstructionParseRequest.
+func FromConstructionParseRequest(v *types.ConstructionParseRequest) (*ConstructionParseRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionParseRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	m.Signed = v.Signed
+	m.Transaction = v.Transaction
+
+	return m, nil
+}
+
+// ToConstructionParseRequest converts a *ConstructionParseRequest to a *types.ConstructionParseRequest.
+func ToConstructionParseRequest(m *ConstructionParseRequest) (*types.ConstructionParseRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionParseRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.Signed = m.Signed
+	v.Transaction = m.Transaction
+
+	return v, nil
+}
+
+// FromConstructionParseResponse converts a *types.ConstructionParseResponse to a *ConstructionParseResponse.
+func FromConstructionParseResponse(v *types.ConstructionParseResponse) (*ConstructionParseResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionParseResponse{}
+	if len(v.Operations) > 0 {
+		m.Operations = make([]*Operation, len(v.Operations))
+		for i, value := range v.Operations {
+			converted, err := FromOperation(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Operations[i] = converted
+		}
+	}
+	if len(v.AccountIdentifierSigners) > 0 {
+		m.AccountIdentifierSigners = make([]*AccountIdentifier, len(v.AccountIdentifierSigners))
+		for i, value := range v.AccountIdentifierSigners {
+			converted, err := FromAccountIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			m.AccountIdentifierSigners[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToConstructionParseResponse converts a *ConstructionParseResponse to a *types.ConstructionParseResponse.
+func ToConstructionParseResponse(m *ConstructionParseResponse) (*types.ConstructionParseResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionParseResponse{}
+	v.Operations = make([]*types.Operation, len(m.Operations))
+	for i, value := range m.Operations {
+		converted, err := ToOperation(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Operations[i] = converted
+	}
+	if len(m.AccountIdentifierSigners) > 0 {
+		v.AccountIdentifierSigners = make([]*types.AccountIdentifier, len(m.AccountIdentifierSigners))
+		for i, value := range m.AccountIdentifierSigners {
+			converted, err := ToAccountIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			v.AccountIdentifierSigners[i] = converted
+		}
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromConstructionPayloadsRequest converts a *types.ConstructionPayloadsRequest to a *ConstructionPayloadsRequest.
+func FromConstructionPayloadsRequest(v *types.ConstructionPayloadsRequest) (*ConstructionPayloadsRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionPayloadsRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if len(v.Operations) > 0 {
+		m.Operations = make([]*Operation, len(v.Operations))
+		for i, value := range v.Operations {
+			converted, err := FromOperation(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Operations[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+	if len(v.PublicKeys) > 0 {
+		m.PublicKeys = make([]*PublicKey, len(v.PublicKeys))
+		for i, value := range v.PublicKeys {
+			converted, err := FromPublicKey(value)
+			if err != nil {
+				return nil, err
+			}
+			m.PublicKeys[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToConstructionPayloadsRequest converts a *ConstructionPayloadsRequest to a *types.ConstructionPayloadsRequest.
+func ToConstructionPayloadsRequest(m *ConstructionPayloadsRequest) (*types.ConstructionPayloadsRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionPayloadsRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.Operations = make([]*types.Operation, len(m.Operations))
+	for i, value := range m.Operations {
+		converted, err := ToOperation(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Operations[i] = converted
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+	if len(m.PublicKeys) > 0 {
+		v.PublicKeys = make([]*types.PublicKey, len(m.PublicKeys))
+		for i, value := range m.PublicKeys {
+			converted, err := ToPublicKey(value)
+			if err != nil {
+				return nil, err
+			}
+			v.PublicKeys[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromConstructionPayloadsResponse converts a *types.ConstructionPayloadsResponse to a *ConstructionPayloadsResponse.
+func FromConstructionPayloadsResponse(v *types.ConstructionPayloadsResponse) (*ConstructionPayloadsResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionPayloadsResponse{}
+	m.UnsignedTransaction = v.UnsignedTransaction
+	if len(v.Payloads) > 0 {
+		m.Payloads = make([]*SigningPayload, len(v.Payloads))
+		for i, value := range v.Payloads {
+			converted, err := FromSigningPayload(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Payloads[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToConstructionPayloadsResponse converts a *ConstructionPayloadsResponse to a *types.ConstructionPayloadsResponse.
+func ToConstructionPayloadsResponse(m *ConstructionPayloadsResponse) (*types.ConstructionPayloadsResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionPayloadsResponse{}
+	v.UnsignedTransaction = m.UnsignedTransaction
+	v.Payloads = make([]*types.SigningPayload, len(m.Payloads))
+	for i, value := range m.Payloads {
+		converted, err := ToSigningPayload(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Payloads[i] = converted
+	}
+
+	return v, nil
+}
+
+// FromConstructionPreprocessRequest converts a *types.ConstructionPreprocessRequest to a *ConstructionPreprocessRequest.
+func FromConstructionPreprocessRequest(v *types.ConstructionPreprocessRequest) (*ConstructionPreprocessRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionPreprocessRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if len(v.Operations) > 0 {
+		m.Operations = make([]*Operation, len(v.Operations))
+		for i, value := range v.Operations {
+			converted, err := FromOperation(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Operations[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+	if len(v.MaxFee) > 0 {
+		m.MaxFee = make([]*Amount, len(v.MaxFee))
+		for i, value := range v.MaxFee {
+			converted, err := FromAmount(value)
+			if err != nil {
+				return nil, err
+			}
+			m.MaxFee[i] = converted
+		}
+	}
+	m.SuggestedFeeMultiplier = v.SuggestedFeeMultiplier
+
+	return m, nil
+}
+
+// ToConstructionPreprocessRequest converts a *ConstructionPreprocessRequest to a *types.ConstructionPreprocessRequest.
+func ToConstructionPreprocessRequest(m *ConstructionPreprocessRequest) (*types.ConstructionPreprocessRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionPreprocessRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.Operations = make([]*types.Operation, len(m.Operations))
+	for i, value := range m.Operations {
+		converted, err := ToOperation(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Operations[i] = converted
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+	if len(m.MaxFee) > 0 {
+		v.MaxFee = make([]*types.Amount, len(m.MaxFee))
+		for i, value := range m.MaxFee {
+			converted, err := ToAmount(value)
+			if err != nil {
+				return nil, err
+			}
+			v.MaxFee[i] = converted
+		}
+	}
+	v.SuggestedFeeMultiplier = m.SuggestedFeeMultiplier
+
+	return v, nil
+}
+
+// FromConstructionPreprocessResponse converts a *types.ConstructionPreprocessResponse to a *ConstructionPreprocessResponse.
+func FromConstructionPreprocessResponse(v *types.ConstructionPreprocessResponse) (*ConstructionPreprocessResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionPreprocessResponse{}
+	options, err := marshalMetadata(v.Options)
+	if err != nil {
+		return nil, err
+	}
+	m.Options = options
+	if len(v.RequiredPublicKeys) > 0 {
+		m.RequiredPublicKeys = make([]*AccountIdentifier, len(v.RequiredPublicKeys))
+		for i, value := range v.RequiredPublicKeys {
+			converted, err := FromAccountIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			m.RequiredPublicKeys[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToConstructionPreprocessResponse converts a *ConstructionPreprocessResponse to a *types.ConstructionPreprocessResponse.
+func ToConstructionPreprocessResponse(m *ConstructionPreprocessResponse) (*types.ConstructionPreprocessResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionPreprocessResponse{}
+	options, err := unmarshalMetadata(m.Options)
+	if err != nil {
+		return nil, err
+	}
+	v.Options = options
+	if len(m.RequiredPublicKeys) > 0 {
+		v.RequiredPublicKeys = make([]*types.AccountIdentifier, len(m.RequiredPublicKeys))
+		for i, value := range m.RequiredPublicKeys {
+			converted, err := ToAccountIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			v.RequiredPublicKeys[i] = converted
+		}
+	}
+
+	return v, nil
+}
+
+// FromConstructionSubmitRequest converts a *types.ConstructionSubmitRequest to a *ConstructionSubmitRequest.
+func FromConstructionSubmitRequest(v *types.ConstructionSubmitRequest) (*ConstructionSubmitRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &ConstructionSubmitRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	m.SignedTransaction = v.SignedTransaction
+
+	return m, nil
+}
+
+// ToConstructionSubmitRequest converts a *ConstructionSubmitRequest to a *types.ConstructionSubmitRequest.
+func ToConstructionSubmitRequest(m *ConstructionSubmitRequest) (*types.ConstructionSubmitRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.ConstructionSubmitRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.SignedTransaction = m.SignedTransaction
+
+	return v, nil
+}
+
+// FromCurrency converts a *types.Currency to a *Currency.
+func FromCurrency(v *types.Currency) (*Currency, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Currency{}
+	m.Symbol = v.Symbol
+	m.Decimals = v.Decimals
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToCurrency converts a *Currency to a *types.Currency.
+func ToCurrency(m *Currency) (*types.Currency, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Currency{}
+	v.Symbol = m.Symbol
+	v.Decimals = m.Decimals
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromError converts a *types.Error to a *Error.
+func FromError(v *types.Error) (*Error, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Error{}
+	m.Code = v.Code
+	m.Message = v.Message
+	m.Description = v.Description
+	m.Retriable = v.Retriable
+	details, err := marshalMetadata(v.Details)
+	if err != nil {
+		return nil, err
+	}
+	m.Details = details
+
+	return m, nil
+}
+
+// ToError converts a *Error to a *types.Error.
+func ToError(m *Error) (*types.Error, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Error{}
+	v.Code = m.Code
+	v.Message = m.Message
+	v.Description = m.Description
+	v.Retriable = m.Retriable
+	details, err := unmarshalMetadata(m.Details)
+	if err != nil {
+		return nil, err
+	}
+	v.Details = details
+
+	return v, nil
+}
+
+// FromEventsBlocksRequest converts a *types.EventsBlocksRequest to a *EventsBlocksRequest.
+func FromEventsBlocksRequest(v *types.EventsBlocksRequest) (*EventsBlocksRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &EventsBlocksRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	m.Offset = v.Offset
+	m.Limit = v.Limit
+
+	return m, nil
+}
+
+// ToEventsBlocksRequest converts a *EventsBlocksRequest to a *types.EventsBlocksRequest.
+func ToEventsBlocksRequest(m *EventsBlocksRequest) (*types.EventsBlocksRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.EventsBlocksRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	v.Offset = m.Offset
+	v.Limit = m.Limit
+
+	return v, nil
+}
+
+// FromEventsBlocksResponse converts a *types.EventsBlocksResponse to a *EventsBlocksResponse.
+func FromEventsBlocksResponse(v *types.EventsBlocksResponse) (*EventsBlocksResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &EventsBlocksResponse{}
+	m.MaxSequence = v.MaxSequence
+	if len(v.Events) > 0 {
+		m.Events = make([]*BlockEvent, len(v.Events))
+		for i, value := range v.Events {
+			converted, err := FromBlockEvent(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Events[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToEventsBlocksResponse converts a *EventsBlocksResponse to a *types.EventsBlocksResponse.
+func ToEventsBlocksResponse(m *EventsBlocksResponse) (*types.EventsBlocksResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.EventsBlocksResponse{}
+	v.MaxSequence = m.MaxSequence
+	v.Events = make([]*types.BlockEvent, len(m.Events))
+	for i, value := range m.Events {
+		converted, err := ToBlockEvent(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Events[i] = converted
+	}
+
+	return v, nil
+}
+
+// FromMempoolResponse converts a *types.MempoolResponse to a *MempoolResponse.
+func FromMempoolResponse(v *types.MempoolResponse) (*MempoolResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &MempoolResponse{}
+	if len(v.TransactionIdentifiers) > 0 {
+		m.TransactionIdentifiers = make([]*TransactionIdentifier, len(v.TransactionIdentifiers))
+		for i, value := range v.TransactionIdentifiers {
+			converted, err := FromTransactionIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			m.TransactionIdentifiers[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToMempoolResponse converts a *MempoolResponse to a *types.MempoolResponse.
+func ToMempoolResponse(m *MempoolResponse) (*types.MempoolResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.MempoolResponse{}
+	v.TransactionIdentifiers = make([]*types.TransactionIdentifier, len(m.TransactionIdentifiers))
+	for i, value := range m.TransactionIdentifiers {
+		converted, err := ToTransactionIdentifier(value)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifiers[i] = converted
+	}
+
+	return v, nil
+}
+
+// FromMempoolTransactionRequest converts a *types.MempoolTransactionRequest to a *MempoolTransactionRequest.
+func FromMempoolTransactionRequest(v *types.MempoolTransactionRequest) (*MempoolTransactionRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &MempoolTransactionRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.TransactionIdentifier != nil {
+		value, err := FromTransactionIdentifier(v.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.TransactionIdentifier = value
+	}
+
+	return m, nil
+}
+
+// ToMempoolTransactionRequest converts a *MempoolTransactionRequest to a *types.MempoolTransactionRequest.
+func ToMempoolTransactionRequest(m *MempoolTransactionRequest) (*types.MempoolTransactionRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.MempoolTransactionRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.TransactionIdentifier != nil {
+		value, err := ToTransactionIdentifier(m.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifier = value
+	}
+
+	return v, nil
+}
+
+// FromMempoolTransactionResponse converts a *types.MempoolTransactionResponse to a *MempoolTransactionResponse.
+func FromMempoolTransactionResponse(v *types.MempoolTransactionResponse) (*MempoolTransactionResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &MempoolTransactionResponse{}
+	if v.Transaction != nil {
+		value, err := FromTransaction(v.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		m.Transaction = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToMempoolTransactionResponse converts a *MempoolTransactionResponse to a *types.MempoolTransactionResponse.
+func ToMempoolTransactionResponse(m *MempoolTransactionResponse) (*types.MempoolTransactionResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.MempoolTransactionResponse{}
+	if m.Transaction != nil {
+		value, err := ToTransaction(m.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		v.Transaction = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromMetadataRequest converts a *types.MetadataRequest to a *MetadataRequest.
+func FromMetadataRequest(v *types.MetadataRequest) (*MetadataRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &MetadataRequest{}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToMetadataRequest converts a *MetadataRequest to a *types.MetadataRequest.
+func ToMetadataRequest(m *MetadataRequest) (*types.MetadataRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.MetadataRequest{}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromNetworkIdentifier converts a *types.NetworkIdentifier to a *NetworkIdentifier.
+func FromNetworkIdentifier(v *types.NetworkIdentifier) (*NetworkIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &NetworkIdentifier{}
+	m.Blockchain = v.Blockchain
+	m.Network = v.Network
+	if v.SubNetworkIdentifier != nil {
+		value, err := FromSubNetworkIdentifier(v.SubNetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.SubNetworkIdentifier = value
+	}
+
+	return m, nil
+}
+
+// ToNetworkIdentifier converts a *NetworkIdentifier to a *types.NetworkIdentifier.
+func ToNetworkIdentifier(m *NetworkIdentifier) (*types.NetworkIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.NetworkIdentifier{}
+	v.Blockchain = m.Blockchain
+	v.Network = m.Network
+	if m.SubNetworkIdentifier != nil {
+		value, err := ToSubNetworkIdentifier(m.SubNetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.SubNetworkIdentifier = value
+	}
+
+	return v, nil
+}
+
+// FromNetworkListResponse converts a *types.NetworkListResponse to a *NetworkListResponse.
+func FromNetworkListResponse(v *types.NetworkListResponse) (*NetworkListResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &NetworkListResponse{}
+	if len(v.NetworkIdentifiers) > 0 {
+		m.NetworkIdentifiers = make([]*NetworkIdentifier, len(v.NetworkIdentifiers))
+		for i, value := range v.NetworkIdentifiers {
+			converted, err := FromNetworkIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			m.NetworkIdentifiers[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToNetworkListResponse converts a *NetworkListResponse to a *types.NetworkListResponse.
+func ToNetworkListResponse(m *NetworkListResponse) (*types.NetworkListResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.NetworkListResponse{}
+	v.NetworkIdentifiers = make([]*types.NetworkIdentifier, len(m.NetworkIdentifiers))
+	for i, value := range m.NetworkIdentifiers {
+		converted, err := ToNetworkIdentifier(value)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifiers[i] = converted
+	}
+
+	return v, nil
+}
+
+// FromNetworkOptionsResponse converts a *types.NetworkOptionsResponse to a *NetworkOptionsResponse.
+func FromNetworkOptionsResponse(v *types.NetworkOptionsResponse) (*NetworkOptionsResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &NetworkOptionsResponse{}
+	if v.Version != nil {
+		value, err := FromVersion(v.Version)
+		if err != nil {
+			return nil, err
+		}
+		m.Version = value
+	}
+	if v.Allow != nil {
+		value, err := FromAllow(v.Allow)
+		if err != nil {
+			return nil, err
+		}
+		m.Allow = value
+	}
+
+	return m, nil
+}
+
+// ToNetworkOptionsResponse converts a *NetworkOptionsResponse to a *types.NetworkOptionsResponse.
+func ToNetworkOptionsResponse(m *NetworkOptionsResponse) (*types.NetworkOptionsResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.NetworkOptionsResponse{}
+	if m.Version != nil {
+		value, err := ToVersion(m.Version)
+		if err != nil {
+			return nil, err
+		}
+		v.Version = value
+	}
+	if m.Allow != nil {
+		value, err := ToAllow(m.Allow)
+		if err != nil {
+			return nil, err
+		}
+		v.Allow = value
+	}
+
+	return v, nil
+}
+
+// FromNetworkRequest converts a *types.NetworkRequest to a *NetworkRequest.
+func FromNetworkRequest(v *types.NetworkRequest) (*NetworkRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &NetworkRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToNetworkRequest converts a *NetworkRequest to a *types.NetworkRequest.
+func ToNetworkRequest(m *NetworkRequest) (*types.NetworkRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.NetworkRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromNetworkStatusResponse converts a *types.NetworkStatusResponse to a *NetworkStatusResponse.
+func FromNetworkStatusResponse(v *types.NetworkStatusResponse) (*NetworkStatusResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &NetworkStatusResponse{}
+	if v.CurrentBlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.CurrentBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.CurrentBlockIdentifier = value
+	}
+	m.CurrentBlockTimestamp = v.CurrentBlockTimestamp
+	if v.GenesisBlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.GenesisBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.GenesisBlockIdentifier = value
+	}
+	if v.OldestBlockIdentifier != nil {
+		value, err := FromBlockIdentifier(v.OldestBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.OldestBlockIdentifier = value
+	}
+	if v.SyncStatus != nil {
+		value, err := FromSyncStatus(v.SyncStatus)
+		if err != nil {
+			return nil, err
+		}
+		m.SyncStatus = value
+	}
+	if len(v.Peers) > 0 {
+		m.Peers = make([]*Peer, len(v.Peers))
+		for i, value := range v.Peers {
+			converted, err := FromPeer(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Peers[i] = converted
+		}
+	}
+
+	return m, nil
+}
+
+// ToNetworkStatusResponse converts a *NetworkStatusResponse to a *types.NetworkStatusResponse.
+func ToNetworkStatusResponse(m *NetworkStatusResponse) (*types.NetworkStatusResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.NetworkStatusResponse{}
+	if m.CurrentBlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.CurrentBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.CurrentBlockIdentifier = value
+	}
+	v.CurrentBlockTimestamp = m.CurrentBlockTimestamp
+	if m.GenesisBlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.GenesisBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.GenesisBlockIdentifier = value
+	}
+	if m.OldestBlockIdentifier != nil {
+		value, err := ToBlockIdentifier(m.OldestBlockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.OldestBlockIdentifier = value
+	}
+	if m.SyncStatus != nil {
+		value, err := ToSyncStatus(m.SyncStatus)
+		if err != nil {
+			return nil, err
+		}
+		v.SyncStatus = value
+	}
+	v.Peers = make([]*types.Peer, len(m.Peers))
+	for i, value := range m.Peers {
+		converted, err := ToPeer(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Peers[i] = converted
+	}
+
+	return v, nil
+}
+
+// FromOperation converts a *types.Operation to a *Operation.
+func FromOperation(v *types.Operation) (*Operation, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Operation{}
+	if v.OperationIdentifier != nil {
+		value, err := FromOperationIdentifier(v.OperationIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.OperationIdentifier = value
+	}
+	if len(v.RelatedOperations) > 0 {
+		m.RelatedOperations = make([]*OperationIdentifier, len(v.RelatedOperations))
+		for i, value := range v.RelatedOperations {
+			converted, err := FromOperationIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			m.RelatedOperations[i] = converted
+		}
+	}
+	m.Type = v.Type
+	m.Status = v.Status
+	if v.Account != nil {
+		value, err := FromAccountIdentifier(v.Account)
+		if err != nil {
+			return nil, err
+		}
+		m.Account = value
+	}
+	if v.Amount != nil {
+		value, err := FromAmount(v.Amount)
+		if err != nil {
+			return nil, err
+		}
+		m.Amount = value
+	}
+	if v.CoinChange != nil {
+		value, err := FromCoinChange(v.CoinChange)
+		if err != nil {
+			return nil, err
+		}
+		m.CoinChange = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToOperation converts a *Operation to a *types.Operation.
+func ToOperation(m *Operation) (*types.Operation, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Operation{}
+	if m.OperationIdentifier != nil {
+		value, err := ToOperationIdentifier(m.OperationIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.OperationIdentifier = value
+	}
+	if len(m.RelatedOperations) > 0 {
+		v.RelatedOperations = make([]*types.OperationIdentifier, len(m.RelatedOperations))
+		for i, value := range m.RelatedOperations {
+			converted, err := ToOperationIdentifier(value)
+			if err != nil {
+				return nil, err
+			}
+			v.RelatedOperations[i] = converted
+		}
+	}
+	v.Type = m.Type
+	v.Status = m.Status
+	if m.Account != nil {
+		value, err := ToAccountIdentifier(m.Account)
+		if err != nil {
+			return nil, err
+		}
+		v.Account = value
+	}
+	if m.Amount != nil {
+		value, err := ToAmount(m.Amount)
+		if err != nil {
+			return nil, err
+		}
+		v.Amount = value
+	}
+	if m.CoinChange != nil {
+		value, err := ToCoinChange(m.CoinChange)
+		if err != nil {
+			return nil, err
+		}
+		v.CoinChange = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromOperationIdentifier converts a *types.OperationIdentifier to a *OperationIdentifier.
+func FromOperationIdentifier(v *types.OperationIdentifier) (*OperationIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &OperationIdentifier{}
+	m.Index = v.Index
+	m.NetworkIndex = v.NetworkIndex
+
+	return m, nil
+}
+
+// ToOperationIdentifier converts a *OperationIdentifier to a *types.OperationIdentifier.
+func ToOperationIdentifier(m *OperationIdentifier) (*types.OperationIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.OperationIdentifier{}
+	v.Index = m.Index
+	v.NetworkIndex = m.NetworkIndex
+
+	return v, nil
+}
+
+// FromOperationStatus converts a *types.OperationStatus to a *OperationStatus.
+func FromOperationStatus(v *types.OperationStatus) (*OperationStatus, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &OperationStatus{}
+	m.Status = v.Status
+	m.Successful = v.Successful
+
+	return m, nil
+}
+
+// ToOperationStatus converts a *OperationStatus to a *types.OperationStatus.
+func ToOperationStatus(m *OperationStatus) (*types.OperationStatus, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.OperationStatus{}
+	v.Status = m.Status
+	v.Successful = m.Successful
+
+	return v, nil
+}
+
+// FromPartialBlockIdentifier converts a *types.PartialBlockIdentifier to a *PartialBlockIdentifier.
+func FromPartialBlockIdentifier(v *types.PartialBlockIdentifier) (*PartialBlockIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &PartialBlockIdentifier{}
+	m.Index = v.Index
+	m.Hash = v.Hash
+
+	return m, nil
+}
+
+// ToPartialBlockIdentifier converts a *PartialBlockIdentifier to a *types.PartialBlockIdentifier.
+func ToPartialBlockIdentifier(m *PartialBlockIdentifier) (*types.PartialBlockIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.PartialBlockIdentifier{}
+	v.Index = m.Index
+	v.Hash = m.Hash
+
+	return v, nil
+}
+
+// FromPeer converts a *types.Peer to a *Peer.
+func FromPeer(v *types.Peer) (*Peer, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Peer{}
+	m.PeerId = v.PeerID
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToPeer converts a *Peer to a *types.Peer.
+func ToPeer(m *Peer) (*types.Peer, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Peer{}
+	v.PeerID = m.PeerId
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromPublicKey converts a *types.PublicKey to a *PublicKey.
+func FromPublicKey(v *types.PublicKey) (*PublicKey, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &PublicKey{}
+	m.Bytes = v.Bytes
+	m.CurveType = string(v.CurveType)
+
+	return m, nil
+}
+
+// ToPublicKey converts a *PublicKey to a *types.PublicKey.
+func ToPublicKey(m *PublicKey) (*types.PublicKey, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.PublicKey{}
+	v.Bytes = m.Bytes
+	v.CurveType = types.CurveType(m.CurveType)
+
+	return v, nil
+}
+
+// FromRelatedTransaction converts a *types.RelatedTransaction to a *RelatedTransaction.
+func FromRelatedTransaction(v *types.RelatedTransaction) (*RelatedTransaction, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &RelatedTransaction{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.TransactionIdentifier != nil {
+		value, err := FromTransactionIdentifier(v.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.TransactionIdentifier = value
+	}
+	m.Direction = string(v.Direction)
+
+	return m, nil
+}
+
+// ToRelatedTransaction converts a *RelatedTransaction to a *types.RelatedTransaction.
+func ToRelatedTransaction(m *RelatedTransaction) (*types.RelatedTransaction, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.RelatedTransaction{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.TransactionIdentifier != nil {
+		value, err := ToTransactionIdentifier(m.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifier = value
+	}
+	v.Direction = types.Direction(m.Direction)
+
+	return v, nil
+}
+
+// FromSearchTransactionsRequest converts a *types.SearchTransactionsRequest to a *SearchTransactionsRequest.
+func FromSearchTransactionsRequest(v *types.SearchTransactionsRequest) (*SearchTransactionsRequest, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &SearchTransactionsRequest{}
+	if v.NetworkIdentifier != nil {
+		value, err := FromNetworkIdentifier(v.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.NetworkIdentifier = value
+	}
+	if v.Operator != nil {
+		value := string(*v.Operator)
+		m.Operator = &value
+	}
+	m.MaxBlock = v.MaxBlock
+	m.Offset = v.Offset
+	m.Limit = v.Limit
+	if v.TransactionIdentifier != nil {
+		value, err := FromTransactionIdentifier(v.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.TransactionIdentifier = value
+	}
+	if v.AccountIdentifier != nil {
+		value, err := FromAccountIdentifier(v.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.AccountIdentifier = value
+	}
+	if v.CoinIdentifier != nil {
+		value, err := FromCoinIdentifier(v.CoinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.CoinIdentifier = value
+	}
+	if v.Currency != nil {
+		value, err := FromCurrency(v.Currency)
+		if err != nil {
+			return nil, err
+		}
+		m.Currency = value
+	}
+	m.Status = v.Status
+	m.Type = v.Type
+	m.Address = v.Address
+	m.Success = v.Success
+
+	return m, nil
+}
+
+// ToSearchTransactionsRequest converts a *SearchTransactionsRequest to a *types.SearchTransactionsRequest.
+func ToSearchTransactionsRequest(m *SearchTransactionsRequest) (*types.SearchTransactionsRequest, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.SearchTransactionsRequest{}
+	if m.NetworkIdentifier != nil {
+		value, err := ToNetworkIdentifier(m.NetworkIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.NetworkIdentifier = value
+	}
+	if m.Operator != nil {
+		value := types.Operator(*m.Operator)
+		v.Operator = &value
+	}
+	v.MaxBlock = m.MaxBlock
+	v.Offset = m.Offset
+	v.Limit = m.Limit
+	if m.TransactionIdentifier != nil {
+		value, err := ToTransactionIdentifier(m.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifier = value
+	}
+	if m.AccountIdentifier != nil {
+		value, err := ToAccountIdentifier(m.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.AccountIdentifier = value
+	}
+	if m.CoinIdentifier != nil {
+		value, err := ToCoinIdentifier(m.CoinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.CoinIdentifier = value
+	}
+	if m.Currency != nil {
+		value, err := ToCurrency(m.Currency)
+		if err != nil {
+			return nil, err
+		}
+		v.Currency = value
+	}
+	v.Status = m.Status
+	v.Type = m.Type
+	v.Address = m.Address
+	v.Success = m.Success
+
+	return v, nil
+}
+
+// FromSearchTransactionsResponse converts a *types.SearchTransactionsResponse to a *SearchTransactionsResponse.
+func FromSearchTransactionsResponse(v *types.SearchTransactionsResponse) (*SearchTransactionsResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &SearchTransactionsResponse{}
+	if len(v.Transactions) > 0 {
+		m.Transactions = make([]*BlockTransaction, len(v.Transactions))
+		for i, value := range v.Transactions {
+			converted, err := FromBlockTransaction(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Transactions[i] = converted
+		}
+	}
+	m.TotalCount = v.TotalCount
+	m.NextOffset = v.NextOffset
+
+	return m, nil
+}
+
+// ToSearchTransactionsResponse converts a *SearchTransactionsResponse to a *types.SearchTransactionsResponse.
+func ToSearchTransactionsResponse(m *SearchTransactionsResponse) (*types.SearchTransactionsResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.SearchTransactionsResponse{}
+	v.Transactions = make([]*types.BlockTransaction, len(m.Transactions))
+	for i, value := range m.Transactions {
+		converted, err := ToBlockTransaction(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Transactions[i] = converted
+	}
+	v.TotalCount = m.TotalCount
+	v.NextOffset = m.NextOffset
+
+	return v, nil
+}
+
+// FromSignature converts a *types.Signature to a *Signature.
+func FromSignature(v *types.Signature) (*Signature, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Signature{}
+	if v.SigningPayload != nil {
+		value, err := FromSigningPayload(v.SigningPayload)
+		if err != nil {
+			return nil, err
+		}
+		m.SigningPayload = value
+	}
+	if v.PublicKey != nil {
+		value, err := FromPublicKey(v.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		m.PublicKey = value
+	}
+	m.SignatureType = string(v.SignatureType)
+	m.Bytes = v.Bytes
+
+	return m, nil
+}
+
+// ToSignature converts a *Signature to a *types.Signature.
+func ToSignature(m *Signature) (*types.Signature, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Signature{}
+	if m.SigningPayload != nil {
+		value, err := ToSigningPayload(m.SigningPayload)
+		if err != nil {
+			return nil, err
+		}
+		v.SigningPayload = value
+	}
+	if m.PublicKey != nil {
+		value, err := ToPublicKey(m.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		v.PublicKey = value
+	}
+	v.SignatureType = types.SignatureType(m.SignatureType)
+	v.Bytes = m.Bytes
+
+	return v, nil
+}
+
+// FromSigningPayload converts a *types.SigningPayload to a *SigningPayload.
+func FromSigningPayload(v *types.SigningPayload) (*SigningPayload, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &SigningPayload{}
+	if v.AccountIdentifier != nil {
+		value, err := FromAccountIdentifier(v.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.AccountIdentifier = value
+	}
+	m.Bytes = v.Bytes
+	m.SignatureType = string(v.SignatureType)
+
+	return m, nil
+}
+
+// ToSigningPayload converts a *SigningPayload to a *types.SigningPayload.
+func ToSigningPayload(m *SigningPayload) (*types.SigningPayload, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.SigningPayload{}
+	if m.AccountIdentifier != nil {
+		value, err := ToAccountIdentifier(m.AccountIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.AccountIdentifier = value
+	}
+	v.Bytes = m.Bytes
+	v.SignatureType = types.SignatureType(m.SignatureType)
+
+	return v, nil
+}
+
+// FromSubAccountIdentifier converts a *types.SubAccountIdentifier to a *SubAccountIdentifier.
+func FromSubAccountIdentifier(v *types.SubAccountIdentifier) (*SubAccountIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &SubAccountIdentifier{}
+	m.Address = v.Address
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToSubAccountIdentifier converts a *SubAccountIdentifier to a *types.SubAccountIdentifier.
+func ToSubAccountIdentifier(m *SubAccountIdentifier) (*types.SubAccountIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.SubAccountIdentifier{}
+	v.Address = m.Address
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromSubNetworkIdentifier converts a *types.SubNetworkIdentifier to a *SubNetworkIdentifier.
+func FromSubNetworkIdentifier(v *types.SubNetworkIdentifier) (*SubNetworkIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &SubNetworkIdentifier{}
+	m.Network = v.Network
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToSubNetworkIdentifier converts a *SubNetworkIdentifier to a *types.SubNetworkIdentifier.
+func ToSubNetworkIdentifier(m *SubNetworkIdentifier) (*types.SubNetworkIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.SubNetworkIdentifier{}
+	v.Network = m.Network
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromSyncStatus converts a *types.SyncStatus to a *SyncStatus.
+func FromSyncStatus(v *types.SyncStatus) (*SyncStatus, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &SyncStatus{}
+	m.CurrentIndex = v.CurrentIndex
+	m.TargetIndex = v.TargetIndex
+	m.Stage = v.Stage
+	m.Synced = v.Synced
+
+	return m, nil
+}
+
+// ToSyncStatus converts a *SyncStatus to a *types.SyncStatus.
+func ToSyncStatus(m *SyncStatus) (*types.SyncStatus, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.SyncStatus{}
+	v.CurrentIndex = m.CurrentIndex
+	v.TargetIndex = m.TargetIndex
+	v.Stage = m.Stage
+	v.Synced = m.Synced
+
+	return v, nil
+}
+
+// FromTransaction converts a *types.Transaction to a *Transaction.
+func FromTransaction(v *types.Transaction) (*Transaction, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Transaction{}
+	if v.TransactionIdentifier != nil {
+		value, err := FromTransactionIdentifier(v.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.TransactionIdentifier = value
+	}
+	if len(v.Operations) > 0 {
+		m.Operations = make([]*Operation, len(v.Operations))
+		for i, value := range v.Operations {
+			converted, err := FromOperation(value)
+			if err != nil {
+				return nil, err
+			}
+			m.Operations[i] = converted
+		}
+	}
+	if len(v.RelatedTransactions) > 0 {
+		m.RelatedTransactions = make([]*RelatedTransaction, len(v.RelatedTransactions))
+		for i, value := range v.RelatedTransactions {
+			converted, err := FromRelatedTransaction(value)
+			if err != nil {
+				return nil, err
+			}
+			m.RelatedTransactions[i] = converted
+		}
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToTransaction converts a *Transaction to a *types.Transaction.
+func ToTransaction(m *Transaction) (*types.Transaction, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Transaction{}
+	if m.TransactionIdentifier != nil {
+		value, err := ToTransactionIdentifier(m.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifier = value
+	}
+	v.Operations = make([]*types.Operation, len(m.Operations))
+	for i, value := range m.Operations {
+		converted, err := ToOperation(value)
+		if err != nil {
+			return nil, err
+		}
+		v.Operations[i] = converted
+	}
+	if len(m.RelatedTransactions) > 0 {
+		v.RelatedTransactions = make([]*types.RelatedTransaction, len(m.RelatedTransactions))
+		for i, value := range m.RelatedTransactions {
+			converted, err := ToRelatedTransaction(value)
+			if err != nil {
+				return nil, err
+			}
+			v.RelatedTransactions[i] = converted
+		}
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromTransactionIdentifier converts a *types.TransactionIdentifier to a *TransactionIdentifier.
+func FromTransactionIdentifier(v *types.TransactionIdentifier) (*TransactionIdentifier, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &TransactionIdentifier{}
+	m.Hash = v.Hash
+
+	return m, nil
+}
+
+// ToTransactionIdentifier converts a *TransactionIdentifier to a *types.TransactionIdentifier.
+func ToTransactionIdentifier(m *TransactionIdentifier) (*types.TransactionIdentifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.TransactionIdentifier{}
+	v.Hash = m.Hash
+
+	return v, nil
+}
+
+// FromTransactionIdentifierResponse converts a *types.TransactionIdentifierResponse to a *TransactionIdentifierResponse.
+func FromTransactionIdentifierResponse(v *types.TransactionIdentifierResponse) (*TransactionIdentifierResponse, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &TransactionIdentifierResponse{}
+	if v.TransactionIdentifier != nil {
+		value, err := FromTransactionIdentifier(v.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		m.TransactionIdentifier = value
+	}
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToTransactionIdentifierResponse converts a *TransactionIdentifierResponse to a *types.TransactionIdentifierResponse.
+func ToTransactionIdentifierResponse(m *TransactionIdentifierResponse) (*types.TransactionIdentifierResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.TransactionIdentifierResponse{}
+	if m.TransactionIdentifier != nil {
+		value, err := ToTransactionIdentifier(m.TransactionIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		v.TransactionIdentifier = value
+	}
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromVersion converts a *types.Version to a *Version.
+func FromVersion(v *types.Version) (*Version, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &Version{}
+	m.RosettaVersion = v.RosettaVersion
+	m.NodeVersion = v.NodeVersion
+	m.MiddlewareVersion = v.MiddlewareVersion
+	metadata, err := marshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Metadata = metadata
+
+	return m, nil
+}
+
+// ToVersion converts a *Version to a *types.Version.
+func ToVersion(m *Version) (*types.Version, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.Version{}
+	v.RosettaVersion = m.RosettaVersion
+	v.NodeVersion = m.NodeVersion
+	v.MiddlewareVersion = m.MiddlewareVersion
+	metadata, err := unmarshalMetadata(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	v.Metadata = metadata
+
+	return v, nil
+}
+
+// FromType converts v (a pointer to any struct in the
+// types package) to the message with the same name.
+func FromType(v interface{}) (proto.Message, error) {
+	switch v := v.(type) {
+	case *types.AccountBalanceRequest:
+		return FromAccountBalanceRequest(v)
+	case *types.AccountBalanceResponse:
+		return FromAccountBalanceResponse(v)
+	case *types.AccountCoin:
+		return FromAccountCoin(v)
+	case *types.AccountCoinsRequest:
+		return FromAccountCoinsRequest(v)
+	case *types.AccountCoinsResponse:
+		return FromAccountCoinsResponse(v)
+	case *types.AccountCurrency:
+		return FromAccountCurrency(v)
+	case *types.AccountIdentifier:
+		return FromAccountIdentifier(v)
+	case *types.Allow:
+		return FromAllow(v)
+	case *types.Amount:
+		return FromAmount(v)
+	case *types.BalanceExemption:
+		return FromBalanceExemption(v)
+	case *types.Block:
+		return FromBlock(v)
+	case *types.BlockEvent:
+		return FromBlockEvent(v)
+	case *types.BlockIdentifier:
+		return FromBlockIdentifier(v)
+	case *types.BlockRequest:
+		return FromBlockRequest(v)
+	case *types.BlockResponse:
+		return FromBlockResponse(v)
+	case *types.BlockTransaction:
+		return FromBlockTransaction(v)
+	case *types.BlockTransactionRequest:
+		return FromBlockTransactionRequest(v)
+	case *types.BlockTransactionResponse:
+		return FromBlockTransactionResponse(v)
+	case *types.CallRequest:
+		return FromCallRequest(v)
+	case *types.CallResponse:
+		return FromCallResponse(v)
+	case *types.Coin:
+		return FromCoin(v)
+	case *types.CoinChange:
+		return FromCoinChange(v)
+	case *types.CoinIdentifier:
+		return FromCoinIdentifier(v)
+	case *types.ConstructionCombineRequest:
+		return FromConstructionCombineRequest(v)
+	case *types.ConstructionCombineResponse:
+		return FromConstructionCombineResponse(v)
+	case *types.ConstructionDeriveRequest:
+		return FromConstructionDeriveRequest(v)
+	case *types.ConstructionDeriveResponse:
+		return FromConstructionDeriveResponse(v)
+	case *types.ConstructionHashRequest:
+		return FromConstructionHashRequest(v)
+	case *types.ConstructionMetadataRequest:
+		return FromConstructionMetadataRequest(v)
+	case *types.ConstructionMetadataResponse:
+		return FromConstructionMetadataResponse(v)
+	case *types.ConstructionParseRequest:
+		return FromConstructionParseRequest(v)
+	case *types.ConstructionParseResponse:
+		return FromConstructionParseResponse(v)
+	case *types.ConstructionPayloadsRequest:
+		return FromConstructionPayloadsRequest(v)
+	case *types.ConstructionPayloadsResponse:
+		return FromConstructionPayloadsResponse(v)
+	case *types.ConstructionPreprocessRequest:
+		return FromConstructionPreprocessRequest(v)
+	case *types.ConstructionPreprocessResponse:
+		return FromConstructionPreprocessResponse(v)
+	case *types.ConstructionSubmitRequest:
+		return FromConstructionSubmitRequest(v)
+	case *types.Currency:
+		return FromCurrency(v)
+	case *types.Error:
+		return FromError(v)
+	case *types.EventsBlocksRequest:
+		return FromEventsBlocksRequest(v)
+	case *types.EventsBlocksResponse:
+		return FromEventsBlocksResponse(v)
+	case *types.MempoolResponse:
+		return FromMempoolResponse(v)
+	case *types.MempoolTransactionRequest:
+		return FromMempoolTransactionRequest(v)
+	case *types.MempoolTransactionResponse:
+		return FromMempoolTransactionResponse(v)
+	case *types.MetadataRequest:
+		return FromMetadataRequest(v)
+	case *types.NetworkIdentifier:
+		return FromNetworkIdentifier(v)
+	case *types.NetworkListResponse:
+		return FromNetworkListResponse(v)
+	case *types.NetworkOptionsResponse:
+		return FromNetworkOptionsResponse(v)
+	case *types.NetworkRequest:
+		return FromNetworkRequest(v)
+	case *types.NetworkStatusResponse:
+		return FromNetworkStatusResponse(v)
+	case *types.Operation:
+		return FromOperation(v)
+	case *types.OperationIdentifier:
+		return FromOperationIdentifier(v)
+	case *types.OperationStatus:
+		return FromOperationStatus(v)
+	case *types.PartialBlockIdentifier:
+		return FromPartialBlockIdentifier(v)
+	case *types.Peer:
+		return FromPeer(v)
+	case *types.PublicKey:
+		return FromPublicKey(v)
+	case *types.RelatedTransaction:
+		return FromRelatedTransaction(v)
+	case *types.SearchTransactionsRequest:
+		return FromSearchTransactionsRequest(v)
+	case *types.SearchTransactionsResponse:
+		return FromSearchTransactionsResponse(v)
+	case *types.Signature:
+		return FromSignature(v)
+	case *types.SigningPayload:
+		return FromSigningPayload(v)
+	case *types.SubAccountIdentifier:
+		return FromSubAccountIdentifier(v)
+	case *types.SubNetworkIdentifier:
+		return FromSubNetworkIdentifier(v)
+	case *types.SyncStatus:
+		return FromSyncStatus(v)
+	case *types.Transaction:
+		return FromTransaction(v)
+	case *types.TransactionIdentifier:
+		return FromTransactionIdentifier(v)
+	case *types.TransactionIdentifierResponse:
+		return FromTransactionIdentifierResponse(v)
+	case *types.Version:
+		return FromVersion(v)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrTypeUnsupported, v)
+	}
+}
+
+// newMessage returns an empty message for v (a
+// pointer to any struct in the types package).
+func newMessage(v interface{}) (proto.Message, error) {
+	switch v.(type) {
+	case *types.AccountBalanceRequest:
+		return &AccountBalanceRequest{}, nil
+	case *types.AccountBalanceResponse:
+		return &AccountBalanceResponse{}, nil
+	case *types.AccountCoin:
+		return &AccountCoin{}, nil
+	case *types.AccountCoinsRequest:
+		return &AccountCoinsRequest{}, nil
+	case *types.AccountCoinsResponse:
+		return &AccountCoinsResponse{}, nil
+	case *types.AccountCurrency:
+		return &AccountCurrency{}, nil
+	case *types.AccountIdentifier:
+		return &AccountIdentifier{}, nil
+	case *types.Allow:
+		return &Allow{}, nil
+	case *types.Amount:
+		return &Amount{}, nil
+	case *types.BalanceExemption:
+		return &BalanceExemption{}, nil
+	case *types.Block:
+		return &Block{}, nil
+	case *types.BlockEvent:
+		return &BlockEvent{}, nil
+	case *types.BlockIdentifier:
+		return &BlockIdentifier{}, nil
+	case *types.BlockRequest:
+		return &BlockRequest{}, nil
+	case *types.BlockResponse:
+		return &BlockResponse{}, nil
+	case *types.BlockTransaction:
+		return &BlockTransaction{}, nil
+	case *types.BlockTransactionRequest:
+		return &BlockTransactionRequest{}, nil
+	case *types.BlockTransactionResponse:
+		return &BlockTransactionResponse{}, nil
+	case *types.CallRequest:
+		return &CallRequest{}, nil
+	case *types.CallResponse:
+		return &CallResponse{}, nil
+	case *types.Coin:
+		return &Coin{}, nil
+	case *types.CoinChange:
+		return &CoinChange{}, nil
+	case *types.CoinIdentifier:
+		return &CoinIdentifier{}, nil
+	case *types.ConstructionCombineRequest:
+		return &ConstructionCombineRequest{}, nil
+	case *types.ConstructionCombineResponse:
+		return &ConstructionCombineResponse{}, nil
+	case *types.ConstructionDeriveRequest:
+		return &ConstructionDeriveRequest{}, nil
+	case *types.ConstructionDeriveResponse:
+		return &ConstructionDeriveResponse{}, nil
+	case *types.ConstructionHashRequest:
+		return &ConstructionHashRequest{}, nil
+	case *types.ConstructionMetadataRequest:
+		return &ConstructionMetadataRequest{}, nil
+	case *types.ConstructionMetadataResponse:
+		return &ConstructionMetadataResponse{}, nil
+	case *types.ConstructionParseRequest:
+		return &ConstructionParseRequest{}, nil
+	case *types.ConstructionParseResponse:
+		return &ConstructionParseResponse{}, nil
+	case *types.ConstructionPayloadsRequest:
+		return &ConstructionPayloadsRequest{}, nil
+	case *types.ConstructionPayloadsResponse:
+		return &ConstructionPayloadsResponse{}, nil
+	case *types.ConstructionPreprocessRequest:
+		return &ConstructionPreprocessRequest{}, nil
+	case *types.ConstructionPreprocessResponse:
+		return &ConstructionPreprocessResponse{}, nil
+	case *types.ConstructionSubmitRequest:
+		return &ConstructionSubmitRequest{}, nil
+	case *types.Currency:
+		return &Currency{}, nil
+	case *types.Error:
+		return &Error{}, nil
+	case *types.EventsBlocksRequest:
+		return &EventsBlocksRequest{}, nil
+	case *types.EventsBlocksResponse:
+		return &EventsBlocksResponse{}, nil
+	case *types.MempoolResponse:
+		return &MempoolResponse{}, nil
+	case *types.MempoolTransactionRequest:
+		return &MempoolTransactionRequest{}, nil
+	case *types.MempoolTransactionResponse:
+		return &MempoolTransactionResponse{}, nil
+	case *types.MetadataRequest:
+		return &MetadataRequest{}, nil
+	case *types.NetworkIdentifier:
+		return &NetworkIdentifier{}, nil
+	case *types.NetworkListResponse:
+		return &NetworkListResponse{}, nil
+	case *types.NetworkOptionsResponse:
+		return &NetworkOptionsResponse{}, nil
+	case *types.NetworkRequest:
+		return &NetworkRequest{}, nil
+	case *types.NetworkStatusResponse:
+		return &NetworkStatusResponse{}, nil
+	case *types.Operation:
+		return &Operation{}, nil
+	case *types.OperationIdentifier:
+		return &OperationIdentifier{}, nil
+	case *types.OperationStatus:
+		return &OperationStatus{}, nil
+	case *types.PartialBlockIdentifier:
+		return &PartialBlockIdentifier{}, nil
+	case *types.Peer:
+		return &Peer{}, nil
+	case *types.PublicKey:
+		return &PublicKey{}, nil
+	case *types.RelatedTransaction:
+		return &RelatedTransaction{}, nil
+	case *types.SearchTransactionsRequest:
+		return &SearchTransactionsRequest{}, nil
+	case *types.SearchTransactionsResponse:
+		return &SearchTransactionsResponse{}, nil
+	case *types.Signature:
+		return &Signature{}, nil
+	case *types.SigningPayload:
+		return &SigningPayload{}, nil
+	case *types.SubAccountIdentifier:
+		return &SubAccountIdentifier{}, nil
+	case *types.SubNetworkIdentifier:
+		return &SubNetworkIdentifier{}, nil
+	case *types.SyncStatus:
+		return &SyncStatus{}, nil
+	case *types.Transaction:
+		return &Transaction{}, nil
+	case *types.TransactionIdentifier:
+		return &TransactionIdentifier{}, nil
+	case *types.TransactionIdentifierResponse:
+		return &TransactionIdentifierResponse{}, nil
+	case *types.Version:
+		return &Version{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrTypeUnsupported, v)
+	}
+}
+
+// toType converts m (created by newMessage(v)) into v.
+func toType(m proto.Message, v interface{}) error {
+	switch v := v.(type) {
+	case *types.AccountBalanceRequest:
+		converted, err := ToAccountBalanceRequest(m.(*AccountBalanceRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.AccountBalanceResponse:
+		converted, err := ToAccountBalanceResponse(m.(*AccountBalanceResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.AccountCoin:
+		converted, err := ToAccountCoin(m.(*AccountCoin)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.AccountCoinsRequest:
+		converted, err := ToAccountCoinsRequest(m.(*AccountCoinsRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.AccountCoinsResponse:
+		converted, err := ToAccountCoinsResponse(m.(*AccountCoinsResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.AccountCurrency:
+		converted, err := ToAccountCurrency(m.(*AccountCurrency)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.AccountIdentifier:
+		converted, err := ToAccountIdentifier(m.(*AccountIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Allow:
+		converted, err := ToAllow(m.(*Allow)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Amount:
+		converted, err := ToAmount(m.(*Amount)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BalanceExemption:
+		converted, err := ToBalanceExemption(m.(*BalanceExemption)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Block:
+		converted, err := ToBlock(m.(*Block)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockEvent:
+		converted, err := ToBlockEvent(m.(*BlockEvent)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockIdentifier:
+		converted, err := ToBlockIdentifier(m.(*BlockIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockRequest:
+		converted, err := ToBlockRequest(m.(*BlockRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockResponse:
+		converted, err := ToBlockResponse(m.(*BlockResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockTransaction:
+		converted, err := ToBlockTransaction(m.(*BlockTransaction)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockTransactionRequest:
+		converted, err := ToBlockTransactionRequest(m.(*BlockTransactionRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.BlockTransactionResponse:
+		converted, err := ToBlockTransactionResponse(m.(*BlockTransactionResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.CallRequest:
+		converted, err := ToCallRequest(m.(*CallRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.CallResponse:
+		converted, err := ToCallResponse(m.(*CallResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Coin:
+		converted, err := ToCoin(m.(*Coin)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.CoinChange:
+		converted, err := ToCoinChange(m.(*CoinChange)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.CoinIdentifier:
+		converted, err := ToCoinIdentifier(m.(*CoinIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionCombineRequest:
+		converted, err := ToConstructionCombineRequest(m.(*ConstructionCombineRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionCombineResponse:
+		converted, err := ToConstructionCombineResponse(m.(*ConstructionCombineResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionDeriveRequest:
+		converted, err := ToConstructionDeriveRequest(m.(*ConstructionDeriveRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionDeriveResponse:
+		converted, err := ToConstructionDeriveResponse(m.(*ConstructionDeriveResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionHashRequest:
+		converted, err := ToConstructionHashRequest(m.(*ConstructionHashRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionMetadataRequest:
+		converted, err := ToConstructionMetadataRequest(m.(*ConstructionMetadataRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionMetadataResponse:
+		converted, err := ToConstructionMetadataResponse(m.(*ConstructionMetadataResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionParseRequest:
+		converted, err := ToConstructionParseRequest(m.(*ConstructionParseRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionParseResponse:
+		converted, err := ToConstructionParseResponse(m.(*ConstructionParseResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionPayloadsRequest:
+		converted, err := ToConstructionPayloadsRequest(m.(*ConstructionPayloadsRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionPayloadsResponse:
+		converted, err := ToConstructionPayloadsResponse(m.(*ConstructionPayloadsResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionPreprocessRequest:
+		converted, err := ToConstructionPreprocessRequest(m.(*ConstructionPreprocessRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionPreprocessResponse:
+		converted, err := ToConstructionPreprocessResponse(m.(*ConstructionPreprocessResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.ConstructionSubmitRequest:
+		converted, err := ToConstructionSubmitRequest(m.(*ConstructionSubmitRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Currency:
+		converted, err := ToCurrency(m.(*Currency)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Error:
+		converted, err := ToError(m.(*Error)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.EventsBlocksRequest:
+		converted, err := ToEventsBlocksRequest(m.(*EventsBlocksRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.EventsBlocksResponse:
+		converted, err := ToEventsBlocksResponse(m.(*EventsBlocksResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.MempoolResponse:
+		converted, err := ToMempoolResponse(m.(*MempoolResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.MempoolTransactionRequest:
+		converted, err := ToMempoolTransactionRequest(m.(*MempoolTransactionRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.MempoolTransactionResponse:
+		converted, err := ToMempoolTransactionResponse(m.(*MempoolTransactionResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.MetadataRequest:
+		converted, err := ToMetadataRequest(m.(*MetadataRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.NetworkIdentifier:
+		converted, err := ToNetworkIdentifier(m.(*NetworkIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.NetworkListResponse:
+		converted, err := ToNetworkListResponse(m.(*NetworkListResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.NetworkOptionsResponse:
+		converted, err := ToNetworkOptionsResponse(m.(*NetworkOptionsResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.NetworkRequest:
+		converted, err := ToNetworkRequest(m.(*NetworkRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.NetworkStatusResponse:
+		converted, err := ToNetworkStatusResponse(m.(*NetworkStatusResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Operation:
+		converted, err := ToOperation(m.(*Operation)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.OperationIdentifier:
+		converted, err := ToOperationIdentifier(m.(*OperationIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.OperationStatus:
+		converted, err := ToOperationStatus(m.(*OperationStatus)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.PartialBlockIdentifier:
+		converted, err := ToPartialBlockIdentifier(m.(*PartialBlockIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Peer:
+		converted, err := ToPeer(m.(*Peer)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.PublicKey:
+		converted, err := ToPublicKey(m.(*PublicKey)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.RelatedTransaction:
+		converted, err := ToRelatedTransaction(m.(*RelatedTransaction)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.SearchTransactionsRequest:
+		converted, err := ToSearchTransactionsRequest(m.(*SearchTransactionsRequest)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.SearchTransactionsResponse:
+		converted, err := ToSearchTransactionsResponse(m.(*SearchTransactionsResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Signature:
+		converted, err := ToSignature(m.(*Signature)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.SigningPayload:
+		converted, err := ToSigningPayload(m.(*SigningPayload)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.SubAccountIdentifier:
+		converted, err := ToSubAccountIdentifier(m.(*SubAccountIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.SubNetworkIdentifier:
+		converted, err := ToSubNetworkIdentifier(m.(*SubNetworkIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.SyncStatus:
+		converted, err := ToSyncStatus(m.(*SyncStatus)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Transaction:
+		converted, err := ToTransaction(m.(*Transaction)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.TransactionIdentifier:
+		converted, err := ToTransactionIdentifier(m.(*TransactionIdentifier)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.TransactionIdentifierResponse:
+		converted, err := ToTransactionIdentifierResponse(m.(*TransactionIdentifierResponse)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	case *types.Version:
+		converted, err := ToVersion(m.(*Version)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+	default:
+		return fmt.Errorf("%w: %T", ErrTypeUnsupported, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestBlockRoundTrip(t *testing.T) {
+	block := &types.Block{
+		BlockIdentifier:       &types.BlockIdentifier{Index: 2, Hash: "block 2"},
+		ParentBlockIdentifier: &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+		Timestamp:             1600000000000,
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx"},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index:        0,
+							NetworkIndex: types.Int64(1),
+						},
+						Type:   "TRANSFER",
+						Status: types.String("SUCCESS"),
+						Account: &types.AccountIdentifier{
+							Address: "addr",
+							SubAccount: &types.SubAccountIdentifier{
+								Address:  "staking",
+								Metadata: map[string]interface{}{"validator": "val"},
+							},
+						},
+						Amount: &types.Amount{
+							Value: "-100",
+							Currency: &types.Currency{
+								Symbol:   "BTC",
+								Decimals: 8,
+							},
+						},
+						CoinChange: &types.CoinChange{
+							CoinIdentifier: &types.CoinIdentifier{Identifier: "tx:0"},
+							CoinAction:     types.CoinSpent,
+						},
+					},
+				},
+				RelatedTransactions: []*types.RelatedTransaction{
+					{
+						TransactionIdentifier: &types.TransactionIdentifier{Hash: "other"},
+						Direction:             types.Forward,
+					},
+				},
+				Metadata: map[string]interface{}{
+					"size":  float64(250),
+					"flags": []interface{}{"a", "b"},
+				},
+			},
+		},
+	}
+
+	m, err := FromBlock(block)
+	assert.NoError(t, err)
+
+	// Ensure the message survives the wire.
+	b, err := proto.Marshal(m)
+	assert.NoError(t, err)
+	var decoded Block
+	assert.NoError(t, proto.Unmarshal(b, &decoded))
+
+	converted, err := ToBlock(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, block, converted)
+}
+
+func TestRequiredArrays(t *testing.T) {
+	// Required arrays are never null in JSON
+	// (even when empty on the wire).
+	converted, err := ToNetworkStatusResponse(&NetworkStatusResponse{
+		CurrentBlockIdentifier: &BlockIdentifier{Index: 1, Hash: "block 1"},
+		GenesisBlockIdentifier: &BlockIdentifier{Index: 0, Hash: "block 0"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.Peer{}, converted.Peers)
+	assert.JSONEq(
+		t,
+		`{
+			"current_block_identifier": {"index": 1, "hash": "block 1"},
+			"current_block_timestamp": 0,
+			"genesis_block_identifier": {"index": 0, "hash": "block 0"},
+			"peers": []
+		}`,
+		types.PrintStruct(converted),
+	)
+
+	// Optional arrays are omitted.
+	request, err := ToAccountBalanceRequest(&AccountBalanceRequest{})
+	assert.NoError(t, err)
+	assert.Nil(t, request.Currencies)
+}
+
+func TestNil(t *testing.T) {
+	m, err := FromBlock(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+
+	v, err := ToBlock(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestMetadataInvalid(t *testing.T) {
+	_, err := FromCurrency(&types.Currency{
+		Symbol:   "BTC",
+		Metadata: map[string]interface{}{"invalid": make(chan int)},
+	})
+	assert.True(t, errors.Is(err, ErrMetadataInvalid))
+
+	_, err = ToCurrency(&Currency{Symbol: "BTC", Metadata: []byte("{")})
+	assert.True(t, errors.Is(err, ErrMetadataInvalid))
+}
@@ -0,0 +1,638 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gen generates pb/types.proto (a message for each struct in the
+// types package) and pb/convert.go (functions to convert each struct
+// to and from its message). It is invoked by protogen.sh.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const license = `// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+`
+
+// kind is the kind of a field of a struct
+// in the types package.
+type kind int
+
+const (
+	scalarKind kind = iota
+	optionalKind
+	enumKind
+	optionalEnumKind
+	bytesKind
+	stringsKind
+	messageKind
+	messagesKind
+	metadataKind
+)
+
+// field is a field of a struct in
+// the types package.
+type field struct {
+	goName    string
+	protoName string
+	pbName    string
+	kind      kind
+
+	// protoType is the scalar or message type
+	// of the field in types.proto.
+	protoType string
+
+	// goType is the type of the field
+	// in the types package.
+	goType string
+
+	// required is true if the field is
+	// not omitted from JSON when empty.
+	required bool
+}
+
+// message is a struct in the types package.
+type message struct {
+	name    string
+	comment string
+	fields  []*field
+}
+
+var scalars = map[string]string{
+	"string":  "string",
+	"int64":   "int64",
+	"int32":   "int32",
+	"bool":    "bool",
+	"float64": "double",
+}
+
+func main() {
+	typesDir := flag.String("types", "types", "directory of the types package")
+	protoPath := flag.String("proto", "pb/types.proto", "path of the generated proto file")
+	convertPath := flag.String("convert", "pb/convert.go", "path of the generated converters")
+	flag.Parse()
+
+	messages, err := parseTypes(*typesDir)
+	if err != nil {
+		log.Fatalf("unable to parse types: %s", err.Error())
+	}
+
+	if err := ioutil.WriteFile(*protoPath, generateProto(messages), 0600); err != nil {
+		log.Fatalf("unable to write %s: %s", *protoPath, err.Error())
+	}
+
+	converters, err := format.Source(generateConverters(messages))
+	if err != nil {
+		log.Fatalf("unable to format converters: %s", err.Error())
+	}
+
+	if err := ioutil.WriteFile(*convertPath, converters, 0600); err != nil {
+		log.Fatalf("unable to write %s: %s", *convertPath, err.Error())
+	}
+}
+
+// parseTypes returns a *message for each
+// exported struct in the types package
+// (sorted by name).
+func parseTypes(dir string) ([]*message, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, ok := pkgs["types"]
+	if !ok {
+		return nil, fmt.Errorf("types package not found in %s", dir)
+	}
+
+	structs := map[string]*ast.StructType{}
+	comments := map[string]string{}
+	enums := map[string]bool{}
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if !typeSpec.Name.IsExported() {
+					continue
+				}
+
+				switch t := typeSpec.Type.(type) {
+				case *ast.StructType:
+					if !rosettaType(t) {
+						continue
+					}
+
+					structs[typeSpec.Name.Name] = t
+					comments[typeSpec.Name.Name] = gen.Doc.Text()
+				case *ast.Ident:
+					if t.Name == "string" {
+						enums[typeSpec.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	messages := []*message{}
+	for name, s := range structs {
+		m := &message{name: name, comment: comments[name]}
+		for _, f := range s.Fields.List {
+			if len(f.Names) == 0 {
+				return nil, fmt.Errorf("%s has an embedded field", name)
+			}
+
+			parsed, err := parseField(f, structs, enums)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, f.Names[0].Name, err)
+			}
+
+			m.fields = append(m.fields, parsed)
+		}
+
+		messages = append(messages, m)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].name < messages[j].name
+	})
+
+	return messages, nil
+}
+
+// rosettaType returns true if s is a Rosetta type
+// (all of its fields are encoded in JSON). Other
+// structs in the types package (ex: builders) do
+// not have messages.
+func rosettaType(s *ast.StructType) bool {
+	for _, f := range s.Fields.List {
+		if f.Tag == nil || !strings.Contains(f.Tag.Value, `json:"`) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseField returns the *field for
+// a field of a struct.
+func parseField(
+	f *ast.Field,
+	structs map[string]*ast.StructType,
+	enums map[string]bool,
+) (*field, error) {
+	goName := f.Names[0].Name
+	protoName := snakeCase(goName)
+	parsed := &field{
+		goName:    goName,
+		protoName: protoName,
+		pbName:    goCamelCase(protoName),
+	}
+
+	if f.Tag != nil {
+		tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("json")
+		parsed.required = !strings.Contains(tag, "omitempty")
+	}
+
+	switch t := f.Type.(type) {
+	case *ast.Ident:
+		if scalar, ok := scalars[t.Name]; ok {
+			parsed.kind = scalarKind
+			parsed.protoType = scalar
+			parsed.goType = t.Name
+			return parsed, nil
+		}
+
+		if enums[t.Name] {
+			parsed.kind = enumKind
+			parsed.protoType = "string"
+			parsed.goType = t.Name
+			return parsed, nil
+		}
+	case *ast.StarExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+
+		if scalar, ok := scalars[ident.Name]; ok {
+			parsed.kind = optionalKind
+			parsed.protoType = scalar
+			parsed.goType = ident.Name
+			return parsed, nil
+		}
+
+		if enums[ident.Name] {
+			parsed.kind = optionalEnumKind
+			parsed.protoType = "string"
+			parsed.goType = ident.Name
+			return parsed, nil
+		}
+
+		if _, ok := structs[ident.Name]; ok {
+			parsed.kind = messageKind
+			parsed.protoType = ident.Name
+			parsed.goType = ident.Name
+			return parsed, nil
+		}
+	case *ast.ArrayType:
+		switch elt := t.Elt.(type) {
+		case *ast.Ident:
+			if elt.Name == "byte" {
+				parsed.kind = bytesKind
+				parsed.protoType = "bytes"
+				return parsed, nil
+			}
+
+			if elt.Name == "string" {
+				parsed.kind = stringsKind
+				parsed.protoType = "string"
+				return parsed, nil
+			}
+		case *ast.StarExpr:
+			ident, ok := elt.X.(*ast.Ident)
+			if !ok {
+				break
+			}
+
+			if _, ok := structs[ident.Name]; ok {
+				parsed.kind = messagesKind
+				parsed.protoType = ident.Name
+				parsed.goType = ident.Name
+				return parsed, nil
+			}
+		}
+	case *ast.MapType:
+		key, keyOk := t.Key.(*ast.Ident)
+		value, valueOk := t.Value.(*ast.InterfaceType)
+		if keyOk && valueOk && key.Name == "string" && len(value.Methods.List) == 0 {
+			parsed.kind = metadataKind
+			parsed.protoType = "bytes"
+			return parsed, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type %T", f.Type)
+}
+
+// snakeCase converts a Go name to snake case
+// (ex: PeerID to peer_id).
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// goCamelCase returns the name protoc-gen-go uses
+// for a field with a snake case name.
+func goCamelCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		} else if unicode.IsDigit(r) {
+			upper = true
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// protoComment converts a Go doc comment
+// to a proto comment.
+func protoComment(comment string, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		if len(line) == 0 {
+			b.WriteString(indent + "//\n")
+			continue
+		}
+
+		b.WriteString(indent + "// " + line + "\n")
+	}
+
+	return b.String()
+}
+
+// generateProto returns the contents of types.proto.
+func generateProto(messages []*message) []byte {
+	var b bytes.Buffer
+	b.WriteString(license)
+	b.WriteString(`
+// Code generated by pb/internal/gen. DO NOT EDIT.
+//
+// Each message mirrors the struct with the same name in the types
+// package. Fields are numbered in the order they are declared in
+// each struct. Enumerations are represented as strings (so that
+// implementation-specific values are preserved) and metadata maps
+// are encoded as JSON.
+
+syntax = "proto3";
+
+package rosetta;
+
+option go_package = "github.com/coinbase/rosetta-sdk-go/pb";
+`)
+
+	for _, m := range messages {
+		b.WriteString("\n")
+		if len(m.comment) > 0 {
+			b.WriteString(protoComment(m.comment, ""))
+		}
+
+		fmt.Fprintf(&b, "message %s {\n", m.name)
+		for i, f := range m.fields {
+			label := ""
+			switch f.kind {
+			case optionalKind, optionalEnumKind:
+				label = "optional "
+			case stringsKind, messagesKind:
+				label = "repeated "
+			}
+
+			if f.kind == metadataKind {
+				b.WriteString("  // JSON-encoded\n")
+			}
+
+			fmt.Fprintf(&b, "  %s%s %s = %d;\n", label, f.protoType, f.protoName, i+1)
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.Bytes()
+}
+
+// generateConverters returns the contents of convert.go.
+func generateConverters(messages []*message) []byte {
+	var b bytes.Buffer
+	b.WriteString(license)
+	b.WriteString(`
+// Code generated by pb/internal/gen. DO NOT EDIT.
+
+package pb
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+`)
+
+	for _, m := range messages {
+		writeFrom(&b, m)
+		writeTo(&b, m)
+	}
+
+	writeDispatch(&b, messages)
+
+	return b.Bytes()
+}
+
+// writeDispatch writes the functions that convert
+// any struct in the types package (used by Marshal
+// and Unmarshal).
+func writeDispatch(b *bytes.Buffer, messages []*message) {
+	b.WriteString(`
+// FromType converts v (a pointer to any struct in the
+// types package) to the message with the same name.
+func FromType(v interface{}) (proto.Message, error) {
+	switch v := v.(type) {
+`)
+	for _, m := range messages {
+		fmt.Fprintf(b, "\tcase *types.%[1]s:\n\t\treturn From%[1]s(v)\n", m.name)
+	}
+	b.WriteString(`	default:
+		return nil, fmt.Errorf("%w: %T", ErrTypeUnsupported, v)
+	}
+}
+
+// newMessage returns an empty message for v (a
+// pointer to any struct in the types package).
+func newMessage(v interface{}) (proto.Message, error) {
+	switch v.(type) {
+`)
+	for _, m := range messages {
+		fmt.Fprintf(b, "\tcase *types.%[1]s:\n\t\treturn &%[1]s{}, nil\n", m.name)
+	}
+	b.WriteString(`	default:
+		return nil, fmt.Errorf("%w: %T", ErrTypeUnsupported, v)
+	}
+}
+
+// toType converts m (created by newMessage(v)) into v.
+func toType(m proto.Message, v interface{}) error {
+	switch v := v.(type) {
+`)
+	for _, m := range messages {
+		fmt.Fprintf(b, `	case *types.%[1]s:
+		converted, err := To%[1]s(m.(*%[1]s)) // nolint:forcetypeassert
+		if err != nil {
+			return err
+		}
+		*v = *converted
+`, m.name)
+	}
+	b.WriteString(`	default:
+		return fmt.Errorf("%w: %T", ErrTypeUnsupported, v)
+	}
+
+	return nil
+}
+`)
+}
+
+func writeFrom(b *bytes.Buffer, m *message) {
+	fmt.Fprintf(b, `
+// From%[1]s converts a *types.%[1]s to a *%[1]s.
+func From%[1]s(v *types.%[1]s) (*%[1]s, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	m := &%[1]s{}
+`, m.name)
+
+	for _, f := range m.fields {
+		switch f.kind {
+		case scalarKind, optionalKind, bytesKind, stringsKind:
+			fmt.Fprintf(b, "\tm.%s = v.%s\n", f.pbName, f.goName)
+		case enumKind:
+			fmt.Fprintf(b, "\tm.%s = string(v.%s)\n", f.pbName, f.goName)
+		case optionalEnumKind:
+			fmt.Fprintf(b, `	if v.%[2]s != nil {
+		value := string(*v.%[2]s)
+		m.%[1]s = &value
+	}
+`, f.pbName, f.goName)
+		case messageKind:
+			fmt.Fprintf(b, `	if v.%[2]s != nil {
+		value, err := From%[3]s(v.%[2]s)
+		if err != nil {
+			return nil, err
+		}
+		m.%[1]s = value
+	}
+`, f.pbName, f.goName, f.goType)
+		case messagesKind:
+			fmt.Fprintf(b, `	if len(v.%[2]s) > 0 {
+		m.%[1]s = make([]*%[3]s, len(v.%[2]s))
+		for i, value := range v.%[2]s {
+			converted, err := From%[3]s(value)
+			if err != nil {
+				return nil, err
+			}
+			m.%[1]s[i] = converted
+		}
+	}
+`, f.pbName, f.goName, f.goType)
+		case metadataKind:
+			fmt.Fprintf(b, `	%[3]s, err := marshalMetadata(v.%[2]s)
+	if err != nil {
+		return nil, err
+	}
+	m.%[1]s = %[3]s
+`, f.pbName, f.goName, lowerFirst(f.goName))
+		}
+	}
+
+	b.WriteString("\n\treturn m, nil\n}\n")
+}
+
+func writeTo(b *bytes.Buffer, m *message) {
+	fmt.Fprintf(b, `
+// To%[1]s converts a *%[1]s to a *types.%[1]s.
+func To%[1]s(m *%[1]s) (*types.%[1]s, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	v := &types.%[1]s{}
+`, m.name)
+
+	for _, f := range m.fields {
+		switch f.kind {
+		case scalarKind, optionalKind, bytesKind:
+			fmt.Fprintf(b, "\tv.%s = m.%s\n", f.goName, f.pbName)
+		case stringsKind:
+			fmt.Fprintf(b, "\tv.%s = m.%s\n", f.goName, f.pbName)
+			if f.required {
+				fmt.Fprintf(b, `	if v.%[1]s == nil {
+		v.%[1]s = []string{}
+	}
+`, f.goName)
+			}
+		case enumKind:
+			fmt.Fprintf(b, "\tv.%s = types.%s(m.%s)\n", f.goName, f.goType, f.pbName)
+		case optionalEnumKind:
+			fmt.Fprintf(b, `	if m.%[1]s != nil {
+		value := types.%[3]s(*m.%[1]s)
+		v.%[2]s = &value
+	}
+`, f.pbName, f.goName, f.goType)
+		case messageKind:
+			fmt.Fprintf(b, `	if m.%[1]s != nil {
+		value, err := To%[3]s(m.%[1]s)
+		if err != nil {
+			return nil, err
+		}
+		v.%[2]s = value
+	}
+`, f.pbName, f.goName, f.goType)
+		case messagesKind:
+			loop := fmt.Sprintf(`v.%[2]s = make([]*types.%[3]s, len(m.%[1]s))
+	for i, value := range m.%[1]s {
+		converted, err := To%[3]s(value)
+		if err != nil {
+			return nil, err
+		}
+		v.%[2]s[i] = converted
+	}
+`, f.pbName, f.goName, f.goType)
+			if f.required {
+				// Required arrays are never null in JSON.
+				fmt.Fprintf(b, "\t%s", loop)
+				continue
+			}
+
+			fmt.Fprintf(b, "\tif len(m.%s) > 0 {\n\t\t%s\t}\n", f.pbName, strings.ReplaceAll(loop, "\n\t", "\n\t\t"))
+		case metadataKind:
+			fmt.Fprintf(b, `	%[3]s, err := unmarshalMetadata(m.%[1]s)
+	if err != nil {
+		return nil, err
+	}
+	v.%[2]s = %[3]s
+`, f.pbName, f.goName, lowerFirst(f.goName))
+		}
+	}
+
+	b.WriteString("\n\treturn v, nil\n}\n")
+}
+
+func lowerFirst(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
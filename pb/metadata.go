@@ -0,0 +1,55 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrMetadataInvalid is returned when metadata cannot
+// be encoded to (or decoded from) JSON.
+var ErrMetadataInvalid = errors.New("metadata is invalid")
+
+// marshalMetadata encodes metadata as JSON. Empty
+// metadata is omitted (like in the types package).
+func marshalMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMetadataInvalid, err.Error())
+	}
+
+	return b, nil
+}
+
+// unmarshalMetadata decodes metadata encoded
+// by marshalMetadata.
+func unmarshalMetadata(b []byte) (map[string]interface{}, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(b, &metadata); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMetadataInvalid, err.Error())
+	}
+
+	return metadata, nil
+}
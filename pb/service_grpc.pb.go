@@ -0,0 +1,1274 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Services mirror the APIs in the Rosetta specification (and the
+// Servicer interfaces in the server package). When an
+// implementation returns a *types.Error, it is attached to the
+// gRPC status as an Error detail.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: service.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AccountService_AccountBalance_FullMethodName = "/rosetta.AccountService/AccountBalance"
+	AccountService_AccountCoins_FullMethodName   = "/rosetta.AccountService/AccountCoins"
+)
+
+// AccountServiceClient is the client API for AccountService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccountServiceClient interface {
+	// AccountBalance gets an array of all AccountBalances for an
+	// AccountIdentifier and the BlockIdentifier at which the
+	// balance lookup was performed.
+	AccountBalance(ctx context.Context, in *AccountBalanceRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error)
+	// AccountCoins gets an array of all unspent coins for an
+	// AccountIdentifier and the BlockIdentifier at which the
+	// lookup was performed.
+	AccountCoins(ctx context.Context, in *AccountCoinsRequest, opts ...grpc.CallOption) (*AccountCoinsResponse, error)
+}
+
+type accountServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountServiceClient(cc grpc.ClientConnInterface) AccountServiceClient {
+	return &accountServiceClient{cc}
+}
+
+func (c *accountServiceClient) AccountBalance(ctx context.Context, in *AccountBalanceRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error) {
+	out := new(AccountBalanceResponse)
+	err := c.cc.Invoke(ctx, AccountService_AccountBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) AccountCoins(ctx context.Context, in *AccountCoinsRequest, opts ...grpc.CallOption) (*AccountCoinsResponse, error) {
+	out := new(AccountCoinsResponse)
+	err := c.cc.Invoke(ctx, AccountService_AccountCoins_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountServiceServer is the server API for AccountService service.
+// All implementations must embed UnimplementedAccountServiceServer
+// for forward compatibility
+type AccountServiceServer interface {
+	// AccountBalance gets an array of all AccountBalances for an
+	// AccountIdentifier and the BlockIdentifier at which the
+	// balance lookup was performed.
+	AccountBalance(context.Context, *AccountBalanceRequest) (*AccountBalanceResponse, error)
+	// AccountCoins gets an array of all unspent coins for an
+	// AccountIdentifier and the BlockIdentifier at which the
+	// lookup was performed.
+	AccountCoins(context.Context, *AccountCoinsRequest) (*AccountCoinsResponse, error)
+	mustEmbedUnimplementedAccountServiceServer()
+}
+
+// UnimplementedAccountServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAccountServiceServer struct {
+}
+
+func (UnimplementedAccountServiceServer) AccountBalance(context.Context, *AccountBalanceRequest) (*AccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccountBalance not implemented")
+}
+func (UnimplementedAccountServiceServer) AccountCoins(context.Context, *AccountCoinsRequest) (*AccountCoinsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccountCoins not implemented")
+}
+func (UnimplementedAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {}
+
+// UnsafeAccountServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountServiceServer will
+// result in compilation errors.
+type UnsafeAccountServiceServer interface {
+	mustEmbedUnimplementedAccountServiceServer()
+}
+
+func RegisterAccountServiceServer(s grpc.ServiceRegistrar, srv AccountServiceServer) {
+	s.RegisterService(&AccountService_ServiceDesc, srv)
+}
+
+func _AccountService_AccountBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).AccountBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_AccountBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).AccountBalance(ctx, req.(*AccountBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_AccountCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountCoinsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).AccountCoins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_AccountCoins_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).AccountCoins(ctx, req.(*AccountCoinsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AccountService_ServiceDesc is the grpc.ServiceDesc for AccountService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccountService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.AccountService",
+	HandlerType: (*AccountServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AccountBalance",
+			Handler:    _AccountService_AccountBalance_Handler,
+		},
+		{
+			MethodName: "AccountCoins",
+			Handler:    _AccountService_AccountCoins_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	BlockService_Block_FullMethodName            = "/rosetta.BlockService/Block"
+	BlockService_BlockTransaction_FullMethodName = "/rosetta.BlockService/BlockTransaction"
+)
+
+// BlockServiceClient is the client API for BlockService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BlockServiceClient interface {
+	// Block gets a block by its BlockIdentifier.
+	Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	// BlockTransaction gets a transaction in a block by its
+	// TransactionIdentifier.
+	BlockTransaction(ctx context.Context, in *BlockTransactionRequest, opts ...grpc.CallOption) (*BlockTransactionResponse, error)
+}
+
+type blockServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlockServiceClient(cc grpc.ClientConnInterface) BlockServiceClient {
+	return &blockServiceClient{cc}
+}
+
+func (c *blockServiceClient) Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, BlockService_Block_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockServiceClient) BlockTransaction(ctx context.Context, in *BlockTransactionRequest, opts ...grpc.CallOption) (*BlockTransactionResponse, error) {
+	out := new(BlockTransactionResponse)
+	err := c.cc.Invoke(ctx, BlockService_BlockTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BlockServiceServer is the server API for BlockService service.
+// All implementations must embed UnimplementedBlockServiceServer
+// for forward compatibility
+type BlockServiceServer interface {
+	// Block gets a block by its BlockIdentifier.
+	Block(context.Context, *BlockRequest) (*BlockResponse, error)
+	// BlockTransaction gets a transaction in a block by its
+	// TransactionIdentifier.
+	BlockTransaction(context.Context, *BlockTransactionRequest) (*BlockTransactionResponse, error)
+	mustEmbedUnimplementedBlockServiceServer()
+}
+
+// UnimplementedBlockServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBlockServiceServer struct {
+}
+
+func (UnimplementedBlockServiceServer) Block(context.Context, *BlockRequest) (*BlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Block not implemented")
+}
+func (UnimplementedBlockServiceServer) BlockTransaction(context.Context, *BlockTransactionRequest) (*BlockTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockTransaction not implemented")
+}
+func (UnimplementedBlockServiceServer) mustEmbedUnimplementedBlockServiceServer() {}
+
+// UnsafeBlockServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlockServiceServer will
+// result in compilation errors.
+type UnsafeBlockServiceServer interface {
+	mustEmbedUnimplementedBlockServiceServer()
+}
+
+func RegisterBlockServiceServer(s grpc.ServiceRegistrar, srv BlockServiceServer) {
+	s.RegisterService(&BlockService_ServiceDesc, srv)
+}
+
+func _BlockService_Block_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockServiceServer).Block(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlockService_Block_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockServiceServer).Block(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockService_BlockTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockServiceServer).BlockTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlockService_BlockTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockServiceServer).BlockTransaction(ctx, req.(*BlockTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BlockService_ServiceDesc is the grpc.ServiceDesc for BlockService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BlockService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.BlockService",
+	HandlerType: (*BlockServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Block",
+			Handler:    _BlockService_Block_Handler,
+		},
+		{
+			MethodName: "BlockTransaction",
+			Handler:    _BlockService_BlockTransaction_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	CallService_Call_FullMethodName = "/rosetta.CallService/Call"
+)
+
+// CallServiceClient is the client API for CallService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CallServiceClient interface {
+	// Call invokes an arbitrary, network-specific procedure call.
+	Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error)
+}
+
+type callServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCallServiceClient(cc grpc.ClientConnInterface) CallServiceClient {
+	return &callServiceClient{cc}
+}
+
+func (c *callServiceClient) Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error) {
+	out := new(CallResponse)
+	err := c.cc.Invoke(ctx, CallService_Call_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CallServiceServer is the server API for CallService service.
+// All implementations must embed UnimplementedCallServiceServer
+// for forward compatibility
+type CallServiceServer interface {
+	// Call invokes an arbitrary, network-specific procedure call.
+	Call(context.Context, *CallRequest) (*CallResponse, error)
+	mustEmbedUnimplementedCallServiceServer()
+}
+
+// UnimplementedCallServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCallServiceServer struct {
+}
+
+func (UnimplementedCallServiceServer) Call(context.Context, *CallRequest) (*CallResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Call not implemented")
+}
+func (UnimplementedCallServiceServer) mustEmbedUnimplementedCallServiceServer() {}
+
+// UnsafeCallServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CallServiceServer will
+// result in compilation errors.
+type UnsafeCallServiceServer interface {
+	mustEmbedUnimplementedCallServiceServer()
+}
+
+func RegisterCallServiceServer(s grpc.ServiceRegistrar, srv CallServiceServer) {
+	s.RegisterService(&CallService_ServiceDesc, srv)
+}
+
+func _CallService_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CallServiceServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CallService_Call_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CallServiceServer).Call(ctx, req.(*CallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CallService_ServiceDesc is the grpc.ServiceDesc for CallService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CallService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.CallService",
+	HandlerType: (*CallServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler:    _CallService_Call_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	ConstructionService_ConstructionCombine_FullMethodName    = "/rosetta.ConstructionService/ConstructionCombine"
+	ConstructionService_ConstructionDerive_FullMethodName     = "/rosetta.ConstructionService/ConstructionDerive"
+	ConstructionService_ConstructionHash_FullMethodName       = "/rosetta.ConstructionService/ConstructionHash"
+	ConstructionService_ConstructionMetadata_FullMethodName   = "/rosetta.ConstructionService/ConstructionMetadata"
+	ConstructionService_ConstructionParse_FullMethodName      = "/rosetta.ConstructionService/ConstructionParse"
+	ConstructionService_ConstructionPayloads_FullMethodName   = "/rosetta.ConstructionService/ConstructionPayloads"
+	ConstructionService_ConstructionPreprocess_FullMethodName = "/rosetta.ConstructionService/ConstructionPreprocess"
+	ConstructionService_ConstructionSubmit_FullMethodName     = "/rosetta.ConstructionService/ConstructionSubmit"
+)
+
+// ConstructionServiceClient is the client API for ConstructionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConstructionServiceClient interface {
+	// ConstructionCombine creates a network-specific transaction
+	// from an unsigned transaction and an array of signatures.
+	ConstructionCombine(ctx context.Context, in *ConstructionCombineRequest, opts ...grpc.CallOption) (*ConstructionCombineResponse, error)
+	// ConstructionDerive derives an AccountIdentifier from a
+	// PublicKey.
+	ConstructionDerive(ctx context.Context, in *ConstructionDeriveRequest, opts ...grpc.CallOption) (*ConstructionDeriveResponse, error)
+	// ConstructionHash gets the network-specific transaction hash
+	// for a signed transaction.
+	ConstructionHash(ctx context.Context, in *ConstructionHashRequest, opts ...grpc.CallOption) (*TransactionIdentifierResponse, error)
+	// ConstructionMetadata gets any information required to
+	// construct a transaction for a specific network.
+	ConstructionMetadata(ctx context.Context, in *ConstructionMetadataRequest, opts ...grpc.CallOption) (*ConstructionMetadataResponse, error)
+	// ConstructionParse parses either an unsigned or signed
+	// transaction.
+	ConstructionParse(ctx context.Context, in *ConstructionParseRequest, opts ...grpc.CallOption) (*ConstructionParseResponse, error)
+	// ConstructionPayloads generates an unsigned transaction and
+	// signing payloads.
+	ConstructionPayloads(ctx context.Context, in *ConstructionPayloadsRequest, opts ...grpc.CallOption) (*ConstructionPayloadsResponse, error)
+	// ConstructionPreprocess creates a request to fetch metadata.
+	ConstructionPreprocess(ctx context.Context, in *ConstructionPreprocessRequest, opts ...grpc.CallOption) (*ConstructionPreprocessResponse, error)
+	// ConstructionSubmit submits a pre-signed transaction to the
+	// node.
+	ConstructionSubmit(ctx context.Context, in *ConstructionSubmitRequest, opts ...grpc.CallOption) (*TransactionIdentifierResponse, error)
+}
+
+type constructionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConstructionServiceClient(cc grpc.ClientConnInterface) ConstructionServiceClient {
+	return &constructionServiceClient{cc}
+}
+
+func (c *constructionServiceClient) ConstructionCombine(ctx context.Context, in *ConstructionCombineRequest, opts ...grpc.CallOption) (*ConstructionCombineResponse, error) {
+	out := new(ConstructionCombineResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionCombine_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionDerive(ctx context.Context, in *ConstructionDeriveRequest, opts ...grpc.CallOption) (*ConstructionDeriveResponse, error) {
+	out := new(ConstructionDeriveResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionDerive_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionHash(ctx context.Context, in *ConstructionHashRequest, opts ...grpc.CallOption) (*TransactionIdentifierResponse, error) {
+	out := new(TransactionIdentifierResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionHash_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionMetadata(ctx context.Context, in *ConstructionMetadataRequest, opts ...grpc.CallOption) (*ConstructionMetadataResponse, error) {
+	out := new(ConstructionMetadataResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionParse(ctx context.Context, in *ConstructionParseRequest, opts ...grpc.CallOption) (*ConstructionParseResponse, error) {
+	out := new(ConstructionParseResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionParse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionPayloads(ctx context.Context, in *ConstructionPayloadsRequest, opts ...grpc.CallOption) (*ConstructionPayloadsResponse, error) {
+	out := new(ConstructionPayloadsResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionPayloads_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionPreprocess(ctx context.Context, in *ConstructionPreprocessRequest, opts ...grpc.CallOption) (*ConstructionPreprocessResponse, error) {
+	out := new(ConstructionPreprocessResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionPreprocess_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *constructionServiceClient) ConstructionSubmit(ctx context.Context, in *ConstructionSubmitRequest, opts ...grpc.CallOption) (*TransactionIdentifierResponse, error) {
+	out := new(TransactionIdentifierResponse)
+	err := c.cc.Invoke(ctx, ConstructionService_ConstructionSubmit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConstructionServiceServer is the server API for ConstructionService service.
+// All implementations must embed UnimplementedConstructionServiceServer
+// for forward compatibility
+type ConstructionServiceServer interface {
+	// ConstructionCombine creates a network-specific transaction
+	// from an unsigned transaction and an array of signatures.
+	ConstructionCombine(context.Context, *ConstructionCombineRequest) (*ConstructionCombineResponse, error)
+	// ConstructionDerive derives an AccountIdentifier from a
+	// PublicKey.
+	ConstructionDerive(context.Context, *ConstructionDeriveRequest) (*ConstructionDeriveResponse, error)
+	// ConstructionHash gets the network-specific transaction hash
+	// for a signed transaction.
+	ConstructionHash(context.Context, *ConstructionHashRequest) (*TransactionIdentifierResponse, error)
+	// ConstructionMetadata gets any information required to
+	// construct a transaction for a specific network.
+	ConstructionMetadata(context.Context, *ConstructionMetadataRequest) (*ConstructionMetadataResponse, error)
+	// ConstructionParse parses either an unsigned or signed
+	// transaction.
+	ConstructionParse(context.Context, *ConstructionParseRequest) (*ConstructionParseResponse, error)
+	// ConstructionPayloads generates an unsigned transaction and
+	// signing payloads.
+	ConstructionPayloads(context.Context, *ConstructionPayloadsRequest) (*ConstructionPayloadsResponse, error)
+	// ConstructionPreprocess creates a request to fetch metadata.
+	ConstructionPreprocess(context.Context, *ConstructionPreprocessRequest) (*ConstructionPreprocessResponse, error)
+	// ConstructionSubmit submits a pre-signed transaction to the
+	// node.
+	ConstructionSubmit(context.Context, *ConstructionSubmitRequest) (*TransactionIdentifierResponse, error)
+	mustEmbedUnimplementedConstructionServiceServer()
+}
+
+// UnimplementedConstructionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedConstructionServiceServer struct {
+}
+
+func (UnimplementedConstructionServiceServer) ConstructionCombine(context.Context, *ConstructionCombineRequest) (*ConstructionCombineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionCombine not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionDerive(context.Context, *ConstructionDeriveRequest) (*ConstructionDeriveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionDerive not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionHash(context.Context, *ConstructionHashRequest) (*TransactionIdentifierResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionHash not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionMetadata(context.Context, *ConstructionMetadataRequest) (*ConstructionMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionMetadata not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionParse(context.Context, *ConstructionParseRequest) (*ConstructionParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionParse not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionPayloads(context.Context, *ConstructionPayloadsRequest) (*ConstructionPayloadsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionPayloads not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionPreprocess(context.Context, *ConstructionPreprocessRequest) (*ConstructionPreprocessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionPreprocess not implemented")
+}
+func (UnimplementedConstructionServiceServer) ConstructionSubmit(context.Context, *ConstructionSubmitRequest) (*TransactionIdentifierResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConstructionSubmit not implemented")
+}
+func (UnimplementedConstructionServiceServer) mustEmbedUnimplementedConstructionServiceServer() {}
+
+// UnsafeConstructionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConstructionServiceServer will
+// result in compilation errors.
+type UnsafeConstructionServiceServer interface {
+	mustEmbedUnimplementedConstructionServiceServer()
+}
+
+func RegisterConstructionServiceServer(s grpc.ServiceRegistrar, srv ConstructionServiceServer) {
+	s.RegisterService(&ConstructionService_ServiceDesc, srv)
+}
+
+func _ConstructionService_ConstructionCombine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionCombineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionCombine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionCombine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionCombine(ctx, req.(*ConstructionCombineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionDerive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionDeriveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionDerive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionDerive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionDerive(ctx, req.(*ConstructionDeriveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionHash(ctx, req.(*ConstructionHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionMetadata(ctx, req.(*ConstructionMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionParse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionParse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionParse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionParse(ctx, req.(*ConstructionParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionPayloads_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionPayloadsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionPayloads(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionPayloads_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionPayloads(ctx, req.(*ConstructionPayloadsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionPreprocess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionPreprocessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionPreprocess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionPreprocess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionPreprocess(ctx, req.(*ConstructionPreprocessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConstructionService_ConstructionSubmit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstructionSubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConstructionServiceServer).ConstructionSubmit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConstructionService_ConstructionSubmit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConstructionServiceServer).ConstructionSubmit(ctx, req.(*ConstructionSubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConstructionService_ServiceDesc is the grpc.ServiceDesc for ConstructionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConstructionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.ConstructionService",
+	HandlerType: (*ConstructionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConstructionCombine",
+			Handler:    _ConstructionService_ConstructionCombine_Handler,
+		},
+		{
+			MethodName: "ConstructionDerive",
+			Handler:    _ConstructionService_ConstructionDerive_Handler,
+		},
+		{
+			MethodName: "ConstructionHash",
+			Handler:    _ConstructionService_ConstructionHash_Handler,
+		},
+		{
+			MethodName: "ConstructionMetadata",
+			Handler:    _ConstructionService_ConstructionMetadata_Handler,
+		},
+		{
+			MethodName: "ConstructionParse",
+			Handler:    _ConstructionService_ConstructionParse_Handler,
+		},
+		{
+			MethodName: "ConstructionPayloads",
+			Handler:    _ConstructionService_ConstructionPayloads_Handler,
+		},
+		{
+			MethodName: "ConstructionPreprocess",
+			Handler:    _ConstructionService_ConstructionPreprocess_Handler,
+		},
+		{
+			MethodName: "ConstructionSubmit",
+			Handler:    _ConstructionService_ConstructionSubmit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	EventsService_EventsBlocks_FullMethodName = "/rosetta.EventsService/EventsBlocks"
+)
+
+// EventsServiceClient is the client API for EventsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EventsServiceClient interface {
+	// EventsBlocks gets a range of BlockEvents.
+	EventsBlocks(ctx context.Context, in *EventsBlocksRequest, opts ...grpc.CallOption) (*EventsBlocksResponse, error)
+}
+
+type eventsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventsServiceClient(cc grpc.ClientConnInterface) EventsServiceClient {
+	return &eventsServiceClient{cc}
+}
+
+func (c *eventsServiceClient) EventsBlocks(ctx context.Context, in *EventsBlocksRequest, opts ...grpc.CallOption) (*EventsBlocksResponse, error) {
+	out := new(EventsBlocksResponse)
+	err := c.cc.Invoke(ctx, EventsService_EventsBlocks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventsServiceServer is the server API for EventsService service.
+// All implementations must embed UnimplementedEventsServiceServer
+// for forward compatibility
+type EventsServiceServer interface {
+	// EventsBlocks gets a range of BlockEvents.
+	EventsBlocks(context.Context, *EventsBlocksRequest) (*EventsBlocksResponse, error)
+	mustEmbedUnimplementedEventsServiceServer()
+}
+
+// UnimplementedEventsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEventsServiceServer struct {
+}
+
+func (UnimplementedEventsServiceServer) EventsBlocks(context.Context, *EventsBlocksRequest) (*EventsBlocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EventsBlocks not implemented")
+}
+func (UnimplementedEventsServiceServer) mustEmbedUnimplementedEventsServiceServer() {}
+
+// UnsafeEventsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventsServiceServer will
+// result in compilation errors.
+type UnsafeEventsServiceServer interface {
+	mustEmbedUnimplementedEventsServiceServer()
+}
+
+func RegisterEventsServiceServer(s grpc.ServiceRegistrar, srv EventsServiceServer) {
+	s.RegisterService(&EventsService_ServiceDesc, srv)
+}
+
+func _EventsService_EventsBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventsBlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventsServiceServer).EventsBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventsService_EventsBlocks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventsServiceServer).EventsBlocks(ctx, req.(*EventsBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EventsService_ServiceDesc is the grpc.ServiceDesc for EventsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.EventsService",
+	HandlerType: (*EventsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "EventsBlocks",
+			Handler:    _EventsService_EventsBlocks_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	MempoolService_Mempool_FullMethodName            = "/rosetta.MempoolService/Mempool"
+	MempoolService_MempoolTransaction_FullMethodName = "/rosetta.MempoolService/MempoolTransaction"
+)
+
+// MempoolServiceClient is the client API for MempoolService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MempoolServiceClient interface {
+	// Mempool gets all TransactionIdentifiers in the mempool.
+	Mempool(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*MempoolResponse, error)
+	// MempoolTransaction gets a transaction in the mempool by its
+	// TransactionIdentifier.
+	MempoolTransaction(ctx context.Context, in *MempoolTransactionRequest, opts ...grpc.CallOption) (*MempoolTransactionResponse, error)
+}
+
+type mempoolServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMempoolServiceClient(cc grpc.ClientConnInterface) MempoolServiceClient {
+	return &mempoolServiceClient{cc}
+}
+
+func (c *mempoolServiceClient) Mempool(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*MempoolResponse, error) {
+	out := new(MempoolResponse)
+	err := c.cc.Invoke(ctx, MempoolService_Mempool_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mempoolServiceClient) MempoolTransaction(ctx context.Context, in *MempoolTransactionRequest, opts ...grpc.CallOption) (*MempoolTransactionResponse, error) {
+	out := new(MempoolTransactionResponse)
+	err := c.cc.Invoke(ctx, MempoolService_MempoolTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MempoolServiceServer is the server API for MempoolService service.
+// All implementations must embed UnimplementedMempoolServiceServer
+// for forward compatibility
+type MempoolServiceServer interface {
+	// Mempool gets all TransactionIdentifiers in the mempool.
+	Mempool(context.Context, *NetworkRequest) (*MempoolResponse, error)
+	// MempoolTransaction gets a transaction in the mempool by its
+	// TransactionIdentifier.
+	MempoolTransaction(context.Context, *MempoolTransactionRequest) (*MempoolTransactionResponse, error)
+	mustEmbedUnimplementedMempoolServiceServer()
+}
+
+// UnimplementedMempoolServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMempoolServiceServer struct {
+}
+
+func (UnimplementedMempoolServiceServer) Mempool(context.Context, *NetworkRequest) (*MempoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mempool not implemented")
+}
+func (UnimplementedMempoolServiceServer) MempoolTransaction(context.Context, *MempoolTransactionRequest) (*MempoolTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MempoolTransaction not implemented")
+}
+func (UnimplementedMempoolServiceServer) mustEmbedUnimplementedMempoolServiceServer() {}
+
+// UnsafeMempoolServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MempoolServiceServer will
+// result in compilation errors.
+type UnsafeMempoolServiceServer interface {
+	mustEmbedUnimplementedMempoolServiceServer()
+}
+
+func RegisterMempoolServiceServer(s grpc.ServiceRegistrar, srv MempoolServiceServer) {
+	s.RegisterService(&MempoolService_ServiceDesc, srv)
+}
+
+func _MempoolService_Mempool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MempoolServiceServer).Mempool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MempoolService_Mempool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MempoolServiceServer).Mempool(ctx, req.(*NetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MempoolService_MempoolTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MempoolTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MempoolServiceServer).MempoolTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MempoolService_MempoolTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MempoolServiceServer).MempoolTransaction(ctx, req.(*MempoolTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MempoolService_ServiceDesc is the grpc.ServiceDesc for MempoolService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MempoolService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.MempoolService",
+	HandlerType: (*MempoolServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Mempool",
+			Handler:    _MempoolService_Mempool_Handler,
+		},
+		{
+			MethodName: "MempoolTransaction",
+			Handler:    _MempoolService_MempoolTransaction_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	NetworkService_NetworkList_FullMethodName    = "/rosetta.NetworkService/NetworkList"
+	NetworkService_NetworkOptions_FullMethodName = "/rosetta.NetworkService/NetworkOptions"
+	NetworkService_NetworkStatus_FullMethodName  = "/rosetta.NetworkService/NetworkStatus"
+)
+
+// NetworkServiceClient is the client API for NetworkService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type NetworkServiceClient interface {
+	// NetworkList gets the list of NetworkIdentifiers supported by
+	// the node.
+	NetworkList(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*NetworkListResponse, error)
+	// NetworkOptions gets the version information and allowed
+	// network-specific types for a NetworkIdentifier.
+	NetworkOptions(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkOptionsResponse, error)
+	// NetworkStatus gets the current status of a network.
+	NetworkStatus(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkStatusResponse, error)
+}
+
+type networkServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNetworkServiceClient(cc grpc.ClientConnInterface) NetworkServiceClient {
+	return &networkServiceClient{cc}
+}
+
+func (c *networkServiceClient) NetworkList(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*NetworkListResponse, error) {
+	out := new(NetworkListResponse)
+	err := c.cc.Invoke(ctx, NetworkService_NetworkList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) NetworkOptions(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkOptionsResponse, error) {
+	out := new(NetworkOptionsResponse)
+	err := c.cc.Invoke(ctx, NetworkService_NetworkOptions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) NetworkStatus(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkStatusResponse, error) {
+	out := new(NetworkStatusResponse)
+	err := c.cc.Invoke(ctx, NetworkService_NetworkStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NetworkServiceServer is the server API for NetworkService service.
+// All implementations must embed UnimplementedNetworkServiceServer
+// for forward compatibility
+type NetworkServiceServer interface {
+	// NetworkList gets the list of NetworkIdentifiers supported by
+	// the node.
+	NetworkList(context.Context, *MetadataRequest) (*NetworkListResponse, error)
+	// NetworkOptions gets the version information and allowed
+	// network-specific types for a NetworkIdentifier.
+	NetworkOptions(context.Context, *NetworkRequest) (*NetworkOptionsResponse, error)
+	// NetworkStatus gets the current status of a network.
+	NetworkStatus(context.Context, *NetworkRequest) (*NetworkStatusResponse, error)
+	mustEmbedUnimplementedNetworkServiceServer()
+}
+
+// UnimplementedNetworkServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedNetworkServiceServer struct {
+}
+
+func (UnimplementedNetworkServiceServer) NetworkList(context.Context, *MetadataRequest) (*NetworkListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkList not implemented")
+}
+func (UnimplementedNetworkServiceServer) NetworkOptions(context.Context, *NetworkRequest) (*NetworkOptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkOptions not implemented")
+}
+func (UnimplementedNetworkServiceServer) NetworkStatus(context.Context, *NetworkRequest) (*NetworkStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkStatus not implemented")
+}
+func (UnimplementedNetworkServiceServer) mustEmbedUnimplementedNetworkServiceServer() {}
+
+// UnsafeNetworkServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NetworkServiceServer will
+// result in compilation errors.
+type UnsafeNetworkServiceServer interface {
+	mustEmbedUnimplementedNetworkServiceServer()
+}
+
+func RegisterNetworkServiceServer(s grpc.ServiceRegistrar, srv NetworkServiceServer) {
+	s.RegisterService(&NetworkService_ServiceDesc, srv)
+}
+
+func _NetworkService_NetworkList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).NetworkList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_NetworkList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).NetworkList(ctx, req.(*MetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_NetworkOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).NetworkOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_NetworkOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).NetworkOptions(ctx, req.(*NetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_NetworkStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).NetworkStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_NetworkStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).NetworkStatus(ctx, req.(*NetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NetworkService_ServiceDesc is the grpc.ServiceDesc for NetworkService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NetworkService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.NetworkService",
+	HandlerType: (*NetworkServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NetworkList",
+			Handler:    _NetworkService_NetworkList_Handler,
+		},
+		{
+			MethodName: "NetworkOptions",
+			Handler:    _NetworkService_NetworkOptions_Handler,
+		},
+		{
+			MethodName: "NetworkStatus",
+			Handler:    _NetworkService_NetworkStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
+
+const (
+	SearchService_SearchTransactions_FullMethodName = "/rosetta.SearchService/SearchTransactions"
+)
+
+// SearchServiceClient is the client API for SearchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SearchServiceClient interface {
+	// SearchTransactions searches for transactions matching a set
+	// of conditions.
+	SearchTransactions(ctx context.Context, in *SearchTransactionsRequest, opts ...grpc.CallOption) (*SearchTransactionsResponse, error)
+}
+
+type searchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSearchServiceClient(cc grpc.ClientConnInterface) SearchServiceClient {
+	return &searchServiceClient{cc}
+}
+
+func (c *searchServiceClient) SearchTransactions(ctx context.Context, in *SearchTransactionsRequest, opts ...grpc.CallOption) (*SearchTransactionsResponse, error) {
+	out := new(SearchTransactionsResponse)
+	err := c.cc.Invoke(ctx, SearchService_SearchTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchServiceServer is the server API for SearchService service.
+// All implementations must embed UnimplementedSearchServiceServer
+// for forward compatibility
+type SearchServiceServer interface {
+	// SearchTransactions searches for transactions matching a set
+	// of conditions.
+	SearchTransactions(context.Context, *SearchTransactionsRequest) (*SearchTransactionsResponse, error)
+	mustEmbedUnimplementedSearchServiceServer()
+}
+
+// UnimplementedSearchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSearchServiceServer struct {
+}
+
+func (UnimplementedSearchServiceServer) SearchTransactions(context.Context, *SearchTransactionsRequest) (*SearchTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTransactions not implemented")
+}
+func (UnimplementedSearchServiceServer) mustEmbedUnimplementedSearchServiceServer() {}
+
+// UnsafeSearchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SearchServiceServer will
+// result in compilation errors.
+type UnsafeSearchServiceServer interface {
+	mustEmbedUnimplementedSearchServiceServer()
+}
+
+func RegisterSearchServiceServer(s grpc.ServiceRegistrar, srv SearchServiceServer) {
+	s.RegisterService(&SearchService_ServiceDesc, srv)
+}
+
+func _SearchService_SearchTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServiceServer).SearchTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SearchService_SearchTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServiceServer).SearchTransactions(ctx, req.(*SearchTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SearchService_ServiceDesc is the grpc.ServiceDesc for SearchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SearchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rosetta.SearchService",
+	HandlerType: (*SearchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchTransactions",
+			Handler:    _SearchService_SearchTransactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service.proto",
+}
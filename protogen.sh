@@ -0,0 +1,35 @@
+#!/bin/bash
+# Copyright 2022 Coinbase, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Generate pb/types.proto and pb/convert.go from the types package
+# (run after codegen.sh so that messages match the specification).
+go run ./pb/internal/gen -types types -proto pb/types.proto -convert pb/convert.go;
+
+# Install protoc plugins
+PROTOC_GEN_GO_VERSION=v1.33.0
+PROTOC_GEN_GO_GRPC_VERSION=v1.3.0
+GOBIN="${PWD}/tmp" go install google.golang.org/protobuf/cmd/protoc-gen-go@${PROTOC_GEN_GO_VERSION};
+GOBIN="${PWD}/tmp" go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@${PROTOC_GEN_GO_GRPC_VERSION};
+
+# Generate messages and services
+protoc \
+  --plugin=protoc-gen-go="${PWD}/tmp/protoc-gen-go" \
+  --plugin=protoc-gen-go-grpc="${PWD}/tmp/protoc-gen-go-grpc" \
+  --proto_path=pb \
+  --go_out=pb --go_opt=paths=source_relative \
+  --go-grpc_out=pb --go-grpc_opt=paths=source_relative \
+  pb/types.proto pb/service.proto;
+
+rm -rf tmp;
@@ -0,0 +1,264 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// defaultRecheckInterval is the default time.Duration
+	// between checks of the live head while changes are
+	// parked.
+	defaultRecheckInterval = 5 * time.Second
+
+	// defaultMaxRecheckInterval is the default maximum
+	// time.Duration between checks of the live head.
+	defaultMaxRecheckInterval = 1 * time.Minute
+)
+
+// LiveHeadHelper is an optional extension of Helper. If the
+// Helper implements LiveHeadHelper and node backoff is enabled
+// (see WithNodeBackoff), changes the node cannot reconcile
+// because it is behind the block at which they were computed
+// are parked until the node catches up (instead of causing
+// reconciliation to fail).
+type LiveHeadHelper interface {
+	LiveHead(ctx context.Context) (*types.BlockIdentifier, error)
+}
+
+// BackoffConfiguration configures how often the live head is
+// checked while waiting for the node to catch up (see
+// WithNodeBackoff).
+type BackoffConfiguration struct {
+	// RecheckInterval is the time.Duration between checks
+	// of the live head. The interval doubles (up to
+	// MaxRecheckInterval) each time the node has not caught
+	// up to any parked change. If not populated,
+	// defaultRecheckInterval is used.
+	RecheckInterval time.Duration `json:"recheck_interval,omitempty"`
+
+	// MaxRecheckInterval is the maximum time.Duration
+	// between checks of the live head. If not populated,
+	// defaultMaxRecheckInterval is used.
+	MaxRecheckInterval time.Duration `json:"max_recheck_interval,omitempty"`
+}
+
+// headScheduler holds changes until the
+// node reaches the block they were computed at.
+type headScheduler struct {
+	sync.Mutex
+
+	config   *BackoffConfiguration
+	parked   []*parser.BalanceChange
+	interval time.Duration
+
+	// accounts is the number of parked changes for
+	// each *types.AccountCurrency (keyed by types.Hash).
+	accounts map[string]int
+}
+
+func newHeadScheduler(config *BackoffConfiguration) *headScheduler {
+	if config.RecheckInterval <= 0 {
+		config.RecheckInterval = defaultRecheckInterval
+	}
+
+	if config.MaxRecheckInterval <= 0 {
+		config.MaxRecheckInterval = defaultMaxRecheckInterval
+	}
+
+	if config.MaxRecheckInterval < config.RecheckInterval {
+		config.MaxRecheckInterval = config.RecheckInterval
+	}
+
+	return &headScheduler{
+		config:   config,
+		parked:   []*parser.BalanceChange{},
+		interval: config.RecheckInterval,
+		accounts: map[string]int{},
+	}
+}
+
+// changeKey returns the key of the
+// *types.AccountCurrency of change.
+func changeKey(change *parser.BalanceChange) string {
+	return types.Hash(&types.AccountCurrency{
+		Account:  change.Account,
+		Currency: change.Currency,
+	})
+}
+
+// park holds change until the node reaches change.Block.
+func (h *headScheduler) park(change *parser.BalanceChange) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.parked = append(h.parked, change)
+	h.accounts[changeKey(change)]++
+}
+
+// parkedFor returns a boolean indicating if any change for
+// the *types.AccountCurrency of change is parked. Changes for
+// an account with parked changes must be parked behind them
+// so that they are not reconciled out of order.
+func (h *headScheduler) parkedFor(change *parser.BalanceChange) bool {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.accounts[changeKey(change)] > 0
+}
+
+// size returns the number of parked changes.
+func (h *headScheduler) size() int {
+	h.Lock()
+	defer h.Unlock()
+
+	return len(h.parked)
+}
+
+// ready returns all parked changes (in the order they were
+// parked) computed at or before liveIndex. The interval is
+// reset if any change is ready and doubled otherwise.
+func (h *headScheduler) ready(liveIndex int64) []*parser.BalanceChange {
+	h.Lock()
+	defer h.Unlock()
+
+	ready := []*parser.BalanceChange{}
+	parked := []*parser.BalanceChange{}
+	for _, change := range h.parked {
+		if change.Block.Index <= liveIndex {
+			ready = append(ready, change)
+
+			key := changeKey(change)
+			h.accounts[key]--
+			if h.accounts[key] == 0 {
+				delete(h.accounts, key)
+			}
+		} else {
+			parked = append(parked, change)
+		}
+	}
+	h.parked = parked
+
+	if len(ready) > 0 {
+		h.interval = h.config.RecheckInterval
+	} else {
+		h.backoff()
+	}
+
+	return ready
+}
+
+// backoff doubles the interval (up to MaxRecheckInterval).
+// The caller must hold the lock.
+func (h *headScheduler) backoff() {
+	h.interval *= 2
+	if h.interval > h.config.MaxRecheckInterval {
+		h.interval = h.config.MaxRecheckInterval
+	}
+}
+
+// nextInterval returns the time.Duration to wait before
+// checking the live head again.
+func (h *headScheduler) nextInterval() time.Duration {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.interval
+}
+
+// waitForNode waits for the recheck interval before
+// checking if the node has caught up. It returns early
+// with an error if ctx is canceled.
+func (r *Reconciler) waitForNode(ctx context.Context) error {
+	timer := time.NewTimer(r.scheduler.nextInterval())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// nodeBehind returns a boolean indicating if the node has
+// not yet reached index (always false if node backoff is
+// disabled or the Helper does not implement LiveHeadHelper).
+func (r *Reconciler) nodeBehind(ctx context.Context, index int64) bool {
+	if r.scheduler == nil {
+		return false
+	}
+
+	helper, ok := r.helper.(LiveHeadHelper)
+	if !ok {
+		return false
+	}
+
+	liveHead, err := helper.LiveHead(ctx)
+	if err != nil {
+		r.debugLog("unable to get live head: %s", err.Error())
+		return false
+	}
+
+	return liveHead.Index < index
+}
+
+// reconcileParkedChanges enqueues parked changes for active
+// reconciliation once the node reaches the block they were
+// computed at.
+func (r *Reconciler) reconcileParkedChanges(ctx context.Context) error {
+	helper, ok := r.helper.(LiveHeadHelper)
+	if !ok {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.scheduler.nextInterval()):
+		}
+
+		if r.scheduler.size() == 0 {
+			continue
+		}
+
+		liveHead, err := helper.LiveHead(ctx)
+		if err != nil {
+			r.debugLog("unable to get live head: %s", err.Error())
+			r.scheduler.Lock()
+			r.scheduler.backoff()
+			r.scheduler.Unlock()
+			continue
+		}
+
+		ready := r.scheduler.ready(liveHead.Index)
+		r.debugLog(
+			"node at block %d: enqueueing %d parked changes (%d still parked)",
+			liveHead.Index,
+			len(ready),
+			r.scheduler.size(),
+		)
+		for _, change := range ready {
+			r.wrappedActiveEnqueue(ctx, change)
+		}
+	}
+}
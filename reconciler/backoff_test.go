@@ -0,0 +1,202 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// liveHeadHelper is a *mocks.Helper that
+// implements LiveHeadHelper.
+type liveHeadHelper struct {
+	*mocks.Helper
+
+	sync.Mutex
+	head *types.BlockIdentifier
+}
+
+func (h *liveHeadHelper) setHead(index int64) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.head = &types.BlockIdentifier{
+		Hash:  "live head",
+		Index: index,
+	}
+}
+
+func (h *liveHeadHelper) LiveHead(ctx context.Context) (*types.BlockIdentifier, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.head, nil
+}
+
+func TestHeadScheduler(t *testing.T) {
+	h := newHeadScheduler(&BackoffConfiguration{
+		RecheckInterval:    1 * time.Second,
+		MaxRecheckInterval: 3 * time.Second,
+	})
+
+	changes := []*parser.BalanceChange{}
+	for _, index := range []int64{5, 7, 6} {
+		change := &parser.BalanceChange{
+			Block: &types.BlockIdentifier{Index: index},
+		}
+		changes = append(changes, change)
+		h.park(change)
+	}
+	assert.Equal(t, 3, h.size())
+
+	assert.Len(t, h.ready(4), 0)
+	assert.Equal(t, 2*time.Second, h.nextInterval())
+	assert.Len(t, h.ready(4), 0)
+	assert.Equal(t, 3*time.Second, h.nextInterval())
+
+	assert.Equal(t, []*parser.BalanceChange{changes[0], changes[2]}, h.ready(6))
+	assert.Equal(t, 1*time.Second, h.nextInterval())
+	assert.Equal(t, 1, h.size())
+
+	// Default intervals are used if not populated.
+	h = newHeadScheduler(&BackoffConfiguration{})
+	assert.Equal(t, defaultRecheckInterval, h.config.RecheckInterval)
+	assert.Equal(t, defaultMaxRecheckInterval, h.config.MaxRecheckInterval)
+}
+
+func TestReconcileChange_NodeBehind(t *testing.T) {
+	var (
+		ctx             = context.Background()
+		accountCurrency = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 1"},
+			Currency: btc,
+		}
+		change = &parser.BalanceChange{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block: &types.BlockIdentifier{
+				Hash:  "block 10",
+				Index: 10,
+			},
+		}
+	)
+
+	helper := &liveHeadHelper{Helper: &mocks.Helper{}}
+	helper.setHead(9)
+	r := New(
+		helper,
+		&mocks.Handler{},
+		nil,
+		WithLookupBalanceByBlock(),
+		WithNodeBackoff(&BackoffConfiguration{
+			RecheckInterval: 10 * time.Millisecond,
+		}),
+	)
+
+	helper.On(
+		"LiveBalance",
+		ctx,
+		accountCurrency.Account,
+		accountCurrency.Currency,
+		int64(10),
+	).Return(nil, nil, errors.New("block not found")).Once()
+
+	// The change is parked instead of failing reconciliation.
+	assert.NoError(t, r.reconcileChange(ctx, change))
+	assert.Equal(t, 1, r.QueueMetrics().ParkedDepth)
+	helper.AssertExpectations(t)
+
+	// Later changes for the same account are parked
+	// behind it (without looking up the live balance).
+	laterChange := &parser.BalanceChange{
+		Account:  accountCurrency.Account,
+		Currency: accountCurrency.Currency,
+		Block: &types.BlockIdentifier{
+			Hash:  "block 11",
+			Index: 11,
+		},
+	}
+	assert.NoError(t, r.reconcileChange(ctx, laterChange))
+	assert.Equal(t, 2, r.QueueMetrics().ParkedDepth)
+	helper.AssertExpectations(t)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	d := make(chan struct{})
+	go func() {
+		err := r.reconcileParkedChanges(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+		close(d)
+	}()
+
+	// The change is enqueued once the node catches up.
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, r.QueueSize())
+	helper.setHead(11)
+	for _, expected := range []*parser.BalanceChange{change, laterChange} {
+		select {
+		case enqueued := <-r.changeQueue:
+			assert.Equal(t, expected, enqueued)
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "parked change not enqueued")
+		}
+	}
+	assert.Equal(t, 0, r.QueueMetrics().ParkedDepth)
+	assert.False(t, r.scheduler.parkedFor(laterChange))
+
+	cancel()
+	<-d
+}
+
+func TestWaitForNode(t *testing.T) {
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithNodeBackoff(&BackoffConfiguration{
+			RecheckInterval: 1 * time.Hour,
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.Is(r.waitForNode(ctx), context.Canceled))
+}
+
+func TestNodeBehind_Unsupported(t *testing.T) {
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithNodeBackoff(&BackoffConfiguration{}),
+	)
+	assert.False(t, r.nodeBehind(context.Background(), 10))
+	assert.NoError(t, r.reconcileParkedChanges(context.Background()))
+
+	helper := &liveHeadHelper{Helper: &mocks.Helper{}}
+	helper.setHead(5)
+	r = New(helper, &mocks.Handler{}, nil)
+	assert.False(t, r.nodeBehind(context.Background(), 10))
+}
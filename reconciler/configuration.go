@@ -17,6 +17,7 @@ package reconciler
 import (
 	"fmt"
 
+	"github.com/coinbase/rosetta-sdk-go/parser"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -60,6 +61,7 @@ func WithSeenAccounts(seen []*types.AccountCurrency) Option {
 				Entry: acct,
 			})
 			r.seenAccounts[types.Hash(acct)] = struct{}{}
+			r.coverage.see(acct)
 		}
 
 		fmt.Printf(
@@ -110,3 +112,94 @@ func WithBacklogSize(size int) Option {
 		r.backlogSize = size
 	}
 }
+
+// WithActivePriority causes inactive reconciliation to yield to
+// active reconciliation whenever more than threshold changes are
+// waiting to be actively reconciled (so that tip-following
+// reconciliation does not fall behind on busy chains). Inactive
+// reconciliation never yields for longer than maxInactiveYield.
+func WithActivePriority(threshold int) Option {
+	return func(r *Reconciler) {
+		r.activePriority = threshold
+	}
+}
+
+// WithBalanceExemptions considers any balance difference covered
+// by exemptions (greater_or_equal, less_or_equal, or dynamic for a
+// particular currency and/or SubAccount address) exempt, in addition
+// to the *types.BalanceExemption provided to the *parser.Parser.
+// The exemptions should be validated with asserter.BalanceExemptions.
+func WithBalanceExemptions(exemptions []*types.BalanceExemption) Option {
+	return func(r *Reconciler) {
+		r.exemptionParser = parser.New(nil, nil, exemptions)
+	}
+}
+
+// WithTolerance considers any balance difference tolerated by
+// tolerance (and not covered by any *types.BalanceExemption) exempt
+// instead of a reconciliation failure (ex: DriftTolerance). The Handler
+// is invoked with a dynamic *types.BalanceExemption for the currency.
+func WithTolerance(tolerance ToleranceFunc) Option {
+	return func(r *Reconciler) {
+		r.tolerance = tolerance
+	}
+}
+
+// WithSpotCheck periodically reconciles a random previously seen
+// *types.AccountCurrency at a random historical block (see
+// SpotCheckConfiguration) and records the result in the
+// *SpotCheckReport. Spot checks require historical balance
+// lookup (WithLookupBalanceByBlock).
+func WithSpotCheck(config *SpotCheckConfiguration) Option {
+	return func(r *Reconciler) {
+		r.spotChecker = newSpotChecker(config)
+	}
+}
+
+// WithNodeBackoff parks any change that cannot be reconciled
+// because the node is behind the block at which it was computed
+// (and any inactive reconciliation at such a block) until the
+// node catches up, instead of failing reconciliation. The Helper
+// must implement LiveHeadHelper.
+func WithNodeBackoff(config *BackoffConfiguration) Option {
+	return func(r *Reconciler) {
+		r.scheduler = newHeadScheduler(config)
+	}
+}
+
+// WithResultSink publishes the *Result of every reconciliation
+// (success, failure, exemption, or skip) to sink (ex: a
+// *WebhookSink). Results are published asynchronously so that
+// a slow sink does not block reconciliation.
+func WithResultSink(sink ResultSink) Option {
+	return func(r *Reconciler) {
+		r.results = newResultEmitter(sink)
+	}
+}
+
+// WithFilter restricts reconciliation to the
+// *types.AccountCurrency matched by filter. Interesting
+// accounts are always reconciled.
+func WithFilter(filter *Filter) Option {
+	return func(r *Reconciler) {
+		r.filter = filter
+	}
+}
+
+// WithFailureHandler invokes handler to determine how
+// to proceed after each reconciliation failure (instead
+// of relying on the error returned by the Handler).
+func WithFailureHandler(handler FailureHandler) Option {
+	return func(r *Reconciler) {
+		r.failureHandler = handler
+	}
+}
+
+// WithQueue persists all work enqueued for reconciliation
+// in queue so that it can be recovered after a restart.
+// Any persisted work is loaded when Reconcile is called.
+func WithQueue(queue Queue) Option {
+	return func(r *Reconciler) {
+		r.queue = queue
+	}
+}
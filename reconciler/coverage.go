@@ -0,0 +1,338 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// CurrencyCoverage is the reconciliation
+// coverage of a single *types.Currency.
+type CurrencyCoverage struct {
+	Currency   *types.Currency `json:"currency"`
+	Seen       int64           `json:"seen"`
+	Reconciled int64           `json:"reconciled"`
+	Coverage   float64         `json:"coverage"`
+}
+
+// Coverage summarizes the reconciliations performed
+// by a Reconciler since it was started.
+type Coverage struct {
+	// Seen is the number of *types.AccountCurrency
+	// that have been seen (and will be reconciled).
+	Seen int64 `json:"seen"`
+
+	// Reconciled is the number of seen *types.AccountCurrency
+	// that were successfully reconciled (or exempt) at or
+	// after the provided minimum index.
+	Reconciled int64 `json:"reconciled"`
+
+	// Coverage is Reconciled / Seen.
+	Coverage float64 `json:"coverage"`
+
+	// Currencies is the coverage of each *types.Currency
+	// (sorted by symbol).
+	Currencies []*CurrencyCoverage `json:"currencies"`
+
+	// Successes, Exemptions, Failures, and Skipped
+	// are the number of reconciliations with each
+	// outcome.
+	Successes  int64 `json:"successes"`
+	Exemptions int64 `json:"exemptions"`
+	Failures   int64 `json:"failures"`
+	Skipped    int64 `json:"skipped"`
+
+	// HeadIndex is the index of the last synced
+	// block (-1 if no block has been synced).
+	HeadIndex int64 `json:"head_index"`
+
+	// LastIndexReconciled is the last block
+	// index reconciled actively.
+	LastIndexReconciled int64 `json:"last_index_reconciled"`
+
+	// Lag is the number of blocks between HeadIndex
+	// and LastIndexReconciled.
+	Lag int64 `json:"lag"`
+}
+
+// accountCoverage tracks the last index at which
+// a seen *types.AccountCurrency was reconciled.
+type accountCoverage struct {
+	currency       *types.Currency
+	lastReconciled int64
+}
+
+// coverageTracker records the outcome of
+// every reconciliation.
+type coverageTracker struct {
+	sync.Mutex
+
+	accounts map[string]*accountCoverage
+
+	successes  int64
+	exemptions int64
+	failures   int64
+	skipped    int64
+}
+
+func newCoverageTracker() *coverageTracker {
+	return &coverageTracker{
+		accounts: map[string]*accountCoverage{},
+	}
+}
+
+// track returns the *accountCoverage for key, adding
+// it to the tracked accounts if it is not already
+// tracked. The caller must hold the lock.
+func (c *coverageTracker) track(key string, currency *types.Currency) *accountCoverage {
+	account, ok := c.accounts[key]
+	if !ok {
+		account = &accountCoverage{
+			currency:       currency,
+			lastReconciled: -1,
+		}
+		c.accounts[key] = account
+	}
+
+	return account
+}
+
+// see adds accountCurrency to the tracked accounts.
+func (c *coverageTracker) see(accountCurrency *types.AccountCurrency) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.track(types.Hash(accountCurrency), accountCurrency.Currency)
+}
+
+// forget removes accountCurrency from
+// the tracked accounts.
+func (c *coverageTracker) forget(accountCurrency *types.AccountCurrency) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.accounts, types.Hash(accountCurrency))
+}
+
+// reconciled records a successful (or exempt)
+// reconciliation at block.
+func (c *coverageTracker) reconciled(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	block *types.BlockIdentifier,
+	exempt bool,
+) {
+	key := types.Hash(&types.AccountCurrency{
+		Account:  account,
+		Currency: currency,
+	})
+
+	c.Lock()
+	defer c.Unlock()
+
+	if exempt {
+		c.exemptions++
+	} else {
+		c.successes++
+	}
+
+	tracked := c.track(key, currency)
+	if block != nil && block.Index > tracked.lastReconciled {
+		tracked.lastReconciled = block.Index
+	}
+}
+
+// coverage computes the *Coverage of all tracked accounts
+// reconciled at or after minimumIndex.
+func (c *coverageTracker) coverage(minimumIndex int64) *Coverage {
+	c.Lock()
+	defer c.Unlock()
+
+	coverage := &Coverage{
+		Successes:  c.successes,
+		Exemptions: c.exemptions,
+		Failures:   c.failures,
+		Skipped:    c.skipped,
+		Currencies: []*CurrencyCoverage{},
+	}
+
+	currencies := map[string]*CurrencyCoverage{}
+	for _, account := range c.accounts {
+		key := types.Hash(account.currency)
+		currency, ok := currencies[key]
+		if !ok {
+			currency = &CurrencyCoverage{Currency: account.currency}
+			currencies[key] = currency
+			coverage.Currencies = append(coverage.Currencies, currency)
+		}
+
+		coverage.Seen++
+		currency.Seen++
+		if account.lastReconciled >= 0 && account.lastReconciled >= minimumIndex {
+			coverage.Reconciled++
+			currency.Reconciled++
+		}
+	}
+
+	if coverage.Seen > 0 {
+		coverage.Coverage = float64(coverage.Reconciled) / float64(coverage.Seen)
+	}
+
+	for _, currency := range coverage.Currencies {
+		currency.Coverage = float64(currency.Reconciled) / float64(currency.Seen)
+	}
+
+	sort.Slice(coverage.Currencies, func(i, j int) bool {
+		a, b := coverage.Currencies[i].Currency, coverage.Currencies[j].Currency
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+
+		return a.Decimals < b.Decimals
+	})
+
+	return coverage
+}
+
+// coverageHandler records the outcome of each
+// reconciliation before invoking the Handler.
+type coverageHandler struct {
+	Handler
+
+	tracker *coverageTracker
+}
+
+// ReconciliationFailed records a failure.
+func (c *coverageHandler) ReconciliationFailed(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+) error {
+	c.tracker.Lock()
+	c.tracker.failures++
+	c.tracker.Unlock()
+
+	return c.Handler.ReconciliationFailed(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		computedBalance,
+		liveBalance,
+		block,
+	)
+}
+
+// ReconciliationSucceeded records a success.
+func (c *coverageHandler) ReconciliationSucceeded(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	balance string,
+	block *types.BlockIdentifier,
+) error {
+	c.tracker.reconciled(account, currency, block, false)
+
+	return c.Handler.ReconciliationSucceeded(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		balance,
+		block,
+	)
+}
+
+// ReconciliationExempt records an exemption.
+func (c *coverageHandler) ReconciliationExempt(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+	exemption *types.BalanceExemption,
+) error {
+	c.tracker.reconciled(account, currency, block, true)
+
+	return c.Handler.ReconciliationExempt(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		computedBalance,
+		liveBalance,
+		block,
+		exemption,
+	)
+}
+
+// ReconciliationSkipped records a skipped reconciliation.
+func (c *coverageHandler) ReconciliationSkipped(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	cause string,
+) error {
+	c.tracker.Lock()
+	c.tracker.skipped++
+	c.tracker.Unlock()
+
+	return c.Handler.ReconciliationSkipped(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		cause,
+	)
+}
+
+// Coverage returns the *Coverage of all reconciliations
+// performed since the Reconciler was started. Only
+// reconciliations at or after minimumIndex are considered
+// when computing coverage (use -1 to consider all
+// reconciliations).
+func (r *Reconciler) Coverage(ctx context.Context, minimumIndex int64) *Coverage {
+	coverage := r.coverage.coverage(minimumIndex)
+	coverage.HeadIndex = -1
+	coverage.LastIndexReconciled = r.LastIndexReconciled()
+
+	dbTx := r.helper.DatabaseTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	// CurrentBlock returns an error
+	// if no block has been synced.
+	head, err := r.helper.CurrentBlock(ctx, dbTx)
+	if err == nil {
+		coverage.HeadIndex = head.Index
+	}
+
+	if coverage.HeadIndex > coverage.LastIndexReconciled {
+		coverage.Lag = coverage.HeadIndex - coverage.LastIndexReconciled
+	}
+
+	return coverage
+}
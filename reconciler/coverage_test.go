@@ -0,0 +1,172 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestCoverage(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		btc1 = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 1"},
+			Currency: btc,
+		}
+		btc2 = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 2"},
+			Currency: btc,
+		}
+		eth1 = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 1"},
+			Currency: eth,
+		}
+		block3 = &types.BlockIdentifier{Hash: "block 3", Index: 3}
+		block5 = &types.BlockIdentifier{Hash: "block 5", Index: 5}
+	)
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		nil,
+		WithSeenAccounts([]*types.AccountCurrency{btc1, btc2, eth1}),
+	)
+
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", ctx).Once()
+	mockHelper.On("DatabaseTransaction", ctx).Return(mtxn).Once()
+	mockHelper.On("CurrentBlock", ctx, mtxn).Return(nil, errors.New("no head")).Once()
+
+	t.Run("no reconciliations", func(t *testing.T) {
+		assert.Equal(t, &Coverage{
+			Seen: 3,
+			Currencies: []*CurrencyCoverage{
+				{Currency: btc, Seen: 2},
+				{Currency: eth, Seen: 1},
+			},
+			HeadIndex:           -1,
+			LastIndexReconciled: -1,
+		}, r.Coverage(ctx, -1))
+	})
+
+	mockHandler.On("ReconciliationSucceeded", ctx, ActiveReconciliation, btc1.Account, btc, "10", block5).
+		Return(nil).
+		Once()
+	mockHandler.On(
+		"ReconciliationExempt",
+		ctx,
+		InactiveReconciliation,
+		eth1.Account,
+		eth,
+		"10",
+		"11",
+		block3,
+		mock.Anything,
+	).Return(nil).Once()
+	mockHandler.On("ReconciliationFailed", ctx, ActiveReconciliation, btc2.Account, btc, "10", "11", block5).
+		Return(errors.New("failed")).
+		Once()
+	mockHandler.On("ReconciliationSkipped", ctx, ActiveReconciliation, btc2.Account, btc, BlockGone).
+		Return(nil).
+		Once()
+
+	assert.NoError(t, r.handler.ReconciliationSucceeded(
+		ctx,
+		ActiveReconciliation,
+		btc1.Account,
+		btc,
+		"10",
+		block5,
+	))
+	assert.NoError(t, r.handler.ReconciliationExempt(
+		ctx,
+		InactiveReconciliation,
+		eth1.Account,
+		eth,
+		"10",
+		"11",
+		block3,
+		&types.BalanceExemption{},
+	))
+	assert.Error(t, r.handler.ReconciliationFailed(
+		ctx,
+		ActiveReconciliation,
+		btc2.Account,
+		btc,
+		"10",
+		"11",
+		block5,
+	))
+	assert.NoError(t, r.handler.ReconciliationSkipped(
+		ctx,
+		ActiveReconciliation,
+		btc2.Account,
+		btc,
+		BlockGone,
+	))
+	r.updateLastChecked(block5.Index)
+
+	mtxn2 := &mockDatabase.Transaction{}
+	mtxn2.On("Discard", ctx).Twice()
+	mockHelper.On("DatabaseTransaction", ctx).Return(mtxn2).Twice()
+	mockHelper.On("CurrentBlock", ctx, mtxn2).Return(&types.BlockIdentifier{
+		Hash:  "block 8",
+		Index: 8,
+	}, nil).Twice()
+
+	t.Run("all reconciliations", func(t *testing.T) {
+		assert.Equal(t, &Coverage{
+			Seen:       3,
+			Reconciled: 2,
+			Coverage:   2.0 / 3.0,
+			Currencies: []*CurrencyCoverage{
+				{Currency: btc, Seen: 2, Reconciled: 1, Coverage: 0.5},
+				{Currency: eth, Seen: 1, Reconciled: 1, Coverage: 1},
+			},
+			Successes:           1,
+			Exemptions:          1,
+			Failures:            1,
+			Skipped:             1,
+			HeadIndex:           8,
+			LastIndexReconciled: 5,
+			Lag:                 3,
+		}, r.Coverage(ctx, -1))
+	})
+
+	t.Run("minimum index", func(t *testing.T) {
+		coverage := r.Coverage(ctx, 4)
+		assert.Equal(t, int64(1), coverage.Reconciled)
+		assert.Equal(t, []*CurrencyCoverage{
+			{Currency: btc, Seen: 2, Reconciled: 1, Coverage: 0.5},
+			{Currency: eth, Seen: 1},
+		}, coverage.Currencies)
+	})
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mtxn.AssertExpectations(t)
+	mtxn2.AssertExpectations(t)
+}
@@ -38,6 +38,32 @@ var (
 	ErrBlockExistsFailed        = errors.New("unable to check if block exists")
 	ErrGetComputedBalanceFailed = errors.New("unable to get computed balance")
 	ErrLiveBalanceLookupFailed  = errors.New("unable to lookup live balance")
+	ErrQueueLoadFailed          = errors.New("unable to load reconciliation queue")
+	ErrQueueUpdateFailed        = errors.New("unable to update reconciliation queue")
+
+	// ErrFilterInvalid is returned when a *FilterConfiguration
+	// contains an invalid pattern or sub account class.
+	ErrFilterInvalid = errors.New("invalid reconciliation filter")
+
+	// ErrReconciliationHalted is returned when a
+	// FailureHandler returns HaltVerdict.
+	ErrReconciliationHalted = errors.New("reconciliation halted")
+
+	ErrFailureHandlerFailed  = errors.New("unable to handle reconciliation failure")
+	ErrFailureVerdictInvalid = errors.New("invalid reconciliation failure verdict")
+
+	// ErrSpotCheckUnsupported is returned when spot checks
+	// are enabled without historical balance lookup.
+	ErrSpotCheckUnsupported = errors.New("spot checks require historical balance lookup")
+
+	// ErrPriorityInvalid is returned when a priority
+	// weight is not positive.
+	ErrPriorityInvalid = errors.New("invalid reconciliation priority")
+
+	// ErrWebhookFailed is returned when a WebhookSink
+	// cannot publish a reconciliation result (including
+	// when the webhook responds with a non-2xx status).
+	ErrWebhookFailed = errors.New("unable to publish reconciliation result to webhook")
 )
 
 // Err takes an error as an argument and returns
@@ -50,6 +76,15 @@ func Err(err error) bool {
 		ErrBlockExistsFailed,
 		ErrGetComputedBalanceFailed,
 		ErrLiveBalanceLookupFailed,
+		ErrQueueLoadFailed,
+		ErrQueueUpdateFailed,
+		ErrFilterInvalid,
+		ErrReconciliationHalted,
+		ErrFailureHandlerFailed,
+		ErrFailureVerdictInvalid,
+		ErrSpotCheckUnsupported,
+		ErrPriorityInvalid,
+		ErrWebhookFailed,
 	}
 
 	return utils.FindError(reconcilerErrors, err)
@@ -0,0 +1,119 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// ToleranceFunc determines if a balance difference (live - computed)
+// for a *types.AccountIdentifier and *types.Currency that is not
+// covered by any *types.BalanceExemption should be tolerated
+// instead of considered a reconciliation failure.
+type ToleranceFunc func(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	difference *big.Int,
+) bool
+
+// DriftTolerance returns a ToleranceFunc that tolerates any
+// difference with an absolute value of at most maxDrift base
+// units (ex: to allow for rounding in fee calculations).
+func DriftTolerance(maxDrift *big.Int) ToleranceFunc {
+	return func(
+		account *types.AccountIdentifier,
+		currency *types.Currency,
+		difference *big.Int,
+	) bool {
+		return new(big.Int).Abs(difference).Cmp(maxDrift) <= 0
+	}
+}
+
+// CurrencyDriftTolerance returns a ToleranceFunc that tolerates
+// a difference with an absolute value of at most the base units
+// specified for the *types.Currency in maxDrift (keyed by
+// types.Hash(currency)). Differences in any other currency are
+// not tolerated.
+func CurrencyDriftTolerance(maxDrift map[string]*big.Int) ToleranceFunc {
+	return func(
+		account *types.AccountIdentifier,
+		currency *types.Currency,
+		difference *big.Int,
+	) bool {
+		drift, ok := maxDrift[types.Hash(currency)]
+		if !ok {
+			return false
+		}
+
+		return new(big.Int).Abs(difference).Cmp(drift) <= 0
+	}
+}
+
+// findExemption returns the *types.BalanceExemption (provided to
+// the *parser.Parser, with WithBalanceExemptions, or returned by
+// the FailureHandler with ExemptVerdict) that covers difference
+// (live - computed), if it exists.
+//
+// If no *types.BalanceExemption applies but the difference is
+// tolerated by the ToleranceFunc, a dynamic *types.BalanceExemption
+// is returned for the *types.Currency (and SubAccount, if populated).
+func (r *Reconciler) findExemption(
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	difference string,
+) *types.BalanceExemption {
+	matches := []*types.BalanceExemption{}
+	if r.parser != nil {
+		matches = append(matches, r.parser.FindExemptions(account, currency)...)
+	}
+
+	if r.exemptionParser != nil {
+		matches = append(matches, r.exemptionParser.FindExemptions(account, currency)...)
+	}
+
+	if exemption := r.verdictExemption(&types.AccountCurrency{
+		Account:  account,
+		Currency: currency,
+	}); exemption != nil {
+		matches = append(matches, exemption)
+	}
+
+	// Check if the reconciliation was exempt (supports compound exemptions)
+	if exemption := parser.MatchBalanceExemption(matches, difference); exemption != nil {
+		return exemption
+	}
+
+	if r.tolerance == nil {
+		return nil
+	}
+
+	bigDifference, ok := new(big.Int).SetString(difference, 10)
+	if !ok || !r.tolerance(account, currency, bigDifference) {
+		return nil
+	}
+
+	exemption := &types.BalanceExemption{
+		Currency:      currency,
+		ExemptionType: types.BalanceDynamic,
+	}
+	if account.SubAccount != nil {
+		exemption.SubAccountAddress = &account.SubAccount.Address
+	}
+
+	return exemption
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestFindExemption(t *testing.T) {
+	var (
+		subAccountAddress = "staking"
+		account           = &types.AccountIdentifier{
+			Address: "addr 1",
+		}
+		subAccount = &types.AccountIdentifier{
+			Address: "addr 1",
+			SubAccount: &types.SubAccountIdentifier{
+				Address: subAccountAddress,
+			},
+		}
+		parserExemption = &types.BalanceExemption{
+			Currency:      eth,
+			ExemptionType: types.BalanceDynamic,
+		}
+		greaterExemption = &types.BalanceExemption{
+			Currency:      btc,
+			ExemptionType: types.BalanceGreaterOrEqual,
+		}
+		lessExemption = &types.BalanceExemption{
+			SubAccountAddress: &subAccountAddress,
+			ExemptionType:     types.BalanceLessOrEqual,
+		}
+	)
+
+	var tests = map[string]struct {
+		parser  *parser.Parser
+		options []Option
+
+		account    *types.AccountIdentifier
+		currency   *types.Currency
+		difference string
+
+		exemption *types.BalanceExemption
+	}{
+		"no exemptions": {
+			account:    account,
+			currency:   btc,
+			difference: "10",
+		},
+		"parser exemption": {
+			parser:     parser.New(nil, nil, []*types.BalanceExemption{parserExemption}),
+			account:    account,
+			currency:   eth,
+			difference: "-10",
+			exemption:  parserExemption,
+		},
+		"greater or equal": {
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{greaterExemption}),
+			},
+			account:    account,
+			currency:   btc,
+			difference: "10",
+			exemption:  greaterExemption,
+		},
+		"greater or equal (negative difference)": {
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{greaterExemption}),
+			},
+			account:    account,
+			currency:   btc,
+			difference: "-10",
+		},
+		"greater or equal (other currency)": {
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{greaterExemption}),
+			},
+			account:    account,
+			currency:   eth,
+			difference: "10",
+		},
+		"less or equal (sub account)": {
+			parser: parser.New(nil, nil, []*types.BalanceExemption{parserExemption}),
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{
+					greaterExemption,
+					lessExemption,
+				}),
+			},
+			account:    subAccount,
+			currency:   btc,
+			difference: "-10",
+			exemption:  lessExemption,
+		},
+		"less or equal (no sub account)": {
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{lessExemption}),
+			},
+			account:    account,
+			currency:   btc,
+			difference: "-10",
+		},
+		"tolerated": {
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{greaterExemption}),
+				WithTolerance(DriftTolerance(big.NewInt(10))),
+			},
+			account:    subAccount,
+			currency:   btc,
+			difference: "-10",
+			exemption: &types.BalanceExemption{
+				Currency:          btc,
+				SubAccountAddress: &subAccountAddress,
+				ExemptionType:     types.BalanceDynamic,
+			},
+		},
+		"not tolerated": {
+			options: []Option{
+				WithTolerance(DriftTolerance(big.NewInt(10))),
+			},
+			account:    account,
+			currency:   btc,
+			difference: "-11",
+		},
+		"exemption before tolerance": {
+			options: []Option{
+				WithBalanceExemptions([]*types.BalanceExemption{greaterExemption}),
+				WithTolerance(DriftTolerance(big.NewInt(10))),
+			},
+			account:    account,
+			currency:   btc,
+			difference: "5",
+			exemption:  greaterExemption,
+		},
+		"invalid difference": {
+			options: []Option{
+				WithTolerance(DriftTolerance(big.NewInt(10))),
+			},
+			account:    account,
+			currency:   btc,
+			difference: "hello",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := New(nil, nil, test.parser, test.options...)
+			assert.Equal(
+				t,
+				test.exemption,
+				r.findExemption(test.account, test.currency, test.difference),
+			)
+		})
+	}
+}
+
+func TestCurrencyDriftTolerance(t *testing.T) {
+	tolerance := CurrencyDriftTolerance(map[string]*big.Int{
+		types.Hash(btc): big.NewInt(100),
+	})
+	account := &types.AccountIdentifier{Address: "addr 1"}
+
+	assert.True(t, tolerance(account, btc, big.NewInt(100)))
+	assert.True(t, tolerance(account, btc, big.NewInt(-100)))
+	assert.False(t, tolerance(account, btc, big.NewInt(101)))
+	assert.False(t, tolerance(account, eth, big.NewInt(1)))
+}
+
+func TestHandleBalanceMismatch_Tolerance(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		account = &types.AccountIdentifier{
+			Address: "addr 1",
+		}
+		block = &types.BlockIdentifier{
+			Hash:  "block 10",
+			Index: 10,
+		}
+	)
+
+	mockHandler := &mocks.Handler{}
+	r := New(
+		nil,
+		mockHandler,
+		nil,
+		WithTolerance(DriftTolerance(big.NewInt(1))),
+	)
+
+	mockHandler.On(
+		"ReconciliationExempt",
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		"101",
+		block,
+		&types.BalanceExemption{
+			Currency:      btc,
+			ExemptionType: types.BalanceDynamic,
+		},
+	).Return(nil).Once()
+	assert.NoError(t, r.handleBalanceMismatch(
+		ctx,
+		"1",
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		"101",
+		block,
+		nil,
+	))
+
+	mockHandler.On(
+		"ReconciliationFailed",
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		"102",
+		block,
+	).Return(ErrReconciliationHalted).Once()
+	assert.ErrorIs(t, r.handleBalanceMismatch(
+		ctx,
+		"2",
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		"102",
+		block,
+		nil,
+	), ErrReconciliationHalted)
+
+	mockHandler.AssertExpectations(t)
+}
@@ -0,0 +1,291 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// FailureVerdict is returned by a FailureHandler to
+// determine how the Reconciler should proceed after
+// a reconciliation failure.
+type FailureVerdict string
+
+const (
+	// HaltVerdict stops reconciliation (Reconcile
+	// returns ErrReconciliationHalted).
+	HaltVerdict FailureVerdict = "HALT"
+
+	// SkipVerdict ignores the failure and continues
+	// reconciliation (the Handler is invoked with
+	// FailureSkipped).
+	SkipVerdict FailureVerdict = "SKIP"
+
+	// RetryVerdict reconciles the account again once the
+	// block at FailureDecision.RetryIndex has been processed
+	// (the Handler is invoked with FailureRetry).
+	RetryVerdict FailureVerdict = "RETRY"
+
+	// ExemptVerdict considers the failure exempt (the Handler
+	// is invoked with FailureDecision.Exemption). The exemption
+	// is applied to all later reconciliations of the account.
+	ExemptVerdict FailureVerdict = "EXEMPT"
+)
+
+const (
+	// FailureSkipped is the skip cause when a
+	// FailureHandler returns SkipVerdict.
+	FailureSkipped = "FAILURE_SKIPPED"
+
+	// FailureRetry is the skip cause when a
+	// FailureHandler returns RetryVerdict.
+	FailureRetry = "FAILURE_RETRY"
+)
+
+// Failure is the full context of a reconciliation
+// failure provided to a FailureHandler.
+type Failure struct {
+	ReconciliationType string                   `json:"reconciliation_type"`
+	Account            *types.AccountIdentifier `json:"account_identifier"`
+	Currency           *types.Currency          `json:"currency"`
+	ComputedBalance    string                   `json:"computed_balance"`
+	LiveBalance        string                   `json:"live_balance"`
+
+	// Difference is LiveBalance - ComputedBalance.
+	Difference string `json:"difference"`
+
+	// Block is the block at which the balances
+	// were compared.
+	Block *types.BlockIdentifier `json:"block_identifier"`
+
+	// Change is the balance change that caused the
+	// account to be reconciled. It is nil for inactive
+	// reconciliation.
+	Change *parser.BalanceChange `json:"balance_change,omitempty"`
+
+	// Attempt is the number of times the account has
+	// failed reconciliation and been retried (0 on the
+	// first failure).
+	Attempt int `json:"attempt"`
+}
+
+// FailureDecision is returned by a FailureHandler
+// to resolve a reconciliation failure.
+type FailureDecision struct {
+	Verdict FailureVerdict `json:"verdict"`
+
+	// Reason is included in the error returned
+	// on HaltVerdict.
+	Reason string `json:"reason,omitempty"`
+
+	// RetryIndex is the block index at which to retry
+	// reconciliation when Verdict is RetryVerdict. If
+	// RetryIndex is not greater than the index of the
+	// failed block, the next block is used.
+	RetryIndex int64 `json:"retry_index,omitempty"`
+
+	// Exemption is provided to the Handler when Verdict
+	// is ExemptVerdict. If Exemption is nil, a dynamic
+	// *types.BalanceExemption for the currency is used.
+	Exemption *types.BalanceExemption `json:"exemption,omitempty"`
+}
+
+// FailureHandler is invoked by the Reconciler when a
+// reconciliation fails (and no balance exemption applies)
+// to allow for programmatic triage. When a FailureHandler
+// is not provided (see WithFailureHandler) or returns a nil
+// *FailureDecision, the error returned by
+// Handler.ReconciliationFailed determines if reconciliation
+// halts.
+type FailureHandler interface {
+	ReconciliationFailure(
+		ctx context.Context,
+		failure *Failure,
+	) (*FailureDecision, error)
+}
+
+// pendingRetry is an account that will be reconciled
+// again once the block at index is processed.
+type pendingRetry struct {
+	accountCurrency *types.AccountCurrency
+	index           int64
+}
+
+// handleFailure invokes the FailureHandler and
+// acts on the returned *FailureDecision.
+func (r *Reconciler) handleFailure(
+	ctx context.Context,
+	failure *Failure,
+) error {
+	decision, err := r.failureHandler.ReconciliationFailure(ctx, failure)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFailureHandlerFailed, err)
+	}
+
+	if decision == nil {
+		return r.handler.ReconciliationFailed(
+			ctx,
+			failure.ReconciliationType,
+			failure.Account,
+			failure.Currency,
+			failure.ComputedBalance,
+			failure.LiveBalance,
+			failure.Block,
+		)
+	}
+
+	accountCurrency := &types.AccountCurrency{
+		Account:  failure.Account,
+		Currency: failure.Currency,
+	}
+
+	switch decision.Verdict {
+	case HaltVerdict:
+		if err := r.handler.ReconciliationFailed(
+			ctx,
+			failure.ReconciliationType,
+			failure.Account,
+			failure.Currency,
+			failure.ComputedBalance,
+			failure.LiveBalance,
+			failure.Block,
+		); err != nil {
+			return err
+		}
+
+		return fmt.Errorf(
+			"%w: %s for %s",
+			ErrReconciliationHalted,
+			decision.Reason,
+			types.PrintStruct(accountCurrency),
+		)
+	case SkipVerdict:
+		r.clearRetries(accountCurrency)
+
+		return r.handler.ReconciliationSkipped(
+			ctx,
+			failure.ReconciliationType,
+			failure.Account,
+			failure.Currency,
+			FailureSkipped,
+		)
+	case RetryVerdict:
+		retryIndex := decision.RetryIndex
+		if retryIndex <= failure.Block.Index {
+			retryIndex = failure.Block.Index + 1
+		}
+
+		r.retryMutex.Lock()
+		r.pendingRetries = append(r.pendingRetries, &pendingRetry{
+			accountCurrency: accountCurrency,
+			index:           retryIndex,
+		})
+		r.retryAttempts[types.Hash(accountCurrency)] = failure.Attempt + 1
+		r.retryMutex.Unlock()
+
+		return r.handler.ReconciliationSkipped(
+			ctx,
+			failure.ReconciliationType,
+			failure.Account,
+			failure.Currency,
+			FailureRetry,
+		)
+	case ExemptVerdict:
+		exemption := decision.Exemption
+		if exemption == nil {
+			exemption = &types.BalanceExemption{
+				Currency:      failure.Currency,
+				ExemptionType: types.BalanceDynamic,
+			}
+		}
+
+		r.clearRetries(accountCurrency)
+		r.retryMutex.Lock()
+		r.verdictExemptions[types.Hash(accountCurrency)] = exemption
+		r.retryMutex.Unlock()
+
+		return r.handler.ReconciliationExempt(
+			ctx,
+			failure.ReconciliationType,
+			failure.Account,
+			failure.Currency,
+			failure.ComputedBalance,
+			failure.LiveBalance,
+			failure.Block,
+			exemption,
+		)
+	default:
+		return fmt.Errorf("%w: %s", ErrFailureVerdictInvalid, decision.Verdict)
+	}
+}
+
+// retryAttempt returns the number of times reconciliation
+// of accountCurrency has been retried since it last
+// succeeded.
+func (r *Reconciler) retryAttempt(accountCurrency *types.AccountCurrency) int {
+	r.retryMutex.Lock()
+	defer r.retryMutex.Unlock()
+
+	return r.retryAttempts[types.Hash(accountCurrency)]
+}
+
+// clearRetries resets the retry attempts
+// of accountCurrency.
+func (r *Reconciler) clearRetries(accountCurrency *types.AccountCurrency) {
+	r.retryMutex.Lock()
+	defer r.retryMutex.Unlock()
+
+	if len(r.retryAttempts) == 0 {
+		return
+	}
+
+	delete(r.retryAttempts, types.Hash(accountCurrency))
+}
+
+// verdictExemption returns the *types.BalanceExemption
+// returned with ExemptVerdict for accountCurrency, if any.
+func (r *Reconciler) verdictExemption(
+	accountCurrency *types.AccountCurrency,
+) *types.BalanceExemption {
+	r.retryMutex.Lock()
+	defer r.retryMutex.Unlock()
+
+	return r.verdictExemptions[types.Hash(accountCurrency)]
+}
+
+// dueRetries returns (and removes) all pending
+// retries that should be performed at index.
+func (r *Reconciler) dueRetries(index int64) []*types.AccountCurrency {
+	r.retryMutex.Lock()
+	defer r.retryMutex.Unlock()
+
+	due := []*types.AccountCurrency{}
+	remaining := []*pendingRetry{}
+	for _, retry := range r.pendingRetries {
+		if retry.index > index {
+			remaining = append(remaining, retry)
+			continue
+		}
+
+		due = append(due, retry.accountCurrency)
+	}
+	r.pendingRetries = remaining
+
+	return due
+}
@@ -0,0 +1,279 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// staticFailureHandler returns the same
+// *FailureDecision for every failure.
+type staticFailureHandler struct {
+	decision *FailureDecision
+	err      error
+
+	failures []*Failure
+}
+
+func (s *staticFailureHandler) ReconciliationFailure(
+	ctx context.Context,
+	failure *Failure,
+) (*FailureDecision, error) {
+	s.failures = append(s.failures, failure)
+	return s.decision, s.err
+}
+
+func TestFailureHandler(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		block = &types.BlockIdentifier{
+			Hash:  "block 10",
+			Index: 10,
+		}
+		account = &types.AccountIdentifier{
+			Address: "addr 1",
+		}
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+		change = &parser.BalanceChange{
+			Account:    account,
+			Currency:   currency,
+			Block:      block,
+			Difference: "10",
+		}
+		exemption = &types.BalanceExemption{
+			Currency:      currency,
+			ExemptionType: types.BalanceDynamic,
+		}
+	)
+
+	mismatch := func(r *Reconciler) error {
+		return r.handleBalanceMismatch(
+			ctx,
+			"-10",
+			ActiveReconciliation,
+			account,
+			currency,
+			"100",
+			"90",
+			block,
+			change,
+		)
+	}
+
+	newReconciler := func(failureHandler FailureHandler) (*Reconciler, *mocks.Handler) {
+		mockHandler := &mocks.Handler{}
+		r := New(
+			nil,
+			mockHandler,
+			parser.New(nil, nil, nil),
+			WithFailureHandler(failureHandler),
+		)
+
+		return r, mockHandler
+	}
+
+	t.Run("halt", func(t *testing.T) {
+		failureHandler := &staticFailureHandler{
+			decision: &FailureDecision{Verdict: HaltVerdict, Reason: "supply mismatch"},
+		}
+		r, mockHandler := newReconciler(failureHandler)
+		mockHandler.On(
+			"ReconciliationFailed",
+			ctx,
+			ActiveReconciliation,
+			account,
+			currency,
+			"100",
+			"90",
+			block,
+		).Return(nil).Once()
+
+		err := mismatch(r)
+		assert.True(t, errors.Is(err, ErrReconciliationHalted))
+		assert.Contains(t, err.Error(), "supply mismatch")
+		assert.Equal(t, []*Failure{
+			{
+				ReconciliationType: ActiveReconciliation,
+				Account:            account,
+				Currency:           currency,
+				ComputedBalance:    "100",
+				LiveBalance:        "90",
+				Difference:         "-10",
+				Block:              block,
+				Change:             change,
+			},
+		}, failureHandler.failures)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		r, mockHandler := newReconciler(&staticFailureHandler{
+			decision: &FailureDecision{Verdict: SkipVerdict},
+		})
+		mockHandler.On(
+			"ReconciliationSkipped",
+			ctx,
+			ActiveReconciliation,
+			account,
+			currency,
+			FailureSkipped,
+		).Return(nil).Once()
+
+		assert.NoError(t, mismatch(r))
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("exempt", func(t *testing.T) {
+		failureHandler := &staticFailureHandler{
+			decision: &FailureDecision{Verdict: ExemptVerdict, Exemption: exemption},
+		}
+		r, mockHandler := newReconciler(failureHandler)
+		mockHandler.On(
+			"ReconciliationExempt",
+			ctx,
+			ActiveReconciliation,
+			account,
+			currency,
+			"100",
+			"90",
+			block,
+			exemption,
+		).Return(nil).Twice()
+
+		assert.NoError(t, mismatch(r))
+
+		// The exemption applies to later reconciliations
+		// without invoking the FailureHandler.
+		assert.NoError(t, mismatch(r))
+		assert.Len(t, failureHandler.failures, 1)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("exempt without exemption", func(t *testing.T) {
+		r, mockHandler := newReconciler(&staticFailureHandler{
+			decision: &FailureDecision{Verdict: ExemptVerdict},
+		})
+		mockHandler.On(
+			"ReconciliationExempt",
+			ctx,
+			ActiveReconciliation,
+			account,
+			currency,
+			"100",
+			"90",
+			block,
+			exemption,
+		).Return(nil).Once()
+
+		assert.NoError(t, mismatch(r))
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("nil decision", func(t *testing.T) {
+		r, mockHandler := newReconciler(&staticFailureHandler{})
+		mockHandler.On(
+			"ReconciliationFailed",
+			ctx,
+			ActiveReconciliation,
+			account,
+			currency,
+			"100",
+			"90",
+			block,
+		).Return(errors.New("reconciliation failed")).Once()
+
+		err := mismatch(r)
+		assert.EqualError(t, err, "reconciliation failed")
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		failureHandler := &staticFailureHandler{
+			decision: &FailureDecision{Verdict: RetryVerdict, RetryIndex: 12},
+		}
+		r, mockHandler := newReconciler(failureHandler)
+		mockHandler.On(
+			"ReconciliationSkipped",
+			ctx,
+			ActiveReconciliation,
+			account,
+			currency,
+			FailureRetry,
+		).Return(nil).Twice()
+
+		assert.NoError(t, mismatch(r))
+
+		// Retry is not due until block 12
+		block11 := &types.BlockIdentifier{Hash: "block 11", Index: 11}
+		assert.NoError(t, r.queueChanges(ctx, block11, []*parser.BalanceChange{}))
+		assert.Equal(t, 0, r.QueueSize())
+
+		block12 := &types.BlockIdentifier{Hash: "block 12", Index: 12}
+		assert.NoError(t, r.queueChanges(ctx, block12, []*parser.BalanceChange{}))
+		assert.Equal(t, 1, r.QueueSize())
+		assert.Equal(t, &parser.BalanceChange{
+			Account:    account,
+			Currency:   currency,
+			Block:      block12,
+			Difference: zeroString,
+		}, <-r.changeQueue)
+
+		// Attempts are tracked across retries
+		// and reset on success.
+		assert.NoError(t, mismatch(r))
+		assert.Equal(t, 0, failureHandler.failures[0].Attempt)
+		assert.Equal(t, 1, failureHandler.failures[1].Attempt)
+		assert.Equal(t, 2, r.retryAttempt(&types.AccountCurrency{
+			Account:  account,
+			Currency: currency,
+		}))
+
+		r.clearRetries(&types.AccountCurrency{Account: account, Currency: currency})
+		assert.Equal(t, 0, r.retryAttempt(&types.AccountCurrency{
+			Account:  account,
+			Currency: currency,
+		}))
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("invalid verdict", func(t *testing.T) {
+		r, mockHandler := newReconciler(&staticFailureHandler{
+			decision: &FailureDecision{Verdict: "IGNORE"},
+		})
+
+		assert.True(t, errors.Is(mismatch(r), ErrFailureVerdictInvalid))
+		mockHandler.AssertNotCalled(t, "ReconciliationFailed", mock.Anything)
+	})
+
+	t.Run("failure handler error", func(t *testing.T) {
+		r, _ := newReconciler(&staticFailureHandler{
+			err: errors.New("triage unavailable"),
+		})
+
+		assert.True(t, errors.Is(mismatch(r), ErrFailureHandlerFailed))
+	})
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// SubAccountClass restricts reconciliation by the
+// presence of a *types.SubAccountIdentifier.
+type SubAccountClass string
+
+const (
+	// AnySubAccount reconciles accounts with
+	// or without a SubAccount.
+	AnySubAccount SubAccountClass = ""
+
+	// NoSubAccount only reconciles accounts
+	// without a SubAccount.
+	NoSubAccount SubAccountClass = "none"
+
+	// SubAccountOnly only reconciles accounts
+	// with a SubAccount.
+	SubAccountOnly SubAccountClass = "only"
+)
+
+// FilterConfiguration describes the *types.AccountCurrency
+// that should be reconciled. All fields are optional and
+// an *types.AccountCurrency must satisfy every populated
+// field to be reconciled.
+type FilterConfiguration struct {
+	// Currencies restricts reconciliation to accounts
+	// holding any of these currencies.
+	Currencies []*types.Currency `json:"currencies,omitempty"`
+
+	// AddressPrefixes and AddressPatterns (regular expressions)
+	// restrict reconciliation to accounts with an address that
+	// has any of the prefixes or matches any of the patterns.
+	AddressPrefixes []string `json:"address_prefixes,omitempty"`
+	AddressPatterns []string `json:"address_patterns,omitempty"`
+
+	// SubAccountClass restricts reconciliation to accounts
+	// with or without a SubAccount.
+	SubAccountClass SubAccountClass `json:"sub_account_class,omitempty"`
+
+	// SubAccountPatterns (regular expressions) restrict
+	// reconciliation of accounts with a SubAccount to those
+	// with a SubAccount address that matches any of the
+	// patterns. Accounts without a SubAccount are not
+	// affected.
+	SubAccountPatterns []string `json:"sub_account_patterns,omitempty"`
+
+	// Exclude is never reconciled (ex: accounts with known
+	// balance issues). If the Currency of an excluded
+	// *types.AccountCurrency is nil, the account is excluded
+	// for all currencies.
+	Exclude []*types.AccountCurrency `json:"exclude,omitempty"`
+}
+
+// Filter determines which *types.AccountCurrency
+// are reconciled (see WithFilter).
+type Filter struct {
+	currencies         map[string]struct{}
+	addressPrefixes    []string
+	addressPatterns    []*regexp.Regexp
+	subAccountClass    SubAccountClass
+	subAccountPatterns []*regexp.Regexp
+	excluded           map[string]struct{}
+}
+
+// compilePatterns compiles a slice of regular expressions.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrFilterInvalid, pattern, err)
+		}
+
+		compiled[i] = re
+	}
+
+	return compiled, nil
+}
+
+// matchesAny returns a boolean indicating if s
+// matches any of the provided patterns.
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewFilter returns a new *Filter constructed
+// from a *FilterConfiguration.
+func NewFilter(config *FilterConfiguration) (*Filter, error) {
+	f := &Filter{
+		currencies:      map[string]struct{}{},
+		addressPrefixes: config.AddressPrefixes,
+		subAccountClass: config.SubAccountClass,
+		excluded:        map[string]struct{}{},
+	}
+
+	switch config.SubAccountClass {
+	case AnySubAccount, NoSubAccount, SubAccountOnly:
+	default:
+		return nil, fmt.Errorf(
+			"%w: sub account class %s",
+			ErrFilterInvalid,
+			config.SubAccountClass,
+		)
+	}
+
+	for _, currency := range config.Currencies {
+		f.currencies[types.Hash(currency)] = struct{}{}
+	}
+
+	var err error
+	f.addressPatterns, err = compilePatterns(config.AddressPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	f.subAccountPatterns, err = compilePatterns(config.SubAccountPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, excluded := range config.Exclude {
+		if excluded == nil || excluded.Account == nil {
+			return nil, fmt.Errorf("%w: excluded account is nil", ErrFilterInvalid)
+		}
+
+		f.excluded[types.Hash(excluded)] = struct{}{}
+	}
+
+	return f, nil
+}
+
+// Match returns a boolean indicating if
+// accountCurrency should be reconciled.
+func (f *Filter) Match(accountCurrency *types.AccountCurrency) bool {
+	if len(f.currencies) > 0 {
+		if _, ok := f.currencies[types.Hash(accountCurrency.Currency)]; !ok {
+			return false
+		}
+	}
+
+	account := accountCurrency.Account
+	if len(f.addressPrefixes) > 0 || len(f.addressPatterns) > 0 {
+		matched := matchesAny(f.addressPatterns, account.Address)
+		for _, prefix := range f.addressPrefixes {
+			if matched {
+				break
+			}
+
+			matched = strings.HasPrefix(account.Address, prefix)
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	switch {
+	case f.subAccountClass == NoSubAccount && account.SubAccount != nil:
+		return false
+	case f.subAccountClass == SubAccountOnly && account.SubAccount == nil:
+		return false
+	case account.SubAccount != nil && len(f.subAccountPatterns) > 0 &&
+		!matchesAny(f.subAccountPatterns, account.SubAccount.Address):
+		return false
+	}
+
+	if len(f.excluded) > 0 {
+		if _, ok := f.excluded[types.Hash(accountCurrency)]; ok {
+			return false
+		}
+
+		if _, ok := f.excluded[types.Hash(&types.AccountCurrency{Account: account})]; ok {
+			return false
+		}
+	}
+
+	return true
+}
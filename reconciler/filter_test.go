@@ -0,0 +1,226 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	btc = &types.Currency{Symbol: "BTC", Decimals: 8}
+	eth = &types.Currency{Symbol: "ETH", Decimals: 18}
+)
+
+func TestFilter(t *testing.T) {
+	var tests = map[string]struct {
+		config *FilterConfiguration
+		err    error
+
+		matches    []*types.AccountCurrency
+		nonMatches []*types.AccountCurrency
+	}{
+		"empty filter": {
+			config: &FilterConfiguration{},
+			matches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "addr"}, Currency: btc},
+				{
+					Account: &types.AccountIdentifier{
+						Address:    "addr",
+						SubAccount: &types.SubAccountIdentifier{Address: "stake"},
+					},
+					Currency: eth,
+				},
+			},
+		},
+		"currencies": {
+			config: &FilterConfiguration{Currencies: []*types.Currency{btc}},
+			matches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "addr"}, Currency: btc},
+			},
+			nonMatches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "addr"}, Currency: eth},
+			},
+		},
+		"address prefixes and patterns": {
+			config: &FilterConfiguration{
+				AddressPrefixes: []string{"cosmos1"},
+				AddressPatterns: []string{"^0x[0-9a-f]{4}$"},
+			},
+			matches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "cosmos1abc"}, Currency: btc},
+				{Account: &types.AccountIdentifier{Address: "0xab12"}, Currency: btc},
+			},
+			nonMatches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "cosmos2abc"}, Currency: btc},
+				{Account: &types.AccountIdentifier{Address: "0xab123"}, Currency: btc},
+			},
+		},
+		"no sub accounts": {
+			config: &FilterConfiguration{SubAccountClass: NoSubAccount},
+			matches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "addr"}, Currency: btc},
+			},
+			nonMatches: []*types.AccountCurrency{
+				{
+					Account: &types.AccountIdentifier{
+						Address:    "addr",
+						SubAccount: &types.SubAccountIdentifier{Address: "stake"},
+					},
+					Currency: btc,
+				},
+			},
+		},
+		"sub account patterns": {
+			config: &FilterConfiguration{
+				SubAccountClass:    SubAccountOnly,
+				SubAccountPatterns: []string{"^stake"},
+			},
+			matches: []*types.AccountCurrency{
+				{
+					Account: &types.AccountIdentifier{
+						Address:    "addr",
+						SubAccount: &types.SubAccountIdentifier{Address: "staked"},
+					},
+					Currency: btc,
+				},
+			},
+			nonMatches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "addr"}, Currency: btc},
+				{
+					Account: &types.AccountIdentifier{
+						Address:    "addr",
+						SubAccount: &types.SubAccountIdentifier{Address: "locked"},
+					},
+					Currency: btc,
+				},
+			},
+		},
+		"exclude": {
+			config: &FilterConfiguration{
+				Exclude: []*types.AccountCurrency{
+					{Account: &types.AccountIdentifier{Address: "burn"}},
+					{Account: &types.AccountIdentifier{Address: "fees"}, Currency: eth},
+				},
+			},
+			matches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "fees"}, Currency: btc},
+			},
+			nonMatches: []*types.AccountCurrency{
+				{Account: &types.AccountIdentifier{Address: "burn"}, Currency: btc},
+				{Account: &types.AccountIdentifier{Address: "burn"}, Currency: eth},
+				{Account: &types.AccountIdentifier{Address: "fees"}, Currency: eth},
+			},
+		},
+		"invalid pattern": {
+			config: &FilterConfiguration{AddressPatterns: []string{"("}},
+			err:    ErrFilterInvalid,
+		},
+		"invalid sub account class": {
+			config: &FilterConfiguration{SubAccountClass: "some"},
+			err:    ErrFilterInvalid,
+		},
+		"invalid exclusion": {
+			config: &FilterConfiguration{Exclude: []*types.AccountCurrency{{Currency: btc}}},
+			err:    ErrFilterInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, err := NewFilter(test.config)
+			if test.err != nil {
+				assert.Nil(t, filter)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			for _, accountCurrency := range test.matches {
+				assert.True(t, filter.Match(accountCurrency), types.PrintStruct(accountCurrency))
+			}
+
+			for _, accountCurrency := range test.nonMatches {
+				assert.False(t, filter.Match(accountCurrency), types.PrintStruct(accountCurrency))
+			}
+		})
+	}
+}
+
+func TestQueueChanges_Filter(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		btcAccount = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 1"},
+			Currency: btc,
+		}
+		ethAccount = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 2"},
+			Currency: eth,
+		}
+		interestingAccount = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 3"},
+			Currency: eth,
+		}
+	)
+
+	filter, err := NewFilter(&FilterConfiguration{
+		Currencies: []*types.Currency{btc},
+	})
+	assert.NoError(t, err)
+
+	r := New(
+		nil,
+		nil,
+		nil,
+		WithFilter(filter),
+		WithSeenAccounts([]*types.AccountCurrency{btcAccount, ethAccount}),
+		WithInterestingAccounts([]*types.AccountCurrency{interestingAccount}),
+	)
+
+	// Seen accounts are filtered
+	assert.Equal(t, []*InactiveEntry{{Entry: btcAccount}}, r.inactiveQueue)
+	assert.Equal(t, map[string]struct{}{types.Hash(btcAccount): {}}, r.seenAccounts)
+
+	err = r.queueChanges(context.Background(), block, []*parser.BalanceChange{
+		{
+			Account:  btcAccount.Account,
+			Currency: btcAccount.Currency,
+			Block:    block,
+		},
+		{
+			Account:  ethAccount.Account,
+			Currency: ethAccount.Currency,
+			Block:    block,
+		},
+	})
+	assert.NoError(t, err)
+
+	// Interesting accounts are always reconciled
+	assert.Equal(t, 2, r.QueueSize())
+	assert.Equal(t, btcAccount.Account, (<-r.changeQueue).Account)
+	assert.Equal(t, interestingAccount.Account, (<-r.changeQueue).Account)
+	assert.False(t, ContainsAccountCurrency(r.seenAccounts, ethAccount))
+	assert.True(t, ContainsAccountCurrency(r.seenAccounts, interestingAccount))
+}
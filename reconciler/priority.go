@@ -0,0 +1,154 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// DefaultPriority is the priority weight of any
+// *types.AccountCurrency without a priority set
+// with SetPriority.
+const DefaultPriority = 1.0
+
+// EnqueueAccount enqueues accountCurrency for inactive
+// reconciliation at the current head before any other
+// account (regardless of when it was last reconciled).
+// If accountCurrency has not been seen before, it is
+// added to the inactive reconciliation queue after it is
+// reconciled. Manually enqueued accounts are not persisted
+// by the Queue.
+func (r *Reconciler) EnqueueAccount(accountCurrency *types.AccountCurrency) {
+	r.inactiveQueueMutex.Lock(true)
+	defer r.inactiveQueueMutex.Unlock()
+
+	r.manualQueue = append(r.manualQueue, &InactiveEntry{
+		Entry: accountCurrency,
+	})
+}
+
+// SetPriority sets the priority weight of accountCurrency.
+// Accounts are inactively reconciled every inactive frequency
+// (see WithInactiveFrequency) divided by weight blocks (so an
+// account with a weight of 10 is reconciled 10 times as often
+// as an account with the DefaultPriority and an account with
+// a weight of math.Inf(1) is reconciled at every block).
+func (r *Reconciler) SetPriority(
+	accountCurrency *types.AccountCurrency,
+	weight float64,
+) error {
+	if weight <= 0 || math.IsNaN(weight) {
+		return fmt.Errorf("%w: %f", ErrPriorityInvalid, weight)
+	}
+
+	r.inactiveQueueMutex.Lock(true)
+	defer r.inactiveQueueMutex.Unlock()
+
+	key := types.Hash(accountCurrency)
+	if weight == DefaultPriority {
+		delete(r.priorities, key)
+	} else {
+		r.priorities[key] = weight
+	}
+
+	// Reposition accountCurrency in the inactive reconciliation
+	// queue so the new priority takes effect immediately.
+	for i, entry := range r.inactiveQueue {
+		if types.Hash(entry.Entry) != key {
+			continue
+		}
+
+		r.inactiveQueue = append(r.inactiveQueue[:i], r.inactiveQueue[i+1:]...)
+		r.insertInactiveEntry(entry)
+		break
+	}
+
+	return nil
+}
+
+// inactiveFrequencyFor returns the number of blocks between inactive
+// reconciliations of accountCurrency. The caller must hold
+// inactiveQueueMutex.
+func (r *Reconciler) inactiveFrequencyFor(accountCurrency *types.AccountCurrency) int64 {
+	weight, ok := r.priorities[types.Hash(accountCurrency)]
+	if !ok {
+		return r.inactiveFrequency
+	}
+
+	frequency := int64(math.Ceil(float64(r.inactiveFrequency) / weight))
+	if frequency < 1 {
+		return 1
+	}
+
+	return frequency
+}
+
+// nextInactiveIndex returns the first index at which entry
+// should be inactively reconciled (-1 if it has never been
+// checked). The caller must hold inactiveQueueMutex.
+func (r *Reconciler) nextInactiveIndex(entry *InactiveEntry) int64 {
+	if entry.LastCheck == nil { // block is set to nil when loaded from previous run
+		return -1
+	}
+
+	return entry.LastCheck.Index + r.inactiveFrequencyFor(entry.Entry)
+}
+
+// insertInactiveEntry adds entry to the inactive reconciliation
+// queue. If any priorities are set, entry is placed according to
+// the index at which it should next be reconciled (instead of at
+// the end of the queue). The caller must hold inactiveQueueMutex.
+func (r *Reconciler) insertInactiveEntry(entry *InactiveEntry) {
+	if len(r.priorities) == 0 {
+		r.inactiveQueue = append(r.inactiveQueue, entry)
+		return
+	}
+
+	// The queue is not necessarily ordered (entries are appended
+	// in the order they were enqueued while no priorities are set),
+	// so entry is inserted before the first entry that should be
+	// reconciled after it instead of using a binary search.
+	next := r.nextInactiveIndex(entry)
+	i := len(r.inactiveQueue)
+	for j, queued := range r.inactiveQueue {
+		if r.nextInactiveIndex(queued) > next {
+			i = j
+			break
+		}
+	}
+
+	r.inactiveQueue = append(r.inactiveQueue, nil)
+	copy(r.inactiveQueue[i+1:], r.inactiveQueue[i:])
+	r.inactiveQueue[i] = entry
+}
+
+// nextInactiveEntry returns the next *InactiveEntry to
+// consider for inactive reconciliation and whether it was
+// manually enqueued (see EnqueueAccount). The caller must
+// hold inactiveQueueMutex.
+func (r *Reconciler) nextInactiveEntry() (*InactiveEntry, bool) {
+	if len(r.manualQueue) > 0 {
+		return r.manualQueue[0], true
+	}
+
+	if len(r.inactiveQueue) > 0 {
+		return r.inactiveQueue[0], false
+	}
+
+	return nil, false
+}
@@ -0,0 +1,220 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestSetPriority(t *testing.T) {
+	var (
+		accountCurrency = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 1"},
+			Currency: btc,
+		}
+		accountCurrency2 = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 2"},
+			Currency: btc,
+		}
+		accountCurrency3 = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 3"},
+			Currency: eth,
+		}
+	)
+
+	r := New(
+		nil,
+		nil,
+		nil,
+		WithInactiveFrequency(100),
+		WithSeenAccounts([]*types.AccountCurrency{
+			accountCurrency,
+			accountCurrency2,
+			accountCurrency3,
+		}),
+	)
+	for i, entry := range r.inactiveQueue {
+		entry.LastCheck = &types.BlockIdentifier{
+			Hash:  "block",
+			Index: int64(10 * (i + 1)),
+		}
+	}
+
+	for _, weight := range []float64{0, -1, math.NaN()} {
+		assert.True(t, errors.Is(r.SetPriority(accountCurrency, weight), ErrPriorityInvalid))
+	}
+
+	assert.Equal(t, int64(100), r.inactiveFrequencyFor(accountCurrency))
+
+	// Low priority accounts are moved to the back of the queue
+	// (next index 10 + 200).
+	assert.NoError(t, r.SetPriority(accountCurrency, 0.5))
+	assert.Equal(t, int64(200), r.inactiveFrequencyFor(accountCurrency))
+	assert.Equal(t, accountCurrency2, r.inactiveQueue[0].Entry)
+	assert.Equal(t, accountCurrency3, r.inactiveQueue[1].Entry)
+	assert.Equal(t, accountCurrency, r.inactiveQueue[2].Entry)
+
+	// High priority accounts are moved to the front of
+	// the queue (next index 30 + 1).
+	assert.NoError(t, r.SetPriority(accountCurrency3, math.Inf(1)))
+	assert.Equal(t, int64(1), r.inactiveFrequencyFor(accountCurrency3))
+	assert.Equal(t, accountCurrency3, r.inactiveQueue[0].Entry)
+	assert.Equal(t, accountCurrency2, r.inactiveQueue[1].Entry)
+	assert.Equal(t, accountCurrency, r.inactiveQueue[2].Entry)
+
+	assert.NoError(t, r.SetPriority(accountCurrency2, 10))
+	assert.Equal(t, int64(10), r.inactiveFrequencyFor(accountCurrency2))
+	assert.Equal(t, accountCurrency2, r.inactiveQueue[0].Entry)
+
+	// Resetting the priority removes it.
+	assert.NoError(t, r.SetPriority(accountCurrency2, DefaultPriority))
+	assert.NoError(t, r.SetPriority(accountCurrency3, DefaultPriority))
+	assert.NoError(t, r.SetPriority(accountCurrency, DefaultPriority))
+	assert.Len(t, r.priorities, 0)
+	assert.Len(t, r.inactiveQueue, 3)
+}
+
+func TestSetPriority_UnorderedQueue(t *testing.T) {
+	accountCurrencies := []*types.AccountCurrency{}
+	for _, address := range []string{"addr 1", "addr 2", "addr 3", "addr 4"} {
+		accountCurrencies = append(accountCurrencies, &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: address},
+			Currency: btc,
+		})
+	}
+
+	r := New(
+		nil,
+		nil,
+		nil,
+		WithInactiveFrequency(100),
+		WithSeenAccounts(accountCurrencies),
+	)
+
+	// The queue is not ordered by next index
+	// (130, 110, 120, 165).
+	for i, index := range []int64{30, 10, 20, 65} {
+		r.inactiveQueue[i].LastCheck = &types.BlockIdentifier{
+			Hash:  "block",
+			Index: index,
+		}
+	}
+
+	// The next index of addr 4 is 115, so it is placed before
+	// the first account that should be reconciled after it.
+	assert.NoError(t, r.SetPriority(accountCurrencies[3], 2))
+	assert.Equal(t, accountCurrencies[3], r.inactiveQueue[0].Entry)
+	assert.Equal(t, accountCurrencies[0], r.inactiveQueue[1].Entry)
+	assert.Equal(t, accountCurrencies[1], r.inactiveQueue[2].Entry)
+	assert.Equal(t, accountCurrencies[2], r.inactiveQueue[3].Entry)
+}
+
+func TestReconcile_EnqueueAccount(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 10",
+			Index: 10,
+		}
+		accountCurrency = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 1"},
+			Currency: btc,
+		}
+		accountCurrency2 = &types.AccountCurrency{
+			Account:  &types.AccountIdentifier{Address: "addr 2"},
+			Currency: btc,
+		}
+	)
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		nil,
+		WithActiveConcurrency(0),
+		WithInactiveConcurrency(1),
+		WithLookupBalanceByBlock(),
+		WithSeenAccounts([]*types.AccountCurrency{accountCurrency}),
+	)
+
+	// accountCurrency is not due for inactive reconciliation.
+	r.inactiveQueue[0].LastCheck = block
+
+	r.EnqueueAccount(accountCurrency2)
+	assert.Equal(t, 1, r.QueueMetrics().ManualDepth)
+	entry, manual := r.nextInactiveEntry()
+	assert.Equal(t, accountCurrency2, entry.Entry)
+	assert.True(t, manual)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn).Once()
+	mockHelper.On("CurrentBlock", mock.Anything, mtxn).Return(block, nil).Once()
+
+	mtxn2 := &mockDatabase.Transaction{}
+	mtxn2.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn2).Once()
+	mockReconcilerCallsDelay(
+		mockHelper,
+		mockHandler,
+		accountCurrency2,
+		"100",
+		block,
+		block,
+		0,
+		"",
+	)
+	mockHandler.On(
+		"ReconciliationSucceeded",
+		mock.Anything,
+		InactiveReconciliation,
+		accountCurrency2.Account,
+		accountCurrency2.Currency,
+		"100",
+		block,
+	).Return(nil).Run(
+		func(args mock.Arguments) {
+			cancel()
+		},
+	).Once()
+
+	err := r.Reconcile(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// accountCurrency2 was not seen before so it is
+	// added to the inactive queue.
+	metrics := r.QueueMetrics()
+	assert.Equal(t, 0, metrics.ManualDepth)
+	assert.Equal(t, 2, metrics.InactiveDepth)
+	assert.True(t, ContainsAccountCurrency(r.seenAccounts, accountCurrency2))
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mtxn.AssertExpectations(t)
+	mtxn2.AssertExpectations(t)
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -36,9 +37,11 @@ func New(
 	p *parser.Parser,
 	options ...Option,
 ) *Reconciler {
+	coverage := newCoverageTracker()
 	r := &Reconciler{
 		helper:              helper,
-		handler:             handler,
+		handler:             &coverageHandler{Handler: handler, tracker: coverage},
+		coverage:            coverage,
 		parser:              p,
 		inactiveFrequency:   defaultInactiveFrequency,
 		ActiveConcurrency:   defaultReconcilerConcurrency,
@@ -50,12 +53,38 @@ func New(
 		backlogSize:         defaultBacklogSize,
 		lastIndexChecked:    -1,
 		processQueue:        make(chan *blockRequest, processQueueBacklog),
+		activeEnqueued:      map[*parser.BalanceChange]time.Time{},
+		retryAttempts:       map[string]int{},
+		verdictExemptions:   map[string]*types.BalanceExemption{},
+		activeAccounts:      map[string][]*parser.BalanceChange{},
+		priorities:          map[string]float64{},
+		activeWork:          make(chan *activeWork),
 	}
 
 	for _, opt := range options {
 		opt(r)
 	}
 
+	if r.results != nil {
+		r.handler = &resultHandler{Handler: r.handler, emitter: r.results}
+	}
+
+	// Remove any seen accounts that should
+	// not be reconciled.
+	if r.filter != nil {
+		inactiveQueue := []*InactiveEntry{}
+		for _, entry := range r.inactiveQueue {
+			if !r.filter.Match(entry.Entry) {
+				delete(r.seenAccounts, types.Hash(entry.Entry))
+				r.coverage.forget(entry.Entry)
+				continue
+			}
+
+			inactiveQueue = append(inactiveQueue, entry)
+		}
+		r.inactiveQueue = inactiveQueue
+	}
+
 	// Create change queue
 	r.changeQueue = make(chan *parser.BalanceChange, r.backlogSize)
 
@@ -80,31 +109,72 @@ func (r *Reconciler) wrappedActiveEnqueue(
 	ctx context.Context,
 	change *parser.BalanceChange,
 ) {
+	r.activeEnqueuedMutex.Lock()
+	r.activeEnqueued[change] = time.Now()
+	r.activeEnqueuedMutex.Unlock()
+
 	select {
 	case r.changeQueue <- change:
 	default:
-		r.debugLog(
-			"skipping active enqueue because backlog has %d items",
-			r.backlogSize,
-		)
+		r.skipBacklogFull(ctx, change)
+	}
+}
 
-		if err := r.handler.ReconciliationSkipped(
-			ctx,
-			ActiveReconciliation,
-			change.Account,
-			change.Currency,
-			BacklogFull,
-		); err != nil {
-			log.Printf("%s: reconciliation skipped handling failed\n", err.Error())
-		}
+// skipBacklogFull skips active reconciliation of
+// change because the backlog is full.
+func (r *Reconciler) skipBacklogFull(ctx context.Context, change *parser.BalanceChange) {
+	r.activeDequeued(change)
+	r.debugLog(
+		"skipping active enqueue because backlog has %d items",
+		r.backlogSize,
+	)
+
+	if err := r.removeChange(ctx, change); err != nil {
+		log.Printf("%s: unable to remove skipped change\n", err.Error())
 	}
+
+	if err := r.handler.ReconciliationSkipped(
+		ctx,
+		ActiveReconciliation,
+		change.Account,
+		change.Currency,
+		BacklogFull,
+	); err != nil {
+		log.Printf("%s: reconciliation skipped handling failed\n", err.Error())
+	}
+}
+
+// activeDequeued stops tracking the enqueue time of
+// a change that is no longer in the changeQueue.
+func (r *Reconciler) activeDequeued(change *parser.BalanceChange) {
+	r.activeEnqueuedMutex.Lock()
+	delete(r.activeEnqueued, change)
+	r.activeEnqueuedMutex.Unlock()
+}
+
+// removeChange removes a change that is no longer
+// enqueued from the Queue (if configured).
+func (r *Reconciler) removeChange(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) error {
+	if r.queue == nil {
+		return nil
+	}
+
+	if err := r.queue.RemoveChange(ctx, change); err != nil {
+		return fmt.Errorf("%w: %v", ErrQueueUpdateFailed, err)
+	}
+
+	return nil
 }
 
 func (r *Reconciler) wrappedInactiveEnqueue(
+	ctx context.Context,
 	accountCurrency *types.AccountCurrency,
 	liveBlock *types.BlockIdentifier,
 ) {
-	if err := r.inactiveAccountQueue(true, accountCurrency, liveBlock, false); err != nil {
+	if err := r.inactiveAccountQueue(ctx, true, accountCurrency, liveBlock, false); err != nil {
 		log.Printf(
 			"%s: unable to queue account %s",
 			err.Error(),
@@ -113,6 +183,26 @@ func (r *Reconciler) wrappedInactiveEnqueue(
 	}
 }
 
+// requeueInactiveEntry ensures an *InactiveEntry that could
+// not be reconciled is not leaked. Manually enqueued entries
+// are enqueued again in the manualQueue (instead of the
+// inactive queue) to avoid duplicate inactive entries.
+func (r *Reconciler) requeueInactiveEntry(
+	ctx context.Context,
+	entry *InactiveEntry,
+	manual bool,
+	liveBlock *types.BlockIdentifier,
+) {
+	if !manual {
+		r.wrappedInactiveEnqueue(ctx, entry.Entry, liveBlock)
+		return
+	}
+
+	r.inactiveQueueMutex.Lock(false)
+	r.manualQueue = append(r.manualQueue, entry)
+	r.inactiveQueueMutex.Unlock()
+}
+
 // addToQueueMap adds a *types.AccountCurrency
 // to the prune map at the provided index.
 func (r *Reconciler) addToQueueMap(
@@ -173,8 +263,28 @@ func (r *Reconciler) queueChanges(
 	block *types.BlockIdentifier,
 	balanceChanges []*parser.BalanceChange,
 ) error {
-	// Ensure all interestingAccounts are checked
-	for _, account := range r.interestingAccounts {
+	// Skip any changes that should not be reconciled
+	// before adding interesting accounts (which
+	// are always reconciled).
+	if r.filter != nil {
+		filtered := []*parser.BalanceChange{}
+		for _, change := range balanceChanges {
+			if r.filter.Match(&types.AccountCurrency{
+				Account:  change.Account,
+				Currency: change.Currency,
+			}) {
+				filtered = append(filtered, change)
+			}
+		}
+		balanceChanges = filtered
+	}
+
+	// Ensure all interestingAccounts and any accounts
+	// with a retry due at this block are checked
+	accounts := make([]*types.AccountCurrency, len(r.interestingAccounts))
+	copy(accounts, r.interestingAccounts)
+	accounts = append(accounts, r.dueRetries(block.Index)...)
+	for _, account := range accounts {
 		skipAccount := false
 		// Look through balance changes for account + currency
 		for _, change := range balanceChanges {
@@ -201,6 +311,7 @@ func (r *Reconciler) queueChanges(
 		})
 	}
 
+	activeChanges := []*parser.BalanceChange{}
 	for _, change := range balanceChanges {
 		// Add all seen accounts to inactive reconciler queue.
 		//
@@ -216,7 +327,7 @@ func (r *Reconciler) queueChanges(
 		}
 
 		r.inactiveQueueMutex.Lock(true)
-		err := r.inactiveAccountQueue(false, acctCurrency, block, true)
+		err := r.inactiveAccountQueue(ctx, false, acctCurrency, block, true)
 		r.inactiveQueueMutex.Unlock()
 		if err != nil {
 			return err
@@ -245,10 +356,103 @@ func (r *Reconciler) queueChanges(
 		r.addToQueueMap(m, key, change.Block.Index)
 		r.queueMap.Unlock(key)
 
-		// Add change to active queue
+		activeChanges = append(activeChanges, change)
+	}
+
+	// Persist changes before enqueuing to ensure
+	// they are not lost if we restart before they
+	// are reconciled.
+	if r.queue != nil && len(activeChanges) > 0 {
+		if err := r.queue.AddChanges(ctx, activeChanges); err != nil {
+			return fmt.Errorf("%w: %v", ErrQueueUpdateFailed, err)
+		}
+	}
+
+	// Add changes to active queue
+	for _, change := range activeChanges {
+		r.wrappedActiveEnqueue(ctx, change)
+	}
+
+	return nil
+}
+
+// loadQueue enqueues all work persisted in the
+// Queue (if configured) before a restart.
+func (r *Reconciler) loadQueue(ctx context.Context) error {
+	if r.queue == nil {
+		return nil
+	}
+
+	changes, entries, err := r.queue.LoadQueue(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrQueueLoadFailed, err)
+	}
+
+	r.inactiveQueueMutex.Lock(true)
+	queued := make(map[string]*InactiveEntry, len(r.inactiveQueue))
+	for _, entry := range r.inactiveQueue {
+		queued[types.Hash(entry.Entry)] = entry
+	}
+
+	for _, entry := range entries {
+		if r.filter != nil && !r.filter.Match(entry.Entry) {
+			continue
+		}
+
+		key := types.Hash(entry.Entry)
+		if existing, ok := queued[key]; ok {
+			existing.LastCheck = entry.LastCheck
+			continue
+		}
+
+		r.seenAccounts[key] = struct{}{}
+		r.coverage.see(entry.Entry)
+		r.inactiveQueue = append(r.inactiveQueue, entry)
+		queued[key] = entry
+	}
+
+	// Accounts that were checked least recently
+	// should be reconciled first.
+	sort.SliceStable(r.inactiveQueue, func(i, j int) bool {
+		a, b := r.inactiveQueue[i].LastCheck, r.inactiveQueue[j].LastCheck
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+
+		return a.Index < b.Index
+	})
+	r.inactiveQueueMutex.Unlock()
+
+	for _, change := range changes {
+		accountCurrency := &types.AccountCurrency{
+			Account:  change.Account,
+			Currency: change.Currency,
+		}
+
+		// The filter may have changed since
+		// the change was enqueued.
+		if r.filter != nil && !r.filter.Match(accountCurrency) {
+			if err := r.removeChange(ctx, change); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		key := types.Hash(accountCurrency)
+		m := r.queueMap.Lock(key, true)
+		r.addToQueueMap(m, key, change.Block.Index)
+		r.queueMap.Unlock(key)
+
 		r.wrappedActiveEnqueue(ctx, change)
 	}
 
+	log.Printf(
+		"Loaded %d changes and %d inactive accounts from reconciliation queue\n",
+		len(changes),
+		len(entries),
+	)
+
 	return nil
 }
 
@@ -256,7 +460,50 @@ func (r *Reconciler) queueChanges(
 // number of items currently enqueued for active
 // reconciliation.
 func (r *Reconciler) QueueSize() int {
-	return len(r.changeQueue)
+	r.activeMutex.Lock()
+	defer r.activeMutex.Unlock()
+
+	return len(r.changeQueue) + r.activeHeld
+}
+
+// QueueMetrics returns the depth and age of the
+// active and inactive reconciliation queues.
+func (r *Reconciler) QueueMetrics() *QueueMetrics {
+	metrics := &QueueMetrics{
+		ActiveDepth:         r.QueueSize(),
+		OldestInactiveIndex: -1,
+	}
+
+	if r.scheduler != nil {
+		metrics.ParkedDepth = r.scheduler.size()
+	}
+
+	now := time.Now()
+	r.activeEnqueuedMutex.Lock()
+	for _, enqueued := range r.activeEnqueued {
+		if age := now.Sub(enqueued); age > metrics.OldestActiveAge {
+			metrics.OldestActiveAge = age
+		}
+	}
+	r.activeEnqueuedMutex.Unlock()
+
+	r.inactiveQueueMutex.Lock(false)
+	defer r.inactiveQueueMutex.Unlock()
+
+	metrics.InactiveDepth = len(r.inactiveQueue)
+	metrics.ManualDepth = len(r.manualQueue)
+	for _, entry := range r.inactiveQueue {
+		if entry.LastCheck == nil {
+			metrics.NeverChecked++
+			continue
+		}
+
+		if metrics.OldestInactiveIndex == -1 || entry.LastCheck.Index < metrics.OldestInactiveIndex {
+			metrics.OldestInactiveIndex = entry.LastCheck.Index
+		}
+	}
+
+	return metrics
 }
 
 // LastIndexReconciled is the last block index
@@ -407,12 +654,9 @@ func (r *Reconciler) handleBalanceMismatch(
 	computedBalance string,
 	liveBalance string,
 	block *types.BlockIdentifier,
+	change *parser.BalanceChange,
 ) error {
-	// Check if the reconciliation was exempt (supports compound exemptions)
-	exemption := parser.MatchBalanceExemption(
-		r.parser.FindExemptions(account, currency),
-		difference,
-	)
+	exemption := r.findExemption(account, currency, difference)
 	if exemption != nil {
 		// Return handler result (regardless if error) so that we don't invoke the handler for
 		// a failed reconciliation as well.
@@ -428,6 +672,23 @@ func (r *Reconciler) handleBalanceMismatch(
 		)
 	}
 
+	if r.failureHandler != nil {
+		return r.handleFailure(ctx, &Failure{
+			ReconciliationType: reconciliationType,
+			Account:            account,
+			Currency:           currency,
+			ComputedBalance:    computedBalance,
+			LiveBalance:        liveBalance,
+			Difference:         difference,
+			Block:              block,
+			Change:             change,
+			Attempt: r.retryAttempt(&types.AccountCurrency{
+				Account:  account,
+				Currency: currency,
+			}),
+		})
+	}
+
 	// If we didn't find a matching exemption,
 	// we should consider the reconciliation
 	// a failure.
@@ -457,6 +718,7 @@ func (r *Reconciler) accountReconciliation(
 	liveAmount string,
 	liveBlock *types.BlockIdentifier,
 	inactive bool,
+	change *parser.BalanceChange,
 ) error {
 	accountCurrency := &types.AccountCurrency{
 		Account:  account,
@@ -561,9 +823,11 @@ func (r *Reconciler) accountReconciliation(
 				computedBalance,
 				liveAmount,
 				liveBlock,
+				change,
 			)
 		}
 
+		r.clearRetries(accountCurrency)
 		return r.handler.ReconciliationSucceeded(
 			ctx,
 			reconciliationType,
@@ -578,6 +842,7 @@ func (r *Reconciler) accountReconciliation(
 }
 
 func (r *Reconciler) inactiveAccountQueue(
+	ctx context.Context,
 	inactive bool,
 	accountCurrency *types.AccountCurrency,
 	liveBlock *types.BlockIdentifier,
@@ -592,14 +857,24 @@ func (r *Reconciler) inactiveAccountQueue(
 	shouldEnqueueInactive := false
 	if !inactive && !ContainsAccountCurrency(r.seenAccounts, accountCurrency) {
 		r.seenAccounts[types.Hash(accountCurrency)] = struct{}{}
+		r.coverage.see(accountCurrency)
 		shouldEnqueueInactive = true
 	}
 
-	if inactive || shouldEnqueueInactive {
-		r.inactiveQueue = append(r.inactiveQueue, &InactiveEntry{
-			Entry:     accountCurrency,
-			LastCheck: liveBlock,
-		})
+	if !inactive && !shouldEnqueueInactive {
+		return nil
+	}
+
+	entry := &InactiveEntry{
+		Entry:     accountCurrency,
+		LastCheck: liveBlock,
+	}
+	r.insertInactiveEntry(entry)
+
+	if r.queue != nil {
+		if err := r.queue.SetInactiveEntry(ctx, entry); err != nil {
+			return fmt.Errorf("%w: %v", ErrQueueUpdateFailed, err)
+		}
 	}
 
 	return nil
@@ -657,7 +932,7 @@ func (r *Reconciler) skipAndPrune(
 		return err
 	}
 
-	return r.updateQueueMap(
+	if err := r.updateQueueMap(
 		ctx,
 		&types.AccountCurrency{
 			Account:  change.Account,
@@ -665,7 +940,11 @@ func (r *Reconciler) skipAndPrune(
 		},
 		change.Block.Index,
 		pruneActiveReconciliation,
-	)
+	); err != nil {
+		return err
+	}
+
+	return r.removeChange(ctx, change)
 }
 
 // updateQueueMap removes a *parser.BalanceChange
@@ -716,93 +995,233 @@ func (r *Reconciler) updateQueueMap(
 	return r.pruneBalances(ctx, acctCurrency, index)
 }
 
-// reconcileActiveAccounts selects an account
-// from the Reconciler account queue and
-// reconciles the balance. This is useful
-// for detecting if balance changes in operations
-// were correct.
-func (r *Reconciler) reconcileActiveAccounts(ctx context.Context) error { // nolint:gocognit
+// activeWork is a change dispatched to
+// an active reconciliation worker.
+type activeWork struct {
+	key    string
+	change *parser.BalanceChange
+}
+
+// dispatchActiveChanges routes changes from the changeQueue to the
+// active reconciliation workers. Changes for an *types.AccountCurrency
+// that is already being reconciled are held until the worker
+// reconciling it is done (and are then reconciled by the same worker),
+// so that changes for the same *types.AccountCurrency are always
+// reconciled in the order they were enqueued while changes for
+// different accounts are reconciled in parallel.
+//
+// At most backlogSize changes are held (see WithBacklogSize). A
+// change that would be held when that many changes are already
+// held is skipped, like any change enqueued when the backlog is
+// full.
+func (r *Reconciler) dispatchActiveChanges(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case balanceChange := <-r.changeQueue:
-			if balanceChange.Block.Index < r.highWaterMark {
-				r.debugLog(
-					"waiting to continue active reconciliation until reaching high water mark...",
-				)
+		case change := <-r.changeQueue:
+			key := types.Hash(&types.AccountCurrency{
+				Account:  change.Account,
+				Currency: change.Currency,
+			})
 
-				if err := r.skipAndPrune(ctx, balanceChange, HeadBehind); err != nil {
-					return err
+			r.activeMutex.Lock()
+			if held, ok := r.activeAccounts[key]; ok {
+				if r.activeHeld >= r.backlogSize {
+					r.activeMutex.Unlock()
+					r.skipBacklogFull(ctx, change)
+					continue
 				}
 
+				r.activeAccounts[key] = append(held, change)
+				r.activeHeld++
+				r.activeMutex.Unlock()
 				continue
 			}
+			r.activeAccounts[key] = []*parser.BalanceChange{}
+			r.activeMutex.Unlock()
+
+			select {
+			case r.activeWork <- &activeWork{key: key, change: change}:
+			case <-ctx.Done():
+				r.releaseAccount(ctx, key, change)
+				return ctx.Err()
+			}
+		}
+	}
+}
 
-			amount, block, err := r.bestLiveBalance(
-				ctx,
-				balanceChange.Account,
-				balanceChange.Currency,
-				balanceChange.Block.Index,
-			)
-			if err != nil {
-				// Ensure we don't leak reconciliations if
-				// context is canceled.
-				if errors.Is(err, context.Canceled) {
-					r.wrappedActiveEnqueue(ctx, balanceChange)
+// nextChange returns the next change held for key. If
+// there are no changes held for key, nil is returned and
+// the *types.AccountCurrency is no longer considered to
+// be reconciling.
+func (r *Reconciler) nextChange(key string) *parser.BalanceChange {
+	r.activeMutex.Lock()
+	defer r.activeMutex.Unlock()
+
+	held := r.activeAccounts[key]
+	if len(held) == 0 {
+		delete(r.activeAccounts, key)
+		return nil
+	}
+
+	r.activeAccounts[key] = held[1:]
+	r.activeHeld--
+
+	return held[0]
+}
+
+// releaseAccount re-enqueues change (if not nil) and any
+// changes held for key so that they are not leaked when
+// reconciliation stops.
+func (r *Reconciler) releaseAccount(
+	ctx context.Context,
+	key string,
+	change *parser.BalanceChange,
+) {
+	r.activeMutex.Lock()
+	held := r.activeAccounts[key]
+	delete(r.activeAccounts, key)
+	r.activeHeld -= len(held)
+	r.activeMutex.Unlock()
+
+	if change != nil {
+		r.wrappedActiveEnqueue(ctx, change)
+	}
+
+	for _, heldChange := range held {
+		r.wrappedActiveEnqueue(ctx, heldChange)
+	}
+}
+
+// reconcileActiveAccounts reconciles the changes dispatched
+// by dispatchActiveChanges. This is useful for detecting
+// if balance changes in operations were correct.
+func (r *Reconciler) reconcileActiveAccounts(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case work := <-r.activeWork:
+			for change := work.change; change != nil; change = r.nextChange(work.key) {
+				if err := r.reconcileChange(ctx, change); err != nil {
+					r.releaseAccount(ctx, work.key, nil)
 					return err
 				}
+			}
+		}
+	}
+}
 
-				tip, tErr := r.helper.IndexAtTip(ctx, balanceChange.Block.Index)
-				switch {
-				case tErr == nil && tip:
-					if err := r.skipAndPrune(ctx, balanceChange, TipFailure); err != nil {
-						return err
-					}
+// reconcileChange reconciles the balance of the
+// *types.AccountCurrency in a *parser.BalanceChange.
+func (r *Reconciler) reconcileChange( // nolint:gocognit
+	ctx context.Context,
+	balanceChange *parser.BalanceChange,
+) error {
+	r.activeDequeued(balanceChange)
+	if balanceChange.Block.Index < r.highWaterMark {
+		r.debugLog(
+			"waiting to continue active reconciliation until reaching high water mark...",
+		)
 
-					continue
-				case tErr != nil:
-					fmt.Printf("%v: could not determine if at tip\n", tErr)
-				}
+		if err := r.skipAndPrune(ctx, balanceChange, HeadBehind); err != nil {
+			return err
+		}
 
-				return fmt.Errorf("%w: %v", ErrLiveBalanceLookupFailed, err)
-			}
+		return nil
+	}
 
-			err = r.accountReconciliation(
-				ctx,
-				balanceChange.Account,
-				balanceChange.Currency,
-				amount.Value,
-				block,
-				false,
-			)
-			if err != nil {
-				// Ensure we don't leak reconciliations if
-				// context is canceled.
-				if errors.Is(err, context.Canceled) {
-					r.wrappedActiveEnqueue(ctx, balanceChange)
-				}
+	// Changes for an account with parked changes are
+	// parked behind them to preserve their order.
+	if r.scheduler != nil && r.scheduler.parkedFor(balanceChange) {
+		r.debugLog(
+			"parking change at block %d behind parked changes for the same account",
+			balanceChange.Block.Index,
+		)
+		r.scheduler.park(balanceChange)
+		return nil
+	}
 
-				return err
-			}
+	amount, block, err := r.bestLiveBalance(
+		ctx,
+		balanceChange.Account,
+		balanceChange.Currency,
+		balanceChange.Block.Index,
+	)
+	if err != nil {
+		// Ensure we don't leak reconciliations if
+		// context is canceled.
+		if errors.Is(err, context.Canceled) {
+			r.wrappedActiveEnqueue(ctx, balanceChange)
+			return err
+		}
 
-			// Attempt to prune historical balances that will not be used
-			// anymore.
-			if err := r.updateQueueMap(
-				ctx,
-				&types.AccountCurrency{
-					Account:  balanceChange.Account,
-					Currency: balanceChange.Currency,
-				},
+		// Wait for the node to catch up instead of
+		// failing reconciliation.
+		if r.nodeBehind(ctx, balanceChange.Block.Index) {
+			r.debugLog(
+				"parking change at block %d until node catches up",
 				balanceChange.Block.Index,
-				pruneActiveReconciliation,
-			); err != nil {
+			)
+			r.scheduler.park(balanceChange)
+			return nil
+		}
+
+		tip, tErr := r.helper.IndexAtTip(ctx, balanceChange.Block.Index)
+		switch {
+		case tErr == nil && tip:
+			if err := r.skipAndPrune(ctx, balanceChange, TipFailure); err != nil {
 				return err
 			}
 
-			r.updateLastChecked(balanceChange.Block.Index)
+			return nil
+		case tErr != nil:
+			fmt.Printf("%v: could not determine if at tip\n", tErr)
 		}
+
+		return fmt.Errorf("%w: %v", ErrLiveBalanceLookupFailed, err)
 	}
+
+	err = r.accountReconciliation(
+		ctx,
+		balanceChange.Account,
+		balanceChange.Currency,
+		amount.Value,
+		block,
+		false,
+		balanceChange,
+	)
+	if err != nil {
+		// Ensure we don't leak reconciliations if
+		// context is canceled.
+		if errors.Is(err, context.Canceled) {
+			r.wrappedActiveEnqueue(ctx, balanceChange)
+		}
+
+		return err
+	}
+
+	// Attempt to prune historical balances that will not be used
+	// anymore.
+	if err := r.updateQueueMap(
+		ctx,
+		&types.AccountCurrency{
+			Account:  balanceChange.Account,
+			Currency: balanceChange.Currency,
+		},
+		balanceChange.Block.Index,
+		pruneActiveReconciliation,
+	); err != nil {
+		return err
+	}
+
+	if err := r.removeChange(ctx, balanceChange); err != nil {
+		return err
+	}
+
+	r.updateLastChecked(balanceChange.Block.Index)
+	return nil
 }
 
 // shouldAttemptInactiveReconciliation returns a boolean indicating whether
@@ -840,10 +1259,25 @@ func (r *Reconciler) shouldAttemptInactiveReconciliation(
 func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 	ctx context.Context,
 ) error {
+	lastAttempt := time.Now()
 	for ctx.Err() == nil {
+		// Yield to active reconciliation if it is falling behind
+		if r.activePriority > 0 &&
+			r.QueueSize() > r.activePriority &&
+			time.Since(lastAttempt) < maxInactiveYield {
+			r.debugLog(
+				"yielding inactive reconciliation to active reconciliation (%d changes enqueued)",
+				r.QueueSize(),
+			)
+			time.Sleep(inactiveReconciliationSleep)
+			continue
+		}
+		lastAttempt = time.Now()
+
 		r.inactiveQueueMutex.Lock(false)
 		queueLen := len(r.inactiveQueue)
-		if queueLen == 0 {
+		nextAcct, manual := r.nextInactiveEntry()
+		if nextAcct == nil {
 			r.inactiveQueueMutex.Unlock()
 			r.debugLog(
 				"no accounts ready for inactive reconciliation (0 accounts in queue)",
@@ -852,7 +1286,6 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 			continue
 		}
 
-		nextAcct := r.inactiveQueue[0]
 		key := types.Hash(nextAcct.Entry)
 
 		// Lock BST while determining if we should attempt reconciliation
@@ -869,11 +1302,7 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 			continue
 		}
 
-		nextValidIndex := int64(-1)
-		if nextAcct.LastCheck != nil { // block is set to nil when loaded from previous run
-			nextValidIndex = nextAcct.LastCheck.Index + r.inactiveFrequency
-		}
-
+		nextValidIndex := r.nextInactiveIndex(nextAcct)
 		if nextValidIndex <= head.Index ||
 			r.helper.ForceInactiveReconciliation(
 				ctx,
@@ -881,7 +1310,11 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 				nextAcct.Entry.Currency,
 				nextAcct.LastCheck,
 			) {
-			r.inactiveQueue = r.inactiveQueue[1:]
+			if manual {
+				r.manualQueue = r.manualQueue[1:]
+			} else {
+				r.inactiveQueue = r.inactiveQueue[1:]
+			}
 			r.inactiveQueueMutex.Unlock()
 
 			// Add nextAcct to queueMap before returning
@@ -897,11 +1330,34 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 			)
 			if err != nil {
 				// Ensure we don't leak reconciliations
-				r.wrappedInactiveEnqueue(nextAcct.Entry, block)
+				r.requeueInactiveEntry(ctx, nextAcct, manual, block)
 				if errors.Is(err, context.Canceled) {
 					return err
 				}
 
+				// Wait for the node to catch up instead of
+				// failing reconciliation.
+				if r.nodeBehind(ctx, head.Index) {
+					if err := r.updateQueueMap(
+						ctx,
+						nextAcct.Entry,
+						head.Index,
+						false,
+					); err != nil {
+						return err
+					}
+
+					r.debugLog(
+						"waiting to continue inactive reconciliation until node reaches block %d",
+						head.Index,
+					)
+					if err := r.waitForNode(ctx); err != nil {
+						return err
+					}
+
+					continue
+				}
+
 				tip, tErr := r.helper.IndexAtTip(ctx, head.Index)
 				switch {
 				case tErr == nil && tip:
@@ -939,9 +1395,10 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 				amount.Value,
 				block,
 				true,
+				nil,
 			)
 			if err != nil {
-				r.wrappedInactiveEnqueue(nextAcct.Entry, block)
+				r.requeueInactiveEntry(ctx, nextAcct, manual, block)
 				return err
 			}
 
@@ -960,8 +1417,10 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 
 			// Always re-enqueue accounts after they have been inactively
 			// reconciled. If we don't re-enqueue, we will never check
-			// these accounts again.
-			err = r.inactiveAccountQueue(true, nextAcct.Entry, block, false)
+			// these accounts again. Manually enqueued accounts are
+			// already in the inactive queue unless they have not
+			// been seen before.
+			err = r.inactiveAccountQueue(ctx, !manual, nextAcct.Entry, block, false)
 			if err != nil {
 				return err
 			}
@@ -983,11 +1442,25 @@ func (r *Reconciler) reconcileInactiveAccounts( // nolint:gocognit
 // Reconcile starts the active and inactive Reconciler goroutines.
 // If any goroutine errors, the function will return an error.
 func (r *Reconciler) Reconcile(ctx context.Context) error {
+	if r.spotChecker != nil && !r.lookupBalanceByBlock {
+		return ErrSpotCheckUnsupported
+	}
+
+	if err := r.loadQueue(ctx); err != nil {
+		return err
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		return r.queueWorker(ctx)
 	})
 
+	if r.ActiveConcurrency > 0 {
+		g.Go(func() error {
+			return r.dispatchActiveChanges(ctx)
+		})
+	}
+
 	for j := 0; j < r.ActiveConcurrency; j++ {
 		g.Go(func() error {
 			return r.reconcileActiveAccounts(ctx)
@@ -1000,6 +1473,24 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		})
 	}
 
+	if r.spotChecker != nil {
+		g.Go(func() error {
+			return r.reconcileSpotChecks(ctx)
+		})
+	}
+
+	if r.scheduler != nil {
+		g.Go(func() error {
+			return r.reconcileParkedChanges(ctx)
+		})
+	}
+
+	if r.results != nil {
+		g.Go(func() error {
+			return r.results.run(ctx)
+		})
+	}
+
 	if err := g.Wait(); err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -580,7 +581,7 @@ func TestAccountReconciliation(t *testing.T) {
 		).Return(
 			nil,
 		).Once()
-		err := reconciler.accountReconciliation(ctx, account, currency, "100", block0, false)
+		err := reconciler.accountReconciliation(ctx, account, currency, "100", block0, false, nil)
 		assert.NoError(t, err)
 		mockHelper.AssertExpectations(t)
 		mockHandler.AssertExpectations(t)
@@ -632,6 +633,7 @@ func TestInactiveAccountQueue(t *testing.T) {
 
 	t.Run("new account in active reconciliation", func(t *testing.T) {
 		err := r.inactiveAccountQueue(
+			context.Background(),
 			false,
 			accountCurrency,
 			block,
@@ -649,6 +651,7 @@ func TestInactiveAccountQueue(t *testing.T) {
 
 	t.Run("another new account in active reconciliation", func(t *testing.T) {
 		err := r.inactiveAccountQueue(
+			context.Background(),
 			false,
 			accountCurrency2,
 			block2,
@@ -676,6 +679,7 @@ func TestInactiveAccountQueue(t *testing.T) {
 		r.inactiveQueue = []*InactiveEntry{}
 
 		err := r.inactiveAccountQueue(
+			context.Background(),
 			false,
 			accountCurrency,
 			block,
@@ -692,6 +696,7 @@ func TestInactiveAccountQueue(t *testing.T) {
 
 	t.Run("previous account in inactive reconciliation", func(t *testing.T) {
 		err := r.inactiveAccountQueue(
+			context.Background(),
 			true,
 			accountCurrency,
 			block,
@@ -713,6 +718,7 @@ func TestInactiveAccountQueue(t *testing.T) {
 
 	t.Run("another previous account in inactive reconciliation", func(t *testing.T) {
 		err := r.inactiveAccountQueue(
+			context.Background(),
 			true,
 			accountCurrency2,
 			block2,
@@ -2811,16 +2817,44 @@ func TestPruningRaceCondition(t *testing.T) {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 
+	mockHelper.On(
+		"PruneBalances",
+		mock.Anything,
+		accountCurrency2.Account,
+		accountCurrency2.Currency,
+		block2.Index-safeBalancePruneDepth,
+	).Return(
+		nil,
+	).Once()
+	// accountCurrency2 is reconciled before accountCurrency (which
+	// has a delayed live balance lookup) because changes for the same
+	// *types.AccountCurrency are reconciled in order.
+	mtxn2 := &mockDatabase.Transaction{}
+	mtxn2.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn2).Once()
+	mockReconcilerCalls(
+		mockHelper,
+		mockHandler,
+		mtxn2,
+		true,
+		accountCurrency2,
+		"120",
+		"120",
+		block2,
+		block2,
+		true,
+		ActiveReconciliation,
+		nil,
+		false,
+		false,
+	)
+
 	mockHelper.On(
 		"PruneBalances",
 		mock.Anything,
 		accountCurrency.Account,
 		accountCurrency.Currency,
 		block.Index-safeBalancePruneDepth,
-	).Run(
-		func(args mock.Arguments) {
-			cancel()
-		},
 	).Return(
 		nil,
 	).Once()
@@ -2838,35 +2872,21 @@ func TestPruningRaceCondition(t *testing.T) {
 		ActiveReconciliation,
 	)
 
+	// The change for accountCurrency at block2 is held until
+	// the change at block is reconciled (and pruned).
 	mockHelper.On(
 		"PruneBalances",
 		mock.Anything,
-		accountCurrency2.Account,
-		accountCurrency2.Currency,
+		accountCurrency.Account,
+		accountCurrency.Currency,
 		block2.Index-safeBalancePruneDepth,
+	).Run(
+		func(args mock.Arguments) {
+			cancel()
+		},
 	).Return(
 		nil,
 	).Once()
-	mtxn2 := &mockDatabase.Transaction{}
-	mtxn2.On("Discard", mock.Anything).Once()
-	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn2).Once()
-	mockReconcilerCalls(
-		mockHelper,
-		mockHandler,
-		mtxn2,
-		true,
-		accountCurrency2,
-		"120",
-		"120",
-		block2,
-		block2,
-		true,
-		ActiveReconciliation,
-		nil,
-		false,
-		false,
-	)
-
 	mtxn3 := &mockDatabase.Transaction{}
 	mtxn3.On("Discard", mock.Anything).Once()
 	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn3).Once()
@@ -3441,3 +3461,419 @@ func TestReconcile_SuccessOnlyInactiveOverride(t *testing.T) {
 	err := r.Reconcile(ctx)
 	assert.Contains(t, context.Canceled.Error(), err.Error())
 }
+
+// memoryQueue is an in-memory Queue used to
+// simulate a restart of the Reconciler.
+type memoryQueue struct {
+	sync.Mutex
+
+	changes map[string]*parser.BalanceChange
+	entries map[string]*InactiveEntry
+}
+
+func newMemoryQueue() *memoryQueue {
+	return &memoryQueue{
+		changes: map[string]*parser.BalanceChange{},
+		entries: map[string]*InactiveEntry{},
+	}
+}
+
+func memoryQueueKey(change *parser.BalanceChange) string {
+	return fmt.Sprintf("%s/%d", types.Hash(&types.AccountCurrency{
+		Account:  change.Account,
+		Currency: change.Currency,
+	}), change.Block.Index)
+}
+
+func (m *memoryQueue) LoadQueue(
+	ctx context.Context,
+) ([]*parser.BalanceChange, []*InactiveEntry, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	changes := []*parser.BalanceChange{}
+	for _, change := range m.changes {
+		changes = append(changes, change)
+	}
+
+	entries := []*InactiveEntry{}
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+
+	return changes, entries, nil
+}
+
+func (m *memoryQueue) AddChanges(ctx context.Context, changes []*parser.BalanceChange) error {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, change := range changes {
+		m.changes[memoryQueueKey(change)] = change
+	}
+
+	return nil
+}
+
+func (m *memoryQueue) RemoveChange(ctx context.Context, change *parser.BalanceChange) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.changes, memoryQueueKey(change))
+	return nil
+}
+
+func (m *memoryQueue) SetInactiveEntry(ctx context.Context, entry *InactiveEntry) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.entries[types.Hash(entry.Entry)] = entry
+	return nil
+}
+
+func TestReconcile_Queue(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		}
+		accountCurrency = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 1",
+			},
+			Currency: &types.Currency{
+				Symbol:   "BTC",
+				Decimals: 8,
+			},
+		}
+		block2 = &types.BlockIdentifier{
+			Hash:  "block 2",
+			Index: 2,
+		}
+		accountCurrency2 = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 2",
+			},
+			Currency: &types.Currency{
+				Symbol:   "ETH",
+				Decimals: 18,
+			},
+		}
+	)
+
+	// Persist work enqueued before a restart
+	ctx := context.Background()
+	queue := newMemoryQueue()
+	assert.NoError(t, queue.AddChanges(ctx, []*parser.BalanceChange{
+		{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block:    block,
+		},
+	}))
+	assert.NoError(t, queue.SetInactiveEntry(ctx, &InactiveEntry{
+		Entry:     accountCurrency,
+		LastCheck: block,
+	}))
+
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		nil,
+		WithActiveConcurrency(1),
+		WithInactiveConcurrency(0),
+		WithLookupBalanceByBlock(),
+		WithSeenAccounts([]*types.AccountCurrency{accountCurrency}),
+		WithQueue(queue),
+	)
+	ctx, cancel := context.WithCancel(ctx)
+
+	mtxn := &mockDatabase.Transaction{}
+	mtxn.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn).Once()
+	mockReconcilerCalls(
+		mockHelper,
+		mockHandler,
+		mtxn,
+		true,
+		accountCurrency,
+		"100",
+		"100",
+		block,
+		block,
+		true,
+		ActiveReconciliation,
+		nil,
+		false,
+		false,
+	)
+
+	mtxn2 := &mockDatabase.Transaction{}
+	mtxn2.On("Discard", mock.Anything).Once()
+	mockHelper.On("DatabaseTransaction", mock.Anything).Return(mtxn2).Once()
+	mockReconcilerCalls(
+		mockHelper,
+		mockHandler,
+		mtxn2,
+		true,
+		accountCurrency2,
+		"250",
+		"250",
+		block2,
+		block2,
+		true,
+		ActiveReconciliation,
+		nil,
+		false,
+		false,
+	)
+
+	go func() {
+		err := r.Reconcile(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	err := r.QueueChanges(ctx, block2, []*parser.BalanceChange{
+		{
+			Account:  accountCurrency2.Account,
+			Currency: accountCurrency2.Currency,
+			Block:    block2,
+		},
+	})
+	assert.NoError(t, err)
+
+	time.Sleep(1 * time.Second)
+	cancel()
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	mtxn.AssertExpectations(t)
+	mtxn2.AssertExpectations(t)
+
+	// All changes are removed from the queue once reconciled
+	// and all seen accounts are persisted.
+	queue.Lock()
+	assert.Len(t, queue.changes, 0)
+	assert.Len(t, queue.entries, 2)
+	assert.Equal(t, block2, queue.entries[types.Hash(accountCurrency2)].LastCheck)
+	queue.Unlock()
+
+	// The loaded inactive entry is merged with
+	// the provided seen accounts.
+	assert.Equal(t, &QueueMetrics{
+		InactiveDepth:       2,
+		OldestInactiveIndex: 1,
+	}, r.QueueMetrics())
+}
+
+func TestDispatchActiveChanges(t *testing.T) {
+	var (
+		accountCurrency = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 1",
+			},
+			Currency: btc,
+		}
+		accountCurrency2 = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 2",
+			},
+			Currency: eth,
+		}
+		key  = types.Hash(accountCurrency)
+		key2 = types.Hash(accountCurrency2)
+	)
+
+	change := func(acctCurrency *types.AccountCurrency, index int64) *parser.BalanceChange {
+		return &parser.BalanceChange{
+			Account:  acctCurrency.Account,
+			Currency: acctCurrency.Currency,
+			Block: &types.BlockIdentifier{
+				Hash:  fmt.Sprintf("block %d", index),
+				Index: index,
+			},
+		}
+	}
+
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithActiveConcurrency(2),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := make(chan struct{})
+	go func() {
+		err := r.dispatchActiveChanges(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+		close(d)
+	}()
+
+	changes := []*parser.BalanceChange{
+		change(accountCurrency, 1),
+		change(accountCurrency2, 1),
+		change(accountCurrency, 2),
+		change(accountCurrency, 3),
+	}
+	for _, c := range changes {
+		r.changeQueue <- c
+	}
+
+	// Changes for different accounts are dispatched
+	// concurrently.
+	work := <-r.activeWork
+	assert.Equal(t, key, work.key)
+	assert.Equal(t, changes[0], work.change)
+	work = <-r.activeWork
+	assert.Equal(t, key2, work.key)
+	assert.Equal(t, changes[1], work.change)
+
+	// Changes for an account being reconciled are held
+	// until the worker reconciling it is done.
+	assert.Eventually(t, func() bool {
+		return r.QueueSize() == 2
+	}, 1*time.Second, 10*time.Millisecond)
+	assert.Equal(t, changes[2], r.nextChange(key))
+	assert.Equal(t, changes[3], r.nextChange(key))
+	assert.Nil(t, r.nextChange(key))
+	assert.Nil(t, r.nextChange(key2))
+	assert.Equal(t, 0, r.QueueSize())
+
+	// Once an account is no longer being reconciled, any
+	// new change is dispatched.
+	r.changeQueue <- change(accountCurrency, 4)
+	work = <-r.activeWork
+	assert.Equal(t, key, work.key)
+	assert.Equal(t, int64(4), work.change.Block.Index)
+
+	// Held changes are re-enqueued when released.
+	r.changeQueue <- change(accountCurrency, 5)
+	assert.Eventually(t, func() bool {
+		return r.QueueSize() == 1
+	}, 1*time.Second, 10*time.Millisecond)
+	cancel()
+	<-d
+
+	r.releaseAccount(context.Background(), key, nil)
+	assert.Equal(t, 1, r.QueueSize())
+	assert.Equal(t, int64(5), (<-r.changeQueue).Block.Index)
+	r.activeMutex.Lock()
+	assert.Len(t, r.activeAccounts, 0)
+	r.activeMutex.Unlock()
+}
+
+func TestDispatchActiveChanges_BacklogFull(t *testing.T) {
+	accountCurrency := &types.AccountCurrency{
+		Account: &types.AccountIdentifier{
+			Address: "addr 1",
+		},
+		Currency: btc,
+	}
+	key := types.Hash(accountCurrency)
+
+	mockHandler := &mocks.Handler{}
+	r := New(
+		&mocks.Helper{},
+		mockHandler,
+		nil,
+		WithBacklogSize(2),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := make(chan struct{})
+	go func() {
+		err := r.dispatchActiveChanges(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+		close(d)
+	}()
+
+	skipped := make(chan struct{})
+	mockHandler.On(
+		"ReconciliationSkipped",
+		mock.Anything,
+		ActiveReconciliation,
+		accountCurrency.Account,
+		accountCurrency.Currency,
+		BacklogFull,
+	).Return(nil).Run(func(args mock.Arguments) {
+		close(skipped)
+	}).Once()
+
+	// The first change is dispatched and the next two
+	// are held. At most backlogSize changes are held,
+	// so the last change is skipped.
+	for i := int64(1); i <= 4; i++ {
+		r.changeQueue <- &parser.BalanceChange{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block: &types.BlockIdentifier{
+				Hash:  fmt.Sprintf("block %d", i),
+				Index: i,
+			},
+		}
+
+		if i == 1 {
+			work := <-r.activeWork
+			assert.Equal(t, int64(1), work.change.Block.Index)
+		}
+	}
+
+	<-skipped
+	assert.Equal(t, 2, r.QueueSize())
+	assert.Equal(t, int64(2), r.nextChange(key).Block.Index)
+	assert.Equal(t, int64(3), r.nextChange(key).Block.Index)
+	assert.Nil(t, r.nextChange(key))
+
+	cancel()
+	<-d
+	mockHandler.AssertExpectations(t)
+}
+
+func TestReconcileInactiveAccounts_ActivePriority(t *testing.T) {
+	accountCurrency := &types.AccountCurrency{
+		Account: &types.AccountIdentifier{
+			Address: "addr 1",
+		},
+		Currency: btc,
+	}
+
+	// No helper calls are expected because inactive
+	// reconciliation yields to active reconciliation.
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	r := New(
+		mockHelper,
+		mockHandler,
+		nil,
+		WithActivePriority(1),
+		WithSeenAccounts([]*types.AccountCurrency{accountCurrency}),
+	)
+
+	for i := int64(0); i < 2; i++ {
+		r.changeQueue <- &parser.BalanceChange{
+			Account:  accountCurrency.Account,
+			Currency: accountCurrency.Currency,
+			Block: &types.BlockIdentifier{
+				Hash:  fmt.Sprintf("block %d", i),
+				Index: i,
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		2*inactiveReconciliationSleep,
+	)
+	defer cancel()
+
+	err := r.reconcileInactiveAccounts(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
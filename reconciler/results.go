@@ -0,0 +1,346 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// SucceededResult is the Result.Type of
+	// a successful reconciliation.
+	SucceededResult = "SUCCEEDED"
+
+	// FailedResult is the Result.Type of
+	// a failed reconciliation.
+	FailedResult = "FAILED"
+
+	// ExemptResult is the Result.Type of an exempt
+	// reconciliation (see ReconciliationExempt).
+	ExemptResult = "EXEMPT"
+
+	// SkippedResult is the Result.Type of
+	// a skipped reconciliation.
+	SkippedResult = "SKIPPED"
+
+	// defaultResultBacklog is the maximum number of
+	// *Result waiting to be published before new
+	// results are dropped.
+	defaultResultBacklog = 1000
+
+	// resultDrainTimeout is the maximum time.Duration
+	// spent publishing any *Result waiting to be published
+	// when reconciliation stops.
+	resultDrainTimeout = 10 * time.Second
+
+	// defaultWebhookTimeout is the default timeout of
+	// each request made by the *WebhookSink.
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// Result is the outcome of a single reconciliation.
+type Result struct {
+	Type               string                   `json:"type"`
+	ReconciliationType string                   `json:"reconciliation_type"`
+	Account            *types.AccountIdentifier `json:"account_identifier"`
+	Currency           *types.Currency          `json:"currency"`
+
+	// Block is not populated for skipped reconciliations.
+	Block *types.BlockIdentifier `json:"block_identifier,omitempty"`
+
+	// ComputedBalance is only populated for failed and exempt
+	// reconciliations (the balance of a successful reconciliation
+	// is populated in LiveBalance).
+	ComputedBalance string `json:"computed_balance,omitempty"`
+	LiveBalance     string `json:"live_balance,omitempty"`
+
+	// Exemption is only populated for exempt reconciliations
+	// and Cause is only populated for skipped reconciliations.
+	Exemption *types.BalanceExemption `json:"exemption,omitempty"`
+	Cause     string                  `json:"cause,omitempty"`
+
+	// Timestamp is when the reconciliation completed
+	// (in milliseconds since the Unix Epoch).
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ResultSink is invoked with the *Result of every
+// reconciliation (see WithResultSink).
+type ResultSink interface {
+	Publish(ctx context.Context, result *Result) error
+}
+
+// resultEmitter publishes each *Result to a ResultSink
+// without blocking reconciliation.
+type resultEmitter struct {
+	sink    ResultSink
+	results chan *Result
+}
+
+func newResultEmitter(sink ResultSink) *resultEmitter {
+	return &resultEmitter{
+		sink:    sink,
+		results: make(chan *Result, defaultResultBacklog),
+	}
+}
+
+// emit enqueues result for publishing. If the
+// backlog is full, result is dropped.
+func (e *resultEmitter) emit(result *Result) {
+	result.Timestamp = utils.Milliseconds()
+
+	select {
+	case e.results <- result:
+	default:
+		log.Printf(
+			"dropping %s reconciliation result for %s because backlog has %d items\n",
+			result.Type,
+			types.PrintStruct(result.Account),
+			defaultResultBacklog,
+		)
+	}
+}
+
+// publish invokes the ResultSink with result.
+func (e *resultEmitter) publish(ctx context.Context, result *Result) {
+	if err := e.sink.Publish(ctx, result); err != nil {
+		log.Printf(
+			"%s: unable to publish %s reconciliation result for %s\n",
+			err.Error(),
+			result.Type,
+			types.PrintStruct(result.Account),
+		)
+	}
+}
+
+// run publishes each *Result until ctx is done. Any
+// *Result waiting to be published when ctx is done
+// is published before returning (so that the result
+// that halted reconciliation is not lost).
+func (e *resultEmitter) run(ctx context.Context) error {
+	for {
+		select {
+		case result := <-e.results:
+			e.publish(ctx, result)
+		case <-ctx.Done():
+			drainCtx, cancel := context.WithTimeout(context.Background(), resultDrainTimeout)
+			defer cancel()
+
+			for {
+				select {
+				case result := <-e.results:
+					e.publish(drainCtx, result)
+				default:
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// resultHandler emits the *Result of each
+// reconciliation before invoking the Handler.
+type resultHandler struct {
+	Handler
+
+	emitter *resultEmitter
+}
+
+// ReconciliationFailed emits a failure.
+func (h *resultHandler) ReconciliationFailed(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+) error {
+	h.emitter.emit(&Result{
+		Type:               FailedResult,
+		ReconciliationType: reconciliationType,
+		Account:            account,
+		Currency:           currency,
+		Block:              block,
+		ComputedBalance:    computedBalance,
+		LiveBalance:        liveBalance,
+	})
+
+	return h.Handler.ReconciliationFailed(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		computedBalance,
+		liveBalance,
+		block,
+	)
+}
+
+// ReconciliationSucceeded emits a success.
+func (h *resultHandler) ReconciliationSucceeded(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	balance string,
+	block *types.BlockIdentifier,
+) error {
+	h.emitter.emit(&Result{
+		Type:               SucceededResult,
+		ReconciliationType: reconciliationType,
+		Account:            account,
+		Currency:           currency,
+		Block:              block,
+		LiveBalance:        balance,
+	})
+
+	return h.Handler.ReconciliationSucceeded(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		balance,
+		block,
+	)
+}
+
+// ReconciliationExempt emits an exemption.
+func (h *resultHandler) ReconciliationExempt(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+	exemption *types.BalanceExemption,
+) error {
+	h.emitter.emit(&Result{
+		Type:               ExemptResult,
+		ReconciliationType: reconciliationType,
+		Account:            account,
+		Currency:           currency,
+		Block:              block,
+		ComputedBalance:    computedBalance,
+		LiveBalance:        liveBalance,
+		Exemption:          exemption,
+	})
+
+	return h.Handler.ReconciliationExempt(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		computedBalance,
+		liveBalance,
+		block,
+		exemption,
+	)
+}
+
+// ReconciliationSkipped emits a skipped reconciliation.
+func (h *resultHandler) ReconciliationSkipped(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	cause string,
+) error {
+	h.emitter.emit(&Result{
+		Type:               SkippedResult,
+		ReconciliationType: reconciliationType,
+		Account:            account,
+		Currency:           currency,
+		Cause:              cause,
+	})
+
+	return h.Handler.ReconciliationSkipped(
+		ctx,
+		reconciliationType,
+		account,
+		currency,
+		cause,
+	)
+}
+
+// WebhookSink is a ResultSink that POSTs each
+// *Result as JSON to a webhook URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a new *WebhookSink that POSTs
+// each *Result to url using client. If client is nil,
+// a client with a defaultWebhookTimeout is used.
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+
+	return &WebhookSink{
+		url:    url,
+		client: client,
+	}
+}
+
+// Publish POSTs result to the webhook URL. Any response
+// other than a 2xx is considered an error.
+func (w *WebhookSink) Publish(ctx context.Context, result *Result) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookFailed, err)
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		w.url,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookFailed, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"%w: status code %d with body %s",
+			ErrWebhookFailed,
+			resp.StatusCode,
+			respBody,
+		)
+	}
+
+	return nil
+}
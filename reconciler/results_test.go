@@ -0,0 +1,248 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// memorySink is a ResultSink that
+// stores all published results.
+type memorySink struct {
+	sync.Mutex
+
+	results []*Result
+}
+
+func (m *memorySink) Publish(ctx context.Context, result *Result) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.results = append(m.results, result)
+	return nil
+}
+
+func TestResultSink(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		account = &types.AccountIdentifier{
+			Address: "addr 1",
+		}
+		block = &types.BlockIdentifier{
+			Hash:  "block 10",
+			Index: 10,
+		}
+		exemption = &types.BalanceExemption{
+			Currency:      btc,
+			ExemptionType: types.BalanceDynamic,
+		}
+	)
+
+	sink := &memorySink{}
+	mockHandler := &mocks.Handler{}
+	r := New(nil, mockHandler, nil, WithResultSink(sink))
+
+	mockHandler.On(
+		"ReconciliationSucceeded",
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		block,
+	).Return(nil).Once()
+	assert.NoError(t, r.handler.ReconciliationSucceeded(
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		block,
+	))
+
+	mockHandler.On(
+		"ReconciliationExempt",
+		ctx,
+		InactiveReconciliation,
+		account,
+		btc,
+		"100",
+		"90",
+		block,
+		exemption,
+	).Return(nil).Once()
+	assert.NoError(t, r.handler.ReconciliationExempt(
+		ctx,
+		InactiveReconciliation,
+		account,
+		btc,
+		"100",
+		"90",
+		block,
+		exemption,
+	))
+
+	mockHandler.On(
+		"ReconciliationSkipped",
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		HeadBehind,
+	).Return(nil).Once()
+	assert.NoError(t, r.handler.ReconciliationSkipped(
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		HeadBehind,
+	))
+
+	// Failures are published even if the Handler
+	// halts reconciliation.
+	mockHandler.On(
+		"ReconciliationFailed",
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		"80",
+		block,
+	).Return(ErrReconciliationHalted).Once()
+	assert.Error(t, r.handler.ReconciliationFailed(
+		ctx,
+		ActiveReconciliation,
+		account,
+		btc,
+		"100",
+		"80",
+		block,
+	))
+	mockHandler.AssertExpectations(t)
+
+	// Successes are still recorded for coverage.
+	assert.Equal(t, int64(1), r.coverage.coverage(-1).Successes)
+
+	// All pending results are published when
+	// reconciliation stops.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.True(t, errors.Is(r.results.run(cancelCtx), context.Canceled))
+
+	sink.Lock()
+	defer sink.Unlock()
+	for _, result := range sink.results {
+		assert.Greater(t, result.Timestamp, int64(0))
+		result.Timestamp = 0
+	}
+	assert.Equal(t, []*Result{
+		{
+			Type:               SucceededResult,
+			ReconciliationType: ActiveReconciliation,
+			Account:            account,
+			Currency:           btc,
+			Block:              block,
+			LiveBalance:        "100",
+		},
+		{
+			Type:               ExemptResult,
+			ReconciliationType: InactiveReconciliation,
+			Account:            account,
+			Currency:           btc,
+			Block:              block,
+			ComputedBalance:    "100",
+			LiveBalance:        "90",
+			Exemption:          exemption,
+		},
+		{
+			Type:               SkippedResult,
+			ReconciliationType: ActiveReconciliation,
+			Account:            account,
+			Currency:           btc,
+			Cause:              HeadBehind,
+		},
+		{
+			Type:               FailedResult,
+			ReconciliationType: ActiveReconciliation,
+			Account:            account,
+			Currency:           btc,
+			Block:              block,
+			ComputedBalance:    "100",
+			LiveBalance:        "80",
+		},
+	}, sink.results)
+}
+
+func TestResultEmitter_Backlog(t *testing.T) {
+	e := newResultEmitter(&memorySink{})
+	for i := 0; i < defaultResultBacklog+1; i++ {
+		e.emit(&Result{Type: SucceededResult})
+	}
+
+	assert.Len(t, e.results, defaultResultBacklog)
+}
+
+func TestWebhookSink(t *testing.T) {
+	result := &Result{
+		Type:               FailedResult,
+		ReconciliationType: ActiveReconciliation,
+		Account:            &types.AccountIdentifier{Address: "addr 1"},
+		Currency:           btc,
+		Block: &types.BlockIdentifier{
+			Hash:  "block 10",
+			Index: 10,
+		},
+		ComputedBalance: "100",
+		LiveBalance:     "80",
+		Timestamp:       1000,
+	}
+
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var received Result
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		assert.Equal(t, result, &received)
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer ts.Close()
+
+	sink := NewWebhookSink(ts.URL, nil)
+	assert.NoError(t, sink.Publish(context.Background(), result))
+
+	status = http.StatusInternalServerError
+	err := sink.Publish(context.Background(), result)
+	assert.True(t, errors.Is(err, ErrWebhookFailed))
+	assert.Contains(t, err.Error(), "status code 500 with body body")
+
+	err = NewWebhookSink("http://[::1", nil).Publish(context.Background(), result)
+	assert.True(t, errors.Is(err, ErrWebhookFailed))
+}
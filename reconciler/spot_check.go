@@ -0,0 +1,266 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// defaultSpotCheckInterval is the default
+	// time.Duration between spot checks.
+	defaultSpotCheckInterval = 1 * time.Second
+
+	// maxSpotCheckMismatches is the maximum number
+	// of *SpotCheckMismatch retained in the
+	// *SpotCheckReport (most recent first).
+	maxSpotCheckMismatches = 100
+)
+
+// SpotCheckConfiguration configures historical spot checks
+// (see WithSpotCheck).
+type SpotCheckConfiguration struct {
+	// Interval is the time.Duration between spot checks
+	// (the sampling rate). If not populated,
+	// defaultSpotCheckInterval is used.
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// MinimumIndex is the oldest block index that
+	// is sampled (ex: the index before which historical
+	// balances are pruned).
+	MinimumIndex int64 `json:"minimum_index,omitempty"`
+
+	// MaxDepth restricts sampling to blocks within MaxDepth
+	// of the synced head. If not populated, any block at or
+	// after MinimumIndex is sampled.
+	MaxDepth int64 `json:"max_depth,omitempty"`
+}
+
+// SpotCheckMismatch is a historical balance that
+// could not be reconciled during a spot check.
+type SpotCheckMismatch struct {
+	Account         *types.AccountIdentifier `json:"account_identifier"`
+	Currency        *types.Currency          `json:"currency"`
+	Block           *types.BlockIdentifier   `json:"block_identifier"`
+	ComputedBalance string                   `json:"computed_balance"`
+	LiveBalance     string                   `json:"live_balance"`
+	Difference      string                   `json:"difference"`
+}
+
+// SpotCheckReport summarizes all spot checks
+// performed by the Reconciler.
+type SpotCheckReport struct {
+	// Samples is the number of *types.AccountCurrency and
+	// block index pairs sampled. Each sample is counted as
+	// Matched, Exempt, Mismatched, Skipped (the block was
+	// orphaned), or Errored (ex: the balance was pruned).
+	Samples    int64 `json:"samples"`
+	Matched    int64 `json:"matched"`
+	Exempt     int64 `json:"exempt"`
+	Mismatched int64 `json:"mismatched"`
+	Skipped    int64 `json:"skipped"`
+	Errored    int64 `json:"errored"`
+
+	// Mismatches are the most recent mismatches
+	// (at most maxSpotCheckMismatches).
+	Mismatches []*SpotCheckMismatch `json:"mismatches"`
+}
+
+// spotChecker samples historical balances
+// and records the result of each sample.
+type spotChecker struct {
+	sync.Mutex
+
+	config *SpotCheckConfiguration
+	rand   *rand.Rand
+	report *SpotCheckReport
+}
+
+func newSpotChecker(config *SpotCheckConfiguration) *spotChecker {
+	if config.Interval <= 0 {
+		config.Interval = defaultSpotCheckInterval
+	}
+
+	return &spotChecker{
+		config: config,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		report: &SpotCheckReport{Mismatches: []*SpotCheckMismatch{}},
+	}
+}
+
+// sampleIndex returns a random block index in the sampling
+// range for head (or false if there is nothing to sample).
+func (s *spotChecker) sampleIndex(head int64) (int64, bool) {
+	minimum := s.config.MinimumIndex
+	if s.config.MaxDepth > 0 && head-s.config.MaxDepth > minimum {
+		minimum = head - s.config.MaxDepth
+	}
+
+	if head < minimum {
+		return 0, false
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return minimum + s.rand.Int63n(head-minimum+1), true
+}
+
+// sampleEntry returns a random entry from entries.
+func (s *spotChecker) sampleEntry(entries []*InactiveEntry) *InactiveEntry {
+	s.Lock()
+	defer s.Unlock()
+
+	return entries[s.rand.Intn(len(entries))]
+}
+
+// record updates the report with the outcome of a sample.
+func (s *spotChecker) record(outcome func(report *SpotCheckReport)) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.report.Samples++
+	outcome(s.report)
+}
+
+// SpotCheckReport returns a summary of all historical spot
+// checks performed by the Reconciler (or nil if spot checks
+// are not enabled).
+func (r *Reconciler) SpotCheckReport() *SpotCheckReport {
+	if r.spotChecker == nil {
+		return nil
+	}
+
+	r.spotChecker.Lock()
+	defer r.spotChecker.Unlock()
+
+	report := *r.spotChecker.report
+	report.Mismatches = append([]*SpotCheckMismatch{}, report.Mismatches...)
+
+	return &report
+}
+
+// spotCheck reconciles a random previously seen
+// *types.AccountCurrency at a random historical block.
+// This catches historical balance corruption that
+// reconciliation at the tip cannot detect.
+func (r *Reconciler) spotCheck(ctx context.Context) error {
+	r.inactiveQueueMutex.Lock(false)
+	if len(r.inactiveQueue) == 0 {
+		r.inactiveQueueMutex.Unlock()
+		r.debugLog("no accounts to spot check")
+		return nil
+	}
+	entry := r.spotChecker.sampleEntry(r.inactiveQueue)
+	r.inactiveQueueMutex.Unlock()
+
+	dbTx := r.helper.DatabaseTransaction(ctx)
+	head, err := r.helper.CurrentBlock(ctx, dbTx)
+	dbTx.Discard(ctx)
+	if err != nil {
+		r.debugLog("waiting to spot check until a block is synced...")
+		return nil
+	}
+
+	index, ok := r.spotChecker.sampleIndex(head.Index)
+	if !ok {
+		r.debugLog("no blocks to spot check")
+		return nil
+	}
+
+	account := entry.Entry.Account
+	currency := entry.Entry.Currency
+	amount, liveBlock, err := r.helper.LiveBalance(ctx, account, currency, index)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrLiveBalanceLookupFailed, err)
+	}
+
+	difference, computedBalance, _, err := r.CompareBalance(
+		ctx,
+		account,
+		currency,
+		amount.Value,
+		liveBlock,
+	)
+	switch {
+	case errors.Is(err, ErrBlockGone):
+		r.spotChecker.record(func(report *SpotCheckReport) { report.Skipped++ })
+		return nil
+	case err != nil:
+		return err
+	case difference == zeroString:
+		r.spotChecker.record(func(report *SpotCheckReport) { report.Matched++ })
+		return nil
+	case r.findExemption(account, currency, difference) != nil:
+		r.spotChecker.record(func(report *SpotCheckReport) { report.Exempt++ })
+		return nil
+	}
+
+	mismatch := &SpotCheckMismatch{
+		Account:         account,
+		Currency:        currency,
+		Block:           liveBlock,
+		ComputedBalance: computedBalance,
+		LiveBalance:     amount.Value,
+		Difference:      difference,
+	}
+	log.Printf(
+		"spot check failed for %s at block %d: computed %s, live %s\n",
+		types.PrintStruct(entry.Entry),
+		liveBlock.Index,
+		computedBalance,
+		amount.Value,
+	)
+	r.spotChecker.record(func(report *SpotCheckReport) {
+		report.Mismatched++
+		report.Mismatches = append([]*SpotCheckMismatch{mismatch}, report.Mismatches...)
+		if len(report.Mismatches) > maxSpotCheckMismatches {
+			report.Mismatches = report.Mismatches[:maxSpotCheckMismatches]
+		}
+	})
+
+	return nil
+}
+
+// reconcileSpotChecks performs a spot check every
+// SpotCheckConfiguration.Interval until ctx is done.
+// Spot checks that error are recorded in the
+// *SpotCheckReport instead of stopping reconciliation.
+func (r *Reconciler) reconcileSpotChecks(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.spotChecker.config.Interval):
+		}
+
+		if err := r.spotCheck(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			r.debugLog("spot check errored: %s", err.Error())
+			r.spotChecker.record(func(report *SpotCheckReport) { report.Errored++ })
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/reconciler"
+	mockDatabase "github.com/coinbase/rosetta-sdk-go/mocks/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestSpotCheckSampleIndex(t *testing.T) {
+	s := newSpotChecker(&SpotCheckConfiguration{
+		MinimumIndex: 5,
+		MaxDepth:     10,
+	})
+	assert.Equal(t, defaultSpotCheckInterval, s.config.Interval)
+
+	for i := 0; i < 100; i++ {
+		index, ok := s.sampleIndex(100)
+		assert.True(t, ok)
+		assert.True(t, index >= 90 && index <= 100)
+
+		index, ok = s.sampleIndex(8)
+		assert.True(t, ok)
+		assert.True(t, index >= 5 && index <= 8)
+	}
+
+	_, ok := s.sampleIndex(4)
+	assert.False(t, ok)
+}
+
+func TestSpotCheck(t *testing.T) {
+	var (
+		ctx             = context.Background()
+		accountCurrency = &types.AccountCurrency{
+			Account: &types.AccountIdentifier{
+				Address: "addr 1",
+			},
+			Currency: btc,
+		}
+		block = &types.BlockIdentifier{
+			Hash:  "block 10",
+			Index: 10,
+		}
+		head = block
+	)
+
+	// Only the head block is sampled.
+	newReconciler := func(seen []*types.AccountCurrency) (*Reconciler, *mocks.Helper) {
+		mockHelper := &mocks.Helper{}
+		r := New(
+			mockHelper,
+			&mocks.Handler{},
+			nil,
+			WithLookupBalanceByBlock(),
+			WithSeenAccounts(seen),
+			WithTolerance(DriftTolerance(big.NewInt(1))),
+			WithSpotCheck(&SpotCheckConfiguration{
+				MinimumIndex: block.Index,
+			}),
+		)
+
+		return r, mockHelper
+	}
+
+	mockSample := func(
+		mockHelper *mocks.Helper,
+		computed string,
+		live string,
+		canonical bool,
+	) *mockDatabase.Transaction {
+		mtxn := &mockDatabase.Transaction{}
+		mtxn.On("Discard", ctx).Twice()
+		mockHelper.On("DatabaseTransaction", ctx).Return(mtxn).Twice()
+		mockHelper.On("CurrentBlock", ctx, mtxn).Return(head, nil).Twice()
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			accountCurrency.Account,
+			accountCurrency.Currency,
+			block.Index,
+		).Return(
+			&types.Amount{Value: live, Currency: accountCurrency.Currency},
+			block,
+			nil,
+		).Once()
+		mockHelper.On("CanonicalBlock", ctx, mtxn, block).Return(canonical, nil).Once()
+		if canonical {
+			mockHelper.On(
+				"ComputedBalance",
+				ctx,
+				mtxn,
+				accountCurrency.Account,
+				accountCurrency.Currency,
+				block.Index,
+			).Return(
+				&types.Amount{Value: computed, Currency: accountCurrency.Currency},
+				nil,
+			).Once()
+		}
+
+		return mtxn
+	}
+
+	t.Run("no accounts", func(t *testing.T) {
+		r, mockHelper := newReconciler(nil)
+		assert.NoError(t, r.spotCheck(ctx))
+		assert.Equal(t, &SpotCheckReport{Mismatches: []*SpotCheckMismatch{}}, r.SpotCheckReport())
+		mockHelper.AssertExpectations(t)
+	})
+
+	t.Run("no blocks", func(t *testing.T) {
+		r, mockHelper := newReconciler([]*types.AccountCurrency{accountCurrency})
+		mtxn := &mockDatabase.Transaction{}
+		mtxn.On("Discard", ctx).Once()
+		mockHelper.On("DatabaseTransaction", ctx).Return(mtxn).Once()
+		mockHelper.On("CurrentBlock", ctx, mtxn).Return(&types.BlockIdentifier{
+			Hash:  "block 9",
+			Index: 9,
+		}, nil).Once()
+
+		assert.NoError(t, r.spotCheck(ctx))
+		assert.Equal(t, int64(0), r.SpotCheckReport().Samples)
+		mockHelper.AssertExpectations(t)
+		mtxn.AssertExpectations(t)
+	})
+
+	t.Run("outcomes", func(t *testing.T) {
+		r, mockHelper := newReconciler([]*types.AccountCurrency{accountCurrency})
+
+		mtxn := mockSample(mockHelper, "100", "100", true)
+		assert.NoError(t, r.spotCheck(ctx))
+		mtxn.AssertExpectations(t)
+
+		mtxn = mockSample(mockHelper, "100", "101", true)
+		assert.NoError(t, r.spotCheck(ctx))
+		mtxn.AssertExpectations(t)
+
+		mtxn = mockSample(mockHelper, "100", "90", true)
+		assert.NoError(t, r.spotCheck(ctx))
+		mtxn.AssertExpectations(t)
+
+		mtxn = mockSample(mockHelper, "", "100", false)
+		assert.NoError(t, r.spotCheck(ctx))
+		mtxn.AssertExpectations(t)
+
+		mockHelper.AssertExpectations(t)
+		assert.Equal(t, &SpotCheckReport{
+			Samples:    4,
+			Matched:    1,
+			Exempt:     1,
+			Mismatched: 1,
+			Skipped:    1,
+			Mismatches: []*SpotCheckMismatch{
+				{
+					Account:         accountCurrency.Account,
+					Currency:        accountCurrency.Currency,
+					Block:           block,
+					ComputedBalance: "100",
+					LiveBalance:     "90",
+					Difference:      "-10",
+				},
+			},
+		}, r.SpotCheckReport())
+	})
+
+	t.Run("live balance lookup failed", func(t *testing.T) {
+		r, mockHelper := newReconciler([]*types.AccountCurrency{accountCurrency})
+		mtxn := &mockDatabase.Transaction{}
+		mtxn.On("Discard", ctx).Once()
+		mockHelper.On("DatabaseTransaction", ctx).Return(mtxn).Once()
+		mockHelper.On("CurrentBlock", ctx, mtxn).Return(head, nil).Once()
+		mockHelper.On(
+			"LiveBalance",
+			ctx,
+			accountCurrency.Account,
+			accountCurrency.Currency,
+			block.Index,
+		).Return(nil, nil, errors.New("node unavailable")).Once()
+
+		err := r.spotCheck(ctx)
+		assert.True(t, errors.Is(err, ErrLiveBalanceLookupFailed))
+		mockHelper.AssertExpectations(t)
+		mtxn.AssertExpectations(t)
+	})
+}
+
+func TestReconcile_SpotCheckUnsupported(t *testing.T) {
+	r := New(
+		&mocks.Helper{},
+		&mocks.Handler{},
+		nil,
+		WithSpotCheck(&SpotCheckConfiguration{}),
+	)
+
+	assert.True(t, errors.Is(r.Reconcile(context.Background()), ErrSpotCheckUnsupported))
+	assert.Nil(t, New(&mocks.Helper{}, &mocks.Handler{}, nil).SpotCheckReport())
+}
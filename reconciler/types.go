@@ -129,6 +129,12 @@ const (
 	// processQueueBacklog is the maximum number of blocks
 	// we can get behind the syncing loop without blocking.
 	processQueueBacklog = 1000
+
+	// maxInactiveYield is the maximum amount of time an inactive
+	// reconciliation goroutine yields to active reconciliation
+	// (see WithActivePriority) before reconciling another account.
+	// This ensures inactive reconciliation is never starved.
+	maxInactiveYield = 1 * time.Minute
 )
 
 // Helper functions are used by Reconciler to compare
@@ -238,11 +244,82 @@ type Handler interface {
 	) error
 }
 
+// Queue persists the work enqueued for reconciliation
+// so that it is not lost when the Reconciler is restarted
+// (see WithQueue). Changes are persisted before they are
+// enqueued and removed only after they are reconciled (or
+// skipped), so any change may be reconciled more than once.
+type Queue interface {
+	// LoadQueue returns all persisted changes and
+	// inactive entries. It is invoked once when
+	// Reconcile is called.
+	LoadQueue(
+		ctx context.Context,
+	) ([]*parser.BalanceChange, []*InactiveEntry, error)
+
+	// AddChanges persists changes enqueued for
+	// active reconciliation.
+	AddChanges(
+		ctx context.Context,
+		changes []*parser.BalanceChange,
+	) error
+
+	// RemoveChange removes a change that has
+	// been actively reconciled (or skipped).
+	RemoveChange(
+		ctx context.Context,
+		change *parser.BalanceChange,
+	) error
+
+	// SetInactiveEntry persists the last time an
+	// *types.AccountCurrency in the inactive queue
+	// was reconciled.
+	SetInactiveEntry(
+		ctx context.Context,
+		entry *InactiveEntry,
+	) error
+}
+
 // InactiveEntry is used to track the last
 // time that an *types.AccountCurrency was reconciled.
 type InactiveEntry struct {
-	Entry     *types.AccountCurrency
-	LastCheck *types.BlockIdentifier
+	Entry     *types.AccountCurrency `json:"entry"`
+	LastCheck *types.BlockIdentifier `json:"last_check,omitempty"`
+}
+
+// QueueMetrics describe the work enqueued
+// for reconciliation.
+type QueueMetrics struct {
+	// ActiveDepth is the number of changes
+	// waiting for active reconciliation.
+	ActiveDepth int `json:"active_depth"`
+
+	// OldestActiveAge is how long the oldest change
+	// has been waiting for active reconciliation.
+	OldestActiveAge time.Duration `json:"oldest_active_age"`
+
+	// InactiveDepth is the number of accounts in the
+	// inactive reconciliation queue.
+	InactiveDepth int `json:"inactive_depth"`
+
+	// NeverChecked is the number of accounts in the
+	// inactive reconciliation queue that have not been
+	// reconciled since they were loaded or first seen.
+	NeverChecked int `json:"never_checked"`
+
+	// ParkedDepth is the number of changes waiting
+	// for the node to reach the block they were
+	// computed at (see WithNodeBackoff).
+	ParkedDepth int `json:"parked_depth"`
+
+	// ManualDepth is the number of accounts enqueued
+	// with EnqueueAccount that have not been reconciled.
+	ManualDepth int `json:"manual_depth"`
+
+	// OldestInactiveIndex is the smallest block index at
+	// which an account in the inactive reconciliation queue
+	// was last reconciled (-1 if no account has been checked).
+	OldestInactiveIndex int64 `json:"oldest_inactive_index"`
 }
 
 // blockRequest is used to enqueue processed
@@ -260,10 +337,34 @@ type Reconciler struct {
 	handler Handler
 	parser  *parser.Parser
 
+	// exemptionParser matches the *types.BalanceExemption
+	// provided with WithBalanceExemptions (in addition to
+	// any provided to parser) and tolerance determines if
+	// a difference not covered by any exemption should be
+	// tolerated.
+	exemptionParser *parser.Parser
+	tolerance       ToleranceFunc
+
+	// spotChecker is populated when historical
+	// spot checks are enabled.
+	spotChecker *spotChecker
+
+	// scheduler parks changes until the node catches
+	// up when node backoff is enabled.
+	scheduler *headScheduler
+
+	// results publishes the *Result of each
+	// reconciliation to a ResultSink, if provided.
+	results *resultEmitter
+
 	lookupBalanceByBlock bool
 	interestingAccounts  []*types.AccountCurrency
 	backlogSize          int
 	changeQueue          chan *parser.BalanceChange
+	queue                Queue
+	filter               *Filter
+	failureHandler       FailureHandler
+	coverage             *coverageTracker
 	inactiveFrequency    int64
 	debugLogging         bool
 	balancePruning       bool
@@ -293,6 +394,14 @@ type Reconciler struct {
 	seenAccounts  map[string]struct{}
 	inactiveQueue []*InactiveEntry
 
+	// manualQueue contains accounts enqueued with EnqueueAccount
+	// (reconciled before any account in inactiveQueue) and
+	// priorities contains the priority weight of any account
+	// with a priority other than the DefaultPriority. Both are
+	// protected by inactiveQueueMutex.
+	manualQueue []*InactiveEntry
+	priorities  map[string]float64
+
 	// inactiveQueueMutex needed because we can't peek at the tip
 	// of a channel to determine when it is ready to look at.
 	inactiveQueueMutex *utils.PriorityMutex
@@ -301,12 +410,43 @@ type Reconciler struct {
 	lastIndexMutex   sync.Mutex
 	lastIndexChecked int64
 
+	// activeAccounts are the *types.AccountCurrency being
+	// actively reconciled, mapped to any changes held until
+	// the current reconciliation completes (activeHeld is the
+	// total number of held changes). activeWork is used to
+	// dispatch changes to the active reconciliation workers.
+	activeMutex    sync.Mutex
+	activeAccounts map[string][]*parser.BalanceChange
+	activeHeld     int
+	activeWork     chan *activeWork
+
+	// activePriority is the active backlog size above which
+	// inactive reconciliation yields to active reconciliation.
+	activePriority int
+
 	// queueMap tracks the *types.AccountCurrency items
 	// in the active reconciliation queue and being actively
 	// reconciled. It ensures we don't accidentally attempt to prune
 	// computed balances being used by other goroutines.
 	queueMap *utils.ShardedMap
 
+	// pendingRetries are accounts that should be
+	// reconciled again at some later block (see
+	// RetryVerdict). retryAttempts tracks the number
+	// of retries since each account last succeeded.
+	// verdictExemptions are the *types.BalanceExemption
+	// returned with ExemptVerdict for each account (applied
+	// to all later reconciliations of the account).
+	retryMutex        sync.Mutex
+	pendingRetries    []*pendingRetry
+	retryAttempts     map[string]int
+	verdictExemptions map[string]*types.BalanceExemption
+
+	// activeEnqueued tracks when each change in the
+	// changeQueue was enqueued (to report QueueMetrics).
+	activeEnqueuedMutex sync.Mutex
+	activeEnqueued      map[*parser.BalanceChange]time.Time
+
 	// processQueue is a buffered channel of recently processed
 	// blocks that must be parsed for reconciliation. We enqueue
 	// blocks asynchronously so that we don't slow down the sync
@@ -0,0 +1,553 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/coinbase/rosetta-sdk-go/pb"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Client communicates with a Rosetta server over gRPC. Like
+// the client package, each call returns a *types.Error when the
+// server returns one.
+type Client struct {
+	account      pb.AccountServiceClient
+	block        pb.BlockServiceClient
+	call         pb.CallServiceClient
+	construction pb.ConstructionServiceClient
+	events       pb.EventsServiceClient
+	mempool      pb.MempoolServiceClient
+	network      pb.NetworkServiceClient
+	search       pb.SearchServiceClient
+}
+
+// NewClient creates a new Client that
+// makes calls using conn.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{
+		account:      pb.NewAccountServiceClient(conn),
+		block:        pb.NewBlockServiceClient(conn),
+		call:         pb.NewCallServiceClient(conn),
+		construction: pb.NewConstructionServiceClient(conn),
+		events:       pb.NewEventsServiceClient(conn),
+		mempool:      pb.NewMempoolServiceClient(conn),
+		network:      pb.NewNetworkServiceClient(conn),
+		search:       pb.NewSearchServiceClient(conn),
+	}
+}
+
+// AccountBalance calls AccountBalance on the AccountService.
+func (c *Client) AccountBalance(
+	ctx context.Context,
+	request *types.AccountBalanceRequest,
+	opts ...grpc.CallOption,
+) (*types.AccountBalanceResponse, *types.Error, error) {
+	req, err := pb.FromAccountBalanceRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.account.AccountBalance(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToAccountBalanceResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// AccountCoins calls AccountCoins on the AccountService.
+func (c *Client) AccountCoins(
+	ctx context.Context,
+	request *types.AccountCoinsRequest,
+	opts ...grpc.CallOption,
+) (*types.AccountCoinsResponse, *types.Error, error) {
+	req, err := pb.FromAccountCoinsRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.account.AccountCoins(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToAccountCoinsResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// Block calls Block on the BlockService.
+func (c *Client) Block(
+	ctx context.Context,
+	request *types.BlockRequest,
+	opts ...grpc.CallOption,
+) (*types.BlockResponse, *types.Error, error) {
+	req, err := pb.FromBlockRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.block.Block(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToBlockResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// BlockTransaction calls BlockTransaction on the BlockService.
+func (c *Client) BlockTransaction(
+	ctx context.Context,
+	request *types.BlockTransactionRequest,
+	opts ...grpc.CallOption,
+) (*types.BlockTransactionResponse, *types.Error, error) {
+	req, err := pb.FromBlockTransactionRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.block.BlockTransaction(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToBlockTransactionResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// Call calls Call on the CallService.
+func (c *Client) Call(
+	ctx context.Context,
+	request *types.CallRequest,
+	opts ...grpc.CallOption,
+) (*types.CallResponse, *types.Error, error) {
+	req, err := pb.FromCallRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.call.Call(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToCallResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionCombine calls ConstructionCombine on the ConstructionService.
+func (c *Client) ConstructionCombine(
+	ctx context.Context,
+	request *types.ConstructionCombineRequest,
+	opts ...grpc.CallOption,
+) (*types.ConstructionCombineResponse, *types.Error, error) {
+	req, err := pb.FromConstructionCombineRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionCombine(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToConstructionCombineResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionDerive calls ConstructionDerive on the ConstructionService.
+func (c *Client) ConstructionDerive(
+	ctx context.Context,
+	request *types.ConstructionDeriveRequest,
+	opts ...grpc.CallOption,
+) (*types.ConstructionDeriveResponse, *types.Error, error) {
+	req, err := pb.FromConstructionDeriveRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionDerive(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToConstructionDeriveResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionHash calls ConstructionHash on the ConstructionService.
+func (c *Client) ConstructionHash(
+	ctx context.Context,
+	request *types.ConstructionHashRequest,
+	opts ...grpc.CallOption,
+) (*types.TransactionIdentifierResponse, *types.Error, error) {
+	req, err := pb.FromConstructionHashRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionHash(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToTransactionIdentifierResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionMetadata calls ConstructionMetadata on the ConstructionService.
+func (c *Client) ConstructionMetadata(
+	ctx context.Context,
+	request *types.ConstructionMetadataRequest,
+	opts ...grpc.CallOption,
+) (*types.ConstructionMetadataResponse, *types.Error, error) {
+	req, err := pb.FromConstructionMetadataRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionMetadata(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToConstructionMetadataResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionParse calls ConstructionParse on the ConstructionService.
+func (c *Client) ConstructionParse(
+	ctx context.Context,
+	request *types.ConstructionParseRequest,
+	opts ...grpc.CallOption,
+) (*types.ConstructionParseResponse, *types.Error, error) {
+	req, err := pb.FromConstructionParseRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionParse(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToConstructionParseResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionPayloads calls ConstructionPayloads on the ConstructionService.
+func (c *Client) ConstructionPayloads(
+	ctx context.Context,
+	request *types.ConstructionPayloadsRequest,
+	opts ...grpc.CallOption,
+) (*types.ConstructionPayloadsResponse, *types.Error, error) {
+	req, err := pb.FromConstructionPayloadsRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionPayloads(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToConstructionPayloadsResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionPreprocess calls ConstructionPreprocess on the ConstructionService.
+func (c *Client) ConstructionPreprocess(
+	ctx context.Context,
+	request *types.ConstructionPreprocessRequest,
+	opts ...grpc.CallOption,
+) (*types.ConstructionPreprocessResponse, *types.Error, error) {
+	req, err := pb.FromConstructionPreprocessRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionPreprocess(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToConstructionPreprocessResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// ConstructionSubmit calls ConstructionSubmit on the ConstructionService.
+func (c *Client) ConstructionSubmit(
+	ctx context.Context,
+	request *types.ConstructionSubmitRequest,
+	opts ...grpc.CallOption,
+) (*types.TransactionIdentifierResponse, *types.Error, error) {
+	req, err := pb.FromConstructionSubmitRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.construction.ConstructionSubmit(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToTransactionIdentifierResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// EventsBlocks calls EventsBlocks on the EventsService.
+func (c *Client) EventsBlocks(
+	ctx context.Context,
+	request *types.EventsBlocksRequest,
+	opts ...grpc.CallOption,
+) (*types.EventsBlocksResponse, *types.Error, error) {
+	req, err := pb.FromEventsBlocksRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.events.EventsBlocks(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToEventsBlocksResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// Mempool calls Mempool on the MempoolService.
+func (c *Client) Mempool(
+	ctx context.Context,
+	request *types.NetworkRequest,
+	opts ...grpc.CallOption,
+) (*types.MempoolResponse, *types.Error, error) {
+	req, err := pb.FromNetworkRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.mempool.Mempool(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToMempoolResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// MempoolTransaction calls MempoolTransaction on the MempoolService.
+func (c *Client) MempoolTransaction(
+	ctx context.Context,
+	request *types.MempoolTransactionRequest,
+	opts ...grpc.CallOption,
+) (*types.MempoolTransactionResponse, *types.Error, error) {
+	req, err := pb.FromMempoolTransactionRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.mempool.MempoolTransaction(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToMempoolTransactionResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// NetworkList calls NetworkList on the NetworkService.
+func (c *Client) NetworkList(
+	ctx context.Context,
+	request *types.MetadataRequest,
+	opts ...grpc.CallOption,
+) (*types.NetworkListResponse, *types.Error, error) {
+	req, err := pb.FromMetadataRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.network.NetworkList(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToNetworkListResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// NetworkOptions calls NetworkOptions on the NetworkService.
+func (c *Client) NetworkOptions(
+	ctx context.Context,
+	request *types.NetworkRequest,
+	opts ...grpc.CallOption,
+) (*types.NetworkOptionsResponse, *types.Error, error) {
+	req, err := pb.FromNetworkRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.network.NetworkOptions(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToNetworkOptionsResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// NetworkStatus calls NetworkStatus on the NetworkService.
+func (c *Client) NetworkStatus(
+	ctx context.Context,
+	request *types.NetworkRequest,
+	opts ...grpc.CallOption,
+) (*types.NetworkStatusResponse, *types.Error, error) {
+	req, err := pb.FromNetworkRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.network.NetworkStatus(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToNetworkStatusResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// SearchTransactions calls SearchTransactions on the SearchService.
+func (c *Client) SearchTransactions(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+	opts ...grpc.CallOption,
+) (*types.SearchTransactionsResponse, *types.Error, error) {
+	req, err := pb.FromSearchTransactionsRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := c.search.SearchTransactions(ctx, req, opts...)
+	if err != nil {
+		rosettaErr, err := callError(err)
+		return nil, rosettaErr, err
+	}
+
+	response, err := pb.ToSearchTransactionsResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/coinbase/rosetta-sdk-go/client"
+	"github.com/coinbase/rosetta-sdk-go/pb"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// rosettaStatus returns a gRPC status error with code
+// that carries err as a detail.
+func rosettaStatus(code codes.Code, err *types.Error) error {
+	st := status.New(code, err.Message)
+	detail, convertErr := pb.FromError(err)
+	if convertErr != nil {
+		return st.Err()
+	}
+
+	withDetails, detailsErr := st.WithDetails(detail)
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// invalidRequestError is returned when a request
+// cannot be converted or is rejected by the asserter
+// (the HTTP controllers return a *types.Error with
+// only a message in this case).
+func invalidRequestError(err error) error {
+	return rosettaStatus(codes.InvalidArgument, &types.Error{Message: err.Error()})
+}
+
+// serviceError is returned when a servicer
+// returns a *types.Error.
+func serviceError(err *types.Error) error {
+	return rosettaStatus(codes.Internal, err)
+}
+
+// internalError is returned when a
+// response cannot be converted.
+func internalError(err error) error {
+	return rosettaStatus(codes.Internal, &types.Error{Message: err.Error()})
+}
+
+// callError extracts the *types.Error (if any) from
+// an error returned by a gRPC call. Like the client
+// package, transient errors wrap client.ErrRetriable.
+func callError(err error) (*types.Error, error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, err
+	}
+
+	for _, detail := range st.Details() {
+		pbErr, ok := detail.(*pb.Error)
+		if !ok {
+			continue
+		}
+
+		rosettaErr, convertErr := pb.ToError(pbErr)
+		if convertErr != nil || rosettaErr == nil {
+			continue
+		}
+
+		return rosettaErr, fmt.Errorf("%+v", *rosettaErr)
+	}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return nil, fmt.Errorf("%w: code: %s message: %s", client.ErrRetriable, st.Code(), st.Message())
+	default:
+		return nil, err
+	}
+}
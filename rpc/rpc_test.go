@@ -0,0 +1,179 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/client"
+	"github.com/coinbase/rosetta-sdk-go/pb"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	network = &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Mainnet",
+	}
+
+	account = &types.AccountIdentifier{
+		Address: "addr",
+	}
+
+	errAccountMissing = &types.Error{
+		Code:      12,
+		Message:   "account missing",
+		Retriable: true,
+		Details:   map[string]interface{}{"address": "addr"},
+	}
+)
+
+type accountServicer struct{}
+
+func (s *accountServicer) AccountBalance(
+	ctx context.Context,
+	request *types.AccountBalanceRequest,
+) (*types.AccountBalanceResponse, *types.Error) {
+	if request.AccountIdentifier.Address != account.Address {
+		return nil, errAccountMissing
+	}
+
+	return &types.AccountBalanceResponse{
+		BlockIdentifier: &types.BlockIdentifier{Index: 10, Hash: "block 10"},
+		Balances: []*types.Amount{
+			{
+				Value:    "100",
+				Currency: &types.Currency{Symbol: "BTC", Decimals: 8},
+			},
+		},
+		Metadata: map[string]interface{}{"sequence": float64(1)},
+	}, nil
+}
+
+func (s *accountServicer) AccountCoins(
+	ctx context.Context,
+	request *types.AccountCoinsRequest,
+) (*types.AccountCoinsResponse, *types.Error) {
+	return &types.AccountCoinsResponse{
+		BlockIdentifier: &types.BlockIdentifier{Index: 10, Hash: "block 10"},
+		Coins:           []*types.Coin{},
+	}, nil
+}
+
+func newClient(t *testing.T, servicer *accountServicer) (*Client, func()) {
+	a, err := asserter.NewServer(
+		[]string{"TRANSFER"},
+		false,
+		[]*types.NetworkIdentifier{network},
+		nil,
+		false,
+		"",
+	)
+	assert.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterAccountServiceServer(s, NewAccountServer(servicer, a))
+	go s.Serve(listener) // nolint:errcheck
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	return NewClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func TestAccountBalance(t *testing.T) {
+	c, stop := newClient(t, &accountServicer{})
+	defer stop()
+
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		response, rosettaErr, err := c.AccountBalance(ctx, &types.AccountBalanceRequest{
+			NetworkIdentifier: network,
+			AccountIdentifier: account,
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, rosettaErr)
+		assert.Equal(t, "100", response.Balances[0].Value)
+		assert.Equal(t, int64(10), response.BlockIdentifier.Index)
+		assert.Equal(t, map[string]interface{}{"sequence": float64(1)}, response.Metadata)
+	})
+
+	t.Run("servicer error", func(t *testing.T) {
+		response, rosettaErr, err := c.AccountBalance(ctx, &types.AccountBalanceRequest{
+			NetworkIdentifier: network,
+			AccountIdentifier: &types.AccountIdentifier{Address: "other"},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		assert.Equal(t, errAccountMissing, rosettaErr)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		response, rosettaErr, err := c.AccountBalance(ctx, &types.AccountBalanceRequest{
+			NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"},
+			AccountIdentifier: account,
+		})
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		assert.NotNil(t, rosettaErr)
+		assert.Contains(t, rosettaErr.Message, asserter.ErrRequestedNetworkNotSupported.Error())
+	})
+
+	t.Run("required arrays", func(t *testing.T) {
+		response, rosettaErr, err := c.AccountCoins(ctx, &types.AccountCoinsRequest{
+			NetworkIdentifier: network,
+			AccountIdentifier: account,
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, rosettaErr)
+		assert.Equal(t, []*types.Coin{}, response.Coins)
+	})
+}
+
+func TestUnimplemented(t *testing.T) {
+	c, stop := newClient(t, &accountServicer{})
+	defer stop()
+
+	// The BlockService is not registered.
+	response, rosettaErr, err := c.Block(context.Background(), &types.BlockRequest{
+		NetworkIdentifier: network,
+		BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(1)},
+	})
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, client.ErrRetriable))
+	assert.Nil(t, rosettaErr)
+	assert.Nil(t, response)
+}
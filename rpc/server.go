@@ -0,0 +1,723 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/pb"
+	"github.com/coinbase/rosetta-sdk-go/server"
+)
+
+// AccountServer serves the Account API over gRPC
+// using a server.AccountAPIServicer.
+type AccountServer struct {
+	pb.UnimplementedAccountServiceServer
+
+	servicer server.AccountAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewAccountServer creates a new AccountServer.
+func NewAccountServer(
+	servicer server.AccountAPIServicer,
+	asserter *asserter.Asserter,
+) *AccountServer {
+	return &AccountServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// AccountBalance implements pb.AccountServiceServer.
+func (s *AccountServer) AccountBalance(
+	ctx context.Context,
+	req *pb.AccountBalanceRequest,
+) (*pb.AccountBalanceResponse, error) {
+	request, err := pb.ToAccountBalanceRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.AccountBalanceRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.AccountBalance(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromAccountBalanceResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// AccountCoins implements pb.AccountServiceServer.
+func (s *AccountServer) AccountCoins(
+	ctx context.Context,
+	req *pb.AccountCoinsRequest,
+) (*pb.AccountCoinsResponse, error) {
+	request, err := pb.ToAccountCoinsRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.AccountCoinsRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.AccountCoins(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromAccountCoinsResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// BlockServer serves the Block API over gRPC
+// using a server.BlockAPIServicer.
+type BlockServer struct {
+	pb.UnimplementedBlockServiceServer
+
+	servicer server.BlockAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewBlockServer creates a new BlockServer.
+func NewBlockServer(
+	servicer server.BlockAPIServicer,
+	asserter *asserter.Asserter,
+) *BlockServer {
+	return &BlockServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// Block implements pb.BlockServiceServer.
+func (s *BlockServer) Block(
+	ctx context.Context,
+	req *pb.BlockRequest,
+) (*pb.BlockResponse, error) {
+	request, err := pb.ToBlockRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.BlockRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.Block(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromBlockResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// BlockTransaction implements pb.BlockServiceServer.
+func (s *BlockServer) BlockTransaction(
+	ctx context.Context,
+	req *pb.BlockTransactionRequest,
+) (*pb.BlockTransactionResponse, error) {
+	request, err := pb.ToBlockTransactionRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.BlockTransactionRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.BlockTransaction(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromBlockTransactionResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// CallServer serves the Call API over gRPC
+// using a server.CallAPIServicer.
+type CallServer struct {
+	pb.UnimplementedCallServiceServer
+
+	servicer server.CallAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewCallServer creates a new CallServer.
+func NewCallServer(
+	servicer server.CallAPIServicer,
+	asserter *asserter.Asserter,
+) *CallServer {
+	return &CallServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// Call implements pb.CallServiceServer.
+func (s *CallServer) Call(
+	ctx context.Context,
+	req *pb.CallRequest,
+) (*pb.CallResponse, error) {
+	request, err := pb.ToCallRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.CallRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.Call(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromCallResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionServer serves the Construction API over gRPC
+// using a server.ConstructionAPIServicer.
+type ConstructionServer struct {
+	pb.UnimplementedConstructionServiceServer
+
+	servicer server.ConstructionAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewConstructionServer creates a new ConstructionServer.
+func NewConstructionServer(
+	servicer server.ConstructionAPIServicer,
+	asserter *asserter.Asserter,
+) *ConstructionServer {
+	return &ConstructionServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// ConstructionCombine implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionCombine(
+	ctx context.Context,
+	req *pb.ConstructionCombineRequest,
+) (*pb.ConstructionCombineResponse, error) {
+	request, err := pb.ToConstructionCombineRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionCombineRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionCombine(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromConstructionCombineResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionDerive implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionDerive(
+	ctx context.Context,
+	req *pb.ConstructionDeriveRequest,
+) (*pb.ConstructionDeriveResponse, error) {
+	request, err := pb.ToConstructionDeriveRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionDeriveRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionDerive(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromConstructionDeriveResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionHash implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionHash(
+	ctx context.Context,
+	req *pb.ConstructionHashRequest,
+) (*pb.TransactionIdentifierResponse, error) {
+	request, err := pb.ToConstructionHashRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionHashRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionHash(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromTransactionIdentifierResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionMetadata implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionMetadata(
+	ctx context.Context,
+	req *pb.ConstructionMetadataRequest,
+) (*pb.ConstructionMetadataResponse, error) {
+	request, err := pb.ToConstructionMetadataRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionMetadataRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionMetadata(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromConstructionMetadataResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionParse implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionParse(
+	ctx context.Context,
+	req *pb.ConstructionParseRequest,
+) (*pb.ConstructionParseResponse, error) {
+	request, err := pb.ToConstructionParseRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionParseRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionParse(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromConstructionParseResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionPayloads implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionPayloads(
+	ctx context.Context,
+	req *pb.ConstructionPayloadsRequest,
+) (*pb.ConstructionPayloadsResponse, error) {
+	request, err := pb.ToConstructionPayloadsRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionPayloadsRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionPayloads(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromConstructionPayloadsResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionPreprocess implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionPreprocess(
+	ctx context.Context,
+	req *pb.ConstructionPreprocessRequest,
+) (*pb.ConstructionPreprocessResponse, error) {
+	request, err := pb.ToConstructionPreprocessRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionPreprocessRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionPreprocess(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromConstructionPreprocessResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// ConstructionSubmit implements pb.ConstructionServiceServer.
+func (s *ConstructionServer) ConstructionSubmit(
+	ctx context.Context,
+	req *pb.ConstructionSubmitRequest,
+) (*pb.TransactionIdentifierResponse, error) {
+	request, err := pb.ToConstructionSubmitRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.ConstructionSubmitRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.ConstructionSubmit(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromTransactionIdentifierResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// EventsServer serves the Events API over gRPC
+// using a server.EventsAPIServicer.
+type EventsServer struct {
+	pb.UnimplementedEventsServiceServer
+
+	servicer server.EventsAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewEventsServer creates a new EventsServer.
+func NewEventsServer(
+	servicer server.EventsAPIServicer,
+	asserter *asserter.Asserter,
+) *EventsServer {
+	return &EventsServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// EventsBlocks implements pb.EventsServiceServer.
+func (s *EventsServer) EventsBlocks(
+	ctx context.Context,
+	req *pb.EventsBlocksRequest,
+) (*pb.EventsBlocksResponse, error) {
+	request, err := pb.ToEventsBlocksRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.EventsBlocksRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.EventsBlocks(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromEventsBlocksResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// MempoolServer serves the Mempool API over gRPC
+// using a server.MempoolAPIServicer.
+type MempoolServer struct {
+	pb.UnimplementedMempoolServiceServer
+
+	servicer server.MempoolAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewMempoolServer creates a new MempoolServer.
+func NewMempoolServer(
+	servicer server.MempoolAPIServicer,
+	asserter *asserter.Asserter,
+) *MempoolServer {
+	return &MempoolServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// Mempool implements pb.MempoolServiceServer.
+func (s *MempoolServer) Mempool(
+	ctx context.Context,
+	req *pb.NetworkRequest,
+) (*pb.MempoolResponse, error) {
+	request, err := pb.ToNetworkRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.NetworkRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.Mempool(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromMempoolResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// MempoolTransaction implements pb.MempoolServiceServer.
+func (s *MempoolServer) MempoolTransaction(
+	ctx context.Context,
+	req *pb.MempoolTransactionRequest,
+) (*pb.MempoolTransactionResponse, error) {
+	request, err := pb.ToMempoolTransactionRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.MempoolTransactionRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.MempoolTransaction(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromMempoolTransactionResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// NetworkServer serves the Network API over gRPC
+// using a server.NetworkAPIServicer.
+type NetworkServer struct {
+	pb.UnimplementedNetworkServiceServer
+
+	servicer server.NetworkAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewNetworkServer creates a new NetworkServer.
+func NewNetworkServer(
+	servicer server.NetworkAPIServicer,
+	asserter *asserter.Asserter,
+) *NetworkServer {
+	return &NetworkServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// NetworkList implements pb.NetworkServiceServer.
+func (s *NetworkServer) NetworkList(
+	ctx context.Context,
+	req *pb.MetadataRequest,
+) (*pb.NetworkListResponse, error) {
+	request, err := pb.ToMetadataRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.MetadataRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.NetworkList(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromNetworkListResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// NetworkOptions implements pb.NetworkServiceServer.
+func (s *NetworkServer) NetworkOptions(
+	ctx context.Context,
+	req *pb.NetworkRequest,
+) (*pb.NetworkOptionsResponse, error) {
+	request, err := pb.ToNetworkRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.NetworkRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.NetworkOptions(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromNetworkOptionsResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// NetworkStatus implements pb.NetworkServiceServer.
+func (s *NetworkServer) NetworkStatus(
+	ctx context.Context,
+	req *pb.NetworkRequest,
+) (*pb.NetworkStatusResponse, error) {
+	request, err := pb.ToNetworkRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.NetworkRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.NetworkStatus(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromNetworkStatusResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
+
+// SearchServer serves the Search API over gRPC
+// using a server.SearchAPIServicer.
+type SearchServer struct {
+	pb.UnimplementedSearchServiceServer
+
+	servicer server.SearchAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewSearchServer creates a new SearchServer.
+func NewSearchServer(
+	servicer server.SearchAPIServicer,
+	asserter *asserter.Asserter,
+) *SearchServer {
+	return &SearchServer{
+		servicer: servicer,
+		asserter: asserter,
+	}
+}
+
+// SearchTransactions implements pb.SearchServiceServer.
+func (s *SearchServer) SearchTransactions(
+	ctx context.Context,
+	req *pb.SearchTransactionsRequest,
+) (*pb.SearchTransactionsResponse, error) {
+	request, err := pb.ToSearchTransactionsRequest(req)
+	if err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	if err := s.asserter.SearchTransactionsRequest(request); err != nil {
+		return nil, invalidRequestError(err)
+	}
+
+	response, serviceErr := s.servicer.SearchTransactions(ctx, request)
+	if serviceErr != nil {
+		return nil, serviceError(serviceErr)
+	}
+
+	result, err := pb.FromSearchTransactionsResponse(response)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return result, nil
+}
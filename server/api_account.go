@@ -17,7 +17,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -30,16 +29,19 @@ import (
 type AccountAPIController struct {
 	service  AccountAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewAccountAPIController creates a default api controller
 func NewAccountAPIController(
 	s AccountAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &AccountAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig(options),
 	}
 }
 
@@ -64,26 +66,22 @@ func (c *AccountAPIController) Routes() Routes {
 // AccountBalance - Get an Account's Balance
 func (c *AccountAPIController) AccountBalance(w http.ResponseWriter, r *http.Request) {
 	accountBalanceRequest := &types.AccountBalanceRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&accountBalanceRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, accountBalanceRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that AccountBalanceRequest is correct
 	if err := c.asserter.AccountBalanceRequest(accountBalanceRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.AccountBalance(r.Context(), accountBalanceRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -94,26 +92,22 @@ func (c *AccountAPIController) AccountBalance(w http.ResponseWriter, r *http.Req
 // AccountCoins - Get an Account's Unspent Coins
 func (c *AccountAPIController) AccountCoins(w http.ResponseWriter, r *http.Request) {
 	accountCoinsRequest := &types.AccountCoinsRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&accountCoinsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, accountCoinsRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that AccountCoinsRequest is correct
 	if err := c.asserter.AccountCoinsRequest(accountCoinsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.AccountCoins(r.Context(), accountCoinsRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -17,7 +17,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -30,16 +29,19 @@ import (
 type BlockAPIController struct {
 	service  BlockAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewBlockAPIController creates a default api controller
 func NewBlockAPIController(
 	s BlockAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &BlockAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig(options),
 	}
 }
 
@@ -64,26 +66,22 @@ func (c *BlockAPIController) Routes() Routes {
 // Block - Get a Block
 func (c *BlockAPIController) Block(w http.ResponseWriter, r *http.Request) {
 	blockRequest := &types.BlockRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&blockRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, blockRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that BlockRequest is correct
 	if err := c.asserter.BlockRequest(blockRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.Block(r.Context(), blockRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -94,26 +92,22 @@ func (c *BlockAPIController) Block(w http.ResponseWriter, r *http.Request) {
 // BlockTransaction - Get a Block Transaction
 func (c *BlockAPIController) BlockTransaction(w http.ResponseWriter, r *http.Request) {
 	blockTransactionRequest := &types.BlockTransactionRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&blockTransactionRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, blockTransactionRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that BlockTransactionRequest is correct
 	if err := c.asserter.BlockTransactionRequest(blockTransactionRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.BlockTransaction(r.Context(), blockTransactionRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -17,7 +17,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -30,16 +29,19 @@ import (
 type CallAPIController struct {
 	service  CallAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewCallAPIController creates a default api controller
 func NewCallAPIController(
 	s CallAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &CallAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig(options),
 	}
 }
 
@@ -58,26 +60,22 @@ func (c *CallAPIController) Routes() Routes {
 // Call - Make a Network-Specific Procedure Call
 func (c *CallAPIController) Call(w http.ResponseWriter, r *http.Request) {
 	callRequest := &types.CallRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&callRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, callRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that CallRequest is correct
 	if err := c.asserter.CallRequest(callRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.Call(r.Context(), callRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -17,7 +17,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -30,16 +29,19 @@ import (
 type ConstructionAPIController struct {
 	service  ConstructionAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewConstructionAPIController creates a default api controller
 func NewConstructionAPIController(
 	s ConstructionAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &ConstructionAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig(options),
 	}
 }
 
@@ -100,26 +102,22 @@ func (c *ConstructionAPIController) Routes() Routes {
 // ConstructionCombine - Create Network Transaction from Signatures
 func (c *ConstructionAPIController) ConstructionCombine(w http.ResponseWriter, r *http.Request) {
 	constructionCombineRequest := &types.ConstructionCombineRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionCombineRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionCombineRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionCombineRequest is correct
 	if err := c.asserter.ConstructionCombineRequest(constructionCombineRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionCombine(r.Context(), constructionCombineRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -130,26 +128,22 @@ func (c *ConstructionAPIController) ConstructionCombine(w http.ResponseWriter, r
 // ConstructionDerive - Derive an AccountIdentifier from a PublicKey
 func (c *ConstructionAPIController) ConstructionDerive(w http.ResponseWriter, r *http.Request) {
 	constructionDeriveRequest := &types.ConstructionDeriveRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionDeriveRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionDeriveRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionDeriveRequest is correct
 	if err := c.asserter.ConstructionDeriveRequest(constructionDeriveRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionDerive(r.Context(), constructionDeriveRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -160,26 +154,22 @@ func (c *ConstructionAPIController) ConstructionDerive(w http.ResponseWriter, r
 // ConstructionHash - Get the Hash of a Signed Transaction
 func (c *ConstructionAPIController) ConstructionHash(w http.ResponseWriter, r *http.Request) {
 	constructionHashRequest := &types.ConstructionHashRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionHashRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionHashRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionHashRequest is correct
 	if err := c.asserter.ConstructionHashRequest(constructionHashRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionHash(r.Context(), constructionHashRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -190,26 +180,22 @@ func (c *ConstructionAPIController) ConstructionHash(w http.ResponseWriter, r *h
 // ConstructionMetadata - Get Metadata for Transaction Construction
 func (c *ConstructionAPIController) ConstructionMetadata(w http.ResponseWriter, r *http.Request) {
 	constructionMetadataRequest := &types.ConstructionMetadataRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionMetadataRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionMetadataRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionMetadataRequest is correct
 	if err := c.asserter.ConstructionMetadataRequest(constructionMetadataRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionMetadata(r.Context(), constructionMetadataRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -220,26 +206,22 @@ func (c *ConstructionAPIController) ConstructionMetadata(w http.ResponseWriter,
 // ConstructionParse - Parse a Transaction
 func (c *ConstructionAPIController) ConstructionParse(w http.ResponseWriter, r *http.Request) {
 	constructionParseRequest := &types.ConstructionParseRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionParseRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionParseRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionParseRequest is correct
 	if err := c.asserter.ConstructionParseRequest(constructionParseRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionParse(r.Context(), constructionParseRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -250,26 +232,22 @@ func (c *ConstructionAPIController) ConstructionParse(w http.ResponseWriter, r *
 // ConstructionPayloads - Generate an Unsigned Transaction and Signing Payloads
 func (c *ConstructionAPIController) ConstructionPayloads(w http.ResponseWriter, r *http.Request) {
 	constructionPayloadsRequest := &types.ConstructionPayloadsRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionPayloadsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionPayloadsRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionPayloadsRequest is correct
 	if err := c.asserter.ConstructionPayloadsRequest(constructionPayloadsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionPayloads(r.Context(), constructionPayloadsRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -280,19 +258,15 @@ func (c *ConstructionAPIController) ConstructionPayloads(w http.ResponseWriter,
 // ConstructionPreprocess - Create a Request to Fetch Metadata
 func (c *ConstructionAPIController) ConstructionPreprocess(w http.ResponseWriter, r *http.Request) {
 	constructionPreprocessRequest := &types.ConstructionPreprocessRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionPreprocessRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionPreprocessRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionPreprocessRequest is correct
 	if err := c.asserter.ConstructionPreprocessRequest(constructionPreprocessRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
@@ -302,7 +276,7 @@ func (c *ConstructionAPIController) ConstructionPreprocess(w http.ResponseWriter
 		constructionPreprocessRequest,
 	)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -313,26 +287,22 @@ func (c *ConstructionAPIController) ConstructionPreprocess(w http.ResponseWriter
 // ConstructionSubmit - Submit a Signed Transaction
 func (c *ConstructionAPIController) ConstructionSubmit(w http.ResponseWriter, r *http.Request) {
 	constructionSubmitRequest := &types.ConstructionSubmitRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&constructionSubmitRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, constructionSubmitRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that ConstructionSubmitRequest is correct
 	if err := c.asserter.ConstructionSubmitRequest(constructionSubmitRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.ConstructionSubmit(r.Context(), constructionSubmitRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
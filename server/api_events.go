@@ -17,7 +17,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -30,16 +29,19 @@ import (
 type EventsAPIController struct {
 	service  EventsAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewEventsAPIController creates a default api controller
 func NewEventsAPIController(
 	s EventsAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &EventsAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig(options),
 	}
 }
 
@@ -58,26 +60,22 @@ func (c *EventsAPIController) Routes() Routes {
 // EventsBlocks - [INDEXER] Get a range of BlockEvents
 func (c *EventsAPIController) EventsBlocks(w http.ResponseWriter, r *http.Request) {
 	eventsBlocksRequest := &types.EventsBlocksRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&eventsBlocksRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, eventsBlocksRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that EventsBlocksRequest is correct
 	if err := c.asserter.EventsBlocksRequest(eventsBlocksRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.EventsBlocks(r.Context(), eventsBlocksRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
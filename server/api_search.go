@@ -17,7 +17,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -30,16 +29,19 @@ import (
 type SearchAPIController struct {
 	service  SearchAPIServicer
 	asserter *asserter.Asserter
+	config   *controllerConfig
 }
 
 // NewSearchAPIController creates a default api controller
 func NewSearchAPIController(
 	s SearchAPIServicer,
 	asserter *asserter.Asserter,
+	options ...ControllerOption,
 ) Router {
 	return &SearchAPIController{
 		service:  s,
 		asserter: asserter,
+		config:   newControllerConfig(options),
 	}
 }
 
@@ -58,26 +60,22 @@ func (c *SearchAPIController) Routes() Routes {
 // SearchTransactions - [INDEXER] Search for Transactions
 func (c *SearchAPIController) SearchTransactions(w http.ResponseWriter, r *http.Request) {
 	searchTransactionsRequest := &types.SearchTransactionsRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&searchTransactionsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+	if err := c.config.decode(r, searchTransactionsRequest); err != nil {
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	// Assert that SearchTransactionsRequest is correct
 	if err := c.asserter.SearchTransactionsRequest(searchTransactionsRequest); err != nil {
-		EncodeJSONResponse(&types.Error{
-			Message: err.Error(),
-		}, http.StatusInternalServerError, w)
+		c.config.encodeRequestError(err, w)
 
 		return
 	}
 
 	result, serviceErr := c.service.SearchTransactions(r.Context(), searchTransactionsRequest)
 	if serviceErr != nil {
-		EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+		c.config.encodeServiceError(serviceErr, w)
 
 		return
 	}
@@ -0,0 +1,258 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// DefaultAPIKeyHeader is the header that contains
+	// the API key of a request.
+	DefaultAPIKeyHeader = "X-API-Key"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when
+	// a request does not contain any credentials it supports.
+	ErrNoCredentials = errors.New("no credentials")
+
+	// ErrInvalidCredentials is returned by an Authenticator
+	// when the credentials in a request are not valid.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// DataAPIPathPrefixes are the path prefixes of all
+// Data API routes (ex: to make them public with an
+// *AuthRule).
+var DataAPIPathPrefixes = []string{
+	"/network/",
+	"/account/",
+	"/block",
+	"/mempool",
+	"/events/",
+	"/search/",
+	"/call",
+}
+
+// Authenticator authenticates an *http.Request and returns the
+// identity of the caller (ex: the name of an API key or the
+// subject of a JWT). If the request does not contain any
+// credentials the Authenticator supports, ErrNoCredentials
+// is returned.
+type Authenticator interface {
+	Authenticate(*http.Request) (string, error)
+}
+
+// AuthRule determines how requests to all
+// routes under PathPrefix are authorized.
+type AuthRule struct {
+	PathPrefix string
+
+	// Public routes do not require
+	// authentication.
+	Public bool
+
+	// Identities are the only identities allowed to
+	// access the routes. If empty, any authenticated
+	// caller is allowed.
+	Identities []string
+}
+
+// PublicRules returns a public *AuthRule
+// for each prefix in prefixes.
+func PublicRules(prefixes ...string) []*AuthRule {
+	rules := make([]*AuthRule, len(prefixes))
+	for i, prefix := range prefixes {
+		rules[i] = &AuthRule{PathPrefix: prefix, Public: true}
+	}
+
+	return rules
+}
+
+// AuthConfig configures AuthMiddleware.
+type AuthConfig struct {
+	// Authenticators are attempted in order until
+	// one finds credentials in a request.
+	Authenticators []Authenticator
+
+	// Rules are matched by the longest PathPrefix. Requests
+	// to routes without a matching rule must be authenticated.
+	Rules []*AuthRule
+
+	// Errors configures ErrUnauthorized and
+	// ErrForbidden (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+type identityContextKey struct{}
+
+// Identity returns the identity of the caller
+// authenticated by AuthMiddleware (if any).
+func Identity(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(string)
+	return identity, ok
+}
+
+// AuthMiddleware authenticates and authorizes requests
+// according to config. The identity of an authenticated
+// caller is available to Servicers with Identity.
+func AuthMiddleware(config *AuthConfig) Middleware {
+	rules := map[string]*AuthRule{}
+	prefixes := []string{}
+	for _, rule := range config.Rules {
+		rules[rule.PathPrefix] = rule
+		prefixes = append(prefixes, rule.PathPrefix)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var rule *AuthRule
+			if prefix, ok := matchPrefix(r.URL.Path, prefixes); ok {
+				rule = rules[prefix]
+			}
+
+			if rule != nil && rule.Public {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			identity, err := authenticate(config.Authenticators, r)
+			if err != nil {
+				config.Errors.encode(ErrUnauthorized, err.Error(), w)
+				return
+			}
+
+			if rule != nil && len(rule.Identities) > 0 && !containsIdentity(rule.Identities, identity) {
+				config.Errors.encode(
+					ErrForbidden,
+					fmt.Sprintf("%s cannot access %s", identity, r.URL.Path),
+					w,
+				)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), identityContextKey{}, identity)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authenticate returns the identity from the first
+// Authenticator that finds credentials in r.
+func authenticate(authenticators []Authenticator, r *http.Request) (string, error) {
+	for _, authenticator := range authenticators {
+		identity, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return identity, nil
+	}
+
+	return "", ErrNoCredentials
+}
+
+func containsIdentity(identities []string, identity string) bool {
+	for _, allowed := range identities {
+		if allowed == identity {
+			return true
+		}
+	}
+
+	return false
+}
+
+// APIKeyAuthenticator authenticates requests
+// with an API key provided in a header.
+type APIKeyAuthenticator struct {
+	header string
+	keys   map[string]string
+}
+
+// NewAPIKeyAuthenticator returns an *APIKeyAuthenticator for
+// keys (a map of API key to identity). If header is empty,
+// DefaultAPIKeyHeader is used.
+func NewAPIKeyAuthenticator(header string, keys map[string]string) *APIKeyAuthenticator {
+	if len(header) == 0 {
+		header = DefaultAPIKeyHeader
+	}
+
+	return &APIKeyAuthenticator{
+		header: header,
+		keys:   keys,
+	}
+}
+
+// Authenticate returns the identity of the API key in r.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (string, error) {
+	key := r.Header.Get(a.header)
+	if len(key) == 0 {
+		return "", ErrNoCredentials
+	}
+
+	// All keys are compared (in constant time) so that
+	// the response time does not reveal which keys exist.
+	identity := ""
+	found := false
+	for candidate, candidateIdentity := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			identity = candidateIdentity
+			found = true
+		}
+	}
+
+	if !found {
+		return "", fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+	}
+
+	return identity, nil
+}
+
+// ClientCertAuthenticator authenticates requests with the
+// verified TLS client certificate of the connection (mTLS).
+// The identity of the caller is the common name of the
+// certificate. The http.Server must be configured to verify
+// client certificates (ex: with tls.RequireAndVerifyClientCert).
+type ClientCertAuthenticator struct{}
+
+// NewClientCertAuthenticator returns a
+// new *ClientCertAuthenticator.
+func NewClientCertAuthenticator() *ClientCertAuthenticator {
+	return &ClientCertAuthenticator{}
+}
+
+// Authenticate returns the common name of the
+// verified client certificate of r.
+func (a *ClientCertAuthenticator) Authenticate(r *http.Request) (string, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 ||
+		len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", ErrNoCredentials
+	}
+
+	identity := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if len(identity) == 0 {
+		return "", fmt.Errorf("%w: client certificate has no common name", ErrInvalidCredentials)
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,443 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func identityHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ := Identity(r.Context())
+		EncodeJSONResponse(map[string]string{"identity": identity}, http.StatusOK, w)
+	})
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) +
+		"." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signed := encodeSegment(t, map[string]string{"alg": "ES256", "kid": kid}) +
+		"." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		EncodeJSONResponse(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "rsa",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+					"e": base64.RawURLEncoding.EncodeToString(
+						big.NewInt(int64(rsaKey.E)).Bytes(),
+					),
+				},
+				{
+					"kty": "EC",
+					"kid": "ec",
+					"crv": "P-256",
+					"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+					"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+				},
+			},
+		}, http.StatusOK, w)
+	}))
+	defer jwks.Close()
+
+	handler := Chain(identityHandler(), AuthMiddleware(&AuthConfig{
+		Authenticators: []Authenticator{
+			NewAPIKeyAuthenticator("", map[string]string{
+				"key1": "alice",
+				"key2": "bob",
+			}),
+			NewJWTAuthenticator(&JWTConfig{
+				JWKSURL:  jwks.URL,
+				Issuer:   "issuer",
+				Audience: "rosetta",
+			}),
+			NewClientCertAuthenticator(),
+		},
+		Rules: append(
+			PublicRules(DataAPIPathPrefixes...),
+			&AuthRule{
+				PathPrefix: "/construction/submit",
+				Identities: []string{"alice", "service"},
+			},
+		),
+		Errors: &ErrorConfig{Status: ErrorCodeStatus},
+	}))
+
+	now := time.Now().Unix()
+	validClaims := map[string]interface{}{
+		"sub": "service",
+		"iss": "issuer",
+		"aud": []string{"other", "rosetta"},
+		"exp": now + 60,
+	}
+
+	var tests = map[string]struct {
+		path    string
+		headers map[string]string
+		tls     *tls.ConnectionState
+
+		status   int
+		identity string
+		err      *types.Error
+	}{
+		"public route": {
+			path:   "/network/list",
+			status: http.StatusOK,
+		},
+		"missing credentials": {
+			path:   "/construction/payloads",
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+		"api key": {
+			path:     "/construction/payloads",
+			headers:  map[string]string{DefaultAPIKeyHeader: "key2"},
+			status:   http.StatusOK,
+			identity: "bob",
+		},
+		"unknown api key": {
+			path:    "/construction/payloads",
+			headers: map[string]string{DefaultAPIKeyHeader: "key3"},
+			status:  http.StatusUnauthorized,
+			err:     ErrUnauthorized,
+		},
+		"forbidden identity": {
+			path:    "/construction/submit",
+			headers: map[string]string{DefaultAPIKeyHeader: "key2"},
+			status:  http.StatusForbidden,
+			err:     ErrForbidden,
+		},
+		"allowed identity": {
+			path:     "/construction/submit",
+			headers:  map[string]string{DefaultAPIKeyHeader: "key1"},
+			status:   http.StatusOK,
+			identity: "alice",
+		},
+		"rs256 token": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signRS256(t, rsaKey, "rsa", validClaims),
+			},
+			status:   http.StatusOK,
+			identity: "service",
+		},
+		"es256 token": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signES256(t, ecKey, "ec", validClaims),
+			},
+			status:   http.StatusOK,
+			identity: "service",
+		},
+		"expired token": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signRS256(t, rsaKey, "rsa", map[string]interface{}{
+					"sub": "service",
+					"iss": "issuer",
+					"aud": "rosetta",
+					"exp": now - 60,
+				}),
+			},
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+		"missing expiry": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signRS256(t, rsaKey, "rsa", map[string]interface{}{
+					"sub": "service",
+					"iss": "issuer",
+					"aud": "rosetta",
+				}),
+			},
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+		"wrong audience": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signRS256(t, rsaKey, "rsa", map[string]interface{}{
+					"sub": "service",
+					"iss": "issuer",
+					"aud": "other",
+					"exp": now + 60,
+				}),
+			},
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+		"unsupported algorithm": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + encodeSegment(t, map[string]string{"alg": "none", "kid": "rsa"}) +
+					"." + encodeSegment(t, validClaims) + ".",
+			},
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+		"wrong key": {
+			path: "/construction/submit",
+			headers: map[string]string{
+				"Authorization": "Bearer " + signRS256(t, rsaKey, "ec", validClaims),
+			},
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+		"client certificate": {
+			path: "/construction/payloads",
+			tls: &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{
+					{{Subject: pkix.Name{CommonName: "client"}}},
+				},
+			},
+			status:   http.StatusOK,
+			identity: "client",
+		},
+		"unverified client certificate": {
+			path: "/construction/payloads",
+			tls: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{
+					{Subject: pkix.Name{CommonName: "client"}},
+				},
+			},
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthorized,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.path, nil)
+			for header, value := range test.headers {
+				req.Header.Set(header, value)
+			}
+			req.TLS = test.tls
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, test.status, w.Code)
+
+			if test.err != nil {
+				var rosettaErr types.Error
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+				assert.Equal(t, test.err.Code, rosettaErr.Code)
+				assert.Equal(t, test.err.Message, rosettaErr.Message)
+				assert.NotNil(t, rosettaErr.Description)
+				return
+			}
+
+			var response map[string]string
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, test.identity, response["identity"])
+		})
+	}
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	var fetches int32
+	release := make(chan struct{})
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		EncodeJSONResponse(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "rsa",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e": base64.RawURLEncoding.EncodeToString(
+						big.NewInt(int64(key.E)).Bytes(),
+					),
+				},
+				{
+					// Unsupported keys are skipped.
+					"kty": "OKP",
+					"kid": "unsupported",
+				},
+			},
+		}, http.StatusOK, w)
+	}))
+	defer jwks.Close()
+
+	authenticator := NewJWTAuthenticator(&JWTConfig{
+		JWKSURL:            jwks.URL,
+		AllowMissingExpiry: true,
+	})
+	token := signRS256(t, key, "rsa", map[string]interface{}{"sub": "service"})
+	newRequest := func(ctx context.Context) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/construction/submit", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req.WithContext(ctx)
+	}
+
+	// A request that is cancelled while the JWKS is
+	// fetched does not cancel the fetch.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = authenticator.Authenticate(newRequest(ctx))
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+
+	// Concurrent requests share a single fetch.
+	var wg sync.WaitGroup
+	identities := make([]string, 5)
+	for i := range identities {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			identity, err := authenticator.Authenticate(newRequest(context.Background()))
+			assert.NoError(t, err)
+			identities[i] = identity
+		}(i)
+	}
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"service", "service", "service", "service", "service"}, identities)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// Unknown keys do not trigger a fetch until
+	// minJWKSRefreshInterval has elapsed.
+	_, err = authenticator.Authenticate(newRequest(context.Background()))
+	assert.NoError(t, err)
+	unknown := httptest.NewRequest(http.MethodPost, "/construction/submit", nil)
+	unknown.Header.Set(
+		"Authorization",
+		"Bearer "+signRS256(t, key, "unsupported", map[string]interface{}{"sub": "service"}),
+	)
+	_, err = authenticator.Authenticate(unknown)
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestAuthMiddlewareErrors(t *testing.T) {
+	forbidden := &types.Error{Code: 12, Message: "not allowed"}
+	rules := []*AuthRule{
+		{
+			PathPrefix: "/construction/submit",
+			Identities: []string{"alice"},
+		},
+	}
+	authenticators := []Authenticator{
+		NewAPIKeyAuthenticator("", map[string]string{"key1": "alice", "key2": "bob"}),
+	}
+
+	var tests = map[string]struct {
+		errors  *ErrorConfig
+		headers map[string]string
+
+		status int
+		err    *types.Error
+	}{
+		"default status": {
+			status: http.StatusInternalServerError,
+			err:    ErrUnauthorized,
+		},
+		"replaced error": {
+			errors: &ErrorConfig{
+				Replacements: map[*types.Error]*types.Error{ErrForbidden: forbidden},
+				Status:       ErrorCodeStatus,
+			},
+			headers: map[string]string{DefaultAPIKeyHeader: "key2"},
+			status:  http.StatusForbidden,
+			err:     forbidden,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := Chain(identityHandler(), AuthMiddleware(&AuthConfig{
+				Authenticators: authenticators,
+				Rules:          rules,
+				Errors:         test.errors,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/construction/submit", nil)
+			for header, value := range test.headers {
+				req.Header.Set(header, value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, test.status, w.Code)
+
+			var rosettaErr types.Error
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+			assert.Equal(t, test.err.Code, rosettaErr.Code)
+			assert.Equal(t, test.err.Message, rosettaErr.Message)
+		})
+	}
+}
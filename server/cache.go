@@ -0,0 +1,307 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// DefaultCacheMaxBytes is the default maximum
+	// size of all cached responses.
+	DefaultCacheMaxBytes = 64 * 1024 * 1024
+
+	// DefaultCacheTTL is the default maximum
+	// time a response is cached.
+	DefaultCacheTTL = 10 * time.Minute
+
+	// DefaultCacheMaxRequestBytes is the default maximum
+	// size of the body of a cacheable request.
+	DefaultCacheMaxRequestBytes = 16 * 1024
+
+	// CacheHeader is set to CacheHit or CacheMiss
+	// on responses to cacheable requests.
+	CacheHeader = "X-Cache"
+
+	// CacheHit is the value of CacheHeader when
+	// a response is served from the cache.
+	CacheHit = "HIT"
+
+	// CacheMiss is the value of CacheHeader when
+	// a response is not in the cache.
+	CacheMiss = "MISS"
+)
+
+// DefaultCachePaths are the routes that are
+// cached by default (see ImmutableRequest).
+var DefaultCachePaths = []string{
+	"/block",
+	"/block/transaction",
+}
+
+// CacheableFunc returns true if the response to a request to
+// path with body is immutable (and can be cached).
+type CacheableFunc func(path string, body []byte) bool
+
+// ImmutableRequest is the default CacheableFunc. Only
+// requests that identify a block by hash are cached:
+// /block requests with a hash and all /block/transaction
+// requests. Requests for a block by index (or for the
+// current block) are tip-relative, so they are not cached.
+func ImmutableRequest(path string, body []byte) bool {
+	switch path {
+	case "/block":
+		var request types.BlockRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return false
+		}
+
+		return request.BlockIdentifier != nil && request.BlockIdentifier.Hash != nil
+	case "/block/transaction":
+		var request types.BlockTransactionRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return false
+		}
+
+		return request.BlockIdentifier != nil && len(request.BlockIdentifier.Hash) > 0
+	default:
+		return false
+	}
+}
+
+// CacheConfig configures CacheMiddleware.
+type CacheConfig struct {
+	// MaxBytes is the maximum size of all cached responses.
+	// When exceeded, the least recently used responses are
+	// evicted. If 0, DefaultCacheMaxBytes is used.
+	MaxBytes int64
+
+	// TTL is the maximum time a response is cached.
+	// If 0, DefaultCacheTTL is used.
+	TTL time.Duration
+
+	// Paths are the routes that can be cached. The bodies of
+	// requests to other routes are not read (or passed to
+	// Cacheable). If nil, DefaultCachePaths is used.
+	Paths []string
+
+	// MaxRequestBytes is the maximum size of the body of a
+	// cacheable request. At most MaxRequestBytes+1 bytes of a
+	// body are buffered and larger requests bypass the cache.
+	// If 0, DefaultCacheMaxRequestBytes is used.
+	MaxRequestBytes int64
+
+	// Cacheable determines which requests to Paths
+	// are cached. If nil, ImmutableRequest is used.
+	Cacheable CacheableFunc
+
+	// Errors configures ErrInvalidRequest, which is returned
+	// when the body of a request cannot be read (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// cacheEntry is a cached response.
+type cacheEntry struct {
+	key     [sha256.Size]byte
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func (e *cacheEntry) size() int64 {
+	return int64(len(e.body))
+}
+
+// responseCache is an LRU cache of responses
+// with a size limit.
+type responseCache struct {
+	maxBytes int64
+	ttl      time.Duration
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	bytes   int64
+	lru     *list.List
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+func newResponseCache(config *CacheConfig, now func() time.Time) *responseCache {
+	cache := &responseCache{
+		maxBytes: config.MaxBytes,
+		ttl:      config.TTL,
+		now:      now,
+		lru:      list.New(),
+		entries:  map[[sha256.Size]byte]*list.Element{},
+	}
+
+	if cache.maxBytes <= 0 {
+		cache.maxBytes = DefaultCacheMaxBytes
+	}
+
+	if cache.ttl <= 0 {
+		cache.ttl = DefaultCacheTTL
+	}
+
+	return cache
+}
+
+// get returns the unexpired entry for key (if any).
+func (c *responseCache) get(key [sha256.Size]byte) (*cacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return entry, true
+}
+
+// put adds an entry for key (evicting the least
+// recently used entries if the cache is full).
+// Responses larger than the cache are skipped.
+func (c *responseCache) put(key [sha256.Size]byte, header http.Header, body []byte) {
+	entry := &cacheEntry{
+		key:     key,
+		header:  header,
+		body:    body,
+		expires: c.now().Add(c.ttl),
+	}
+	if entry.size() > c.maxBytes {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	c.bytes += entry.size()
+	for c.bytes > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *responseCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size()
+}
+
+// CacheMiddleware caches successful responses to requests that
+// are immutable (as determined by config.Cacheable) keyed by the
+// hash of the path and body of the request. Responses to
+// cacheable requests have CacheHeader set to CacheHit or
+// CacheMiss.
+//
+// The caller is not part of the key, so a cached response is
+// served to any caller that reaches CacheMiddleware. When using
+// AuthMiddleware, CacheMiddleware must run after it (ex:
+// Chain(router, AuthMiddleware(...), CacheMiddleware(...))).
+func CacheMiddleware(config *CacheConfig) Middleware {
+	cache := newResponseCache(config, time.Now)
+	cacheable := config.Cacheable
+	if cacheable == nil {
+		cacheable = ImmutableRequest
+	}
+
+	paths := map[string]struct{}{}
+	cachePaths := config.Paths
+	if cachePaths == nil {
+		cachePaths = DefaultCachePaths
+	}
+	for _, path := range cachePaths {
+		paths[path] = struct{}{}
+	}
+
+	maxRequestBytes := config.MaxRequestBytes
+	if maxRequestBytes <= 0 {
+		maxRequestBytes = DefaultCacheMaxRequestBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := paths[r.URL.Path]; !ok || r.ContentLength > maxRequestBytes {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The Content-Length may be unknown (or
+			// incorrect), so at most maxRequestBytes+1
+			// bytes are read to determine the size.
+			body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBytes+1))
+			if err != nil {
+				config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+				return
+			}
+
+			if int64(len(body)) > maxRequestBytes {
+				r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+				next.ServeHTTP(w, r)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			if !cacheable(r.URL.Path, body) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := sha256.Sum256(append([]byte(r.URL.Path+"\x00"), body...))
+			if entry, ok := cache.get(key); ok {
+				for header, values := range entry.header {
+					w.Header()[header] = values
+				}
+				w.Header().Set(CacheHeader, CacheHit)
+				w.WriteHeader(http.StatusOK)
+				w.Write(entry.body) // nolint:errcheck
+				return
+			}
+
+			recorder := newResponseRecorder()
+			next.ServeHTTP(recorder, r)
+
+			// Errors (ex: a block that is not yet
+			// available) are not cached.
+			if recorder.status == http.StatusOK {
+				cache.put(key, recorder.header.Clone(), recorder.body.Bytes())
+			}
+
+			recorder.header.Set(CacheHeader, CacheMiss)
+			recorder.flush(w)
+		})
+	}
+}
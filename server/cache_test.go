@@ -0,0 +1,252 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// countingHandler counts the requests it
+// serves and responds with status.
+type countingHandler struct {
+	status int
+	count  int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.count++
+	EncodeJSONResponse(map[string]int{"count": h.count}, h.status, w)
+}
+
+func TestImmutableRequest(t *testing.T) {
+	var tests = map[string]struct {
+		path    string
+		request interface{}
+
+		cacheable bool
+	}{
+		"block by hash": {
+			path: "/block",
+			request: &types.BlockRequest{
+				NetworkIdentifier: validationNetwork,
+				BlockIdentifier:   &types.PartialBlockIdentifier{Hash: types.String("block 1")},
+			},
+			cacheable: true,
+		},
+		"block by index": {
+			path: "/block",
+			request: &types.BlockRequest{
+				NetworkIdentifier: validationNetwork,
+				BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(1)},
+			},
+		},
+		"current block": {
+			path: "/block",
+			request: &types.BlockRequest{
+				NetworkIdentifier: validationNetwork,
+				BlockIdentifier:   &types.PartialBlockIdentifier{},
+			},
+		},
+		"block transaction": {
+			path: "/block/transaction",
+			request: &types.BlockTransactionRequest{
+				NetworkIdentifier:     validationNetwork,
+				BlockIdentifier:       &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx"},
+			},
+			cacheable: true,
+		},
+		"network status": {
+			path:    "/network/status",
+			request: &types.NetworkRequest{NetworkIdentifier: validationNetwork},
+		},
+		"malformed": {
+			path:    "/block",
+			request: "{",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(
+				t,
+				test.cacheable,
+				ImmutableRequest(test.path, []byte(types.PrintStruct(test.request))),
+			)
+		})
+	}
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	next := &countingHandler{status: http.StatusOK}
+	handler := Chain(next, CacheMiddleware(&CacheConfig{}))
+
+	byHash := types.PrintStruct(&types.BlockRequest{
+		NetworkIdentifier: validationNetwork,
+		BlockIdentifier:   &types.PartialBlockIdentifier{Hash: types.String("block 1")},
+	})
+	byIndex := types.PrintStruct(&types.BlockRequest{
+		NetworkIdentifier: validationNetwork,
+		BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(1)},
+	})
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/block", bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := serve(byHash)
+	assert.Equal(t, CacheMiss, w.Header().Get(CacheHeader))
+	assert.JSONEq(t, `{"count": 1}`, w.Body.String())
+
+	w = serve(byHash)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, CacheHit, w.Header().Get(CacheHeader))
+	assert.Equal(t, "application/json; charset=UTF-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"count": 1}`, w.Body.String())
+
+	// Tip-relative requests bypass the cache.
+	w = serve(byIndex)
+	assert.Empty(t, w.Header().Get(CacheHeader))
+	assert.JSONEq(t, `{"count": 2}`, w.Body.String())
+	w = serve(byIndex)
+	assert.JSONEq(t, `{"count": 3}`, w.Body.String())
+
+	// Errors are not cached.
+	next.status = http.StatusInternalServerError
+	errorRequest := types.PrintStruct(&types.BlockRequest{
+		NetworkIdentifier: validationNetwork,
+		BlockIdentifier:   &types.PartialBlockIdentifier{Hash: types.String("block 2")},
+	})
+	w = serve(errorRequest)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, CacheMiss, w.Header().Get(CacheHeader))
+	w = serve(errorRequest)
+	assert.Equal(t, CacheMiss, w.Header().Get(CacheHeader))
+	assert.Equal(t, 5, next.count)
+}
+
+func TestCacheMiddlewareBuffering(t *testing.T) {
+	next := &fixedHandler{response: map[string]string{}, status: http.StatusOK}
+	calls := 0
+	handler := Chain(next, CacheMiddleware(&CacheConfig{
+		MaxRequestBytes: 8,
+		Cacheable: func(path string, body []byte) bool {
+			calls++
+			return true
+		},
+	}))
+
+	var tests = map[string]struct {
+		path          string
+		body          string
+		unknownLength bool
+
+		cacheable bool
+	}{
+		"cacheable": {
+			path:      "/block",
+			body:      "12345678",
+			cacheable: true,
+		},
+		"other route": {
+			path: "/network/status",
+			body: "12345678",
+		},
+		"too large": {
+			path: "/block",
+			body: "123456789",
+		},
+		"too large with unknown length": {
+			path:          "/block/transaction",
+			body:          strings.Repeat("1", 100),
+			unknownLength: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls = 0
+			req := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.body))
+			if test.unknownLength {
+				req.ContentLength = -1
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, test.body, string(next.body))
+
+			if test.cacheable {
+				assert.Equal(t, 1, calls)
+				assert.Equal(t, CacheMiss, w.Header().Get(CacheHeader))
+				return
+			}
+
+			assert.Equal(t, 0, calls)
+			assert.Empty(t, w.Header().Get(CacheHeader))
+		})
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newResponseCache(&CacheConfig{
+		MaxBytes: 10,
+		TTL:      time.Minute,
+	}, func() time.Time { return now })
+
+	key := func(s string) [sha256.Size]byte {
+		return sha256.Sum256([]byte(s))
+	}
+
+	cache.put(key("a"), http.Header{}, []byte("aaaa"))
+	cache.put(key("b"), http.Header{}, []byte("bbbb"))
+
+	// Touch a so that b is
+	// least recently used.
+	_, ok := cache.get(key("a"))
+	assert.True(t, ok)
+
+	cache.put(key("c"), http.Header{}, []byte("cccc"))
+	_, ok = cache.get(key("b"))
+	assert.False(t, ok)
+	_, ok = cache.get(key("a"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), cache.bytes)
+
+	// Responses larger than the
+	// cache are skipped.
+	cache.put(key("d"), http.Header{}, []byte("ddddddddddd"))
+	_, ok = cache.get(key("d"))
+	assert.False(t, ok)
+
+	// Entries expire after the TTL.
+	now = now.Add(time.Minute)
+	_, ok = cache.get(key("a"))
+	assert.False(t, ok)
+	assert.Equal(t, int64(4), cache.bytes)
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// ErrTrailingData is returned by controllers with strict
+// decoding when a request body has data after the request.
+var ErrTrailingData = errors.New("request has trailing data")
+
+// ErrorStatusFunc returns the HTTP status of the
+// response to a request that failed with err.
+type ErrorStatusFunc func(err *types.Error) int
+
+// DefaultErrorStatus responds to all errors with a 500
+// (as required by the Rosetta specification).
+func DefaultErrorStatus(err *types.Error) int {
+	return http.StatusInternalServerError
+}
+
+// ErrorCodeStatus responds to errors with their Code when it
+// is a 4xx or 5xx status (like the errors of this package,
+// ex: ErrInvalidRequest) and with a 500 otherwise.
+func ErrorCodeStatus(err *types.Error) int {
+	if err.Code >= http.StatusBadRequest && err.Code < 600 { // nolint:gomnd
+		return int(err.Code)
+	}
+
+	return http.StatusInternalServerError
+}
+
+// controllerConfig configures how
+// a controller handles requests.
+type controllerConfig struct {
+	strict      bool
+	errorStatus ErrorStatusFunc
+}
+
+// ControllerOption is used to overwrite default values
+// in a controller. Any ControllerOption not provided
+// falls back to the default value.
+type ControllerOption func(c *controllerConfig)
+
+// WithStrictDecoding rejects requests with unknown fields
+// or trailing data (instead of ignoring them). Invalid
+// requests are answered with ErrInvalidRequest (with the
+// reason in its description).
+func WithStrictDecoding() ControllerOption {
+	return func(c *controllerConfig) {
+		c.strict = true
+	}
+}
+
+// WithErrorStatus overrides the default status
+// (DefaultErrorStatus) of error responses.
+func WithErrorStatus(errorStatus ErrorStatusFunc) ControllerOption {
+	return func(c *controllerConfig) {
+		c.errorStatus = errorStatus
+	}
+}
+
+func newControllerConfig(options []ControllerOption) *controllerConfig {
+	config := &controllerConfig{
+		errorStatus: DefaultErrorStatus,
+	}
+
+	for _, opt := range options {
+		opt(config)
+	}
+
+	return config
+}
+
+// decode decodes the body of r into request.
+func (c *controllerConfig) decode(r *http.Request, request interface{}) error {
+	if !c.strict {
+		return json.NewDecoder(r.Body).Decode(request)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingData
+	}
+
+	return types.CheckUnknownFields(body, request)
+}
+
+// encodeRequestError writes the error of
+// an invalid request to w.
+func (c *controllerConfig) encodeRequestError(err error, w http.ResponseWriter) {
+	if c.strict {
+		EncodeError(ErrInvalidRequest, err.Error(), c.errorStatus(ErrInvalidRequest), w)
+		return
+	}
+
+	rosettaErr := &types.Error{
+		Message: err.Error(),
+	}
+	EncodeJSONResponse(rosettaErr, c.errorStatus(rosettaErr), w)
+}
+
+// encodeServiceError writes an error
+// returned by a Servicer to w.
+func (c *controllerConfig) encodeServiceError(err *types.Error, w http.ResponseWriter) {
+	EncodeJSONResponse(err, c.errorStatus(err), w)
+}
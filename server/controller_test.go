@@ -0,0 +1,149 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var errCallFailed = &types.Error{
+	Code:    http.StatusBadGateway,
+	Message: "node unavailable",
+}
+
+// callServicer echoes the parameters of a call (or
+// fails if the method is "fail").
+type callServicer struct{}
+
+func (s *callServicer) Call(
+	ctx context.Context,
+	request *types.CallRequest,
+) (*types.CallResponse, *types.Error) {
+	if request.Method == "fail" {
+		return nil, errCallFailed
+	}
+
+	return &types.CallResponse{Result: request.Parameters, Idempotent: true}, nil
+}
+
+func TestControllerOptions(t *testing.T) {
+	a, err := asserter.NewServer(
+		[]string{"TRANSFER"},
+		false,
+		[]*types.NetworkIdentifier{validationNetwork},
+		[]string{"echo", "fail"},
+		false,
+		"",
+	)
+	assert.NoError(t, err)
+
+	network := `"network_identifier":{"blockchain":"Bitcoin","network":"Mainnet"}`
+	var tests = map[string]struct {
+		options []ControllerOption
+		body    string
+
+		status int
+		err    *types.Error
+	}{
+		"valid": {
+			body:   `{` + network + `,"method":"echo","parameters":{"a":1}}`,
+			status: http.StatusOK,
+		},
+		"unknown field": {
+			body:   `{` + network + `,"method":"echo","parameters":{},"extra":1}`,
+			status: http.StatusOK,
+		},
+		"unknown field (strict)": {
+			options: []ControllerOption{WithStrictDecoding()},
+			body:    `{` + network + `,"method":"echo","parameters":{},"extra":1}`,
+			status:  http.StatusInternalServerError,
+			err: &types.Error{
+				Code:        ErrInvalidRequest.Code,
+				Message:     ErrInvalidRequest.Message,
+				Description: types.String(`json: unknown field "extra"`),
+			},
+		},
+		"trailing data (strict)": {
+			options: []ControllerOption{WithStrictDecoding()},
+			body:    `{` + network + `,"method":"echo","parameters":{}}{}`,
+			status:  http.StatusInternalServerError,
+			err: &types.Error{
+				Code:        ErrInvalidRequest.Code,
+				Message:     ErrInvalidRequest.Message,
+				Description: types.String(ErrTrailingData.Error()),
+			},
+		},
+		"invalid request": {
+			body:   `{"method":"echo","parameters":{}}`,
+			status: http.StatusInternalServerError,
+			err:    &types.Error{Message: asserter.ErrNetworkIdentifierIsNil.Error()},
+		},
+		"invalid request (strict, error code status)": {
+			options: []ControllerOption{WithStrictDecoding(), WithErrorStatus(ErrorCodeStatus)},
+			body:    `{"method":"echo","parameters":{}}`,
+			status:  http.StatusBadRequest,
+			err: &types.Error{
+				Code:        ErrInvalidRequest.Code,
+				Message:     ErrInvalidRequest.Message,
+				Description: types.String(asserter.ErrNetworkIdentifierIsNil.Error()),
+			},
+		},
+		"service error": {
+			body:   `{` + network + `,"method":"fail","parameters":{}}`,
+			status: http.StatusInternalServerError,
+			err:    errCallFailed,
+		},
+		"service error (error code status)": {
+			options: []ControllerOption{WithErrorStatus(ErrorCodeStatus)},
+			body:    `{` + network + `,"method":"fail","parameters":{}}`,
+			status:  http.StatusBadGateway,
+			err:     errCallFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := NewRouter(NewCallAPIController(&callServicer{}, a, test.options...))
+			req := httptest.NewRequest(http.MethodPost, "/call", strings.NewReader(test.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.status, w.Code)
+			if test.err == nil {
+				return
+			}
+
+			rosettaErr := &types.Error{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rosettaErr))
+			assert.Equal(t, test.err, rosettaErr)
+		})
+	}
+}
+
+func TestErrorCodeStatus(t *testing.T) {
+	assert.Equal(t, http.StatusTooManyRequests, ErrorCodeStatus(ErrRateLimited))
+	assert.Equal(t, http.StatusInternalServerError, ErrorCodeStatus(&types.Error{Code: 12}))
+	assert.Equal(t, http.StatusInternalServerError, ErrorCodeStatus(&types.Error{Code: 1000}))
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultCorsMethods are the methods allowed
+	// by default (the Rosetta API only uses POST).
+	DefaultCorsMethods = []string{http.MethodGet, http.MethodPost, http.MethodOptions}
+
+	// DefaultCorsHeaders are the request headers allowed
+	// by default (including the headers read by the
+	// authenticators of AuthMiddleware).
+	DefaultCorsHeaders = []string{
+		"Origin",
+		"X-Requested-With",
+		"Content-Type",
+		"Accept",
+		"Authorization",
+		DefaultAPIKeyHeader,
+	}
+
+	// ErrCorsCredentialsAnyOrigin is returned by NewCorsMiddleware
+	// when credentials are allowed from any origin (which would
+	// let any website make authenticated requests on behalf of
+	// a user).
+	ErrCorsCredentialsAnyOrigin = errors.New(
+		"credentials cannot be allowed from any origin",
+	)
+)
+
+// CorsConfig configures NewCorsMiddleware. Unlike
+// CorsMiddleware (which allows all origins), the
+// allowed origins, methods, and headers can be
+// restricted.
+type CorsConfig struct {
+	// AllowedOrigins are the origins allowed to make requests.
+	// Origins can be exact (ex: "https://explorer.com"), a
+	// wildcard subdomain (ex: "https://*.explorer.com"), or
+	// "*" (any origin). If empty, any origin is allowed.
+	AllowedOrigins []string
+
+	// AllowedMethods are the methods allowed in requests.
+	// If empty, DefaultCorsMethods are allowed.
+	AllowedMethods []string
+
+	// AllowedHeaders are the headers allowed in requests.
+	// If empty, DefaultCorsHeaders are allowed.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers
+	// browsers expose to callers.
+	ExposedHeaders []string
+
+	// AllowCredentials allows requests to include cookies
+	// or client certificates. AllowedOrigins must be
+	// restricted (not empty or "*") to allow credentials.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers can cache the result
+	// of a preflight request. If 0, the browser default
+	// is used.
+	MaxAge time.Duration
+}
+
+// allowedOrigin returns true if
+// origin matches pattern.
+func allowedOrigin(pattern string, origin string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+
+	wildcard := strings.Index(pattern, "*")
+	if wildcard < 0 {
+		return false
+	}
+
+	prefix, suffix := pattern[:wildcard], pattern[wildcard+1:]
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
+
+// NewCorsMiddleware returns a Middleware that handles
+// CORS (including OPTIONS preflight requests) according
+// to config. Requests from origins that are not allowed
+// are served without CORS headers (so browsers reject
+// their responses).
+func NewCorsMiddleware(config *CorsConfig) (Middleware, error) {
+	origins := config.AllowedOrigins
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+
+	anyOrigin := false
+	for _, origin := range origins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+	}
+
+	if anyOrigin && config.AllowCredentials {
+		return nil, ErrCorsCredentialsAnyOrigin
+	}
+
+	methods := config.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCorsMethods
+	}
+
+	headers := config.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultCorsHeaders
+	}
+
+	allowedMethods := strings.Join(methods, ", ")
+	allowedHeaders := strings.Join(headers, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			preflight := r.Method == http.MethodOptions &&
+				len(r.Header.Get("Access-Control-Request-Method")) > 0
+
+			origin := r.Header.Get("Origin")
+			allowed := false
+			for _, pattern := range origins {
+				if len(origin) > 0 && allowedOrigin(pattern, origin) {
+					allowed = true
+					break
+				}
+			}
+
+			if allowed {
+				// The origin must be echoed when
+				// only some origins are allowed.
+				if anyOrigin {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Add("Vary", "Origin")
+				}
+
+				if config.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+
+				if len(exposedHeaders) > 0 && !preflight {
+					w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+				}
+			}
+
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				if config.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}, nil
+}
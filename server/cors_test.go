@@ -0,0 +1,163 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedOrigin(t *testing.T) {
+	assert.True(t, allowedOrigin("*", "https://a.com"))
+	assert.True(t, allowedOrigin("https://a.com", "https://a.com"))
+	assert.False(t, allowedOrigin("https://a.com", "https://b.com"))
+	assert.True(t, allowedOrigin("https://*.a.com", "https://www.a.com"))
+	assert.False(t, allowedOrigin("https://*.a.com", "https://.a.com"))
+	assert.False(t, allowedOrigin("https://*.a.com", "https://a.com"))
+	assert.False(t, allowedOrigin("https://*.a.com", "http://www.a.com"))
+}
+
+func TestNewCorsMiddleware(t *testing.T) {
+	var tests = map[string]struct {
+		config  *CorsConfig
+		method  string
+		headers map[string]string
+
+		status          int
+		expectedHeaders map[string]string
+	}{
+		"no origin": {
+			config:          &CorsConfig{},
+			method:          http.MethodPost,
+			status:          http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		"any origin": {
+			config:  &CorsConfig{ExposedHeaders: []string{"X-Cache"}},
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "https://a.com"},
+			status:  http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "*",
+				"Access-Control-Expose-Headers": "X-Cache",
+				"Vary":                          "",
+			},
+		},
+		"allowed origin": {
+			config: &CorsConfig{
+				AllowedOrigins:   []string{"https://*.a.com"},
+				AllowCredentials: true,
+			},
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "https://www.a.com"},
+			status:  http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://www.a.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Vary":                             "Origin",
+			},
+		},
+		"disallowed origin": {
+			config:  &CorsConfig{AllowedOrigins: []string{"https://a.com"}},
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "https://b.com"},
+			status:  http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		"default headers": {
+			config: &CorsConfig{AllowedOrigins: []string{"https://a.com"}},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://a.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			status: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "https://a.com",
+				"Access-Control-Allow-Headers": "Origin, X-Requested-With, Content-Type, " +
+					"Accept, Authorization, X-API-Key",
+			},
+		},
+		"preflight": {
+			config: &CorsConfig{
+				AllowedOrigins: []string{"https://a.com"},
+				AllowedHeaders: []string{"Content-Type", "X-API-Key"},
+				MaxAge:         10 * time.Minute,
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://a.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			status: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://a.com",
+				"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+				"Access-Control-Allow-Headers": "Content-Type, X-API-Key",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		"disallowed preflight": {
+			config: &CorsConfig{AllowedOrigins: []string{"https://a.com"}},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://b.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			status: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			middleware, err := NewCorsMiddleware(test.config)
+			assert.NoError(t, err)
+
+			handler := Chain(identityHandler(), middleware)
+			req := httptest.NewRequest(test.method, "/network/list", nil)
+			for header, value := range test.headers {
+				req.Header.Set(header, value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, test.status, w.Code)
+			for header, value := range test.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(header), header)
+			}
+		})
+	}
+}
+
+func TestNewCorsMiddlewareCredentials(t *testing.T) {
+	_, err := NewCorsMiddleware(&CorsConfig{AllowCredentials: true})
+	assert.Equal(t, ErrCorsCredentialsAnyOrigin, err)
+
+	_, err = NewCorsMiddleware(&CorsConfig{
+		AllowedOrigins:   []string{"https://a.com", "*"},
+		AllowCredentials: true,
+	})
+	assert.Equal(t, ErrCorsCredentialsAnyOrigin, err)
+}
@@ -0,0 +1,440 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// EventsStreamPath is the path of the
+	// events stream extension endpoint.
+	EventsStreamPath = "/events/stream"
+
+	// DefaultEventsPollInterval is how often the
+	// EventsAPIServicer is polled for new events
+	// when a stream is caught up.
+	DefaultEventsPollInterval = 1 * time.Second
+
+	// DefaultEventsStreamLimit is the maximum number
+	// of events fetched from the EventsAPIServicer
+	// at once.
+	DefaultEventsStreamLimit = 100
+
+	// DefaultEventsHeartbeatInterval is how often a
+	// heartbeat is sent on an idle stream (so that
+	// proxies do not close it).
+	DefaultEventsHeartbeatInterval = 30 * time.Second
+
+	// eventsWriteTimeout is the maximum time
+	// to write a message to a WebSocket.
+	eventsWriteTimeout = 10 * time.Second
+
+	sseContentType = "text/event-stream"
+)
+
+// ErrStreamEncoding is sent on an events stream (which is then
+// closed) when an event or error returned by the EventsAPIServicer
+// cannot be encoded (ex: it has metadata that is not JSON).
+var ErrStreamEncoding = &types.Error{
+	Code:    http.StatusInternalServerError,
+	Message: "unable to encode stream message",
+}
+
+// errStreamUnsupported is returned when a request is
+// neither a WebSocket upgrade nor an SSE request.
+var errStreamUnsupported = errors.New(
+	"events stream requires a WebSocket upgrade or Accept: " + sseContentType,
+)
+
+// EventsStreamConfig configures the events stream
+// extension endpoint. Zero values use the defaults.
+type EventsStreamConfig struct {
+	PollInterval      time.Duration
+	Limit             int64
+	HeartbeatInterval time.Duration
+
+	// CheckOrigin determines if a WebSocket upgrade from
+	// a cross-origin request is allowed. If nil, only
+	// requests from the same origin are allowed.
+	CheckOrigin func(*http.Request) bool
+
+	// Errors configures ErrInvalidRequest, which is returned
+	// when a stream cannot be started (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// EventsStreamController streams BlockEvents from an
+// EventsAPIServicer over WebSocket or Server-Sent Events.
+// It is an extension of the Rosetta API, so it must be
+// explicitly added to a router with NewRouter.
+type EventsStreamController struct {
+	service  EventsAPIServicer
+	asserter *asserter.Asserter
+	config   EventsStreamConfig
+	upgrader websocket.Upgrader
+}
+
+// NewEventsStreamController creates a new EventsStreamController
+// backed by the same EventsAPIServicer that serves /events/blocks.
+func NewEventsStreamController(
+	s EventsAPIServicer,
+	asserter *asserter.Asserter,
+	config *EventsStreamConfig,
+) Router {
+	c := &EventsStreamController{
+		service:  s,
+		asserter: asserter,
+	}
+
+	if config != nil {
+		c.config = *config
+	}
+
+	if c.config.PollInterval <= 0 {
+		c.config.PollInterval = DefaultEventsPollInterval
+	}
+
+	if c.config.Limit <= 0 {
+		c.config.Limit = DefaultEventsStreamLimit
+	}
+
+	if c.config.HeartbeatInterval <= 0 {
+		c.config.HeartbeatInterval = DefaultEventsHeartbeatInterval
+	}
+
+	c.upgrader = websocket.Upgrader{CheckOrigin: c.config.CheckOrigin}
+
+	return c
+}
+
+// Routes returns all of the api route for the EventsStreamController
+func (c *EventsStreamController) Routes() Routes {
+	return Routes{
+		{
+			"EventsStream",
+			http.MethodGet,
+			EventsStreamPath,
+			c.EventsStream,
+		},
+	}
+}
+
+// eventsWriter writes messages to a stream.
+type eventsWriter interface {
+	event(*types.BlockEvent) error
+	error(*types.Error) error
+	heartbeat() error
+}
+
+// EventsStream - [EXTENSION] Stream BlockEvents
+//
+// The network is specified with the blockchain, network, and
+// (optional) sub_network query parameters. Events are streamed
+// from offset (or from Last-Event-ID for SSE reconnects). If no
+// offset is provided, only new events are streamed.
+func (c *EventsStreamController) EventsStream(w http.ResponseWriter, r *http.Request) {
+	request, err := c.streamRequest(r)
+	if err != nil {
+		c.config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var writer eventsWriter
+	switch {
+	case websocket.IsWebSocketUpgrade(r):
+		conn, err := c.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already
+			// responded with an error.
+			return
+		}
+		defer conn.Close()
+
+		// Control messages are only processed while reading, so
+		// messages are read (and discarded) until the client
+		// closes the connection.
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		writer = &websocketWriter{conn: conn}
+	case strings.Contains(r.Header.Get("Accept"), sseContentType):
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			EncodeJSONResponse(&types.Error{
+				Message: "streaming is not supported",
+			}, http.StatusInternalServerError, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", sseContentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		writer = &sseWriter{w: w, flusher: flusher}
+	default:
+		c.config.Errors.encode(ErrInvalidRequest, errStreamUnsupported.Error(), w)
+		return
+	}
+
+	c.stream(ctx, request, writer)
+}
+
+// streamRequest parses the *types.EventsBlocksRequest
+// used to start a stream.
+func (c *EventsStreamController) streamRequest(
+	r *http.Request,
+) (*types.EventsBlocksRequest, error) {
+	query := r.URL.Query()
+	request := &types.EventsBlocksRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{
+			Blockchain: query.Get("blockchain"),
+			Network:    query.Get("network"),
+		},
+		Limit: types.Int64(c.config.Limit),
+	}
+
+	if subNetwork := query.Get("sub_network"); len(subNetwork) > 0 {
+		request.NetworkIdentifier.SubNetworkIdentifier = &types.SubNetworkIdentifier{
+			Network: subNetwork,
+		}
+	}
+
+	offset := query.Get("offset")
+	if lastEventID := r.Header.Get("Last-Event-ID"); len(lastEventID) > 0 {
+		lastSequence, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Last-Event-ID: %w", err)
+		}
+
+		offset = strconv.FormatInt(lastSequence+1, 10)
+	}
+
+	if len(offset) > 0 {
+		parsed, err := strconv.ParseInt(offset, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse offset: %w", err)
+		}
+
+		request.Offset = &parsed
+	}
+
+	if err := c.asserter.EventsBlocksRequest(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// stream polls the EventsAPIServicer and writes each
+// BlockEvent to writer until ctx is done, a write
+// fails, or the servicer returns a non-retriable
+// error.
+func (c *EventsStreamController) stream(
+	ctx context.Context,
+	request *types.EventsBlocksRequest,
+	writer eventsWriter,
+) {
+	lastWrite := time.Now()
+	for ctx.Err() == nil {
+		response, serviceErr := c.service.EventsBlocks(ctx, request)
+		switch {
+		case serviceErr != nil:
+			if err := writer.error(serviceErr); err != nil || !serviceErr.Retriable {
+				return
+			}
+
+			lastWrite = time.Now()
+		case request.Offset == nil:
+			// Only stream events after the
+			// current max sequence.
+			next := response.MaxSequence + 1
+			request.Offset = &next
+			continue
+		default:
+			for _, event := range response.Events {
+				if err := writer.event(event); err != nil {
+					return
+				}
+
+				next := event.Sequence + 1
+				request.Offset = &next
+				lastWrite = time.Now()
+			}
+
+			if int64(len(response.Events)) == c.config.Limit {
+				// There may be more events
+				// available.
+				continue
+			}
+		}
+
+		if time.Since(lastWrite) >= c.config.HeartbeatInterval {
+			if err := writer.heartbeat(); err != nil {
+				return
+			}
+
+			lastWrite = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.config.PollInterval):
+		}
+	}
+}
+
+// streamEncodingError returns ErrStreamEncoding
+// with the reason err in its description.
+func streamEncodingError(err error) *types.Error {
+	encodingErr := *ErrStreamEncoding
+	encodingErr.Description = types.String(err.Error())
+	return &encodingErr
+}
+
+// sseWriter writes Server-Sent Events. The id of each event
+// is its sequence (so that clients can resume with
+// Last-Event-ID) and the type is the BlockEventType.
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s *sseWriter) write(message string) error {
+	if _, err := s.w.Write([]byte(message)); err != nil {
+		return err
+	}
+
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseWriter) event(event *types.BlockEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return s.encodingError(err)
+	}
+
+	return s.write(fmt.Sprintf(
+		"id: %d\nevent: %s\ndata: %s\n\n",
+		event.Sequence,
+		event.Type,
+		data,
+	))
+}
+
+func (s *sseWriter) error(err *types.Error) error {
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		return s.encodingError(marshalErr)
+	}
+
+	return s.write(fmt.Sprintf("event: error\ndata: %s\n\n", data))
+}
+
+// encodingError writes ErrStreamEncoding and returns
+// err (so that the stream is closed).
+func (s *sseWriter) encodingError(err error) error {
+	// ErrStreamEncoding with a string
+	// description can always be encoded.
+	data, _ := json.Marshal(streamEncodingError(err))
+	if writeErr := s.write(fmt.Sprintf("event: error\ndata: %s\n\n", data)); writeErr != nil {
+		return writeErr
+	}
+
+	return err
+}
+
+func (s *sseWriter) heartbeat() error {
+	return s.write(": heartbeat\n\n")
+}
+
+// EventsStreamMessage is a message sent
+// on an events WebSocket.
+type EventsStreamMessage struct {
+	Event *types.BlockEvent `json:"event,omitempty"`
+	Error *types.Error      `json:"error,omitempty"`
+}
+
+// websocketWriter writes an EventsStreamMessage
+// for each event (or error) and pings when idle.
+type websocketWriter struct {
+	conn *websocket.Conn
+}
+
+func (s *websocketWriter) write(message *EventsStreamMessage) error {
+	b, err := json.Marshal(message)
+	if err != nil {
+		// ErrStreamEncoding with a string
+		// description can always be encoded.
+		b, _ = json.Marshal(&EventsStreamMessage{Error: streamEncodingError(err)})
+		if writeErr := s.writeMessage(b); writeErr != nil {
+			return writeErr
+		}
+
+		return err
+	}
+
+	return s.writeMessage(b)
+}
+
+// writeMessage writes an encoded
+// EventsStreamMessage.
+func (s *websocketWriter) writeMessage(b []byte) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout)); err != nil {
+		return err
+	}
+
+	return s.conn.WriteMessage(websocket.TextMessage, b)
+}
+
+func (s *websocketWriter) event(event *types.BlockEvent) error {
+	return s.write(&EventsStreamMessage{Event: event})
+}
+
+func (s *websocketWriter) error(err *types.Error) error {
+	return s.write(&EventsStreamMessage{Error: err})
+}
+
+func (s *websocketWriter) heartbeat() error {
+	return s.conn.WriteControl(
+		websocket.PingMessage,
+		nil,
+		time.Now().Add(eventsWriteTimeout),
+	)
+}
@@ -0,0 +1,296 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// eventsServicer serves events from a slice
+// that can be appended to.
+type eventsServicer struct {
+	mutex  sync.Mutex
+	events []*types.BlockEvent
+	err    *types.Error
+}
+
+func (s *eventsServicer) add(eventType types.BlockEventType, index int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.events = append(s.events, &types.BlockEvent{
+		Sequence: int64(len(s.events)),
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: index,
+			Hash:  fmt.Sprintf("block %d", index),
+		},
+		Type: eventType,
+	})
+}
+
+func (s *eventsServicer) EventsBlocks(
+	ctx context.Context,
+	request *types.EventsBlocksRequest,
+) (*types.EventsBlocksResponse, *types.Error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	response := &types.EventsBlocksResponse{
+		MaxSequence: int64(len(s.events)) - 1,
+		Events:      []*types.BlockEvent{},
+	}
+
+	offset := int64(0)
+	if request.Offset != nil {
+		offset = *request.Offset
+	}
+
+	for i := offset; i < int64(len(s.events)) && i < offset+*request.Limit; i++ {
+		response.Events = append(response.Events, s.events[i])
+	}
+
+	return response, nil
+}
+
+func eventsStreamServer(t *testing.T, servicer *eventsServicer) *httptest.Server {
+	a, err := asserter.NewServer(
+		[]string{"TRANSFER"},
+		false,
+		[]*types.NetworkIdentifier{validationNetwork},
+		nil,
+		false,
+		"",
+	)
+	assert.NoError(t, err)
+
+	return httptest.NewServer(NewRouter(NewEventsStreamController(servicer, a, &EventsStreamConfig{
+		PollInterval: 10 * time.Millisecond,
+		Limit:        2,
+		Errors:       &ErrorConfig{Status: ErrorCodeStatus},
+	})))
+}
+
+func streamQuery(offset string) string {
+	query := url.Values{}
+	query.Set("blockchain", validationNetwork.Blockchain)
+	query.Set("network", validationNetwork.Network)
+	if len(offset) > 0 {
+		query.Set("offset", offset)
+	}
+
+	return EventsStreamPath + "?" + query.Encode()
+}
+
+func TestEventsStreamSSE(t *testing.T) {
+	servicer := &eventsServicer{}
+	for i := int64(0); i < 3; i++ {
+		servicer.add(types.ADDED, i)
+	}
+
+	s := eventsStreamServer(t, servicer)
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Resume after the first event.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+streamQuery(""), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "0")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Events added after the stream
+	// starts are also streamed.
+	servicer.add(types.REMOVED, 2)
+
+	reader := bufio.NewReader(resp.Body)
+	for _, expected := range []struct {
+		sequence  int64
+		eventType types.BlockEventType
+	}{
+		{1, types.ADDED},
+		{2, types.ADDED},
+		{3, types.REMOVED},
+	} {
+		lines := []string{}
+		for {
+			line, err := reader.ReadString('\n')
+			assert.NoError(t, err)
+			if line == "\n" {
+				break
+			}
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+
+		assert.Len(t, lines, 3)
+		assert.Equal(t, fmt.Sprintf("id: %d", expected.sequence), lines[0])
+		assert.Equal(t, fmt.Sprintf("event: %s", expected.eventType), lines[1])
+
+		var event types.BlockEvent
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event))
+		assert.Equal(t, expected.sequence, event.Sequence)
+	}
+}
+
+func TestEventsStreamWebSocket(t *testing.T) {
+	servicer := &eventsServicer{}
+	servicer.add(types.ADDED, 0)
+
+	s := eventsStreamServer(t, servicer)
+	defer s.Close()
+
+	// Without an offset, only new
+	// events are streamed.
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(s.URL, "http")+streamQuery(""),
+		nil,
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// Wait for the stream to start.
+	time.Sleep(50 * time.Millisecond)
+	servicer.add(types.ADDED, 1)
+
+	var message EventsStreamMessage
+	assert.NoError(t, conn.ReadJSON(&message))
+	assert.Nil(t, message.Error)
+	assert.Equal(t, int64(1), message.Event.Sequence)
+	assert.Equal(t, int64(1), message.Event.BlockIdentifier.Index)
+}
+
+func TestEventsStreamUnencodable(t *testing.T) {
+	servicer := &eventsServicer{
+		err: &types.Error{
+			Code:    1,
+			Message: "unencodable",
+			Details: map[string]interface{}{"callback": func() {}},
+		},
+	}
+
+	s := eventsStreamServer(t, servicer)
+	defer s.Close()
+
+	t.Run("sse", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, s.URL+streamQuery("0"), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// The error is sent and
+		// the stream is closed.
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSuffix(string(body), "\n\n"), "\n")
+		assert.Len(t, lines, 2)
+		assert.Equal(t, "event: error", lines[0])
+
+		var rosettaErr types.Error
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &rosettaErr))
+		assert.Equal(t, ErrStreamEncoding.Code, rosettaErr.Code)
+		assert.NotNil(t, rosettaErr.Description)
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(
+			"ws"+strings.TrimPrefix(s.URL, "http")+streamQuery("0"),
+			nil,
+		)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		var message EventsStreamMessage
+		assert.NoError(t, conn.ReadJSON(&message))
+		assert.Equal(t, ErrStreamEncoding.Code, message.Error.Code)
+
+		_, _, err = conn.ReadMessage()
+		assert.Error(t, err)
+	})
+}
+
+func TestEventsStreamInvalid(t *testing.T) {
+	s := eventsStreamServer(t, &eventsServicer{})
+	defer s.Close()
+
+	var tests = map[string]struct {
+		path   string
+		accept string
+	}{
+		"unsupported network": {
+			path:   EventsStreamPath + "?blockchain=Bitcoin&network=Testnet3",
+			accept: "text/event-stream",
+		},
+		"invalid offset": {
+			path:   streamQuery("latest"),
+			accept: "text/event-stream",
+		},
+		"negative offset": {
+			path:   streamQuery("-1"),
+			accept: "text/event-stream",
+		},
+		"not streaming": {
+			path:   streamQuery(""),
+			accept: "application/json",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, s.URL+test.path, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Accept", test.accept)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var rosettaErr types.Error
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&rosettaErr))
+			assert.Equal(t, ErrInvalidRequest.Code, rosettaErr.Code)
+		})
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// MetricsPath serves Prometheus metrics.
+	MetricsPath = "/metrics"
+
+	// HealthPath responds with 200 while
+	// the server is running.
+	HealthPath = "/healthz"
+
+	// ReadinessPath responds with 200 if all
+	// ReadinessChecks pass (and 503 otherwise).
+	ReadinessPath = "/readyz"
+
+	// DefaultReadinessTimeout is the maximum time
+	// all ReadinessChecks can take.
+	DefaultReadinessTimeout = 5 * time.Second
+
+	// otherRoute is the route label of requests
+	// to paths that are not Rosetta routes (to
+	// bound the cardinality of metrics).
+	otherRoute = "other"
+
+	readinessOK = "ok"
+)
+
+var _ prometheus.Collector = (*PrometheusMetrics)(nil)
+
+// PrometheusMetrics records the count, latency, and
+// status of requests to each Rosetta route. It can be
+// registered as a prometheus.Collector.
+type PrometheusMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// NewPrometheusMetrics returns a new PrometheusMetrics
+// where all metric names are prefixed with namespace.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	return &PrometheusMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of requests by route and status code.",
+		}, []string{"route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of requests by route.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10), // nolint:gomnd
+		}, []string{"route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of requests being served.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (p *PrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	p.requests.Describe(ch)
+	p.duration.Describe(ch)
+	p.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (p *PrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	p.requests.Collect(ch)
+	p.duration.Collect(ch)
+	p.inFlight.Collect(ch)
+}
+
+// metricsRoute returns the route label of path.
+func metricsRoute(path string) string {
+	if _, ok := routeValidators[path]; ok {
+		return path
+	}
+
+	switch path {
+	case EventsStreamPath, SubmissionStatusPath, OpenAPIPath, MetricsPath, HealthPath, ReadinessPath:
+		return path
+	default:
+		return otherRoute
+	}
+}
+
+// Middleware returns a Middleware that records
+// every request in p.
+func (p *PrometheusMetrics) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			p.inFlight.Inc()
+			defer p.inFlight.Dec()
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			route := metricsRoute(r.URL.Path)
+			p.requests.WithLabelValues(route, strconv.Itoa(sw.status)).Inc()
+			p.duration.WithLabelValues(route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// statusWriter records the status of a response
+// without buffering it (so that streaming and
+// upgraded connections are not affected).
+type statusWriter struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records status.
+func (s *statusWriter) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes b to the response.
+func (s *statusWriter) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher (if the
+// underlying http.ResponseWriter does).
+func (s *statusWriter) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker (if the
+// underlying http.ResponseWriter does).
+func (s *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("http.Hijacker is not supported")
+	}
+
+	s.status = http.StatusSwitchingProtocols
+	s.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+// ReadinessCheck determines if a server is ready
+// to serve requests (ex: if the node is synced).
+type ReadinessCheck struct {
+	Name  string
+	Check func(context.Context) error
+}
+
+// ReadinessResponse is returned on ReadinessPath with
+// the result of each ReadinessCheck ("ok" or an error).
+type ReadinessResponse struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// InstrumentationConfig configures
+// NewInstrumentationController.
+type InstrumentationConfig struct {
+	// Registry is served on MetricsPath. If nil, a new
+	// registry with Go and process collectors is used.
+	Registry *prometheus.Registry
+
+	// Metrics are registered with Registry (if
+	// not nil).
+	Metrics *PrometheusMetrics
+
+	// ReadinessChecks are run concurrently on
+	// each request to ReadinessPath.
+	ReadinessChecks []*ReadinessCheck
+
+	// ReadinessTimeout is the maximum time all
+	// ReadinessChecks can take. If 0,
+	// DefaultReadinessTimeout is used.
+	ReadinessTimeout time.Duration
+}
+
+// A InstrumentationController serves metrics, health,
+// and readiness endpoints.
+type InstrumentationController struct {
+	metrics          http.Handler
+	readinessChecks  []*ReadinessCheck
+	readinessTimeout time.Duration
+}
+
+// NewInstrumentationController creates a new
+// InstrumentationController. It returns an error if
+// Metrics cannot be registered with Registry.
+func NewInstrumentationController(config *InstrumentationConfig) (Router, error) {
+	registry := config.Registry
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	if config.Metrics != nil {
+		if err := registry.Register(config.Metrics); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := config.ReadinessTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadinessTimeout
+	}
+
+	return &InstrumentationController{
+		metrics:          promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+		readinessChecks:  config.ReadinessChecks,
+		readinessTimeout: timeout,
+	}, nil
+}
+
+// Routes returns all of the api route for the InstrumentationController
+func (c *InstrumentationController) Routes() Routes {
+	return Routes{
+		{
+			"Metrics",
+			http.MethodGet,
+			MetricsPath,
+			c.metrics.ServeHTTP,
+		},
+		{
+			"Health",
+			http.MethodGet,
+			HealthPath,
+			c.Health,
+		},
+		{
+			"Readiness",
+			http.MethodGet,
+			ReadinessPath,
+			c.Readiness,
+		},
+	}
+}
+
+// Health - Check if the server is running
+func (c *InstrumentationController) Health(w http.ResponseWriter, r *http.Request) {
+	EncodeJSONResponse(map[string]string{"status": readinessOK}, http.StatusOK, w)
+}
+
+// Readiness - Check if the server is ready to serve requests
+func (c *InstrumentationController) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), c.readinessTimeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+
+	results := make(chan *result, len(c.readinessChecks))
+	for _, check := range c.readinessChecks {
+		go func(check *ReadinessCheck) {
+			results <- &result{name: check.Name, err: check.Check(ctx)}
+		}(check)
+	}
+
+	response := &ReadinessResponse{
+		Ready:  true,
+		Checks: map[string]string{},
+	}
+	for _, check := range c.readinessChecks {
+		response.Checks[check.Name] = context.DeadlineExceeded.Error()
+	}
+
+	// Checks that do not finish before the
+	// timeout are reported as exceeded.
+wait:
+	for range c.readinessChecks {
+		select {
+		case res := <-results:
+			response.Checks[res.name] = readinessOK
+			if res.err != nil {
+				response.Checks[res.name] = res.err.Error()
+			}
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	status := http.StatusOK
+	for _, check := range response.Checks {
+		if check != readinessOK {
+			response.Ready = false
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	EncodeJSONResponse(response, status, w)
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics("rosetta")
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			EncodeJSONResponse(map[string]string{}, http.StatusOK, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}), metrics.Middleware())
+
+	for _, path := range []string{"/block", "/block", "/account/balance", "/unknown/1"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(
+		metrics.requests.WithLabelValues("/block", "200"),
+	))
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		metrics.requests.WithLabelValues("/account/balance", "500"),
+	))
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		metrics.requests.WithLabelValues(otherRoute, "500"),
+	))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.inFlight))
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.duration))
+}
+
+func TestInstrumentationController(t *testing.T) {
+	metrics := NewPrometheusMetrics("rosetta")
+	synced := false
+	controller, err := NewInstrumentationController(&InstrumentationConfig{
+		Registry: prometheus.NewRegistry(),
+		Metrics:  metrics,
+		ReadinessChecks: []*ReadinessCheck{
+			{
+				Name: "synced",
+				Check: func(ctx context.Context) error {
+					if !synced {
+						return errors.New("node is syncing")
+					}
+
+					return nil
+				},
+			},
+			{
+				Name: "database",
+				Check: func(ctx context.Context) error {
+					return nil
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	handler := Chain(NewRouter(controller), metrics.Middleware())
+
+	t.Run("health", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response ReadinessResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, ReadinessResponse{
+			Ready: false,
+			Checks: map[string]string{
+				"synced":   "node is syncing",
+				"database": "ok",
+			},
+		}, response)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		synced = true
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.Contains(
+			w.Body.String(),
+			`rosetta_http_requests_total{code="503",route="/readyz"} 1`,
+		))
+	})
+
+	t.Run("duplicate metrics", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(metrics)
+		_, err := NewInstrumentationController(&InstrumentationConfig{
+			Registry: registry,
+			Metrics:  metrics,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	controller, err := NewInstrumentationController(&InstrumentationConfig{
+		ReadinessChecks: []*ReadinessCheck{
+			{
+				Name: "slow",
+				Check: func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				},
+			},
+		},
+		ReadinessTimeout: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	NewRouter(controller).ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), context.DeadlineExceeded.Error()))
+}
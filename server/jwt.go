@@ -0,0 +1,308 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultJWKSRefreshInterval is the default interval
+	// at which the JWKS of a *JWTAuthenticator is fetched.
+	DefaultJWKSRefreshInterval = 5 * time.Minute
+
+	// DefaultJWKSTimeout is the default timeout
+	// of a request to fetch a JWKS.
+	DefaultJWKSTimeout = 10 * time.Second
+
+	// minJWKSRefreshInterval is the minimum interval between
+	// fetches of a JWKS triggered by an unknown key id.
+	minJWKSRefreshInterval = 30 * time.Second
+
+	bearerPrefix = "Bearer "
+)
+
+// jwtAlgorithms are the signature algorithms
+// accepted by a *JWTAuthenticator.
+var jwtAlgorithms = map[string]struct{}{
+	string(jose.RS256): {},
+	string(jose.RS384): {},
+	string(jose.RS512): {},
+	string(jose.ES256): {},
+	string(jose.ES384): {},
+	string(jose.ES512): {},
+}
+
+// JWTConfig configures a *JWTAuthenticator.
+type JWTConfig struct {
+	// JWKSURL is the URL of the JSON Web Key Set
+	// used to verify the signature of tokens.
+	JWKSURL string
+
+	// Issuer and Audience (if populated) must
+	// match the iss and aud claims of tokens.
+	Issuer   string
+	Audience string
+
+	// AllowMissingExpiry accepts tokens without an
+	// exp claim (by default, exp is required).
+	AllowMissingExpiry bool
+
+	// RefreshInterval defaults to
+	// DefaultJWKSRefreshInterval.
+	RefreshInterval time.Duration
+
+	// Leeway is the clock skew allowed when
+	// checking the exp and nbf claims.
+	Leeway time.Duration
+
+	// HTTPClient defaults to an *http.Client
+	// with DefaultJWKSTimeout. The JWKS is not
+	// fetched with the context of a request, so
+	// HTTPClient should have a timeout.
+	HTTPClient *http.Client
+}
+
+// JWTAuthenticator authenticates requests with a bearer
+// token (a JWT signed with RS256, RS384, RS512, ES256, ES384,
+// or ES512) verified with a JWKS. The identity of the caller
+// is the sub claim of the token.
+type JWTAuthenticator struct {
+	config *JWTConfig
+
+	// fetches ensures only one fetch of the
+	// JWKS is in flight at a time.
+	fetches singleflight.Group
+
+	keysMutex sync.Mutex
+	keys      map[string]interface{}
+	fetched   time.Time
+	attempted time.Time
+}
+
+// NewJWTAuthenticator returns a new *JWTAuthenticator. The
+// JWKS is fetched when the first token is verified.
+func NewJWTAuthenticator(config *JWTConfig) *JWTAuthenticator {
+	c := *config
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = DefaultJWKSRefreshInterval
+	}
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: DefaultJWKSTimeout}
+	}
+
+	return &JWTAuthenticator{config: &c}
+}
+
+// Authenticate verifies the bearer token of r and
+// returns its subject.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (string, error) {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return "", ErrNoCredentials
+	}
+
+	claims, err := a.verify(r.Context(), strings.TrimPrefix(authorization, bearerPrefix))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCredentials, err.Error())
+	}
+
+	return claims.Subject, nil
+}
+
+// verify checks the signature and claims
+// of token.
+func (a *JWTAuthenticator) verify(ctx context.Context, token string) (*jwt.Claims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("token is malformed: %w", err)
+	}
+
+	if len(parsed.Headers) != 1 {
+		return nil, errors.New("token must have exactly one signature")
+	}
+
+	header := parsed.Headers[0]
+	if _, ok := jwtAlgorithms[header.Algorithm]; !ok {
+		return nil, fmt.Errorf("algorithm %s is not supported", header.Algorithm)
+	}
+
+	key, err := a.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.Claims
+	if err := parsed.Claims(key, &claims); err != nil {
+		return nil, fmt.Errorf("signature is invalid: %w", err)
+	}
+
+	if err := a.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+// validateClaims ensures the claims of a
+// token are valid at the current time.
+func (a *JWTAuthenticator) validateClaims(claims *jwt.Claims) error {
+	if claims.Expiry == nil && !a.config.AllowMissingExpiry {
+		return errors.New("expiry is missing")
+	}
+
+	expected := jwt.Expected{
+		Issuer: a.config.Issuer,
+		Time:   time.Now(),
+	}
+	if len(a.config.Audience) > 0 {
+		expected.Audience = jwt.Audience{a.config.Audience}
+	}
+
+	if err := claims.ValidateWithLeeway(expected, a.config.Leeway); err != nil {
+		return err
+	}
+
+	if len(claims.Subject) == 0 {
+		return errors.New("subject is missing")
+	}
+
+	return nil
+}
+
+// key returns the public key with keyID, fetching the
+// JWKS if it is stale or does not contain keyID.
+func (a *JWTAuthenticator) key(ctx context.Context, keyID string) (interface{}, error) {
+	a.keysMutex.Lock()
+	key, ok := a.keys[keyID]
+	loaded := a.keys != nil
+	stale := time.Since(a.fetched) >= a.config.RefreshInterval
+	// We limit how often unknown key ids trigger a fetch
+	// so that bogus tokens cannot flood the JWKS provider.
+	throttled := time.Since(a.attempted) < minJWKSRefreshInterval
+	a.keysMutex.Unlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	if !loaded || !throttled {
+		// The JWKS is fetched without holding keysMutex (so
+		// requests with known keys are not blocked) and with
+		// a background context (so a cancelled request does
+		// not fail the fetch shared by other requests).
+		result := a.fetches.DoChan("jwks", func() (interface{}, error) {
+			return nil, a.refresh()
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-result:
+			if res.Err != nil && !loaded {
+				return nil, res.Err
+			}
+		}
+	}
+
+	a.keysMutex.Lock()
+	defer a.keysMutex.Unlock()
+
+	key, ok = a.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %s is unknown", keyID)
+	}
+
+	return key, nil
+}
+
+// refresh fetches the JWKS and replaces all keys
+// (if the fetch succeeds).
+func (a *JWTAuthenticator) refresh() error {
+	keys, err := fetchJWKS(context.Background(), a.config.HTTPClient, a.config.JWKSURL)
+
+	a.keysMutex.Lock()
+	defer a.keysMutex.Unlock()
+
+	a.attempted = time.Now()
+	if err != nil {
+		return err
+	}
+
+	a.keys = keys
+	a.fetched = a.attempted
+	return nil
+}
+
+// fetchJWKS returns all supported signing
+// keys in the JWKS at url by key id.
+func fetchJWKS(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create jwks request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch jwks: status %d", resp.StatusCode)
+	}
+
+	// Each key is decoded individually so that a single
+	// unsupported key cannot prevent all others from
+	// being used.
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("unable to decode jwks: %w", err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, raw := range jwks.Keys {
+		var jwk jose.JSONWebKey
+		if err := jwk.UnmarshalJSON(raw); err != nil {
+			continue
+		}
+
+		if !jwk.Valid() || !jwk.IsPublic() || (len(jwk.Use) > 0 && jwk.Use != "sig") {
+			continue
+		}
+
+		keys[jwk.KeyID] = jwk.Key
+	}
+
+	return keys, nil
+}
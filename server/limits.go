@@ -0,0 +1,377 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// bucketSweepInterval is how often idle
+	// rate limit buckets are removed.
+	bucketSweepInterval = time.Minute
+
+	// DefaultMaxRateLimitBuckets is the default maximum
+	// number of rate limit buckets tracked at once.
+	DefaultMaxRateLimitBuckets = 100000
+)
+
+var (
+	// ErrRateLimited is returned when a caller
+	// exceeds the rate limit of a route.
+	ErrRateLimited = &types.Error{
+		Code:      http.StatusTooManyRequests,
+		Message:   "rate limit exceeded",
+		Retriable: true,
+	}
+
+	// ErrRequestTooLarge is returned when the body
+	// of a request exceeds the limit of a route.
+	ErrRequestTooLarge = &types.Error{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: "request body too large",
+	}
+)
+
+// RateLimitKeyFunc returns the key a request is
+// rate limited by (ex: the IP of the caller).
+type RateLimitKeyFunc func(*http.Request) string
+
+// ClientIPKey rate limits requests by the
+// IP address of the caller.
+func ClientIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// APIKeyHeaderKey returns a RateLimitKeyFunc that rate limits
+// requests by the API key in header (falling back to the IP
+// of the caller when a request has no API key). If header is
+// empty, DefaultAPIKeyHeader is used.
+//
+// The API key is not verified, so callers can avoid the limit
+// by sending a different key with each request. Prefer
+// IdentityKey unless every route requires an API key.
+func APIKeyHeaderKey(header string) RateLimitKeyFunc {
+	if len(header) == 0 {
+		header = DefaultAPIKeyHeader
+	}
+
+	return func(r *http.Request) string {
+		if key := r.Header.Get(header); len(key) > 0 {
+			return "key:" + key
+		}
+
+		return "ip:" + ClientIPKey(r)
+	}
+}
+
+// IdentityKey rate limits requests by the identity of the
+// caller authenticated by AuthMiddleware (falling back to
+// the IP of the caller for public routes).
+func IdentityKey(r *http.Request) string {
+	if identity, ok := Identity(r.Context()); ok {
+		return "identity:" + identity
+	}
+
+	return "ip:" + ClientIPKey(r)
+}
+
+// RateLimitRule limits the rate of requests each
+// caller can make to all routes under PathPrefix.
+type RateLimitRule struct {
+	PathPrefix string
+
+	// Rate is the number of requests allowed per
+	// second (on average). If 0, requests are not
+	// rate limited.
+	Rate float64
+
+	// Burst is the number of requests allowed
+	// at once. If 0, the ceiling of Rate is used.
+	Burst int
+}
+
+// RateLimitConfig configures RateLimitMiddleware.
+type RateLimitConfig struct {
+	// Rules are matched by the longest PathPrefix. Requests
+	// to routes without a matching rule are not rate limited.
+	// Each rule has its own bucket for each caller.
+	Rules []*RateLimitRule
+
+	// Key determines the caller of a request. If nil,
+	// IdentityKey is used.
+	Key RateLimitKeyFunc
+
+	// MaxBuckets is the maximum number of buckets (one for
+	// each rule and caller) tracked at once. When a new
+	// bucket would exceed it, the least recently used bucket
+	// is evicted. If 0, DefaultMaxRateLimitBuckets is used.
+	MaxBuckets int
+
+	// Errors configures ErrRateLimited
+	// (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// bucket is a token bucket.
+type bucket struct {
+	key     string
+	rule    *RateLimitRule
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter tracks a bucket for each
+// rule and caller.
+type rateLimiter struct {
+	rules      map[string]*RateLimitRule
+	prefixes   []string
+	key        RateLimitKeyFunc
+	maxBuckets int
+	now        func() time.Time
+
+	mutex   sync.Mutex
+	buckets map[string]*list.Element
+	recent  *list.List // of *bucket, most recently used first
+	swept   time.Time
+}
+
+func newRateLimiter(config *RateLimitConfig, now func() time.Time) *rateLimiter {
+	limiter := &rateLimiter{
+		rules:      map[string]*RateLimitRule{},
+		key:        config.Key,
+		maxBuckets: config.MaxBuckets,
+		now:        now,
+		buckets:    map[string]*list.Element{},
+		recent:     list.New(),
+		swept:      now(),
+	}
+
+	if limiter.key == nil {
+		limiter.key = IdentityKey
+	}
+
+	if limiter.maxBuckets <= 0 {
+		limiter.maxBuckets = DefaultMaxRateLimitBuckets
+	}
+
+	for _, rule := range config.Rules {
+		limiter.rules[rule.PathPrefix] = rule
+		limiter.prefixes = append(limiter.prefixes, rule.PathPrefix)
+	}
+
+	return limiter
+}
+
+func burst(rule *RateLimitRule) float64 {
+	if rule.Burst > 0 {
+		return float64(rule.Burst)
+	}
+
+	return math.Ceil(rule.Rate)
+}
+
+// allow consumes a token from the bucket of the caller
+// of r. If the bucket is empty, it returns how long the
+// caller must wait for a token.
+func (l *rateLimiter) allow(r *http.Request) (bool, time.Duration) {
+	matched, ok := matchPrefix(r.URL.Path, l.prefixes)
+	if !ok {
+		return true, 0
+	}
+
+	rule := l.rules[matched]
+	if rule.Rate <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	key := matched + "\x00" + l.key(r)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.sweep(now)
+
+	size := burst(rule)
+	var b *bucket
+	if element, ok := l.buckets[key]; ok {
+		b = element.Value.(*bucket)
+		l.recent.MoveToFront(element)
+	} else {
+		if len(l.buckets) >= l.maxBuckets {
+			l.evict()
+		}
+
+		b = &bucket{key: key, rule: rule, tokens: size, updated: now}
+		l.buckets[key] = l.recent.PushFront(b)
+	}
+
+	b.tokens = math.Min(size, b.tokens+now.Sub(b.updated).Seconds()*rule.Rate)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / rule.Rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep removes buckets that have refilled (these
+// callers have been idle for long enough that a new
+// bucket is equivalent).
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < bucketSweepInterval {
+		return
+	}
+
+	for key, element := range l.buckets {
+		b := element.Value.(*bucket)
+		if b.tokens+now.Sub(b.updated).Seconds()*b.rule.Rate >= burst(b.rule) {
+			l.recent.Remove(element)
+			delete(l.buckets, key)
+		}
+	}
+
+	l.swept = now
+}
+
+// evict removes the least recently used bucket.
+func (l *rateLimiter) evict() {
+	element := l.recent.Back()
+	if element == nil {
+		return
+	}
+
+	l.recent.Remove(element)
+	delete(l.buckets, element.Value.(*bucket).key)
+}
+
+// RateLimitMiddleware limits the rate of requests each
+// caller can make with a token bucket for each rule in
+// config. Requests that exceed the limit are rejected with
+// ErrRateLimited (and a Retry-After header).
+func RateLimitMiddleware(config *RateLimitConfig) Middleware {
+	limiter := newRateLimiter(config, time.Now)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, wait := limiter.allow(r)
+			if !allowed {
+				seconds := int64(math.Ceil(wait.Seconds()))
+				w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+				config.Errors.encode(
+					ErrRateLimited,
+					fmt.Sprintf("retry in %s", wait.Round(time.Millisecond)),
+					w,
+				)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// BodyLimitRule limits the size of the body of
+// requests to all routes under PathPrefix.
+type BodyLimitRule struct {
+	PathPrefix string
+
+	// MaxBytes is the maximum size of the body of
+	// a request. If 0, the size is not limited.
+	MaxBytes int64
+}
+
+// BodyLimitConfig configures BodyLimitMiddleware.
+type BodyLimitConfig struct {
+	// MaxBytes is the limit for routes without
+	// a matching rule. If 0, the size is not
+	// limited.
+	MaxBytes int64
+
+	// Rules are matched by the longest PathPrefix.
+	Rules []*BodyLimitRule
+
+	// Errors configures ErrRequestTooLarge and
+	// ErrInvalidRequest (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// BodyLimitMiddleware rejects requests with a body larger
+// than the limit of their route with ErrRequestTooLarge.
+func BodyLimitMiddleware(config *BodyLimitConfig) Middleware {
+	limits := map[string]int64{}
+	prefixes := []string{}
+	for _, rule := range config.Rules {
+		limits[rule.PathPrefix] = rule.MaxBytes
+		prefixes = append(prefixes, rule.PathPrefix)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := config.MaxBytes
+			if prefix, ok := matchPrefix(r.URL.Path, prefixes); ok {
+				limit = limits[prefix]
+			}
+
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tooLarge := fmt.Sprintf("limit for %s is %d bytes", r.URL.Path, limit)
+			if r.ContentLength > limit {
+				config.Errors.encode(ErrRequestTooLarge, tooLarge, w)
+				return
+			}
+
+			// The Content-Length may be unknown (or
+			// incorrect), so at most limit+1 bytes
+			// are read to determine the size.
+			body, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+			if err != nil {
+				config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+				return
+			}
+
+			if int64(len(body)) > limit {
+				config.Errors.encode(ErrRequestTooLarge, tooLarge, w)
+				return
+			}
+
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func limitRequest(path string, remoteAddr string, apiKey string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.RemoteAddr = remoteAddr
+	if len(apiKey) > 0 {
+		req.Header.Set(DefaultAPIKeyHeader, apiKey)
+	}
+
+	return req
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(&RateLimitConfig{
+		Rules: []*RateLimitRule{
+			{PathPrefix: "/", Rate: 1, Burst: 2},
+			{PathPrefix: "/construction/", Rate: 0.5},
+			{PathPrefix: "/network/", Rate: 0},
+		},
+		Key: APIKeyHeaderKey(""),
+	}, func() time.Time { return now })
+
+	// The burst is allowed.
+	for i := 0; i < 2; i++ {
+		allowed, _ := limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+		assert.True(t, allowed)
+	}
+
+	allowed, wait := limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, wait)
+
+	// Callers and route groups have
+	// separate buckets.
+	allowed, _ = limiter.allow(limitRequest("/block", "1.1.1.1:81", "key"))
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow(limitRequest("/block", "2.2.2.2:80", ""))
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow(limitRequest("/construction/submit", "1.1.1.1:80", ""))
+	assert.True(t, allowed)
+	allowed, wait = limiter.allow(limitRequest("/construction/submit", "1.1.1.1:80", ""))
+	assert.False(t, allowed)
+	assert.Equal(t, 2*time.Second, wait)
+
+	// Rules without a rate are not limited.
+	for i := 0; i < 10; i++ {
+		allowed, _ = limiter.allow(limitRequest("/network/list", "1.1.1.1:80", ""))
+		assert.True(t, allowed)
+	}
+
+	// Tokens are refilled over time.
+	now = now.Add(500 * time.Millisecond)
+	allowed, wait = limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.True(t, allowed)
+
+	// Idle buckets are removed.
+	assert.Len(t, limiter.buckets, 4)
+	now = now.Add(bucketSweepInterval)
+	allowed, _ = limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.True(t, allowed)
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestRateLimiterMaxBuckets(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(&RateLimitConfig{
+		Rules:      []*RateLimitRule{{PathPrefix: "/", Rate: 1}},
+		Key:        ClientIPKey,
+		MaxBuckets: 2,
+	}, func() time.Time { return now })
+
+	allowed, _ := limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow(limitRequest("/block", "2.2.2.2:80", ""))
+	assert.True(t, allowed)
+
+	// 1.1.1.1 is used more recently than 2.2.2.2.
+	allowed, _ = limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.False(t, allowed)
+
+	// The least recently used bucket is evicted.
+	allowed, _ = limiter.allow(limitRequest("/block", "3.3.3.3:80", ""))
+	assert.True(t, allowed)
+	assert.Len(t, limiter.buckets, 2)
+	assert.Equal(t, 2, limiter.recent.Len())
+	assert.Contains(t, limiter.buckets, "/\x001.1.1.1")
+	assert.NotContains(t, limiter.buckets, "/\x002.2.2.2")
+
+	allowed, _ = limiter.allow(limitRequest("/block", "1.1.1.1:80", ""))
+	assert.False(t, allowed)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	handler := Chain(identityHandler(), RateLimitMiddleware(&RateLimitConfig{
+		Rules:  []*RateLimitRule{{PathPrefix: "/", Rate: 0.1}},
+		Errors: &ErrorConfig{Status: ErrorCodeStatus},
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, limitRequest("/block", "1.1.1.1:80", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, limitRequest("/block", "1.1.1.1:80", ""))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	var rosettaErr types.Error
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+	assert.Equal(t, ErrRateLimited.Code, rosettaErr.Code)
+	assert.True(t, rosettaErr.Retriable)
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	next := &fixedHandler{response: map[string]string{}, status: http.StatusOK}
+	handler := Chain(next, BodyLimitMiddleware(&BodyLimitConfig{
+		MaxBytes: 8,
+		Rules: []*BodyLimitRule{
+			{PathPrefix: "/construction/", MaxBytes: 16},
+			{PathPrefix: "/call", MaxBytes: 0},
+		},
+		Errors: &ErrorConfig{Status: ErrorCodeStatus},
+	}))
+
+	var tests = map[string]struct {
+		path          string
+		body          string
+		unknownLength bool
+
+		status int
+	}{
+		"within default": {
+			path:   "/block",
+			body:   "12345678",
+			status: http.StatusOK,
+		},
+		"exceeds default": {
+			path:   "/block",
+			body:   "123456789",
+			status: http.StatusRequestEntityTooLarge,
+		},
+		"within rule": {
+			path:   "/construction/submit",
+			body:   "123456789",
+			status: http.StatusOK,
+		},
+		"exceeds rule": {
+			path:   "/construction/submit",
+			body:   strings.Repeat("1", 17),
+			status: http.StatusRequestEntityTooLarge,
+		},
+		"unlimited rule": {
+			path:   "/call",
+			body:   strings.Repeat("1", 100),
+			status: http.StatusOK,
+		},
+		"unknown length": {
+			path:          "/block",
+			body:          "123456789",
+			unknownLength: true,
+			status:        http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			next.body = nil
+			req := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.body))
+			if test.unknownLength {
+				req.ContentLength = -1
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, test.status, w.Code)
+
+			if test.status != http.StatusOK {
+				var rosettaErr types.Error
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+				assert.Equal(t, ErrRequestTooLarge.Code, rosettaErr.Code)
+				assert.Nil(t, next.body)
+				return
+			}
+
+			assert.Equal(t, test.body, string(next.body))
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// RedactedValue replaces the value of
+	// redacted fields in logs.
+	RedactedValue = "[REDACTED]"
+
+	// maxLoggedErrorBytes is the maximum size of an
+	// error response that is parsed to log its code.
+	maxLoggedErrorBytes = 4096
+
+	requestLogMessage = "request"
+)
+
+// DefaultRedactedFields are the fields of construction
+// requests that are redacted by default (signed and
+// unsigned transactions, signatures, and public keys).
+var DefaultRedactedFields = []string{
+	"hex_bytes",
+	"signatures",
+	"public_keys",
+	"payloads",
+	"signed_transaction",
+	"unsigned_transaction",
+	"transaction",
+}
+
+// LogLevel is the severity of a log.
+type LogLevel int
+
+// Levels of logs written by LoggingMiddleware.
+const (
+	LogLevelInfo LogLevel = iota
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the name of level.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// LogField is a key and value of
+// a structured log.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger writes structured logs. Adapters are
+// provided for the standard library (NewStdLogger),
+// slog (NewSlogLogger), and zap (NewSugaredLogger).
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields ...LogField)
+}
+
+// stdLogger writes logs as key=value
+// pairs to a *log.Logger.
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger returns a Logger that writes key=value
+// pairs to logger. If logger is nil, the standard
+// logger is used.
+func NewStdLogger(logger *log.Logger) Logger {
+	return &stdLogger{logger: logger}
+}
+
+// Log implements Logger.
+func (s *stdLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%q", level, msg)
+	for _, field := range fields {
+		value := fmt.Sprint(field.Value)
+		if _, ok := field.Value.(string); ok || strings.ContainsAny(value, " \"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+
+		fmt.Fprintf(&b, " %s=%s", field.Key, value)
+	}
+
+	if s.logger == nil {
+		log.Print(b.String())
+		return
+	}
+
+	s.logger.Print(b.String())
+}
+
+// SugaredLogger is the subset of the methods of
+// *zap.SugaredLogger used by NewSugaredLogger.
+type SugaredLogger interface {
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// sugaredLogger adapts a SugaredLogger.
+type sugaredLogger struct {
+	logger SugaredLogger
+}
+
+// NewSugaredLogger returns a Logger that writes to
+// logger (usually a *zap.SugaredLogger).
+func NewSugaredLogger(logger SugaredLogger) Logger {
+	return &sugaredLogger{logger: logger}
+}
+
+// Log implements Logger.
+func (s *sugaredLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	keysAndValues := make([]interface{}, 0, len(fields)*2) // nolint:gomnd
+	for _, field := range fields {
+		keysAndValues = append(keysAndValues, field.Key, field.Value)
+	}
+
+	switch level {
+	case LogLevelError:
+		s.logger.Errorw(msg, keysAndValues...)
+	case LogLevelWarn:
+		s.logger.Warnw(msg, keysAndValues...)
+	default:
+		s.logger.Infow(msg, keysAndValues...)
+	}
+}
+
+// LoggingConfig configures LoggingMiddleware.
+type LoggingConfig struct {
+	// Logger writes logs. If nil, NewStdLogger(nil)
+	// is used.
+	Logger Logger
+
+	// LogRequestBody includes the body of each request
+	// (with RedactedFields redacted) in logs.
+	LogRequestBody bool
+
+	// RedactedFields are JSON fields (at any depth) that are
+	// redacted from logged request bodies. If nil,
+	// DefaultRedactedFields is used.
+	RedactedFields []string
+
+	// Errors configures ErrInvalidRequest, which is returned
+	// when the body of a request cannot be read (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// loggingWriter records the status of a response and
+// the start of error responses (to log their code).
+type loggingWriter struct {
+	*statusWriter
+
+	errorBody bytes.Buffer
+}
+
+// Write writes b to the response.
+func (l *loggingWriter) Write(b []byte) (int, error) {
+	if l.status != http.StatusOK && l.errorBody.Len() < maxLoggedErrorBytes {
+		l.errorBody.Write(b)
+	}
+
+	return l.statusWriter.Write(b)
+}
+
+// LoggingMiddleware logs the method, route, network,
+// latency, status, and Rosetta error code (if any) of
+// every request with a Logger. Responses with a 5xx
+// status are logged as errors and responses with a
+// 4xx status are logged as warnings.
+func LoggingMiddleware(config *LoggingConfig) Middleware {
+	logger := config.Logger
+	if logger == nil {
+		logger = NewStdLogger(nil)
+	}
+
+	redacted := map[string]struct{}{}
+	fields := config.RedactedFields
+	if fields == nil {
+		fields = DefaultRedactedFields
+	}
+	for _, field := range fields {
+		redacted[field] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = ioutil.ReadAll(r.Body)
+				if err != nil {
+					config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+					return
+				}
+				r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+
+			lw := &loggingWriter{
+				statusWriter: &statusWriter{ResponseWriter: w, status: http.StatusOK},
+			}
+			next.ServeHTTP(lw, r)
+
+			logFields := []LogField{
+				{Key: "method", Value: r.Method},
+				{Key: "route", Value: r.URL.Path},
+			}
+
+			if network := requestNetwork(body); network != nil {
+				logFields = append(logFields, LogField{Key: "network", Value: network})
+			}
+
+			logFields = append(
+				logFields,
+				LogField{Key: "status", Value: lw.status},
+				LogField{Key: "latency", Value: time.Since(start)},
+			)
+
+			if code, ok := errorCode(lw.errorBody.Bytes()); ok {
+				logFields = append(logFields, LogField{Key: "error_code", Value: code})
+			}
+
+			if identity, ok := Identity(r.Context()); ok {
+				logFields = append(logFields, LogField{Key: "identity", Value: identity})
+			}
+
+			if config.LogRequestBody && len(body) > 0 {
+				logFields = append(logFields, LogField{
+					Key:   "request_body",
+					Value: redactBody(body, redacted),
+				})
+			}
+
+			level := LogLevelInfo
+			switch {
+			case lw.status >= http.StatusInternalServerError:
+				level = LogLevelError
+			case lw.status >= http.StatusBadRequest:
+				level = LogLevelWarn
+			}
+
+			logger.Log(r.Context(), level, requestLogMessage, logFields...)
+		})
+	}
+}
+
+// requestNetwork returns the network of a Rosetta request
+// (ex: "Bitcoin/Mainnet") or nil if there is none.
+func requestNetwork(body []byte) interface{} {
+	var request struct {
+		NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil || request.NetworkIdentifier == nil {
+		return nil
+	}
+
+	network := request.NetworkIdentifier.Blockchain + "/" + request.NetworkIdentifier.Network
+	if request.NetworkIdentifier.SubNetworkIdentifier != nil {
+		network += "/" + request.NetworkIdentifier.SubNetworkIdentifier.Network
+	}
+
+	return network
+}
+
+// errorCode returns the code of a *types.Error
+// response (if body is one).
+func errorCode(body []byte) (int32, bool) {
+	if len(body) == 0 {
+		return 0, false
+	}
+
+	var rosettaErr struct {
+		Code    *int32 `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &rosettaErr); err != nil || rosettaErr.Code == nil {
+		return 0, false
+	}
+
+	return *rosettaErr.Code, true
+}
+
+// redactBody returns body (compacted) with the value of
+// all redacted fields replaced by RedactedValue. If body
+// is not JSON, it is redacted entirely.
+func redactBody(body []byte, redacted map[string]struct{}) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return RedactedValue
+	}
+
+	return types.PrintStruct(redact(value, redacted))
+}
+
+func redact(value interface{}, redacted map[string]struct{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if _, ok := redacted[key]; ok {
+				v[key] = RedactedValue
+				continue
+			}
+
+			v[key] = redact(field, redacted)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item, redacted)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,250 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// entry is a log written to a recordingLogger.
+type entry struct {
+	level  LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+type recordingLogger struct {
+	entries []*entry
+}
+
+func (r *recordingLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	e := &entry{level: level, msg: msg, fields: map[string]interface{}{}}
+	for _, field := range fields {
+		e.fields[field.Key] = field.Value
+	}
+
+	r.entries = append(r.entries, e)
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	combineRequest := types.PrintStruct(&types.ConstructionCombineRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{
+			Blockchain: "Bitcoin",
+			Network:    "Mainnet",
+			SubNetworkIdentifier: &types.SubNetworkIdentifier{
+				Network: "shard 1",
+			},
+		},
+		UnsignedTransaction: "unsigned",
+		Signatures: []*types.Signature{
+			{
+				SigningPayload: &types.SigningPayload{
+					AccountIdentifier: &types.AccountIdentifier{Address: "addr"},
+					Bytes:             []byte("payload"),
+				},
+				PublicKey:     &types.PublicKey{Bytes: []byte("key"), CurveType: types.Secp256k1},
+				SignatureType: types.Ecdsa,
+				Bytes:         []byte("signature"),
+			},
+		},
+	})
+
+	var tests = map[string]struct {
+		config   *LoggingConfig
+		path     string
+		request  string
+		response interface{}
+		status   int
+
+		level  LogLevel
+		fields map[string]interface{}
+	}{
+		"success": {
+			config:   &LoggingConfig{},
+			path:     "/network/status",
+			request:  types.PrintStruct(&types.NetworkRequest{NetworkIdentifier: validationNetwork}),
+			response: map[string]string{},
+			status:   http.StatusOK,
+			level:    LogLevelInfo,
+			fields: map[string]interface{}{
+				"method":  http.MethodPost,
+				"route":   "/network/status",
+				"network": "Bitcoin/Mainnet",
+				"status":  http.StatusOK,
+			},
+		},
+		"rosetta error": {
+			config:   &LoggingConfig{},
+			path:     "/block",
+			request:  "{}",
+			response: &types.Error{Code: 12, Message: "block not found"},
+			status:   http.StatusInternalServerError,
+			level:    LogLevelError,
+			fields: map[string]interface{}{
+				"method":     http.MethodPost,
+				"route":      "/block",
+				"status":     http.StatusInternalServerError,
+				"error_code": int32(12),
+			},
+		},
+		"invalid request": {
+			config:   &LoggingConfig{},
+			path:     "/block",
+			request:  "{",
+			response: ErrInvalidRequest,
+			status:   http.StatusBadRequest,
+			level:    LogLevelWarn,
+			fields: map[string]interface{}{
+				"method":     http.MethodPost,
+				"route":      "/block",
+				"status":     http.StatusBadRequest,
+				"error_code": int32(http.StatusBadRequest),
+			},
+		},
+		"redacted body": {
+			config:   &LoggingConfig{LogRequestBody: true},
+			path:     "/construction/combine",
+			request:  combineRequest,
+			response: map[string]string{},
+			status:   http.StatusOK,
+			level:    LogLevelInfo,
+			fields: map[string]interface{}{
+				"method":  http.MethodPost,
+				"route":   "/construction/combine",
+				"network": "Bitcoin/Mainnet/shard 1",
+				"status":  http.StatusOK,
+				"request_body": `{"network_identifier":{"blockchain":"Bitcoin","network":"Mainnet",` +
+					`"sub_network_identifier":{"network":"shard 1"}},` +
+					`"signatures":"[REDACTED]","unsigned_transaction":"[REDACTED]"}`,
+			},
+		},
+		"custom redaction": {
+			config: &LoggingConfig{
+				LogRequestBody: true,
+				RedactedFields: []string{"hex_bytes"},
+			},
+			path: "/construction/derive",
+			request: `{"public_key":{"hex_bytes":"abcd","curve_type":"secp256k1"},` +
+				`"metadata":{"keys":[{"hex_bytes":"ef"}]}}`,
+			response: map[string]string{},
+			status:   http.StatusOK,
+			level:    LogLevelInfo,
+			fields: map[string]interface{}{
+				"method": http.MethodPost,
+				"route":  "/construction/derive",
+				"status": http.StatusOK,
+				"request_body": `{"metadata":{"keys":[{"hex_bytes":"[REDACTED]"}]},` +
+					`"public_key":{"curve_type":"secp256k1","hex_bytes":"[REDACTED]"}}`,
+			},
+		},
+		"malformed body": {
+			config:   &LoggingConfig{LogRequestBody: true},
+			path:     "/construction/submit",
+			request:  "signed transaction",
+			response: map[string]string{},
+			status:   http.StatusOK,
+			level:    LogLevelInfo,
+			fields: map[string]interface{}{
+				"method":       http.MethodPost,
+				"route":        "/construction/submit",
+				"status":       http.StatusOK,
+				"request_body": RedactedValue,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			test.config.Logger = logger
+
+			next := &fixedHandler{response: test.response, status: test.status}
+			handler := Chain(next, LoggingMiddleware(test.config))
+
+			req := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.request))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			// The handler receives the
+			// original request.
+			assert.Equal(t, test.request, string(next.body))
+			assert.Equal(t, test.status, w.Code)
+
+			assert.Len(t, logger.entries, 1)
+			e := logger.entries[0]
+			assert.Equal(t, test.level, e.level)
+			assert.Equal(t, requestLogMessage, e.msg)
+			assert.NotNil(t, e.fields["latency"])
+			delete(e.fields, "latency")
+			assert.Equal(t, test.fields, e.fields)
+		})
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := NewStdLogger(log.New(&b, "", 0))
+	logger.Log(
+		context.Background(),
+		LogLevelWarn,
+		"request",
+		LogField{Key: "route", Value: "/block"},
+		LogField{Key: "status", Value: 400},
+	)
+
+	assert.Equal(t, "level=warn msg=\"request\" route=\"/block\" status=400\n", b.String())
+}
+
+type sugared struct {
+	calls []string
+	args  []interface{}
+}
+
+func (s *sugared) Infow(msg string, keysAndValues ...interface{}) {
+	s.calls = append(s.calls, "info")
+	s.args = keysAndValues
+}
+
+func (s *sugared) Warnw(msg string, keysAndValues ...interface{}) {
+	s.calls = append(s.calls, "warn")
+	s.args = keysAndValues
+}
+
+func (s *sugared) Errorw(msg string, keysAndValues ...interface{}) {
+	s.calls = append(s.calls, "error")
+	s.args = keysAndValues
+}
+
+func TestSugaredLogger(t *testing.T) {
+	s := &sugared{}
+	logger := NewSugaredLogger(s)
+	ctx := context.Background()
+
+	logger.Log(ctx, LogLevelInfo, "request")
+	logger.Log(ctx, LogLevelWarn, "request")
+	logger.Log(ctx, LogLevelError, "request", LogField{Key: "status", Value: 500})
+
+	assert.Equal(t, []string{"info", "warn", "error"}, s.calls)
+	assert.Equal(t, []interface{}{"status", 500}, s.args)
+}
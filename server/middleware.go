@@ -0,0 +1,178 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Middleware wraps an http.Handler (ex: to authenticate
+// requests before they reach a controller).
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps handler with all middlewares. The first
+// middleware is the outermost (it is invoked first).
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// Errors returned by middleware. Rosetta error codes are defined
+// by each implementation, so the code of each error is an HTTP
+// status (used by ErrorCodeStatus) and each error can be replaced
+// with an ErrorConfig.
+var (
+	// ErrUnauthorized is returned when a request
+	// cannot be authenticated.
+	ErrUnauthorized = &types.Error{
+		Code:    http.StatusUnauthorized,
+		Message: "unauthorized",
+	}
+
+	// ErrForbidden is returned when an authenticated
+	// caller is not allowed to access a route.
+	ErrForbidden = &types.Error{
+		Code:    http.StatusForbidden,
+		Message: "forbidden",
+	}
+)
+
+// ErrorConfig configures the errors returned by middleware.
+// Rosetta error codes are defined by each implementation, so
+// each error of this package can be replaced with an error of
+// the implementation (ex: one listed in /network/options).
+type ErrorConfig struct {
+	// Replacements maps errors of this package (ex:
+	// ErrRateLimited) to the *types.Error returned
+	// instead.
+	Replacements map[*types.Error]*types.Error
+
+	// Status returns the HTTP status of the response to
+	// each error of this package (before it is replaced).
+	// It defaults to DefaultErrorStatus (a 500, as required
+	// by the Rosetta specification). ErrorCodeStatus uses
+	// the HTTP status in the Code of each error instead.
+	Status ErrorStatusFunc
+}
+
+// error returns the *types.Error to
+// respond with instead of err.
+func (c *ErrorConfig) error(err *types.Error) *types.Error {
+	if c == nil {
+		return err
+	}
+
+	if replacement, ok := c.Replacements[err]; ok && replacement != nil {
+		return replacement
+	}
+
+	return err
+}
+
+// status returns the HTTP status of
+// the response to err.
+func (c *ErrorConfig) status(err *types.Error) int {
+	if c == nil || c.Status == nil {
+		return DefaultErrorStatus(err)
+	}
+
+	return c.Status(err)
+}
+
+// encode writes err (or its replacement) with an optional
+// description to w. A nil *ErrorConfig uses the defaults.
+func (c *ErrorConfig) encode(err *types.Error, description string, w http.ResponseWriter) {
+	EncodeError(c.error(err), description, c.status(err), w)
+}
+
+// EncodeError writes err (with an optional description)
+// to the http response with status.
+func EncodeError(
+	err *types.Error,
+	description string,
+	status int,
+	w http.ResponseWriter,
+) {
+	response := *err
+	if len(description) > 0 {
+		response.Description = &description
+	}
+
+	EncodeJSONResponse(&response, status, w)
+}
+
+// matchPrefix returns the longest prefix in
+// prefixes that path starts with (and false
+// if there is none).
+func matchPrefix(path string, prefixes []string) (string, bool) {
+	match := ""
+	found := false
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) && (!found || len(prefix) > len(match)) {
+			match = prefix
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+// responseRecorder buffers the response written by an
+// http.Handler so that middleware can inspect (and
+// replace) it before it is sent.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: http.Header{},
+		status: http.StatusOK,
+	}
+}
+
+// Header returns the headers of the response.
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+// Write buffers b.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+// WriteHeader records the status of the response.
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// flush writes the buffered response to w.
+func (r *responseRecorder) flush(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes()) // nolint:errcheck
+}
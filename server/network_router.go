@@ -0,0 +1,225 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// networkListPath is served by the NetworkRouter
+	// with the networks of all NetworkHandlers.
+	networkListPath = "/network/list"
+)
+
+var (
+	// ErrNetworkHandlerInvalid is returned when a
+	// *NetworkHandler has no network or handler.
+	ErrNetworkHandlerInvalid = errors.New("network handler must have a network and handler")
+
+	// ErrNetworkDuplicate is returned when multiple
+	// *NetworkHandlers have the same network.
+	ErrNetworkDuplicate = errors.New("network has multiple handlers")
+)
+
+// NetworkHandler serves all requests
+// for a single network.
+type NetworkHandler struct {
+	Network *types.NetworkIdentifier
+	Handler http.Handler
+}
+
+// NetworkServicers are the Servicers (and Asserter) that
+// implement the Rosetta API for a single network. Nil
+// Servicers are not served.
+type NetworkServicers struct {
+	Network  *types.NetworkIdentifier
+	Asserter *asserter.Asserter
+
+	AccountAPI      AccountAPIServicer
+	BlockAPI        BlockAPIServicer
+	CallAPI         CallAPIServicer
+	ConstructionAPI ConstructionAPIServicer
+	EventsAPI       EventsAPIServicer
+	MempoolAPI      MempoolAPIServicer
+	NetworkAPI      NetworkAPIServicer
+	SearchAPI       SearchAPIServicer
+}
+
+// NetworkHandler returns a *NetworkHandler that serves
+// the controllers of all Servicers in s.
+func (s *NetworkServicers) NetworkHandler() *NetworkHandler {
+	routers := []Router{}
+	if s.AccountAPI != nil {
+		routers = append(routers, NewAccountAPIController(s.AccountAPI, s.Asserter))
+	}
+	if s.BlockAPI != nil {
+		routers = append(routers, NewBlockAPIController(s.BlockAPI, s.Asserter))
+	}
+	if s.CallAPI != nil {
+		routers = append(routers, NewCallAPIController(s.CallAPI, s.Asserter))
+	}
+	if s.ConstructionAPI != nil {
+		routers = append(routers, NewConstructionAPIController(s.ConstructionAPI, s.Asserter))
+	}
+	if s.EventsAPI != nil {
+		routers = append(routers, NewEventsAPIController(s.EventsAPI, s.Asserter))
+	}
+	if s.MempoolAPI != nil {
+		routers = append(routers, NewMempoolAPIController(s.MempoolAPI, s.Asserter))
+	}
+	if s.NetworkAPI != nil {
+		routers = append(routers, NewNetworkAPIController(s.NetworkAPI, s.Asserter))
+	}
+	if s.SearchAPI != nil {
+		routers = append(routers, NewSearchAPIController(s.SearchAPI, s.Asserter))
+	}
+
+	return &NetworkHandler{
+		Network: s.Network,
+		Handler: NewRouter(routers...),
+	}
+}
+
+// NetworkRouter dispatches each request to the
+// *NetworkHandler for the NetworkIdentifier in the
+// request so that a single server can host multiple
+// networks with isolated implementations.
+type NetworkRouter struct {
+	// Errors configures ErrInvalidRequest, which is returned
+	// when the network of a request cannot be read or is not
+	// served (see ErrorConfig).
+	Errors *ErrorConfig
+
+	networks []*types.NetworkIdentifier
+	handlers map[string]http.Handler
+}
+
+// NewNetworkRouter creates a new *NetworkRouter. /network/list
+// is served by the *NetworkRouter with the networks of all
+// handlers (in order).
+func NewNetworkRouter(handlers ...*NetworkHandler) (*NetworkRouter, error) {
+	router := &NetworkRouter{
+		handlers: map[string]http.Handler{},
+	}
+
+	for _, handler := range handlers {
+		if handler == nil || handler.Network == nil || handler.Handler == nil {
+			return nil, ErrNetworkHandlerInvalid
+		}
+
+		key := types.Hash(handler.Network)
+		if _, ok := router.handlers[key]; ok {
+			return nil, fmt.Errorf(
+				"%w: %s",
+				ErrNetworkDuplicate,
+				types.PrintStruct(handler.Network),
+			)
+		}
+
+		router.networks = append(router.networks, handler.Network)
+		router.handlers[key] = handler.Handler
+	}
+
+	return router, nil
+}
+
+// ServeHTTP dispatches r to the handler for its network. The
+// network is read from the network_identifier of the request
+// body (or, for requests without a body like the events stream,
+// from the blockchain, network, and sub_network query
+// parameters).
+func (n *NetworkRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == networkListPath {
+		EncodeJSONResponse(&types.NetworkListResponse{
+			NetworkIdentifiers: n.networks,
+		}, http.StatusOK, w)
+		return
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			n.Errors.encode(ErrInvalidRequest, err.Error(), w)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	network, err := routedNetwork(r, body)
+	if err != nil {
+		n.Errors.encode(ErrInvalidRequest, err.Error(), w)
+		return
+	}
+
+	handler, ok := n.handlers[types.Hash(network)]
+	if !ok {
+		n.Errors.encode(
+			ErrInvalidRequest,
+			fmt.Sprintf(
+				"%s: %s",
+				asserter.ErrRequestedNetworkNotSupported.Error(),
+				types.PrintStruct(network),
+			),
+			w,
+		)
+		return
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// routedNetwork returns the network of r.
+func routedNetwork(r *http.Request, body []byte) (*types.NetworkIdentifier, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		query := r.URL.Query()
+		network := &types.NetworkIdentifier{
+			Blockchain: query.Get("blockchain"),
+			Network:    query.Get("network"),
+		}
+		if subNetwork := query.Get("sub_network"); len(subNetwork) > 0 {
+			network.SubNetworkIdentifier = &types.SubNetworkIdentifier{Network: subNetwork}
+		}
+
+		if len(network.Blockchain) == 0 || len(network.Network) == 0 {
+			return nil, asserter.ErrNetworkIdentifierIsNil
+		}
+
+		return network, nil
+	}
+
+	var request struct {
+		NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, err
+	}
+
+	if request.NetworkIdentifier == nil {
+		return nil, asserter.ErrNetworkIdentifierIsNil
+	}
+
+	return request.NetworkIdentifier, nil
+}
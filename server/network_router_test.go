@@ -0,0 +1,199 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// statusServicer responds to /network/status with
+// a current block with a network-specific hash.
+type statusServicer struct {
+	NetworkAPIServicer
+
+	hash string
+}
+
+func (s *statusServicer) NetworkStatus(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*types.NetworkStatusResponse, *types.Error) {
+	return &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{Index: 1, Hash: s.hash},
+		GenesisBlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: "genesis"},
+		Peers:                  []*types.Peer{},
+	}, nil
+}
+
+func networkServicers(
+	t *testing.T,
+	network *types.NetworkIdentifier,
+	hash string,
+) *NetworkServicers {
+	a, err := asserter.NewServer(
+		[]string{"TRANSFER"},
+		false,
+		[]*types.NetworkIdentifier{network},
+		nil,
+		false,
+		"",
+	)
+	assert.NoError(t, err)
+
+	return &NetworkServicers{
+		Network:    network,
+		Asserter:   a,
+		NetworkAPI: &statusServicer{hash: hash},
+	}
+}
+
+func TestNetworkRouter(t *testing.T) {
+	mainnet := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Mainnet"}
+	testnet := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	shard := &types.NetworkIdentifier{
+		Blockchain:           "Bitcoin",
+		Network:              "Mainnet",
+		SubNetworkIdentifier: &types.SubNetworkIdentifier{Network: "shard 1"},
+	}
+
+	router, err := NewNetworkRouter(
+		networkServicers(t, mainnet, "mainnet").NetworkHandler(),
+		networkServicers(t, testnet, "testnet").NetworkHandler(),
+		networkServicers(t, shard, "shard").NetworkHandler(),
+	)
+	assert.NoError(t, err)
+	router.Errors = &ErrorConfig{Status: ErrorCodeStatus}
+
+	t.Run("network list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(
+			http.MethodPost,
+			"/network/list",
+			bytes.NewBufferString("{}"),
+		))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response types.NetworkListResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []*types.NetworkIdentifier{mainnet, testnet, shard}, response.NetworkIdentifiers)
+	})
+
+	for _, test := range []struct {
+		network *types.NetworkIdentifier
+		hash    string
+	}{
+		{mainnet, "mainnet"},
+		{testnet, "testnet"},
+		{shard, "shard"},
+	} {
+		t.Run(test.hash, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(
+				http.MethodPost,
+				"/network/status",
+				bytes.NewBufferString(types.PrintStruct(&types.NetworkRequest{
+					NetworkIdentifier: test.network,
+				})),
+			))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response types.NetworkStatusResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, test.hash, response.CurrentBlockIdentifier.Hash)
+		})
+	}
+
+	var invalidTests = map[string]string{
+		"unsupported network": types.PrintStruct(&types.NetworkRequest{
+			NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Ethereum", Network: "Mainnet"},
+		}),
+		"missing network": "{}",
+		"malformed":       "{",
+		"empty":           "",
+	}
+
+	t.Run("default error status", func(t *testing.T) {
+		defaultRouter, err := NewNetworkRouter(networkServicers(t, mainnet, "mainnet").NetworkHandler())
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		defaultRouter.ServeHTTP(w, httptest.NewRequest(
+			http.MethodPost,
+			"/network/status",
+			bytes.NewBufferString("{}"),
+		))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	for name, body := range invalidTests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(
+				http.MethodPost,
+				"/network/status",
+				bytes.NewBufferString(body),
+			))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var rosettaErr types.Error
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+			assert.Equal(t, ErrInvalidRequest.Code, rosettaErr.Code)
+		})
+	}
+
+	t.Run("query parameters", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			EncodeJSONResponse(map[string]string{}, http.StatusOK, w)
+		})
+		router, err := NewNetworkRouter(&NetworkHandler{Network: shard, Handler: handler})
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(
+			http.MethodGet,
+			EventsStreamPath+"?blockchain=Bitcoin&network=Mainnet&sub_network=shard+1",
+			nil,
+		))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestNewNetworkRouterInvalid(t *testing.T) {
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Mainnet"}
+	handler := http.NotFoundHandler()
+
+	_, err := NewNetworkRouter(&NetworkHandler{Network: network})
+	assert.True(t, errors.Is(err, ErrNetworkHandlerInvalid))
+
+	_, err = NewNetworkRouter(
+		&NetworkHandler{Network: network, Handler: handler},
+		&NetworkHandler{
+			Network: &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Mainnet"},
+			Handler: handler,
+		},
+	)
+	assert.True(t, errors.Is(err, ErrNetworkDuplicate))
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	_ "embed" // required to embed the OpenAPI document
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// OpenAPIPath is the path of the OpenAPI
+	// document extension endpoint.
+	OpenAPIPath = "/openapi.json"
+
+	// VersionHeader is the header a client declares the
+	// version of the Rosetta API it supports with. Responses
+	// from VersionMiddleware include the version of the server
+	// in the same header.
+	VersionHeader = "Rosetta-Version"
+)
+
+// ErrIncompatibleVersion is returned when a client declares
+// a version of the Rosetta API that is not compatible with
+// the version of the server.
+var ErrIncompatibleVersion = &types.Error{
+	Code:    http.StatusBadRequest,
+	Message: "incompatible Rosetta API version",
+}
+
+// openAPIDocument is the Rosetta OpenAPI document (version
+// types.RosettaAPIVersion) the SDK was generated from.
+//
+//go:embed api.json
+var openAPIDocument []byte
+
+// OpenAPIDocument returns the Rosetta OpenAPI document
+// (version types.RosettaAPIVersion) the SDK was
+// generated from.
+func OpenAPIDocument() []byte {
+	document := make([]byte, len(openAPIDocument))
+	copy(document, openAPIDocument)
+
+	return document
+}
+
+// OpenAPIController serves the Rosetta OpenAPI document.
+// It is an extension of the Rosetta API, so it must be
+// explicitly added to a router with NewRouter.
+type OpenAPIController struct{}
+
+// NewOpenAPIController creates a new OpenAPIController.
+func NewOpenAPIController() Router {
+	return &OpenAPIController{}
+}
+
+// Routes returns all of the api route for the OpenAPIController
+func (c *OpenAPIController) Routes() Routes {
+	return Routes{
+		{
+			"OpenAPI",
+			http.MethodGet,
+			OpenAPIPath,
+			c.OpenAPI,
+		},
+	}
+}
+
+// OpenAPI - [EXTENSION] Get the Rosetta OpenAPI Document
+func (c *OpenAPIController) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPIDocument) // nolint:errcheck
+}
+
+// VersionCompatibleFunc returns true if a client
+// supporting clientVersion can be served by a
+// server supporting serverVersion.
+type VersionCompatibleFunc func(clientVersion string, serverVersion string) (bool, error)
+
+// parseVersion returns the major, minor, and patch
+// numbers of a semantic version (ex: "1.4.10").
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) != len(parsed) {
+		return parsed, fmt.Errorf("%s is not a semantic version", version)
+	}
+
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return parsed, fmt.Errorf("%s is not a semantic version", version)
+		}
+
+		parsed[i] = number
+	}
+
+	return parsed, nil
+}
+
+// SameMinorVersion is the default VersionCompatibleFunc.
+// Versions are compatible if they have the same major
+// and minor version (patch versions of the Rosetta API
+// are backwards compatible).
+func SameMinorVersion(clientVersion string, serverVersion string) (bool, error) {
+	client, err := parseVersion(clientVersion)
+	if err != nil {
+		return false, err
+	}
+
+	server, err := parseVersion(serverVersion)
+	if err != nil {
+		return false, err
+	}
+
+	return client[0] == server[0] && client[1] == server[1], nil
+}
+
+// VersionConfig configures VersionMiddleware.
+type VersionConfig struct {
+	// Version is the version of the Rosetta API the server
+	// supports. If empty, types.RosettaAPIVersion is used.
+	Version string
+
+	// Compatible determines if the version declared by a
+	// client is compatible. If nil, SameMinorVersion is
+	// used.
+	Compatible VersionCompatibleFunc
+
+	// Errors configures ErrIncompatibleVersion
+	// (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// VersionMiddleware checks the version of the Rosetta API declared
+// by a client in the VersionHeader (if present) and responds with
+// ErrIncompatibleVersion if it is not compatible with the version
+// of the server. All responses include the version of the server
+// in the VersionHeader.
+func VersionMiddleware(config *VersionConfig) Middleware {
+	version := config.Version
+	if len(version) == 0 {
+		version = types.RosettaAPIVersion
+	}
+
+	compatible := config.Compatible
+	if compatible == nil {
+		compatible = SameMinorVersion
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(VersionHeader, version)
+
+			clientVersion := r.Header.Get(VersionHeader)
+			if len(clientVersion) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ok, err := compatible(clientVersion, version)
+			if err != nil {
+				config.Errors.encode(ErrIncompatibleVersion, err.Error(), w)
+				return
+			}
+
+			if !ok {
+				config.Errors.encode(
+					ErrIncompatibleVersion,
+					fmt.Sprintf(
+						"client version %s is not compatible with server version %s",
+						clientVersion,
+						version,
+					),
+					w,
+				)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestOpenAPIController(t *testing.T) {
+	router := NewRouter(NewOpenAPIController())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var document struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]interface{} `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+
+	// The embedded document must match the
+	// version the types were generated from.
+	assert.Equal(t, types.RosettaAPIVersion, document.Info.Version)
+	for path := range routeValidators {
+		assert.Contains(t, document.Paths, path)
+	}
+
+	assert.Equal(t, w.Body.Bytes(), OpenAPIDocument())
+}
+
+func TestSameMinorVersion(t *testing.T) {
+	var tests = map[string]struct {
+		client string
+		server string
+
+		compatible bool
+		err        bool
+	}{
+		"same version":     {client: "1.4.10", server: "1.4.10", compatible: true},
+		"older patch":      {client: "1.4.2", server: "1.4.10", compatible: true},
+		"prefixed version": {client: "v1.4.12", server: "1.4.10", compatible: true},
+		"different minor":  {client: "1.5.0", server: "1.4.10"},
+		"different major":  {client: "2.4.10", server: "1.4.10"},
+		"invalid version":  {client: "1.4", server: "1.4.10", err: true},
+		"non-numeric":      {client: "1.4.x", server: "1.4.10", err: true},
+		"invalid server":   {client: "1.4.10", server: "latest", err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			compatible, err := SameMinorVersion(test.client, test.server)
+			assert.Equal(t, test.compatible, compatible)
+			assert.Equal(t, test.err, err != nil)
+		})
+	}
+}
+
+func TestVersionMiddleware(t *testing.T) {
+	var tests = map[string]struct {
+		config        *VersionConfig
+		clientVersion string
+
+		status int
+	}{
+		"no version": {
+			config: &VersionConfig{},
+			status: http.StatusOK,
+		},
+		"compatible": {
+			config:        &VersionConfig{},
+			clientVersion: "1.4.1",
+			status:        http.StatusOK,
+		},
+		"incompatible": {
+			config:        &VersionConfig{},
+			clientVersion: "1.3.0",
+			status:        http.StatusInternalServerError,
+		},
+		"incompatible with error status": {
+			config: &VersionConfig{
+				Errors: &ErrorConfig{Status: ErrorCodeStatus},
+			},
+			clientVersion: "1.3.0",
+			status:        http.StatusBadRequest,
+		},
+		"invalid": {
+			config: &VersionConfig{
+				Errors: &ErrorConfig{Status: ErrorCodeStatus},
+			},
+			clientVersion: "latest",
+			status:        http.StatusBadRequest,
+		},
+		"custom compatibility": {
+			config: &VersionConfig{
+				Version: "1.5.0",
+				Compatible: func(clientVersion string, serverVersion string) (bool, error) {
+					return clientVersion == "1.3.0", nil
+				},
+			},
+			clientVersion: "1.3.0",
+			status:        http.StatusOK,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := Chain(identityHandler(), VersionMiddleware(test.config))
+			req := httptest.NewRequest(http.MethodPost, "/network/list", nil)
+			if len(test.clientVersion) > 0 {
+				req.Header.Set(VersionHeader, test.clientVersion)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, test.status, w.Code)
+
+			version := test.config.Version
+			if len(version) == 0 {
+				version = types.RosettaAPIVersion
+			}
+			assert.Equal(t, version, w.Header().Get(VersionHeader))
+
+			if test.status != http.StatusOK {
+				rosettaErr := &types.Error{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rosettaErr))
+				assert.Equal(t, ErrIncompatibleVersion.Code, rosettaErr.Code)
+				assert.NotNil(t, rosettaErr.Description)
+			}
+		})
+	}
+}
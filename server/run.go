@@ -0,0 +1,255 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultAddr is the address Run
+	// listens on by default.
+	DefaultAddr = ":8080"
+
+	// DefaultDrainTimeout is the maximum time Run waits
+	// for in-flight requests to finish on shutdown.
+	DefaultDrainTimeout = 30 * time.Second
+
+	// DefaultReadHeaderTimeout is the maximum time
+	// to read the headers of a request.
+	DefaultReadHeaderTimeout = 10 * time.Second
+
+	// DefaultIdleTimeout is the maximum time an idle
+	// keep-alive connection is kept open.
+	DefaultIdleTimeout = 2 * time.Minute
+)
+
+// ErrDrainTimeout is returned by Run when in-flight
+// requests do not finish before the drain timeout
+// (the remaining connections are closed).
+var ErrDrainTimeout = errors.New("connections not drained before timeout")
+
+// runConfig configures Run.
+type runConfig struct {
+	addr     string
+	listener net.Listener
+
+	drainTimeout      time.Duration
+	readHeaderTimeout time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	keepAlives        bool
+
+	tlsConfig *tls.Config
+	certFile  string
+	keyFile   string
+
+	signals []os.Signal
+}
+
+// RunOption is used to overwrite default values in
+// Run. Any RunOption not provided falls back to the
+// default value.
+type RunOption func(c *runConfig)
+
+// WithAddr overrides the default
+// address to listen on.
+func WithAddr(addr string) RunOption {
+	return func(c *runConfig) {
+		c.addr = addr
+	}
+}
+
+// WithListener serves on listener instead
+// of listening on an address.
+func WithListener(listener net.Listener) RunOption {
+	return func(c *runConfig) {
+		c.listener = listener
+	}
+}
+
+// WithDrainTimeout overrides the default maximum time
+// to wait for in-flight requests to finish on shutdown.
+func WithDrainTimeout(timeout time.Duration) RunOption {
+	return func(c *runConfig) {
+		c.drainTimeout = timeout
+	}
+}
+
+// WithReadHeaderTimeout overrides the default maximum
+// time to read the headers of a request.
+func WithReadHeaderTimeout(timeout time.Duration) RunOption {
+	return func(c *runConfig) {
+		c.readHeaderTimeout = timeout
+	}
+}
+
+// WithWriteTimeout sets the maximum time to write a
+// response (by default, there is no limit so that
+// responses can be streamed).
+func WithWriteTimeout(timeout time.Duration) RunOption {
+	return func(c *runConfig) {
+		c.writeTimeout = timeout
+	}
+}
+
+// WithIdleTimeout overrides the default maximum time
+// an idle keep-alive connection is kept open.
+func WithIdleTimeout(timeout time.Duration) RunOption {
+	return func(c *runConfig) {
+		c.idleTimeout = timeout
+	}
+}
+
+// WithoutKeepAlives closes each connection
+// after a single request.
+func WithoutKeepAlives() RunOption {
+	return func(c *runConfig) {
+		c.keepAlives = false
+	}
+}
+
+// WithTLS serves HTTPS with the certificate and key in
+// certFile and keyFile (which are loaded on startup).
+func WithTLS(certFile string, keyFile string) RunOption {
+	return func(c *runConfig) {
+		c.certFile = certFile
+		c.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig serves HTTPS with config (ex: to
+// require client certificates). Certificates in
+// config are used if WithTLS is not provided.
+func WithTLSConfig(config *tls.Config) RunOption {
+	return func(c *runConfig) {
+		c.tlsConfig = config
+	}
+}
+
+// WithSignals overrides the default signals (SIGINT and
+// SIGTERM) that trigger a graceful shutdown.
+func WithSignals(signals ...os.Signal) RunOption {
+	return func(c *runConfig) {
+		c.signals = signals
+	}
+}
+
+// Run serves handler (usually created with NewRouter and
+// wrapped with any Middleware) until ctx is done or a
+// shutdown signal is received. On shutdown, Run stops
+// accepting connections, closes idle keep-alive
+// connections, and waits for in-flight requests to finish
+// (up to the drain timeout). The context of each request is
+// cancelled when shutdown starts, so that requests that never
+// finish on their own (ex: the events stream) end instead of
+// holding up shutdown.
+//
+// Run returns nil after a graceful shutdown, ErrDrainTimeout
+// if requests did not finish in time, or any error that
+// prevented the server from starting.
+func Run(ctx context.Context, handler http.Handler, options ...RunOption) error {
+	config := &runConfig{
+		addr:              DefaultAddr,
+		drainTimeout:      DefaultDrainTimeout,
+		readHeaderTimeout: DefaultReadHeaderTimeout,
+		idleTimeout:       DefaultIdleTimeout,
+		keepAlives:        true,
+		signals:           []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+
+	for _, opt := range options {
+		opt(config)
+	}
+
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
+	srv := &http.Server{
+		Addr:              config.addr,
+		Handler:           handler,
+		ReadHeaderTimeout: config.readHeaderTimeout,
+		WriteTimeout:      config.writeTimeout,
+		IdleTimeout:       config.idleTimeout,
+		TLSConfig:         config.tlsConfig,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	srv.SetKeepAlivesEnabled(config.keepAlives)
+	srv.RegisterOnShutdown(cancelBase)
+
+	listener := config.listener
+	if listener == nil {
+		var err error
+		listener, err = net.Listen("tcp", config.addr)
+		if err != nil {
+			return fmt.Errorf("unable to listen on %s: %w", config.addr, err)
+		}
+	}
+
+	if len(config.signals) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, config.signals...)
+		defer stop()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if config.tlsConfig != nil || len(config.certFile) > 0 {
+			serveErr <- srv.ServeTLS(listener, config.certFile, config.keyFile)
+			return
+		}
+
+		serveErr <- srv.Serve(listener)
+	}()
+
+	log.Printf("Listening on %s\n", listener.Addr())
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("unable to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down (waiting up to %s for requests to finish)\n", config.drainTimeout)
+
+	// Shutdown does not use ctx because
+	// it is already done.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.drainTimeout)
+	defer cancel()
+
+	srv.SetKeepAlivesEnabled(false)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close() // nolint:errcheck
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrDrainTimeout
+		}
+
+		return fmt.Errorf("unable to shutdown: %w", err)
+	}
+
+	return nil
+}
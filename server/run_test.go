@@ -0,0 +1,157 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowHandler signals started when a request
+// arrives and responds after delay.
+func slowHandler(started chan struct{}, delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(delay)
+		EncodeJSONResponse(map[string]string{"status": "done"}, http.StatusOK, w)
+	})
+}
+
+func TestRun(t *testing.T) {
+	var tests = map[string]struct {
+		delay        time.Duration
+		drainTimeout time.Duration
+
+		err          error
+		responseDone bool
+	}{
+		"drained": {
+			delay:        100 * time.Millisecond,
+			drainTimeout: 5 * time.Second,
+			responseDone: true,
+		},
+		"drain timeout": {
+			delay:        5 * time.Second,
+			drainTimeout: 50 * time.Millisecond,
+			err:          ErrDrainTimeout,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+
+			started := make(chan struct{})
+			ctx, cancel := context.WithCancel(context.Background())
+			runErr := make(chan error, 1)
+			go func() {
+				runErr <- Run(
+					ctx,
+					slowHandler(started, test.delay),
+					WithListener(listener),
+					WithDrainTimeout(test.drainTimeout),
+					WithSignals(),
+				)
+			}()
+
+			responseDone := make(chan bool, 1)
+			go func() {
+				resp, err := http.Get("http://" + listener.Addr().String())
+				if err != nil {
+					responseDone <- false
+					return
+				}
+				defer resp.Body.Close()
+
+				_, err = ioutil.ReadAll(resp.Body)
+				responseDone <- err == nil && resp.StatusCode == http.StatusOK
+			}()
+
+			// Shutdown while the request
+			// is in-flight.
+			<-started
+			cancel()
+
+			assert.Equal(t, test.err, <-runErr)
+			assert.Equal(t, test.responseDone, <-responseDone)
+
+			// No new connections are accepted.
+			_, err = http.Get("http://" + listener.Addr().String())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRunListenError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	err = Run(
+		context.Background(),
+		http.NotFoundHandler(),
+		WithAddr(listener.Addr().String()),
+		WithSignals(),
+	)
+	assert.Error(t, err)
+}
+
+func TestRunStream(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	// The handler streams until its
+	// context is cancelled.
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		close(started)
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- Run(
+			ctx,
+			handler,
+			WithListener(listener),
+			WithDrainTimeout(5*time.Second),
+			WithSignals(),
+		)
+	}()
+
+	resp, err := http.Get("http://" + listener.Addr().String())
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-runErr:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end on shutdown")
+	}
+}
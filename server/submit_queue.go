@@ -0,0 +1,478 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// SubmissionStatusPath is the path of the submission
+	// status extension endpoint.
+	SubmissionStatusPath = "/construction/submit/status"
+
+	// DefaultSubmitWorkers is the default number of
+	// transactions submitted at once.
+	DefaultSubmitWorkers = 4
+
+	// DefaultSubmitQueueSize is the default maximum
+	// number of queued transactions.
+	DefaultSubmitQueueSize = 1024
+
+	// DefaultSubmitMaxAttempts is the default maximum
+	// number of times a transaction is submitted.
+	DefaultSubmitMaxAttempts = 5
+
+	// DefaultSubmitRetryInterval is the default time to
+	// wait before resubmitting a transaction.
+	DefaultSubmitRetryInterval = 5 * time.Second
+
+	// DefaultSubmissionRetention is the default time the
+	// status of a finished submission is kept.
+	DefaultSubmissionRetention = 1 * time.Hour
+
+	// SubmissionStateMetadataKey is set in the metadata of
+	// the response to a queued /construction/submit request.
+	SubmissionStateMetadataKey = "submission_state"
+
+	// submissionSweepInterval is how often expired
+	// submissions are removed.
+	submissionSweepInterval = 1 * time.Minute
+)
+
+var (
+	// ErrSubmissionNotFound is returned when the status of
+	// a transaction that was not queued is requested.
+	ErrSubmissionNotFound = &types.Error{
+		Code:    http.StatusNotFound,
+		Message: "submission not found",
+	}
+
+	// ErrSubmitQueueFull is returned when a transaction
+	// is submitted while the queue is full.
+	ErrSubmitQueueFull = &types.Error{
+		Code:      http.StatusServiceUnavailable,
+		Message:   "submit queue is full",
+		Retriable: true,
+	}
+)
+
+// SubmissionState is the state of a queued transaction.
+type SubmissionState string
+
+// States of a queued transaction.
+const (
+	SubmissionQueued    SubmissionState = "queued"
+	SubmissionSubmitted SubmissionState = "submitted"
+	SubmissionFailed    SubmissionState = "failed"
+)
+
+// SubmissionStatusRequest is the request to the
+// submission status extension endpoint.
+type SubmissionStatusRequest struct {
+	NetworkIdentifier     *types.NetworkIdentifier     `json:"network_identifier"`
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+}
+
+// SubmissionStatus is the status of a queued transaction.
+// Error is the error returned by the last attempt to
+// submit the transaction (if any) and Metadata is the
+// metadata returned when it was submitted.
+type SubmissionStatus struct {
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+	State                 SubmissionState              `json:"state"`
+	Attempts              int                          `json:"attempts"`
+	Error                 *types.Error                 `json:"error,omitempty"`
+	Metadata              map[string]interface{}       `json:"metadata,omitempty"`
+}
+
+// SubmissionQueue queues signed transactions that are
+// submitted to a node asynchronously. Implementations
+// can be backed by any durable queue.
+type SubmissionQueue interface {
+	// Enqueue queues request (which has the transaction
+	// identified by transactionIdentifier). It must not
+	// wait for the transaction to be submitted.
+	Enqueue(
+		ctx context.Context,
+		request *types.ConstructionSubmitRequest,
+		transactionIdentifier *types.TransactionIdentifier,
+	) *types.Error
+
+	// Status returns the status of a queued transaction
+	// (or ErrSubmissionNotFound).
+	Status(ctx context.Context, request *SubmissionStatusRequest) (*SubmissionStatus, *types.Error)
+}
+
+// asyncSubmitServicer queues /construction/submit
+// requests instead of submitting them.
+type asyncSubmitServicer struct {
+	ConstructionAPIServicer
+
+	queue SubmissionQueue
+}
+
+// NewAsyncSubmitServicer returns a ConstructionAPIServicer that
+// serves all requests with s except /construction/submit. Signed
+// transactions are hashed (with s.ConstructionHash) and added to
+// queue, and the response is returned immediately with the
+// SubmissionStateMetadataKey set to SubmissionQueued. Callers poll
+// the SubmissionStatusController for the result of the submission.
+func NewAsyncSubmitServicer(
+	s ConstructionAPIServicer,
+	queue SubmissionQueue,
+) ConstructionAPIServicer {
+	return &asyncSubmitServicer{
+		ConstructionAPIServicer: s,
+		queue:                   queue,
+	}
+}
+
+// ConstructionSubmit queues the signed transaction in request.
+func (a *asyncSubmitServicer) ConstructionSubmit(
+	ctx context.Context,
+	request *types.ConstructionSubmitRequest,
+) (*types.TransactionIdentifierResponse, *types.Error) {
+	hash, err := a.ConstructionHash(ctx, &types.ConstructionHashRequest{
+		NetworkIdentifier: request.NetworkIdentifier,
+		SignedTransaction: request.SignedTransaction,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.queue.Enqueue(ctx, request, hash.TransactionIdentifier); err != nil {
+		return nil, err
+	}
+
+	return &types.TransactionIdentifierResponse{
+		TransactionIdentifier: hash.TransactionIdentifier,
+		Metadata: map[string]interface{}{
+			SubmissionStateMetadataKey: SubmissionQueued,
+		},
+	}, nil
+}
+
+// SubmitFunc submits a signed transaction to a node
+// (usually the ConstructionSubmit method of the
+// ConstructionAPIServicer that is not queued).
+type SubmitFunc func(
+	context.Context,
+	*types.ConstructionSubmitRequest,
+) (*types.TransactionIdentifierResponse, *types.Error)
+
+// SubmitQueueConfig configures a MemorySubmissionQueue.
+// Zero values use the defaults.
+type SubmitQueueConfig struct {
+	Workers       int
+	Size          int
+	MaxAttempts   int
+	RetryInterval time.Duration
+
+	// Retention is how long the status of a submitted
+	// (or failed) transaction is kept.
+	Retention time.Duration
+
+	// Errors replaces ErrSubmitQueueFull (see ErrorConfig).
+	// Its status is set by the controller that serves
+	// /construction/submit (see WithErrorStatus).
+	Errors *ErrorConfig
+}
+
+// submission is a transaction in a
+// MemorySubmissionQueue.
+type submission struct {
+	request  *types.ConstructionSubmitRequest
+	status   *SubmissionStatus
+	finished time.Time
+}
+
+// submissionKey returns the key of the transaction
+// identified by hash on network. Transactions are
+// keyed by network because the same hash can be
+// submitted to multiple networks.
+func submissionKey(network *types.NetworkIdentifier, hash string) string {
+	if network == nil {
+		return "\x00" + hash
+	}
+
+	key := network.Blockchain + "\x00" + network.Network
+	if network.SubNetworkIdentifier != nil {
+		key += "\x00" + network.SubNetworkIdentifier.Network
+	}
+
+	return key + "\x00" + hash
+}
+
+// MemorySubmissionQueue is an in-memory SubmissionQueue.
+// Transactions are submitted by workers (started with
+// Start) and queued again (after the RetryInterval)
+// while submission fails with a retriable error. Queued transactions are lost on
+// restart, so implementations that need durability should
+// provide their own SubmissionQueue.
+type MemorySubmissionQueue struct {
+	submit SubmitFunc
+	config SubmitQueueConfig
+	now    func() time.Time
+
+	queue chan *submission
+
+	mutex       sync.Mutex
+	submissions map[string]*submission
+	swept       time.Time
+}
+
+// NewMemorySubmissionQueue creates a new
+// *MemorySubmissionQueue that submits
+// transactions with submit.
+func NewMemorySubmissionQueue(
+	submit SubmitFunc,
+	config *SubmitQueueConfig,
+) *MemorySubmissionQueue {
+	q := &MemorySubmissionQueue{
+		submit:      submit,
+		now:         time.Now,
+		submissions: map[string]*submission{},
+	}
+
+	if config != nil {
+		q.config = *config
+	}
+
+	if q.config.Workers <= 0 {
+		q.config.Workers = DefaultSubmitWorkers
+	}
+
+	if q.config.Size <= 0 {
+		q.config.Size = DefaultSubmitQueueSize
+	}
+
+	if q.config.MaxAttempts <= 0 {
+		q.config.MaxAttempts = DefaultSubmitMaxAttempts
+	}
+
+	if q.config.RetryInterval <= 0 {
+		q.config.RetryInterval = DefaultSubmitRetryInterval
+	}
+
+	if q.config.Retention <= 0 {
+		q.config.Retention = DefaultSubmissionRetention
+	}
+
+	q.queue = make(chan *submission, q.config.Size)
+	q.swept = q.now()
+
+	return q
+}
+
+// Start starts the workers that submit queued
+// transactions. Workers stop when ctx is done.
+func (q *MemorySubmissionQueue) Start(ctx context.Context) {
+	for i := 0; i < q.config.Workers; i++ {
+		go q.work(ctx)
+	}
+}
+
+// Enqueue implements SubmissionQueue. Transactions that are
+// already queued (or submitted) are not queued again, so
+// clients can safely retry /construction/submit.
+func (q *MemorySubmissionQueue) Enqueue(
+	ctx context.Context,
+	request *types.ConstructionSubmitRequest,
+	transactionIdentifier *types.TransactionIdentifier,
+) *types.Error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := q.now()
+	q.sweep(now)
+
+	key := submissionKey(request.NetworkIdentifier, transactionIdentifier.Hash)
+	if existing, ok := q.submissions[key]; ok &&
+		existing.status.State != SubmissionFailed {
+		return nil
+	}
+
+	s := &submission{
+		request: request,
+		status: &SubmissionStatus{
+			TransactionIdentifier: transactionIdentifier,
+			State:                 SubmissionQueued,
+		},
+	}
+
+	select {
+	case q.queue <- s:
+	default:
+		return q.config.Errors.error(ErrSubmitQueueFull)
+	}
+
+	q.submissions[key] = s
+	return nil
+}
+
+// Status implements SubmissionQueue.
+func (q *MemorySubmissionQueue) Status(
+	ctx context.Context,
+	request *SubmissionStatusRequest,
+) (*SubmissionStatus, *types.Error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	s, ok := q.submissions[submissionKey(
+		request.NetworkIdentifier,
+		request.TransactionIdentifier.Hash,
+	)]
+	if !ok {
+		return nil, ErrSubmissionNotFound
+	}
+
+	status := *s.status
+	return &status, nil
+}
+
+// sweep removes finished submissions that are
+// older than the retention.
+func (q *MemorySubmissionQueue) sweep(now time.Time) {
+	if now.Sub(q.swept) < submissionSweepInterval {
+		return
+	}
+
+	for key, s := range q.submissions {
+		if !s.finished.IsZero() && now.Sub(s.finished) >= q.config.Retention {
+			delete(q.submissions, key)
+		}
+	}
+
+	q.swept = now
+}
+
+// work submits queued transactions
+// until ctx is done.
+func (q *MemorySubmissionQueue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-q.queue:
+			q.process(ctx, s)
+		}
+	}
+}
+
+// process submits s. If submission fails with a retriable
+// error (and s has attempts left), s is queued again after
+// the RetryInterval (so workers never wait between attempts).
+func (q *MemorySubmissionQueue) process(ctx context.Context, s *submission) {
+	response, err := q.submit(ctx, s.request)
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	status := *s.status
+	status.Attempts++
+	status.Error = err
+	s.status = &status
+
+	switch {
+	case err == nil:
+		status.State = SubmissionSubmitted
+		status.Metadata = response.Metadata
+	case err.Retriable && status.Attempts < q.config.MaxAttempts:
+		time.AfterFunc(q.config.RetryInterval, func() {
+			select {
+			case <-ctx.Done():
+			case q.queue <- s:
+			}
+		})
+		return
+	default:
+		status.State = SubmissionFailed
+	}
+
+	s.finished = q.now()
+}
+
+// SubmissionStatusController serves the status of
+// transactions queued by NewAsyncSubmitServicer. It is
+// an extension of the Rosetta API, so it must be
+// explicitly added to a router with NewRouter.
+type SubmissionStatusController struct {
+	queue    SubmissionQueue
+	asserter *asserter.Asserter
+	errors   *ErrorConfig
+}
+
+// NewSubmissionStatusController creates a new
+// SubmissionStatusController backed by queue. Errors
+// (ErrInvalidRequest and the errors returned by queue)
+// are configured with errors (see ErrorConfig).
+func NewSubmissionStatusController(
+	queue SubmissionQueue,
+	asserter *asserter.Asserter,
+	errors *ErrorConfig,
+) Router {
+	return &SubmissionStatusController{
+		queue:    queue,
+		asserter: asserter,
+		errors:   errors,
+	}
+}
+
+// Routes returns all of the api route for the SubmissionStatusController
+func (c *SubmissionStatusController) Routes() Routes {
+	return Routes{
+		{
+			"SubmissionStatus",
+			http.MethodPost,
+			SubmissionStatusPath,
+			c.SubmissionStatus,
+		},
+	}
+}
+
+// SubmissionStatus - [EXTENSION] Get the Status of a Queued Transaction
+func (c *SubmissionStatusController) SubmissionStatus(w http.ResponseWriter, r *http.Request) {
+	request := &SubmissionStatusRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		c.errors.encode(ErrInvalidRequest, err.Error(), w)
+		return
+	}
+
+	if err := c.asserter.ValidSupportedNetwork(request.NetworkIdentifier); err != nil {
+		c.errors.encode(ErrInvalidRequest, err.Error(), w)
+		return
+	}
+
+	if err := asserter.TransactionIdentifier(request.TransactionIdentifier); err != nil {
+		c.errors.encode(ErrInvalidRequest, err.Error(), w)
+		return
+	}
+
+	status, err := c.queue.Status(r.Context(), request)
+	if err != nil {
+		c.errors.encode(err, "", w)
+		return
+	}
+
+	EncodeJSONResponse(status, http.StatusOK, w)
+}
@@ -0,0 +1,316 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// submitServicer hashes transactions and fails
+// to submit them until failures is 0.
+type submitServicer struct {
+	ConstructionAPIServicer
+
+	mutex     sync.Mutex
+	failures  int
+	err       *types.Error
+	submitted []string
+}
+
+func (s *submitServicer) ConstructionHash(
+	ctx context.Context,
+	request *types.ConstructionHashRequest,
+) (*types.TransactionIdentifierResponse, *types.Error) {
+	return &types.TransactionIdentifierResponse{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "hash " + request.SignedTransaction},
+	}, nil
+}
+
+func (s *submitServicer) ConstructionSubmit(
+	ctx context.Context,
+	request *types.ConstructionSubmitRequest,
+) (*types.TransactionIdentifierResponse, *types.Error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return nil, s.err
+	}
+
+	s.submitted = append(s.submitted, request.SignedTransaction)
+	return &types.TransactionIdentifierResponse{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "hash " + request.SignedTransaction},
+		Metadata:              map[string]interface{}{"fee": "1"},
+	}, nil
+}
+
+func submitQueueServer(
+	t *testing.T,
+	servicer *submitServicer,
+	config *SubmitQueueConfig,
+) (*httptest.Server, context.CancelFunc) {
+	a, err := asserter.NewServer(
+		[]string{"TRANSFER"},
+		false,
+		[]*types.NetworkIdentifier{validationNetwork},
+		nil,
+		false,
+		"",
+	)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := NewMemorySubmissionQueue(servicer.ConstructionSubmit, config)
+	queue.Start(ctx)
+
+	return httptest.NewServer(NewRouter(
+		NewConstructionAPIController(NewAsyncSubmitServicer(servicer, queue), a),
+		NewSubmissionStatusController(queue, a, &ErrorConfig{Status: ErrorCodeStatus}),
+	)), cancel
+}
+
+func post(t *testing.T, url string, request interface{}, response interface{}) int {
+	body, err := json.Marshal(request)
+	assert.NoError(t, err)
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	return resp.StatusCode
+}
+
+func waitForState(
+	t *testing.T,
+	url string,
+	hash string,
+	state SubmissionState,
+) *SubmissionStatus {
+	var status *SubmissionStatus
+	assert.Eventually(t, func() bool {
+		status = &SubmissionStatus{}
+		post(t, url+SubmissionStatusPath, &SubmissionStatusRequest{
+			NetworkIdentifier:     validationNetwork,
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: hash},
+		}, status)
+
+		return status.State == state
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return status
+}
+
+func TestAsyncSubmit(t *testing.T) {
+	var tests = map[string]struct {
+		failures int
+		err      *types.Error
+
+		state    SubmissionState
+		attempts int
+		metadata map[string]interface{}
+		status   *types.Error
+	}{
+		"submitted": {
+			state:    SubmissionSubmitted,
+			attempts: 1,
+			metadata: map[string]interface{}{"fee": "1"},
+		},
+		"submitted after retries": {
+			failures: 2,
+			err:      &types.Error{Code: 1, Message: "node unavailable", Retriable: true},
+			state:    SubmissionSubmitted,
+			attempts: 3,
+			metadata: map[string]interface{}{"fee": "1"},
+		},
+		"out of attempts": {
+			failures: 5,
+			err:      &types.Error{Code: 1, Message: "node unavailable", Retriable: true},
+			state:    SubmissionFailed,
+			attempts: 3,
+			status:   &types.Error{Code: 1, Message: "node unavailable", Retriable: true},
+		},
+		"not retriable": {
+			failures: 1,
+			err:      &types.Error{Code: 2, Message: "invalid transaction"},
+			state:    SubmissionFailed,
+			attempts: 1,
+			status:   &types.Error{Code: 2, Message: "invalid transaction"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			servicer := &submitServicer{failures: test.failures, err: test.err}
+			s, cancel := submitQueueServer(t, servicer, &SubmitQueueConfig{
+				MaxAttempts:   3,
+				RetryInterval: time.Millisecond,
+			})
+			defer s.Close()
+			defer cancel()
+
+			response := &types.TransactionIdentifierResponse{}
+			code := post(t, s.URL+"/construction/submit", &types.ConstructionSubmitRequest{
+				NetworkIdentifier: validationNetwork,
+				SignedTransaction: "tx",
+			}, response)
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "hash tx", response.TransactionIdentifier.Hash)
+			assert.Equal(t, string(SubmissionQueued), response.Metadata[SubmissionStateMetadataKey])
+
+			status := waitForState(t, s.URL, "hash tx", test.state)
+			assert.Equal(t, test.attempts, status.Attempts)
+			assert.Equal(t, test.metadata, status.Metadata)
+			assert.Equal(t, test.status, status.Error)
+		})
+	}
+}
+
+func TestSubmissionStatusNotFound(t *testing.T) {
+	s, cancel := submitQueueServer(t, &submitServicer{}, nil)
+	defer s.Close()
+	defer cancel()
+
+	rosettaErr := &types.Error{}
+	code := post(t, s.URL+SubmissionStatusPath, &SubmissionStatusRequest{
+		NetworkIdentifier:     validationNetwork,
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "missing"},
+	}, rosettaErr)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, ErrSubmissionNotFound, rosettaErr)
+
+	code = post(t, s.URL+SubmissionStatusPath, &SubmissionStatusRequest{
+		NetworkIdentifier: validationNetwork,
+	}, rosettaErr)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, ErrInvalidRequest.Code, rosettaErr.Code)
+}
+
+func TestMemorySubmissionQueue(t *testing.T) {
+	queue := NewMemorySubmissionQueue(nil, &SubmitQueueConfig{
+		Size:      1,
+		Retention: time.Hour,
+	})
+	now := time.Now()
+	queue.now = func() time.Time { return now }
+	queue.swept = now
+
+	ctx := context.Background()
+	request := &types.ConstructionSubmitRequest{SignedTransaction: "tx"}
+	assert.Nil(t, queue.Enqueue(ctx, request, &types.TransactionIdentifier{Hash: "a"}))
+
+	// Queued transactions are not queued again.
+	assert.Nil(t, queue.Enqueue(ctx, request, &types.TransactionIdentifier{Hash: "a"}))
+	assert.Equal(t, ErrSubmitQueueFull, queue.Enqueue(
+		ctx,
+		request,
+		&types.TransactionIdentifier{Hash: "b"},
+	))
+
+	// Finished submissions are removed
+	// after the retention.
+	queue.submissions[submissionKey(nil, "a")].finished = now
+	now = now.Add(time.Hour)
+	assert.Equal(t, ErrSubmitQueueFull, queue.Enqueue(
+		ctx,
+		request,
+		&types.TransactionIdentifier{Hash: "b"},
+	))
+
+	_, err := queue.Status(ctx, &SubmissionStatusRequest{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "a"},
+	})
+	assert.Equal(t, ErrSubmissionNotFound, err)
+}
+
+func TestMemorySubmissionQueueNetworks(t *testing.T) {
+	queue := NewMemorySubmissionQueue(nil, &SubmitQueueConfig{Size: 2})
+
+	ctx := context.Background()
+	otherNetwork := &types.NetworkIdentifier{
+		Blockchain: validationNetwork.Blockchain,
+		Network:    "other",
+	}
+	assert.Nil(t, queue.Enqueue(ctx, &types.ConstructionSubmitRequest{
+		NetworkIdentifier: validationNetwork,
+		SignedTransaction: "tx",
+	}, &types.TransactionIdentifier{Hash: "a"}))
+
+	// The same hash is queued on each network.
+	assert.Nil(t, queue.Enqueue(ctx, &types.ConstructionSubmitRequest{
+		NetworkIdentifier: otherNetwork,
+		SignedTransaction: "tx",
+	}, &types.TransactionIdentifier{Hash: "a"}))
+	assert.Len(t, queue.queue, 2)
+
+	_, err := queue.Status(ctx, &SubmissionStatusRequest{
+		NetworkIdentifier:     otherNetwork,
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "a"},
+	})
+	assert.Nil(t, err)
+
+	_, err = queue.Status(ctx, &SubmissionStatusRequest{
+		NetworkIdentifier:     &types.NetworkIdentifier{Blockchain: "missing", Network: "other"},
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "a"},
+	})
+	assert.Equal(t, ErrSubmissionNotFound, err)
+}
+
+func TestMemorySubmissionQueueRetry(t *testing.T) {
+	servicer := &submitServicer{
+		failures: 1,
+		err:      &types.Error{Code: 1, Message: "node unavailable", Retriable: true},
+	}
+	queue := NewMemorySubmissionQueue(servicer.ConstructionSubmit, &SubmitQueueConfig{
+		Workers:       1,
+		RetryInterval: time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue.Start(ctx)
+
+	request := &types.ConstructionSubmitRequest{SignedTransaction: "tx"}
+	assert.Nil(t, queue.Enqueue(ctx, request, &types.TransactionIdentifier{Hash: "a"}))
+	assert.Eventually(t, func() bool {
+		status, _ := queue.Status(ctx, &SubmissionStatusRequest{
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "a"},
+		})
+		return status.Attempts == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The worker is not blocked while
+	// "a" waits to be retried.
+	assert.Nil(t, queue.Enqueue(ctx, request, &types.TransactionIdentifier{Hash: "b"}))
+	assert.Eventually(t, func() bool {
+		status, _ := queue.Status(ctx, &SubmissionStatusRequest{
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "b"},
+		})
+		return status.State == SubmissionSubmitted
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// ErrTimeout is returned when a request is
+// not served before its timeout.
+var ErrTimeout = &types.Error{
+	Code:      http.StatusGatewayTimeout,
+	Message:   "request timed out",
+	Retriable: true,
+}
+
+// TimeoutRule overrides the timeout of
+// all routes under PathPrefix.
+type TimeoutRule struct {
+	PathPrefix string
+
+	// Timeout is the maximum time to serve a request.
+	// If 0, requests are not timed out.
+	Timeout time.Duration
+}
+
+// TimeoutConfig configures TimeoutMiddleware.
+type TimeoutConfig struct {
+	// Timeout is the maximum time to serve a request to
+	// a route without a matching rule. If 0, requests
+	// to these routes are not timed out.
+	Timeout time.Duration
+
+	// Rules are matched by the longest PathPrefix.
+	Rules []*TimeoutRule
+
+	// Errors configures ErrTimeout (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// timeoutResult is the result of
+// serving a request.
+type timeoutResult struct {
+	recorder *responseRecorder
+	panicked interface{}
+}
+
+// TimeoutMiddleware serves each request with a deadline on its
+// context (so that Servicers can abandon slow queries to a node)
+// and responds with ErrTimeout if the request is not served in
+// time. Responses are buffered until they are served, so streams
+// (EventsStreamPath) are never timed out.
+func TimeoutMiddleware(config *TimeoutConfig) Middleware {
+	rules := map[string]*TimeoutRule{}
+	prefixes := []string{}
+	for _, rule := range config.Rules {
+		rules[rule.PathPrefix] = rule
+		prefixes = append(prefixes, rule.PathPrefix)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := config.Timeout
+			if matched, ok := matchPrefix(r.URL.Path, prefixes); ok {
+				timeout = rules[matched].Timeout
+			}
+
+			if timeout <= 0 || r.URL.Path == EventsStreamPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			// The request is served in a separate goroutine (with
+			// a recorder it owns) so that a response can be sent
+			// when the deadline is exceeded even if the handler
+			// ignores its context.
+			done := make(chan *timeoutResult, 1)
+			go func() {
+				result := &timeoutResult{recorder: newResponseRecorder()}
+				defer func() {
+					result.panicked = recover()
+					done <- result
+				}()
+
+				next.ServeHTTP(result.recorder, r.WithContext(ctx))
+			}()
+
+			select {
+			case result := <-done:
+				if result.panicked != nil {
+					panic(result.panicked)
+				}
+
+				result.recorder.flush(w)
+			case <-ctx.Done():
+				config.Errors.encode(
+					ErrTimeout,
+					"request not served within "+timeout.String(),
+					w,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// delayedHandler responds after delay (or when
+// the request context is done if wait is
+// true).
+func delayedHandler(delay time.Duration, wait bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait {
+			<-r.Context().Done()
+		} else {
+			time.Sleep(delay)
+		}
+
+		_, hasDeadline := r.Context().Deadline()
+		w.Header().Set("X-Deadline", map[bool]string{true: "yes", false: "no"}[hasDeadline])
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("done")) // nolint:errcheck
+	})
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	config := &TimeoutConfig{
+		Timeout: 50 * time.Millisecond,
+		Rules: []*TimeoutRule{
+			{PathPrefix: "/block", Timeout: 500 * time.Millisecond},
+			{PathPrefix: "/construction/submit"},
+		},
+		Errors: &ErrorConfig{Status: ErrorCodeStatus},
+	}
+
+	var tests = map[string]struct {
+		path  string
+		delay time.Duration
+		wait  bool
+
+		status   int
+		deadline string
+	}{
+		"served in time": {
+			path:     "/network/status",
+			status:   http.StatusAccepted,
+			deadline: "yes",
+		},
+		"handler ignores context": {
+			path:   "/network/status",
+			delay:  time.Second,
+			status: http.StatusGatewayTimeout,
+		},
+		"handler waits for context": {
+			path:   "/account/balance",
+			wait:   true,
+			status: http.StatusGatewayTimeout,
+		},
+		"longer rule": {
+			path:     "/block/transaction",
+			delay:    100 * time.Millisecond,
+			status:   http.StatusAccepted,
+			deadline: "yes",
+		},
+		"no timeout": {
+			path:     "/construction/submit",
+			delay:    100 * time.Millisecond,
+			status:   http.StatusAccepted,
+			deadline: "no",
+		},
+		"stream": {
+			path:     EventsStreamPath,
+			delay:    100 * time.Millisecond,
+			status:   http.StatusAccepted,
+			deadline: "no",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := Chain(delayedHandler(test.delay, test.wait), TimeoutMiddleware(config))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, test.path, nil))
+
+			assert.Equal(t, test.status, w.Code)
+			if test.status != http.StatusGatewayTimeout {
+				assert.Equal(t, "done", w.Body.String())
+				assert.Equal(t, test.deadline, w.Header().Get("X-Deadline"))
+				return
+			}
+
+			rosettaErr := &types.Error{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rosettaErr))
+			assert.Equal(t, ErrTimeout.Code, rosettaErr.Code)
+			assert.True(t, rosettaErr.Retriable)
+		})
+	}
+}
+
+func TestTimeoutMiddlewarePanic(t *testing.T) {
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), TimeoutMiddleware(&TimeoutConfig{Timeout: time.Second}))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodPost, "/network/status", nil),
+		)
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// tracerName is the name of the
+// tracer that creates spans.
+const tracerName = "github.com/coinbase/rosetta-sdk-go/server"
+
+// Attributes of the spans created by TracingMiddleware.
+const (
+	HTTPMethodKey      = attribute.Key("http.method")
+	HTTPRouteKey       = attribute.Key("http.route")
+	HTTPStatusCodeKey  = attribute.Key("http.status_code")
+	BlockchainKey      = attribute.Key("rosetta.network.blockchain")
+	NetworkKey         = attribute.Key("rosetta.network.network")
+	SubNetworkKey      = attribute.Key("rosetta.network.sub_network")
+	BlockIndexKey      = attribute.Key("rosetta.block.index")
+	BlockHashKey       = attribute.Key("rosetta.block.hash")
+	TransactionHashKey = attribute.Key("rosetta.transaction.hash")
+	ErrorCodeKey       = attribute.Key("rosetta.error.code")
+)
+
+// TracingConfig configures TracingMiddleware.
+type TracingConfig struct {
+	// TracerProvider creates the tracer used to create
+	// spans. If nil, the global TracerProvider is used.
+	TracerProvider trace.TracerProvider
+
+	// Propagator extracts the trace context from the headers
+	// of a request. If nil, W3C Trace Context headers
+	// (traceparent and tracestate) are extracted.
+	Propagator propagation.TextMapPropagator
+
+	// Errors configures ErrInvalidRequest, which is returned
+	// when the body of a request cannot be read (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// tracedRequest contains the fields of a
+// Rosetta request that are traced.
+type tracedRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	BlockIdentifier   *struct {
+		Index *int64  `json:"index"`
+		Hash  *string `json:"hash"`
+	} `json:"block_identifier"`
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+}
+
+// requestAttributes returns the attributes of
+// the Rosetta request in body (if any).
+func requestAttributes(body []byte) []attribute.KeyValue {
+	var request tracedRequest
+	if len(body) == 0 || json.Unmarshal(body, &request) != nil {
+		return nil
+	}
+
+	attributes := []attribute.KeyValue{}
+	if network := request.NetworkIdentifier; network != nil {
+		attributes = append(
+			attributes,
+			BlockchainKey.String(network.Blockchain),
+			NetworkKey.String(network.Network),
+		)
+
+		if network.SubNetworkIdentifier != nil {
+			attributes = append(
+				attributes,
+				SubNetworkKey.String(network.SubNetworkIdentifier.Network),
+			)
+		}
+	}
+
+	if block := request.BlockIdentifier; block != nil {
+		if block.Index != nil {
+			attributes = append(attributes, BlockIndexKey.Int64(*block.Index))
+		}
+
+		if block.Hash != nil {
+			attributes = append(attributes, BlockHashKey.String(*block.Hash))
+		}
+	}
+
+	if request.TransactionIdentifier != nil {
+		attributes = append(
+			attributes,
+			TransactionHashKey.String(request.TransactionIdentifier.Hash),
+		)
+	}
+
+	return attributes
+}
+
+// TracingMiddleware creates a span for every request (named by its
+// route) that continues the trace in the headers of the request (if
+// any). The span is added to the context passed to Servicers (so
+// that they can create child spans) and has the network, block
+// index, block hash, and transaction hash of the request (when
+// present) as attributes. Spans of requests that fail with a 5xx
+// status are marked as errors.
+func TracingMiddleware(config *TracingConfig) Middleware {
+	provider := config.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	tracer := provider.Tracer(tracerName)
+
+	propagator := config.Propagator
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			route := metricsRoute(r.URL.Path)
+			attributes := []attribute.KeyValue{
+				HTTPMethodKey.String(r.Method),
+				HTTPRouteKey.String(route),
+			}
+
+			if r.Body != nil {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+					return
+				}
+				r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+				attributes = append(attributes, requestAttributes(body)...)
+			}
+
+			ctx, span := tracer.Start(
+				ctx,
+				route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attributes...),
+			)
+			defer span.End()
+
+			lw := &loggingWriter{
+				statusWriter: &statusWriter{ResponseWriter: w, status: http.StatusOK},
+			}
+			next.ServeHTTP(lw, r.WithContext(ctx))
+
+			span.SetAttributes(HTTPStatusCodeKey.Int(lw.status))
+			if code, ok := errorCode(lw.errorBody.Bytes()); ok {
+				span.SetAttributes(ErrorCodeKey.Int(int(code)))
+			}
+
+			if lw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(lw.status))
+			}
+		})
+	}
+}
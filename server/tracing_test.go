@@ -0,0 +1,147 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	tracingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tracingParent  = "00-" + tracingTraceID + "-00f067aa0ba902b7-01"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	network := `"network_identifier":{"blockchain":"Bitcoin","network":"Mainnet",` +
+		`"sub_network_identifier":{"network":"shard 1"}}`
+
+	var tests = map[string]struct {
+		path        string
+		body        string
+		traceparent string
+		status      int
+		response    string
+
+		attributes []attribute.KeyValue
+		code       codes.Code
+	}{
+		"block by index": {
+			path:        "/block",
+			body:        `{` + network + `,"block_identifier":{"index":100}}`,
+			traceparent: tracingParent,
+			status:      http.StatusOK,
+			attributes: []attribute.KeyValue{
+				HTTPMethodKey.String(http.MethodPost),
+				HTTPRouteKey.String("/block"),
+				BlockchainKey.String("Bitcoin"),
+				NetworkKey.String("Mainnet"),
+				SubNetworkKey.String("shard 1"),
+				BlockIndexKey.Int64(100),
+				HTTPStatusCodeKey.Int(http.StatusOK),
+			},
+			code: codes.Unset,
+		},
+		"block transaction": {
+			path: "/block/transaction",
+			body: `{` + network + `,"block_identifier":{"index":1,"hash":"block 1"},` +
+				`"transaction_identifier":{"hash":"tx 1"}}`,
+			status: http.StatusOK,
+			attributes: []attribute.KeyValue{
+				HTTPMethodKey.String(http.MethodPost),
+				HTTPRouteKey.String("/block/transaction"),
+				BlockchainKey.String("Bitcoin"),
+				NetworkKey.String("Mainnet"),
+				SubNetworkKey.String("shard 1"),
+				BlockIndexKey.Int64(1),
+				BlockHashKey.String("block 1"),
+				TransactionHashKey.String("tx 1"),
+				HTTPStatusCodeKey.Int(http.StatusOK),
+			},
+			code: codes.Unset,
+		},
+		"error": {
+			path:     "/unknown",
+			body:     `not json`,
+			status:   http.StatusInternalServerError,
+			response: types.PrintStruct(&types.Error{Code: 12, Message: "node unavailable"}),
+			attributes: []attribute.KeyValue{
+				HTTPMethodKey.String(http.MethodPost),
+				HTTPRouteKey.String(otherRoute),
+				HTTPStatusCodeKey.Int(http.StatusInternalServerError),
+				ErrorCodeKey.Int(12),
+			},
+			code: codes.Error,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			var handlerSpan trace.SpanContext
+			handler := Chain(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					handlerSpan = trace.SpanContextFromContext(r.Context())
+
+					// The body can still be read
+					// by the controller.
+					body := make([]byte, len(test.body))
+					_, err := r.Body.Read(body)
+					assert.NoError(t, err)
+					assert.Equal(t, test.body, string(body))
+
+					w.WriteHeader(test.status)
+					w.Write([]byte(test.response)) // nolint:errcheck
+				}),
+				TracingMiddleware(&TracingConfig{TracerProvider: provider}),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			if len(test.traceparent) > 0 {
+				req.Header.Set("traceparent", test.traceparent)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			assert.Len(t, spans, 1)
+			span := spans[0]
+
+			assert.Equal(t, handlerSpan, span.SpanContext())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.ElementsMatch(t, test.attributes, span.Attributes())
+			assert.Equal(t, test.code, span.Status().Code)
+
+			if len(test.traceparent) > 0 {
+				assert.Equal(t, tracingTraceID, span.SpanContext().TraceID().String())
+				assert.True(t, span.Parent().IsRemote())
+			} else {
+				assert.False(t, span.Parent().IsValid())
+			}
+		})
+	}
+}
@@ -0,0 +1,559 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	// ErrInvalidRequest is returned when a request
+	// is not valid.
+	ErrInvalidRequest = &types.Error{
+		Code:    http.StatusBadRequest,
+		Message: "invalid request",
+	}
+
+	// ErrInvalidResponse is returned instead of a response
+	// that is not valid when StrictResponses is set.
+	ErrInvalidResponse = &types.Error{
+		Code:    http.StatusInternalServerError,
+		Message: "invalid response",
+	}
+)
+
+// ValidationConfig configures ValidationMiddleware.
+type ValidationConfig struct {
+	// Asserter validates requests (usually
+	// constructed with asserter.NewServer).
+	Asserter *asserter.Asserter
+
+	// ResponseAsserter (if populated) validates responses
+	// (usually constructed with asserter.NewClientWithOptions
+	// using the same configuration as /network/options). Any
+	// invalid response is logged (but still sent).
+	ResponseAsserter *asserter.Asserter
+
+	// StrictResponses replaces invalid responses with
+	// ErrInvalidResponse (with the route, the assertion that
+	// failed, and the original response in its details) so
+	// that violations of the specification fail integration
+	// tests. It should not be set in production.
+	StrictResponses bool
+
+	// Errors configures ErrInvalidRequest and
+	// ErrInvalidResponse (see ErrorConfig).
+	Errors *ErrorConfig
+}
+
+// routeValidator validates the requests
+// and responses of a route.
+type routeValidator struct {
+	// request returns the decoded request
+	// (or an error if it is invalid).
+	request func(*asserter.Asserter, []byte) (interface{}, error)
+
+	// response validates a successful response
+	// to a decoded request (if populated).
+	response func(*asserter.Asserter, interface{}, []byte) error
+}
+
+// decode unmarshals body into v.
+func decode(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unable to decode: %w", err)
+	}
+
+	return nil
+}
+
+// routeValidators are the validators
+// of all routes by path.
+var routeValidators = map[string]*routeValidator{
+	"/network/list": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.MetadataRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.MetadataRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.NetworkListResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.NetworkListResponse(response)
+		},
+	},
+	"/network/status": {
+		request: networkRequest,
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.NetworkStatusResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.NetworkStatusResponse(response)
+		},
+	},
+	"/network/options": {
+		request: networkRequest,
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.NetworkOptionsResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.NetworkOptionsResponse(response)
+		},
+	},
+	"/account/balance": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.AccountBalanceRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.AccountBalanceRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.AccountBalanceResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.AccountBalanceResponse(
+				request.(*types.AccountBalanceRequest).BlockIdentifier,
+				response,
+			)
+		},
+	},
+	"/account/coins": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.AccountCoinsRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.AccountCoinsRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.AccountCoinsResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.AccountCoinsResponse(response)
+		},
+	},
+	"/block": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.BlockRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.BlockRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.BlockResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			// Omitted blocks do not have a Block.
+			if response.Block != nil {
+				if err := a.Block(response.Block); err != nil {
+					return err
+				}
+			}
+
+			for _, transaction := range response.OtherTransactions {
+				if err := asserter.TransactionIdentifier(transaction); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	"/block/transaction": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.BlockTransactionRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.BlockTransactionRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.BlockTransactionResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return a.Transaction(response.Transaction)
+		},
+	},
+	"/mempool": {
+		request: networkRequest,
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.MempoolResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.MempoolTransactions(response.TransactionIdentifiers)
+		},
+	},
+	"/mempool/transaction": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.MempoolTransactionRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.MempoolTransactionRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.MempoolTransactionResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return a.Transaction(response.Transaction)
+		},
+	},
+	"/construction/derive": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionDeriveRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionDeriveRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.ConstructionDeriveResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.ConstructionDeriveResponse(response)
+		},
+	},
+	"/construction/preprocess": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionPreprocessRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionPreprocessRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.ConstructionPreprocessResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.ConstructionPreprocessResponse(response)
+		},
+	},
+	"/construction/metadata": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionMetadataRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionMetadataRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.ConstructionMetadataResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.ConstructionMetadataResponse(response)
+		},
+	},
+	"/construction/payloads": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionPayloadsRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionPayloadsRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.ConstructionPayloadsResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.ConstructionPayloadsResponse(response)
+		},
+	},
+	"/construction/combine": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionCombineRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionCombineRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.ConstructionCombineResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.ConstructionCombineResponse(response)
+		},
+	},
+	"/construction/parse": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionParseRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionParseRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.ConstructionParseResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return a.ConstructionParseResponse(
+				response,
+				request.(*types.ConstructionParseRequest).Signed,
+			)
+		},
+	},
+	"/construction/hash": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionHashRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionHashRequest(request)
+		},
+		response: transactionIdentifierResponse,
+	},
+	"/construction/submit": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.ConstructionSubmitRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.ConstructionSubmitRequest(request)
+		},
+		response: transactionIdentifierResponse,
+	},
+	"/call": {
+		// There is no way to validate the result
+		// of an implementation-specific method.
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.CallRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.CallRequest(request)
+		},
+	},
+	"/events/blocks": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.EventsBlocksRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.EventsBlocksRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.EventsBlocksResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return asserter.EventsBlocksResponse(response)
+		},
+	},
+	"/search/transactions": {
+		request: func(a *asserter.Asserter, body []byte) (interface{}, error) {
+			request := &types.SearchTransactionsRequest{}
+			if err := decode(body, request); err != nil {
+				return nil, err
+			}
+
+			return request, a.SearchTransactionsRequest(request)
+		},
+		response: func(a *asserter.Asserter, request interface{}, body []byte) error {
+			response := &types.SearchTransactionsResponse{}
+			if err := decode(body, response); err != nil {
+				return err
+			}
+
+			return a.SearchTransactionsResponse(response)
+		},
+	},
+}
+
+func networkRequest(a *asserter.Asserter, body []byte) (interface{}, error) {
+	request := &types.NetworkRequest{}
+	if err := decode(body, request); err != nil {
+		return nil, err
+	}
+
+	return request, a.NetworkRequest(request)
+}
+
+func transactionIdentifierResponse(a *asserter.Asserter, request interface{}, body []byte) error {
+	response := &types.TransactionIdentifierResponse{}
+	if err := decode(body, response); err != nil {
+		return err
+	}
+
+	return asserter.TransactionIdentifierResponse(response)
+}
+
+// validateResponse validates the response to request
+// on a route (or the *types.Error if it is not
+// successful).
+func validateResponse(
+	a *asserter.Asserter,
+	validator *routeValidator,
+	request interface{},
+	status int,
+	body []byte,
+) error {
+	if status != http.StatusOK {
+		rosettaErr := &types.Error{}
+		if err := decode(body, rosettaErr); err != nil {
+			return err
+		}
+
+		return a.Error(rosettaErr)
+	}
+
+	if validator.response == nil {
+		return nil
+	}
+
+	return validator.response(a, request, body)
+}
+
+// encodeInvalidResponse writes ErrInvalidResponse (with the
+// details of the invalid response in recorder) to w.
+func encodeInvalidResponse(
+	errorConfig *ErrorConfig,
+	route string,
+	recorder *responseRecorder,
+	err error,
+	w http.ResponseWriter,
+) {
+	var response interface{}
+	if decodeErr := json.Unmarshal(recorder.body.Bytes(), &response); decodeErr != nil {
+		response = recorder.body.String()
+	}
+
+	invalidResponse := *errorConfig.error(ErrInvalidResponse)
+	invalidResponse.Details = map[string]interface{}{
+		"route":    route,
+		"status":   recorder.status,
+		"error":    err.Error(),
+		"response": response,
+	}
+
+	EncodeJSONResponse(&invalidResponse, errorConfig.status(ErrInvalidResponse), w)
+}
+
+// ValidationMiddleware validates the body of every request to a
+// Rosetta route with the Asserter before it reaches a controller
+// (responding with ErrInvalidRequest if it is invalid). If a
+// ResponseAsserter is provided, all responses are validated and
+// violations are logged (ex: while debugging an implementation)
+// or, if StrictResponses is set, returned as errors.
+func ValidationMiddleware(config *ValidationConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			validator, ok := routeValidators[r.URL.Path]
+			if !ok || r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+				return
+			}
+
+			// The controller decodes the
+			// body again.
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			request, err := validator.request(config.Asserter, body)
+			if err != nil {
+				config.Errors.encode(ErrInvalidRequest, err.Error(), w)
+				return
+			}
+
+			if config.ResponseAsserter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := newResponseRecorder()
+			next.ServeHTTP(recorder, r)
+
+			if err := validateResponse(
+				config.ResponseAsserter,
+				validator,
+				request,
+				recorder.status,
+				recorder.body.Bytes(),
+			); err != nil {
+				log.Printf("invalid response to %s: %s\n", r.URL.Path, err.Error())
+
+				if config.StrictResponses {
+					encodeInvalidResponse(config.Errors, r.URL.Path, recorder, err, w)
+					return
+				}
+			}
+
+			recorder.flush(w)
+		})
+	}
+}
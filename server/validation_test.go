@@ -0,0 +1,312 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	validationNetwork = &types.NetworkIdentifier{
+		Blockchain: "Bitcoin",
+		Network:    "Mainnet",
+	}
+
+	validationAllowedErrors = []*types.Error{
+		{Code: 12, Message: "block not found", Retriable: true},
+	}
+)
+
+func validationAsserters(t *testing.T) (*asserter.Asserter, *asserter.Asserter) {
+	requestAsserter, err := asserter.NewServer(
+		[]string{"TRANSFER"},
+		false,
+		[]*types.NetworkIdentifier{validationNetwork},
+		nil,
+		false,
+		"",
+	)
+	assert.NoError(t, err)
+
+	responseAsserter, err := asserter.NewClientWithOptions(
+		validationNetwork,
+		&types.BlockIdentifier{Index: 0, Hash: "block 0"},
+		[]string{"TRANSFER"},
+		[]*types.OperationStatus{{Status: "SUCCESS", Successful: true}},
+		validationAllowedErrors,
+		nil,
+		&asserter.Validations{Enabled: false},
+	)
+	assert.NoError(t, err)
+
+	return requestAsserter, responseAsserter
+}
+
+// fixedHandler responds with response and status and
+// records the request body it receives.
+type fixedHandler struct {
+	response interface{}
+	status   int
+
+	body []byte
+}
+
+func (h *fixedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.body, _ = ioutil.ReadAll(r.Body)
+	EncodeJSONResponse(h.response, h.status, w)
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	requestAsserter, responseAsserter := validationAsserters(t)
+
+	validRequest := types.PrintStruct(&types.BlockRequest{
+		NetworkIdentifier: validationNetwork,
+		BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(1)},
+	})
+	validResponse := &types.BlockResponse{
+		Block: &types.Block{
+			BlockIdentifier:       &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+			ParentBlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: "block 0"},
+			Timestamp:             asserter.MinUnixEpoch + 1,
+		},
+	}
+
+	var tests = map[string]struct {
+		path     string
+		request  string
+		response interface{}
+		status   int
+
+		responseAsserter *asserter.Asserter
+		errors           *ErrorConfig
+
+		expectedStatus int
+		err            *types.Error
+		invalidLogged  bool
+	}{
+		"valid request": {
+			path:           "/block",
+			request:        validRequest,
+			response:       validResponse,
+			status:         http.StatusOK,
+			expectedStatus: http.StatusOK,
+		},
+		"malformed request": {
+			path:           "/block",
+			request:        "{",
+			expectedStatus: http.StatusInternalServerError,
+			err:            ErrInvalidRequest,
+		},
+		"replaced error": {
+			path:    "/block",
+			request: "{",
+			errors: &ErrorConfig{
+				Replacements: map[*types.Error]*types.Error{
+					ErrInvalidRequest: validationAllowedErrors[0],
+				},
+				Status: ErrorCodeStatus,
+			},
+			expectedStatus: http.StatusBadRequest,
+			err:            validationAllowedErrors[0],
+		},
+		"unsupported network": {
+			path: "/block",
+			request: types.PrintStruct(&types.BlockRequest{
+				NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"},
+				BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(1)},
+			}),
+			expectedStatus: http.StatusInternalServerError,
+			err:            ErrInvalidRequest,
+		},
+		"unknown route": {
+			path:           "/custom",
+			request:        "{",
+			response:       map[string]string{},
+			status:         http.StatusOK,
+			expectedStatus: http.StatusOK,
+		},
+		"valid response": {
+			path:             "/block",
+			request:          validRequest,
+			response:         validResponse,
+			status:           http.StatusOK,
+			responseAsserter: responseAsserter,
+			expectedStatus:   http.StatusOK,
+		},
+		"invalid response": {
+			path:    "/block",
+			request: validRequest,
+			response: &types.BlockResponse{
+				Block: &types.Block{
+					BlockIdentifier:       &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+					ParentBlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: "block 0"},
+				},
+			},
+			status:           http.StatusOK,
+			responseAsserter: responseAsserter,
+			expectedStatus:   http.StatusOK,
+			invalidLogged:    true,
+		},
+		"allowed error": {
+			path:             "/block",
+			request:          validRequest,
+			response:         validationAllowedErrors[0],
+			status:           http.StatusInternalServerError,
+			responseAsserter: responseAsserter,
+			expectedStatus:   http.StatusInternalServerError,
+		},
+		"unexpected error": {
+			path:             "/block",
+			request:          validRequest,
+			response:         &types.Error{Code: 13, Message: "unexpected"},
+			status:           http.StatusInternalServerError,
+			responseAsserter: responseAsserter,
+			expectedStatus:   http.StatusInternalServerError,
+			invalidLogged:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+
+			next := &fixedHandler{response: test.response, status: test.status}
+			handler := Chain(next, ValidationMiddleware(&ValidationConfig{
+				Asserter:         requestAsserter,
+				ResponseAsserter: test.responseAsserter,
+				Errors:           test.errors,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.request))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.invalidLogged, logs.Len() > 0)
+			if test.err != nil {
+				var rosettaErr types.Error
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+				assert.Equal(t, test.err.Code, rosettaErr.Code)
+				assert.Equal(t, test.err.Message, rosettaErr.Message)
+				assert.Nil(t, next.body)
+				return
+			}
+
+			// The controller receives the
+			// original request.
+			assert.Equal(t, test.request, string(next.body))
+
+			expected, err := json.Marshal(test.response)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), w.Body.String())
+		})
+	}
+}
+
+func TestValidationMiddlewareStrictResponses(t *testing.T) {
+	requestAsserter, responseAsserter := validationAsserters(t)
+
+	validRequest := types.PrintStruct(&types.BlockRequest{
+		NetworkIdentifier: validationNetwork,
+		BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(1)},
+	})
+	invalidBlock := &types.BlockResponse{
+		Block: &types.Block{
+			BlockIdentifier:       &types.BlockIdentifier{Index: 1, Hash: "block 1"},
+			ParentBlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: "block 0"},
+		},
+	}
+
+	var tests = map[string]struct {
+		response interface{}
+		status   int
+
+		expectedStatus int
+		expectedError  string
+	}{
+		"valid response": {
+			response:       validationAllowedErrors[0],
+			status:         http.StatusInternalServerError,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"invalid response": {
+			response:       invalidBlock,
+			status:         http.StatusOK,
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  asserter.ErrTimestampBeforeMin.Error(),
+		},
+		"unexpected error": {
+			response:       &types.Error{Code: 13, Message: "unexpected"},
+			status:         http.StatusInternalServerError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  asserter.ErrErrorUnexpectedCode.Error(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+
+			next := &fixedHandler{response: test.response, status: test.status}
+			handler := Chain(next, ValidationMiddleware(&ValidationConfig{
+				Asserter:         requestAsserter,
+				ResponseAsserter: responseAsserter,
+				StrictResponses:  true,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/block", bytes.NewBufferString(validRequest))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if len(test.expectedError) == 0 {
+				expected, err := json.Marshal(test.response)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expected), w.Body.String())
+				return
+			}
+
+			var rosettaErr types.Error
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rosettaErr))
+			assert.Equal(t, ErrInvalidResponse.Code, rosettaErr.Code)
+			assert.Equal(t, ErrInvalidResponse.Message, rosettaErr.Message)
+			assert.Equal(t, "/block", rosettaErr.Details["route"])
+			assert.Equal(t, float64(test.status), rosettaErr.Details["status"])
+			assert.Contains(t, rosettaErr.Details["error"], test.expectedError)
+
+			response, err := json.Marshal(rosettaErr.Details["response"])
+			assert.NoError(t, err)
+			expected, err := json.Marshal(test.response)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(response))
+		})
+	}
+}
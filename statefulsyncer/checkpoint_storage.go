@@ -0,0 +1,156 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statefulsyncer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neilotoole/errgroup"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	checkpointKey = "checkpoint"
+)
+
+var _ syncer.HandlerCheckpointStore = (*CheckpointStorage)(nil)
+var _ modules.BlockWorker = (*CheckpointStorage)(nil)
+
+// CheckpointStorage implements syncer.HandlerCheckpointStore
+// by writing the *syncer.Checkpoint of each block in the same
+// database transaction the block is stored in by a Handler
+// that uses *modules.BlockStorage. It must be provided to the
+// *modules.BlockStorage as a modules.BlockWorker.
+type CheckpointStorage struct {
+	db database.Database
+}
+
+// NewCheckpointStorage returns a new *CheckpointStorage.
+func NewCheckpointStorage(db database.Database) *CheckpointStorage {
+	return &CheckpointStorage{db: db}
+}
+
+// SavesCheckpointInHandler returns true because each
+// *syncer.Checkpoint is written by AddingBlock and
+// RemovingBlock.
+func (c *CheckpointStorage) SavesCheckpointInHandler() bool {
+	return true
+}
+
+func (c *CheckpointStorage) storeCheckpoint(
+	ctx context.Context,
+	dbTx database.Transaction,
+	checkpoint *syncer.Checkpoint,
+) error {
+	encoded, err := c.db.Encoder().Encode("", checkpoint)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCheckpointSaveFailed, err)
+	}
+
+	if err := dbTx.Set(ctx, []byte(checkpointKey), encoded, true); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCheckpointSaveFailed, err)
+	}
+
+	return nil
+}
+
+// SaveCheckpoint is invoked by the syncer when sync progress is
+// made without invoking the Handler (ex: an omitted block).
+func (c *CheckpointStorage) SaveCheckpoint(
+	ctx context.Context,
+	checkpoint *syncer.Checkpoint,
+) error {
+	dbTx := c.db.Transaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	if err := c.storeCheckpoint(ctx, dbTx, checkpoint); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCheckpointSaveFailed, err)
+	}
+
+	return nil
+}
+
+// LoadCheckpoint returns the last saved *syncer.Checkpoint
+// (or nil if none has been saved).
+func (c *CheckpointStorage) LoadCheckpoint(
+	ctx context.Context,
+) (*syncer.Checkpoint, error) {
+	dbTx := c.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	exists, val, err := dbTx.Get(ctx, []byte(checkpointKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrCheckpointLoadFailed, err)
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	var checkpoint syncer.Checkpoint
+	if err := c.db.Encoder().Decode("", val, &checkpoint, true); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrCheckpointLoadFailed, err)
+	}
+
+	return &checkpoint, nil
+}
+
+// AddingBlock is called by *modules.BlockStorage when adding a block
+// and writes the *syncer.Checkpoint attached to ctx.
+func (c *CheckpointStorage) AddingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	return nil, c.storeContextCheckpoint(ctx, transaction)
+}
+
+// RemovingBlock is called by *modules.BlockStorage when removing a block
+// and writes the *syncer.Checkpoint attached to ctx.
+func (c *CheckpointStorage) RemovingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	return nil, c.storeContextCheckpoint(ctx, transaction)
+}
+
+// storeContextCheckpoint writes the *syncer.Checkpoint
+// attached to ctx by the syncer (if any). When several
+// blocks are removed in one transaction, each removal
+// writes the same *syncer.Checkpoint.
+func (c *CheckpointStorage) storeContextCheckpoint(
+	ctx context.Context,
+	dbTx database.Transaction,
+) error {
+	checkpoint := syncer.CheckpointFromContext(ctx)
+	if checkpoint == nil {
+		return nil
+	}
+
+	return c.storeCheckpoint(ctx, dbTx, checkpoint)
+}
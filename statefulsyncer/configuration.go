@@ -16,6 +16,8 @@ package statefulsyncer
 
 import (
 	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
 )
 
 // Option is used to overwrite default values in
@@ -58,6 +60,14 @@ func WithPruneSleepTime(sleepTime int) Option {
 	}
 }
 
+// WithPrunePolicies adds policies that are consulted
+// before each block is pruned (ex: modules.KeepEveryKthBlock).
+func WithPrunePolicies(policies ...modules.PrunePolicy) Option {
+	return func(s *StatefulSyncer) {
+		s.prunePolicies = append(s.prunePolicies, policies...)
+	}
+}
+
 // WithSeenConcurrency overrides the number of concurrent
 // invocations of BlockSeen we will handle. We default
 // to the value of runtime.NumCPU().
@@ -16,20 +16,16 @@ package statefulsyncer
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
 	"runtime"
 	"time"
 
 	"golang.org/x/sync/semaphore"
 
 	"github.com/coinbase/rosetta-sdk-go/fetcher"
-	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/storage/modules"
 	"github.com/coinbase/rosetta-sdk-go/syncer"
 	"github.com/coinbase/rosetta-sdk-go/types"
-	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
 var _ syncer.Handler = (*StatefulSyncer)(nil)
@@ -67,6 +63,7 @@ type StatefulSyncer struct {
 	pastBlockLimit   int
 	adjustmentWindow int64
 	pruneSleepTime   time.Duration
+	prunePolicies    []modules.PrunePolicy
 
 	// SeenSemaphore limits how many executions of
 	// BlockSeen occur concurrently.
@@ -178,62 +175,15 @@ func (s *StatefulSyncer) Sync(ctx context.Context, startIndex int64, endIndex in
 // in the initializer because the caller may wish to change
 // pruning strategies during syncing.
 func (s *StatefulSyncer) Prune(ctx context.Context, helper PruneHelper) error {
-	for ctx.Err() == nil {
-		// We don't use a timer pattern because s.pruneSleepTime is defined
-		// as the time between pruning runs. Using a timer would only guarantee
-		// that the difference between starts of each pruning run are s.pruneSleepTime.
-		if err := utils.ContextSleep(ctx, s.pruneSleepTime); err != nil {
-			return err
-		}
-
-		headBlock, err := s.blockStorage.GetHeadBlockIdentifier(ctx)
-		if headBlock == nil && errors.Is(err, storageErrs.ErrHeadBlockNotFound) {
-			// this will occur when we are waiting for the first block to be synced
-			continue
-		}
-		if err != nil {
-			return err
-		}
-
-		oldestIndex, err := s.blockStorage.GetOldestBlockIndex(ctx)
-		if oldestIndex == -1 && errors.Is(err, storageErrs.ErrOldestIndexMissing) {
-			// this will occur when we have yet to store the oldest index
-			continue
-		}
-		if err != nil {
-			return err
-		}
-
-		pruneableIndex, err := helper.PruneableIndex(ctx, headBlock.Index)
-		if err != nil {
-			return fmt.Errorf("%w: could not determine pruneable index", err)
-		}
-
-		if pruneableIndex < oldestIndex {
-			continue
-		}
-
-		firstPruned, lastPruned, err := s.blockStorage.Prune(
-			ctx,
-			pruneableIndex,
-			int64(s.pastBlockLimit)*pruneBuffer, // we should be very cautious about pruning
-		)
-		if err != nil {
-			return err
-		}
-
-		// firstPruned and lastPruned are -1 if there is nothing to prune
-		if firstPruned != -1 && lastPruned != -1 {
-			pruneMessage := fmt.Sprintf("pruned blocks %d-%d", firstPruned, lastPruned)
-			if firstPruned == lastPruned {
-				pruneMessage = fmt.Sprintf("pruned block %d", firstPruned)
-			}
-
-			log.Println(pruneMessage)
-		}
-	}
+	pruner := modules.NewPruner(
+		s.blockStorage,
+		int64(s.pastBlockLimit)*pruneBuffer, // we should be very cautious about pruning
+		modules.WithPruneInterval(s.pruneSleepTime),
+		modules.WithPruneableIndex(helper.PruneableIndex),
+		modules.WithPrunePolicies(s.prunePolicies...),
+	)
 
-	return ctx.Err()
+	return pruner.Run(ctx)
 }
 
 // BlockSeen is called by the syncer when a block is seen.
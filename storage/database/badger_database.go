@@ -22,10 +22,10 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/badger/v2"
@@ -97,6 +97,22 @@ type BadgerDatabase struct {
 	// Track the closed status to ensure we exit garbage
 	// collection when the db closes.
 	closed chan struct{}
+
+	// periodicGC determines if value log garbage collection
+	// is run in the background.
+	periodicGC bool
+
+	// readOnly determines if the database
+	// rejects all modifications.
+	readOnly bool
+
+	// writes and deletes track the number of keys set and
+	// deleted in committed transactions since the last
+	// maintenance run. They must be accessed atomically.
+	writes  int64
+	deletes int64
+
+	metrics Metrics
 }
 
 // DefaultBadgerOptions are the default options used to initialized
@@ -211,6 +227,8 @@ func NewBadgerDatabase(
 		pool:          encoder.NewBufferPool(),
 		compress:      true,
 		writerShards:  utils.DefaultShards,
+		periodicGC:    true,
+		metrics:       noopMetrics{},
 	}
 	for _, opt := range storageOptions {
 		opt(b)
@@ -220,6 +238,13 @@ func NewBadgerDatabase(
 	// write transactions.
 	b.writer = utils.NewMutexMap(b.writerShards)
 
+	// Garbage collection rewrites the value log,
+	// so it cannot be run in read-only mode.
+	if b.readOnly {
+		b.badgerOptions.ReadOnly = true
+		b.periodicGC = false
+	}
+
 	db, err := badger.Open(b.badgerOptions)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseOpenFailed, err)
@@ -234,7 +259,9 @@ func NewBadgerDatabase(
 
 	// Start periodic ValueGC goroutine (up to user of BadgerDB to call
 	// periodically to reclaim value logs on-disk).
-	go b.periodicGC(ctx)
+	if b.periodicGC {
+		go b.runPeriodicGC(ctx)
+	}
 
 	return b, nil
 }
@@ -252,12 +279,12 @@ func (b *BadgerDatabase) Close(ctx context.Context) error {
 	return nil
 }
 
-// periodicGC attempts to reclaim storage every
+// runPeriodicGC attempts to reclaim storage every
 // defaultGCInterval.
 //
 // Inspired by:
 // https://github.com/ipfs/go-ds-badger/blob/a69f1020ba3954680900097e0c9d0181b88930ad/datastore.go#L173-L199
-func (b *BadgerDatabase) periodicGC(ctx context.Context) {
+func (b *BadgerDatabase) runPeriodicGC(ctx context.Context) {
 	// We start the timeout with the default sleep to aggressively check
 	// for space to reclaim on startup.
 	gcTimeout := time.NewTimer(defaultGCSleep)
@@ -275,6 +302,10 @@ func (b *BadgerDatabase) periodicGC(ctx context.Context) {
 		case <-gcTimeout.C:
 			start := time.Now()
 			err := b.db.RunValueLogGC(defualtGCDiscardRatio)
+			if err != badger.ErrNoRewrite && err != badger.ErrRejected {
+				b.metrics.ObserveGC(time.Since(start), 0, err)
+			}
+
 			switch err {
 			case badger.ErrNoRewrite, badger.ErrRejected:
 				// No rewrite means we've fully garbage collected.
@@ -302,6 +333,145 @@ func (b *BadgerDatabase) Encoder() *encoder.Encoder {
 	return b.encoder
 }
 
+// diskUsage returns the size of all files used by the BadgerDatabase.
+// Badger only refreshes its own size estimate once a minute, so we
+// walk the database directories instead.
+func (b *BadgerDatabase) diskUsage() (int64, error) {
+	if b.badgerOptions.InMemory {
+		lsm, vlog := b.db.Size()
+		return lsm + vlog, nil
+	}
+
+	dirs := []string{b.badgerOptions.Dir}
+	if b.badgerOptions.ValueDir != b.badgerOptions.Dir {
+		dirs = append(dirs, b.badgerOptions.ValueDir)
+	}
+
+	var size int64
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() {
+				size += info.Size()
+			}
+
+			return nil
+		})
+		if err != nil {
+			return -1, fmt.Errorf("%w: %v", storageErrs.ErrWalkFilesFailed, err)
+		}
+	}
+
+	return size, nil
+}
+
+// MaintenanceStats returns the current disk usage of the
+// BadgerDatabase and the number of writes and deletes
+// committed since the last maintenance run.
+func (b *BadgerDatabase) MaintenanceStats(ctx context.Context) (*MaintenanceStats, error) {
+	size, err := b.diskUsage()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrMaintenanceStatsFailed, err)
+	}
+
+	return &MaintenanceStats{
+		DiskUsage: size,
+		Writes:    atomic.LoadInt64(&b.writes),
+		Deletes:   atomic.LoadInt64(&b.deletes),
+	}, nil
+}
+
+// RunMaintenance runs value log garbage collection until
+// there is nothing left to rewrite. If compact is true,
+// all LSM tree levels are also compacted into the lowest
+// level (which drops deleted and expired keys).
+func (b *BadgerDatabase) RunMaintenance(
+	ctx context.Context,
+	compact bool,
+) (*MaintenanceResult, error) {
+	if b.readOnly {
+		return nil, storageErrs.ErrDatabaseReadOnly
+	}
+
+	start := time.Now()
+	before, err := b.diskUsage()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrMaintenanceFailed, err)
+	}
+
+	// Reset the counters before running maintenance so that
+	// any writes that occur while running are tracked for
+	// the next run.
+	atomic.StoreInt64(&b.writes, 0)
+	atomic.StoreInt64(&b.deletes, 0)
+
+	result := &MaintenanceResult{}
+	if compact {
+		if err := b.db.Flatten(1); err != nil {
+			return nil, fmt.Errorf("%w: unable to compact: %v", storageErrs.ErrMaintenanceFailed, err)
+		}
+
+		result.Compacted = true
+	}
+
+	for ctx.Err() == nil {
+		err := b.db.RunValueLogGC(defualtGCDiscardRatio)
+		if errors.Is(err, badger.ErrNoRewrite) || errors.Is(err, badger.ErrRejected) {
+			break
+		}
+		if err != nil {
+			b.metrics.ObserveGC(time.Since(start), 0, err)
+			return nil, fmt.Errorf(
+				"%w: unable to collect value log garbage: %v",
+				storageErrs.ErrMaintenanceFailed,
+				err,
+			)
+		}
+
+		result.ValueLogsRewritten++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrMaintenanceFailed, err)
+	}
+
+	after, err := b.diskUsage()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrMaintenanceFailed, err)
+	}
+
+	if before > after {
+		result.ReclaimedBytes = before - after
+	}
+	result.Duration = time.Since(start)
+	b.metrics.ObserveGC(result.Duration, result.ReclaimedBytes, nil)
+
+	return result, nil
+}
+
+// CacheStats returns the hits and misses of the
+// BadgerDB block and index caches.
+func (b *BadgerDatabase) CacheStats() []*CacheStats {
+	blockCache := b.db.BlockCacheMetrics()
+	indexCache := b.db.IndexCacheMetrics()
+
+	return []*CacheStats{
+		{
+			Name:   "block",
+			Hits:   blockCache.Hits(),
+			Misses: blockCache.Misses(),
+		},
+		{
+			Name:   "index",
+			Hits:   indexCache.Hits(),
+			Misses: indexCache.Misses(),
+		},
+	}
+}
+
 // BadgerTransaction is a wrapper around a Badger
 // DB transaction that implements the DatabaseTransaction
 // interface.
@@ -322,6 +492,11 @@ type BadgerTransaction struct {
 	// times. This will almost certainly lead to a panic.
 	reclaimLock      sync.Mutex
 	buffersToReclaim []*bytes.Buffer
+
+	// writes and deletes are added to the BadgerDatabase
+	// counters once the transaction is committed.
+	writes  int64
+	deletes int64
 }
 
 // Transaction creates a new exclusive write BadgerTransaction.
@@ -379,7 +554,12 @@ func (b *BadgerTransaction) releaseLocks() {
 
 // Commit attempts to commit and discard the transaction.
 func (b *BadgerTransaction) Commit(context.Context) error {
+	start := time.Now()
 	err := b.txn.Commit()
+	b.db.metrics.ObserveCommit(time.Since(start), err)
+	if errors.Is(err, badger.ErrConflict) {
+		b.db.metrics.ObserveConflict()
+	}
 
 	// Reclaim all allocated buffers for future work.
 	b.reclaimLock.Lock()
@@ -399,6 +579,11 @@ func (b *BadgerTransaction) Commit(context.Context) error {
 		return fmt.Errorf("%w: %v", storageErrs.ErrCommitFailed, err)
 	}
 
+	atomic.AddInt64(&b.db.writes, b.writes)
+	atomic.AddInt64(&b.db.deletes, b.deletes)
+	b.writes = 0
+	b.deletes = 0
+
 	return nil
 }
 
@@ -430,6 +615,10 @@ func (b *BadgerTransaction) Set(
 	b.rwLock.Lock()
 	defer b.rwLock.Unlock()
 
+	if b.db.readOnly {
+		return storageErrs.ErrDatabaseReadOnly
+	}
+
 	if reclaimValue {
 		b.buffersToReclaim = append(
 			b.buffersToReclaim,
@@ -437,7 +626,14 @@ func (b *BadgerTransaction) Set(
 		)
 	}
 
-	return b.txn.Set(key, value)
+	start := time.Now()
+	if err := b.txn.Set(key, value); err != nil {
+		return err
+	}
+
+	b.writes++
+	b.db.metrics.ObserveOperation(WriteOperation, keyNamespace(key), len(value), time.Since(start))
+	return nil
 }
 
 // Get accesses the value of the key within a transaction.
@@ -449,9 +645,11 @@ func (b *BadgerTransaction) Get(
 	b.rwLock.RLock()
 	defer b.rwLock.RUnlock()
 
+	start := time.Now()
 	value := b.db.pool.Get()
 	item, err := b.txn.Get(key)
 	if err == badger.ErrKeyNotFound {
+		b.db.metrics.ObserveOperation(ReadOperation, keyNamespace(key), 0, time.Since(start))
 		return false, nil, nil
 	} else if err != nil {
 		return false, nil, err
@@ -465,6 +663,7 @@ func (b *BadgerTransaction) Get(
 		return false, nil, err
 	}
 
+	b.db.metrics.ObserveOperation(ReadOperation, keyNamespace(key), value.Len(), time.Since(start))
 	return true, value.Bytes(), nil
 }
 
@@ -473,7 +672,18 @@ func (b *BadgerTransaction) Delete(ctx context.Context, key []byte) error {
 	b.rwLock.Lock()
 	defer b.rwLock.Unlock()
 
-	return b.txn.Delete(key)
+	if b.db.readOnly {
+		return storageErrs.ErrDatabaseReadOnly
+	}
+
+	start := time.Now()
+	if err := b.txn.Delete(key); err != nil {
+		return err
+	}
+
+	b.deletes++
+	b.db.metrics.ObserveOperation(DeleteOperation, keyNamespace(key), 0, time.Since(start))
+	return nil
 }
 
 // Scan calls a worker for each item in a scan instead
@@ -489,7 +699,9 @@ func (b *BadgerTransaction) Scan(
 	b.rwLock.RLock()
 	defer b.rwLock.RUnlock()
 
+	start := time.Now()
 	entries := 0
+	scanned := 0
 	opts := badger.DefaultIteratorOptions
 	opts.Reverse = reverse
 	it := b.txn.NewIterator(opts)
@@ -498,6 +710,7 @@ func (b *BadgerTransaction) Scan(
 		item := it.Item()
 		k := item.Key()
 		err := item.Value(func(v []byte) error {
+			scanned += len(v)
 			if err := worker(k, v); err != nil {
 				return fmt.Errorf("%w: worker failed for key %s", err, string(k))
 			}
@@ -514,6 +727,7 @@ func (b *BadgerTransaction) Scan(
 		}
 	}
 
+	b.db.metrics.ObserveOperation(ScanOperation, keyNamespace(prefix), scanned, time.Since(start))
 	return entries, nil
 }
 
@@ -662,83 +876,30 @@ func BadgerTrain(
 	}
 	defer utils.RemoveTempDir(tmpDir)
 
-	// We must use a restricted namespace or we will inadvertently
-	// fetch all namespaces that contain the namespace we care about.
-	restrictedNamespace := fmt.Sprintf("%s/", namespace)
-
-	totalUncompressedSize := float64(0)
-	totalDiskSize := float64(0)
-	entriesSeen := 0
-	txn := badgerDb.ReadTransaction(ctx)
-	defer txn.Discard(ctx)
-	_, err = txn.Scan(
-		ctx,
-		[]byte(restrictedNamespace),
-		[]byte(restrictedNamespace),
-		func(k []byte, v []byte) error {
-			decompressedSize, diskSize, err := decompressAndSave(
-				badgerDb.Encoder(),
-				namespace,
-				tmpDir,
-				k,
-				v,
-			)
-			if err != nil {
-				return fmt.Errorf("%w: unable to decompress and save", err)
-			}
-
-			totalUncompressedSize += decompressedSize
-			totalDiskSize += diskSize
-			entriesSeen++
-
-			if entriesSeen > maxEntries-1 && maxEntries != -1 {
-				return storageErrs.ErrMaxEntries
-			}
-
-			return nil
-		},
-		true,
-		false,
-	)
-	if err != nil && !errors.Is(err, storageErrs.ErrMaxEntries) {
-		return -1, -1, fmt.Errorf("%w for %s: %v", storageErrs.ErrScanFailed, namespace, err)
-	}
-
-	if entriesSeen == 0 {
-		return -1, -1, fmt.Errorf("%w %s", storageErrs.ErrNoEntriesFoundInNamespace, namespace)
+	sample, err := SampleNamespace(ctx, badgerDb, namespace, tmpDir, maxEntries)
+	if err != nil {
+		return -1, -1, err
 	}
 
 	log.Printf(
 		"found %d entries for %s (average uncompressed size: %fB)\n",
-		entriesSeen,
+		sample.Entries,
 		namespace,
-		totalUncompressedSize/float64(entriesSeen),
+		sample.UncompressedSize/float64(sample.Entries),
 	)
 
 	log.Printf(
 		"found %d entries for %s (average disk size: %fB)\n",
-		entriesSeen,
+		sample.Entries,
 		namespace,
-		totalDiskSize/float64(entriesSeen),
+		sample.DiskSize/float64(sample.Entries),
 	)
 
 	// Invoke ZSTD
 	dictPath := path.Clean(output)
 	log.Printf("creating dictionary %s\n", dictPath)
-	cmd := exec.Command(
-		"zstd",
-		"--train",
-		"-r",
-		tmpDir,
-		"-o",
-		dictPath,
-	) // #nosec G204
-	if err := cmd.Start(); err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrInvokeZSTDFailed, err)
-	}
-
-	if err := cmd.Wait(); err != nil {
-		return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrTrainZSTDFailed, err)
+	if err := TrainDictionary(ctx, tmpDir, dictPath, 0); err != nil {
+		return -1, -1, err
 	}
 
 	encoder, err := encoder.NewEncoder([]*encoder.CompressorEntry{
@@ -794,10 +955,10 @@ func BadgerTrain(
 	)
 
 	if len(compressorEntries) > 0 {
-		oldDictionarySize := totalDiskSize / sizeUncompressed
+		oldDictionarySize := sample.DiskSize / sizeUncompressed
 		log.Printf(
 			"[IN SAMPLE] Total Size Compressed (with old dictionary): %fMB (%% of original size %f%%)",
-			utils.BtoMb(totalDiskSize),
+			utils.BtoMb(sample.DiskSize),
 			oldDictionarySize*utils.OneHundred,
 		)
 	}
@@ -814,7 +975,7 @@ func BadgerTrain(
 		ctx,
 		badgerDb,
 		namespace,
-		restrictedNamespace,
+		fmt.Sprintf("%s/", namespace),
 		encoder,
 	)
 }
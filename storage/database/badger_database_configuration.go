@@ -68,3 +68,34 @@ func WithWriterShards(shards int) BadgerOption {
 		b.writerShards = shards
 	}
 }
+
+// WithoutPeriodicGC disables the value log garbage
+// collection that is run in the background by default.
+// This is useful when running maintenance with a
+// MaintenanceScheduler instead.
+func WithoutPeriodicGC() BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.periodicGC = false
+	}
+}
+
+// WithReadOnly opens the BadgerDB in read-only mode. Any
+// attempt to modify the database returns ErrDatabaseReadOnly
+// and periodic garbage collection is disabled.
+//
+// BadgerDB only allows a read-only open when no other
+// process has the database open for writing. Multiple
+// read-only processes can open the same database.
+func WithReadOnly() BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.readOnly = true
+	}
+}
+
+// WithMetrics provides Metrics that are invoked
+// with instrumentation of all database activity.
+func WithMetrics(metrics Metrics) BadgerOption {
+	return func(b *BadgerDatabase) {
+		b.metrics = metrics
+	}
+}
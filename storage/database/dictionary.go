@@ -0,0 +1,164 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"path"
+	"strconv"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// NamespaceSample summarizes the values sampled
+// from a namespace for dictionary training.
+type NamespaceSample struct {
+	Entries          int     `json:"entries"`
+	UncompressedSize float64 `json:"uncompressed_size"`
+	DiskSize         float64 `json:"disk_size"`
+}
+
+// SampleNamespace writes up to maxEntries decompressed values stored
+// in namespace to dir (one file per value) so they can be used to train
+// a zstd dictionary. If maxEntries is -1, all values are written.
+func SampleNamespace(
+	ctx context.Context,
+	db Database,
+	namespace string,
+	dir string,
+	maxEntries int,
+) (*NamespaceSample, error) {
+	// We must use a restricted namespace or we will inadvertently
+	// fetch all namespaces that contain the namespace we care about.
+	restrictedNamespace := fmt.Sprintf("%s/", namespace)
+
+	sample := &NamespaceSample{}
+	txn := db.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+	_, err := txn.Scan(
+		ctx,
+		[]byte(restrictedNamespace),
+		[]byte(restrictedNamespace),
+		func(k []byte, v []byte) error {
+			decompressedSize, diskSize, err := decompressAndSave(
+				db.Encoder(),
+				namespace,
+				dir,
+				k,
+				v,
+			)
+			if err != nil {
+				return fmt.Errorf("%w: unable to decompress and save", err)
+			}
+
+			sample.UncompressedSize += decompressedSize
+			sample.DiskSize += diskSize
+			sample.Entries++
+
+			if sample.Entries > maxEntries-1 && maxEntries != -1 {
+				return storageErrs.ErrMaxEntries
+			}
+
+			return nil
+		},
+		true,
+		false,
+	)
+	if err != nil && !errors.Is(err, storageErrs.ErrMaxEntries) {
+		return nil, fmt.Errorf("%w for %s: %v", storageErrs.ErrScanFailed, namespace, err)
+	}
+
+	if sample.Entries == 0 {
+		return nil, fmt.Errorf("%w %s", storageErrs.ErrNoEntriesFoundInNamespace, namespace)
+	}
+
+	return sample, nil
+}
+
+// TrainDictionary trains a zstd dictionary on all samples in dir
+// and persists it to output. If maxSize is 0, the zstd default
+// dictionary size is used.
+//
+// This requires the zstd CLI to be installed.
+func TrainDictionary(
+	ctx context.Context,
+	dir string,
+	output string,
+	maxSize int,
+) error {
+	args := []string{
+		"--train",
+		"-r",
+		dir,
+		"-o",
+		path.Clean(output),
+	}
+	if maxSize > 0 {
+		args = append(args, "--maxdict="+strconv.Itoa(maxSize))
+	}
+
+	cmd := exec.CommandContext(ctx, "zstd", args...) // #nosec G204
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrInvokeZSTDFailed, err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrTrainZSTDFailed, err)
+	}
+
+	return nil
+}
+
+// TrainNamespaceDictionary samples up to maxEntries values stored in
+// namespace, trains a zstd dictionary on them, and persists it to output.
+// The returned *encoder.CompressorEntry can be provided to a Database
+// to use the dictionary.
+//
+// NOTE: Values already stored in namespace must be re-encoded with
+// the new dictionary before it can be used (see NOTE on encoder.Encoder).
+func TrainNamespaceDictionary(
+	ctx context.Context,
+	db Database,
+	namespace string,
+	output string,
+	maxEntries int,
+) (*encoder.CompressorEntry, *NamespaceSample, error) {
+	tmpDir, err := utils.CreateTempDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrCreateTempDirectoryFailed, err)
+	}
+	defer utils.RemoveTempDir(tmpDir)
+
+	sample, err := SampleNamespace(ctx, db, namespace, tmpDir, maxEntries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("creating dictionary %s from %d %s entries\n", output, sample.Entries, namespace)
+	if err := TrainDictionary(ctx, tmpDir, output, 0); err != nil {
+		return nil, nil, err
+	}
+
+	return &encoder.CompressorEntry{
+		Namespace:      namespace,
+		DictionaryPath: path.Clean(output),
+	}, sample, nil
+}
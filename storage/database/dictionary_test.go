@@ -0,0 +1,107 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func TestTrainNamespaceDictionary(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	namespace := "bogus"
+	txn := database.Transaction(ctx)
+	for i := 0; i < 1000; i++ {
+		entry := &BogusEntry{
+			Index: i,
+			Stuff: fmt.Sprintf("block %d", i),
+		}
+		compressedEntry, err := database.Encoder().Encode(namespace, entry)
+		assert.NoError(t, err)
+		assert.NoError(
+			t,
+			txn.Set(ctx, []byte(fmt.Sprintf("%s/%d", namespace, i)), compressedEntry, true),
+		)
+	}
+	assert.NoError(t, txn.Commit(ctx))
+
+	t.Run("empty namespace", func(t *testing.T) {
+		entry, sample, err := TrainNamespaceDictionary(
+			ctx,
+			database,
+			"missing",
+			path.Join(newDir, "missing_dict"),
+			-1,
+		)
+		assert.True(t, errors.Is(err, storageErrs.ErrNoEntriesFoundInNamespace))
+		assert.Nil(t, entry)
+		assert.Nil(t, sample)
+	})
+
+	t.Run("sample limit", func(t *testing.T) {
+		sampleDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(sampleDir)
+
+		sample, err := SampleNamespace(ctx, database, namespace, sampleDir, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 100, sample.Entries)
+		assert.True(t, sample.UncompressedSize > 0)
+		assert.True(t, sample.DiskSize > 0)
+	})
+
+	t.Run("train and load", func(t *testing.T) {
+		dictPath := path.Join(newDir, "bogus_dict")
+		entry, sample, err := TrainNamespaceDictionary(ctx, database, namespace, dictPath, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, &encoder.CompressorEntry{
+			Namespace:      namespace,
+			DictionaryPath: dictPath,
+		}, entry)
+		assert.Equal(t, 1000, sample.Entries)
+
+		e, err := encoder.NewEncoder([]*encoder.CompressorEntry{entry}, encoder.NewBufferPool(), true)
+		assert.NoError(t, err)
+
+		object := &BogusEntry{Index: 1, Stuff: "block 1"}
+		withDict, err := e.Encode(namespace, object)
+		assert.NoError(t, err)
+		withoutDict, err := e.Encode("", object)
+		assert.NoError(t, err)
+		assert.True(t, len(withDict) < len(withoutDict))
+
+		var decoded BogusEntry
+		assert.NoError(t, e.Decode(namespace, withDict, &decoded, false))
+		assert.Equal(t, object, &decoded)
+	})
+}
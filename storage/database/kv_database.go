@@ -0,0 +1,671 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// KVWrite is a single change applied to a KVStore.
+// A nil Value deletes Key.
+type KVWrite struct {
+	Key   []byte
+	Value []byte
+}
+
+// KVStore is the narrow interface a key-value engine must
+// implement to back a Database (see NewKVDatabase). Locking,
+// transaction buffering, and encoding are handled by KVDatabase,
+// so a KVStore only needs to provide consistent snapshots
+// and atomic batch writes.
+type KVStore interface {
+	// Snapshot returns a consistent, read-only view
+	// of the store at the time of the call.
+	Snapshot() (KVSnapshot, error)
+
+	// Apply atomically applies all writes. The KVStore
+	// must not retain writes after Apply returns.
+	Apply(writes []*KVWrite) error
+
+	// Close shuts down the store.
+	Close() error
+}
+
+// KVSnapshot is a consistent, read-only view of a KVStore.
+type KVSnapshot interface {
+	// Get returns the value of key. The returned value
+	// must not be modified and is only valid until the
+	// snapshot is released.
+	Get(key []byte) (bool, []byte, error)
+
+	// Iterate calls worker for each key with prefix, starting at
+	// the smallest key >= seekStart. If reverse is true, keys are
+	// visited from greatest to least, starting at the greatest
+	// key <= seekStart. Any error returned by worker must be
+	// returned by Iterate.
+	Iterate(
+		prefix []byte,
+		seekStart []byte,
+		reverse bool,
+		worker func([]byte, []byte) error,
+	) error
+
+	// Release frees any resources held by the snapshot.
+	Release()
+}
+
+// KVDatabase implements the Database interface on top
+// of any KVStore.
+type KVDatabase struct {
+	store             KVStore
+	compressorEntries []*encoder.CompressorEntry
+
+	pool     *encoder.BufferPool
+	encoder  *encoder.Encoder
+	compress bool
+
+	writer       *utils.MutexMap
+	writerShards int
+
+	// readOnly determines if the database
+	// rejects all modifications.
+	readOnly bool
+
+	metrics Metrics
+
+	// Like BadgerDB, a write transaction fails to commit if any
+	// key it read or wrote was committed by another transaction
+	// after its snapshot was created. commits stores the version
+	// of the last commit of each key (only while a write transaction
+	// that could conflict with it is open) and readVersions counts
+	// the open write transactions created at each version.
+	commitLock    sync.Mutex
+	commitVersion uint64
+	commits       map[string]uint64
+	readVersions  map[uint64]int
+}
+
+// NewKVDatabase creates a new KVDatabase backed by store.
+func NewKVDatabase(
+	store KVStore,
+	storageOptions ...KVOption,
+) (Database, error) {
+	k := &KVDatabase{
+		store:        store,
+		pool:         encoder.NewBufferPool(),
+		compress:     true,
+		writerShards: utils.DefaultShards,
+		metrics:      noopMetrics{},
+		commits:      map[string]uint64{},
+		readVersions: map[uint64]int{},
+	}
+	for _, opt := range storageOptions {
+		opt(k)
+	}
+
+	// Initialize utils.MutexMap used to track granular
+	// write transactions.
+	k.writer = utils.NewMutexMap(k.writerShards)
+
+	encoder, err := encoder.NewEncoder(k.compressorEntries, k.pool, k.compress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrCompressorLoadFailed, err)
+	}
+	k.encoder = encoder
+
+	return k, nil
+}
+
+// Close closes the underlying KVStore.
+func (k *KVDatabase) Close(ctx context.Context) error {
+	if err := k.store.Close(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrDBCloseFailed, err)
+	}
+
+	return nil
+}
+
+// Encoder returns the KVDatabase encoder.
+func (k *KVDatabase) Encoder() *encoder.Encoder {
+	return k.encoder
+}
+
+// KVTransaction buffers writes on top of a KVSnapshot
+// and implements the Transaction interface. Buffered
+// writes are applied atomically on Commit.
+type KVTransaction struct {
+	db       *KVDatabase
+	snapshot KVSnapshot
+	rwLock   sync.RWMutex
+
+	// err is populated if the snapshot could
+	// not be created. It is returned by all
+	// subsequent calls.
+	err error
+
+	writable bool
+	writes   map[string]*KVWrite
+
+	// readVersion is the commit version of snapshot. If
+	// tracked is true, the transaction is counted in
+	// readVersions and all keys it reads are stored in
+	// reads (protected by readsLock) to detect conflicts.
+	readVersion uint64
+	tracked     bool
+	reads       map[string]struct{}
+	readsLock   sync.Mutex
+
+	holdGlobal bool
+	identifier string
+
+	// Values passed to Set with reclaimValue are
+	// returned to the BufferPool once the transaction
+	// is committed or discarded.
+	buffersToReclaim []*bytes.Buffer
+}
+
+func (k *KVDatabase) newTransaction(writable bool) *KVTransaction {
+	txn := &KVTransaction{
+		db:               k,
+		writable:         writable,
+		writes:           map[string]*KVWrite{},
+		reads:            map[string]struct{}{},
+		buffersToReclaim: []*bytes.Buffer{},
+	}
+
+	// The snapshot is created while holding commitLock
+	// so that it matches readVersion exactly.
+	k.commitLock.Lock()
+	defer k.commitLock.Unlock()
+
+	snapshot, err := k.store.Snapshot()
+	if err != nil {
+		txn.err = fmt.Errorf("%w: %v", storageErrs.ErrSnapshotFailed, err)
+		return txn
+	}
+	txn.snapshot = snapshot
+	txn.readVersion = k.commitVersion
+
+	if writable {
+		txn.tracked = true
+		k.readVersions[txn.readVersion]++
+	}
+
+	return txn
+}
+
+// untrack stops tracking a write transaction created
+// at readVersion and prunes any commits that can no
+// longer conflict with an open transaction.
+func (k *KVDatabase) untrack(readVersion uint64) {
+	k.commitLock.Lock()
+	defer k.commitLock.Unlock()
+
+	k.readVersions[readVersion]--
+	if k.readVersions[readVersion] > 0 {
+		return
+	}
+	delete(k.readVersions, readVersion)
+
+	if len(k.readVersions) == 0 {
+		k.commits = map[string]uint64{}
+		return
+	}
+
+	oldest := k.commitVersion
+	for version := range k.readVersions {
+		if version < oldest {
+			oldest = version
+		}
+	}
+
+	for key, version := range k.commits {
+		if version <= oldest {
+			delete(k.commits, key)
+		}
+	}
+}
+
+// conflicts returns true if any key read or written by t
+// was committed after t's snapshot was created. The caller
+// must hold commitLock.
+func (k *KVDatabase) conflicts(t *KVTransaction) bool {
+	for key := range t.writes {
+		if k.commits[key] > t.readVersion {
+			return true
+		}
+	}
+
+	t.readsLock.Lock()
+	defer t.readsLock.Unlock()
+	for key := range t.reads {
+		if k.commits[key] > t.readVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordRead stores a key read by a write transaction
+// (so a conflicting commit can be detected).
+func (t *KVTransaction) recordRead(key []byte) {
+	if !t.tracked {
+		return
+	}
+
+	t.readsLock.Lock()
+	defer t.readsLock.Unlock()
+
+	t.reads[string(key)] = struct{}{}
+}
+
+// Transaction creates a new exclusive write KVTransaction.
+func (k *KVDatabase) Transaction(
+	ctx context.Context,
+) Transaction {
+	k.writer.GLock()
+
+	txn := k.newTransaction(true)
+	txn.holdGlobal = true
+
+	return txn
+}
+
+// ReadTransaction creates a new read KVTransaction.
+func (k *KVDatabase) ReadTransaction(
+	ctx context.Context,
+) Transaction {
+	return k.newTransaction(false)
+}
+
+// WriteTransaction creates a new write KVTransaction
+// for a particular identifier.
+func (k *KVDatabase) WriteTransaction(
+	ctx context.Context,
+	identifier string,
+	priority bool,
+) Transaction {
+	k.writer.Lock(identifier, priority)
+
+	txn := k.newTransaction(true)
+	txn.identifier = identifier
+
+	return txn
+}
+
+// close releases the snapshot, reclaims buffers, and
+// releases all locks. It is safe to call multiple times.
+//
+// Like a BadgerTransaction, a closed KVTransaction can
+// still be read from (see withSnapshot).
+func (t *KVTransaction) close() {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if t.snapshot != nil {
+		t.snapshot.Release()
+		t.snapshot = nil
+	}
+
+	t.writes = map[string]*KVWrite{}
+	if t.tracked {
+		t.tracked = false
+		t.db.untrack(t.readVersion)
+	}
+
+	for _, buf := range t.buffersToReclaim {
+		t.db.pool.Put(buf)
+	}
+	t.buffersToReclaim = nil
+
+	if t.holdGlobal {
+		t.holdGlobal = false
+		t.db.writer.GUnlock()
+	}
+	if len(t.identifier) > 0 {
+		t.db.writer.Unlock(t.identifier)
+		t.identifier = ""
+	}
+}
+
+// withSnapshot calls fn with the transaction snapshot. If the
+// transaction is closed, fn is called with a new snapshot
+// (that includes any committed writes) instead.
+func (t *KVTransaction) withSnapshot(fn func(KVSnapshot) error) error {
+	if t.snapshot != nil {
+		return fn(t.snapshot)
+	}
+
+	snapshot, err := t.db.store.Snapshot()
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrSnapshotFailed, err)
+	}
+	defer snapshot.Release()
+
+	return fn(snapshot)
+}
+
+// Commit attempts to apply all buffered writes and
+// close the transaction.
+func (t *KVTransaction) Commit(context.Context) error {
+	defer t.close()
+
+	t.rwLock.RLock()
+	defer t.rwLock.RUnlock()
+
+	if t.err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCommitFailed, t.err)
+	}
+
+	if len(t.writes) == 0 {
+		return nil
+	}
+
+	writes := make([]*KVWrite, 0, len(t.writes))
+	for _, write := range t.writes {
+		writes = append(writes, write)
+	}
+	sort.Slice(writes, func(i, j int) bool {
+		return bytes.Compare(writes[i].Key, writes[j].Key) < 0
+	})
+
+	t.db.commitLock.Lock()
+	defer t.db.commitLock.Unlock()
+
+	if t.db.conflicts(t) {
+		t.db.metrics.ObserveConflict()
+		return fmt.Errorf(
+			"%w: a key was committed by another transaction",
+			storageErrs.ErrTransactionConflict,
+		)
+	}
+
+	start := time.Now()
+	err := t.db.store.Apply(writes)
+	t.db.metrics.ObserveCommit(time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCommitFailed, err)
+	}
+
+	t.db.commitVersion++
+	for _, write := range writes {
+		t.db.commits[string(write.Key)] = t.db.commitVersion
+	}
+
+	return nil
+}
+
+// Discard discards an open transaction. All transactions
+// must be either discarded or committed.
+func (t *KVTransaction) Discard(context.Context) {
+	t.close()
+}
+
+func (t *KVTransaction) write(key []byte, value []byte) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	if t.db.readOnly {
+		return storageErrs.ErrDatabaseReadOnly
+	}
+
+	if !t.writable {
+		return storageErrs.ErrReadOnlyTxn
+	}
+
+	thisK := make([]byte, len(key))
+	copy(thisK, key)
+	t.writes[string(thisK)] = &KVWrite{Key: thisK, Value: value}
+
+	return nil
+}
+
+// Set changes the value of the key to the value within a transaction.
+func (t *KVTransaction) Set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+) error {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	if reclaimValue {
+		t.buffersToReclaim = append(
+			t.buffersToReclaim,
+			bytes.NewBuffer(value),
+		)
+	}
+
+	// A nil value would be interpreted
+	// as a delete by the KVStore.
+	if value == nil {
+		value = []byte{}
+	}
+
+	start := time.Now()
+	if err := t.write(key, value); err != nil {
+		return err
+	}
+
+	t.db.metrics.ObserveOperation(WriteOperation, keyNamespace(key), len(value), time.Since(start))
+	return nil
+}
+
+// Get accesses the value of the key within a transaction.
+// It is up to the caller to reclaim any memory returned.
+func (t *KVTransaction) Get(
+	ctx context.Context,
+	key []byte,
+) (bool, []byte, error) {
+	t.rwLock.RLock()
+	defer t.rwLock.RUnlock()
+
+	if t.err != nil {
+		return false, nil, t.err
+	}
+
+	var v []byte
+	if write, ok := t.writes[string(key)]; ok {
+		if write.Value == nil {
+			return false, nil, nil
+		}
+
+		v = write.Value
+	} else {
+		t.recordRead(key)
+
+		start := time.Now()
+		var exists bool
+		err := t.withSnapshot(func(snapshot KVSnapshot) error {
+			var err error
+			exists, v, err = snapshot.Get(key)
+			if err != nil || !exists {
+				return err
+			}
+
+			// Copy the value while the
+			// snapshot is still valid.
+			value := t.db.pool.Get()
+			_, err = value.Write(v)
+			v = value.Bytes()
+
+			return err
+		})
+		if err != nil {
+			return false, nil, fmt.Errorf("%w: %v", storageErrs.ErrGetFailed, err)
+		}
+
+		t.db.metrics.ObserveOperation(ReadOperation, keyNamespace(key), len(v), time.Since(start))
+		return exists, v, nil
+	}
+
+	value := t.db.pool.Get()
+	if _, err := value.Write(v); err != nil {
+		return false, nil, err
+	}
+
+	return true, value.Bytes(), nil
+}
+
+// Delete removes the key and its value within the transaction.
+func (t *KVTransaction) Delete(ctx context.Context, key []byte) error {
+	t.rwLock.Lock()
+	defer t.rwLock.Unlock()
+
+	start := time.Now()
+	if err := t.write(key, nil); err != nil {
+		return err
+	}
+
+	t.db.metrics.ObserveOperation(DeleteOperation, keyNamespace(key), 0, time.Since(start))
+	return nil
+}
+
+// pendingKeys returns the sorted keys of all buffered
+// writes that would be visited by a Scan.
+func (t *KVTransaction) pendingKeys(
+	prefix []byte,
+	seekStart []byte,
+	reverse bool,
+) []string {
+	keys := []string{}
+	for key, write := range t.writes {
+		if !bytes.HasPrefix(write.Key, prefix) {
+			continue
+		}
+
+		cmp := bytes.Compare(write.Key, seekStart)
+		if (!reverse && cmp < 0) || (reverse && cmp > 0) {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if reverse {
+			return keys[i] > keys[j]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+// Scan calls a worker for each item in a scan instead
+// of reading all items into memory. Buffered writes
+// are merged with the snapshot.
+func (t *KVTransaction) Scan(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool, // reverse == true means greatest to least
+) (int, error) {
+	t.rwLock.RLock()
+	defer t.rwLock.RUnlock()
+
+	if t.err != nil {
+		return -1, t.err
+	}
+
+	start := time.Now()
+	entries := 0
+	scanned := 0
+	visit := func(k []byte, v []byte) error {
+		scanned += len(v)
+		if err := worker(k, v); err != nil {
+			return fmt.Errorf("%w: worker failed for key %s", err, string(k))
+		}
+
+		entries++
+		if logEntries && entries%logModulo == 0 {
+			log.Printf("scanned %d entries for %s\n", entries, string(prefix))
+		}
+
+		return nil
+	}
+
+	// visitPending visits all buffered writes that
+	// come before key (or all if key is nil).
+	pending := t.pendingKeys(prefix, seekStart, reverse)
+	visitPending := func(key *string) error {
+		for len(pending) > 0 {
+			next := pending[0]
+			if key != nil && (next == *key || (next > *key) != reverse) {
+				return nil
+			}
+
+			pending = pending[1:]
+			write := t.writes[next]
+			if write.Value == nil {
+				continue
+			}
+
+			if err := visit(write.Key, write.Value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err := t.withSnapshot(func(snapshot KVSnapshot) error {
+		return snapshot.Iterate(prefix, seekStart, reverse, func(k []byte, v []byte) error {
+			key := string(k)
+			if err := visitPending(&key); err != nil {
+				return err
+			}
+
+			t.recordRead(k)
+			write, ok := t.writes[key]
+			if !ok {
+				return visit(k, v)
+			}
+
+			pending = pending[1:]
+			if write.Value == nil {
+				return nil
+			}
+
+			return visit(write.Key, write.Value)
+		})
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	if err := visitPending(nil); err != nil {
+		return -1, err
+	}
+
+	t.db.metrics.ObserveOperation(ScanOperation, keyNamespace(prefix), scanned, time.Since(start))
+	return entries, nil
+}
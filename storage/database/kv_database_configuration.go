@@ -0,0 +1,66 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
+)
+
+// KVOption is used to overwrite default values in
+// KVDatabase construction. Any Option not provided
+// falls back to the default value.
+type KVOption func(k *KVDatabase)
+
+// WithKVCompressorEntries provides zstd dictionaries
+// for given namespaces.
+func WithKVCompressorEntries(entries []*encoder.CompressorEntry) KVOption {
+	return func(k *KVDatabase) {
+		k.compress = true
+		k.compressorEntries = entries
+	}
+}
+
+// WithoutKVCompression disables zstd compression.
+func WithoutKVCompression() KVOption {
+	return func(k *KVDatabase) {
+		k.compress = false
+	}
+}
+
+// WithKVWriterShards overrides the default shards used
+// in the writer utils.MutexMap. It is recommended
+// to set this value to your write concurrency to prevent
+// lock contention.
+func WithKVWriterShards(shards int) KVOption {
+	return func(k *KVDatabase) {
+		k.writerShards = shards
+	}
+}
+
+// WithKVReadOnly rejects any attempt to modify
+// the database with ErrDatabaseReadOnly.
+func WithKVReadOnly() KVOption {
+	return func(k *KVDatabase) {
+		k.readOnly = true
+	}
+}
+
+// WithKVMetrics provides Metrics that are invoked
+// with instrumentation of all database activity.
+func WithKVMetrics(metrics Metrics) KVOption {
+	return func(k *KVDatabase) {
+		k.metrics = metrics
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// kvBackends returns constructors for
+// each KVStore-backed Database.
+func kvBackends() map[string]func(context.Context, string) (Database, error) {
+	return map[string]func(context.Context, string) (Database, error){
+		"memory": func(ctx context.Context, dir string) (Database, error) {
+			return NewMemoryDatabase(ctx)
+		},
+		"pebble": func(ctx context.Context, dir string) (Database, error) {
+			return NewPebbleDatabase(ctx, dir, nil)
+		},
+	}
+}
+
+func scanKeys(
+	ctx context.Context,
+	t *testing.T,
+	txn Transaction,
+	prefix string,
+	seekStart string,
+	reverse bool,
+) []string {
+	keys := []string{}
+	entries, err := txn.Scan(
+		ctx,
+		[]byte(prefix),
+		[]byte(seekStart),
+		func(k []byte, v []byte) error {
+			assert.Equal(t, "v"+string(k), string(v))
+			keys = append(keys, string(k))
+			return nil
+		},
+		false,
+		reverse,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), entries)
+
+	return keys
+}
+
+func TestKVDatabase(t *testing.T) {
+	for name, newDatabase := range kvBackends() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			newDir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(newDir)
+
+			database, err := newDatabase(ctx, newDir)
+			assert.NoError(t, err)
+			defer database.Close(ctx)
+
+			t.Run("No key exists", func(t *testing.T) {
+				txn := database.ReadTransaction(ctx)
+				exists, value, err := txn.Get(ctx, []byte("hello"))
+				assert.False(t, exists)
+				assert.Nil(t, value)
+				assert.NoError(t, err)
+				txn.Discard(ctx)
+			})
+
+			t.Run("Set and get within a transaction", func(t *testing.T) {
+				txn := database.Transaction(ctx)
+				assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+
+				exists, value, err := txn.Get(ctx, []byte("hello"))
+				assert.True(t, exists)
+				assert.Equal(t, []byte("hola"), value)
+				assert.NoError(t, err)
+
+				// Ensure tx does not affect db
+				txn2 := database.ReadTransaction(ctx)
+				exists, value, err = txn2.Get(ctx, []byte("hello"))
+				assert.False(t, exists)
+				assert.Nil(t, value)
+				assert.NoError(t, err)
+
+				assert.NoError(t, txn.Commit(ctx))
+
+				// Ensure a snapshot is not
+				// affected by later commits.
+				exists, value, err = txn2.Get(ctx, []byte("hello"))
+				assert.False(t, exists)
+				assert.Nil(t, value)
+				assert.NoError(t, err)
+				txn2.Discard(ctx)
+
+				txn3 := database.ReadTransaction(ctx)
+				exists, value, err = txn3.Get(ctx, []byte("hello"))
+				assert.True(t, exists)
+				assert.Equal(t, []byte("hola"), value)
+				assert.NoError(t, err)
+				txn3.Discard(ctx)
+			})
+
+			t.Run("Read-only transaction", func(t *testing.T) {
+				txn := database.ReadTransaction(ctx)
+				err := txn.Set(ctx, []byte("hello"), []byte("world"), false)
+				assert.ErrorIs(t, err, storageErrs.ErrReadOnlyTxn)
+				assert.ErrorIs(t, txn.Delete(ctx, []byte("hello")), storageErrs.ErrReadOnlyTxn)
+				txn.Discard(ctx)
+			})
+
+			t.Run("Discard transaction", func(t *testing.T) {
+				txn := database.WriteTransaction(ctx, "hello", true)
+				assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("world"), true))
+				txn.Discard(ctx)
+
+				txn2 := database.ReadTransaction(ctx)
+				exists, value, err := txn2.Get(ctx, []byte("hello"))
+				txn2.Discard(ctx)
+				assert.True(t, exists)
+				assert.Equal(t, []byte("hola"), value)
+				assert.NoError(t, err)
+			})
+
+			t.Run("Delete within a transaction", func(t *testing.T) {
+				txn := database.WriteTransaction(ctx, "hello", false)
+				assert.NoError(t, txn.Delete(ctx, []byte("hello")))
+
+				exists, value, err := txn.Get(ctx, []byte("hello"))
+				assert.False(t, exists)
+				assert.Nil(t, value)
+				assert.NoError(t, err)
+				assert.NoError(t, txn.Commit(ctx))
+
+				// Closed transactions can still be read from
+				exists, value, err = txn.Get(ctx, []byte("hello"))
+				assert.False(t, exists)
+				assert.Nil(t, value)
+				assert.NoError(t, err)
+			})
+
+			t.Run("Scan", func(t *testing.T) {
+				txn := database.Transaction(ctx)
+				for i := 0; i < 10; i++ {
+					k := fmt.Sprintf("test/%d", i)
+					assert.NoError(t, txn.Set(ctx, []byte(k), []byte("v"+k), true))
+				}
+				for i := 0; i < 10; i++ {
+					k := fmt.Sprintf("testing/%d", i)
+					assert.NoError(t, txn.Set(ctx, []byte(k), []byte("v"+k), true))
+				}
+				assert.NoError(t, txn.Commit(ctx))
+
+				txn = database.ReadTransaction(ctx)
+				assert.Equal(
+					t,
+					[]string{"test/5", "test/6", "test/7", "test/8", "test/9"},
+					scanKeys(ctx, t, txn, "test/", "test/5", false),
+				)
+				assert.Equal(
+					t,
+					[]string{"test/3", "test/2", "test/1", "test/0"},
+					scanKeys(ctx, t, txn, "test/", "test/3", true),
+				)
+				assert.Len(t, scanKeys(ctx, t, txn, "test/", "test/", false), 10)
+				assert.Len(t, scanKeys(ctx, t, txn, "test/", "test/\xff", true), 10)
+				txn.Discard(ctx)
+			})
+
+			t.Run("Scan with pending writes", func(t *testing.T) {
+				txn := database.Transaction(ctx)
+				assert.NoError(t, txn.Delete(ctx, []byte("test/2")))
+				assert.NoError(t, txn.Delete(ctx, []byte("test/9")))
+				assert.NoError(t, txn.Set(ctx, []byte("test/0"), []byte("vtest/0"), false))
+				assert.NoError(t, txn.Set(ctx, []byte("test/10"), []byte("vtest/10"), false))
+				assert.NoError(t, txn.Set(ctx, []byte("test/99"), []byte("vtest/99"), false))
+				assert.NoError(t, txn.Set(ctx, []byte("testing/10"), []byte("vtesting/10"), false))
+
+				expected := []string{
+					"test/0",
+					"test/1",
+					"test/10",
+					"test/3",
+					"test/4",
+					"test/5",
+					"test/6",
+					"test/7",
+					"test/8",
+					"test/99",
+				}
+				assert.Equal(t, expected, scanKeys(ctx, t, txn, "test/", "test/", false))
+				assert.Equal(
+					t,
+					[]string{"test/10", "test/1", "test/0"},
+					scanKeys(ctx, t, txn, "test/", "test/2", true),
+				)
+
+				// Ensure pending writes are not visible
+				// outside of the transaction.
+				txn2 := database.ReadTransaction(ctx)
+				assert.Len(t, scanKeys(ctx, t, txn2, "test/", "test/", false), 10)
+				assert.NoError(t, txn.Commit(ctx))
+				txn2.Discard(ctx)
+
+				txn3 := database.ReadTransaction(ctx)
+				assert.Equal(t, expected, scanKeys(ctx, t, txn3, "test/", "test/", false))
+				txn3.Discard(ctx)
+			})
+
+			t.Run("Scan worker error", func(t *testing.T) {
+				txn := database.ReadTransaction(ctx)
+				entries, err := txn.Scan(
+					ctx,
+					[]byte("test/"),
+					[]byte("test/"),
+					func(k []byte, v []byte) error {
+						return storageErrs.ErrScanWorkerFailed
+					},
+					false,
+					false,
+				)
+				assert.Equal(t, -1, entries)
+				assert.ErrorIs(t, err, storageErrs.ErrScanWorkerFailed)
+				txn.Discard(ctx)
+			})
+
+			t.Run("Write conflicts", func(t *testing.T) {
+				setup := database.Transaction(ctx)
+				assert.NoError(t, setup.Set(ctx, []byte("conflict/a"), []byte("0"), false))
+				assert.NoError(t, setup.Commit(ctx))
+
+				// Both transactions read and update the same key.
+				txn1 := database.WriteTransaction(ctx, "1", false)
+				txn2 := database.WriteTransaction(ctx, "2", false)
+				txn3 := database.WriteTransaction(ctx, "3", false)
+				for _, txn := range []Transaction{txn1, txn2} {
+					exists, _, err := txn.Get(ctx, []byte("conflict/a"))
+					assert.True(t, exists)
+					assert.NoError(t, err)
+					assert.NoError(t, txn.Set(ctx, []byte("conflict/a"), []byte("1"), false))
+				}
+
+				// A transaction that only reads and writes
+				// other keys does not conflict.
+				exists, _, err := txn3.Get(ctx, []byte("conflict/c"))
+				assert.False(t, exists)
+				assert.NoError(t, err)
+				assert.NoError(t, txn3.Set(ctx, []byte("conflict/b"), []byte("1"), false))
+
+				assert.NoError(t, txn1.Commit(ctx))
+				assert.ErrorIs(t, txn2.Commit(ctx), storageErrs.ErrTransactionConflict)
+				assert.NoError(t, txn3.Commit(ctx))
+
+				// A transaction created after the commit
+				// does not conflict.
+				txn4 := database.WriteTransaction(ctx, "2", false)
+				_, _, err = txn4.Get(ctx, []byte("conflict/a"))
+				assert.NoError(t, err)
+				assert.NoError(t, txn4.Set(ctx, []byte("conflict/a"), []byte("2"), false))
+				assert.NoError(t, txn4.Commit(ctx))
+
+				kv := database.(*KVDatabase)
+				assert.Len(t, kv.commits, 0)
+				assert.Len(t, kv.readVersions, 0)
+			})
+		})
+	}
+}
+
+func TestPebbleDatabase_Reopen(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := NewPebbleDatabase(ctx, newDir, nil, WithoutKVCompression())
+	assert.NoError(t, err)
+
+	txn := database.Transaction(ctx)
+	assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), true))
+	assert.NoError(t, txn.Commit(ctx))
+	assert.NoError(t, database.Close(ctx))
+
+	database, err = NewPebbleDatabase(ctx, newDir, nil, WithoutKVCompression())
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	txn = database.ReadTransaction(ctx)
+	exists, value, err := txn.Get(ctx, []byte("hello"))
+	assert.True(t, exists)
+	assert.Equal(t, []byte("hola"), value)
+	assert.NoError(t, err)
+	txn.Discard(ctx)
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	assert.Equal(t, []byte("test0"), prefixUpperBound([]byte("test/")))
+	assert.Equal(t, []byte{0x01}, prefixUpperBound([]byte{0x00, 0xff}))
+	assert.Nil(t, prefixUpperBound([]byte{0xff, 0xff}))
+	assert.Nil(t, prefixUpperBound([]byte{}))
+}
@@ -0,0 +1,351 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// DefaultMaintenanceInterval is the default time
+	// between scheduled maintenance runs.
+	DefaultMaintenanceInterval = 1 * time.Hour
+
+	// DefaultMaintenanceCheckInterval is the default time
+	// between checks of MaintenanceStats against any
+	// configured thresholds.
+	DefaultMaintenanceCheckInterval = 1 * time.Minute
+
+	// DefaultTombstoneMinimumKeys is the default number of
+	// keys that must be written or deleted since the last
+	// maintenance run before the tombstone ratio is considered.
+	DefaultTombstoneMinimumKeys = 10000
+
+	// DefaultDiskUsageCooldown is the default minimum time
+	// between maintenance runs caused by disk usage. Maintenance
+	// can't always bring disk usage below the threshold (ex: when
+	// most of the database is live data), so without a cooldown
+	// maintenance would run at every check.
+	DefaultDiskUsageCooldown = 30 * time.Minute
+)
+
+// MaintenanceTrigger describes why maintenance was run.
+type MaintenanceTrigger string
+
+const (
+	// ScheduledMaintenance is run when the maintenance
+	// interval has elapsed since the last run.
+	ScheduledMaintenance MaintenanceTrigger = "schedule"
+
+	// DiskUsageMaintenance is run when the disk usage
+	// of the database exceeds the configured threshold.
+	DiskUsageMaintenance MaintenanceTrigger = "disk usage"
+
+	// TombstoneMaintenance is run when the ratio of deleted
+	// keys exceeds the configured threshold.
+	TombstoneMaintenance MaintenanceTrigger = "tombstone ratio"
+
+	// ManualMaintenance is run when Trigger is called.
+	ManualMaintenance MaintenanceTrigger = "manual"
+)
+
+// MaintenanceStats are the statistics a Maintainable
+// database reports to determine if maintenance should run.
+type MaintenanceStats struct {
+	// DiskUsage is the size of the database on disk in bytes.
+	DiskUsage int64 `json:"disk_usage"`
+
+	// Writes and Deletes are the number of keys set and
+	// deleted since the last maintenance run.
+	Writes  int64 `json:"writes"`
+	Deletes int64 `json:"deletes"`
+}
+
+// TombstoneRatio returns the fraction of keys modified since
+// the last maintenance run that were deleted.
+func (s *MaintenanceStats) TombstoneRatio() float64 {
+	total := s.Writes + s.Deletes
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Deletes) / float64(total)
+}
+
+// MaintenanceResult summarizes a single maintenance run.
+type MaintenanceResult struct {
+	ReclaimedBytes     int64         `json:"reclaimed_bytes"`
+	ValueLogsRewritten int           `json:"value_logs_rewritten"`
+	Compacted          bool          `json:"compacted"`
+	ExpiredEntries     int           `json:"expired_entries"`
+	Duration           time.Duration `json:"duration"`
+}
+
+// Maintainable is implemented by any Database that can
+// reclaim storage from deleted or overwritten keys.
+type Maintainable interface {
+	MaintenanceStats(ctx context.Context) (*MaintenanceStats, error)
+	RunMaintenance(ctx context.Context, compact bool) (*MaintenanceResult, error)
+}
+
+// MaintenanceMetrics are the cumulative metrics
+// of all maintenance run by a MaintenanceScheduler.
+type MaintenanceMetrics struct {
+	Runs           int64 `json:"runs"`
+	Failures       int64 `json:"failures"`
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+
+	LastRun      time.Time          `json:"last_run"`
+	LastTrigger  MaintenanceTrigger `json:"last_trigger"`
+	LastDuration time.Duration      `json:"last_duration"`
+	LastError    string             `json:"last_error,omitempty"`
+}
+
+// MaintenanceScheduler runs maintenance on a Maintainable
+// database on a schedule or when thresholds are exceeded.
+type MaintenanceScheduler struct {
+	db Maintainable
+
+	interval                time.Duration
+	checkInterval           time.Duration
+	diskUsageThreshold      int64
+	diskUsageCooldown       time.Duration
+	tombstoneRatioThreshold float64
+	tombstoneMinimumKeys    int64
+	compact                 bool
+
+	// runLock ensures only one maintenance
+	// run occurs at a time.
+	runLock sync.Mutex
+
+	metricsLock sync.Mutex
+	metrics     *MaintenanceMetrics
+	lastRun     time.Time
+
+	// lastCompleted is the time the last maintenance run
+	// completed (zero if maintenance has not run yet).
+	lastCompleted time.Time
+}
+
+// MaintenanceOption is used to overwrite default values in
+// MaintenanceScheduler construction. Any Option not provided
+// falls back to the default value.
+type MaintenanceOption func(m *MaintenanceScheduler)
+
+// WithMaintenanceInterval overrides the DefaultMaintenanceInterval.
+// If interval is 0, maintenance is only run when a threshold
+// is exceeded or Trigger is called.
+func WithMaintenanceInterval(interval time.Duration) MaintenanceOption {
+	return func(m *MaintenanceScheduler) {
+		m.interval = interval
+	}
+}
+
+// WithMaintenanceCheckInterval overrides the
+// DefaultMaintenanceCheckInterval.
+func WithMaintenanceCheckInterval(interval time.Duration) MaintenanceOption {
+	return func(m *MaintenanceScheduler) {
+		m.checkInterval = interval
+	}
+}
+
+// WithDiskUsageThreshold runs maintenance whenever the disk usage
+// of the database exceeds threshold bytes (at most once per
+// disk usage cooldown, see WithDiskUsageCooldown).
+func WithDiskUsageThreshold(threshold int64) MaintenanceOption {
+	return func(m *MaintenanceScheduler) {
+		m.diskUsageThreshold = threshold
+	}
+}
+
+// WithDiskUsageCooldown overrides the DefaultDiskUsageCooldown.
+func WithDiskUsageCooldown(cooldown time.Duration) MaintenanceOption {
+	return func(m *MaintenanceScheduler) {
+		m.diskUsageCooldown = cooldown
+	}
+}
+
+// WithTombstoneRatioThreshold runs maintenance whenever the ratio
+// of deleted keys to modified keys since the last run exceeds ratio
+// and at least minimumKeys have been modified.
+func WithTombstoneRatioThreshold(ratio float64, minimumKeys int64) MaintenanceOption {
+	return func(m *MaintenanceScheduler) {
+		m.tombstoneRatioThreshold = ratio
+		m.tombstoneMinimumKeys = minimumKeys
+	}
+}
+
+// WithMaintenanceCompaction compacts the database
+// on each maintenance run.
+func WithMaintenanceCompaction() MaintenanceOption {
+	return func(m *MaintenanceScheduler) {
+		m.compact = true
+	}
+}
+
+// NewMaintenanceScheduler returns a new *MaintenanceScheduler. If
+// the provided Database does not implement Maintainable,
+// ErrMaintenanceUnsupported is returned.
+func NewMaintenanceScheduler(
+	db Database,
+	options ...MaintenanceOption,
+) (*MaintenanceScheduler, error) {
+	maintainable, ok := db.(Maintainable)
+	if !ok {
+		return nil, storageErrs.ErrMaintenanceUnsupported
+	}
+
+	m := &MaintenanceScheduler{
+		db:                   maintainable,
+		interval:             DefaultMaintenanceInterval,
+		checkInterval:        DefaultMaintenanceCheckInterval,
+		diskUsageCooldown:    DefaultDiskUsageCooldown,
+		tombstoneMinimumKeys: DefaultTombstoneMinimumKeys,
+		metrics:              &MaintenanceMetrics{},
+		lastRun:              time.Now(),
+	}
+
+	for _, opt := range options {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+// Metrics returns a copy of the cumulative MaintenanceMetrics.
+func (m *MaintenanceScheduler) Metrics() *MaintenanceMetrics {
+	m.metricsLock.Lock()
+	defer m.metricsLock.Unlock()
+
+	metrics := *m.metrics
+	return &metrics
+}
+
+// Check returns the MaintenanceTrigger that should cause
+// maintenance to run, if any. If no maintenance is needed,
+// an empty MaintenanceTrigger is returned.
+func (m *MaintenanceScheduler) Check(ctx context.Context) (MaintenanceTrigger, error) {
+	m.metricsLock.Lock()
+	lastRun := m.lastRun
+	lastCompleted := m.lastCompleted
+	m.metricsLock.Unlock()
+
+	if m.interval > 0 && time.Since(lastRun) >= m.interval {
+		return ScheduledMaintenance, nil
+	}
+
+	if m.diskUsageThreshold <= 0 && m.tombstoneRatioThreshold <= 0 {
+		return "", nil
+	}
+
+	stats, err := m.db.MaintenanceStats(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", storageErrs.ErrMaintenanceStatsFailed, err)
+	}
+
+	if m.diskUsageThreshold > 0 && stats.DiskUsage > m.diskUsageThreshold &&
+		(lastCompleted.IsZero() || time.Since(lastCompleted) >= m.diskUsageCooldown) {
+		return DiskUsageMaintenance, nil
+	}
+
+	if m.tombstoneRatioThreshold > 0 &&
+		stats.Writes+stats.Deletes >= m.tombstoneMinimumKeys &&
+		stats.TombstoneRatio() >= m.tombstoneRatioThreshold {
+		return TombstoneMaintenance, nil
+	}
+
+	return "", nil
+}
+
+// Trigger runs maintenance immediately. If maintenance is
+// already running, Trigger waits for it to complete
+// before starting another run.
+func (m *MaintenanceScheduler) Trigger(ctx context.Context) (*MaintenanceResult, error) {
+	return m.run(ctx, ManualMaintenance)
+}
+
+func (m *MaintenanceScheduler) run(
+	ctx context.Context,
+	trigger MaintenanceTrigger,
+) (*MaintenanceResult, error) {
+	m.runLock.Lock()
+	defer m.runLock.Unlock()
+
+	start := time.Now()
+	result, err := m.db.RunMaintenance(ctx, m.compact)
+
+	m.metricsLock.Lock()
+	defer m.metricsLock.Unlock()
+
+	m.lastRun = time.Now()
+	m.lastCompleted = m.lastRun
+	m.metrics.Runs++
+	m.metrics.LastRun = start
+	m.metrics.LastTrigger = trigger
+	m.metrics.LastDuration = time.Since(start)
+	if err != nil {
+		m.metrics.Failures++
+		m.metrics.LastError = err.Error()
+		return nil, err
+	}
+
+	m.metrics.LastError = ""
+	m.metrics.ReclaimedBytes += result.ReclaimedBytes
+
+	return result, nil
+}
+
+// Run checks if maintenance should be run every check
+// interval until the context is canceled. Maintenance
+// failures are logged and retried at the next check.
+func (m *MaintenanceScheduler) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		if err := utils.ContextSleep(ctx, m.checkInterval); err != nil {
+			return err
+		}
+
+		trigger, err := m.Check(ctx)
+		if err != nil {
+			log.Printf("unable to check if maintenance is needed: %s\n", err.Error())
+			continue
+		}
+
+		if len(trigger) == 0 {
+			continue
+		}
+
+		result, err := m.run(ctx, trigger)
+		if err != nil {
+			log.Printf("error during maintenance (%s): %s\n", trigger, err.Error())
+			continue
+		}
+
+		log.Printf(
+			"completed maintenance (%s): reclaimed %d bytes in %s\n",
+			trigger,
+			result.ReclaimedBytes,
+			result.Duration,
+		)
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,257 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+var _ Maintainable = (*BadgerDatabase)(nil)
+
+type fakeMaintainable struct {
+	Database
+
+	lock  sync.Mutex
+	stats *MaintenanceStats
+	err   error
+	runs  int
+}
+
+func (f *fakeMaintainable) MaintenanceStats(ctx context.Context) (*MaintenanceStats, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	stats := *f.stats
+	return &stats, nil
+}
+
+func (f *fakeMaintainable) RunMaintenance(
+	ctx context.Context,
+	compact bool,
+) (*MaintenanceResult, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.runs++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	f.stats = &MaintenanceStats{DiskUsage: f.stats.DiskUsage / 2}
+	return &MaintenanceResult{ReclaimedBytes: 10, Compacted: compact}, nil
+}
+
+func (f *fakeMaintainable) getRuns() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.runs
+}
+
+func TestBadgerMaintenance(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := NewBadgerDatabase(
+		ctx,
+		newDir,
+		WithIndexCacheSize(TinyIndexCacheSize),
+		WithoutPeriodicGC(),
+	)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	maintainable := database.(Maintainable)
+
+	txn := database.Transaction(ctx)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, txn.Set(ctx, []byte(fmt.Sprintf("key/%d", i)), []byte("value"), false))
+	}
+	assert.NoError(t, txn.Commit(ctx))
+
+	// Discarded transactions are not counted.
+	txn = database.Transaction(ctx)
+	assert.NoError(t, txn.Set(ctx, []byte("discarded"), []byte("value"), false))
+	txn.Discard(ctx)
+
+	txn = database.Transaction(ctx)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, txn.Delete(ctx, []byte(fmt.Sprintf("key/%d", i))))
+	}
+	assert.NoError(t, txn.Commit(ctx))
+
+	stats, err := maintainable.MaintenanceStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), stats.Writes)
+	assert.Equal(t, int64(5), stats.Deletes)
+	assert.InDelta(t, float64(1)/3, stats.TombstoneRatio(), 0.0001)
+	assert.True(t, stats.DiskUsage > 0)
+
+	result, err := maintainable.RunMaintenance(ctx, true)
+	assert.NoError(t, err)
+	assert.True(t, result.Compacted)
+	assert.True(t, result.ReclaimedBytes >= 0)
+
+	stats, err = maintainable.MaintenanceStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Writes)
+	assert.Equal(t, int64(0), stats.Deletes)
+	assert.Equal(t, float64(0), stats.TombstoneRatio())
+
+	// Ensure data is still readable after maintenance.
+	txn = database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+	exists, _, err := txn.Get(ctx, []byte("key/4"))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	exists, value, err := txn.Get(ctx, []byte("key/5"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestMaintenanceScheduler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unsupported database", func(t *testing.T) {
+		m, err := NewMaintenanceScheduler(&KVDatabase{})
+		assert.True(t, errors.Is(err, storageErrs.ErrMaintenanceUnsupported))
+		assert.Nil(t, m)
+	})
+
+	t.Run("check thresholds", func(t *testing.T) {
+		db := &fakeMaintainable{stats: &MaintenanceStats{DiskUsage: 100}}
+		m, err := NewMaintenanceScheduler(
+			db,
+			WithMaintenanceInterval(0),
+			WithDiskUsageThreshold(150),
+			WithTombstoneRatioThreshold(0.5, 10),
+		)
+		assert.NoError(t, err)
+
+		trigger, err := m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, trigger)
+
+		db.stats = &MaintenanceStats{DiskUsage: 100, Writes: 2, Deletes: 6}
+		trigger, err = m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, trigger)
+
+		db.stats = &MaintenanceStats{DiskUsage: 100, Writes: 4, Deletes: 6}
+		trigger, err = m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, TombstoneMaintenance, trigger)
+
+		db.stats = &MaintenanceStats{DiskUsage: 200}
+		trigger, err = m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, DiskUsageMaintenance, trigger)
+	})
+
+	t.Run("disk usage cooldown", func(t *testing.T) {
+		db := &fakeMaintainable{stats: &MaintenanceStats{DiskUsage: 1000}}
+		m, err := NewMaintenanceScheduler(
+			db,
+			WithMaintenanceInterval(0),
+			WithDiskUsageThreshold(150),
+			WithDiskUsageCooldown(20*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		_, err = m.run(ctx, DiskUsageMaintenance)
+		assert.NoError(t, err)
+
+		// Disk usage is still above the threshold
+		// but maintenance just ran.
+		trigger, err := m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, trigger)
+
+		time.Sleep(25 * time.Millisecond)
+		trigger, err = m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, DiskUsageMaintenance, trigger)
+	})
+
+	t.Run("check schedule", func(t *testing.T) {
+		db := &fakeMaintainable{stats: &MaintenanceStats{}}
+		m, err := NewMaintenanceScheduler(db, WithMaintenanceInterval(time.Millisecond))
+		assert.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+		trigger, err := m.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, ScheduledMaintenance, trigger)
+	})
+
+	t.Run("manual trigger", func(t *testing.T) {
+		db := &fakeMaintainable{stats: &MaintenanceStats{}}
+		m, err := NewMaintenanceScheduler(db, WithMaintenanceCompaction())
+		assert.NoError(t, err)
+
+		result, err := m.Trigger(ctx)
+		assert.NoError(t, err)
+		assert.True(t, result.Compacted)
+
+		db.err = errors.New("bad")
+		result, err = m.Trigger(ctx)
+		assert.EqualError(t, err, "bad")
+		assert.Nil(t, result)
+
+		metrics := m.Metrics()
+		assert.Equal(t, int64(2), metrics.Runs)
+		assert.Equal(t, int64(1), metrics.Failures)
+		assert.Equal(t, int64(10), metrics.ReclaimedBytes)
+		assert.Equal(t, ManualMaintenance, metrics.LastTrigger)
+		assert.Equal(t, "bad", metrics.LastError)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		db := &fakeMaintainable{stats: &MaintenanceStats{DiskUsage: 1000}}
+		m, err := NewMaintenanceScheduler(
+			db,
+			WithMaintenanceInterval(0),
+			WithMaintenanceCheckInterval(time.Millisecond),
+			WithDiskUsageThreshold(150),
+		)
+		assert.NoError(t, err)
+
+		runCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		err = m.Run(runCtx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		// Disk usage is still above the threshold after the
+		// first run, but the cooldown prevents another run.
+		assert.Equal(t, 1, db.getRuns())
+		metrics := m.Metrics()
+		assert.Equal(t, int64(1), metrics.Runs)
+		assert.Equal(t, DiskUsageMaintenance, metrics.LastTrigger)
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryStore is a KVStore that keeps all data in memory.
+// Each Apply copies the entire store so that snapshots
+// are never modified, which makes it best suited for
+// tests and small datasets.
+type memoryStore struct {
+	lock sync.RWMutex
+	data map[string][]byte
+}
+
+// memorySnapshot is an immutable view
+// of a memoryStore.
+type memorySnapshot struct {
+	data map[string][]byte
+}
+
+// NewMemoryDatabase creates a new Database
+// that keeps all data in memory. This is
+// useful for testing.
+func NewMemoryDatabase(
+	ctx context.Context,
+	storageOptions ...KVOption,
+) (Database, error) {
+	return NewKVDatabase(
+		&memoryStore{data: map[string][]byte{}},
+		storageOptions...,
+	)
+}
+
+func (m *memoryStore) Snapshot() (KVSnapshot, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return &memorySnapshot{data: m.data}, nil
+}
+
+func (m *memoryStore) Apply(writes []*KVWrite) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	data := make(map[string][]byte, len(m.data)+len(writes))
+	for k, v := range m.data {
+		data[k] = v
+	}
+
+	for _, write := range writes {
+		if write.Value == nil {
+			delete(data, string(write.Key))
+			continue
+		}
+
+		value := make([]byte, len(write.Value))
+		copy(value, write.Value)
+		data[string(write.Key)] = value
+	}
+	m.data = data
+
+	return nil
+}
+
+func (m *memoryStore) Close() error {
+	return nil
+}
+
+func (m *memorySnapshot) Get(key []byte) (bool, []byte, error) {
+	value, ok := m.data[string(key)]
+	return ok, value, nil
+}
+
+func (m *memorySnapshot) Iterate(
+	prefix []byte,
+	seekStart []byte,
+	reverse bool,
+	worker func([]byte, []byte) error,
+) error {
+	keys := []string{}
+	for k := range m.data {
+		key := []byte(k)
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+
+		cmp := bytes.Compare(key, seekStart)
+		if (!reverse && cmp < 0) || (reverse && cmp > 0) {
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if reverse {
+			return keys[i] > keys[j]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	for _, k := range keys {
+		if err := worker([]byte(k), m.data[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *memorySnapshot) Release() {}
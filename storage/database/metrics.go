@@ -0,0 +1,110 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"time"
+)
+
+// MetricsOperation is a type of database operation
+// reported to Metrics.
+type MetricsOperation string
+
+const (
+	// ReadOperation is reported when a key is read.
+	ReadOperation MetricsOperation = "read"
+
+	// WriteOperation is reported when a key is set.
+	WriteOperation MetricsOperation = "write"
+
+	// DeleteOperation is reported when a key is deleted.
+	DeleteOperation MetricsOperation = "delete"
+
+	// ScanOperation is reported when a prefix is scanned.
+	ScanOperation MetricsOperation = "scan"
+)
+
+// Metrics is invoked by a Database to report instrumentation.
+// Implementations must be safe to call concurrently.
+type Metrics interface {
+	// ObserveOperation is called after each read, write, delete,
+	// or scan with the namespace of the key (or scan prefix),
+	// the number of value bytes read or written, and how long
+	// the operation took.
+	ObserveOperation(
+		operation MetricsOperation,
+		namespace string,
+		bytes int,
+		duration time.Duration,
+	)
+
+	// ObserveCommit is called after each transaction commit
+	// with the error returned by the commit (if any).
+	ObserveCommit(duration time.Duration, err error)
+
+	// ObserveConflict is called when a transaction
+	// fails to commit because of a conflict.
+	ObserveConflict()
+
+	// ObserveGC is called after each garbage collection
+	// run with the number of bytes reclaimed (if known).
+	ObserveGC(duration time.Duration, reclaimedBytes int64, err error)
+}
+
+// CacheStats are the cumulative hits and
+// misses of a database cache.
+type CacheStats struct {
+	Name   string `json:"name"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// HitRatio returns the fraction of cache
+// lookups that were hits.
+func (c *CacheStats) HitRatio() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(c.Hits) / float64(total)
+}
+
+// CacheStatsProvider is implemented by any Database
+// that can report statistics about its caches.
+type CacheStatsProvider interface {
+	CacheStats() []*CacheStats
+}
+
+// noopMetrics is used by a Database when
+// no Metrics are provided.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveOperation(MetricsOperation, string, int, time.Duration) {}
+func (noopMetrics) ObserveCommit(time.Duration, error)                            {}
+func (noopMetrics) ObserveConflict()                                              {}
+func (noopMetrics) ObserveGC(time.Duration, int64, error)                         {}
+
+// keyNamespace returns the namespace of a key (all
+// bytes before the first "/"). We report metrics
+// by namespace instead of key to limit cardinality.
+func keyNamespace(key []byte) string {
+	if i := bytes.IndexByte(key, '/'); i >= 0 {
+		return string(key[:i])
+	}
+
+	return string(key)
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+var _ CacheStatsProvider = (*BadgerDatabase)(nil)
+
+// recordingMetrics is a Metrics that counts the
+// operations and commits reported to it.
+type recordingMetrics struct {
+	lock       sync.Mutex
+	operations map[string]int
+	bytes      map[string]int
+	commits    int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		operations: map[string]int{},
+		bytes:      map[string]int{},
+	}
+}
+
+func (r *recordingMetrics) ObserveOperation(
+	operation MetricsOperation,
+	namespace string,
+	bytes int,
+	duration time.Duration,
+) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	label := string(operation) + " " + namespace
+	r.operations[label]++
+	r.bytes[label] += bytes
+}
+
+func (r *recordingMetrics) ObserveCommit(duration time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err == nil {
+		r.commits++
+	}
+}
+
+func (r *recordingMetrics) ObserveConflict()                      {}
+func (r *recordingMetrics) ObserveGC(time.Duration, int64, error) {}
+
+func TestCacheStatsHitRatio(t *testing.T) {
+	assert.Equal(t, float64(0), (&CacheStats{}).HitRatio())
+	assert.Equal(t, 0.75, (&CacheStats{Hits: 3, Misses: 1}).HitRatio())
+}
+
+func TestKeyNamespace(t *testing.T) {
+	assert.Equal(t, "balance", keyNamespace([]byte("balance/abc/def")))
+	assert.Equal(t, "block", keyNamespace([]byte("block")))
+	assert.Equal(t, "", keyNamespace([]byte("/abc")))
+}
+
+func TestDatabaseMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	badgerMetrics := newRecordingMetrics()
+	badgerDB, err := NewBadgerDatabase(
+		ctx,
+		newDir,
+		WithIndexCacheSize(TinyIndexCacheSize),
+		WithMetrics(badgerMetrics),
+	)
+	assert.NoError(t, err)
+	defer badgerDB.Close(ctx)
+
+	memoryMetrics := newRecordingMetrics()
+	memoryDB, err := NewMemoryDatabase(ctx, WithKVMetrics(memoryMetrics))
+	assert.NoError(t, err)
+	defer memoryDB.Close(ctx)
+
+	tests := map[string]struct {
+		db      Database
+		metrics *recordingMetrics
+	}{
+		"badger": {db: badgerDB, metrics: badgerMetrics},
+		"memory": {db: memoryDB, metrics: memoryMetrics},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			txn := test.db.Transaction(ctx)
+			assert.NoError(t, txn.Set(ctx, []byte("balance/a"), []byte("hello"), true))
+			assert.NoError(t, txn.Set(ctx, []byte("balance/b"), []byte("world"), true))
+			assert.NoError(t, txn.Delete(ctx, []byte("block/a")))
+			assert.NoError(t, txn.Commit(ctx))
+
+			txn = test.db.ReadTransaction(ctx)
+			exists, _, err := txn.Get(ctx, []byte("balance/a"))
+			assert.NoError(t, err)
+			assert.True(t, exists)
+			_, err = txn.Scan(
+				ctx,
+				[]byte("balance/"),
+				[]byte("balance/"),
+				func(k []byte, v []byte) error { return nil },
+				false,
+				false,
+			)
+			assert.NoError(t, err)
+			txn.Discard(ctx)
+
+			m := test.metrics
+			m.lock.Lock()
+			defer m.lock.Unlock()
+			assert.Equal(t, 2, m.operations["write balance"])
+			assert.Equal(t, 10, m.bytes["write balance"])
+			assert.Equal(t, 1, m.operations["delete block"])
+			assert.Equal(t, 1, m.operations["read balance"])
+			assert.Equal(t, 10, m.bytes["scan balance"])
+			assert.Equal(t, 1, m.commits)
+		})
+	}
+}
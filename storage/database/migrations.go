@@ -0,0 +1,349 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// schemaVersionKey stores the number of migrations
+	// applied to a Database.
+	schemaVersionKey = "schema-version"
+
+	// MigrationBatchSize is the number of keys modified in
+	// each database transaction by RenamePrefix, DeletePrefix,
+	// and TransformValues. Migrations are applied in batches
+	// to avoid exceeding the maximum transaction size.
+	MigrationBatchSize = 1000
+)
+
+var errMigrationBatchFull = errors.New("migration batch full")
+
+// MigrationFunc modifies the layout of a Database. A
+// MigrationFunc may commit any number of transactions.
+//
+// If a MigrationFunc is interrupted (ex: the process is killed),
+// it will be run again from the start the next time migrations
+// are applied, so it must be idempotent.
+type MigrationFunc func(ctx context.Context, db Database) error
+
+// Migration is a single versioned change to the
+// layout of a Database. The version of a Migration
+// is its position in a list of migrations (starting
+// at 1).
+type Migration struct {
+	Description string
+
+	// Up applies the migration.
+	Up MigrationFunc
+
+	// Down reverts the migration. If Down is nil, the
+	// migration is irreversible.
+	Down MigrationFunc
+}
+
+// SchemaVersion returns the number of migrations that
+// have been applied to db. A database that has never been
+// migrated has a schema version of 0.
+func SchemaVersion(ctx context.Context, db Database) (int64, error) {
+	txn := db.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	exists, value, err := txn.Get(ctx, []byte(schemaVersionKey))
+	if err != nil {
+		return -1, fmt.Errorf("%w: %v", storageErrs.ErrSchemaVersionReadFailed, err)
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("%w: %v", storageErrs.ErrSchemaVersionReadFailed, err)
+	}
+
+	return version, nil
+}
+
+func setSchemaVersion(ctx context.Context, db Database, version int64) error {
+	txn := db.Transaction(ctx)
+	defer txn.Discard(ctx)
+
+	value := []byte(strconv.FormatInt(version, 10))
+	if err := txn.Set(ctx, []byte(schemaVersionKey), value, false); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrSchemaVersionWriteFailed, err)
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrSchemaVersionWriteFailed, err)
+	}
+
+	return nil
+}
+
+// Migrate applies any migrations that have not yet been
+// applied to db (in order) and returns the schema version
+// of db. Migrate must be called before db is used by any
+// other caller.
+func Migrate(ctx context.Context, db Database, migrations []*Migration) (int64, error) {
+	return MigrateTo(ctx, db, migrations, int64(len(migrations)))
+}
+
+// MigrateTo applies (or reverts) migrations until the
+// schema version of db is target. If any migration that
+// must be reverted is irreversible, no migration is reverted.
+func MigrateTo(
+	ctx context.Context,
+	db Database,
+	migrations []*Migration,
+	target int64,
+) (int64, error) {
+	for i, migration := range migrations {
+		if migration == nil || migration.Up == nil {
+			return -1, fmt.Errorf(
+				"%w: migration %d has no up function",
+				storageErrs.ErrMigrationInvalid,
+				i+1,
+			)
+		}
+	}
+
+	latest := int64(len(migrations))
+	if target < 0 || target > latest {
+		return -1, fmt.Errorf(
+			"%w: target version %d must be between 0 and %d",
+			storageErrs.ErrMigrationInvalid,
+			target,
+			latest,
+		)
+	}
+
+	version, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return -1, err
+	}
+
+	if version > latest {
+		return -1, fmt.Errorf(
+			"%w: schema version %d is newer than supported version %d",
+			storageErrs.ErrSchemaVersionUnsupported,
+			version,
+			latest,
+		)
+	}
+
+	for i := version; i > target; i-- {
+		if migrations[i-1].Down == nil {
+			return -1, fmt.Errorf(
+				"%w: migration %d (%s)",
+				storageErrs.ErrMigrationIrreversible,
+				i,
+				migrations[i-1].Description,
+			)
+		}
+	}
+
+	for ; version < target; version++ {
+		migration := migrations[version]
+		log.Printf("Applying migration %d: %s\n", version+1, migration.Description)
+		if err := migration.Up(ctx, db); err != nil {
+			return -1, fmt.Errorf(
+				"%w: migration %d (%s): %v",
+				storageErrs.ErrMigrationFailed,
+				version+1,
+				migration.Description,
+				err,
+			)
+		}
+
+		if err := setSchemaVersion(ctx, db, version+1); err != nil {
+			return -1, err
+		}
+	}
+
+	for ; version > target; version-- {
+		migration := migrations[version-1]
+		log.Printf("Reverting migration %d: %s\n", version, migration.Description)
+		if err := migration.Down(ctx, db); err != nil {
+			return -1, fmt.Errorf(
+				"%w: revert migration %d (%s): %v",
+				storageErrs.ErrMigrationFailed,
+				version,
+				migration.Description,
+				err,
+			)
+		}
+
+		if err := setSchemaVersion(ctx, db, version-1); err != nil {
+			return -1, err
+		}
+	}
+
+	return version, nil
+}
+
+// migrateBatches invokes handler with each key and value
+// with prefix. Each batch of MigrationBatchSize keys is
+// handled in a separate database transaction.
+func migrateBatches(
+	ctx context.Context,
+	db Database,
+	prefix []byte,
+	handler func(context.Context, Transaction, []byte, []byte) error,
+) error {
+	seekStart := prefix
+	for {
+		keys := [][]byte{}
+		values := [][]byte{}
+		txn := db.ReadTransaction(ctx)
+		_, err := txn.Scan(
+			ctx,
+			prefix,
+			seekStart,
+			func(k []byte, v []byte) error {
+				// Keys and values are only valid
+				// for the duration of the worker.
+				keys = append(keys, append([]byte{}, k...))
+				values = append(values, append([]byte{}, v...))
+				if len(keys) == MigrationBatchSize {
+					return errMigrationBatchFull
+				}
+
+				return nil
+			},
+			false,
+			false,
+		)
+		txn.Discard(ctx)
+		if err != nil && !errors.Is(err, errMigrationBatchFull) {
+			return fmt.Errorf("%w: unable to scan %s", err, string(prefix))
+		}
+
+		if len(keys) == 0 {
+			return nil
+		}
+
+		txn = db.Transaction(ctx)
+		for i := range keys {
+			if err := handler(ctx, txn, keys[i], values[i]); err != nil {
+				txn.Discard(ctx)
+				return err
+			}
+		}
+
+		if err := txn.Commit(ctx); err != nil {
+			return err
+		}
+
+		if len(keys) < MigrationBatchSize {
+			return nil
+		}
+
+		seekStart = append(keys[len(keys)-1], 0x00)
+	}
+}
+
+// RenamePrefix returns a MigrationFunc that moves all keys
+// with oldPrefix to newPrefix (ex: to rename a namespace).
+// Values are moved without being decoded, so values compressed
+// with a namespace dictionary must also be re-encoded (see
+// TransformValues).
+//
+// The prefixes must not overlap, so that the migration
+// can be resumed if it is interrupted.
+func RenamePrefix(oldPrefix []byte, newPrefix []byte) MigrationFunc {
+	return func(ctx context.Context, db Database) error {
+		if bytes.HasPrefix(oldPrefix, newPrefix) || bytes.HasPrefix(newPrefix, oldPrefix) {
+			return fmt.Errorf(
+				"%w: prefixes %s and %s overlap",
+				storageErrs.ErrMigrationInvalid,
+				string(oldPrefix),
+				string(newPrefix),
+			)
+		}
+
+		return migrateBatches(
+			ctx,
+			db,
+			oldPrefix,
+			func(ctx context.Context, txn Transaction, key []byte, value []byte) error {
+				newKey := append(append([]byte{}, newPrefix...), key[len(oldPrefix):]...)
+				if err := txn.Set(ctx, newKey, value, false); err != nil {
+					return err
+				}
+
+				return txn.Delete(ctx, key)
+			},
+		)
+	}
+}
+
+// DeletePrefix returns a MigrationFunc that deletes all
+// keys with prefix (ex: to drop an index before it is
+// rebuilt).
+func DeletePrefix(prefix []byte) MigrationFunc {
+	return func(ctx context.Context, db Database) error {
+		return migrateBatches(
+			ctx,
+			db,
+			prefix,
+			func(ctx context.Context, txn Transaction, key []byte, _ []byte) error {
+				return txn.Delete(ctx, key)
+			},
+		)
+	}
+}
+
+// TransformValues returns a MigrationFunc that replaces the
+// value of each key with prefix with the result of transform
+// (ex: to re-encode values in a new format). If transform
+// returns a nil value, the key is deleted.
+//
+// transform must be idempotent (ex: by returning values
+// already in the new format unchanged) because an interrupted
+// migration is run again from the start.
+func TransformValues(
+	prefix []byte,
+	transform func(key []byte, value []byte) ([]byte, error),
+) MigrationFunc {
+	return func(ctx context.Context, db Database) error {
+		return migrateBatches(
+			ctx,
+			db,
+			prefix,
+			func(ctx context.Context, txn Transaction, key []byte, value []byte) error {
+				newValue, err := transform(key, value)
+				if err != nil {
+					return fmt.Errorf("%w: unable to transform %s", err, string(key))
+				}
+
+				if newValue == nil {
+					return txn.Delete(ctx, key)
+				}
+
+				return txn.Set(ctx, key, newValue, false)
+			},
+		)
+	}
+}
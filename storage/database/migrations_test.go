@@ -0,0 +1,193 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+func countPrefix(ctx context.Context, t *testing.T, db Database, prefix string) int {
+	txn := db.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	count, err := txn.Scan(
+		ctx,
+		[]byte(prefix),
+		[]byte(prefix),
+		func(k []byte, v []byte) error { return nil },
+		false,
+		false,
+	)
+	assert.NoError(t, err)
+
+	return count
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := NewMemoryDatabase(ctx)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	// We write more keys than fit in a single
+	// batch to ensure all batches are migrated.
+	keys := MigrationBatchSize*2 + 10
+	txn := db.Transaction(ctx)
+	for i := 0; i < keys; i++ {
+		assert.NoError(t, txn.Set(
+			ctx,
+			[]byte(fmt.Sprintf("old/%05d", i)),
+			[]byte(fmt.Sprintf("%d", i)),
+			false,
+		))
+	}
+	assert.NoError(t, txn.Set(ctx, []byte("index/1"), []byte("1"), false))
+	assert.NoError(t, txn.Commit(ctx))
+
+	migrations := []*Migration{
+		{
+			Description: "rename old namespace",
+			Up:          RenamePrefix([]byte("old/"), []byte("new/")),
+			Down:        RenamePrefix([]byte("new/"), []byte("old/")),
+		},
+		{
+			Description: "re-encode values",
+			Up: TransformValues([]byte("new/"), func(k []byte, v []byte) ([]byte, error) {
+				if bytes.HasPrefix(v, []byte("v2:")) {
+					return v, nil
+				}
+
+				return append([]byte("v2:"), v...), nil
+			}),
+			Down: TransformValues([]byte("new/"), func(k []byte, v []byte) ([]byte, error) {
+				return bytes.TrimPrefix(v, []byte("v2:")), nil
+			}),
+		},
+		{
+			Description: "drop index",
+			Up:          DeletePrefix([]byte("index/")),
+		},
+	}
+
+	t.Run("unmigrated database", func(t *testing.T) {
+		version, err := SchemaVersion(ctx, db)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), version)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		version, err := MigrateTo(ctx, db, migrations, 4)
+		assert.True(t, errors.Is(err, storageErrs.ErrMigrationInvalid))
+		assert.Equal(t, int64(-1), version)
+
+		version, err = Migrate(ctx, db, []*Migration{{Description: "missing up"}})
+		assert.True(t, errors.Is(err, storageErrs.ErrMigrationInvalid))
+		assert.Equal(t, int64(-1), version)
+	})
+
+	t.Run("migrate up", func(t *testing.T) {
+		version, err := Migrate(ctx, db, migrations)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+
+		version, err = SchemaVersion(ctx, db)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+
+		assert.Equal(t, 0, countPrefix(ctx, t, db, "old/"))
+		assert.Equal(t, keys, countPrefix(ctx, t, db, "new/"))
+		assert.Equal(t, 0, countPrefix(ctx, t, db, "index/"))
+
+		txn := db.ReadTransaction(ctx)
+		exists, value, err := txn.Get(ctx, []byte(fmt.Sprintf("new/%05d", keys-1)))
+		txn.Discard(ctx)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, fmt.Sprintf("v2:%d", keys-1), string(value))
+
+		// Migrating again is a no-op
+		version, err = Migrate(ctx, db, migrations)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+	})
+
+	t.Run("irreversible migration", func(t *testing.T) {
+		version, err := MigrateTo(ctx, db, migrations, 0)
+		assert.True(t, errors.Is(err, storageErrs.ErrMigrationIrreversible))
+		assert.Equal(t, int64(-1), version)
+
+		version, err = SchemaVersion(ctx, db)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+	})
+
+	t.Run("migrate down", func(t *testing.T) {
+		migrations[2].Down = func(context.Context, Database) error { return nil }
+		version, err := MigrateTo(ctx, db, migrations, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), version)
+
+		assert.Equal(t, keys, countPrefix(ctx, t, db, "old/"))
+		assert.Equal(t, 0, countPrefix(ctx, t, db, "new/"))
+
+		txn := db.ReadTransaction(ctx)
+		exists, value, err := txn.Get(ctx, []byte("old/00010"))
+		txn.Discard(ctx)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, "10", string(value))
+	})
+
+	t.Run("failed migration", func(t *testing.T) {
+		failure := []*Migration{
+			migrations[0],
+			{
+				Description: "fail",
+				Up: func(context.Context, Database) error {
+					return errors.New("boom")
+				},
+			},
+		}
+		version, err := Migrate(ctx, db, failure)
+		assert.True(t, errors.Is(err, storageErrs.ErrMigrationFailed))
+		assert.Contains(t, err.Error(), "boom")
+		assert.Equal(t, int64(-1), version)
+
+		// Successful migrations are recorded
+		version, err = SchemaVersion(ctx, db)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), version)
+	})
+
+	t.Run("unsupported schema version", func(t *testing.T) {
+		version, err := Migrate(ctx, db, nil)
+		assert.True(t, errors.Is(err, storageErrs.ErrSchemaVersionUnsupported))
+		assert.Equal(t, int64(-1), version)
+	})
+
+	t.Run("overlapping prefixes", func(t *testing.T) {
+		err := RenamePrefix([]byte("new/"), []byte("new/v2/"))(ctx, db)
+		assert.True(t, errors.Is(err, storageErrs.ErrMigrationInvalid))
+	})
+}
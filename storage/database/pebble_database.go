@@ -0,0 +1,251 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/cockroachdb/pebble"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// DefaultPebbleMemTableSize is 64 MB.
+	DefaultPebbleMemTableSize = 64 << 20
+
+	// DefaultPebbleCacheSize is 256 MB.
+	DefaultPebbleCacheSize = 256 << 20
+)
+
+// pebbleStore is a KVStore backed by Pebble
+// (https://github.com/cockroachdb/pebble). Unlike BadgerDB,
+// Pebble stores values inline in an LSM (no value log), so
+// it does not require periodic value log garbage collection.
+type pebbleStore struct {
+	db *pebble.DB
+}
+
+// pebbleSnapshot is a KVSnapshot
+// backed by a *pebble.Snapshot.
+type pebbleSnapshot struct {
+	snapshot *pebble.Snapshot
+}
+
+// DefaultPebbleOptions are the default options used to
+// initialize a new Pebble database. Callers can provide their
+// own settings to NewPebbleDatabase.
+func DefaultPebbleOptions() *pebble.Options {
+	return &pebble.Options{
+		Cache:        pebble.NewCache(DefaultPebbleCacheSize),
+		MemTableSize: DefaultPebbleMemTableSize,
+	}
+}
+
+// NewPebbleDatabase creates a new Database backed by Pebble
+// in dir. If pebbleOptions is nil, DefaultPebbleOptions
+// are used.
+func NewPebbleDatabase(
+	ctx context.Context,
+	dir string,
+	pebbleOptions *pebble.Options,
+	storageOptions ...KVOption,
+) (Database, error) {
+	defaultOptions := pebbleOptions == nil
+	if defaultOptions {
+		pebbleOptions = DefaultPebbleOptions()
+	}
+
+	db, err := pebble.Open(path.Clean(dir), pebbleOptions)
+
+	// Pebble takes its own reference to the cache, so we
+	// release the reference to any cache we created.
+	if defaultOptions {
+		pebbleOptions.Cache.Unref()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseOpenFailed, err)
+	}
+
+	database, err := NewKVDatabase(&pebbleStore{db: db}, storageOptions...)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return database, nil
+}
+
+// NewReadOnlyPebbleDatabase opens an existing Pebble database
+// in dir in read-only mode (see WithKVReadOnly). Pebble locks
+// its directory even when opened read-only, so this is usually
+// used to open a checkpoint created with KVDatabase.Checkpoint
+// while the original database is written by another process.
+func NewReadOnlyPebbleDatabase(
+	ctx context.Context,
+	dir string,
+	pebbleOptions *pebble.Options,
+	storageOptions ...KVOption,
+) (Database, error) {
+	if pebbleOptions == nil {
+		pebbleOptions = DefaultPebbleOptions()
+		defer pebbleOptions.Cache.Unref()
+	}
+	pebbleOptions.ReadOnly = true
+
+	return NewPebbleDatabase(
+		ctx,
+		dir,
+		pebbleOptions,
+		append(storageOptions, WithKVReadOnly())...,
+	)
+}
+
+func (p *pebbleStore) Snapshot() (KVSnapshot, error) {
+	return &pebbleSnapshot{snapshot: p.db.NewSnapshot()}, nil
+}
+
+func (p *pebbleStore) Apply(writes []*KVWrite) error {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+
+	for _, write := range writes {
+		var err error
+		if write.Value == nil {
+			err = batch.Delete(write.Key, nil)
+		} else {
+			err = batch.Set(write.Key, write.Value, nil)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrApplyWritesFailed, err)
+		}
+	}
+
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrApplyWritesFailed, err)
+	}
+
+	return nil
+}
+
+// Checkpoint creates a consistent copy of the store in
+// dir. Immutable files are hard-linked when possible, so
+// this is fast and uses little additional disk space.
+func (p *pebbleStore) Checkpoint(dir string) error {
+	if err := p.db.Checkpoint(dir, pebble.WithFlushedWAL()); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrCheckpointFailed, err)
+	}
+
+	return nil
+}
+
+func (p *pebbleStore) Close() error {
+	if err := p.db.Close(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrStoreCloseFailed, err)
+	}
+
+	return nil
+}
+
+func (p *pebbleSnapshot) Get(key []byte) (bool, []byte, error) {
+	v, closer, err := p.snapshot.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return false, nil, nil
+	} else if err != nil {
+		return false, nil, err
+	}
+	defer closer.Close()
+
+	// The value returned by Pebble is only valid
+	// until closer is closed.
+	value := make([]byte, len(v))
+	copy(value, v)
+
+	return true, value, nil
+}
+
+// prefixUpperBound returns the smallest key greater
+// than all keys with prefix (or nil if there is
+// no such key).
+func prefixUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			upper := make([]byte, i+1)
+			copy(upper, prefix)
+			upper[i]++
+
+			return upper
+		}
+	}
+
+	return nil
+}
+
+func (p *pebbleSnapshot) Iterate(
+	prefix []byte,
+	seekStart []byte,
+	reverse bool,
+	worker func([]byte, []byte) error,
+) error {
+	it, err := p.snapshot.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrIteratorFailed, err)
+	}
+	defer it.Close()
+
+	var valid bool
+	if reverse {
+		// SeekLT on the immediate successor of seekStart
+		// positions the iterator at the greatest key <= seekStart.
+		successor := make([]byte, len(seekStart)+1)
+		copy(successor, seekStart)
+		valid = it.SeekLT(successor)
+	} else {
+		valid = it.SeekGE(seekStart)
+	}
+
+	for ; valid; valid = step(it, reverse) {
+		if err := worker(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrIteratorFailed, err)
+	}
+
+	return nil
+}
+
+// step advances it in the scan direction.
+func step(it *pebble.Iterator, reverse bool) bool {
+	if reverse {
+		return it.Prev()
+	}
+
+	return it.Next()
+}
+
+func (p *pebbleSnapshot) Release() {
+	_ = p.snapshot.Close()
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// DefaultPostgresPageSize is the number of rows
+	// fetched at a time while iterating.
+	DefaultPostgresPageSize = 1000
+
+	postgresTable = "rosetta_kv"
+)
+
+// postgresStore is a KVStore backed by a PostgreSQL table. Values
+// are opaque to PostgreSQL, so data that should be queried with
+// SQL should be stored with the relational storage modules (ex:
+// modules.PostgresBlockStorage) instead.
+type postgresStore struct {
+	db       *sql.DB
+	pageSize int
+}
+
+// postgresSnapshot is a KVSnapshot backed by a read-only,
+// REPEATABLE READ PostgreSQL transaction.
+type postgresSnapshot struct {
+	store *postgresStore
+	tx    *sql.Tx
+}
+
+// NewPostgresDatabase creates a new Database backed by PostgreSQL.
+// db must be opened by the caller with a PostgreSQL driver (ex:
+// github.com/lib/pq or github.com/jackc/pgx/v4/stdlib). Any
+// PostgresMigrations that have not yet been applied are applied
+// before the Database is returned.
+func NewPostgresDatabase(
+	ctx context.Context,
+	db *sql.DB,
+	storageOptions ...KVOption,
+) (Database, error) {
+	if _, err := MigratePostgres(ctx, db); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseOpenFailed, err)
+	}
+
+	return NewKVDatabase(
+		&postgresStore{db: db, pageSize: DefaultPostgresPageSize},
+		storageOptions...,
+	)
+}
+
+// NewReadOnlyPostgresDatabase creates a new Database backed by
+// PostgreSQL in read-only mode (see WithKVReadOnly). Unlike
+// NewPostgresDatabase, no migrations are applied, so db can
+// be opened with a role that only has SELECT privileges. An
+// error is returned if the schema of db is not up to date.
+//
+// PostgreSQL provides isolation across processes, so any number
+// of read-only Databases can be opened while another process
+// writes to the same database.
+func NewReadOnlyPostgresDatabase(
+	ctx context.Context,
+	db *sql.DB,
+	storageOptions ...KVOption,
+) (Database, error) {
+	version, err := PostgresSchemaVersion(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrDatabaseOpenFailed, err)
+	}
+
+	if version != len(PostgresMigrations) {
+		return nil, fmt.Errorf(
+			"%w: schema version %d does not match supported version %d",
+			storageErrs.ErrDatabaseOpenFailed,
+			version,
+			len(PostgresMigrations),
+		)
+	}
+
+	return NewKVDatabase(
+		&postgresStore{db: db, pageSize: DefaultPostgresPageSize},
+		append(storageOptions, WithKVReadOnly())...,
+	)
+}
+
+func (p *postgresStore) Snapshot() (KVSnapshot, error) {
+	tx, err := p.db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &postgresSnapshot{store: p, tx: tx}, nil
+}
+
+func (p *postgresStore) Apply(writes []*KVWrite) error {
+	ctx := context.Background()
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrApplyWritesFailed, err)
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	for _, write := range writes {
+		if write.Value == nil {
+			_, err = tx.ExecContext(ctx, "DELETE FROM "+postgresTable+" WHERE key = $1", write.Key)
+		} else {
+			_, err = tx.ExecContext(
+				ctx,
+				"INSERT INTO "+postgresTable+" (key, value) VALUES ($1, $2) "+
+					"ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value",
+				write.Key,
+				write.Value,
+			)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrApplyWritesFailed, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrApplyWritesFailed, err)
+	}
+
+	return nil
+}
+
+func (p *postgresStore) Close() error {
+	if err := p.db.Close(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrStoreCloseFailed, err)
+	}
+
+	return nil
+}
+
+func (p *postgresSnapshot) Get(key []byte) (bool, []byte, error) {
+	var value []byte
+	err := p.tx.QueryRow("SELECT value FROM "+postgresTable+" WHERE key = $1", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil, nil
+	} else if err != nil {
+		return false, nil, err
+	}
+
+	return true, value, nil
+}
+
+// page returns up to pageSize rows starting at seekStart
+// (inclusive when inclusive is true).
+func (p *postgresSnapshot) page(
+	prefix []byte,
+	seekStart []byte,
+	inclusive bool,
+	reverse bool,
+) ([]*KVWrite, error) {
+	seekOp, order := ">", "ASC"
+	if reverse {
+		seekOp, order = "<", "DESC"
+	}
+	if inclusive {
+		seekOp += "="
+	}
+
+	query := "SELECT key, value FROM " + postgresTable + " WHERE key >= $1 AND key " + seekOp + " $2"
+	args := []interface{}{prefix, seekStart}
+	if upper := prefixUpperBound(prefix); upper != nil {
+		query += " AND key < $3"
+		args = append(args, upper)
+	}
+	query += fmt.Sprintf(" ORDER BY key %s LIMIT %d", order, p.store.pageSize)
+
+	rows, err := p.tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*KVWrite{}
+	for rows.Next() {
+		item := &KVWrite{}
+		if err := rows.Scan(&item.Key, &item.Value); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Iterate fetches rows a page at a time so that worker is
+// never invoked while a query is in progress (most drivers
+// do not support concurrent queries in a transaction).
+func (p *postgresSnapshot) Iterate(
+	prefix []byte,
+	seekStart []byte,
+	reverse bool,
+	worker func([]byte, []byte) error,
+) error {
+	inclusive := true
+	for {
+		items, err := p.page(prefix, seekStart, inclusive, reverse)
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrIteratorFailed, err)
+		}
+
+		for _, item := range items {
+			if err := worker(item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < p.store.pageSize {
+			return nil
+		}
+
+		seekStart = items[len(items)-1].Key
+		inclusive = false
+	}
+}
+
+func (p *postgresSnapshot) Release() {
+	_ = p.tx.Rollback()
+}
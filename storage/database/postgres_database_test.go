@@ -0,0 +1,231 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+func expectMigrations(mock sqlmock.Sqlmock, version int) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+		WithArgs(postgresMigrationLock).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS " + postgresMigrationsTable).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0)")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+	for i := version; i < len(PostgresMigrations); i++ {
+		mock.ExpectExec(regexp.QuoteMeta(PostgresMigrations[i])).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO " + postgresMigrationsTable).
+			WithArgs(i + 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+}
+
+func TestMigratePostgres(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new database", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		expectMigrations(mock, 0)
+		mock.ExpectCommit()
+
+		version, err := MigratePostgres(ctx, db)
+		assert.NoError(t, err)
+		assert.Equal(t, len(PostgresMigrations), version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("migrated database", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		expectMigrations(mock, len(PostgresMigrations))
+		mock.ExpectCommit()
+
+		version, err := MigratePostgres(ctx, db)
+		assert.NoError(t, err)
+		assert.Equal(t, len(PostgresMigrations), version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		expectMigrations(mock, len(PostgresMigrations)+1)
+		mock.ExpectRollback()
+
+		version, err := MigratePostgres(ctx, db)
+		assert.Error(t, err)
+		assert.Equal(t, -1, version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed migration", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+			WillReturnError(errors.New("connection reset"))
+		mock.ExpectRollback()
+
+		_, err = NewPostgresDatabase(ctx, db)
+		assert.ErrorIs(t, err, storageErrs.ErrDatabaseOpenFailed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresDatabase(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	expectMigrations(mock, len(PostgresMigrations))
+	mock.ExpectCommit()
+
+	database, err := NewPostgresDatabase(ctx, db, WithoutKVCompression())
+	assert.NoError(t, err)
+	database.(*KVDatabase).store.(*postgresStore).pageSize = 2
+
+	t.Run("Get", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT value FROM rosetta_kv WHERE key = $1")).
+			WithArgs([]byte("hello")).
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow([]byte("hola")))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT value FROM rosetta_kv WHERE key = $1")).
+			WithArgs([]byte("bye")).
+			WillReturnRows(sqlmock.NewRows([]string{"value"}))
+		mock.ExpectRollback()
+
+		txn := database.ReadTransaction(ctx)
+		exists, value, err := txn.Get(ctx, []byte("hello"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("hola"), value)
+
+		exists, value, err = txn.Get(ctx, []byte("bye"))
+		assert.NoError(t, err)
+		assert.False(t, exists)
+		assert.Nil(t, value)
+		txn.Discard(ctx)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM rosetta_kv WHERE key = $1")).
+			WithArgs([]byte("bye")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO rosetta_kv (key, value) VALUES ($1, $2)")).
+			WithArgs([]byte("hello"), []byte("hola")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), false))
+		assert.NoError(t, txn.Delete(ctx, []byte("bye")))
+		assert.NoError(t, txn.Commit(ctx))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Commit failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO rosetta_kv (key, value) VALUES ($1, $2)")).
+			WillReturnError(errors.New("disk full"))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		txn := database.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("hello"), []byte("hola"), false))
+		err := txn.Commit(ctx)
+		assert.ErrorIs(t, err, storageErrs.ErrCommitFailed)
+		assert.Contains(t, err.Error(), "disk full")
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		query := "SELECT key, value FROM rosetta_kv WHERE key >= $1 AND key %s $2 AND key < $3 ORDER BY key %s LIMIT 2"
+		rows := func(keys ...string) *sqlmock.Rows {
+			r := sqlmock.NewRows([]string{"key", "value"})
+			for _, k := range keys {
+				r.AddRow([]byte(k), []byte("v"+k))
+			}
+
+			return r
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, ">=", "ASC"))).
+			WithArgs([]byte("test/"), []byte("test/"), []byte("test0")).
+			WillReturnRows(rows("test/0", "test/1"))
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, ">", "ASC"))).
+			WithArgs([]byte("test/"), []byte("test/1"), []byte("test0")).
+			WillReturnRows(rows("test/2"))
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, "<=", "DESC"))).
+			WithArgs([]byte("test/"), []byte("test/1"), []byte("test0")).
+			WillReturnRows(rows("test/1", "test/0"))
+		mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, "<", "DESC"))).
+			WithArgs([]byte("test/"), []byte("test/0"), []byte("test0")).
+			WillReturnRows(rows())
+		mock.ExpectRollback()
+
+		txn := database.ReadTransaction(ctx)
+		assert.Equal(
+			t,
+			[]string{"test/0", "test/1", "test/2"},
+			scanKeys(ctx, t, txn, "test/", "test/", false),
+		)
+		assert.Equal(
+			t,
+			[]string{"test/1", "test/0"},
+			scanKeys(ctx, t, txn, "test/", "test/1", true),
+		)
+		txn.Discard(ctx)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Each pooled connection is closed separately, so
+	// we release extra idle connections first.
+	db.SetMaxIdleConns(1)
+	mock.ExpectClose()
+	assert.NoError(t, database.Close(ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
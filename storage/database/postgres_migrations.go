@@ -0,0 +1,179 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	// postgresMigrationLock is the pg_advisory_xact_lock key
+	// held while applying migrations. This prevents multiple
+	// processes from migrating the same database concurrently.
+	postgresMigrationLock = 0x726f7365747461
+
+	postgresMigrationsTable = "rosetta_schema_migrations"
+
+	// PostgresBlocksTable contains a row for each block
+	// stored by modules.PostgresBlockStorage.
+	PostgresBlocksTable = "rosetta_blocks"
+
+	// PostgresTransactionsTable contains a row for each
+	// transaction in PostgresBlocksTable.
+	PostgresTransactionsTable = "rosetta_transactions"
+
+	// PostgresBalancesTable contains the current balance of each
+	// account and currency stored by modules.PostgresBalanceStorage.
+	PostgresBalancesTable = "rosetta_balances"
+
+	// PostgresCoinsTable contains each unspent coin
+	// stored by modules.PostgresCoinStorage.
+	PostgresCoinsTable = "rosetta_coins"
+)
+
+// PostgresMigrations are the schema migrations applied (in order)
+// by MigratePostgres. A migration must never be modified once
+// released (add a new migration instead).
+var PostgresMigrations = []string{
+	// 1: key-value table used by postgresStore. BYTEA values
+	// are compared byte-wise, so keys are ordered the same
+	// way they are in other KVStores.
+	`CREATE TABLE IF NOT EXISTS ` + postgresTable + ` (
+		key BYTEA PRIMARY KEY,
+		value BYTEA NOT NULL
+	)`,
+
+	// 2-6: relational tables used by the PostgreSQL storage
+	// modules. Account and currency keys are the types.Hash
+	// of the identifier (so accounts that only differ by
+	// metadata are stored separately).
+	`CREATE TABLE IF NOT EXISTS ` + PostgresBlocksTable + ` (
+		block_index BIGINT PRIMARY KEY,
+		block_hash TEXT NOT NULL UNIQUE,
+		parent_index BIGINT NOT NULL,
+		parent_hash TEXT NOT NULL,
+		block_timestamp BIGINT NOT NULL,
+		metadata JSONB
+	)`,
+	`CREATE TABLE IF NOT EXISTS ` + PostgresTransactionsTable + ` (
+		block_index BIGINT NOT NULL
+			REFERENCES ` + PostgresBlocksTable + ` (block_index) ON DELETE CASCADE,
+		transaction_index INTEGER NOT NULL,
+		transaction_hash TEXT NOT NULL,
+		transaction_data JSONB NOT NULL,
+		PRIMARY KEY (block_index, transaction_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS rosetta_transactions_hash
+		ON ` + PostgresTransactionsTable + ` (transaction_hash)`,
+	`CREATE TABLE IF NOT EXISTS ` + PostgresBalancesTable + ` (
+		account_key TEXT NOT NULL,
+		currency_key TEXT NOT NULL,
+		address TEXT NOT NULL,
+		sub_account_address TEXT,
+		symbol TEXT NOT NULL,
+		decimals INTEGER NOT NULL,
+		value NUMERIC NOT NULL,
+		block_index BIGINT NOT NULL,
+		block_hash TEXT NOT NULL,
+		PRIMARY KEY (account_key, currency_key)
+	)`,
+	`CREATE TABLE IF NOT EXISTS ` + PostgresCoinsTable + ` (
+		coin_identifier TEXT PRIMARY KEY,
+		account_key TEXT NOT NULL,
+		address TEXT NOT NULL,
+		sub_account_address TEXT,
+		symbol TEXT NOT NULL,
+		decimals INTEGER NOT NULL,
+		value NUMERIC NOT NULL,
+		account JSONB NOT NULL,
+		coin JSONB NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS rosetta_coins_account
+		ON ` + PostgresCoinsTable + ` (account_key)`,
+}
+
+// PostgresSchemaVersion returns the number of PostgresMigrations
+// that have been applied to db without modifying it.
+func PostgresSchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(MAX(version), 0) FROM "+postgresMigrationsTable,
+	).Scan(&version); err != nil {
+		return -1, fmt.Errorf("%w: unable to get schema version", err)
+	}
+
+	return version, nil
+}
+
+// MigratePostgres applies any PostgresMigrations that
+// have not yet been applied to db in a single transaction.
+// It returns the schema version of db.
+func MigratePostgres(ctx context.Context, db *sql.DB) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("%w: unable to begin migration", err)
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationLock); err != nil {
+		return -1, fmt.Errorf("%w: unable to acquire migration lock", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+postgresMigrationsTable+` (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return -1, fmt.Errorf("%w: unable to create migrations table", err)
+	}
+
+	var version int
+	if err := tx.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(MAX(version), 0) FROM "+postgresMigrationsTable,
+	).Scan(&version); err != nil {
+		return -1, fmt.Errorf("%w: unable to get schema version", err)
+	}
+
+	if version > len(PostgresMigrations) {
+		return -1, fmt.Errorf(
+			"schema version %d is newer than supported version %d",
+			version,
+			len(PostgresMigrations),
+		)
+	}
+
+	for ; version < len(PostgresMigrations); version++ {
+		if _, err := tx.ExecContext(ctx, PostgresMigrations[version]); err != nil {
+			return -1, fmt.Errorf("%w: unable to apply migration %d", err, version+1)
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO "+postgresMigrationsTable+" (version) VALUES ($1)",
+			version+1,
+		); err != nil {
+			return -1, fmt.Errorf("%w: unable to record migration %d", err, version+1)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, fmt.Errorf("%w: unable to commit migrations", err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+// Multi-process access
+//
+// BadgerDB and Pebble lock their directory when opened, so no
+// other process can open a database (even in read-only mode) while
+// it is open for writing. To run a sidecar query service (ex: a
+// balance API or block explorer) against data written by another
+// process, use one of the following approaches:
+//
+//   - PostgreSQL: open the database written by NewPostgresDatabase
+//     with NewReadOnlyPostgresDatabase in each reader.
+//   - Pebble: periodically call Checkpoint in the writer and open the
+//     most recent checkpoint with NewReadOnlyPebbleDatabase.
+//     Checkpoints hard-link immutable files, so data is not copied.
+//   - BadgerDB: open a stopped database (or one restored with
+//     ImportSnapshot) with WithReadOnly. Any number of read-only
+//     processes can open the same BadgerDB.
+//
+// Read-only Databases return ErrDatabaseReadOnly from any
+// attempt to modify them.
+
+var _ Checkpointer = (*KVDatabase)(nil)
+
+// Checkpointer is implemented by any Database that can
+// create a consistent, openable copy of itself while
+// it is in use.
+type Checkpointer interface {
+	Checkpoint(ctx context.Context, dir string) error
+}
+
+// kvCheckpointer is implemented by any
+// KVStore that supports checkpoints.
+type kvCheckpointer interface {
+	Checkpoint(dir string) error
+}
+
+// Checkpoint creates a consistent copy of the database in dir
+// (which must not exist). It returns ErrCheckpointUnsupported
+// if the KVStore cannot create checkpoints.
+func (k *KVDatabase) Checkpoint(ctx context.Context, dir string) error {
+	checkpointer, ok := k.store.(kvCheckpointer)
+	if !ok {
+		return storageErrs.ErrCheckpointUnsupported
+	}
+
+	return checkpointer.Checkpoint(dir)
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"path"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func assertReadOnly(ctx context.Context, t *testing.T, database Database) {
+	txn := database.Transaction(ctx)
+	assert.True(t, errors.Is(
+		txn.Set(ctx, []byte("hello"), []byte("world"), true),
+		storageErrs.ErrDatabaseReadOnly,
+	))
+	assert.True(t, errors.Is(
+		txn.Delete(ctx, []byte("hello")),
+		storageErrs.ErrDatabaseReadOnly,
+	))
+	txn.Discard(ctx)
+}
+
+func assertValue(
+	ctx context.Context,
+	t *testing.T,
+	database Database,
+	key string,
+	expected string,
+) {
+	txn := database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	exists, value, err := txn.Get(ctx, []byte(key))
+	assert.NoError(t, err)
+	if len(expected) == 0 {
+		assert.False(t, exists)
+		return
+	}
+
+	assert.True(t, exists)
+	assert.Equal(t, expected, string(value))
+}
+
+func setValue(ctx context.Context, t *testing.T, database Database, key string, value string) {
+	txn := database.Transaction(ctx)
+	defer txn.Discard(ctx)
+
+	assert.NoError(t, txn.Set(ctx, []byte(key), []byte(value), true))
+	assert.NoError(t, txn.Commit(ctx))
+}
+
+func TestBadgerReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	setValue(ctx, t, database, "hello", "world")
+	assert.NoError(t, database.Close(ctx))
+
+	reader1, err := NewBadgerDatabase(
+		ctx,
+		newDir,
+		WithIndexCacheSize(TinyIndexCacheSize),
+		WithReadOnly(),
+	)
+	assert.NoError(t, err)
+	defer reader1.Close(ctx)
+
+	// Multiple readers can open the same database.
+	reader2, err := NewBadgerDatabase(
+		ctx,
+		newDir,
+		WithIndexCacheSize(TinyIndexCacheSize),
+		WithReadOnly(),
+	)
+	assert.NoError(t, err)
+	defer reader2.Close(ctx)
+
+	for _, reader := range []Database{reader1, reader2} {
+		assertValue(ctx, t, reader, "hello", "world")
+		assertReadOnly(ctx, t, reader)
+	}
+
+	_, err = reader1.(*BadgerDatabase).RunMaintenance(ctx, true)
+	assert.True(t, errors.Is(err, storageErrs.ErrDatabaseReadOnly))
+
+	// A writer cannot open the database while it is open in read-only mode.
+	_, err = newTestBadgerDatabase(ctx, newDir)
+	assert.True(t, errors.Is(err, storageErrs.ErrDatabaseOpenFailed))
+}
+
+func TestPebbleCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := NewPebbleDatabase(ctx, path.Join(newDir, "db"), nil)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	setValue(ctx, t, database, "hello", "world")
+	checkpointDir := path.Join(newDir, "checkpoint")
+	assert.NoError(t, database.(Checkpointer).Checkpoint(ctx, checkpointDir))
+	setValue(ctx, t, database, "hello2", "world2")
+
+	reader, err := NewReadOnlyPebbleDatabase(ctx, checkpointDir, nil)
+	assert.NoError(t, err)
+	defer reader.Close(ctx)
+
+	assertValue(ctx, t, reader, "hello", "world")
+	assertValue(ctx, t, reader, "hello2", "")
+	assertReadOnly(ctx, t, reader)
+
+	// The writer is unaffected by the reader.
+	assertValue(ctx, t, database, "hello2", "world2")
+
+	memoryDatabase, err := NewMemoryDatabase(ctx)
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(
+		memoryDatabase.(Checkpointer).Checkpoint(ctx, path.Join(newDir, "memory")),
+		storageErrs.ErrCheckpointUnsupported,
+	))
+}
+
+func TestReadOnlyPostgresDatabase(t *testing.T) {
+	ctx := context.Background()
+	versionQuery := regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0)")
+
+	t.Run("up to date schema", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(versionQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(len(PostgresMigrations)))
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		database, err := NewReadOnlyPostgresDatabase(ctx, db)
+		assert.NoError(t, err)
+		assertReadOnly(ctx, t, database)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("outdated schema", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(versionQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(0))
+
+		database, err := NewReadOnlyPostgresDatabase(ctx, db)
+		assert.True(t, errors.Is(err, storageErrs.ErrDatabaseOpenFailed))
+		assert.Nil(t, database)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,474 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// SnapshotVersion is the version of snapshots
+	// written by ExportSnapshot. It is written after
+	// the header so that unsupported snapshots are
+	// rejected before any keys are imported.
+	SnapshotVersion = 1
+
+	// DefaultSnapshotBatchBytes is the maximum size of keys
+	// and values written in a single transaction during
+	// ImportSnapshot (to avoid exceeding database
+	// transaction size limits).
+	DefaultSnapshotBatchBytes = 16 << 20
+
+	snapshotMagic = "ROSETTA-SNAPSHOT\n"
+
+	// Record types
+	snapshotEntryRecord    = byte(1)
+	snapshotManifestRecord = byte(2)
+
+	// maxSnapshotFieldSize prevents large allocations
+	// when reading a corrupted snapshot.
+	maxSnapshotFieldSize = 1 << 30
+)
+
+// SnapshotManifest is written at the end of a snapshot
+// and describes its contents.
+type SnapshotManifest struct {
+	Version int `json:"version"`
+
+	// Entries is the number of keys in the snapshot.
+	Entries int64 `json:"entries"`
+
+	// Bytes is the uncompressed size of all
+	// keys and values in the snapshot.
+	Bytes int64 `json:"bytes"`
+
+	// Namespaces is the number of keys in each namespace (the
+	// key prefix before the first "/"), ex: "block" or "balance".
+	Namespaces map[string]int64 `json:"namespaces"`
+
+	// Checksum is the hex-encoded SHA-256 hash
+	// of all entry records in the snapshot.
+	Checksum string `json:"checksum"`
+}
+
+func (m *SnapshotManifest) add(key []byte, value []byte) {
+	namespace := key
+	if i := bytes.IndexByte(key, '/'); i >= 0 {
+		namespace = key[:i]
+	}
+
+	m.Entries++
+	m.Bytes += int64(len(key) + len(value))
+	m.Namespaces[string(namespace)]++
+}
+
+// snapshotWriter writes snapshot records
+// while tracking their checksum.
+type snapshotWriter struct {
+	w      *bufio.Writer
+	hasher hash.Hash
+	buf    [binary.MaxVarintLen64]byte
+}
+
+func (s *snapshotWriter) writeField(w io.Writer, field []byte) error {
+	n := binary.PutUvarint(s.buf[:], uint64(len(field)))
+	if _, err := w.Write(s.buf[:n]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(field)
+	return err
+}
+
+func (s *snapshotWriter) writeEntry(key []byte, value []byte) error {
+	w := io.MultiWriter(s.w, s.hasher)
+	if _, err := w.Write([]byte{snapshotEntryRecord}); err != nil {
+		return err
+	}
+
+	if err := s.writeField(w, key); err != nil {
+		return err
+	}
+
+	return s.writeField(w, value)
+}
+
+// ExportSnapshot writes a gzip-compressed snapshot of all keys
+// in database to w. The snapshot is read in a single
+// ReadTransaction, so it is consistent even if database is
+// being written to. Values are written as stored, so a
+// snapshot must be imported into a database that uses the
+// same compressor entries.
+func ExportSnapshot(
+	ctx context.Context,
+	database Database,
+	w io.Writer,
+) (*SnapshotManifest, error) {
+	gz := gzip.NewWriter(w)
+	s := &snapshotWriter{
+		w:      bufio.NewWriter(gz),
+		hasher: sha256.New(),
+	}
+	manifest := &SnapshotManifest{
+		Version:    SnapshotVersion,
+		Namespaces: map[string]int64{},
+	}
+
+	if _, err := s.w.WriteString(snapshotMagic); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	if err := s.w.WriteByte(SnapshotVersion); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	txn := database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	_, err := txn.Scan(
+		ctx,
+		[]byte{},
+		[]byte{},
+		func(k []byte, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			manifest.add(k, v)
+			return s.writeEntry(k, v)
+		},
+		true,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	manifest.Checksum = hex.EncodeToString(s.hasher.Sum(nil))
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	if err := s.w.WriteByte(snapshotManifestRecord); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	if err := s.writeField(s.w, manifestBytes); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	if err := s.w.Flush(); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotExportFailed, err)
+	}
+
+	return manifest, nil
+}
+
+// snapshotReader reads snapshot records
+// while tracking their checksum.
+type snapshotReader struct {
+	r      *bufio.Reader
+	hasher hash.Hash
+}
+
+func (s *snapshotReader) ReadByte() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err == nil {
+		_, _ = s.hasher.Write([]byte{b})
+	}
+
+	return b, err
+}
+
+func (s *snapshotReader) readField() ([]byte, error) {
+	size, err := binary.ReadUvarint(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > maxSnapshotFieldSize {
+		return nil, fmt.Errorf("field size %d exceeds maximum %d", size, maxSnapshotFieldSize)
+	}
+
+	field := make([]byte, size)
+	if _, err := io.ReadFull(s.r, field); err != nil {
+		return nil, err
+	}
+	_, _ = s.hasher.Write(field)
+
+	return field, nil
+}
+
+// isEmpty returns true if database does not contain any keys.
+func isEmpty(ctx context.Context, database Database) (bool, error) {
+	errFound := errors.New("found key")
+
+	txn := database.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	_, err := txn.Scan(
+		ctx,
+		[]byte{},
+		[]byte{},
+		func(k []byte, v []byte) error {
+			return errFound
+		},
+		false,
+		false,
+	)
+	if errors.Is(err, errFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// readSnapshot reads a snapshot written by ExportSnapshot from r,
+// calling handler (if not nil) with each entry. The manifest is
+// only returned once all entries have been verified against it.
+func readSnapshot(
+	ctx context.Context,
+	r io.Reader,
+	handler func(key []byte, value []byte) error,
+) (*SnapshotManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotInvalid, err)
+	}
+	defer gz.Close()
+
+	s := &snapshotReader{
+		r:      bufio.NewReader(gz),
+		hasher: sha256.New(),
+	}
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(s.r, magic); err != nil || string(magic) != snapshotMagic {
+		return nil, fmt.Errorf("%w: missing snapshot header", storageErrs.ErrSnapshotInvalid)
+	}
+
+	version, err := s.r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotInvalid, err)
+	}
+
+	if version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", storageErrs.ErrSnapshotVersionUnsupported, version)
+	}
+
+	observed := &SnapshotManifest{
+		Version:    SnapshotVersion,
+		Namespaces: map[string]int64{},
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		recordType, err := s.r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotInvalid, err)
+		}
+
+		if recordType == snapshotManifestRecord {
+			break
+		}
+
+		if recordType != snapshotEntryRecord {
+			return nil, fmt.Errorf("%w: unknown record type %d", storageErrs.ErrSnapshotInvalid, recordType)
+		}
+		_, _ = s.hasher.Write([]byte{recordType})
+
+		key, err := s.readField()
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read key: %v", storageErrs.ErrSnapshotInvalid, err)
+		}
+
+		value, err := s.readField()
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read value: %v", storageErrs.ErrSnapshotInvalid, err)
+		}
+
+		if handler != nil {
+			if err := handler(key, value); err != nil {
+				return nil, err
+			}
+		}
+
+		observed.add(key, value)
+	}
+	observed.Checksum = hex.EncodeToString(s.hasher.Sum(nil))
+
+	manifestBytes, err := s.readField()
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read manifest: %v", storageErrs.ErrSnapshotInvalid, err)
+	}
+
+	var manifest SnapshotManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse manifest: %v", storageErrs.ErrSnapshotInvalid, err)
+	}
+
+	if manifest.Checksum != observed.Checksum ||
+		manifest.Entries != observed.Entries ||
+		manifest.Bytes != observed.Bytes {
+		return nil, fmt.Errorf(
+			"%w: manifest has %d entries (checksum %s) but read %d entries (checksum %s)",
+			storageErrs.ErrSnapshotChecksumMismatch,
+			manifest.Entries,
+			manifest.Checksum,
+			observed.Entries,
+			observed.Checksum,
+		)
+	}
+
+	return &manifest, nil
+}
+
+// rewindableSnapshot returns a reader that can be read twice by
+// calling rewind. If r is not an io.ReadSeeker, it is copied to a
+// temporary file (removed by cleanup) as it is read.
+func rewindableSnapshot(r io.Reader) (io.Reader, func() (io.Reader, error), func(), error) {
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		rewind := func() (io.Reader, error) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+
+			return seeker, nil
+		}
+
+		return seeker, rewind, func() {}, nil
+	}
+
+	spool, err := ioutil.TempFile("", "rosetta-snapshot-*")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rewind := func() (io.Reader, error) {
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		return spool, nil
+	}
+	cleanup := func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}
+
+	return io.TeeReader(r, spool), rewind, cleanup, nil
+}
+
+// ImportSnapshot reads a snapshot written by ExportSnapshot from r
+// and writes all of its keys to database, which must be empty.
+//
+// The snapshot is verified against its manifest before any keys are
+// written (by reading it twice), so a corrupted snapshot is never
+// imported. If r is not an io.ReadSeeker, the snapshot is copied to
+// a temporary file while it is verified. Keys are written in
+// multiple transactions, so if ImportSnapshot returns an error
+// after verification (ex: the database is unavailable), database
+// should be discarded.
+func ImportSnapshot(
+	ctx context.Context,
+	database Database,
+	r io.Reader,
+) (*SnapshotManifest, error) {
+	empty, err := isEmpty(ctx, database)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotImportFailed, err)
+	}
+
+	if !empty {
+		return nil, storageErrs.ErrSnapshotDatabaseNotEmpty
+	}
+
+	source, rewind, cleanup, err := rewindableSnapshot(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotImportFailed, err)
+	}
+	defer cleanup()
+
+	if _, err := readSnapshot(ctx, source, nil); err != nil {
+		return nil, err
+	}
+
+	source, err = rewind()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotImportFailed, err)
+	}
+
+	txn := database.Transaction(ctx)
+	batchBytes := 0
+	manifest, err := readSnapshot(ctx, source, func(key []byte, value []byte) error {
+		if batchBytes > 0 && batchBytes+len(key)+len(value) > DefaultSnapshotBatchBytes {
+			committed := txn
+			txn = nil
+			if err := committed.Commit(ctx); err != nil {
+				return fmt.Errorf("%w: %v", storageErrs.ErrSnapshotImportFailed, err)
+			}
+
+			txn = database.Transaction(ctx)
+			batchBytes = 0
+		}
+
+		if err := txn.Set(ctx, key, value, false); err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrSnapshotImportFailed, err)
+		}
+
+		batchBytes += len(key) + len(value)
+		return nil
+	})
+	if err != nil {
+		if txn != nil {
+			txn.Discard(ctx)
+		}
+
+		return nil, err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrSnapshotImportFailed, err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func populateSnapshotDatabase(ctx context.Context, t *testing.T, database Database) {
+	txn := database.Transaction(ctx)
+	for i := 0; i < 100; i++ {
+		for _, namespace := range []string{"block", "balance"} {
+			k := fmt.Sprintf("%s/%d", namespace, i)
+			assert.NoError(t, txn.Set(ctx, []byte(k), []byte("v"+k), false))
+		}
+	}
+	assert.NoError(t, txn.Set(ctx, []byte("head"), []byte("vhead"), false))
+	assert.NoError(t, txn.Commit(ctx))
+}
+
+// rewriteSnapshot decompresses snapshot, applies
+// modify, and compresses the result.
+func rewriteSnapshot(t *testing.T, snapshot []byte, modify func([]byte) []byte) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(snapshot))
+	assert.NoError(t, err)
+	raw, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(modify(raw))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	source, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer source.Close(ctx)
+	populateSnapshotDatabase(ctx, t, source)
+
+	var snapshot bytes.Buffer
+	manifest, err := ExportSnapshot(ctx, source, &snapshot)
+	assert.NoError(t, err)
+	assert.Equal(t, SnapshotVersion, manifest.Version)
+	assert.Equal(t, int64(201), manifest.Entries)
+	assert.Equal(t, map[string]int64{
+		"block":   100,
+		"balance": 100,
+		"head":    1,
+	}, manifest.Namespaces)
+	assert.Len(t, manifest.Checksum, 64)
+
+	t.Run("import", func(t *testing.T) {
+		for name, newDatabase := range kvBackends() {
+			t.Run(name, func(t *testing.T) {
+				dir, err := utils.CreateTempDir()
+				assert.NoError(t, err)
+				defer utils.RemoveTempDir(dir)
+
+				destination, err := newDatabase(ctx, dir)
+				assert.NoError(t, err)
+				defer destination.Close(ctx)
+
+				imported, err := ImportSnapshot(ctx, destination, bytes.NewReader(snapshot.Bytes()))
+				assert.NoError(t, err)
+				assert.Equal(t, manifest, imported)
+
+				txn := destination.ReadTransaction(ctx)
+				assert.Len(t, scanKeys(ctx, t, txn, "block/", "block/", false), 100)
+				assert.Len(t, scanKeys(ctx, t, txn, "balance/", "balance/", false), 100)
+				exists, value, err := txn.Get(ctx, []byte("head"))
+				assert.NoError(t, err)
+				assert.True(t, exists)
+				assert.Equal(t, []byte("vhead"), value)
+				txn.Discard(ctx)
+
+				// A database can't be imported into twice
+				_, err = ImportSnapshot(ctx, destination, bytes.NewReader(snapshot.Bytes()))
+				assert.ErrorIs(t, err, storageErrs.ErrSnapshotDatabaseNotEmpty)
+			})
+		}
+	})
+
+	t.Run("corrupted value", func(t *testing.T) {
+		destination, err := NewMemoryDatabase(ctx)
+		assert.NoError(t, err)
+
+		corrupted := rewriteSnapshot(t, snapshot.Bytes(), func(raw []byte) []byte {
+			return bytes.Replace(raw, []byte("vblock/42"), []byte("vblock/43"), 1)
+		})
+		_, err = ImportSnapshot(ctx, destination, bytes.NewReader(corrupted))
+		assert.ErrorIs(t, err, storageErrs.ErrSnapshotChecksumMismatch)
+
+		// Nothing is written before the snapshot is verified
+		empty, err := isEmpty(ctx, destination)
+		assert.NoError(t, err)
+		assert.True(t, empty)
+
+		// Snapshots that can't be rewound are verified the same way
+		_, err = ImportSnapshot(ctx, destination, bufio.NewReader(bytes.NewReader(corrupted)))
+		assert.ErrorIs(t, err, storageErrs.ErrSnapshotChecksumMismatch)
+		empty, err = isEmpty(ctx, destination)
+		assert.NoError(t, err)
+		assert.True(t, empty)
+	})
+
+	t.Run("import from stream", func(t *testing.T) {
+		destination, err := NewMemoryDatabase(ctx)
+		assert.NoError(t, err)
+
+		imported, err := ImportSnapshot(
+			ctx,
+			destination,
+			bufio.NewReader(bytes.NewReader(snapshot.Bytes())),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, manifest, imported)
+
+		txn := destination.ReadTransaction(ctx)
+		assert.Len(t, scanKeys(ctx, t, txn, "block/", "block/", false), 100)
+		txn.Discard(ctx)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		destination, err := NewMemoryDatabase(ctx)
+		assert.NoError(t, err)
+
+		truncated := rewriteSnapshot(t, snapshot.Bytes(), func(raw []byte) []byte {
+			return raw[:len(raw)/2]
+		})
+		_, err = ImportSnapshot(ctx, destination, bytes.NewReader(truncated))
+		assert.ErrorIs(t, err, storageErrs.ErrSnapshotInvalid)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		destination, err := NewMemoryDatabase(ctx)
+		assert.NoError(t, err)
+
+		unsupported := rewriteSnapshot(t, snapshot.Bytes(), func(raw []byte) []byte {
+			raw[len(snapshotMagic)] = SnapshotVersion + 1
+			return raw
+		})
+		_, err = ImportSnapshot(ctx, destination, bytes.NewReader(unsupported))
+		assert.ErrorIs(t, err, storageErrs.ErrSnapshotVersionUnsupported)
+	})
+
+	t.Run("not a snapshot", func(t *testing.T) {
+		destination, err := NewMemoryDatabase(ctx)
+		assert.NoError(t, err)
+
+		_, err = ImportSnapshot(ctx, destination, bytes.NewReader([]byte("hello")))
+		assert.ErrorIs(t, err, storageErrs.ErrSnapshotInvalid)
+	})
+}
@@ -0,0 +1,532 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+const (
+	// DefaultExpireBatchSize is the default number of
+	// expired entries deleted in a single transaction.
+	DefaultExpireBatchSize = 1000
+
+	// ttlIndexNamespace is prepended to the expiry
+	// index of all entries with a TTL. Index keys are
+	// ttlIndexNamespace/<big-endian expiry>/<key> so
+	// that a scan visits entries in order of expiry.
+	ttlIndexNamespace = "ttl-index"
+
+	// expiryHeaderSize is the size of the expiry (in
+	// UnixNano, 0 if the entry never expires) prepended
+	// to every value in an expiring namespace.
+	expiryHeaderSize = 8
+)
+
+var (
+	_ Database     = (*ExpiringDatabase)(nil)
+	_ Maintainable = (*ExpiringDatabase)(nil)
+
+	// errExpireBatchFull is returned by the
+	// expiry index scan worker to stop the scan.
+	errExpireBatchFull = errors.New("expire batch full")
+)
+
+// ExpiringTransaction is a Transaction that can
+// set entries that expire.
+type ExpiringTransaction interface {
+	Transaction
+
+	// SetWithTTL sets the value of key, which expires
+	// after ttl. If ttl is 0, the entry never expires.
+	SetWithTTL(
+		ctx context.Context,
+		key []byte,
+		value []byte,
+		reclaimValue bool,
+		ttl time.Duration,
+	) error
+}
+
+// ExpiringDatabase wraps a Database to expire entries in
+// configured namespaces (the portion of a key before the
+// first "/") after some TTL. This is useful for keeping
+// data that is only relevant for a limited time (ex: mempool
+// records, stale broadcast metadata, or old reconciliation
+// queue entries) from growing the database without bound.
+//
+// Expired entries are never returned by Get or Scan and are
+// deleted lazily when read in a write transaction. All
+// remaining expired entries are deleted by ExpireEntries,
+// which is run before any maintenance (see Maintainable).
+//
+// Values in expiring namespaces are stored with an expiry
+// header, so a namespace must not be added to or removed from
+// an ExpiringDatabase without a migration (see Migrate).
+type ExpiringDatabase struct {
+	Database
+
+	namespaces map[string]time.Duration
+	batchSize  int
+
+	// expireLock ensures only one call
+	// to ExpireEntries runs at a time.
+	expireLock sync.Mutex
+
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+// TTLOption is used to overwrite default values in
+// ExpiringDatabase construction. Any Option not provided
+// falls back to the default value.
+type TTLOption func(e *ExpiringDatabase)
+
+// WithNamespaceTTL expires all entries set in namespace
+// ttl after they are written. If ttl is 0, entries in
+// namespace only expire if set with SetWithTTL.
+func WithNamespaceTTL(namespace string, ttl time.Duration) TTLOption {
+	return func(e *ExpiringDatabase) {
+		e.namespaces[namespace] = ttl
+	}
+}
+
+// WithExpireBatchSize overrides the DefaultExpireBatchSize.
+func WithExpireBatchSize(size int) TTLOption {
+	return func(e *ExpiringDatabase) {
+		e.batchSize = size
+	}
+}
+
+// NewExpiringDatabase returns a new *ExpiringDatabase
+// that wraps db.
+func NewExpiringDatabase(
+	db Database,
+	options ...TTLOption,
+) (*ExpiringDatabase, error) {
+	e := &ExpiringDatabase{
+		Database:   db,
+		namespaces: map[string]time.Duration{},
+		batchSize:  DefaultExpireBatchSize,
+		now:        time.Now,
+	}
+
+	for _, opt := range options {
+		opt(e)
+	}
+
+	for namespace, ttl := range e.namespaces {
+		if len(namespace) == 0 || namespace == ttlIndexNamespace {
+			return nil, fmt.Errorf("%w: namespace %q cannot expire", storageErrs.ErrTTLInvalid, namespace)
+		}
+
+		if ttl < 0 {
+			return nil, fmt.Errorf("%w: %s for namespace %s", storageErrs.ErrTTLInvalid, ttl, namespace)
+		}
+	}
+
+	if e.batchSize <= 0 {
+		return nil, fmt.Errorf("%w: batch size %d", storageErrs.ErrTTLInvalid, e.batchSize)
+	}
+
+	return e, nil
+}
+
+// Transaction creates a new exclusive write ExpiringTransaction.
+func (e *ExpiringDatabase) Transaction(ctx context.Context) Transaction {
+	return &expiringTransaction{
+		Transaction: e.Database.Transaction(ctx),
+		db:          e,
+		writable:    true,
+	}
+}
+
+// ReadTransaction creates a new read ExpiringTransaction.
+func (e *ExpiringDatabase) ReadTransaction(ctx context.Context) Transaction {
+	return &expiringTransaction{
+		Transaction: e.Database.ReadTransaction(ctx),
+		db:          e,
+	}
+}
+
+// WriteTransaction creates a new write ExpiringTransaction
+// for a particular identifier.
+func (e *ExpiringDatabase) WriteTransaction(
+	ctx context.Context,
+	identifier string,
+	priority bool,
+) Transaction {
+	return &expiringTransaction{
+		Transaction: e.Database.WriteTransaction(ctx, identifier, priority),
+		db:          e,
+		writable:    true,
+	}
+}
+
+// expiring returns the TTL of the namespace of key and
+// if the namespace is configured to expire entries.
+func (e *ExpiringDatabase) expiring(key []byte) (time.Duration, bool) {
+	ttl, ok := e.namespaces[keyNamespace(key)]
+	return ttl, ok
+}
+
+// expired returns the value of an entry in an expiring
+// namespace (without the expiry header) and if the
+// entry has expired.
+func (e *ExpiringDatabase) expired(key []byte, value []byte) ([]byte, bool, error) {
+	if len(value) < expiryHeaderSize {
+		return nil, false, fmt.Errorf(
+			"%w: %s has %d bytes",
+			storageErrs.ErrExpiringEntryInvalid,
+			string(key),
+			len(value),
+		)
+	}
+
+	expiry := int64(binary.BigEndian.Uint64(value[:expiryHeaderSize]))
+	return value[expiryHeaderSize:], expiry != 0 && expiry <= e.now().UnixNano(), nil
+}
+
+func ttlIndexKey(expiry int64, key []byte) []byte {
+	indexKey := make([]byte, 0, len(ttlIndexNamespace)+expiryHeaderSize+len(key)+2)
+	indexKey = append(indexKey, ttlIndexNamespace...)
+	indexKey = append(indexKey, '/')
+	indexKey = append(indexKey, make([]byte, expiryHeaderSize)...)
+	binary.BigEndian.PutUint64(indexKey[len(indexKey)-expiryHeaderSize:], uint64(expiry))
+	indexKey = append(indexKey, '/')
+
+	return append(indexKey, key...)
+}
+
+func parseTTLIndexKey(indexKey []byte) (int64, []byte, error) {
+	prefixLength := len(ttlIndexNamespace) + 1
+	if len(indexKey) < prefixLength+expiryHeaderSize+1 {
+		return -1, nil, fmt.Errorf("%w: index key %x", storageErrs.ErrExpiringEntryInvalid, indexKey)
+	}
+
+	expiry := indexKey[prefixLength : prefixLength+expiryHeaderSize]
+	return int64(binary.BigEndian.Uint64(expiry)), indexKey[prefixLength+expiryHeaderSize+1:], nil
+}
+
+// ExpireEntries deletes all expired entries and returns
+// the number of entries deleted. Entries are deleted in
+// batches of the expire batch size, each in its own
+// exclusive transaction.
+func (e *ExpiringDatabase) ExpireEntries(ctx context.Context) (int, error) {
+	e.expireLock.Lock()
+	defer e.expireLock.Unlock()
+
+	prefix := []byte(ttlIndexNamespace + "/")
+	expired := 0
+	for ctx.Err() == nil {
+		now := e.now().UnixNano()
+		indexKeys := [][]byte{}
+		txn := e.Database.Transaction(ctx)
+		_, err := txn.Scan(
+			ctx,
+			prefix,
+			prefix,
+			func(k []byte, v []byte) error {
+				expiry, _, err := parseTTLIndexKey(k)
+				if err != nil {
+					return err
+				}
+
+				// The index is sorted by expiry, so there
+				// are no more expired entries.
+				if expiry > now {
+					return errExpireBatchFull
+				}
+
+				indexKeys = append(indexKeys, append([]byte{}, k...))
+				if len(indexKeys) == e.batchSize {
+					return errExpireBatchFull
+				}
+
+				return nil
+			},
+			false,
+			false,
+		)
+		if err != nil && !errors.Is(err, errExpireBatchFull) {
+			txn.Discard(ctx)
+			return -1, fmt.Errorf("%w: %v", storageErrs.ErrExpireFailed, err)
+		}
+
+		for _, indexKey := range indexKeys {
+			expiry, key, _ := parseTTLIndexKey(indexKey)
+			deleted, err := e.expireEntry(ctx, txn, key, expiry)
+			if err != nil {
+				txn.Discard(ctx)
+				return -1, fmt.Errorf("%w: %v", storageErrs.ErrExpireFailed, err)
+			}
+
+			if deleted {
+				expired++
+			}
+
+			if err := txn.Delete(ctx, indexKey); err != nil {
+				txn.Discard(ctx)
+				return -1, fmt.Errorf("%w: %v", storageErrs.ErrExpireFailed, err)
+			}
+		}
+
+		if err := txn.Commit(ctx); err != nil {
+			return -1, fmt.Errorf("%w: %v", storageErrs.ErrExpireFailed, err)
+		}
+
+		if len(indexKeys) < e.batchSize {
+			return expired, nil
+		}
+	}
+
+	return -1, fmt.Errorf("%w: %v", storageErrs.ErrExpireFailed, ctx.Err())
+}
+
+// expireEntry deletes key if it still has the provided
+// expiry. An entry may have been overwritten (or deleted)
+// since it was indexed, in which case the index entry is
+// stale and the entry is left as is.
+func (e *ExpiringDatabase) expireEntry(
+	ctx context.Context,
+	txn Transaction,
+	key []byte,
+	expiry int64,
+) (bool, error) {
+	exists, value, err := txn.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists || len(value) < expiryHeaderSize ||
+		int64(binary.BigEndian.Uint64(value[:expiryHeaderSize])) != expiry {
+		return false, nil
+	}
+
+	if err := txn.Delete(ctx, key); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// MaintenanceStats returns the MaintenanceStats of the
+// wrapped Database. If the wrapped Database is not
+// Maintainable, empty MaintenanceStats are returned.
+func (e *ExpiringDatabase) MaintenanceStats(ctx context.Context) (*MaintenanceStats, error) {
+	maintainable, ok := e.Database.(Maintainable)
+	if !ok {
+		return &MaintenanceStats{}, nil
+	}
+
+	return maintainable.MaintenanceStats(ctx)
+}
+
+// RunMaintenance deletes all expired entries and then
+// runs maintenance on the wrapped Database (if it is
+// Maintainable) to reclaim the space they used.
+func (e *ExpiringDatabase) RunMaintenance(
+	ctx context.Context,
+	compact bool,
+) (*MaintenanceResult, error) {
+	start := time.Now()
+	expired, err := e.ExpireEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrMaintenanceFailed, err)
+	}
+
+	result := &MaintenanceResult{}
+	if maintainable, ok := e.Database.(Maintainable); ok {
+		result, err = maintainable.RunMaintenance(ctx, compact)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.ExpiredEntries = expired
+	result.Duration = time.Since(start)
+
+	return result, nil
+}
+
+// expiringTransaction wraps a Transaction to
+// add and remove expiry headers.
+type expiringTransaction struct {
+	Transaction
+
+	db       *ExpiringDatabase
+	writable bool
+}
+
+// Set changes the value of the key to the value within a
+// transaction. If key is in an expiring namespace, it
+// expires after the namespace TTL.
+func (t *expiringTransaction) Set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+) error {
+	ttl, ok := t.db.expiring(key)
+	if !ok {
+		return t.Transaction.Set(ctx, key, value, reclaimValue)
+	}
+
+	return t.set(ctx, key, value, ttl)
+}
+
+// SetWithTTL sets the value of key, which expires after ttl.
+// If ttl is 0, the entry never expires. ErrTTLUnsupported is
+// returned if key is not in an expiring namespace.
+func (t *expiringTransaction) SetWithTTL(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	reclaimValue bool,
+	ttl time.Duration,
+) error {
+	if _, ok := t.db.expiring(key); !ok {
+		return fmt.Errorf("%w: %s", storageErrs.ErrTTLUnsupported, keyNamespace(key))
+	}
+
+	if ttl < 0 {
+		return fmt.Errorf("%w: %s", storageErrs.ErrTTLInvalid, ttl)
+	}
+
+	return t.set(ctx, key, value, ttl)
+}
+
+func (t *expiringTransaction) set(
+	ctx context.Context,
+	key []byte,
+	value []byte,
+	ttl time.Duration,
+) error {
+	var expiry int64
+	if ttl > 0 {
+		expiry = t.db.now().Add(ttl).UnixNano()
+	}
+
+	entry := make([]byte, expiryHeaderSize+len(value))
+	binary.BigEndian.PutUint64(entry, uint64(expiry))
+	copy(entry[expiryHeaderSize:], value)
+
+	if err := t.Transaction.Set(ctx, key, entry, false); err != nil {
+		return err
+	}
+
+	if expiry == 0 {
+		return nil
+	}
+
+	return t.Transaction.Set(ctx, ttlIndexKey(expiry, key), []byte{}, false)
+}
+
+// Get accesses the value of the key within a transaction.
+// Expired entries are not returned (and are deleted if
+// the transaction is writable).
+func (t *expiringTransaction) Get(
+	ctx context.Context,
+	key []byte,
+) (bool, []byte, error) {
+	exists, value, err := t.Transaction.Get(ctx, key)
+	if err != nil || !exists {
+		return exists, value, err
+	}
+
+	if _, ok := t.db.expiring(key); !ok {
+		return exists, value, nil
+	}
+
+	value, expired, err := t.db.expired(key, value)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if !expired {
+		return true, value, nil
+	}
+
+	if t.writable {
+		if err := t.Transaction.Delete(ctx, key); err != nil {
+			return false, nil, err
+		}
+	}
+
+	return false, nil, nil
+}
+
+// Scan calls a worker for each item in a scan. Expired
+// entries are skipped (and are deleted after the scan if
+// the transaction is writable).
+func (t *expiringTransaction) Scan(
+	ctx context.Context,
+	prefix []byte,
+	seekStart []byte,
+	worker func([]byte, []byte) error,
+	logEntries bool,
+	reverse bool,
+) (int, error) {
+	entries := 0
+	expiredKeys := [][]byte{}
+	_, err := t.Transaction.Scan(
+		ctx,
+		prefix,
+		seekStart,
+		func(k []byte, v []byte) error {
+			if _, ok := t.db.expiring(k); ok {
+				value, expired, err := t.db.expired(k, v)
+				if err != nil {
+					return err
+				}
+
+				if expired {
+					if t.writable {
+						expiredKeys = append(expiredKeys, append([]byte{}, k...))
+					}
+
+					return nil
+				}
+
+				v = value
+			}
+
+			entries++
+			return worker(k, v)
+		},
+		logEntries,
+		reverse,
+	)
+	if err != nil {
+		return -1, err
+	}
+
+	// Keys cannot be deleted while the
+	// Transaction is scanning.
+	for _, key := range expiredKeys {
+		if err := t.Transaction.Delete(ctx, key); err != nil {
+			return -1, err
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+func TestExpiringDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	memory, err := NewMemoryDatabase(ctx)
+	assert.NoError(t, err)
+	defer memory.Close(ctx)
+
+	_, err = NewExpiringDatabase(memory, WithNamespaceTTL("mempool", -time.Second))
+	assert.True(t, errors.Is(err, storageErrs.ErrTTLInvalid))
+
+	_, err = NewExpiringDatabase(memory, WithNamespaceTTL(ttlIndexNamespace, time.Second))
+	assert.True(t, errors.Is(err, storageErrs.ErrTTLInvalid))
+
+	db, err := NewExpiringDatabase(
+		memory,
+		WithNamespaceTTL("mempool", time.Minute),
+		WithNamespaceTTL("broadcast", 0),
+		WithExpireBatchSize(2),
+	)
+	assert.NoError(t, err)
+
+	now := time.Unix(1600000000, 0)
+	db.now = func() time.Time { return now }
+
+	t.Run("set entries", func(t *testing.T) {
+		txn := db.Transaction(ctx)
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, txn.Set(
+				ctx,
+				[]byte(fmt.Sprintf("mempool/%d", i)),
+				[]byte(fmt.Sprintf("tx%d", i)),
+				false,
+			))
+		}
+		assert.NoError(t, txn.Set(ctx, []byte("block/1"), []byte("block"), false))
+		assert.NoError(t, txn.Set(ctx, []byte("broadcast/a"), []byte("forever"), false))
+
+		expiring := txn.(ExpiringTransaction)
+		assert.NoError(t, expiring.SetWithTTL(
+			ctx,
+			[]byte("broadcast/b"),
+			[]byte("stale"),
+			false,
+			2*time.Minute,
+		))
+		assert.True(t, errors.Is(
+			expiring.SetWithTTL(ctx, []byte("block/2"), []byte("block"), false, time.Minute),
+			storageErrs.ErrTTLUnsupported,
+		))
+		assert.True(t, errors.Is(
+			expiring.SetWithTTL(ctx, []byte("broadcast/c"), []byte("c"), false, -time.Minute),
+			storageErrs.ErrTTLInvalid,
+		))
+		assert.NoError(t, txn.Commit(ctx))
+
+		// Values are stored with an expiry header
+		// in expiring namespaces only.
+		raw := memory.ReadTransaction(ctx)
+		_, value, err := raw.Get(ctx, []byte("mempool/0"))
+		assert.NoError(t, err)
+		assert.Len(t, value, expiryHeaderSize+3)
+		_, value, err = raw.Get(ctx, []byte("block/1"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("block"), value)
+		raw.Discard(ctx)
+	})
+
+	t.Run("read before expiry", func(t *testing.T) {
+		txn := db.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+
+		exists, value, err := txn.Get(ctx, []byte("mempool/1"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("tx1"), value)
+
+		values := []string{}
+		count, err := txn.Scan(
+			ctx,
+			[]byte("mempool/"),
+			[]byte("mempool/"),
+			func(k []byte, v []byte) error {
+				values = append(values, string(v))
+				return nil
+			},
+			false,
+			false,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+		assert.Equal(t, []string{"tx0", "tx1", "tx2", "tx3", "tx4"}, values)
+	})
+
+	t.Run("overwrite extends expiry", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+		txn := db.Transaction(ctx)
+		assert.NoError(t, txn.Set(ctx, []byte("mempool/4"), []byte("tx4"), false))
+		assert.NoError(t, txn.Commit(ctx))
+	})
+
+	t.Run("lazy expiry", func(t *testing.T) {
+		now = now.Add(45 * time.Second)
+
+		txn := db.ReadTransaction(ctx)
+		exists, _, err := txn.Get(ctx, []byte("mempool/0"))
+		assert.NoError(t, err)
+		assert.False(t, exists)
+
+		values := []string{}
+		count, err := txn.Scan(
+			ctx,
+			[]byte(""),
+			[]byte(""),
+			func(k []byte, v []byte) error {
+				if keyNamespace(k) != ttlIndexNamespace {
+					values = append(values, string(v))
+				}
+				return nil
+			},
+			false,
+			false,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, count) // includes 7 index keys
+		assert.Equal(t, []string{"block", "forever", "stale", "tx4"}, values)
+		txn.Discard(ctx)
+
+		// Expired entries are deleted when
+		// read in a write transaction.
+		txn = db.Transaction(ctx)
+		exists, _, err = txn.Get(ctx, []byte("mempool/0"))
+		assert.NoError(t, err)
+		assert.False(t, exists)
+		assert.NoError(t, txn.Commit(ctx))
+
+		raw := memory.ReadTransaction(ctx)
+		exists, _, err = raw.Get(ctx, []byte("mempool/0"))
+		assert.NoError(t, err)
+		assert.False(t, exists)
+		exists, _, err = raw.Get(ctx, []byte("mempool/1"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		raw.Discard(ctx)
+	})
+
+	t.Run("expire entries", func(t *testing.T) {
+		result, err := db.RunMaintenance(ctx, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ExpiredEntries)
+		assert.False(t, result.Compacted)
+
+		raw := memory.ReadTransaction(ctx)
+		count, err := raw.Scan(
+			ctx,
+			[]byte("mempool/"),
+			[]byte("mempool/"),
+			func(k []byte, v []byte) error { return nil },
+			false,
+			false,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		// Only the index entries of mempool/4 and
+		// broadcast/b remain.
+		count, err = raw.Scan(
+			ctx,
+			[]byte(ttlIndexNamespace),
+			[]byte(ttlIndexNamespace),
+			func(k []byte, v []byte) error { return nil },
+			false,
+			false,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		raw.Discard(ctx)
+
+		now = now.Add(time.Hour)
+		expired, err := db.ExpireEntries(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, expired)
+
+		txn := db.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+		exists, value, err := txn.Get(ctx, []byte("broadcast/a"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []byte("forever"), value)
+	})
+}
@@ -41,31 +41,61 @@ const (
 // can be used by zstd. You can read more about these "dicts" here:
 // https://github.com/facebook/zstd#the-case-for-small-data-compression.
 //
-// NOTE: If you change these dicts, you will not be able
-// to decode previously encoded data. For many users, providing
-// no dicts is sufficient!
+// NOTE: If you change these dicts (or disable compression
+// for a namespace), you will not be able to decode previously
+// encoded data. For many users, providing no dicts is sufficient!
 type Encoder struct {
-	compressionDicts map[string][]byte
-	pool             *BufferPool
-	compress         bool
+	compressionDicts       map[string][]byte
+	compressionLevels      map[string]int
+	uncompressedNamespaces map[string]struct{}
+	pool                   *BufferPool
+	compress               bool
 }
 
-// CompressorEntry is used to initialize a dictionary compression.
+// CompressorEntry is used to configure compression for a namespace.
 // All DictionaryPaths are loaded from disk at initialization.
 type CompressorEntry struct {
-	Namespace      string
+	Namespace string
+
+	// DictionaryPath is the path of a zstd dictionary used
+	// to compress values in the namespace, if populated.
 	DictionaryPath string
+
+	// CompressionLevel is the zstd compression level used for
+	// values in the namespace. If 0, zstd.DefaultCompression
+	// is used.
+	CompressionLevel int
+
+	// DisableCompression stores values in the namespace without
+	// compression. This is useful for payloads that are already
+	// compressed (where zstd only adds overhead).
+	DisableCompression bool
 }
 
-// NewEncoder returns a new *Encoder. The dicts
-// provided should contain k:v of namespace:zstd dict.
+// NewEncoder returns a new *Encoder. The entries
+// provided configure compression for each namespace.
 func NewEncoder(
 	entries []*CompressorEntry,
 	pool *BufferPool,
 	compress bool,
 ) (*Encoder, error) {
 	dicts := map[string][]byte{}
+	levels := map[string]int{}
+	uncompressed := map[string]struct{}{}
 	for _, entry := range entries {
+		if entry.DisableCompression {
+			uncompressed[entry.Namespace] = struct{}{}
+			continue
+		}
+
+		if entry.CompressionLevel != 0 {
+			levels[entry.Namespace] = entry.CompressionLevel
+		}
+
+		if len(entry.DictionaryPath) == 0 {
+			continue
+		}
+
 		b, err := ioutil.ReadFile(path.Clean(entry.DictionaryPath))
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -81,12 +111,21 @@ func NewEncoder(
 	}
 
 	return &Encoder{
-		compressionDicts: dicts,
-		pool:             pool,
-		compress:         compress,
+		compressionDicts:       dicts,
+		compressionLevels:      levels,
+		uncompressedNamespaces: uncompressed,
+		pool:                   pool,
+		compress:               compress,
 	}, nil
 }
 
+// compressNamespace returns a boolean indicating if
+// values in a namespace should be compressed.
+func (e *Encoder) compressNamespace(namespace string) bool {
+	_, ok := e.uncompressedNamespaces[namespace]
+	return !ok
+}
+
 func getEncoder(w io.Writer) *msgpack.Encoder {
 	enc := msgpack.NewEncoder(w)
 	enc.SetCustomStructTag(jsonTag)
@@ -103,7 +142,7 @@ func (e *Encoder) Encode(namespace string, object interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("%w: %v", errors.ErrObjectEncodeFailed, err)
 	}
 
-	if !e.compress {
+	if !e.compress || !e.compressNamespace(namespace) {
 		return buf.Bytes(), nil
 	}
 
@@ -117,9 +156,14 @@ func (e *Encoder) Encode(namespace string, object interface{}) ([]byte, error) {
 }
 
 // EncodeRaw only compresses an input, leaving encoding to the caller.
-// This is particularly useful for training a compressor.
+// This is particularly useful for training a compressor. If compression
+// is disabled for the namespace, the input is returned as-is.
 func (e *Encoder) EncodeRaw(namespace string, input []byte) ([]byte, error) {
-	return e.encode(input, e.compressionDicts[namespace])
+	if !e.compressNamespace(namespace) {
+		return input, nil
+	}
+
+	return e.encode(input, e.compressionDicts[namespace], e.compressionLevels[namespace])
 }
 
 func getDecoder(r io.Reader) *msgpack.Decoder {
@@ -137,7 +181,7 @@ func (e *Encoder) Decode(
 	object interface{},
 	reclaimInput bool,
 ) error {
-	if e.compress {
+	if e.compress && e.compressNamespace(namespace) {
 		decompressed, err := e.DecodeRaw(namespace, input)
 		if err != nil {
 			return fmt.Errorf("%w: %v", errors.ErrRawDecompressFailed, err)
@@ -162,18 +206,27 @@ func (e *Encoder) Decode(
 }
 
 // DecodeRaw only decompresses an input, leaving decoding to the caller.
-// This is particularly useful for training a compressor.
+// This is particularly useful for training a compressor. If compression
+// is disabled for the namespace, the input is returned as-is.
 func (e *Encoder) DecodeRaw(namespace string, input []byte) ([]byte, error) {
+	if !e.compressNamespace(namespace) {
+		return input, nil
+	}
+
 	return e.decode(input, e.compressionDicts[namespace])
 }
 
-func (e *Encoder) encode(input []byte, zstdDict []byte) ([]byte, error) {
+func (e *Encoder) encode(input []byte, zstdDict []byte, level int) ([]byte, error) {
+	if level == 0 {
+		level = zstd.DefaultCompression
+	}
+
 	buf := e.pool.Get()
 	var writer io.WriteCloser
 	if len(zstdDict) > 0 {
-		writer = zstd.NewWriterLevelDict(buf, zstd.DefaultCompression, zstdDict)
+		writer = zstd.NewWriterLevelDict(buf, level, zstdDict)
 	} else {
-		writer = zstd.NewWriter(buf)
+		writer = zstd.NewWriterLevel(buf, level)
 	}
 	if _, err := writer.Write(input); err != nil {
 		return nil, fmt.Errorf("%w: %v", errors.ErrBufferWriteFailed, err)
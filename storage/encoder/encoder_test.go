@@ -80,6 +80,60 @@ func TestEncoder(t *testing.T) {
 	assert.NoError(t, g.Wait())
 }
 
+func TestEncoderNamespaceSettings(t *testing.T) {
+	e, err := NewEncoder([]*CompressorEntry{
+		{
+			Namespace:          "raw",
+			DisableCompression: true,
+		},
+		{
+			Namespace:        "fast",
+			CompressionLevel: 1,
+		},
+		{
+			Namespace:        "small",
+			CompressionLevel: 19,
+		},
+	}, NewBufferPool(), true)
+	assert.NoError(t, err)
+
+	object := []string{}
+	for i := 0; i < 100; i++ {
+		object = append(object, fmt.Sprintf("value %d", i%10))
+	}
+
+	raw, err := NewEncoder(nil, NewBufferPool(), false)
+	assert.NoError(t, err)
+	uncompressed, err := raw.Encode("", object)
+	assert.NoError(t, err)
+
+	// Values in namespaces with compression disabled are
+	// stored as-is.
+	rawEnc, err := e.Encode("raw", object)
+	assert.NoError(t, err)
+	assert.Equal(t, uncompressed, rawEnc)
+	rawDec, err := e.DecodeRaw("raw", rawEnc)
+	assert.NoError(t, err)
+	assert.Equal(t, rawEnc, rawDec)
+
+	fastEnc, err := e.Encode("fast", object)
+	assert.NoError(t, err)
+	smallEnc, err := e.Encode("small", object)
+	assert.NoError(t, err)
+	assert.True(t, len(fastEnc) < len(uncompressed))
+	assert.True(t, len(smallEnc) <= len(fastEnc))
+
+	for namespace, encoded := range map[string][]byte{
+		"raw":   rawEnc,
+		"fast":  fastEnc,
+		"small": smallEnc,
+	} {
+		var decoded []string
+		assert.NoError(t, e.Decode(namespace, encoded, &decoded, false))
+		assert.Equal(t, object, decoded)
+	}
+}
+
 var (
 	benchmarkCoin = &types.AccountCoin{
 		Account: &types.AccountIdentifier{
@@ -43,6 +43,11 @@ var (
 	ErrInvokeZSTDFailed           = errors.New("unable to start zstd")
 	ErrTrainZSTDFailed            = errors.New("unable to train zstd")
 	ErrWalkFilesFailed            = errors.New("unable to walk files")
+	ErrTransactionClosed          = errors.New("transaction already committed or discarded")
+
+	// ErrDatabaseReadOnly is returned when attempting to
+	// modify a database opened in read-only mode.
+	ErrDatabaseReadOnly = errors.New("database is read-only")
 
 	BadgerStorageErrs = []error{
 		ErrDatabaseOpenFailed,
@@ -66,6 +71,120 @@ var (
 		ErrInvokeZSTDFailed,
 		ErrTrainZSTDFailed,
 		ErrWalkFilesFailed,
+		ErrTransactionClosed,
+		ErrDatabaseReadOnly,
+	}
+)
+
+// KV Storage Errors
+var (
+	ErrSnapshotFailed    = errors.New("unable to create database snapshot")
+	ErrGetFailed         = errors.New("unable to get key")
+	ErrIteratorFailed    = errors.New("unable to iterate over keys")
+	ErrStoreCloseFailed  = errors.New("unable to close key-value store")
+	ErrApplyWritesFailed = errors.New("unable to apply writes")
+	ErrReadOnlyTxn       = errors.New("transaction is read-only")
+
+	// ErrTransactionConflict is returned when a transaction
+	// cannot be committed because a key it read or wrote
+	// was committed by another transaction after it was
+	// created. The transaction should be retried.
+	ErrTransactionConflict = errors.New("transaction conflict")
+
+	// ErrCheckpointUnsupported is returned when a checkpoint
+	// is requested from a KVStore that cannot create one.
+	ErrCheckpointUnsupported = errors.New("checkpoint unsupported")
+	ErrCheckpointFailed      = errors.New("unable to create checkpoint")
+
+	KVStorageErrs = []error{
+		ErrSnapshotFailed,
+		ErrGetFailed,
+		ErrIteratorFailed,
+		ErrStoreCloseFailed,
+		ErrApplyWritesFailed,
+		ErrReadOnlyTxn,
+		ErrTransactionConflict,
+		ErrCheckpointUnsupported,
+		ErrCheckpointFailed,
+	}
+)
+
+// Snapshot Errors
+var (
+	ErrSnapshotExportFailed       = errors.New("unable to export snapshot")
+	ErrSnapshotImportFailed       = errors.New("unable to import snapshot")
+	ErrSnapshotInvalid            = errors.New("invalid snapshot")
+	ErrSnapshotChecksumMismatch   = errors.New("snapshot checksum mismatch")
+	ErrSnapshotDatabaseNotEmpty   = errors.New("cannot import snapshot into non-empty database")
+	ErrSnapshotVersionUnsupported = errors.New("unsupported snapshot version")
+
+	SnapshotErrs = []error{
+		ErrSnapshotExportFailed,
+		ErrSnapshotImportFailed,
+		ErrSnapshotInvalid,
+		ErrSnapshotChecksumMismatch,
+		ErrSnapshotDatabaseNotEmpty,
+		ErrSnapshotVersionUnsupported,
+	}
+)
+
+// Maintenance Errors
+var (
+	ErrMaintenanceFailed      = errors.New("unable to run database maintenance")
+	ErrMaintenanceStatsFailed = errors.New("unable to get database maintenance stats")
+	ErrMaintenanceUnsupported = errors.New("database does not support maintenance")
+
+	MaintenanceErrs = []error{
+		ErrMaintenanceFailed,
+		ErrMaintenanceStatsFailed,
+		ErrMaintenanceUnsupported,
+	}
+)
+
+// Migration Errors
+var (
+	ErrMigrationInvalid = errors.New("invalid migration")
+	ErrMigrationFailed  = errors.New("unable to apply migration")
+
+	// ErrMigrationIrreversible is returned when migrating
+	// down past a migration that has no Down function.
+	ErrMigrationIrreversible = errors.New("migration is irreversible")
+
+	// ErrSchemaVersionUnsupported is returned when the stored
+	// schema version is newer than any known migration (the
+	// database was written by a newer version of the SDK).
+	ErrSchemaVersionUnsupported = errors.New("unsupported schema version")
+
+	ErrSchemaVersionReadFailed  = errors.New("unable to read schema version")
+	ErrSchemaVersionWriteFailed = errors.New("unable to write schema version")
+
+	MigrationErrs = []error{
+		ErrMigrationInvalid,
+		ErrMigrationFailed,
+		ErrMigrationIrreversible,
+		ErrSchemaVersionUnsupported,
+		ErrSchemaVersionReadFailed,
+		ErrSchemaVersionWriteFailed,
+	}
+)
+
+// TTL Errors
+var (
+	ErrTTLInvalid = errors.New("invalid ttl")
+
+	// ErrTTLUnsupported is returned when an entry with a TTL
+	// is written to a namespace that is not configured to
+	// expire entries.
+	ErrTTLUnsupported = errors.New("namespace does not support ttl")
+
+	ErrExpiringEntryInvalid = errors.New("expiring entry is malformed")
+	ErrExpireFailed         = errors.New("unable to expire entries")
+
+	TTLErrs = []error{
+		ErrTTLInvalid,
+		ErrTTLUnsupported,
+		ErrExpiringEntryInvalid,
+		ErrExpireFailed,
 	}
 )
 
@@ -106,6 +225,9 @@ var (
 	ErrBroadcastHandleFailureUnsuccessful = errors.New("unable to handle broadcast failure")
 	ErrBroadcastCommitDeleteFailed        = errors.New("unable to commit broadcast delete")
 	ErrBroadcastPerformFailed             = errors.New("unable to perform broadcast")
+	ErrBroadcastFeeBumpFailed             = errors.New("unable to bump broadcast fee")
+	ErrBroadcastTxAbandoned               = errors.New("unable to handle abandoned transaction")
+	ErrBroadcastFinalityCheckFailed       = errors.New("unable to check broadcast finality")
 
 	BroadcastStorageErrs = []error{
 		ErrBroadcastTxStale,
@@ -131,6 +253,9 @@ var (
 		ErrBroadcastHandleFailureUnsuccessful,
 		ErrBroadcastCommitDeleteFailed,
 		ErrBroadcastPerformFailed,
+		ErrBroadcastFeeBumpFailed,
+		ErrBroadcastTxAbandoned,
+		ErrBroadcastFinalityCheckFailed,
 	}
 )
 
@@ -159,6 +284,7 @@ var (
 	ErrCoinParseFailed              = errors.New("unable to parse amount for coin")
 	ErrCoinImportFailed             = errors.New("unable to import coins")
 	ErrCoinNotFound                 = errors.New("coin not found")
+	ErrCoinPageLimitInvalid         = errors.New("coin page limit must be positive")
 
 	CoinStorageErrs = []error{
 		ErrCoinQueryFailed,
@@ -182,6 +308,7 @@ var (
 		ErrCoinParseFailed,
 		ErrCoinImportFailed,
 		ErrCoinNotFound,
+		ErrCoinPageLimitInvalid,
 	}
 )
 
@@ -232,6 +359,8 @@ var (
 	ErrJobMetadataUpdateFailed       = errors.New("unable to update metadata")
 	ErrJobDoesNotExist               = errors.New("job does not exist")
 	ErrJobDecodeFailed               = errors.New("unable to decode job")
+	ErrJobImportFailed               = errors.New("unable to import job")
+	ErrJobAlreadyExists              = errors.New("job already exists")
 
 	JobStorageErrs = []error{
 		ErrJobsGetAllFailed,
@@ -252,6 +381,8 @@ var (
 		ErrJobMetadataUpdateFailed,
 		ErrJobDoesNotExist,
 		ErrJobDecodeFailed,
+		ErrJobImportFailed,
+		ErrJobAlreadyExists,
 	}
 )
 
@@ -280,6 +411,38 @@ var (
 	ErrPrefundedAcctStoreFailed = errors.New("unable to store prefunded account")
 	ErrRandomAddress            = errors.New("cannot select random address")
 
+	// ErrKeyEncrypted is returned when a stored key is
+	// encrypted but KeyStorage has no KeyEncrypter.
+	ErrKeyEncrypted = errors.New("key is encrypted but no key encrypter was provided")
+
+	// ErrKeyDecryptFailed is returned when a stored key
+	// cannot be decrypted (usually because of an incorrect
+	// passphrase).
+	ErrKeyDecryptFailed = errors.New("unable to decrypt key")
+
+	ErrKeyEncryptFailed = errors.New("unable to encrypt key")
+	ErrKeyExportFailed  = errors.New("unable to export keys")
+	ErrKeyAuditFailed   = errors.New("unable to log key audit event")
+
+	// ErrPassphraseEmpty is returned when a
+	// PassphraseEncrypter is created without
+	// a passphrase.
+	ErrPassphraseEmpty = errors.New("passphrase cannot be empty")
+
+	// ErrKeyDerivationFailed is returned when an encryption
+	// key cannot be derived from a passphrase.
+	ErrKeyDerivationFailed = errors.New("unable to derive encryption key")
+
+	// ErrCiphertextTooShort is returned when an encrypted
+	// private key is too short to contain a salt and nonce.
+	ErrCiphertextTooShort = errors.New("ciphertext too short")
+
+	// ErrCiphertextInvalid is returned when an encrypted
+	// private key cannot be authenticated (because the
+	// passphrase is wrong, the ciphertext was modified, or
+	// it was encrypted for another account or curve).
+	ErrCiphertextInvalid = errors.New("ciphertext invalid")
+
 	KeyStorageErrs = []error{
 		ErrAddrExists,
 		ErrAddrCheckIfExistsFailed,
@@ -300,6 +463,15 @@ var (
 		ErrAddrImportFailed,
 		ErrPrefundedAcctStoreFailed,
 		ErrRandomAddress,
+		ErrKeyEncrypted,
+		ErrKeyDecryptFailed,
+		ErrKeyEncryptFailed,
+		ErrKeyExportFailed,
+		ErrKeyAuditFailed,
+		ErrPassphraseEmpty,
+		ErrKeyDerivationFailed,
+		ErrCiphertextTooShort,
+		ErrCiphertextInvalid,
 	}
 )
 
@@ -336,6 +508,18 @@ var (
 
 	ErrHelperHandlerMissing = errors.New("balance storage helper or handler is missing")
 
+	// ErrInvalidBalanceRange is returned when the end index
+	// of a historical balance query precedes the start index.
+	ErrInvalidBalanceRange = errors.New("invalid balance range")
+
+	// ErrBootstrapFileInvalid is returned when a bootstrap
+	// file cannot be parsed as JSON or CSV.
+	ErrBootstrapFileInvalid = errors.New("bootstrap file invalid")
+
+	// ErrBootstrapEntryInvalid is returned when an entry
+	// in a bootstrap file fails validation.
+	ErrBootstrapEntryInvalid = errors.New("bootstrap entry invalid")
+
 	BalanceStorageErrs = []error{
 		ErrNegativeBalance,
 		ErrInvalidLiveBalance,
@@ -345,6 +529,9 @@ var (
 		ErrInvalidChangeValue,
 		ErrInvalidValue,
 		ErrHelperHandlerMissing,
+		ErrInvalidBalanceRange,
+		ErrBootstrapFileInvalid,
+		ErrBootstrapEntryInvalid,
 	}
 )
 
@@ -403,6 +590,8 @@ var (
 	ErrNothingToPrune                 = errors.New("nothing to prune")
 	ErrPruningFailed                  = errors.New("pruning failed")
 	ErrCannotPruneTransaction         = errors.New("cannot prune transaction")
+	ErrPrunePolicyFailed              = errors.New("prune policy failed")
+	ErrPruneWorkerFailed              = errors.New("prune worker failed")
 	ErrCannotStoreBackwardRelation    = errors.New("cannot store backward relation")
 	ErrCannotRemoveBackwardRelation   = errors.New("cannot remove backward relation")
 
@@ -440,11 +629,94 @@ var (
 		ErrNothingToPrune,
 		ErrPruningFailed,
 		ErrCannotPruneTransaction,
+		ErrPrunePolicyFailed,
+		ErrPruneWorkerFailed,
 		ErrCannotStoreBackwardRelation,
 		ErrCannotRemoveBackwardRelation,
 	}
 )
 
+// Transaction Index Storage Errors
+var (
+	// ErrSearchOperatorInvalid is returned when a search
+	// request contains an unsupported operator.
+	ErrSearchOperatorInvalid = errors.New("search operator invalid")
+
+	// ErrSearchOffsetInvalid is returned when a search
+	// request contains a negative offset.
+	ErrSearchOffsetInvalid = errors.New("search offset invalid")
+
+	// ErrSearchLimitInvalid is returned when a search
+	// request contains a limit that is not positive or
+	// exceeds the maximum limit.
+	ErrSearchLimitInvalid = errors.New("search limit invalid")
+
+	// ErrSearchSuccessUnsupported is returned when a search
+	// request filters on success but no asserter was provided
+	// to determine which operation statuses are successful.
+	ErrSearchSuccessUnsupported = errors.New("search by success unsupported without asserter")
+
+	// ErrInvalidTimestampRange is returned when a timestamp
+	// range is negative or the start is after the end.
+	ErrInvalidTimestampRange = errors.New("invalid timestamp range")
+
+	ErrTransactionIndexStoreFailed  = errors.New("unable to store transaction index")
+	ErrTransactionIndexDeleteFailed = errors.New("unable to delete transaction index")
+	ErrTransactionIndexQueryFailed  = errors.New("unable to query transaction index")
+
+	TransactionIndexStorageErrs = []error{
+		ErrSearchOperatorInvalid,
+		ErrSearchOffsetInvalid,
+		ErrSearchLimitInvalid,
+		ErrSearchSuccessUnsupported,
+		ErrInvalidTimestampRange,
+		ErrTransactionIndexStoreFailed,
+		ErrTransactionIndexDeleteFailed,
+		ErrTransactionIndexQueryFailed,
+	}
+)
+
+// Reconciliation Storage Errors
+var (
+	// ErrReconciliationRecordInvalid is returned when a
+	// reconciliation record is missing an account, currency,
+	// or result.
+	ErrReconciliationRecordInvalid = errors.New("reconciliation record invalid")
+
+	// ErrReconciliationQueryInvalid is returned when a
+	// reconciliation history query contains an invalid
+	// limit or block range.
+	ErrReconciliationQueryInvalid = errors.New("reconciliation query invalid")
+
+	ErrReconciliationStoreFailed = errors.New("unable to store reconciliation")
+	ErrReconciliationQueryFailed = errors.New("unable to query reconciliations")
+	ErrReconciliationStatsFailed = errors.New("unable to compute reconciliation stats")
+
+	ErrReconcilerQueueLoadFailed   = errors.New("unable to load reconciler queue")
+	ErrReconcilerQueueUpdateFailed = errors.New("unable to update reconciler queue")
+
+	ReconciliationStorageErrs = []error{
+		ErrReconciliationRecordInvalid,
+		ErrReconciliationQueryInvalid,
+		ErrReconciliationStoreFailed,
+		ErrReconciliationQueryFailed,
+		ErrReconciliationStatsFailed,
+		ErrReconcilerQueueLoadFailed,
+		ErrReconcilerQueueUpdateFailed,
+	}
+)
+
+// Checkpoint Storage Errors
+var (
+	ErrCheckpointLoadFailed = errors.New("unable to load checkpoint")
+	ErrCheckpointSaveFailed = errors.New("unable to save checkpoint")
+
+	CheckpointStorageErrs = []error{
+		ErrCheckpointLoadFailed,
+		ErrCheckpointSaveFailed,
+	}
+)
+
 // Err takes an error as an argument and returns
 // whether or not the error is one thrown by the storage
 // along with the specific source of the error
@@ -452,12 +724,20 @@ func Err(err error) (bool, string) {
 	storageErrs := map[string][]error{
 		"balance storage error":   BalanceStorageErrs,
 		"block storage error":     BlockStorageErrs,
+		"transaction index error": TransactionIndexStorageErrs,
 		"coin storage error":      CoinStorageErrs,
 		"key storage error":       KeyStorageErrs,
 		"badger storage error":    BadgerStorageErrs,
+		"kv storage error":        KVStorageErrs,
+		"snapshot error":          SnapshotErrs,
+		"maintenance error":       MaintenanceErrs,
+		"migration error":         MigrationErrs,
+		"ttl error":               TTLErrs,
 		"compressor error":        CompressorErrs,
 		"job storage error":       JobStorageErrs,
 		"broadcast storage error": BroadcastStorageErrs,
+		"reconciliation error":    ReconciliationStorageErrs,
+		"checkpoint error":        CheckpointStorageErrs,
 	}
 
 	for key, val := range storageErrs {
@@ -47,6 +47,41 @@ func TestErr(t *testing.T) {
 			is:     true,
 			source: "badger storage error",
 		},
+		"kv storage error": {
+			err:    ErrSnapshotFailed,
+			is:     true,
+			source: "kv storage error",
+		},
+		"snapshot error": {
+			err:    ErrSnapshotInvalid,
+			is:     true,
+			source: "snapshot error",
+		},
+		"maintenance error": {
+			err:    ErrMaintenanceFailed,
+			is:     true,
+			source: "maintenance error",
+		},
+		"migration error": {
+			err:    ErrSchemaVersionUnsupported,
+			is:     true,
+			source: "migration error",
+		},
+		"ttl error": {
+			err:    ErrTTLUnsupported,
+			is:     true,
+			source: "ttl error",
+		},
+		"transaction index error": {
+			err:    ErrSearchLimitInvalid,
+			is:     true,
+			source: "transaction index error",
+		},
+		"reconciliation error": {
+			err:    ErrReconciliationQueryInvalid,
+			is:     true,
+			source: "reconciliation error",
+		},
 		"broadcast storage error": {
 			err:    ErrBroadcastTxStale,
 			is:     true,
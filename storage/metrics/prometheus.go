@@ -0,0 +1,237 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics implements database.Metrics
+// with Prometheus collectors.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+)
+
+const (
+	metricsResultSuccess = "success"
+	metricsResultFailure = "failure"
+)
+
+var _ database.Metrics = (*PrometheusMetrics)(nil)
+var _ prometheus.Collector = (*PrometheusMetrics)(nil)
+
+// PrometheusMetrics is an implementation of database.Metrics
+// that can be registered as a prometheus.Collector.
+type PrometheusMetrics struct {
+	operations        *prometheus.CounterVec
+	operationBytes    *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+
+	commits        *prometheus.CounterVec
+	commitDuration prometheus.Histogram
+	conflicts      prometheus.Counter
+
+	gcRuns           *prometheus.CounterVec
+	gcReclaimedBytes prometheus.Counter
+	gcDuration       prometheus.Histogram
+
+	cacheHits     *prometheus.Desc
+	cacheMisses   *prometheus.Desc
+	cacheHitRatio *prometheus.Desc
+
+	providersMutex sync.Mutex
+	providers      []database.CacheStatsProvider
+}
+
+// NewPrometheusMetrics returns a new PrometheusMetrics
+// where all metric names are prefixed with namespace.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	return &PrometheusMetrics{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Number of database operations by operation and key namespace.",
+		}, []string{"operation", "namespace"}),
+		operationBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operation_bytes_total",
+			Help:      "Value bytes read or written by operation and key namespace.",
+		}, []string{"operation", "namespace"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of database operations by operation and key namespace.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 10), // nolint:gomnd
+		}, []string{"operation", "namespace"}),
+		commits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commits_total",
+			Help:      "Number of transaction commits by result.",
+		}, []string{"result"}),
+		commitDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "commit_duration_seconds",
+			Help:      "Latency of transaction commits.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10), // nolint:gomnd
+		}),
+		conflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "conflicts_total",
+			Help:      "Number of transaction commits that failed because of a conflict.",
+		}),
+		gcRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gc_runs_total",
+			Help:      "Number of garbage collection runs by result.",
+		}, []string{"result"}),
+		gcReclaimedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gc_reclaimed_bytes_total",
+			Help:      "Bytes reclaimed by garbage collection.",
+		}),
+		gcDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "gc_duration_seconds",
+			Help:      "Duration of garbage collection runs.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 4, 10), // nolint:gomnd
+		}),
+		cacheHits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_hits_total"),
+			"Number of cache hits by cache.",
+			[]string{"cache"},
+			nil,
+		),
+		cacheMisses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_misses_total"),
+			"Number of cache misses by cache.",
+			[]string{"cache"},
+			nil,
+		),
+		cacheHitRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_hit_ratio"),
+			"Fraction of cache lookups that were hits by cache.",
+			[]string{"cache"},
+			nil,
+		),
+	}
+}
+
+// AddCacheStatsProvider registers a database.CacheStatsProvider
+// to report cache statistics from when collected.
+func (p *PrometheusMetrics) AddCacheStatsProvider(provider database.CacheStatsProvider) {
+	p.providersMutex.Lock()
+	defer p.providersMutex.Unlock()
+
+	p.providers = append(p.providers, provider)
+}
+
+// ObserveOperation records a database operation.
+func (p *PrometheusMetrics) ObserveOperation(
+	operation database.MetricsOperation,
+	namespace string,
+	bytes int,
+	duration time.Duration,
+) {
+	labels := prometheus.Labels{"operation": string(operation), "namespace": namespace}
+	p.operations.With(labels).Inc()
+	p.operationBytes.With(labels).Add(float64(bytes))
+	p.operationDuration.With(labels).Observe(duration.Seconds())
+}
+
+// ObserveCommit records a transaction commit.
+func (p *PrometheusMetrics) ObserveCommit(duration time.Duration, err error) {
+	p.commits.WithLabelValues(metricsResult(err)).Inc()
+	p.commitDuration.Observe(duration.Seconds())
+}
+
+// ObserveConflict records a transaction conflict.
+func (p *PrometheusMetrics) ObserveConflict() {
+	p.conflicts.Inc()
+}
+
+// ObserveGC records a garbage collection run.
+func (p *PrometheusMetrics) ObserveGC(duration time.Duration, reclaimedBytes int64, err error) {
+	p.gcRuns.WithLabelValues(metricsResult(err)).Inc()
+	p.gcDuration.Observe(duration.Seconds())
+	if reclaimedBytes > 0 {
+		p.gcReclaimedBytes.Add(float64(reclaimedBytes))
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (p *PrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	p.operations.Describe(ch)
+	p.operationBytes.Describe(ch)
+	p.operationDuration.Describe(ch)
+	p.commits.Describe(ch)
+	p.commitDuration.Describe(ch)
+	p.conflicts.Describe(ch)
+	p.gcRuns.Describe(ch)
+	p.gcReclaimedBytes.Describe(ch)
+	p.gcDuration.Describe(ch)
+	ch <- p.cacheHits
+	ch <- p.cacheMisses
+	ch <- p.cacheHitRatio
+}
+
+// Collect implements prometheus.Collector.
+func (p *PrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	p.operations.Collect(ch)
+	p.operationBytes.Collect(ch)
+	p.operationDuration.Collect(ch)
+	p.commits.Collect(ch)
+	p.commitDuration.Collect(ch)
+	p.conflicts.Collect(ch)
+	p.gcRuns.Collect(ch)
+	p.gcReclaimedBytes.Collect(ch)
+	p.gcDuration.Collect(ch)
+
+	p.providersMutex.Lock()
+	providers := make([]database.CacheStatsProvider, len(p.providers))
+	copy(providers, p.providers)
+	p.providersMutex.Unlock()
+
+	for _, provider := range providers {
+		for _, stats := range provider.CacheStats() {
+			ch <- prometheus.MustNewConstMetric(
+				p.cacheHits,
+				prometheus.CounterValue,
+				float64(stats.Hits),
+				stats.Name,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				p.cacheMisses,
+				prometheus.CounterValue,
+				float64(stats.Misses),
+				stats.Name,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				p.cacheHitRatio,
+				prometheus.GaugeValue,
+				stats.HitRatio(),
+				stats.Name,
+			)
+		}
+	}
+}
+
+func metricsResult(err error) string {
+	if err != nil {
+		return metricsResultFailure
+	}
+
+	return metricsResultSuccess
+}
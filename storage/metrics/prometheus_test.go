@@ -0,0 +1,163 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+type fakeCacheStatsProvider struct {
+	stats []*database.CacheStats
+}
+
+func (f *fakeCacheStatsProvider) CacheStats() []*database.CacheStats {
+	return f.stats
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics("test")
+
+	metrics.ObserveOperation(database.ReadOperation, "balance", 10, time.Millisecond)
+	metrics.ObserveOperation(database.ReadOperation, "balance", 5, time.Millisecond)
+	metrics.ObserveOperation(database.WriteOperation, "block", 7, time.Millisecond)
+	metrics.ObserveCommit(time.Millisecond, nil)
+	metrics.ObserveCommit(time.Millisecond, errors.New("conflict"))
+	metrics.ObserveConflict()
+	metrics.ObserveGC(time.Second, 100, nil)
+	metrics.ObserveGC(time.Second, 0, errors.New("gc failed"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(
+		metrics.operations.WithLabelValues("read", "balance"),
+	))
+	assert.Equal(t, float64(15), testutil.ToFloat64(
+		metrics.operationBytes.WithLabelValues("read", "balance"),
+	))
+	assert.Equal(t, float64(7), testutil.ToFloat64(
+		metrics.operationBytes.WithLabelValues("write", "block"),
+	))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.commits.WithLabelValues("success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.commits.WithLabelValues("failure")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.conflicts))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.gcRuns.WithLabelValues("success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.gcRuns.WithLabelValues("failure")))
+	assert.Equal(t, float64(100), testutil.ToFloat64(metrics.gcReclaimedBytes))
+
+	metrics.AddCacheStatsProvider(&fakeCacheStatsProvider{
+		stats: []*database.CacheStats{{Name: "block", Hits: 3, Misses: 1}},
+	})
+	expected := `
+# HELP test_cache_hit_ratio Fraction of cache lookups that were hits by cache.
+# TYPE test_cache_hit_ratio gauge
+test_cache_hit_ratio{cache="block"} 0.75
+# HELP test_cache_hits_total Number of cache hits by cache.
+# TYPE test_cache_hits_total counter
+test_cache_hits_total{cache="block"} 3
+# HELP test_cache_misses_total Number of cache misses by cache.
+# TYPE test_cache_misses_total counter
+test_cache_misses_total{cache="block"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(
+		metrics,
+		strings.NewReader(expected),
+		"test_cache_hit_ratio",
+		"test_cache_hits_total",
+		"test_cache_misses_total",
+	))
+}
+
+func TestDatabaseMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	badgerMetrics := NewPrometheusMetrics("badger")
+	badgerDB, err := database.NewBadgerDatabase(
+		ctx,
+		newDir,
+		database.WithIndexCacheSize(database.TinyIndexCacheSize),
+		database.WithMetrics(badgerMetrics),
+	)
+	assert.NoError(t, err)
+	defer badgerDB.Close(ctx)
+	badgerMetrics.AddCacheStatsProvider(badgerDB.(database.CacheStatsProvider))
+
+	memoryMetrics := NewPrometheusMetrics("memory")
+	memoryDB, err := database.NewMemoryDatabase(ctx, database.WithKVMetrics(memoryMetrics))
+	assert.NoError(t, err)
+	defer memoryDB.Close(ctx)
+
+	tests := map[string]struct {
+		db      database.Database
+		metrics *PrometheusMetrics
+	}{
+		"badger": {db: badgerDB, metrics: badgerMetrics},
+		"memory": {db: memoryDB, metrics: memoryMetrics},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			txn := test.db.Transaction(ctx)
+			assert.NoError(t, txn.Set(ctx, []byte("balance/a"), []byte("hello"), true))
+			assert.NoError(t, txn.Set(ctx, []byte("balance/b"), []byte("world"), true))
+			assert.NoError(t, txn.Delete(ctx, []byte("block/a")))
+			assert.NoError(t, txn.Commit(ctx))
+
+			txn = test.db.ReadTransaction(ctx)
+			exists, _, err := txn.Get(ctx, []byte("balance/a"))
+			assert.NoError(t, err)
+			assert.True(t, exists)
+			_, err = txn.Scan(
+				ctx,
+				[]byte("balance/"),
+				[]byte("balance/"),
+				func(k []byte, v []byte) error { return nil },
+				false,
+				false,
+			)
+			assert.NoError(t, err)
+			txn.Discard(ctx)
+
+			m := test.metrics
+			assert.Equal(t, float64(2), testutil.ToFloat64(
+				m.operations.WithLabelValues("write", "balance"),
+			))
+			assert.Equal(t, float64(10), testutil.ToFloat64(
+				m.operationBytes.WithLabelValues("write", "balance"),
+			))
+			assert.Equal(t, float64(1), testutil.ToFloat64(
+				m.operations.WithLabelValues("delete", "block"),
+			))
+			assert.Equal(t, float64(1), testutil.ToFloat64(
+				m.operations.WithLabelValues("read", "balance"),
+			))
+			assert.Equal(t, float64(10), testutil.ToFloat64(
+				m.operationBytes.WithLabelValues("scan", "balance"),
+			))
+			assert.Equal(t, float64(1), testutil.ToFloat64(m.commits.WithLabelValues("success")))
+		})
+	}
+}
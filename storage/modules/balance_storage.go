@@ -21,6 +21,8 @@ import (
 	"log"
 	"math/big"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/neilotoole/errgroup"
@@ -57,11 +59,16 @@ const (
 	// maxBalancePruneSize is the maximum number of balances
 	// we should consider pruning at one time.
 	maxBalancePruneSize = 5000
+
+	// RetainAllBalances can be provided to WithBalanceRetention
+	// to never prune historical balances.
+	RetainAllBalances = -1
 )
 
 var (
 	errAccountFound = errors.New("account found")
 	errTooManyKeys  = errors.New("too many keys")
+	errRangeEnd     = errors.New("range end")
 )
 
 /*
@@ -153,17 +160,43 @@ type BalanceStorage struct {
 	pendingReconciliationMutex *utils.PriorityMutex
 
 	parser *parser.Parser
+
+	// retention is the number of blocks of historical
+	// balances kept below any index provided to PruneBalances.
+	retention int64
+}
+
+// BalanceStorageOption is used to overwrite default values in
+// BalanceStorage construction. Any Option not provided
+// falls back to the default value.
+type BalanceStorageOption func(b *BalanceStorage)
+
+// WithBalanceRetention sets the number of blocks of historical
+// balances to retain below any index provided to PruneBalances.
+// If RetainAllBalances is provided, historical balances are
+// never pruned.
+func WithBalanceRetention(blocks int64) BalanceStorageOption {
+	return func(b *BalanceStorage) {
+		b.retention = blocks
+	}
 }
 
 // NewBalanceStorage returns a new BalanceStorage.
 func NewBalanceStorage(
 	db database.Database,
+	options ...BalanceStorageOption,
 ) *BalanceStorage {
-	return &BalanceStorage{
+	b := &BalanceStorage{
 		db:                         db,
 		numCPU:                     runtime.NumCPU(),
 		pendingReconciliationMutex: new(utils.PriorityMutex),
 	}
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
 }
 
 // Initialize adds a BalanceStorageHelper and BalanceStorageHandler to BalanceStorage.
@@ -761,12 +794,24 @@ func (b *BalanceStorage) OrphanBalance(
 // <= some index. This can significantly reduce storage
 // usage in scenarios where historical balances are only
 // retrieved once (like reconciliation).
+//
+// If a retention was configured with WithBalanceRetention,
+// only historical balances <= index - retention are removed.
 func (b *BalanceStorage) PruneBalances(
 	ctx context.Context,
 	account *types.AccountIdentifier,
 	currency *types.Currency,
 	index int64,
 ) error {
+	if b.retention == RetainAllBalances {
+		return nil
+	}
+
+	index -= b.retention
+	if index < 0 {
+		return nil
+	}
+
 	key := GetAccountKey(pruneNamespace, account, currency)
 	dbTx := b.db.WriteTransaction(ctx, string(key), false)
 	defer dbTx.Discard(ctx)
@@ -892,6 +937,35 @@ func (b *BalanceStorage) UpdateBalance(
 	return newAccount, nil
 }
 
+// WarmAccounts reads the account and current balance
+// of each *types.AccountCurrency so that subsequent reads
+// of these frequently accessed accounts are served from the
+// database cache. This is typically called after opening
+// storage with any accounts known to be accessed often (ex:
+// exchange hot wallets).
+func (b *BalanceStorage) WarmAccounts(
+	ctx context.Context,
+	accounts []*types.AccountCurrency,
+) error {
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	for _, account := range accounts {
+		for _, namespace := range []string{accountNamespace, balanceNamespace} {
+			key := GetAccountKey(namespace, account.Account, account.Currency)
+			if _, _, err := dbTx.Get(ctx, key); err != nil {
+				return fmt.Errorf(
+					"%w: unable to warm account %s",
+					err,
+					types.PrintStruct(account),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 // GetBalance returns the balance of a types.AccountIdentifier
 // at the canonical block of a certain index.
 func (b *BalanceStorage) GetBalance(
@@ -926,24 +1000,63 @@ func (b *BalanceStorage) GetBalanceTransactional(
 	currency *types.Currency,
 	index int64,
 ) (*types.Amount, error) {
+	if err := b.checkBalanceAvailable(ctx, dbTx, account, currency, index); err != nil {
+		return nil, err
+	}
+
+	amount, err := b.getHistoricalBalance(
+		ctx,
+		dbTx,
+		account,
+		currency,
+		index,
+	)
+	// If account record exists but we don't
+	// find any records for the index, we assume
+	// the balance to be 0 (i.e. before any balance
+	// changes applied). If syncing starts after
+	// genesis, this behavior could cause issues.
+	if errors.Is(err, storageErrs.ErrAccountMissing) {
+		return &types.Amount{
+			Value:    "0",
+			Currency: currency,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return amount, nil
+}
+
+// checkBalanceAvailable returns an error if the account
+// does not exist or its historical balance at index
+// has been pruned.
+func (b *BalanceStorage) checkBalanceAvailable(
+	ctx context.Context,
+	dbTx database.Transaction,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	index int64,
+) error {
 	key := GetAccountKey(balanceNamespace, account, currency)
 	exists, _, err := dbTx.Get(ctx, key)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if !exists {
-		return nil, storageErrs.ErrAccountMissing
+		return storageErrs.ErrAccountMissing
 	}
 
 	key = GetAccountKey(pruneNamespace, account, currency)
 	exists, lastPruned, err := BigIntGet(ctx, key, dbTx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if exists && lastPruned.Int64() >= index {
-		return nil, fmt.Errorf(
+		return fmt.Errorf(
 			"%w: desired %d last pruned %d",
 			storageErrs.ErrBalancePruned,
 			index,
@@ -951,29 +1064,153 @@ func (b *BalanceStorage) GetBalanceTransactional(
 		)
 	}
 
-	amount, err := b.getHistoricalBalance(
-		ctx,
-		dbTx,
-		account,
-		currency,
-		index,
-	)
-	// If account record exists but we don't
-	// find any records for the index, we assume
-	// the balance to be 0 (i.e. before any balance
-	// changes applied). If syncing starts after
-	// genesis, this behavior could cause issues.
-	if errors.Is(err, storageErrs.ErrAccountMissing) {
-		return &types.Amount{
+	return nil
+}
+
+// HistoricalBalance is the balance of a types.AccountIdentifier
+// after all balance changes in the block at Index were applied.
+type HistoricalBalance struct {
+	Index  int64         `json:"index"`
+	Amount *types.Amount `json:"amount"`
+}
+
+// GetBalanceAtBlock returns the balance of a types.AccountIdentifier
+// at the canonical block of a certain index and the index of the
+// block where that balance was last updated. If there were no
+// balance changes at or before index, the returned Index is -1.
+func (b *BalanceStorage) GetBalanceAtBlock(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	index int64,
+) (*HistoricalBalance, error) {
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	balance, err := b.getBalanceAtBlock(ctx, dbTx, account, currency, index)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get balance", err)
+	}
+
+	return balance, nil
+}
+
+// getBalanceAtBlock returns the balance of a types.AccountIdentifier
+// at a certain index in a database transaction.
+func (b *BalanceStorage) getBalanceAtBlock(
+	ctx context.Context,
+	dbTx database.Transaction,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	index int64,
+) (*HistoricalBalance, error) {
+	if err := b.checkBalanceAvailable(ctx, dbTx, account, currency, index); err != nil {
+		return nil, err
+	}
+
+	balance := &HistoricalBalance{
+		Index: -1,
+		Amount: &types.Amount{
 			Value:    "0",
 			Currency: currency,
-		}, nil
+		},
 	}
+	_, err := dbTx.Scan(
+		ctx,
+		GetHistoricalBalancePrefix(account, currency),
+		GetHistoricalBalanceKey(account, currency, index),
+		func(k []byte, v []byte) error {
+			balanceIndex, err := parseHistoricalBalanceIndex(k)
+			if err != nil {
+				return err
+			}
+
+			balance.Index = balanceIndex
+			balance.Amount.Value = new(big.Int).SetBytes(v).String()
+			return errAccountFound
+		},
+		false,
+		true,
+	)
+	if err != nil && !errors.Is(err, errAccountFound) {
+		return nil, fmt.Errorf("%w: database scan failed", err)
+	}
+
+	return balance, nil
+}
+
+// GetBalanceRange returns the balance of a types.AccountIdentifier
+// at startIndex followed by every balance change in
+// (startIndex, endIndex], ordered by index. This allows callers to
+// determine the balance at any index in the range.
+func (b *BalanceStorage) GetBalanceRange(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	startIndex int64,
+	endIndex int64,
+) ([]*HistoricalBalance, error) {
+	if endIndex < startIndex {
+		return nil, fmt.Errorf(
+			"%w: end index %d is less than start index %d",
+			storageErrs.ErrInvalidBalanceRange,
+			endIndex,
+			startIndex,
+		)
+	}
+
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	start, err := b.getBalanceAtBlock(ctx, dbTx, account, currency, startIndex)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: unable to get balance", err)
 	}
 
-	return amount, nil
+	balances := []*HistoricalBalance{start}
+	_, err = dbTx.Scan(
+		ctx,
+		GetHistoricalBalancePrefix(account, currency),
+		GetHistoricalBalanceKey(account, currency, startIndex+1),
+		func(k []byte, v []byte) error {
+			balanceIndex, err := parseHistoricalBalanceIndex(k)
+			if err != nil {
+				return err
+			}
+
+			if balanceIndex > endIndex {
+				return errRangeEnd
+			}
+
+			balances = append(balances, &HistoricalBalance{
+				Index: balanceIndex,
+				Amount: &types.Amount{
+					Value:    new(big.Int).SetBytes(v).String(),
+					Currency: currency,
+				},
+			})
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil && !errors.Is(err, errRangeEnd) {
+		return nil, fmt.Errorf("%w: database scan failed", err)
+	}
+
+	return balances, nil
+}
+
+// parseHistoricalBalanceIndex returns the block index
+// encoded in a historical balance key.
+func parseHistoricalBalanceIndex(key []byte) (int64, error) {
+	parts := strings.Split(string(key), "/")
+	index, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("%w: unable to parse historical balance key %s", err, string(key))
+	}
+
+	return index, nil
 }
 
 func (b *BalanceStorage) fetchAndSetBalance(
@@ -1078,63 +1315,149 @@ type BootstrapBalance struct {
 	Value    string                   `json:"value,omitempty"`
 }
 
+// bootstrapBalanceParser parses *BootstrapBalance
+// from JSON and CSV bootstrap files.
+var bootstrapBalanceParser = &bootstrapParser{
+	columns: []string{
+		BootstrapAddressColumn,
+		BootstrapSymbolColumn,
+		BootstrapDecimalsColumn,
+		BootstrapValueColumn,
+	},
+	parseJSON: func(data []byte) (interface{}, error) {
+		var balance BootstrapBalance
+		if err := decodeBootstrapJSON(data, &balance); err != nil {
+			return nil, err
+		}
+
+		return &balance, nil
+	},
+	parseCSV: func(row *bootstrapRow) (interface{}, error) {
+		currency, err := row.currency()
+		if err != nil {
+			return nil, err
+		}
+
+		return &BootstrapBalance{
+			Account:  row.account(),
+			Currency: currency,
+			Value:    row.get(BootstrapValueColumn),
+		}, nil
+	},
+}
+
+// validateBootstrapBalance ensures a *BootstrapBalance
+// is a positive balance of a valid account and currency.
+func validateBootstrapBalance(balance *BootstrapBalance) error {
+	// Ensure change.Difference is valid
+	amountValue, ok := new(big.Int).SetString(balance.Value, 10)
+	if !ok {
+		return fmt.Errorf("%s is not an integer", balance.Value)
+	}
+
+	if amountValue.Sign() < 1 {
+		return fmt.Errorf("cannot bootstrap zero or negative balance %s", amountValue.String())
+	}
+
+	if err := asserter.AccountIdentifier(balance.Account); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBootstrapEntryInvalid, err)
+	}
+
+	if err := asserter.Currency(balance.Currency); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBootstrapEntryInvalid, err)
+	}
+
+	return nil
+}
+
 // BootstrapBalances is utilized to set the balance of
 // any number of AccountIdentifiers at the genesis blocks.
 // This is particularly useful for setting the value of
 // accounts that received an allocation in the genesis block.
+//
+// The bootstrap file may be a JSON array of *BootstrapBalance
+// or a CSV file (see BootstrapFormatCSV) and is streamed, so
+// it is never loaded into memory at once. All entries are
+// validated before any balance is written. Balances are then
+// written in batches (see WithBootstrapBatchSize), so an error
+// while writing may leave some balances bootstrapped.
 func (b *BalanceStorage) BootstrapBalances(
 	ctx context.Context,
 	bootstrapBalancesFile string,
 	genesisBlockIdentifier *types.BlockIdentifier,
+	options ...BootstrapOption,
 ) error {
-	// Read bootstrap file
-	balances := []*BootstrapBalance{}
-	if err := utils.LoadAndParse(bootstrapBalancesFile, &balances); err != nil {
+	config := newBootstrapConfig(options...)
+
+	// Validate bootstrap file
+	if _, err := streamBootstrapFile(
+		ctx,
+		bootstrapBalancesFile,
+		config,
+		bootstrapBalanceParser,
+		func(entry interface{}, _ *BootstrapProgress) error {
+			return validateBootstrapBalance(entry.(*BootstrapBalance))
+		},
+	); err != nil {
 		return err
 	}
 
 	// Update balances in database
 	dbTransaction := b.db.Transaction(ctx)
-	defer dbTransaction.Discard(ctx)
+	defer func() {
+		dbTransaction.Discard(ctx)
+	}()
 
-	for _, balance := range balances {
-		// Ensure change.Difference is valid
-		amountValue, ok := new(big.Int).SetString(balance.Value, 10)
-		if !ok {
-			return fmt.Errorf("%s is not an integer", balance.Value)
-		}
+	pending := 0
+	progress, err := streamBootstrapFile(
+		ctx,
+		bootstrapBalancesFile,
+		config,
+		bootstrapBalanceParser,
+		func(entry interface{}, progress *BootstrapProgress) error {
+			balance := entry.(*BootstrapBalance)
+			err := b.SetBalance(
+				ctx,
+				dbTransaction,
+				balance.Account,
+				&types.Amount{
+					Value:    balance.Value,
+					Currency: balance.Currency,
+				},
+				genesisBlockIdentifier,
+			)
+			if err != nil {
+				return err
+			}
 
-		if amountValue.Sign() < 1 {
-			return fmt.Errorf("cannot bootstrap zero or negative balance %s", amountValue.String())
-		}
+			pending++
+			if pending < config.batchSize {
+				return nil
+			}
 
-		log.Printf(
-			"Setting account %s balance to %s %+v\n",
-			balance.Account.Address,
-			balance.Value,
-			balance.Currency,
-		)
+			if err := dbTransaction.Commit(ctx); err != nil {
+				return err
+			}
 
-		err := b.SetBalance(
-			ctx,
-			dbTransaction,
-			balance.Account,
-			&types.Amount{
-				Value:    balance.Value,
-				Currency: balance.Currency,
-			},
-			genesisBlockIdentifier,
-		)
-		if err != nil {
-			return err
-		}
+			dbTransaction = b.db.Transaction(ctx)
+			pending = 0
+			config.reportProgress(progress)
+			return nil
+		},
+	)
+	if err != nil {
+		return err
 	}
 
 	if err := dbTransaction.Commit(ctx); err != nil {
 		return err
 	}
 
-	log.Printf("%d Balances Bootstrapped\n", len(balances))
+	if pending > 0 {
+		config.reportProgress(progress)
+	}
+
+	log.Printf("%d Balances Bootstrapped\n", progress.Entries)
 	return nil
 }
 
@@ -1213,6 +1213,147 @@ func TestBootstrapBalances(t *testing.T) {
 	mockHandler.AssertExpectations(t)
 }
 
+func TestBootstrapBalancesCSV(t *testing.T) {
+	var (
+		genesisBlockIdentifier = &types.BlockIdentifier{
+			Index: 0,
+			Hash:  "0",
+		}
+
+		currency = &types.Currency{
+			Symbol:   "BTC",
+			Decimals: 8,
+		}
+
+		account1 = &types.AccountIdentifier{
+			Address: "addr1",
+		}
+
+		account2 = &types.AccountIdentifier{
+			Address: "addr2",
+		}
+
+		account3 = &types.AccountIdentifier{
+			Address: "addr2",
+			SubAccount: &types.SubAccountIdentifier{
+				Address: "stake",
+			},
+		}
+	)
+
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewBalanceStorage(database)
+	mockHelper := &mocks.BalanceStorageHelper{}
+	mockHandler := &mocks.BalanceStorageHandler{}
+	mockHelper.On("Asserter").Return(baseAsserter())
+	mockHelper.On("ExemptFunc").Return(exemptFunc())
+	mockHelper.On("BalanceExemptions").Return([]*types.BalanceExemption{})
+	storage.Initialize(mockHelper, mockHandler)
+	bootstrapBalancesFile := path.Join(newDir, "balances.csv")
+
+	t.Run("Missing column", func(t *testing.T) {
+		assert.NoError(
+			t,
+			ioutil.WriteFile(
+				bootstrapBalancesFile,
+				[]byte("address,symbol,value\naddr1,BTC,10\n"),
+				utils.DefaultFilePermissions,
+			),
+		)
+
+		err := storage.BootstrapBalances(ctx, bootstrapBalancesFile, genesisBlockIdentifier)
+		assert.True(t, errors.Is(err, storageErrs.ErrBootstrapFileInvalid))
+		assert.Contains(t, err.Error(), "header is missing column decimals")
+	})
+
+	t.Run("Invalid entry", func(t *testing.T) {
+		assert.NoError(
+			t,
+			ioutil.WriteFile(
+				bootstrapBalancesFile,
+				[]byte("address,symbol,decimals,value\naddr1,BTC,8,10\n,BTC,8,10\n"),
+				utils.DefaultFilePermissions,
+			),
+		)
+
+		err := storage.BootstrapBalances(ctx, bootstrapBalancesFile, genesisBlockIdentifier)
+		assert.True(t, errors.Is(err, storageErrs.ErrBootstrapEntryInvalid))
+
+		// No balances are written when any entry is invalid
+		accounts, err := storage.GetAllAccountCurrency(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 0)
+	})
+
+	t.Run("Set balances in batches", func(t *testing.T) {
+		assert.NoError(
+			t,
+			ioutil.WriteFile(
+				bootstrapBalancesFile,
+				[]byte(
+					"value, symbol, decimals, address, sub_account_address\n"+
+						"10,BTC,8,addr1,\n"+
+						"20,BTC,8,addr2,\n"+
+						"30,BTC,8,addr2,stake\n",
+				),
+				utils.DefaultFilePermissions,
+			),
+		)
+
+		mockHandler.On("AccountsSeen", ctx, mock.Anything, 1).Return(nil).Times(3)
+		progress := []int64{}
+		err := storage.BootstrapBalances(
+			ctx,
+			bootstrapBalancesFile,
+			genesisBlockIdentifier,
+			WithBootstrapBatchSize(2),
+			WithBootstrapProgress(func(p *BootstrapProgress) {
+				assert.True(t, p.BytesRead <= p.TotalBytes)
+				progress = append(progress, p.Entries)
+			}),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 3}, progress)
+
+		for account, value := range map[*types.AccountIdentifier]string{
+			account1: "10",
+			account2: "20",
+			account3: "30",
+		} {
+			amount, err := storage.GetOrSetBalance(
+				ctx,
+				account,
+				currency,
+				genesisBlockIdentifier,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, &types.Amount{Value: value, Currency: currency}, amount)
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		err := storage.BootstrapBalances(
+			ctx,
+			bootstrapBalancesFile,
+			genesisBlockIdentifier,
+			WithBootstrapFormat(BootstrapFormatJSON),
+		)
+		assert.True(t, errors.Is(err, storageErrs.ErrBootstrapFileInvalid))
+	})
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
 func TestBalanceReconciliation(t *testing.T) {
 	var (
 		account = &types.AccountIdentifier{
@@ -1851,3 +1992,157 @@ func TestBlockSyncing(t *testing.T) {
 	mockHelper.AssertExpectations(t)
 	mockHandler.AssertExpectations(t)
 }
+
+func TestHistoricalBalances(t *testing.T) {
+	var (
+		account = &types.AccountIdentifier{
+			Address: "blah",
+		}
+		missingAccount = &types.AccountIdentifier{
+			Address: "missing",
+		}
+		currency = &types.Currency{
+			Symbol:   "BLAH",
+			Decimals: 2,
+		}
+		changes = []*parser.BalanceChange{
+			{
+				Account:  account,
+				Currency: currency,
+				Block: &types.BlockIdentifier{
+					Hash:  "2",
+					Index: 2,
+				},
+				Difference: "100",
+			},
+			{
+				Account:  account,
+				Currency: currency,
+				Block: &types.BlockIdentifier{
+					Hash:  "5",
+					Index: 5,
+				},
+				Difference: "-40",
+			},
+			{
+				Account:  account,
+				Currency: currency,
+				Block: &types.BlockIdentifier{
+					Hash:  "9",
+					Index: 9,
+				},
+				Difference: "15",
+			},
+		}
+	)
+
+	tests := map[string]struct {
+		options []BalanceStorageOption
+
+		pruneIndex     int64
+		expectedPruned int64
+	}{
+		"no retention": {
+			pruneIndex:     5,
+			expectedPruned: 5,
+		},
+		"retain 2 blocks": {
+			options:        []BalanceStorageOption{WithBalanceRetention(2)},
+			pruneIndex:     5,
+			expectedPruned: 3,
+		},
+		"retain all": {
+			options:        []BalanceStorageOption{WithBalanceRetention(RetainAllBalances)},
+			pruneIndex:     5,
+			expectedPruned: -1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			newDir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(newDir)
+
+			database, err := newTestBadgerDatabase(ctx, newDir)
+			assert.NoError(t, err)
+			defer database.Close(ctx)
+
+			storage := NewBalanceStorage(database, test.options...)
+			mockHelper := &mocks.BalanceStorageHelper{}
+			mockHandler := &mocks.BalanceStorageHandler{}
+			mockHelper.On("Asserter").Return(baseAsserter())
+			mockHelper.On("ExemptFunc").Return(exemptFunc())
+			mockHelper.On("BalanceExemptions").Return([]*types.BalanceExemption{})
+			storage.Initialize(mockHelper, mockHandler)
+
+			for _, change := range changes {
+				txn := storage.db.Transaction(ctx)
+				_, err := storage.UpdateBalance(ctx, txn, change, change.Block)
+				assert.NoError(t, err)
+				assert.NoError(t, txn.Commit(ctx))
+			}
+
+			balance, err := storage.GetBalanceAtBlock(ctx, missingAccount, currency, 5)
+			assert.True(t, errors.Is(err, storageErrs.ErrAccountMissing))
+			assert.Nil(t, balance)
+
+			balance, err = storage.GetBalanceAtBlock(ctx, account, currency, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, &HistoricalBalance{
+				Index:  -1,
+				Amount: &types.Amount{Value: "0", Currency: currency},
+			}, balance)
+
+			balance, err = storage.GetBalanceAtBlock(ctx, account, currency, 7)
+			assert.NoError(t, err)
+			assert.Equal(t, &HistoricalBalance{
+				Index:  5,
+				Amount: &types.Amount{Value: "60", Currency: currency},
+			}, balance)
+
+			balances, err := storage.GetBalanceRange(ctx, account, currency, 3, 9)
+			assert.NoError(t, err)
+			assert.Equal(t, []*HistoricalBalance{
+				{Index: 2, Amount: &types.Amount{Value: "100", Currency: currency}},
+				{Index: 5, Amount: &types.Amount{Value: "60", Currency: currency}},
+				{Index: 9, Amount: &types.Amount{Value: "75", Currency: currency}},
+			}, balances)
+
+			balances, err = storage.GetBalanceRange(ctx, account, currency, 5, 8)
+			assert.NoError(t, err)
+			assert.Equal(t, []*HistoricalBalance{
+				{Index: 5, Amount: &types.Amount{Value: "60", Currency: currency}},
+			}, balances)
+
+			balances, err = storage.GetBalanceRange(ctx, account, currency, 8, 5)
+			assert.True(t, errors.Is(err, storageErrs.ErrInvalidBalanceRange))
+			assert.Nil(t, balances)
+
+			assert.NoError(t, storage.PruneBalances(ctx, account, currency, test.pruneIndex))
+
+			// Balances at or below the pruned index are no longer available.
+			if test.expectedPruned >= 0 {
+				balance, err = storage.GetBalanceAtBlock(ctx, account, currency, test.expectedPruned)
+				assert.True(t, errors.Is(err, storageErrs.ErrBalancePruned))
+				assert.Nil(t, balance)
+			}
+
+			balance, err = storage.GetBalanceAtBlock(ctx, account, currency, test.expectedPruned+1)
+			assert.NoError(t, err)
+			assert.NotNil(t, balance)
+
+			balance, err = storage.GetBalanceAtBlock(ctx, account, currency, 9)
+			assert.NoError(t, err)
+			assert.Equal(t, &HistoricalBalance{
+				Index:  9,
+				Amount: &types.Amount{Value: "75", Currency: currency},
+			}, balance)
+
+			mockHelper.AssertExpectations(t)
+			mockHandler.AssertExpectations(t)
+		})
+	}
+}
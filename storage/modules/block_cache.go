@@ -0,0 +1,376 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var _ database.CacheStatsProvider = (*BlockStorage)(nil)
+
+const (
+	// blockCacheStatsName is the name of the
+	// BlockStorage cache in *database.CacheStats.
+	blockCacheStatsName = "block_storage"
+)
+
+// BlockStorageOption is used to configure BlockStorage.
+type BlockStorageOption func(b *BlockStorage)
+
+// WithBlockCache caches up to size recently read (or
+// prefetched) blocks in memory. Cached blocks are stored
+// encoded, so each read still decodes the block but does
+// not touch the database (other than to confirm the block
+// is still canonical).
+func WithBlockCache(size int) BlockStorageOption {
+	return func(b *BlockStorage) {
+		if size > 0 {
+			b.cache = newBlockCache(size)
+		}
+	}
+}
+
+// blockCacheEntry is a single encoded
+// block in the blockCache.
+type blockCacheEntry struct {
+	hash    string
+	index   int64
+	encoded []byte
+}
+
+// blockCache is a least-recently-used cache
+// of encoded blocks keyed by block hash.
+//
+// Block contents never change for a given hash, so entries
+// only need to be invalidated when a block is pruned. Any
+// block that is orphaned is ignored because lookups confirm
+// the block index still references the cached hash.
+type blockCache struct {
+	size int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	// generation is incremented on each invalidation. Blocks
+	// read before an invalidation are not added to the cache
+	// to prevent caching data that was just pruned.
+	generation uint64
+
+	hits   uint64
+	misses uint64
+
+	// prefetching is true while a goroutine is loading
+	// blocks from prefetchNext to prefetchEnd.
+	prefetching  bool
+	prefetchNext int64
+	prefetchEnd  int64
+}
+
+func newBlockCache(size int) *blockCache {
+	return &blockCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (c *blockCache) currentGeneration() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.generation
+}
+
+// get returns the entry for hash. Lookups made while
+// prefetching are not recorded in the cache stats.
+func (c *blockCache) get(hash string, record bool) (*blockCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[hash]
+	if !ok {
+		if record {
+			c.misses++
+		}
+
+		return nil, false
+	}
+
+	if record {
+		c.hits++
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*blockCacheEntry), true
+}
+
+func (c *blockCache) add(generation uint64, entry *blockCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if element, ok := c.entries[entry.hash]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.hash] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*blockCacheEntry).hash)
+	}
+}
+
+func (c *blockCache) invalidate(hash string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.generation++
+	if element, ok := c.entries[hash]; ok {
+		c.order.Remove(element)
+		delete(c.entries, hash)
+	}
+}
+
+// getCachedBlock returns a block from the cache if the block
+// referenced by blockIdentifier is cached and still canonical
+// in dbTx. Lookups of the head block are never cached.
+func (b *BlockStorage) getCachedBlock(
+	ctx context.Context,
+	dbTx database.Transaction,
+	blockIdentifier *types.PartialBlockIdentifier,
+	record bool,
+) (*types.Block, bool) {
+	if b.cache == nil || blockIdentifier == nil {
+		return nil, false
+	}
+
+	var hash string
+	switch {
+	case blockIdentifier.Hash != nil:
+		hash = *blockIdentifier.Hash
+	case blockIdentifier.Index != nil:
+		exists, key, err := dbTx.Get(ctx, getBlockIndexKey(*blockIdentifier.Index))
+		if err != nil || !exists {
+			return nil, false
+		}
+
+		hash = strings.TrimPrefix(string(key), blockNamespace+"/")
+	default:
+		return nil, false
+	}
+
+	entry, ok := b.cache.get(hash, record)
+	if !ok {
+		return nil, false
+	}
+
+	if blockIdentifier.Index != nil && *blockIdentifier.Index != entry.index {
+		return nil, false
+	}
+
+	// A cached block may have been orphaned, so we ensure
+	// its index still references its hash.
+	if blockIdentifier.Hash != nil {
+		exists, key, err := dbTx.Get(ctx, getBlockIndexKey(entry.index))
+		if err != nil || !exists {
+			return nil, false
+		}
+
+		if _, blockKey := getBlockHashKey(hash); string(key) != string(blockKey) {
+			return nil, false
+		}
+	}
+
+	var block types.Block
+	if err := b.db.Encoder().Decode("", entry.encoded, &block, false); err != nil {
+		return nil, false
+	}
+
+	return &block, true
+}
+
+// cacheBlock adds a block to the cache if no block
+// was invalidated since generation.
+func (b *BlockStorage) cacheBlock(generation uint64, block *types.Block) {
+	if b.cache == nil {
+		return
+	}
+
+	encoded, err := b.db.Encoder().Encode("", block)
+	if err != nil {
+		return
+	}
+
+	b.cache.add(generation, &blockCacheEntry{
+		hash:    block.BlockIdentifier.Hash,
+		index:   block.BlockIdentifier.Index,
+		encoded: encoded,
+	})
+}
+
+// invalidateCachedBlock removes a block from the cache. This
+// must be called after any change to block data is committed.
+func (b *BlockStorage) invalidateCachedBlock(blockIdentifier *types.BlockIdentifier) {
+	if b.cache == nil {
+		return
+	}
+
+	b.cache.invalidate(blockIdentifier.Hash)
+}
+
+// CacheStats returns the hits and misses of the
+// block cache. If the block cache is not enabled,
+// nothing is returned.
+func (b *BlockStorage) CacheStats() []*database.CacheStats {
+	if b.cache == nil {
+		return nil
+	}
+
+	b.cache.lock.Lock()
+	defer b.cache.lock.Unlock()
+
+	return []*database.CacheStats{
+		{
+			Name:   blockCacheStatsName,
+			Hits:   b.cache.hits,
+			Misses: b.cache.misses,
+		},
+	}
+}
+
+// WarmCache loads the head block and the blocks preceding
+// it (up to blocks in total) into the block cache. This is
+// typically called after opening storage so that the first
+// requests served (which are usually for recent blocks) do
+// not need to read from disk. The number of blocks loaded
+// is returned.
+func (b *BlockStorage) WarmCache(ctx context.Context, blocks int) (int, error) {
+	if b.cache == nil {
+		return 0, nil
+	}
+
+	head, err := b.GetHeadBlockIdentifier(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: cannot get head block identifier", err)
+	}
+
+	warmed := 0
+	for warmed < blocks && ctx.Err() == nil {
+		block, err := b.GetBlock(ctx, types.ConstructPartialBlockIdentifier(head))
+		if err != nil {
+			// We stop warming once we reach pruned blocks.
+			break
+		}
+
+		warmed++
+
+		// We should break if we have reached genesis.
+		if block.ParentBlockIdentifier.Index == block.BlockIdentifier.Index {
+			break
+		}
+
+		head = block.ParentBlockIdentifier
+	}
+
+	log.Printf("%d blocks loaded into block cache\n", warmed)
+	return warmed, ctx.Err()
+}
+
+// PrefetchBlocks loads blocks from startIndex to endIndex
+// (inclusive) into the block cache in the background so that
+// they can be read from memory when they are requested.
+// PrefetchBlocks returns immediately and prefetching stops
+// when ctx is canceled.
+//
+// If blocks are already being prefetched, the range being
+// prefetched is extended. No more blocks than fit in the
+// cache are prefetched at once.
+func (b *BlockStorage) PrefetchBlocks(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) {
+	if b.cache == nil || startIndex > endIndex {
+		return
+	}
+
+	if endIndex-startIndex >= int64(b.cache.size) {
+		endIndex = startIndex + int64(b.cache.size) - 1
+	}
+
+	b.cache.lock.Lock()
+	defer b.cache.lock.Unlock()
+
+	if b.cache.prefetching {
+		if startIndex > b.cache.prefetchEnd+1 {
+			b.cache.prefetchNext = startIndex
+		}
+
+		if endIndex > b.cache.prefetchEnd {
+			b.cache.prefetchEnd = endIndex
+		}
+
+		return
+	}
+
+	b.cache.prefetching = true
+	b.cache.prefetchNext = startIndex
+	b.cache.prefetchEnd = endIndex
+	go b.prefetch(ctx)
+}
+
+// nextPrefetchIndex returns the next index to prefetch
+// or false if prefetching is complete.
+func (b *BlockStorage) nextPrefetchIndex(ctx context.Context) (int64, bool) {
+	b.cache.lock.Lock()
+	defer b.cache.lock.Unlock()
+
+	if ctx.Err() != nil || b.cache.prefetchNext > b.cache.prefetchEnd {
+		b.cache.prefetching = false
+		return -1, false
+	}
+
+	index := b.cache.prefetchNext
+	b.cache.prefetchNext++
+	return index, true
+}
+
+func (b *BlockStorage) prefetch(ctx context.Context) {
+	for {
+		index, ok := b.nextPrefetchIndex(ctx)
+		if !ok {
+			return
+		}
+
+		// Blocks that are missing (or pruned) are
+		// skipped because they cannot be cached.
+		_, _ = b.getBlock(ctx, &types.PartialBlockIdentifier{Index: &index}, false)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func cachedBlocks(b *BlockStorage) int {
+	b.cache.lock.Lock()
+	defer b.cache.lock.Unlock()
+
+	return b.cache.order.Len()
+}
+
+func TestBlockCache(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	db, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	blocks := []*types.Block{}
+	for i := int64(0); i < 30; i++ {
+		parentIndex := i - 1
+		if parentIndex < 0 {
+			parentIndex = 0
+		}
+
+		blocks = append(blocks, &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: i,
+				Hash:  fmt.Sprintf("block %d", i),
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: parentIndex,
+				Hash:  fmt.Sprintf("block %d", parentIndex),
+			},
+			Timestamp: 1,
+			Transactions: []*types.Transaction{
+				simpleTransactionFactory(fmt.Sprintf("tx %d", i), "addr", "10", currency),
+			},
+		})
+	}
+
+	storage := NewBlockStorage(db, blockWorkerConcurrency, WithBlockCache(5))
+	for _, block := range blocks {
+		assert.NoError(t, storage.SeeBlock(ctx, block))
+		assert.NoError(t, storage.AddBlock(ctx, block))
+	}
+
+	index := func(i int64) *types.PartialBlockIdentifier {
+		return &types.PartialBlockIdentifier{Index: &i}
+	}
+
+	t.Run("cache disabled", func(t *testing.T) {
+		uncached := NewBlockStorage(db, blockWorkerConcurrency)
+		assert.Nil(t, uncached.CacheStats())
+
+		warmed, err := uncached.WarmCache(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, warmed)
+
+		block, err := uncached.GetBlock(ctx, index(10))
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[10], block)
+	})
+
+	t.Run("cache miss and hit", func(t *testing.T) {
+		block, err := storage.GetBlock(ctx, index(10))
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[10], block)
+		assert.Equal(t, []*database.CacheStats{
+			{Name: blockCacheStatsName, Hits: 0, Misses: 1},
+		}, storage.CacheStats())
+
+		block, err = storage.GetBlock(ctx, index(10))
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[10], block)
+
+		block, err = storage.GetBlock(
+			ctx,
+			types.ConstructPartialBlockIdentifier(blocks[10].BlockIdentifier),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[10], block)
+		assert.Equal(t, []*database.CacheStats{
+			{Name: blockCacheStatsName, Hits: 2, Misses: 1},
+		}, storage.CacheStats())
+
+		// Cached blocks can be modified by the caller
+		block.Transactions = nil
+		block, err = storage.GetBlock(ctx, index(10))
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[10], block)
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		for i := int64(11); i <= 15; i++ {
+			_, err := storage.GetBlock(ctx, index(i))
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 5, cachedBlocks(storage))
+
+		_, ok := storage.cache.get(blocks[10].BlockIdentifier.Hash, false)
+		assert.False(t, ok)
+		_, ok = storage.cache.get(blocks[15].BlockIdentifier.Hash, false)
+		assert.True(t, ok)
+	})
+
+	t.Run("orphaned block", func(t *testing.T) {
+		_, err := storage.GetBlock(ctx, index(29))
+		assert.NoError(t, err)
+
+		assert.NoError(t, storage.RemoveBlock(ctx, blocks[29].BlockIdentifier))
+		_, err = storage.GetBlock(
+			ctx,
+			types.ConstructPartialBlockIdentifier(blocks[29].BlockIdentifier),
+		)
+		assert.True(t, errors.Is(err, storageErrs.ErrBlockNotFound))
+
+		_, err = storage.GetBlock(ctx, index(29))
+		assert.True(t, errors.Is(err, storageErrs.ErrBlockNotFound))
+	})
+
+	t.Run("pruned block", func(t *testing.T) {
+		_, err := storage.GetBlock(ctx, index(0))
+		assert.NoError(t, err)
+
+		firstPruned, lastPruned, err := storage.Prune(ctx, 0, minPruningDepth)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), firstPruned)
+		assert.Equal(t, int64(0), lastPruned)
+
+		_, err = storage.GetBlock(ctx, index(0))
+		assert.True(t, errors.Is(err, storageErrs.ErrCannotAccessPrunedData))
+	})
+
+	t.Run("warm cache", func(t *testing.T) {
+		warm := NewBlockStorage(db, blockWorkerConcurrency, WithBlockCache(5))
+		warmed, err := warm.WarmCache(ctx, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, warmed)
+
+		for i := int64(26); i <= 28; i++ {
+			block, err := warm.GetBlock(ctx, index(i))
+			assert.NoError(t, err)
+			assert.Equal(t, blocks[i], block)
+		}
+		assert.Equal(t, []*database.CacheStats{
+			{Name: blockCacheStatsName, Hits: 3, Misses: 3},
+		}, warm.CacheStats())
+
+		// Warming stops at pruned blocks
+		warmed, err = warm.WarmCache(ctx, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 28, warmed)
+	})
+
+	t.Run("prefetch", func(t *testing.T) {
+		prefetch := NewBlockStorage(db, blockWorkerConcurrency, WithBlockCache(5))
+
+		// Only as many blocks as fit in the cache are prefetched
+		prefetch.PrefetchBlocks(ctx, 5, 100)
+		assert.Eventually(t, func() bool {
+			return cachedBlocks(prefetch) == 5
+		}, 5*time.Second, 10*time.Millisecond)
+
+		for i := int64(5); i <= 9; i++ {
+			block, err := prefetch.GetBlock(ctx, index(i))
+			assert.NoError(t, err)
+			assert.Equal(t, blocks[i], block)
+		}
+		assert.Equal(t, []*database.CacheStats{
+			{Name: blockCacheStatsName, Hits: 5, Misses: 0},
+		}, prefetch.CacheStats())
+	})
+}
@@ -0,0 +1,335 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// DefaultPruneInterval is how long a Pruner
+	// sleeps between pruning attempts.
+	DefaultPruneInterval = 10 * time.Minute
+)
+
+// PruneDecision is returned by a PrunePolicy to indicate
+// what should happen to a block that could be pruned.
+type PruneDecision int
+
+const (
+	// PruneBlock indicates the block data should be removed.
+	PruneBlock PruneDecision = iota
+
+	// RetainBlock indicates the block data should be kept
+	// but pruning should continue with the next block.
+	RetainBlock
+
+	// StopPruning indicates the block (and all blocks after
+	// it) should not be pruned yet.
+	StopPruning
+)
+
+// PrunePolicy decides what to do with a block that could be
+// pruned. head is the current head block of BlockStorage.
+type PrunePolicy func(
+	ctx context.Context,
+	head *types.BlockIdentifier,
+	block *types.Block,
+) (PruneDecision, error)
+
+// KeepLastBlocks returns a PrunePolicy that never
+// prunes the last n blocks (relative to head).
+func KeepLastBlocks(n int64) PrunePolicy {
+	return func(
+		ctx context.Context,
+		head *types.BlockIdentifier,
+		block *types.Block,
+	) (PruneDecision, error) {
+		if block.BlockIdentifier.Index > head.Index-n {
+			return StopPruning, nil
+		}
+
+		return PruneBlock, nil
+	}
+}
+
+// KeepEveryKthBlock returns a PrunePolicy that retains every
+// block with an index that is a multiple of k (ex: to keep
+// periodic checkpoints available).
+func KeepEveryKthBlock(k int64) PrunePolicy {
+	return func(
+		ctx context.Context,
+		head *types.BlockIdentifier,
+		block *types.Block,
+	) (PruneDecision, error) {
+		if k > 0 && block.BlockIdentifier.Index%k == 0 {
+			return RetainBlock, nil
+		}
+
+		return PruneBlock, nil
+	}
+}
+
+// KeepBlocksAfter returns a PrunePolicy that never prunes
+// blocks with a timestamp (in milliseconds) >= timestamp.
+func KeepBlocksAfter(timestamp int64) PrunePolicy {
+	return func(
+		ctx context.Context,
+		head *types.BlockIdentifier,
+		block *types.Block,
+	) (PruneDecision, error) {
+		if block.Timestamp >= timestamp {
+			return StopPruning, nil
+		}
+
+		return PruneBlock, nil
+	}
+}
+
+// evaluatePrunePolicies returns the most conservative
+// PruneDecision of all policies.
+func evaluatePrunePolicies(
+	ctx context.Context,
+	policies []PrunePolicy,
+	head *types.BlockIdentifier,
+	block *types.Block,
+) (PruneDecision, error) {
+	decision := PruneBlock
+	for _, policy := range policies {
+		policyDecision, err := policy(ctx, head, block)
+		if err != nil {
+			return PruneBlock, err
+		}
+
+		if policyDecision > decision {
+			decision = policyDecision
+		}
+	}
+
+	return decision, nil
+}
+
+// PruneWorker is an interface that allows for work to be
+// done while a block is pruned from storage in the same
+// database transaction as the change (ex: removing module
+// data derived from the block). Any BlockWorker provided
+// to BlockStorage.Initialize that implements PruneWorker
+// is invoked when a block is pruned.
+type PruneWorker interface {
+	PruningBlock(
+		context.Context,
+		*types.BlockResponse,
+		database.Transaction,
+	) error
+}
+
+// PruneProgress summarizes a pruning run.
+type PruneProgress struct {
+	// FirstIndex and LastIndex are the range of block indices
+	// processed (pruned or retained). They are -1 if no
+	// blocks were processed.
+	FirstIndex int64 `json:"first_index"`
+	LastIndex  int64 `json:"last_index"`
+
+	// Pruned is the number of blocks pruned.
+	Pruned int64 `json:"pruned"`
+
+	// Retained is the number of blocks
+	// retained by a PrunePolicy.
+	Retained int64 `json:"retained"`
+}
+
+func (p *PruneProgress) add(index int64, decision PruneDecision) {
+	if p.FirstIndex == -1 {
+		p.FirstIndex = index
+	}
+
+	if p.LastIndex < index {
+		p.LastIndex = index
+	}
+
+	if decision == RetainBlock {
+		p.Retained++
+		return
+	}
+
+	p.Pruned++
+}
+
+// PruneCallback is invoked with the progress of
+// a pruning run after each block is processed.
+type PruneCallback func(context.Context, *PruneProgress)
+
+// Pruner periodically prunes a BlockStorage
+// according to a collection of PrunePolicy.
+type Pruner struct {
+	blockStorage *BlockStorage
+	minDepth     int64
+
+	interval       time.Duration
+	policies       []PrunePolicy
+	pruneableIndex func(ctx context.Context, headIndex int64) (int64, error)
+	callback       PruneCallback
+}
+
+// PrunerOption is used to overwrite default values in
+// Pruner construction. Any Option not provided
+// falls back to the default value.
+type PrunerOption func(p *Pruner)
+
+// WithPruneInterval overrides the DefaultPruneInterval.
+func WithPruneInterval(interval time.Duration) PrunerOption {
+	return func(p *Pruner) {
+		p.interval = interval
+	}
+}
+
+// WithPrunePolicies adds policies that are consulted
+// before each block is pruned.
+func WithPrunePolicies(policies ...PrunePolicy) PrunerOption {
+	return func(p *Pruner) {
+		p.policies = append(p.policies, policies...)
+	}
+}
+
+// WithPruneableIndex provides a function that returns the
+// largest block index that is safe to prune. By default,
+// all blocks deeper than minDepth can be pruned.
+func WithPruneableIndex(
+	pruneableIndex func(ctx context.Context, headIndex int64) (int64, error),
+) PrunerOption {
+	return func(p *Pruner) {
+		p.pruneableIndex = pruneableIndex
+	}
+}
+
+// WithPruneCallback provides a PruneCallback that is
+// invoked after each block is processed.
+func WithPruneCallback(callback PruneCallback) PrunerOption {
+	return func(p *Pruner) {
+		p.callback = callback
+	}
+}
+
+// NewPruner returns a new *Pruner. Blocks within minDepth of
+// the head block are never pruned (as they could be orphaned).
+func NewPruner(
+	blockStorage *BlockStorage,
+	minDepth int64,
+	options ...PrunerOption,
+) *Pruner {
+	p := &Pruner{
+		blockStorage: blockStorage,
+		minDepth:     minDepth,
+		interval:     DefaultPruneInterval,
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	return p
+}
+
+// PruneOnce prunes all pruneable blocks
+// and returns a summary of the run.
+func (p *Pruner) PruneOnce(ctx context.Context) (*PruneProgress, error) {
+	progress := &PruneProgress{
+		FirstIndex: -1,
+		LastIndex:  -1,
+	}
+
+	headBlock, err := p.blockStorage.GetHeadBlockIdentifier(ctx)
+	if headBlock == nil && errors.Is(err, storageErrs.ErrHeadBlockNotFound) {
+		// this will occur when we are waiting for the first block to be synced
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	oldestIndex, err := p.blockStorage.GetOldestBlockIndex(ctx)
+	if oldestIndex == -1 && errors.Is(err, storageErrs.ErrOldestIndexMissing) {
+		// this will occur when we have yet to store the oldest index
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pruneableIndex := headBlock.Index
+	if p.pruneableIndex != nil {
+		pruneableIndex, err = p.pruneableIndex(ctx, headBlock.Index)
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not determine pruneable index", err)
+		}
+	}
+
+	if pruneableIndex < oldestIndex {
+		return progress, nil
+	}
+
+	return p.blockStorage.PruneWithPolicies(
+		ctx,
+		pruneableIndex,
+		p.minDepth,
+		p.policies,
+		p.callback,
+	)
+}
+
+// Run calls PruneOnce every interval until
+// the context is canceled or an error is
+// encountered.
+func (p *Pruner) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		// We don't use a timer pattern because p.interval is defined
+		// as the time between pruning runs. Using a timer would only guarantee
+		// that the difference between starts of each pruning run are p.interval.
+		if err := utils.ContextSleep(ctx, p.interval); err != nil {
+			return err
+		}
+
+		progress, err := p.PruneOnce(ctx)
+		if err != nil {
+			return err
+		}
+
+		// FirstIndex and LastIndex are -1 if there is nothing to prune
+		if progress.FirstIndex != -1 && progress.LastIndex != -1 {
+			pruneMessage := fmt.Sprintf("pruned blocks %d-%d", progress.FirstIndex, progress.LastIndex)
+			if progress.FirstIndex == progress.LastIndex {
+				pruneMessage = fmt.Sprintf("pruned block %d", progress.FirstIndex)
+			}
+
+			if progress.Retained > 0 {
+				pruneMessage = fmt.Sprintf("%s (retained %d)", pruneMessage, progress.Retained)
+			}
+
+			log.Println(pruneMessage)
+		}
+	}
+
+	return ctx.Err()
+}
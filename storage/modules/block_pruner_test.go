@@ -0,0 +1,280 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neilotoole/errgroup"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+// recordingPruneWorker is a BlockWorker that
+// records all blocks it is asked to prune.
+type recordingPruneWorker struct {
+	pruned []int64
+	lock   sync.Mutex
+}
+
+func (r *recordingPruneWorker) AddingBlock(
+	context.Context,
+	*errgroup.Group,
+	*types.Block,
+	database.Transaction,
+) (database.CommitWorker, error) {
+	return nil, nil
+}
+
+func (r *recordingPruneWorker) RemovingBlock(
+	context.Context,
+	*errgroup.Group,
+	*types.Block,
+	database.Transaction,
+) (database.CommitWorker, error) {
+	return nil, nil
+}
+
+func (r *recordingPruneWorker) PruningBlock(
+	ctx context.Context,
+	blockResponse *types.BlockResponse,
+	dbTx database.Transaction,
+) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.pruned = append(r.pruned, blockResponse.Block.BlockIdentifier.Index)
+	return nil
+}
+
+func newTestPrunerStorage(
+	ctx context.Context,
+	t *testing.T,
+	dir string,
+	blocks int64,
+) (*BlockStorage, *recordingPruneWorker) {
+	database, err := newTestBadgerDatabase(ctx, dir)
+	assert.NoError(t, err)
+
+	storage := NewBlockStorage(database, blockWorkerConcurrency)
+	worker := &recordingPruneWorker{}
+	storage.Initialize([]BlockWorker{worker})
+
+	for i := int64(0); i < blocks; i++ {
+		parentIndex := i - 1
+		if parentIndex < 0 {
+			parentIndex = 0
+		}
+
+		block := &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Hash:  fmt.Sprintf("block %d", i),
+				Index: i,
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Hash:  fmt.Sprintf("block %d", parentIndex),
+				Index: parentIndex,
+			},
+			Timestamp: i * 1000,
+			Transactions: []*types.Transaction{
+				simpleTransactionFactory(
+					fmt.Sprintf("tx %d", i),
+					"addr1",
+					"100",
+					&types.Currency{Symbol: "hello"},
+				),
+			},
+		}
+		assert.NoError(t, storage.SeeBlock(ctx, block))
+		assert.NoError(t, storage.AddBlock(ctx, block))
+	}
+
+	return storage, worker
+}
+
+func assertBlockAccessible(
+	ctx context.Context,
+	t *testing.T,
+	storage *BlockStorage,
+	index int64,
+	accessible bool,
+) {
+	block, err := storage.GetBlock(ctx, &types.PartialBlockIdentifier{Index: &index})
+	if accessible {
+		assert.NoError(t, err)
+		assert.Equal(t, index, block.BlockIdentifier.Index)
+		assert.Len(t, block.Transactions, 1)
+		return
+	}
+
+	assert.ErrorIs(t, err, storageErrs.ErrCannotAccessPrunedData)
+}
+
+func TestPruner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nothing synced", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		storage, _ := newTestPrunerStorage(ctx, t, newDir, 0)
+		defer storage.db.Close(ctx)
+
+		progress, err := NewPruner(storage, minPruningDepth).PruneOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &PruneProgress{FirstIndex: -1, LastIndex: -1}, progress)
+	})
+
+	t.Run("keep last and every kth block", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		storage, worker := newTestPrunerStorage(ctx, t, newDir, 50)
+		defer storage.db.Close(ctx)
+
+		callbacks := 0
+		pruner := NewPruner(
+			storage,
+			5,
+			WithPrunePolicies(KeepLastBlocks(30), KeepEveryKthBlock(10)),
+			WithPruneCallback(func(ctx context.Context, progress *PruneProgress) {
+				callbacks++
+				assert.Equal(t, int64(callbacks), progress.Pruned+progress.Retained)
+			}),
+		)
+
+		progress, err := pruner.PruneOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &PruneProgress{
+			FirstIndex: 0,
+			LastIndex:  19,
+			Pruned:     18,
+			Retained:   2,
+		}, progress)
+		assert.Equal(t, 20, callbacks)
+
+		oldestIndex, err := storage.GetOldestBlockIndex(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), oldestIndex)
+
+		for i := int64(0); i < 50; i++ {
+			assertBlockAccessible(ctx, t, storage, i, i%10 == 0 || i >= 20)
+		}
+		assert.Len(t, worker.pruned, 18)
+		assert.NotContains(t, worker.pruned, int64(10))
+
+		// Nothing else can be pruned
+		progress, err = pruner.PruneOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-1), progress.FirstIndex)
+	})
+
+	t.Run("keep blocks after timestamp", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		storage, worker := newTestPrunerStorage(ctx, t, newDir, 50)
+		defer storage.db.Close(ctx)
+
+		pruner := NewPruner(
+			storage,
+			5,
+			WithPrunePolicies(KeepBlocksAfter(25000)),
+			WithPruneableIndex(func(ctx context.Context, headIndex int64) (int64, error) {
+				assert.Equal(t, int64(49), headIndex)
+				return 40, nil
+			}),
+		)
+
+		progress, err := pruner.PruneOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &PruneProgress{
+			FirstIndex: 0,
+			LastIndex:  24,
+			Pruned:     25,
+		}, progress)
+		assert.Len(t, worker.pruned, 25)
+		assertBlockAccessible(ctx, t, storage, 24, false)
+		assertBlockAccessible(ctx, t, storage, 25, true)
+	})
+
+	t.Run("policy error", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		storage, _ := newTestPrunerStorage(ctx, t, newDir, 50)
+		defer storage.db.Close(ctx)
+
+		pruner := NewPruner(
+			storage,
+			5,
+			WithPrunePolicies(func(
+				ctx context.Context,
+				head *types.BlockIdentifier,
+				block *types.Block,
+			) (PruneDecision, error) {
+				return PruneBlock, errors.New("policy unavailable")
+			}),
+		)
+
+		progress, err := pruner.PruneOnce(ctx)
+		assert.Nil(t, progress)
+		assert.ErrorIs(t, err, storageErrs.ErrPruningFailed)
+		assert.Contains(t, err.Error(), storageErrs.ErrPrunePolicyFailed.Error())
+		assertBlockAccessible(ctx, t, storage, 0, true)
+	})
+
+	t.Run("run until canceled", func(t *testing.T) {
+		newDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(newDir)
+
+		storage, _ := newTestPrunerStorage(ctx, t, newDir, 50)
+		defer storage.db.Close(ctx)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		pruner := NewPruner(
+			storage,
+			5,
+			WithPruneInterval(time.Millisecond),
+			WithPruneCallback(func(ctx context.Context, progress *PruneProgress) {
+				if progress.LastIndex == 9 {
+					cancel()
+				}
+			}),
+		)
+
+		assert.ErrorIs(t, pruner.Run(runCtx), context.Canceled)
+
+		oldestIndex, err := storage.GetOldestBlockIndex(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), oldestIndex)
+	})
+}
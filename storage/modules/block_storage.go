@@ -147,17 +147,26 @@ type BlockStorage struct {
 
 	workers           []BlockWorker
 	workerConcurrency int
+
+	// cache is nil unless WithBlockCache is provided.
+	cache *blockCache
 }
 
 // NewBlockStorage returns a new BlockStorage.
 func NewBlockStorage(
 	db database.Database,
 	workerConcurrency int,
+	options ...BlockStorageOption,
 ) *BlockStorage {
-	return &BlockStorage{
+	b := &BlockStorage{
 		db:                db,
 		workerConcurrency: workerConcurrency,
 	}
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
 }
 
 // Initialize adds a []BlockWorker to BlockStorage. Usually
@@ -228,12 +237,14 @@ func (b *BlockStorage) GetOldestBlockIndex(
 }
 
 // pruneBlock attempts to prune a single block in a database transaction.
-// If a block is pruned, we return its index.
+// If a block is pruned (or retained by a PrunePolicy), we return its index
+// and the PruneDecision made for it.
 func (b *BlockStorage) pruneBlock(
 	ctx context.Context,
 	index int64,
 	minDepth int64,
-) (int64, error) {
+	policies []PrunePolicy,
+) (int64, PruneDecision, error) {
 	// We create a separate transaction for each pruning attempt so that
 	// we don't hit the database tx size maximum. As a result, it is possible
 	// that we prune a collection of blocks, encounter an error, and cannot
@@ -243,22 +254,22 @@ func (b *BlockStorage) pruneBlock(
 
 	oldestIndex, err := b.GetOldestBlockIndexTransactional(ctx, dbTx)
 	if err != nil {
-		return -1, fmt.Errorf("%w: %v", storageErrs.ErrOldestIndexRead, err)
+		return -1, PruneBlock, fmt.Errorf("%w: %v", storageErrs.ErrOldestIndexRead, err)
 	}
 
 	if index < oldestIndex {
-		return -1, storageErrs.ErrNothingToPrune
+		return -1, PruneBlock, storageErrs.ErrNothingToPrune
 	}
 
 	head, err := b.GetHeadBlockIdentifierTransactional(ctx, dbTx)
 	if err != nil {
-		return -1, fmt.Errorf("%w: cannot get head block identifier", err)
+		return -1, PruneBlock, fmt.Errorf("%w: cannot get head block identifier", err)
 	}
 
 	// Ensure we are only pruning blocks that could not be
 	// accessed later in a reorg.
 	if oldestIndex > head.Index-minDepth {
-		return -1, storageErrs.ErrNothingToPrune
+		return -1, PruneBlock, storageErrs.ErrNothingToPrune
 	}
 
 	blockResponse, err := b.GetBlockLazyTransactional(
@@ -267,52 +278,93 @@ func (b *BlockStorage) pruneBlock(
 		dbTx,
 	)
 	if err != nil && !errors.Is(err, storageErrs.ErrBlockNotFound) {
-		return -1, err
+		return -1, PruneBlock, err
 	}
 
 	// If there is an omitted block, we will have a non-nil error. When
 	// a block is omitted, we should not attempt to remove it because
 	// it doesn't exist.
+	decision := PruneBlock
 	if err == nil {
-		blockIdentifier := blockResponse.Block.BlockIdentifier
-
-		// Remove all transaction hashes
-		g, gctx := errgroup.WithContextN(ctx, b.workerConcurrency, b.workerConcurrency)
-		for i := range blockResponse.OtherTransactions {
-			// We need to set variable before calling goroutine
-			// to avoid getting an updated pointer as loop iteration
-			// continues.
-			tx := blockResponse.OtherTransactions[i]
-			g.Go(func() error {
-				if err := b.pruneTransaction(gctx, dbTx, blockIdentifier, tx); err != nil {
-					return fmt.Errorf("%w: %v", storageErrs.ErrCannotPruneTransaction, err)
-				}
-
-				return nil
-			})
-		}
-
-		if err := g.Wait(); err != nil {
-			return -1, err
+		decision, err = evaluatePrunePolicies(ctx, policies, head, blockResponse.Block)
+		if err != nil {
+			return -1, PruneBlock, fmt.Errorf("%w: %v", storageErrs.ErrPrunePolicyFailed, err)
 		}
+	}
 
-		_, blockKey := getBlockHashKey(blockIdentifier.Hash)
-		if err := dbTx.Set(ctx, blockKey, []byte(""), true); err != nil {
-			return -1, err
+	switch decision {
+	case StopPruning:
+		return -1, StopPruning, storageErrs.ErrNothingToPrune
+	case PruneBlock:
+		if err := b.pruneBlockData(ctx, dbTx, blockResponse); err != nil {
+			return -1, PruneBlock, err
 		}
 	}
 
 	// Update prune index
 	if err := b.setOldestBlockIndex(ctx, dbTx, true, oldestIndex+1); err != nil {
-		return -1, fmt.Errorf("%w: %v", storageErrs.ErrOldestIndexUpdateFailed, err)
+		return -1, PruneBlock, fmt.Errorf("%w: %v", storageErrs.ErrOldestIndexUpdateFailed, err)
 	}
 
 	// Commit tx
 	if err := dbTx.Commit(ctx); err != nil {
-		return -1, err
+		return -1, PruneBlock, err
+	}
+
+	if decision == PruneBlock && blockResponse != nil {
+		b.invalidateCachedBlock(blockResponse.Block.BlockIdentifier)
+	}
+
+	return oldestIndex, decision, nil
+}
+
+// pruneBlockData removes all block and transaction data
+// for a block (blockResponse is nil if the block was
+// omitted) and invokes any PruneWorkers.
+func (b *BlockStorage) pruneBlockData(
+	ctx context.Context,
+	dbTx database.Transaction,
+	blockResponse *types.BlockResponse,
+) error {
+	if blockResponse == nil {
+		return nil
+	}
+
+	for _, worker := range b.workers {
+		pruneWorker, ok := worker.(PruneWorker)
+		if !ok {
+			continue
+		}
+
+		if err := pruneWorker.PruningBlock(ctx, blockResponse, dbTx); err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrPruneWorkerFailed, err)
+		}
+	}
+
+	blockIdentifier := blockResponse.Block.BlockIdentifier
+
+	// Remove all transaction hashes
+	g, gctx := errgroup.WithContextN(ctx, b.workerConcurrency, b.workerConcurrency)
+	for i := range blockResponse.OtherTransactions {
+		// We need to set variable before calling goroutine
+		// to avoid getting an updated pointer as loop iteration
+		// continues.
+		tx := blockResponse.OtherTransactions[i]
+		g.Go(func() error {
+			if err := b.pruneTransaction(gctx, dbTx, blockIdentifier, tx); err != nil {
+				return fmt.Errorf("%w: %v", storageErrs.ErrCannotPruneTransaction, err)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
 	}
 
-	return oldestIndex, nil
+	_, blockKey := getBlockHashKey(blockIdentifier.Hash)
+	return dbTx.Set(ctx, blockKey, []byte(""), true)
 }
 
 // Prune removes block and transaction data
@@ -330,28 +382,47 @@ func (b *BlockStorage) Prune(
 	index int64,
 	minDepth int64,
 ) (int64, int64, error) {
-	firstPruned := int64(-1)
-	lastPruned := int64(-1)
+	progress, err := b.PruneWithPolicies(ctx, index, minDepth, nil, nil)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return progress.FirstIndex, progress.LastIndex, nil
+}
+
+// PruneWithPolicies is like Prune but consults policies
+// before pruning each block (a block is only pruned if all
+// policies return PruneBlock). Blocks retained by a policy
+// are skipped but remain accessible. If provided, callback is
+// invoked after each block is pruned or retained.
+func (b *BlockStorage) PruneWithPolicies(
+	ctx context.Context,
+	index int64,
+	minDepth int64,
+	policies []PrunePolicy,
+	callback PruneCallback,
+) (*PruneProgress, error) {
+	progress := &PruneProgress{
+		FirstIndex: -1,
+		LastIndex:  -1,
+	}
 
 	for ctx.Err() == nil {
-		prunedBlock, err := b.pruneBlock(ctx, index, minDepth)
+		prunedBlock, decision, err := b.pruneBlock(ctx, index, minDepth, policies)
 		if errors.Is(err, storageErrs.ErrNothingToPrune) {
-			return firstPruned, lastPruned, nil
+			return progress, nil
 		}
 		if err != nil {
-			return -1, -1, fmt.Errorf("%w: %v", storageErrs.ErrPruningFailed, err)
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrPruningFailed, err)
 		}
 
-		if firstPruned == -1 {
-			firstPruned = prunedBlock
-		}
-
-		if lastPruned < prunedBlock {
-			lastPruned = prunedBlock
+		progress.add(prunedBlock, decision)
+		if callback != nil {
+			callback(ctx, progress)
 		}
 	}
 
-	return -1, -1, ctx.Err()
+	return nil, ctx.Err()
 }
 
 // GetHeadBlockIdentifier returns the head block identifier,
@@ -536,6 +607,18 @@ func (b *BlockStorage) GetBlockTransactional(
 	ctx context.Context,
 	dbTx database.Transaction,
 	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	if block, ok := b.getCachedBlock(ctx, dbTx, blockIdentifier, true); ok {
+		return block, nil
+	}
+
+	return b.getBlockTransactional(ctx, dbTx, blockIdentifier)
+}
+
+func (b *BlockStorage) getBlockTransactional(
+	ctx context.Context,
+	dbTx database.Transaction,
+	blockIdentifier *types.PartialBlockIdentifier,
 ) (*types.Block, error) {
 	blockResponse, err := b.GetBlockLazyTransactional(ctx, blockIdentifier, dbTx)
 	if err != nil {
@@ -580,10 +663,41 @@ func (b *BlockStorage) GetBlock(
 	ctx context.Context,
 	blockIdentifier *types.PartialBlockIdentifier,
 ) (*types.Block, error) {
+	return b.getBlock(ctx, blockIdentifier, true)
+}
+
+// getBlock returns a block and adds it to the
+// block cache (if enabled).
+func (b *BlockStorage) getBlock(
+	ctx context.Context,
+	blockIdentifier *types.PartialBlockIdentifier,
+	record bool,
+) (*types.Block, error) {
+	if b.cache == nil {
+		transaction := b.db.ReadTransaction(ctx)
+		defer transaction.Discard(ctx)
+
+		return b.getBlockTransactional(ctx, transaction, blockIdentifier)
+	}
+
+	// The generation must be read before the database
+	// transaction is created so that we never cache a
+	// block that is pruned while we are reading it.
+	generation := b.cache.currentGeneration()
 	transaction := b.db.ReadTransaction(ctx)
 	defer transaction.Discard(ctx)
 
-	return b.GetBlockTransactional(ctx, transaction, blockIdentifier)
+	if block, ok := b.getCachedBlock(ctx, transaction, blockIdentifier, record); ok {
+		return block, nil
+	}
+
+	block, err := b.getBlockTransactional(ctx, transaction, blockIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	b.cacheBlock(generation, block)
+	return block, nil
 }
 
 func (b *BlockStorage) seeBlock(
@@ -749,6 +863,33 @@ func (b *BlockStorage) AddBlock(
 	return b.callWorkersAndCommit(ctx, block, transaction, true)
 }
 
+// AddBlockTransactional stores a block in a *ModuleTransaction
+// so that it can be committed atomically with changes made by
+// other modules. All BlockWorkers are invoked in the same
+// transaction and any CommitWorkers they return are invoked
+// after the *ModuleTransaction is committed.
+func (b *BlockStorage) AddBlockTransactional(
+	ctx context.Context,
+	transaction *ModuleTransaction,
+	block *types.Block,
+) error {
+	err := b.storeBlock(ctx, transaction, block.BlockIdentifier)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBlockStoreFailed, err)
+	}
+
+	commitWorkers, err := b.callWorkers(ctx, block, transaction, true)
+	if err != nil {
+		return err
+	}
+
+	for _, cw := range commitWorkers {
+		transaction.OnCommit(cw)
+	}
+
+	return nil
+}
+
 func (b *BlockStorage) deleteBlock(
 	ctx context.Context,
 	transaction database.Transaction,
@@ -795,13 +936,55 @@ func (b *BlockStorage) RemoveBlock(
 	transaction := b.db.WriteTransaction(ctx, blockSyncIdentifier, true)
 	defer transaction.Discard(ctx)
 
+	block, err := b.removeBlock(ctx, transaction, blockIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return b.callWorkersAndCommit(ctx, block, transaction, false)
+}
+
+// RemoveBlockTransactional removes a block in a *ModuleTransaction
+// so that it can be committed atomically with changes made by
+// other modules. All BlockWorkers are invoked in the same
+// transaction and any CommitWorkers they return are invoked
+// after the *ModuleTransaction is committed.
+func (b *BlockStorage) RemoveBlockTransactional(
+	ctx context.Context,
+	transaction *ModuleTransaction,
+	blockIdentifier *types.BlockIdentifier,
+) error {
+	block, err := b.removeBlock(ctx, transaction, blockIdentifier)
+	if err != nil {
+		return err
+	}
+
+	commitWorkers, err := b.callWorkers(ctx, block, transaction, false)
+	if err != nil {
+		return err
+	}
+
+	for _, cw := range commitWorkers {
+		transaction.OnCommit(cw)
+	}
+
+	return nil
+}
+
+// removeBlock removes a block and all of its
+// transaction hashes and returns the removed block.
+func (b *BlockStorage) removeBlock(
+	ctx context.Context,
+	transaction database.Transaction,
+	blockIdentifier *types.BlockIdentifier,
+) (*types.Block, error) {
 	block, err := b.GetBlockTransactional(
 		ctx,
 		transaction,
 		types.ConstructPartialBlockIdentifier(blockIdentifier),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Remove all transaction hashes
@@ -821,15 +1004,15 @@ func (b *BlockStorage) RemoveBlock(
 		})
 	}
 	if err := g.Wait(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Delete block
 	if err := b.deleteBlock(ctx, transaction, block); err != nil {
-		return fmt.Errorf("%w: %v", storageErrs.ErrBlockDeleteFailed, err)
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrBlockDeleteFailed, err)
 	}
 
-	return b.callWorkersAndCommit(ctx, block, transaction, false)
+	return block, nil
 }
 
 func (b *BlockStorage) callWorkersAndCommit(
@@ -838,6 +1021,36 @@ func (b *BlockStorage) callWorkersAndCommit(
 	txn database.Transaction,
 	adding bool,
 ) error {
+	commitWorkers, err := b.callWorkers(ctx, block, txn, adding)
+	if err != nil {
+		return err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return err
+	}
+
+	for _, cw := range commitWorkers {
+		if cw == nil {
+			continue
+		}
+
+		if err := cw(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// callWorkers invokes all BlockWorkers in a transaction
+// and returns any CommitWorkers they return.
+func (b *BlockStorage) callWorkers(
+	ctx context.Context,
+	block *types.Block,
+	txn database.Transaction,
+	adding bool,
+) ([]database.CommitWorker, error) {
 	commitWorkers := make([]database.CommitWorker, len(b.workers))
 
 	// Provision global errgroup to use for all workers
@@ -853,31 +1066,17 @@ func (b *BlockStorage) callWorkersAndCommit(
 			cw, err = w.RemovingBlock(gctx, g, block, txn)
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		commitWorkers[i] = cw
 	}
 
 	if err := g.Wait(); err != nil {
-		return err
-	}
-
-	if err := txn.Commit(ctx); err != nil {
-		return err
-	}
-
-	for _, cw := range commitWorkers {
-		if cw == nil {
-			continue
-		}
-
-		if err := cw(ctx); err != nil {
-			return err
-		}
+		return nil, err
 	}
 
-	return nil
+	return commitWorkers, nil
 }
 
 // SetNewStartIndex attempts to remove all blocks
@@ -1310,9 +1509,10 @@ func (b *BlockStorage) findBlockTransaction(
 		return nil, fmt.Errorf("%w: %v", storageErrs.ErrOldestIndexRead, err)
 	}
 
-	if blockIdentifier.Index < oldestIndex {
-		return nil, storageErrs.ErrCannotAccessPrunedData
-	}
+	// Blocks older than the oldest index may have been
+	// retained by a PrunePolicy, so we can only determine
+	// if a transaction was pruned by looking it up.
+	pruned := blockIdentifier.Index < oldestIndex
 
 	namespace, key := getTransactionKey(blockIdentifier, transactionIdentifier)
 	txExists, tx, err := txn.Get(ctx, key)
@@ -1321,6 +1521,10 @@ func (b *BlockStorage) findBlockTransaction(
 	}
 
 	if !txExists {
+		if pruned {
+			return nil, storageErrs.ErrCannotAccessPrunedData
+		}
+
 		return nil, fmt.Errorf(
 			"%w %s",
 			storageErrs.ErrTransactionNotFound,
@@ -1333,6 +1537,11 @@ func (b *BlockStorage) findBlockTransaction(
 		return nil, fmt.Errorf("%w: unable to decode block data for transaction", err)
 	}
 
+	// Pruned transactions only contain the block index.
+	if bt.Transaction == nil {
+		return nil, storageErrs.ErrCannotAccessPrunedData
+	}
+
 	return bt.Transaction, nil
 }
 
@@ -0,0 +1,401 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// DefaultBootstrapBatchSize is the number of bootstrap
+	// entries written in each database transaction.
+	DefaultBootstrapBatchSize = 10000
+)
+
+// BootstrapFormat is the encoding of a bootstrap file.
+type BootstrapFormat string
+
+const (
+	// BootstrapFormatAuto detects the format of a bootstrap
+	// file from its contents. Files that start with "[" are
+	// parsed as JSON and all other files are parsed as CSV.
+	BootstrapFormatAuto BootstrapFormat = ""
+
+	// BootstrapFormatJSON is a JSON array of entries.
+	BootstrapFormatJSON BootstrapFormat = "json"
+
+	// BootstrapFormatCSV is a CSV file with a header row.
+	// Columns are identified by the header, so they can be
+	// provided in any order.
+	BootstrapFormatCSV BootstrapFormat = "csv"
+)
+
+// Columns of CSV bootstrap files. CSV files cannot
+// specify any metadata (use JSON files instead).
+const (
+	BootstrapAddressColumn        = "address"
+	BootstrapSubAccountColumn     = "sub_account_address"
+	BootstrapSymbolColumn         = "symbol"
+	BootstrapDecimalsColumn       = "decimals"
+	BootstrapValueColumn          = "value"
+	BootstrapCoinIdentifierColumn = "coin_identifier"
+)
+
+// BootstrapProgress is provided to the progress handler
+// after each batch of bootstrap entries is written.
+type BootstrapProgress struct {
+	Entries    int64 `json:"entries"`
+	BytesRead  int64 `json:"bytes_read"`
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+// BootstrapOption is used to configure the loading
+// of a bootstrap file.
+type BootstrapOption func(b *bootstrapConfig)
+
+type bootstrapConfig struct {
+	format    BootstrapFormat
+	batchSize int
+	progress  func(*BootstrapProgress)
+}
+
+// WithBootstrapFormat sets the format of the bootstrap
+// file instead of detecting it.
+func WithBootstrapFormat(format BootstrapFormat) BootstrapOption {
+	return func(b *bootstrapConfig) {
+		b.format = format
+	}
+}
+
+// WithBootstrapBatchSize overrides the number of entries
+// written in each database transaction.
+func WithBootstrapBatchSize(size int) BootstrapOption {
+	return func(b *bootstrapConfig) {
+		b.batchSize = size
+	}
+}
+
+// WithBootstrapProgress sets a handler that is invoked
+// after each batch of entries is written.
+func WithBootstrapProgress(handler func(*BootstrapProgress)) BootstrapOption {
+	return func(b *bootstrapConfig) {
+		b.progress = handler
+	}
+}
+
+func newBootstrapConfig(options ...BootstrapOption) *bootstrapConfig {
+	config := &bootstrapConfig{
+		format:    BootstrapFormatAuto,
+		batchSize: DefaultBootstrapBatchSize,
+	}
+	for _, opt := range options {
+		opt(config)
+	}
+
+	if config.batchSize <= 0 {
+		config.batchSize = DefaultBootstrapBatchSize
+	}
+
+	return config
+}
+
+func (b *bootstrapConfig) reportProgress(progress *BootstrapProgress) {
+	log.Printf(
+		"%d bootstrap entries loaded (%d/%d bytes)\n",
+		progress.Entries,
+		progress.BytesRead,
+		progress.TotalBytes,
+	)
+
+	if b.progress != nil {
+		b.progress(progress)
+	}
+}
+
+// countingReader counts the bytes read from
+// a bootstrap file to report progress.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// bootstrapRow is a single row of a CSV
+// bootstrap file.
+type bootstrapRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (b *bootstrapRow) get(column string) string {
+	i, ok := b.columns[column]
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(b.record[i])
+}
+
+func (b *bootstrapRow) account() *types.AccountIdentifier {
+	account := &types.AccountIdentifier{Address: b.get(BootstrapAddressColumn)}
+	if subAccount := b.get(BootstrapSubAccountColumn); len(subAccount) > 0 {
+		account.SubAccount = &types.SubAccountIdentifier{Address: subAccount}
+	}
+
+	return account
+}
+
+func (b *bootstrapRow) currency() (*types.Currency, error) {
+	decimals, err := strconv.ParseInt(b.get(BootstrapDecimalsColumn), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse decimals: %w", err)
+	}
+
+	return &types.Currency{
+		Symbol:   b.get(BootstrapSymbolColumn),
+		Decimals: int32(decimals),
+	}, nil
+}
+
+// bootstrapParser parses a single entry from
+// a JSON or CSV bootstrap file.
+type bootstrapParser struct {
+	columns   []string
+	parseJSON func([]byte) (interface{}, error)
+	parseCSV  func(*bootstrapRow) (interface{}, error)
+}
+
+// detectBootstrapFormat skips any leading whitespace
+// and returns the format indicated by the first character.
+func detectBootstrapFormat(reader *bufio.Reader) (BootstrapFormat, error) {
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		if unicode.IsSpace(rune(c)) {
+			continue
+		}
+
+		if err := reader.UnreadByte(); err != nil {
+			return "", err
+		}
+
+		if c == '[' {
+			return BootstrapFormatJSON, nil
+		}
+
+		return BootstrapFormatCSV, nil
+	}
+}
+
+// streamBootstrapFile invokes handler with each entry
+// of a bootstrap file without loading the entire file
+// into memory.
+func streamBootstrapFile(
+	ctx context.Context,
+	filePath string,
+	config *bootstrapConfig,
+	parser *bootstrapParser,
+	handler func(interface{}, *BootstrapProgress) error,
+) (*BootstrapProgress, error) {
+	file, err := os.Open(path.Clean(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to load file %s", err, filePath)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to stat file %s", err, filePath)
+	}
+
+	counter := &countingReader{reader: file}
+	reader := bufio.NewReader(counter)
+	progress := &BootstrapProgress{TotalBytes: info.Size()}
+
+	format := config.format
+	if format == BootstrapFormatAuto {
+		format, err = detectBootstrapFormat(reader)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to detect format: %v",
+				storageErrs.ErrBootstrapFileInvalid,
+				err,
+			)
+		}
+	}
+
+	handle := func(entry interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		progress.Entries++
+		progress.BytesRead = counter.count
+		return handler(entry, progress)
+	}
+
+	switch format {
+	case BootstrapFormatJSON:
+		err = streamBootstrapJSON(reader, parser, handle)
+	case BootstrapFormatCSV:
+		err = streamBootstrapCSV(reader, parser, handle)
+	default:
+		err = fmt.Errorf(
+			"%w: format %s is not supported",
+			storageErrs.ErrBootstrapFileInvalid,
+			format,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	progress.BytesRead = counter.count
+	return progress, nil
+}
+
+// decodeBootstrapJSON strictly decodes a
+// single entry of a JSON bootstrap file.
+func decodeBootstrapJSON(data []byte, output interface{}) error {
+	// To prevent silent erroring, we explicitly
+	// reject any unknown fields.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(output); err != nil {
+		return err
+	}
+
+	return types.CheckUnknownFields(data, output)
+}
+
+func streamBootstrapJSON(
+	reader io.Reader,
+	parser *bootstrapParser,
+	handle func(interface{}) error,
+) error {
+	dec := json.NewDecoder(reader)
+	if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+		return fmt.Errorf(
+			"%w: expected JSON array: %v",
+			storageErrs.ErrBootstrapFileInvalid,
+			err,
+		)
+	}
+
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrBootstrapFileInvalid, err)
+		}
+
+		entry, err := parser.parseJSON(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrBootstrapFileInvalid, err)
+		}
+
+		if err := handle(entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrBootstrapFileInvalid, err)
+	}
+
+	return nil
+}
+
+func streamBootstrapCSV(
+	reader io.Reader,
+	parser *bootstrapParser,
+	handle func(interface{}) error,
+) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.ReuseRecord = true
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return fmt.Errorf(
+			"%w: unable to read header: %v",
+			storageErrs.ErrBootstrapFileInvalid,
+			err,
+		)
+	}
+
+	row := &bootstrapRow{columns: map[string]int{}}
+	for i, column := range header {
+		row.columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range parser.columns {
+		if _, ok := row.columns[column]; !ok {
+			return fmt.Errorf(
+				"%w: header is missing column %s",
+				storageErrs.ErrBootstrapFileInvalid,
+				column,
+			)
+		}
+	}
+
+	for entries := 1; ; entries++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrBootstrapFileInvalid, err)
+		}
+
+		row.record = record
+		entry, err := parser.parseCSV(row)
+		if err != nil {
+			return fmt.Errorf(
+				"%w: entry %d: %v",
+				storageErrs.ErrBootstrapFileInvalid,
+				entries,
+				err,
+			)
+		}
+
+		if err := handle(entry); err != nil {
+			return err
+		}
+	}
+}
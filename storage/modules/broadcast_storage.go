@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"sync"
 
 	"github.com/neilotoole/errgroup"
@@ -57,11 +58,85 @@ type BroadcastStorage struct {
 	broadcastBehindTip  bool
 	blockBroadcastLimit int
 
+	rebroadcastMultiplier float64
+	maxStaleDepth         int64
+	abandonDepth          int64
+	feeBumper             FeeBumper
+	finalityChecker       FinalityChecker
+
 	// Running BroadcastAll concurrently
 	// could cause corruption.
 	broadcastAllMutex sync.Mutex
 }
 
+// FeeBumper is invoked before a stale transaction is rebroadcast
+// and may return a replacement transaction (usually with a higher
+// fee) and its signed payload. If the returned
+// *types.TransactionIdentifier is nil, the original transaction
+// is rebroadcast. The FeeBumper may update the Intent of the
+// *Broadcast if the replacement has a different intent.
+type FeeBumper func(
+	context.Context,
+	*Broadcast,
+) (*types.TransactionIdentifier, string, error)
+
+// FinalityChecker is invoked once a transaction has reached its
+// confirmation depth (and on each subsequent block until it
+// returns true) to determine if the transaction is final (ex: using
+// a chain-provided finality signal). The transaction is only
+// confirmed once it is considered final. The database.Transaction
+// is the transaction used to add the block. If a FinalityChecker
+// returns an error, the transaction is not considered final and
+// is checked again at the next block.
+type FinalityChecker func(
+	context.Context,
+	database.Transaction,
+	*Broadcast,
+	*types.BlockIdentifier, // block containing the transaction
+) (bool, error)
+
+// BroadcastStorageOption is used to configure
+// rebroadcast policies of BroadcastStorage.
+type BroadcastStorageOption func(b *BroadcastStorage)
+
+// WithExponentialRebroadcast multiplies the stale depth by multiplier
+// after each broadcast of a transaction (up to maxStaleDepth, if
+// maxStaleDepth is positive) so that transactions that are slow to
+// confirm are not rebroadcast as frequently.
+func WithExponentialRebroadcast(multiplier float64, maxStaleDepth int64) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.rebroadcastMultiplier = multiplier
+		b.maxStaleDepth = maxStaleDepth
+	}
+}
+
+// WithAbandonDepth abandons any transaction that has not been
+// seen on-chain blocks after it was first broadcast, regardless
+// of how many times it has been broadcast. Abandoned transactions
+// are passed to BroadcastStorageHandler.BroadcastFailed.
+func WithAbandonDepth(blocks int64) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.abandonDepth = blocks
+	}
+}
+
+// WithFeeBumper invokes a FeeBumper each
+// time a stale transaction is rebroadcast.
+func WithFeeBumper(feeBumper FeeBumper) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.feeBumper = feeBumper
+	}
+}
+
+// WithFinalityChecker only confirms a transaction
+// that has reached its confirmation depth once
+// finalityChecker considers it final.
+func WithFinalityChecker(finalityChecker FinalityChecker) BroadcastStorageOption {
+	return func(b *BroadcastStorage) {
+		b.finalityChecker = finalityChecker
+	}
+}
+
 // BroadcastStorageHelper is used by BroadcastStorage to submit transactions
 // and find said transaction in blocks on-chain.
 type BroadcastStorageHelper interface {
@@ -140,6 +215,31 @@ type Broadcast struct {
 	Payload               string                       `json:"payload"`
 	LastBroadcast         *types.BlockIdentifier       `json:"broadcast_at"`
 	Broadcasts            int                          `json:"broadcasts"`
+
+	// FirstBroadcast is the block at which the transaction
+	// was first broadcast.
+	FirstBroadcast *types.BlockIdentifier `json:"first_broadcast,omitempty"`
+
+	// ReplacedTransactions are the transactions replaced by a
+	// FeeBumper. Any of them may still be confirmed on-chain.
+	ReplacedTransactions []*types.TransactionIdentifier `json:"replaced_transactions,omitempty"`
+}
+
+// BroadcastStatus describes the progress of a Broadcast.
+type BroadcastStatus struct {
+	Broadcast *Broadcast `json:"broadcast"`
+
+	// Age is the number of blocks since the transaction was
+	// first broadcast (-1 if it has not been broadcast).
+	Age int64 `json:"age"`
+
+	// Depth is the confirmation depth of the transaction
+	// (0 if it has not been seen on-chain).
+	Depth int64 `json:"depth"`
+
+	// Stale is true if the transaction has not been seen
+	// on-chain within the stale depth of its last broadcast.
+	Stale bool `json:"stale"`
 }
 
 // NewBroadcastStorage returns a new BroadcastStorage.
@@ -150,8 +250,9 @@ func NewBroadcastStorage(
 	tipDelay int64,
 	broadcastBehindTip bool,
 	blockBroadcastLimit int,
+	options ...BroadcastStorageOption,
 ) *BroadcastStorage {
-	return &BroadcastStorage{
+	b := &BroadcastStorage{
 		db:                  db,
 		staleDepth:          staleDepth,
 		broadcastLimit:      broadcastLimit,
@@ -159,6 +260,12 @@ func NewBroadcastStorage(
 		broadcastBehindTip:  broadcastBehindTip,
 		blockBroadcastLimit: blockBroadcastLimit,
 	}
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
 }
 
 // Initialize adds a BroadcastStorageHelper and BroadcastStorageHandler to BroadcastStorage.
@@ -171,10 +278,62 @@ func (b *BroadcastStorage) Initialize(
 	b.handler = handler
 }
 
+// staleDepthFor returns the number of blocks after the last
+// broadcast of a transaction that it is considered stale.
+func (b *BroadcastStorage) staleDepthFor(broadcast *Broadcast) int64 {
+	depth := b.staleDepth
+	if b.rebroadcastMultiplier <= 1 {
+		return depth
+	}
+
+	for i := 1; i < broadcast.Broadcasts; i++ {
+		depth = int64(math.Ceil(float64(depth) * b.rebroadcastMultiplier))
+		if b.maxStaleDepth > 0 && depth >= b.maxStaleDepth {
+			return b.maxStaleDepth
+		}
+	}
+
+	return depth
+}
+
+// shouldAbandon returns a boolean indicating if a
+// broadcast has exceeded the abandon depth at index.
+func (b *BroadcastStorage) shouldAbandon(index int64, broadcast *Broadcast) bool {
+	return b.abandonDepth > 0 &&
+		broadcast.FirstBroadcast != nil &&
+		index-broadcast.FirstBroadcast.Index >= b.abandonDepth
+}
+
+// findBroadcast looks for a broadcast transaction (or any
+// transaction it replaced) in processed blocks.
+func (b *BroadcastStorage) findBroadcast(
+	ctx context.Context,
+	broadcast *Broadcast,
+	dbTx database.Transaction,
+) (*types.BlockIdentifier, *types.Transaction, error) {
+	identifiers := append(
+		[]*types.TransactionIdentifier{broadcast.TransactionIdentifier},
+		broadcast.ReplacedTransactions...,
+	)
+	for _, identifier := range identifiers {
+		foundBlock, foundTransaction, err := b.helper.FindTransaction(ctx, identifier, dbTx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if foundBlock != nil {
+			return foundBlock, foundTransaction, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
 func (b *BroadcastStorage) invokeAddBlockHandlers(
 	ctx context.Context,
 	dbTx database.Transaction,
 	staleBroadcasts []*Broadcast,
+	abandonedBroadcasts []*Broadcast,
 	confirmedTransactions []*Broadcast,
 	foundBlocks []*types.BlockIdentifier,
 	foundTransactions []*types.Transaction,
@@ -190,6 +349,23 @@ func (b *BroadcastStorage) invokeAddBlockHandlers(
 		}
 	}
 
+	for _, abandoned := range abandonedBroadcasts {
+		if err := b.handler.BroadcastFailed(
+			ctx,
+			dbTx,
+			abandoned.Identifier,
+			abandoned.TransactionIdentifier,
+			abandoned.Intent,
+		); err != nil {
+			return fmt.Errorf(
+				"%w %s: %v",
+				errors.ErrBroadcastTxAbandoned,
+				abandoned.TransactionIdentifier.Hash,
+				err,
+			)
+		}
+	}
+
 	for i, broadcast := range confirmedTransactions {
 		err := b.handler.TransactionConfirmed(
 			ctx,
@@ -225,32 +401,58 @@ func (b *BroadcastStorage) AddingBlock(
 	}
 
 	staleBroadcasts := []*Broadcast{}
+	abandonedBroadcasts := []*Broadcast{}
 	confirmedTransactions := []*Broadcast{}
 	foundTransactions := []*types.Transaction{}
 	foundBlocks := []*types.BlockIdentifier{}
 
 	for _, broadcast := range broadcasts {
+		namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
+		abandon := func() error {
+			log.Printf(
+				"Abandoning transaction %s after %d blocks\n",
+				broadcast.TransactionIdentifier.Hash,
+				block.BlockIdentifier.Index-broadcast.FirstBroadcast.Index,
+			)
+			abandonedBroadcasts = append(abandonedBroadcasts, broadcast)
+			if err := transaction.Delete(ctx, key); err != nil {
+				return fmt.Errorf("%w: %v", errors.ErrBroadcastDeleteFailed, err)
+			}
+
+			return nil
+		}
+
 		if broadcast.LastBroadcast == nil {
+			if b.shouldAbandon(block.BlockIdentifier.Index, broadcast) {
+				if err := abandon(); err != nil {
+					return nil, err
+				}
+			}
+
 			continue
 		}
 
-		namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
-
 		// We perform the FindTransaction search in the context of the block database
 		// transaction so we can access any transactions of depth 1 (in the current
 		// block).
-		foundBlock, foundTransaction, err := b.helper.FindTransaction(
-			ctx,
-			broadcast.TransactionIdentifier,
-			transaction,
-		)
+		foundBlock, foundTransaction, err := b.findBroadcast(ctx, broadcast, transaction)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastFindTxFailed, err)
 		}
 
+		// Check if we should abandon the broadcast
+		if foundBlock == nil && b.shouldAbandon(block.BlockIdentifier.Index, broadcast) {
+			if err := abandon(); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
 		// Check if we should mark the broadcast as stale
 		if foundBlock == nil &&
-			block.BlockIdentifier.Index-broadcast.LastBroadcast.Index >= b.staleDepth-depthOffset {
+			block.BlockIdentifier.Index-broadcast.LastBroadcast.Index >=
+				b.staleDepthFor(broadcast)-depthOffset {
 			staleBroadcasts = append(staleBroadcasts, broadcast)
 			broadcast.LastBroadcast = nil
 			bytes, err := b.db.Encoder().Encode(namespace, broadcast)
@@ -272,6 +474,11 @@ func (b *BroadcastStorage) AddingBlock(
 
 		// Check if we should mark the transaction as confirmed
 		if block.BlockIdentifier.Index-foundBlock.Index >= broadcast.ConfirmationDepth-depthOffset {
+			// We will check again at the next block.
+			if !b.final(ctx, transaction, broadcast, foundBlock) {
+				continue
+			}
+
 			confirmedTransactions = append(confirmedTransactions, broadcast)
 			foundTransactions = append(foundTransactions, foundTransaction)
 			foundBlocks = append(foundBlocks, foundBlock)
@@ -286,6 +493,7 @@ func (b *BroadcastStorage) AddingBlock(
 		ctx,
 		transaction,
 		staleBroadcasts,
+		abandonedBroadcasts,
 		confirmedTransactions,
 		foundBlocks,
 		foundTransactions,
@@ -302,6 +510,37 @@ func (b *BroadcastStorage) AddingBlock(
 	}, nil
 }
 
+// final returns a boolean indicating if a broadcast found
+// in block is final (always true without a FinalityChecker).
+// A failed finality check (ex: the node is temporarily
+// unavailable) should not halt syncing, so the broadcast
+// is considered not final and is checked again at the
+// next block.
+func (b *BroadcastStorage) final(
+	ctx context.Context,
+	dbTx database.Transaction,
+	broadcast *Broadcast,
+	block *types.BlockIdentifier,
+) bool {
+	if b.finalityChecker == nil {
+		return true
+	}
+
+	final, err := b.finalityChecker(ctx, dbTx, broadcast, block)
+	if err != nil {
+		log.Printf(
+			"%s %s: %v\n",
+			errors.ErrBroadcastFinalityCheckFailed.Error(),
+			broadcast.TransactionIdentifier.Hash,
+			err,
+		)
+
+		return false
+	}
+
+	return final
+}
+
 // RemovingBlock is called by BlockStorage when removing a block.
 // TODO: error if transaction removed after confirmed (means confirmation depth not deep enough)
 func (b *BroadcastStorage) RemovingBlock(
@@ -394,9 +633,46 @@ func (b *BroadcastStorage) GetAllBroadcasts(ctx context.Context) ([]*Broadcast,
 	return b.getAllBroadcasts(ctx, dbTx)
 }
 
+// bumpFee invokes the FeeBumper (if any) before a
+// rebroadcast and returns the *types.TransactionIdentifier
+// that was replaced (if any).
+func (b *BroadcastStorage) bumpFee(
+	ctx context.Context,
+	broadcast *Broadcast,
+) (*types.TransactionIdentifier, error) {
+	// Only transactions that are stale (and not those
+	// rebroadcast on request) are replaced.
+	if b.feeBumper == nil || broadcast.Broadcasts == 0 || broadcast.LastBroadcast != nil {
+		return nil, nil
+	}
+
+	identifier, payload, err := b.feeBumper(ctx, broadcast)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w %s: %v",
+			errors.ErrBroadcastFeeBumpFailed,
+			broadcast.TransactionIdentifier.Hash,
+			err,
+		)
+	}
+
+	if identifier == nil ||
+		types.Hash(identifier) == types.Hash(broadcast.TransactionIdentifier) {
+		return nil, nil
+	}
+
+	replaced := broadcast.TransactionIdentifier
+	broadcast.ReplacedTransactions = append(broadcast.ReplacedTransactions, replaced)
+	broadcast.TransactionIdentifier = identifier
+	broadcast.Payload = payload
+
+	return replaced, nil
+}
+
 func (b *BroadcastStorage) performBroadcast(
 	ctx context.Context,
 	broadcast *Broadcast,
+	replaced *types.TransactionIdentifier,
 	onlyEligible bool,
 ) error {
 	namespace, key := getBroadcastKey(broadcast.TransactionIdentifier)
@@ -408,6 +684,15 @@ func (b *BroadcastStorage) performBroadcast(
 	txn := b.db.Transaction(ctx)
 	defer txn.Discard(ctx)
 
+	// Broadcasts are stored by transaction hash, so
+	// the replaced transaction must be removed.
+	if replaced != nil {
+		_, replacedKey := getBroadcastKey(replaced)
+		if err := txn.Delete(ctx, replacedKey); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrBroadcastDeleteFailed, err)
+		}
+	}
+
 	if err := txn.Set(ctx, key, bytes, true); err != nil {
 		return fmt.Errorf("%w: %v", errors.ErrBroadcastUpdateFailed, err)
 	}
@@ -527,10 +812,18 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 		// re-broadcast if exiting between broadcasting the transaction and updating
 		// the value in the database. If the transaction is never really broadcast,
 		// it will be rebroadcast when it is considered stale!
+		replaced, err := b.bumpFee(ctx, broadcast)
+		if err != nil {
+			return err
+		}
+
+		if broadcast.FirstBroadcast == nil {
+			broadcast.FirstBroadcast = currBlock
+		}
 		broadcast.LastBroadcast = currBlock
 		broadcast.Broadcasts++
 
-		if err := b.performBroadcast(ctx, broadcast, onlyEligible); err != nil {
+		if err := b.performBroadcast(ctx, broadcast, replaced, onlyEligible); err != nil {
 			return fmt.Errorf("%w: %v", errors.ErrBroadcastPerformFailed, err)
 		}
 	}
@@ -538,6 +831,74 @@ func (b *BroadcastStorage) BroadcastAll(ctx context.Context, onlyEligible bool)
 	return nil
 }
 
+// GetBroadcastStatuses returns the BroadcastStatus
+// of all in-process broadcasts.
+func (b *BroadcastStorage) GetBroadcastStatuses(ctx context.Context) ([]*BroadcastStatus, error) {
+	currBlock, err := b.helper.CurrentBlockIdentifier(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastGetCurrentBlockIdentifierFailed, err)
+	}
+
+	dbTx := b.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	broadcasts, err := b.getAllBroadcasts(ctx, dbTx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastGetAllFailed, err)
+	}
+
+	statuses := make([]*BroadcastStatus, len(broadcasts))
+	for i, broadcast := range broadcasts {
+		status := &BroadcastStatus{
+			Broadcast: broadcast,
+			Age:       -1,
+		}
+		statuses[i] = status
+
+		if currBlock == nil || broadcast.FirstBroadcast == nil {
+			continue
+		}
+
+		status.Age = currBlock.Index - broadcast.FirstBroadcast.Index
+		foundBlock, _, err := b.findBroadcast(ctx, broadcast, dbTx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrBroadcastFindTxFailed, err)
+		}
+
+		if foundBlock != nil {
+			status.Depth = currBlock.Index - foundBlock.Index + depthOffset
+			continue
+		}
+
+		// A broadcast without a last broadcast is
+		// waiting to be rebroadcast.
+		status.Stale = broadcast.LastBroadcast == nil ||
+			currBlock.Index-broadcast.LastBroadcast.Index >=
+				b.staleDepthFor(broadcast)-depthOffset
+	}
+
+	return statuses, nil
+}
+
+// GetStaleBroadcasts returns the BroadcastStatus of all
+// broadcasts that have not been seen on-chain within the
+// stale depth of their last broadcast.
+func (b *BroadcastStorage) GetStaleBroadcasts(ctx context.Context) ([]*BroadcastStatus, error) {
+	statuses, err := b.GetBroadcastStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := []*BroadcastStatus{}
+	for _, status := range statuses {
+		if status.Stale {
+			stale = append(stale, status)
+		}
+	}
+
+	return stale, nil
+}
+
 // LockedAccounts returns all *types.AccountIdentifier currently active in transaction broadcasts.
 // The caller SHOULD NOT broadcast a transaction from an account if it is
 // considered locked!
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	mocks "github.com/coinbase/rosetta-sdk-go/mocks/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -193,6 +194,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 1",
 				LastBroadcast:         blocks[0].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[0].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -248,6 +250,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 1",
 				LastBroadcast:         blocks[0].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[0].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -309,6 +312,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 1",
 				LastBroadcast:         blocks[0].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[0].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 			{
@@ -319,6 +323,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 2",
 				LastBroadcast:         blocks[1].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[1].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -405,6 +410,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 1",
 				LastBroadcast:         blocks[2].BlockIdentifier,
 				Broadcasts:            2,
+				FirstBroadcast:        blocks[0].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 			{
@@ -415,6 +421,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 2",
 				LastBroadcast:         blocks[1].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[1].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -511,6 +518,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 1",
 				LastBroadcast:         blocks[2].BlockIdentifier,
 				Broadcasts:            2,
+				FirstBroadcast:        blocks[0].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 			{
@@ -521,6 +529,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 2",
 				LastBroadcast:         blocks[3].BlockIdentifier,
 				Broadcasts:            2,
+				FirstBroadcast:        blocks[1].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -600,6 +609,7 @@ func TestBroadcastStorageBroadcastSuccess(t *testing.T) {
 				Payload:               "payload 2",
 				LastBroadcast:         blocks[3].BlockIdentifier,
 				Broadcasts:            2,
+				FirstBroadcast:        blocks[1].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -1136,6 +1146,7 @@ func TestBroadcastStorageBehindTip(t *testing.T) {
 				Payload:               "payload 1",
 				LastBroadcast:         blocks[60].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[60].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 			{
@@ -1146,6 +1157,7 @@ func TestBroadcastStorageBehindTip(t *testing.T) {
 				Payload:               "payload 2",
 				LastBroadcast:         blocks[60].BlockIdentifier,
 				Broadcasts:            1,
+				FirstBroadcast:        blocks[60].BlockIdentifier,
 				ConfirmationDepth:     confirmationDepth,
 			},
 		}, broadcasts)
@@ -1320,3 +1332,456 @@ func TestBroadcastStorageClearBroadcasts(t *testing.T) {
 		mockHandler.AssertExpectations(t)
 	})
 }
+
+func TestBroadcastStorageRebroadcastPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	bumps := 0
+	storage := NewBroadcastStorage(
+		database,
+		staleDepth,
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithExponentialRebroadcast(2, 10),
+		WithAbandonDepth(10),
+		WithFeeBumper(func(
+			ctx context.Context,
+			broadcast *Broadcast,
+		) (*types.TransactionIdentifier, string, error) {
+			bumps++
+			if broadcast.Identifier != "broadcast 1" {
+				return nil, "", nil
+			}
+
+			return &types.TransactionIdentifier{Hash: "tx 1 bumped"}, "payload 1 bumped", nil
+		}),
+	)
+	send1 := opFiller("addr 1", 1)
+	send2 := opFiller("addr 2", 1)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	blocks := blockFiller(0, 16)
+
+	addBlock := func(
+		t *testing.T,
+		block *types.Block,
+		setup func(*mocks.BroadcastStorageHelper, *mocks.BroadcastStorageHandler, context.Context),
+	) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil)
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		setup(mockHelper, mockHandler, gctx)
+		commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	}
+
+	t.Run("exponential stale depth", func(t *testing.T) {
+		assert.Equal(t, int64(3), storage.staleDepthFor(&Broadcast{Broadcasts: 1}))
+		assert.Equal(t, int64(6), storage.staleDepthFor(&Broadcast{Broadcasts: 2}))
+		assert.Equal(t, int64(10), storage.staleDepthFor(&Broadcast{Broadcasts: 3}))
+		assert.Equal(t, int64(10), storage.staleDepthFor(&Broadcast{Broadcasts: 4}))
+	})
+
+	t.Run("broadcast at block 0", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			"payload 1",
+			1,
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		addBlock(t, blocks[0], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"BroadcastTransaction",
+				ctx,
+				network,
+				"payload 1",
+			).Return(
+				&types.TransactionIdentifier{Hash: "tx 1"},
+				nil,
+			).Once()
+		})
+		assert.Equal(t, 0, bumps)
+	})
+
+	t.Run("status before stale", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		storage.Initialize(mockHelper, &mocks.BroadcastStorageHandler{})
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(blocks[1].BlockIdentifier, nil)
+		mockHelper.On(
+			"FindTransaction",
+			ctx,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+			mock.Anything,
+		).Return(nil, nil, nil)
+
+		statuses, err := storage.GetBroadcastStatuses(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, int64(1), statuses[0].Age)
+		assert.Equal(t, int64(0), statuses[0].Depth)
+		assert.False(t, statuses[0].Stale)
+
+		stale, err := storage.GetStaleBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, stale, 0)
+	})
+
+	t.Run("stale at block 2 and fee bumped", func(t *testing.T) {
+		addBlock(t, blocks[2], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				&types.TransactionIdentifier{Hash: "tx 1"},
+				mock.Anything,
+			).Return(nil, nil, nil).Once()
+			mockHandler.On(
+				"TransactionStale",
+				gctx,
+				mock.Anything,
+				"broadcast 1",
+				&types.TransactionIdentifier{Hash: "tx 1"},
+			).Return(nil).Once()
+			mockHelper.On(
+				"BroadcastTransaction",
+				ctx,
+				network,
+				"payload 1 bumped",
+			).Return(
+				&types.TransactionIdentifier{Hash: "tx 1 bumped"},
+				nil,
+			).Once()
+		})
+		assert.Equal(t, 1, bumps)
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*Broadcast{
+			{
+				Identifier:            "broadcast 1",
+				NetworkIdentifier:     network,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1 bumped"},
+				Intent:                send1,
+				Payload:               "payload 1 bumped",
+				LastBroadcast:         blocks[2].BlockIdentifier,
+				Broadcasts:            2,
+				FirstBroadcast:        blocks[0].BlockIdentifier,
+				ConfirmationDepth:     1,
+				ReplacedTransactions: []*types.TransactionIdentifier{
+					{Hash: "tx 1"},
+				},
+			},
+		}, broadcasts)
+	})
+
+	t.Run("rebroadcast on request at block 3 is not fee bumped", func(t *testing.T) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		storage.Initialize(mockHelper, &mocks.BroadcastStorageHandler{})
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(blocks[3].BlockIdentifier, nil)
+		mockHelper.On(
+			"BroadcastTransaction",
+			ctx,
+			network,
+			"payload 1 bumped",
+		).Return(
+			&types.TransactionIdentifier{Hash: "tx 1 bumped"},
+			nil,
+		).Once()
+
+		assert.NoError(t, storage.BroadcastAll(ctx, false))
+		assert.Equal(t, 1, bumps)
+		mockHelper.AssertExpectations(t)
+	})
+
+	t.Run("replaced transaction confirmed at block 4", func(t *testing.T) {
+		addBlock(t, blocks[4], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				&types.TransactionIdentifier{Hash: "tx 1 bumped"},
+				mock.Anything,
+			).Return(nil, nil, nil).Once()
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				&types.TransactionIdentifier{Hash: "tx 1"},
+				mock.Anything,
+			).Return(
+				blocks[4].BlockIdentifier,
+				&types.Transaction{
+					TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				},
+				nil,
+			).Once()
+			mockHandler.On(
+				"TransactionConfirmed",
+				gctx,
+				mock.Anything,
+				"broadcast 1",
+				blocks[4].BlockIdentifier,
+				&types.Transaction{
+					TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				},
+				send1,
+			).Return(nil).Once()
+		})
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+	})
+
+	t.Run("abandoned after 10 blocks", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 2",
+			network,
+			send2,
+			&types.TransactionIdentifier{Hash: "tx 2"},
+			"payload 2",
+			1,
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		addBlock(t, blocks[5], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"BroadcastTransaction",
+				ctx,
+				network,
+				"payload 2",
+			).Return(
+				&types.TransactionIdentifier{Hash: "tx 2"},
+				nil,
+			).Once()
+		})
+
+		addBlock(t, blocks[15], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				&types.TransactionIdentifier{Hash: "tx 2"},
+				mock.Anything,
+			).Return(nil, nil, nil).Once()
+			mockHandler.On(
+				"BroadcastFailed",
+				gctx,
+				mock.Anything,
+				"broadcast 2",
+				&types.TransactionIdentifier{Hash: "tx 2"},
+				send2,
+			).Return(nil).Once()
+		})
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+	})
+}
+
+func TestBroadcastStorageFinalityChecker(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	db, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	checks := 0
+	storage := NewBroadcastStorage(
+		db,
+		staleDepth,
+		broadcastLimit,
+		broadcastTipDelay,
+		broadcastBehindTip,
+		blockBroadcastLimit,
+		WithFinalityChecker(func(
+			ctx context.Context,
+			dbTx database.Transaction,
+			broadcast *Broadcast,
+			block *types.BlockIdentifier,
+		) (bool, error) {
+			checks++
+			assert.NotNil(t, dbTx)
+			assert.Equal(t, "broadcast 1", broadcast.Identifier)
+			assert.Equal(t, int64(1), block.Index)
+
+			// A failed check does not halt syncing and
+			// the transaction is checked again at the
+			// next block.
+			if checks == 1 {
+				return false, errors.New("node unavailable")
+			}
+
+			return true, nil
+		}),
+	)
+	send1 := opFiller("addr 1", 1)
+	network := &types.NetworkIdentifier{Blockchain: "Bitcoin", Network: "Testnet3"}
+	tx1 := &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+	}
+	blocks := blockFiller(0, 4)
+
+	addBlock := func(
+		t *testing.T,
+		block *types.Block,
+		setup func(*mocks.BroadcastStorageHelper, *mocks.BroadcastStorageHandler, context.Context),
+	) {
+		mockHelper := &mocks.BroadcastStorageHelper{}
+		mockHandler := &mocks.BroadcastStorageHandler{}
+		storage.Initialize(mockHelper, mockHandler)
+		mockHelper.On("AtTip", ctx, mock.Anything).Return(true, nil)
+		mockHelper.On("CurrentBlockIdentifier", ctx).Return(block.BlockIdentifier, nil)
+
+		txn := storage.db.Transaction(ctx)
+		g, gctx := errgroup.WithContext(ctx)
+		setup(mockHelper, mockHandler, gctx)
+		commitWorker, err := storage.AddingBlock(gctx, g, block, txn)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Wait())
+		assert.NoError(t, txn.Commit(ctx))
+		assert.NoError(t, commitWorker(ctx))
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	}
+
+	t.Run("broadcast at block 0", func(t *testing.T) {
+		dbTx := db.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Broadcast(
+			ctx,
+			dbTx,
+			"broadcast 1",
+			network,
+			send1,
+			tx1.TransactionIdentifier,
+			"payload 1",
+			1,
+		))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		addBlock(t, blocks[0], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"BroadcastTransaction",
+				ctx,
+				network,
+				"payload 1",
+			).Return(
+				tx1.TransactionIdentifier,
+				nil,
+			).Once()
+		})
+		assert.Equal(t, 0, checks)
+	})
+
+	t.Run("included but not final at block 1", func(t *testing.T) {
+		addBlock(t, blocks[1], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				tx1.TransactionIdentifier,
+				mock.Anything,
+			).Return(blocks[1].BlockIdentifier, tx1, nil).Once()
+		})
+		assert.Equal(t, 1, checks)
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 1)
+	})
+
+	t.Run("final at block 2", func(t *testing.T) {
+		addBlock(t, blocks[2], func(
+			mockHelper *mocks.BroadcastStorageHelper,
+			mockHandler *mocks.BroadcastStorageHandler,
+			gctx context.Context,
+		) {
+			mockHelper.On(
+				"FindTransaction",
+				gctx,
+				tx1.TransactionIdentifier,
+				mock.Anything,
+			).Return(blocks[1].BlockIdentifier, tx1, nil).Once()
+			mockHandler.On(
+				"TransactionConfirmed",
+				gctx,
+				mock.Anything,
+				"broadcast 1",
+				blocks[1].BlockIdentifier,
+				tx1,
+				send1,
+			).Return(nil).Once()
+		})
+		assert.Equal(t, 2, checks)
+
+		broadcasts, err := storage.GetAllBroadcasts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, broadcasts, 0)
+	})
+}
@@ -16,7 +16,9 @@ package modules
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"log"
 	"math/big"
 	"runtime"
 	"strings"
@@ -24,6 +26,7 @@ import (
 	"github.com/neilotoole/errgroup"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/parser"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
 	"github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -37,6 +40,8 @@ const (
 
 var _ BlockWorker = (*CoinStorage)(nil)
 
+var errCoinPageFull = goerrors.New("coin page full")
+
 // CoinStorage implements storage methods for storing
 // UTXOs.
 type CoinStorage struct {
@@ -122,6 +127,24 @@ func (c *CoinStorage) AddCoins(
 	dbTransaction := c.db.Transaction(ctx)
 	defer dbTransaction.Discard(ctx)
 
+	if err := c.AddCoinsTransactional(ctx, dbTransaction, accountCoins); err != nil {
+		return err
+	}
+
+	if err := dbTransaction.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrReconciliationUpdateCommitFailed, err)
+	}
+
+	return nil
+}
+
+// AddCoinsTransactional saves an array of AccountCoins in a database
+// transaction. Coins that are already stored are skipped.
+func (c *CoinStorage) AddCoinsTransactional(
+	ctx context.Context,
+	dbTransaction database.Transaction,
+	accountCoins []*types.AccountCoin,
+) error {
 	for _, accountCoin := range accountCoins {
 		exists, _, _, err := c.getAndDecodeCoin(ctx, dbTransaction, accountCoin.Coin.CoinIdentifier)
 		if err != nil {
@@ -138,10 +161,6 @@ func (c *CoinStorage) AddCoins(
 		}
 	}
 
-	if err := dbTransaction.Commit(ctx); err != nil {
-		return fmt.Errorf("%w: %v", errors.ErrReconciliationUpdateCommitFailed, err)
-	}
-
 	return nil
 }
 
@@ -164,11 +183,17 @@ func (c *CoinStorage) addCoin(
 		return fmt.Errorf("%w: %v", errors.ErrCoinStoreFailed, err)
 	}
 
+	// We store the encoded coin in the account index so that
+	// an account's coins can be iterated without looking up
+	// each coin separately. We copy encodedResult because it
+	// may be reclaimed after the transaction is committed.
+	accountCoinValue := make([]byte, len(encodedResult))
+	copy(accountCoinValue, encodedResult)
 	if err := storeUniqueKey(
 		ctx,
 		transaction,
 		getCoinAccountCoin(account, coin.CoinIdentifier),
-		[]byte(""),
+		accountCoinValue,
 		false,
 	); err != nil {
 		return fmt.Errorf("%w: %v", errors.ErrAccountCoinStoreFailed, err)
@@ -177,32 +202,6 @@ func (c *CoinStorage) addCoin(
 	return nil
 }
 
-func getAndDecodeCoins(
-	ctx context.Context,
-	transaction database.Transaction,
-	accountIdentifier *types.AccountIdentifier,
-) (map[string]struct{}, error) {
-	coins := map[string]struct{}{}
-	_, err := transaction.Scan(
-		ctx,
-		getCoinAccountPrefix(accountIdentifier),
-		getCoinAccountPrefix(accountIdentifier),
-		func(k []byte, v []byte) error {
-			vals := strings.Split(string(k), "/")
-			coinIdentifier := vals[len(vals)-1]
-			coins[coinIdentifier] = struct{}{}
-			return nil
-		},
-		false,
-		false,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errors.ErrAccountCoinQueryFailed, err)
-	}
-
-	return coins, nil
-}
-
 func (c *CoinStorage) removeCoin(
 	ctx context.Context,
 	account *types.AccountIdentifier,
@@ -230,29 +229,6 @@ func (c *CoinStorage) removeCoin(
 	return nil
 }
 
-func (c *CoinStorage) skipOperation(
-	operation *types.Operation,
-) (bool, error) {
-	if operation.CoinChange == nil {
-		return true, nil
-	}
-
-	if operation.Amount == nil {
-		return true, nil
-	}
-
-	success, err := c.asserter.OperationSuccessful(operation)
-	if err != nil {
-		return false, fmt.Errorf("%w: %v", errors.ErrOperationParseFailed, err)
-	}
-
-	if !success {
-		return true, nil
-	}
-
-	return false, nil
-}
-
 // updateCoins iterates through the transactions
 // in a block to determine which coins to add
 // and remove from storage.
@@ -263,59 +239,32 @@ func (c *CoinStorage) skipOperation(
 // Alternatively, we could add all coins to the database
 // (regardless of whether they are spent in the same block),
 // however, this would put a larger strain on the db.
-func (c *CoinStorage) updateCoins( // nolint:gocognit
+func (c *CoinStorage) updateCoins(
 	ctx context.Context,
 	g *errgroup.Group,
 	block *types.Block,
 	addCoinCreated bool,
 	dbTx database.Transaction,
 ) error {
-	addCoins := map[string]*types.Operation{}
-	removeCoins := map[string]*types.Operation{}
-
-	for _, txn := range block.Transactions {
-		for _, operation := range txn.Operations {
-			skip, err := c.skipOperation(operation)
-			if err != nil {
-				return fmt.Errorf("%w: %v", errors.ErrUnableToDetermineIfSkipOperation, err)
-			}
-			if skip {
-				continue
-			}
-
-			coinChange := operation.CoinChange
-			identifier := coinChange.CoinIdentifier.Identifier
-			coinDict := removeCoins
-			if addCoinCreated && coinChange.CoinAction == types.CoinCreated ||
-				!addCoinCreated && coinChange.CoinAction == types.CoinSpent {
-				coinDict = addCoins
-			}
-
-			if _, ok := coinDict[identifier]; ok {
-				return fmt.Errorf("%w %s", errors.ErrDuplicateCoinFound, identifier)
-			}
-
-			coinDict[identifier] = operation
+	changes, err := parser.New(c.asserter, nil, nil).CoinChanges(block, !addCoinCreated)
+	if err != nil {
+		if goerrors.Is(err, parser.ErrCoinChangesDuplicateCoin) {
+			return fmt.Errorf("%w: %v", errors.ErrDuplicateCoinFound, err)
 		}
-	}
 
-	for identifier, val := range addCoins {
-		if _, ok := removeCoins[identifier]; ok {
-			continue
-		}
+		return fmt.Errorf("%w: %v", errors.ErrUnableToDetermineIfSkipOperation, err)
+	}
 
+	for _, val := range changes.Added {
 		// We need to set variable before calling goroutine
 		// to avoid getting an updated pointer as loop iteration
 		// continues.
-		op := val
+		accountCoin := val
 		g.Go(func() error {
 			if err := c.addCoin(
 				ctx,
-				op.Account,
-				&types.Coin{
-					CoinIdentifier: op.CoinChange.CoinIdentifier,
-					Amount:         op.Amount,
-				},
+				accountCoin.Account,
+				accountCoin.Coin,
 				dbTx,
 			); err != nil {
 				return fmt.Errorf("%w: %v", errors.ErrCoinAddFailed, err)
@@ -325,20 +274,16 @@ func (c *CoinStorage) updateCoins( // nolint:gocognit
 		})
 	}
 
-	for identifier, val := range removeCoins {
-		if _, ok := addCoins[identifier]; ok {
-			continue
-		}
-
+	for _, val := range changes.Removed {
 		// We need to set variable before calling goroutine
 		// to avoid getting an updated pointer as loop iteration
 		// continues.
-		op := val
+		accountCoin := val
 		g.Go(func() error {
 			if err := c.removeCoin(
 				ctx,
-				op.Account,
-				op.CoinChange.CoinIdentifier,
+				accountCoin.Account,
+				accountCoin.Coin.CoinIdentifier,
 				dbTx,
 			); err != nil {
 				return fmt.Errorf("%w: %v", errors.ErrCoinRemoveFailed, err)
@@ -371,14 +316,166 @@ func (c *CoinStorage) RemovingBlock(
 	return nil, c.updateCoins(ctx, g, block, false, transaction)
 }
 
+// CoinFilter limits the coins returned when iterating
+// over the coins of a *types.AccountIdentifier.
+type CoinFilter struct {
+	// Currency only includes coins of a particular
+	// *types.Currency, if populated.
+	Currency *types.Currency
+
+	// MinimumValue only includes coins with a value
+	// >= MinimumValue, if populated.
+	MinimumValue *big.Int
+}
+
+// matches returns a boolean indicating if a *types.Coin
+// satisfies the CoinFilter.
+func (f *CoinFilter) matches(coin *types.Coin) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	if f.Currency != nil && types.Hash(coin.Amount.Currency) != types.Hash(f.Currency) {
+		return false, nil
+	}
+
+	if f.MinimumValue == nil {
+		return true, nil
+	}
+
+	val, ok := new(big.Int).SetString(coin.Amount.Value, 10)
+	if !ok {
+		return false, fmt.Errorf(
+			"%w %s",
+			errors.ErrCoinParseFailed,
+			coin.CoinIdentifier.Identifier,
+		)
+	}
+
+	return val.Cmp(f.MinimumValue) >= 0, nil
+}
+
+// IterateCoinsTransactional invokes worker on each unspent coin
+// for a provided *types.AccountIdentifier that satisfies filter
+// (which may be nil), ordered by coin identifier. If cursor is
+// populated, iteration starts after the coin with that identifier.
+// Coins are decoded one at a time, so accounts with many coins
+// are never loaded into memory at once. If worker returns
+// an error, iteration stops and that error is returned.
+func (c *CoinStorage) IterateCoinsTransactional(
+	ctx context.Context,
+	dbTx database.Transaction,
+	accountIdentifier *types.AccountIdentifier,
+	filter *CoinFilter,
+	cursor string,
+	worker func(*types.Coin) error,
+) error {
+	prefix := getCoinAccountPrefix(accountIdentifier)
+	seekStart := prefix
+	if len(cursor) > 0 {
+		seekStart = getCoinAccountCoin(
+			accountIdentifier,
+			&types.CoinIdentifier{Identifier: cursor},
+		)
+	}
+
+	var workerErr error
+	_, err := dbTx.Scan(
+		ctx,
+		prefix,
+		seekStart,
+		func(k []byte, v []byte) error {
+			coinIdentifier := strings.TrimPrefix(string(k), string(prefix)+"/")
+			if coinIdentifier == cursor {
+				return nil
+			}
+
+			coin, err := c.decodeAccountCoin(ctx, dbTx, coinIdentifier, v)
+			if err != nil {
+				return err
+			}
+
+			ok, err := filter.matches(coin)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return nil
+			}
+
+			if err := worker(coin); err != nil {
+				workerErr = err
+				return err
+			}
+
+			return nil
+		},
+		false,
+		false,
+	)
+	if workerErr != nil {
+		return workerErr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrAccountCoinQueryFailed, err)
+	}
+
+	return nil
+}
+
+// decodeAccountCoin decodes a *types.Coin stored in the account
+// index. Account index entries created before coins were stored
+// in the index are empty, so we fall back to looking up the coin.
+func (c *CoinStorage) decodeAccountCoin(
+	ctx context.Context,
+	dbTx database.Transaction,
+	coinIdentifier string,
+	val []byte,
+) (*types.Coin, error) {
+	if len(val) > 0 {
+		var accountCoin types.AccountCoin
+		if err := c.db.Encoder().DecodeAccountCoin(val, &accountCoin, false); err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrCoinDecodeFailed, err)
+		}
+
+		return accountCoin.Coin, nil
+	}
+
+	exists, coin, _, err := c.getAndDecodeCoin(
+		ctx,
+		dbTx,
+		&types.CoinIdentifier{Identifier: coinIdentifier},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrCoinQueryFailed, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%w %s", errors.ErrCoinGetFailed, coinIdentifier)
+	}
+
+	return coin, nil
+}
+
 // GetCoinsTransactional returns all unspent coins for a provided *types.AccountIdentifier.
 func (c *CoinStorage) GetCoinsTransactional(
 	ctx context.Context,
 	dbTx database.Transaction,
 	accountIdentifier *types.AccountIdentifier,
 ) ([]*types.Coin, *types.BlockIdentifier, error) {
-	coins, err := getAndDecodeCoins(ctx, dbTx, accountIdentifier)
-	if err != nil {
+	coinArr := []*types.Coin{}
+	if err := c.IterateCoinsTransactional(
+		ctx,
+		dbTx,
+		accountIdentifier,
+		nil,
+		"",
+		func(coin *types.Coin) error {
+			coinArr = append(coinArr, coin)
+			return nil
+		},
+	); err != nil {
 		return nil, nil, fmt.Errorf("%w: %v", errors.ErrAccountIdentifierQueryFailed, err)
 	}
 
@@ -387,24 +484,6 @@ func (c *CoinStorage) GetCoinsTransactional(
 		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCurrentBlockGetFailed, err)
 	}
 
-	coinArr := []*types.Coin{}
-	for coinIdentifier := range coins {
-		exists, coin, _, err := c.getAndDecodeCoin(
-			ctx,
-			dbTx,
-			&types.CoinIdentifier{Identifier: coinIdentifier},
-		)
-		if err != nil {
-			return nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinQueryFailed, err)
-		}
-
-		if !exists {
-			return nil, nil, fmt.Errorf("%w %s: %v", errors.ErrCoinGetFailed, coinIdentifier, err)
-		}
-
-		coinArr = append(coinArr, coin)
-	}
-
 	return coinArr, headBlockIdentifier, nil
 }
 
@@ -449,6 +528,110 @@ func (c *CoinStorage) GetCoin(
 	return c.GetCoinTransactional(ctx, dbTx, coinIdentifier)
 }
 
+// GetCoinsPage returns at most limit unspent coins for a provided
+// *types.AccountIdentifier that satisfy filter (which may be nil),
+// starting after cursor. The returned cursor can be provided to
+// fetch the next page and is empty when there are no more coins.
+func (c *CoinStorage) GetCoinsPage(
+	ctx context.Context,
+	accountIdentifier *types.AccountIdentifier,
+	filter *CoinFilter,
+	cursor string,
+	limit int,
+) ([]*types.Coin, string, *types.BlockIdentifier, error) {
+	if limit <= 0 {
+		return nil, "", nil, fmt.Errorf("%w: %d", errors.ErrCoinPageLimitInvalid, limit)
+	}
+
+	dbTx := c.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	// We fetch one more coin than requested to determine
+	// if there is another page.
+	coins := []*types.Coin{}
+	hasMore := false
+	err := c.IterateCoinsTransactional(
+		ctx,
+		dbTx,
+		accountIdentifier,
+		filter,
+		cursor,
+		func(coin *types.Coin) error {
+			if len(coins) == limit {
+				hasMore = true
+				return errCoinPageFull
+			}
+
+			coins = append(coins, coin)
+			return nil
+		},
+	)
+	if err != nil && !goerrors.Is(err, errCoinPageFull) {
+		return nil, "", nil, fmt.Errorf("%w: %v", errors.ErrAccountIdentifierQueryFailed, err)
+	}
+
+	headBlockIdentifier, err := c.helper.CurrentBlockIdentifier(ctx, dbTx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("%w: %v", errors.ErrCurrentBlockGetFailed, err)
+	}
+
+	nextCursor := ""
+	if hasMore {
+		nextCursor = coins[len(coins)-1].CoinIdentifier.Identifier
+	}
+
+	return coins, nextCursor, headBlockIdentifier, nil
+}
+
+// GetCoinBalance returns the sum of all unspent coins for a
+// *types.AccountIdentifier and *types.Currency without loading
+// all coins into memory.
+func (c *CoinStorage) GetCoinBalance(
+	ctx context.Context,
+	accountIdentifier *types.AccountIdentifier,
+	currency *types.Currency,
+) (*big.Int, *types.BlockIdentifier, error) {
+	dbTx := c.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	bal := big.NewInt(0)
+	err := c.IterateCoinsTransactional(
+		ctx,
+		dbTx,
+		accountIdentifier,
+		&CoinFilter{Currency: currency},
+		"",
+		func(coin *types.Coin) error {
+			val, ok := new(big.Int).SetString(coin.Amount.Value, 10)
+			if !ok {
+				return fmt.Errorf(
+					"%w %s",
+					errors.ErrCoinParseFailed,
+					coin.CoinIdentifier.Identifier,
+				)
+			}
+
+			bal.Add(bal, val)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"%w for %s: %v",
+			errors.ErrUTXOBalanceGetFailed,
+			accountIdentifier.Address,
+			err,
+		)
+	}
+
+	headBlockIdentifier, err := c.helper.CurrentBlockIdentifier(ctx, dbTx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCurrentBlockGetFailed, err)
+	}
+
+	return bal, headBlockIdentifier, nil
+}
+
 // GetLargestCoin returns the largest Coin for a
 // *types.AccountIdentifier and *types.Currency.
 // If no Coins are available, a 0 balance is returned.
@@ -457,7 +640,35 @@ func (c *CoinStorage) GetLargestCoin(
 	accountIdentifier *types.AccountIdentifier,
 	currency *types.Currency,
 ) (*big.Int, *types.CoinIdentifier, *types.BlockIdentifier, error) {
-	coins, blockIdentifier, err := c.GetCoins(ctx, accountIdentifier)
+	dbTx := c.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	bal := big.NewInt(0)
+	var coinIdentifier *types.CoinIdentifier
+	err := c.IterateCoinsTransactional(
+		ctx,
+		dbTx,
+		accountIdentifier,
+		&CoinFilter{Currency: currency},
+		"",
+		func(coin *types.Coin) error {
+			val, ok := new(big.Int).SetString(coin.Amount.Value, 10)
+			if !ok {
+				return fmt.Errorf(
+					"%w %s",
+					errors.ErrCoinParseFailed,
+					coin.CoinIdentifier.Identifier,
+				)
+			}
+
+			if bal.Cmp(val) == -1 {
+				bal = val
+				coinIdentifier = coin.CoinIdentifier
+			}
+
+			return nil
+		},
+	)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf(
 			"%w for %s: %v",
@@ -467,33 +678,17 @@ func (c *CoinStorage) GetLargestCoin(
 		)
 	}
 
-	bal := big.NewInt(0)
-	var coinIdentifier *types.CoinIdentifier
-	for _, coin := range coins {
-		if types.Hash(
-			coin.Amount.Currency,
-		) != types.Hash(
-			currency,
-		) {
-			continue
-		}
-
-		val, ok := new(big.Int).SetString(coin.Amount.Value, 10)
-		if !ok {
-			return nil, nil, nil, fmt.Errorf(
-				"%w %s",
-				errors.ErrCoinParseFailed,
-				coin.CoinIdentifier.Identifier,
-			)
-		}
-
-		if bal.Cmp(val) == -1 {
-			bal = val
-			coinIdentifier = coin.CoinIdentifier
-		}
+	headBlockIdentifier, err := c.helper.CurrentBlockIdentifier(ctx, dbTx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf(
+			"%w for %s: %v",
+			errors.ErrUTXOBalanceGetFailed,
+			accountIdentifier.Address,
+			err,
+		)
 	}
 
-	return bal, coinIdentifier, blockIdentifier, nil
+	return bal, coinIdentifier, headBlockIdentifier, nil
 }
 
 // SetCoinsImported sets coins of a set of addresses by
@@ -521,3 +716,136 @@ func (c *CoinStorage) SetCoinsImported(
 
 	return nil
 }
+
+// BootstrapCoin represents a coin owned by a
+// *types.AccountIdentifier in the genesis block.
+type BootstrapCoin struct {
+	Account *types.AccountIdentifier `json:"account_identifier,omitempty"`
+	Coin    *types.Coin              `json:"coin,omitempty"`
+}
+
+// bootstrapCoinParser parses *BootstrapCoin
+// from JSON and CSV bootstrap files.
+var bootstrapCoinParser = &bootstrapParser{
+	columns: []string{
+		BootstrapAddressColumn,
+		BootstrapCoinIdentifierColumn,
+		BootstrapSymbolColumn,
+		BootstrapDecimalsColumn,
+		BootstrapValueColumn,
+	},
+	parseJSON: func(data []byte) (interface{}, error) {
+		var coin BootstrapCoin
+		if err := decodeBootstrapJSON(data, &coin); err != nil {
+			return nil, err
+		}
+
+		return &coin, nil
+	},
+	parseCSV: func(row *bootstrapRow) (interface{}, error) {
+		currency, err := row.currency()
+		if err != nil {
+			return nil, err
+		}
+
+		return &BootstrapCoin{
+			Account: row.account(),
+			Coin: &types.Coin{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: row.get(BootstrapCoinIdentifierColumn),
+				},
+				Amount: &types.Amount{
+					Value:    row.get(BootstrapValueColumn),
+					Currency: currency,
+				},
+			},
+		}, nil
+	},
+}
+
+// validateBootstrapCoin ensures a *BootstrapCoin
+// is a valid coin of a valid account.
+func validateBootstrapCoin(coin *BootstrapCoin) error {
+	if err := asserter.AccountIdentifier(coin.Account); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBootstrapEntryInvalid, err)
+	}
+
+	if err := asserter.Coin(coin.Coin); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBootstrapEntryInvalid, err)
+	}
+
+	return nil
+}
+
+// BootstrapCoins adds all coins in a bootstrap file
+// to storage. This is useful for chains where UTXOs
+// are allocated in the genesis block.
+//
+// The bootstrap file may be a JSON array of *BootstrapCoin
+// or a CSV file (see BootstrapFormatCSV) and is streamed, so
+// it is never loaded into memory at once. All entries are
+// validated before any coin is written. Coins are then
+// written in batches (see WithBootstrapBatchSize), so an error
+// while writing may leave some coins imported.
+func (c *CoinStorage) BootstrapCoins(
+	ctx context.Context,
+	bootstrapCoinsFile string,
+	options ...BootstrapOption,
+) error {
+	config := newBootstrapConfig(options...)
+
+	// Validate bootstrap file
+	if _, err := streamBootstrapFile(
+		ctx,
+		bootstrapCoinsFile,
+		config,
+		bootstrapCoinParser,
+		func(entry interface{}, _ *BootstrapProgress) error {
+			return validateBootstrapCoin(entry.(*BootstrapCoin))
+		},
+	); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinImportFailed, err)
+	}
+
+	accountCoins := make([]*types.AccountCoin, 0, config.batchSize)
+	addCoins := func(progress *BootstrapProgress) error {
+		if err := c.AddCoins(ctx, accountCoins); err != nil {
+			return err
+		}
+
+		accountCoins = accountCoins[:0]
+		config.reportProgress(progress)
+		return nil
+	}
+
+	progress, err := streamBootstrapFile(
+		ctx,
+		bootstrapCoinsFile,
+		config,
+		bootstrapCoinParser,
+		func(entry interface{}, progress *BootstrapProgress) error {
+			coin := entry.(*BootstrapCoin)
+			accountCoins = append(accountCoins, &types.AccountCoin{
+				Account: coin.Account,
+				Coin:    coin.Coin,
+			})
+			if len(accountCoins) < config.batchSize {
+				return nil
+			}
+
+			return addCoins(progress)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinImportFailed, err)
+	}
+
+	if len(accountCoins) > 0 {
+		if err := addCoins(progress); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrCoinImportFailed, err)
+		}
+	}
+
+	log.Printf("%d Coins Bootstrapped\n", progress.Entries)
+	return nil
+}
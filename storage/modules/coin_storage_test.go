@@ -16,8 +16,11 @@ package modules
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"math/big"
+	"path"
 	"testing"
 
 	"github.com/neilotoole/errgroup"
@@ -995,3 +998,249 @@ func TestCoinStorage(t *testing.T) {
 
 	mockHelper.AssertExpectations(t)
 }
+
+func TestCoinStoragePagination(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	mockHelper := &mocks.CoinStorageHelper{}
+	mockHelper.On("CurrentBlockIdentifier", ctx, mock.Anything).Return(blockIdentifier, nil)
+	c := NewCoinStorage(database, mockHelper, nil)
+
+	otherCurrency := &types.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+	}
+	newCoin := func(identifier string, value string, currency *types.Currency) *types.Coin {
+		return &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{Identifier: identifier},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: currency,
+			},
+		}
+	}
+	coins := []*types.Coin{
+		newCoin("coin1", "10", currency),
+		newCoin("coin2", "20", otherCurrency),
+		newCoin("coin3", "30", currency),
+		newCoin("coin4", "40", currency),
+		newCoin("coin5", "5", currency),
+	}
+	accountCoins := []*types.AccountCoin{}
+	for _, coin := range coins {
+		accountCoins = append(accountCoins, &types.AccountCoin{
+			Account: account,
+			Coin:    coin,
+		})
+	}
+	accountCoins = append(accountCoins, &types.AccountCoin{
+		Account: account2,
+		Coin:    newCoin("coin6", "100", currency),
+	})
+	assert.NoError(t, c.AddCoins(ctx, accountCoins))
+
+	// Simulate an account index entry written before coins
+	// were stored in the account index.
+	txn := c.db.Transaction(ctx)
+	assert.NoError(t, txn.Set(
+		ctx,
+		getCoinAccountCoin(account, coins[2].CoinIdentifier),
+		[]byte(""),
+		false,
+	))
+	assert.NoError(t, txn.Commit(ctx))
+
+	t.Run("invalid limit", func(t *testing.T) {
+		page, cursor, block, err := c.GetCoinsPage(ctx, account, nil, "", 0)
+		assert.True(t, errors.Is(err, storageErrs.ErrCoinPageLimitInvalid))
+		assert.Nil(t, page)
+		assert.Empty(t, cursor)
+		assert.Nil(t, block)
+	})
+
+	t.Run("paginate all coins", func(t *testing.T) {
+		page, cursor, block, err := c.GetCoinsPage(ctx, account, nil, "", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, coins[:2], page)
+		assert.Equal(t, "coin2", cursor)
+		assert.Equal(t, blockIdentifier, block)
+
+		page, cursor, _, err = c.GetCoinsPage(ctx, account, nil, cursor, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, coins[2:4], page)
+		assert.Equal(t, "coin4", cursor)
+
+		page, cursor, _, err = c.GetCoinsPage(ctx, account, nil, cursor, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, coins[4:], page)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("paginate filtered coins", func(t *testing.T) {
+		filter := &CoinFilter{
+			Currency:     currency,
+			MinimumValue: big.NewInt(10),
+		}
+		page, cursor, _, err := c.GetCoinsPage(ctx, account, filter, "", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Coin{coins[0], coins[2]}, page)
+		assert.Equal(t, "coin3", cursor)
+
+		page, cursor, _, err = c.GetCoinsPage(ctx, account, filter, cursor, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Coin{coins[3]}, page)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("balances", func(t *testing.T) {
+		bal, block, err := c.GetCoinBalance(ctx, account, currency)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(85), bal)
+		assert.Equal(t, blockIdentifier, block)
+
+		bal, _, err = c.GetCoinBalance(ctx, account2, currency)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(100), bal)
+
+		bal, coinIdentifier, _, err := c.GetLargestCoin(ctx, account, currency)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(40), bal)
+		assert.Equal(t, coins[3].CoinIdentifier, coinIdentifier)
+	})
+
+	t.Run("stop iteration", func(t *testing.T) {
+		dbTx := c.db.ReadTransaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		errStop := errors.New("stop")
+		seen := 0
+		err := c.IterateCoinsTransactional(
+			ctx,
+			dbTx,
+			account,
+			nil,
+			"",
+			func(coin *types.Coin) error {
+				seen++
+				return errStop
+			},
+		)
+		assert.True(t, errors.Is(err, errStop))
+		assert.Equal(t, 1, seen)
+	})
+}
+
+func TestBootstrapCoins(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	mockHelper := &mocks.CoinStorageHelper{}
+	mockHelper.On("CurrentBlockIdentifier", ctx, mock.Anything).Return(blockIdentifier, nil)
+	c := NewCoinStorage(database, mockHelper, nil)
+
+	newCoin := func(identifier string, value string) *types.Coin {
+		return &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{Identifier: identifier},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: currency,
+			},
+		}
+	}
+
+	t.Run("JSON file", func(t *testing.T) {
+		bootstrapCoinsFile := path.Join(newDir, "coins.json")
+		file, err := json.Marshal([]*BootstrapCoin{
+			{Account: account, Coin: newCoin("coin1", "10")},
+			{Account: account, Coin: newCoin("coin2", "20")},
+		})
+		assert.NoError(t, err)
+		assert.NoError(
+			t,
+			ioutil.WriteFile(bootstrapCoinsFile, file, utils.DefaultFilePermissions),
+		)
+
+		assert.NoError(t, c.BootstrapCoins(ctx, bootstrapCoinsFile))
+		coins, _, err := c.GetCoins(ctx, account)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*types.Coin{
+			newCoin("coin1", "10"),
+			newCoin("coin2", "20"),
+		}, coins)
+	})
+
+	t.Run("CSV file", func(t *testing.T) {
+		bootstrapCoinsFile := path.Join(newDir, "coins.csv")
+		assert.NoError(
+			t,
+			ioutil.WriteFile(
+				bootstrapCoinsFile,
+				[]byte(
+					"address,coin_identifier,symbol,decimals,value\n"+
+						"blah2,coin3,sym,12,30\n"+
+						"blah2,coin4,sym,12,40\n"+
+						"blah2,coin5,sym,12,50\n",
+				),
+				utils.DefaultFilePermissions,
+			),
+		)
+
+		entries := int64(0)
+		assert.NoError(t, c.BootstrapCoins(
+			ctx,
+			bootstrapCoinsFile,
+			WithBootstrapBatchSize(2),
+			WithBootstrapProgress(func(p *BootstrapProgress) {
+				entries = p.Entries
+			}),
+		))
+		assert.Equal(t, int64(3), entries)
+
+		coins, _, err := c.GetCoins(ctx, account2)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*types.Coin{
+			newCoin("coin3", "30"),
+			newCoin("coin4", "40"),
+			newCoin("coin5", "50"),
+		}, coins)
+	})
+
+	t.Run("Invalid coin", func(t *testing.T) {
+		bootstrapCoinsFile := path.Join(newDir, "invalid.csv")
+		assert.NoError(
+			t,
+			ioutil.WriteFile(
+				bootstrapCoinsFile,
+				[]byte(
+					"address,coin_identifier,symbol,decimals,value\n"+
+						"blah3,coin6,sym,12,60\n"+
+						"blah3,,sym,12,70\n",
+				),
+				utils.DefaultFilePermissions,
+			),
+		)
+
+		err := c.BootstrapCoins(ctx, bootstrapCoinsFile)
+		assert.True(t, errors.Is(err, storageErrs.ErrCoinImportFailed))
+		assert.Contains(t, err.Error(), storageErrs.ErrBootstrapEntryInvalid.Error())
+
+		coins, _, err := c.GetCoins(ctx, &types.AccountIdentifier{Address: "blah3"})
+		assert.NoError(t, err)
+		assert.Len(t, coins, 0)
+	})
+}
@@ -165,14 +165,14 @@ func (j *JobStorage) AllCompleted(ctx context.Context) ([]*job.Job, error) {
 	return j.getAllJobs(ctx, dbTx, getJobMetadataKey(completedKey))
 }
 
-func (j *JobStorage) getNextIdentifier(
+func (j *JobStorage) loadNextIdentifier(
 	ctx context.Context,
 	dbTx database.Transaction,
-) (string, error) {
+) (int, error) {
 	k := getJobMetadataKey("identifier")
 	exists, v, err := dbTx.Get(ctx, k)
 	if err != nil {
-		return "", fmt.Errorf("%w: %v", errors.ErrJobGetFailed, err)
+		return -1, fmt.Errorf("%w: %v", errors.ErrJobGetFailed, err)
 	}
 
 	// Get existing identifier
@@ -180,20 +180,44 @@ func (j *JobStorage) getNextIdentifier(
 	if exists {
 		err = j.db.Encoder().Decode("", v, &nextIdentifier, true)
 		if err != nil {
-			return "", fmt.Errorf("%w: %v", errors.ErrJobIdentifierDecodeFailed, err)
+			return -1, fmt.Errorf("%w: %v", errors.ErrJobIdentifierDecodeFailed, err)
 		}
 	} else {
 		nextIdentifier = 0
 	}
 
-	// Increment and save
-	encoded, err := j.db.Encoder().Encode("", nextIdentifier+1)
+	return nextIdentifier, nil
+}
+
+func (j *JobStorage) storeNextIdentifier(
+	ctx context.Context,
+	dbTx database.Transaction,
+	nextIdentifier int,
+) error {
+	encoded, err := j.db.Encoder().Encode("", nextIdentifier)
 	if err != nil {
-		return "", fmt.Errorf("%w: %v", errors.ErrJobIdentifierEncodeFailed, err)
+		return fmt.Errorf("%w: %v", errors.ErrJobIdentifierEncodeFailed, err)
 	}
 
-	if err := dbTx.Set(ctx, k, encoded, true); err != nil {
-		return "", fmt.Errorf("%w: %v", errors.ErrJobIdentifierUpdateFailed, err)
+	if err := dbTx.Set(ctx, getJobMetadataKey("identifier"), encoded, true); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrJobIdentifierUpdateFailed, err)
+	}
+
+	return nil
+}
+
+func (j *JobStorage) getNextIdentifier(
+	ctx context.Context,
+	dbTx database.Transaction,
+) (string, error) {
+	nextIdentifier, err := j.loadNextIdentifier(ctx, dbTx)
+	if err != nil {
+		return "", err
+	}
+
+	// Increment and save
+	if err := j.storeNextIdentifier(ctx, dbTx, nextIdentifier+1); err != nil {
+		return "", err
 	}
 
 	return strconv.Itoa(nextIdentifier), nil
@@ -402,3 +426,65 @@ func (j *JobStorage) Get(
 
 	return &output, nil
 }
+
+// Import stores in-flight (ready or broadcasting) *job.Jobs
+// under their existing identifiers (ex: Jobs returned by
+// AllProcessing on another database). The next identifier
+// assigned by Update is advanced past all imported identifiers
+// so that they are never reused.
+func (j *JobStorage) Import(
+	ctx context.Context,
+	dbTx database.Transaction,
+	jobs []*job.Job,
+) error {
+	nextIdentifier, err := j.loadNextIdentifier(ctx, dbTx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrJobIdentifierGetFailed, err)
+	}
+
+	for _, v := range jobs {
+		if len(v.Identifier) == 0 {
+			return fmt.Errorf("%w: job identifier is empty", errors.ErrJobImportFailed)
+		}
+
+		if v.Status != job.Ready && v.Status != job.Broadcasting {
+			return fmt.Errorf(
+				"%w: job %s is %s",
+				errors.ErrJobImportFailed,
+				v.Identifier,
+				v.Status,
+			)
+		}
+
+		k := getJobKey(v.Identifier)
+		exists, _, err := dbTx.Get(ctx, k)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrJobGetFailed, err)
+		}
+		if exists {
+			return fmt.Errorf("%w %s", errors.ErrJobAlreadyExists, v.Identifier)
+		}
+
+		encoded, err := j.db.Encoder().Encode("", v)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrJobEncodeFailed, err)
+		}
+
+		if err := dbTx.Set(ctx, k, encoded, true); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrJobUpdateFailed, err)
+		}
+
+		if err := j.updateMetadata(ctx, dbTx, nil, v); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrJobMetadataUpdateFailed, err)
+		}
+
+		// Identifiers assigned by Update are always
+		// integers.
+		identifier, err := strconv.Atoi(v.Identifier)
+		if err == nil && identifier >= nextIdentifier {
+			nextIdentifier = identifier + 1
+		}
+	}
+
+	return j.storeNextIdentifier(ctx, dbTx, nextIdentifier)
+}
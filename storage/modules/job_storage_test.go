@@ -16,11 +16,13 @@ package modules
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/coinbase/rosetta-sdk-go/constructor/job"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
@@ -331,3 +333,85 @@ func TestJobStorage(t *testing.T) {
 		assert.Equal(t, "", jobIdentifier)
 	})
 }
+
+func TestJobStorageImport(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	storage := NewJobStorage(database)
+
+	readyJob := &job.Job{
+		Identifier: "4",
+		Workflow:   "transfer",
+		Status:     job.Ready,
+		Index:      1,
+		State:      `{"create":{"account":"addr 1"}}`,
+	}
+	broadcastingJob := &job.Job{
+		Identifier: "7",
+		Workflow:   "transfer",
+		Status:     job.Broadcasting,
+		Index:      2,
+	}
+
+	t.Run("import terminal job", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		err := storage.Import(ctx, dbTx, []*job.Job{
+			{Identifier: "1", Workflow: "transfer", Status: job.Completed},
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrJobImportFailed))
+	})
+
+	t.Run("import jobs", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		assert.NoError(t, storage.Import(ctx, dbTx, []*job.Job{readyJob, broadcastingJob}))
+		assert.NoError(t, dbTx.Commit(ctx))
+
+		jobs, err := storage.AllProcessing(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*job.Job{readyJob, broadcastingJob}, jobs)
+
+		readTx := database.ReadTransaction(ctx)
+		defer readTx.Discard(ctx)
+
+		jobs, err = storage.Ready(ctx, readTx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{readyJob}, jobs)
+
+		jobs, err = storage.Broadcasting(ctx, readTx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*job.Job{broadcastingJob}, jobs)
+	})
+
+	t.Run("import existing job", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		err := storage.Import(ctx, dbTx, []*job.Job{readyJob})
+		assert.True(t, errors.Is(err, storageErrs.ErrJobAlreadyExists))
+	})
+
+	t.Run("identifiers are not reused", func(t *testing.T) {
+		dbTx := database.Transaction(ctx)
+		defer dbTx.Discard(ctx)
+
+		identifier, err := storage.Update(ctx, dbTx, &job.Job{
+			Workflow: "transfer",
+			Status:   job.Ready,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "8", identifier)
+		assert.NoError(t, dbTx.Commit(ctx))
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// scrypt parameters recommended for interactive logins.
+	scryptN      = 32768
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+
+	passphraseSaltLen = 16
+)
+
+var _ KeyEncrypter = (*PassphraseEncrypter)(nil)
+
+// KeyEncrypter is used by KeyStorage to encrypt private
+// keys at rest. Implementations backed by a KMS can
+// be provided to avoid storing a passphrase locally.
+//
+// associatedData identifies the key being encrypted (its
+// account and curve) and must be authenticated but not
+// encrypted (ex: as AEAD additional data or a KMS encryption
+// context), so that an encrypted private key can't be moved
+// to another account in storage without Decrypt failing.
+type KeyEncrypter interface {
+	Encrypt(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error)
+}
+
+// PassphraseEncrypter is a KeyEncrypter that encrypts
+// with AES-GCM using a key derived from a passphrase
+// with scrypt.
+type PassphraseEncrypter struct {
+	passphrase []byte
+	salt       []byte
+
+	// derivedKeys caches the key derived for each
+	// salt because scrypt is intentionally slow.
+	derivedKeys sync.Map
+}
+
+// NewPassphraseEncrypter returns a new *PassphraseEncrypter.
+func NewPassphraseEncrypter(passphrase string) (*PassphraseEncrypter, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.ErrPassphraseEmpty
+	}
+
+	salt := make([]byte, passphraseSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("%w: unable to generate salt", err)
+	}
+
+	return &PassphraseEncrypter{
+		passphrase: []byte(passphrase),
+		salt:       salt,
+	}, nil
+}
+
+func (p *PassphraseEncrypter) aead(salt []byte) (cipher.AEAD, error) {
+	derivedKey, ok := p.derivedKeys.Load(string(salt))
+	if !ok {
+		key, err := scrypt.Key(p.passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrKeyDerivationFailed, err)
+		}
+
+		derivedKey, _ = p.derivedKeys.LoadOrStore(string(salt), key)
+	}
+
+	block, err := aes.NewCipher(derivedKey.([]byte))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Encrypt returns the salt, nonce, and sealed plaintext.
+func (p *PassphraseEncrypter) Encrypt(
+	ctx context.Context,
+	plaintext []byte,
+	associatedData []byte,
+) ([]byte, error) {
+	aead, err := p.aead(p.salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("%w: unable to generate nonce", err)
+	}
+
+	ciphertext := append([]byte{}, p.salt...)
+	ciphertext = append(ciphertext, nonce...)
+	return aead.Seal(ciphertext, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt opens ciphertext returned by Encrypt. It fails if
+// associatedData does not match what was provided to Encrypt.
+func (p *PassphraseEncrypter) Decrypt(
+	ctx context.Context,
+	ciphertext []byte,
+	associatedData []byte,
+) ([]byte, error) {
+	if len(ciphertext) < passphraseSaltLen {
+		return nil, errors.ErrCiphertextTooShort
+	}
+
+	aead, err := p.aead(ciphertext[:passphraseSaltLen])
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext = ciphertext[passphraseSaltLen:]
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.ErrCiphertextTooShort
+	}
+
+	plaintext, err := aead.Open(
+		nil,
+		ciphertext[:aead.NonceSize()],
+		ciphertext[aead.NonceSize():],
+		associatedData,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrCiphertextInvalid, err)
+	}
+
+	return plaintext, nil
+}
+
+// KeyAction is an action performed
+// with a key in KeyStorage.
+type KeyAction string
+
+const (
+	// KeyStored is logged when a key is stored.
+	KeyStored KeyAction = "stored"
+
+	// KeyAccessed is logged when a private
+	// key is retrieved.
+	KeyAccessed KeyAction = "accessed"
+
+	// KeySigned is logged when a key is
+	// used to sign a payload.
+	KeySigned KeyAction = "signed"
+
+	// KeyExported is logged when a private
+	// key is exported.
+	KeyExported KeyAction = "exported"
+)
+
+// KeyAuditEvent describes a single use of a key.
+type KeyAuditEvent struct {
+	Time    time.Time                `json:"time"`
+	Action  KeyAction                `json:"action"`
+	Account *types.AccountIdentifier `json:"account"`
+}
+
+// KeyAuditLogger is invoked by KeyStorage
+// each time a key is used.
+type KeyAuditLogger interface {
+	LogKeyEvent(ctx context.Context, event *KeyAuditEvent) error
+}
+
+// WriterKeyAuditLogger is a KeyAuditLogger that
+// writes each KeyAuditEvent as a line of JSON.
+type WriterKeyAuditLogger struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+// NewWriterKeyAuditLogger returns a new *WriterKeyAuditLogger.
+func NewWriterKeyAuditLogger(w io.Writer) *WriterKeyAuditLogger {
+	return &WriterKeyAuditLogger{w: w}
+}
+
+// LogKeyEvent writes a KeyAuditEvent to the
+// underlying io.Writer.
+func (l *WriterKeyAuditLogger) LogKeyEvent(ctx context.Context, event *KeyAuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	_, err = l.w.Write(append(line, '\n'))
+	return err
+}
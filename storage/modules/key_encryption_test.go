@@ -0,0 +1,51 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+func TestPassphraseEncrypter(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewPassphraseEncrypter("")
+	assert.True(t, errors.Is(err, storageErrs.ErrPassphraseEmpty))
+
+	encrypter, err := NewPassphraseEncrypter("passphrase")
+	assert.NoError(t, err)
+
+	ciphertext, err := encrypter.Encrypt(ctx, []byte("secret"), []byte("key/1/secp256k1"))
+	assert.NoError(t, err)
+
+	plaintext, err := encrypter.Decrypt(ctx, ciphertext, []byte("key/1/secp256k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), plaintext)
+
+	_, err = encrypter.Decrypt(ctx, ciphertext, []byte("key/2/secp256k1"))
+	assert.True(t, errors.Is(err, storageErrs.ErrCiphertextInvalid))
+
+	_, err = encrypter.Decrypt(ctx, ciphertext, []byte("key/1/edwards25519"))
+	assert.True(t, errors.Is(err, storageErrs.ErrCiphertextInvalid))
+
+	_, err = encrypter.Decrypt(ctx, ciphertext[:passphraseSaltLen+1], nil)
+	assert.True(t, errors.Is(err, storageErrs.ErrCiphertextTooShort))
+}
@@ -16,9 +16,11 @@ package modules
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/keys"
 	"github.com/coinbase/rosetta-sdk-go/storage/database"
@@ -27,8 +29,9 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
 
-// WARNING: KEY STORAGE USING THIS PACKAGE IS NOT SECURE!!!! ONLY USE
-// FOR TESTING!!!!
+// WARNING: UNLESS A KeyEncrypter IS PROVIDED, KEY STORAGE USING THIS
+// PACKAGE STORES PRIVATE KEYS IN PLAINTEXT!!!! ONLY USE WITHOUT
+// ENCRYPTION FOR TESTING!!!!
 
 // PrefundedAccount is used to load prefunded addresses into key storage.
 type PrefundedAccount struct {
@@ -52,23 +55,169 @@ func getAccountKey(account *types.AccountIdentifier) []byte {
 // on top of a database.Database and database.Transaction interface.
 type KeyStorage struct {
 	db database.Database
+
+	encrypter   KeyEncrypter
+	auditLogger KeyAuditLogger
+}
+
+// KeyStorageOption is used to configure KeyStorage.
+type KeyStorageOption func(k *KeyStorage)
+
+// WithKeyEncrypter encrypts all private keys
+// at rest with a KeyEncrypter.
+func WithKeyEncrypter(encrypter KeyEncrypter) KeyStorageOption {
+	return func(k *KeyStorage) {
+		k.encrypter = encrypter
+	}
+}
+
+// WithKeyAuditLogger logs every time a key is
+// stored, accessed, used to sign, or exported.
+func WithKeyAuditLogger(auditLogger KeyAuditLogger) KeyStorageOption {
+	return func(k *KeyStorage) {
+		k.auditLogger = auditLogger
+	}
 }
 
 // NewKeyStorage returns a new KeyStorage.
 func NewKeyStorage(
 	db database.Database,
+	options ...KeyStorageOption,
 ) *KeyStorage {
-	return &KeyStorage{
+	k := &KeyStorage{
 		db: db,
 	}
+
+	for _, opt := range options {
+		opt(k)
+	}
+
+	return k
 }
 
 // Key is the struct stored in key storage. This
 // is public so that accounts can be loaded from
 // a configuration file.
+//
+// When a KeyEncrypter is used, the private key
+// is omitted from KeyPair and stored in
+// EncryptedPrivateKey instead.
 type Key struct {
-	Account *types.AccountIdentifier `json:"account"`
-	KeyPair *keys.KeyPair            `json:"keypair"`
+	Account             *types.AccountIdentifier `json:"account"`
+	KeyPair             *keys.KeyPair            `json:"keypair"`
+	EncryptedPrivateKey []byte                   `json:"encrypted_private_key,omitempty"`
+}
+
+func (k *KeyStorage) audit(
+	ctx context.Context,
+	action KeyAction,
+	account *types.AccountIdentifier,
+) error {
+	if k.auditLogger == nil {
+		return nil
+	}
+
+	if err := k.auditLogger.LogKeyEvent(ctx, &KeyAuditEvent{
+		Time:    time.Now(),
+		Action:  action,
+		Account: account,
+	}); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrKeyAuditFailed, err)
+	}
+
+	return nil
+}
+
+// keyAssociatedData returns the data authenticated with
+// an encrypted private key: the key ID (the storage key of
+// its account) and its curve.
+func keyAssociatedData(account *types.AccountIdentifier, curve types.CurveType) []byte {
+	return append(append(getAccountKey(account), '/'), curve...)
+}
+
+// sealKey returns the Key to store for a *keys.KeyPair,
+// encrypting the private key if there is a KeyEncrypter.
+func (k *KeyStorage) sealKey(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	keyPair *keys.KeyPair,
+) (*Key, error) {
+	if k.encrypter == nil {
+		return &Key{
+			Account: account,
+			KeyPair: keyPair,
+		}, nil
+	}
+
+	encrypted, err := k.encrypter.Encrypt(
+		ctx,
+		keyPair.PrivateKey,
+		keyAssociatedData(account, keyPair.PublicKey.CurveType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrKeyEncryptFailed, err)
+	}
+
+	return &Key{
+		Account: account,
+		KeyPair: &keys.KeyPair{
+			PublicKey: keyPair.PublicKey,
+		},
+		EncryptedPrivateKey: encrypted,
+	}, nil
+}
+
+// openKey returns the *keys.KeyPair of a stored
+// Key, decrypting the private key if necessary.
+func (k *KeyStorage) openKey(ctx context.Context, key *Key) (*keys.KeyPair, error) {
+	if len(key.EncryptedPrivateKey) == 0 {
+		return key.KeyPair, nil
+	}
+
+	if k.encrypter == nil {
+		return nil, fmt.Errorf(
+			"%w: %s",
+			storageErrs.ErrKeyEncrypted,
+			types.PrintStruct(key.Account),
+		)
+	}
+
+	privateKey, err := k.encrypter.Decrypt(
+		ctx,
+		key.EncryptedPrivateKey,
+		keyAssociatedData(key.Account, key.KeyPair.PublicKey.CurveType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s %v",
+			storageErrs.ErrKeyDecryptFailed,
+			types.PrintStruct(key.Account),
+			err,
+		)
+	}
+
+	return &keys.KeyPair{
+		PublicKey:  key.KeyPair.PublicKey,
+		PrivateKey: privateKey,
+	}, nil
+}
+
+func (k *KeyStorage) setKey(
+	ctx context.Context,
+	dbTx database.Transaction,
+	key *Key,
+) error {
+	val, err := k.db.Encoder().Encode("", key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrSerializeKeyFailed, err)
+	}
+
+	err = dbTx.Set(ctx, getAccountKey(key.Account), val, true)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrStoreKeyFailed, err)
+	}
+
+	return nil
 }
 
 // StoreTransactional stores a key in a database transaction.
@@ -96,20 +245,16 @@ func (k *KeyStorage) StoreTransactional(
 		)
 	}
 
-	val, err := k.db.Encoder().Encode("", &Key{
-		Account: account,
-		KeyPair: keyPair,
-	})
+	key, err := k.sealKey(ctx, account, keyPair)
 	if err != nil {
-		return fmt.Errorf("%w: %v", storageErrs.ErrSerializeKeyFailed, err)
+		return err
 	}
 
-	err = dbTx.Set(ctx, getAccountKey(account), val, true)
-	if err != nil {
-		return fmt.Errorf("%w: %v", storageErrs.ErrStoreKeyFailed, err)
+	if err := k.setKey(ctx, dbTx, key); err != nil {
+		return err
 	}
 
-	return nil
+	return k.audit(ctx, KeyStored, account)
 }
 
 // Store saves a keys.KeyPair for a given address. If the address already
@@ -139,6 +284,23 @@ func (k *KeyStorage) GetTransactional(
 	ctx context.Context,
 	dbTx database.Transaction,
 	account *types.AccountIdentifier,
+) (*keys.KeyPair, error) {
+	keyPair, err := k.getKeyPair(ctx, dbTx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := k.audit(ctx, KeyAccessed, account); err != nil {
+		return nil, err
+	}
+
+	return keyPair, nil
+}
+
+func (k *KeyStorage) getKeyPair(
+	ctx context.Context,
+	dbTx database.Transaction,
+	account *types.AccountIdentifier,
 ) (*keys.KeyPair, error) {
 	exists, rawKey, err := dbTx.Get(ctx, getAccountKey(account))
 	if err != nil {
@@ -159,7 +321,7 @@ func (k *KeyStorage) GetTransactional(
 		return nil, fmt.Errorf("%w: %v", storageErrs.ErrParseSavedKeyFailed, err)
 	}
 
-	return kp.KeyPair, nil
+	return k.openKey(ctx, &kp)
 }
 
 // Get returns a *keys.KeyPair for an AccountIdentifier, if it exists.
@@ -178,7 +340,26 @@ func (k *KeyStorage) GetAllAccountsTransactional(
 	ctx context.Context,
 	dbTx database.Transaction,
 ) ([]*types.AccountIdentifier, error) {
-	accounts := []*types.AccountIdentifier{}
+	storedKeys, err := k.getAllKeysTransactional(ctx, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*types.AccountIdentifier, len(storedKeys))
+	for i, key := range storedKeys {
+		accounts[i] = key.Account
+	}
+
+	return accounts, nil
+}
+
+// getAllKeysTransactional returns all Keys as they
+// are stored (without decrypting private keys).
+func (k *KeyStorage) getAllKeysTransactional(
+	ctx context.Context,
+	dbTx database.Transaction,
+) ([]*Key, error) {
+	storedKeys := []*Key{}
 	_, err := dbTx.Scan(
 		ctx,
 		[]byte(keyNamespace),
@@ -190,7 +371,7 @@ func (k *KeyStorage) GetAllAccountsTransactional(
 				return fmt.Errorf("%w: %v", storageErrs.ErrKeyScanFailed, err)
 			}
 
-			accounts = append(accounts, kp.Account)
+			storedKeys = append(storedKeys, &kp)
 			return nil
 		},
 		false,
@@ -200,7 +381,7 @@ func (k *KeyStorage) GetAllAccountsTransactional(
 		return nil, fmt.Errorf("%w: %v", storageErrs.ErrKeyScanFailed, err)
 	}
 
-	return accounts, nil
+	return storedKeys, nil
 }
 
 // GetAllAccounts returns all AccountIdentifiers in key storage.
@@ -216,9 +397,12 @@ func (k *KeyStorage) Sign(
 	ctx context.Context,
 	payloads []*types.SigningPayload,
 ) ([]*types.Signature, error) {
+	dbTx := k.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
 	signatures := make([]*types.Signature, len(payloads))
 	for i, payload := range payloads {
-		keyPair, err := k.Get(ctx, payload.AccountIdentifier)
+		keyPair, err := k.getKeyPair(ctx, dbTx, payload.AccountIdentifier)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"%w for %s: %v",
@@ -242,6 +426,10 @@ func (k *KeyStorage) Sign(
 			return nil, fmt.Errorf("%w for %d: %v", storageErrs.ErrSignPayloadFailed, i, err)
 		}
 
+		if err := k.audit(ctx, KeySigned, payload.AccountIdentifier); err != nil {
+			return nil, err
+		}
+
 		signatures[i] = signature
 	}
 
@@ -286,3 +474,102 @@ func (k *KeyStorage) ImportAccounts(ctx context.Context, accounts []*PrefundedAc
 	}
 	return nil
 }
+
+// ExportPublicKeys returns all Keys in key storage
+// without any private key material.
+func (k *KeyStorage) ExportPublicKeys(ctx context.Context) ([]*Key, error) {
+	dbTx := k.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	storedKeys, err := k.getAllKeysTransactional(ctx, dbTx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrKeyExportFailed, err)
+	}
+
+	publicKeys := make([]*Key, len(storedKeys))
+	for i, key := range storedKeys {
+		publicKeys[i] = &Key{
+			Account: key.Account,
+			KeyPair: &keys.KeyPair{
+				PublicKey: key.KeyPair.PublicKey,
+			},
+		}
+	}
+
+	return publicKeys, nil
+}
+
+// ExportAccounts returns all keys in key storage
+// as PrefundedAccounts (which can be loaded with
+// ImportAccounts). Private keys are decrypted, so
+// the output should be handled with care.
+func (k *KeyStorage) ExportAccounts(ctx context.Context) ([]*PrefundedAccount, error) {
+	dbTx := k.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	storedKeys, err := k.getAllKeysTransactional(ctx, dbTx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrKeyExportFailed, err)
+	}
+
+	accounts := make([]*PrefundedAccount, len(storedKeys))
+	for i, key := range storedKeys {
+		keyPair, err := k.openKey(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", storageErrs.ErrKeyExportFailed, err)
+		}
+
+		if err := k.audit(ctx, KeyExported, key.Account); err != nil {
+			return nil, err
+		}
+
+		accounts[i] = &PrefundedAccount{
+			PrivateKeyHex:     hex.EncodeToString(keyPair.PrivateKey),
+			AccountIdentifier: key.Account,
+			CurveType:         keyPair.PublicKey.CurveType,
+		}
+	}
+
+	return accounts, nil
+}
+
+// EncryptKeys encrypts all keys that were stored
+// in plaintext (before a KeyEncrypter was provided)
+// and returns the number of keys encrypted.
+func (k *KeyStorage) EncryptKeys(ctx context.Context) (int, error) {
+	if k.encrypter == nil {
+		return -1, fmt.Errorf("%w: no key encrypter provided", storageErrs.ErrKeyEncryptFailed)
+	}
+
+	dbTx := k.db.Transaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	storedKeys, err := k.getAllKeysTransactional(ctx, dbTx)
+	if err != nil {
+		return -1, err
+	}
+
+	encrypted := 0
+	for _, key := range storedKeys {
+		if len(key.EncryptedPrivateKey) > 0 {
+			continue
+		}
+
+		sealed, err := k.sealKey(ctx, key.Account, key.KeyPair)
+		if err != nil {
+			return -1, err
+		}
+
+		if err := k.setKey(ctx, dbTx, sealed); err != nil {
+			return -1, err
+		}
+
+		encrypted++
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return -1, fmt.Errorf("%w: %v", storageErrs.ErrCommitKeyFailed, err)
+	}
+
+	return encrypted, nil
+}
@@ -15,13 +15,18 @@
 package modules
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/coinbase/rosetta-sdk-go/keys"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
 )
@@ -217,3 +222,161 @@ func TestKeyStorage(t *testing.T) {
 		assert.Equal(t, endLen, startingLen)
 	})
 }
+
+func TestKeyStorageEncryption(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	encrypter, err := NewPassphraseEncrypter("correct horse battery staple")
+	assert.NoError(t, err)
+
+	var auditLog bytes.Buffer
+	k := NewKeyStorage(
+		database,
+		WithKeyEncrypter(encrypter),
+		WithKeyAuditLogger(NewWriterKeyAuditLogger(&auditLog)),
+	)
+
+	kp1, err := keys.GenerateKeypair(types.Edwards25519)
+	assert.NoError(t, err)
+	account1 := &types.AccountIdentifier{Address: "addr1"}
+
+	kp2, err := keys.GenerateKeypair(types.Secp256k1)
+	assert.NoError(t, err)
+	account2 := &types.AccountIdentifier{Address: "addr2"}
+
+	t.Run("plaintext key encrypted later", func(t *testing.T) {
+		plaintext := NewKeyStorage(database)
+		assert.NoError(t, plaintext.Store(ctx, account2, kp2))
+
+		encrypted, err := k.EncryptKeys(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, encrypted)
+
+		_, err = plaintext.Get(ctx, account2)
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyEncrypted))
+
+		encrypted, err = k.EncryptKeys(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, encrypted)
+
+		_, err = plaintext.EncryptKeys(ctx)
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyEncryptFailed))
+	})
+
+	t.Run("private key is not stored in plaintext", func(t *testing.T) {
+		assert.NoError(t, k.Store(ctx, account1, kp1))
+
+		txn := database.ReadTransaction(ctx)
+		exists, raw, err := txn.Get(ctx, getAccountKey(account1))
+		txn.Discard(ctx)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		var stored Key
+		assert.NoError(t, database.Encoder().Decode("", raw, &stored, false))
+		assert.Nil(t, stored.KeyPair.PrivateKey)
+		assert.NotEmpty(t, stored.EncryptedPrivateKey)
+		assert.Equal(t, kp1.PublicKey, stored.KeyPair.PublicKey)
+
+		v, err := k.Get(ctx, account1)
+		assert.NoError(t, err)
+		assert.Equal(t, kp1, v)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		wrongEncrypter, err := NewPassphraseEncrypter("wrong")
+		assert.NoError(t, err)
+
+		wrong := NewKeyStorage(database, WithKeyEncrypter(wrongEncrypter))
+		_, err = wrong.Get(ctx, account1)
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyDecryptFailed))
+
+		accounts, err := wrong.GetAllAccounts(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*types.AccountIdentifier{account1, account2}, accounts)
+	})
+
+	t.Run("encrypted key moved to another account", func(t *testing.T) {
+		txn := database.ReadTransaction(ctx)
+		_, raw, err := txn.Get(ctx, getAccountKey(account1))
+		txn.Discard(ctx)
+		assert.NoError(t, err)
+
+		var stored Key
+		assert.NoError(t, database.Encoder().Decode("", raw, &stored, false))
+		stored.Account = &types.AccountIdentifier{Address: "addr3"}
+
+		_, err = k.openKey(ctx, &stored)
+		assert.True(t, errors.Is(err, storageErrs.ErrKeyDecryptFailed))
+		assert.Contains(t, err.Error(), storageErrs.ErrCiphertextInvalid.Error())
+	})
+
+	t.Run("sign payloads", func(t *testing.T) {
+		sigs, err := k.Sign(ctx, []*types.SigningPayload{
+			{
+				AccountIdentifier: account1,
+				Bytes:             hash("msg1"),
+				SignatureType:     types.Ed25519,
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, (&keys.SignerEdwards25519{}).Verify(sigs[0]))
+	})
+
+	t.Run("export public keys", func(t *testing.T) {
+		exported, err := k.ExportPublicKeys(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, exported, 2)
+		for _, key := range exported {
+			assert.Nil(t, key.KeyPair.PrivateKey)
+			assert.Empty(t, key.EncryptedPrivateKey)
+			assert.NotNil(t, key.KeyPair.PublicKey)
+		}
+	})
+
+	t.Run("export and import accounts", func(t *testing.T) {
+		exported, err := k.ExportAccounts(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, exported, 2)
+
+		otherDir, err := utils.CreateTempDir()
+		assert.NoError(t, err)
+		defer utils.RemoveTempDir(otherDir)
+
+		otherDatabase, err := newTestBadgerDatabase(ctx, otherDir)
+		assert.NoError(t, err)
+		defer otherDatabase.Close(ctx)
+
+		other := NewKeyStorage(otherDatabase, WithKeyEncrypter(encrypter))
+		assert.NoError(t, other.ImportAccounts(ctx, exported))
+
+		v, err := other.Get(ctx, account2)
+		assert.NoError(t, err)
+		assert.Equal(t, kp2, v)
+	})
+
+	t.Run("audit log", func(t *testing.T) {
+		actions := map[KeyAction]int{}
+		for _, line := range strings.Split(strings.TrimSpace(auditLog.String()), "\n") {
+			var event KeyAuditEvent
+			assert.NoError(t, json.Unmarshal([]byte(line), &event))
+			assert.NotNil(t, event.Account)
+			actions[event.Action]++
+		}
+
+		assert.Equal(t, map[KeyAction]int{
+			KeyStored:   1,
+			KeyAccessed: 1,
+			KeySigned:   1,
+			KeyExported: 2,
+		}, actions)
+	})
+}
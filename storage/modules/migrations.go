@@ -0,0 +1,37 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+)
+
+// Migrations are the storage layout migrations applied (in
+// order) by Migrate. Any change to the keys or encoding of
+// data stored by a module must be accompanied by a migration
+// so that existing databases are not silently corrupted. A
+// migration must never be modified once released (add a new
+// migration instead).
+var Migrations = []*database.Migration{}
+
+// Migrate applies any Migrations that have not yet been
+// applied to db and returns the schema version of db. Migrate
+// must be called before db is provided to any module. An error
+// is returned if db was migrated by a newer version of the SDK.
+func Migrate(ctx context.Context, db database.Database) (int64, error) {
+	return database.Migrate(ctx, db, Migrations)
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+)
+
+var _ database.Transaction = (*ModuleTransaction)(nil)
+
+// RollbackWorker is registered with a *ModuleTransaction to be
+// called if the transaction is discarded instead of committed.
+type RollbackWorker func(context.Context)
+
+// ModuleTransaction is a single exclusive database.Transaction that
+// can be shared by multiple storage modules (i.e. adding a block,
+// updating balances, adding coins, and recording a broadcast) so that
+// all of their changes are committed or discarded atomically.
+//
+// Modules can register CommitWorkers that are invoked after the
+// transaction is committed and RollbackWorkers that are invoked
+// if it is discarded.
+type ModuleTransaction struct {
+	database.Transaction
+
+	lock            sync.Mutex
+	commitWorkers   []database.CommitWorker
+	rollbackWorkers []RollbackWorker
+	closed          bool
+}
+
+// NewModuleTransaction opens a new exclusive write transaction
+// on a database.Database. The transaction must be either
+// committed or discarded.
+func NewModuleTransaction(
+	ctx context.Context,
+	db database.Database,
+) *ModuleTransaction {
+	return &ModuleTransaction{
+		Transaction: db.Transaction(ctx),
+	}
+}
+
+// OnCommit registers a CommitWorker to be called after
+// the transaction is committed. CommitWorkers are invoked
+// in the order they are registered.
+func (m *ModuleTransaction) OnCommit(worker database.CommitWorker) {
+	if worker == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.commitWorkers = append(m.commitWorkers, worker)
+}
+
+// OnRollback registers a RollbackWorker to be called if
+// the transaction is discarded or fails to commit.
+func (m *ModuleTransaction) OnRollback(worker RollbackWorker) {
+	if worker == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.rollbackWorkers = append(m.rollbackWorkers, worker)
+}
+
+// close marks the transaction as closed and returns the
+// registered workers. If the transaction is already closed,
+// ok is false.
+func (m *ModuleTransaction) close() ([]database.CommitWorker, []RollbackWorker, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return nil, nil, false
+	}
+
+	m.closed = true
+	return m.commitWorkers, m.rollbackWorkers, true
+}
+
+// Commit commits all changes made by any module in the
+// transaction and then invokes all registered CommitWorkers.
+// If the commit fails, all RollbackWorkers are invoked instead.
+func (m *ModuleTransaction) Commit(ctx context.Context) error {
+	commitWorkers, rollbackWorkers, ok := m.close()
+	if !ok {
+		return storageErrs.ErrTransactionClosed
+	}
+
+	if err := m.Transaction.Commit(ctx); err != nil {
+		invokeRollbackWorkers(ctx, rollbackWorkers)
+		return err
+	}
+
+	for _, worker := range commitWorkers {
+		if err := worker(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Discard discards all changes made by any module in the
+// transaction and invokes all registered RollbackWorkers.
+// It is safe to call Discard after Commit (in which case
+// it does nothing), so it is common to defer Discard
+// after opening a *ModuleTransaction.
+func (m *ModuleTransaction) Discard(ctx context.Context) {
+	_, rollbackWorkers, ok := m.close()
+	if !ok {
+		return
+	}
+
+	m.Transaction.Discard(ctx)
+	invokeRollbackWorkers(ctx, rollbackWorkers)
+}
+
+// Rollback is an alias for Discard.
+func (m *ModuleTransaction) Rollback(ctx context.Context) {
+	m.Discard(ctx)
+}
+
+// invokeRollbackWorkers calls RollbackWorkers in the reverse
+// order they were registered.
+func invokeRollbackWorkers(ctx context.Context, workers []RollbackWorker) {
+	for i := len(workers) - 1; i >= 0; i-- {
+		workers[i](ctx)
+	}
+}
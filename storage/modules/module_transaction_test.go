@@ -0,0 +1,187 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func TestModuleTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	db, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer db.Close(ctx)
+
+	blockStorage := NewBlockStorage(db, blockWorkerConcurrency)
+	counterStorage := NewCounterStorage(db)
+	coinHelper := &mocks.CoinStorageHelper{}
+	coinStorage := NewCoinStorage(db, coinHelper, nil)
+	worker := &mocks.BlockWorker{}
+	blockStorage.Initialize([]BlockWorker{worker})
+
+	genesis := &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Hash:  "0",
+			Index: 0,
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "0",
+			Index: 0,
+		},
+	}
+	accountCoin := &types.AccountCoin{
+		Account: account,
+		Coin: &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{Identifier: "coin"},
+			Amount: &types.Amount{
+				Value:    "10",
+				Currency: currency,
+			},
+		},
+	}
+
+	t.Run("rollback", func(t *testing.T) {
+		assert.NoError(t, blockStorage.SeeBlock(ctx, genesis))
+
+		worker.On(
+			"AddingBlock",
+			mock.Anything,
+			mock.Anything,
+			genesis,
+			mock.Anything,
+		).Return(
+			database.CommitWorker(func(context.Context) error {
+				assert.Fail(t, "commit worker should not be called")
+				return nil
+			}),
+			nil,
+		).Once()
+
+		rollbacks := []string{}
+		txn := NewModuleTransaction(ctx, db)
+		txn.OnRollback(func(context.Context) { rollbacks = append(rollbacks, "first") })
+		txn.OnRollback(func(context.Context) { rollbacks = append(rollbacks, "second") })
+
+		assert.NoError(t, blockStorage.AddBlockTransactional(ctx, txn, genesis))
+		assert.NoError(t, coinStorage.AddCoinsTransactional(
+			ctx,
+			txn,
+			[]*types.AccountCoin{accountCoin},
+		))
+		_, err := counterStorage.UpdateTransactional(ctx, txn, BlockCounter, big.NewInt(1))
+		assert.NoError(t, err)
+		txn.Rollback(ctx)
+
+		// Discarding a closed transaction is a no-op.
+		txn.Discard(ctx)
+		assert.Equal(t, []string{"second", "first"}, rollbacks)
+		assert.True(t, errors.Is(txn.Commit(ctx), storageErrs.ErrTransactionClosed))
+
+		head, err := blockStorage.GetHeadBlockIdentifier(ctx)
+		assert.True(t, errors.Is(err, storageErrs.ErrHeadBlockNotFound))
+		assert.Nil(t, head)
+
+		coin, owner, err := coinStorage.GetCoin(ctx, accountCoin.Coin.CoinIdentifier)
+		assert.True(t, errors.Is(err, storageErrs.ErrCoinNotFound))
+		assert.Nil(t, coin)
+		assert.Nil(t, owner)
+
+		val, err := counterStorage.Get(ctx, BlockCounter)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(0), val)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		commits := []string{}
+		worker.On(
+			"AddingBlock",
+			mock.Anything,
+			mock.Anything,
+			genesis,
+			mock.Anything,
+		).Return(
+			database.CommitWorker(func(context.Context) error {
+				commits = append(commits, "worker")
+				return nil
+			}),
+			nil,
+		).Once()
+
+		txn := NewModuleTransaction(ctx, db)
+		defer txn.Discard(ctx)
+		txn.OnRollback(func(context.Context) {
+			assert.Fail(t, "rollback worker should not be called")
+		})
+
+		assert.NoError(t, blockStorage.AddBlockTransactional(ctx, txn, genesis))
+		txn.OnCommit(func(context.Context) error {
+			commits = append(commits, "caller")
+			return nil
+		})
+		assert.NoError(t, coinStorage.AddCoinsTransactional(
+			ctx,
+			txn,
+			[]*types.AccountCoin{accountCoin},
+		))
+		_, err := counterStorage.UpdateTransactional(ctx, txn, BlockCounter, big.NewInt(1))
+		assert.NoError(t, err)
+		assert.Empty(t, commits)
+
+		assert.NoError(t, txn.Commit(ctx))
+		assert.Equal(t, []string{"worker", "caller"}, commits)
+
+		head, err := blockStorage.GetHeadBlockIdentifier(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, genesis.BlockIdentifier, head)
+
+		coin, owner, err := coinStorage.GetCoin(ctx, accountCoin.Coin.CoinIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, accountCoin.Coin, coin)
+		assert.Equal(t, account, owner)
+
+		val, err := counterStorage.Get(ctx, BlockCounter)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(1), val)
+	})
+
+	t.Run("commit worker error", func(t *testing.T) {
+		txn := NewModuleTransaction(ctx, db)
+		defer txn.Discard(ctx)
+
+		txn.OnCommit(func(context.Context) error {
+			return errors.New("bad")
+		})
+		assert.EqualError(t, txn.Commit(ctx), "bad")
+	})
+
+	worker.AssertExpectations(t)
+}
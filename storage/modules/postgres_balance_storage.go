@@ -0,0 +1,249 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var _ PostgresBlockWorker = (*PostgresBalanceStorage)(nil)
+
+// PostgresBalanceStorage implements balance storage methods
+// on top of a relational PostgreSQL table (see
+// database.PostgresBalancesTable) that contains the current
+// balance of each account and currency.
+//
+// Unlike BalanceStorage, the balance of an account is not
+// fetched from the node when it is first seen. Accounts start
+// with a zero balance unless their balance is set with
+// SetBalance (ex: for genesis allocations).
+type PostgresBalanceStorage struct {
+	db     *sql.DB
+	parser *parser.Parser
+}
+
+// NewPostgresBalanceStorage returns a new PostgresBalanceStorage.
+// It should be provided to NewPostgresBlockStorage as a
+// PostgresBlockWorker.
+func NewPostgresBalanceStorage(
+	db *sql.DB,
+	parser *parser.Parser,
+) *PostgresBalanceStorage {
+	return &PostgresBalanceStorage{db: db, parser: parser}
+}
+
+// subAccountAddress returns the address of the
+// sub-account of account or nil if it is not set.
+func subAccountAddress(account *types.AccountIdentifier) interface{} {
+	if account.SubAccount == nil {
+		return nil
+	}
+
+	return account.SubAccount.Address
+}
+
+// AddingBlock is called by PostgresBlockStorage when adding a block.
+func (b *PostgresBalanceStorage) AddingBlock(
+	ctx context.Context,
+	tx *sql.Tx,
+	block *types.Block,
+) error {
+	changes, err := b.parser.BalanceChanges(ctx, block, false)
+	if err != nil {
+		return fmt.Errorf("%w: unable to calculate balance changes", err)
+	}
+
+	for _, change := range changes {
+		if err := b.updateBalance(
+			ctx,
+			tx,
+			change.Account,
+			change.Currency,
+			change.Difference,
+			block.BlockIdentifier,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemovingBlock is called by PostgresBlockStorage when removing
+// a block. The balances changed by the block are restored to
+// the balances at the block's parent.
+func (b *PostgresBalanceStorage) RemovingBlock(
+	ctx context.Context,
+	tx *sql.Tx,
+	block *types.Block,
+) error {
+	changes, err := b.parser.BalanceChanges(ctx, block, true)
+	if err != nil {
+		return fmt.Errorf("%w: unable to calculate balance changes", err)
+	}
+
+	for _, change := range changes {
+		if err := b.updateBalance(
+			ctx,
+			tx,
+			change.Account,
+			change.Currency,
+			change.Difference,
+			block.ParentBlockIdentifier,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateBalance adds difference to the balance of account
+// and currency (creating it if it does not exist). An error
+// is returned if the resulting balance is negative.
+func (b *PostgresBalanceStorage) updateBalance(
+	ctx context.Context,
+	tx *sql.Tx,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	difference string,
+	blockIdentifier *types.BlockIdentifier,
+) error {
+	if _, err := types.BigInt(difference); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInvalidChangeValue, err)
+	}
+
+	var value string
+	if err := tx.QueryRowContext(
+		ctx,
+		"INSERT INTO "+database.PostgresBalancesTable+
+			" (account_key, currency_key, address, sub_account_address, symbol, decimals, "+
+			"value, block_index, block_hash) VALUES ($1, $2, $3, $4, $5, $6, $7::NUMERIC, $8, $9) "+
+			"ON CONFLICT (account_key, currency_key) DO UPDATE SET "+
+			"value = "+database.PostgresBalancesTable+".value + EXCLUDED.value, "+
+			"block_index = EXCLUDED.block_index, block_hash = EXCLUDED.block_hash "+
+			"RETURNING value::TEXT",
+		types.Hash(account),
+		types.Hash(currency),
+		account.Address,
+		subAccountAddress(account),
+		currency.Symbol,
+		currency.Decimals,
+		difference,
+		blockIdentifier.Index,
+		blockIdentifier.Hash,
+	).Scan(&value); err != nil {
+		return fmt.Errorf("%w: unable to update balance: %v", err, types.PrintStruct(account))
+	}
+
+	newValue, err := types.BigInt(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInvalidValue, err)
+	}
+
+	if newValue.Sign() == -1 {
+		return fmt.Errorf(
+			"%w %s:%+v for %+v at %+v",
+			errors.ErrNegativeBalance,
+			newValue.String(),
+			currency,
+			account,
+			blockIdentifier,
+		)
+	}
+
+	return nil
+}
+
+// SetBalance overrides the balance of an account and
+// currency (ex: to set genesis allocations).
+func (b *PostgresBalanceStorage) SetBalance(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	amount *types.Amount,
+	blockIdentifier *types.BlockIdentifier,
+) error {
+	value, err := types.BigInt(amount.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInvalidValue, err)
+	}
+
+	if value.Sign() == -1 {
+		return fmt.Errorf("%w: %s", errors.ErrNegativeBalance, value.String())
+	}
+
+	if _, err := b.db.ExecContext(
+		ctx,
+		"INSERT INTO "+database.PostgresBalancesTable+
+			" (account_key, currency_key, address, sub_account_address, symbol, decimals, "+
+			"value, block_index, block_hash) VALUES ($1, $2, $3, $4, $5, $6, $7::NUMERIC, $8, $9) "+
+			"ON CONFLICT (account_key, currency_key) DO UPDATE SET "+
+			"value = EXCLUDED.value, block_index = EXCLUDED.block_index, "+
+			"block_hash = EXCLUDED.block_hash",
+		types.Hash(account),
+		types.Hash(amount.Currency),
+		account.Address,
+		subAccountAddress(account),
+		amount.Currency.Symbol,
+		amount.Currency.Decimals,
+		amount.Value,
+		blockIdentifier.Index,
+		blockIdentifier.Hash,
+	); err != nil {
+		return fmt.Errorf("%w: unable to set balance: %v", err, types.PrintStruct(account))
+	}
+
+	return nil
+}
+
+// GetBalance returns the current balance of an account and
+// currency and the block at which it was last updated.
+func (b *PostgresBalanceStorage) GetBalance(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+) (*types.Amount, *types.BlockIdentifier, error) {
+	var value string
+	blockIdentifier := &types.BlockIdentifier{}
+	err := b.db.QueryRowContext(
+		ctx,
+		"SELECT value::TEXT, block_index, block_hash FROM "+database.PostgresBalancesTable+
+			" WHERE account_key = $1 AND currency_key = $2",
+		types.Hash(account),
+		types.Hash(currency),
+	).Scan(&value, &blockIdentifier.Index, &blockIdentifier.Hash)
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf(
+			"%w: %s",
+			errors.ErrAccountMissing,
+			types.PrintStruct(account),
+		)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("%w: unable to get balance: %v", err, types.PrintStruct(account))
+	}
+
+	return &types.Amount{
+		Value:    value,
+		Currency: currency,
+	}, blockIdentifier, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestPostgresBalanceStorage(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	storage := NewPostgresBalanceStorage(db, parser.New(newTestPostgresAsserter(t), nil, nil))
+
+	t.Run("get missing balance", func(t *testing.T) {
+		mock.ExpectQuery("SELECT value::TEXT, block_index, block_hash FROM rosetta_balances").
+			WithArgs(types.Hash(account), types.Hash(currency)).
+			WillReturnRows(sqlmock.NewRows([]string{"value", "block_index", "block_hash"}))
+
+		amount, block, err := storage.GetBalance(ctx, account, currency)
+		assert.Nil(t, amount)
+		assert.Nil(t, block)
+		assert.True(t, errors.Is(err, storageErrs.ErrAccountMissing))
+	})
+
+	t.Run("set negative balance", func(t *testing.T) {
+		err := storage.SetBalance(
+			ctx,
+			account,
+			&types.Amount{Value: "-1", Currency: currency},
+			postgresBlock.BlockIdentifier,
+		)
+		assert.True(t, errors.Is(err, storageErrs.ErrNegativeBalance))
+	})
+
+	t.Run("set balance", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO rosetta_balances").
+			WithArgs(
+				types.Hash(account3),
+				types.Hash(currency),
+				"blah",
+				"extra account",
+				"sym",
+				12,
+				"100",
+				1,
+				"block 1",
+			).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		assert.NoError(t, storage.SetBalance(
+			ctx,
+			account3,
+			&types.Amount{Value: "100", Currency: currency},
+			postgresBlock.BlockIdentifier,
+		))
+	})
+
+	t.Run("get balance", func(t *testing.T) {
+		mock.ExpectQuery("SELECT value::TEXT, block_index, block_hash FROM rosetta_balances").
+			WithArgs(types.Hash(account3), types.Hash(currency)).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"value", "block_index", "block_hash"}).
+					AddRow("100", 1, "block 1"),
+			)
+
+		amount, block, err := storage.GetBalance(ctx, account3, currency)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Amount{Value: "100", Currency: currency}, amount)
+		assert.Equal(t, postgresBlock.BlockIdentifier, block)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
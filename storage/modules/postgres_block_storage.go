@@ -0,0 +1,322 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	postgresBlockColumns = "block_index, block_hash, parent_index, parent_hash, " +
+		"block_timestamp, metadata"
+)
+
+// PostgresBlockWorker is an interface that allows for work
+// to be done while a block is added to or removed from
+// PostgresBlockStorage. All work is done in the same
+// PostgreSQL transaction that stores (or removes) the
+// block, so a block is never partially applied.
+type PostgresBlockWorker interface {
+	AddingBlock(ctx context.Context, tx *sql.Tx, block *types.Block) error
+	RemovingBlock(ctx context.Context, tx *sql.Tx, block *types.Block) error
+}
+
+// postgresQueryer is implemented by both *sql.DB and *sql.Tx.
+type postgresQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// PostgresBlockStorage implements block storage methods
+// on top of relational PostgreSQL tables (see
+// database.PostgresBlocksTable), so blocks and
+// transactions can be queried directly with SQL.
+type PostgresBlockStorage struct {
+	db      *sql.DB
+	workers []PostgresBlockWorker
+}
+
+// NewPostgresBlockStorage returns a new PostgresBlockStorage.
+// db must be opened by the caller with a PostgreSQL driver.
+// Any database.PostgresMigrations that have not yet been
+// applied are applied before PostgresBlockStorage is
+// returned.
+func NewPostgresBlockStorage(
+	ctx context.Context,
+	db *sql.DB,
+	workers ...PostgresBlockWorker,
+) (*PostgresBlockStorage, error) {
+	if _, err := database.MigratePostgres(ctx, db); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrDatabaseOpenFailed, err)
+	}
+
+	return &PostgresBlockStorage{db: db, workers: workers}, nil
+}
+
+// AddBlock stores a block and its transactions and
+// calls all workers in a single PostgreSQL transaction.
+func (b *PostgresBlockStorage) AddBlock(ctx context.Context, block *types.Block) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBlockStoreFailed, err)
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	// metadata is NULL (not an empty value)
+	// when the block has no metadata.
+	var metadata interface{}
+	if block.Metadata != nil {
+		encoded, err := json.Marshal(block.Metadata)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrBlockEncodeFailed, err)
+		}
+
+		metadata = encoded
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO "+database.PostgresBlocksTable+" ("+postgresBlockColumns+") "+
+			"VALUES ($1, $2, $3, $4, $5, $6)",
+		block.BlockIdentifier.Index,
+		block.BlockIdentifier.Hash,
+		block.ParentBlockIdentifier.Index,
+		block.ParentBlockIdentifier.Hash,
+		block.Timestamp,
+		metadata,
+	); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBlockStoreFailed, err)
+	}
+
+	for i, transaction := range block.Transactions {
+		encoded, err := json.Marshal(transaction)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrTransactionDataEncodeFailed, err)
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO "+database.PostgresTransactionsTable+
+				" (block_index, transaction_index, transaction_hash, transaction_data) "+
+				"VALUES ($1, $2, $3, $4)",
+			block.BlockIdentifier.Index,
+			i,
+			transaction.TransactionIdentifier.Hash,
+			encoded,
+		); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrTransactionHashStoreFailed, err)
+		}
+	}
+
+	for _, worker := range b.workers {
+		if err := worker.AddingBlock(ctx, tx, block); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBlockStoreFailed, err)
+	}
+
+	return nil
+}
+
+// RemoveBlock removes a block and its transactions and
+// calls all workers in a single PostgreSQL transaction.
+func (b *PostgresBlockStorage) RemoveBlock(
+	ctx context.Context,
+	blockIdentifier *types.BlockIdentifier,
+) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBlockDeleteFailed, err)
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	block, err := b.getBlock(ctx, tx, types.ConstructPartialBlockIdentifier(blockIdentifier))
+	if err != nil {
+		return err
+	}
+
+	for _, worker := range b.workers {
+		if err := worker.RemovingBlock(ctx, tx, block); err != nil {
+			return err
+		}
+	}
+
+	// Transactions are removed by ON DELETE CASCADE.
+	if _, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM "+database.PostgresBlocksTable+" WHERE block_index = $1",
+		blockIdentifier.Index,
+	); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBlockDeleteFailed, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrBlockDeleteFailed, err)
+	}
+
+	return nil
+}
+
+// GetHeadBlockIdentifier returns the identifier
+// of the block with the largest index.
+func (b *PostgresBlockStorage) GetHeadBlockIdentifier(
+	ctx context.Context,
+) (*types.BlockIdentifier, error) {
+	blockIdentifier := &types.BlockIdentifier{}
+	err := b.db.QueryRowContext(
+		ctx,
+		"SELECT block_index, block_hash FROM "+database.PostgresBlocksTable+
+			" ORDER BY block_index DESC LIMIT 1",
+	).Scan(&blockIdentifier.Index, &blockIdentifier.Hash)
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.ErrHeadBlockNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrHeadBlockGetFailed, err)
+	}
+
+	return blockIdentifier, nil
+}
+
+// GetBlock returns the block matching blockIdentifier
+// (or the head block if blockIdentifier is nil or empty).
+func (b *PostgresBlockStorage) GetBlock(
+	ctx context.Context,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	return b.getBlock(ctx, b.db, blockIdentifier)
+}
+
+func (b *PostgresBlockStorage) getBlock(
+	ctx context.Context,
+	q postgresQueryer,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	query := "SELECT " + postgresBlockColumns + " FROM " + database.PostgresBlocksTable
+	args := []interface{}{}
+	switch {
+	case blockIdentifier == nil || (blockIdentifier.Index == nil && blockIdentifier.Hash == nil):
+		query += " ORDER BY block_index DESC LIMIT 1"
+	case blockIdentifier.Hash != nil:
+		query += " WHERE block_hash = $1"
+		args = append(args, *blockIdentifier.Hash)
+	default:
+		query += " WHERE block_index = $1"
+		args = append(args, *blockIdentifier.Index)
+	}
+
+	block := &types.Block{
+		BlockIdentifier:       &types.BlockIdentifier{},
+		ParentBlockIdentifier: &types.BlockIdentifier{},
+		Transactions:          []*types.Transaction{},
+	}
+	var metadata []byte
+	err := q.QueryRowContext(ctx, query, args...).Scan(
+		&block.BlockIdentifier.Index,
+		&block.BlockIdentifier.Hash,
+		&block.ParentBlockIdentifier.Index,
+		&block.ParentBlockIdentifier.Hash,
+		&block.Timestamp,
+		&metadata,
+	)
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrBlockNotFound, types.PrintStruct(blockIdentifier))
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrBlockGetFailed, err)
+	}
+
+	// A block that matches the hash but not the index
+	// of blockIdentifier does not match.
+	if blockIdentifier != nil && blockIdentifier.Index != nil &&
+		*blockIdentifier.Index != block.BlockIdentifier.Index {
+		return nil, fmt.Errorf("%w: %s", errors.ErrBlockNotFound, types.PrintStruct(blockIdentifier))
+	}
+
+	if metadata != nil {
+		if err := json.Unmarshal(metadata, &block.Metadata); err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrBlockDataDecodeFailed, err)
+		}
+	}
+
+	rows, err := q.QueryContext(
+		ctx,
+		"SELECT transaction_data FROM "+database.PostgresTransactionsTable+
+			" WHERE block_index = $1 ORDER BY transaction_index",
+		block.BlockIdentifier.Index,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrTransactionGetFailed, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var encoded []byte
+		if err := rows.Scan(&encoded); err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrTransactionGetFailed, err)
+		}
+
+		transaction := &types.Transaction{}
+		if err := json.Unmarshal(encoded, transaction); err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrBlockDataDecodeFailed, err)
+		}
+
+		block.Transactions = append(block.Transactions, transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrTransactionGetFailed, err)
+	}
+
+	return block, nil
+}
+
+// FindTransaction returns the most recent *types.BlockIdentifier
+// containing the transaction and the transaction.
+func (b *PostgresBlockStorage) FindTransaction(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*types.BlockIdentifier, *types.Transaction, error) {
+	blockIdentifier := &types.BlockIdentifier{}
+	var encoded []byte
+	err := b.db.QueryRowContext(
+		ctx,
+		"SELECT b.block_index, b.block_hash, t.transaction_data FROM "+
+			database.PostgresTransactionsTable+" t JOIN "+database.PostgresBlocksTable+
+			" b ON b.block_index = t.block_index WHERE t.transaction_hash = $1 "+
+			"ORDER BY b.block_index DESC LIMIT 1",
+		transactionIdentifier.Hash,
+	).Scan(&blockIdentifier.Index, &blockIdentifier.Hash, &encoded)
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errors.ErrTransactionDBQueryFailed, err)
+	}
+
+	transaction := &types.Transaction{}
+	if err := json.Unmarshal(encoded, transaction); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errors.ErrBlockDataDecodeFailed, err)
+	}
+
+	return blockIdentifier, transaction, nil
+}
@@ -0,0 +1,331 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	postgresBlock = &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  "block 0",
+			Index: 0,
+		},
+		Timestamp: 1000,
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx 1",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type:    "Transfer",
+						Account: account,
+						Status:  successStatus,
+						Amount: &types.Amount{
+							Value:    "10",
+							Currency: currency,
+						},
+						CoinChange: &types.CoinChange{
+							CoinAction: types.CoinCreated,
+							CoinIdentifier: &types.CoinIdentifier{
+								Identifier: "coin1",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+)
+
+// expectPostgresMigrations expects all database.PostgresMigrations
+// to be applied to a database at version.
+func expectPostgresMigrations(mock sqlmock.Sqlmock, version int) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS rosetta_schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0)")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+	for i := version; i < len(database.PostgresMigrations); i++ {
+		mock.ExpectExec(regexp.QuoteMeta(database.PostgresMigrations[i])).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO rosetta_schema_migrations").
+			WithArgs(i + 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func newTestPostgresAsserter(t *testing.T) *asserter.Asserter {
+	a, err := asserter.NewClientWithOptions(
+		&types.NetworkIdentifier{
+			Blockchain: "bitcoin",
+			Network:    "mainnet",
+		},
+		&types.BlockIdentifier{
+			Hash:  "block 0",
+			Index: 0,
+		},
+		[]string{"Transfer"},
+		[]*types.OperationStatus{
+			{
+				Status:     *successStatus,
+				Successful: true,
+			},
+		},
+		[]*types.Error{},
+		nil,
+		&asserter.Validations{
+			Enabled: false,
+		},
+	)
+	assert.NoError(t, err)
+
+	return a
+}
+
+func newTestPostgresBlockStorage(
+	ctx context.Context,
+	t *testing.T,
+) (*PostgresBlockStorage, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	a := newTestPostgresAsserter(t)
+	expectPostgresMigrations(mock, 1)
+	storage, err := NewPostgresBlockStorage(
+		ctx,
+		db,
+		NewPostgresBalanceStorage(db, parser.New(a, nil, nil)),
+		NewPostgresCoinStorage(db, a),
+	)
+	assert.NoError(t, err)
+
+	return storage, db, mock
+}
+
+func TestPostgresBlockStorage(t *testing.T) {
+	ctx := context.Background()
+	encodedTransaction, err := json.Marshal(postgresBlock.Transactions[0])
+	assert.NoError(t, err)
+	blockColumns := []string{
+		"block_index",
+		"block_hash",
+		"parent_index",
+		"parent_hash",
+		"block_timestamp",
+		"metadata",
+	}
+
+	t.Run("add block", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO rosetta_blocks").
+			WithArgs(1, "block 1", 0, "block 0", 1000, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO rosetta_transactions").
+			WithArgs(1, 0, "tx 1", encodedTransaction).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("INSERT INTO rosetta_balances").
+			WithArgs(
+				types.Hash(account),
+				types.Hash(currency),
+				"blah",
+				nil,
+				"sym",
+				12,
+				"10",
+				1,
+				"block 1",
+			).
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("10"))
+		mock.ExpectExec("INSERT INTO rosetta_coins").
+			WithArgs(
+				"coin1",
+				types.Hash(account),
+				"blah",
+				nil,
+				"sym",
+				12,
+				"10",
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+			).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, storage.AddBlock(ctx, postgresBlock))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("add block with negative balance", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO rosetta_blocks").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO rosetta_transactions").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("INSERT INTO rosetta_balances").
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("-5"))
+		mock.ExpectRollback()
+
+		err := storage.AddBlock(ctx, postgresBlock)
+		assert.True(t, errors.Is(err, storageErrs.ErrNegativeBalance))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("remove block", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT block_index, block_hash, .* WHERE block_hash = ").
+			WithArgs("block 1").
+			WillReturnRows(
+				sqlmock.NewRows(blockColumns).AddRow(1, "block 1", 0, "block 0", 1000, nil),
+			)
+		mock.ExpectQuery("SELECT transaction_data FROM rosetta_transactions").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_data"}).AddRow(encodedTransaction))
+		mock.ExpectQuery("INSERT INTO rosetta_balances").
+			WithArgs(
+				types.Hash(account),
+				types.Hash(currency),
+				"blah",
+				nil,
+				"sym",
+				12,
+				"-10",
+				0,
+				"block 0",
+			).
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("0"))
+		mock.ExpectExec("DELETE FROM rosetta_coins").
+			WithArgs("coin1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM rosetta_blocks").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, storage.RemoveBlock(ctx, postgresBlock.BlockIdentifier))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("remove missing block", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT block_index").
+			WithArgs("block 1").
+			WillReturnRows(sqlmock.NewRows(blockColumns))
+		mock.ExpectRollback()
+
+		err := storage.RemoveBlock(ctx, postgresBlock.BlockIdentifier)
+		assert.True(t, errors.Is(err, storageErrs.ErrBlockNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get block", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT block_index, block_hash, .* WHERE block_index = ").
+			WithArgs(1).
+			WillReturnRows(
+				sqlmock.NewRows(blockColumns).AddRow(1, "block 1", 0, "block 0", 1000, nil),
+			)
+		mock.ExpectQuery("SELECT transaction_data FROM rosetta_transactions").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_data"}).AddRow(encodedTransaction))
+
+		block, err := storage.GetBlock(ctx, &types.PartialBlockIdentifier{Index: types.Int64(1)})
+		assert.NoError(t, err)
+		assert.Equal(t, postgresBlock, block)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get head block identifier", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT block_index, block_hash FROM rosetta_blocks ORDER BY").
+			WillReturnRows(sqlmock.NewRows([]string{"block_index", "block_hash"}))
+		mock.ExpectQuery("SELECT block_index, block_hash FROM rosetta_blocks ORDER BY").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"block_index", "block_hash"}).AddRow(1, "block 1"),
+			)
+
+		head, err := storage.GetHeadBlockIdentifier(ctx)
+		assert.Nil(t, head)
+		assert.True(t, errors.Is(err, storageErrs.ErrHeadBlockNotFound))
+
+		head, err = storage.GetHeadBlockIdentifier(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, postgresBlock.BlockIdentifier, head)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("find transaction", func(t *testing.T) {
+		storage, db, mock := newTestPostgresBlockStorage(ctx, t)
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT b.block_index").
+			WithArgs("tx 1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"block_index", "block_hash", "transaction_data"}).
+					AddRow(1, "block 1", encodedTransaction),
+			)
+
+		blockIdentifier, transaction, err := storage.FindTransaction(
+			ctx,
+			&types.TransactionIdentifier{Hash: "tx 1"},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, postgresBlock.BlockIdentifier, blockIdentifier)
+		assert.Equal(t, postgresBlock.Transactions[0], transaction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var _ PostgresBlockWorker = (*PostgresCoinStorage)(nil)
+
+// PostgresCoinStorage implements storage methods for storing
+// UTXOs on top of a relational PostgreSQL table (see
+// database.PostgresCoinsTable) that contains each
+// unspent coin.
+type PostgresCoinStorage struct {
+	db       *sql.DB
+	asserter *asserter.Asserter
+}
+
+// NewPostgresCoinStorage returns a new PostgresCoinStorage.
+// It should be provided to NewPostgresBlockStorage as a
+// PostgresBlockWorker.
+func NewPostgresCoinStorage(
+	db *sql.DB,
+	asserter *asserter.Asserter,
+) *PostgresCoinStorage {
+	return &PostgresCoinStorage{db: db, asserter: asserter}
+}
+
+// AddingBlock is called by PostgresBlockStorage when adding a block.
+func (c *PostgresCoinStorage) AddingBlock(
+	ctx context.Context,
+	tx *sql.Tx,
+	block *types.Block,
+) error {
+	return c.updateCoins(ctx, tx, block, false)
+}
+
+// RemovingBlock is called by PostgresBlockStorage when removing a block.
+func (c *PostgresCoinStorage) RemovingBlock(
+	ctx context.Context,
+	tx *sql.Tx,
+	block *types.Block,
+) error {
+	return c.updateCoins(ctx, tx, block, true)
+}
+
+func (c *PostgresCoinStorage) updateCoins(
+	ctx context.Context,
+	tx *sql.Tx,
+	block *types.Block,
+	blockRemoved bool,
+) error {
+	changes, err := parser.New(c.asserter, nil, nil).CoinChanges(block, blockRemoved)
+	if err != nil {
+		if goerrors.Is(err, parser.ErrCoinChangesDuplicateCoin) {
+			return fmt.Errorf("%w: %v", errors.ErrDuplicateCoinFound, err)
+		}
+
+		return fmt.Errorf("%w: %v", errors.ErrUnableToDetermineIfSkipOperation, err)
+	}
+
+	for _, accountCoin := range changes.Added {
+		if err := c.addCoin(ctx, tx, accountCoin.Account, accountCoin.Coin); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrCoinAddFailed, err)
+		}
+	}
+
+	for _, accountCoin := range changes.Removed {
+		if err := c.removeCoin(ctx, tx, accountCoin.Coin.CoinIdentifier); err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrCoinRemoveFailed, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *PostgresCoinStorage) addCoin(
+	ctx context.Context,
+	tx *sql.Tx,
+	account *types.AccountIdentifier,
+	coin *types.Coin,
+) error {
+	if _, err := types.BigInt(coin.Amount.Value); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinParseFailed, err)
+	}
+
+	encodedAccount, err := json.Marshal(account)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinDataEncodeFailed, err)
+	}
+
+	encodedCoin, err := json.Marshal(coin)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinDataEncodeFailed, err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO "+database.PostgresCoinsTable+
+			" (coin_identifier, account_key, address, sub_account_address, symbol, decimals, "+
+			"value, account, coin) VALUES ($1, $2, $3, $4, $5, $6, $7::NUMERIC, $8, $9)",
+		coin.CoinIdentifier.Identifier,
+		types.Hash(account),
+		account.Address,
+		subAccountAddress(account),
+		coin.Amount.Currency.Symbol,
+		coin.Amount.Currency.Decimals,
+		coin.Amount.Value,
+		encodedAccount,
+		encodedCoin,
+	); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinStoreFailed, err)
+	}
+
+	return nil
+}
+
+func (c *PostgresCoinStorage) removeCoin(
+	ctx context.Context,
+	tx *sql.Tx,
+	coinIdentifier *types.CoinIdentifier,
+) error {
+	result, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM "+database.PostgresCoinsTable+" WHERE coin_identifier = $1",
+		coinIdentifier.Identifier,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinDeleteFailed, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrCoinDeleteFailed, err)
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("%w: %s", errors.ErrCoinNotFound, coinIdentifier.Identifier)
+	}
+
+	return nil
+}
+
+// GetCoins returns all unspent coins of an account.
+func (c *PostgresCoinStorage) GetCoins(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+) ([]*types.Coin, error) {
+	rows, err := c.db.QueryContext(
+		ctx,
+		"SELECT coin FROM "+database.PostgresCoinsTable+
+			" WHERE account_key = $1 ORDER BY coin_identifier",
+		types.Hash(account),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrAccountCoinQueryFailed, err)
+	}
+	defer rows.Close()
+
+	coins := []*types.Coin{}
+	for rows.Next() {
+		var encoded []byte
+		if err := rows.Scan(&encoded); err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrAccountCoinQueryFailed, err)
+		}
+
+		coin := &types.Coin{}
+		if err := json.Unmarshal(encoded, coin); err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrCoinDecodeFailed, err)
+		}
+
+		coins = append(coins, coin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrAccountCoinQueryFailed, err)
+	}
+
+	return coins, nil
+}
+
+// GetCoin returns an unspent coin and
+// the account that owns it.
+func (c *PostgresCoinStorage) GetCoin(
+	ctx context.Context,
+	coinIdentifier *types.CoinIdentifier,
+) (*types.Coin, *types.AccountIdentifier, error) {
+	var encodedCoin, encodedAccount []byte
+	err := c.db.QueryRowContext(
+		ctx,
+		"SELECT coin, account FROM "+database.PostgresCoinsTable+" WHERE coin_identifier = $1",
+		coinIdentifier.Identifier,
+	).Scan(&encodedCoin, &encodedAccount)
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%w: %s", errors.ErrCoinNotFound, coinIdentifier.Identifier)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinQueryFailed, err)
+	}
+
+	coin := &types.Coin{}
+	if err := json.Unmarshal(encodedCoin, coin); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinDecodeFailed, err)
+	}
+
+	account := &types.AccountIdentifier{}
+	if err := json.Unmarshal(encodedAccount, account); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errors.ErrCoinDecodeFailed, err)
+	}
+
+	return coin, account, nil
+}
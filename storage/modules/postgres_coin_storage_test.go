@@ -0,0 +1,117 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestPostgresCoinStorage(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	storage := NewPostgresCoinStorage(db, newTestPostgresAsserter(t))
+	coin := &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{Identifier: "coin1"},
+		Amount:         &types.Amount{Value: "10", Currency: currency},
+	}
+	encodedCoin, err := json.Marshal(coin)
+	assert.NoError(t, err)
+	encodedAccount, err := json.Marshal(account)
+	assert.NoError(t, err)
+
+	t.Run("spend missing coin", func(t *testing.T) {
+		spendBlock := &types.Block{
+			BlockIdentifier:       &types.BlockIdentifier{Hash: "block 2", Index: 2},
+			ParentBlockIdentifier: postgresBlock.BlockIdentifier,
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+					Operations: []*types.Operation{
+						{
+							OperationIdentifier: &types.OperationIdentifier{Index: 0},
+							Type:                "Transfer",
+							Account:             account,
+							Status:              successStatus,
+							Amount:              &types.Amount{Value: "-10", Currency: currency},
+							CoinChange: &types.CoinChange{
+								CoinAction:     types.CoinSpent,
+								CoinIdentifier: &types.CoinIdentifier{Identifier: "coin2"},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM rosetta_coins").
+			WithArgs("coin2").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		err = storage.AddingBlock(ctx, tx, spendBlock)
+		assert.True(t, errors.Is(err, storageErrs.ErrCoinRemoveFailed))
+		assert.Contains(t, err.Error(), storageErrs.ErrCoinNotFound.Error())
+		assert.NoError(t, tx.Rollback())
+	})
+
+	t.Run("get coins", func(t *testing.T) {
+		mock.ExpectQuery("SELECT coin FROM rosetta_coins").
+			WithArgs(types.Hash(account)).
+			WillReturnRows(sqlmock.NewRows([]string{"coin"}).AddRow(encodedCoin))
+
+		coins, err := storage.GetCoins(ctx, account)
+		assert.NoError(t, err)
+		assert.Equal(t, []*types.Coin{coin}, coins)
+	})
+
+	t.Run("get coin", func(t *testing.T) {
+		mock.ExpectQuery("SELECT coin, account FROM rosetta_coins").
+			WithArgs("coin1").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"coin", "account"}).AddRow(encodedCoin, encodedAccount),
+			)
+
+		fetchedCoin, fetchedAccount, err := storage.GetCoin(ctx, coin.CoinIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, coin, fetchedCoin)
+		assert.Equal(t, account, fetchedAccount)
+	})
+
+	t.Run("get missing coin", func(t *testing.T) {
+		mock.ExpectQuery("SELECT coin, account FROM rosetta_coins").
+			WithArgs("coin2").
+			WillReturnRows(sqlmock.NewRows([]string{"coin", "account"}))
+
+		_, _, err := storage.GetCoin(ctx, &types.CoinIdentifier{Identifier: "coin2"})
+		assert.True(t, errors.Is(err, storageErrs.ErrCoinNotFound))
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/reconciler"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// reconcilerQueueNamespace is prepended to any
+	// persisted reconciler queue entry.
+	reconcilerQueueNamespace = "recon-queue"
+
+	reconcilerQueueActiveNamespace   = "active"
+	reconcilerQueueInactiveNamespace = "inactive"
+)
+
+var _ reconciler.Queue = (*ReconcilerQueueStorage)(nil)
+
+func getReconcilerQueueActivePrefix() []byte {
+	return []byte(fmt.Sprintf("%s/%s/", reconcilerQueueNamespace, reconcilerQueueActiveNamespace))
+}
+
+func getReconcilerQueueActiveKey(change *parser.BalanceChange) []byte {
+	return []byte(fmt.Sprintf(
+		"%s/%s/%s/%020d",
+		reconcilerQueueNamespace,
+		reconcilerQueueActiveNamespace,
+		types.Hash(&types.AccountCurrency{
+			Account:  change.Account,
+			Currency: change.Currency,
+		}),
+		change.Block.Index,
+	))
+}
+
+func getReconcilerQueueInactivePrefix() []byte {
+	return []byte(fmt.Sprintf("%s/%s/", reconcilerQueueNamespace, reconcilerQueueInactiveNamespace))
+}
+
+func getReconcilerQueueInactiveKey(accountCurrency *types.AccountCurrency) []byte {
+	return []byte(fmt.Sprintf(
+		"%s/%s/%s",
+		reconcilerQueueNamespace,
+		reconcilerQueueInactiveNamespace,
+		types.Hash(accountCurrency),
+	))
+}
+
+// ReconcilerQueueStorage implements reconciler.Queue
+// to persist the work enqueued by a *reconciler.Reconciler
+// in a database.Database, so that it can be recovered
+// after a crash or restart.
+type ReconcilerQueueStorage struct {
+	db database.Database
+}
+
+// NewReconcilerQueueStorage returns a new
+// *ReconcilerQueueStorage.
+func NewReconcilerQueueStorage(db database.Database) *ReconcilerQueueStorage {
+	return &ReconcilerQueueStorage{db: db}
+}
+
+// LoadQueue returns all persisted changes (ordered by
+// block index) and inactive entries.
+func (r *ReconcilerQueueStorage) LoadQueue(
+	ctx context.Context,
+) ([]*parser.BalanceChange, []*reconciler.InactiveEntry, error) {
+	dbTx := r.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	changes := []*parser.BalanceChange{}
+	_, err := dbTx.Scan(
+		ctx,
+		getReconcilerQueueActivePrefix(),
+		getReconcilerQueueActivePrefix(),
+		func(k []byte, v []byte) error {
+			var change parser.BalanceChange
+			if err := r.db.Encoder().Decode("", v, &change, false); err != nil {
+				return err
+			}
+
+			changes = append(changes, &change)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueLoadFailed, err)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Block.Index < changes[j].Block.Index
+	})
+
+	entries := []*reconciler.InactiveEntry{}
+	_, err = dbTx.Scan(
+		ctx,
+		getReconcilerQueueInactivePrefix(),
+		getReconcilerQueueInactivePrefix(),
+		func(k []byte, v []byte) error {
+			var entry reconciler.InactiveEntry
+			if err := r.db.Encoder().Decode("", v, &entry, false); err != nil {
+				return err
+			}
+
+			entries = append(entries, &entry)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueLoadFailed, err)
+	}
+
+	return changes, entries, nil
+}
+
+// AddChanges persists changes enqueued
+// for active reconciliation.
+func (r *ReconcilerQueueStorage) AddChanges(
+	ctx context.Context,
+	changes []*parser.BalanceChange,
+) error {
+	dbTx := r.db.WriteTransaction(ctx, reconcilerQueueNamespace, false)
+	defer dbTx.Discard(ctx)
+
+	for _, change := range changes {
+		encoded, err := r.db.Encoder().Encode("", change)
+		if err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+		}
+
+		if err := dbTx.Set(ctx, getReconcilerQueueActiveKey(change), encoded, true); err != nil {
+			return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// RemoveChange removes a change that has been
+// actively reconciled (or skipped).
+func (r *ReconcilerQueueStorage) RemoveChange(
+	ctx context.Context,
+	change *parser.BalanceChange,
+) error {
+	dbTx := r.db.WriteTransaction(ctx, reconcilerQueueNamespace, false)
+	defer dbTx.Discard(ctx)
+
+	if err := dbTx.Delete(ctx, getReconcilerQueueActiveKey(change)); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// SetInactiveEntry persists the last time an account
+// in the inactive queue was reconciled.
+func (r *ReconcilerQueueStorage) SetInactiveEntry(
+	ctx context.Context,
+	entry *reconciler.InactiveEntry,
+) error {
+	encoded, err := r.db.Encoder().Encode("", entry)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+	}
+
+	dbTx := r.db.WriteTransaction(ctx, reconcilerQueueNamespace, false)
+	defer dbTx.Discard(ctx)
+
+	if err := dbTx.Set(ctx, getReconcilerQueueInactiveKey(entry.Entry), encoded, true); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconcilerQueueUpdateFailed, err)
+	}
+
+	return nil
+}
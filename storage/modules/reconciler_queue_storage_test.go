@@ -0,0 +1,115 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/reconciler"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func TestReconcilerQueueStorage(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	q := NewReconcilerQueueStorage(database)
+
+	account1 := &types.AccountIdentifier{Address: "addr1"}
+	account2 := &types.AccountIdentifier{Address: "addr2"}
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	change1 := &parser.BalanceChange{
+		Account:    account1,
+		Currency:   currency,
+		Block:      &types.BlockIdentifier{Index: 10, Hash: "10"},
+		Difference: "100",
+	}
+	change2 := &parser.BalanceChange{
+		Account:    account2,
+		Currency:   currency,
+		Block:      &types.BlockIdentifier{Index: 2, Hash: "2"},
+		Difference: "-5",
+	}
+	change3 := &parser.BalanceChange{
+		Account:    account1,
+		Currency:   currency,
+		Block:      &types.BlockIdentifier{Index: 11, Hash: "11"},
+		Difference: "1",
+	}
+
+	t.Run("empty queue", func(t *testing.T) {
+		changes, entries, err := q.LoadQueue(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 0)
+		assert.Len(t, entries, 0)
+	})
+
+	t.Run("add changes and entries", func(t *testing.T) {
+		assert.NoError(t, q.AddChanges(ctx, []*parser.BalanceChange{change1, change2}))
+		assert.NoError(t, q.AddChanges(ctx, []*parser.BalanceChange{change3}))
+		assert.NoError(t, q.SetInactiveEntry(ctx, &reconciler.InactiveEntry{
+			Entry: &types.AccountCurrency{Account: account1, Currency: currency},
+		}))
+		assert.NoError(t, q.SetInactiveEntry(ctx, &reconciler.InactiveEntry{
+			Entry:     &types.AccountCurrency{Account: account2, Currency: currency},
+			LastCheck: change2.Block,
+		}))
+
+		changes, entries, err := q.LoadQueue(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*parser.BalanceChange{change2, change1, change3}, changes)
+		assert.ElementsMatch(t, []*reconciler.InactiveEntry{
+			{Entry: &types.AccountCurrency{Account: account1, Currency: currency}},
+			{
+				Entry:     &types.AccountCurrency{Account: account2, Currency: currency},
+				LastCheck: change2.Block,
+			},
+		}, entries)
+	})
+
+	t.Run("remove change and update entry", func(t *testing.T) {
+		assert.NoError(t, q.RemoveChange(ctx, change1))
+		assert.NoError(t, q.SetInactiveEntry(ctx, &reconciler.InactiveEntry{
+			Entry:     &types.AccountCurrency{Account: account1, Currency: currency},
+			LastCheck: change3.Block,
+		}))
+
+		changes, entries, err := q.LoadQueue(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*parser.BalanceChange{change2, change3}, changes)
+		assert.ElementsMatch(t, []*reconciler.InactiveEntry{
+			{
+				Entry:     &types.AccountCurrency{Account: account1, Currency: currency},
+				LastCheck: change3.Block,
+			},
+			{
+				Entry:     &types.AccountCurrency{Account: account2, Currency: currency},
+				LastCheck: change2.Block,
+			},
+		}, entries)
+	})
+}
@@ -0,0 +1,899 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// reconciliationNamespace is prepended to any stored
+	// reconciliation record, index, or summary.
+	reconciliationNamespace = "recon"
+
+	reconciliationRecordNamespace  = "record"
+	reconciliationAccountNamespace = "acct"
+	reconciliationSummaryNamespace = "summary"
+	reconciliationSequenceKey      = "sequence"
+
+	// DefaultReconciliationHistoryLimit is the number of
+	// records returned by a history query when no limit
+	// is provided.
+	DefaultReconciliationHistoryLimit = 100
+
+	// MaxReconciliationHistoryLimit is the maximum number
+	// of records that can be returned by a single history
+	// query.
+	MaxReconciliationHistoryLimit = 10000
+
+	// DefaultReconciliationBatchSize is the number of records
+	// written in a single database transaction.
+	DefaultReconciliationBatchSize = 100
+
+	// DefaultReconciliationRetention is the number of most
+	// recent records kept by ReconciliationStorage.
+	DefaultReconciliationRetention = 1000000
+)
+
+var errHistoryDone = errors.New("history done")
+
+// ReconciliationResult is the outcome of a
+// reconciliation attempt.
+type ReconciliationResult string
+
+const (
+	// ReconciliationSuccess is recorded when the computed
+	// balance matched the live balance.
+	ReconciliationSuccess ReconciliationResult = "SUCCESS"
+
+	// ReconciliationFailure is recorded when the computed
+	// balance did not match the live balance.
+	ReconciliationFailure ReconciliationResult = "FAILURE"
+
+	// ReconciliationExempt is recorded when the computed
+	// balance did not match the live balance but the
+	// difference was covered by a *types.BalanceExemption.
+	ReconciliationExempt ReconciliationResult = "EXEMPT"
+
+	// ReconciliationSkipped is recorded when a reconciliation
+	// could not be attempted (ex: the block was orphaned).
+	ReconciliationSkipped ReconciliationResult = "SKIPPED"
+)
+
+// ReconciliationRecord is a single reconciliation
+// attempt stored by ReconciliationStorage.
+type ReconciliationRecord struct {
+	// Sequence is assigned by ReconciliationStorage
+	// when the record is stored and increases with
+	// each stored record.
+	Sequence int64 `json:"sequence"`
+
+	// Timestamp is the time (in milliseconds) the
+	// record was stored.
+	Timestamp int64 `json:"timestamp"`
+
+	// Type is the type of reconciliation (ex: ACTIVE
+	// or INACTIVE).
+	Type string `json:"type,omitempty"`
+
+	AccountCurrency *types.AccountCurrency `json:"account_currency"`
+	Result          ReconciliationResult   `json:"result"`
+
+	// Block, ComputedValue, and LiveValue are not
+	// populated for skipped reconciliations.
+	Block         *types.BlockIdentifier `json:"block,omitempty"`
+	ComputedValue string                 `json:"computed_value,omitempty"`
+	LiveValue     string                 `json:"live_value,omitempty"`
+
+	// Cause is populated for skipped reconciliations.
+	Cause string `json:"cause,omitempty"`
+
+	// Exemption is populated for exempt reconciliations.
+	Exemption *types.BalanceExemption `json:"exemption,omitempty"`
+}
+
+// ReconciliationQuery restricts the records returned
+// by ReconciliationStorage.History. All fields are
+// optional.
+type ReconciliationQuery struct {
+	AccountCurrency *types.AccountCurrency
+	Result          ReconciliationResult
+
+	// MinBlockIndex and MaxBlockIndex are inclusive. When
+	// either is provided, records without a block (skipped
+	// reconciliations) are not returned.
+	MinBlockIndex *int64
+	MaxBlockIndex *int64
+
+	// Limit defaults to DefaultReconciliationHistoryLimit.
+	Limit int
+}
+
+// ReconciliationStats are aggregated over all
+// reconciliation records in ReconciliationStorage.
+type ReconciliationStats struct {
+	Attempts   int64 `json:"attempts"`
+	Successes  int64 `json:"successes"`
+	Failures   int64 `json:"failures"`
+	Exemptions int64 `json:"exemptions"`
+	Skipped    int64 `json:"skipped"`
+
+	// Accounts is the number of *types.AccountCurrency with
+	// at least one reconciliation attempt.
+	Accounts int64 `json:"accounts"`
+
+	// ReconciledAccounts is the number of Accounts that were
+	// last reconciled (successfully or exempt) at or after
+	// the minimum index provided to Stats.
+	ReconciledAccounts int64 `json:"reconciled_accounts"`
+
+	// FailingAccounts is the number of Accounts whose
+	// last (non-skipped) reconciliation failed.
+	FailingAccounts int64 `json:"failing_accounts"`
+
+	// Coverage is ReconciledAccounts / Accounts.
+	Coverage float64 `json:"coverage"`
+
+	// LastReconciledIndex is the largest block index
+	// reconciled successfully (or exempt). It is -1 if
+	// no reconciliation has succeeded.
+	LastReconciledIndex int64 `json:"last_reconciled_index"`
+}
+
+// reconciliationSummary is stored for each
+// *types.AccountCurrency to compute ReconciliationStats
+// without scanning all records.
+type reconciliationSummary struct {
+	AccountCurrency *types.AccountCurrency `json:"account_currency"`
+	Attempts        int64                  `json:"attempts"`
+	Successes       int64                  `json:"successes"`
+	Failures        int64                  `json:"failures"`
+	Exemptions      int64                  `json:"exemptions"`
+	Skipped         int64                  `json:"skipped"`
+
+	// LastResult is the result of the last
+	// non-skipped reconciliation.
+	LastResult ReconciliationResult `json:"last_result,omitempty"`
+
+	// LastReconciled is the last block reconciled
+	// successfully (or exempt).
+	LastReconciled *types.BlockIdentifier `json:"last_reconciled,omitempty"`
+}
+
+func getReconciliationRecordPrefix() []byte {
+	return []byte(fmt.Sprintf("%s/%s/", reconciliationNamespace, reconciliationRecordNamespace))
+}
+
+func getReconciliationRecordKey(sequence int64) []byte {
+	return append(getReconciliationRecordPrefix(), []byte(fmt.Sprintf("%020d", sequence))...)
+}
+
+func getReconciliationAccountPrefix(accountCurrency *types.AccountCurrency) []byte {
+	return []byte(fmt.Sprintf(
+		"%s/%s/%s/",
+		reconciliationNamespace,
+		reconciliationAccountNamespace,
+		types.Hash(accountCurrency),
+	))
+}
+
+func getReconciliationAccountKey(
+	accountCurrency *types.AccountCurrency,
+	sequence int64,
+) []byte {
+	return append(
+		getReconciliationAccountPrefix(accountCurrency),
+		[]byte(fmt.Sprintf("%020d", sequence))...,
+	)
+}
+
+func getReconciliationSummaryPrefix() []byte {
+	return []byte(fmt.Sprintf("%s/%s/", reconciliationNamespace, reconciliationSummaryNamespace))
+}
+
+func getReconciliationSummaryKey(accountCurrency *types.AccountCurrency) []byte {
+	return append(getReconciliationSummaryPrefix(), []byte(types.Hash(accountCurrency))...)
+}
+
+func getReconciliationSequenceKey() []byte {
+	return []byte(fmt.Sprintf("%s/%s", reconciliationNamespace, reconciliationSequenceKey))
+}
+
+// ReconciliationStorage records every reconciliation
+// attempt so that the reconciler can persist its progress
+// and operators can audit past runs.
+//
+// Records are written in batches (see
+// WithReconciliationBatchSize), so Flush should be called
+// before exiting to write any pending records. Only the
+// most recent records are kept (see
+// WithReconciliationRetention) but ReconciliationStats
+// are aggregated over all records ever stored.
+//
+// ReconciliationStorage implements the reconciler.Handler
+// interface and can be provided directly to the reconciler
+// (or called from another reconciler.Handler).
+type ReconciliationStorage struct {
+	db        database.Database
+	batchSize int
+	retention int64
+
+	// pendingLock protects pending, the records
+	// that have not yet been written.
+	pendingLock sync.Mutex
+	pending     []*ReconciliationRecord
+
+	// flushLock serializes all writes because each
+	// stored record increments the same sequence.
+	flushLock sync.Mutex
+}
+
+// ReconciliationStorageOption is used to overwrite default
+// values in ReconciliationStorage construction. Any Option
+// not provided falls back to the default value.
+type ReconciliationStorageOption func(r *ReconciliationStorage)
+
+// WithReconciliationBatchSize overrides the
+// DefaultReconciliationBatchSize. If size is 1,
+// each record is written when it is stored.
+func WithReconciliationBatchSize(size int) ReconciliationStorageOption {
+	return func(r *ReconciliationStorage) {
+		r.batchSize = size
+	}
+}
+
+// WithReconciliationRetention overrides the
+// DefaultReconciliationRetention. If records is 0,
+// all records are kept.
+func WithReconciliationRetention(records int64) ReconciliationStorageOption {
+	return func(r *ReconciliationStorage) {
+		r.retention = records
+	}
+}
+
+// NewReconciliationStorage returns a new ReconciliationStorage.
+func NewReconciliationStorage(
+	db database.Database,
+	options ...ReconciliationStorageOption,
+) *ReconciliationStorage {
+	r := &ReconciliationStorage{
+		db:        db,
+		batchSize: DefaultReconciliationBatchSize,
+		retention: DefaultReconciliationRetention,
+	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
+}
+
+func validateReconciliationRecord(record *ReconciliationRecord) error {
+	if record == nil || record.AccountCurrency == nil ||
+		record.AccountCurrency.Account == nil || record.AccountCurrency.Currency == nil {
+		return fmt.Errorf(
+			"%w: account currency is missing",
+			storageErrs.ErrReconciliationRecordInvalid,
+		)
+	}
+
+	switch record.Result {
+	case ReconciliationSuccess, ReconciliationFailure, ReconciliationExempt:
+		if record.Block == nil {
+			return fmt.Errorf(
+				"%w: block is missing for %s reconciliation",
+				storageErrs.ErrReconciliationRecordInvalid,
+				record.Result,
+			)
+		}
+	case ReconciliationSkipped:
+	default:
+		return fmt.Errorf(
+			"%w: result %s is not supported",
+			storageErrs.ErrReconciliationRecordInvalid,
+			record.Result,
+		)
+	}
+
+	return nil
+}
+
+func (r *ReconciliationStorage) getSummary(
+	ctx context.Context,
+	dbTx database.Transaction,
+	accountCurrency *types.AccountCurrency,
+) (*reconciliationSummary, error) {
+	exists, v, err := dbTx.Get(ctx, getReconciliationSummaryKey(accountCurrency))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return &reconciliationSummary{AccountCurrency: accountCurrency}, nil
+	}
+
+	var summary reconciliationSummary
+	if err := r.db.Encoder().Decode(reconciliationNamespace, v, &summary, true); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// reserveSequences returns the first of count
+// consecutive sequences.
+func (r *ReconciliationStorage) reserveSequences(
+	ctx context.Context,
+	dbTx database.Transaction,
+	count int,
+) (int64, error) {
+	k := getReconciliationSequenceKey()
+	exists, v, err := dbTx.Get(ctx, k)
+	if err != nil {
+		return -1, err
+	}
+
+	var sequence int64
+	if exists {
+		if err := r.db.Encoder().Decode("", v, &sequence, true); err != nil {
+			return -1, err
+		}
+	}
+
+	encoded, err := r.db.Encoder().Encode("", sequence+int64(count))
+	if err != nil {
+		return -1, err
+	}
+
+	if err := dbTx.Set(ctx, k, encoded, true); err != nil {
+		return -1, err
+	}
+
+	return sequence, nil
+}
+
+func (r *ReconciliationStorage) store(
+	ctx context.Context,
+	dbTx database.Transaction,
+	record *ReconciliationRecord,
+	sequence int64,
+) error {
+	record.Sequence = sequence
+	encoded, err := r.db.Encoder().Encode(reconciliationNamespace, record)
+	if err != nil {
+		return err
+	}
+
+	if err := dbTx.Set(ctx, getReconciliationRecordKey(sequence), encoded, true); err != nil {
+		return err
+	}
+
+	// The account index only references the record key, so
+	// no value is stored.
+	accountKey := getReconciliationAccountKey(record.AccountCurrency, sequence)
+	if err := dbTx.Set(ctx, accountKey, []byte{}, false); err != nil {
+		return err
+	}
+
+	summary, err := r.getSummary(ctx, dbTx, record.AccountCurrency)
+	if err != nil {
+		return err
+	}
+
+	summary.Attempts++
+	switch record.Result {
+	case ReconciliationSuccess:
+		summary.Successes++
+	case ReconciliationFailure:
+		summary.Failures++
+	case ReconciliationExempt:
+		summary.Exemptions++
+	case ReconciliationSkipped:
+		summary.Skipped++
+	}
+
+	if record.Result != ReconciliationSkipped {
+		summary.LastResult = record.Result
+	}
+
+	if record.Result == ReconciliationSuccess || record.Result == ReconciliationExempt {
+		if summary.LastReconciled == nil || record.Block.Index >= summary.LastReconciled.Index {
+			summary.LastReconciled = record.Block
+		}
+	}
+
+	encodedSummary, err := r.db.Encoder().Encode(reconciliationNamespace, summary)
+	if err != nil {
+		return err
+	}
+
+	return dbTx.Set(ctx, getReconciliationSummaryKey(record.AccountCurrency), encodedSummary, true)
+}
+
+// prune deletes all records (and their account index
+// entries) with a sequence less than end.
+func (r *ReconciliationStorage) prune(
+	ctx context.Context,
+	dbTx database.Transaction,
+	end int64,
+) error {
+	records := []*ReconciliationRecord{}
+	_, err := dbTx.Scan(
+		ctx,
+		getReconciliationRecordPrefix(),
+		getReconciliationRecordPrefix(),
+		func(k []byte, v []byte) error {
+			var record ReconciliationRecord
+			if err := r.db.Encoder().Decode(reconciliationNamespace, v, &record, true); err != nil {
+				return err
+			}
+
+			if record.Sequence >= end {
+				return errHistoryDone
+			}
+
+			records = append(records, &record)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil && !errors.Is(err, errHistoryDone) {
+		return err
+	}
+
+	for _, record := range records {
+		if err := dbTx.Delete(ctx, getReconciliationRecordKey(record.Sequence)); err != nil {
+			return err
+		}
+
+		accountKey := getReconciliationAccountKey(record.AccountCurrency, record.Sequence)
+		if err := dbTx.Delete(ctx, accountKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Store queues a reconciliation record to be written with the
+// next batch (see WithReconciliationBatchSize) and written
+// immediately if the batch is full. The Timestamp of the record
+// is populated by Store (if it is not already set) and the
+// Sequence is assigned when the record is written.
+func (r *ReconciliationStorage) Store(ctx context.Context, record *ReconciliationRecord) error {
+	if err := validateReconciliationRecord(record); err != nil {
+		return err
+	}
+
+	if record.Timestamp == 0 {
+		record.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	// We store a copy so the caller can't observe the
+	// Sequence being populated by a concurrent Flush.
+	pending := *record
+	r.pendingLock.Lock()
+	r.pending = append(r.pending, &pending)
+	full := len(r.pending) >= r.batchSize
+	r.pendingLock.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	return r.Flush(ctx)
+}
+
+// Flush writes all pending records in a single database
+// transaction (deleting any records outside of the retention
+// window) and updates the aggregated statistics of their
+// *types.AccountCurrency. If the records can't be written,
+// they remain pending.
+func (r *ReconciliationStorage) Flush(ctx context.Context) error {
+	r.flushLock.Lock()
+	defer r.flushLock.Unlock()
+
+	r.pendingLock.Lock()
+	records := r.pending
+	r.pending = nil
+	r.pendingLock.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := r.write(ctx, records); err != nil {
+		r.pendingLock.Lock()
+		r.pending = append(records, r.pending...)
+		r.pendingLock.Unlock()
+
+		return fmt.Errorf("%w: %v", storageErrs.ErrReconciliationStoreFailed, err)
+	}
+
+	return nil
+}
+
+func (r *ReconciliationStorage) write(
+	ctx context.Context,
+	records []*ReconciliationRecord,
+) error {
+	dbTx := r.db.Transaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	first, err := r.reserveSequences(ctx, dbTx, len(records))
+	if err != nil {
+		return err
+	}
+
+	for i, record := range records {
+		if err := r.store(ctx, dbTx, record, first+int64(i)); err != nil {
+			return err
+		}
+	}
+
+	if r.retention > 0 {
+		if err := r.prune(ctx, dbTx, first+int64(len(records))-r.retention); err != nil {
+			return err
+		}
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// ReconciliationFailed records a failed reconciliation.
+func (r *ReconciliationStorage) ReconciliationFailed(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+) error {
+	return r.Store(ctx, &ReconciliationRecord{
+		Type: reconciliationType,
+		AccountCurrency: &types.AccountCurrency{
+			Account:  account,
+			Currency: currency,
+		},
+		Result:        ReconciliationFailure,
+		Block:         block,
+		ComputedValue: computedBalance,
+		LiveValue:     liveBalance,
+	})
+}
+
+// ReconciliationSucceeded records a successful reconciliation.
+func (r *ReconciliationStorage) ReconciliationSucceeded(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	balance string,
+	block *types.BlockIdentifier,
+) error {
+	return r.Store(ctx, &ReconciliationRecord{
+		Type: reconciliationType,
+		AccountCurrency: &types.AccountCurrency{
+			Account:  account,
+			Currency: currency,
+		},
+		Result:        ReconciliationSuccess,
+		Block:         block,
+		ComputedValue: balance,
+		LiveValue:     balance,
+	})
+}
+
+// ReconciliationExempt records a reconciliation that
+// failed but was covered by a *types.BalanceExemption.
+func (r *ReconciliationStorage) ReconciliationExempt(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	computedBalance string,
+	liveBalance string,
+	block *types.BlockIdentifier,
+	exemption *types.BalanceExemption,
+) error {
+	return r.Store(ctx, &ReconciliationRecord{
+		Type: reconciliationType,
+		AccountCurrency: &types.AccountCurrency{
+			Account:  account,
+			Currency: currency,
+		},
+		Result:        ReconciliationExempt,
+		Block:         block,
+		ComputedValue: computedBalance,
+		LiveValue:     liveBalance,
+		Exemption:     exemption,
+	})
+}
+
+// ReconciliationSkipped records a reconciliation that
+// could not be attempted.
+func (r *ReconciliationStorage) ReconciliationSkipped(
+	ctx context.Context,
+	reconciliationType string,
+	account *types.AccountIdentifier,
+	currency *types.Currency,
+	cause string,
+) error {
+	return r.Store(ctx, &ReconciliationRecord{
+		Type: reconciliationType,
+		AccountCurrency: &types.AccountCurrency{
+			Account:  account,
+			Currency: currency,
+		},
+		Result: ReconciliationSkipped,
+		Cause:  cause,
+	})
+}
+
+func validateReconciliationQuery(query *ReconciliationQuery) (int, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultReconciliationHistoryLimit
+	}
+
+	if limit < 0 || limit > MaxReconciliationHistoryLimit {
+		return -1, fmt.Errorf(
+			"%w: limit %d must be between 1 and %d",
+			storageErrs.ErrReconciliationQueryInvalid,
+			limit,
+			MaxReconciliationHistoryLimit,
+		)
+	}
+
+	if query.MinBlockIndex != nil && query.MaxBlockIndex != nil &&
+		*query.MinBlockIndex > *query.MaxBlockIndex {
+		return -1, fmt.Errorf(
+			"%w: min block index %d is greater than max block index %d",
+			storageErrs.ErrReconciliationQueryInvalid,
+			*query.MinBlockIndex,
+			*query.MaxBlockIndex,
+		)
+	}
+
+	return limit, nil
+}
+
+func matchesReconciliationQuery(
+	record *ReconciliationRecord,
+	query *ReconciliationQuery,
+) bool {
+	if len(query.Result) > 0 && record.Result != query.Result {
+		return false
+	}
+
+	if query.MinBlockIndex == nil && query.MaxBlockIndex == nil {
+		return true
+	}
+
+	if record.Block == nil {
+		return false
+	}
+
+	if query.MinBlockIndex != nil && record.Block.Index < *query.MinBlockIndex {
+		return false
+	}
+
+	if query.MaxBlockIndex != nil && record.Block.Index > *query.MaxBlockIndex {
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconciliationStorage) getRecord(
+	ctx context.Context,
+	dbTx database.Transaction,
+	sequence int64,
+) (*ReconciliationRecord, error) {
+	exists, v, err := dbTx.Get(ctx, getReconciliationRecordKey(sequence))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("record %d not found", sequence)
+	}
+
+	var record ReconciliationRecord
+	if err := r.db.Encoder().Decode(reconciliationNamespace, v, &record, true); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// History returns the reconciliation records matching
+// the query, starting with the most recent record.
+func (r *ReconciliationStorage) History(
+	ctx context.Context,
+	query *ReconciliationQuery,
+) ([]*ReconciliationRecord, error) {
+	if query == nil {
+		query = &ReconciliationQuery{}
+	}
+
+	limit, err := validateReconciliationQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	dbTx := r.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	// When an account is provided, we scan its index instead
+	// of all records and lookup each referenced record.
+	prefix := getReconciliationRecordPrefix()
+	if query.AccountCurrency != nil {
+		prefix = getReconciliationAccountPrefix(query.AccountCurrency)
+	}
+	seekStart := append(
+		append([]byte{}, prefix...),
+		[]byte(fmt.Sprintf("%020d", int64(math.MaxInt64)))...,
+	)
+
+	records := []*ReconciliationRecord{}
+	_, err = dbTx.Scan(
+		ctx,
+		prefix,
+		seekStart,
+		func(k []byte, v []byte) error {
+			var record *ReconciliationRecord
+			if query.AccountCurrency != nil {
+				sequence, err := strconv.ParseInt(string(k[len(prefix):]), 10, 64)
+				if err != nil {
+					return err
+				}
+
+				record, err = r.getRecord(ctx, dbTx, sequence)
+				if err != nil {
+					return err
+				}
+			} else {
+				record = &ReconciliationRecord{}
+				if err := r.db.Encoder().Decode(reconciliationNamespace, v, record, false); err != nil {
+					return err
+				}
+			}
+
+			if !matchesReconciliationQuery(record, query) {
+				return nil
+			}
+
+			records = append(records, record)
+			if len(records) == limit {
+				return errHistoryDone
+			}
+
+			return nil
+		},
+		false,
+		true,
+	)
+	if err != nil && !errors.Is(err, errHistoryDone) {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrReconciliationQueryFailed, err)
+	}
+
+	return records, nil
+}
+
+// LastReconciled returns the last block where the
+// *types.AccountCurrency was reconciled successfully (or
+// exempt). If the *types.AccountCurrency was never reconciled
+// successfully, nil is returned.
+func (r *ReconciliationStorage) LastReconciled(
+	ctx context.Context,
+	accountCurrency *types.AccountCurrency,
+) (*types.BlockIdentifier, error) {
+	if err := r.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	dbTx := r.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	summary, err := r.getSummary(ctx, dbTx, accountCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrReconciliationQueryFailed, err)
+	}
+
+	return summary.LastReconciled, nil
+}
+
+// Stats returns ReconciliationStats aggregated over all
+// stored reconciliation records. Accounts are only considered
+// reconciled if they were last reconciled at or after
+// minimumIndex.
+func (r *ReconciliationStorage) Stats(
+	ctx context.Context,
+	minimumIndex int64,
+) (*ReconciliationStats, error) {
+	if err := r.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	dbTx := r.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	stats := &ReconciliationStats{LastReconciledIndex: -1}
+	_, err := dbTx.Scan(
+		ctx,
+		getReconciliationSummaryPrefix(),
+		getReconciliationSummaryPrefix(),
+		func(k []byte, v []byte) error {
+			var summary reconciliationSummary
+			if err := r.db.Encoder().Decode(reconciliationNamespace, v, &summary, false); err != nil {
+				return err
+			}
+
+			stats.Attempts += summary.Attempts
+			stats.Successes += summary.Successes
+			stats.Failures += summary.Failures
+			stats.Exemptions += summary.Exemptions
+			stats.Skipped += summary.Skipped
+			stats.Accounts++
+
+			if summary.LastResult == ReconciliationFailure {
+				stats.FailingAccounts++
+			}
+
+			if summary.LastReconciled == nil {
+				return nil
+			}
+
+			if summary.LastReconciled.Index >= minimumIndex {
+				stats.ReconciledAccounts++
+			}
+
+			if summary.LastReconciled.Index > stats.LastReconciledIndex {
+				stats.LastReconciledIndex = summary.LastReconciled.Index
+			}
+
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrReconciliationStatsFailed, err)
+	}
+
+	if stats.Accounts > 0 {
+		stats.Coverage = float64(stats.ReconciledAccounts) / float64(stats.Accounts)
+	}
+
+	return stats, nil
+}
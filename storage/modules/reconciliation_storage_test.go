@@ -0,0 +1,304 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func TestReconciliationStorage(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	r := NewReconciliationStorage(database)
+
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	account1 := &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "addr1"},
+		Currency: currency,
+	}
+	account2 := &types.AccountCurrency{
+		Account:  &types.AccountIdentifier{Address: "addr2"},
+		Currency: currency,
+	}
+	block := func(index int64) *types.BlockIdentifier {
+		return &types.BlockIdentifier{Index: index, Hash: "block"}
+	}
+	exemption := &types.BalanceExemption{
+		Currency:      currency,
+		ExemptionType: types.BalanceDynamic,
+	}
+
+	t.Run("empty storage", func(t *testing.T) {
+		records, err := r.History(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, records, 0)
+
+		stats, err := r.Stats(ctx, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, &ReconciliationStats{LastReconciledIndex: -1}, stats)
+
+		lastReconciled, err := r.LastReconciled(ctx, account1)
+		assert.NoError(t, err)
+		assert.Nil(t, lastReconciled)
+	})
+
+	t.Run("invalid records", func(t *testing.T) {
+		err := r.Store(ctx, &ReconciliationRecord{Result: ReconciliationSuccess})
+		assert.True(t, errors.Is(err, storageErrs.ErrReconciliationRecordInvalid))
+
+		err = r.Store(ctx, &ReconciliationRecord{
+			AccountCurrency: account1,
+			Result:          ReconciliationFailure,
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrReconciliationRecordInvalid))
+
+		err = r.Store(ctx, &ReconciliationRecord{
+			AccountCurrency: account1,
+			Result:          "blah",
+			Block:           block(1),
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrReconciliationRecordInvalid))
+	})
+
+	t.Run("store reconciliations", func(t *testing.T) {
+		assert.NoError(t, r.ReconciliationSucceeded(
+			ctx, "ACTIVE", account1.Account, currency, "100", block(1),
+		))
+		assert.NoError(t, r.ReconciliationFailed(
+			ctx, "ACTIVE", account2.Account, currency, "100", "90", block(2),
+		))
+		assert.NoError(t, r.ReconciliationSkipped(
+			ctx, "INACTIVE", account1.Account, currency, "BLOCK_GONE",
+		))
+		assert.NoError(t, r.ReconciliationExempt(
+			ctx, "INACTIVE", account2.Account, currency, "100", "110", block(3), exemption,
+		))
+		assert.NoError(t, r.ReconciliationFailed(
+			ctx, "ACTIVE", account1.Account, currency, "100", "50", block(4),
+		))
+	})
+
+	t.Run("all history", func(t *testing.T) {
+		records, err := r.History(ctx, &ReconciliationQuery{})
+		assert.NoError(t, err)
+		assert.Len(t, records, 5)
+		for i, record := range records {
+			assert.Equal(t, int64(4-i), record.Sequence)
+			assert.NotZero(t, record.Timestamp)
+		}
+
+		assert.Equal(t, ReconciliationFailure, records[0].Result)
+		assert.Equal(t, account1, records[0].AccountCurrency)
+		assert.Equal(t, block(4), records[0].Block)
+		assert.Equal(t, "100", records[0].ComputedValue)
+		assert.Equal(t, "50", records[0].LiveValue)
+
+		assert.Equal(t, ReconciliationExempt, records[1].Result)
+		assert.Equal(t, exemption, records[1].Exemption)
+
+		assert.Equal(t, ReconciliationSkipped, records[2].Result)
+		assert.Equal(t, "BLOCK_GONE", records[2].Cause)
+		assert.Equal(t, "INACTIVE", records[2].Type)
+		assert.Nil(t, records[2].Block)
+	})
+
+	t.Run("account history", func(t *testing.T) {
+		records, err := r.History(ctx, &ReconciliationQuery{AccountCurrency: account1})
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, int64(4), records[0].Sequence)
+		assert.Equal(t, int64(2), records[1].Sequence)
+		assert.Equal(t, int64(0), records[2].Sequence)
+
+		records, err = r.History(ctx, &ReconciliationQuery{
+			AccountCurrency: account1,
+			Limit:           1,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+		assert.Equal(t, int64(4), records[0].Sequence)
+
+		records, err = r.History(ctx, &ReconciliationQuery{
+			AccountCurrency: &types.AccountCurrency{
+				Account:  &types.AccountIdentifier{Address: "addr3"},
+				Currency: currency,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, records, 0)
+	})
+
+	t.Run("filtered history", func(t *testing.T) {
+		records, err := r.History(ctx, &ReconciliationQuery{Result: ReconciliationFailure})
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, account1, records[0].AccountCurrency)
+		assert.Equal(t, account2, records[1].AccountCurrency)
+
+		minIndex := int64(2)
+		maxIndex := int64(3)
+		records, err = r.History(ctx, &ReconciliationQuery{
+			MinBlockIndex: &minIndex,
+			MaxBlockIndex: &maxIndex,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, block(3), records[0].Block)
+		assert.Equal(t, block(2), records[1].Block)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		records, err := r.History(ctx, &ReconciliationQuery{Limit: -1})
+		assert.True(t, errors.Is(err, storageErrs.ErrReconciliationQueryInvalid))
+		assert.Nil(t, records)
+
+		records, err = r.History(
+			ctx,
+			&ReconciliationQuery{Limit: MaxReconciliationHistoryLimit + 1},
+		)
+		assert.True(t, errors.Is(err, storageErrs.ErrReconciliationQueryInvalid))
+		assert.Nil(t, records)
+
+		minIndex := int64(3)
+		maxIndex := int64(2)
+		records, err = r.History(ctx, &ReconciliationQuery{
+			MinBlockIndex: &minIndex,
+			MaxBlockIndex: &maxIndex,
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrReconciliationQueryInvalid))
+		assert.Nil(t, records)
+	})
+
+	t.Run("last reconciled", func(t *testing.T) {
+		lastReconciled, err := r.LastReconciled(ctx, account1)
+		assert.NoError(t, err)
+		assert.Equal(t, block(1), lastReconciled)
+
+		lastReconciled, err = r.LastReconciled(ctx, account2)
+		assert.NoError(t, err)
+		assert.Equal(t, block(3), lastReconciled)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		stats, err := r.Stats(ctx, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, &ReconciliationStats{
+			Attempts:            5,
+			Successes:           1,
+			Failures:            2,
+			Exemptions:          1,
+			Skipped:             1,
+			Accounts:            2,
+			ReconciledAccounts:  2,
+			FailingAccounts:     1,
+			Coverage:            1,
+			LastReconciledIndex: 3,
+		}, stats)
+
+		stats, err = r.Stats(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), stats.ReconciledAccounts)
+		assert.Equal(t, 0.5, stats.Coverage)
+	})
+}
+
+func TestReconciliationStorageBatchesAndRetention(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	r := NewReconciliationStorage(
+		database,
+		WithReconciliationBatchSize(3),
+		WithReconciliationRetention(4),
+	)
+
+	currency := &types.Currency{Symbol: "BTC", Decimals: 8}
+	account := &types.AccountIdentifier{Address: "addr1"}
+	accountCurrency := &types.AccountCurrency{Account: account, Currency: currency}
+	stored := func() int {
+		txn := database.ReadTransaction(ctx)
+		defer txn.Discard(ctx)
+
+		count, err := txn.Scan(
+			ctx,
+			getReconciliationRecordPrefix(),
+			getReconciliationRecordPrefix(),
+			func(k []byte, v []byte) error { return nil },
+			false,
+			false,
+		)
+		assert.NoError(t, err)
+		return count
+	}
+
+	// Records are only written once the batch is full.
+	for i := int64(0); i < 2; i++ {
+		assert.NoError(t, r.ReconciliationSucceeded(
+			ctx, "ACTIVE", account, currency, "100", &types.BlockIdentifier{Index: i, Hash: "block"},
+		))
+	}
+	assert.Equal(t, 0, stored())
+
+	assert.NoError(t, r.ReconciliationSucceeded(
+		ctx, "ACTIVE", account, currency, "100", &types.BlockIdentifier{Index: 2, Hash: "block"},
+	))
+	assert.Equal(t, 3, stored())
+
+	// Only the 4 most recent records are kept.
+	for i := int64(3); i < 7; i++ {
+		assert.NoError(t, r.ReconciliationSucceeded(
+			ctx, "ACTIVE", account, currency, "100", &types.BlockIdentifier{Index: i, Hash: "block"},
+		))
+	}
+	assert.Equal(t, 4, stored())
+
+	// Queries flush pending records.
+	records, err := r.History(ctx, &ReconciliationQuery{AccountCurrency: accountCurrency})
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	for i, record := range records {
+		assert.Equal(t, int64(6-i), record.Sequence)
+		assert.Equal(t, int64(6-i), record.Block.Index)
+	}
+
+	// Stats are aggregated over all records ever stored.
+	stats, err := r.Stats(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), stats.Attempts)
+	assert.Equal(t, int64(6), stats.LastReconciledIndex)
+}
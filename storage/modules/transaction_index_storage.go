@@ -0,0 +1,595 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/neilotoole/errgroup"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var _ BlockWorker = (*TransactionIndexStorage)(nil)
+
+const (
+	// transactionIndexNamespace is prepended to any stored
+	// transaction index entry.
+	transactionIndexNamespace = "txidx"
+
+	// Each transaction is indexed under the following
+	// dimensions. All entries under a dimension are ordered
+	// by block index (or timestamp).
+	allIndexDimension      = "all"
+	timeIndexDimension     = "time"
+	hashIndexDimension     = "hash"
+	accountIndexDimension  = "acct"
+	addressIndexDimension  = "addr"
+	currencyIndexDimension = "cur"
+	typeIndexDimension     = "type"
+	coinIndexDimension     = "coin"
+
+	// DefaultSearchTransactionsLimit is the number of transactions
+	// returned by a search when no limit is provided.
+	DefaultSearchTransactionsLimit = 100
+
+	// MaxSearchTransactionsLimit is the maximum number of
+	// transactions that can be returned by a single search.
+	MaxSearchTransactionsLimit = 1000
+)
+
+var errSearchDone = errors.New("search done")
+
+// transactionIndexEntry is stored under every
+// index key of a transaction.
+type transactionIndexEntry struct {
+	BlockIndex      int64  `json:"block_index"`
+	BlockHash       string `json:"block_hash"`
+	TransactionHash string `json:"transaction_hash"`
+	Timestamp       int64  `json:"timestamp"`
+}
+
+func getTransactionIndexPrefix(dimension string, value string) []byte {
+	if dimension == allIndexDimension || dimension == timeIndexDimension {
+		return []byte(fmt.Sprintf("%s/%s/", transactionIndexNamespace, dimension))
+	}
+
+	return []byte(
+		fmt.Sprintf("%s/%s/%s/", transactionIndexNamespace, dimension, types.Hash(value)),
+	)
+}
+
+func getTransactionIndexKey(
+	dimension string,
+	value string,
+	entry *transactionIndexEntry,
+) []byte {
+	prefix := getTransactionIndexPrefix(dimension, value)
+	if dimension == timeIndexDimension {
+		prefix = append(prefix, []byte(fmt.Sprintf("%020d/", entry.Timestamp))...)
+	}
+
+	return append(prefix, []byte(fmt.Sprintf(
+		"%020d/%s/%s",
+		entry.BlockIndex,
+		entry.BlockHash,
+		entry.TransactionHash,
+	))...)
+}
+
+// getTransactionIndexKeys returns all index keys
+// of a transaction (without duplicates).
+func getTransactionIndexKeys(
+	transaction *types.Transaction,
+	entry *transactionIndexEntry,
+) [][]byte {
+	seen := map[string]struct{}{}
+	keys := [][]byte{}
+	add := func(dimension string, value string) {
+		key := getTransactionIndexKey(dimension, value, entry)
+		if _, ok := seen[string(key)]; ok {
+			return
+		}
+
+		seen[string(key)] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	add(allIndexDimension, "")
+	add(timeIndexDimension, "")
+	add(hashIndexDimension, transaction.TransactionIdentifier.Hash)
+	for _, op := range transaction.Operations {
+		add(typeIndexDimension, op.Type)
+		if op.Account != nil {
+			add(accountIndexDimension, types.Hash(op.Account))
+			add(addressIndexDimension, op.Account.Address)
+		}
+
+		if op.Amount != nil {
+			add(currencyIndexDimension, types.Hash(op.Amount.Currency))
+		}
+
+		if op.CoinChange != nil {
+			add(coinIndexDimension, op.CoinChange.CoinIdentifier.Identifier)
+		}
+	}
+
+	return keys
+}
+
+// TransactionIndexStorage maintains secondary indexes of
+// all transactions in BlockStorage (by transaction hash,
+// account, address, currency, operation type, coin, and
+// timestamp) so that the /search/transactions endpoint can
+// be implemented entirely from storage.
+//
+// TransactionIndexStorage must be provided to BlockStorage
+// as a BlockWorker to be kept up to date.
+type TransactionIndexStorage struct {
+	db           database.Database
+	blockStorage *BlockStorage
+	asserter     *asserter.Asserter
+}
+
+// NewTransactionIndexStorage returns a new TransactionIndexStorage.
+// The asserter is only used to evaluate searches by success and
+// may be nil if such searches are not needed.
+func NewTransactionIndexStorage(
+	db database.Database,
+	blockStorage *BlockStorage,
+	asserter *asserter.Asserter,
+) *TransactionIndexStorage {
+	return &TransactionIndexStorage{
+		db:           db,
+		blockStorage: blockStorage,
+		asserter:     asserter,
+	}
+}
+
+func (t *TransactionIndexStorage) forEachIndexKey(
+	block *types.Block,
+	handler func(key []byte, value []byte) error,
+) error {
+	for _, transaction := range block.Transactions {
+		entry := &transactionIndexEntry{
+			BlockIndex:      block.BlockIdentifier.Index,
+			BlockHash:       block.BlockIdentifier.Hash,
+			TransactionHash: transaction.TransactionIdentifier.Hash,
+			Timestamp:       block.Timestamp,
+		}
+		value, err := t.db.Encoder().Encode(transactionIndexNamespace, entry)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range getTransactionIndexKeys(transaction, entry) {
+			if err := handler(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddingBlock is called by BlockStorage when adding a block.
+func (t *TransactionIndexStorage) AddingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	err := t.forEachIndexKey(block, func(key []byte, value []byte) error {
+		// The value is shared by all index keys of a
+		// transaction, so it cannot be reclaimed.
+		return transaction.Set(ctx, key, value, false)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrTransactionIndexStoreFailed, err)
+	}
+
+	return nil, nil
+}
+
+// RemovingBlock is called by BlockStorage when removing a block.
+func (t *TransactionIndexStorage) RemovingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	err := t.forEachIndexKey(block, func(key []byte, value []byte) error {
+		return transaction.Delete(ctx, key)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrTransactionIndexDeleteFailed, err)
+	}
+
+	return nil, nil
+}
+
+// searchCondition is a single condition of a
+// *types.SearchTransactionsRequest. If dimension
+// is populated, all transactions that could match
+// the condition are indexed under dimension and value.
+type searchCondition struct {
+	dimension string
+	value     string
+	matches   func(*types.Transaction) (bool, error)
+}
+
+func anyOperation(
+	transaction *types.Transaction,
+	matches func(*types.Operation) (bool, error),
+) (bool, error) {
+	for _, op := range transaction.Operations {
+		match, err := matches(op)
+		if err != nil {
+			return false, err
+		}
+
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// searchConditions returns the conditions of a
+// *types.SearchTransactionsRequest ordered from most
+// to least selective.
+func (t *TransactionIndexStorage) searchConditions(
+	request *types.SearchTransactionsRequest,
+) ([]*searchCondition, error) {
+	conditions := []*searchCondition{}
+	if request.TransactionIdentifier != nil {
+		hash := request.TransactionIdentifier.Hash
+		conditions = append(conditions, &searchCondition{
+			dimension: hashIndexDimension,
+			value:     hash,
+			matches: func(tx *types.Transaction) (bool, error) {
+				return tx.TransactionIdentifier.Hash == hash, nil
+			},
+		})
+	}
+
+	if request.CoinIdentifier != nil {
+		identifier := request.CoinIdentifier.Identifier
+		conditions = append(conditions, &searchCondition{
+			dimension: coinIndexDimension,
+			value:     identifier,
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					return op.CoinChange != nil &&
+						op.CoinChange.CoinIdentifier.Identifier == identifier, nil
+				})
+			},
+		})
+	}
+
+	if request.AccountIdentifier != nil {
+		account := types.Hash(request.AccountIdentifier)
+		conditions = append(conditions, &searchCondition{
+			dimension: accountIndexDimension,
+			value:     account,
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					return op.Account != nil && types.Hash(op.Account) == account, nil
+				})
+			},
+		})
+	}
+
+	if request.Address != nil {
+		address := *request.Address
+		conditions = append(conditions, &searchCondition{
+			dimension: addressIndexDimension,
+			value:     address,
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					return op.Account != nil && op.Account.Address == address, nil
+				})
+			},
+		})
+	}
+
+	if request.Currency != nil {
+		currency := types.Hash(request.Currency)
+		conditions = append(conditions, &searchCondition{
+			dimension: currencyIndexDimension,
+			value:     currency,
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					return op.Amount != nil && types.Hash(op.Amount.Currency) == currency, nil
+				})
+			},
+		})
+	}
+
+	if request.Type != nil {
+		opType := *request.Type
+		conditions = append(conditions, &searchCondition{
+			dimension: typeIndexDimension,
+			value:     opType,
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					return op.Type == opType, nil
+				})
+			},
+		})
+	}
+
+	if request.Status != nil {
+		status := *request.Status
+		conditions = append(conditions, &searchCondition{
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					return op.Status != nil && *op.Status == status, nil
+				})
+			},
+		})
+	}
+
+	if request.Success != nil {
+		if t.asserter == nil {
+			return nil, storageErrs.ErrSearchSuccessUnsupported
+		}
+
+		success := *request.Success
+		conditions = append(conditions, &searchCondition{
+			matches: func(tx *types.Transaction) (bool, error) {
+				return anyOperation(tx, func(op *types.Operation) (bool, error) {
+					successful, err := t.asserter.OperationSuccessful(op)
+					if err != nil {
+						return false, err
+					}
+
+					return successful == success, nil
+				})
+			},
+		})
+	}
+
+	return conditions, nil
+}
+
+// timestampRange is an inclusive range
+// of block timestamps (in milliseconds).
+type timestampRange struct {
+	start int64
+	end   int64
+}
+
+// SearchTransactions returns all transactions that match a
+// *types.SearchTransactionsRequest using the same semantics
+// as the /search/transactions endpoint. Transactions are
+// returned from the most recent block to the oldest and
+// transactions that have been pruned are omitted.
+//
+// Searches with the "and" operator scan the index of the
+// most selective indexed condition. Searches with the "or"
+// operator (or with only unindexed conditions) scan all
+// transactions.
+func (t *TransactionIndexStorage) SearchTransactions(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+) (*types.SearchTransactionsResponse, error) {
+	return t.search(ctx, request, nil)
+}
+
+// SearchTransactionsInTimestampRange is the same as SearchTransactions
+// except only transactions in blocks with a timestamp in
+// [startTimestamp, endTimestamp] are considered. Transactions are
+// returned from the most recent timestamp to the oldest.
+func (t *TransactionIndexStorage) SearchTransactionsInTimestampRange(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+	startTimestamp int64,
+	endTimestamp int64,
+) (*types.SearchTransactionsResponse, error) {
+	if startTimestamp < 0 || startTimestamp > endTimestamp {
+		return nil, fmt.Errorf(
+			"%w: [%d, %d]",
+			storageErrs.ErrInvalidTimestampRange,
+			startTimestamp,
+			endTimestamp,
+		)
+	}
+
+	return t.search(ctx, request, &timestampRange{start: startTimestamp, end: endTimestamp})
+}
+
+func (t *TransactionIndexStorage) search(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+	timestamps *timestampRange,
+) (*types.SearchTransactionsResponse, error) {
+	operator := types.AND
+	if request.Operator != nil {
+		operator = *request.Operator
+	}
+
+	if operator != types.AND && operator != types.OR {
+		return nil, fmt.Errorf("%w: %s", storageErrs.ErrSearchOperatorInvalid, operator)
+	}
+
+	var offset int64
+	if request.Offset != nil {
+		offset = *request.Offset
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: %d", storageErrs.ErrSearchOffsetInvalid, offset)
+	}
+
+	limit := int64(DefaultSearchTransactionsLimit)
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+
+	if limit <= 0 || limit > MaxSearchTransactionsLimit {
+		return nil, fmt.Errorf("%w: %d", storageErrs.ErrSearchLimitInvalid, limit)
+	}
+
+	maxBlock := int64(math.MaxInt64)
+	if request.MaxBlock != nil {
+		maxBlock = *request.MaxBlock
+	}
+
+	conditions, err := t.searchConditions(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Select the index to scan and where to start scanning it.
+	var prefix, seekStart []byte
+	switch {
+	case timestamps != nil:
+		prefix = getTransactionIndexPrefix(timeIndexDimension, "")
+		seekStart = append(prefix, []byte(fmt.Sprintf("%020d", nextIndex(timestamps.end)))...)
+	default:
+		dimension, value := allIndexDimension, ""
+		if operator == types.AND {
+			for _, condition := range conditions {
+				if len(condition.dimension) > 0 {
+					dimension, value = condition.dimension, condition.value
+					break
+				}
+			}
+		}
+
+		prefix = getTransactionIndexPrefix(dimension, value)
+		seekStart = append(prefix, []byte(fmt.Sprintf("%020d", nextIndex(maxBlock)))...)
+	}
+
+	txn := t.db.ReadTransaction(ctx)
+	defer txn.Discard(ctx)
+
+	response := &types.SearchTransactionsResponse{
+		Transactions: []*types.BlockTransaction{},
+	}
+	_, err = txn.Scan(
+		ctx,
+		prefix,
+		seekStart,
+		func(k []byte, v []byte) error {
+			var entry transactionIndexEntry
+			if err := t.db.Encoder().Decode(transactionIndexNamespace, v, &entry, false); err != nil {
+				return err
+			}
+
+			if timestamps != nil && entry.Timestamp < timestamps.start {
+				return errSearchDone
+			}
+
+			if entry.BlockIndex > maxBlock {
+				return nil
+			}
+
+			blockIdentifier := &types.BlockIdentifier{
+				Index: entry.BlockIndex,
+				Hash:  entry.BlockHash,
+			}
+			transaction, err := t.blockStorage.findBlockTransaction(
+				ctx,
+				blockIdentifier,
+				&types.TransactionIdentifier{Hash: entry.TransactionHash},
+				txn,
+			)
+			if errors.Is(err, storageErrs.ErrCannotAccessPrunedData) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			match, err := matchesSearch(transaction, operator, conditions)
+			if err != nil {
+				return err
+			}
+
+			if !match {
+				return nil
+			}
+
+			response.TotalCount++
+			if response.TotalCount > offset && int64(len(response.Transactions)) < limit {
+				response.Transactions = append(response.Transactions, &types.BlockTransaction{
+					BlockIdentifier: blockIdentifier,
+					Transaction:     transaction,
+				})
+			}
+
+			return nil
+		},
+		false,
+		true,
+	)
+	if err != nil && !errors.Is(err, errSearchDone) {
+		return nil, fmt.Errorf("%w: %v", storageErrs.ErrTransactionIndexQueryFailed, err)
+	}
+
+	nextOffset := offset + int64(len(response.Transactions))
+	if nextOffset < response.TotalCount {
+		response.NextOffset = &nextOffset
+	}
+
+	return response, nil
+}
+
+// nextIndex returns index+1 without overflowing. Seeking
+// to the next index in a reverse scan starts the scan
+// at the last entry of index.
+func nextIndex(index int64) int64 {
+	if index == math.MaxInt64 {
+		return index
+	}
+
+	return index + 1
+}
+
+func matchesSearch(
+	transaction *types.Transaction,
+	operator types.Operator,
+	conditions []*searchCondition,
+) (bool, error) {
+	if len(conditions) == 0 {
+		return true, nil
+	}
+
+	for _, condition := range conditions {
+		match, err := condition.matches(transaction)
+		if err != nil {
+			return false, err
+		}
+
+		if operator == types.OR && match {
+			return true, nil
+		}
+
+		if operator == types.AND && !match {
+			return false, nil
+		}
+	}
+
+	return operator == types.AND, nil
+}
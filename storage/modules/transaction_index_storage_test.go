@@ -0,0 +1,291 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+func searchTransactionHashes(response *types.SearchTransactionsResponse) []string {
+	hashes := []string{}
+	for _, tx := range response.Transactions {
+		hashes = append(hashes, tx.Transaction.TransactionIdentifier.Hash)
+	}
+
+	return hashes
+}
+
+func TestTransactionIndexStorage(t *testing.T) {
+	ctx := context.Background()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	database, err := newTestBadgerDatabase(ctx, newDir)
+	assert.NoError(t, err)
+	defer database.Close(ctx)
+
+	a, err := asserter.NewClientWithOptions(
+		&types.NetworkIdentifier{
+			Blockchain: "bitcoin",
+			Network:    "mainnet",
+		},
+		&types.BlockIdentifier{
+			Hash:  "block 0",
+			Index: 0,
+		},
+		[]string{"Transfer", "Reward"},
+		[]*types.OperationStatus{
+			{
+				Status:     *successStatus,
+				Successful: true,
+			},
+			{
+				Status:     *failureStatus,
+				Successful: false,
+			},
+		},
+		[]*types.Error{},
+		nil,
+		&asserter.Validations{
+			Enabled: false,
+		},
+	)
+	assert.NoError(t, err)
+
+	blockStorage := NewBlockStorage(database, blockWorkerConcurrency)
+	indexStorage := NewTransactionIndexStorage(database, blockStorage, a)
+	blockStorage.Initialize([]BlockWorker{indexStorage})
+
+	hello := &types.Currency{Symbol: "hello"}
+	world := &types.Currency{Symbol: "world"}
+	coinIdentifier := &types.CoinIdentifier{Identifier: "coin1"}
+	txA := simpleTransactionFactory("txA", "addr1", "100", hello)
+	txA.Operations[0].Status = successStatus
+	txB := simpleTransactionFactory("txB", "addr2", "200", hello)
+	txB.Operations[0].Status = failureStatus
+	txC := simpleTransactionFactory("txC", "addr1", "300", world)
+	txC.Operations[0].Type = "Reward"
+	txC.Operations[0].Status = successStatus
+	txC.Operations[0].CoinChange = &types.CoinChange{
+		CoinIdentifier: coinIdentifier,
+		CoinAction:     types.CoinCreated,
+	}
+
+	blocks := []*types.Block{
+		{
+			BlockIdentifier:       &types.BlockIdentifier{Hash: "block 0", Index: 0},
+			ParentBlockIdentifier: &types.BlockIdentifier{Hash: "block 0", Index: 0},
+			Timestamp:             1000,
+		},
+		{
+			BlockIdentifier:       &types.BlockIdentifier{Hash: "block 1", Index: 1},
+			ParentBlockIdentifier: &types.BlockIdentifier{Hash: "block 0", Index: 0},
+			Timestamp:             2000,
+			Transactions:          []*types.Transaction{txA},
+		},
+		{
+			BlockIdentifier:       &types.BlockIdentifier{Hash: "block 2", Index: 2},
+			ParentBlockIdentifier: &types.BlockIdentifier{Hash: "block 1", Index: 1},
+			Timestamp:             3000,
+			Transactions:          []*types.Transaction{txB, txC},
+		},
+	}
+	for _, block := range blocks {
+		assert.NoError(t, blockStorage.SeeBlock(ctx, block))
+		assert.NoError(t, blockStorage.AddBlock(ctx, block))
+	}
+
+	tests := map[string]struct {
+		request *types.SearchTransactionsRequest
+
+		hashes     []string
+		totalCount int64
+		nextOffset *int64
+	}{
+		"all transactions": {
+			request:    &types.SearchTransactionsRequest{},
+			hashes:     []string{"txC", "txB", "txA"},
+			totalCount: 3,
+		},
+		"by transaction hash": {
+			request: &types.SearchTransactionsRequest{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "txB"},
+			},
+			hashes:     []string{"txB"},
+			totalCount: 1,
+		},
+		"by account": {
+			request: &types.SearchTransactionsRequest{
+				AccountIdentifier: &types.AccountIdentifier{Address: "addr1"},
+			},
+			hashes:     []string{"txC", "txA"},
+			totalCount: 2,
+		},
+		"by address and currency": {
+			request: &types.SearchTransactionsRequest{
+				Address:  types.String("addr1"),
+				Currency: hello,
+			},
+			hashes:     []string{"txA"},
+			totalCount: 1,
+		},
+		"by type": {
+			request: &types.SearchTransactionsRequest{
+				Type: types.String("Reward"),
+			},
+			hashes:     []string{"txC"},
+			totalCount: 1,
+		},
+		"by coin": {
+			request: &types.SearchTransactionsRequest{
+				CoinIdentifier: coinIdentifier,
+			},
+			hashes:     []string{"txC"},
+			totalCount: 1,
+		},
+		"by status": {
+			request: &types.SearchTransactionsRequest{
+				Status: failureStatus,
+			},
+			hashes:     []string{"txB"},
+			totalCount: 1,
+		},
+		"by success": {
+			request: &types.SearchTransactionsRequest{
+				Success: types.Bool(true),
+			},
+			hashes:     []string{"txC", "txA"},
+			totalCount: 2,
+		},
+		"or": {
+			request: &types.SearchTransactionsRequest{
+				Operator: types.OperatorP(types.OR),
+				Type:     types.String("Reward"),
+				Address:  types.String("addr2"),
+			},
+			hashes:     []string{"txC", "txB"},
+			totalCount: 2,
+		},
+		"max block": {
+			request: &types.SearchTransactionsRequest{
+				MaxBlock: types.Int64(1),
+			},
+			hashes:     []string{"txA"},
+			totalCount: 1,
+		},
+		"paginated": {
+			request: &types.SearchTransactionsRequest{
+				Offset: types.Int64(1),
+				Limit:  types.Int64(1),
+			},
+			hashes:     []string{"txB"},
+			totalCount: 3,
+			nextOffset: types.Int64(2),
+		},
+		"no matches": {
+			request: &types.SearchTransactionsRequest{
+				Currency: &types.Currency{Symbol: "missing"},
+			},
+			hashes:     []string{},
+			totalCount: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			response, err := indexStorage.SearchTransactions(ctx, test.request)
+			assert.NoError(t, err)
+			assert.Equal(t, test.hashes, searchTransactionHashes(response))
+			assert.Equal(t, test.totalCount, response.TotalCount)
+			assert.Equal(t, test.nextOffset, response.NextOffset)
+		})
+	}
+
+	t.Run("timestamp range", func(t *testing.T) {
+		response, err := indexStorage.SearchTransactionsInTimestampRange(
+			ctx,
+			&types.SearchTransactionsRequest{Currency: hello},
+			1000,
+			3000,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"txB", "txA"}, searchTransactionHashes(response))
+
+		response, err = indexStorage.SearchTransactionsInTimestampRange(
+			ctx,
+			&types.SearchTransactionsRequest{},
+			1000,
+			2999,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"txA"}, searchTransactionHashes(response))
+
+		_, err = indexStorage.SearchTransactionsInTimestampRange(
+			ctx,
+			&types.SearchTransactionsRequest{},
+			3000,
+			1000,
+		)
+		assert.True(t, errors.Is(err, storageErrs.ErrInvalidTimestampRange))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err := indexStorage.SearchTransactions(ctx, &types.SearchTransactionsRequest{
+			Limit: types.Int64(0),
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrSearchLimitInvalid))
+
+		_, err = indexStorage.SearchTransactions(ctx, &types.SearchTransactionsRequest{
+			Offset: types.Int64(-1),
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrSearchOffsetInvalid))
+
+		_, err = indexStorage.SearchTransactions(ctx, &types.SearchTransactionsRequest{
+			Operator: types.OperatorP("xor"),
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrSearchOperatorInvalid))
+
+		noAsserter := NewTransactionIndexStorage(database, blockStorage, nil)
+		_, err = noAsserter.SearchTransactions(ctx, &types.SearchTransactionsRequest{
+			Success: types.Bool(true),
+		})
+		assert.True(t, errors.Is(err, storageErrs.ErrSearchSuccessUnsupported))
+	})
+
+	t.Run("remove block", func(t *testing.T) {
+		assert.NoError(t, blockStorage.RemoveBlock(ctx, blocks[2].BlockIdentifier))
+
+		response, err := indexStorage.SearchTransactions(ctx, &types.SearchTransactionsRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"txA"}, searchTransactionHashes(response))
+
+		response, err = indexStorage.SearchTransactions(ctx, &types.SearchTransactionsRequest{
+			CoinIdentifier: coinIdentifier,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{}, searchTransactionHashes(response))
+	})
+}
@@ -0,0 +1,242 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+const (
+	// archiveExtension is the file extension
+	// of blocks in a DirectoryArchive.
+	archiveExtension = ".json"
+)
+
+// NewArchiveHelper returns a new *ArchiveHelper
+// that sources blocks from archive.
+func NewArchiveHelper(archive BlockArchive) *ArchiveHelper {
+	return &ArchiveHelper{archive: archive}
+}
+
+// NetworkStatus returns a *types.NetworkStatusResponse where
+// the genesis block is the oldest archived block and the
+// current block is the newest archived block. The network
+// argument is ignored.
+func (h *ArchiveHelper) NetworkStatus(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+) (*types.NetworkStatusResponse, error) {
+	oldestIndex, err := h.archive.GetOldestBlockIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get oldest block index: %v", ErrArchiveReadFailed, err)
+	}
+
+	oldest, err := h.archive.GetBlock(ctx, &types.PartialBlockIdentifier{Index: &oldestIndex})
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get oldest block: %v", ErrArchiveReadFailed, err)
+	}
+
+	head, err := h.archive.GetHeadBlockIdentifier(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get head block identifier: %v", ErrArchiveReadFailed, err)
+	}
+
+	headBlock, err := h.archive.GetBlock(ctx, types.ConstructPartialBlockIdentifier(head))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get head block: %v", ErrArchiveReadFailed, err)
+	}
+
+	return &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: head,
+		CurrentBlockTimestamp:  headBlock.Timestamp,
+		GenesisBlockIdentifier: oldest.BlockIdentifier,
+	}, nil
+}
+
+// Block returns the archived block matching identifier.
+// The network argument is ignored.
+func (h *ArchiveHelper) Block(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	identifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	block, err := h.archive.GetBlock(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrArchiveReadFailed, err)
+	}
+
+	return block, nil
+}
+
+// PrefetchBlocks forwards the prefetch hint to the archive
+// if it implements BlockPrefetcher (ex: *modules.BlockStorage).
+func (h *ArchiveHelper) PrefetchBlocks(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) {
+	if prefetcher, ok := h.archive.(BlockPrefetcher); ok {
+		prefetcher.PrefetchBlocks(ctx, startIndex, endIndex)
+	}
+}
+
+// NewDirectoryArchive returns a new *DirectoryArchive
+// for dir, creating dir if it does not exist. Any blocks
+// already in dir are included in the archive.
+func NewDirectoryArchive(dir string) (*DirectoryArchive, error) {
+	if err := utils.EnsurePathExists(dir); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrArchiveReadFailed, err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read directory %s: %v", ErrArchiveReadFailed, dir, err)
+	}
+
+	d := &DirectoryArchive{
+		dir:         dir,
+		oldestIndex: -1,
+		headIndex:   -1,
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), archiveExtension) {
+			continue
+		}
+
+		index, err := strconv.ParseInt(strings.TrimSuffix(file.Name(), archiveExtension), 10, 64)
+		if err != nil || index < 0 {
+			continue
+		}
+
+		d.seeIndex(index)
+	}
+
+	return d, nil
+}
+
+// seeIndex updates the oldest and head
+// indices to include index.
+func (d *DirectoryArchive) seeIndex(index int64) {
+	if d.oldestIndex == -1 || index < d.oldestIndex {
+		d.oldestIndex = index
+	}
+
+	if index > d.headIndex {
+		d.headIndex = index
+	}
+}
+
+func (d *DirectoryArchive) blockPath(index int64) string {
+	return path.Join(d.dir, strconv.FormatInt(index, 10)+archiveExtension)
+}
+
+// AddBlock writes block to the archive (overwriting any
+// archived block at the same index). This makes it possible
+// to populate a DirectoryArchive from a Handler.
+func (d *DirectoryArchive) AddBlock(ctx context.Context, block *types.Block) error {
+	index := block.BlockIdentifier.Index
+	if err := utils.SerializeAndWrite(d.blockPath(index), block); err != nil {
+		return fmt.Errorf("%w: %v", ErrArchiveWriteFailed, err)
+	}
+
+	d.indexLock.Lock()
+	d.seeIndex(index)
+	d.indexLock.Unlock()
+
+	return nil
+}
+
+// GetBlock returns the archived block at identifier.Index. If
+// identifier.Hash is populated, the archived block must have the
+// same hash. If identifier is nil, the head block is returned.
+// Blocks can't be looked up by hash alone.
+func (d *DirectoryArchive) GetBlock(
+	ctx context.Context,
+	identifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	var index int64
+	switch {
+	case identifier == nil:
+		d.indexLock.Lock()
+		index = d.headIndex
+		d.indexLock.Unlock()
+
+		if index == -1 {
+			return nil, ErrArchiveEmpty
+		}
+	case identifier.Index == nil:
+		return nil, fmt.Errorf("%w: index is required", ErrArchiveBlockNotFound)
+	default:
+		index = *identifier.Index
+	}
+
+	blockPath := d.blockPath(index)
+	if _, err := os.Stat(blockPath); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %d", ErrArchiveBlockNotFound, index)
+	}
+
+	var block types.Block
+	if err := utils.LoadAndParse(blockPath, &block); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrArchiveReadFailed, err)
+	}
+
+	if identifier != nil && identifier.Hash != nil &&
+		block.BlockIdentifier.Hash != *identifier.Hash {
+		return nil, fmt.Errorf(
+			"%w: expected %s but got %s",
+			ErrArchiveHashMismatch,
+			*identifier.Hash,
+			block.BlockIdentifier.Hash,
+		)
+	}
+
+	return &block, nil
+}
+
+// GetHeadBlockIdentifier returns the identifier
+// of the archived block with the largest index.
+func (d *DirectoryArchive) GetHeadBlockIdentifier(
+	ctx context.Context,
+) (*types.BlockIdentifier, error) {
+	block, err := d.GetBlock(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return block.BlockIdentifier, nil
+}
+
+// GetOldestBlockIndex returns the smallest
+// index of any archived block.
+func (d *DirectoryArchive) GetOldestBlockIndex(ctx context.Context) (int64, error) {
+	d.indexLock.Lock()
+	defer d.indexLock.Unlock()
+
+	if d.oldestIndex == -1 {
+		return -1, ErrArchiveEmpty
+	}
+
+	return d.oldestIndex, nil
+}
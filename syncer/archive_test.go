@@ -0,0 +1,175 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/coinbase/rosetta-sdk-go/utils"
+)
+
+var (
+	_ BlockArchive    = (*modules.BlockStorage)(nil)
+	_ BlockPrefetcher = (*modules.BlockStorage)(nil)
+	_ BlockPrefetcher = (*ArchiveHelper)(nil)
+)
+
+// prefetchArchive records all prefetch
+// hints it receives.
+type prefetchArchive struct {
+	*DirectoryArchive
+
+	hints [][2]int64
+}
+
+func (p *prefetchArchive) PrefetchBlocks(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) {
+	p.hints = append(p.hints, [2]int64{startIndex, endIndex})
+}
+
+func TestDirectoryArchive(t *testing.T) {
+	ctx := context.Background()
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	archive, err := NewDirectoryArchive(dir)
+	assert.NoError(t, err)
+
+	// Empty archive
+	_, err = archive.GetHeadBlockIdentifier(ctx)
+	assert.ErrorIs(t, err, ErrArchiveEmpty)
+	_, err = archive.GetOldestBlockIndex(ctx)
+	assert.ErrorIs(t, err, ErrArchiveEmpty)
+
+	blocks := createBlocks(3, 6, "")
+	for _, block := range blocks {
+		assert.NoError(t, archive.AddBlock(ctx, block))
+	}
+
+	// Unrelated files are ignored when loading
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "notes.json"), []byte("{}"), 0600))
+
+	archive, err = NewDirectoryArchive(dir)
+	assert.NoError(t, err)
+
+	head, err := archive.GetHeadBlockIdentifier(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[3].BlockIdentifier, head)
+
+	oldest, err := archive.GetOldestBlockIndex(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), oldest)
+
+	block, err := archive.GetBlock(ctx, types.ConstructPartialBlockIdentifier(blocks[1].BlockIdentifier))
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[1], block)
+
+	hash := "block 100"
+	index := int64(4)
+	_, err = archive.GetBlock(ctx, &types.PartialBlockIdentifier{Index: &index, Hash: &hash})
+	assert.ErrorIs(t, err, ErrArchiveHashMismatch)
+
+	_, err = archive.GetBlock(ctx, &types.PartialBlockIdentifier{Hash: &hash})
+	assert.ErrorIs(t, err, ErrArchiveBlockNotFound)
+
+	index = 7
+	_, err = archive.GetBlock(ctx, &types.PartialBlockIdentifier{Index: &index})
+	assert.ErrorIs(t, err, ErrArchiveBlockNotFound)
+}
+
+func TestSync_Archive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	archive, err := NewDirectoryArchive(dir)
+	assert.NoError(t, err)
+
+	blocks := createBlocks(3, 6, "")
+	for _, block := range blocks {
+		assert.NoError(t, archive.AddBlock(ctx, block))
+	}
+
+	helper := NewArchiveHelper(archive)
+	status, err := helper.NetworkStatus(ctx, networkIdentifier)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[0].BlockIdentifier, status.GenesisBlockIdentifier)
+	assert.Equal(t, blocks[3].BlockIdentifier, status.CurrentBlockIdentifier)
+
+	mockHandler := &mocks.Handler{}
+	for _, block := range blocks {
+		mockHandler.On("BlockSeen", mock.Anything, block).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, block).Return(nil).Once()
+	}
+
+	syncer := New(networkIdentifier, helper, mockHandler, cancel)
+	assert.NoError(t, syncer.Sync(ctx, -1, 6))
+	mockHandler.AssertExpectations(t)
+
+	// Syncing past the end of the archive fails
+	// instead of waiting for new blocks.
+	index := int64(7)
+	_, err = helper.Block(ctx, networkIdentifier, &types.PartialBlockIdentifier{Index: &index})
+	assert.ErrorIs(t, err, ErrArchiveReadFailed)
+	assert.Contains(t, err.Error(), ErrArchiveBlockNotFound.Error())
+}
+
+func TestSync_ArchivePrefetch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(dir)
+
+	directoryArchive, err := NewDirectoryArchive(dir)
+	assert.NoError(t, err)
+
+	blocks := createBlocks(0, 299, "")
+	for _, block := range blocks {
+		assert.NoError(t, directoryArchive.AddBlock(ctx, block))
+	}
+
+	archive := &prefetchArchive{DirectoryArchive: directoryArchive}
+	mockHandler := &mocks.Handler{}
+	mockHandler.On("BlockSeen", mock.Anything, mock.Anything).Return(nil)
+	mockHandler.On("BlockAdded", mock.Anything, mock.Anything).Return(nil)
+
+	syncer := New(networkIdentifier, NewArchiveHelper(archive), mockHandler, cancel)
+	assert.NoError(t, syncer.Sync(ctx, -1, 299))
+	mockHandler.AssertNumberOfCalls(t, "BlockAdded", len(blocks))
+
+	assert.Equal(t, [][2]int64{
+		{0, 127},
+		{128, 255},
+		{256, 299},
+	}, archive.hints)
+}
@@ -15,6 +15,8 @@
 package syncer
 
 import (
+	"time"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -65,3 +67,196 @@ func WithAdjustmentWindow(adjustmentWindow int64) Option {
 		s.adjustmentWindow = adjustmentWindow
 	}
 }
+
+// WithMaxCacheBytes caps the estimated number of bytes held by
+// fetched blocks waiting to be processed. Unlike WithCacheSize (which
+// is used to estimate an appropriate concurrency), this is enforced
+// by pausing block fetching while the cap is reached. Blocks already
+// being fetched when the cap is reached are still cached, so the cap
+// can be exceeded by up to the current concurrency.
+func WithMaxCacheBytes(maxBytes int) Option {
+	return func(s *Syncer) {
+		s.maxCacheBytes = maxBytes
+	}
+}
+
+// WithCheckpointStore provides the syncer with a CheckpointStore
+// to persist sync progress to after each block is processed. If
+// store is a HandlerCheckpointStore, the Handler is responsible
+// for saving the *Checkpoint of each block it processes.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(s *Syncer) {
+		s.checkpointStore = store
+
+		handlerStore, ok := store.(HandlerCheckpointStore)
+		s.handlerCheckpoints = ok && handlerStore.SavesCheckpointInHandler()
+	}
+}
+
+// WithAdaptiveConcurrency enables adaptive mode. In adaptive mode,
+// the syncer monitors the number of fetched blocks waiting to be
+// processed by the Handler and the Handler's block processing latency
+// to adjust fetch concurrency (and the memory available to the block
+// cache) so that memory usage stays under memoryBudget.
+func WithAdaptiveConcurrency(memoryBudget int) Option {
+	return func(s *Syncer) {
+		s.adaptive = true
+		s.memoryBudget = memoryBudget
+	}
+}
+
+// WithMaxReorgDepth sets the maximum number of consecutive
+// blocks the syncer will remove during a reorg before returning
+// ErrMaxReorgDepthExceeded. By default, there is no limit.
+func WithMaxReorgDepth(depth int) Option {
+	return func(s *Syncer) {
+		s.maxReorgDepth = depth
+	}
+}
+
+// WithBlockEvents drives the syncer with /events/blocks instead of
+// walking block indices (if the Helper implements EventsHelper).
+// This allows syncing against pruned Rosetta implementations. limit
+// is the maximum number of events to request at once (if 0,
+// the implementation's default is used).
+func WithBlockEvents(limit int64) Option {
+	return func(s *Syncer) {
+		s.useEvents = true
+		s.eventsLimit = limit
+	}
+}
+
+// WithBlockAssertion asserts each fetched block with assertion
+// using workers goroutines (separate from the goroutines used to
+// fetch blocks). If workers is not positive, DefaultStageWorkers
+// is used.
+func WithBlockAssertion(assertion BlockAssertion, workers int) Option {
+	return func(s *Syncer) {
+		if workers <= 0 {
+			workers = DefaultStageWorkers
+		}
+
+		s.assertion = assertion
+		s.assertWorkers = workers
+	}
+}
+
+// WithPreprocessor preprocesses each fetched block with preprocessor
+// using workers goroutines (separate from the goroutines used to
+// fetch and assert blocks). If workers is not positive,
+// DefaultStageWorkers is used.
+func WithPreprocessor(preprocessor Preprocessor, workers int) Option {
+	return func(s *Syncer) {
+		if workers <= 0 {
+			workers = DefaultStageWorkers
+		}
+
+		s.preprocessor = preprocessor
+		s.preprocessWorkers = workers
+	}
+}
+
+// WithStallDetection starts a watchdog while syncing that invokes
+// callback (if not nil) when no block has been processed for timeout
+// (ex: the node is stuck). If recovery is true, the syncer also
+// recycles Helper connections (if the Helper implements
+// ConnectionRecycler) and restarts its fetch workers.
+//
+// Recovery cannot interrupt a Handler that never returns, but
+// the callback is still invoked.
+func WithStallDetection(
+	timeout time.Duration,
+	callback StallCallback,
+	recovery bool,
+) Option {
+	return func(s *Syncer) {
+		s.stallTimeout = timeout
+		s.stallCallback = callback
+		s.stallRecovery = recovery
+	}
+}
+
+// WithProgressHandler provides the syncer with a ProgressHandler
+// that is invoked with a *Progress snapshot every interval. If
+// interval is not positive, DefaultProgressInterval is used.
+func WithProgressHandler(handler ProgressHandler, interval time.Duration) Option {
+	return func(s *Syncer) {
+		if interval <= 0 {
+			interval = DefaultProgressInterval
+		}
+
+		s.progressHandler = handler
+		s.progressInterval = interval
+	}
+}
+
+// MultiOption is used to overwrite default values in
+// MultiSyncer construction. Any MultiOption not provided
+// falls back to the default value.
+type MultiOption func(m *MultiSyncer)
+
+// WithGlobalConcurrency overrides the default maximum number
+// of blocks fetched concurrently across all networks.
+func WithGlobalConcurrency(concurrency int64) MultiOption {
+	return func(m *MultiSyncer) {
+		m.globalConcurrency = concurrency
+	}
+}
+
+// WithGlobalMemoryBudget splits memoryBudget evenly across the
+// block caches of all networks (each syncer is run in adaptive
+// mode with its share of the budget).
+func WithGlobalMemoryBudget(memoryBudget int) MultiOption {
+	return func(m *MultiSyncer) {
+		m.memoryBudget = memoryBudget
+	}
+}
+
+// WithSyncerOptions provides options to the
+// syncers of all networks.
+func WithSyncerOptions(options ...Option) MultiOption {
+	return func(m *MultiSyncer) {
+		m.syncerOptions = append(m.syncerOptions, options...)
+	}
+}
+
+// WithNetworkOptions provides options to the syncer of a
+// single network. These options are applied after any
+// options provided with WithSyncerOptions.
+func WithNetworkOptions(network *types.NetworkIdentifier, options ...Option) MultiOption {
+	return func(m *MultiSyncer) {
+		key := types.Hash(network)
+		m.networkOptions[key] = append(m.networkOptions[key], options...)
+	}
+}
+
+// MempoolOption is used to overwrite default values in
+// MempoolSyncer construction. Any MempoolOption not provided
+// falls back to the default value.
+type MempoolOption func(m *MempoolSyncer)
+
+// WithMempoolPollInterval overrides the default
+// interval between mempool polls.
+func WithMempoolPollInterval(interval time.Duration) MempoolOption {
+	return func(m *MempoolSyncer) {
+		m.pollInterval = interval
+	}
+}
+
+// WithMempoolConcurrency overrides the default number of
+// mempool transactions fetched concurrently.
+func WithMempoolConcurrency(concurrency int) MempoolOption {
+	return func(m *MempoolSyncer) {
+		m.concurrency = concurrency
+	}
+}
+
+// WithReplacementKeys overrides the default ReplacementKeys
+// (which considers transactions spending the same coin to be
+// conflicting). On account-based chains, this is typically
+// the sender and nonce of a transaction.
+func WithReplacementKeys(keys ReplacementKeys) MempoolOption {
+	return func(m *MempoolSyncer) {
+		m.replacementKeys = keys
+	}
+}
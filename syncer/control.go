@@ -0,0 +1,106 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+)
+
+// Pause stops the syncer from processing blocks until Resume
+// is called. Pause is safe to call while Sync is running. Blocks
+// already being fetched are still fetched (so a short burst of
+// BlockSeen calls may still occur), but no blocks are added or
+// removed while paused.
+func (s *Syncer) Pause() {
+	s.controlLock.Lock()
+	defer s.controlLock.Unlock()
+
+	if s.resumed == nil {
+		s.resumed = make(chan struct{})
+	}
+}
+
+// Resume resumes a paused syncer. Calling
+// Resume on a syncer that is not paused has
+// no effect.
+func (s *Syncer) Resume() {
+	s.controlLock.Lock()
+	defer s.controlLock.Unlock()
+
+	if s.resumed != nil {
+		close(s.resumed)
+		s.resumed = nil
+	}
+}
+
+// Paused returns a boolean indicating
+// if the syncer is paused.
+func (s *Syncer) Paused() bool {
+	s.controlLock.Lock()
+	defer s.controlLock.Unlock()
+
+	return s.resumed != nil
+}
+
+// SetTargetIndex updates the index the syncer will sync to (if -1,
+// the syncer syncs indefinitely). SetTargetIndex is safe to call while
+// Sync is running. If the target is lowered below the next index the
+// syncer will process, Sync returns once the block being processed
+// is handled.
+//
+// Sync and SyncRanges overwrite the target when invoked, so
+// SetTargetIndex should be called after syncing has started.
+func (s *Syncer) SetTargetIndex(index int64) {
+	s.controlLock.Lock()
+	defer s.controlLock.Unlock()
+
+	s.targetIndex = index
+}
+
+// TargetIndex returns the index the syncer will sync to
+// (or -1 if the syncer is syncing indefinitely).
+func (s *Syncer) TargetIndex() int64 {
+	s.controlLock.Lock()
+	defer s.controlLock.Unlock()
+
+	return s.targetIndex
+}
+
+// targetReached returns a boolean indicating if the
+// syncer has processed all blocks up to the target index.
+func (s *Syncer) targetReached() bool {
+	targetIndex := s.TargetIndex()
+
+	return targetIndex != -1 && s.nextIndex > targetIndex
+}
+
+// waitIfPaused blocks until the syncer is
+// resumed (if paused) or ctx is canceled.
+func (s *Syncer) waitIfPaused(ctx context.Context) error {
+	s.controlLock.Lock()
+	resumed := s.resumed
+	s.controlLock.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestPauseResume(t *testing.T) {
+	syncer := New(networkIdentifier, &mocks.Helper{}, &mocks.Handler{}, func() {})
+	assert.False(t, syncer.Paused())
+	assert.NoError(t, syncer.waitIfPaused(context.Background()))
+
+	syncer.Pause()
+	syncer.Pause()
+	assert.True(t, syncer.Paused())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, syncer.waitIfPaused(ctx))
+
+	done := make(chan error)
+	go func() {
+		done <- syncer.waitIfPaused(context.Background())
+	}()
+
+	syncer.Resume()
+	syncer.Resume()
+	assert.NoError(t, <-done)
+	assert.False(t, syncer.Paused())
+}
+
+func TestTargetIndex(t *testing.T) {
+	syncer := New(networkIdentifier, &mocks.Helper{}, &mocks.Handler{}, func() {})
+	assert.Equal(t, int64(-1), syncer.TargetIndex())
+	syncer.nextIndex = 10
+	assert.False(t, syncer.targetReached())
+
+	syncer.SetTargetIndex(10)
+	assert.False(t, syncer.targetReached())
+
+	syncer.SetTargetIndex(9)
+	assert.True(t, syncer.targetReached())
+}
+
+func TestSync_Control(t *testing.T) {
+	blocks := createBlocks(0, 9, "")
+	networkStatus := &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[9].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}
+
+	mockSyncer := func(
+		cancel context.CancelFunc,
+		maybe bool,
+	) (*Syncer, *mocks.Helper, *mocks.Handler) {
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		for _, block := range blocks {
+			index := block.BlockIdentifier.Index
+			b := block
+			fetch := mockHelper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(b, nil)
+			seen := mockHandler.On("BlockSeen", mock.Anything, b).Return(nil)
+			if maybe {
+				fetch.Maybe()
+				seen.Maybe()
+			} else {
+				fetch.Once()
+				seen.Once()
+			}
+		}
+
+		return New(networkIdentifier, mockHelper, mockHandler, cancel), mockHelper, mockHandler
+	}
+
+	t.Run("pause and resume", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		syncer, mockHelper, mockHandler := mockSyncer(cancel, false)
+
+		added := 0
+		addedWhilePaused := make(chan int, 1)
+		for _, block := range blocks {
+			b := block
+			mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Run(
+				func(args mock.Arguments) {
+					added++
+					if b.BlockIdentifier.Index != 2 {
+						return
+					}
+
+					syncer.Pause()
+					go func() {
+						time.Sleep(100 * time.Millisecond)
+						addedWhilePaused <- added
+						syncer.Resume()
+					}()
+				},
+			).Once()
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 9))
+		assert.Equal(t, 3, <-addedWhilePaused)
+		assert.Equal(t, 10, added)
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("lower target index", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		syncer, _, mockHandler := mockSyncer(cancel, true)
+		for _, block := range blocks[:5] {
+			b := block
+			mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Run(
+				func(args mock.Arguments) {
+					if b.BlockIdentifier.Index == 3 {
+						syncer.SetTargetIndex(4)
+					}
+				},
+			).Once()
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, -1))
+		mockHandler.AssertExpectations(t)
+		assert.Equal(t, int64(5), syncer.nextIndex)
+	})
+
+	t.Run("raise target index", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		syncer, _, mockHandler := mockSyncer(cancel, true)
+		for _, block := range blocks[:7] {
+			b := block
+			mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Run(
+				func(args mock.Arguments) {
+					if b.BlockIdentifier.Index == 2 {
+						syncer.SetTargetIndex(6)
+					}
+				},
+			).Once()
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 3))
+		mockHandler.AssertExpectations(t)
+		assert.Equal(t, int64(7), syncer.nextIndex)
+	})
+}
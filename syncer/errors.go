@@ -51,6 +51,87 @@ var (
 	ErrBlocksProcessMultipleFailed = errors.New("unable to process blocks")
 	ErrSetStartIndexFailed         = errors.New("unable to set start index")
 	ErrNextSyncableRangeFailed     = errors.New("unable to get next syncable range")
+	ErrLoadCheckpointFailed        = errors.New("unable to load checkpoint")
+	ErrSaveCheckpointFailed        = errors.New("unable to save checkpoint")
+
+	// ErrMaxReorgDepthExceeded is returned when a reorg
+	// would remove more blocks than the configured
+	// max reorg depth.
+	ErrMaxReorgDepthExceeded = errors.New("max reorg depth exceeded")
+
+	ErrFetchAncestorFailed = errors.New("unable to fetch ancestor block")
+
+	// ErrEventsUnsupported is returned when the first request
+	// to /events/blocks fails because it is not implemented. When
+	// this occurs, the syncer falls back to walking block indices.
+	ErrEventsUnsupported = errors.New("/events/blocks is not supported")
+
+	ErrFetchEventsFailed = errors.New("unable to fetch block events")
+
+	ErrBlockAssertionFailed  = errors.New("block assertion failed")
+	ErrPreprocessBlockFailed = errors.New("unable to preprocess block")
+
+	// ErrSyncStalled is returned when a range is abandoned
+	// because no block was processed for the stall timeout.
+	ErrSyncStalled = errors.New("sync stalled")
+
+	ErrRecycleConnectionsFailed = errors.New("unable to recycle connections")
+
+	// ErrBlockRangesMissing is returned when
+	// SyncRanges is invoked without any ranges.
+	ErrBlockRangesMissing = errors.New("no block ranges provided")
+
+	// ErrBlockRangeInvalid is returned when a *BlockRange
+	// has a negative start or ends before it starts.
+	ErrBlockRangeInvalid = errors.New("block range is invalid")
+
+	// ErrBlockRangesOverlap is returned when ranges
+	// provided to SyncRanges overlap.
+	ErrBlockRangesOverlap = errors.New("block ranges overlap")
+
+	ErrRangeCallbackFailed = errors.New("block range callback failed")
+
+	// ErrNetworksMissing is returned when a
+	// MultiSyncer is created without any networks.
+	ErrNetworksMissing = errors.New("no networks provided")
+
+	// ErrDuplicateNetwork is returned when a MultiSyncer
+	// is created with the same network more than once.
+	ErrDuplicateNetwork = errors.New("duplicate network")
+
+	// ErrNetworkNotFound is returned when a
+	// MultiSyncer does not sync a network.
+	ErrNetworkNotFound = errors.New("network not found")
+
+	ErrNetworkSyncFailed = errors.New("unable to sync network")
+
+	ErrFetchMempoolFailed            = errors.New("unable to fetch mempool")
+	ErrFetchMempoolTransactionFailed = errors.New("unable to fetch mempool transaction")
+	ErrMempoolHandlerFailed          = errors.New("unable to handle mempool change")
+
+	// ErrMempoolTransactionNotFound is returned by a MempoolHelper
+	// when a transaction is no longer in the mempool.
+	ErrMempoolTransactionNotFound = errors.New("mempool transaction not found")
+
+	// ErrArchiveEmpty is returned when a BlockArchive
+	// does not contain any blocks.
+	ErrArchiveEmpty = errors.New("archive is empty")
+
+	// ErrArchiveBlockNotFound is returned when a
+	// requested block is not in a BlockArchive.
+	ErrArchiveBlockNotFound = errors.New("block not found in archive")
+
+	// ErrArchiveHashMismatch is returned when an archived
+	// block does not have the requested hash.
+	ErrArchiveHashMismatch = errors.New("archived block hash mismatch")
+
+	// ErrArchiveReadFailed is returned when a
+	// BlockArchive cannot be read.
+	ErrArchiveReadFailed = errors.New("unable to read archive")
+
+	// ErrArchiveWriteFailed is returned when a block
+	// cannot be written to a DirectoryArchive.
+	ErrArchiveWriteFailed = errors.New("unable to write archive")
 )
 
 // Err takes an error as an argument and returns
@@ -69,6 +150,33 @@ func Err(err error) bool {
 		ErrBlocksProcessMultipleFailed,
 		ErrSetStartIndexFailed,
 		ErrNextSyncableRangeFailed,
+		ErrLoadCheckpointFailed,
+		ErrSaveCheckpointFailed,
+		ErrMaxReorgDepthExceeded,
+		ErrFetchAncestorFailed,
+		ErrEventsUnsupported,
+		ErrFetchEventsFailed,
+		ErrBlockAssertionFailed,
+		ErrPreprocessBlockFailed,
+		ErrSyncStalled,
+		ErrRecycleConnectionsFailed,
+		ErrBlockRangesMissing,
+		ErrBlockRangeInvalid,
+		ErrBlockRangesOverlap,
+		ErrRangeCallbackFailed,
+		ErrNetworksMissing,
+		ErrDuplicateNetwork,
+		ErrNetworkNotFound,
+		ErrNetworkSyncFailed,
+		ErrFetchMempoolFailed,
+		ErrFetchMempoolTransactionFailed,
+		ErrMempoolHandlerFailed,
+		ErrMempoolTransactionNotFound,
+		ErrArchiveEmpty,
+		ErrArchiveBlockNotFound,
+		ErrArchiveHashMismatch,
+		ErrArchiveReadFailed,
+		ErrArchiveWriteFailed,
 	}
 
 	return utils.FindError(syncerErrors, err)
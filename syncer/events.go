@@ -0,0 +1,270 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/client"
+	"github.com/coinbase/rosetta-sdk-go/fetcher"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// addEventBlock fetches and adds the block referenced
+// by a types.ADDED *types.BlockEvent. addEventBlock
+// returns true if the Handler was invoked.
+func (s *Syncer) addEventBlock(
+	ctx context.Context,
+	event *types.BlockEvent,
+) (bool, error) {
+	identifier := event.BlockIdentifier
+	block, err := s.helper.Block(
+		ctx,
+		s.network,
+		types.ConstructPartialBlockIdentifier(identifier),
+	)
+	if err != nil {
+		return false, fmt.Errorf("%w %d: %v", ErrFetchBlockFailed, identifier.Index, err)
+	}
+
+	// A block may be omitted by an implementation
+	// that does not populate all indices.
+	if block == nil {
+		s.nextIndex = identifier.Index + 1
+		return false, nil
+	}
+
+	br := &blockResult{index: identifier.Index, block: block}
+	if err := s.prepareBlock(ctx, br); err != nil {
+		return false, err
+	}
+
+	pastBlocks := s.appendPastBlock(s.pastBlocks, block.BlockIdentifier)
+	nextIndex := block.BlockIdentifier.Index + 1
+	if err := s.addBlock(
+		s.withCheckpoint(ctx, pastBlocks, nextIndex, event.Sequence+1),
+		br,
+	); err != nil {
+		return false, err
+	}
+
+	s.pastBlocks = pastBlocks
+	s.nextIndex = nextIndex
+	s.reorgDepth = 0
+	s.blocksSynced++
+
+	return true, nil
+}
+
+// removeEventBlocks removes all blocks referenced by a
+// consecutive run of types.REMOVED *types.BlockEvents (ordered
+// from the tip). If the Handler implements BatchRemovalHandler,
+// all blocks are removed with a single call to BlocksRemoved.
+func (s *Syncer) removeEventBlocks(
+	ctx context.Context,
+	events []*types.BlockEvent,
+) error {
+	identifiers := make([]*types.BlockIdentifier, len(events))
+	for i, event := range events {
+		if types.Hash(s.genesisBlock) == types.Hash(event.BlockIdentifier) {
+			return ErrCannotRemoveGenesisBlock
+		}
+
+		identifiers[i] = event.BlockIdentifier
+	}
+
+	if err := s.checkReorgDepth(s.reorgDepth + len(identifiers)); err != nil {
+		return err
+	}
+
+	// Compute the sync state after each removal so it
+	// can be attached to each Handler invocation.
+	pastBlocks := s.pastBlocks
+	nextIndex := s.nextIndex
+	checkpointCtxs := make([]context.Context, len(events))
+	for i, event := range events {
+		if len(pastBlocks) > 0 &&
+			types.Hash(pastBlocks[len(pastBlocks)-1]) == types.Hash(event.BlockIdentifier) {
+			pastBlocks = pastBlocks[:len(pastBlocks)-1]
+		}
+		nextIndex = event.BlockIdentifier.Index
+		checkpointCtxs[i] = s.withCheckpoint(ctx, pastBlocks, nextIndex, event.Sequence+1)
+	}
+
+	s.beginReorg()
+	if batchHandler, ok := s.handler.(BatchRemovalHandler); ok {
+		if err := batchHandler.BlocksRemoved(
+			checkpointCtxs[len(checkpointCtxs)-1],
+			identifiers,
+		); err != nil {
+			return err
+		}
+	} else {
+		for i, identifier := range identifiers {
+			if err := s.removeBlock(checkpointCtxs[i], identifier); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.pastBlocks = pastBlocks
+	s.nextIndex = nextIndex
+	s.reorgDepth += len(identifiers)
+	s.orphansProcessed += int64(len(identifiers))
+
+	return nil
+}
+
+// processEvents processes a page of *types.BlockEvent. Events that
+// reference blocks before s.nextIndex (or removals of blocks that were
+// never added) are skipped. processEvents returns true if an event
+// past the target index was encountered.
+func (s *Syncer) processEvents(
+	ctx context.Context,
+	events []*types.BlockEvent,
+) (bool, error) {
+	for i := 0; i < len(events); i++ {
+		if err := s.waitIfPaused(ctx); err != nil {
+			return false, err
+		}
+
+		endIndex := s.TargetIndex()
+		event := events[i]
+		handled := false
+		switch event.Type {
+		case types.ADDED:
+			if endIndex != -1 && event.BlockIdentifier.Index > endIndex {
+				return true, nil
+			}
+
+			if event.BlockIdentifier.Index >= s.nextIndex {
+				added, err := s.addEventBlock(ctx, event)
+				if err != nil {
+					return false, fmt.Errorf("%w: %v", ErrBlockProcessFailed, err)
+				}
+				handled = added
+			}
+		case types.REMOVED:
+			// Collect all consecutive removals so they
+			// can be handled together.
+			removed := []*types.BlockEvent{}
+			pastBlocks := s.pastBlocks
+			nextIndex := s.nextIndex
+			for ; i < len(events) && events[i].Type == types.REMOVED; i++ {
+				identifier := events[i].BlockIdentifier
+
+				// Removals of blocks that are not the synced tip
+				// were never added (so they must be ignored instead
+				// of rewinding nextIndex). Removals past the oldest
+				// block in pastBlocks can only be checked by index.
+				if len(pastBlocks) > 0 {
+					if types.Hash(pastBlocks[len(pastBlocks)-1]) != types.Hash(identifier) {
+						continue
+					}
+
+					pastBlocks = pastBlocks[:len(pastBlocks)-1]
+				} else if identifier.Index >= nextIndex {
+					continue
+				}
+
+				nextIndex = identifier.Index
+				removed = append(removed, events[i])
+			}
+			i--
+			event = events[i]
+
+			if len(removed) > 0 {
+				if err := s.removeEventBlocks(ctx, removed); err != nil {
+					return false, fmt.Errorf("%w: %v", ErrBlockProcessFailed, err)
+				}
+
+				// The Handler was only provided with the sync state
+				// of the last event if it was a removal.
+				handled = removed[len(removed)-1] == event
+			}
+		}
+
+		s.eventSequence = event.Sequence + 1
+		if err := s.saveCheckpoint(ctx, handled); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// eventsUnsupported returns true if err indicates
+// that /events/blocks is not implemented.
+func eventsUnsupported(err error) bool {
+	return errors.Is(err, ErrEventsUnsupported) ||
+		errors.Is(err, client.ErrNotImplemented) ||
+		errors.Is(err, fetcher.ErrRequestNotImplemented)
+}
+
+// syncEvents processes *types.BlockEvent from s.eventSequence
+// until there is an error or the target index is synced. If the first
+// request to /events/blocks fails because it is not implemented,
+// ErrEventsUnsupported is returned. Any other error is returned as
+// ErrFetchEventsFailed.
+func (s *Syncer) syncEvents(
+	ctx context.Context,
+	helper EventsHelper,
+) error {
+	var limit *int64
+	if s.eventsLimit > 0 {
+		limit = &s.eventsLimit
+	}
+
+	supported := false
+	for {
+		if err := s.waitIfPaused(ctx); err != nil {
+			return err
+		}
+
+		offset := s.eventSequence
+		_, events, err := helper.EventsBlocks(ctx, s.network, &offset, limit)
+		if err != nil {
+			if !supported && eventsUnsupported(err) {
+				return fmt.Errorf("%w: %v", ErrEventsUnsupported, err)
+			}
+
+			return fmt.Errorf("%w: %v", ErrFetchEventsFailed, err)
+		}
+		supported = true
+
+		done, err := s.processEvents(ctx, events)
+		if err != nil {
+			return err
+		}
+		s.reportProgress(ctx, false)
+
+		if done || s.targetReached() {
+			return nil
+		}
+
+		if len(events) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(defaultSyncSleep):
+		}
+	}
+}
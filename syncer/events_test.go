@@ -0,0 +1,306 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/coinbase/rosetta-sdk-go/client"
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type eventsHelper struct {
+	*mocks.Helper
+	*mocks.EventsHelper
+}
+
+func blockEvent(
+	sequence int64,
+	eventType types.BlockEventType,
+	block *types.Block,
+) *types.BlockEvent {
+	return &types.BlockEvent{
+		Sequence:        sequence,
+		BlockIdentifier: block.BlockIdentifier,
+		Type:            eventType,
+	}
+}
+
+func mockEventBlock(helper *eventsHelper, handler *mocks.Handler, block *types.Block) {
+	helper.Helper.On(
+		"Block",
+		mock.Anything,
+		networkIdentifier,
+		types.ConstructPartialBlockIdentifier(block.BlockIdentifier),
+	).Return(block, nil).Once()
+	handler.On("BlockSeen", mock.Anything, block).Return(nil).Once()
+	handler.On("BlockAdded", mock.Anything, block).Return(nil).Once()
+}
+
+func TestSync_Events(t *testing.T) {
+	blocks := createBlocks(0, 5, "")
+	reorgBlocks := createBlocks(2, 5, "other ")
+	reorgBlocks[0].ParentBlockIdentifier = blocks[1].BlockIdentifier
+
+	networkStatus := &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[5].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}
+
+	t.Run("add and remove", func(t *testing.T) {
+		ctx := context.Background()
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &mocks.Handler{}
+		store := &memoryCheckpointStore{}
+		syncer := New(
+			networkIdentifier,
+			helper,
+			handler,
+			func() {},
+			WithBlockEvents(10),
+			WithCheckpointStore(store),
+		)
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(networkStatus, nil).Once()
+		offset := int64(0)
+		limit := int64(10)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, &limit).Return(
+			int64(3),
+			[]*types.BlockEvent{
+				blockEvent(0, types.ADDED, blocks[0]),
+				blockEvent(1, types.ADDED, blocks[1]),
+				blockEvent(2, types.ADDED, blocks[2]),
+				blockEvent(3, types.ADDED, blocks[3]),
+			},
+			nil,
+		).Once()
+		nextOffset := int64(4)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &nextOffset, &limit).Return(
+			int64(9),
+			[]*types.BlockEvent{
+				blockEvent(4, types.REMOVED, blocks[3]),
+				blockEvent(5, types.REMOVED, blocks[2]),
+				blockEvent(6, types.ADDED, reorgBlocks[0]),
+				blockEvent(7, types.ADDED, reorgBlocks[1]),
+				blockEvent(8, types.ADDED, reorgBlocks[2]),
+				blockEvent(9, types.ADDED, reorgBlocks[3]),
+			},
+			nil,
+		).Once()
+
+		for _, block := range blocks[:4] {
+			mockEventBlock(helper, handler, block)
+		}
+		handler.On("BlockRemoved", mock.Anything, blocks[3].BlockIdentifier).Return(nil).Once()
+		handler.On("BlockRemoved", mock.Anything, blocks[2].BlockIdentifier).Return(nil).Once()
+		for _, block := range reorgBlocks[:3] {
+			mockEventBlock(helper, handler, block)
+		}
+
+		// Events after endIndex are not processed.
+		assert.NoError(t, syncer.Sync(ctx, -1, 4))
+		helper.Helper.AssertExpectations(t)
+		helper.EventsHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+
+		assert.Equal(t, int64(5), syncer.nextIndex)
+		assert.Equal(t, int64(9), syncer.eventSequence)
+		assert.Equal(t, int64(9), store.checkpoint.EventSequence)
+		assert.Equal(t, reorgBlocks[2].BlockIdentifier, store.checkpoint.LastSynced)
+	})
+
+	t.Run("batch removal", func(t *testing.T) {
+		ctx := context.Background()
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &batchHandler{Handler: &mocks.Handler{}}
+		syncer := New(networkIdentifier, helper, handler, func() {}, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(networkStatus, nil).Once()
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(5),
+			[]*types.BlockEvent{
+				blockEvent(0, types.ADDED, blocks[0]),
+				blockEvent(1, types.ADDED, blocks[1]),
+				blockEvent(2, types.ADDED, blocks[2]),
+				blockEvent(3, types.REMOVED, blocks[2]),
+				blockEvent(4, types.REMOVED, blocks[1]),
+				blockEvent(5, types.ADDED, blocks[1]),
+				blockEvent(6, types.ADDED, blocks[2]),
+			},
+			nil,
+		).Once()
+
+		for _, block := range blocks[:3] {
+			mockEventBlock(helper, handler.Handler, block)
+		}
+		mockEventBlock(helper, handler.Handler, blocks[1])
+		mockEventBlock(helper, handler.Handler, blocks[2])
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 2))
+		helper.Helper.AssertExpectations(t)
+		helper.EventsHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+		assert.Equal(t, [][]*types.BlockIdentifier{
+			{blocks[2].BlockIdentifier, blocks[1].BlockIdentifier},
+		}, handler.removed)
+		assert.Equal(t, []*types.BlockIdentifier{
+			blocks[0].BlockIdentifier,
+			blocks[1].BlockIdentifier,
+			blocks[2].BlockIdentifier,
+		}, syncer.pastBlocks)
+	})
+
+	t.Run("cannot remove genesis", func(t *testing.T) {
+		ctx := context.Background()
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &mocks.Handler{}
+		syncer := New(networkIdentifier, helper, handler, func() {}, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(networkStatus, nil).Once()
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(1),
+			[]*types.BlockEvent{
+				blockEvent(0, types.ADDED, blocks[0]),
+				blockEvent(1, types.REMOVED, blocks[0]),
+			},
+			nil,
+		).Once()
+		mockEventBlock(helper, handler, blocks[0])
+
+		err := syncer.Sync(ctx, -1, 3)
+		assert.True(t, errors.Is(err, ErrBlockProcessFailed))
+		assert.Contains(t, err.Error(), ErrCannotRemoveGenesisBlock.Error())
+		assert.Equal(t, int64(1), syncer.eventSequence)
+	})
+
+	t.Run("fallback to indices", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &mocks.Handler{}
+		syncer := New(networkIdentifier, helper, handler, cancel, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(0),
+			nil,
+			fmt.Errorf("%w: code: 501", client.ErrNotImplemented),
+		).Once()
+		for i := int64(0); i <= 2; i++ {
+			index := i
+			block := blocks[index]
+			helper.Helper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(block, nil).Once()
+			handler.On("BlockSeen", mock.Anything, block).Return(nil).Once()
+			handler.On("BlockAdded", mock.Anything, block).Return(nil).Once()
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 2))
+		helper.Helper.AssertExpectations(t)
+		helper.EventsHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+		assert.Equal(t, int64(3), syncer.nextIndex)
+	})
+
+	t.Run("ignore removal of block never added", func(t *testing.T) {
+		ctx := context.Background()
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &mocks.Handler{}
+		syncer := New(networkIdentifier, helper, handler, func() {}, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(networkStatus, nil).Once()
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(3),
+			[]*types.BlockEvent{
+				blockEvent(0, types.ADDED, blocks[0]),
+				blockEvent(1, types.ADDED, blocks[1]),
+				blockEvent(2, types.ADDED, blocks[2]),
+				blockEvent(3, types.REMOVED, reorgBlocks[0]),
+			},
+			nil,
+		).Once()
+		for _, block := range blocks[:3] {
+			mockEventBlock(helper, handler, block)
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 2))
+		helper.Helper.AssertExpectations(t)
+		helper.EventsHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+		assert.Equal(t, int64(3), syncer.nextIndex)
+		assert.Equal(t, int64(4), syncer.eventSequence)
+	})
+
+	t.Run("first request failed", func(t *testing.T) {
+		ctx := context.Background()
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &mocks.Handler{}
+		syncer := New(networkIdentifier, helper, handler, func() {}, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(networkStatus, nil).Once()
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(0),
+			nil,
+			errors.New("unavailable"),
+		).Once()
+
+		// Only unimplemented endpoints fall back to indices.
+		err := syncer.Sync(ctx, -1, 3)
+		assert.True(t, errors.Is(err, ErrFetchEventsFailed))
+		assert.False(t, errors.Is(err, ErrEventsUnsupported))
+		helper.Helper.AssertExpectations(t)
+		helper.EventsHelper.AssertExpectations(t)
+	})
+
+	t.Run("fetch events failed", func(t *testing.T) {
+		ctx := context.Background()
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &mocks.Handler{}
+		syncer := New(networkIdentifier, helper, handler, func() {}, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(networkStatus, nil).Once()
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(0),
+			[]*types.BlockEvent{blockEvent(0, types.ADDED, blocks[0])},
+			nil,
+		).Once()
+		nextOffset := int64(1)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &nextOffset, (*int64)(nil)).Return(
+			int64(0),
+			nil,
+			errors.New("unavailable"),
+		).Once()
+		mockEventBlock(helper, handler, blocks[0])
+
+		err := syncer.Sync(ctx, -1, 3)
+		assert.True(t, errors.Is(err, ErrFetchEventsFailed))
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// handlerV2Adapter adapts a HandlerV2 to a Handler. When
+// used by the syncer, the HandlerV2 is invoked directly with
+// a populated *BlockInfo. Otherwise, an empty *BlockInfo
+// is provided.
+type handlerV2Adapter struct {
+	handler HandlerV2
+}
+
+// AdaptHandlerV2 returns a Handler that invokes handler. The
+// syncer detects adapted handlers and provides them with a
+// *BlockInfo for each invocation.
+func AdaptHandlerV2(handler HandlerV2) Handler {
+	return &handlerV2Adapter{handler: handler}
+}
+
+func (h *handlerV2Adapter) BlockSeen(ctx context.Context, block *types.Block) error {
+	return h.handler.BlockSeen(ctx, block, &BlockInfo{})
+}
+
+func (h *handlerV2Adapter) BlockAdded(ctx context.Context, block *types.Block) error {
+	return h.handler.BlockAdded(ctx, block, &BlockInfo{})
+}
+
+func (h *handlerV2Adapter) BlockRemoved(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) error {
+	return h.handler.BlockRemoved(ctx, block, &BlockInfo{})
+}
+
+// atTip returns a boolean indicating if index is
+// at (or past) the last observed tip.
+func (s *Syncer) atTip(index int64) bool {
+	return s.tip != nil && index >= s.tip.Index
+}
+
+// deadlineHint returns the time by which the handler should
+// return to avoid delaying the syncer. When catching up, we
+// expect the next block to be fetched within the average fetch
+// latency. At tip, we expect to wait defaultSyncSleep.
+func (s *Syncer) deadlineHint(ctx context.Context, atTip bool) time.Time {
+	wait := s.fetchLatency
+	if atTip {
+		wait = defaultSyncSleep
+	}
+
+	if wait == 0 {
+		wait = defaultFetchSleep
+	}
+
+	deadline := time.Now().Add(wait)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+
+	return deadline
+}
+
+// blockInfo returns the *BlockInfo for the next added
+// or removed block (incrementing the handler sequence).
+func (s *Syncer) blockInfo(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) *BlockInfo {
+	s.handlerSequence++
+	atTip := s.atTip(block.Index)
+
+	return &BlockInfo{
+		Sequence: s.handlerSequence,
+		AtTip:    atTip,
+		Deadline: s.deadlineHint(ctx, atTip),
+	}
+}
+
+// beginReorg assigns a new reorg ID if no
+// blocks have been removed since the last block
+// was added.
+func (s *Syncer) beginReorg() {
+	if s.reorgDepth == 0 {
+		s.reorgID++
+	}
+}
+
+// seeBlock invokes the Handler with a seen block.
+func (s *Syncer) seeBlock(ctx context.Context, block *types.Block) error {
+	if h, ok := s.handler.(*handlerV2Adapter); ok {
+		return h.handler.BlockSeen(ctx, block, &BlockInfo{
+			AtTip: s.atTip(block.BlockIdentifier.Index),
+		})
+	}
+
+	return s.handler.BlockSeen(ctx, block)
+}
+
+// removeBlock invokes the Handler with a removed block.
+func (s *Syncer) removeBlock(ctx context.Context, block *types.BlockIdentifier) error {
+	if h, ok := s.handler.(*handlerV2Adapter); ok {
+		info := s.blockInfo(ctx, block)
+		info.ReorgID = s.reorgID
+		return h.handler.BlockRemoved(ctx, block, info)
+	}
+
+	return s.handler.BlockRemoved(ctx, block)
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type handlerV2Call struct {
+	method string
+	index  int64
+	info   *BlockInfo
+}
+
+type recordingHandlerV2 struct {
+	lock  sync.Mutex
+	seen  map[int64]*BlockInfo
+	calls []*handlerV2Call
+}
+
+func (h *recordingHandlerV2) BlockSeen(
+	ctx context.Context,
+	block *types.Block,
+	info *BlockInfo,
+) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.seen == nil {
+		h.seen = map[int64]*BlockInfo{}
+	}
+	h.seen[block.BlockIdentifier.Index] = info
+	return nil
+}
+
+func (h *recordingHandlerV2) BlockAdded(
+	ctx context.Context,
+	block *types.Block,
+	info *BlockInfo,
+) error {
+	h.calls = append(h.calls, &handlerV2Call{"added", block.BlockIdentifier.Index, info})
+	return nil
+}
+
+func (h *recordingHandlerV2) BlockRemoved(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+	info *BlockInfo,
+) error {
+	h.calls = append(h.calls, &handlerV2Call{"removed", block.Index, info})
+	return nil
+}
+
+func TestAdaptHandlerV2(t *testing.T) {
+	handler := &recordingHandlerV2{}
+	adapted := AdaptHandlerV2(handler)
+	block := createBlocks(0, 0, "")[0]
+
+	// Outside of the syncer, an empty *BlockInfo is provided.
+	assert.NoError(t, adapted.BlockSeen(context.Background(), block))
+	assert.NoError(t, adapted.BlockAdded(context.Background(), block))
+	assert.NoError(t, adapted.BlockRemoved(context.Background(), block.BlockIdentifier))
+	assert.Equal(t, &BlockInfo{}, handler.seen[0])
+	assert.Equal(t, []*handlerV2Call{
+		{"added", 0, &BlockInfo{}},
+		{"removed", 0, &BlockInfo{}},
+	}, handler.calls)
+}
+
+func TestSync_HandlerV2(t *testing.T) {
+	blocks := createBlocks(0, 4, "")
+
+	t.Run("catch up and tip", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHelper := &mocks.Helper{}
+		handler := &recordingHandlerV2{}
+		syncer := New(networkIdentifier, mockHelper, AdaptHandlerV2(handler), cancel)
+
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+			CurrentBlockIdentifier: blocks[4].BlockIdentifier,
+			GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+		}, nil)
+		for _, block := range blocks {
+			index := block.BlockIdentifier.Index
+			mockHelper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(block, nil).Once()
+		}
+
+		start := time.Now()
+		assert.NoError(t, syncer.Sync(ctx, -1, 4))
+		mockHelper.AssertExpectations(t)
+
+		assert.Len(t, handler.seen, 5)
+		assert.True(t, handler.seen[4].AtTip)
+		assert.False(t, handler.seen[3].AtTip)
+		assert.Equal(t, int64(0), handler.seen[3].Sequence)
+
+		assert.Len(t, handler.calls, 5)
+		for i, call := range handler.calls {
+			assert.Equal(t, "added", call.method)
+			assert.Equal(t, int64(i), call.index)
+			assert.Equal(t, int64(i+1), call.info.Sequence)
+			assert.Equal(t, i == 4, call.info.AtTip)
+			assert.Equal(t, int64(0), call.info.ReorgID)
+			assert.True(t, call.info.Deadline.After(start))
+		}
+	})
+
+	t.Run("reorg", func(t *testing.T) {
+		reorgBlocks := createBlocks(2, 3, "other ")
+		reorgBlocks[0].ParentBlockIdentifier = blocks[1].BlockIdentifier
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		helper := &eventsHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+		handler := &recordingHandlerV2{}
+		syncer := New(networkIdentifier, helper, AdaptHandlerV2(handler), func() {}, WithBlockEvents(0))
+
+		helper.Helper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+			CurrentBlockIdentifier: blocks[4].BlockIdentifier,
+			GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+		}, nil).Once()
+		offset := int64(0)
+		helper.EventsHelper.On("EventsBlocks", ctx, networkIdentifier, &offset, (*int64)(nil)).Return(
+			int64(8),
+			[]*types.BlockEvent{
+				blockEvent(0, types.ADDED, blocks[0]),
+				blockEvent(1, types.ADDED, blocks[1]),
+				blockEvent(2, types.ADDED, blocks[2]),
+				blockEvent(3, types.ADDED, blocks[3]),
+				blockEvent(4, types.REMOVED, blocks[3]),
+				blockEvent(5, types.REMOVED, blocks[2]),
+				blockEvent(6, types.ADDED, reorgBlocks[0]),
+				blockEvent(7, types.ADDED, reorgBlocks[1]),
+				blockEvent(8, types.ADDED, blocks[4]),
+			},
+			nil,
+		).Once()
+		fetched := append([]*types.Block{}, blocks[:4]...)
+		for _, block := range append(fetched, reorgBlocks...) {
+			helper.Helper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				types.ConstructPartialBlockIdentifier(block.BlockIdentifier),
+			).Return(block, nil).Once()
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 3))
+		helper.Helper.AssertExpectations(t)
+		helper.EventsHelper.AssertExpectations(t)
+
+		expected := []struct {
+			method  string
+			index   int64
+			reorgID int64
+		}{
+			{"added", 0, 0},
+			{"added", 1, 0},
+			{"added", 2, 0},
+			{"added", 3, 0},
+			{"removed", 3, 1},
+			{"removed", 2, 1},
+			{"added", 2, 0},
+			{"added", 3, 0},
+		}
+		assert.Len(t, handler.calls, len(expected))
+		for i, call := range handler.calls {
+			assert.Equal(t, expected[i].method, call.method)
+			assert.Equal(t, expected[i].index, call.index)
+			assert.Equal(t, expected[i].reorgID, call.info.ReorgID)
+			assert.Equal(t, int64(i+1), call.info.Sequence)
+
+			// The context deadline is later than the hint.
+			deadline, _ := ctx.Deadline()
+			assert.True(t, call.info.Deadline.Before(deadline))
+		}
+	})
+}
+
+func TestDeadlineHint(t *testing.T) {
+	syncer := New(networkIdentifier, &mocks.Helper{}, &mocks.Handler{}, func() {})
+
+	start := time.Now()
+	hint := syncer.deadlineHint(context.Background(), false)
+	assert.True(t, hint.Sub(start) >= defaultFetchSleep)
+	assert.True(t, hint.Sub(start) < defaultSyncSleep)
+
+	hint = syncer.deadlineHint(context.Background(), true)
+	assert.True(t, hint.Sub(start) >= defaultSyncSleep)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, deadline, syncer.deadlineHint(ctx, true))
+}
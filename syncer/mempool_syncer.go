@@ -0,0 +1,322 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// NewMempoolSyncer creates a new MempoolSyncer.
+func NewMempoolSyncer(
+	network *types.NetworkIdentifier,
+	helper MempoolHelper,
+	handler MempoolHandler,
+	options ...MempoolOption,
+) *MempoolSyncer {
+	m := &MempoolSyncer{
+		network:         network,
+		helper:          helper,
+		handler:         handler,
+		pollInterval:    DefaultMempoolPollInterval,
+		concurrency:     DefaultMempoolConcurrency,
+		replacementKeys: SpentCoinKeys,
+		notify:          make(chan struct{}, 1),
+		transactions:    map[string]*types.Transaction{},
+	}
+
+	// Override defaults with any provided options
+	for _, opt := range options {
+		opt(m)
+	}
+
+	return m
+}
+
+// SpentCoinKeys is the default ReplacementKeys. It returns the
+// identifiers of all coins spent by a transaction, so that
+// transactions spending the same coin are considered conflicting.
+func SpentCoinKeys(transaction *types.Transaction) []string {
+	keys := []string{}
+	for _, op := range transaction.Operations {
+		if op.CoinChange == nil || op.CoinChange.CoinIdentifier == nil {
+			continue
+		}
+
+		if op.CoinChange.CoinAction != types.CoinSpent {
+			continue
+		}
+
+		keys = append(keys, op.CoinChange.CoinIdentifier.Identifier)
+	}
+
+	return keys
+}
+
+// Notify triggers an immediate poll of the mempool. This can
+// be used to sync from push events (ex: a websocket subscription)
+// instead of waiting for the poll interval. Calling Notify while a
+// poll is already pending is a no-op.
+func (m *MempoolSyncer) Notify() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Transactions returns the identifiers of all transactions
+// currently tracked in the mempool (sorted by hash).
+func (m *MempoolSyncer) Transactions() []*types.TransactionIdentifier {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	identifiers := make([]*types.TransactionIdentifier, 0, len(m.transactions))
+	for _, transaction := range m.transactions {
+		identifiers = append(identifiers, transaction.TransactionIdentifier)
+	}
+
+	sort.Slice(identifiers, func(i, j int) bool {
+		return identifiers[i].Hash < identifiers[j].Hash
+	})
+
+	return identifiers
+}
+
+// fetchTransactions fetches all provided transactions from
+// the mempool with m.concurrency.
+func (m *MempoolSyncer) fetchTransactions(
+	ctx context.Context,
+	identifiers []*types.TransactionIdentifier,
+) ([]*types.Transaction, error) {
+	transactions := make([]*types.Transaction, len(identifiers))
+	indices := make(chan int)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(indices)
+		for i := range identifiers {
+			select {
+			case indices <- i:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+
+		return nil
+	})
+
+	for j := 0; j < m.concurrency; j++ {
+		g.Go(func() error {
+			for i := range indices {
+				transaction, err := m.helper.MempoolTransaction(gctx, m.network, identifiers[i])
+				if errors.Is(err, ErrMempoolTransactionNotFound) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf(
+						"%w %s: %v",
+						ErrFetchMempoolTransactionFailed,
+						identifiers[i].Hash,
+						err,
+					)
+				}
+
+				if transaction == nil {
+					return fmt.Errorf(
+						"%w %s: transaction is nil",
+						ErrFetchMempoolTransactionFailed,
+						identifiers[i].Hash,
+					)
+				}
+
+				transactions[i] = transaction
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Transactions evicted from the mempool after it
+	// was listed are skipped (they will not be in the
+	// mempool on the next poll).
+	found := make([]*types.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		if transaction != nil {
+			found = append(found, transaction)
+		}
+	}
+
+	return found, nil
+}
+
+// setTransaction tracks transaction in m.transactions.
+func (m *MempoolSyncer) setTransaction(transaction *types.Transaction) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.transactions[transaction.TransactionIdentifier.Hash] = transaction
+}
+
+// deleteTransaction stops tracking the
+// transaction with hash in m.transactions.
+func (m *MempoolSyncer) deleteTransaction(hash string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.transactions, hash)
+}
+
+// Poll fetches the contents of the mempool once and invokes the
+// MempoolHandler for all transactions that were removed, replaced,
+// or added since the last poll (in that order). Concurrent
+// invocations of Poll are processed one at a time.
+func (m *MempoolSyncer) Poll(ctx context.Context) error {
+	// Only Poll modifies m.transactions, so it can be
+	// read without holding m.lock while polling (m.lock
+	// is only held to modify it).
+	m.pollLock.Lock()
+	defer m.pollLock.Unlock()
+
+	identifiers, err := m.helper.Mempool(ctx, m.network)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchMempoolFailed, err)
+	}
+
+	current := map[string]struct{}{}
+	newIdentifiers := []*types.TransactionIdentifier{}
+	for _, identifier := range identifiers {
+		if _, ok := current[identifier.Hash]; ok {
+			continue
+		}
+
+		current[identifier.Hash] = struct{}{}
+		if _, ok := m.transactions[identifier.Hash]; !ok {
+			newIdentifiers = append(newIdentifiers, identifier)
+		}
+	}
+
+	removed := []string{}
+	for hash := range m.transactions {
+		if _, ok := current[hash]; !ok {
+			removed = append(removed, hash)
+		}
+	}
+	sort.Strings(removed)
+
+	added, err := m.fetchTransactions(ctx, newIdentifiers)
+	if err != nil {
+		return err
+	}
+
+	// Determine which removed transactions were
+	// replaced by added transactions.
+	removedKeys := map[string]string{}
+	for _, hash := range removed {
+		for _, key := range m.replacementKeys(m.transactions[hash]) {
+			removedKeys[key] = hash
+		}
+	}
+
+	replacements := map[string]*types.Transaction{}
+	replacing := map[string]struct{}{}
+	for _, transaction := range added {
+		for _, key := range m.replacementKeys(transaction) {
+			hash, ok := removedKeys[key]
+			if !ok {
+				continue
+			}
+
+			if _, ok := replacements[hash]; ok {
+				continue
+			}
+
+			replacements[hash] = transaction
+			replacing[transaction.TransactionIdentifier.Hash] = struct{}{}
+			break
+		}
+	}
+
+	for _, hash := range removed {
+		if _, ok := replacements[hash]; ok {
+			continue
+		}
+
+		if err := m.handler.TransactionRemoved(
+			ctx,
+			m.transactions[hash].TransactionIdentifier,
+		); err != nil {
+			return fmt.Errorf("%w: %v", ErrMempoolHandlerFailed, err)
+		}
+		m.deleteTransaction(hash)
+	}
+
+	for _, hash := range removed {
+		transaction, ok := replacements[hash]
+		if !ok {
+			continue
+		}
+
+		if err := m.handler.TransactionReplaced(
+			ctx,
+			m.transactions[hash].TransactionIdentifier,
+			transaction,
+		); err != nil {
+			return fmt.Errorf("%w: %v", ErrMempoolHandlerFailed, err)
+		}
+		m.deleteTransaction(hash)
+		m.setTransaction(transaction)
+	}
+
+	for _, transaction := range added {
+		if _, ok := replacing[transaction.TransactionIdentifier.Hash]; ok {
+			continue
+		}
+
+		if err := m.handler.TransactionAdded(ctx, transaction); err != nil {
+			return fmt.Errorf("%w: %v", ErrMempoolHandlerFailed, err)
+		}
+		m.setTransaction(transaction)
+	}
+
+	return nil
+}
+
+// Sync polls the mempool until there is an error or
+// the context is canceled.
+func (m *MempoolSyncer) Sync(ctx context.Context) error {
+	for {
+		if err := m.Poll(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.notify:
+		case <-time.After(m.pollInterval):
+		}
+	}
+}
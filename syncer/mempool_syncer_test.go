@@ -0,0 +1,205 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func mempoolTransaction(hash string, spentCoin string) *types.Transaction {
+	transaction := &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: hash,
+		},
+		Operations: []*types.Operation{},
+	}
+
+	if len(spentCoin) > 0 {
+		transaction.Operations = append(transaction.Operations, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Type:                "Input",
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{Identifier: spentCoin},
+				CoinAction:     types.CoinSpent,
+			},
+		})
+	}
+
+	return transaction
+}
+
+func TestMempoolSyncer_Poll(t *testing.T) {
+	ctx := context.Background()
+	mockHelper := &mocks.MempoolHelper{}
+	mockHandler := &mocks.MempoolHandler{}
+	syncer := NewMempoolSyncer(networkIdentifier, mockHelper, mockHandler)
+
+	tx1 := mempoolTransaction("tx1", "coin1")
+	tx2 := mempoolTransaction("tx2", "coin2")
+	tx3 := mempoolTransaction("tx3", "")
+	tx4 := mempoolTransaction("tx4", "coin2")
+
+	expectFetch := func(transactions ...*types.Transaction) {
+		for _, transaction := range transactions {
+			mockHelper.On(
+				"MempoolTransaction",
+				mock.Anything,
+				networkIdentifier,
+				transaction.TransactionIdentifier,
+			).Return(transaction, nil).Once()
+		}
+	}
+
+	// Initial poll adds all transactions
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx1.TransactionIdentifier,
+		tx2.TransactionIdentifier,
+	}, nil).Once()
+	expectFetch(tx1, tx2)
+	mockHandler.On("TransactionAdded", ctx, tx1).Return(nil).Once()
+	mockHandler.On("TransactionAdded", ctx, tx2).Return(nil).Once()
+	assert.NoError(t, syncer.Poll(ctx))
+	assert.Equal(t, []*types.TransactionIdentifier{
+		tx1.TransactionIdentifier,
+		tx2.TransactionIdentifier,
+	}, syncer.Transactions())
+
+	// No changes should not trigger any callbacks
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx2.TransactionIdentifier,
+		tx1.TransactionIdentifier,
+	}, nil).Once()
+	assert.NoError(t, syncer.Poll(ctx))
+
+	// tx1 is removed, tx2 is replaced by tx4, and tx3 is added
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx3.TransactionIdentifier,
+		tx4.TransactionIdentifier,
+	}, nil).Once()
+	expectFetch(tx3, tx4)
+	mockHandler.On("TransactionRemoved", ctx, tx1.TransactionIdentifier).Return(nil).Once()
+	mockHandler.On("TransactionReplaced", ctx, tx2.TransactionIdentifier, tx4).Return(nil).Once()
+	mockHandler.On("TransactionAdded", ctx, tx3).Return(nil).Once()
+	assert.NoError(t, syncer.Poll(ctx))
+	assert.Equal(t, []*types.TransactionIdentifier{
+		tx3.TransactionIdentifier,
+		tx4.TransactionIdentifier,
+	}, syncer.Transactions())
+
+	// Transactions evicted before they are fetched are skipped
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx1.TransactionIdentifier,
+		tx3.TransactionIdentifier,
+		tx4.TransactionIdentifier,
+	}, nil).Once()
+	mockHelper.On(
+		"MempoolTransaction",
+		mock.Anything,
+		networkIdentifier,
+		tx1.TransactionIdentifier,
+	).Return(nil, fmt.Errorf("%w: evicted", ErrMempoolTransactionNotFound)).Once()
+	assert.NoError(t, syncer.Poll(ctx))
+	assert.Equal(t, []*types.TransactionIdentifier{
+		tx3.TransactionIdentifier,
+		tx4.TransactionIdentifier,
+	}, syncer.Transactions())
+
+	// Fetch errors are returned
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx1.TransactionIdentifier,
+	}, nil).Once()
+	mockHelper.On(
+		"MempoolTransaction",
+		mock.Anything,
+		networkIdentifier,
+		tx1.TransactionIdentifier,
+	).Return(nil, errors.New("not found")).Once()
+	err := syncer.Poll(ctx)
+	assert.True(t, errors.Is(err, ErrFetchMempoolTransactionFailed))
+
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return(nil, errors.New("unavailable")).Once()
+	err = syncer.Poll(ctx)
+	assert.True(t, errors.Is(err, ErrFetchMempoolFailed))
+
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestMempoolSyncer_Sync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mockHelper := &mocks.MempoolHelper{}
+	mockHandler := &mocks.MempoolHandler{}
+	syncer := NewMempoolSyncer(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		WithMempoolPollInterval(time.Hour),
+		WithMempoolConcurrency(1),
+		WithReplacementKeys(func(*types.Transaction) []string { return nil }),
+	)
+
+	tx1 := mempoolTransaction("tx1", "coin1")
+	tx2 := mempoolTransaction("tx2", "coin1")
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx1.TransactionIdentifier,
+	}, nil).Once()
+	mockHelper.On(
+		"MempoolTransaction",
+		mock.Anything,
+		networkIdentifier,
+		tx1.TransactionIdentifier,
+	).Return(tx1, nil).Once()
+	mockHandler.On("TransactionAdded", ctx, tx1).Return(nil).Run(
+		func(args mock.Arguments) {
+			// Trigger the next poll without waiting
+			// for the poll interval.
+			syncer.Notify()
+			syncer.Notify()
+		},
+	).Once()
+
+	// With no replacement keys, a conflicting transaction
+	// is treated as a removal and an addition.
+	mockHelper.On("Mempool", ctx, networkIdentifier).Return([]*types.TransactionIdentifier{
+		tx2.TransactionIdentifier,
+	}, nil).Once()
+	mockHelper.On(
+		"MempoolTransaction",
+		mock.Anything,
+		networkIdentifier,
+		tx2.TransactionIdentifier,
+	).Return(tx2, nil).Once()
+	mockHandler.On("TransactionRemoved", ctx, tx1.TransactionIdentifier).Return(nil).Once()
+	mockHandler.On("TransactionAdded", ctx, tx2).Return(nil).Run(
+		func(args mock.Arguments) {
+			cancel()
+		},
+	).Once()
+
+	err := syncer.Sync(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
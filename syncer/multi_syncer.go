@@ -0,0 +1,173 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// networkHandler adapts a NetworkHandler
+// to the Handler of a single network.
+type networkHandler struct {
+	network *types.NetworkIdentifier
+	handler NetworkHandler
+}
+
+func (h *networkHandler) BlockSeen(ctx context.Context, block *types.Block) error {
+	return h.handler.BlockSeen(ctx, h.network, block)
+}
+
+func (h *networkHandler) BlockAdded(ctx context.Context, block *types.Block) error {
+	return h.handler.BlockAdded(ctx, h.network, block)
+}
+
+func (h *networkHandler) BlockRemoved(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) error {
+	return h.handler.BlockRemoved(ctx, h.network, block)
+}
+
+// budgetHelper wraps a Helper so that no more than
+// cap(tokens) blocks are fetched concurrently by all
+// helpers sharing tokens.
+type budgetHelper struct {
+	Helper
+
+	tokens chan struct{}
+}
+
+func (h *budgetHelper) Block(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	identifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	select {
+	case h.tokens <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-h.tokens }()
+
+	return h.Helper.Block(ctx, network, identifier)
+}
+
+func (h *budgetHelper) unwrap() Helper {
+	return h.Helper
+}
+
+// NewMultiSyncer creates a new MultiSyncer with a
+// Syncer for each network in networks.
+func NewMultiSyncer(
+	networks []*types.NetworkIdentifier,
+	helper Helper,
+	handler NetworkHandler,
+	options ...MultiOption,
+) (*MultiSyncer, error) {
+	if len(networks) == 0 {
+		return nil, ErrNetworksMissing
+	}
+
+	m := &MultiSyncer{
+		networks:          networks,
+		helper:            helper,
+		handler:           handler,
+		globalConcurrency: DefaultGlobalConcurrency,
+		networkOptions:    map[string][]Option{},
+		syncers:           map[string]*Syncer{},
+	}
+
+	// Override defaults with any provided options
+	for _, opt := range options {
+		opt(m)
+	}
+
+	tokens := make(chan struct{}, m.globalConcurrency)
+	for _, network := range networks {
+		key := types.Hash(network)
+		if _, ok := m.syncers[key]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateNetwork, types.PrintStruct(network))
+		}
+
+		syncerOptions := []Option{WithMaxConcurrency(m.globalConcurrency)}
+		if m.memoryBudget > 0 {
+			syncerOptions = append(
+				syncerOptions,
+				WithAdaptiveConcurrency(m.memoryBudget/len(networks)),
+			)
+		}
+		syncerOptions = append(syncerOptions, m.syncerOptions...)
+		syncerOptions = append(syncerOptions, m.networkOptions[key]...)
+
+		// Each syncer is canceled by MultiSyncer.Sync (not
+		// when its own range is synced), so we provide a
+		// no-op cancel function.
+		m.syncers[key] = New(
+			network,
+			&budgetHelper{Helper: helper, tokens: tokens},
+			&networkHandler{network: network, handler: handler},
+			func() {},
+			syncerOptions...,
+		)
+	}
+
+	return m, nil
+}
+
+// Syncer returns the *Syncer of network. This can be used
+// to control syncing of a single network (ex: Pause).
+func (m *MultiSyncer) Syncer(network *types.NetworkIdentifier) (*Syncer, error) {
+	s, ok := m.syncers[types.Hash(network)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, types.PrintStruct(network))
+	}
+
+	return s, nil
+}
+
+// Sync syncs all networks concurrently from startIndex to
+// endIndex (see Syncer.Sync). If syncing any network fails,
+// syncing of all other networks is canceled and the error
+// is returned.
+func (m *MultiSyncer) Sync(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for _, network := range m.networks {
+		n := network
+		s := m.syncers[types.Hash(n)]
+		g.Go(func() error {
+			if err := s.Sync(gctx, startIndex, endIndex); err != nil {
+				return fmt.Errorf(
+					"%w %s: %v",
+					ErrNetworkSyncFailed,
+					types.PrintStruct(n),
+					err,
+				)
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
@@ -0,0 +1,252 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+var (
+	otherNetworkIdentifier = &types.NetworkIdentifier{
+		Blockchain: "other",
+		Network:    "testnet",
+	}
+)
+
+// inFlightHelper tracks the maximum number
+// of concurrent calls to Block.
+type inFlightHelper struct {
+	*mocks.Helper
+
+	lock     sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (h *inFlightHelper) Block(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	identifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	h.lock.Lock()
+	h.inFlight++
+	if h.inFlight > h.max {
+		h.max = h.inFlight
+	}
+	h.lock.Unlock()
+
+	time.Sleep(time.Millisecond)
+	defer func() {
+		h.lock.Lock()
+		h.inFlight--
+		h.lock.Unlock()
+	}()
+
+	return h.Helper.Block(ctx, network, identifier)
+}
+
+func mockNetworkBlocks(
+	helper *mocks.Helper,
+	handler *mocks.NetworkHandler,
+	network *types.NetworkIdentifier,
+	blocks []*types.Block,
+) {
+	helper.On("NetworkStatus", mock.Anything, network).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[len(blocks)-1].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil)
+	for _, block := range blocks {
+		index := block.BlockIdentifier.Index
+		b := block
+		helper.On(
+			"Block",
+			mock.Anything,
+			network,
+			&types.PartialBlockIdentifier{Index: &index},
+		).Return(b, nil).Once()
+		handler.On("BlockSeen", mock.Anything, network, b).Return(nil).Once()
+		handler.On("BlockAdded", mock.Anything, network, b).Return(nil).Once()
+	}
+}
+
+func TestNewMultiSyncer(t *testing.T) {
+	t.Run("no networks", func(t *testing.T) {
+		m, err := NewMultiSyncer(nil, &mocks.Helper{}, &mocks.NetworkHandler{})
+		assert.Nil(t, m)
+		assert.True(t, errors.Is(err, ErrNetworksMissing))
+	})
+
+	t.Run("duplicate network", func(t *testing.T) {
+		m, err := NewMultiSyncer(
+			[]*types.NetworkIdentifier{networkIdentifier, networkIdentifier},
+			&mocks.Helper{},
+			&mocks.NetworkHandler{},
+		)
+		assert.Nil(t, m)
+		assert.True(t, errors.Is(err, ErrDuplicateNetwork))
+	})
+
+	t.Run("options", func(t *testing.T) {
+		m, err := NewMultiSyncer(
+			[]*types.NetworkIdentifier{networkIdentifier, otherNetworkIdentifier},
+			&mocks.Helper{},
+			&mocks.NetworkHandler{},
+			WithGlobalConcurrency(8),
+			WithGlobalMemoryBudget(1000),
+			WithSyncerOptions(WithPastBlockLimit(10)),
+			WithNetworkOptions(otherNetworkIdentifier, WithPastBlockLimit(20)),
+		)
+		assert.NoError(t, err)
+
+		s, err := m.Syncer(networkIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), s.maxConcurrency)
+		assert.True(t, s.adaptive)
+		assert.Equal(t, 500, s.memoryBudget)
+		assert.Equal(t, 10, s.pastBlockLimit)
+
+		other, err := m.Syncer(otherNetworkIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, 20, other.pastBlockLimit)
+		assert.Equal(t, s.helper.(*budgetHelper).tokens, other.helper.(*budgetHelper).tokens)
+
+		missing, err := m.Syncer(&types.NetworkIdentifier{Blockchain: "missing"})
+		assert.Nil(t, missing)
+		assert.True(t, errors.Is(err, ErrNetworkNotFound))
+	})
+}
+
+// extendedHelper implements all optional
+// extensions of Helper.
+type extendedHelper struct {
+	*mocks.Helper
+	*mocks.EventsHelper
+
+	prefetched [][2]int64
+	recycled   int
+}
+
+func (h *extendedHelper) PrefetchBlocks(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) {
+	h.prefetched = append(h.prefetched, [2]int64{startIndex, endIndex})
+}
+
+func (h *extendedHelper) RecycleConnections(ctx context.Context) error {
+	h.recycled++
+	return nil
+}
+
+func TestMultiSyncer_HelperExtensions(t *testing.T) {
+	ctx := context.Background()
+	helper := &extendedHelper{Helper: &mocks.Helper{}, EventsHelper: &mocks.EventsHelper{}}
+	m, err := NewMultiSyncer(
+		[]*types.NetworkIdentifier{networkIdentifier},
+		helper,
+		&mocks.NetworkHandler{},
+	)
+	assert.NoError(t, err)
+
+	s, err := m.Syncer(networkIdentifier)
+	assert.NoError(t, err)
+	_, ok := s.helper.(*budgetHelper)
+	assert.True(t, ok)
+
+	events, ok := asEventsHelper(s.helper)
+	assert.True(t, ok)
+	assert.Equal(t, helper, events)
+
+	prefetcher, ok := asBlockPrefetcher(s.helper)
+	assert.True(t, ok)
+	prefetcher.PrefetchBlocks(ctx, 1, 10)
+	assert.Equal(t, [][2]int64{{1, 10}}, helper.prefetched)
+
+	assert.NoError(t, s.recoverStall(ctx))
+	assert.Equal(t, 1, helper.recycled)
+
+	// Extensions the wrapped Helper does not
+	// implement are not reported.
+	m, err = NewMultiSyncer(
+		[]*types.NetworkIdentifier{networkIdentifier},
+		&mocks.Helper{},
+		&mocks.NetworkHandler{},
+	)
+	assert.NoError(t, err)
+
+	s, err = m.Syncer(networkIdentifier)
+	assert.NoError(t, err)
+	_, ok = asEventsHelper(s.helper)
+	assert.False(t, ok)
+	_, ok = asBlockPrefetcher(s.helper)
+	assert.False(t, ok)
+	_, ok = asConnectionRecycler(s.helper)
+	assert.False(t, ok)
+}
+
+func TestMultiSyncer_Sync(t *testing.T) {
+	networks := []*types.NetworkIdentifier{networkIdentifier, otherNetworkIdentifier}
+
+	t.Run("shared concurrency", func(t *testing.T) {
+		helper := &inFlightHelper{Helper: &mocks.Helper{}}
+		handler := &mocks.NetworkHandler{}
+		mockNetworkBlocks(helper.Helper, handler, networkIdentifier, createBlocks(0, 20, ""))
+		mockNetworkBlocks(helper.Helper, handler, otherNetworkIdentifier, createBlocks(0, 20, "other "))
+
+		m, err := NewMultiSyncer(networks, helper, handler, WithGlobalConcurrency(2))
+		assert.NoError(t, err)
+		assert.NoError(t, m.Sync(context.Background(), -1, 20))
+		helper.Helper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+		assert.True(t, helper.max <= 2)
+	})
+
+	t.Run("network failure", func(t *testing.T) {
+		helper := &mocks.Helper{}
+		handler := &mocks.NetworkHandler{}
+		helper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(
+			nil,
+			errors.New("node unavailable"),
+		)
+		helper.On("NetworkStatus", mock.Anything, otherNetworkIdentifier).Return(
+			&types.NetworkStatusResponse{
+				CurrentBlockIdentifier: &types.BlockIdentifier{Hash: "block 0", Index: 0},
+				GenesisBlockIdentifier: &types.BlockIdentifier{Hash: "block 0", Index: 0},
+			},
+			nil,
+		).Maybe()
+		helper.On("Block", mock.Anything, otherNetworkIdentifier, mock.Anything).Return(
+			nil,
+			context.Canceled,
+		).Maybe()
+
+		m, err := NewMultiSyncer(networks, helper, handler)
+		assert.NoError(t, err)
+		err = m.Sync(context.Background(), -1, -1)
+		assert.True(t, errors.Is(err, ErrNetworkSyncFailed))
+		assert.Contains(t, err.Error(), "node unavailable")
+	})
+}
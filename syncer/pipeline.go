@@ -0,0 +1,203 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// stage is a single step in block preparation.
+type stage struct {
+	workers int
+	process func(ctx context.Context, br *blockResult) error
+}
+
+// pipelined returns a boolean indicating if fetched blocks
+// are prepared in separate stages (instead of by the
+// goroutine that fetched them).
+func (s *Syncer) pipelined() bool {
+	return s.assertion != nil || s.preprocessor != nil
+}
+
+// assertBlock asserts the block in br (if
+// a BlockAssertion is provided).
+func (s *Syncer) assertBlock(ctx context.Context, br *blockResult) error {
+	if s.assertion == nil || br.block == nil {
+		return nil
+	}
+
+	if err := s.assertion(br.block); err != nil {
+		return fmt.Errorf("%w %d: %v", ErrBlockAssertionFailed, br.index, err)
+	}
+
+	return nil
+}
+
+// preprocessBlock populates br.data with the output
+// of the Preprocessor (if provided).
+func (s *Syncer) preprocessBlock(ctx context.Context, br *blockResult) error {
+	if s.preprocessor == nil || br.block == nil {
+		return nil
+	}
+
+	data, err := s.preprocessor(ctx, br.block)
+	if err != nil {
+		return fmt.Errorf("%w %d: %v", ErrPreprocessBlockFailed, br.index, err)
+	}
+
+	br.data = data
+	return nil
+}
+
+// prepareBlock runs all block preparation steps
+// (assertion, preprocessing, and BlockSeen) on
+// br in the calling goroutine.
+func (s *Syncer) prepareBlock(ctx context.Context, br *blockResult) error {
+	if err := s.assertBlock(ctx, br); err != nil {
+		return err
+	}
+
+	if err := s.preprocessBlock(ctx, br); err != nil {
+		return err
+	}
+
+	return s.handleSeenBlock(ctx, br)
+}
+
+// stages returns the block preparation stages that
+// run after blocks are fetched. BlockSeen is invoked
+// in the last stage.
+func (s *Syncer) stages() []*stage {
+	stages := []*stage{}
+	if s.assertion != nil {
+		stages = append(stages, &stage{workers: s.assertWorkers, process: s.assertBlock})
+	}
+
+	if s.preprocessor != nil {
+		stages = append(stages, &stage{workers: s.preprocessWorkers, process: s.preprocessBlock})
+	}
+
+	last := stages[len(stages)-1]
+	process := last.process
+	last.process = func(ctx context.Context, br *blockResult) error {
+		if err := process(ctx, br); err != nil {
+			return err
+		}
+
+		return s.handleSeenBlock(ctx, br)
+	}
+
+	return stages
+}
+
+// runStage applies process to each *blockResult received
+// on in and forwards it to out. If process returns an error,
+// cancel is invoked to stop all other stages (and fetching).
+func runStage(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	in chan *blockResult,
+	out chan *blockResult,
+	process func(ctx context.Context, br *blockResult) error,
+) error {
+	for br := range in {
+		if err := process(ctx, br); err != nil {
+			cancel()
+			return err
+		}
+
+		select {
+		case out <- br:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// startStages starts all block preparation stages and returns
+// the channel of prepared blocks (which is closed once all stages
+// exit) and the *errgroup.Group running the stages. If the syncer
+// is not pipelined, fetchedBlocks is returned unmodified.
+func (s *Syncer) startStages(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	fetchedBlocks chan *blockResult,
+) (chan *blockResult, *errgroup.Group) {
+	g := &errgroup.Group{}
+	if !s.pipelined() {
+		return fetchedBlocks, g
+	}
+
+	in := fetchedBlocks
+	for _, st := range s.stages() {
+		out := make(chan *blockResult)
+		process := st.process
+		stageIn := in
+
+		var wg sync.WaitGroup
+		wg.Add(st.workers)
+		for i := 0; i < st.workers; i++ {
+			g.Go(func() error {
+				defer wg.Done()
+				return runStage(ctx, cancel, stageIn, out, process)
+			})
+		}
+
+		// Close the stage output once all stage
+		// workers exit.
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+
+		in = out
+	}
+
+	return in, g
+}
+
+// addBlock invokes the Handler with an added block. If the
+// block was preprocessed and the Handler implements
+// PreprocessedHandler, PreprocessedBlockAdded is invoked
+// instead of BlockAdded.
+func (s *Syncer) addBlock(ctx context.Context, br *blockResult) error {
+	preprocessedHandler, ok := s.handler.(PreprocessedHandler)
+	if ok && s.preprocessor != nil {
+		return preprocessedHandler.PreprocessedBlockAdded(ctx, br.block, br.data)
+	}
+
+	if h, ok := s.handler.(*handlerV2Adapter); ok {
+		return h.handler.BlockAdded(ctx, br.block, s.blockInfo(ctx, br.block.BlockIdentifier))
+	}
+
+	return s.handler.BlockAdded(ctx, br.block)
+}
+
+// BalanceChangesPreprocessor returns a Preprocessor that
+// computes the []*parser.BalanceChange of each block.
+func BalanceChangesPreprocessor(p *parser.Parser) Preprocessor {
+	return func(ctx context.Context, block *types.Block) (interface{}, error) {
+		return p.BalanceChanges(ctx, block, false)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type preprocessedHandler struct {
+	*mocks.Handler
+
+	data []interface{}
+}
+
+func (h *preprocessedHandler) PreprocessedBlockAdded(
+	ctx context.Context,
+	block *types.Block,
+	data interface{},
+) error {
+	h.data = append(h.data, data)
+	return nil
+}
+
+func indexPreprocessor(ctx context.Context, block *types.Block) (interface{}, error) {
+	return block.BlockIdentifier.Index, nil
+}
+
+func TestSync_Pipeline(t *testing.T) {
+	blocks := createBlocks(0, 9, "")
+	networkStatus := &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[9].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}
+
+	t.Run("assert and preprocess", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHelper := &mocks.Helper{}
+		handler := &preprocessedHandler{Handler: &mocks.Handler{}}
+
+		var assertedLock sync.Mutex
+		asserted := map[int64]bool{}
+		syncer := New(
+			networkIdentifier,
+			mockHelper,
+			handler,
+			cancel,
+			WithBlockAssertion(func(block *types.Block) error {
+				assertedLock.Lock()
+				defer assertedLock.Unlock()
+
+				asserted[block.BlockIdentifier.Index] = true
+				return nil
+			}, 2),
+			WithPreprocessor(indexPreprocessor, 3),
+		)
+		assert.Equal(t, 2, syncer.assertWorkers)
+		assert.Equal(t, 3, syncer.preprocessWorkers)
+
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		for _, block := range blocks {
+			index := block.BlockIdentifier.Index
+			b := block
+			mockHelper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(b, nil).Once()
+			handler.On("BlockSeen", mock.Anything, b).Return(nil).Once()
+		}
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 9))
+		mockHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+		assert.Len(t, asserted, 10)
+
+		// Preprocessed data is handed off in order.
+		expected := []interface{}{}
+		for i := int64(0); i <= 9; i++ {
+			expected = append(expected, i)
+		}
+		assert.Equal(t, expected, handler.data)
+	})
+
+	t.Run("handler without preprocessed support", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(
+			networkIdentifier,
+			mockHelper,
+			mockHandler,
+			cancel,
+			WithPreprocessor(indexPreprocessor, 0),
+		)
+		assert.Equal(t, DefaultStageWorkers, syncer.preprocessWorkers)
+
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		mockRangeBlocks(mockHelper, mockHandler, blocks)
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 9))
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("assertion failure", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(
+			networkIdentifier,
+			mockHelper,
+			mockHandler,
+			cancel,
+			WithBlockAssertion(func(block *types.Block) error {
+				if block.BlockIdentifier.Index == 5 {
+					return errors.New("invalid block")
+				}
+
+				return nil
+			}, 0),
+		)
+
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		for _, block := range blocks {
+			index := block.BlockIdentifier.Index
+			b := block
+			mockHelper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(b, nil).Maybe()
+			if index == 5 {
+				continue
+			}
+
+			mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Maybe()
+			mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Maybe()
+		}
+
+		err := syncer.Sync(ctx, -1, 9)
+		assert.True(t, errors.Is(err, ErrBlockAssertionFailed))
+		mockHandler.AssertNotCalled(t, "BlockSeen", mock.Anything, blocks[5])
+		assert.True(t, syncer.nextIndex <= 5)
+		cancel()
+	})
+
+	t.Run("preprocess failure during reorg fetch", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(
+			networkIdentifier,
+			mockHelper,
+			mockHandler,
+			func() {},
+			WithPreprocessor(func(ctx context.Context, block *types.Block) (interface{}, error) {
+				return nil, errors.New("unable to parse")
+			}, 1),
+		)
+
+		index := int64(0)
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &index},
+		).Return(blocks[0], nil).Once()
+
+		br, err := syncer.fetchBlockResult(context.Background(), networkIdentifier, 0)
+		assert.Nil(t, br)
+		assert.True(t, errors.Is(err, ErrPreprocessBlockFailed))
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertNotCalled(t, "BlockSeen", mock.Anything, blocks[0])
+	})
+}
+
+func TestBalanceChangesPreprocessor(t *testing.T) {
+	preprocessor := BalanceChangesPreprocessor(parser.New(nil, nil, nil))
+	block := createBlocks(1, 1, "")[0]
+
+	data, err := preprocessor(context.Background(), block)
+	assert.NoError(t, err)
+	assert.Equal(t, []*parser.BalanceChange{}, data)
+}
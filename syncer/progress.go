@@ -0,0 +1,88 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"time"
+)
+
+// resetProgress resets all progress counters. This is
+// invoked each time Sync is called.
+func (s *Syncer) resetProgress(now time.Time) {
+	s.lastProgress = now
+	s.lastBlocksSynced = 0
+	s.blocksSynced = 0
+	s.orphansProcessed = 0
+	s.blocksPerSecond = 0
+}
+
+// progress returns a *Progress snapshot of the
+// current sync state.
+func (s *Syncer) progress() *Progress {
+	progress := &Progress{
+		BlocksSynced:     s.blocksSynced,
+		OrphansProcessed: s.orphansProcessed,
+		BlocksPerSecond:  s.blocksPerSecond,
+		CacheBytes:       s.CacheBytes(),
+	}
+
+	if s.tip != nil {
+		progress.BehindBy = s.tip.Index - (s.nextIndex - 1)
+		if progress.BehindBy < 0 {
+			progress.BehindBy = 0
+		}
+
+		progress.TipReached = progress.BehindBy == 0
+	}
+
+	return progress
+}
+
+// updateRate updates the rolling blocks/sec rate
+// with the blocks added since the last update.
+func (s *Syncer) updateRate(now time.Time) {
+	elapsed := now.Sub(s.lastProgress).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	rate := float64(s.blocksSynced-s.lastBlocksSynced) / elapsed
+	if s.lastBlocksSynced == 0 && s.blocksPerSecond == 0 {
+		s.blocksPerSecond = rate
+	} else {
+		s.blocksPerSecond = s.blocksPerSecond*(1-latencySmoothing) + rate*latencySmoothing
+	}
+
+	s.lastProgress = now
+	s.lastBlocksSynced = s.blocksSynced
+}
+
+// reportProgress invokes the progressHandler (if provided)
+// if progressInterval has elapsed since the last report or
+// if force is true.
+func (s *Syncer) reportProgress(ctx context.Context, force bool) {
+	if s.progressHandler == nil {
+		return
+	}
+
+	now := time.Now()
+	if !force && now.Sub(s.lastProgress) < s.progressInterval {
+		return
+	}
+
+	s.updateRate(now)
+	s.progressHandler.SyncProgress(ctx, s.progress())
+}
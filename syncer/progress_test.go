@@ -0,0 +1,170 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type progressRecorder struct {
+	reports []*Progress
+}
+
+func (p *progressRecorder) SyncProgress(ctx context.Context, progress *Progress) {
+	p.reports = append(p.reports, progress)
+}
+
+func TestProgress(t *testing.T) {
+	tests := map[string]struct {
+		tip       *types.BlockIdentifier
+		nextIndex int64
+
+		expected *Progress
+	}{
+		"tip unknown": {
+			nextIndex: 10,
+			expected:  &Progress{BlocksSynced: 5, OrphansProcessed: 1},
+		},
+		"behind tip": {
+			tip:       &types.BlockIdentifier{Hash: "block 20", Index: 20},
+			nextIndex: 10,
+			expected:  &Progress{BlocksSynced: 5, OrphansProcessed: 1, BehindBy: 11},
+		},
+		"at tip": {
+			tip:       &types.BlockIdentifier{Hash: "block 20", Index: 20},
+			nextIndex: 21,
+			expected:  &Progress{BlocksSynced: 5, OrphansProcessed: 1, TipReached: true},
+		},
+		"tip stale": {
+			tip:       &types.BlockIdentifier{Hash: "block 20", Index: 20},
+			nextIndex: 25,
+			expected:  &Progress{BlocksSynced: 5, OrphansProcessed: 1, TipReached: true},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			syncer := New(networkIdentifier, &mocks.Helper{}, &mocks.Handler{}, func() {})
+			syncer.tip = test.tip
+			syncer.nextIndex = test.nextIndex
+			syncer.blocksSynced = 5
+			syncer.orphansProcessed = 1
+
+			assert.Equal(t, test.expected, syncer.progress())
+		})
+	}
+}
+
+func TestReportProgress(t *testing.T) {
+	recorder := &progressRecorder{}
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		&mocks.Handler{},
+		func() {},
+		WithProgressHandler(recorder, time.Hour),
+	)
+	assert.Equal(t, time.Hour, syncer.progressInterval)
+
+	start := time.Now()
+	syncer.resetProgress(start)
+
+	// Nothing is reported before the interval elapses
+	syncer.blocksSynced = 10
+	syncer.reportProgress(context.Background(), false)
+	assert.Len(t, recorder.reports, 0)
+
+	// The first rate is not smoothed
+	syncer.updateRate(start.Add(2 * time.Second))
+	assert.Equal(t, float64(5), syncer.blocksPerSecond)
+
+	// Subsequent rates are smoothed
+	syncer.blocksSynced = 30
+	syncer.updateRate(start.Add(3 * time.Second))
+	assert.InDelta(t, 5*(1-latencySmoothing)+20*latencySmoothing, syncer.blocksPerSecond, 1e-9)
+
+	syncer.reportProgress(context.Background(), true)
+	assert.Len(t, recorder.reports, 1)
+	assert.Equal(t, int64(30), recorder.reports[0].BlocksSynced)
+
+	t.Run("default interval", func(t *testing.T) {
+		syncer := New(
+			networkIdentifier,
+			&mocks.Helper{},
+			&mocks.Handler{},
+			func() {},
+			WithProgressHandler(recorder, 0),
+		)
+		assert.Equal(t, DefaultProgressInterval, syncer.progressInterval)
+	})
+}
+
+func TestSync_Progress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	recorder := &progressRecorder{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithProgressHandler(recorder, time.Nanosecond),
+	)
+
+	blocks := createBlocks(0, 9, "")
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[9].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil)
+	for _, block := range blocks {
+		index := block.BlockIdentifier.Index
+		b := block
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &index},
+		).Return(b, nil).Once()
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+
+	assert.NoError(t, syncer.Sync(ctx, -1, 9))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+
+	// Progress is reported after each block
+	// and when syncing completes.
+	assert.Len(t, recorder.reports, 11)
+	for i, report := range recorder.reports[:10] {
+		assert.Equal(t, int64(i+1), report.BlocksSynced)
+	}
+
+	final := recorder.reports[10]
+	assert.Equal(t, int64(10), final.BlocksSynced)
+	assert.Equal(t, int64(0), final.OrphansProcessed)
+	assert.Equal(t, int64(0), final.BehindBy)
+	assert.True(t, final.TipReached)
+	assert.True(t, final.BlocksPerSecond > 0)
+}
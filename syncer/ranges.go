@@ -0,0 +1,131 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// sortBlockRanges returns a copy of ranges sorted by start
+// index. An error is returned if any range is invalid or
+// if any ranges overlap.
+func sortBlockRanges(ranges []*BlockRange) ([]*BlockRange, error) {
+	if len(ranges) == 0 {
+		return nil, ErrBlockRangesMissing
+	}
+
+	sorted := make([]*BlockRange, len(ranges))
+	for i, blockRange := range ranges {
+		if blockRange == nil || blockRange.Start < 0 || blockRange.End < blockRange.Start {
+			return nil, fmt.Errorf("%w: %s", ErrBlockRangeInvalid, types.PrintStruct(blockRange))
+		}
+
+		sorted[i] = blockRange
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Start <= sorted[i-1].End {
+			return nil, fmt.Errorf(
+				"%w: %d-%d and %d-%d",
+				ErrBlockRangesOverlap,
+				sorted[i-1].Start,
+				sorted[i-1].End,
+				sorted[i].Start,
+				sorted[i].End,
+			)
+		}
+	}
+
+	return sorted, nil
+}
+
+// startRange prepares the syncer to sync from index. If the
+// last processed block is not the parent of index (i.e. the
+// range is not contiguous with the last synced block), past
+// blocks are cleared so that the first block in the range is
+// not mistaken for a reorg.
+func (s *Syncer) startRange(index int64) {
+	s.nextIndex = index
+	s.reorgDepth = 0
+
+	if len(s.pastBlocks) == 0 {
+		return
+	}
+
+	if s.pastBlocks[len(s.pastBlocks)-1].Index != index-1 {
+		s.pastBlocks = []*types.BlockIdentifier{}
+	}
+}
+
+// SyncRanges syncs a set of disjoint block ranges (ex: to
+// backfill gaps in an existing index). Ranges are synced in
+// order of start index (regardless of the order provided)
+// and callback (if not nil) is invoked after each range is
+// synced. When all ranges are synced, context is canceled.
+//
+// Unlike Sync, SyncRanges always walks block indices (even
+// if WithBlockEvents is provided).
+func (s *Syncer) SyncRanges(
+	ctx context.Context,
+	ranges []*BlockRange,
+	callback RangeCallback,
+) error {
+	sorted, err := sortBlockRanges(ranges)
+	if err != nil {
+		return err
+	}
+
+	if err := s.setStart(ctx, sorted[0].Start); err != nil {
+		return fmt.Errorf("%w: %v", ErrSetStartIndexFailed, err)
+	}
+	s.resetProgress(time.Now())
+
+	for _, blockRange := range sorted {
+		s.startRange(blockRange.Start)
+		s.SetTargetIndex(blockRange.End)
+		if err := s.syncIndices(ctx); err != nil {
+			return err
+		}
+
+		log.Printf("Finished syncing range %d-%d\n", blockRange.Start, blockRange.End)
+		if callback == nil {
+			continue
+		}
+
+		if err := callback(ctx, blockRange); err != nil {
+			return fmt.Errorf(
+				"%w: range %d-%d: %v",
+				ErrRangeCallbackFailed,
+				blockRange.Start,
+				blockRange.End,
+				err,
+			)
+		}
+	}
+
+	s.reportProgress(ctx, true)
+	s.cancel()
+	return nil
+}
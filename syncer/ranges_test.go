@@ -0,0 +1,190 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestSortBlockRanges(t *testing.T) {
+	tests := map[string]struct {
+		ranges []*BlockRange
+
+		expected []*BlockRange
+		err      error
+	}{
+		"single range": {
+			ranges:   []*BlockRange{{Start: 0, End: 10}},
+			expected: []*BlockRange{{Start: 0, End: 10}},
+		},
+		"unsorted ranges": {
+			ranges: []*BlockRange{
+				{Start: 5000000, End: 6000000},
+				{Start: 0, End: 1000000},
+				{Start: 1000001, End: 1000001},
+			},
+			expected: []*BlockRange{
+				{Start: 0, End: 1000000},
+				{Start: 1000001, End: 1000001},
+				{Start: 5000000, End: 6000000},
+			},
+		},
+		"no ranges": {
+			err: ErrBlockRangesMissing,
+		},
+		"nil range": {
+			ranges: []*BlockRange{nil},
+			err:    ErrBlockRangeInvalid,
+		},
+		"negative start": {
+			ranges: []*BlockRange{{Start: -1, End: 10}},
+			err:    ErrBlockRangeInvalid,
+		},
+		"end before start": {
+			ranges: []*BlockRange{{Start: 10, End: 9}},
+			err:    ErrBlockRangeInvalid,
+		},
+		"overlapping ranges": {
+			ranges: []*BlockRange{
+				{Start: 5, End: 10},
+				{Start: 0, End: 5},
+			},
+			err: ErrBlockRangesOverlap,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sorted, err := sortBlockRanges(test.ranges)
+			if test.err != nil {
+				assert.Nil(t, sorted)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, sorted)
+		})
+	}
+}
+
+func mockRangeBlocks(
+	mockHelper *mocks.Helper,
+	mockHandler *mocks.Handler,
+	blocks []*types.Block,
+) {
+	for _, block := range blocks {
+		index := block.BlockIdentifier.Index
+		b := block
+		mockHelper.On(
+			"Block",
+			mock.Anything,
+			networkIdentifier,
+			&types.PartialBlockIdentifier{Index: &index},
+		).Return(b, nil).Once()
+		mockHandler.On("BlockSeen", mock.Anything, b).Return(nil).Once()
+		mockHandler.On("BlockAdded", mock.Anything, b).Return(nil).Once()
+	}
+}
+
+func TestSyncRanges(t *testing.T) {
+	blocks := createBlocks(0, 9, "")
+	networkStatus := &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[9].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}
+
+	t.Run("disjoint ranges", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(networkIdentifier, mockHelper, mockHandler, cancel)
+
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		mockRangeBlocks(mockHelper, mockHandler, blocks[0:4])
+		mockRangeBlocks(mockHelper, mockHandler, blocks[6:8])
+
+		completed := []*BlockRange{}
+		err := syncer.SyncRanges(
+			ctx,
+			[]*BlockRange{
+				{Start: 6, End: 7},
+				{Start: 3, End: 3},
+				{Start: 0, End: 2},
+			},
+			func(ctx context.Context, blockRange *BlockRange) error {
+				completed = append(completed, blockRange)
+				return nil
+			},
+		)
+		assert.NoError(t, err)
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		assert.Equal(t, []*BlockRange{
+			{Start: 0, End: 2},
+			{Start: 3, End: 3},
+			{Start: 6, End: 7},
+		}, completed)
+
+		// Past blocks are reset at the start of
+		// non-contiguous ranges.
+		assert.Equal(t, []*types.BlockIdentifier{
+			blocks[6].BlockIdentifier,
+			blocks[7].BlockIdentifier,
+		}, syncer.pastBlocks)
+		assert.Equal(t, int64(8), syncer.nextIndex)
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(networkIdentifier, mockHelper, mockHandler, cancel)
+
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(networkStatus, nil)
+		mockRangeBlocks(mockHelper, mockHandler, blocks[0:2])
+
+		err := syncer.SyncRanges(
+			ctx,
+			[]*BlockRange{
+				{Start: 0, End: 1},
+				{Start: 5, End: 6},
+			},
+			func(ctx context.Context, blockRange *BlockRange) error {
+				return errors.New("index unavailable")
+			},
+		)
+		assert.True(t, errors.Is(err, ErrRangeCallbackFailed))
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+		assert.NoError(t, ctx.Err())
+		cancel()
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		syncer := New(networkIdentifier, &mocks.Helper{}, &mocks.Handler{}, func() {})
+		err := syncer.SyncRanges(context.Background(), []*BlockRange{}, nil)
+		assert.True(t, errors.Is(err, ErrBlockRangesMissing))
+	})
+}
@@ -48,6 +48,7 @@ func New(
 		pastBlocks:       []*types.BlockIdentifier{},
 		pastBlockLimit:   DefaultPastBlockLimit,
 		adjustmentWindow: DefaultAdjustmentWindow,
+		targetIndex:      -1,
 	}
 
 	// Override defaults with any provided options
@@ -58,6 +59,192 @@ func New(
 	return s
 }
 
+// NewFromCheckpoint creates a new Syncer that resumes syncing from
+// the last *Checkpoint saved to store. When Sync is invoked with a
+// startIndex of -1, syncing starts at the checkpoint (instead of at
+// genesis). If no checkpoint exists, this is equivalent to calling
+// New with WithCheckpointStore.
+func NewFromCheckpoint(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	helper Helper,
+	handler Handler,
+	cancel context.CancelFunc,
+	store CheckpointStore,
+	options ...Option,
+) (*Syncer, error) {
+	s := New(
+		network,
+		helper,
+		handler,
+		cancel,
+		append(options, WithCheckpointStore(store))...,
+	)
+
+	checkpoint, err := store.LoadCheckpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoadCheckpointFailed, err)
+	}
+
+	if checkpoint == nil {
+		return s, nil
+	}
+
+	s.checkpoint = checkpoint
+	s.eventSequence = checkpoint.EventSequence
+	s.pastBlocks = make([]*types.BlockIdentifier, len(checkpoint.PastBlocks))
+	copy(s.pastBlocks, checkpoint.PastBlocks)
+	if len(s.pastBlocks) > s.pastBlockLimit {
+		s.pastBlocks = s.pastBlocks[len(s.pastBlocks)-s.pastBlockLimit:]
+	}
+
+	return s, nil
+}
+
+// checkpointContextKey is the context key of the
+// *Checkpoint attached to each Handler invocation.
+type checkpointContextKey struct{}
+
+// CheckpointFromContext returns the *Checkpoint of the sync state
+// after the block(s) provided to the Handler are processed. A
+// Handler that uses a HandlerCheckpointStore should write it in
+// the same database transaction it uses to process the block(s).
+// If the syncer was not provided with a CheckpointStore, this
+// returns nil.
+func CheckpointFromContext(ctx context.Context) *Checkpoint {
+	checkpoint, _ := ctx.Value(checkpointContextKey{}).(*Checkpoint)
+	return checkpoint
+}
+
+// newCheckpoint returns a *Checkpoint of the provided sync state
+// (copying pastBlocks so that it is not modified by the syncer).
+func newCheckpoint(
+	pastBlocks []*types.BlockIdentifier,
+	nextIndex int64,
+	eventSequence int64,
+) *Checkpoint {
+	checkpoint := &Checkpoint{
+		NextIndex:     nextIndex,
+		PastBlocks:    make([]*types.BlockIdentifier, len(pastBlocks)),
+		EventSequence: eventSequence,
+	}
+	copy(checkpoint.PastBlocks, pastBlocks)
+	if len(pastBlocks) > 0 {
+		checkpoint.LastSynced = pastBlocks[len(pastBlocks)-1]
+	}
+
+	return checkpoint
+}
+
+// withCheckpoint attaches the *Checkpoint of the sync state after
+// a Handler invocation to ctx (if a checkpointStore is provided).
+func (s *Syncer) withCheckpoint(
+	ctx context.Context,
+	pastBlocks []*types.BlockIdentifier,
+	nextIndex int64,
+	eventSequence int64,
+) context.Context {
+	if s.checkpointStore == nil {
+		return ctx
+	}
+
+	return context.WithValue(
+		ctx,
+		checkpointContextKey{},
+		newCheckpoint(pastBlocks, nextIndex, eventSequence),
+	)
+}
+
+// saveCheckpoint persists the current sync state to the
+// checkpointStore (if provided). If handled is true, the
+// Handler was invoked with the current sync state attached
+// to its context, so nothing is saved if the Handler saves
+// checkpoints itself.
+func (s *Syncer) saveCheckpoint(ctx context.Context, handled bool) error {
+	if s.checkpointStore == nil || (handled && s.handlerCheckpoints) {
+		return nil
+	}
+
+	if err := s.checkpointStore.SaveCheckpoint(
+		ctx,
+		newCheckpoint(s.pastBlocks, s.nextIndex, s.eventSequence),
+	); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveCheckpointFailed, err)
+	}
+
+	return nil
+}
+
+// wrappedHelper is implemented by Helpers that wrap another
+// Helper (ex: to limit concurrent fetches). The optional
+// extensions of Helper are looked up on the wrapped Helper,
+// so wrapping a Helper never hides them.
+type wrappedHelper interface {
+	unwrap() Helper
+}
+
+// asEventsHelper returns the EventsHelper implemented
+// by helper (or any Helper it wraps).
+func asEventsHelper(helper Helper) (EventsHelper, bool) {
+	for {
+		if h, ok := helper.(EventsHelper); ok {
+			return h, true
+		}
+
+		w, ok := helper.(wrappedHelper)
+		if !ok {
+			return nil, false
+		}
+		helper = w.unwrap()
+	}
+}
+
+// asBlockPrefetcher returns the BlockPrefetcher implemented
+// by helper (or any Helper it wraps).
+func asBlockPrefetcher(helper Helper) (BlockPrefetcher, bool) {
+	for {
+		if h, ok := helper.(BlockPrefetcher); ok {
+			return h, true
+		}
+
+		w, ok := helper.(wrappedHelper)
+		if !ok {
+			return nil, false
+		}
+		helper = w.unwrap()
+	}
+}
+
+// asConnectionRecycler returns the ConnectionRecycler
+// implemented by helper (or any Helper it wraps).
+func asConnectionRecycler(helper Helper) (ConnectionRecycler, bool) {
+	for {
+		if h, ok := helper.(ConnectionRecycler); ok {
+			return h, true
+		}
+
+		w, ok := helper.(wrappedHelper)
+		if !ok {
+			return nil, false
+		}
+		helper = w.unwrap()
+	}
+}
+
+// appendPastBlock returns pastBlocks with block appended
+// (dropping the oldest block if pastBlockLimit is exceeded).
+func (s *Syncer) appendPastBlock(
+	pastBlocks []*types.BlockIdentifier,
+	block *types.BlockIdentifier,
+) []*types.BlockIdentifier {
+	pastBlocks = append(pastBlocks, block)
+	if len(pastBlocks) > s.pastBlockLimit {
+		pastBlocks = pastBlocks[1:]
+	}
+
+	return pastBlocks
+}
+
 func (s *Syncer) setStart(
 	ctx context.Context,
 	index int64,
@@ -77,6 +264,11 @@ func (s *Syncer) setStart(
 		return nil
 	}
 
+	if s.checkpoint != nil {
+		s.nextIndex = s.checkpoint.NextIndex
+		return nil
+	}
+
 	s.nextIndex = networkStatus.GenesisBlockIdentifier.Index
 	return nil
 }
@@ -168,7 +360,7 @@ func (s *Syncer) processBlock(
 	// index and return.
 	if br.block == nil && !br.orphanHead {
 		s.nextIndex++
-		return nil
+		return s.saveCheckpoint(ctx, false)
 	}
 
 	shouldRemove, lastBlock, err := s.checkRemove(br)
@@ -177,29 +369,178 @@ func (s *Syncer) processBlock(
 	}
 
 	if shouldRemove {
-		err = s.handler.BlockRemoved(ctx, lastBlock)
+		s.beginReorg()
+		if batchHandler, ok := s.handler.(BatchRemovalHandler); ok {
+			return s.removeBlocks(ctx, batchHandler, br)
+		}
+
+		if err := s.checkReorgDepth(s.reorgDepth + 1); err != nil {
+			return err
+		}
+
+		pastBlocks, err := s.popPastBlock(ctx, s.pastBlocks)
+		if err != nil {
+			return err
+		}
+
+		err = s.removeBlock(
+			s.withCheckpoint(ctx, pastBlocks, lastBlock.Index, s.eventSequence),
+			lastBlock,
+		)
 		if err != nil {
 			return err
 		}
-		s.pastBlocks = s.pastBlocks[:len(s.pastBlocks)-1]
+		s.pastBlocks = pastBlocks
 		s.nextIndex = lastBlock.Index
-		return nil
+		s.reorgDepth++
+		s.orphansProcessed++
+		return s.saveCheckpoint(ctx, true)
 	}
 
 	block := br.block
-	err = s.handler.BlockAdded(ctx, block)
-	if err != nil {
+	pastBlocks := s.appendPastBlock(s.pastBlocks, block.BlockIdentifier)
+	nextIndex := block.BlockIdentifier.Index + 1
+	if err := s.addBlock(
+		s.withCheckpoint(ctx, pastBlocks, nextIndex, s.eventSequence),
+		br,
+	); err != nil {
 		return err
 	}
+	s.reorgDepth = 0
+	s.blocksSynced++
 
-	s.pastBlocks = append(s.pastBlocks, block.BlockIdentifier)
-	if len(s.pastBlocks) > s.pastBlockLimit {
-		s.pastBlocks = s.pastBlocks[1:]
+	s.pastBlocks = pastBlocks
+	s.nextIndex = nextIndex
+	return s.saveCheckpoint(ctx, true)
+}
+
+// checkReorgDepth returns an error if removing
+// depth consecutive blocks exceeds maxReorgDepth.
+func (s *Syncer) checkReorgDepth(depth int) error {
+	if s.maxReorgDepth > 0 && depth > s.maxReorgDepth {
+		return fmt.Errorf(
+			"%w: reorg depth %d exceeds %d",
+			ErrMaxReorgDepthExceeded,
+			depth,
+			s.maxReorgDepth,
+		)
 	}
-	s.nextIndex = block.BlockIdentifier.Index + 1
+
 	return nil
 }
 
+// popPastBlock removes the last block from pastBlocks. If the
+// removed block was the last known block (the reorg is deeper
+// than pastBlocks), the removed block is refetched by hash to
+// determine its parent, which becomes the new last block.
+func (s *Syncer) popPastBlock(
+	ctx context.Context,
+	pastBlocks []*types.BlockIdentifier,
+) ([]*types.BlockIdentifier, error) {
+	removed := pastBlocks[len(pastBlocks)-1]
+	pastBlocks = pastBlocks[:len(pastBlocks)-1]
+	if len(pastBlocks) > 0 {
+		return pastBlocks, nil
+	}
+
+	block, err := s.helper.Block(
+		ctx,
+		s.network,
+		types.ConstructPartialBlockIdentifier(removed),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrFetchAncestorFailed, removed.Hash, err)
+	}
+
+	if block == nil || block.ParentBlockIdentifier == nil {
+		return nil, fmt.Errorf("%w %s: parent is unknown", ErrFetchAncestorFailed, removed.Hash)
+	}
+
+	return []*types.BlockIdentifier{block.ParentBlockIdentifier}, nil
+}
+
+// reorgBlocks returns all blocks in pastBlocks that must be removed
+// for the chain to connect to the provided *blockResult (ordered from
+// the current tip to the oldest orphaned block) and the remaining
+// past blocks. To find the fork point, the canonical block at each
+// orphaned index is fetched and compared to the remaining blocks.
+func (s *Syncer) reorgBlocks(
+	ctx context.Context,
+	br *blockResult,
+) ([]*types.BlockIdentifier, []*types.BlockIdentifier, error) {
+	removed := []*types.BlockIdentifier{}
+	pastBlocks := make([]*types.BlockIdentifier, len(s.pastBlocks))
+	copy(pastBlocks, s.pastBlocks)
+
+	current := br
+	for {
+		head := pastBlocks[len(pastBlocks)-1]
+		if types.Hash(s.genesisBlock) == types.Hash(head) {
+			return nil, nil, ErrCannotRemoveGenesisBlock
+		}
+
+		if err := s.checkReorgDepth(s.reorgDepth + len(removed) + 1); err != nil {
+			return nil, nil, err
+		}
+
+		var err error
+		removed = append(removed, head)
+		pastBlocks, err = s.popPastBlock(ctx, pastBlocks)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		current, err = s.fetchBlockResult(ctx, s.network, head.Index)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrFetchBlockReorgFailed, err)
+		}
+
+		// If the canonical block at the orphaned index is omitted, we
+		// can't determine if it connects to the remaining past blocks.
+		// We return here and let any remaining blocks be removed
+		// as they are processed.
+		if current.block == nil && !current.orphanHead {
+			break
+		}
+
+		if current.block != nil &&
+			types.Hash(current.block.ParentBlockIdentifier) == types.Hash(
+				pastBlocks[len(pastBlocks)-1],
+			) {
+			break
+		}
+	}
+
+	return removed, pastBlocks, nil
+}
+
+// removeBlocks removes all blocks orphaned by a reorg
+// with a single call to BlocksRemoved.
+func (s *Syncer) removeBlocks(
+	ctx context.Context,
+	handler BatchRemovalHandler,
+	br *blockResult,
+) error {
+	removed, pastBlocks, err := s.reorgBlocks(ctx, br)
+	if err != nil {
+		return err
+	}
+
+	nextIndex := removed[len(removed)-1].Index
+	if err := handler.BlocksRemoved(
+		s.withCheckpoint(ctx, pastBlocks, nextIndex, s.eventSequence),
+		removed,
+	); err != nil {
+		return err
+	}
+
+	s.pastBlocks = pastBlocks
+	s.nextIndex = nextIndex
+	s.reorgDepth += len(removed)
+	s.orphansProcessed += int64(len(removed))
+	return s.saveCheckpoint(ctx, true)
+}
+
 // addBlockIndices appends a range of indices (from
 // startIndex to endIndex, inclusive) to the
 // blockIndices channel. When all indices are added,
@@ -212,14 +553,32 @@ func (s *Syncer) addBlockIndices(
 ) error {
 	defer close(blockIndices)
 
+	prefetcher, prefetch := asBlockPrefetcher(s.helper)
+	prefetched := startIndex - 1
+
 	i := startIndex
 	for i <= endIndex {
+		// We hint the next window of indices once half of
+		// the previously hinted window has been enqueued.
+		if prefetch && prefetched < endIndex && i+prefetchWindow/2 > prefetched {
+			prefetchEnd := prefetched + prefetchWindow
+			if prefetchEnd > endIndex {
+				prefetchEnd = endIndex
+			}
+
+			prefetcher.PrefetchBlocks(ctx, prefetched+1, prefetchEnd)
+			prefetched = prefetchEnd
+		}
+
 		s.concurrencyLock.Lock()
 		currentConcurrency := s.concurrency
+		cacheFull := s.maxCacheBytes > 0 && s.cacheBytes >= s.maxCacheBytes
 		s.concurrencyLock.Unlock()
 
-		// Don't load if we already have a healthy backlog.
-		if int64(len(blockIndices)) > currentConcurrency {
+		// Don't load if we already have a healthy backlog
+		// or if fetched blocks waiting to be processed
+		// exceed the max cache bytes.
+		if int64(len(blockIndices)) > currentConcurrency || cacheFull {
 			time.Sleep(defaultFetchSleep)
 			continue
 		}
@@ -244,11 +603,33 @@ func (s *Syncer) addBlockIndices(
 	return nil
 }
 
+// fetchBlockResult fetches and prepares (see
+// prepareBlock) the block at index.
 func (s *Syncer) fetchBlockResult(
 	ctx context.Context,
 	network *types.NetworkIdentifier,
 	index int64,
 ) (*blockResult, error) {
+	br, err := s.fetchBlock(ctx, network, index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prepareBlock(ctx, br); err != nil {
+		return nil, err
+	}
+
+	return br, nil
+}
+
+// fetchBlock fetches the block at index
+// without preparing it.
+func (s *Syncer) fetchBlock(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	index int64,
+) (*blockResult, error) {
+	start := time.Now()
 	block, err := s.helper.Block(
 		ctx,
 		network,
@@ -257,7 +638,7 @@ func (s *Syncer) fetchBlockResult(
 		},
 	)
 
-	br := &blockResult{index: index}
+	br := &blockResult{index: index, fetchDuration: time.Since(start)}
 	switch {
 	case errors.Is(err, ErrOrphanHead):
 		br.orphanHead = true
@@ -267,10 +648,6 @@ func (s *Syncer) fetchBlockResult(
 		br.block = block
 	}
 
-	if err := s.handleSeenBlock(ctx, br); err != nil {
-		return nil, err
-	}
-
 	return br, nil
 }
 
@@ -295,8 +672,15 @@ func (s *Syncer) fetchBlocks(
 	blockIndices chan int64,
 	results chan *blockResult,
 ) error {
+	// When blocks are prepared in a separate pipeline,
+	// fetchers only fetch blocks.
+	fetch := s.fetchBlockResult
+	if s.pipelined() {
+		fetch = s.fetchBlock
+	}
+
 	for b := range blockIndices {
-		br, err := s.fetchBlockResult(
+		br, err := fetch(
 			ctx,
 			network,
 			b,
@@ -340,7 +724,11 @@ func (s *Syncer) processBlocks(
 	// if they don't exist in the cache.
 	reorgStart := int64(-1)
 
-	for s.nextIndex <= endIndex {
+	for s.nextIndex <= endIndex && !s.targetReached() {
+		if err := s.waitIfPaused(ctx); err != nil {
+			return err
+		}
+
 		br, exists := cache[s.nextIndex]
 		if !exists {
 			// Wait for more blocks if we aren't
@@ -365,12 +753,17 @@ func (s *Syncer) processBlocks(
 			// will need to make another call to the node
 			// as it is likely in a reorg.
 			delete(cache, s.nextIndex)
+			s.updateCacheBytes(-br.size)
 		}
 
 		lastProcessed := s.nextIndex
+		start := time.Now()
 		if err := s.processBlock(ctx, br); err != nil {
 			return fmt.Errorf("%w: %v", ErrBlockProcessFailed, err)
 		}
+		s.processingLatency = movingAverage(s.processingLatency, time.Since(start))
+		s.markProcessed()
+		s.reportProgress(ctx, false)
 
 		if s.nextIndex < lastProcessed && reorgStart == -1 {
 			reorgStart = lastProcessed
@@ -389,6 +782,71 @@ type blockResult struct {
 	index      int64
 	block      *types.Block
 	orphanHead bool
+
+	// data is the output of the
+	// Preprocessor (if provided).
+	data interface{}
+
+	// size is the estimated size of the
+	// blockResult in bytes (populated when
+	// added to the cache).
+	size int
+
+	// fetchDuration is how long it took
+	// to fetch the block.
+	fetchDuration time.Duration
+}
+
+// updateCacheBytes adds delta to the number of bytes
+// held by fetched blocks waiting to be processed.
+func (s *Syncer) updateCacheBytes(delta int) {
+	s.concurrencyLock.Lock()
+	defer s.concurrencyLock.Unlock()
+
+	s.cacheBytes += delta
+}
+
+// CacheBytes returns the estimated number of bytes held by
+// fetched blocks waiting to be processed. This is safe to
+// call while Sync is running.
+func (s *Syncer) CacheBytes() int {
+	s.concurrencyLock.Lock()
+	defer s.concurrencyLock.Unlock()
+
+	return s.cacheBytes
+}
+
+// movingAverage returns an exponentially weighted moving average
+// of latencies. If there is no average yet, latest is returned.
+func movingAverage(average time.Duration, latest time.Duration) time.Duration {
+	if average == 0 {
+		return latest
+	}
+
+	return time.Duration(
+		float64(average)*(1-latencySmoothing) + float64(latest)*latencySmoothing,
+	)
+}
+
+// handlerBoundConcurrency returns the maximum concurrency that
+// is useful given how quickly the handler processes blocks. Fetching
+// blocks faster than the handler can process them only grows the
+// queue of blocks held in memory.
+func (s *Syncer) handlerBoundConcurrency() int64 {
+	if s.fetchLatency == 0 || s.processingLatency == 0 {
+		return s.maxConcurrency
+	}
+
+	bound := int64(s.fetchLatency/s.processingLatency) + 1
+	if bound < MinConcurrency {
+		return MinConcurrency
+	}
+
+	if bound > s.maxConcurrency {
+		return s.maxConcurrency
+	}
+
+	return bound
 }
 
 func (s *Syncer) adjustWorkers() bool {
@@ -410,10 +868,20 @@ func (s *Syncer) adjustWorkers() bool {
 	// multiply average block size by concurrency
 	estimatedMaxCache := max * float64(s.concurrency)
 
+	// In adaptive mode, blocks waiting on the handler reduce
+	// the memory available for fetching and concurrency is capped
+	// at what the handler can keep up with.
+	cacheSize := float64(s.cacheSize)
+	concurrencyLimit := s.maxConcurrency
+	if s.adaptive {
+		cacheSize = float64(s.memoryBudget) - max*float64(s.queueDepth)
+		concurrencyLimit = s.handlerBoundConcurrency()
+	}
+
 	// If < cacheSize, increase concurrency by 1 up to MaxConcurrency
 	shouldCreate := false
-	if estimatedMaxCache+max < float64(s.cacheSize) &&
-		s.concurrency < s.maxConcurrency &&
+	if estimatedMaxCache+max < cacheSize &&
+		s.concurrency < concurrencyLimit &&
 		s.lastAdjustment > s.adjustmentWindow {
 		s.goalConcurrency++
 		s.concurrency++
@@ -429,8 +897,11 @@ func (s *Syncer) adjustWorkers() bool {
 	// If >= cacheSize, decrease concurrency however many necessary to fit max cache size.
 	//
 	// Note: We always will decrease size, regardless of last adjustment.
-	if estimatedMaxCache > float64(s.cacheSize) {
-		newGoalConcurrency := int64(float64(s.cacheSize) / max)
+	if estimatedMaxCache > cacheSize || s.goalConcurrency > concurrencyLimit {
+		newGoalConcurrency := int64(cacheSize / max)
+		if newGoalConcurrency > concurrencyLimit {
+			newGoalConcurrency = concurrencyLimit
+		}
 		if newGoalConcurrency < MinConcurrency {
 			newGoalConcurrency = MinConcurrency
 		}
@@ -467,7 +938,7 @@ func (s *Syncer) handleSeenBlock(
 		return nil
 	}
 
-	return s.handler.BlockSeen(ctx, result.block)
+	return s.seeBlock(ctx, result.block)
 }
 
 func (s *Syncer) sequenceBlocks( // nolint:golint
@@ -476,18 +947,34 @@ func (s *Syncer) sequenceBlocks( // nolint:golint
 	g *errgroup.Group,
 	blockIndices chan int64,
 	fetchedBlocks chan *blockResult,
+	preparedBlocks chan *blockResult,
 	endIndex int64,
 ) error {
 	cache := make(map[int64]*blockResult)
-	for result := range fetchedBlocks {
+	for result := range preparedBlocks {
+		result.size = utils.SizeOf(result)
+		if existing, ok := cache[result.index]; ok {
+			s.updateCacheBytes(-existing.size)
+		}
 		cache[result.index] = result
+		s.updateCacheBytes(result.size)
 
 		if err := s.processBlocks(ctx, cache, endIndex); err != nil {
 			return fmt.Errorf("%w: %v", ErrBlocksProcessMultipleFailed, err)
 		}
 
+		// Stop sequencing if the target index was
+		// lowered while syncing the range.
+		if s.targetReached() {
+			return nil
+		}
+
+		// Track handler backpressure for adaptive mode.
+		s.queueDepth = len(cache)
+		s.fetchLatency = movingAverage(s.fetchLatency, result.fetchDuration)
+
 		// Determine if concurrency should be adjusted.
-		s.recentBlockSizes = append(s.recentBlockSizes, utils.SizeOf(result))
+		s.recentBlockSizes = append(s.recentBlockSizes, result.size)
 		s.lastAdjustment++
 
 		s.concurrencyLock.Lock()
@@ -550,6 +1037,8 @@ func (s *Syncer) syncRange(
 
 	// Reset sync variables
 	s.recentBlockSizes = []int{}
+	s.queueDepth = 0
+	s.updateCacheBytes(-s.CacheBytes())
 	s.lastAdjustment = 0
 	s.doneLoading = false
 	s.concurrency = startingConcurrency
@@ -563,7 +1052,16 @@ func (s *Syncer) syncRange(
 	// return immediately if the context is canceled).
 	//
 	// Source: https://godoc.org/golang.org/x/sync/errgroup
-	g, pipelineCtx := errgroup.WithContext(ctx)
+	//
+	// stageCtx is canceled if any block preparation stage fails
+	// so that fetching stops.
+	stageCtx, cancelStages := context.WithCancel(ctx)
+	defer cancelStages()
+
+	stopWatchdog := s.startWatchdog(stageCtx, cancelStages)
+	defer stopWatchdog()
+
+	g, pipelineCtx := errgroup.WithContext(stageCtx)
 	g.Go(func() error {
 		return s.addBlockIndices(pipelineCtx, blockIndices, s.nextIndex, endIndex)
 	})
@@ -581,17 +1079,43 @@ func (s *Syncer) syncRange(
 		close(fetchedBlocks)
 	}()
 
+	preparedBlocks, stages := s.startStages(stageCtx, cancelStages, fetchedBlocks)
 	if err := s.sequenceBlocks(
 		ctx,
 		pipelineCtx,
 		g,
 		blockIndices,
 		fetchedBlocks,
+		preparedBlocks,
 		endIndex,
 	); err != nil {
 		return err
 	}
 
+	// If the watchdog detected a stall, all fetch workers
+	// were canceled. We wait for them to exit so the range
+	// can be retried.
+	if s.takeStalled() {
+		_ = stages.Wait()
+		_ = g.Wait()
+		return ErrSyncStalled
+	}
+
+	// If the target index was lowered, we stop fetching
+	// blocks and wait for all goroutines to exit.
+	if s.targetReached() {
+		cancelStages()
+		_ = stages.Wait()
+		_ = g.Wait()
+		return nil
+	}
+
+	// Stage errors cause fetching to be canceled, so we
+	// check them first to return the underlying error.
+	if err := stages.Wait(); err != nil {
+		return fmt.Errorf("%w: unable to sync to %d", err, endIndex)
+	}
+
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("%w: unable to sync to %d", err, endIndex)
 	}
@@ -610,19 +1134,15 @@ func (s *Syncer) Tip() *types.BlockIdentifier {
 	return s.tip
 }
 
-// Sync cycles endlessly until there is an error
-// or the requested range is synced. When the requested
-// range is synced, context is canceled.
-func (s *Syncer) Sync(
-	ctx context.Context,
-	startIndex int64,
-	endIndex int64,
-) error {
-	if err := s.setStart(ctx, startIndex); err != nil {
-		return fmt.Errorf("%w: %v", ErrSetStartIndexFailed, err)
-	}
-
+// syncIndices walks block indices from s.nextIndex
+// until there is an error or the target index is synced.
+func (s *Syncer) syncIndices(ctx context.Context) error {
 	for {
+		if err := s.waitIfPaused(ctx); err != nil {
+			return err
+		}
+
+		endIndex := s.TargetIndex()
 		rangeEnd, halt, err := s.nextSyncableRange(
 			ctx,
 			endIndex,
@@ -632,10 +1152,11 @@ func (s *Syncer) Sync(
 		}
 
 		if halt {
-			if s.nextIndex > endIndex && endIndex != -1 {
+			if s.targetReached() {
 				break
 			}
 
+			s.reportProgress(ctx, false)
 			time.Sleep(defaultSyncSleep)
 			continue
 		}
@@ -647,6 +1168,14 @@ func (s *Syncer) Sync(
 		}
 
 		err = s.syncRange(ctx, rangeEnd)
+		if errors.Is(err, ErrSyncStalled) {
+			if err := s.recoverStall(ctx); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		if err != nil {
 			return fmt.Errorf("%w: unable to sync to %d", err, rangeEnd)
 		}
@@ -656,11 +1185,56 @@ func (s *Syncer) Sync(
 		}
 	}
 
+	return nil
+}
+
+// Sync cycles endlessly until there is an error
+// or the requested range is synced. When the requested
+// range is synced, context is canceled.
+//
+// If WithBlockEvents is provided and the Helper implements
+// EventsHelper, the syncer is driven by /events/blocks. If
+// the Rosetta implementation does not support /events/blocks,
+// the syncer falls back to walking block indices.
+//
+// If WithProgressHandler is provided, a final *Progress
+// snapshot is reported when the requested range is synced.
+//
+// endIndex can be updated while syncing with SetTargetIndex
+// and syncing can be paused with Pause.
+func (s *Syncer) Sync(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) error {
+	if err := s.setStart(ctx, startIndex); err != nil {
+		return fmt.Errorf("%w: %v", ErrSetStartIndexFailed, err)
+	}
+	s.resetProgress(time.Now())
+	s.SetTargetIndex(endIndex)
+
+	events, ok := asEventsHelper(s.helper)
+	if s.useEvents && ok {
+		err := s.syncEvents(ctx, events)
+		switch {
+		case errors.Is(err, ErrEventsUnsupported):
+			log.Printf("%s, falling back to syncing by index\n", err.Error())
+			if err := s.syncIndices(ctx); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+	} else if err := s.syncIndices(ctx); err != nil {
+		return err
+	}
+
 	if startIndex == -1 {
 		startIndex = s.genesisBlock.Index
 	}
 
+	s.reportProgress(ctx, true)
 	s.cancel()
-	log.Printf("Finished syncing %d-%d\n", startIndex, endIndex)
+	log.Printf("Finished syncing %d-%d\n", startIndex, s.TargetIndex())
 	return nil
 }
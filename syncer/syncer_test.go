@@ -1123,3 +1123,479 @@ func TestSync_DynamicOverhead(t *testing.T) {
 	mockHelper.AssertExpectations(t)
 	mockHandler.AssertExpectations(t)
 }
+
+type memoryCheckpointStore struct {
+	checkpoint *Checkpoint
+	saves      int
+	saveErr    error
+}
+
+func (m *memoryCheckpointStore) SaveCheckpoint(
+	ctx context.Context,
+	checkpoint *Checkpoint,
+) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+
+	m.checkpoint = checkpoint
+	m.saves++
+	return nil
+}
+
+func (m *memoryCheckpointStore) LoadCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	return m.checkpoint, nil
+}
+
+func TestSync_Checkpoint(t *testing.T) {
+	blocks := createBlocks(0, 20, "")
+	store := &memoryCheckpointStore{}
+
+	syncRange := func(
+		ctx context.Context,
+		syncer *Syncer,
+		start int64,
+		end int64,
+	) error {
+		mockHelper := syncer.helper.(*mocks.Helper)
+		mockHandler := syncer.handler.(*mocks.Handler)
+		mockHelper.On("NetworkStatus", ctx, networkIdentifier).Return(&types.NetworkStatusResponse{
+			CurrentBlockIdentifier: blocks[20].BlockIdentifier,
+			GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+		}, nil)
+		for i := start; i <= end; i++ {
+			index := i
+			b := blocks[index]
+			mockHelper.On(
+				"Block",
+				mock.AnythingOfType("*context.cancelCtx"),
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(b, nil).Once()
+			mockHandler.On(
+				"BlockSeen",
+				mock.AnythingOfType("*context.cancelCtx"),
+				b,
+			).Return(nil).Once()
+			mockHandler.On(
+				"BlockAdded",
+				mock.AnythingOfType("*context.valueCtx"),
+				b,
+			).Return(nil).Once()
+		}
+
+		return syncer.Sync(ctx, -1, end)
+	}
+
+	// Sync the first half of the blocks
+	ctx, cancel := context.WithCancel(context.Background())
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer, err := NewFromCheckpoint(
+		ctx,
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		store,
+		WithPastBlockLimit(5),
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, syncRange(ctx, syncer, 0, 10))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+
+	assert.Equal(t, 11, store.saves)
+	assert.Equal(t, &Checkpoint{
+		LastSynced: blocks[10].BlockIdentifier,
+		NextIndex:  11,
+		PastBlocks: []*types.BlockIdentifier{
+			blocks[6].BlockIdentifier,
+			blocks[7].BlockIdentifier,
+			blocks[8].BlockIdentifier,
+			blocks[9].BlockIdentifier,
+			blocks[10].BlockIdentifier,
+		},
+	}, store.checkpoint)
+
+	// Resume from the checkpoint (only blocks 11-20
+	// should be fetched).
+	ctx, cancel = context.WithCancel(context.Background())
+	mockHelper = &mocks.Helper{}
+	mockHandler = &mocks.Handler{}
+	syncer, err = NewFromCheckpoint(
+		ctx,
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		store,
+		WithPastBlockLimit(5),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, store.checkpoint.PastBlocks, syncer.pastBlocks)
+	assert.NoError(t, syncRange(ctx, syncer, 11, 20))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	assert.Equal(t, int64(21), store.checkpoint.NextIndex)
+	assert.Equal(t, blocks[20].BlockIdentifier, store.checkpoint.LastSynced)
+
+	t.Run("save error", func(t *testing.T) {
+		syncer := New(
+			networkIdentifier,
+			&mocks.Helper{},
+			&mocks.Handler{},
+			func() {},
+			WithCheckpointStore(&memoryCheckpointStore{saveErr: errors.New("disk full")}),
+		)
+		err := syncer.processBlock(context.Background(), &blockResult{index: 0})
+		assert.True(t, errors.Is(err, ErrSaveCheckpointFailed))
+	})
+
+	t.Run("handler checkpoints", func(t *testing.T) {
+		store := &handlerCheckpointStore{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(
+			networkIdentifier,
+			&mocks.Helper{},
+			mockHandler,
+			func() {},
+			WithPastBlockLimit(5),
+			WithCheckpointStore(store),
+		)
+		syncer.genesisBlock = blocks[0].BlockIdentifier
+		syncer.nextIndex = 0
+
+		for _, b := range blocks[:3] {
+			block := b
+			mockHandler.On(
+				"BlockAdded",
+				mock.AnythingOfType("*context.valueCtx"),
+				block,
+			).Run(func(args mock.Arguments) {
+				store.checkpoint = CheckpointFromContext(args.Get(0).(context.Context))
+			}).Return(nil).Once()
+			assert.NoError(
+				t,
+				syncer.processBlock(context.Background(), &blockResult{
+					index: block.BlockIdentifier.Index,
+					block: block,
+				}),
+			)
+		}
+
+		// The Handler saved each checkpoint.
+		assert.Equal(t, 0, store.saves)
+		assert.Equal(t, &Checkpoint{
+			LastSynced: blocks[2].BlockIdentifier,
+			NextIndex:  3,
+			PastBlocks: []*types.BlockIdentifier{
+				blocks[0].BlockIdentifier,
+				blocks[1].BlockIdentifier,
+				blocks[2].BlockIdentifier,
+			},
+		}, store.checkpoint)
+
+		// Omitted blocks are not provided to the
+		// Handler, so the syncer saves them.
+		assert.NoError(t, syncer.processBlock(context.Background(), &blockResult{index: 3}))
+		assert.Equal(t, 1, store.saves)
+		assert.Equal(t, int64(4), store.checkpoint.NextIndex)
+		mockHandler.AssertExpectations(t)
+	})
+}
+
+type handlerCheckpointStore struct {
+	memoryCheckpointStore
+}
+
+func (h *handlerCheckpointStore) SavesCheckpointInHandler() bool {
+	return true
+}
+
+func TestAdjustWorkers_Adaptive(t *testing.T) {
+	var tests = map[string]struct {
+		memoryBudget      int
+		queueDepth        int
+		fetchLatency      time.Duration
+		processingLatency time.Duration
+
+		shouldCreate    bool
+		goalConcurrency int64
+	}{
+		"no latency data": {
+			memoryBudget:    1000,
+			shouldCreate:    true,
+			goalConcurrency: 5,
+		},
+		"handler keeps up": {
+			memoryBudget:      1000,
+			fetchLatency:      100 * time.Millisecond,
+			processingLatency: 10 * time.Millisecond,
+			shouldCreate:      true,
+			goalConcurrency:   5,
+		},
+		"handler backpressure": {
+			memoryBudget:      1000,
+			fetchLatency:      100 * time.Millisecond,
+			processingLatency: 50 * time.Millisecond,
+			shouldCreate:      false,
+			goalConcurrency:   3,
+		},
+		"queue exceeds memory budget": {
+			memoryBudget:    1000,
+			queueDepth:      8,
+			shouldCreate:    false,
+			goalConcurrency: 2,
+		},
+		"queue fills memory budget": {
+			memoryBudget:    1000,
+			queueDepth:      10,
+			shouldCreate:    false,
+			goalConcurrency: MinConcurrency,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			syncer := New(
+				networkIdentifier,
+				&mocks.Helper{},
+				&mocks.Handler{},
+				func() {},
+				WithSizeMultiplier(1),
+				WithAdaptiveConcurrency(test.memoryBudget),
+			)
+			syncer.concurrency = 4
+			syncer.goalConcurrency = 4
+			syncer.lastAdjustment = syncer.adjustmentWindow + 1
+			syncer.recentBlockSizes = []int{100}
+			syncer.queueDepth = test.queueDepth
+			syncer.fetchLatency = test.fetchLatency
+			syncer.processingLatency = test.processingLatency
+
+			assert.Equal(t, test.shouldCreate, syncer.adjustWorkers())
+			assert.Equal(t, test.goalConcurrency, syncer.goalConcurrency)
+		})
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	assert.Equal(t, 10*time.Millisecond, movingAverage(0, 10*time.Millisecond))
+	assert.Equal(
+		t,
+		28*time.Millisecond,
+		movingAverage(20*time.Millisecond, 60*time.Millisecond),
+	)
+}
+
+type batchHandler struct {
+	*mocks.Handler
+
+	removed [][]*types.BlockIdentifier
+	err     error
+}
+
+func (h *batchHandler) BlocksRemoved(
+	ctx context.Context,
+	blocks []*types.BlockIdentifier,
+) error {
+	if h.err != nil {
+		return h.err
+	}
+
+	h.removed = append(h.removed, blocks)
+	return nil
+}
+
+func TestProcessBlock_DeepReorg(t *testing.T) {
+	ctx := context.Background()
+	original := createBlocks(0, 5, "")
+
+	// fork replaces blocks 2-5 with a different chain
+	fork := createBlocks(2, 5, "other ")
+	fork[0].ParentBlockIdentifier = original[1].BlockIdentifier
+
+	pastBlocks := func(blocks ...*types.Block) []*types.BlockIdentifier {
+		identifiers := []*types.BlockIdentifier{}
+		for _, block := range blocks {
+			identifiers = append(identifiers, block.BlockIdentifier)
+		}
+
+		return identifiers
+	}
+
+	t.Run("reorg deeper than past blocks", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(networkIdentifier, mockHelper, mockHandler, nil)
+		syncer.genesisBlock = original[0].BlockIdentifier
+		syncer.pastBlocks = pastBlocks(original[3])
+		syncer.nextIndex = 4
+
+		mockHelper.On(
+			"Block",
+			ctx,
+			networkIdentifier,
+			types.ConstructPartialBlockIdentifier(original[3].BlockIdentifier),
+		).Return(original[3], nil).Once()
+		mockHandler.On("BlockRemoved", ctx, original[3].BlockIdentifier).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: fork[2]}))
+		assert.Equal(t, int64(3), syncer.nextIndex)
+		assert.Equal(t, pastBlocks(original[2]), syncer.pastBlocks)
+
+		mockHelper.On(
+			"Block",
+			ctx,
+			networkIdentifier,
+			types.ConstructPartialBlockIdentifier(original[2].BlockIdentifier),
+		).Return(nil, errors.New("block not found")).Once()
+		err := syncer.processBlock(ctx, &blockResult{block: fork[1]})
+		assert.True(t, errors.Is(err, ErrFetchAncestorFailed))
+		assert.Equal(t, int64(3), syncer.nextIndex)
+		assert.Equal(t, pastBlocks(original[2]), syncer.pastBlocks)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("max reorg depth", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		mockHandler := &mocks.Handler{}
+		syncer := New(
+			networkIdentifier,
+			mockHelper,
+			mockHandler,
+			nil,
+			WithMaxReorgDepth(1),
+		)
+		syncer.genesisBlock = original[0].BlockIdentifier
+		syncer.pastBlocks = pastBlocks(original[0], original[1], original[2], original[3])
+		syncer.nextIndex = 4
+
+		mockHandler.On("BlockRemoved", ctx, original[3].BlockIdentifier).Return(nil).Once()
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: fork[2]}))
+
+		err := syncer.processBlock(ctx, &blockResult{block: fork[1]})
+		assert.True(t, errors.Is(err, ErrMaxReorgDepthExceeded))
+		assert.Equal(t, int64(3), syncer.nextIndex)
+
+		mockHelper.AssertExpectations(t)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("batch removal", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		handler := &batchHandler{Handler: &mocks.Handler{}}
+		syncer := New(networkIdentifier, mockHelper, handler, nil)
+		syncer.genesisBlock = original[0].BlockIdentifier
+		syncer.pastBlocks = pastBlocks(original[0], original[1], original[2], original[3], original[4])
+		syncer.nextIndex = 5
+
+		for _, block := range fork[:3] {
+			index := block.BlockIdentifier.Index
+			mockHelper.On(
+				"Block",
+				ctx,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(block, nil).Once()
+			handler.On("BlockSeen", ctx, block).Return(nil).Once()
+		}
+
+		assert.NoError(t, syncer.processBlock(ctx, &blockResult{block: fork[3]}))
+		assert.Equal(t, [][]*types.BlockIdentifier{
+			pastBlocks(original[4], original[3], original[2]),
+		}, handler.removed)
+		assert.Equal(t, int64(2), syncer.nextIndex)
+		assert.Equal(t, pastBlocks(original[0], original[1]), syncer.pastBlocks)
+
+		mockHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+	})
+
+	t.Run("batch removal exceeds max reorg depth", func(t *testing.T) {
+		mockHelper := &mocks.Helper{}
+		handler := &batchHandler{Handler: &mocks.Handler{}}
+		syncer := New(networkIdentifier, mockHelper, handler, nil, WithMaxReorgDepth(2))
+		syncer.genesisBlock = original[0].BlockIdentifier
+		syncer.pastBlocks = pastBlocks(original[0], original[1], original[2], original[3], original[4])
+		syncer.nextIndex = 5
+
+		for _, block := range fork[1:3] {
+			index := block.BlockIdentifier.Index
+			mockHelper.On(
+				"Block",
+				ctx,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(block, nil).Once()
+			handler.On("BlockSeen", ctx, block).Return(nil).Once()
+		}
+
+		err := syncer.processBlock(ctx, &blockResult{block: fork[3]})
+		assert.True(t, errors.Is(err, ErrMaxReorgDepthExceeded))
+		assert.Empty(t, handler.removed)
+		assert.Equal(t, int64(5), syncer.nextIndex)
+
+		mockHelper.AssertExpectations(t)
+		handler.AssertExpectations(t)
+	})
+}
+
+func TestAddBlockIndices_MaxCacheBytes(t *testing.T) {
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		&mocks.Handler{},
+		func() {},
+		WithMaxCacheBytes(100),
+	)
+	syncer.concurrency = 1
+	syncer.updateCacheBytes(100)
+	assert.Equal(t, 100, syncer.CacheBytes())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blockIndices := make(chan int64, 10)
+	go func() {
+		_ = syncer.addBlockIndices(ctx, blockIndices, 0, 0)
+	}()
+
+	// No indices are added while the cache is full
+	select {
+	case <-blockIndices:
+		t.Fatal("index added while cache is full")
+	case <-time.After(2 * defaultFetchSleep):
+	}
+
+	syncer.updateCacheBytes(-1)
+	assert.Equal(t, int64(0), <-blockIndices)
+}
+
+func TestSync_MaxCacheBytes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mockHelper := &mocks.Helper{}
+	mockHandler := &mocks.Handler{}
+	syncer := New(
+		networkIdentifier,
+		mockHelper,
+		mockHandler,
+		cancel,
+		WithMaxCacheBytes(1),
+	)
+
+	blocks := createBlocks(0, 9, "")
+	mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: blocks[9].BlockIdentifier,
+		GenesisBlockIdentifier: blocks[0].BlockIdentifier,
+	}, nil)
+	mockRangeBlocks(mockHelper, mockHandler, blocks)
+
+	assert.NoError(t, syncer.Sync(ctx, -1, 9))
+	mockHelper.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+	assert.Equal(t, 0, syncer.CacheBytes())
+	assert.Equal(t, 0, syncer.progress().CacheBytes)
+}
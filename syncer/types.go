@@ -71,10 +71,42 @@ const (
 	// when we are at tip but want to keep syncing.
 	defaultSyncSleep = 2 * time.Second
 
+	// latencySmoothing is the weight given to the most recent
+	// observation when updating latency moving averages in
+	// adaptive mode.
+	latencySmoothing = 0.2
+
+	// DefaultProgressInterval is the default amount of
+	// time between calls to a ProgressHandler.
+	DefaultProgressInterval = 10 * time.Second
+
+	// DefaultStageWorkers is the default number of workers
+	// used by each block preparation stage (see WithBlockAssertion
+	// and WithPreprocessor).
+	DefaultStageWorkers = 4
+
+	// DefaultGlobalConcurrency is the default maximum number
+	// of blocks a MultiSyncer fetches concurrently (across
+	// all networks).
+	DefaultGlobalConcurrency = int64(64) // nolint:gomnd
+
+	// DefaultMempoolPollInterval is the default amount of
+	// time the MempoolSyncer waits between mempool polls.
+	DefaultMempoolPollInterval = 2 * time.Second
+
+	// DefaultMempoolConcurrency is the default number of
+	// mempool transactions the MempoolSyncer will try to
+	// get concurrently.
+	DefaultMempoolConcurrency = 4
+
 	// defaultFetchSleep is the amount of time to sleep
 	// when we are loading more blocks to fetch but we
 	// already have a backlog >= to concurrency.
 	defaultFetchSleep = 500 * time.Millisecond
+
+	// prefetchWindow is the number of block indices
+	// hinted to a BlockPrefetcher at once.
+	prefetchWindow = int64(128) // nolint:gomnd
 )
 
 // Handler is called at various times during the sync cycle
@@ -101,6 +133,160 @@ type Handler interface {
 	) error
 }
 
+// BlockPrefetcher is an optional extension of Helper. If the
+// Helper provided to the syncer implements BlockPrefetcher, the
+// syncer hints which block indices it will request next so that
+// they can be loaded ahead of time (ex: from disk into memory
+// when replaying blocks from storage).
+type BlockPrefetcher interface {
+	// PrefetchBlocks must not block. Blocks from startIndex
+	// to endIndex (inclusive) will be requested soon.
+	PrefetchBlocks(
+		ctx context.Context,
+		startIndex int64,
+		endIndex int64,
+	)
+}
+
+// EventsHelper is an optional extension of Helper. If the Helper
+// provided to the syncer implements EventsHelper and WithBlockEvents
+// is provided, the syncer is driven by the sequence of BlockEvents
+// returned by /events/blocks instead of walking block indices. If
+// /events/blocks is not implemented, EventsBlocks should return an
+// error wrapping ErrEventsUnsupported, client.ErrNotImplemented, or
+// fetcher.ErrRequestNotImplemented (the syncer then falls back to
+// walking block indices).
+type EventsHelper interface {
+	EventsBlocks(
+		ctx context.Context,
+		network *types.NetworkIdentifier,
+		offset *int64,
+		limit *int64,
+	) (int64, []*types.BlockEvent, error)
+}
+
+// BlockInfo provides a HandlerV2 with context about
+// each block it is invoked with.
+type BlockInfo struct {
+	// Sequence is incremented each time a block is added or
+	// removed (starting at 1) and can be used to order handler
+	// invocations. Sequence is 0 when a block is seen (as blocks
+	// are seen concurrently and out of order).
+	Sequence int64
+
+	// AtTip is true if the block is at (or past) the last
+	// observed tip. If AtTip is false, the syncer is catching
+	// up and the handler may want to batch writes.
+	AtTip bool
+
+	// ReorgID identifies the reorg a removed block is a part
+	// of. All blocks removed in the same reorg share a ReorgID.
+	// ReorgID is 0 for blocks that are seen or added.
+	ReorgID int64
+
+	// Deadline is a best effort hint of when the handler should
+	// return to avoid delaying the syncer (the syncer expects the
+	// next block to be ready by then). If the context provided to
+	// the syncer has an earlier deadline, that deadline is used.
+	// Deadline is zero when a block is seen.
+	Deadline time.Time
+}
+
+// HandlerV2 is the same as Handler, except that each method
+// is provided with a *BlockInfo. Use AdaptHandlerV2 to provide
+// a HandlerV2 anywhere a Handler is accepted.
+type HandlerV2 interface {
+	BlockSeen(
+		ctx context.Context,
+		block *types.Block,
+		info *BlockInfo,
+	) error
+
+	BlockAdded(
+		ctx context.Context,
+		block *types.Block,
+		info *BlockInfo,
+	) error
+
+	BlockRemoved(
+		ctx context.Context,
+		block *types.BlockIdentifier,
+		info *BlockInfo,
+	) error
+}
+
+// ConnectionRecycler is an optional extension of Helper. If the
+// Helper implements ConnectionRecycler, RecycleConnections is
+// invoked before the syncer restarts fetch workers after a stall
+// (see WithStallDetection).
+type ConnectionRecycler interface {
+	RecycleConnections(ctx context.Context) error
+}
+
+// Stall is provided to a StallCallback when the syncer
+// has not processed a block for the stall timeout.
+type Stall struct {
+	// NextIndex is the index the syncer is
+	// waiting to process.
+	NextIndex int64
+
+	// LastProcessed is the last block added by
+	// the syncer (nil if no blocks have been added).
+	LastProcessed *types.BlockIdentifier
+
+	// Duration is how long it has been since
+	// a block was processed.
+	Duration time.Duration
+
+	// Recovering is true if the syncer will restart
+	// its fetch workers to attempt to recover.
+	Recovering bool
+}
+
+// StallCallback is invoked when a stall is detected.
+type StallCallback func(ctx context.Context, stall *Stall)
+
+// BatchRemovalHandler is an optional extension of Handler. If
+// the Handler provided to the syncer implements BatchRemovalHandler,
+// the syncer determines all blocks orphaned by a reorg before invoking
+// the handler and then calls BlocksRemoved once (instead of calling
+// BlockRemoved for each orphaned block). This allows downstream storage
+// to perform an atomic multi-block rollback.
+type BatchRemovalHandler interface {
+	// BlocksRemoved is invoked with all orphaned blocks,
+	// ordered from the previous tip to the oldest orphaned
+	// block (the child of the fork point).
+	BlocksRemoved(
+		ctx context.Context,
+		blocks []*types.BlockIdentifier,
+	) error
+}
+
+// BlockAssertion is invoked concurrently on each fetched block
+// before it is provided to the Handler. If an error is returned,
+// syncing stops. The Block method of an *asserter.Asserter
+// can be used directly (ex: WithBlockAssertion(a.Block, 8)).
+type BlockAssertion func(block *types.Block) error
+
+// Preprocessor is invoked concurrently on each fetched block
+// (after BlockAssertion) before it is sequenced. This allows
+// expensive, block-local work (ex: parsing balance changes)
+// to be parallelized. The result is provided to the Handler
+// if it implements PreprocessedHandler.
+type Preprocessor func(ctx context.Context, block *types.Block) (interface{}, error)
+
+// PreprocessedHandler is an optional extension of Handler. If a
+// Preprocessor is provided to the syncer and the Handler implements
+// PreprocessedHandler, PreprocessedBlockAdded is invoked instead
+// of BlockAdded.
+type PreprocessedHandler interface {
+	PreprocessedBlockAdded(
+		ctx context.Context,
+		block *types.Block,
+		data interface{},
+	) error
+}
+
 // Helper is called at various times during the sync cycle
 // to get information about a blockchain network. It is
 // common to implement this helper using the Fetcher package.
@@ -117,6 +303,133 @@ type Helper interface {
 	) (*types.Block, error)
 }
 
+// BlockArchive is a local source of previously fetched
+// blocks. It can be wrapped with NewArchiveHelper to
+// re-run a Handler over blocks without querying a
+// Rosetta implementation. *modules.BlockStorage
+// satisfies this interface.
+type BlockArchive interface {
+	GetBlock(
+		context.Context,
+		*types.PartialBlockIdentifier,
+	) (*types.Block, error)
+
+	GetHeadBlockIdentifier(context.Context) (*types.BlockIdentifier, error)
+
+	GetOldestBlockIndex(context.Context) (int64, error)
+}
+
+// Checkpoint is a snapshot of syncer progress that
+// can be used to resume syncing after a restart.
+type Checkpoint struct {
+	// LastSynced is the last block added by the syncer. This
+	// is nil if no blocks have been added.
+	LastSynced *types.BlockIdentifier `json:"last_synced,omitempty"`
+
+	// NextIndex is the next block index the syncer
+	// will attempt to process.
+	NextIndex int64 `json:"next_index"`
+
+	// PastBlocks are the most recently processed blocks
+	// (used by the syncer to handle reorgs).
+	PastBlocks []*types.BlockIdentifier `json:"past_blocks"`
+
+	// EventSequence is the next BlockEvent sequence the
+	// syncer will process when syncing with WithBlockEvents.
+	EventSequence int64 `json:"event_sequence,omitempty"`
+}
+
+// CheckpointStore is used by the syncer to persist
+// a *Checkpoint after each block is processed.
+type CheckpointStore interface {
+	// SaveCheckpoint is invoked after the Handler successfully
+	// processes a block. The entire *Checkpoint should be written
+	// atomically (so that a crash never results in a partially
+	// written checkpoint).
+	SaveCheckpoint(
+		ctx context.Context,
+		checkpoint *Checkpoint,
+	) error
+
+	// LoadCheckpoint returns the last saved *Checkpoint. If
+	// no checkpoint has been saved, it should return nil.
+	LoadCheckpoint(
+		ctx context.Context,
+	) (*Checkpoint, error)
+}
+
+// HandlerCheckpointStore is a CheckpointStore that writes
+// each *Checkpoint in the same database transaction the
+// Handler uses to process a block (so a crash can never
+// persist one without the other). The syncer attaches the
+// *Checkpoint of each Handler invocation to its context
+// (see CheckpointFromContext) and only invokes SaveCheckpoint
+// when progress is made without invoking the Handler (ex: an
+// omitted block).
+type HandlerCheckpointStore interface {
+	CheckpointStore
+
+	// SavesCheckpointInHandler is invoked once when the
+	// CheckpointStore is provided to the syncer. If it returns
+	// false, the syncer invokes SaveCheckpoint after each
+	// Handler invocation instead.
+	SavesCheckpointInHandler() bool
+}
+
+// BlockRange is an inclusive range of block
+// indices to sync with SyncRanges.
+type BlockRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// RangeCallback is invoked by SyncRanges
+// after each *BlockRange is synced.
+type RangeCallback func(ctx context.Context, blockRange *BlockRange) error
+
+// Progress is a snapshot of syncer progress
+// provided to a ProgressHandler.
+type Progress struct {
+	// BlocksSynced is the number of blocks added
+	// by the syncer since Sync was invoked.
+	BlocksSynced int64 `json:"blocks_synced"`
+
+	// OrphansProcessed is the number of blocks removed
+	// by the syncer (in reorgs) since Sync was invoked.
+	OrphansProcessed int64 `json:"orphans_processed"`
+
+	// TipReached is true if the syncer has added
+	// the last observed tip.
+	TipReached bool `json:"tip_reached"`
+
+	// BehindBy is the number of blocks between the last
+	// block added by the syncer and the last observed tip.
+	// If tip has not been observed (ex: when syncing with
+	// WithBlockEvents), this is 0.
+	BehindBy int64 `json:"behind_by"`
+
+	// BlocksPerSecond is a rolling average of
+	// the rate at which blocks are added.
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+
+	// CacheBytes is the estimated number of bytes held by
+	// fetched blocks waiting to be processed.
+	CacheBytes int `json:"cache_bytes"`
+}
+
+// ProgressHandler is invoked by the syncer on a configurable
+// interval (see WithProgressHandler) with a *Progress snapshot.
+// This makes it possible to export sync progress (ex: to Prometheus
+// or a status endpoint) without parsing logs.
+type ProgressHandler interface {
+	// SyncProgress is invoked synchronously by the syncer, so
+	// implementations should return quickly.
+	SyncProgress(
+		ctx context.Context,
+		progress *Progress,
+	)
+}
+
 // Syncer coordinates blockchain syncing without relying on
 // a storage interface. Instead, it calls a provided Handler
 // whenever a block is added or removed. This provides the client
@@ -144,6 +457,75 @@ type Syncer struct {
 	pastBlocks     []*types.BlockIdentifier
 	pastBlockLimit int
 
+	// If a reorg is deeper than pastBlocks, the syncer refetches
+	// orphaned blocks by hash to determine their parents. If
+	// maxReorgDepth is > 0, the syncer errors instead of
+	// removing more than maxReorgDepth consecutive blocks.
+	maxReorgDepth int
+	reorgDepth    int
+
+	// handlerSequence is the number of blocks added or removed
+	// and reorgID is the number of reorgs observed (used to
+	// populate *BlockInfo).
+	handlerSequence int64
+	reorgID         int64
+
+	// If useEvents is true, the syncer is driven by /events/blocks
+	// (see EventsHelper). eventSequence is the next BlockEvent
+	// sequence to process.
+	useEvents     bool
+	eventsLimit   int64
+	eventSequence int64
+
+	// Used to control a running syncer (see Pause, Resume, and
+	// SetTargetIndex). resumed is non-nil (and closed on Resume)
+	// while the syncer is paused.
+	controlLock sync.Mutex
+	resumed     chan struct{}
+	targetIndex int64
+
+	// Fetched blocks are asserted and preprocessed in separate
+	// stages (each with its own worker pool) before they are
+	// handed off (in order) to the Handler.
+	assertion         BlockAssertion
+	assertWorkers     int
+	preprocessor      Preprocessor
+	preprocessWorkers int
+
+	// If stallTimeout > 0, a watchdog invokes stallCallback when no
+	// block is processed for stallTimeout while syncing a range. If
+	// stallRecovery is true, fetch workers are also restarted. All
+	// watchdog state is protected by watchdogLock.
+	stallTimeout   time.Duration
+	stallCallback  StallCallback
+	stallRecovery  bool
+	watchdogLock   sync.Mutex
+	lastProcessed  *types.BlockIdentifier
+	lastProgressAt time.Time
+	stallIndex     int64
+	stalled        bool
+
+	// If a progressHandler is provided, a *Progress snapshot
+	// is provided to it every progressInterval. blocksSynced and
+	// orphansProcessed are counted from the start of Sync.
+	progressHandler  ProgressHandler
+	progressInterval time.Duration
+	lastProgress     time.Time
+	lastBlocksSynced int64
+	blocksSynced     int64
+	orphansProcessed int64
+	blocksPerSecond  float64
+
+	// If a checkpointStore is provided, a *Checkpoint is
+	// saved after each block is processed. checkpoint is the
+	// *Checkpoint loaded on creation (if any). If
+	// handlerCheckpoints is true, the Handler saves the
+	// *Checkpoint of each block it processes (see
+	// HandlerCheckpointStore).
+	checkpointStore    CheckpointStore
+	checkpoint         *Checkpoint
+	handlerCheckpoints bool
+
 	// Automatically manage concurrency based on the
 	// provided max cache size. The algorithm used here
 	// is a slow rise (to increase concurrency) and fast
@@ -158,8 +540,207 @@ type Syncer struct {
 	adjustmentWindow int64
 	concurrencyLock  sync.Mutex
 
+	// If maxCacheBytes > 0, no more blocks are fetched while the
+	// estimated size of fetched blocks waiting to be processed
+	// (cacheBytes) is at least maxCacheBytes. cacheBytes is
+	// protected by concurrencyLock.
+	maxCacheBytes int
+	cacheBytes    int
+
+	// In adaptive mode, concurrency is also bounded by handler
+	// backpressure. Blocks waiting to be processed by the handler
+	// (queueDepth) count against memoryBudget and fetch concurrency
+	// is capped at the rate the handler can process blocks (estimated
+	// using fetchLatency and processingLatency moving averages).
+	adaptive          bool
+	memoryBudget      int
+	queueDepth        int
+	fetchLatency      time.Duration
+	processingLatency time.Duration
+
 	// doneLoading is used to coordinate adding goroutines
 	// when close to the end of syncing a range.
 	doneLoading     bool
 	doneLoadingLock sync.Mutex
 }
+
+// MempoolHandler is called by the MempoolSyncer whenever the
+// contents of the mempool change.
+type MempoolHandler interface {
+	// TransactionAdded is invoked when a transaction
+	// is first observed in the mempool.
+	TransactionAdded(
+		ctx context.Context,
+		transaction *types.Transaction,
+	) error
+
+	// TransactionRemoved is invoked when a transaction is no longer
+	// in the mempool (it was confirmed, evicted, or dropped).
+	TransactionRemoved(
+		ctx context.Context,
+		transaction *types.TransactionIdentifier,
+	) error
+
+	// TransactionReplaced is invoked when a transaction leaves the
+	// mempool in the same poll that another transaction sharing one of
+	// its replacement keys enters the mempool (ex: replace-by-fee).
+	TransactionReplaced(
+		ctx context.Context,
+		replaced *types.TransactionIdentifier,
+		transaction *types.Transaction,
+	) error
+}
+
+// MempoolHelper is called by the MempoolSyncer to get the contents
+// of the mempool. It is common to implement this helper using the
+// Fetcher package.
+type MempoolHelper interface {
+	Mempool(
+		context.Context,
+		*types.NetworkIdentifier,
+	) ([]*types.TransactionIdentifier, error)
+
+	// MempoolTransaction should return an error wrapping
+	// ErrMempoolTransactionNotFound if the transaction was
+	// evicted from the mempool after it was listed by Mempool.
+	MempoolTransaction(
+		context.Context,
+		*types.NetworkIdentifier,
+		*types.TransactionIdentifier,
+	) (*types.Transaction, error)
+}
+
+// ReplacementKeys returns the keys used to determine if a
+// transaction replaces another transaction in the mempool. Two
+// transactions that share any key are considered conflicting.
+type ReplacementKeys func(*types.Transaction) []string
+
+// MempoolSyncer tracks the unconfirmed transactions in a node's
+// mempool by polling it at a fixed interval (or whenever Notify
+// is called). Like the Syncer, it calls a provided MempoolHandler
+// whenever a transaction is added, removed, or replaced.
+type MempoolSyncer struct {
+	network *types.NetworkIdentifier
+	helper  MempoolHelper
+	handler MempoolHandler
+
+	pollInterval    time.Duration
+	concurrency     int
+	replacementKeys ReplacementKeys
+	notify          chan struct{}
+
+	// transactions are all transactions
+	// currently in the mempool (keyed by hash).
+	// lock is held to modify transactions and
+	// pollLock is held for the duration of Poll.
+	transactions map[string]*types.Transaction
+	lock         sync.Mutex
+	pollLock     sync.Mutex
+}
+
+// NetworkHandler is called by the MultiSyncer whenever a block is
+// seen, added, or removed on any of its networks. It is the same as
+// Handler, except that each method is provided the network the
+// block belongs to.
+type NetworkHandler interface {
+	BlockSeen(
+		ctx context.Context,
+		network *types.NetworkIdentifier,
+		block *types.Block,
+	) error
+
+	BlockAdded(
+		ctx context.Context,
+		network *types.NetworkIdentifier,
+		block *types.Block,
+	) error
+
+	BlockRemoved(
+		ctx context.Context,
+		network *types.NetworkIdentifier,
+		block *types.BlockIdentifier,
+	) error
+}
+
+// MultiSyncer runs a Syncer for each of several networks
+// concurrently in a single process. All syncers share a
+// global fetch concurrency limit and memory budget and
+// invoke a single NetworkHandler.
+type MultiSyncer struct {
+	networks []*types.NetworkIdentifier
+	helper   Helper
+	handler  NetworkHandler
+
+	globalConcurrency int64
+	memoryBudget      int
+	syncerOptions     []Option
+	networkOptions    map[string][]Option
+
+	// syncers are keyed by types.Hash(network)
+	syncers map[string]*Syncer
+}
+
+// ValidationFailure is a block that failed
+// validation (see Validator).
+type ValidationFailure struct {
+	Block *types.BlockIdentifier `json:"block"`
+
+	// Stage is the validation stage that failed
+	// ("assertion" or "preprocess").
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// ValidationReport summarizes a validation run.
+type ValidationReport struct {
+	// BlocksChecked is the number of blocks that were
+	// fetched and validated (including failures).
+	BlocksChecked int64                `json:"blocks_checked"`
+	Failures      []*ValidationFailure `json:"failures"`
+
+	// FetchDuration, AssertDuration, and PreprocessDuration
+	// are the total time spent in each stage (summed across
+	// all workers).
+	FetchDuration      time.Duration `json:"fetch_duration"`
+	AssertDuration     time.Duration `json:"assert_duration"`
+	PreprocessDuration time.Duration `json:"preprocess_duration"`
+
+	// Elapsed is the wall clock duration of validation.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Validator fetches and validates blocks using the syncer
+// without invoking a Handler (a "dry-run"). Unlike syncing,
+// validation failures do not stop validation. Instead, they
+// are collected in a *ValidationReport.
+type Validator struct {
+	network      *types.NetworkIdentifier
+	helper       Helper
+	assertion    BlockAssertion
+	preprocessor Preprocessor
+	options      []Option
+
+	report     *ValidationReport
+	reportLock sync.Mutex
+}
+
+// ArchiveHelper is a Helper that sources blocks from
+// a BlockArchive instead of a Rosetta implementation.
+// This allows a Handler to be re-run over previously
+// fetched blocks (ex: reindexing) at disk speed.
+type ArchiveHelper struct {
+	archive BlockArchive
+}
+
+// DirectoryArchive is a BlockArchive of JSON-serialized
+// blocks stored in a directory (one file per block,
+// named by block index).
+type DirectoryArchive struct {
+	dir string
+
+	// oldestIndex and headIndex are -1
+	// when the archive is empty.
+	oldestIndex int64
+	headIndex   int64
+	indexLock   sync.Mutex
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// AssertionStage is the ValidationFailure
+	// stage of BlockAssertion failures.
+	AssertionStage = "assertion"
+
+	// PreprocessStage is the ValidationFailure
+	// stage of Preprocessor failures.
+	PreprocessStage = "preprocess"
+)
+
+// NewValidator creates a new Validator. Each block is asserted
+// with assertion and (if preprocessor is not nil) preprocessed
+// (ex: with BalanceChangesPreprocessor). Any provided options are
+// applied to the underlying syncer.
+func NewValidator(
+	network *types.NetworkIdentifier,
+	helper Helper,
+	assertion BlockAssertion,
+	preprocessor Preprocessor,
+	options ...Option,
+) *Validator {
+	return &Validator{
+		network:      network,
+		helper:       helper,
+		assertion:    assertion,
+		preprocessor: preprocessor,
+		options:      options,
+	}
+}
+
+// validatorHelper records the time spent fetching blocks.
+type validatorHelper struct {
+	Helper
+
+	validator *Validator
+}
+
+func (h *validatorHelper) Block(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	identifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	start := time.Now()
+	block, err := h.Helper.Block(ctx, network, identifier)
+	h.validator.record(func(r *ValidationReport) {
+		r.FetchDuration += time.Since(start)
+	})
+
+	return block, err
+}
+
+func (h *validatorHelper) unwrap() Helper {
+	return h.Helper
+}
+
+// validatorHandler counts checked blocks
+// (without persisting anything).
+type validatorHandler struct {
+	validator *Validator
+}
+
+func (h *validatorHandler) BlockSeen(ctx context.Context, block *types.Block) error {
+	return nil
+}
+
+func (h *validatorHandler) BlockAdded(ctx context.Context, block *types.Block) error {
+	h.validator.record(func(r *ValidationReport) {
+		r.BlocksChecked++
+	})
+
+	return nil
+}
+
+func (h *validatorHandler) BlockRemoved(
+	ctx context.Context,
+	block *types.BlockIdentifier,
+) error {
+	return nil
+}
+
+// record applies update to the report while
+// holding the report lock.
+func (v *Validator) record(update func(r *ValidationReport)) {
+	v.reportLock.Lock()
+	defer v.reportLock.Unlock()
+
+	update(v.report)
+}
+
+// fail records a *ValidationFailure. If the block cannot
+// be sequenced (it is missing identifiers), err is returned
+// to stop validation.
+func (v *Validator) fail(block *types.Block, stage string, err error) error {
+	v.record(func(r *ValidationReport) {
+		r.Failures = append(r.Failures, &ValidationFailure{
+			Block: block.BlockIdentifier,
+			Stage: stage,
+			Error: err.Error(),
+		})
+	})
+
+	if block.BlockIdentifier == nil || block.ParentBlockIdentifier == nil {
+		return err
+	}
+
+	return nil
+}
+
+func (v *Validator) assert(block *types.Block) error {
+	start := time.Now()
+	err := v.assertion(block)
+	v.record(func(r *ValidationReport) {
+		r.AssertDuration += time.Since(start)
+	})
+
+	if err != nil {
+		return v.fail(block, AssertionStage, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) preprocess(ctx context.Context, block *types.Block) (interface{}, error) {
+	start := time.Now()
+	_, err := v.preprocessor(ctx, block)
+	v.record(func(r *ValidationReport) {
+		r.PreprocessDuration += time.Since(start)
+	})
+
+	if err != nil {
+		return nil, v.fail(block, PreprocessStage, err)
+	}
+
+	return nil, nil
+}
+
+// Validate fetches and validates all blocks from startIndex
+// to endIndex (see Syncer.Sync). If an error occurs that prevents
+// validation from continuing, the *ValidationReport of all blocks
+// validated so far is returned with the error.
+func (v *Validator) Validate(
+	ctx context.Context,
+	startIndex int64,
+	endIndex int64,
+) (*ValidationReport, error) {
+	v.report = &ValidationReport{Failures: []*ValidationFailure{}}
+
+	// Provided options are applied first so that worker
+	// counts set with WithBlockAssertion or WithPreprocessor
+	// are respected (the functions themselves are replaced).
+	options := append([]Option{}, v.options...)
+	options = append(options, func(s *Syncer) {
+		s.assertion = v.assert
+		if s.assertWorkers <= 0 {
+			s.assertWorkers = DefaultStageWorkers
+		}
+
+		s.preprocessor = nil
+		if v.preprocessor != nil {
+			s.preprocessor = v.preprocess
+			if s.preprocessWorkers <= 0 {
+				s.preprocessWorkers = DefaultStageWorkers
+			}
+		}
+	})
+
+	syncer := New(
+		v.network,
+		&validatorHelper{Helper: v.helper, validator: v},
+		&validatorHandler{validator: v},
+		func() {},
+		options...,
+	)
+
+	start := time.Now()
+	err := syncer.Sync(ctx, startIndex, endIndex)
+
+	v.reportLock.Lock()
+	defer v.reportLock.Unlock()
+
+	v.report.Elapsed = time.Since(start)
+	return v.report, err
+}
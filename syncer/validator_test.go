@@ -0,0 +1,161 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+func TestValidator(t *testing.T) {
+	blocks := createBlocks(0, 4, "")
+	mockValidatorHelper := func(blocks []*types.Block) *mocks.Helper {
+		mockHelper := &mocks.Helper{}
+		mockHelper.On("NetworkStatus", mock.Anything, networkIdentifier).Return(
+			&types.NetworkStatusResponse{
+				CurrentBlockIdentifier: &types.BlockIdentifier{Hash: "block 4", Index: 4},
+				GenesisBlockIdentifier: &types.BlockIdentifier{Hash: "block 0", Index: 0},
+			},
+			nil,
+		)
+		for _, block := range blocks {
+			index := block.BlockIdentifier.Index
+			mockHelper.On(
+				"Block",
+				mock.Anything,
+				networkIdentifier,
+				&types.PartialBlockIdentifier{Index: &index},
+			).Return(block, nil).Once()
+		}
+
+		return mockHelper
+	}
+
+	t.Run("collects failures", func(t *testing.T) {
+		mockHelper := mockValidatorHelper(blocks)
+		validator := NewValidator(
+			networkIdentifier,
+			mockHelper,
+			func(block *types.Block) error {
+				if block.BlockIdentifier.Index == 2 {
+					return errors.New("transaction 0 is invalid")
+				}
+
+				return nil
+			},
+			func(ctx context.Context, block *types.Block) (interface{}, error) {
+				if block.BlockIdentifier.Index == 3 {
+					return nil, errors.New("balance change is invalid")
+				}
+
+				return nil, nil
+			},
+		)
+
+		report, err := validator.Validate(context.Background(), -1, 4)
+		assert.NoError(t, err)
+		mockHelper.AssertExpectations(t)
+
+		assert.Equal(t, int64(5), report.BlocksChecked)
+		assert.ElementsMatch(t, []*ValidationFailure{
+			{
+				Block: blocks[2].BlockIdentifier,
+				Stage: AssertionStage,
+				Error: "transaction 0 is invalid",
+			},
+			{
+				Block: blocks[3].BlockIdentifier,
+				Stage: PreprocessStage,
+				Error: "balance change is invalid",
+			},
+		}, report.Failures)
+		assert.True(t, report.FetchDuration > 0)
+		assert.True(t, report.AssertDuration > 0)
+		assert.True(t, report.PreprocessDuration > 0)
+		assert.True(t, report.Elapsed > 0)
+	})
+
+	t.Run("worker options", func(t *testing.T) {
+		mockHelper := mockValidatorHelper(blocks)
+		validator := NewValidator(
+			networkIdentifier,
+			mockHelper,
+			func(block *types.Block) error { return nil },
+			nil,
+			WithBlockAssertion(nil, 1),
+		)
+
+		report, err := validator.Validate(context.Background(), -1, 4)
+		assert.NoError(t, err)
+		mockHelper.AssertExpectations(t)
+		assert.Equal(t, int64(5), report.BlocksChecked)
+		assert.Len(t, report.Failures, 0)
+		assert.Equal(t, int64(0), int64(report.PreprocessDuration))
+	})
+
+	t.Run("helper extensions", func(t *testing.T) {
+		helper := &extendedHelper{Helper: mockValidatorHelper(blocks)}
+		validator := NewValidator(
+			networkIdentifier,
+			helper,
+			func(block *types.Block) error { return nil },
+			nil,
+		)
+
+		report, err := validator.Validate(context.Background(), -1, 4)
+		assert.NoError(t, err)
+		helper.Helper.AssertExpectations(t)
+		assert.Equal(t, int64(5), report.BlocksChecked)
+		assert.Equal(t, [][2]int64{{0, 4}}, helper.prefetched)
+	})
+
+	t.Run("unsequenceable block", func(t *testing.T) {
+		invalid := createBlocks(0, 4, "")
+		invalid[2].ParentBlockIdentifier = nil
+
+		mockHelper := mockValidatorHelper(invalid)
+		validator := NewValidator(
+			networkIdentifier,
+			mockHelper,
+			func(block *types.Block) error {
+				if block.ParentBlockIdentifier == nil {
+					return errors.New("parent block identifier is nil")
+				}
+
+				return nil
+			},
+			nil,
+			WithMaxConcurrency(1),
+		)
+
+		report, err := validator.Validate(context.Background(), -1, 4)
+		assert.True(t, errors.Is(err, ErrBlockAssertionFailed))
+		assert.Equal(t, []*ValidationFailure{
+			{
+				Block: invalid[2].BlockIdentifier,
+				Stage: AssertionStage,
+				Error: "parent block identifier is nil",
+			},
+		}, report.Failures)
+		assert.True(t, report.BlocksChecked <= 2)
+	})
+}
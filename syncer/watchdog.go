@@ -0,0 +1,154 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// watchdogChecks is the number of times the watchdog
+	// checks for a stall per stall timeout.
+	watchdogChecks = 4
+)
+
+// markProcessed records that a block was processed
+// (resetting the stall timer).
+func (s *Syncer) markProcessed() {
+	if s.stallTimeout <= 0 {
+		return
+	}
+
+	s.watchdogLock.Lock()
+	defer s.watchdogLock.Unlock()
+
+	s.lastProgressAt = time.Now()
+	s.stallIndex = s.nextIndex
+	if len(s.pastBlocks) > 0 {
+		s.lastProcessed = s.pastBlocks[len(s.pastBlocks)-1]
+	}
+}
+
+// checkStall returns a *Stall if no block has been
+// processed for the stall timeout.
+func (s *Syncer) checkStall(now time.Time) *Stall {
+	s.watchdogLock.Lock()
+	defer s.watchdogLock.Unlock()
+
+	duration := now.Sub(s.lastProgressAt)
+	if duration < s.stallTimeout {
+		return nil
+	}
+
+	// We reset the stall timer so the callback is invoked
+	// at most once per stall timeout.
+	s.lastProgressAt = now
+	s.stalled = s.stallRecovery
+
+	return &Stall{
+		NextIndex:     s.stallIndex,
+		LastProcessed: s.lastProcessed,
+		Duration:      duration,
+		Recovering:    s.stallRecovery,
+	}
+}
+
+// takeStalled returns a boolean indicating if the watchdog
+// requested recovery (and clears the request).
+func (s *Syncer) takeStalled() bool {
+	s.watchdogLock.Lock()
+	defer s.watchdogLock.Unlock()
+
+	stalled := s.stalled
+	s.stalled = false
+	return stalled
+}
+
+// watchdog checks for stalls while a range is synced until
+// done is closed. If recovery is enabled, cancel is invoked
+// to stop all fetch workers.
+func (s *Syncer) watchdog(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	done chan struct{},
+) {
+	ticker := time.NewTicker(s.stallTimeout / watchdogChecks)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			stall := s.checkStall(now)
+			if stall == nil {
+				continue
+			}
+
+			log.Printf("syncer stalled for %s at index %d\n", stall.Duration, stall.NextIndex)
+			if s.stallCallback != nil {
+				s.stallCallback(ctx, stall)
+			}
+
+			if stall.Recovering {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// startWatchdog starts a watchdog for the range being
+// synced (if stall detection is enabled). The returned
+// function stops the watchdog.
+func (s *Syncer) startWatchdog(
+	ctx context.Context,
+	cancel context.CancelFunc,
+) func() {
+	if s.stallTimeout <= 0 {
+		return func() {}
+	}
+
+	s.watchdogLock.Lock()
+	s.lastProgressAt = time.Now()
+	s.stallIndex = s.nextIndex
+	s.stalled = false
+	s.watchdogLock.Unlock()
+
+	done := make(chan struct{})
+	go s.watchdog(ctx, cancel, done)
+	return func() { close(done) }
+}
+
+// recoverStall recycles Helper connections (if supported)
+// before fetch workers are restarted.
+func (s *Syncer) recoverStall(ctx context.Context) error {
+	log.Printf("restarting fetch workers at index %d\n", s.nextIndex)
+	recycler, ok := asConnectionRecycler(s.helper)
+	if !ok {
+		return nil
+	}
+
+	if err := recycler.RecycleConnections(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrRecycleConnectionsFailed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks "github.com/coinbase/rosetta-sdk-go/mocks/syncer"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// stuckHelper blocks the first fetch of stuckIndex
+// until the provided context is canceled.
+type stuckHelper struct {
+	blocks     []*types.Block
+	stuckIndex int64
+
+	lock       sync.Mutex
+	stuck      bool
+	recycled   int
+	recycleErr error
+}
+
+func (h *stuckHelper) NetworkStatus(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+) (*types.NetworkStatusResponse, error) {
+	return &types.NetworkStatusResponse{
+		CurrentBlockIdentifier: h.blocks[len(h.blocks)-1].BlockIdentifier,
+		GenesisBlockIdentifier: h.blocks[0].BlockIdentifier,
+	}, nil
+}
+
+func (h *stuckHelper) Block(
+	ctx context.Context,
+	network *types.NetworkIdentifier,
+	identifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	h.lock.Lock()
+	stick := *identifier.Index == h.stuckIndex && !h.stuck
+	if stick {
+		h.stuck = true
+	}
+	h.lock.Unlock()
+
+	if stick {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return h.blocks[*identifier.Index], nil
+}
+
+func (h *stuckHelper) RecycleConnections(ctx context.Context) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.recycled++
+	return h.recycleErr
+}
+
+func TestSync_StallRecovery(t *testing.T) {
+	blocks := createBlocks(0, 9, "")
+
+	t.Run("recover", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		helper := &stuckHelper{blocks: blocks, stuckIndex: 3}
+		mockHandler := &mocks.Handler{}
+		for _, block := range blocks {
+			mockHandler.On("BlockSeen", mock.Anything, block).Return(nil)
+			mockHandler.On("BlockAdded", mock.Anything, block).Return(nil).Once()
+		}
+
+		var stallsLock sync.Mutex
+		stalls := []*Stall{}
+		syncer := New(
+			networkIdentifier,
+			helper,
+			mockHandler,
+			cancel,
+			WithStallDetection(100*time.Millisecond, func(ctx context.Context, stall *Stall) {
+				stallsLock.Lock()
+				defer stallsLock.Unlock()
+
+				stalls = append(stalls, stall)
+			}, true),
+		)
+
+		assert.NoError(t, syncer.Sync(ctx, -1, 9))
+		mockHandler.AssertExpectations(t)
+		assert.Equal(t, 1, helper.recycled)
+
+		assert.Len(t, stalls, 1)
+		assert.Equal(t, int64(3), stalls[0].NextIndex)
+		assert.Equal(t, blocks[2].BlockIdentifier, stalls[0].LastProcessed)
+		assert.True(t, stalls[0].Duration >= 100*time.Millisecond)
+		assert.True(t, stalls[0].Recovering)
+	})
+
+	t.Run("recycle failure", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		helper := &stuckHelper{
+			blocks:     blocks,
+			stuckIndex: 0,
+			recycleErr: errors.New("connection pool closed"),
+		}
+		mockHandler := &mocks.Handler{}
+		mockHandler.On("BlockSeen", mock.Anything, mock.Anything).Return(nil)
+		syncer := New(
+			networkIdentifier,
+			helper,
+			mockHandler,
+			cancel,
+			WithStallDetection(100*time.Millisecond, nil, true),
+		)
+
+		err := syncer.Sync(ctx, -1, 9)
+		assert.True(t, errors.Is(err, ErrRecycleConnectionsFailed))
+		mockHandler.AssertNotCalled(t, "BlockAdded", mock.Anything, mock.Anything)
+		cancel()
+	})
+}
+
+func TestCheckStall(t *testing.T) {
+	syncer := New(
+		networkIdentifier,
+		&mocks.Helper{},
+		&mocks.Handler{},
+		func() {},
+		WithStallDetection(time.Minute, nil, false),
+	)
+	syncer.nextIndex = 5
+	syncer.pastBlocks = []*types.BlockIdentifier{{Hash: "block 4", Index: 4}}
+	syncer.markProcessed()
+
+	start := syncer.lastProgressAt
+	assert.Nil(t, syncer.checkStall(start.Add(time.Second)))
+
+	stall := syncer.checkStall(start.Add(time.Minute))
+	assert.Equal(t, &Stall{
+		NextIndex:     5,
+		LastProcessed: &types.BlockIdentifier{Hash: "block 4", Index: 4},
+		Duration:      time.Minute,
+	}, stall)
+	assert.False(t, syncer.takeStalled())
+
+	// The stall timer is reset after a stall is reported
+	assert.Nil(t, syncer.checkStall(start.Add(time.Minute+time.Second)))
+}
@@ -0,0 +1,293 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrAmountNil is returned when a nil amount
+	// is formatted, negated, or used in arithmetic
+	// (or a comparison).
+	ErrAmountNil = errors.New("amount cannot be nil")
+
+	// ErrAmountCurrencyMismatch is returned when amounts
+	// of different currencies are added, subtracted, or
+	// compared (or when a decimal amount is parsed with
+	// a symbol that is not the symbol of its currency).
+	ErrAmountCurrencyMismatch = errors.New("amount currencies do not match")
+
+	// ErrDecimalInvalid is returned when a decimal
+	// amount (ex: "1.23") cannot be parsed.
+	ErrDecimalInvalid = errors.New("decimal amount is invalid")
+
+	// ErrDecimalPrecisionExceeded is returned when a decimal
+	// amount has more (non-zero) decimal places than its
+	// currency (so it cannot be represented in atomic units).
+	ErrDecimalPrecisionExceeded = errors.New(
+		"decimal amount has more decimal places than currency",
+	)
+)
+
+// NewAmountFromBigInt constructs an *Amount of
+// value (in atomic units) in currency.
+func NewAmountFromBigInt(value *big.Int, currency *Currency) *Amount {
+	return NewAmount(value.String(), currency)
+}
+
+// FormatValue renders value (in atomic units) as a decimal
+// with decimals decimal places (ex: "123000000" with 8
+// decimals is "1.23"). Trailing zeros are removed.
+func FormatValue(value string, decimals int32) (string, error) {
+	v, err := BigInt(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrAmountValueInvalid, value)
+	}
+
+	if decimals < 0 {
+		return "", fmt.Errorf("%w: decimals %d", ErrCurrencyInvalid, decimals)
+	}
+
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(v).String()
+	if decimals == 0 {
+		return sign + digits, nil
+	}
+
+	// Pad the digits so that there is at
+	// least 1 digit before the decimal point.
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	point := len(digits) - int(decimals)
+	fraction := strings.TrimRight(digits[point:], "0")
+	if len(fraction) == 0 {
+		return sign + digits[:point], nil
+	}
+
+	return sign + digits[:point] + "." + fraction, nil
+}
+
+// ParseValue parses a decimal (ex: "1.23") with at most
+// decimals decimal places to a value in atomic units (ex:
+// "123000000" with 8 decimals).
+func ParseValue(decimal string, decimals int32) (string, error) {
+	if decimals < 0 {
+		return "", fmt.Errorf("%w: decimals %d", ErrCurrencyInvalid, decimals)
+	}
+
+	sign, unsigned := "", decimal
+	switch {
+	case strings.HasPrefix(decimal, "-"):
+		sign, unsigned = "-", decimal[1:]
+	case strings.HasPrefix(decimal, "+"):
+		unsigned = decimal[1:]
+	}
+
+	parts := strings.Split(unsigned, ".")
+	if len(parts) > 2 || len(parts[0])+len(parts[len(parts)-1]) == 0 || !digitsOnly(parts) {
+		return "", fmt.Errorf("%w: %s", ErrDecimalInvalid, decimal)
+	}
+
+	integer, fraction := parts[0], ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+
+	if len(fraction) > int(decimals) {
+		if len(strings.TrimRight(fraction[decimals:], "0")) > 0 {
+			return "", fmt.Errorf(
+				"%w: %s has more than %d decimal places",
+				ErrDecimalPrecisionExceeded,
+				decimal,
+				decimals,
+			)
+		}
+
+		fraction = fraction[:decimals]
+	}
+
+	fraction += strings.Repeat("0", int(decimals)-len(fraction))
+
+	// digitsOnly ensures the value is an integer (so
+	// normalizing it with big.Int does not fail).
+	v, _ := new(big.Int).SetString(sign+integer+fraction, 10)
+	return v.String(), nil
+}
+
+// digitsOnly returns true if all parts
+// only contain the digits 0-9.
+func digitsOnly(parts []string) bool {
+	for _, part := range parts {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// FormatAmount renders amount as a decimal followed by the
+// symbol of its currency (ex: "1.23 BTC" for the value
+// "123000000" of a currency with 8 decimals).
+func FormatAmount(amount *Amount) (string, error) {
+	if amount == nil {
+		return "", fmt.Errorf("%w: unable to format amount", ErrAmountNil)
+	}
+
+	if err := validateAmount(amount); err != nil {
+		return "", err
+	}
+
+	decimal, err := FormatValue(amount.Value, amount.Currency.Decimals)
+	if err != nil {
+		return "", err
+	}
+
+	return decimal + " " + amount.Currency.Symbol, nil
+}
+
+// ParseAmount parses a decimal optionally followed by the
+// symbol of currency (ex: "1.23 BTC" or "1.23") to an
+// *Amount of currency (in atomic units).
+func ParseAmount(decimal string, currency *Currency) (*Amount, error) {
+	if currency == nil || len(currency.Symbol) == 0 || currency.Decimals < 0 {
+		return nil, ErrCurrencyInvalid
+	}
+
+	fields := strings.Fields(decimal)
+	switch len(fields) {
+	case 1:
+	case 2: // nolint:gomnd
+		if fields[1] != currency.Symbol {
+			return nil, fmt.Errorf(
+				"%w: expected %s but got %s",
+				ErrAmountCurrencyMismatch,
+				currency.Symbol,
+				fields[1],
+			)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrDecimalInvalid, decimal)
+	}
+
+	value, err := ParseValue(fields[0], currency.Decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAmount(value, currency), nil
+}
+
+// amountValues returns the values of a and b or an error
+// if either is invalid or their currencies are not equal.
+func amountValues(a *Amount, b *Amount) (*big.Int, *big.Int, error) {
+	if a == nil {
+		return nil, nil, fmt.Errorf("%w: first amount", ErrAmountNil)
+	}
+
+	if b == nil {
+		return nil, nil, fmt.Errorf("%w: second amount", ErrAmountNil)
+	}
+
+	if err := validateAmount(a); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateAmount(b); err != nil {
+		return nil, nil, err
+	}
+
+	if !a.Currency.Equal(b.Currency) {
+		return nil, nil, fmt.Errorf(
+			"%w: %s and %s",
+			ErrAmountCurrencyMismatch,
+			CurrencyString(a.Currency),
+			CurrencyString(b.Currency),
+		)
+	}
+
+	aVal, _ := BigInt(a.Value)
+	bVal, _ := BigInt(b.Value)
+	return aVal, bVal, nil
+}
+
+// AddAmounts returns a+b or an error if the currencies
+// of a and b are not equal. The metadata of a and b
+// is not included in the sum.
+func AddAmounts(a *Amount, b *Amount) (*Amount, error) {
+	aVal, bVal, err := amountValues(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAmountFromBigInt(new(big.Int).Add(aVal, bVal), a.Currency), nil
+}
+
+// SubtractAmounts returns a-b or an error if the currencies
+// of a and b are not equal. The metadata of a and b is not
+// included in the difference.
+func SubtractAmounts(a *Amount, b *Amount) (*Amount, error) {
+	aVal, bVal, err := amountValues(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAmountFromBigInt(new(big.Int).Sub(aVal, bVal), a.Currency), nil
+}
+
+// NegateAmount returns -amount (with
+// the metadata of amount).
+func NegateAmount(amount *Amount) (*Amount, error) {
+	if amount == nil {
+		return nil, fmt.Errorf("%w: unable to negate amount", ErrAmountNil)
+	}
+
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
+
+	value, err := NegateValue(amount.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	negated := amount.Clone()
+	negated.Value = value
+	return negated, nil
+}
+
+// CompareAmounts returns -1 if a < b, 0 if a == b, and 1
+// if a > b (or an error if the currencies of a and b are
+// not equal).
+func CompareAmounts(a *Amount, b *Amount) (int, error) {
+	aVal, bVal, err := amountValues(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	return aVal.Cmp(bVal), nil
+}
@@ -0,0 +1,281 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var eth = &Currency{Symbol: "ETH", Decimals: 18}
+
+func TestFormatValue(t *testing.T) {
+	var tests = map[string]struct {
+		value    string
+		decimals int32
+		result   string
+		err      error
+	}{
+		"integer": {
+			value:    "100000000",
+			decimals: 8,
+			result:   "1",
+		},
+		"fraction": {
+			value:    "123000000",
+			decimals: 8,
+			result:   "1.23",
+		},
+		"less than 1": {
+			value:    "5",
+			decimals: 8,
+			result:   "0.00000005",
+		},
+		"negative": {
+			value:    "-123000000",
+			decimals: 8,
+			result:   "-1.23",
+		},
+		"zero": {
+			value:    "0",
+			decimals: 8,
+			result:   "0",
+		},
+		"no decimals": {
+			value:    "-42",
+			decimals: 0,
+			result:   "-42",
+		},
+		"larger than int64": {
+			value:    "123456789012345678901234567890",
+			decimals: 18,
+			result:   "123456789012.34567890123456789",
+		},
+		"invalid value": {
+			value:    "1.5",
+			decimals: 8,
+			err:      ErrAmountValueInvalid,
+		},
+		"negative decimals": {
+			value:    "1",
+			decimals: -1,
+			err:      ErrCurrencyInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := FormatValue(test.value, test.decimals)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	var tests = map[string]struct {
+		decimal  string
+		decimals int32
+		result   string
+		err      error
+	}{
+		"integer": {
+			decimal:  "1",
+			decimals: 8,
+			result:   "100000000",
+		},
+		"fraction": {
+			decimal:  "1.23",
+			decimals: 8,
+			result:   "123000000",
+		},
+		"leading point": {
+			decimal:  ".5",
+			decimals: 2,
+			result:   "50",
+		},
+		"trailing point": {
+			decimal:  "5.",
+			decimals: 2,
+			result:   "500",
+		},
+		"negative": {
+			decimal:  "-0.00000005",
+			decimals: 8,
+			result:   "-5",
+		},
+		"positive sign": {
+			decimal:  "+1.5",
+			decimals: 1,
+			result:   "15",
+		},
+		"negative zero": {
+			decimal:  "-0.0",
+			decimals: 8,
+			result:   "0",
+		},
+		"extra trailing zeros": {
+			decimal:  "1.2300",
+			decimals: 2,
+			result:   "123",
+		},
+		"larger than int64": {
+			decimal:  "123456789012.34567890123456789",
+			decimals: 18,
+			result:   "123456789012345678901234567890",
+		},
+		"too many decimal places": {
+			decimal:  "1.234",
+			decimals: 2,
+			err:      ErrDecimalPrecisionExceeded,
+		},
+		"empty": {
+			decimal:  "",
+			decimals: 2,
+			err:      ErrDecimalInvalid,
+		},
+		"point only": {
+			decimal:  "-.",
+			decimals: 2,
+			err:      ErrDecimalInvalid,
+		},
+		"multiple points": {
+			decimal:  "1.2.3",
+			decimals: 2,
+			err:      ErrDecimalInvalid,
+		},
+		"multiple signs": {
+			decimal:  "-+1",
+			decimals: 2,
+			err:      ErrDecimalInvalid,
+		},
+		"exponent": {
+			decimal:  "1e2",
+			decimals: 2,
+			err:      ErrDecimalInvalid,
+		},
+		"negative decimals": {
+			decimal:  "1",
+			decimals: -1,
+			err:      ErrCurrencyInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseValue(test.decimal, test.decimals)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestFormatAndParseAmount(t *testing.T) {
+	formatted, err := FormatAmount(NewAmount("123000000", btc))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.23 BTC", formatted)
+
+	_, err = FormatAmount(NewAmount("1", nil))
+	assert.True(t, errors.Is(err, ErrCurrencyInvalid))
+
+	_, err = FormatAmount(nil)
+	assert.True(t, errors.Is(err, ErrAmountNil))
+
+	amount, err := ParseAmount("1.23 BTC", btc)
+	assert.NoError(t, err)
+	assert.Equal(t, NewAmount("123000000", btc), amount)
+
+	amount, err = ParseAmount("1.23", btc)
+	assert.NoError(t, err)
+	assert.Equal(t, NewAmount("123000000", btc), amount)
+
+	_, err = ParseAmount("1.23 ETH", btc)
+	assert.True(t, errors.Is(err, ErrAmountCurrencyMismatch))
+
+	_, err = ParseAmount("1.23 BTC extra", btc)
+	assert.True(t, errors.Is(err, ErrDecimalInvalid))
+
+	_, err = ParseAmount("1.23", nil)
+	assert.True(t, errors.Is(err, ErrCurrencyInvalid))
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	large, ok := new(big.Int).SetString("99999999999999999999999999", 10)
+	assert.True(t, ok)
+	a := NewAmountFromBigInt(large, eth)
+	b := NewAmount("1", eth)
+
+	sum, err := AddAmounts(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, NewAmount("100000000000000000000000000", eth), sum)
+
+	difference, err := SubtractAmounts(b, a)
+	assert.NoError(t, err)
+	assert.Equal(t, NewAmount("-99999999999999999999999998", eth), difference)
+
+	negated, err := NegateAmount(&Amount{
+		Value:    "5",
+		Currency: btc,
+		Metadata: map[string]interface{}{"a": "b"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &Amount{
+		Value:    "-5",
+		Currency: btc,
+		Metadata: map[string]interface{}{"a": "b"},
+	}, negated)
+
+	comparison, err := CompareAmounts(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, comparison)
+
+	comparison, err = CompareAmounts(b, NewAmount("1", eth))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, comparison)
+
+	_, err = AddAmounts(a, NewAmount("1", btc))
+	assert.True(t, errors.Is(err, ErrAmountCurrencyMismatch))
+
+	_, err = SubtractAmounts(a, NewAmount("1", btc))
+	assert.True(t, errors.Is(err, ErrAmountCurrencyMismatch))
+
+	_, err = CompareAmounts(a, NewAmount("1", btc))
+	assert.True(t, errors.Is(err, ErrAmountCurrencyMismatch))
+
+	_, err = AddAmounts(a, NewAmount("1.5", eth))
+	assert.True(t, errors.Is(err, ErrAmountValueInvalid))
+
+	_, err = NegateAmount(nil)
+	assert.True(t, errors.Is(err, ErrAmountNil))
+
+	_, err = AddAmounts(a, nil)
+	assert.True(t, errors.Is(err, ErrAmountNil))
+
+	_, err = CompareAmounts(nil, b)
+	assert.True(t, errors.Is(err, ErrAmountNil))
+}
@@ -0,0 +1,348 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrOperationTypeMissing is returned when an
+	// *Operation is built without a type.
+	ErrOperationTypeMissing = errors.New("operation type is missing")
+
+	// ErrOperationIndexInvalid is returned when an
+	// *Operation is built with a negative index.
+	ErrOperationIndexInvalid = errors.New("operation index is invalid")
+
+	// ErrRelatedOperationInvalid is returned when an *Operation
+	// is related to an operation with an index that is not
+	// less than its own.
+	ErrRelatedOperationInvalid = errors.New(
+		"related operation index must be less than the operation index",
+	)
+
+	// ErrAccountAddressMissing is returned when an
+	// *AccountIdentifier is built without an address.
+	ErrAccountAddressMissing = errors.New("account address is missing")
+
+	// ErrAmountValueInvalid is returned when an *Amount
+	// is built with a value that is not an integer.
+	ErrAmountValueInvalid = errors.New("amount value is not an integer")
+
+	// ErrCurrencyInvalid is returned when an *Amount is
+	// built without a currency (or with a currency without
+	// a symbol or with negative decimals).
+	ErrCurrencyInvalid = errors.New("currency is invalid")
+
+	// ErrCoinChangeInvalid is returned when a *CoinChange is
+	// built without an identifier or with an unknown action.
+	ErrCoinChangeInvalid = errors.New("coin change is invalid")
+
+	// ErrTransactionHashMissing is returned when a
+	// *Transaction is built without a hash.
+	ErrTransactionHashMissing = errors.New("transaction hash is missing")
+)
+
+// NewAccountIdentifier constructs an
+// *AccountIdentifier for address.
+func NewAccountIdentifier(address string) *AccountIdentifier {
+	return &AccountIdentifier{Address: address}
+}
+
+// NewCurrency constructs a *Currency.
+func NewCurrency(symbol string, decimals int32) *Currency {
+	return &Currency{Symbol: symbol, Decimals: decimals}
+}
+
+// NewAmount constructs an *Amount of value
+// (in atomic units) in currency.
+func NewAmount(value string, currency *Currency) *Amount {
+	return &Amount{Value: value, Currency: currency}
+}
+
+// NewOperation constructs an *Operation (without a status,
+// as required when constructing transactions). account and
+// amount are optional.
+func NewOperation(
+	index int64,
+	opType string,
+	account *AccountIdentifier,
+	amount *Amount,
+) *Operation {
+	return &Operation{
+		OperationIdentifier: &OperationIdentifier{Index: index},
+		Type:                opType,
+		Account:             account,
+		Amount:              amount,
+	}
+}
+
+// validateAccount returns an error if account is invalid.
+func validateAccount(account *AccountIdentifier) error {
+	if account == nil {
+		return nil
+	}
+
+	if len(account.Address) == 0 {
+		return ErrAccountAddressMissing
+	}
+
+	if account.SubAccount != nil && len(account.SubAccount.Address) == 0 {
+		return fmt.Errorf("%w: sub-account", ErrAccountAddressMissing)
+	}
+
+	return nil
+}
+
+// validateAmount returns an error if amount is invalid.
+func validateAmount(amount *Amount) error {
+	if amount == nil {
+		return nil
+	}
+
+	if _, err := BigInt(amount.Value); err != nil {
+		return fmt.Errorf("%w: %s", ErrAmountValueInvalid, amount.Value)
+	}
+
+	if amount.Currency == nil || len(amount.Currency.Symbol) == 0 ||
+		amount.Currency.Decimals < 0 {
+		return ErrCurrencyInvalid
+	}
+
+	return nil
+}
+
+// validateCoinChange returns an error if
+// coinChange is invalid.
+func validateCoinChange(coinChange *CoinChange) error {
+	if coinChange == nil {
+		return nil
+	}
+
+	if coinChange.CoinIdentifier == nil || len(coinChange.CoinIdentifier.Identifier) == 0 {
+		return fmt.Errorf("%w: identifier is missing", ErrCoinChangeInvalid)
+	}
+
+	if coinChange.CoinAction != CoinCreated && coinChange.CoinAction != CoinSpent {
+		return fmt.Errorf("%w: unknown action %s", ErrCoinChangeInvalid, coinChange.CoinAction)
+	}
+
+	return nil
+}
+
+// OperationBuilder builds an *Operation. Each With method
+// returns the builder so that calls can be chained:
+//
+//	op, err := types.NewOperationBuilder("TRANSFER").
+//		WithAccount(types.NewAccountIdentifier("addr")).
+//		WithAmount("-100", types.NewCurrency("BTC", 8)).
+//		Build()
+type OperationBuilder struct {
+	op *Operation
+}
+
+// NewOperationBuilder creates a new *OperationBuilder
+// for an operation of opType (with index 0).
+func NewOperationBuilder(opType string) *OperationBuilder {
+	return &OperationBuilder{op: NewOperation(0, opType, nil, nil)}
+}
+
+// WithIndex sets the index of the operation.
+func (b *OperationBuilder) WithIndex(index int64) *OperationBuilder {
+	b.op.OperationIdentifier.Index = index
+	return b
+}
+
+// WithNetworkIndex sets the network index of the operation.
+func (b *OperationBuilder) WithNetworkIndex(networkIndex int64) *OperationBuilder {
+	b.op.OperationIdentifier.NetworkIndex = Int64(networkIndex)
+	return b
+}
+
+// WithRelatedOperations relates the operation to the
+// operations with indices.
+func (b *OperationBuilder) WithRelatedOperations(indices ...int64) *OperationBuilder {
+	for _, index := range indices {
+		b.op.RelatedOperations = append(
+			b.op.RelatedOperations,
+			&OperationIdentifier{Index: index},
+		)
+	}
+
+	return b
+}
+
+// WithStatus sets the status of the operation (only
+// operations returned by the Data API have a status).
+func (b *OperationBuilder) WithStatus(status string) *OperationBuilder {
+	b.op.Status = String(status)
+	return b
+}
+
+// WithAccount sets the account of the operation.
+func (b *OperationBuilder) WithAccount(account *AccountIdentifier) *OperationBuilder {
+	b.op.Account = account
+	return b
+}
+
+// WithSubAccount sets the sub-account of the
+// account of the operation.
+func (b *OperationBuilder) WithSubAccount(
+	address string,
+	metadata map[string]interface{},
+) *OperationBuilder {
+	// The account is copied so that an *AccountIdentifier
+	// passed to WithAccount is not modified.
+	account := AccountIdentifier{}
+	if b.op.Account != nil {
+		account = *b.op.Account
+	}
+
+	account.SubAccount = &SubAccountIdentifier{Address: address, Metadata: metadata}
+	b.op.Account = &account
+	return b
+}
+
+// WithAmount sets the amount of the operation to
+// value (in atomic units) of currency.
+func (b *OperationBuilder) WithAmount(value string, currency *Currency) *OperationBuilder {
+	b.op.Amount = NewAmount(value, currency)
+	return b
+}
+
+// WithCoinChange sets the coin change of the operation.
+func (b *OperationBuilder) WithCoinChange(identifier string, action CoinAction) *OperationBuilder {
+	b.op.CoinChange = &CoinChange{
+		CoinIdentifier: &CoinIdentifier{Identifier: identifier},
+		CoinAction:     action,
+	}
+	return b
+}
+
+// WithMetadata sets key in the metadata of the operation.
+func (b *OperationBuilder) WithMetadata(key string, value interface{}) *OperationBuilder {
+	if b.op.Metadata == nil {
+		b.op.Metadata = map[string]interface{}{}
+	}
+
+	b.op.Metadata[key] = value
+	return b
+}
+
+// Build returns the *Operation or an error if it is invalid.
+// The builder should not be used after Build is called.
+func (b *OperationBuilder) Build() (*Operation, error) {
+	op := b.op
+	if len(op.Type) == 0 {
+		return nil, ErrOperationTypeMissing
+	}
+
+	if op.OperationIdentifier.Index < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrOperationIndexInvalid, op.OperationIdentifier.Index)
+	}
+
+	for _, related := range op.RelatedOperations {
+		if related.Index < 0 || related.Index >= op.OperationIdentifier.Index {
+			return nil, fmt.Errorf("%w: %d", ErrRelatedOperationInvalid, related.Index)
+		}
+	}
+
+	if err := validateAccount(op.Account); err != nil {
+		return nil, err
+	}
+
+	if err := validateAmount(op.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := validateCoinChange(op.CoinChange); err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// TransactionBuilder builds a *Transaction. Operations
+// are indexed in the order they are added.
+type TransactionBuilder struct {
+	tx         *Transaction
+	operations []*OperationBuilder
+}
+
+// NewTransactionBuilder creates a new *TransactionBuilder
+// for the transaction with hash.
+func NewTransactionBuilder(hash string) *TransactionBuilder {
+	return &TransactionBuilder{
+		tx: &Transaction{
+			TransactionIdentifier: &TransactionIdentifier{Hash: hash},
+			Operations:            []*Operation{},
+		},
+	}
+}
+
+// AddOperation adds the operation built by op to the
+// transaction (with the next index).
+func (b *TransactionBuilder) AddOperation(op *OperationBuilder) *TransactionBuilder {
+	b.operations = append(b.operations, op.WithIndex(int64(len(b.operations))))
+	return b
+}
+
+// WithRelatedTransaction relates the transaction to the
+// transaction with hash on network (or on the same network
+// if network is nil).
+func (b *TransactionBuilder) WithRelatedTransaction(
+	network *NetworkIdentifier,
+	hash string,
+	direction Direction,
+) *TransactionBuilder {
+	b.tx.RelatedTransactions = append(b.tx.RelatedTransactions, &RelatedTransaction{
+		NetworkIdentifier:     network,
+		TransactionIdentifier: &TransactionIdentifier{Hash: hash},
+		Direction:             direction,
+	})
+	return b
+}
+
+// WithMetadata sets key in the metadata of the transaction.
+func (b *TransactionBuilder) WithMetadata(key string, value interface{}) *TransactionBuilder {
+	if b.tx.Metadata == nil {
+		b.tx.Metadata = map[string]interface{}{}
+	}
+
+	b.tx.Metadata[key] = value
+	return b
+}
+
+// Build returns the *Transaction or an error if it (or any
+// of its operations) is invalid. The builder should not be
+// used after Build is called.
+func (b *TransactionBuilder) Build() (*Transaction, error) {
+	if len(b.tx.TransactionIdentifier.Hash) == 0 {
+		return nil, ErrTransactionHashMissing
+	}
+
+	for i, opBuilder := range b.operations {
+		op, err := opBuilder.Build()
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation %d", err, i)
+		}
+
+		b.tx.Operations = append(b.tx.Operations, op)
+	}
+
+	return b.tx, nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var btc = &Currency{Symbol: "BTC", Decimals: 8}
+
+func TestNewOperation(t *testing.T) {
+	assert.Equal(t, &Operation{
+		OperationIdentifier: &OperationIdentifier{Index: 1},
+		Type:                "TRANSFER",
+		Account:             &AccountIdentifier{Address: "addr"},
+		Amount:              &Amount{Value: "100", Currency: btc},
+	}, NewOperation(
+		1,
+		"TRANSFER",
+		NewAccountIdentifier("addr"),
+		NewAmount("100", NewCurrency("BTC", 8)),
+	))
+}
+
+func TestOperationBuilder(t *testing.T) {
+	var tests = map[string]struct {
+		builder *OperationBuilder
+
+		op  *Operation
+		err error
+	}{
+		"full operation": {
+			builder: NewOperationBuilder("TRANSFER").
+				WithIndex(2).
+				WithNetworkIndex(5).
+				WithRelatedOperations(0, 1).
+				WithStatus("SUCCESS").
+				WithAccount(NewAccountIdentifier("addr")).
+				WithSubAccount("staking", map[string]interface{}{"validator": "val"}).
+				WithAmount("-100", btc).
+				WithCoinChange("tx:0", CoinSpent).
+				WithMetadata("memo", "hello"),
+			op: &Operation{
+				OperationIdentifier: &OperationIdentifier{Index: 2, NetworkIndex: Int64(5)},
+				RelatedOperations:   []*OperationIdentifier{{Index: 0}, {Index: 1}},
+				Type:                "TRANSFER",
+				Status:              String("SUCCESS"),
+				Account: &AccountIdentifier{
+					Address: "addr",
+					SubAccount: &SubAccountIdentifier{
+						Address:  "staking",
+						Metadata: map[string]interface{}{"validator": "val"},
+					},
+				},
+				Amount: &Amount{Value: "-100", Currency: btc},
+				CoinChange: &CoinChange{
+					CoinIdentifier: &CoinIdentifier{Identifier: "tx:0"},
+					CoinAction:     CoinSpent,
+				},
+				Metadata: map[string]interface{}{"memo": "hello"},
+			},
+		},
+		"intent": {
+			builder: NewOperationBuilder("TRANSFER"),
+			op: &Operation{
+				OperationIdentifier: &OperationIdentifier{Index: 0},
+				Type:                "TRANSFER",
+			},
+		},
+		"missing type": {
+			builder: NewOperationBuilder(""),
+			err:     ErrOperationTypeMissing,
+		},
+		"negative index": {
+			builder: NewOperationBuilder("TRANSFER").WithIndex(-1),
+			err:     ErrOperationIndexInvalid,
+		},
+		"related operation after operation": {
+			builder: NewOperationBuilder("TRANSFER").WithIndex(1).WithRelatedOperations(1),
+			err:     ErrRelatedOperationInvalid,
+		},
+		"missing address": {
+			builder: NewOperationBuilder("TRANSFER").WithSubAccount("staking", nil),
+			err:     ErrAccountAddressMissing,
+		},
+		"missing sub-account address": {
+			builder: NewOperationBuilder("TRANSFER").
+				WithAccount(NewAccountIdentifier("addr")).
+				WithSubAccount("", nil),
+			err: ErrAccountAddressMissing,
+		},
+		"decimal amount": {
+			builder: NewOperationBuilder("TRANSFER").WithAmount("1.5", btc),
+			err:     ErrAmountValueInvalid,
+		},
+		"missing currency": {
+			builder: NewOperationBuilder("TRANSFER").WithAmount("100", nil),
+			err:     ErrCurrencyInvalid,
+		},
+		"negative decimals": {
+			builder: NewOperationBuilder("TRANSFER").WithAmount("100", NewCurrency("BTC", -1)),
+			err:     ErrCurrencyInvalid,
+		},
+		"missing coin": {
+			builder: NewOperationBuilder("TRANSFER").WithCoinChange("", CoinCreated),
+			err:     ErrCoinChangeInvalid,
+		},
+		"unknown coin action": {
+			builder: NewOperationBuilder("TRANSFER").WithCoinChange("tx:0", "coin_burned"),
+			err:     ErrCoinChangeInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			op, err := test.builder.Build()
+			assert.True(t, errors.Is(err, test.err), err)
+			assert.Equal(t, test.op, op)
+		})
+	}
+}
+
+func TestWithSubAccountCopiesAccount(t *testing.T) {
+	account := NewAccountIdentifier("addr")
+	op, err := NewOperationBuilder("TRANSFER").
+		WithAccount(account).
+		WithSubAccount("staking", nil).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "staking", op.Account.SubAccount.Address)
+	assert.Nil(t, account.SubAccount)
+}
+
+func TestTransactionBuilder(t *testing.T) {
+	tx, err := NewTransactionBuilder("tx").
+		AddOperation(NewOperationBuilder("TRANSFER").
+			WithAccount(NewAccountIdentifier("sender")).
+			WithAmount("-100", btc)).
+		AddOperation(NewOperationBuilder("TRANSFER").
+			WithRelatedOperations(0).
+			WithAccount(NewAccountIdentifier("recipient")).
+			WithAmount("100", btc)).
+		WithRelatedTransaction(nil, "parent", Backward).
+		WithMetadata("size", 250).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &Transaction{
+		TransactionIdentifier: &TransactionIdentifier{Hash: "tx"},
+		Operations: []*Operation{
+			{
+				OperationIdentifier: &OperationIdentifier{Index: 0},
+				Type:                "TRANSFER",
+				Account:             &AccountIdentifier{Address: "sender"},
+				Amount:              &Amount{Value: "-100", Currency: btc},
+			},
+			{
+				OperationIdentifier: &OperationIdentifier{Index: 1},
+				RelatedOperations:   []*OperationIdentifier{{Index: 0}},
+				Type:                "TRANSFER",
+				Account:             &AccountIdentifier{Address: "recipient"},
+				Amount:              &Amount{Value: "100", Currency: btc},
+			},
+		},
+		RelatedTransactions: []*RelatedTransaction{
+			{
+				TransactionIdentifier: &TransactionIdentifier{Hash: "parent"},
+				Direction:             Backward,
+			},
+		},
+		Metadata: map[string]interface{}{"size": 250},
+	}, tx)
+
+	// Transactions without operations have an
+	// empty (not null) array of operations.
+	tx, err = NewTransactionBuilder("tx").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Operation{}, tx.Operations)
+
+	_, err = NewTransactionBuilder("").Build()
+	assert.True(t, errors.Is(err, ErrTransactionHashMissing))
+
+	_, err = NewTransactionBuilder("tx").
+		AddOperation(NewOperationBuilder("TRANSFER")).
+		AddOperation(NewOperationBuilder("TRANSFER").WithAmount("1.5", btc)).
+		Build()
+	assert.True(t, errors.Is(err, ErrAmountValueInvalid))
+	assert.Contains(t, err.Error(), "operation 1")
+}
@@ -0,0 +1,259 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CanonicalVersion is the version of the canonical
+	// encoding produced by MarshalCanonical (and hashed by
+	// CanonicalHash). The encoding of a version never changes, so
+	// hashes of the same value are stable across releases
+	// of the SDK (and can be reproduced in other languages).
+	CanonicalVersion = 1
+
+	// maxCanonicalNumberDigits is the maximum number of
+	// digits of a number in the canonical encoding.
+	maxCanonicalNumberDigits = 1024
+)
+
+var (
+	// ErrCanonicalVersionUnsupported is returned when
+	// a value is encoded with an unknown version of
+	// the canonical encoding.
+	ErrCanonicalVersionUnsupported = errors.New("canonical encoding version is not supported")
+
+	// ErrCanonicalNumberUnsupported is returned when a
+	// number cannot be written in the canonical encoding
+	// (it has more than 1024 digits when written without
+	// an exponent).
+	ErrCanonicalNumberUnsupported = errors.New("number is too large for canonical encoding")
+)
+
+// MarshalCanonical returns the canonical encoding (version
+// CanonicalVersion) of v. v is first encoded with encoding/json
+// (so custom MarshalJSON methods and struct tags, including
+// omitempty, are respected) and then rewritten so that the same
+// value always has the same encoding:
+//
+//   - Whitespace is removed.
+//   - Object members are sorted by the UTF-8 bytes of their keys.
+//   - Object members with a null value are removed (so a nil
+//     pointer, slice, or map is equivalent to a missing field).
+//     Empty arrays and objects are preserved and null array
+//     elements are preserved.
+//   - Numbers are written exactly (never rounded to a float64) in
+//     plain decimal notation without an exponent, a positive sign,
+//     leading zeros, or trailing fractional zeros (ex: 1.50e2 is
+//     written as 150 and -0.0 is written as 0).
+//   - Strings are written as UTF-8. Only quotation marks, reverse
+//     solidi, and control characters are escaped (\b, \f, \n, \r,
+//     and \t with their short escape and all others as \u00xx).
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	return MarshalCanonicalVersion(v, CanonicalVersion)
+}
+
+// MarshalCanonicalVersion returns the canonical encoding of
+// v with a particular version of the canonical encoding (see
+// MarshalCanonical).
+func MarshalCanonicalVersion(v interface{}, version int) ([]byte, error) {
+	if version != CanonicalVersion {
+		return nil, fmt.Errorf("%w: %d", ErrCanonicalVersionUnsupported, version)
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := writeCanonical(&b, decoded); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// writeCanonical writes the canonical encoding of
+// v (a value decoded from JSON) to b.
+func writeCanonical(b *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		number, err := canonicalNumber(v.String())
+		if err != nil {
+			return err
+		}
+
+		b.WriteString(number)
+	case string:
+		writeCanonicalString(b, v)
+	case []interface{}:
+		b.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			if err := writeCanonical(b, element); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key, value := range v {
+			if value != nil {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			writeCanonicalString(b, key)
+			b.WriteByte(':')
+			if err := writeCanonical(b, v[key]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value %T", v)
+	}
+
+	return nil
+}
+
+// canonicalNumber returns the canonical encoding of
+// number (a valid JSON number).
+func canonicalNumber(number string) (string, error) {
+	original, sign := number, ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+
+	exponent := 0
+	if i := strings.IndexAny(number, "eE"); i >= 0 {
+		// The exponent is limited to 32 bits so that
+		// computing the length of the number cannot
+		// overflow.
+		parsed, err := strconv.ParseInt(strings.TrimPrefix(number[i+1:], "+"), 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrCanonicalNumberUnsupported, original)
+		}
+
+		exponent, number = int(parsed), number[:i]
+	}
+
+	// The number is digits * 10^exponent.
+	digits := number
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		digits = number[:i] + number[i+1:]
+		exponent -= len(number) - i - 1
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	if len(digits) == 0 {
+		return "0", nil
+	}
+
+	trimmed := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(trimmed)
+	digits = trimmed
+
+	// point is the position of the decimal point in
+	// digits and length is the number of digits
+	// written.
+	point := len(digits) + exponent
+	length := len(digits)
+	if exponent > 0 {
+		length += exponent
+	}
+	if point < 0 {
+		length -= point
+	}
+
+	if length > maxCanonicalNumberDigits {
+		return "", fmt.Errorf("%w: %s", ErrCanonicalNumberUnsupported, original)
+	}
+
+	switch {
+	case exponent >= 0:
+		return sign + digits + strings.Repeat("0", exponent), nil
+	case point > 0:
+		return sign + digits[:point] + "." + digits[point:], nil
+	default:
+		return sign + "0." + strings.Repeat("0", -point) + digits, nil
+	}
+}
+
+// writeCanonicalString writes the
+// canonical encoding of s to b.
+func writeCanonicalString(b *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 { // nolint:gomnd
+				b.WriteString(`\u00`)
+				b.WriteByte(hex[r>>4])
+				b.WriteByte(hex[r&0xF])
+				continue
+			}
+
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	var tests = map[string]struct {
+		value  interface{}
+		result string
+		err    error
+	}{
+		"type": {
+			value: &Operation{
+				OperationIdentifier: &OperationIdentifier{Index: 1},
+				Type:                "TRANSFER",
+				Account:             &AccountIdentifier{Address: "addr"},
+				Metadata:            map[string]interface{}{"b": 1, "a": "<&>"},
+			},
+			result: `{"account":{"address":"addr"},"metadata":{"a":"<&>","b":1},` +
+				`"operation_identifier":{"index":1},"type":"TRANSFER"}`,
+		},
+		"nil": {
+			value:  nil,
+			result: `null`,
+		},
+		"null members": {
+			value: map[string]interface{}{
+				"a": nil,
+				"b": []interface{}{nil},
+				"c": []interface{}{},
+				"d": map[string]interface{}{},
+			},
+			result: `{"b":[null],"c":[],"d":{}}`,
+		},
+		"nil required field": {
+			value:  &Operation{Type: "TRANSFER"},
+			result: `{"type":"TRANSFER"}`,
+		},
+		"numbers": {
+			value: []interface{}{
+				json.Number("1.50e2"),
+				json.Number("-0.0"),
+				json.Number("0.000012"),
+				json.Number("1.2E-7"),
+				json.Number("123456789012345678901234567890"),
+				json.Number("-1e21"),
+				json.Number("10.010"),
+				1.5,
+				int64(100),
+			},
+			result: `[150,0,0.000012,0.00000012,123456789012345678901234567890,` +
+				`-1000000000000000000000,10.01,1.5,100]`,
+		},
+		"raw message": {
+			value:  json.RawMessage(`{ "b": 1.0, "a": [ true, false ] }`),
+			result: `{"a":[true,false],"b":1}`,
+		},
+		"strings": {
+			value:  "quote \" backslash \\ newline \n tab \t control \u0001 unicode   é",
+			result: `"quote \" backslash \\ newline \n tab \t control \u0001 unicode ` + "  é\"",
+		},
+		"number too large": {
+			value: json.Number("1e2000"),
+			err:   ErrCanonicalNumberUnsupported,
+		},
+		"number too small": {
+			value: json.Number("1e-2000"),
+			err:   ErrCanonicalNumberUnsupported,
+		},
+		"exponent too large": {
+			value: json.Number("1e9999999999999999999"),
+			err:   ErrCanonicalNumberUnsupported,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := MarshalCanonical(test.value)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, string(result))
+		})
+	}
+}
+
+func TestMarshalCanonicalVersion(t *testing.T) {
+	result, err := MarshalCanonicalVersion(&Currency{Symbol: "BTC", Decimals: 8}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"decimals":8,"symbol":"BTC"}`, string(result))
+
+	_, err = MarshalCanonicalVersion(&Currency{Symbol: "BTC", Decimals: 8}, 2)
+	assert.True(t, errors.Is(err, ErrCanonicalVersionUnsupported))
+}
+
+func TestCanonicalCanonicalHash(t *testing.T) {
+	// These hashes must never change (they are
+	// used as keys by applications).
+	assert.Equal(
+		t,
+		"c473e5b0c56dbb5600e339fdb0b91ffd294efec6cbdfa4ca7bd3b5f4cdb5a366",
+		CanonicalHash(&Currency{Symbol: "BTC", Decimals: 8}),
+	)
+	assert.Equal(
+		t,
+		"502f13c28d20921ea5bcd66d6289d032eddb0ddc5d1349480a268e92947b2dc1",
+		CanonicalHash(&Transaction{
+			TransactionIdentifier: &TransactionIdentifier{Hash: "h"},
+			Operations:            []*Operation{},
+		}),
+	)
+
+	// Numbers are hashed exactly.
+	assert.NotEqual(
+		t,
+		CanonicalHash(json.Number("12345678901234567890")),
+		CanonicalHash(json.Number("12345678901234567891")),
+	)
+	assert.Equal(t, CanonicalHash(json.Number("1e2")), CanonicalHash(100))
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+)
+
+// cloneMetadata returns a deep copy of metadata. Nested
+// maps and slices (as decoded from JSON) are copied and
+// all other values are shared.
+func cloneMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+
+	c := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		c[k] = cloneValue(v)
+	}
+
+	return c
+}
+
+// cloneValue returns a deep copy of
+// a value in metadata.
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return cloneMetadata(v)
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+
+		c := make([]interface{}, len(v))
+		for i, x := range v {
+			c[i] = cloneValue(x)
+		}
+
+		return c
+	default:
+		return v
+	}
+}
+
+// MetadataEqual returns true if a and b are equal when
+// encoded in JSON: nil and empty metadata are equal,
+// numbers are equal if they have the same value regardless
+// of their type (ex: int64(1), float64(1), and
+// json.Number("1.0") are equal), and values of any
+// other type are compared by their JSON encoding.
+func MetadataEqual(a map[string]interface{}, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, va := range a {
+		vb, ok := b[k]
+		if !ok || !valueEqual(va, vb) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// valueEqual returns true if a and b
+// (values in metadata) are equal.
+func valueEqual(a interface{}, b interface{}) bool {
+	a, b = normalizeValue(a), normalizeValue(b)
+
+	if na, ok := number(a); ok {
+		nb, ok := number(b)
+		return ok && na.Cmp(nb) == 0
+	}
+
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		s, ok := b.(string)
+		return ok && a == s
+	case bool:
+		v, ok := b.(bool)
+		return ok && a == v
+	case map[string]interface{}:
+		m, ok := b.(map[string]interface{})
+		return ok && MetadataEqual(a, m)
+	case []interface{}:
+		s, ok := b.([]interface{})
+		if !ok || len(a) != len(s) {
+			return false
+		}
+
+		for i := range a {
+			if !valueEqual(a[i], s[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		// Values that cannot be encoded
+		// in JSON are compared directly.
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// normalizeValue converts a value in metadata that is
+// not a number, string, bool, map[string]interface{},
+// or []interface{} (ex: a struct) to the value decoded
+// from its JSON encoding.
+func normalizeValue(v interface{}) interface{} {
+	if _, ok := number(v); ok {
+		return v
+	}
+
+	switch v.(type) {
+	case nil, string, bool, map[string]interface{}, []interface{}:
+		return v
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return v
+	}
+
+	return decoded
+}
+
+// number returns the exact value of v if
+// it is a number (ok is false otherwise).
+func number(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Rat).SetUint64(n), true
+	case float32:
+		r := new(big.Rat).SetFloat64(float64(n))
+		return r, r != nil
+	case float64:
+		r := new(big.Rat).SetFloat64(n)
+		return r, r != nil
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	default:
+		return nil, false
+	}
+}
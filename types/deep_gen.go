@@ -0,0 +1,2887 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by internal/typegen. DO NOT EDIT.
+
+package types
+
+import (
+	"bytes"
+)
+
+// Clone returns a deep copy of the AccountBalanceRequest.
+func (v *AccountBalanceRequest) Clone() *AccountBalanceRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.AccountIdentifier = v.AccountIdentifier.Clone()
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+	if v.Currencies != nil {
+		c.Currencies = make([]*Currency, len(v.Currencies))
+		for i, x := range v.Currencies {
+			c.Currencies[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the AccountBalanceRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountBalanceRequest) Equal(o *AccountBalanceRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.AccountIdentifier.Equal(o.AccountIdentifier) {
+		return false
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if len(v.Currencies) != len(o.Currencies) {
+		return false
+	}
+
+	for i := range v.Currencies {
+		if !v.Currencies[i].Equal(o.Currencies[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the AccountBalanceResponse.
+func (v *AccountBalanceResponse) Clone() *AccountBalanceResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+	if v.Balances != nil {
+		c.Balances = make([]*Amount, len(v.Balances))
+		for i, x := range v.Balances {
+			c.Balances[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the AccountBalanceResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountBalanceResponse) Equal(o *AccountBalanceResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if len(v.Balances) != len(o.Balances) {
+		return false
+	}
+
+	for i := range v.Balances {
+		if !v.Balances[i].Equal(o.Balances[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the AccountCoin.
+func (v *AccountCoin) Clone() *AccountCoin {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Account = v.Account.Clone()
+	c.Coin = v.Coin.Clone()
+
+	return &c
+}
+
+// Equal returns true if the AccountCoin is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountCoin) Equal(o *AccountCoin) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.Account.Equal(o.Account) {
+		return false
+	}
+
+	if !v.Coin.Equal(o.Coin) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the AccountCoinsRequest.
+func (v *AccountCoinsRequest) Clone() *AccountCoinsRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.AccountIdentifier = v.AccountIdentifier.Clone()
+	if v.Currencies != nil {
+		c.Currencies = make([]*Currency, len(v.Currencies))
+		for i, x := range v.Currencies {
+			c.Currencies[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the AccountCoinsRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountCoinsRequest) Equal(o *AccountCoinsRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.AccountIdentifier.Equal(o.AccountIdentifier) {
+		return false
+	}
+
+	if v.IncludeMempool != o.IncludeMempool {
+		return false
+	}
+
+	if len(v.Currencies) != len(o.Currencies) {
+		return false
+	}
+
+	for i := range v.Currencies {
+		if !v.Currencies[i].Equal(o.Currencies[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the AccountCoinsResponse.
+func (v *AccountCoinsResponse) Clone() *AccountCoinsResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+	if v.Coins != nil {
+		c.Coins = make([]*Coin, len(v.Coins))
+		for i, x := range v.Coins {
+			c.Coins[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the AccountCoinsResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountCoinsResponse) Equal(o *AccountCoinsResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if len(v.Coins) != len(o.Coins) {
+		return false
+	}
+
+	for i := range v.Coins {
+		if !v.Coins[i].Equal(o.Coins[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the AccountCurrency.
+func (v *AccountCurrency) Clone() *AccountCurrency {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Account = v.Account.Clone()
+	c.Currency = v.Currency.Clone()
+
+	return &c
+}
+
+// Equal returns true if the AccountCurrency is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountCurrency) Equal(o *AccountCurrency) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.Account.Equal(o.Account) {
+		return false
+	}
+
+	if !v.Currency.Equal(o.Currency) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the AccountIdentifier.
+func (v *AccountIdentifier) Clone() *AccountIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.SubAccount = v.SubAccount.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the AccountIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *AccountIdentifier) Equal(o *AccountIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Address != o.Address {
+		return false
+	}
+
+	if !v.SubAccount.Equal(o.SubAccount) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Allow.
+func (v *Allow) Clone() *Allow {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.OperationStatuses != nil {
+		c.OperationStatuses = make([]*OperationStatus, len(v.OperationStatuses))
+		for i, x := range v.OperationStatuses {
+			c.OperationStatuses[i] = x.Clone()
+		}
+	}
+	if v.OperationTypes != nil {
+		c.OperationTypes = append([]string{}, v.OperationTypes...)
+	}
+	if v.Errors != nil {
+		c.Errors = make([]*Error, len(v.Errors))
+		for i, x := range v.Errors {
+			c.Errors[i] = x.Clone()
+		}
+	}
+	if v.TimestampStartIndex != nil {
+		x := *v.TimestampStartIndex
+		c.TimestampStartIndex = &x
+	}
+	if v.CallMethods != nil {
+		c.CallMethods = append([]string{}, v.CallMethods...)
+	}
+	if v.BalanceExemptions != nil {
+		c.BalanceExemptions = make([]*BalanceExemption, len(v.BalanceExemptions))
+		for i, x := range v.BalanceExemptions {
+			c.BalanceExemptions[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the Allow is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Allow) Equal(o *Allow) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if len(v.OperationStatuses) != len(o.OperationStatuses) {
+		return false
+	}
+
+	for i := range v.OperationStatuses {
+		if !v.OperationStatuses[i].Equal(o.OperationStatuses[i]) {
+			return false
+		}
+	}
+
+	if len(v.OperationTypes) != len(o.OperationTypes) {
+		return false
+	}
+
+	for i := range v.OperationTypes {
+		if v.OperationTypes[i] != o.OperationTypes[i] {
+			return false
+		}
+	}
+
+	if len(v.Errors) != len(o.Errors) {
+		return false
+	}
+
+	for i := range v.Errors {
+		if !v.Errors[i].Equal(o.Errors[i]) {
+			return false
+		}
+	}
+
+	if v.HistoricalBalanceLookup != o.HistoricalBalanceLookup {
+		return false
+	}
+
+	if (v.TimestampStartIndex == nil) != (o.TimestampStartIndex == nil) ||
+		(v.TimestampStartIndex != nil && *v.TimestampStartIndex != *o.TimestampStartIndex) {
+		return false
+	}
+
+	if len(v.CallMethods) != len(o.CallMethods) {
+		return false
+	}
+
+	for i := range v.CallMethods {
+		if v.CallMethods[i] != o.CallMethods[i] {
+			return false
+		}
+	}
+
+	if len(v.BalanceExemptions) != len(o.BalanceExemptions) {
+		return false
+	}
+
+	for i := range v.BalanceExemptions {
+		if !v.BalanceExemptions[i].Equal(o.BalanceExemptions[i]) {
+			return false
+		}
+	}
+
+	if v.MempoolCoins != o.MempoolCoins {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Amount.
+func (v *Amount) Clone() *Amount {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Currency = v.Currency.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Amount is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Amount) Equal(o *Amount) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Value != o.Value {
+		return false
+	}
+
+	if !v.Currency.Equal(o.Currency) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BalanceExemption.
+func (v *BalanceExemption) Clone() *BalanceExemption {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.SubAccountAddress != nil {
+		x := *v.SubAccountAddress
+		c.SubAccountAddress = &x
+	}
+	c.Currency = v.Currency.Clone()
+
+	return &c
+}
+
+// Equal returns true if the BalanceExemption is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BalanceExemption) Equal(o *BalanceExemption) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if (v.SubAccountAddress == nil) != (o.SubAccountAddress == nil) ||
+		(v.SubAccountAddress != nil && *v.SubAccountAddress != *o.SubAccountAddress) {
+		return false
+	}
+
+	if !v.Currency.Equal(o.Currency) {
+		return false
+	}
+
+	if v.ExemptionType != o.ExemptionType {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Block.
+func (v *Block) Clone() *Block {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+	c.ParentBlockIdentifier = v.ParentBlockIdentifier.Clone()
+	if v.Transactions != nil {
+		c.Transactions = make([]*Transaction, len(v.Transactions))
+		for i, x := range v.Transactions {
+			c.Transactions[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Block is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Block) Equal(o *Block) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if !v.ParentBlockIdentifier.Equal(o.ParentBlockIdentifier) {
+		return false
+	}
+
+	if v.Timestamp != o.Timestamp {
+		return false
+	}
+
+	if len(v.Transactions) != len(o.Transactions) {
+		return false
+	}
+
+	for i := range v.Transactions {
+		if !v.Transactions[i].Equal(o.Transactions[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockEvent.
+func (v *BlockEvent) Clone() *BlockEvent {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the BlockEvent is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockEvent) Equal(o *BlockEvent) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Sequence != o.Sequence {
+		return false
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if v.Type != o.Type {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockIdentifier.
+func (v *BlockIdentifier) Clone() *BlockIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	return &c
+}
+
+// Equal returns true if the BlockIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockIdentifier) Equal(o *BlockIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Index != o.Index {
+		return false
+	}
+
+	if v.Hash != o.Hash {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockRequest.
+func (v *BlockRequest) Clone() *BlockRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the BlockRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockRequest) Equal(o *BlockRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockResponse.
+func (v *BlockResponse) Clone() *BlockResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Block = v.Block.Clone()
+	if v.OtherTransactions != nil {
+		c.OtherTransactions = make([]*TransactionIdentifier, len(v.OtherTransactions))
+		for i, x := range v.OtherTransactions {
+			c.OtherTransactions[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the BlockResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockResponse) Equal(o *BlockResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.Block.Equal(o.Block) {
+		return false
+	}
+
+	if len(v.OtherTransactions) != len(o.OtherTransactions) {
+		return false
+	}
+
+	for i := range v.OtherTransactions {
+		if !v.OtherTransactions[i].Equal(o.OtherTransactions[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockTransaction.
+func (v *BlockTransaction) Clone() *BlockTransaction {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+	c.Transaction = v.Transaction.Clone()
+
+	return &c
+}
+
+// Equal returns true if the BlockTransaction is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockTransaction) Equal(o *BlockTransaction) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if !v.Transaction.Equal(o.Transaction) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockTransactionRequest.
+func (v *BlockTransactionRequest) Clone() *BlockTransactionRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.BlockIdentifier = v.BlockIdentifier.Clone()
+	c.TransactionIdentifier = v.TransactionIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the BlockTransactionRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockTransactionRequest) Equal(o *BlockTransactionRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.BlockIdentifier.Equal(o.BlockIdentifier) {
+		return false
+	}
+
+	if !v.TransactionIdentifier.Equal(o.TransactionIdentifier) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the BlockTransactionResponse.
+func (v *BlockTransactionResponse) Clone() *BlockTransactionResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Transaction = v.Transaction.Clone()
+
+	return &c
+}
+
+// Equal returns true if the BlockTransactionResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *BlockTransactionResponse) Equal(o *BlockTransactionResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.Transaction.Equal(o.Transaction) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the CallRequest.
+func (v *CallRequest) Clone() *CallRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.Parameters = cloneMetadata(v.Parameters)
+
+	return &c
+}
+
+// Equal returns true if the CallRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *CallRequest) Equal(o *CallRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if v.Method != o.Method {
+		return false
+	}
+
+	if !MetadataEqual(v.Parameters, o.Parameters) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the CallResponse.
+func (v *CallResponse) Clone() *CallResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Result = cloneMetadata(v.Result)
+
+	return &c
+}
+
+// Equal returns true if the CallResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *CallResponse) Equal(o *CallResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !MetadataEqual(v.Result, o.Result) {
+		return false
+	}
+
+	if v.Idempotent != o.Idempotent {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Coin.
+func (v *Coin) Clone() *Coin {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.CoinIdentifier = v.CoinIdentifier.Clone()
+	c.Amount = v.Amount.Clone()
+
+	return &c
+}
+
+// Equal returns true if the Coin is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Coin) Equal(o *Coin) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.CoinIdentifier.Equal(o.CoinIdentifier) {
+		return false
+	}
+
+	if !v.Amount.Equal(o.Amount) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the CoinChange.
+func (v *CoinChange) Clone() *CoinChange {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.CoinIdentifier = v.CoinIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the CoinChange is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *CoinChange) Equal(o *CoinChange) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.CoinIdentifier.Equal(o.CoinIdentifier) {
+		return false
+	}
+
+	if v.CoinAction != o.CoinAction {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the CoinIdentifier.
+func (v *CoinIdentifier) Clone() *CoinIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	return &c
+}
+
+// Equal returns true if the CoinIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *CoinIdentifier) Equal(o *CoinIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Identifier != o.Identifier {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionCombineRequest.
+func (v *ConstructionCombineRequest) Clone() *ConstructionCombineRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	if v.Signatures != nil {
+		c.Signatures = make([]*Signature, len(v.Signatures))
+		for i, x := range v.Signatures {
+			c.Signatures[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionCombineRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionCombineRequest) Equal(o *ConstructionCombineRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if v.UnsignedTransaction != o.UnsignedTransaction {
+		return false
+	}
+
+	if len(v.Signatures) != len(o.Signatures) {
+		return false
+	}
+
+	for i := range v.Signatures {
+		if !v.Signatures[i].Equal(o.Signatures[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionCombineResponse.
+func (v *ConstructionCombineResponse) Clone() *ConstructionCombineResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	return &c
+}
+
+// Equal returns true if the ConstructionCombineResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionCombineResponse) Equal(o *ConstructionCombineResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.SignedTransaction != o.SignedTransaction {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionDeriveRequest.
+func (v *ConstructionDeriveRequest) Clone() *ConstructionDeriveRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.PublicKey = v.PublicKey.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the ConstructionDeriveRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionDeriveRequest) Equal(o *ConstructionDeriveRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.PublicKey.Equal(o.PublicKey) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionDeriveResponse.
+func (v *ConstructionDeriveResponse) Clone() *ConstructionDeriveResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.AccountIdentifier = v.AccountIdentifier.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the ConstructionDeriveResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionDeriveResponse) Equal(o *ConstructionDeriveResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.AccountIdentifier.Equal(o.AccountIdentifier) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionHashRequest.
+func (v *ConstructionHashRequest) Clone() *ConstructionHashRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the ConstructionHashRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionHashRequest) Equal(o *ConstructionHashRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if v.SignedTransaction != o.SignedTransaction {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionMetadataRequest.
+func (v *ConstructionMetadataRequest) Clone() *ConstructionMetadataRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.Options = cloneMetadata(v.Options)
+	if v.PublicKeys != nil {
+		c.PublicKeys = make([]*PublicKey, len(v.PublicKeys))
+		for i, x := range v.PublicKeys {
+			c.PublicKeys[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionMetadataRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionMetadataRequest) Equal(o *ConstructionMetadataRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !MetadataEqual(v.Options, o.Options) {
+		return false
+	}
+
+	if len(v.PublicKeys) != len(o.PublicKeys) {
+		return false
+	}
+
+	for i := range v.PublicKeys {
+		if !v.PublicKeys[i].Equal(o.PublicKeys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionMetadataResponse.
+func (v *ConstructionMetadataResponse) Clone() *ConstructionMetadataResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Metadata = cloneMetadata(v.Metadata)
+	if v.SuggestedFee != nil {
+		c.SuggestedFee = make([]*Amount, len(v.SuggestedFee))
+		for i, x := range v.SuggestedFee {
+			c.SuggestedFee[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionMetadataResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionMetadataResponse) Equal(o *ConstructionMetadataResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	if len(v.SuggestedFee) != len(o.SuggestedFee) {
+		return false
+	}
+
+	for i := range v.SuggestedFee {
+		if !v.SuggestedFee[i].Equal(o.SuggestedFee[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionParseRequest.
+func (v *ConstructionParseRequest) Clone() *ConstructionParseRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the ConstructionParseRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionParseRequest) Equal(o *ConstructionParseRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if v.Signed != o.Signed {
+		return false
+	}
+
+	if v.Transaction != o.Transaction {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionParseResponse.
+func (v *ConstructionParseResponse) Clone() *ConstructionParseResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Operations != nil {
+		c.Operations = make([]*Operation, len(v.Operations))
+		for i, x := range v.Operations {
+			c.Operations[i] = x.Clone()
+		}
+	}
+	if v.AccountIdentifierSigners != nil {
+		c.AccountIdentifierSigners = make([]*AccountIdentifier, len(v.AccountIdentifierSigners))
+		for i, x := range v.AccountIdentifierSigners {
+			c.AccountIdentifierSigners[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the ConstructionParseResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionParseResponse) Equal(o *ConstructionParseResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if len(v.Operations) != len(o.Operations) {
+		return false
+	}
+
+	for i := range v.Operations {
+		if !v.Operations[i].Equal(o.Operations[i]) {
+			return false
+		}
+	}
+
+	if len(v.AccountIdentifierSigners) != len(o.AccountIdentifierSigners) {
+		return false
+	}
+
+	for i := range v.AccountIdentifierSigners {
+		if !v.AccountIdentifierSigners[i].Equal(o.AccountIdentifierSigners[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionPayloadsRequest.
+func (v *ConstructionPayloadsRequest) Clone() *ConstructionPayloadsRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	if v.Operations != nil {
+		c.Operations = make([]*Operation, len(v.Operations))
+		for i, x := range v.Operations {
+			c.Operations[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+	if v.PublicKeys != nil {
+		c.PublicKeys = make([]*PublicKey, len(v.PublicKeys))
+		for i, x := range v.PublicKeys {
+			c.PublicKeys[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionPayloadsRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionPayloadsRequest) Equal(o *ConstructionPayloadsRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if len(v.Operations) != len(o.Operations) {
+		return false
+	}
+
+	for i := range v.Operations {
+		if !v.Operations[i].Equal(o.Operations[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	if len(v.PublicKeys) != len(o.PublicKeys) {
+		return false
+	}
+
+	for i := range v.PublicKeys {
+		if !v.PublicKeys[i].Equal(o.PublicKeys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionPayloadsResponse.
+func (v *ConstructionPayloadsResponse) Clone() *ConstructionPayloadsResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Payloads != nil {
+		c.Payloads = make([]*SigningPayload, len(v.Payloads))
+		for i, x := range v.Payloads {
+			c.Payloads[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionPayloadsResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionPayloadsResponse) Equal(o *ConstructionPayloadsResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.UnsignedTransaction != o.UnsignedTransaction {
+		return false
+	}
+
+	if len(v.Payloads) != len(o.Payloads) {
+		return false
+	}
+
+	for i := range v.Payloads {
+		if !v.Payloads[i].Equal(o.Payloads[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionPreprocessRequest.
+func (v *ConstructionPreprocessRequest) Clone() *ConstructionPreprocessRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	if v.Operations != nil {
+		c.Operations = make([]*Operation, len(v.Operations))
+		for i, x := range v.Operations {
+			c.Operations[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+	if v.MaxFee != nil {
+		c.MaxFee = make([]*Amount, len(v.MaxFee))
+		for i, x := range v.MaxFee {
+			c.MaxFee[i] = x.Clone()
+		}
+	}
+	if v.SuggestedFeeMultiplier != nil {
+		x := *v.SuggestedFeeMultiplier
+		c.SuggestedFeeMultiplier = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionPreprocessRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionPreprocessRequest) Equal(o *ConstructionPreprocessRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if len(v.Operations) != len(o.Operations) {
+		return false
+	}
+
+	for i := range v.Operations {
+		if !v.Operations[i].Equal(o.Operations[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	if len(v.MaxFee) != len(o.MaxFee) {
+		return false
+	}
+
+	for i := range v.MaxFee {
+		if !v.MaxFee[i].Equal(o.MaxFee[i]) {
+			return false
+		}
+	}
+
+	if (v.SuggestedFeeMultiplier == nil) != (o.SuggestedFeeMultiplier == nil) ||
+		(v.SuggestedFeeMultiplier != nil && *v.SuggestedFeeMultiplier != *o.SuggestedFeeMultiplier) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionPreprocessResponse.
+func (v *ConstructionPreprocessResponse) Clone() *ConstructionPreprocessResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Options = cloneMetadata(v.Options)
+	if v.RequiredPublicKeys != nil {
+		c.RequiredPublicKeys = make([]*AccountIdentifier, len(v.RequiredPublicKeys))
+		for i, x := range v.RequiredPublicKeys {
+			c.RequiredPublicKeys[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the ConstructionPreprocessResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionPreprocessResponse) Equal(o *ConstructionPreprocessResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !MetadataEqual(v.Options, o.Options) {
+		return false
+	}
+
+	if len(v.RequiredPublicKeys) != len(o.RequiredPublicKeys) {
+		return false
+	}
+
+	for i := range v.RequiredPublicKeys {
+		if !v.RequiredPublicKeys[i].Equal(o.RequiredPublicKeys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the ConstructionSubmitRequest.
+func (v *ConstructionSubmitRequest) Clone() *ConstructionSubmitRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the ConstructionSubmitRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *ConstructionSubmitRequest) Equal(o *ConstructionSubmitRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if v.SignedTransaction != o.SignedTransaction {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Currency.
+func (v *Currency) Clone() *Currency {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Currency is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Currency) Equal(o *Currency) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Symbol != o.Symbol {
+		return false
+	}
+
+	if v.Decimals != o.Decimals {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Error.
+func (v *Error) Clone() *Error {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Description != nil {
+		x := *v.Description
+		c.Description = &x
+	}
+	c.Details = cloneMetadata(v.Details)
+
+	return &c
+}
+
+// Equal returns true if the Error is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Error) Equal(o *Error) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Code != o.Code {
+		return false
+	}
+
+	if v.Message != o.Message {
+		return false
+	}
+
+	if (v.Description == nil) != (o.Description == nil) ||
+		(v.Description != nil && *v.Description != *o.Description) {
+		return false
+	}
+
+	if v.Retriable != o.Retriable {
+		return false
+	}
+
+	if !MetadataEqual(v.Details, o.Details) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the EventsBlocksRequest.
+func (v *EventsBlocksRequest) Clone() *EventsBlocksRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	if v.Offset != nil {
+		x := *v.Offset
+		c.Offset = &x
+	}
+	if v.Limit != nil {
+		x := *v.Limit
+		c.Limit = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the EventsBlocksRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *EventsBlocksRequest) Equal(o *EventsBlocksRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if (v.Offset == nil) != (o.Offset == nil) ||
+		(v.Offset != nil && *v.Offset != *o.Offset) {
+		return false
+	}
+
+	if (v.Limit == nil) != (o.Limit == nil) ||
+		(v.Limit != nil && *v.Limit != *o.Limit) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the EventsBlocksResponse.
+func (v *EventsBlocksResponse) Clone() *EventsBlocksResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Events != nil {
+		c.Events = make([]*BlockEvent, len(v.Events))
+		for i, x := range v.Events {
+			c.Events[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the EventsBlocksResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *EventsBlocksResponse) Equal(o *EventsBlocksResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.MaxSequence != o.MaxSequence {
+		return false
+	}
+
+	if len(v.Events) != len(o.Events) {
+		return false
+	}
+
+	for i := range v.Events {
+		if !v.Events[i].Equal(o.Events[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the MempoolResponse.
+func (v *MempoolResponse) Clone() *MempoolResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.TransactionIdentifiers != nil {
+		c.TransactionIdentifiers = make([]*TransactionIdentifier, len(v.TransactionIdentifiers))
+		for i, x := range v.TransactionIdentifiers {
+			c.TransactionIdentifiers[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the MempoolResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *MempoolResponse) Equal(o *MempoolResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if len(v.TransactionIdentifiers) != len(o.TransactionIdentifiers) {
+		return false
+	}
+
+	for i := range v.TransactionIdentifiers {
+		if !v.TransactionIdentifiers[i].Equal(o.TransactionIdentifiers[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the MempoolTransactionRequest.
+func (v *MempoolTransactionRequest) Clone() *MempoolTransactionRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.TransactionIdentifier = v.TransactionIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the MempoolTransactionRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *MempoolTransactionRequest) Equal(o *MempoolTransactionRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.TransactionIdentifier.Equal(o.TransactionIdentifier) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the MempoolTransactionResponse.
+func (v *MempoolTransactionResponse) Clone() *MempoolTransactionResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Transaction = v.Transaction.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the MempoolTransactionResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *MempoolTransactionResponse) Equal(o *MempoolTransactionResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.Transaction.Equal(o.Transaction) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the MetadataRequest.
+func (v *MetadataRequest) Clone() *MetadataRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the MetadataRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *MetadataRequest) Equal(o *MetadataRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the NetworkIdentifier.
+func (v *NetworkIdentifier) Clone() *NetworkIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.SubNetworkIdentifier = v.SubNetworkIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the NetworkIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *NetworkIdentifier) Equal(o *NetworkIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Blockchain != o.Blockchain {
+		return false
+	}
+
+	if v.Network != o.Network {
+		return false
+	}
+
+	if !v.SubNetworkIdentifier.Equal(o.SubNetworkIdentifier) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the NetworkListResponse.
+func (v *NetworkListResponse) Clone() *NetworkListResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.NetworkIdentifiers != nil {
+		c.NetworkIdentifiers = make([]*NetworkIdentifier, len(v.NetworkIdentifiers))
+		for i, x := range v.NetworkIdentifiers {
+			c.NetworkIdentifiers[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the NetworkListResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *NetworkListResponse) Equal(o *NetworkListResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if len(v.NetworkIdentifiers) != len(o.NetworkIdentifiers) {
+		return false
+	}
+
+	for i := range v.NetworkIdentifiers {
+		if !v.NetworkIdentifiers[i].Equal(o.NetworkIdentifiers[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the NetworkOptionsResponse.
+func (v *NetworkOptionsResponse) Clone() *NetworkOptionsResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Version = v.Version.Clone()
+	c.Allow = v.Allow.Clone()
+
+	return &c
+}
+
+// Equal returns true if the NetworkOptionsResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *NetworkOptionsResponse) Equal(o *NetworkOptionsResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.Version.Equal(o.Version) {
+		return false
+	}
+
+	if !v.Allow.Equal(o.Allow) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the NetworkRequest.
+func (v *NetworkRequest) Clone() *NetworkRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the NetworkRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *NetworkRequest) Equal(o *NetworkRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the NetworkStatusResponse.
+func (v *NetworkStatusResponse) Clone() *NetworkStatusResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.CurrentBlockIdentifier = v.CurrentBlockIdentifier.Clone()
+	c.GenesisBlockIdentifier = v.GenesisBlockIdentifier.Clone()
+	c.OldestBlockIdentifier = v.OldestBlockIdentifier.Clone()
+	c.SyncStatus = v.SyncStatus.Clone()
+	if v.Peers != nil {
+		c.Peers = make([]*Peer, len(v.Peers))
+		for i, x := range v.Peers {
+			c.Peers[i] = x.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Equal returns true if the NetworkStatusResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *NetworkStatusResponse) Equal(o *NetworkStatusResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.CurrentBlockIdentifier.Equal(o.CurrentBlockIdentifier) {
+		return false
+	}
+
+	if v.CurrentBlockTimestamp != o.CurrentBlockTimestamp {
+		return false
+	}
+
+	if !v.GenesisBlockIdentifier.Equal(o.GenesisBlockIdentifier) {
+		return false
+	}
+
+	if !v.OldestBlockIdentifier.Equal(o.OldestBlockIdentifier) {
+		return false
+	}
+
+	if !v.SyncStatus.Equal(o.SyncStatus) {
+		return false
+	}
+
+	if len(v.Peers) != len(o.Peers) {
+		return false
+	}
+
+	for i := range v.Peers {
+		if !v.Peers[i].Equal(o.Peers[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Operation.
+func (v *Operation) Clone() *Operation {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.OperationIdentifier = v.OperationIdentifier.Clone()
+	if v.RelatedOperations != nil {
+		c.RelatedOperations = make([]*OperationIdentifier, len(v.RelatedOperations))
+		for i, x := range v.RelatedOperations {
+			c.RelatedOperations[i] = x.Clone()
+		}
+	}
+	if v.Status != nil {
+		x := *v.Status
+		c.Status = &x
+	}
+	c.Account = v.Account.Clone()
+	c.Amount = v.Amount.Clone()
+	c.CoinChange = v.CoinChange.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Operation is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Operation) Equal(o *Operation) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.OperationIdentifier.Equal(o.OperationIdentifier) {
+		return false
+	}
+
+	if len(v.RelatedOperations) != len(o.RelatedOperations) {
+		return false
+	}
+
+	for i := range v.RelatedOperations {
+		if !v.RelatedOperations[i].Equal(o.RelatedOperations[i]) {
+			return false
+		}
+	}
+
+	if v.Type != o.Type {
+		return false
+	}
+
+	if (v.Status == nil) != (o.Status == nil) ||
+		(v.Status != nil && *v.Status != *o.Status) {
+		return false
+	}
+
+	if !v.Account.Equal(o.Account) {
+		return false
+	}
+
+	if !v.Amount.Equal(o.Amount) {
+		return false
+	}
+
+	if !v.CoinChange.Equal(o.CoinChange) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the OperationIdentifier.
+func (v *OperationIdentifier) Clone() *OperationIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.NetworkIndex != nil {
+		x := *v.NetworkIndex
+		c.NetworkIndex = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the OperationIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *OperationIdentifier) Equal(o *OperationIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Index != o.Index {
+		return false
+	}
+
+	if (v.NetworkIndex == nil) != (o.NetworkIndex == nil) ||
+		(v.NetworkIndex != nil && *v.NetworkIndex != *o.NetworkIndex) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the OperationStatus.
+func (v *OperationStatus) Clone() *OperationStatus {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	return &c
+}
+
+// Equal returns true if the OperationStatus is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *OperationStatus) Equal(o *OperationStatus) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Status != o.Status {
+		return false
+	}
+
+	if v.Successful != o.Successful {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the PartialBlockIdentifier.
+func (v *PartialBlockIdentifier) Clone() *PartialBlockIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Index != nil {
+		x := *v.Index
+		c.Index = &x
+	}
+	if v.Hash != nil {
+		x := *v.Hash
+		c.Hash = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the PartialBlockIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *PartialBlockIdentifier) Equal(o *PartialBlockIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if (v.Index == nil) != (o.Index == nil) ||
+		(v.Index != nil && *v.Index != *o.Index) {
+		return false
+	}
+
+	if (v.Hash == nil) != (o.Hash == nil) ||
+		(v.Hash != nil && *v.Hash != *o.Hash) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Peer.
+func (v *Peer) Clone() *Peer {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Peer is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Peer) Equal(o *Peer) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.PeerID != o.PeerID {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the PublicKey.
+func (v *PublicKey) Clone() *PublicKey {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Bytes != nil {
+		c.Bytes = append([]byte{}, v.Bytes...)
+	}
+
+	return &c
+}
+
+// Equal returns true if the PublicKey is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *PublicKey) Equal(o *PublicKey) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !bytes.Equal(v.Bytes, o.Bytes) {
+		return false
+	}
+
+	if v.CurveType != o.CurveType {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the RelatedTransaction.
+func (v *RelatedTransaction) Clone() *RelatedTransaction {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	c.TransactionIdentifier = v.TransactionIdentifier.Clone()
+
+	return &c
+}
+
+// Equal returns true if the RelatedTransaction is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *RelatedTransaction) Equal(o *RelatedTransaction) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if !v.TransactionIdentifier.Equal(o.TransactionIdentifier) {
+		return false
+	}
+
+	if v.Direction != o.Direction {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the SearchTransactionsRequest.
+func (v *SearchTransactionsRequest) Clone() *SearchTransactionsRequest {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.NetworkIdentifier = v.NetworkIdentifier.Clone()
+	if v.Operator != nil {
+		x := *v.Operator
+		c.Operator = &x
+	}
+	if v.MaxBlock != nil {
+		x := *v.MaxBlock
+		c.MaxBlock = &x
+	}
+	if v.Offset != nil {
+		x := *v.Offset
+		c.Offset = &x
+	}
+	if v.Limit != nil {
+		x := *v.Limit
+		c.Limit = &x
+	}
+	c.TransactionIdentifier = v.TransactionIdentifier.Clone()
+	c.AccountIdentifier = v.AccountIdentifier.Clone()
+	c.CoinIdentifier = v.CoinIdentifier.Clone()
+	c.Currency = v.Currency.Clone()
+	if v.Status != nil {
+		x := *v.Status
+		c.Status = &x
+	}
+	if v.Type != nil {
+		x := *v.Type
+		c.Type = &x
+	}
+	if v.Address != nil {
+		x := *v.Address
+		c.Address = &x
+	}
+	if v.Success != nil {
+		x := *v.Success
+		c.Success = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the SearchTransactionsRequest is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *SearchTransactionsRequest) Equal(o *SearchTransactionsRequest) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.NetworkIdentifier.Equal(o.NetworkIdentifier) {
+		return false
+	}
+
+	if (v.Operator == nil) != (o.Operator == nil) ||
+		(v.Operator != nil && *v.Operator != *o.Operator) {
+		return false
+	}
+
+	if (v.MaxBlock == nil) != (o.MaxBlock == nil) ||
+		(v.MaxBlock != nil && *v.MaxBlock != *o.MaxBlock) {
+		return false
+	}
+
+	if (v.Offset == nil) != (o.Offset == nil) ||
+		(v.Offset != nil && *v.Offset != *o.Offset) {
+		return false
+	}
+
+	if (v.Limit == nil) != (o.Limit == nil) ||
+		(v.Limit != nil && *v.Limit != *o.Limit) {
+		return false
+	}
+
+	if !v.TransactionIdentifier.Equal(o.TransactionIdentifier) {
+		return false
+	}
+
+	if !v.AccountIdentifier.Equal(o.AccountIdentifier) {
+		return false
+	}
+
+	if !v.CoinIdentifier.Equal(o.CoinIdentifier) {
+		return false
+	}
+
+	if !v.Currency.Equal(o.Currency) {
+		return false
+	}
+
+	if (v.Status == nil) != (o.Status == nil) ||
+		(v.Status != nil && *v.Status != *o.Status) {
+		return false
+	}
+
+	if (v.Type == nil) != (o.Type == nil) ||
+		(v.Type != nil && *v.Type != *o.Type) {
+		return false
+	}
+
+	if (v.Address == nil) != (o.Address == nil) ||
+		(v.Address != nil && *v.Address != *o.Address) {
+		return false
+	}
+
+	if (v.Success == nil) != (o.Success == nil) ||
+		(v.Success != nil && *v.Success != *o.Success) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the SearchTransactionsResponse.
+func (v *SearchTransactionsResponse) Clone() *SearchTransactionsResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.Transactions != nil {
+		c.Transactions = make([]*BlockTransaction, len(v.Transactions))
+		for i, x := range v.Transactions {
+			c.Transactions[i] = x.Clone()
+		}
+	}
+	if v.NextOffset != nil {
+		x := *v.NextOffset
+		c.NextOffset = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the SearchTransactionsResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *SearchTransactionsResponse) Equal(o *SearchTransactionsResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if len(v.Transactions) != len(o.Transactions) {
+		return false
+	}
+
+	for i := range v.Transactions {
+		if !v.Transactions[i].Equal(o.Transactions[i]) {
+			return false
+		}
+	}
+
+	if v.TotalCount != o.TotalCount {
+		return false
+	}
+
+	if (v.NextOffset == nil) != (o.NextOffset == nil) ||
+		(v.NextOffset != nil && *v.NextOffset != *o.NextOffset) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Signature.
+func (v *Signature) Clone() *Signature {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.SigningPayload = v.SigningPayload.Clone()
+	c.PublicKey = v.PublicKey.Clone()
+	if v.Bytes != nil {
+		c.Bytes = append([]byte{}, v.Bytes...)
+	}
+
+	return &c
+}
+
+// Equal returns true if the Signature is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Signature) Equal(o *Signature) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.SigningPayload.Equal(o.SigningPayload) {
+		return false
+	}
+
+	if !v.PublicKey.Equal(o.PublicKey) {
+		return false
+	}
+
+	if v.SignatureType != o.SignatureType {
+		return false
+	}
+
+	if !bytes.Equal(v.Bytes, o.Bytes) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the SigningPayload.
+func (v *SigningPayload) Clone() *SigningPayload {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.AccountIdentifier = v.AccountIdentifier.Clone()
+	if v.Bytes != nil {
+		c.Bytes = append([]byte{}, v.Bytes...)
+	}
+
+	return &c
+}
+
+// Equal returns true if the SigningPayload is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *SigningPayload) Equal(o *SigningPayload) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.AccountIdentifier.Equal(o.AccountIdentifier) {
+		return false
+	}
+
+	if !bytes.Equal(v.Bytes, o.Bytes) {
+		return false
+	}
+
+	if v.SignatureType != o.SignatureType {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the SubAccountIdentifier.
+func (v *SubAccountIdentifier) Clone() *SubAccountIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the SubAccountIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *SubAccountIdentifier) Equal(o *SubAccountIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Address != o.Address {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the SubNetworkIdentifier.
+func (v *SubNetworkIdentifier) Clone() *SubNetworkIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the SubNetworkIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *SubNetworkIdentifier) Equal(o *SubNetworkIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Network != o.Network {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the SyncStatus.
+func (v *SyncStatus) Clone() *SyncStatus {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.CurrentIndex != nil {
+		x := *v.CurrentIndex
+		c.CurrentIndex = &x
+	}
+	if v.TargetIndex != nil {
+		x := *v.TargetIndex
+		c.TargetIndex = &x
+	}
+	if v.Stage != nil {
+		x := *v.Stage
+		c.Stage = &x
+	}
+	if v.Synced != nil {
+		x := *v.Synced
+		c.Synced = &x
+	}
+
+	return &c
+}
+
+// Equal returns true if the SyncStatus is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *SyncStatus) Equal(o *SyncStatus) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if (v.CurrentIndex == nil) != (o.CurrentIndex == nil) ||
+		(v.CurrentIndex != nil && *v.CurrentIndex != *o.CurrentIndex) {
+		return false
+	}
+
+	if (v.TargetIndex == nil) != (o.TargetIndex == nil) ||
+		(v.TargetIndex != nil && *v.TargetIndex != *o.TargetIndex) {
+		return false
+	}
+
+	if (v.Stage == nil) != (o.Stage == nil) ||
+		(v.Stage != nil && *v.Stage != *o.Stage) {
+		return false
+	}
+
+	if (v.Synced == nil) != (o.Synced == nil) ||
+		(v.Synced != nil && *v.Synced != *o.Synced) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Transaction.
+func (v *Transaction) Clone() *Transaction {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.TransactionIdentifier = v.TransactionIdentifier.Clone()
+	if v.Operations != nil {
+		c.Operations = make([]*Operation, len(v.Operations))
+		for i, x := range v.Operations {
+			c.Operations[i] = x.Clone()
+		}
+	}
+	if v.RelatedTransactions != nil {
+		c.RelatedTransactions = make([]*RelatedTransaction, len(v.RelatedTransactions))
+		for i, x := range v.RelatedTransactions {
+			c.RelatedTransactions[i] = x.Clone()
+		}
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Transaction is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Transaction) Equal(o *Transaction) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.TransactionIdentifier.Equal(o.TransactionIdentifier) {
+		return false
+	}
+
+	if len(v.Operations) != len(o.Operations) {
+		return false
+	}
+
+	for i := range v.Operations {
+		if !v.Operations[i].Equal(o.Operations[i]) {
+			return false
+		}
+	}
+
+	if len(v.RelatedTransactions) != len(o.RelatedTransactions) {
+		return false
+	}
+
+	for i := range v.RelatedTransactions {
+		if !v.RelatedTransactions[i].Equal(o.RelatedTransactions[i]) {
+			return false
+		}
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the TransactionIdentifier.
+func (v *TransactionIdentifier) Clone() *TransactionIdentifier {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+
+	return &c
+}
+
+// Equal returns true if the TransactionIdentifier is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *TransactionIdentifier) Equal(o *TransactionIdentifier) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.Hash != o.Hash {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the TransactionIdentifierResponse.
+func (v *TransactionIdentifierResponse) Clone() *TransactionIdentifierResponse {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	c.TransactionIdentifier = v.TransactionIdentifier.Clone()
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the TransactionIdentifierResponse is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *TransactionIdentifierResponse) Equal(o *TransactionIdentifierResponse) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if !v.TransactionIdentifier.Equal(o.TransactionIdentifier) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of the Version.
+func (v *Version) Clone() *Version {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.MiddlewareVersion != nil {
+		x := *v.MiddlewareVersion
+		c.MiddlewareVersion = &x
+	}
+	c.Metadata = cloneMetadata(v.Metadata)
+
+	return &c
+}
+
+// Equal returns true if the Version is structurally
+// equal to o (nil and empty slices are equal and
+// metadata is compared with MetadataEqual).
+func (v *Version) Equal(o *Version) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+
+	if v.RosettaVersion != o.RosettaVersion {
+		return false
+	}
+
+	if v.NodeVersion != o.NodeVersion {
+		return false
+	}
+
+	if (v.MiddlewareVersion == nil) != (o.MiddlewareVersion == nil) ||
+		(v.MiddlewareVersion != nil && *v.MiddlewareVersion != *o.MiddlewareVersion) {
+		return false
+	}
+
+	if !MetadataEqual(v.Metadata, o.Metadata) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,224 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func deepTransaction() *Transaction {
+	return &Transaction{
+		TransactionIdentifier: &TransactionIdentifier{Hash: "tx"},
+		Operations: []*Operation{
+			{
+				OperationIdentifier: &OperationIdentifier{Index: 0, NetworkIndex: Int64(2)},
+				Type:                "TRANSFER",
+				Status:              String("SUCCESS"),
+				Account: &AccountIdentifier{
+					Address:    "addr",
+					SubAccount: &SubAccountIdentifier{Address: "sub"},
+				},
+				Amount: &Amount{
+					Value:    "100",
+					Currency: &Currency{Symbol: "BTC", Decimals: 8},
+				},
+				Metadata: map[string]interface{}{
+					"nested": map[string]interface{}{"list": []interface{}{"a", 1.0}},
+				},
+			},
+		},
+		RelatedTransactions: []*RelatedTransaction{
+			{
+				TransactionIdentifier: &TransactionIdentifier{Hash: "related"},
+				Direction:             Forward,
+			},
+		},
+	}
+}
+
+func TestClone(t *testing.T) {
+	tx := deepTransaction()
+	c := tx.Clone()
+	assert.Equal(t, tx, c)
+	assert.True(t, tx.Equal(c))
+
+	// Modifying the clone does not modify the original.
+	c.Operations[0].OperationIdentifier.Index = 1
+	*c.Operations[0].OperationIdentifier.NetworkIndex = 3
+	*c.Operations[0].Status = "FAILURE"
+	c.Operations[0].Account.SubAccount.Address = "other"
+	c.Operations[0].Amount.Currency.Decimals = 18
+	nested := c.Operations[0].Metadata["nested"].(map[string]interface{})
+	nested["list"].([]interface{})[0] = "b"
+	c.RelatedTransactions[0].Direction = Backward
+	assert.Equal(t, deepTransaction(), tx)
+	assert.False(t, tx.Equal(c))
+
+	var nilTx *Transaction
+	assert.Nil(t, nilTx.Clone())
+
+	// nil and empty slices are preserved.
+	empty := &Transaction{Operations: []*Operation{}}
+	assert.Equal(t, []*Operation{}, empty.Clone().Operations)
+	assert.Nil(t, empty.Clone().RelatedTransactions)
+
+	payload := &SigningPayload{Bytes: []byte{1, 2}}
+	clonedPayload := payload.Clone()
+	clonedPayload.Bytes[0] = 3
+	assert.Equal(t, []byte{1, 2}, payload.Bytes)
+}
+
+func TestEqual(t *testing.T) {
+	var tests = map[string]struct {
+		a     *Operation
+		b     *Operation
+		equal bool
+	}{
+		"both nil": {
+			equal: true,
+		},
+		"one nil": {
+			a: &Operation{},
+		},
+		"same": {
+			a:     deepTransaction().Operations[0],
+			b:     deepTransaction().Operations[0],
+			equal: true,
+		},
+		"different type": {
+			a: &Operation{Type: "TRANSFER"},
+			b: &Operation{Type: "FEE"},
+		},
+		"optional field missing": {
+			a: &Operation{Status: String("SUCCESS")},
+			b: &Operation{},
+		},
+		"different optional field": {
+			a: &Operation{Status: String("SUCCESS")},
+			b: &Operation{Status: String("FAILURE")},
+		},
+		"nil and empty slices": {
+			a:     &Operation{RelatedOperations: []*OperationIdentifier{}},
+			b:     &Operation{},
+			equal: true,
+		},
+		"different related operations": {
+			a: &Operation{RelatedOperations: []*OperationIdentifier{{Index: 1}}},
+			b: &Operation{RelatedOperations: []*OperationIdentifier{{Index: 2}}},
+		},
+		"different nested struct": {
+			a: &Operation{Amount: &Amount{Value: "1", Currency: &Currency{Symbol: "BTC"}}},
+			b: &Operation{Amount: &Amount{Value: "1", Currency: &Currency{Symbol: "ETH"}}},
+		},
+		"nil and empty metadata": {
+			a:     &Operation{Metadata: map[string]interface{}{}},
+			b:     &Operation{},
+			equal: true,
+		},
+		"metadata numbers of different types": {
+			a:     &Operation{Metadata: map[string]interface{}{"fee": int64(10)}},
+			b:     &Operation{Metadata: map[string]interface{}{"fee": 10.0}},
+			equal: true,
+		},
+		"different metadata": {
+			a: &Operation{Metadata: map[string]interface{}{"fee": 10}},
+			b: &Operation{Metadata: map[string]interface{}{"fee": 11}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.equal, test.a.Equal(test.b))
+			assert.Equal(t, test.equal, test.b.Equal(test.a))
+		})
+	}
+}
+
+func TestMetadataEqual(t *testing.T) {
+	type gas struct {
+		Limit int64 `json:"limit"`
+	}
+
+	var tests = map[string]struct {
+		a     map[string]interface{}
+		b     map[string]interface{}
+		equal bool
+	}{
+		"nil and empty": {
+			a:     map[string]interface{}{},
+			equal: true,
+		},
+		"different keys": {
+			a: map[string]interface{}{"a": "b"},
+			b: map[string]interface{}{"b": "b"},
+		},
+		"numbers": {
+			a: map[string]interface{}{
+				"int":    1,
+				"uint":   uint8(2),
+				"float":  3.5,
+				"number": json.Number("4"),
+			},
+			b: map[string]interface{}{
+				"int":    json.Number("1.0"),
+				"uint":   int32(2),
+				"float":  json.Number("3.5"),
+				"number": uint64(4),
+			},
+			equal: true,
+		},
+		"number and string": {
+			a: map[string]interface{}{"a": 1},
+			b: map[string]interface{}{"a": "1"},
+		},
+		"nested": {
+			a: map[string]interface{}{
+				"a": map[string]interface{}{"b": []interface{}{1, true, nil}},
+			},
+			b: map[string]interface{}{
+				"a": map[string]interface{}{"b": []interface{}{1.0, true, nil}},
+			},
+			equal: true,
+		},
+		"different slice order": {
+			a: map[string]interface{}{"a": []interface{}{1, 2}},
+			b: map[string]interface{}{"a": []interface{}{2, 1}},
+		},
+		"struct and map": {
+			a:     map[string]interface{}{"gas": &gas{Limit: 21000}},
+			b:     map[string]interface{}{"gas": map[string]interface{}{"limit": 21000.0}},
+			equal: true,
+		},
+		"raw message and map": {
+			a:     map[string]interface{}{"a": json.RawMessage(`{"b": 1, "c": [true]}`)},
+			b:     map[string]interface{}{"a": map[string]interface{}{"c": []interface{}{true}, "b": 1}},
+			equal: true,
+		},
+		"different structs": {
+			a: map[string]interface{}{"gas": gas{Limit: 1}},
+			b: map[string]interface{}{"gas": gas{Limit: 2}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.equal, MetadataEqual(test.a, test.b))
+			assert.Equal(t, test.equal, MetadataEqual(test.b, test.a))
+		})
+	}
+}
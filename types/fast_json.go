@@ -0,0 +1,95 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// The generated MarshalJSON methods of the types in
+// fast_json_gen.go (Block, Transaction, Operation, and the
+// types they contain) encode JSON without reflection. Their
+// output is identical to the output of encoding/json:
+//
+//   - Strings that encoding/json would escape (other than with
+//     HTML escaping, which encoding/json applies to the output
+//     of MarshalJSON) are encoded with encoding/json.
+//   - Metadata is encoded with encoding/json.
+//
+// The same types can also be decoded without reflection
+// by building with the fastjson build tag (see
+// fast_json_unmarshal.go).
+
+// appendJSONKey appends key (a quoted object key
+// followed by a colon) to b, preceded by a comma
+// if it is not the first member of an object.
+func appendJSONKey(b []byte, key string) []byte {
+	if b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+
+	return append(b, key...)
+}
+
+// appendJSONString appends s to b
+// as a JSON string.
+func appendJSONString(b []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c < 0x20 || c == '"' || c == '\\' { // nolint:gomnd
+				return appendJSONValueString(b, s)
+			}
+
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || r == '\u2028' || r == '\u2029' {
+			return appendJSONValueString(b, s)
+		}
+
+		i += size
+	}
+
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"')
+}
+
+// appendJSONValueString appends s (a string that
+// must be escaped) to b with encoding/json.
+func appendJSONValueString(b []byte, s string) []byte {
+	// Encoding a string never fails.
+	b, _ = appendJSONValue(b, s)
+	return b
+}
+
+// appendJSONValue appends v to b with encoding/json
+// (without HTML escaping, which encoding/json applies
+// to the output of MarshalJSON if required).
+func appendJSONValue(b []byte, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return append(b, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...), nil
+}
@@ -0,0 +1,489 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by internal/typegen. DO NOT EDIT.
+
+package types
+
+import (
+	"strconv"
+)
+
+// MarshalJSON encodes the AccountIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *AccountIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the AccountIdentifier to b as JSON.
+func (v *AccountIdentifier) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"address":`)
+	b = appendJSONString(b, v.Address)
+	if v.SubAccount != nil {
+		b = appendJSONKey(b, `"sub_account":`)
+		if b, err = v.SubAccount.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the Amount to JSON
+// without reflection (see fast_json.go).
+func (v *Amount) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the Amount to b as JSON.
+func (v *Amount) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"value":`)
+	b = appendJSONString(b, v.Value)
+	b = appendJSONKey(b, `"currency":`)
+	if v.Currency == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.Currency.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the Block to JSON
+// without reflection (see fast_json.go).
+func (v *Block) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the Block to b as JSON.
+func (v *Block) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"block_identifier":`)
+	if v.BlockIdentifier == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.BlockIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = appendJSONKey(b, `"parent_block_identifier":`)
+	if v.ParentBlockIdentifier == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.ParentBlockIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = appendJSONKey(b, `"timestamp":`)
+	b = strconv.AppendInt(b, v.Timestamp, 10)
+	b = appendJSONKey(b, `"transactions":`)
+	if v.Transactions == nil {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i, x := range v.Transactions {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if x == nil {
+				b = append(b, "null"...)
+				continue
+			}
+
+			if b, err = x.appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	}
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the BlockIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *BlockIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the BlockIdentifier to b as JSON.
+func (v *BlockIdentifier) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	b = appendJSONKey(b, `"index":`)
+	b = strconv.AppendInt(b, v.Index, 10)
+	b = appendJSONKey(b, `"hash":`)
+	b = appendJSONString(b, v.Hash)
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the CoinChange to JSON
+// without reflection (see fast_json.go).
+func (v *CoinChange) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the CoinChange to b as JSON.
+func (v *CoinChange) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"coin_identifier":`)
+	if v.CoinIdentifier == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.CoinIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = appendJSONKey(b, `"coin_action":`)
+	b = appendJSONString(b, string(v.CoinAction))
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the CoinIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *CoinIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the CoinIdentifier to b as JSON.
+func (v *CoinIdentifier) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	b = appendJSONKey(b, `"identifier":`)
+	b = appendJSONString(b, v.Identifier)
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the Currency to JSON
+// without reflection (see fast_json.go).
+func (v *Currency) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the Currency to b as JSON.
+func (v *Currency) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"symbol":`)
+	b = appendJSONString(b, v.Symbol)
+	b = appendJSONKey(b, `"decimals":`)
+	b = strconv.AppendInt(b, int64(v.Decimals), 10)
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the NetworkIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *NetworkIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the NetworkIdentifier to b as JSON.
+func (v *NetworkIdentifier) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"blockchain":`)
+	b = appendJSONString(b, v.Blockchain)
+	b = appendJSONKey(b, `"network":`)
+	b = appendJSONString(b, v.Network)
+	if v.SubNetworkIdentifier != nil {
+		b = appendJSONKey(b, `"sub_network_identifier":`)
+		if b, err = v.SubNetworkIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the Operation to JSON
+// without reflection (see fast_json.go).
+func (v *Operation) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the Operation to b as JSON.
+func (v *Operation) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"operation_identifier":`)
+	if v.OperationIdentifier == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.OperationIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.RelatedOperations) > 0 {
+		b = appendJSONKey(b, `"related_operations":`)
+		b = append(b, '[')
+		for i, x := range v.RelatedOperations {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if x == nil {
+				b = append(b, "null"...)
+				continue
+			}
+
+			if b, err = x.appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	}
+	b = appendJSONKey(b, `"type":`)
+	b = appendJSONString(b, v.Type)
+	if v.Status != nil {
+		b = appendJSONKey(b, `"status":`)
+		b = appendJSONString(b, *v.Status)
+	}
+	if v.Account != nil {
+		b = appendJSONKey(b, `"account":`)
+		if b, err = v.Account.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if v.Amount != nil {
+		b = appendJSONKey(b, `"amount":`)
+		if b, err = v.Amount.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if v.CoinChange != nil {
+		b = appendJSONKey(b, `"coin_change":`)
+		if b, err = v.CoinChange.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the OperationIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *OperationIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the OperationIdentifier to b as JSON.
+func (v *OperationIdentifier) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	b = appendJSONKey(b, `"index":`)
+	b = strconv.AppendInt(b, v.Index, 10)
+	if v.NetworkIndex != nil {
+		b = appendJSONKey(b, `"network_index":`)
+		b = strconv.AppendInt(b, *v.NetworkIndex, 10)
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the RelatedTransaction to JSON
+// without reflection (see fast_json.go).
+func (v *RelatedTransaction) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the RelatedTransaction to b as JSON.
+func (v *RelatedTransaction) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	if v.NetworkIdentifier != nil {
+		b = appendJSONKey(b, `"network_identifier":`)
+		if b, err = v.NetworkIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = appendJSONKey(b, `"transaction_identifier":`)
+	if v.TransactionIdentifier == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.TransactionIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = appendJSONKey(b, `"direction":`)
+	b = appendJSONString(b, string(v.Direction))
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the SubAccountIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *SubAccountIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the SubAccountIdentifier to b as JSON.
+func (v *SubAccountIdentifier) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"address":`)
+	b = appendJSONString(b, v.Address)
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the SubNetworkIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *SubNetworkIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the SubNetworkIdentifier to b as JSON.
+func (v *SubNetworkIdentifier) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"network":`)
+	b = appendJSONString(b, v.Network)
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the Transaction to JSON
+// without reflection (see fast_json.go).
+func (v *Transaction) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the Transaction to b as JSON.
+func (v *Transaction) appendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '{')
+	b = appendJSONKey(b, `"transaction_identifier":`)
+	if v.TransactionIdentifier == nil {
+		b = append(b, "null"...)
+	} else {
+		if b, err = v.TransactionIdentifier.appendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	b = appendJSONKey(b, `"operations":`)
+	if v.Operations == nil {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i, x := range v.Operations {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if x == nil {
+				b = append(b, "null"...)
+				continue
+			}
+
+			if b, err = x.appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	}
+	if len(v.RelatedTransactions) > 0 {
+		b = appendJSONKey(b, `"related_transactions":`)
+		b = append(b, '[')
+		for i, x := range v.RelatedTransactions {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if x == nil {
+				b = append(b, "null"...)
+				continue
+			}
+
+			if b, err = x.appendJSON(b); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	}
+	if len(v.Metadata) > 0 {
+		b = appendJSONKey(b, `"metadata":`)
+		if b, err = appendJSONValue(b, v.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// MarshalJSON encodes the TransactionIdentifier to JSON
+// without reflection (see fast_json.go).
+func (v *TransactionIdentifier) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+// appendJSON appends the TransactionIdentifier to b as JSON.
+func (v *TransactionIdentifier) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	b = appendJSONKey(b, `"hash":`)
+	b = appendJSONString(b, v.Hash)
+
+	return append(b, '}'), nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build fastjson
+// +build fastjson
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fastJSONReader is implemented by the types
+// with generated UnmarshalJSON methods.
+type fastJSONReader interface {
+	readJSON(r *jsonReader) bool
+}
+
+var (
+	fastJSONReaderType = reflect.TypeOf((*fastJSONReader)(nil)).Elem()
+	unmarshalerType    = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// CheckUnknownFields returns an error if data has an object
+// member that json.Decoder.DisallowUnknownFields would reject
+// when decoding data into v. Decoders that disallow unknown
+// fields must call it after decoding because the generated
+// UnmarshalJSON methods (added by the fastjson build tag)
+// cannot see the options of a json.Decoder.
+//
+// Only the members of types with generated UnmarshalJSON
+// methods are checked (json.Decoder checks all others) and
+// data that cannot be decoded into v is ignored (json.Decoder
+// returns these errors).
+func CheckUnknownFields(data []byte, v interface{}) error {
+	return checkUnknownFields(data, reflect.TypeOf(v))
+}
+
+// checkUnknownFields checks the members of data
+// that are decoded into a value of type t.
+func checkUnknownFields(data []byte, t reflect.Type) error {
+	if t == nil {
+		return nil
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fast := reflect.PtrTo(t).Implements(fastJSONReaderType)
+	if !fast && (t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return checkJSONObject(data, func(key string, value []byte) error {
+			field, ok := jsonField(t, key)
+			if !ok {
+				if fast {
+					return fmt.Errorf("json: unknown field %q", key)
+				}
+
+				return nil
+			}
+
+			return checkUnknownFields(value, field.Type)
+		})
+	case reflect.Map:
+		return checkJSONObject(data, func(key string, value []byte) error {
+			return checkUnknownFields(value, t.Elem())
+		})
+	case reflect.Slice, reflect.Array:
+		var elements []json.RawMessage
+		if json.Unmarshal(data, &elements) != nil {
+			return nil
+		}
+
+		for _, element := range elements {
+			if err := checkUnknownFields(element, t.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkJSONObject calls member with the key and value of
+// each member of data (in order) if data is an object.
+func checkJSONObject(data []byte, member func(key string, value []byte) error) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+
+		if err := member(token.(string), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonField returns the field of t that encoding/json
+// decodes the member with key into (an exact match of
+// its name is preferred to a case-insensitive match).
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if len(name) == 0 && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				if nested, ok := jsonField(embedded, key); ok {
+					return nested, true
+				}
+
+				continue
+			}
+		}
+
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		if name == key {
+			return field, true
+		}
+
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = &field
+		}
+	}
+
+	if folded != nil {
+		return *folded, true
+	}
+
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,27 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !fastjson
+// +build !fastjson
+
+package types
+
+// CheckUnknownFields returns an error if data has an object
+// member that json.Decoder.DisallowUnknownFields would reject
+// when decoding data into v. Without the fastjson build tag,
+// json.Decoder already rejects these members, so it always
+// returns nil.
+func CheckUnknownFields(data []byte, v interface{}) error {
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// plainTypes caches the types returned by plainType.
+var plainTypes = map[reflect.Type]reflect.Type{}
+
+// plainType returns a type with the same fields (and JSON
+// tags) as t but without any methods (so that values are
+// encoded by encoding/json with reflection).
+func plainType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return reflect.PtrTo(plainType(t.Elem()))
+	case reflect.Slice:
+		return reflect.SliceOf(plainType(t.Elem()))
+	case reflect.Struct:
+		if plain, ok := plainTypes[t]; ok {
+			return plain
+		}
+
+		fields := []reflect.StructField{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			field.Type = plainType(field.Type)
+			fields = append(fields, field)
+		}
+
+		plain := reflect.StructOf(fields)
+		plainTypes[t] = plain
+		return plain
+	default:
+		return t
+	}
+}
+
+// plainValue converts v to a value of t
+// (a type returned by plainType).
+func plainValue(v reflect.Value, t reflect.Type) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+
+		plain := reflect.New(t.Elem())
+		plain.Elem().Set(plainValue(v.Elem(), t.Elem()))
+		return plain
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+
+		plain := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			plain.Index(i).Set(plainValue(v.Index(i), t.Elem()))
+		}
+
+		return plain
+	case reflect.Struct:
+		plain := reflect.New(t).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			plain.Field(i).Set(plainValue(v.Field(i), t.Field(i).Type))
+		}
+
+		return plain
+	default:
+		return v
+	}
+}
+
+// plain converts v to a value without methods.
+func plain(v interface{}) interface{} {
+	value := reflect.ValueOf(v)
+	return plainValue(value, plainType(value.Type())).Interface()
+}
+
+func fastJSONBlock() *Block {
+	return &Block{
+		BlockIdentifier:       &BlockIdentifier{Index: 100, Hash: "block 100"},
+		ParentBlockIdentifier: &BlockIdentifier{Index: 99, Hash: "block 99"},
+		Timestamp:             1582833600000,
+		Transactions: []*Transaction{
+			{
+				TransactionIdentifier: &TransactionIdentifier{Hash: "tx <&> \"quoted\""},
+				Operations: []*Operation{
+					{
+						OperationIdentifier: &OperationIdentifier{Index: 0, NetworkIndex: Int64(-1)},
+						Type:                "TRANSFER",
+						Status:              String("SUCCESS"),
+						Account: &AccountIdentifier{
+							Address: "addr   é \x01",
+							SubAccount: &SubAccountIdentifier{
+								Address:  "sub",
+								Metadata: map[string]interface{}{"html": "<b>"},
+							},
+						},
+						Amount: &Amount{
+							Value:    "-100",
+							Currency: &Currency{Symbol: "BTC", Decimals: 8},
+						},
+						Metadata: map[string]interface{}{
+							"nested": map[string]interface{}{"list": []interface{}{1, "a", nil}},
+						},
+					},
+					{
+						OperationIdentifier: &OperationIdentifier{Index: 1},
+						RelatedOperations:   []*OperationIdentifier{{Index: 0}},
+						Type:                "COIN",
+						CoinChange: &CoinChange{
+							CoinIdentifier: &CoinIdentifier{Identifier: "coin"},
+							CoinAction:     CoinCreated,
+						},
+					},
+					nil,
+				},
+				RelatedTransactions: []*RelatedTransaction{
+					{
+						NetworkIdentifier: &NetworkIdentifier{
+							Blockchain:           "bitcoin",
+							Network:              "mainnet",
+							SubNetworkIdentifier: &SubNetworkIdentifier{Network: "shard 1"},
+						},
+						TransactionIdentifier: &TransactionIdentifier{Hash: "related"},
+						Direction:             Forward,
+					},
+				},
+			},
+			{
+				TransactionIdentifier: &TransactionIdentifier{Hash: "invalid \xff utf8"},
+				Operations:            []*Operation{},
+			},
+			{},
+		},
+		Metadata: map[string]interface{}{"size": 1.5},
+	}
+}
+
+func TestFastJSONMarshal(t *testing.T) {
+	var tests = map[string]interface{}{
+		"block":       fastJSONBlock(),
+		"empty block": &Block{},
+		"operation":   fastJSONBlock().Transactions[0].Operations[0],
+		"transactions": []*Transaction{
+			fastJSONBlock().Transactions[0],
+			nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected, err := json.Marshal(plain(test))
+			assert.NoError(t, err)
+
+			encoded, err := json.Marshal(test)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(encoded))
+
+			// Without HTML escaping
+			var expectedBuf, buf bytes.Buffer
+			expectedEncoder := json.NewEncoder(&expectedBuf)
+			expectedEncoder.SetEscapeHTML(false)
+			assert.NoError(t, expectedEncoder.Encode(plain(test)))
+
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			assert.NoError(t, encoder.Encode(test))
+			assert.Equal(t, expectedBuf.String(), buf.String())
+		})
+	}
+
+	// Invalid metadata
+	_, err := json.Marshal(&Operation{Metadata: map[string]interface{}{"a": make(chan int)}})
+	assert.Error(t, err)
+}
+func TestFastJSONDisallowUnknownFields(t *testing.T) {
+	if reflect.TypeOf(&Block{}).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		t.Skip("DisallowUnknownFields is not supported with the fastjson build tag")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(
+		`{"transactions":[{"operations":[{"operation_identifier":{"index":0},"unknown":1}]}]}`,
+	)))
+	decoder.DisallowUnknownFields()
+
+	var block Block
+	assert.Error(t, decoder.Decode(&block))
+}
+
+func BenchmarkBlockMarshalJSON(b *testing.B) {
+	block := benchmarkBlock()
+	for name, v := range map[string]interface{}{
+		"generated":  block,
+		"reflection": plain(block),
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := json.Marshal(v); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkBlock returns a block with 100 transactions
+// (each with 2 operations).
+func benchmarkBlock() *Block {
+	block := &Block{
+		BlockIdentifier:       &BlockIdentifier{Index: 100, Hash: "block 100"},
+		ParentBlockIdentifier: &BlockIdentifier{Index: 99, Hash: "block 99"},
+		Timestamp:             1582833600000,
+	}
+
+	currency := &Currency{Symbol: "BTC", Decimals: 8}
+	for i := 0; i < 100; i++ {
+		tx := &Transaction{
+			TransactionIdentifier: &TransactionIdentifier{Hash: fmt.Sprintf("tx %d", i)},
+		}
+
+		for j := int64(0); j < 2; j++ {
+			value := "-100"
+			if j == 1 {
+				value = "100"
+			}
+
+			tx.Operations = append(tx.Operations, &Operation{
+				OperationIdentifier: &OperationIdentifier{Index: j},
+				Type:                "TRANSFER",
+				Status:              String("SUCCESS"),
+				Account:             &AccountIdentifier{Address: fmt.Sprintf("addr %d", j)},
+				Amount:              &Amount{Value: value, Currency: currency},
+			})
+		}
+
+		block.Transactions = append(block.Transactions, tx)
+	}
+
+	return block
+}
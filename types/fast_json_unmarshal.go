@@ -0,0 +1,370 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build fastjson
+// +build fastjson
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// When built with the fastjson build tag, the types in
+// fast_json_gen.go also have generated UnmarshalJSON methods
+// (in fast_json_unmarshal_gen.go) that decode JSON without
+// reflection. They decode values exactly like encoding/json:
+//
+//   - Metadata is decoded with encoding/json.
+//   - If a value cannot be decoded exactly like encoding/json
+//     would decode it (ex: a field has the wrong type or a
+//     key only matches a field case-insensitively), the value
+//     is decoded again with encoding/json (so errors are
+//     returned by encoding/json).
+//
+// However, encoding/json does not pass the options of a Decoder
+// to UnmarshalJSON methods, so DisallowUnknownFields has no
+// effect on these types. Decoders that disallow unknown fields
+// must also call CheckUnknownFields (as all strict decoders in
+// the SDK do).
+
+// jsonReader reads the JSON values decoded by the generated
+// UnmarshalJSON methods. All read methods return false if the
+// next value is invalid, has an unexpected type, or cannot be
+// decoded exactly like encoding/json would decode it.
+type jsonReader struct {
+	data []byte
+	pos  int
+}
+
+// readJSON reads a value from data with read (if data is
+// not null, which is ignored like encoding/json does). It
+// returns false if data has any trailing data.
+func readJSON(data []byte, read func(r *jsonReader) bool) bool {
+	r := &jsonReader{data: data}
+	if !r.readNull() && !read(r) {
+		return false
+	}
+
+	r.skipSpace()
+	return r.pos == len(r.data)
+}
+
+// skipSpace skips any whitespace.
+func (r *jsonReader) skipSpace() {
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case ' ', '\t', '\n', '\r':
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next character that is not
+// whitespace (or 0 if there is none).
+func (r *jsonReader) peek() byte {
+	r.skipSpace()
+	if r.pos >= len(r.data) {
+		return 0
+	}
+
+	return r.data[r.pos]
+}
+
+// readNull reads the next value if it is null.
+func (r *jsonReader) readNull() bool {
+	if r.peek() == 'n' && bytes.HasPrefix(r.data[r.pos:], []byte("null")) {
+		r.pos += 4
+		return true
+	}
+
+	return false
+}
+
+// readString reads a string.
+func (r *jsonReader) readString() (string, bool) {
+	s, ok := r.readStringBytes()
+	return string(s), ok
+}
+
+// readStringBytes reads a string (without copying
+// it if it does not need to be unescaped).
+func (r *jsonReader) readStringBytes() ([]byte, bool) {
+	if r.peek() != '"' {
+		return nil, false
+	}
+
+	start := r.pos
+	r.pos++
+
+	escaped, ascii := false, true
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		switch {
+		case c == '"':
+			r.pos++
+			contents := r.data[start+1 : r.pos-1]
+			if !escaped && (ascii || utf8.Valid(contents)) {
+				return contents, true
+			}
+
+			var s string
+			if err := json.Unmarshal(r.data[start:r.pos], &s); err != nil {
+				return nil, false
+			}
+
+			return []byte(s), true
+		case c == '\\':
+			escaped = true
+			r.pos += 2
+			continue
+		case c < 0x20: // nolint:gomnd
+			return nil, false
+		case c >= utf8.RuneSelf:
+			ascii = false
+		}
+
+		r.pos++
+	}
+
+	return nil, false
+}
+
+// skipString reads a string
+// (without decoding it).
+func (r *jsonReader) skipString() bool {
+	if r.peek() != '"' {
+		return false
+	}
+	r.pos++
+
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case '"':
+			r.pos++
+			return true
+		case '\\':
+			r.pos += 2
+		default:
+			r.pos++
+		}
+	}
+
+	return false
+}
+
+// readInt reads an integer of bitSize bits.
+func (r *jsonReader) readInt(bitSize int) (int64, bool) {
+	number, ok := r.readNumber()
+	if !ok {
+		return 0, false
+	}
+
+	i, err := strconv.ParseInt(number, 10, bitSize)
+	return i, err == nil
+}
+
+// readNumber reads a number (as
+// it is written in the data).
+func (r *jsonReader) readNumber() (string, bool) {
+	r.skipSpace()
+	start := r.pos
+
+	digits := func() bool {
+		digitsStart := r.pos
+		for r.pos < len(r.data) && r.data[r.pos] >= '0' && r.data[r.pos] <= '9' {
+			r.pos++
+		}
+
+		return r.pos > digitsStart
+	}
+
+	if r.pos < len(r.data) && r.data[r.pos] == '-' {
+		r.pos++
+	}
+
+	integerStart := r.pos
+	if !digits() || (r.data[integerStart] == '0' && r.pos-integerStart > 1) {
+		return "", false
+	}
+
+	if r.pos < len(r.data) && r.data[r.pos] == '.' {
+		r.pos++
+		if !digits() {
+			return "", false
+		}
+	}
+
+	if r.pos < len(r.data) && (r.data[r.pos] == 'e' || r.data[r.pos] == 'E') {
+		r.pos++
+		if r.pos < len(r.data) && (r.data[r.pos] == '+' || r.data[r.pos] == '-') {
+			r.pos++
+		}
+
+		if !digits() {
+			return "", false
+		}
+	}
+
+	return string(r.data[start:r.pos]), true
+}
+
+// readRaw reads any value (without decoding it). The
+// value is not validated (it must be decoded or
+// validated by the caller).
+func (r *jsonReader) readRaw() ([]byte, bool) {
+	r.skipSpace()
+	start := r.pos
+
+	depth := 0
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case '"':
+			if !r.skipString() {
+				return nil, false
+			}
+
+			if depth == 0 {
+				return r.data[start:r.pos], true
+			}
+
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth < 0 {
+				return nil, false
+			}
+
+			if depth == 0 {
+				r.pos++
+				return r.data[start:r.pos], true
+			}
+		default:
+			if depth == 0 {
+				for r.pos < len(r.data) && !strings.ContainsRune(" \t\n\r,:]}", rune(r.data[r.pos])) {
+					r.pos++
+				}
+
+				return r.data[start:r.pos], r.pos > start
+			}
+		}
+
+		r.pos++
+	}
+
+	return nil, false
+}
+
+// readObject reads an object, calling member with the
+// key of each member (member must read its value). key
+// is only valid until member returns.
+func (r *jsonReader) readObject(member func(key []byte) bool) bool {
+	if r.peek() != '{' {
+		return false
+	}
+	r.pos++
+
+	if r.peek() == '}' {
+		r.pos++
+		return true
+	}
+
+	for {
+		key, ok := r.readStringBytes()
+		if !ok || r.peek() != ':' {
+			return false
+		}
+		r.pos++
+
+		if !member(key) {
+			return false
+		}
+
+		switch r.peek() {
+		case ',':
+			r.pos++
+		case '}':
+			r.pos++
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// readArray reads an array, calling element
+// for each element (element must read it).
+func (r *jsonReader) readArray(element func() bool) bool {
+	if r.peek() != '[' {
+		return false
+	}
+	r.pos++
+
+	if r.peek() == ']' {
+		r.pos++
+		return true
+	}
+
+	for {
+		if !element() {
+			return false
+		}
+
+		switch r.peek() {
+		case ',':
+			r.pos++
+		case ']':
+			r.pos++
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// readMetadata reads metadata into m
+// with encoding/json.
+func (r *jsonReader) readMetadata(m *map[string]interface{}) bool {
+	raw, ok := r.readRaw()
+	return ok && json.Unmarshal(raw, m) == nil
+}
+
+// skipMember skips the value of a member with a key that
+// is not the key of any of fields. encoding/json matches
+// keys to fields case-insensitively, so the value is not
+// skipped if key matches any of fields (or is not ASCII).
+func (r *jsonReader) skipMember(key []byte, fields []string) bool {
+	for i := 0; i < len(key); i++ {
+		if key[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	for _, field := range fields {
+		if bytes.EqualFold(key, []byte(field)) {
+			return false
+		}
+	}
+
+	raw, ok := r.readRaw()
+	return ok && json.Valid(raw)
+}
@@ -0,0 +1,962 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by internal/typegen. DO NOT EDIT.
+
+//go:build fastjson
+// +build fastjson
+
+package types
+
+import (
+	"encoding/json"
+)
+
+// UnmarshalJSON decodes the AccountIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *AccountIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias AccountIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// accountIdentifierJSONFields are the keys
+// of the fields of AccountIdentifier.
+var accountIdentifierJSONFields = []string{
+	"address",
+	"sub_account",
+	"metadata",
+}
+
+// readJSON reads the AccountIdentifier from r.
+func (v *AccountIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "address":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Address = x
+			}
+
+			return ok
+		case "sub_account":
+			if r.readNull() {
+				v.SubAccount = nil
+				return true
+			}
+
+			if v.SubAccount == nil {
+				v.SubAccount = &SubAccountIdentifier{}
+			}
+
+			return v.SubAccount.readJSON(r)
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, accountIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the Amount from JSON
+// without reflection (see fast_json.go).
+func (v *Amount) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias Amount
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// amountJSONFields are the keys
+// of the fields of Amount.
+var amountJSONFields = []string{
+	"value",
+	"currency",
+	"metadata",
+}
+
+// readJSON reads the Amount from r.
+func (v *Amount) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "value":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Value = x
+			}
+
+			return ok
+		case "currency":
+			if r.readNull() {
+				v.Currency = nil
+				return true
+			}
+
+			if v.Currency == nil {
+				v.Currency = &Currency{}
+			}
+
+			return v.Currency.readJSON(r)
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, amountJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the Block from JSON
+// without reflection (see fast_json.go).
+func (v *Block) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias Block
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// blockJSONFields are the keys
+// of the fields of Block.
+var blockJSONFields = []string{
+	"block_identifier",
+	"parent_block_identifier",
+	"timestamp",
+	"transactions",
+	"metadata",
+}
+
+// readJSON reads the Block from r.
+func (v *Block) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "block_identifier":
+			if r.readNull() {
+				v.BlockIdentifier = nil
+				return true
+			}
+
+			if v.BlockIdentifier == nil {
+				v.BlockIdentifier = &BlockIdentifier{}
+			}
+
+			return v.BlockIdentifier.readJSON(r)
+		case "parent_block_identifier":
+			if r.readNull() {
+				v.ParentBlockIdentifier = nil
+				return true
+			}
+
+			if v.ParentBlockIdentifier == nil {
+				v.ParentBlockIdentifier = &BlockIdentifier{}
+			}
+
+			return v.ParentBlockIdentifier.readJSON(r)
+		case "timestamp":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readInt(64)
+			if ok {
+				v.Timestamp = x
+			}
+
+			return ok
+		case "transactions":
+			if r.readNull() {
+				v.Transactions = nil
+				return true
+			}
+
+			s := v.Transactions[:0]
+			ok := r.readArray(func() bool {
+				i := len(s)
+				if i < cap(s) {
+					s = s[:i+1]
+				} else {
+					s = append(s, nil)
+				}
+
+				if r.readNull() {
+					s[i] = nil
+					return true
+				}
+
+				if s[i] == nil {
+					s[i] = &Transaction{}
+				}
+
+				return s[i].readJSON(r)
+			})
+			if s == nil {
+				s = []*Transaction{}
+			}
+
+			v.Transactions = s
+			return ok
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, blockJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the BlockIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *BlockIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias BlockIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// blockIdentifierJSONFields are the keys
+// of the fields of BlockIdentifier.
+var blockIdentifierJSONFields = []string{
+	"index",
+	"hash",
+}
+
+// readJSON reads the BlockIdentifier from r.
+func (v *BlockIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "index":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readInt(64)
+			if ok {
+				v.Index = x
+			}
+
+			return ok
+		case "hash":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Hash = x
+			}
+
+			return ok
+		default:
+			return r.skipMember(key, blockIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the CoinChange from JSON
+// without reflection (see fast_json.go).
+func (v *CoinChange) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias CoinChange
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// coinChangeJSONFields are the keys
+// of the fields of CoinChange.
+var coinChangeJSONFields = []string{
+	"coin_identifier",
+	"coin_action",
+}
+
+// readJSON reads the CoinChange from r.
+func (v *CoinChange) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "coin_identifier":
+			if r.readNull() {
+				v.CoinIdentifier = nil
+				return true
+			}
+
+			if v.CoinIdentifier == nil {
+				v.CoinIdentifier = &CoinIdentifier{}
+			}
+
+			return v.CoinIdentifier.readJSON(r)
+		case "coin_action":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.CoinAction = CoinAction(x)
+			}
+
+			return ok
+		default:
+			return r.skipMember(key, coinChangeJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the CoinIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *CoinIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias CoinIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// coinIdentifierJSONFields are the keys
+// of the fields of CoinIdentifier.
+var coinIdentifierJSONFields = []string{
+	"identifier",
+}
+
+// readJSON reads the CoinIdentifier from r.
+func (v *CoinIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "identifier":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Identifier = x
+			}
+
+			return ok
+		default:
+			return r.skipMember(key, coinIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the Currency from JSON
+// without reflection (see fast_json.go).
+func (v *Currency) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias Currency
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// currencyJSONFields are the keys
+// of the fields of Currency.
+var currencyJSONFields = []string{
+	"symbol",
+	"decimals",
+	"metadata",
+}
+
+// readJSON reads the Currency from r.
+func (v *Currency) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "symbol":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Symbol = x
+			}
+
+			return ok
+		case "decimals":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readInt(32)
+			if ok {
+				v.Decimals = int32(x)
+			}
+
+			return ok
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, currencyJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the NetworkIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *NetworkIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias NetworkIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// networkIdentifierJSONFields are the keys
+// of the fields of NetworkIdentifier.
+var networkIdentifierJSONFields = []string{
+	"blockchain",
+	"network",
+	"sub_network_identifier",
+}
+
+// readJSON reads the NetworkIdentifier from r.
+func (v *NetworkIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "blockchain":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Blockchain = x
+			}
+
+			return ok
+		case "network":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Network = x
+			}
+
+			return ok
+		case "sub_network_identifier":
+			if r.readNull() {
+				v.SubNetworkIdentifier = nil
+				return true
+			}
+
+			if v.SubNetworkIdentifier == nil {
+				v.SubNetworkIdentifier = &SubNetworkIdentifier{}
+			}
+
+			return v.SubNetworkIdentifier.readJSON(r)
+		default:
+			return r.skipMember(key, networkIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the Operation from JSON
+// without reflection (see fast_json.go).
+func (v *Operation) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias Operation
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// operationJSONFields are the keys
+// of the fields of Operation.
+var operationJSONFields = []string{
+	"operation_identifier",
+	"related_operations",
+	"type",
+	"status",
+	"account",
+	"amount",
+	"coin_change",
+	"metadata",
+}
+
+// readJSON reads the Operation from r.
+func (v *Operation) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "operation_identifier":
+			if r.readNull() {
+				v.OperationIdentifier = nil
+				return true
+			}
+
+			if v.OperationIdentifier == nil {
+				v.OperationIdentifier = &OperationIdentifier{}
+			}
+
+			return v.OperationIdentifier.readJSON(r)
+		case "related_operations":
+			if r.readNull() {
+				v.RelatedOperations = nil
+				return true
+			}
+
+			s := v.RelatedOperations[:0]
+			ok := r.readArray(func() bool {
+				i := len(s)
+				if i < cap(s) {
+					s = s[:i+1]
+				} else {
+					s = append(s, nil)
+				}
+
+				if r.readNull() {
+					s[i] = nil
+					return true
+				}
+
+				if s[i] == nil {
+					s[i] = &OperationIdentifier{}
+				}
+
+				return s[i].readJSON(r)
+			})
+			if s == nil {
+				s = []*OperationIdentifier{}
+			}
+
+			v.RelatedOperations = s
+			return ok
+		case "type":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Type = x
+			}
+
+			return ok
+		case "status":
+			if r.readNull() {
+				v.Status = nil
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				if v.Status == nil {
+					v.Status = new(string)
+				}
+				*v.Status = x
+			}
+
+			return ok
+		case "account":
+			if r.readNull() {
+				v.Account = nil
+				return true
+			}
+
+			if v.Account == nil {
+				v.Account = &AccountIdentifier{}
+			}
+
+			return v.Account.readJSON(r)
+		case "amount":
+			if r.readNull() {
+				v.Amount = nil
+				return true
+			}
+
+			if v.Amount == nil {
+				v.Amount = &Amount{}
+			}
+
+			return v.Amount.readJSON(r)
+		case "coin_change":
+			if r.readNull() {
+				v.CoinChange = nil
+				return true
+			}
+
+			if v.CoinChange == nil {
+				v.CoinChange = &CoinChange{}
+			}
+
+			return v.CoinChange.readJSON(r)
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, operationJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the OperationIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *OperationIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias OperationIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// operationIdentifierJSONFields are the keys
+// of the fields of OperationIdentifier.
+var operationIdentifierJSONFields = []string{
+	"index",
+	"network_index",
+}
+
+// readJSON reads the OperationIdentifier from r.
+func (v *OperationIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "index":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readInt(64)
+			if ok {
+				v.Index = x
+			}
+
+			return ok
+		case "network_index":
+			if r.readNull() {
+				v.NetworkIndex = nil
+				return true
+			}
+
+			x, ok := r.readInt(64)
+			if ok {
+				if v.NetworkIndex == nil {
+					v.NetworkIndex = new(int64)
+				}
+				*v.NetworkIndex = x
+			}
+
+			return ok
+		default:
+			return r.skipMember(key, operationIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the RelatedTransaction from JSON
+// without reflection (see fast_json.go).
+func (v *RelatedTransaction) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias RelatedTransaction
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// relatedTransactionJSONFields are the keys
+// of the fields of RelatedTransaction.
+var relatedTransactionJSONFields = []string{
+	"network_identifier",
+	"transaction_identifier",
+	"direction",
+}
+
+// readJSON reads the RelatedTransaction from r.
+func (v *RelatedTransaction) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "network_identifier":
+			if r.readNull() {
+				v.NetworkIdentifier = nil
+				return true
+			}
+
+			if v.NetworkIdentifier == nil {
+				v.NetworkIdentifier = &NetworkIdentifier{}
+			}
+
+			return v.NetworkIdentifier.readJSON(r)
+		case "transaction_identifier":
+			if r.readNull() {
+				v.TransactionIdentifier = nil
+				return true
+			}
+
+			if v.TransactionIdentifier == nil {
+				v.TransactionIdentifier = &TransactionIdentifier{}
+			}
+
+			return v.TransactionIdentifier.readJSON(r)
+		case "direction":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Direction = Direction(x)
+			}
+
+			return ok
+		default:
+			return r.skipMember(key, relatedTransactionJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the SubAccountIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *SubAccountIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias SubAccountIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// subAccountIdentifierJSONFields are the keys
+// of the fields of SubAccountIdentifier.
+var subAccountIdentifierJSONFields = []string{
+	"address",
+	"metadata",
+}
+
+// readJSON reads the SubAccountIdentifier from r.
+func (v *SubAccountIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "address":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Address = x
+			}
+
+			return ok
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, subAccountIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the SubNetworkIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *SubNetworkIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias SubNetworkIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// subNetworkIdentifierJSONFields are the keys
+// of the fields of SubNetworkIdentifier.
+var subNetworkIdentifierJSONFields = []string{
+	"network",
+	"metadata",
+}
+
+// readJSON reads the SubNetworkIdentifier from r.
+func (v *SubNetworkIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "network":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Network = x
+			}
+
+			return ok
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, subNetworkIdentifierJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the Transaction from JSON
+// without reflection (see fast_json.go).
+func (v *Transaction) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias Transaction
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// transactionJSONFields are the keys
+// of the fields of Transaction.
+var transactionJSONFields = []string{
+	"transaction_identifier",
+	"operations",
+	"related_transactions",
+	"metadata",
+}
+
+// readJSON reads the Transaction from r.
+func (v *Transaction) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "transaction_identifier":
+			if r.readNull() {
+				v.TransactionIdentifier = nil
+				return true
+			}
+
+			if v.TransactionIdentifier == nil {
+				v.TransactionIdentifier = &TransactionIdentifier{}
+			}
+
+			return v.TransactionIdentifier.readJSON(r)
+		case "operations":
+			if r.readNull() {
+				v.Operations = nil
+				return true
+			}
+
+			s := v.Operations[:0]
+			ok := r.readArray(func() bool {
+				i := len(s)
+				if i < cap(s) {
+					s = s[:i+1]
+				} else {
+					s = append(s, nil)
+				}
+
+				if r.readNull() {
+					s[i] = nil
+					return true
+				}
+
+				if s[i] == nil {
+					s[i] = &Operation{}
+				}
+
+				return s[i].readJSON(r)
+			})
+			if s == nil {
+				s = []*Operation{}
+			}
+
+			v.Operations = s
+			return ok
+		case "related_transactions":
+			if r.readNull() {
+				v.RelatedTransactions = nil
+				return true
+			}
+
+			s := v.RelatedTransactions[:0]
+			ok := r.readArray(func() bool {
+				i := len(s)
+				if i < cap(s) {
+					s = s[:i+1]
+				} else {
+					s = append(s, nil)
+				}
+
+				if r.readNull() {
+					s[i] = nil
+					return true
+				}
+
+				if s[i] == nil {
+					s[i] = &RelatedTransaction{}
+				}
+
+				return s[i].readJSON(r)
+			})
+			if s == nil {
+				s = []*RelatedTransaction{}
+			}
+
+			v.RelatedTransactions = s
+			return ok
+		case "metadata":
+			return r.readMetadata(&v.Metadata)
+		default:
+			return r.skipMember(key, transactionJSONFields)
+		}
+	})
+}
+
+// UnmarshalJSON decodes the TransactionIdentifier from JSON
+// without reflection (see fast_json.go).
+func (v *TransactionIdentifier) UnmarshalJSON(data []byte) error {
+	if readJSON(data, v.readJSON) {
+		return nil
+	}
+
+	type Alias TransactionIdentifier
+	return json.Unmarshal(data, (*Alias)(v))
+}
+
+// transactionIdentifierJSONFields are the keys
+// of the fields of TransactionIdentifier.
+var transactionIdentifierJSONFields = []string{
+	"hash",
+}
+
+// readJSON reads the TransactionIdentifier from r.
+func (v *TransactionIdentifier) readJSON(r *jsonReader) bool {
+	return r.readObject(func(key []byte) bool {
+		switch string(key) {
+		case "hash":
+			if r.readNull() {
+				return true
+			}
+
+			x, ok := r.readString()
+			if ok {
+				v.Hash = x
+			}
+
+			return ok
+		default:
+			return r.skipMember(key, transactionIdentifierJSONFields)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build fastjson
+// +build fastjson
+
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newPlain returns a pointer to the zero value of the
+// type without methods of v (a pointer).
+func newPlain(v interface{}) interface{} {
+	return reflect.New(plainType(reflect.TypeOf(v).Elem())).Interface()
+}
+func TestFastJSONUnmarshal(t *testing.T) {
+	encodedBlock, err := json.Marshal(fastJSONBlock())
+	assert.NoError(t, err)
+
+	// fast is true if the data is decoded
+	// without falling back to encoding/json.
+	var tests = map[string]struct {
+		data string
+		fast bool
+		err  bool
+	}{
+		"block": {
+			data: string(encodedBlock),
+			fast: true,
+		},
+		"whitespace": {
+			data: ` { "block_identifier" : { "index" : 1 , "hash" : "a" } ,
+				"transactions" : [ ] } `,
+			fast: true,
+		},
+		"null": {
+			data: `null`,
+			fast: true,
+		},
+		"null fields": {
+			data: `{"block_identifier":null,"timestamp":null,"transactions":[null],
+				"metadata":null}`,
+			fast: true,
+		},
+		"unknown fields": {
+			data: `{"timestamp":1,"unknown":{"a":[1,{"b":"\"}"}]},"other":"x"}`,
+			fast: true,
+		},
+		"case-insensitive field": {
+			data: `{"Timestamp":1,"BLOCK_IDENTIFIER":{"Index":2}}`,
+		},
+		"non-ASCII field": {
+			data: `{"timeſtamp":1}`,
+		},
+		"duplicate fields": {
+			data: `{"timestamp":1,"timestamp":2}`,
+			fast: true,
+		},
+		"escaped strings": {
+			data: `{"block_identifier":{"hash":"é\n\"\\\/"}}`,
+			fast: true,
+		},
+		"non-ASCII strings": {
+			data: `{"block_identifier":{"hash":"é ☃"}}`,
+			fast: true,
+		},
+		"numbers": {
+			data: `{"timestamp":-0,"transactions":[{"operations":[{"operation_identifier":
+				{"index":9223372036854775807}}]}],"metadata":{"a":1e400}}`,
+			err: true,
+		},
+		"float index": {
+			data: `{"block_identifier":{"index":1.0}}`,
+			err:  true,
+		},
+		"exponent index": {
+			data: `{"block_identifier":{"index":1e2}}`,
+			err:  true,
+		},
+		"overflowing decimals": {
+			data: `{"transactions":[{"operations":[{"amount":{"currency":
+				{"decimals":2147483648}}}]}]}`,
+			err: true,
+		},
+		"string index": {
+			data: `{"block_identifier":{"index":"1"}}`,
+			err:  true,
+		},
+		"number hash": {
+			data: `{"block_identifier":{"hash":1}}`,
+			err:  true,
+		},
+		"object transactions": {
+			data: `{"transactions":{}}`,
+			err:  true,
+		},
+		"array block": {
+			data: `[]`,
+			err:  true,
+		},
+		"invalid JSON": {
+			data: `{"timestamp":1,}`,
+			err:  true,
+		},
+		"trailing data": {
+			data: `{"timestamp":1}}`,
+			err:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.fast, readJSON([]byte(test.data), (&Block{}).readJSON))
+
+			expected := newPlain(&Block{})
+			expectedErr := json.Unmarshal([]byte(test.data), expected)
+
+			block := &Block{}
+			err := json.Unmarshal([]byte(test.data), block)
+			if test.err {
+				assert.Error(t, expectedErr)
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, expectedErr)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, plain(block))
+
+			// Calling UnmarshalJSON directly
+			direct := &Block{}
+			assert.NoError(t, direct.UnmarshalJSON([]byte(test.data)))
+			assert.Equal(t, block, direct)
+		})
+	}
+}
+
+func TestFastJSONUnmarshalExisting(t *testing.T) {
+	// Like encoding/json, decoding into an existing value only
+	// modifies the fields in the data (reusing pointers).
+	data := `{"transactions":[{"operations":[{"type":"FEE","metadata":{"b":2}}]},
+		{"transaction_identifier":null}],"block_identifier":{"hash":"new"}}`
+
+	expected := plain(fastJSONBlock())
+	assert.NoError(t, json.Unmarshal([]byte(data), expected))
+
+	block := fastJSONBlock()
+	shared := block.Transactions[0].Operations[0]
+	assert.NoError(t, json.Unmarshal([]byte(data), block))
+	assert.Equal(t, expected, plain(block))
+	assert.Equal(t, "FEE", shared.Type)
+}
+func BenchmarkBlockUnmarshalJSON(b *testing.B) {
+	data, err := json.Marshal(benchmarkBlock())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for name, newBlock := range map[string]func() interface{}{
+		"generated":  func() interface{} { return &Block{} },
+		"reflection": func() interface{} { return newPlain(&Block{}) },
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := json.Unmarshal(data, newBlock()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckUnknownFields(t *testing.T) {
+	type wrapper struct {
+		Operations []*Operation           `json:"operations"`
+		Blocks     map[string]*Block      `json:"blocks"`
+		Other      map[string]interface{} `json:"other"`
+	}
+
+	var tests = map[string]struct {
+		data string
+		err  string
+	}{
+		"known fields": {
+			data: `{"operations":[{"operation_identifier":{"index":0},"type":"TRANSFER",` +
+				`"metadata":{"extra":true}}],"other":{"extra":{"a":1}}}`,
+		},
+		"case-insensitive field": {
+			data: `{"operations":[{"operation_identifier":{"index":0},"Type":"TRANSFER"}]}`,
+		},
+		"unknown field": {
+			data: `{"operations":[{"operation_identifier":{"index":0},"extra":1}]}`,
+			err:  `json: unknown field "extra"`,
+		},
+		"unknown nested field": {
+			data: `{"operations":[{"operation_identifier":{"index":0},` +
+				`"amount":{"value":"1","currency":{"symbol":"BTC","decimals":8,"extra":1}}}]}`,
+			err: `json: unknown field "extra"`,
+		},
+		"unknown field in map": {
+			data: `{"blocks":{"a":{"block_identifier":{"index":1,"hash":"a","extra":1}}}}`,
+			err:  `json: unknown field "extra"`,
+		},
+		"invalid": {
+			data: `{"operations":"invalid"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckUnknownFields([]byte(test.data), &wrapper{})
+			if len(test.err) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
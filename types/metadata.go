@@ -0,0 +1,216 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+var (
+	// ErrMetadataKeyMissing is returned when a
+	// key is not present in metadata (or its
+	// value is null).
+	ErrMetadataKeyMissing = errors.New("metadata key is missing")
+
+	// ErrMetadataValueInvalid is returned when the value
+	// of a key in metadata cannot be converted to the
+	// requested type.
+	ErrMetadataValueInvalid = errors.New("metadata value is invalid")
+
+	// ErrMetadataNil is returned when metadata is
+	// set with a nil pointer to the metadata.
+	ErrMetadataNil = errors.New("metadata pointer is nil")
+)
+
+// Metadata provides typed access to the metadata of
+// any type (ex: types.Metadata(op.Metadata)). All of
+// its methods can be called on nil metadata.
+//
+// Values are converted like they would be if the metadata
+// was decoded from JSON (so the same value can be read
+// whether it was set in-process or decoded from a request):
+// integers can be read from any integer type, a float64
+// without a fractional part, a json.Number, or a decimal
+// string (the usual encoding of large integers in Rosetta).
+type Metadata map[string]interface{}
+
+// value returns the value of key or
+// ErrMetadataKeyMissing if it is not set.
+func (m Metadata) value(key string) (interface{}, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMetadataKeyMissing, key)
+	}
+
+	return v, nil
+}
+
+// metadataValueInvalid returns an
+// ErrMetadataValueInvalid error for the value v of key.
+func metadataValueInvalid(key string, v interface{}, expected string) error {
+	return fmt.Errorf(
+		"%w: %s is %T (expected %s)",
+		ErrMetadataValueInvalid,
+		key,
+		v,
+		expected,
+	)
+}
+
+// GetString returns the value of key
+// if it is a string.
+func (m Metadata) GetString(key string) (string, error) {
+	v, err := m.value(key)
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", metadataValueInvalid(key, v, "string")
+	}
+
+	return s, nil
+}
+
+// GetBig returns the value of key if it is an
+// integer (or a decimal string of an integer).
+func (m Metadata) GetBig(key string) (*big.Int, error) {
+	v, err := m.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch i := v.(type) {
+	case *big.Int:
+		return new(big.Int).Set(i), nil
+	case string:
+		parsed, err := BigInt(i)
+		if err != nil {
+			return nil, metadataValueInvalid(key, v, "integer")
+		}
+
+		return parsed, nil
+	}
+
+	n, ok := number(v)
+	if !ok || !n.IsInt() {
+		return nil, metadataValueInvalid(key, v, "integer")
+	}
+
+	return new(big.Int).Set(n.Num()), nil
+}
+
+// GetInt64 returns the value of key if it is an integer
+// (or a decimal string of an integer) that fits in an
+// int64.
+func (m Metadata) GetInt64(key string) (int64, error) {
+	i, err := m.GetBig(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if !i.IsInt64() {
+		return 0, fmt.Errorf("%w: %s is out of range of int64", ErrMetadataValueInvalid, key)
+	}
+
+	return i.Int64(), nil
+}
+
+// GetBool returns the value of key if it is
+// a bool (or the string "true" or "false").
+func (m Metadata) GetBool(key string) (bool, error) {
+	v, err := m.value(key)
+	if err != nil {
+		return false, err
+	}
+
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		if b == "true" || b == "false" {
+			return strconv.ParseBool(b)
+		}
+	}
+
+	return false, metadataValueInvalid(key, v, "bool")
+}
+
+// GetStruct decodes the value of key into output (a
+// pointer) by encoding it to JSON and decoding it with
+// encoding/json (so output is populated like it would
+// be if the value was decoded from a request).
+func (m Metadata) GetStruct(key string, output interface{}) error {
+	v, err := m.value(key)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrMetadataValueInvalid, key, err.Error())
+	}
+
+	if err := json.Unmarshal(encoded, output); err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrMetadataValueInvalid, key, err.Error())
+	}
+
+	return nil
+}
+
+// SetMetadata sets key to value in the metadata
+// that metadata points to (ex: &op.Metadata),
+// creating the metadata if it is nil. It returns
+// ErrMetadataNil if metadata is a nil pointer.
+func SetMetadata(metadata *map[string]interface{}, key string, value interface{}) error {
+	if metadata == nil {
+		return fmt.Errorf("%w: %s", ErrMetadataNil, key)
+	}
+
+	if *metadata == nil {
+		*metadata = map[string]interface{}{}
+	}
+
+	(*metadata)[key] = value
+	return nil
+}
+
+// SetMetadataStruct sets key to the value decoded from
+// the JSON encoding of value (so the metadata contains
+// the same value it would if it was decoded from a
+// request) in the metadata that metadata points to,
+// creating the metadata if it is nil.
+func SetMetadataStruct(metadata *map[string]interface{}, key string, value interface{}) error {
+	if metadata == nil {
+		return fmt.Errorf("%w: %s", ErrMetadataNil, key)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrMetadataValueInvalid, key, err.Error())
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return fmt.Errorf("%w: %s: %s", ErrMetadataValueInvalid, key, err.Error())
+	}
+
+	return SetMetadata(metadata, key, decoded)
+}
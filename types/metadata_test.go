@@ -0,0 +1,261 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMetadata = Metadata{
+	"string":        "hello",
+	"int":           10,
+	"int64":         int64(-20),
+	"uint64":        uint64(18446744073709551615),
+	"float":         float64(30),
+	"fraction":      1.5,
+	"number":        json.Number("123456789012345678901234567890"),
+	"big":           big.NewInt(40),
+	"decimal":       "-50",
+	"decimal float": "5.5",
+	"true":          true,
+	"true string":   "true",
+	"yes":           "yes",
+	"null":          nil,
+	"account": map[string]interface{}{
+		"address": "addr",
+		"metadata": map[string]interface{}{
+			"index": 1,
+		},
+	},
+	"account struct": &AccountIdentifier{Address: "addr2"},
+}
+
+func TestMetadataGetString(t *testing.T) {
+	var tests = map[string]struct {
+		key    string
+		result string
+		err    error
+	}{
+		"string":  {key: "string", result: "hello"},
+		"decimal": {key: "decimal", result: "-50"},
+		"int":     {key: "int", err: ErrMetadataValueInvalid},
+		"null":    {key: "null", err: ErrMetadataKeyMissing},
+		"missing": {key: "missing", err: ErrMetadataKeyMissing},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := testMetadata.GetString(test.key)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestMetadataGetBig(t *testing.T) {
+	var tests = map[string]struct {
+		key    string
+		result string
+		err    error
+	}{
+		"int":           {key: "int", result: "10"},
+		"int64":         {key: "int64", result: "-20"},
+		"uint64":        {key: "uint64", result: "18446744073709551615"},
+		"float":         {key: "float", result: "30"},
+		"number":        {key: "number", result: "123456789012345678901234567890"},
+		"big":           {key: "big", result: "40"},
+		"decimal":       {key: "decimal", result: "-50"},
+		"fraction":      {key: "fraction", err: ErrMetadataValueInvalid},
+		"decimal float": {key: "decimal float", err: ErrMetadataValueInvalid},
+		"string":        {key: "string", err: ErrMetadataValueInvalid},
+		"bool":          {key: "true", err: ErrMetadataValueInvalid},
+		"missing":       {key: "missing", err: ErrMetadataKeyMissing},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := testMetadata.GetBig(test.key)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result.String())
+		})
+	}
+
+	// The result is a copy.
+	result, err := testMetadata.GetBig("big")
+	assert.NoError(t, err)
+	result.SetInt64(0)
+	assert.Equal(t, big.NewInt(40), testMetadata["big"])
+}
+
+func TestMetadataGetInt64(t *testing.T) {
+	var tests = map[string]struct {
+		key    string
+		result int64
+		err    error
+	}{
+		"int":      {key: "int", result: 10},
+		"int64":    {key: "int64", result: -20},
+		"float":    {key: "float", result: 30},
+		"decimal":  {key: "decimal", result: -50},
+		"uint64":   {key: "uint64", err: ErrMetadataValueInvalid},
+		"number":   {key: "number", err: ErrMetadataValueInvalid},
+		"fraction": {key: "fraction", err: ErrMetadataValueInvalid},
+		"missing":  {key: "missing", err: ErrMetadataKeyMissing},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := testMetadata.GetInt64(test.key)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestMetadataGetBool(t *testing.T) {
+	var tests = map[string]struct {
+		key    string
+		result bool
+		err    error
+	}{
+		"bool":    {key: "true", result: true},
+		"string":  {key: "true string", result: true},
+		"yes":     {key: "yes", err: ErrMetadataValueInvalid},
+		"int":     {key: "int", err: ErrMetadataValueInvalid},
+		"missing": {key: "missing", err: ErrMetadataKeyMissing},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := testMetadata.GetBool(test.key)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestMetadataGetStruct(t *testing.T) {
+	var tests = map[string]struct {
+		key    string
+		result *AccountIdentifier
+		err    error
+	}{
+		"map": {
+			key: "account",
+			result: &AccountIdentifier{
+				Address:  "addr",
+				Metadata: map[string]interface{}{"index": float64(1)},
+			},
+		},
+		"struct": {
+			key:    "account struct",
+			result: &AccountIdentifier{Address: "addr2"},
+		},
+		"invalid": {
+			key: "string",
+			err: ErrMetadataValueInvalid,
+		},
+		"missing": {
+			key: "missing",
+			err: ErrMetadataKeyMissing,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var result AccountIdentifier
+			err := testMetadata.GetStruct(test.key, &result)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, &result)
+		})
+	}
+}
+
+func TestMetadataNil(t *testing.T) {
+	op := &Operation{}
+	_, err := Metadata(op.Metadata).GetString("memo")
+	assert.True(t, errors.Is(err, ErrMetadataKeyMissing))
+}
+
+func TestSetMetadata(t *testing.T) {
+	op := &Operation{}
+	assert.NoError(t, SetMetadata(&op.Metadata, "memo", "hello"))
+	assert.NoError(t, SetMetadata(&op.Metadata, "index", 1))
+	assert.Equal(t, map[string]interface{}{"memo": "hello", "index": 1}, op.Metadata)
+
+	memo, err := Metadata(op.Metadata).GetString("memo")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", memo)
+
+	err = SetMetadata(nil, "memo", "hello")
+	assert.True(t, errors.Is(err, ErrMetadataNil))
+}
+
+func TestSetMetadataStruct(t *testing.T) {
+	op := &Operation{}
+	assert.NoError(t, SetMetadataStruct(
+		&op.Metadata,
+		"account",
+		&AccountIdentifier{Address: "addr"},
+	))
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"account": map[string]interface{}{"address": "addr"},
+		},
+		op.Metadata,
+	)
+
+	var account AccountIdentifier
+	assert.NoError(t, Metadata(op.Metadata).GetStruct("account", &account))
+	assert.Equal(t, AccountIdentifier{Address: "addr"}, account)
+
+	err := SetMetadataStruct(&op.Metadata, "invalid", make(chan int))
+	assert.True(t, errors.Is(err, ErrMetadataValueInvalid))
+	assert.NotContains(t, op.Metadata, "invalid")
+
+	err = SetMetadataStruct(nil, "account", &AccountIdentifier{Address: "addr"})
+	assert.True(t, errors.Is(err, ErrMetadataNil))
+}
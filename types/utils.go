@@ -57,6 +57,11 @@ func hashBytes(data []byte) string {
 // Inspiration:
 // https://github.com/onsi/gomega/blob/c0be49994280db30b6b68390f67126d773bc5558/matchers/match_json_matcher.go#L16
 //
+// Hash is used as a persisted key, so its output never changes
+// (numbers are rounded to a float64 and null members are kept).
+// Use CanonicalHash for a hash that can be reproduced in other
+// languages.
+//
 // It is important to note that any interface that is a slice
 // or contains slices will not be equal if the slice ordering is
 // different.
@@ -83,6 +88,22 @@ func Hash(i interface{}) string {
 	return hashBytes(c)
 }
 
+// CanonicalHash returns the hex-encoded sha256 hash of the
+// canonical encoding of any interface (see MarshalCanonical).
+// Hashes of the same value are stable across releases of the
+// SDK and can be reproduced in other languages.
+//
+// Like Hash, any interface that is a slice or contains slices
+// will not be equal if the slice ordering is different.
+func CanonicalHash(i interface{}) string {
+	canonical, err := MarshalCanonical(i)
+	if err != nil {
+		log.Fatal(fmt.Errorf("%w: unable to marshal %+v", err, i))
+	}
+
+	return hashBytes(canonical)
+}
+
 // BigInt returns a *big.Int representation of a value.
 func BigInt(value string) (*big.Int, error) {
 	parsedVal, ok := new(big.Int).SetString(value, 10)
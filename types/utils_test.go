@@ -113,6 +113,22 @@ func TestHash(t *testing.T) {
 	}
 }
 
+func TestHashLegacy(t *testing.T) {
+	// Hash is used as a persisted key, so its
+	// encoding must never change.
+	assert.Equal(
+		t,
+		"c473e5b0c56dbb5600e339fdb0b91ffd294efec6cbdfa4ca7bd3b5f4cdb5a366",
+		Hash(&Currency{Symbol: "BTC", Decimals: 8}),
+	)
+	assert.Equal(t, hashBytes([]byte(`{"a":null}`)), Hash(map[string]interface{}{"a": nil}))
+	assert.Equal(
+		t,
+		Hash(json.Number("12345678901234567890")),
+		Hash(json.Number("12345678901234567891")),
+	)
+}
+
 func TestAddValues(t *testing.T) {
 	var tests = map[string]struct {
 		a      string
@@ -146,6 +146,10 @@ func LoadAndParse(filePath string, output interface{}) error {
 		return fmt.Errorf("%w: unable to unmarshal", err)
 	}
 
+	if err := types.CheckUnknownFields(b, output); err != nil {
+		return fmt.Errorf("%w: unable to unmarshal", err)
+	}
+
 	return nil
 }
 